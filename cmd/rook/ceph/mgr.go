@@ -19,6 +19,7 @@ package ceph
 import (
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/rook/rook/cmd/rook/rook"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
@@ -37,7 +38,12 @@ var mgrCmd = &cobra.Command{
 var mgrSidecarCmd = &cobra.Command{
 	Use: "watch-active",
 }
+var mgrMetricsProxyCmd = &cobra.Command{
+	Use: "metrics-proxy",
+}
 var (
+	metricsProxy              mgr.MetricsProxy
+	metricsAuthType           string
 	updateMgrServicesInterval string
 	daemonName                string
 	clusterSpec               cephv1.ClusterSpec
@@ -49,6 +55,7 @@ func init() {
 
 	// add the subcommands to the parent mgr command
 	mgrCmd.AddCommand(mgrSidecarCmd)
+	mgrCmd.AddCommand(mgrMetricsProxyCmd)
 
 	mgrSidecarCmd.Flags().BoolVar(&clusterSpec.Dashboard.Enabled, "dashboard-enabled", false, "whether the dashboard is enabled")
 	mgrSidecarCmd.Flags().BoolVar(&clusterSpec.Monitoring.Enabled, "monitoring-enabled", false, "whether the monitoring is enabled")
@@ -58,9 +65,32 @@ func init() {
 	mgrSidecarCmd.Flags().StringVar(&daemonName, "daemon-name", "", "the name of the local mgr daemon")
 	mgrSidecarCmd.Flags().StringVar(&rawCephVersion, "ceph-version", "", "the version of ceph")
 
+	mgrMetricsProxyCmd.Flags().IntVar(&metricsProxy.MetricsPort, "metrics-port", int(mgr.DefaultMetricsPort), "the port serving the metrics")
+	mgrMetricsProxyCmd.Flags().IntVar(&metricsProxy.ModulePort, "module-port", 0, "the port of the prometheus module on the loopback interface")
+	mgrMetricsProxyCmd.Flags().StringVar(&metricsProxy.TLSDir, "tls-dir", "", "the directory of the certificate and the key, if the metrics are served over TLS")
+	mgrMetricsProxyCmd.Flags().StringVar(&metricsAuthType, "auth-type", "", "the type of authentication of the metrics (bearer or basic)")
+	mgrMetricsProxyCmd.Flags().StringVar(&metricsProxy.AuthDir, "auth-dir", "", "the directory of the credentials of the metrics")
+
 	flags.SetFlagsFromEnv(mgrCmd.Flags(), rook.RookEnvVarPrefix)
 	flags.SetFlagsFromEnv(mgrSidecarCmd.Flags(), rook.RookEnvVarPrefix)
+	flags.SetFlagsFromEnv(mgrMetricsProxyCmd.Flags(), rook.RookEnvVarPrefix)
 	mgrSidecarCmd.RunE = runMgrSidecar
+	mgrMetricsProxyCmd.RunE = runMgrMetricsProxy
+}
+
+// Serve the metrics of the mgr prometheus module over TLS and/or with authentication
+func runMgrMetricsProxy(cmd *cobra.Command, args []string) error {
+	rook.SetLogLevel()
+	rook.LogStartupInfo(mgrMetricsProxyCmd.Flags())
+
+	if metricsProxy.ModulePort == 0 {
+		rook.TerminateFatal(errors.New("the port of the prometheus module is required"))
+	}
+	metricsProxy.AuthType = cephv1.MetricsAuthType(metricsAuthType)
+	if err := metricsProxy.Run(); err != nil {
+		rook.TerminateFatal(err)
+	}
+	return nil
 }
 
 // Start the mgr daemon sidecar
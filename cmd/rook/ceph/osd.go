@@ -19,6 +19,7 @@ package ceph
 import (
 	ctx "context"
 	"encoding/json"
+	"io/ioutil"
 	"os"
 	"strings"
 
@@ -26,6 +27,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/cmd/rook/rook"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	osddaemon "github.com/rook/rook/pkg/daemon/ceph/osd"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
@@ -56,10 +58,18 @@ var osdRemoveCmd = &cobra.Command{
 	Use:   "remove",
 	Short: "Removes a set of OSDs from the cluster",
 }
+var osdCrushLocationCmd = &cobra.Command{
+	Use:   "crush-location",
+	Short: "Writes the CRUSH location of a portable osd from the labels of its current node",
+}
 
 var (
 	osdDataDeviceFilter     string
 	osdDataDevicePathFilter string
+	osdDriveGroups          string
+	osdReplacements         string
+	osdTopologyLabels       string
+	osdDryRun               bool
 	ownerRefID              string
 	clusterName             string
 	osdID                   int
@@ -72,6 +82,7 @@ var (
 	lvBackedPV              bool
 	osdIDsToRemove          string
 	preservePVC             bool
+	crushLocationFile       string
 )
 
 func addOSDFlags(command *cobra.Command) {
@@ -82,6 +93,10 @@ func addOSDFlags(command *cobra.Command) {
 	provisionCmd.Flags().StringVar(&cfg.devices, "data-devices", "", "comma separated list of devices to use for storage")
 	provisionCmd.Flags().StringVar(&osdDataDeviceFilter, "data-device-filter", "", "a regex filter for the device names to use, or \"all\"")
 	provisionCmd.Flags().StringVar(&osdDataDevicePathFilter, "data-device-path-filter", "", "a regex filter for the device path names to use")
+	provisionCmd.Flags().StringVar(&osdDriveGroups, "drive-groups", "", "JSON-marshalled list of the drive groups selecting the devices of the node")
+	provisionCmd.Flags().StringVar(&osdReplacements, "replace-osds", "", "JSON-marshalled list of the destroyed OSDs whose devices are wiped and prepared again with the same IDs")
+	provisionCmd.Flags().BoolVar(&osdDryRun, "dry-run", false, "only report the devices that would be consumed, without touching them")
+	provisionCmd.Flags().StringVar(&osdTopologyLabels, "topology-labels", "", "JSON-marshalled list of the node labels setting the CRUSH location of the OSDs")
 	provisionCmd.Flags().StringVar(&cfg.metadataDevice, "metadata-device", "", "device to use for metadata (e.g. a high performance SSD/NVMe device)")
 	provisionCmd.Flags().BoolVar(&cfg.forceFormat, "force-format", false,
 		"true to force the format of any specified devices, even if they already have a filesystem.  BE CAREFUL!")
@@ -102,11 +117,16 @@ func addOSDFlags(command *cobra.Command) {
 	osdRemoveCmd.Flags().StringVar(&osdIDsToRemove, "osd-ids", "", "OSD IDs to remove from the cluster")
 	osdRemoveCmd.Flags().BoolVar(&preservePVC, "preserve-pvc", false, "Whether PVCs for OSDs will be deleted")
 
+	// flags for detecting the crush location of portable OSDs when they start
+	osdCrushLocationCmd.Flags().StringVar(&crushLocationFile, "location-file", "", "the file the CRUSH location is written to")
+	osdCrushLocationCmd.Flags().StringVar(&osdTopologyLabels, "topology-labels", "", "JSON-marshalled list of the node labels setting the CRUSH location of the OSDs")
+
 	// add the subcommands to the parent osd command
 	osdCmd.AddCommand(osdConfigCmd,
 		provisionCmd,
 		osdStartCmd,
-		osdRemoveCmd)
+		osdRemoveCmd,
+		osdCrushLocationCmd)
 }
 
 func addOSDConfigFlags(command *cobra.Command) {
@@ -132,11 +152,13 @@ func init() {
 	flags.SetFlagsFromEnv(provisionCmd.Flags(), rook.RookEnvVarPrefix)
 	flags.SetFlagsFromEnv(osdStartCmd.Flags(), rook.RookEnvVarPrefix)
 	flags.SetFlagsFromEnv(osdRemoveCmd.Flags(), rook.RookEnvVarPrefix)
+	flags.SetFlagsFromEnv(osdCrushLocationCmd.Flags(), rook.RookEnvVarPrefix)
 
 	osdConfigCmd.RunE = writeOSDConfig
 	provisionCmd.RunE = prepareOSD
 	osdStartCmd.RunE = startOSD
 	osdRemoveCmd.RunE = removeOSDs
+	osdCrushLocationCmd.RunE = writeCrushLocation
 }
 
 // Start the osd daemon if provisioned by ceph-volume
@@ -214,9 +236,24 @@ func prepareOSD(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	driveGroups, err := parseDriveGroups(osdDriveGroups)
+	if err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to parse drive groups (%q)", osdDriveGroups))
+	}
+
+	replaceOSDs, err := parseReplaceOSDs(osdReplacements)
+	if err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to parse the replaced osds (%q)", osdReplacements))
+	}
+
+	topologyLabels, err := parseTopologyLabels(osdTopologyLabels)
+	if err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to parse the topology labels (%q)", osdTopologyLabels))
+	}
+
 	context := createContext()
 	commonOSDInit(provisionCmd)
-	crushLocation, topologyAffinity, err := getLocation(context.Clientset)
+	crushLocation, topologyAffinity, err := getLocation(context.Clientset, topologyLabels)
 	if err != nil {
 		rook.TerminateFatal(err)
 	}
@@ -230,9 +267,14 @@ func prepareOSD(cmd *cobra.Command, args []string) error {
 	clusterInfo.Context = ctx.Background()
 	kv := k8sutil.NewConfigMapKVStore(clusterInfo.Namespace, context.Clientset, ownerInfo)
 	agent := osddaemon.NewAgent(context, dataDevices, cfg.metadataDevice, forceFormat,
-		cfg.storeConfig, &clusterInfo, cfg.nodeName, kv, cfg.pvcBacked)
+		cfg.storeConfig, &clusterInfo, cfg.nodeName, kv, cfg.pvcBacked, driveGroups, replaceOSDs, osdDryRun)
 
-	err = osddaemon.Provision(context, agent, crushLocation, topologyAffinity)
+	// the devices of the node are only enumerated and prepared while holding the provisioning lease of the node
+	release, err := osddaemon.AcquireProvisioningLease(context, os.Getenv(k8sutil.NodeNameEnvVar), os.Getenv(k8sutil.ProvisioningLeaseNamespaceEnvVar), os.Getenv(k8sutil.PodNameEnvVar))
+	if err == nil {
+		err = osddaemon.Provision(context, agent, crushLocation, topologyAffinity)
+		release()
+	}
 	if err != nil {
 		// something failed in the OSD orchestration, update the status map with failure details
 		status := oposd.OrchestrationStatus{
@@ -271,6 +313,31 @@ func removeOSDs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Write the CRUSH location of a portable osd, which follows the topology of the node it was scheduled on
+func writeCrushLocation(cmd *cobra.Command, args []string) error {
+	if err := flags.VerifyRequiredFlags(osdCrushLocationCmd, []string{"location-file"}); err != nil {
+		return err
+	}
+	rook.SetLogLevel()
+	rook.LogStartupInfo(osdCrushLocationCmd.Flags())
+
+	topologyLabels, err := parseTopologyLabels(osdTopologyLabels)
+	if err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to parse the topology labels (%q)", osdTopologyLabels))
+	}
+
+	context := createContext()
+	crushLocation, _, err := getLocation(context.Clientset, topologyLabels)
+	if err != nil {
+		rook.TerminateFatal(err)
+	}
+	if err := ioutil.WriteFile(crushLocationFile, []byte(crushLocation), 0644); err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to write the crush location to %q", crushLocationFile))
+	}
+	logger.Infof("crush location of osd: %s", crushLocation)
+	return nil
+}
+
 func commonOSDInit(cmd *cobra.Command) {
 	rook.SetLogLevel()
 	rook.LogStartupInfo(cmd.Flags())
@@ -279,19 +346,58 @@ func commonOSDInit(cmd *cobra.Command) {
 }
 
 // use zone/region/hostname labels in the crushmap
-func getLocation(clientset kubernetes.Interface) (string, string, error) {
+func getLocation(clientset kubernetes.Interface, topologyLabels []cephv1.CrushTopologyLabel) (string, string, error) {
 	// get the value the operator instructed to use as the host name in the CRUSH map
 	hostNameLabel := os.Getenv("ROOK_CRUSHMAP_HOSTNAME")
 
 	rootLabel := os.Getenv(oposd.CrushRootVarName)
 
-	loc, topologyAffinity, err := oposd.GetLocationWithNode(clientset, os.Getenv(k8sutil.NodeNameEnvVar), rootLabel, hostNameLabel)
+	loc, topologyAffinity, err := oposd.GetLocationWithNode(clientset, os.Getenv(k8sutil.NodeNameEnvVar), rootLabel, hostNameLabel, topologyLabels)
 	if err != nil {
 		return "", "", err
 	}
 	return loc, topologyAffinity, nil
 }
 
+// Parse the drive groups, which are sent as a JSON-marshalled list of the drive groups of the node
+func parseDriveGroups(driveGroups string) ([]cephv1.DriveGroup, error) {
+	if driveGroups == "" {
+		return nil, nil
+	}
+
+	result := []cephv1.DriveGroup{}
+	if err := json.Unmarshal([]byte(driveGroups), &result); err != nil {
+		return nil, errors.Wrap(err, "failed to JSON unmarshal drive groups")
+	}
+	return result, nil
+}
+
+// Parse the topology labels, which are sent as a JSON-marshalled list of the topology labels of the storage spec
+func parseTopologyLabels(topologyLabels string) ([]cephv1.CrushTopologyLabel, error) {
+	if topologyLabels == "" {
+		return nil, nil
+	}
+
+	result := []cephv1.CrushTopologyLabel{}
+	if err := json.Unmarshal([]byte(topologyLabels), &result); err != nil {
+		return nil, errors.Wrap(err, "failed to JSON unmarshal the topology labels")
+	}
+	return result, nil
+}
+
+// Parse the replaced OSDs, which are sent as a JSON-marshalled list of the OSD replacements of the node or PVC
+func parseReplaceOSDs(replacements string) ([]cephv1.OSDReplacement, error) {
+	if replacements == "" {
+		return nil, nil
+	}
+
+	result := []cephv1.OSDReplacement{}
+	if err := json.Unmarshal([]byte(replacements), &result); err != nil {
+		return nil, errors.Wrap(err, "failed to JSON unmarshal the replaced osds")
+	}
+	return result, nil
+}
+
 // Parse the devices, which are sent as a JSON-marshalled list of device IDs with a StorageConfig spec
 func parseDevices(devices string) ([]osddaemon.DesiredDevice, error) {
 	if devices == "" {
@@ -55,6 +55,9 @@ var (
 	logLevelRaw        string
 	operatorImage      string
 	serviceAccountName string
+	kubeAPIQPS         float32
+	kubeAPIBurst       int
+	kubeAPIRetryBudget float64
 	Cfg                = &Config{}
 	logger             = capnslog.NewPackageLogger("github.com/rook/rook", "rookcmd")
 )
@@ -71,6 +74,9 @@ func init() {
 	RootCmd.PersistentFlags().StringVar(&logLevelRaw, "log-level", "INFO", "logging level for logging/tracing output (valid values: CRITICAL,ERROR,WARNING,NOTICE,INFO,DEBUG,TRACE)")
 	RootCmd.PersistentFlags().StringVar(&operatorImage, "operator-image", "", "Override the image url that the operator uses. The default is read from the operator pod.")
 	RootCmd.PersistentFlags().StringVar(&serviceAccountName, "service-account", "", "Override the service account that the operator uses. The default is read from the operator pod.")
+	RootCmd.PersistentFlags().Float32Var(&kubeAPIQPS, "kube-api-qps", k8sutil.DefaultClientQPS, "rate of the requests to the Kubernetes API per second")
+	RootCmd.PersistentFlags().IntVar(&kubeAPIBurst, "kube-api-burst", k8sutil.DefaultClientBurst, "burst of the requests to the Kubernetes API")
+	RootCmd.PersistentFlags().Float64Var(&kubeAPIRetryBudget, "kube-api-retry-budget", k8sutil.DefaultControllerRetryBudget, "share of the rate and of the burst of the requests to the Kubernetes API a single controller can use, between 0 and 1")
 
 	// load the environment variables
 	flags.SetFlagsFromEnv(RootCmd.Flags(), RookEnvVarPrefix)
@@ -149,6 +155,9 @@ func NewContext() *clusterd.Context {
 		}
 	}
 
+	// the clients built from the config share its rate limiter
+	k8sutil.ConfigureClientThrottling(context.KubeConfig, kubeAPIQPS, kubeAPIBurst, kubeAPIRetryBudget)
+
 	context.Clientset, err = kubernetes.NewForConfig(context.KubeConfig)
 	TerminateOnError(err, "failed to create k8s clientset")
 
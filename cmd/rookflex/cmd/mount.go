@@ -77,6 +77,10 @@ func handleMount(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if opts.IsBlockMode() {
+		return mapBlockDevice(client, getMounter(), devicePath, opts)
+	}
+
 	// construct the input we'll need to get the global mount path
 	driverDir, err := getDriverDir()
 	if err != nil {
@@ -202,6 +206,51 @@ func mount(client *rpc.Client, mounter *k8smount.SafeFormatAndMount, globalVolum
 	return err
 }
 
+// mapBlockDevice binds the rbd device to a file of the pod volume. The device is neither formatted nor mounted,
+// the applications managing raw devices open it directly.
+func mapBlockDevice(client *rpc.Client, mounter *k8smount.SafeFormatAndMount, devicePath string, opts *flexvolume.AttachOptions) error {
+	blockPath := path.Join(opts.MountDir, flexvolume.BlockDeviceFile)
+	log(client, fmt.Sprintf("binding device %s of volume %s/%s to %s", devicePath, opts.BlockPool, opts.Image, blockPath), false)
+	err := redirectStdout(
+		client,
+		func() error {
+			notMnt, err := mounter.Interface.IsLikelyNotMountPoint(blockPath)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("error checking if %s is a mount point: %v", blockPath, err)
+			}
+			if err == nil && !notMnt {
+				// The device is already bound
+				return nil
+			}
+			if err := os.MkdirAll(opts.MountDir, 0750); err != nil {
+				return errors.Wrap(err, "failed to create dir")
+			}
+			// A device can only be bound to a file
+			f, err := os.OpenFile(blockPath, os.O_CREATE, 0640)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create file %s", blockPath)
+			}
+			f.Close()
+
+			if err := mounter.Interface.Mount(devicePath, blockPath, "", []string{opts.RW, "bind"}); err != nil {
+				if err := os.Remove(blockPath); err != nil {
+					log(client, fmt.Sprintf("failed to remove file %s. %v", blockPath, err), false)
+				}
+				return fmt.Errorf("failed to bind device %s to %s, error %v", devicePath, blockPath, err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		log(client, fmt.Sprintf("mount volume %s/%s failed: %v", opts.BlockPool, opts.Image, err), true)
+		return err
+	}
+
+	log(client, fmt.Sprintf("volume %s/%s has been attached and its device bound to %s", opts.BlockPool, opts.Image, blockPath), false)
+	setBlockFSGroup(client, blockPath, opts)
+	return nil
+}
+
 func mountCephFS(client *rpc.Client, opts *flexvolume.AttachOptions) error {
 	if opts.FsName == "" {
 		return errors.New("Rook: Attach filesystem failed: Filesystem name is not provided")
@@ -360,3 +409,33 @@ func setFSGroup(client *rpc.Client, opts *flexvolume.AttachOptions) {
 
 	log(client, fmt.Sprintf("successfully set fsgroup to %d", fsGroup), false)
 }
+
+// setBlockFSGroup gives the fsGroup requested in the security context of the pod access to the device, as the
+// kubelet does for raw block volumes. If no fsGroup is specified, does nothing.
+func setBlockFSGroup(client *rpc.Client, blockPath string, opts *flexvolume.AttachOptions) {
+	if opts.FsGroup == "" {
+		return
+	}
+
+	fsGroup, err := strconv.Atoi(opts.FsGroup)
+	if err != nil {
+		log(client, fmt.Sprintf("invalid fsgroup %s. %+v", opts.FsGroup, err), true)
+		return
+	}
+
+	if err := os.Chown(blockPath, -1, fsGroup); err != nil {
+		log(client, fmt.Sprintf("fsgroup: chown failed on %s. %v", blockPath, err), true)
+		return
+	}
+
+	mask := os.FileMode(0660)
+	if opts.RW != "rw" {
+		mask = os.FileMode(0440)
+	}
+	if err := os.Chmod(blockPath, mask); err != nil {
+		log(client, fmt.Sprintf("fsgroup: chmod failed on %s: %+v", blockPath, err), true)
+		return
+	}
+
+	log(client, fmt.Sprintf("successfully set fsgroup of device %s to %d", blockPath, fsGroup), false)
+}
@@ -19,7 +19,9 @@ package cmd
 import (
 	"fmt"
 	"net/rpc"
+	"os"
 	"os/exec"
+	"path"
 	"strings"
 
 	"github.com/rook/rook/pkg/daemon/ceph/agent/flexvolume"
@@ -71,6 +73,10 @@ func handleUnmount(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Unmount volume at mount dir %s failed: %v", opts.MountDir, err)
 	}
 
+	if opts.IsBlockMode() {
+		return unmapBlockDevice(client, mounter, opts)
+	}
+
 	// construct the input we'll need to get the global mount path
 	driverDir, err := getDriverDir()
 	if err != nil {
@@ -121,19 +127,65 @@ func handleUnmount(cmd *cobra.Command, args []string) error {
 	}
 
 	if safeToDetach {
-		// call detach
-		log(client, fmt.Sprintf("calling agent to detach mountDir: %s", opts.MountDir), false)
-		err = client.Call("Controller.Detach", opts, nil)
-		if err != nil {
-			log(client, fmt.Sprintf("Detach volume from %s failed: %v", opts.MountDir, err), true)
-			return fmt.Errorf("Rook: Unmount volume failed: %v", err)
+		if err := detach(client, opts); err != nil {
+			return err
 		}
-		log(client, fmt.Sprintf("volume has been unmounted and detached from %s", opts.MountDir), false)
 	}
 	log(client, fmt.Sprintf("volume has been unmounted from %s", opts.MountDir), false)
 	return nil
 }
 
+// unmapBlockDevice unbinds the rbd device from the pod volume and detaches the volume once it is not used
+// by another pod of the node anymore
+func unmapBlockDevice(client *rpc.Client, mounter *k8smount.SafeFormatAndMount, opts *flexvolume.AttachOptions) error {
+	blockPath := path.Join(opts.MountDir, flexvolume.BlockDeviceFile)
+	safeToDetach := false
+	err := redirectStdout(
+		client,
+		func() error {
+			// Unbind the device from the pod volume
+			if err := k8smount.CleanupMountPoint(blockPath, mounter.Interface, false); err != nil {
+				return fmt.Errorf("failed to unbind device at %s: %+v", blockPath, err)
+			}
+			if err := os.Remove(opts.MountDir); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove dir %s: %+v", opts.MountDir, err)
+			}
+
+			// Remove attachment item from the CRD
+			err := client.Call("Controller.RemoveAttachmentObject", opts, &safeToDetach)
+			if err != nil {
+				log(client, fmt.Sprintf("Unmount volume %s failed: %v", opts.MountDir, err), true)
+				// Do not return error. Try detaching first. If error happens during detach, Kubernetes will retry.
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		log(client, fmt.Sprintf("unmount volume %s/%s failed: %v", opts.BlockPool, opts.Image, err), true)
+		return err
+	}
+
+	// The device is unmapped once no pod of the node uses it anymore
+	if safeToDetach {
+		if err := detach(client, opts); err != nil {
+			return err
+		}
+	}
+	log(client, fmt.Sprintf("device has been unbound from %s", opts.MountDir), false)
+	return nil
+}
+
+func detach(client *rpc.Client, opts *flexvolume.AttachOptions) error {
+	log(client, fmt.Sprintf("calling agent to detach mountDir: %s", opts.MountDir), false)
+	err := client.Call("Controller.Detach", opts, nil)
+	if err != nil {
+		log(client, fmt.Sprintf("Detach volume from %s failed: %v", opts.MountDir, err), true)
+		return fmt.Errorf("Rook: Unmount volume failed: %v", err)
+	}
+	log(client, fmt.Sprintf("volume has been unmounted and detached from %s", opts.MountDir), false)
+	return nil
+}
+
 func unmountCephFS(client *rpc.Client, mounter *k8smount.SafeFormatAndMount, mountDir string) error {
 	// Unmount pod mount dir
 
@@ -18,7 +18,8 @@ package v1
 
 import (
 	"reflect"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -33,21 +34,69 @@ func (c *ClusterSpec) IsStretchCluster() bool {
 	return c.Mon.StretchCluster != nil && len(c.Mon.StretchCluster.Zones) > 0
 }
 
+// IsContributor returns whether the cluster contributes local OSDs to an external cluster
+func (c *ClusterSpec) IsContributor() bool {
+	return c.External.Enable && c.External.Contributor
+}
+
+// RequireMonFailoverConfirmation returns whether the failover of the mons must be approved
+func (c *ClusterSpec) RequireMonFailoverConfirmation() bool {
+	return c.Mon.Failover != nil && c.Mon.Failover.RequireConfirmation
+}
+
+// IsMonAutoScaled returns whether the mon count is scaled with the number of failure domains
+func (c *ClusterSpec) IsMonAutoScaled() bool {
+	return c.Mon.AutoScale != nil && c.Mon.AutoScale.Enabled
+}
+
+// MonPVCRetentionPeriod returns how long the PVCs of the removed mons are kept, zero if they are deleted
+// right away
+func (c *ClusterSpec) MonPVCRetentionPeriod() time.Duration {
+	if c.Mon.Failover == nil || c.Mon.Failover.PVCRetentionPeriod == nil {
+		return 0
+	}
+	return c.Mon.Failover.PVCRetentionPeriod.Duration
+}
+
+// CephImage returns the image of the daemons of the given type, which is the hotfix image if the
+// hotfix applies to this type of daemons
+func (c *ClusterSpec) CephImage(daemonType HotfixDaemonType) string {
+	if c.CephVersion.Hotfix != nil {
+		for _, d := range c.CephVersion.Hotfix.Daemons {
+			if d == daemonType {
+				return c.CephVersion.Hotfix.Image
+			}
+		}
+	}
+	return c.CephVersion.Image
+}
+
+// SecureMetrics returns whether the mgr prometheus endpoint is served over TLS or requires the scrapes
+// to be authenticated
+func (m *MonitoringSpec) SecureMetrics() bool {
+	return m.TLS != nil || m.Auth != nil
+}
+
 func (c *CephCluster) ValidateCreate() error {
 	logger.Infof("validate create cephcluster %q", c.ObjectMeta.Name)
 	//If external mode enabled, then check if other fields are empty
 	if c.Spec.External.Enable {
-		if c.Spec.Mon != (MonSpec{}) || c.Spec.Dashboard != (DashboardSpec{}) || !reflect.DeepEqual(c.Spec.Monitoring, (MonitoringSpec{})) || c.Spec.DisruptionManagement != (DisruptionManagementSpec{}) || len(c.Spec.Mgr.Modules) > 0 || len(c.Spec.Network.Provider) > 0 || len(c.Spec.Network.Selectors) > 0 {
+		// the network of the contributed OSDs must be set so the daemons of the external cluster can reach them
+		networkSet := !c.Spec.External.Contributor && (len(c.Spec.Network.Provider) > 0 || len(c.Spec.Network.Selectors) > 0)
+		if c.Spec.Mon != (MonSpec{}) || !reflect.DeepEqual(c.Spec.Dashboard, DashboardSpec{}) || !reflect.DeepEqual(c.Spec.Monitoring, (MonitoringSpec{})) || c.Spec.DisruptionManagement != (DisruptionManagementSpec{}) || len(c.Spec.Mgr.Modules) > 0 || networkSet {
 			return errors.New("invalid create : external mode enabled cannot have mon,dashboard,monitoring,network,disruptionManagement,storage fields in CR")
 		}
 	}
-	return nil
+	return validateMinimumResources(&c.Spec)
 }
 
 func (c *CephCluster) ValidateUpdate(old runtime.Object) error {
 	logger.Infof("validate update cephcluster %q", c.ObjectMeta.Name)
 	occ := old.(*CephCluster)
-	return validateUpdatedCephCluster(c, occ)
+	if err := validateUpdatedCephCluster(c, occ); err != nil {
+		return err
+	}
+	return validateMinimumResources(&c.Spec)
 }
 
 func (c *CephCluster) ValidateDelete() error {
@@ -59,14 +108,16 @@ func validateUpdatedCephCluster(updatedCephCluster *CephCluster, found *CephClus
 		return errors.Errorf("invalid update: DataDirHostPath change from %q to %q is not allowed", found.Spec.DataDirHostPath, updatedCephCluster.Spec.DataDirHostPath)
 	}
 
-	if updatedCephCluster.Spec.Network.HostNetwork != found.Spec.Network.HostNetwork {
-		return errors.Errorf("invalid update: HostNetwork change from %q to %q is not allowed", strconv.FormatBool(found.Spec.Network.HostNetwork), strconv.FormatBool(updatedCephCluster.Spec.Network.HostNetwork))
-	}
-
+	// the host networking can be switched, the operator migrates the mons one at a time to the new network
 	if updatedCephCluster.Spec.Network.Provider != found.Spec.Network.Provider {
 		return errors.Errorf("invalid update: Provider change from %q to %q is not allowed", found.Spec.Network.Provider, updatedCephCluster.Spec.Network.Provider)
 	}
 
+	// the profile changes the pools and the drivers of the cluster, it is only set when the cluster is created
+	if updatedCephCluster.Spec.Profile != found.Spec.Profile {
+		return errors.Errorf("invalid update: Profile change from %q to %q is not allowed", found.Spec.Profile, updatedCephCluster.Spec.Profile)
+	}
+
 	for i, storageClassDeviceSet := range updatedCephCluster.Spec.Storage.StorageClassDeviceSets {
 		if storageClassDeviceSet.Encrypted != found.Spec.Storage.StorageClassDeviceSets[i].Encrypted {
 			return errors.Errorf("invalid update: StorageClassDeviceSet %q encryption change from %t to %t is not allowed", storageClassDeviceSet.Name, found.Spec.Storage.StorageClassDeviceSets[i].Encrypted, storageClassDeviceSet.Encrypted)
@@ -76,6 +127,18 @@ func validateUpdatedCephCluster(updatedCephCluster *CephCluster, found *CephClus
 	return nil
 }
 
+// validateMinimumResources refuses the resources below the minimum viable memory of the daemons if the minimum
+// resources policy is "Reject"
+func validateMinimumResources(spec *ClusterSpec) error {
+	if spec.MinimumResourcesPolicy != MinimumResourcesPolicyReject {
+		return nil
+	}
+	if below := spec.ResourcesBelowMinimum(); len(below) > 0 {
+		return errors.Errorf("invalid resources with minimumResourcesPolicy %q: %s", spec.MinimumResourcesPolicy, strings.Join(below, ", "))
+	}
+	return nil
+}
+
 func (c *CephCluster) GetStatusConditions() *[]Condition {
 	return &c.Status.Conditions
 }
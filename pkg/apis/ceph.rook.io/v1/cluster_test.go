@@ -38,7 +38,8 @@ func Test_validateUpdatedCephCluster(t *testing.T) {
 		{"even mon count", args{&CephCluster{Spec: ClusterSpec{Mon: MonSpec{Count: 2}}}, &CephCluster{}}, false},
 		{"good mon count", args{&CephCluster{Spec: ClusterSpec{Mon: MonSpec{Count: 3}}}, &CephCluster{}}, false},
 		{"changed DataDirHostPath", args{&CephCluster{Spec: ClusterSpec{DataDirHostPath: "foo"}}, &CephCluster{Spec: ClusterSpec{DataDirHostPath: "bar"}}}, true},
-		{"changed HostNetwork", args{&CephCluster{Spec: ClusterSpec{Network: NetworkSpec{HostNetwork: false}}}, &CephCluster{Spec: ClusterSpec{Network: NetworkSpec{HostNetwork: true}}}}, true},
+		{"changed HostNetwork", args{&CephCluster{Spec: ClusterSpec{Network: NetworkSpec{HostNetwork: false}}}, &CephCluster{Spec: ClusterSpec{Network: NetworkSpec{HostNetwork: true}}}}, false},
+		{"changed Provider", args{&CephCluster{Spec: ClusterSpec{Network: NetworkSpec{Provider: "multus"}}}, &CephCluster{Spec: ClusterSpec{Network: NetworkSpec{Provider: "host"}}}}, true},
 		{"changed storageClassDeviceSet encryption", args{&CephCluster{Spec: ClusterSpec{Storage: StorageScopeSpec{StorageClassDeviceSets: []StorageClassDeviceSet{{Name: "foo", Encrypted: false}}}}}, &CephCluster{Spec: ClusterSpec{Storage: StorageScopeSpec{StorageClassDeviceSets: []StorageClassDeviceSet{{Name: "foo", Encrypted: true}}}}}}, true},
 	}
 	for _, tt := range tests {
@@ -68,6 +69,15 @@ func TestCephClusterValidateCreate(t *testing.T) {
 	}
 	err = c.ValidateCreate()
 	assert.Error(t, err)
+
+	// the network of the contributed osds can be set
+	c.Spec.Monitoring = MonitoringSpec{}
+	c.Spec.Network.Provider = "host"
+	err = c.ValidateCreate()
+	assert.Error(t, err)
+	c.Spec.External.Contributor = true
+	err = c.ValidateCreate()
+	assert.NoError(t, err)
 }
 
 func TestCephClusterValidateUpdate(t *testing.T) {
@@ -87,4 +97,34 @@ func TestCephClusterValidateUpdate(t *testing.T) {
 	uc.Spec.DataDirHostPath = "var/rook"
 	err = uc.ValidateUpdate(c)
 	assert.Error(t, err)
+
+	// the host networking can be switched on an existing cluster
+	uc = c.DeepCopy()
+	uc.Spec.Network.HostNetwork = true
+	err = uc.ValidateUpdate(c)
+	assert.NoError(t, err)
+	err = c.ValidateUpdate(uc)
+	assert.NoError(t, err)
+
+	// the network provider cannot be changed
+	uc.Spec.Network.Provider = "multus"
+	err = uc.ValidateUpdate(c)
+	assert.Error(t, err)
+
+	// the profile cannot be changed
+	uc = c.DeepCopy()
+	uc.Spec.Profile = ClusterProfileArchive
+	err = uc.ValidateUpdate(c)
+	assert.Error(t, err)
+	err = c.ValidateUpdate(uc)
+	assert.Error(t, err)
+}
+
+func TestCephImage(t *testing.T) {
+	spec := ClusterSpec{CephVersion: CephVersionSpec{Image: "quay.io/ceph/ceph:v16.2.6"}}
+	assert.Equal(t, "quay.io/ceph/ceph:v16.2.6", spec.CephImage(HotfixDaemonRgw))
+
+	spec.CephVersion.Hotfix = &CephHotfixSpec{Image: "quay.io/ceph/ceph:v16.2.6-hotfix", Daemons: []HotfixDaemonType{HotfixDaemonRgw}}
+	assert.Equal(t, "quay.io/ceph/ceph:v16.2.6-hotfix", spec.CephImage(HotfixDaemonRgw))
+	assert.Equal(t, "quay.io/ceph/ceph:v16.2.6", spec.CephImage(HotfixDaemonMds))
 }
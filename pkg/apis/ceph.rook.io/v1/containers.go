@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"github.com/pkg/errors"
+	rook "github.com/rook/rook/pkg/apis/rook.io"
+	v1 "k8s.io/api/core/v1"
+)
+
+// All returns the container overrides defined for 'all' daemons in the Ceph cluster CRD.
+func (c ContainerOverridesSpec) All() DaemonContainerOverrides {
+	return c[KeyAll]
+}
+
+// GetDaemonContainerOverrides returns the container overrides of the pods of a daemon. The arguments and the
+// init containers of the daemon are added after the ones of 'all' daemons.
+func GetDaemonContainerOverrides(c ContainerOverridesSpec, name rook.KeyType) DaemonContainerOverrides {
+	overrides := c.All()
+	daemonOverrides, ok := c[name]
+	if !ok || name == KeyAll {
+		return overrides
+	}
+	return DaemonContainerOverrides{
+		ExtraArgs:      append(append([]string{}, overrides.ExtraArgs...), daemonOverrides.ExtraArgs...),
+		InitContainers: append(append([]v1.Container{}, overrides.InitContainers...), daemonOverrides.InitContainers...),
+	}
+}
+
+// ApplyToPodSpec appends the extra arguments to the main container of a pod spec, its first container, and adds
+// the init containers after the init containers of the pod
+func (o DaemonContainerOverrides) ApplyToPodSpec(spec *v1.PodSpec) {
+	if len(spec.Containers) == 0 {
+		return
+	}
+	main := &spec.Containers[0]
+	main.Args = append(main.Args, o.ExtraArgs...)
+	for i := range o.InitContainers {
+		container := o.InitContainers[i].DeepCopy()
+		if container.Image == "" {
+			container.Image = main.Image
+		}
+		spec.InitContainers = append(spec.InitContainers, *container)
+	}
+}
+
+// Validate checks that the init containers of all the daemons have a unique name
+func (c ContainerOverridesSpec) Validate() error {
+	for name := range c {
+		names := map[string]bool{}
+		for _, container := range GetDaemonContainerOverrides(c, name).InitContainers {
+			if container.Name == "" {
+				return errors.Errorf("an init container of %q has no name", name)
+			}
+			if names[container.Name] {
+				return errors.Errorf("init container %q of %q is defined more than once", container.Name, name)
+			}
+			names[container.Name] = true
+		}
+	}
+	return nil
+}
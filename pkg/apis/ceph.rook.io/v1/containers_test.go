@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func TestContainerOverridesSpec(t *testing.T) {
+	specYaml := []byte(`
+all:
+  extraArgs:
+  - --debug_ms=1
+osd:
+  extraArgs:
+  - --osd_op_num_shards=4
+  initContainers:
+  - name: udev
+    command: ["udevadm", "trigger"]
+`)
+
+	rawJSON, err := yaml.ToJSON(specYaml)
+	assert.Nil(t, err)
+
+	var overrides ContainerOverridesSpec
+	err = json.Unmarshal(rawJSON, &overrides)
+	assert.Nil(t, err)
+
+	expected := ContainerOverridesSpec{
+		"all": {ExtraArgs: []string{"--debug_ms=1"}},
+		"osd": {
+			ExtraArgs:      []string{"--osd_op_num_shards=4"},
+			InitContainers: []v1.Container{{Name: "udev", Command: []string{"udevadm", "trigger"}}},
+		},
+	}
+	assert.Equal(t, expected, overrides)
+	assert.NoError(t, overrides.Validate())
+}
+
+func TestGetDaemonContainerOverrides(t *testing.T) {
+	overrides := ContainerOverridesSpec{
+		"all": {ExtraArgs: []string{"--a"}, InitContainers: []v1.Container{{Name: "init-all"}}},
+		"osd": {ExtraArgs: []string{"--b"}, InitContainers: []v1.Container{{Name: "init-osd", Image: "busybox"}}},
+	}
+
+	// the daemons without overrides use the overrides of all the daemons
+	assert.Equal(t, overrides["all"], GetDaemonContainerOverrides(overrides, KeyMon))
+	// the overrides of the daemon are added after the overrides of all the daemons
+	osd := GetDaemonContainerOverrides(overrides, KeyOSD)
+	assert.Equal(t, []string{"--a", "--b"}, osd.ExtraArgs)
+	assert.Equal(t, []v1.Container{{Name: "init-all"}, {Name: "init-osd", Image: "busybox"}}, osd.InitContainers)
+	assert.Equal(t, DaemonContainerOverrides{}, GetDaemonContainerOverrides(ContainerOverridesSpec{}, KeyMgr))
+
+	podSpec := &v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "chown", Image: "ceph"}},
+		Containers:     []v1.Container{{Name: "osd", Image: "ceph", Args: []string{"--foreground"}}, {Name: "log-collector"}},
+	}
+	osd.ApplyToPodSpec(podSpec)
+	assert.Equal(t, []string{"--foreground", "--a", "--b"}, podSpec.Containers[0].Args)
+	assert.Nil(t, podSpec.Containers[1].Args)
+	// the init containers without image run the image of the main container
+	assert.Equal(t, []v1.Container{{Name: "chown", Image: "ceph"}, {Name: "init-all", Image: "ceph"}, {Name: "init-osd", Image: "busybox"}}, podSpec.InitContainers)
+	assert.Equal(t, "", overrides["all"].InitContainers[0].Image)
+
+	// the init containers require a unique name
+	overrides["mgr"] = DaemonContainerOverrides{InitContainers: []v1.Container{{Name: "init-all"}}}
+	assert.Error(t, overrides.Validate())
+	overrides["mgr"] = DaemonContainerOverrides{InitContainers: []v1.Container{{Image: "busybox"}}}
+	assert.Error(t, overrides.Validate())
+	delete(overrides, "mgr")
+	assert.NoError(t, overrides.Validate())
+}
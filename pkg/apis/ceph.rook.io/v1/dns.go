@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"github.com/pkg/errors"
+	rook "github.com/rook/rook/pkg/apis/rook.io"
+	v1 "k8s.io/api/core/v1"
+)
+
+// All returns the DNS settings defined for 'all' daemons in the Ceph cluster CRD.
+func (d DNSSpec) All() DaemonDNSSpec {
+	return d[KeyAll]
+}
+
+// GetDaemonDNS returns the DNS settings of the pods of a daemon. The policy and the config of the daemon
+// override the ones of 'all' daemons separately.
+func GetDaemonDNS(d DNSSpec, name rook.KeyType) DaemonDNSSpec {
+	dns := d.All()
+	daemonDNS, ok := d[name]
+	if !ok {
+		return dns
+	}
+	if daemonDNS.Policy != "" {
+		dns.Policy = daemonDNS.Policy
+	}
+	if daemonDNS.Config != nil {
+		dns.Config = daemonDNS.Config
+	}
+	return dns
+}
+
+// ApplyToPodSpec sets the DNS policy and the DNS config of a pod spec if they are defined
+func (d DaemonDNSSpec) ApplyToPodSpec(spec *v1.PodSpec) {
+	if d.Policy != "" {
+		spec.DNSPolicy = d.Policy
+	}
+	if d.Config != nil {
+		spec.DNSConfig = d.Config.DeepCopy()
+	}
+}
+
+// Validate checks that the DNS settings of all the daemons can be applied to their pods
+func (d DNSSpec) Validate() error {
+	for name := range d {
+		dns := GetDaemonDNS(d, name)
+		if dns.Policy == v1.DNSNone && (dns.Config == nil || len(dns.Config.Nameservers) == 0) {
+			return errors.Errorf("dns policy %q of %q requires at least one nameserver in the dns config", v1.DNSNone, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func TestDNSSpec(t *testing.T) {
+	specYaml := []byte(`
+all:
+  policy: ClusterFirstWithHostNet
+mon:
+  policy: None
+  config:
+    nameservers:
+    - 10.0.0.10
+    searches:
+    - example.com
+`)
+
+	rawJSON, err := yaml.ToJSON(specYaml)
+	assert.Nil(t, err)
+
+	var dns DNSSpec
+	err = json.Unmarshal(rawJSON, &dns)
+	assert.Nil(t, err)
+
+	expected := DNSSpec{
+		"all": {Policy: v1.DNSClusterFirstWithHostNet},
+		"mon": {
+			Policy: v1.DNSNone,
+			Config: &v1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}, Searches: []string{"example.com"}},
+		},
+	}
+	assert.Equal(t, expected, dns)
+	assert.NoError(t, dns.Validate())
+}
+
+func TestGetDaemonDNS(t *testing.T) {
+	config := &v1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}}
+	dns := DNSSpec{
+		"all": {Policy: v1.DNSClusterFirst, Config: config},
+		"mgr": {Policy: v1.DNSDefault},
+	}
+
+	// the daemons without settings use the settings of all the daemons
+	assert.Equal(t, DaemonDNSSpec{Policy: v1.DNSClusterFirst, Config: config}, GetDaemonDNS(dns, KeyOSD))
+	// the policy of the daemon overrides the policy of all the daemons
+	assert.Equal(t, DaemonDNSSpec{Policy: v1.DNSDefault, Config: config}, GetDaemonDNS(dns, KeyMgr))
+	assert.Equal(t, DaemonDNSSpec{}, GetDaemonDNS(DNSSpec{}, KeyMon))
+
+	podSpec := &v1.PodSpec{DNSPolicy: v1.DNSClusterFirstWithHostNet}
+	DaemonDNSSpec{}.ApplyToPodSpec(podSpec)
+	assert.Equal(t, v1.DNSClusterFirstWithHostNet, podSpec.DNSPolicy)
+	assert.Nil(t, podSpec.DNSConfig)
+	GetDaemonDNS(dns, KeyMgr).ApplyToPodSpec(podSpec)
+	assert.Equal(t, v1.DNSDefault, podSpec.DNSPolicy)
+	assert.Equal(t, config, podSpec.DNSConfig)
+
+	// the none policy requires a nameserver
+	dns = DNSSpec{"osd": {Policy: v1.DNSNone}}
+	assert.Error(t, dns.Validate())
+	dns["all"] = DaemonDNSSpec{Config: config}
+	assert.NoError(t, dns.Validate())
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ValidateDriveGroups validates the drive groups of the storage spec
+func (s *StorageScopeSpec) ValidateDriveGroups() error {
+	names := map[string]bool{}
+	for _, driveGroup := range s.DriveGroups {
+		if driveGroup.Name == "" {
+			return errors.New("the name of a drive group is required")
+		}
+		if names[driveGroup.Name] {
+			return errors.Errorf("drive group %q is defined more than once", driveGroup.Name)
+		}
+		names[driveGroup.Name] = true
+
+		filters := map[string]*DriveGroupDeviceFilter{
+			"dataDevices": &driveGroup.DataDevices,
+			"dbDevices":   driveGroup.DBDevices,
+			"walDevices":  driveGroup.WALDevices,
+		}
+		for role, filter := range filters {
+			if filter == nil {
+				continue
+			}
+			if err := filter.validate(); err != nil {
+				return errors.Wrapf(err, "invalid %s of drive group %q", role, driveGroup.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (f *DriveGroupDeviceFilter) validate() error {
+	if _, _, err := f.sizeRange(); err != nil {
+		return err
+	}
+	if _, err := regexp.Compile(f.Model); err != nil {
+		return errors.Wrapf(err, "invalid model %q", f.Model)
+	}
+	if _, err := regexp.Compile(f.Vendor); err != nil {
+		return errors.Wrapf(err, "invalid vendor %q", f.Vendor)
+	}
+	if f.Limit < 0 {
+		return errors.Errorf("invalid limit %d", f.Limit)
+	}
+	return nil
+}
+
+// Matches returns whether a disk with the given properties is selected by the filter
+func (f *DriveGroupDeviceFilter) Matches(rotational bool, size uint64, model, vendor string) (bool, error) {
+	if f.Rotational != nil && *f.Rotational != rotational {
+		return false, nil
+	}
+
+	minSize, maxSize, err := f.sizeRange()
+	if err != nil {
+		return false, err
+	}
+	if size < minSize || (maxSize > 0 && size > maxSize) {
+		return false, nil
+	}
+
+	if matched, err := matchesPattern(f.Model, model); err != nil || !matched {
+		return false, err
+	}
+	return matchesPattern(f.Vendor, vendor)
+}
+
+func matchesPattern(pattern, value string) (bool, error) {
+	if pattern == "" {
+		return true, nil
+	}
+	matched, err := regexp.MatchString(pattern, strings.TrimSpace(value))
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid regular expression %q", pattern)
+	}
+	return matched, nil
+}
+
+// sizeRange returns the minimum and the maximum size in bytes of the filter, the maximum being 0 if unbounded
+func (f *DriveGroupDeviceFilter) sizeRange() (uint64, uint64, error) {
+	if f.Size == "" {
+		return 0, 0, nil
+	}
+	if !strings.Contains(f.Size, ":") {
+		size, err := parseDriveGroupSize(f.Size)
+		return size, size, err
+	}
+
+	bounds := strings.SplitN(f.Size, ":", 2)
+	var minSize, maxSize uint64
+	var err error
+	if bounds[0] != "" {
+		if minSize, err = parseDriveGroupSize(bounds[0]); err != nil {
+			return 0, 0, err
+		}
+	}
+	if bounds[1] != "" {
+		if maxSize, err = parseDriveGroupSize(bounds[1]); err != nil {
+			return 0, 0, err
+		}
+		if maxSize < minSize {
+			return 0, 0, errors.Errorf("invalid size %q, the minimum is greater than the maximum", f.Size)
+		}
+	}
+	return minSize, maxSize, nil
+}
+
+func parseDriveGroupSize(size string) (uint64, error) {
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid size %q", size)
+	}
+	if quantity.Sign() < 0 {
+		return 0, errors.Errorf("invalid negative size %q", size)
+	}
+	return uint64(quantity.Value()), nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDriveGroups(t *testing.T) {
+	spec := StorageScopeSpec{}
+	assert.NoError(t, spec.ValidateDriveGroups())
+
+	spec.DriveGroups = []DriveGroup{
+		{Name: "hdd", DataDevices: DriveGroupDeviceFilter{Size: "1Ti:"}, DBDevices: &DriveGroupDeviceFilter{Model: "^Samsung"}},
+		{Name: "ssd", DataDevices: DriveGroupDeviceFilter{Size: ":2Ti", Limit: 4}},
+	}
+	assert.NoError(t, spec.ValidateDriveGroups())
+
+	t.Run("missing name", func(t *testing.T) {
+		s := spec.DeepCopy()
+		s.DriveGroups[1].Name = ""
+		assert.Error(t, s.ValidateDriveGroups())
+	})
+	t.Run("duplicate name", func(t *testing.T) {
+		s := spec.DeepCopy()
+		s.DriveGroups[1].Name = "hdd"
+		assert.Error(t, s.ValidateDriveGroups())
+	})
+	t.Run("invalid size", func(t *testing.T) {
+		for _, size := range []string{"big", "2Ti:1Ti", "-1Gi:"} {
+			s := spec.DeepCopy()
+			s.DriveGroups[0].DataDevices.Size = size
+			assert.Error(t, s.ValidateDriveGroups(), size)
+		}
+	})
+	t.Run("invalid model", func(t *testing.T) {
+		s := spec.DeepCopy()
+		s.DriveGroups[0].DBDevices.Model = "("
+		assert.Error(t, s.ValidateDriveGroups())
+	})
+	t.Run("invalid limit", func(t *testing.T) {
+		s := spec.DeepCopy()
+		s.DriveGroups[1].DataDevices.Limit = -1
+		assert.Error(t, s.ValidateDriveGroups())
+	})
+}
+
+func TestDriveGroupDeviceFilterMatches(t *testing.T) {
+	rotational := true
+	tests := []struct {
+		name       string
+		filter     DriveGroupDeviceFilter
+		rotational bool
+		size       uint64
+		model      string
+		vendor     string
+		expected   bool
+	}{
+		{"empty filter", DriveGroupDeviceFilter{}, false, 1, "", "", true},
+		{"rotational", DriveGroupDeviceFilter{Rotational: &rotational}, true, 1, "", "", true},
+		{"not rotational", DriveGroupDeviceFilter{Rotational: &rotational}, false, 1, "", "", false},
+		{"above minimum", DriveGroupDeviceFilter{Size: "1Ti:"}, false, 2 << 40, "", "", true},
+		{"below minimum", DriveGroupDeviceFilter{Size: "1Ti:"}, false, 1 << 39, "", "", false},
+		{"below maximum", DriveGroupDeviceFilter{Size: ":1Ti"}, false, 1 << 40, "", "", true},
+		{"above maximum", DriveGroupDeviceFilter{Size: ":1Ti"}, false, 2 << 40, "", "", false},
+		{"in range", DriveGroupDeviceFilter{Size: "100G:2T"}, false, 1000000000000, "", "", true},
+		{"exact size", DriveGroupDeviceFilter{Size: "1Ti"}, false, 1 << 40, "", "", true},
+		{"not exact size", DriveGroupDeviceFilter{Size: "1Ti"}, false, 1000000000000, "", "", false},
+		{"model", DriveGroupDeviceFilter{Model: "^Samsung SSD"}, false, 1, "Samsung SSD 970  ", "", true},
+		{"other model", DriveGroupDeviceFilter{Model: "^Samsung SSD"}, false, 1, "INTEL SSD", "", false},
+		{"vendor", DriveGroupDeviceFilter{Vendor: "ATA|SEAGATE"}, false, 1, "", "SEAGATE ", true},
+		{"other vendor", DriveGroupDeviceFilter{Vendor: "ATA|SEAGATE"}, false, 1, "", "WDC", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := tt.filter.Matches(tt.rotational, tt.size, tt.model, tt.vendor)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, matched)
+		})
+	}
+
+	_, err := (&DriveGroupDeviceFilter{Size: "big"}).Matches(false, 1, "", "")
+	assert.Error(t, err)
+}
@@ -30,4 +30,9 @@ const (
 	KeyOSD        rookcore.KeyType = "osd"
 	KeyCleanup    rookcore.KeyType = "cleanup"
 	KeyMonitoring rookcore.KeyType = "monitoring"
+	KeyNodeTuning rookcore.KeyType = "nodetuning"
+	KeyRGW        rookcore.KeyType = "rgw"
+	KeyRBDMirror  rookcore.KeyType = "rbdmirror"
+	KeyFSMirror   rookcore.KeyType = "fsmirror"
+	KeyNFS        rookcore.KeyType = "nfs"
 )
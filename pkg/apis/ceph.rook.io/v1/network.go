@@ -16,6 +16,10 @@ limitations under the License.
 
 package v1
 
+import (
+	"github.com/pkg/errors"
+)
+
 // IsMultus get whether to use multus network provider
 func (n *NetworkSpec) IsMultus() bool {
 	return n.Provider == "multus"
@@ -26,3 +30,62 @@ func (n *NetworkSpec) IsMultus() bool {
 func (n *NetworkSpec) IsHost() bool {
 	return (n.HostNetwork && n.Provider == "") || n.Provider == "host"
 }
+
+// RequireMsgr2 get whether only the msgr2 protocol is accepted by the mons
+func (n *NetworkSpec) RequireMsgr2() bool {
+	return n.Connections != nil && n.Connections.RequireMsgr2
+}
+
+// EncryptionEnforced get whether the secure mode is required for all the connections
+func (n *NetworkSpec) EncryptionEnforced() bool {
+	return n.Connections != nil && n.Connections.Encryption != nil && n.Connections.Encryption.Enforced
+}
+
+// GetIPFamilies returns the IP families Ceph daemons should listen on, the primary family first. The ipFamilies
+// take precedence over the ipFamily and dualStack settings. Nil is returned if no family is configured.
+func (n *NetworkSpec) GetIPFamilies() []IPFamilyType {
+	if len(n.IPFamilies) > 0 {
+		return n.IPFamilies
+	}
+	if n.DualStack {
+		if n.IPFamily == IPv6 {
+			return []IPFamilyType{IPv6, IPv4}
+		}
+		return []IPFamilyType{IPv4, IPv6}
+	}
+	if n.IPFamily != "" {
+		return []IPFamilyType{n.IPFamily}
+	}
+	return nil
+}
+
+// IsDualStack get whether Ceph daemons should listen on both IPv4 and IPv6
+func (n *NetworkSpec) IsDualStack() bool {
+	return len(n.GetIPFamilies()) == 2
+}
+
+// ValidateIPFamilies checks the ipFamilies are known and unique, and consistent with the ipFamily and dualStack
+// settings
+func (n *NetworkSpec) ValidateIPFamilies() error {
+	if len(n.IPFamilies) == 0 {
+		return nil
+	}
+	if len(n.IPFamilies) > 2 {
+		return errors.Errorf("at most two ip families can be set, got %v", n.IPFamilies)
+	}
+	for i, family := range n.IPFamilies {
+		if family != IPv4 && family != IPv6 {
+			return errors.Errorf("invalid ip family %q, expected %q or %q", family, IPv4, IPv6)
+		}
+		if i > 0 && family == n.IPFamilies[0] {
+			return errors.Errorf("duplicate ip family %q", family)
+		}
+	}
+	if n.IPFamily != "" && n.IPFamily != n.IPFamilies[0] {
+		return errors.Errorf("ipFamily %q must be the first of the ipFamilies %v", n.IPFamily, n.IPFamilies)
+	}
+	if n.DualStack && len(n.IPFamilies) != 2 {
+		return errors.Errorf("dualStack requires both ip families in the ipFamilies, got %v", n.IPFamilies)
+	}
+	return nil
+}
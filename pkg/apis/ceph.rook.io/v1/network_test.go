@@ -71,3 +71,64 @@ selectors:
 
 	assert.Equal(t, expected, net)
 }
+
+func TestNetworkConnections(t *testing.T) {
+	netSpecYAML := []byte(`
+connections:
+  requireMsgr2: true
+  encryption:
+    enforced: true`)
+
+	rawJSON, err := yaml.ToJSON(netSpecYAML)
+	assert.Nil(t, err)
+
+	var net NetworkSpec
+
+	err = json.Unmarshal(rawJSON, &net)
+	assert.Nil(t, err)
+	assert.True(t, net.RequireMsgr2())
+	assert.True(t, net.EncryptionEnforced())
+
+	net = NetworkSpec{}
+	assert.False(t, net.RequireMsgr2())
+	assert.False(t, net.EncryptionEnforced())
+
+	net.Connections = &ConnectionsSpec{RequireMsgr2: true}
+	assert.True(t, net.RequireMsgr2())
+	assert.False(t, net.EncryptionEnforced())
+}
+
+func TestNetworkIPFamilies(t *testing.T) {
+	net := NetworkSpec{}
+	assert.Nil(t, net.GetIPFamilies())
+	assert.False(t, net.IsDualStack())
+	assert.NoError(t, net.ValidateIPFamilies())
+
+	net.IPFamily = IPv6
+	assert.Equal(t, []IPFamilyType{IPv6}, net.GetIPFamilies())
+	assert.False(t, net.IsDualStack())
+
+	net.DualStack = true
+	assert.Equal(t, []IPFamilyType{IPv6, IPv4}, net.GetIPFamilies())
+	assert.True(t, net.IsDualStack())
+	net.IPFamily = ""
+	assert.Equal(t, []IPFamilyType{IPv4, IPv6}, net.GetIPFamilies())
+
+	// the ipFamilies take precedence
+	net = NetworkSpec{IPFamilies: []IPFamilyType{IPv6}}
+	assert.Equal(t, []IPFamilyType{IPv6}, net.GetIPFamilies())
+	assert.NoError(t, net.ValidateIPFamilies())
+	net.IPFamilies = []IPFamilyType{IPv6, IPv4}
+	assert.True(t, net.IsDualStack())
+	assert.NoError(t, net.ValidateIPFamilies())
+
+	net.IPFamilies = []IPFamilyType{IPv6, IPv6}
+	assert.Error(t, net.ValidateIPFamilies())
+	net.IPFamilies = []IPFamilyType{"IPv5"}
+	assert.Error(t, net.ValidateIPFamilies())
+	net.IPFamilies = []IPFamilyType{IPv4, IPv6}
+	net.IPFamily = IPv6
+	assert.Error(t, net.ValidateIPFamilies())
+	net = NetworkSpec{IPFamilies: []IPFamilyType{IPv4}, DualStack: true}
+	assert.Error(t, net.ValidateIPFamilies())
+}
@@ -76,6 +76,41 @@ func ValidateObjectSpec(gs *CephObjectStore) error {
 	if gs.Spec.Gateway.Port <= 0 && gs.Spec.Gateway.SecurePort <= 0 {
 		return errors.New("invalid create: either of port or securePort fields should be not be zero")
 	}
+	return validateInstanceGroups(&gs.Spec)
+}
+
+func validateInstanceGroups(spec *ObjectStoreSpec) error {
+	if len(spec.Gateway.InstanceGroups) == 0 {
+		return nil
+	}
+	if spec.IsExternal() {
+		return errors.New("instance groups are not supported by an external object store")
+	}
+	names := map[string]bool{}
+	weight := int32(0)
+	for _, group := range spec.Gateway.InstanceGroups {
+		if group.Name == "" {
+			return errors.New("missing name of instance group")
+		}
+		// the name of the deployment of the gateway pods ends with "a"
+		if group.Name == "a" {
+			return errors.Errorf("instance group name %q is reserved", group.Name)
+		}
+		if names[group.Name] {
+			return errors.Errorf("duplicate instance group %q", group.Name)
+		}
+		names[group.Name] = true
+		if group.Instances < 1 {
+			return errors.Errorf("instance group %q must have at least one instance", group.Name)
+		}
+		if group.Weight < 0 || group.Weight > 100 {
+			return errors.Errorf("weight %d of instance group %q must be between 0 and 100", group.Weight, group.Name)
+		}
+		weight += group.Weight
+	}
+	if weight > 100 {
+		return errors.Errorf("the weights of the instance groups add up to %d, more than 100", weight)
+	}
 	return nil
 }
 
@@ -21,6 +21,7 @@ import (
 
 	rook "github.com/rook/rook/pkg/apis/rook.io"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -60,6 +61,35 @@ func TestValidateObjectStoreSpec(t *testing.T) {
 	err = ValidateObjectSpec(o)
 	assert.Error(t, err)
 }
+
+func TestValidateInstanceGroups(t *testing.T) {
+	spec := &ObjectStoreSpec{Gateway: GatewaySpec{Port: 80}}
+	assert.NoError(t, validateInstanceGroups(spec))
+
+	spec.Gateway.InstanceGroups = []RGWInstanceGroupSpec{{Name: "new", Instances: 2, Weight: 60}, {Name: "canary", Instances: 1, Weight: 40}}
+	assert.NoError(t, validateInstanceGroups(spec))
+
+	// the weights add up to more than 100
+	spec.Gateway.InstanceGroups[1].Weight = 41
+	assert.Error(t, validateInstanceGroups(spec))
+	spec.Gateway.InstanceGroups[1].Weight = 10
+
+	// duplicate and reserved names
+	spec.Gateway.InstanceGroups[1].Name = "new"
+	assert.Error(t, validateInstanceGroups(spec))
+	spec.Gateway.InstanceGroups[1].Name = "a"
+	assert.Error(t, validateInstanceGroups(spec))
+	spec.Gateway.InstanceGroups[1].Name = "canary"
+
+	// no instance
+	spec.Gateway.InstanceGroups[1].Instances = 0
+	assert.Error(t, validateInstanceGroups(spec))
+	spec.Gateway.InstanceGroups[1].Instances = 1
+
+	// external object store
+	spec.Gateway.ExternalRgwEndpoints = []v1.EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateInstanceGroups(spec))
+}
 func TestIsTLSEnabled(t *testing.T) {
 	objStore := &CephObjectStore{
 		ObjectMeta: metav1.ObjectMeta{
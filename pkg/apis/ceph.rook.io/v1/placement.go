@@ -145,3 +145,9 @@ func GetArbiterPlacement(p PlacementSpec) Placement {
 func GetOSDPlacement(p PlacementSpec) Placement {
 	return p.All().Merge(p[KeyOSD])
 }
+
+// GetNodeTuningPlacement returns the placement for the node tuning daemonset. The daemonset runs on
+// the storage nodes, so it starts from the OSD placement.
+func GetNodeTuningPlacement(p PlacementSpec) Placement {
+	return GetOSDPlacement(p).Merge(p[KeyNodeTuning])
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// the erasure coding profile of the data pool of the object stores of an archive cluster tolerates the
+	// loss of three hosts with an overhead of 37.5%
+	archiveDataChunks   = 8
+	archiveCodingChunks = 3
+)
+
+// IsObjectOnly returns whether the cluster only serves objects, so the block and filesystem csi drivers
+// are not needed
+func (c *ClusterSpec) IsObjectOnly() bool {
+	return c.Profile == ClusterProfileArchive
+}
+
+// ApplyProfileDefaults applies the defaults of the profile to the settings not set in the cluster spec.
+// The resources are copied, so the resources of the object the spec was read from are not changed.
+func (c *ClusterSpec) ApplyProfileDefaults() {
+	if c.Profile != ClusterProfileArchive {
+		return
+	}
+
+	// a single mgr with a small footprint, the cluster has no block or filesystem clients to serve
+	if c.Mgr.Count == 0 {
+		c.Mgr.Count = 1
+	}
+	if _, ok := c.Resources[ResourcesKeyMgr]; !ok {
+		resources := ResourceSpec{}
+		for key, r := range c.Resources {
+			resources[key] = r
+		}
+		resources[ResourcesKeyMgr] = v1.ResourceRequirements{
+			Limits: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("500m"),
+				v1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("100m"),
+				v1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		}
+		c.Resources = resources
+	}
+}
+
+// ApplyProfileDefaults applies the defaults of the profile of the cluster to the pools of the object store
+// whose replication or erasure coding is not set. The pools of a store in a multisite zone or of an external
+// store are not created by the store, so they are left untouched.
+func (s *ObjectStoreSpec) ApplyProfileDefaults(profile ClusterProfile) {
+	if profile != ClusterProfileArchive || s.IsMultisite() || s.IsExternal() {
+		return
+	}
+
+	if !s.MetadataPool.IsReplicated() && !s.MetadataPool.IsErasureCoded() {
+		if s.MetadataPool.FailureDomain == "" {
+			s.MetadataPool.FailureDomain = "host"
+		}
+		s.MetadataPool.Replicated.Size = 3
+		s.MetadataPool.Replicated.RequireSafeReplicaSize = true
+	}
+
+	if !s.DataPool.IsReplicated() && !s.DataPool.IsErasureCoded() {
+		if s.DataPool.FailureDomain == "" {
+			s.DataPool.FailureDomain = "host"
+		}
+		s.DataPool.ErasureCoded.DataChunks = archiveDataChunks
+		s.DataPool.ErasureCoded.CodingChunks = archiveCodingChunks
+		parameters := map[string]string{
+			// the pool is expected to hold most of the data of the cluster
+			"target_size_ratio": ".9",
+		}
+		if s.DataPool.CompressionMode == "" {
+			// most archived data is already compressed
+			parameters["compression_mode"] = "none"
+		}
+		for key, value := range s.DataPool.Parameters {
+			parameters[key] = value
+		}
+		s.DataPool.Parameters = parameters
+	}
+}
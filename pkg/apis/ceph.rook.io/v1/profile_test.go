@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestClusterApplyProfileDefaults(t *testing.T) {
+	// no defaults without a profile
+	spec := &ClusterSpec{}
+	spec.ApplyProfileDefaults()
+	assert.Equal(t, 0, spec.Mgr.Count)
+	assert.Nil(t, spec.Resources)
+	assert.False(t, spec.IsObjectOnly())
+
+	// the archive defaults
+	osdResources := v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")}}
+	resources := ResourceSpec{ResourcesKeyOSD: osdResources}
+	spec = &ClusterSpec{Profile: ClusterProfileArchive, Resources: resources}
+	spec.ApplyProfileDefaults()
+	assert.True(t, spec.IsObjectOnly())
+	assert.Equal(t, 1, spec.Mgr.Count)
+	mgrMemory := spec.Resources[ResourcesKeyMgr].Limits[v1.ResourceMemory]
+	assert.Equal(t, "512Mi", mgrMemory.String())
+	assert.Equal(t, osdResources, spec.Resources[ResourcesKeyOSD])
+	// the resources of the spec are copied
+	_, ok := resources[ResourcesKeyMgr]
+	assert.False(t, ok)
+
+	// the settings of the spec are kept
+	mgrResources := v1.ResourceRequirements{Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}}
+	spec = &ClusterSpec{Profile: ClusterProfileArchive, Mgr: MgrSpec{Count: 2}, Resources: ResourceSpec{ResourcesKeyMgr: mgrResources}}
+	spec.ApplyProfileDefaults()
+	assert.Equal(t, 2, spec.Mgr.Count)
+	assert.Equal(t, mgrResources, spec.Resources[ResourcesKeyMgr])
+}
+
+func TestObjectStoreApplyProfileDefaults(t *testing.T) {
+	// no defaults without a profile
+	spec := &ObjectStoreSpec{}
+	spec.ApplyProfileDefaults("")
+	assert.Equal(t, ObjectStoreSpec{}, *spec)
+
+	// the archive defaults
+	spec = &ObjectStoreSpec{DataPool: PoolSpec{Parameters: map[string]string{"target_size_ratio": ".5"}}}
+	spec.ApplyProfileDefaults(ClusterProfileArchive)
+	assert.Equal(t, uint(3), spec.MetadataPool.Replicated.Size)
+	assert.Equal(t, "host", spec.MetadataPool.FailureDomain)
+	assert.Equal(t, uint(8), spec.DataPool.ErasureCoded.DataChunks)
+	assert.Equal(t, uint(3), spec.DataPool.ErasureCoded.CodingChunks)
+	assert.Equal(t, "host", spec.DataPool.FailureDomain)
+	assert.Equal(t, map[string]string{"target_size_ratio": ".5", "compression_mode": "none"}, spec.DataPool.Parameters)
+
+	// the pools with a replication or erasure coding are kept
+	spec = &ObjectStoreSpec{
+		MetadataPool: PoolSpec{Replicated: ReplicatedSpec{Size: 2}},
+		DataPool:     PoolSpec{FailureDomain: "rack", ErasureCoded: ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}},
+	}
+	spec.ApplyProfileDefaults(ClusterProfileArchive)
+	assert.Equal(t, uint(2), spec.MetadataPool.Replicated.Size)
+	assert.Equal(t, "", spec.MetadataPool.FailureDomain)
+	assert.Equal(t, uint(4), spec.DataPool.ErasureCoded.DataChunks)
+	assert.Nil(t, spec.DataPool.Parameters)
+
+	// the pools of a multisite zone are not created by the store
+	spec = &ObjectStoreSpec{Zone: ZoneSpec{Name: "zone-a"}}
+	spec.ApplyProfileDefaults(ClusterProfileArchive)
+	assert.False(t, spec.DataPool.IsErasureCoded())
+}
@@ -56,6 +56,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&CephClient{},
 		&CephClientList{},
+		&CephAuthExport{},
+		&CephAuthExportList{},
 		&CephCluster{},
 		&CephClusterList{},
 		&CephBlockPool{},
@@ -74,10 +76,22 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephObjectZoneGroupList{},
 		&CephObjectZone{},
 		&CephObjectZoneList{},
+		&CephBucketNotification{},
+		&CephBucketNotificationList{},
+		&CephBucketTopic{},
+		&CephBucketTopicList{},
 		&CephRBDMirror{},
 		&CephRBDMirrorList{},
 		&CephFilesystemMirror{},
 		&CephFilesystemMirrorList{},
+		&CephFilesystemSubVolumeGroup{},
+		&CephFilesystemSubVolumeGroupList{},
+		&CephFilesystemStaticVolume{},
+		&CephFilesystemStaticVolumeList{},
+		&CephBenchmark{},
+		&CephBenchmarkList{},
+		&CephPoolMigration{},
+		&CephPoolMigrationList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
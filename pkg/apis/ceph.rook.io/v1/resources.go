@@ -17,7 +17,12 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
@@ -43,8 +48,21 @@ const (
 	ResourcesKeyFilesystemMirror = "fsmirror"
 	// ResourcesKeyCleanup represents the name of resource in the CR for the cleanup
 	ResourcesKeyCleanup = "cleanup"
+	// ResourcesKeyNodeTuning represents the name of resource in the CR for the node tuning daemonset
+	ResourcesKeyNodeTuning = "nodetuning"
 )
 
+// minimumMemoryMB is the minimum viable memory in MB of the daemons of the keys of the resources. The daemons
+// with less memory are expected to be OOM-killed under load, such as the OSDs during a backfill.
+var minimumMemoryMB = map[string]int64{
+	ResourcesKeyMon:            1024,
+	ResourcesKeyMgr:            512,
+	ResourcesKeyMgrSidecar:     40,
+	ResourcesKeyOSD:            2048,
+	ResourcesKeyPrepareOSD:     50,
+	ResourcesKeyCrashCollector: 60,
+}
+
 // GetMgrResources returns the placement for the MGR service
 func GetMgrResources(p ResourceSpec) v1.ResourceRequirements {
 	return p[ResourcesKeyMgr]
@@ -97,3 +115,92 @@ func GetLogCollectorResources(p ResourceSpec) v1.ResourceRequirements {
 func GetCleanupResources(p ResourceSpec) v1.ResourceRequirements {
 	return p[ResourcesKeyCleanup]
 }
+
+// GetNodeTuningResources returns the resources for the node tuning daemonset
+func GetNodeTuningResources(p ResourceSpec) v1.ResourceRequirements {
+	return p[ResourcesKeyNodeTuning]
+}
+
+// GetMinimumMemory returns the minimum viable memory of the daemons of a key of the resources. The
+// osd-<deviceClass> keys have the minimum of the OSDs.
+func GetMinimumMemory(key string) (resource.Quantity, bool) {
+	if strings.HasPrefix(key, ResourcesKeyOSD+"-") {
+		key = ResourcesKeyOSD
+	}
+	mb, ok := minimumMemoryMB[key]
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	return *resource.NewQuantity(mb*1024*1024, resource.BinarySI), true
+}
+
+// ResourcesBelowMinimum returns the memory requests and limits below the minimum viable memory of the daemons,
+// in the resources of the cluster and of the storage class device sets
+func (s *ClusterSpec) ResourcesBelowMinimum() []string {
+	keys := make([]string, 0, len(s.Resources))
+	for key := range s.Resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	below := []string{}
+	for _, key := range keys {
+		if minimum, ok := GetMinimumMemory(key); ok {
+			below = append(below, memoryBelowMinimum(fmt.Sprintf("%q", key), s.Resources[key], minimum)...)
+		}
+	}
+	osdMinimum, _ := GetMinimumMemory(ResourcesKeyOSD)
+	for _, deviceSet := range s.Storage.StorageClassDeviceSets {
+		below = append(below, memoryBelowMinimum(fmt.Sprintf("storageClassDeviceSet %q", deviceSet.Name), deviceSet.Resources, osdMinimum)...)
+	}
+	return below
+}
+
+// RaiseResourcesToMinimum raises the memory requests and limits below the minimum viable memory of the daemons
+// to the minimum. The resources are copied, so the resources of the object the spec was read from are not changed.
+func (s *ClusterSpec) RaiseResourcesToMinimum() {
+	resources := ResourceSpec{}
+	for key, r := range s.Resources {
+		if minimum, ok := GetMinimumMemory(key); ok {
+			r = raiseMemoryToMinimum(r, minimum)
+		}
+		resources[key] = r
+	}
+	if s.Resources != nil {
+		s.Resources = resources
+	}
+
+	osdMinimum, _ := GetMinimumMemory(ResourcesKeyOSD)
+	deviceSets := make([]StorageClassDeviceSet, 0, len(s.Storage.StorageClassDeviceSets))
+	for _, deviceSet := range s.Storage.StorageClassDeviceSets {
+		deviceSet.Resources = raiseMemoryToMinimum(deviceSet.Resources, osdMinimum)
+		deviceSets = append(deviceSets, deviceSet)
+	}
+	if s.Storage.StorageClassDeviceSets != nil {
+		s.Storage.StorageClassDeviceSets = deviceSets
+	}
+}
+
+// memoryBelowMinimum describes the memory request and limit of the resources below the minimum
+func memoryBelowMinimum(name string, resources v1.ResourceRequirements, minimum resource.Quantity) []string {
+	below := []string{}
+	if memory, ok := resources.Requests[v1.ResourceMemory]; ok && !memory.IsZero() && memory.Cmp(minimum) < 0 {
+		below = append(below, fmt.Sprintf("%s memory request %s is below the minimum %s", name, memory.String(), minimum.String()))
+	}
+	if memory, ok := resources.Limits[v1.ResourceMemory]; ok && !memory.IsZero() && memory.Cmp(minimum) < 0 {
+		below = append(below, fmt.Sprintf("%s memory limit %s is below the minimum %s", name, memory.String(), minimum.String()))
+	}
+	return below
+}
+
+// raiseMemoryToMinimum returns a copy of the resources with the memory request and limit below the minimum raised
+// to the minimum. The memory that is not set is left unset.
+func raiseMemoryToMinimum(resources v1.ResourceRequirements, minimum resource.Quantity) v1.ResourceRequirements {
+	raised := *resources.DeepCopy()
+	for _, list := range []v1.ResourceList{raised.Requests, raised.Limits} {
+		if memory, ok := list[v1.ResourceMemory]; ok && !memory.IsZero() && memory.Cmp(minimum) < 0 {
+			list[v1.ResourceMemory] = minimum.DeepCopy()
+		}
+	}
+	return raised
+}
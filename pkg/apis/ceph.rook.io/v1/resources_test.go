@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func memoryResources(request, limit string) v1.ResourceRequirements {
+	r := v1.ResourceRequirements{}
+	if request != "" {
+		r.Requests = v1.ResourceList{v1.ResourceMemory: resource.MustParse(request)}
+	}
+	if limit != "" {
+		r.Limits = v1.ResourceList{v1.ResourceMemory: resource.MustParse(limit)}
+	}
+	return r
+}
+
+func memoryOf(list v1.ResourceList) string {
+	memory := list[v1.ResourceMemory]
+	return memory.String()
+}
+
+func TestGetMinimumMemory(t *testing.T) {
+	minimum, ok := GetMinimumMemory(ResourcesKeyMon)
+	assert.True(t, ok)
+	assert.Equal(t, "1Gi", minimum.String())
+	minimum, ok = GetMinimumMemory("osd-nvme")
+	assert.True(t, ok)
+	assert.Equal(t, "2Gi", minimum.String())
+	_, ok = GetMinimumMemory(ResourcesKeyCleanup)
+	assert.False(t, ok)
+}
+
+func TestResourcesBelowMinimum(t *testing.T) {
+	spec := &ClusterSpec{
+		Resources: ResourceSpec{
+			ResourcesKeyMon:     memoryResources("512Mi", "4Gi"),
+			ResourcesKeyOSD:     memoryResources("4Gi", "4Gi"),
+			"osd-hdd":           memoryResources("", "1Gi"),
+			ResourcesKeyCleanup: memoryResources("10Mi", ""),
+		},
+		Storage: StorageScopeSpec{StorageClassDeviceSets: []StorageClassDeviceSet{
+			{Name: "set1", Resources: memoryResources("1Gi", "")},
+			{Name: "set2"},
+		}},
+	}
+	assert.Equal(t, []string{
+		`"mon" memory request 512Mi is below the minimum 1Gi`,
+		`"osd-hdd" memory limit 1Gi is below the minimum 2Gi`,
+		`storageClassDeviceSet "set1" memory request 1Gi is below the minimum 2Gi`,
+	}, spec.ResourcesBelowMinimum())
+
+	// the cluster is only refused with the reject policy
+	c := &CephCluster{Spec: *spec}
+	assert.NoError(t, c.ValidateCreate())
+	c.Spec.MinimumResourcesPolicy = MinimumResourcesPolicyRaise
+	assert.NoError(t, c.ValidateCreate())
+	c.Spec.MinimumResourcesPolicy = MinimumResourcesPolicyReject
+	assert.Error(t, c.ValidateCreate())
+	assert.Error(t, c.ValidateUpdate(&CephCluster{Spec: *spec}))
+
+	// the memory is raised to the minimum without changing the original resources
+	original := spec.DeepCopy()
+	spec.RaiseResourcesToMinimum()
+	assert.Empty(t, spec.ResourcesBelowMinimum())
+	assert.Equal(t, "1Gi", memoryOf(spec.Resources[ResourcesKeyMon].Requests))
+	assert.Equal(t, "4Gi", memoryOf(spec.Resources[ResourcesKeyMon].Limits))
+	assert.Equal(t, "2Gi", memoryOf(spec.Resources["osd-hdd"].Limits))
+	assert.Equal(t, "0", memoryOf(spec.Resources["osd-hdd"].Requests))
+	assert.Equal(t, "10Mi", memoryOf(spec.Resources[ResourcesKeyCleanup].Requests))
+	assert.Equal(t, "2Gi", memoryOf(spec.Storage.StorageClassDeviceSets[0].Resources.Requests))
+	assert.Nil(t, spec.Storage.StorageClassDeviceSets[1].Resources.Requests)
+	assert.Equal(t, "512Mi", memoryOf(c.Spec.Resources[ResourcesKeyMon].Requests))
+	assert.Equal(t, 3, len(original.ResourcesBelowMinimum()))
+}
@@ -22,10 +22,22 @@ import (
 	"github.com/hashicorp/vault/api"
 )
 
+const (
+	// vaultAuthMethodKey is the connection detail of the Vault auth method, "kubernetes" to log in with the
+	// service account token of the pods
+	vaultAuthMethodKey        = "VAULT_AUTH_METHOD"
+	vaultAuthMethodKubernetes = "kubernetes"
+)
+
 var (
 	VaultTLSConnectionDetails = []string{api.EnvVaultCACert, api.EnvVaultClientCert, api.EnvVaultClientKey}
 )
 
+// ReadOnlyRootFilesystem returns whether the containers of the Ceph daemons run with a read-only root filesystem
+func (s *SecuritySpec) ReadOnlyRootFilesystem() bool {
+	return s.Profile == SecurityProfileRestricted
+}
+
 // IsEnabled return whether a KMS is configured
 func (kms *KeyManagementServiceSpec) IsEnabled() bool {
 	return len(kms.ConnectionDetails) != 0
@@ -36,6 +48,11 @@ func (kms *KeyManagementServiceSpec) IsTokenAuthEnabled() bool {
 	return kms.TokenSecretName != ""
 }
 
+// IsK8sAuthEnabled return whether the KMS is logged in with the service account token of the pods
+func (kms *KeyManagementServiceSpec) IsK8sAuthEnabled() bool {
+	return getParam(kms.ConnectionDetails, vaultAuthMethodKey) == vaultAuthMethodKubernetes
+}
+
 // IsTLSEnabled return KMS TLS details are configured
 func (kms *KeyManagementServiceSpec) IsTLSEnabled() bool {
 	for _, tlsOption := range VaultTLSConnectionDetails {
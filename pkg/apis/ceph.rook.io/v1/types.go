@@ -63,6 +63,87 @@ type CephClusterHealthCheckSpec struct {
 	// LivenessProbe allows to change the livenessprobe configuration for a given daemon
 	// +optional
 	LivenessProbe map[rook.KeyType]*ProbeSpec `json:"livenessProbe,omitempty"`
+	// MuteWarnings are the transient health warnings muted by the operator for a bounded duration when they
+	// are raised, such as OSD_SLOW_PING_TIME_BACK during a known network maintenance
+	// +optional
+	MuteWarnings []HealthMuteSpec `json:"muteWarnings,omitempty"`
+	// OSDFlapping marks out the OSDs that repeatedly go down and up again
+	// +optional
+	// +nullable
+	OSDFlapping *OSDFlappingSpec `json:"osdFlapping,omitempty"`
+	// Blocklist reports the entries of the osd blocklist and clears the stale entries of the nodes that rejoined
+	// +optional
+	// +nullable
+	Blocklist *BlocklistSpec `json:"blocklist,omitempty"`
+	// DiskPrediction reports the failure risk of the devices of the OSDs predicted by the mgr from their SMART data
+	// +optional
+	// +nullable
+	DiskPrediction *DiskPredictionSpec `json:"diskPrediction,omitempty"`
+}
+
+// HealthMuteSpec represents a health warning muted by the operator
+type HealthMuteSpec struct {
+	// Code is the code of the health check, like OSD_SLOW_PING_TIME_BACK
+	Code string `json:"code"`
+	// TTL is how long the warning is muted after it is raised, like 2h. The warning is not muted again
+	// until it clears. Defaults to 1h.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// Sticky keeps the warning muted when it gets worse, such as when more OSDs are affected
+	// +optional
+	Sticky bool `json:"sticky,omitempty"`
+}
+
+// OSDFlappingSpec represents the detection of the OSDs that repeatedly go down and up again
+type OSDFlappingSpec struct {
+	// Enabled marks out the flapping OSDs
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxFlaps is the number of times an OSD comes up again within the window after which it is marked
+	// out. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxFlaps int `json:"maxFlaps,omitempty"`
+	// Window is the period the flaps are counted over, like 30m. Defaults to 1h.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
+// DiskPredictionSpec represents the prediction of the failures of the devices of the OSDs
+type DiskPredictionSpec struct {
+	// Enabled enables the local failure prediction of the mgr and reports the failure risk of the OSDs in the status
+	// of the cluster and in the metrics of the operator
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// WarnThreshold is the life expectancy of a device under which its OSDs are at medium risk, like 2016h.
+	// Defaults to 6 weeks.
+	// +optional
+	WarnThreshold *metav1.Duration `json:"warnThreshold,omitempty"`
+	// MarkOutThreshold is the life expectancy of a device under which its OSDs are at high risk, like 336h.
+	// Defaults to 2 weeks.
+	// +optional
+	MarkOutThreshold *metav1.Duration `json:"markOutThreshold,omitempty"`
+	// MarkOut marks out the OSDs at high risk, one at a time once the placement groups are clean, so their data
+	// is moved before the device fails
+	// +optional
+	MarkOut bool `json:"markOut,omitempty"`
+	// Interval is the interval between the checks of the predictions, like 30m. Defaults to 1h.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// BlocklistSpec represents the management of the osd blocklist
+type BlocklistSpec struct {
+	// Enabled reports the number of entries of the osd blocklist in the status of the cluster
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// ClearRejoinedNodes removes the entries of the addresses of the nodes that were not ready when the entries
+	// were found, once the nodes are ready again
+	// +optional
+	ClearRejoinedNodes bool `json:"clearRejoinedNodes,omitempty"`
+	// Interval is the interval between the checks of the blocklist, like 1m. Defaults to 5m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
 // DaemonHealthSpec is a daemon health check
@@ -74,11 +155,21 @@ type DaemonHealthSpec struct {
 	// Monitor represents the health check settings for the Ceph monitor
 	// +optional
 	// +nullable
-	Monitor HealthCheckSpec `json:"mon,omitempty"`
+	Monitor MonHealthCheckSpec `json:"mon,omitempty"`
 	// ObjectStorageDaemon represents the health check settings for the Ceph OSDs
 	// +optional
 	// +nullable
 	ObjectStorageDaemon HealthCheckSpec `json:"osd,omitempty"`
+	// Manager represents the health check settings for the Ceph managers. The timeout is the duration
+	// the active mgr can be unresponsive before it is failed over to a standby.
+	// +optional
+	// +nullable
+	Manager HealthCheckSpec `json:"mgr,omitempty"`
+	// Keyring represents the periodic check of the keys of the keyring and CSI secrets, which are
+	// repaired with the keys of the Ceph users if they differ
+	// +optional
+	// +nullable
+	Keyring HealthCheckSpec `json:"keyring,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -131,12 +222,38 @@ type ClusterSpec struct {
 	// +optional
 	Resources ResourceSpec `json:"resources,omitempty"`
 
+	// MinimumResourcesPolicy is what happens when the memory requests or limits of the daemons in Resources are
+	// below their minimum viable memory. "Warn" only reports them, "Reject" refuses the cluster in the admission
+	// webhook and the operator, and "Raise" runs the daemons with their minimum memory. Defaults to "Warn".
+	// +kubebuilder:validation:Enum=Warn;Reject;Raise;""
+	// +optional
+	MinimumResourcesPolicy MinimumResourcesPolicy `json:"minimumResourcesPolicy,omitempty"`
+
+	// Profile is a preset of defaults for a common deployment pattern, applied to the settings not set in the
+	// specs of the cluster and of its object stores. "archive" is an object-only cluster for cold storage.
+	// +kubebuilder:validation:Enum=archive;""
+	// +optional
+	Profile ClusterProfile `json:"profile,omitempty"`
+
 	// PriorityClassNames sets priority classes on components
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +nullable
 	// +optional
 	PriorityClassNames PriorityClassNamesSpec `json:"priorityClassNames,omitempty"`
 
+	// DNS sets the DNS policy and the DNS config of the pods of the daemons, for all the daemons or per daemon
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	DNS DNSSpec `json:"dns,omitempty"`
+
+	// ContainerOverrides appends arguments to the main container and adds init containers to the pods of the
+	// daemons, for all the daemons or per daemon
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	ContainerOverrides ContainerOverridesSpec `json:"containerOverrides,omitempty"`
+
 	// The path on the host where config and data can be persisted
 	// +kubebuilder:validation:Pattern=`^/(\S+)`
 	// +optional
@@ -204,6 +321,12 @@ type ClusterSpec struct {
 	// +nullable
 	CleanupPolicy CleanupPolicySpec `json:"cleanupPolicy,omitempty"`
 
+	// DeletionPolicy is what happens to the resources generated for the CR when the CR is deleted. With
+	// "Orphan", the deployments, services, secrets, configmaps and PVCs are kept. Defaults to "Delete".
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
 	// Internal daemon healthchecks and liveness probe
 	// +optional
 	// +nullable
@@ -218,6 +341,58 @@ type ClusterSpec struct {
 	// +optional
 	// +nullable
 	LogCollector LogCollectorSpec `json:"logCollector,omitempty"`
+
+	// NodeTuning represents the kernel settings applied on the storage nodes
+	// +optional
+	// +nullable
+	NodeTuning NodeTuningSpec `json:"nodeTuning,omitempty"`
+
+	// Notifications represents the external notifications of the critical events of the cluster
+	// +optional
+	// +nullable
+	Notifications NotificationsSpec `json:"notifications,omitempty"`
+}
+
+// NotificationsSpec represents the sinks receiving the notifications of the critical events of the cluster
+type NotificationsSpec struct {
+	// Webhook is the webhook receiving the notifications
+	// +optional
+	// +nullable
+	Webhook *WebhookNotificationSpec `json:"webhook,omitempty"`
+}
+
+// WebhookNotificationSpec represents a webhook receiving the notifications as JSON POST requests
+type WebhookNotificationSpec struct {
+	// URL is the URL of the webhook
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// SecretName is the name of the secret in the namespace of the cluster whose "secret" key signs the
+	// notifications with HMAC-SHA256 in the X-Rook-Signature header
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Severity is the minimum severity of the notifications sent to the webhook, critical by default
+	// +kubebuilder:validation:Enum=info;warning;critical;""
+	// +optional
+	Severity string `json:"severity,omitempty"`
+}
+
+// NodeTuningSpec represents the settings of the daemonset tuning the kernel of the storage nodes
+type NodeTuningSpec struct {
+	// Enabled determines whether the operator runs the node tuning daemonset on the storage nodes
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Sysctls are the kernel parameters to set on the nodes in addition to the defaults required by ceph,
+	// for example "fs.aio-max-nr: 1048576". A parameter set here overrides the default value.
+	// +optional
+	// +nullable
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// TransparentHugePages is the transparent huge pages mode to set on the nodes. If empty, the mode
+	// configured on the nodes is left untouched.
+	// +kubebuilder:validation:Enum=always;madvise;never;""
+	// +optional
+	TransparentHugePages string `json:"transparentHugePages,omitempty"`
 }
 
 // LogCollectorSpec is the logging spec
@@ -236,6 +411,34 @@ type SecuritySpec struct {
 	// +optional
 	// +nullable
 	KeyManagementService KeyManagementServiceSpec `json:"kms,omitempty"`
+	// KeyRotation is the rotation of the encryption keys of the OSDs on PVC
+	// +optional
+	KeyRotation KeyRotationSpec `json:"keyRotation,omitempty"`
+	// Profile is the security profile of the pods of the Ceph daemons. With the "restricted" profile, the mon, mgr
+	// and OSD containers run with a read-only root filesystem.
+	// +kubebuilder:validation:Enum=default;restricted;""
+	// +optional
+	Profile SecurityProfile `json:"profile,omitempty"`
+}
+
+// SecurityProfile is the security profile of the pods of the Ceph daemons
+type SecurityProfile string
+
+const (
+	// SecurityProfileDefault runs the containers of the Ceph daemons with a writable root filesystem
+	SecurityProfileDefault SecurityProfile = "default"
+	// SecurityProfileRestricted runs the containers of the mon, mgr and OSD daemons with a read-only root filesystem
+	SecurityProfileRestricted SecurityProfile = "restricted"
+)
+
+// KeyRotationSpec represents the rotation of the LUKS keys of the encrypted OSDs on PVC
+type KeyRotationSpec struct {
+	// Enabled rotates the keys periodically, and on demand when the CephCluster is annotated
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval is the time between two rotations of the key of an OSD, 720h by default
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
 // KeyManagementServiceSpec represent various details of the KMS server
@@ -260,8 +463,48 @@ type CephVersionSpec struct {
 	// Whether to allow unsupported versions (do not set to true in production)
 	// +optional
 	AllowUnsupported bool `json:"allowUnsupported,omitempty"`
+
+	// Hotfix runs some types of daemons with another image of the same Ceph release, to roll out
+	// a fix to the affected daemons only instead of updating the whole cluster
+	// +optional
+	// +nullable
+	Hotfix *CephHotfixSpec `json:"hotfix,omitempty"`
+
+	// Canary upgrades a single daemon of each type to a new image, then waits for the upgrade to be
+	// approved with the annotation "ceph.rook.io/approve-upgrade" of the CephCluster before upgrading
+	// the other daemons. A single OSD of each device class is upgraded.
+	// +optional
+	Canary bool `json:"canary,omitempty"`
 }
 
+// CephHotfixSpec represents an image rolled out to some types of daemons only
+type CephHotfixSpec struct {
+	// Image is the container image of the hotfix. It must be the same Ceph release as the image
+	// of the cluster and not an older version.
+	Image string `json:"image"`
+
+	// Daemons are the types of daemons running the hotfix image
+	// +kubebuilder:validation:MinItems=1
+	Daemons []HotfixDaemonType `json:"daemons"`
+}
+
+// HotfixDaemonType is a type of daemon that can run a hotfix image
+// +kubebuilder:validation:Enum=mgr;mds;rgw;rbd-mirror;cephfs-mirror
+type HotfixDaemonType string
+
+const (
+	// HotfixDaemonMgr is the type of the mgr daemons
+	HotfixDaemonMgr HotfixDaemonType = "mgr"
+	// HotfixDaemonMds is the type of the mds daemons
+	HotfixDaemonMds HotfixDaemonType = "mds"
+	// HotfixDaemonRgw is the type of the rgw daemons
+	HotfixDaemonRgw HotfixDaemonType = "rgw"
+	// HotfixDaemonRbdMirror is the type of the rbd-mirror daemons
+	HotfixDaemonRbdMirror HotfixDaemonType = "rbd-mirror"
+	// HotfixDaemonCephFSMirror is the type of the cephfs-mirror daemons
+	HotfixDaemonCephFSMirror HotfixDaemonType = "cephfs-mirror"
+)
+
 // DashboardSpec represents the settings for the Ceph dashboard
 type DashboardSpec struct {
 	// Enabled determines whether to enable the dashboard
@@ -278,6 +521,80 @@ type DashboardSpec struct {
 	// SSL determines whether SSL should be used
 	// +optional
 	SSL bool `json:"ssl,omitempty"`
+	// Grafana generates a read-only dashboard account and the provisioning of Grafana with the Prometheus of the cluster
+	// +optional
+	// +nullable
+	Grafana *DashboardGrafanaSpec `json:"grafana,omitempty"`
+	// SSO configures the single sign-on of the dashboard
+	// +optional
+	// +nullable
+	SSO *DashboardSSOSpec `json:"sso,omitempty"`
+	// Users are the dashboard accounts managed by the operator
+	// +optional
+	// +nullable
+	Users []DashboardUserSpec `json:"users,omitempty"`
+	// EnableObjectGateway configures the object gateway management of the dashboard with the credentials of
+	// the object stores of the cluster, and keeps them updated when the keys of the dashboard rgw user change
+	// +optional
+	EnableObjectGateway bool `json:"enableObjectGateway,omitempty"`
+}
+
+// DashboardUserSpec represents a dashboard account managed by the operator
+type DashboardUserSpec struct {
+	// Name is the username of the account
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Roles are the dashboard roles of the account, e.g. "read-only"
+	// +kubebuilder:validation:MinItems=1
+	Roles []string `json:"roles"`
+	// PasswordSecretName is the name of a secret with the password of the account in the "password" key.
+	// If empty, a password is generated and saved in the secret of the account.
+	// +optional
+	PasswordSecretName string `json:"passwordSecretName,omitempty"`
+}
+
+// DashboardSSOSpec represents the single sign-on settings of the dashboard
+type DashboardSSOSpec struct {
+	// SAML2 configures the single sign-on with a SAML 2.0 identity provider
+	// +optional
+	// +nullable
+	SAML2 *DashboardSAML2Spec `json:"saml2,omitempty"`
+}
+
+// DashboardSAML2Spec represents the settings of the single sign-on with a SAML 2.0 identity provider
+type DashboardSAML2Spec struct {
+	// BaseURL is the URL of the dashboard as reached by the users, including the URL prefix
+	// +kubebuilder:validation:Pattern=`^https?://`
+	BaseURL string `json:"baseURL"`
+	// IdPMetadataURL is the URL of the metadata of the identity provider
+	// +optional
+	IdPMetadataURL string `json:"idpMetadataURL,omitempty"`
+	// IdPMetadataSecretName is the name of a secret with the metadata of the identity provider in the
+	// "idp-metadata.xml" key, used instead of the URL of the metadata
+	// +optional
+	IdPMetadataSecretName string `json:"idpMetadataSecretName,omitempty"`
+	// IdPUsernameAttribute is the attribute of the identity provider with the username, "uid" by default
+	// +optional
+	IdPUsernameAttribute string `json:"idpUsernameAttribute,omitempty"`
+	// IdPEntityID is the entity ID of the identity provider, required if the metadata describes several
+	// identity providers or if the requests are signed
+	// +optional
+	IdPEntityID string `json:"idpEntityID,omitempty"`
+	// SPCertSecretName is the name of a TLS secret with the certificate and the key signing the requests
+	// of the dashboard to the identity provider. The secret is mounted in the mgr pods.
+	// +optional
+	SPCertSecretName string `json:"spCertSecretName,omitempty"`
+}
+
+// DashboardGrafanaSpec represents the settings of the read-only dashboard account and of the Grafana provisioning
+type DashboardGrafanaSpec struct {
+	// Enabled determines whether to generate the read-only dashboard account and the Grafana provisioning configmap
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// PrometheusURL is the URL of the Prometheus datasource of Grafana. If empty, the rook-prometheus service
+	// in the rules namespace of the monitoring settings is used.
+	// +optional
+	PrometheusURL string `json:"prometheusURL,omitempty"`
 }
 
 // MonitoringSpec represents the settings for Prometheus based Ceph monitoring
@@ -302,6 +619,108 @@ type MonitoringSpec struct {
 	// +kubebuilder:validation:Maximum=65535
 	// +optional
 	ExternalMgrPrometheusPort uint16 `json:"externalMgrPrometheusPort,omitempty"`
+
+	// TLS serves the mgr prometheus endpoint over TLS
+	// +optional
+	// +nullable
+	TLS *MetricsTLSSpec `json:"tls,omitempty"`
+
+	// Auth requires the scrapes of the mgr prometheus endpoint to be authenticated
+	// +optional
+	// +nullable
+	Auth *MetricsAuthSpec `json:"auth,omitempty"`
+
+	// PerfCounters selects the perf counters of the Ceph daemons exported by the mgr prometheus endpoint
+	// +optional
+	// +nullable
+	PerfCounters *PerfCountersSpec `json:"perfCounters,omitempty"`
+
+	// Topology publishes a read-only snapshot of the pools, the CRUSH rules, the OSD tree and the capacity
+	// of the cluster as JSON in the "rook-ceph-topology" configmap
+	// +optional
+	// +nullable
+	Topology TopologySnapshotSpec `json:"topology,omitempty"`
+}
+
+// PerfCounterPriority is the priority level of the perf counters of the Ceph daemons
+type PerfCounterPriority string
+
+const (
+	// PerfCounterPriorityCritical exports only the critical perf counters
+	PerfCounterPriorityCritical PerfCounterPriority = "critical"
+	// PerfCounterPriorityInteresting exports the interesting and critical perf counters
+	PerfCounterPriorityInteresting PerfCounterPriority = "interesting"
+	// PerfCounterPriorityUseful exports the useful, interesting and critical perf counters, the Ceph default
+	PerfCounterPriorityUseful PerfCounterPriority = "useful"
+)
+
+// PerfCountersSpec represents the perf counters of the Ceph daemons exported as metrics. The daemons only send
+// the selected perf counters to the mgr, which keeps the cardinality of the metrics down on large clusters.
+type PerfCountersSpec struct {
+	// PriorityLevel is the lowest priority of the perf counters exported, "useful" by default
+	// +kubebuilder:validation:Enum=critical;interesting;useful
+	// +optional
+	PriorityLevel PerfCounterPriority `json:"priorityLevel,omitempty"`
+
+	// DaemonTypes are the types of the daemons exporting their perf counters, all of them if empty.
+	// The "client" type covers the rgw and rbd-mirror daemons.
+	// +optional
+	DaemonTypes []PerfCounterDaemonType `json:"daemonTypes,omitempty"`
+
+	// RecordingRules deploys the prometheus recording rules pre-aggregating the heaviest perf counter series,
+	// along with the prometheus rules of the cluster when monitoring is enabled
+	// +optional
+	RecordingRules bool `json:"recordingRules,omitempty"`
+}
+
+// PerfCounterDaemonType is a type of Ceph daemon exporting perf counters
+// +kubebuilder:validation:Enum=mon;mgr;osd;mds;client
+type PerfCounterDaemonType string
+
+// TopologySnapshotSpec represents the settings of the snapshot of the storage topology
+type TopologySnapshotSpec struct {
+	// Enabled publishes the snapshot of the storage topology
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval is the interval between the refreshes of the snapshot, 5m by default
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// MetricsTLSSpec represents the TLS settings of the mgr prometheus endpoint
+type MetricsTLSSpec struct {
+	// SecretName is the name of the kubernetes.io/tls secret with the certificate ("tls.crt") and the key
+	// ("tls.key") served by the endpoint, and the CA ("ca.crt") verifying them for the service monitor
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// ServerName is the name verified in the certificate by prometheus. If empty, the name of the
+	// metrics service is verified, e.g. "rook-ceph-mgr.rook-ceph.svc".
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// MetricsAuthType is the type of authentication of the mgr prometheus endpoint
+type MetricsAuthType string
+
+const (
+	// MetricsAuthBearer authenticates the scrapes with a bearer token
+	MetricsAuthBearer MetricsAuthType = "bearer"
+	// MetricsAuthBasic authenticates the scrapes with a username and a password
+	MetricsAuthBasic MetricsAuthType = "basic"
+)
+
+// MetricsAuthSpec represents the authentication of the mgr prometheus endpoint
+type MetricsAuthSpec struct {
+	// Type is the type of authentication
+	// +kubebuilder:validation:Enum=bearer;basic
+	Type MetricsAuthType `json:"type"`
+
+	// SecretName is the name of the secret with the "token" key for a bearer token, or the "username"
+	// and "password" keys for basic authentication
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
 }
 
 // ClusterStatus represents the status of a Ceph cluster
@@ -313,79 +732,699 @@ type ClusterStatus struct {
 	CephStatus  *CephStatus     `json:"ceph,omitempty"`
 	CephStorage *CephStorage    `json:"storage,omitempty"`
 	CephVersion *ClusterVersion `json:"version,omitempty"`
-}
-
-// CephDaemonsVersions show the current ceph version for different ceph daemons
-type CephDaemonsVersions struct {
-	// Mon shows Mon Ceph version
+	// HotfixVersion is the version of the hotfix image once it is validated
 	// +optional
-	Mon map[string]int `json:"mon,omitempty"`
-	// Mgr shows Mgr Ceph version
+	HotfixVersion *ClusterVersion `json:"hotfixVersion,omitempty"`
+	// OSDPrepareFailures are the failures of the OSD prepare jobs of the last reconcile, by node or PVC
 	// +optional
-	Mgr map[string]int `json:"mgr,omitempty"`
-	// Osd shows Osd Ceph version
+	OSDPrepareFailures []OSDPrepareFailure `json:"osdPrepareFailures,omitempty"`
+	// OSDReplacements are the replacements of the OSDs requested by annotating their deployment
 	// +optional
-	Osd map[string]int `json:"osd,omitempty"`
-	// Rgw shows Rgw Ceph version
+	OSDReplacements []OSDReplacement `json:"osdReplacements,omitempty"`
+	// OSDProvisioning is the progress of the OSD prepare jobs of the last reconcile
 	// +optional
-	Rgw map[string]int `json:"rgw,omitempty"`
-	// Mds shows Mds Ceph version
+	OSDProvisioning *OSDProvisioningStatus `json:"osdProvisioning,omitempty"`
+	// MonHealth is the health of each mon as observed by the mon health checker
 	// +optional
-	Mds map[string]int `json:"mds,omitempty"`
-	// RbdMirror shows RbdMirror Ceph version
+	MonHealth *MonHealthStatus `json:"monHealth,omitempty"`
+	// CorrectiveActions is the history of the automated corrective actions taken by the operator,
+	// from the oldest to the most recent. Only the most recent actions are kept.
 	// +optional
-	RbdMirror map[string]int `json:"rbd-mirror,omitempty"`
-	// CephFSMirror shows CephFSMirror Ceph version
+	CorrectiveActions []CorrectiveAction `json:"correctiveActions,omitempty"`
+	// DashboardSSO is the status of the single sign-on of the dashboard
 	// +optional
-	CephFSMirror map[string]int `json:"cephfs-mirror,omitempty"`
-	// Overall shows overall Ceph version
+	DashboardSSO *DashboardSSOStatus `json:"dashboardSSO,omitempty"`
+	// DisabledMgrModules are the mgr modules enabled in the spec that the operator disabled because they
+	// repeatedly crashed the mgr. A module is enabled again once it is disabled or removed in the spec
+	// and then enabled again.
 	// +optional
-	Overall map[string]int `json:"overall,omitempty"`
+	DisabledMgrModules []string `json:"disabledMgrModules,omitempty"`
+	// OSDKeyRotation is the rotation of the encryption keys of the OSDs on PVC
+	// +optional
+	OSDKeyRotation *OSDKeyRotationStatus `json:"osdKeyRotation,omitempty"`
+	// OSDWeightRampUp is the ramp-up of the CRUSH weight of the new OSDs
+	// +optional
+	OSDWeightRampUp *OSDWeightRampUpStatus `json:"osdWeightRampUp,omitempty"`
+	// OSDMigration is the migration of the OSDs with a legacy format
+	// +optional
+	OSDMigration *OSDMigrationStatus `json:"osdMigration,omitempty"`
+	// DeviceInventory is the inventory of the devices discovered on the nodes
+	// +optional
+	DeviceInventory *DeviceInventoryStatus `json:"deviceInventory,omitempty"`
+	// NodeMaintenance are the nodes in maintenance whose OSDs are set noout by the operator
+	// +optional
+	NodeMaintenance *NodeMaintenanceStatus `json:"nodeMaintenance,omitempty"`
+	// OSDProvisioningPlan are the devices the OSD prepare jobs would consume, reported in dry-run mode
+	// +optional
+	OSDProvisioningPlan *OSDProvisioningPlan `json:"osdProvisioningPlan,omitempty"`
+	// NetworkMigration is the progress of the migration of the daemons after network.hostNetwork changed
+	// +optional
+	NetworkMigration *NetworkMigrationStatus `json:"networkMigration,omitempty"`
+	// Blocklist is the summary of the osd blocklist
+	// +optional
+	Blocklist *BlocklistStatus `json:"blocklist,omitempty"`
+	// RetainedMonPVCs are the PVCs of the mons failed over or removed that are kept until the end of their
+	// retention period
+	// +optional
+	RetainedMonPVCs []RetainedMonPVC `json:"retainedMonPVCs,omitempty"`
+	// DiskPrediction is the failure risk of the OSDs predicted from the SMART data of their devices
+	// +optional
+	DiskPrediction *DiskPredictionStatus `json:"diskPrediction,omitempty"`
+	// EnforcedConnectionOptions are the connection options set by the operator to enforce the connection
+	// settings of the spec. Only these options are removed when the settings are not enforced anymore.
+	// +optional
+	EnforcedConnectionOptions []string `json:"enforcedConnectionOptions,omitempty"`
 }
 
-// CephStatus is the details health of a Ceph Cluster
-type CephStatus struct {
-	Health         string                       `json:"health,omitempty"`
-	Details        map[string]CephHealthMessage `json:"details,omitempty"`
-	LastChecked    string                       `json:"lastChecked,omitempty"`
-	LastChanged    string                       `json:"lastChanged,omitempty"`
-	PreviousHealth string                       `json:"previousHealth,omitempty"`
-	Capacity       Capacity                     `json:"capacity,omitempty"`
+// FailureRisk is the risk of failure of the device of an OSD
+type FailureRisk string
+
+const (
+	// FailureRiskLow is the risk of a device expected to live longer than the warn threshold
+	FailureRiskLow FailureRisk = "Low"
+	// FailureRiskMedium is the risk of a device expected to fail before the warn threshold
+	FailureRiskMedium FailureRisk = "Medium"
+	// FailureRiskHigh is the risk of a device expected to fail before the mark out threshold
+	FailureRiskHigh FailureRisk = "High"
+	// FailureRiskUnknown is the risk of a device without prediction, such as when no SMART data was collected
+	FailureRiskUnknown FailureRisk = "Unknown"
+)
+
+// DiskPredictionStatus represents the failure risk of the OSDs
+type DiskPredictionStatus struct {
+	// LastChecked is the time of the last check of the predictions
 	// +optional
-	Versions *CephDaemonsVersions `json:"versions,omitempty"`
+	LastChecked string `json:"lastChecked,omitempty"`
+	// OSDsByRisk is the number of OSDs at each risk of failure
+	// +optional
+	OSDsByRisk map[FailureRisk]int `json:"osdsByRisk,omitempty"`
+	// AtRisk are the OSDs at medium or high risk of failure
+	// +optional
+	AtRisk []OSDFailureRisk `json:"atRisk,omitempty"`
 }
 
-// Capacity is the capacity information of a Ceph Cluster
-type Capacity struct {
-	TotalBytes     uint64 `json:"bytesTotal,omitempty"`
-	UsedBytes      uint64 `json:"bytesUsed,omitempty"`
-	AvailableBytes uint64 `json:"bytesAvailable,omitempty"`
-	LastUpdated    string `json:"lastUpdated,omitempty"`
+// OSDFailureRisk represents the failure risk of an OSD
+type OSDFailureRisk struct {
+	// OSD is the ID of the OSD
+	OSD int `json:"osd"`
+	// DeviceID is the ID of the device of the OSD reported by Ceph, made of its vendor, model and serial
+	DeviceID string `json:"deviceID"`
+	// Host is the host of the device
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Risk is the risk of failure of the device
+	Risk FailureRisk `json:"risk"`
+	// LifeExpectancyMin is the earliest time the device is predicted to fail
+	// +optional
+	LifeExpectancyMin string `json:"lifeExpectancyMin,omitempty"`
+	// LifeExpectancyMax is the latest time the device is predicted to fail
+	// +optional
+	LifeExpectancyMax string `json:"lifeExpectancyMax,omitempty"`
+	// Out is whether the OSD is out, such as when marked out by the operator because of its risk
+	// +optional
+	Out bool `json:"out,omitempty"`
 }
 
-// CephStorage represents flavors of Ceph Cluster Storage
-type CephStorage struct {
-	DeviceClasses []DeviceClasses `json:"deviceClasses,omitempty"`
+// RetainedMonPVC represents the PVC of a removed mon kept for its retention period
+type RetainedMonPVC struct {
+	// Name of the PVC
+	Name string `json:"name"`
+	// Mon is the name of the mon the PVC belonged to
+	// +optional
+	Mon string `json:"mon,omitempty"`
+	// RemovedAt is the time the mon was found removed
+	// +optional
+	RemovedAt string `json:"removedAt,omitempty"`
+	// DeleteAfter is the time after which the PVC is deleted
+	// +optional
+	DeleteAfter string `json:"deleteAfter,omitempty"`
 }
 
-// DeviceClasses represents device classes of a Ceph Cluster
-type DeviceClasses struct {
-	Name string `json:"name,omitempty"`
+// OSDKeyRotationPhase is the phase of the rotation of the key of an OSD
+type OSDKeyRotationPhase string
+
+const (
+	// OSDKeyRotationRotating means the new key of the OSD is being set on its devices
+	OSDKeyRotationRotating OSDKeyRotationPhase = "Rotating"
+	// OSDKeyRotationCompleted means the devices of the OSD are only opened with the new key
+	OSDKeyRotationCompleted OSDKeyRotationPhase = "Completed"
+	// OSDKeyRotationFailed means the rotation failed and is retried
+	OSDKeyRotationFailed OSDKeyRotationPhase = "Failed"
+)
+
+// OSDKeyRotationStatus represents the rotation of the encryption keys of the OSDs on PVC
+type OSDKeyRotationStatus struct {
+	// LastRequest is the value of the annotation of the last on-demand rotation of the keys
+	// +optional
+	LastRequest string `json:"lastRequest,omitempty"`
+	// OSDs are the rotations of the keys of the encrypted OSDs
+	// +optional
+	OSDs []OSDKeyRotation `json:"osds,omitempty"`
 }
 
-// ClusterVersion represents the version of a Ceph Cluster
-type ClusterVersion struct {
-	Image   string `json:"image,omitempty"`
-	Version string `json:"version,omitempty"`
+// OSDKeyRotation represents the rotation of the key of an encrypted OSD
+type OSDKeyRotation struct {
+	// ID is the ID of the OSD
+	ID int `json:"id"`
+	// PVC is the name of the data PVC of the OSD, which names its key
+	PVC string `json:"pvc"`
+	// Phase is the phase of the last rotation
+	Phase OSDKeyRotationPhase `json:"phase"`
+	// Message describes the phase of the last rotation
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastRotationTime is when the key was last rotated
+	// +optional
+	LastRotationTime string `json:"lastRotationTime,omitempty"`
 }
 
-// CephHealthMessage represents the health message of a Ceph Cluster
-type CephHealthMessage struct {
-	Severity string `json:"severity"`
-	Message  string `json:"message"`
+// NodeMaintenanceStatus represents the nodes in maintenance whose OSDs are set noout by the operator
+type NodeMaintenanceStatus struct {
+	// Nodes are the nodes in maintenance
+	// +optional
+	Nodes []NodeMaintenance `json:"nodes,omitempty"`
 }
 
-// Condition represents a status condition on any Rook-Ceph Custom Resource.
+// NodeMaintenance represents a node in maintenance
+type NodeMaintenance struct {
+	// Node is the name of the node
+	Node string `json:"node"`
+	// Hosts are the CRUSH hosts of the OSDs of the node, with the noout flag set
+	Hosts []string `json:"hosts"`
+}
+
+// OSDProvisioningPlan represents the devices the OSD prepare jobs would consume in dry-run mode
+type OSDProvisioningPlan struct {
+	// Time is the time of the last prepare jobs run in dry-run mode
+	// +optional
+	Time string `json:"time,omitempty"`
+	// Nodes are the nodes and PVCs with devices to consume, the details of the devices being in the
+	// "rook-ceph-osd-provisioning-plan" configmap
+	// +optional
+	Nodes []OSDNodeProvisioningPlan `json:"nodes,omitempty"`
+}
+
+// OSDNodeProvisioningPlan represents the devices the OSDs would be prepared on for a node or a PVC
+type OSDNodeProvisioningPlan struct {
+	// Name is the name of the node or the PVC
+	Name string `json:"name"`
+	// PVC is whether the OSDs would be prepared on a PVC
+	// +optional
+	PVC bool `json:"pvc,omitempty"`
+	// Devices are the paths of the devices
+	// +optional
+	Devices []string `json:"devices,omitempty"`
+}
+
+// NetworkMigrationPhase is the phase of the migration of the daemons to a new network
+type NetworkMigrationPhase string
+
+const (
+	// NetworkMigrationMigratingMons means the mons on the previous network are failed over one at a time
+	NetworkMigrationMigratingMons NetworkMigrationPhase = "MigratingMons"
+	// NetworkMigrationRollingDaemons means all the mons are on the new network and the other daemons are updated
+	NetworkMigrationRollingDaemons NetworkMigrationPhase = "RollingDaemons"
+	// NetworkMigrationCompleted means all the daemons were updated on the new network
+	NetworkMigrationCompleted NetworkMigrationPhase = "Completed"
+)
+
+// NetworkMigrationStatus represents the migration of the daemons between the host network and the pod network
+type NetworkMigrationStatus struct {
+	// Network is the network the daemons are migrated to, either "host" or "pod"
+	Network string `json:"network"`
+	// Phase is the phase of the migration
+	Phase NetworkMigrationPhase `json:"phase"`
+	// StartTime is when the migration started
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// MonsOnPreviousNetwork are the mons still waiting to be failed over to the new network
+	// +optional
+	MonsOnPreviousNetwork []string `json:"monsOnPreviousNetwork,omitempty"`
+	// Message describes the phase of the migration
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BlocklistStatus represents the summary of the osd blocklist
+type BlocklistStatus struct {
+	// Entries is the number of entries of the osd blocklist
+	Entries int `json:"entries"`
+	// LastChecked is the time of the last check of the blocklist
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+}
+
+// DeviceInventoryStatus represents the inventory of the devices discovered on the nodes
+type DeviceInventoryStatus struct {
+	// LastUpdated is the time of the last refresh of the inventory
+	// +optional
+	LastUpdated string `json:"lastUpdated,omitempty"`
+	// Nodes are the devices of each node
+	// +optional
+	Nodes []NodeDeviceInventory `json:"nodes,omitempty"`
+	// Message describes why the inventory is empty
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NodeDeviceInventory represents the devices discovered on a node
+type NodeDeviceInventory struct {
+	// Name is the name of the node
+	Name string `json:"name"`
+	// Devices are the devices of the node
+	// +optional
+	Devices []InventoryDevice `json:"devices,omitempty"`
+}
+
+// InventoryDevice represents a device discovered on a node
+type InventoryDevice struct {
+	// Name is the name of the device, like "sdb"
+	Name string `json:"name"`
+	// Size is the size of the device in bytes
+	Size uint64 `json:"size"`
+	// Type is the type of the device, like "disk" or "lvm"
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Rotational is whether the device is an HDD
+	Rotational bool `json:"rotational"`
+	// Model is the model of the device
+	// +optional
+	Model string `json:"model,omitempty"`
+	// Serial is the serial number of the device
+	// +optional
+	Serial string `json:"serial,omitempty"`
+	// Available is whether an OSD can be created on the device
+	Available bool `json:"available"`
+	// RejectedReasons are the reasons why an OSD cannot be created on the device
+	// +optional
+	RejectedReasons []string `json:"rejectedReasons,omitempty"`
+}
+
+// OSDMigrationPhase is the phase of the migration of the OSDs with a legacy format
+type OSDMigrationPhase string
+
+const (
+	// OSDMigrationDraining means the OSDs of the failure domain are out, waiting for the placement groups to be clean
+	OSDMigrationDraining OSDMigrationPhase = "Draining"
+	// OSDMigrationReplacing means the OSDs of the failure domain are destroyed and prepared again
+	OSDMigrationReplacing OSDMigrationPhase = "Replacing"
+	// OSDMigrationPaused means the migration is paused before the next failure domain
+	OSDMigrationPaused OSDMigrationPhase = "Paused"
+	// OSDMigrationCompleted means no OSD with a legacy format is left
+	OSDMigrationCompleted OSDMigrationPhase = "Completed"
+)
+
+// OSDMigrationStatus represents the migration of the OSDs with a legacy format
+type OSDMigrationStatus struct {
+	// Phase is the phase of the migration
+	Phase OSDMigrationPhase `json:"phase"`
+	// FailureDomain is the failure domain being migrated, for example "host=node1"
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+	// OSDs are the OSDs of the failure domain being migrated
+	// +optional
+	OSDs []int `json:"osds,omitempty"`
+	// PendingOSDs is the number of OSDs with a legacy format left to migrate, including the current ones
+	// +optional
+	PendingOSDs int `json:"pendingOSDs,omitempty"`
+	// MigratedOSDs is the number of OSDs migrated
+	// +optional
+	MigratedOSDs int `json:"migratedOSDs,omitempty"`
+	// Message describes the phase of the migration
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// OSDWeightRampUpPhase is the phase of the ramp-up of the CRUSH weight of an OSD
+type OSDWeightRampUpPhase string
+
+const (
+	// OSDWeightRampUpRampingUp means the CRUSH weight of the OSD is raised at each step
+	OSDWeightRampUpRampingUp OSDWeightRampUpPhase = "RampingUp"
+	// OSDWeightRampUpCompleted means the OSD reached its target weight, or didn't start with a weight of 0
+	OSDWeightRampUpCompleted OSDWeightRampUpPhase = "Completed"
+)
+
+// OSDWeightRampUpStatus represents the ramp-up of the CRUSH weight of the new OSDs
+type OSDWeightRampUpStatus struct {
+	// Since is when the ramp-up was enabled, only the OSDs created afterwards are ramped up
+	Since string `json:"since"`
+	// LastStepTime is when the weights were last raised
+	// +optional
+	LastStepTime string `json:"lastStepTime,omitempty"`
+	// Message describes the last step
+	// +optional
+	Message string `json:"message,omitempty"`
+	// OSDs are the ramp-ups of the new OSDs
+	// +optional
+	OSDs []OSDWeightRampUp `json:"osds,omitempty"`
+}
+
+// OSDWeightRampUp represents the ramp-up of the CRUSH weight of a new OSD
+type OSDWeightRampUp struct {
+	// ID is the ID of the OSD
+	ID int `json:"id"`
+	// Phase is the phase of the ramp-up
+	Phase OSDWeightRampUpPhase `json:"phase"`
+	// Weight is the current CRUSH weight of the OSD
+	// +optional
+	Weight string `json:"weight,omitempty"`
+	// TargetWeight is the CRUSH weight reached at the end of the ramp-up, the size of the device in TiB
+	// +optional
+	TargetWeight string `json:"targetWeight,omitempty"`
+}
+
+// DashboardSSOStatus represents the single sign-on of the dashboard configured by the operator
+type DashboardSSOStatus struct {
+	// Protocol is the protocol of the single sign-on
+	Protocol string `json:"protocol"`
+	// ACSURL is the URL of the assertion consumer service of the dashboard to register in the identity provider
+	// +optional
+	ACSURL string `json:"acsURL,omitempty"`
+	// MetadataURL is the URL of the metadata of the dashboard as a service provider
+	// +optional
+	MetadataURL string `json:"metadataURL,omitempty"`
+}
+
+// CorrectiveActionType is the type of an automated corrective action taken by the operator
+type CorrectiveActionType string
+
+const (
+	// CorrectiveActionMonFailover is the failover of a mon to a new mon
+	CorrectiveActionMonFailover CorrectiveActionType = "MonFailover"
+	// CorrectiveActionMonRemoval is the removal of an extra mon
+	CorrectiveActionMonRemoval CorrectiveActionType = "MonRemoval"
+	// CorrectiveActionOSDRemoval is the removal of the deployment of an OSD that is out and safe to destroy, or
+	// the purge of an OSD that stayed down and out for longer than the grace period of storage.autoRemoveOSD
+	CorrectiveActionOSDRemoval CorrectiveActionType = "OSDRemoval"
+	// CorrectiveActionMgrModuleDisabled is the disabling of a mgr module that repeatedly crashed the mgr
+	CorrectiveActionMgrModuleDisabled CorrectiveActionType = "MgrModuleDisabled"
+	// CorrectiveActionOSDMarkedOut is an OSD marked out because it repeatedly went down and up again, or because
+	// its device is predicted to fail soon
+	CorrectiveActionOSDMarkedOut CorrectiveActionType = "OSDMarkedOut"
+	// CorrectiveActionHealthMuted is a transient health warning muted as set in healthCheck.muteWarnings
+	CorrectiveActionHealthMuted CorrectiveActionType = "HealthMuted"
+	// CorrectiveActionBlocklistCleared is a stale entry of the osd blocklist removed after its node rejoined
+	CorrectiveActionBlocklistCleared CorrectiveActionType = "BlocklistCleared"
+	// CorrectiveActionOSDPodRecreated is the pod of an OSD of a pod set created again after it failed or was deleted
+	CorrectiveActionOSDPodRecreated CorrectiveActionType = "OSDPodRecreated"
+)
+
+// CorrectiveAction represents an automated corrective action taken by the operator
+type CorrectiveAction struct {
+	// Time is the time the action was taken
+	Time string `json:"time"`
+	// Type is the type of the action
+	Type CorrectiveActionType `json:"type"`
+	// Target is the name of the daemon the action was taken on
+	Target string `json:"target"`
+	// Reason is why the action was taken
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// MonHealthStatus represents the quorum and health details of the mons
+type MonHealthStatus struct {
+	// Mons is the list of mons found in the mon map
+	// +optional
+	Mons []MonHealth `json:"mons,omitempty"`
+	// LastChecked is the time the mon health was last refreshed
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// DesiredCount is the number of mons targeted by the operator, scaled with the number of failure domains
+	// if mon.autoScale is enabled
+	// +optional
+	DesiredCount int `json:"desiredCount,omitempty"`
+}
+
+// MonHealth represents the health of a single mon
+type MonHealth struct {
+	// Name of the mon
+	Name string `json:"name"`
+	// Rank of the mon in the mon map
+	Rank int `json:"rank"`
+	// InQuorum is whether the mon is currently in quorum
+	InQuorum bool `json:"inQuorum"`
+	// ClockSkewSeconds is the clock skew of the mon relative to the leader
+	// +optional
+	ClockSkewSeconds float64 `json:"clockSkewSeconds,omitempty"`
+	// StoreSizeBytes is the size of the mon store on disk
+	// +optional
+	StoreSizeBytes uint64 `json:"storeSizeBytes,omitempty"`
+	// LastCompacted is the time of the last compaction of the mon store by the operator
+	// +optional
+	LastCompacted string `json:"lastCompacted,omitempty"`
+}
+
+// CephDaemonsVersions show the current ceph version for different ceph daemons
+type CephDaemonsVersions struct {
+	// Mon shows Mon Ceph version
+	// +optional
+	Mon map[string]int `json:"mon,omitempty"`
+	// Mgr shows Mgr Ceph version
+	// +optional
+	Mgr map[string]int `json:"mgr,omitempty"`
+	// Osd shows Osd Ceph version
+	// +optional
+	Osd map[string]int `json:"osd,omitempty"`
+	// Rgw shows Rgw Ceph version
+	// +optional
+	Rgw map[string]int `json:"rgw,omitempty"`
+	// Mds shows Mds Ceph version
+	// +optional
+	Mds map[string]int `json:"mds,omitempty"`
+	// RbdMirror shows RbdMirror Ceph version
+	// +optional
+	RbdMirror map[string]int `json:"rbd-mirror,omitempty"`
+	// CephFSMirror shows CephFSMirror Ceph version
+	// +optional
+	CephFSMirror map[string]int `json:"cephfs-mirror,omitempty"`
+	// Overall shows overall Ceph version
+	// +optional
+	Overall map[string]int `json:"overall,omitempty"`
+}
+
+// CephStatus is the details health of a Ceph Cluster
+type CephStatus struct {
+	Health         string                       `json:"health,omitempty"`
+	Details        map[string]CephHealthMessage `json:"details,omitempty"`
+	LastChecked    string                       `json:"lastChecked,omitempty"`
+	LastChanged    string                       `json:"lastChanged,omitempty"`
+	PreviousHealth string                       `json:"previousHealth,omitempty"`
+	Capacity       Capacity                     `json:"capacity,omitempty"`
+	// +optional
+	Versions *CephDaemonsVersions `json:"versions,omitempty"`
+	// Balancer is the status of the balancer when it is configured in the mgr settings
+	// +optional
+	Balancer *BalancerStatus `json:"balancer,omitempty"`
+	// Recovery is the progress of the recovery and the backfill of the placement groups, such as after
+	// a change of the topology. It is only set while objects are degraded or misplaced.
+	// +optional
+	Recovery *RecoveryStatus `json:"recovery,omitempty"`
+	// PGsNotDeepScrubbed is the number of placement groups that were not deep scrubbed in time
+	// +optional
+	PGsNotDeepScrubbed int `json:"pgsNotDeepScrubbed,omitempty"`
+}
+
+// RecoveryStatus represents the progress of the recovery and the backfill of the placement groups
+type RecoveryStatus struct {
+	// DegradedObjects is the number of object copies that are missing
+	// +optional
+	DegradedObjects uint64 `json:"degradedObjects,omitempty"`
+	// DegradedPercent is the percentage of the object copies that are missing
+	// +optional
+	DegradedPercent float64 `json:"degradedPercent,omitempty"`
+	// MisplacedObjects is the number of object copies that are not on the OSDs they belong to
+	// +optional
+	MisplacedObjects uint64 `json:"misplacedObjects,omitempty"`
+	// MisplacedPercent is the percentage of the object copies that are not on the OSDs they belong to
+	// +optional
+	MisplacedPercent float64 `json:"misplacedPercent,omitempty"`
+	// RecoveringObjectsPerSec is the current rate of the recovery and the backfill
+	// +optional
+	RecoveringObjectsPerSec uint64 `json:"recoveringObjectsPerSec,omitempty"`
+	// StartTime is when the objects started to be degraded or misplaced, or when more objects became
+	// degraded or misplaced since
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// StartObjects is the number of degraded and misplaced object copies at the start time
+	// +optional
+	StartObjects uint64 `json:"startObjects,omitempty"`
+	// EstimatedCompletionTime is when the recovery and the backfill are expected to complete, at the
+	// average rate since the start time
+	// +optional
+	EstimatedCompletionTime string `json:"estimatedCompletionTime,omitempty"`
+}
+
+// BalancerStatus represents the status of the balancer mgr module
+type BalancerStatus struct {
+	// Active is whether the balancer is turned on
+	Active bool `json:"active"`
+	// Mode is the mode of the balancer
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Score is the score of the data distribution as evaluated by the balancer, lower is better
+	// +optional
+	Score float64 `json:"score,omitempty"`
+	// LastOptimizeStarted is when the balancer last started to optimize
+	// +optional
+	LastOptimizeStarted string `json:"lastOptimizeStarted,omitempty"`
+	// OptimizeResult is the result of the last optimization
+	// +optional
+	OptimizeResult string `json:"optimizeResult,omitempty"`
+}
+
+// Capacity is the capacity information of a Ceph Cluster
+type Capacity struct {
+	TotalBytes     uint64 `json:"bytesTotal,omitempty"`
+	UsedBytes      uint64 `json:"bytesUsed,omitempty"`
+	AvailableBytes uint64 `json:"bytesAvailable,omitempty"`
+	LastUpdated    string `json:"lastUpdated,omitempty"`
+}
+
+// CephStorage represents flavors of Ceph Cluster Storage
+type CephStorage struct {
+	DeviceClasses []DeviceClasses `json:"deviceClasses,omitempty"`
+	// OSDs is the status of each OSD of the cluster, refreshed by the health check of the OSDs
+	// +optional
+	OSDs []OSDStatus `json:"osd,omitempty"`
+}
+
+// DeviceClasses represents device classes of a Ceph Cluster
+type DeviceClasses struct {
+	Name string `json:"name,omitempty"`
+}
+
+// OSDStatus represents the status of an OSD
+type OSDStatus struct {
+	// ID is the id of the OSD
+	ID int `json:"id"`
+	// Node is the node running the OSD
+	// +optional
+	Node string `json:"node,omitempty"`
+	// DevicePath is the path of the device of an OSD on a node
+	// +optional
+	DevicePath string `json:"devicePath,omitempty"`
+	// PVC is the name of the PVC of an OSD on a PVC
+	// +optional
+	PVC string `json:"pvc,omitempty"`
+	// Up is whether the OSD is up
+	Up bool `json:"up"`
+	// In is whether the OSD is in the cluster
+	In bool `json:"in"`
+	// DeviceClass is the crush device class of the OSD
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+	// Version is the Ceph version the OSD runs
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// OSDPrepareFailure represents the failure of the OSD prepare job of a node or PVC
+type OSDPrepareFailure struct {
+	// Name is the name of the node or PVC
+	Name string `json:"name"`
+	// Message is the reason of the failure
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// OSDProvisioningStatus represents the progress of the OSD prepare jobs of a reconcile
+type OSDProvisioningStatus struct {
+	// Generation is the generation of the CephCluster the prepare jobs run for
+	Generation int64 `json:"generation"`
+	// Pending is the number of prepare jobs waiting for a slot to run
+	Pending int `json:"pending"`
+	// InFlight is the number of prepare jobs running
+	InFlight int `json:"inFlight"`
+	// Completed is the number of prepare jobs that completed
+	Completed int `json:"completed"`
+	// Failed is the number of prepare jobs that failed
+	Failed int `json:"failed"`
+	// StartTime is when the prepare jobs started
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// CompletionTime is when all the prepare jobs finished, empty while they are running. If the operator
+	// restarts before, the prepare jobs of the completed nodes are not run again for the same generation.
+	// +optional
+	CompletionTime string `json:"completionTime,omitempty"`
+	// CompletedNodes are the nodes whose prepare job completed while the prepare jobs are running
+	// +optional
+	CompletedNodes []string `json:"completedNodes,omitempty"`
+	// Nodes are the prepare jobs running or waiting on each node while the prepare jobs are running
+	// +optional
+	Nodes []OSDProvisioningNodeStatus `json:"nodes,omitempty"`
+}
+
+// OSDProvisioningNodeStatus represents the prepare jobs of a node running or waiting for a slot
+type OSDProvisioningNodeStatus struct {
+	// Name is the name of the node, empty for the prepare jobs on PVCs whose volume is not bound to a node yet
+	// +optional
+	Name string `json:"name,omitempty"`
+	// InFlight are the nodes or PVCs whose prepare job runs on the node
+	// +optional
+	InFlight []string `json:"inFlight,omitempty"`
+	// Pending are the nodes or PVCs whose prepare job waits for a slot on the node, in order
+	// +optional
+	Pending []string `json:"pending,omitempty"`
+	// LeaseHolder is the pod holding the provisioning lease of the node, such as a prepare job or the discover
+	// daemon
+	// +optional
+	LeaseHolder string `json:"leaseHolder,omitempty"`
+}
+
+// OSDReplacementPhase is the phase of the replacement of an OSD
+type OSDReplacementPhase string
+
+const (
+	// OSDReplacementPending means the OSD cannot be stopped yet without making placement groups inactive, or
+	// the OSD is out and its placement groups are not moved to the other OSDs yet
+	OSDReplacementPending OSDReplacementPhase = "Pending"
+	// OSDReplacementDestroying means the OSD is safe to destroy and its deployment is deleted, the destroy is
+	// retried until it succeeds
+	OSDReplacementDestroying OSDReplacementPhase = "Destroying"
+	// OSDReplacementPreparing means the OSD is destroyed and its device is wiped and prepared again
+	OSDReplacementPreparing OSDReplacementPhase = "Preparing"
+	// OSDReplacementCompleted means the OSD is running again on its wiped device with the same ID
+	OSDReplacementCompleted OSDReplacementPhase = "Completed"
+)
+
+// OSDReplacement represents the replacement of an OSD, which is destroyed and prepared again on its
+// wiped device with the same ID
+type OSDReplacement struct {
+	// ID is the ID of the OSD
+	ID int `json:"id"`
+	// Name is the name of the node or PVC of the OSD
+	Name string `json:"name"`
+	// Path is the path of the block device of the OSD being replaced
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Phase is the phase of the replacement
+	Phase OSDReplacementPhase `json:"phase"`
+	// Message describes the phase of the replacement
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the OSD was destroyed
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// CompletionTime is the time the OSD was running again
+	// +optional
+	CompletionTime string `json:"completionTime,omitempty"`
+}
+
+// ClusterVersion represents the version of a Ceph Cluster
+type ClusterVersion struct {
+	Image   string `json:"image,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// CephHealthMessage represents the health message of a Ceph Cluster
+type CephHealthMessage struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Condition represents a status condition on any Rook-Ceph Custom Resource.
 type Condition struct {
 	Type               ConditionType      `json:"type,omitempty"`
 	Status             v1.ConditionStatus `json:"status,omitempty"`
@@ -423,6 +1462,54 @@ const (
 	// ObjectHasNoDependentsReason represents when a resource object has no dependents that are
 	// blocking deletion.
 	ObjectHasNoDependentsReason ConditionReason = "ObjectHasNoDependents"
+	// ResourcesOrphanedReason represents that the generated resources were orphaned by the deletion policy
+	ResourcesOrphanedReason ConditionReason = "ResourcesOrphaned"
+
+	// IncompatibleClientsReason represents when connected clients do not support the enforced
+	// connection settings.
+	IncompatibleClientsReason ConditionReason = "IncompatibleClients"
+	// CompatibleClientsReason represents when all connected clients support the enforced connection
+	// settings.
+	CompatibleClientsReason ConditionReason = "CompatibleClients"
+
+	// MonFailoverAwaitingApprovalReason represents when the failover of a mon out of quorum is waiting
+	// for approval.
+	MonFailoverAwaitingApprovalReason ConditionReason = "AwaitingApproval"
+	// MonFailoverNotProposedReason represents when no mon failover is waiting for approval.
+	MonFailoverNotProposedReason ConditionReason = "NoFailoverProposed"
+	// MonClockSkewReason represents when the clock skew of a mon is above the warning threshold.
+	MonClockSkewReason ConditionReason = "MonClockSkew"
+	// MgrFailedOverReason represents when the unresponsive active mgr was failed over.
+	MgrFailedOverReason ConditionReason = "MgrFailedOver"
+	// OSDPrepareFailedReason represents when OSD prepare jobs failed and the reconcile continued.
+	OSDPrepareFailedReason ConditionReason = "OSDPrepareFailed"
+	// OSDPrepareSucceededReason represents when the OSD prepare jobs succeeded again.
+	OSDPrepareSucceededReason ConditionReason = "OSDPrepareSucceeded"
+	// MgrModuleCrashLoopReason represents when mgr modules of the spec were disabled because they
+	// repeatedly crashed the mgr.
+	MgrModuleCrashLoopReason ConditionReason = "MgrModuleCrashLoop"
+	// MgrModuleCrashLoopResolvedReason represents when no mgr module of the spec is disabled anymore.
+	MgrModuleCrashLoopResolvedReason ConditionReason = "MgrModuleCrashLoopResolved"
+	// KeyringRepairedReason represents when the key of a secret was replaced with the key of the Ceph user.
+	KeyringRepairedReason ConditionReason = "KeyringRepaired"
+	// UpgradeAwaitingApprovalReason represents when the canary daemons were upgraded and the upgrade of
+	// the other daemons is waiting for approval.
+	UpgradeAwaitingApprovalReason ConditionReason = "CanariesUpgraded"
+	// UpgradeNotAwaitingApprovalReason represents when no upgrade is waiting for approval.
+	UpgradeNotAwaitingApprovalReason ConditionReason = "NoUpgradeAwaitingApproval"
+	// OSDCreationRefusedReason represents when the new OSDs were not created because the rebalance could
+	// fill the cluster above the nearfull ratio.
+	OSDCreationRefusedReason ConditionReason = "OSDCreationRefused"
+	// OSDCreationAllowedReason represents when the new OSDs were created although the rebalance could fill
+	// the cluster above the nearfull ratio, because it is allowed in the storage spec.
+	OSDCreationAllowedReason ConditionReason = "OSDCreationAllowed"
+	// RebalanceBelowNearfullReason represents when the rebalance to the new OSDs keeps the cluster below the
+	// nearfull ratio.
+	RebalanceBelowNearfullReason ConditionReason = "RebalanceBelowNearfull"
+	// NodeTuningFailedReason represents when the node tuning failed and the reconcile continued.
+	NodeTuningFailedReason ConditionReason = "NodeTuningFailed"
+	// NodeTuningSucceededReason represents when the node tuning succeeded again.
+	NodeTuningSucceededReason ConditionReason = "NodeTuningSucceeded"
 )
 
 // ConditionType represent a resource's status
@@ -444,6 +1531,27 @@ const (
 
 	// ConditionDeletionIsBlocked represents when deletion of the object is blocked.
 	ConditionDeletionIsBlocked ConditionType = "DeletionIsBlocked"
+	// ConditionResourcesOrphaned represents that the resources generated for the object are kept after its deletion
+	ConditionResourcesOrphaned ConditionType = "ResourcesOrphaned"
+
+	// ConditionClientsIncompatible represents when connected clients would be locked out by the
+	// enforcement of the connection settings.
+	ConditionClientsIncompatible ConditionType = "ClientsIncompatible"
+
+	// ConditionMonFailoverProposed represents when the failover of a mon is waiting for approval.
+	ConditionMonFailoverProposed ConditionType = "MonFailoverProposed"
+
+	// ConditionUpgradeAwaitingApproval represents when the canary daemons of a canary upgrade were
+	// upgraded and the upgrade of the other daemons is waiting for approval.
+	ConditionUpgradeAwaitingApproval ConditionType = "UpgradeAwaitingApproval"
+
+	// ConditionRebalanceAboveNearfull represents when the rebalance of the data to the new OSDs could fill
+	// the cluster above the nearfull ratio.
+	ConditionRebalanceAboveNearfull ConditionType = "RebalanceAboveNearfull"
+
+	// ConditionDegraded represents when the cluster is reconciled without some of its resources, such as
+	// the OSDs of the nodes whose prepare job failed, the mgr modules that crashed the mgr or the node tuning.
+	ConditionDegraded ConditionType = "Degraded"
 )
 
 // ClusterState represents the state of a Ceph Cluster
@@ -481,6 +1589,85 @@ type MonSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
 	VolumeClaimTemplate *v1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+	// Failover is the failover settings of the mons
+	// +optional
+	// +nullable
+	Failover *MonFailoverSpec `json:"failover,omitempty"`
+	// Compaction is the settings of the compaction of the mon stores by the operator
+	// +optional
+	// +nullable
+	Compaction *MonCompactionSpec `json:"compaction,omitempty"`
+	// DNSDiscovery is the settings of the discovery of the mons by the clients with DNS SRV records
+	// +optional
+	// +nullable
+	DNSDiscovery *MonDNSDiscoverySpec `json:"dnsDiscovery,omitempty"`
+	// AutoScale is the settings of the scaling of the mon count with the number of failure domains
+	// +optional
+	// +nullable
+	AutoScale *MonAutoScaleSpec `json:"autoScale,omitempty"`
+}
+
+// MonAutoScaleSpec represents the scaling of the mon count with the size of the cluster. The mon count is the
+// largest odd number not above the number of failure domains of the nodes allowed to run the mons, within the
+// min and max counts, and overrides the count of the mons.
+type MonAutoScaleSpec struct {
+	// Enabled scales the mon count with the number of failure domains
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// MinCount is the mon count of the small clusters, 3 if not set
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9
+	// +optional
+	MinCount int `json:"minCount,omitempty"`
+	// MaxCount is the mon count of the large clusters, 5 if not set
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9
+	// +optional
+	MaxCount int `json:"maxCount,omitempty"`
+	// FailureDomainLabel is the node label of the failure domains, the hostname if not set
+	// +optional
+	FailureDomainLabel string `json:"failureDomainLabel,omitempty"`
+}
+
+// MonDNSDiscoverySpec represents the discovery of the mons with DNS SRV records. The mons are published
+// behind a headless service, and the clients look up the mons with mon_dns_srv_name instead of a static
+// list of mon IPs.
+type MonDNSDiscoverySpec struct {
+	// Enabled publishes the mons behind a headless service and the client configuration in a configmap
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// ClusterDomain is the DNS domain of the Kubernetes cluster, "cluster.local" if not set
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +optional
+	ClusterDomain string `json:"clusterDomain,omitempty"`
+}
+
+// MonCompactionSpec represents the compaction of the mon stores by the operator. A single mon is
+// compacted at a time, and only while all the mons are in quorum.
+type MonCompactionSpec struct {
+	// Interval is the interval between the compactions of each mon store. The stores are not compacted
+	// periodically if not set.
+	// +optional
+	// +nullable
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// StoreSizeThreshold is the size above which a mon store is compacted
+	// +optional
+	// +nullable
+	StoreSizeThreshold *resource.Quantity `json:"storeSizeThreshold,omitempty"`
+}
+
+// MonFailoverSpec represents the failover settings of the mons
+type MonFailoverSpec struct {
+	// RequireConfirmation determines if the failover of a mon out of quorum must be approved. Instead of
+	// failing over the mon after the timeout, the failover is proposed with an event and a condition of the
+	// CephCluster and only happens once approved with the ceph.rook.io/approve-mon-failover annotation.
+	// +optional
+	RequireConfirmation bool `json:"requireConfirmation,omitempty"`
+	// PVCRetentionPeriod is how long the PVC of a mon that was failed over or removed is kept before it is
+	// deleted, for instance to recover the mon store. The PVCs are deleted right away if not set.
+	// +optional
+	// +nullable
+	PVCRetentionPeriod *metav1.Duration `json:"pvcRetentionPeriod,omitempty"`
 }
 
 // StretchClusterSpec represents the specification of a stretched Ceph Cluster
@@ -513,9 +1700,9 @@ type StretchClusterZoneSpec struct {
 
 // MgrSpec represents options to configure a ceph mgr
 type MgrSpec struct {
-	// Count is the number of manager to run
+	// Count is the number of manager to run. One manager is active and the others are standbys.
 	// +kubebuilder:validation:Minimum=0
-	// +kubebuilder:validation:Maximum=2
+	// +kubebuilder:validation:Maximum=5
 	// +optional
 	Count int `json:"count,omitempty"`
 	// AllowMultiplePerNode allows to run multiple managers on the same node (not recommended)
@@ -525,6 +1712,49 @@ type MgrSpec struct {
 	// +optional
 	// +nullable
 	Modules []Module `json:"modules,omitempty"`
+	// Balancer is the configuration of the balancer module, which optimizes the distribution of the data
+	// across the OSDs
+	// +optional
+	// +nullable
+	Balancer *BalancerSpec `json:"balancer,omitempty"`
+}
+
+// BalancerSpec represents the settings of the balancer mgr module
+type BalancerSpec struct {
+	// Mode is the mode of the balancer: "upmap" (the default), "crush-compat", or "off" to turn the balancer off
+	// +kubebuilder:validation:Enum=upmap;crush-compat;off
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// MaxMisplacedRatio is the maximum ratio of the objects that the balancer can misplace at once
+	// (target_max_misplaced_ratio), between 0 and 1. The Ceph default of 0.05 applies if not set.
+	// +optional
+	MaxMisplacedRatio *float64 `json:"maxMisplacedRatio,omitempty"`
+	// Schedule is the window when the balancer is allowed to optimize the data distribution,
+	// the balancer runs at any time if not set
+	// +optional
+	// +nullable
+	Schedule *BalancerScheduleSpec `json:"schedule,omitempty"`
+}
+
+// BalancerScheduleSpec represents the window when the balancer is allowed to optimize
+type BalancerScheduleSpec struct {
+	// BeginTime is the time of the day in UTC when the balancer starts optimizing, formatted as HHMM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3])[0-5][0-9]$`
+	BeginTime string `json:"beginTime"`
+	// EndTime is the time of the day in UTC when the balancer stops optimizing, formatted as HHMM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3])[0-5][0-9]$`
+	EndTime string `json:"endTime"`
+	// BeginWeekday is the first day of the week when the balancer optimizes, from 0 (Sunday) to 6 (Saturday)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=6
+	// +optional
+	BeginWeekday *int `json:"beginWeekday,omitempty"`
+	// EndWeekday is the day of the week when the balancer stops optimizing, from 0 (Sunday) to 6 (Saturday).
+	// The balancer optimizes every day if the begin and end weekdays are the same.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=6
+	// +optional
+	EndWeekday *int `json:"endWeekday,omitempty"`
 }
 
 // Module represents mgr modules that the user wants to enable or disable
@@ -535,6 +1765,20 @@ type Module struct {
 	// Enabled determines whether a module should be enabled or not
 	// +optional
 	Enabled bool `json:"enabled,omitempty"`
+	// Settings are the options of the module, set as "mgr/<module>/<key>" in the centralized mon
+	// configuration database while the module is enabled
+	// +optional
+	// +nullable
+	Settings map[string]string `json:"settings,omitempty"`
+	// Port is the port the module listens on, exposed with a named port of the mgr modules service
+	// while the module is enabled. The "server_port" setting of the module is set to the port if not set.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int `json:"port,omitempty"`
+	// ServiceMonitor scrapes the metrics served on the port of the module when the monitoring is enabled
+	// +optional
+	ServiceMonitor bool `json:"serviceMonitor,omitempty"`
 }
 
 // ExternalSpec represents the options supported by an external cluster
@@ -544,6 +1788,11 @@ type ExternalSpec struct {
 	// Enable determines whether external mode is enabled or not
 	// +optional
 	Enable bool `json:"enable,omitempty"`
+	// Contributor runs the OSDs of the storage spec in this Kubernetes cluster and adds them to the external
+	// cluster, whose mons and mgrs run in another Kubernetes cluster. The admin key of the external cluster is
+	// required.
+	// +optional
+	Contributor bool `json:"contributor,omitempty"`
 }
 
 // CrashCollectorSpec represents options to configure the crash controller
@@ -633,10 +1882,80 @@ type PoolSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	StatusCheck MirrorHealthCheckSpec `json:"statusCheck,omitempty"`
 
+	// ImageGC finds the RBD images provisioned by the CSI driver that have no persistent volume, such as when the
+	// provisioning was interrupted, and reports them or moves them to the trash
+	// +optional
+	// +nullable
+	ImageGC *ImageGCSpec `json:"imageGC,omitempty"`
+
 	// The quota settings
 	// +optional
 	// +nullable
 	Quotas QuotaSpec `json:"quotas,omitempty"`
+
+	// The mclock scheduler settings of the OSDs of the device class of the pool
+	// +optional
+	// +nullable
+	MClock *MClockSpec `json:"mclock,omitempty"`
+
+	// The settings of the migration of the pool to a new crush rule when its device class, failure domain or
+	// crush root changes. Only applied to the replicated CephBlockPools.
+	// +optional
+	// +nullable
+	Migration *PoolMigrationSpec `json:"migration,omitempty"`
+}
+
+// PoolMigrationSpec represents the settings of the migration of a pool to a new crush rule
+type PoolMigrationSpec struct {
+	// MaxBackfills is the osd_max_backfills of the OSDs of the new device class during the migration,
+	// bounding the impact of the backfill on the client IO. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBackfills int `json:"maxBackfills,omitempty"`
+}
+
+// MClockSpec represents the settings of the mclock scheduler of the OSDs, bounding the client IO
+// and the background work. The settings require Ceph Quincy or newer.
+type MClockSpec struct {
+	// Profile is the mclock profile of the OSDs. The allocations are only honored with the custom
+	// profile, which is the default if any allocation is set.
+	// +kubebuilder:validation:Enum=high_client_ops;balanced;high_recovery_ops;custom;""
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
+	// Client is the allocation of the client IO
+	// +optional
+	// +nullable
+	Client *MClockAllocationSpec `json:"client,omitempty"`
+
+	// Recovery is the allocation of the background recovery IO
+	// +optional
+	// +nullable
+	Recovery *MClockAllocationSpec `json:"recovery,omitempty"`
+
+	// BestEffort is the allocation of the background best-effort IO such as scrubbing and snap trimming
+	// +optional
+	// +nullable
+	BestEffort *MClockAllocationSpec `json:"bestEffort,omitempty"`
+}
+
+// MClockAllocationSpec represents the allocation of a class of IO by the mclock scheduler. The
+// settings left empty keep the Ceph defaults.
+type MClockAllocationSpec struct {
+	// Reservation is the IO capacity reserved for the class
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	Reservation string `json:"reservation,omitempty"`
+
+	// Weight is the proportional share of the spare IO capacity given to the class
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Weight int `json:"weight,omitempty"`
+
+	// Limit is the maximum IO capacity of the class, zero means unlimited
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	Limit string `json:"limit,omitempty"`
 }
 
 // MirrorHealthCheckSpec represents the health specification of a Ceph Storage Pool mirror
@@ -644,6 +1963,10 @@ type MirrorHealthCheckSpec struct {
 	// +optional
 	// +nullable
 	Mirror HealthCheckSpec `json:"mirror,omitempty"`
+	// Capacity is the periodic collection of the usage and the compression statistics of a CephBlockPool
+	// +optional
+	// +nullable
+	Capacity HealthCheckSpec `json:"capacity,omitempty"`
 }
 
 // CephBlockPoolStatus represents the mirroring status of Ceph Storage Pool
@@ -659,6 +1982,133 @@ type CephBlockPoolStatus struct {
 	// +optional
 	// +nullable
 	Info map[string]string `json:"info,omitempty"`
+	// CapacityStatus is the usage and the compression statistics of the pool
+	// +optional
+	// +nullable
+	CapacityStatus *PoolCapacityStatus `json:"capacityStatus,omitempty"`
+	// Migration is the status of the migration of the pool to a new crush rule
+	// +optional
+	// +nullable
+	Migration *PoolMigrationStatus `json:"migration,omitempty"`
+	// ImageGC are the orphaned images of the pool
+	// +optional
+	// +nullable
+	ImageGC *ImageGCStatus `json:"imageGC,omitempty"`
+}
+
+// PoolMigrationPhase is the phase of the migration of a pool to a new crush rule
+type PoolMigrationPhase string
+
+const (
+	// PoolMigrationInProgress is the phase of a migration moving the data of the pool
+	PoolMigrationInProgress PoolMigrationPhase = "InProgress"
+	// PoolMigrationCompleted is the phase of a migration that moved all the data of the pool
+	PoolMigrationCompleted PoolMigrationPhase = "Completed"
+)
+
+// PoolMigrationStatus represents the status of the migration of a pool to a new crush rule
+type PoolMigrationStatus struct {
+	// Phase is the phase of the migration
+	// +optional
+	Phase PoolMigrationPhase `json:"phase,omitempty"`
+	// PreviousCrushRule is the crush rule of the pool before the migration
+	// +optional
+	PreviousCrushRule string `json:"previousCrushRule,omitempty"`
+	// CrushRule is the crush rule the pool is migrated to
+	// +optional
+	CrushRule string `json:"crushRule,omitempty"`
+	// MisplacedObjects is the number of objects of the pool that are not moved yet
+	// +optional
+	MisplacedObjects int64 `json:"misplacedObjects,omitempty"`
+	// Progress is the percentage of the objects of the pool already moved, e.g. "42.50%"
+	// +optional
+	Progress string `json:"progress,omitempty"`
+	// StartTime is the time the migration started
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// CompletionTime is the time the migration completed
+	// +optional
+	CompletionTime string `json:"completionTime,omitempty"`
+	// LastChecked is the last time the progress was checked
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+}
+
+// ImageGCAction is the action taken on the orphaned images of a pool
+type ImageGCAction string
+
+const (
+	// ImageGCActionReport only reports the orphaned images in the status of the pool
+	ImageGCActionReport ImageGCAction = "Report"
+	// ImageGCActionTrash moves the orphaned images to the trash of the pool, from where they can be restored
+	ImageGCActionTrash ImageGCAction = "Trash"
+)
+
+// ImageGCSpec represents the collection of the orphaned RBD images of a pool
+type ImageGCSpec struct {
+	// Enabled enables the periodic scan of the images of the pool
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Action is the action taken on the orphaned images. Defaults to Report.
+	// +kubebuilder:validation:Enum=Report;Trash;""
+	// +optional
+	Action ImageGCAction `json:"action,omitempty"`
+	// MinAge is the age of an image without persistent volume after which it is orphaned, like 48h.
+	// Defaults to 24h.
+	// +optional
+	MinAge *metav1.Duration `json:"minAge,omitempty"`
+	// ImagePrefix is the prefix of the names of the images provisioned by the CSI driver, as set by the
+	// volumeNamePrefix of the storage classes. Defaults to "csi-vol-".
+	// +optional
+	ImagePrefix string `json:"imagePrefix,omitempty"`
+	// Interval is the interval between the scans, like 6h. Defaults to 1h.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// ImageGCStatus represents the orphaned RBD images of a pool found at the last scan
+type ImageGCStatus struct {
+	// LastChecked is the time of the last scan of the images
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// OrphanedImages are the orphaned images reported and left in the pool
+	// +optional
+	OrphanedImages []string `json:"orphanedImages,omitempty"`
+	// TrashedImages are the orphaned images moved to the trash at the last scan
+	// +optional
+	TrashedImages []string `json:"trashedImages,omitempty"`
+	// Message describes why the last scan failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PoolCapacityStatus represents the usage and the compression statistics of a pool from "ceph df detail"
+type PoolCapacityStatus struct {
+	// StoredBytes is the size of the data stored by the clients in the pool
+	// +optional
+	StoredBytes uint64 `json:"storedBytes,omitempty"`
+	// UsedBytes is the raw capacity used by the pool, including the replicas or the coding chunks
+	// +optional
+	UsedBytes uint64 `json:"usedBytes,omitempty"`
+	// CompressedBytes is the raw capacity used by the compressed data
+	// +optional
+	CompressedBytes uint64 `json:"compressedBytes,omitempty"`
+	// CompressedOriginalBytes is the raw size of the compressed data before its compression
+	// +optional
+	CompressedOriginalBytes uint64 `json:"compressedOriginalBytes,omitempty"`
+	// CompressionRatio is the ratio of the size of the compressed data before and after its compression,
+	// e.g. "2.50", or empty if no data is compressed
+	// +optional
+	CompressionRatio string `json:"compressionRatio,omitempty"`
+	// CompressionSavedBytes is the raw capacity saved by the compression
+	// +optional
+	CompressionSavedBytes uint64 `json:"compressionSavedBytes,omitempty"`
+	// LastChecked is the last time the statistics were collected
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details contains the error of the last collection, if any
+	// +optional
+	Details string `json:"details,omitempty"`
 }
 
 // MirroringStatusSpec is the status of the pool mirroring
@@ -975,6 +2425,12 @@ type FilesystemSpec struct {
 	// +optional
 	PreserveFilesystemOnDelete bool `json:"preserveFilesystemOnDelete,omitempty"`
 
+	// DeletionPolicy is what happens to the resources generated for the CR when the CR is deleted. With
+	// "Orphan", the deployments, services, secrets, configmaps and PVCs are kept. Defaults to "Delete".
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
 	// The mds pod info
 	MetadataServer MetadataServerSpec `json:"metadataServer"`
 
@@ -986,6 +2442,25 @@ type FilesystemSpec struct {
 	// The mirroring statusCheck
 	// +kubebuilder:pruning:PreserveUnknownFields
 	StatusCheck MirrorHealthCheckSpec `json:"statusCheck,omitempty"`
+
+	// Hooks are the jobs run by the operator once the filesystem is ready
+	// +optional
+	Hooks HooksSpec `json:"hooks,omitempty"`
+
+	// Clients is the reporting and the eviction of the sessions of the clients of the filesystem
+	// +optional
+	Clients FilesystemClientsSpec `json:"clients,omitempty"`
+}
+
+// FilesystemClientsSpec is the reporting and the eviction of the sessions of the clients of a filesystem
+type FilesystemClientsSpec struct {
+	// StatusCheck is the periodic reporting of the sessions of the clients in the status, every minute by default
+	// +optional
+	// +nullable
+	StatusCheck HealthCheckSpec `json:"statusCheck,omitempty"`
+	// EvictFromDeletedNodes evicts the stale sessions of the clients whose node or pod no longer exists
+	// +optional
+	EvictFromDeletedNodes bool `json:"evictFromDeletedNodes,omitempty"`
 }
 
 // MetadataServerSpec represents the specification of a Ceph Metadata Server
@@ -1027,6 +2502,36 @@ type MetadataServerSpec struct {
 	// PriorityClassName sets priority classes on components
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Tuning is the tuning of the cache and of the client sessions of the metadata servers, applied to
+	// the running metadata servers without restarting them
+	// +nullable
+	// +optional
+	Tuning *MDSTuningSpec `json:"tuning,omitempty"`
+}
+
+// MDSTuningSpec represents the tuning of the cache and of the client sessions of the metadata servers. The
+// settings left empty keep the Ceph defaults.
+type MDSTuningSpec struct {
+	// CacheMemoryLimit is the memory limit of the cache of a metadata server, like 4Gi. It overrides the
+	// limit derived from the memory resources, and must be lower than the memory limit of the pods.
+	// +nullable
+	// +optional
+	CacheMemoryLimit *resource.Quantity `json:"cacheMemoryLimit,omitempty"`
+
+	// MaxCapsPerClient is the maximum number of capabilities a client can hold
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxCapsPerClient int `json:"maxCapsPerClient,omitempty"`
+
+	// SessionTimeout is how long a client can be unresponsive before its capabilities are revoked, like 60s
+	// +optional
+	SessionTimeout *metav1.Duration `json:"sessionTimeout,omitempty"`
+
+	// SessionAutoclose is how long a client can be unresponsive before its session is closed and the
+	// client is evicted, like 300s
+	// +optional
+	SessionAutoclose *metav1.Duration `json:"sessionAutoclose,omitempty"`
 }
 
 // FSMirroringSpec represents the setting for a mirrored filesystem
@@ -1075,6 +2580,56 @@ type CephFilesystemStatus struct {
 	// MirroringStatus is the filesystem mirroring status
 	// +optional
 	MirroringStatus *FilesystemMirroringInfoSpec `json:"mirroringStatus,omitempty"`
+	// Hooks is the status of the post-ready hooks
+	// +optional
+	Hooks []HookStatus `json:"hooks,omitempty"`
+	// Clients is the status of the sessions of the clients of the filesystem
+	// +optional
+	// +nullable
+	Clients *FilesystemClientsStatus `json:"clients,omitempty"`
+}
+
+// FilesystemClientsStatus is the status of the sessions of the clients of a filesystem
+type FilesystemClientsStatus struct {
+	// Sessions are the sessions of the clients with the mds of rank 0
+	// +optional
+	Sessions []FilesystemClientSession `json:"sessions,omitempty"`
+	// Evicted are the sessions evicted by the last eviction
+	// +optional
+	Evicted []FilesystemClientSession `json:"evicted,omitempty"`
+	// EvictionRequest is the value of the evict-clients annotation handled last
+	// +optional
+	EvictionRequest string `json:"evictionRequest,omitempty"`
+	// LastChecked is the last time the sessions were checked
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details contains the error of the last check
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// FilesystemClientSession is a session of a client with the mds, and the node or the pod of the client
+type FilesystemClientSession struct {
+	// ID is the id of the client
+	ID int64 `json:"id"`
+	// State is the state of the session, such as "open" or "stale"
+	// +optional
+	State string `json:"state,omitempty"`
+	// Address is the ip of the client
+	// +optional
+	Address string `json:"address,omitempty"`
+	// Hostname is the hostname reported by the client
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// Node is the node of the client, if the client runs on a node of the cluster or in a pod with the host network
+	// +optional
+	Node string `json:"node,omitempty"`
+	// Pod is the pod of the client as "namespace/name", if the client runs in a pod
+	// +optional
+	Pod string `json:"pod,omitempty"`
+	// NumCaps is the number of the capabilities held by the client
+	// +optional
+	NumCaps int `json:"numCaps,omitempty"`
 }
 
 // FilesystemMirroringInfo is the status of the pool mirroring
@@ -1258,6 +2813,12 @@ type ObjectStoreSpec struct {
 	// +optional
 	PreservePoolsOnDelete bool `json:"preservePoolsOnDelete,omitempty"`
 
+	// DeletionPolicy is what happens to the resources generated for the CR when the CR is deleted. With
+	// "Orphan", the deployments, services, secrets, configmaps and PVCs are kept. Defaults to "Delete".
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
 	// The rgw pod info
 	// +optional
 	// +nullable
@@ -1276,7 +2837,90 @@ type ObjectStoreSpec struct {
 	// Security represents security settings
 	// +optional
 	// +nullable
-	Security *SecuritySpec `json:"security,omitempty"`
+	Security *ObjectStoreSecuritySpec `json:"security,omitempty"`
+
+	// Hooks are the jobs run by the operator once the object store is ready
+	// +optional
+	Hooks HooksSpec `json:"hooks,omitempty"`
+}
+
+// ObjectStoreSecuritySpec represents the server side encryption settings of the object store. The KMS of the
+// SSE-KMS encryption, with the keys requested by the S3 clients, is in kms.
+type ObjectStoreSecuritySpec struct {
+	SecuritySpec `json:",inline"`
+	// ServerSideEncryptionS3 is the KMS of the SSE-S3 encryption, with the keys managed by rgw, such as for
+	// the default encryption of the buckets. Only Vault with the transit secret engine is supported.
+	// +optional
+	// +nullable
+	ServerSideEncryptionS3 KeyManagementServiceSpec `json:"s3,omitempty"`
+}
+
+// HooksSpec represents the jobs run by the operator for a resource
+type HooksSpec struct {
+	// PostReady are the jobs run once after the resource first becomes ready, e.g. to seed buckets or
+	// create subvolumes. A hook that failed is run again if its job is deleted.
+	// +optional
+	PostReady []HookSpec `json:"postReady,omitempty"`
+}
+
+// HookSpec represents a job run by the operator for a resource
+type HookSpec struct {
+	// Name is the name of the hook, unique for the resource
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name string `json:"name"`
+	// Image is the image of the job
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// Command is the entrypoint of the job, the entrypoint of the image is used if empty
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// Args are the arguments of the entrypoint
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// Env are the environment variables of the job, in addition to the ROOK_HOOK_RESOURCE_KIND,
+	// ROOK_HOOK_RESOURCE_NAME and ROOK_HOOK_RESOURCE_NAMESPACE variables set by the operator
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+	// ServiceAccountName is the service account of the job, the default service account of the
+	// namespace is used if empty
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// BackoffLimit is the number of retries of the job before the hook is failed, 6 by default
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+// HookPhase is the phase of a hook
+type HookPhase string
+
+const (
+	// HookRunning means the job of the hook is running
+	HookRunning HookPhase = "Running"
+	// HookSucceeded means the job of the hook completed, it is not run again
+	HookSucceeded HookPhase = "Succeeded"
+	// HookFailed means the job of the hook failed
+	HookFailed HookPhase = "Failed"
+)
+
+// HookStatus represents the status of a hook
+type HookStatus struct {
+	// Name is the name of the hook
+	Name string `json:"name"`
+	// JobName is the name of the job of the hook
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// Phase is the phase of the hook
+	// +optional
+	Phase HookPhase `json:"phase,omitempty"`
+	// Message is the reason of a failure
+	// +optional
+	Message string `json:"message,omitempty"`
+	// CompletionTime is the time the job of the hook completed
+	// +optional
+	// +nullable
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // BucketHealthCheckSpec represents the health check of an object store
@@ -1298,6 +2942,20 @@ type HealthCheckSpec struct {
 	Timeout string `json:"timeout,omitempty"`
 }
 
+// MonHealthCheckSpec represents the health check settings for the Ceph monitors. The timeout is the
+// duration a mon can be out of quorum before it is failed over.
+type MonHealthCheckSpec struct {
+	HealthCheckSpec `json:",inline"`
+	// DisableFailover disables the failover of the mons out of quorum while the health of the mons is
+	// still checked
+	// +optional
+	DisableFailover bool `json:"disableFailover,omitempty"`
+	// ClockSkewWarning is the clock skew of a mon relative to the leader above which a warning is
+	// reported, like 100ms. No warning is reported by the operator if not set.
+	// +optional
+	ClockSkewWarning string `json:"clockSkewWarning,omitempty"`
+}
+
 // GatewaySpec represents the specification of Ceph Object Store Gateway
 type GatewaySpec struct {
 	// The port the rgw service will be listening on (http)
@@ -1363,6 +3021,65 @@ type GatewaySpec struct {
 	// +optional
 	// +nullable
 	Service *RGWServiceSpec `json:"service,omitempty"`
+
+	// The draining of the in-flight requests of the rgw pods before they are stopped
+	// +optional
+	// +nullable
+	Drain *RGWDrainSpec `json:"drain,omitempty"`
+
+	// InstanceGroups are additional groups of rgw pods with their own placement and resources, such as
+	// to move the traffic of the object store to new nodes gradually
+	// +optional
+	// +nullable
+	InstanceGroups []RGWInstanceGroupSpec `json:"instanceGroups,omitempty"`
+}
+
+// RGWInstanceGroupSpec represents a group of rgw pods of an object store running in their own deployment
+// and exposed by their own service
+type RGWInstanceGroupSpec struct {
+	// Name is the name of the group, unique in the object store
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=20
+	Name string `json:"name"`
+
+	// The number of pods of the group
+	// +kubebuilder:validation:Minimum=1
+	Instances int32 `json:"instances"`
+
+	// The affinity to place the rgw pods of the group
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Placement Placement `json:"placement,omitempty"`
+
+	// The resource requirements for the rgw pods of the group
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PriorityClassName sets priority classes on the rgw pods of the group
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Weight is the percentage of the traffic of the object store to send to the group, set as a hint in the
+	// annotations of the services for the ingress controllers. The pods of the gateway get the remaining traffic.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// RGWDrainSpec represents the draining of the requests of the rgw pods when they are stopped by a scale-down or an update
+type RGWDrainSpec struct {
+	// Disabled stops the rgw pods without waiting for their in-flight requests
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// GracePeriodSeconds is the maximum time to wait for the in-flight requests to complete, 30 seconds by default
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GracePeriodSeconds int32 `json:"gracePeriodSeconds,omitempty"`
 }
 
 // ZoneSpec represents a Ceph Object Store Gateway Zone specification
@@ -1383,6 +3100,9 @@ type ObjectStoreStatus struct {
 	// +nullable
 	Info       map[string]string `json:"info,omitempty"`
 	Conditions []Condition       `json:"conditions,omitempty"`
+	// Hooks is the status of the post-ready hooks
+	// +optional
+	Hooks []HookStatus `json:"hooks,omitempty"`
 }
 
 // BucketStatus represents the status of a bucket
@@ -1419,6 +3139,24 @@ type ObjectStoreUserStatus struct {
 	// +optional
 	// +nullable
 	Info map[string]string `json:"info,omitempty"`
+	// KeyRotation is the status of the rotation of the keys of the user
+	// +optional
+	// +nullable
+	KeyRotation *ObjectUserKeyRotationStatus `json:"keyRotation,omitempty"`
+}
+
+// ObjectUserKeyRotationStatus represents the status of the rotation of the keys of an object store user
+type ObjectUserKeyRotationStatus struct {
+	// LastRotationTime is when the keys were last rotated
+	// +optional
+	// +nullable
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// PreviousAccessKey is the access key replaced by the last rotation, removed after the grace period
+	// +optional
+	PreviousAccessKey string `json:"previousAccessKey,omitempty"`
+	// LastRequest is the value of the annotation of the last on-demand rotation of the keys
+	// +optional
+	LastRequest string `json:"lastRequest,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -1438,12 +3176,34 @@ type ObjectStoreUserSpec struct {
 	// The display name for the ceph users
 	// +optional
 	DisplayName string `json:"displayName,omitempty"`
+	// The rgw tenant of the user, which isolates the names of its buckets from the buckets of the other tenants
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_]+$`
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
 	// +optional
 	// +nullable
 	Capabilities *ObjectUserCapSpec `json:"capabilities,omitempty"`
 	// +optional
 	// +nullable
 	Quotas *ObjectUserQuotaSpec `json:"quotas,omitempty"`
+	// KeyRotation is the periodic rotation of the S3 keys of the user
+	// +optional
+	// +nullable
+	KeyRotation *ObjectUserKeyRotationSpec `json:"keyRotation,omitempty"`
+}
+
+// ObjectUserKeyRotationSpec represents the rotation of the S3 keys of an object store user
+type ObjectUserKeyRotationSpec struct {
+	// Enabled rotates the keys periodically, and on demand when the CephObjectStoreUser is annotated
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval is the time between two rotations of the keys, 720h by default
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// GracePeriod is how long the previous keys stay valid after a rotation so the clients can reload the secret,
+	// 1h by default
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
 }
 
 // Additional admin-level capabilities for the Ceph object store user
@@ -1485,6 +3245,238 @@ type ObjectUserQuotaSpec struct {
 	// +optional
 	// +nullable
 	MaxObjects *int64 `json:"maxObjects,omitempty"`
+	// Bucket is the quota of each bucket of the user
+	// +optional
+	// +nullable
+	Bucket *ObjectBucketQuotaSpec `json:"bucket,omitempty"`
+}
+
+// ObjectBucketQuotaSpec is the quota of each bucket of an object store user
+type ObjectBucketQuotaSpec struct {
+	// Maximum size limit of the objects of a bucket
+	// +optional
+	// +nullable
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Maximum number of objects of a bucket
+	// +optional
+	// +nullable
+	MaxObjects *int64 `json:"maxObjects,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketTopic represents a Ceph Object Topic for Bucket Notifications
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:subresource:status
+type CephBucketTopic struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of the topic
+	Spec BucketTopicSpec `json:"spec"`
+	// Status represents the status of the topic
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *BucketTopicStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketTopicList represents a list of Ceph Object Topics for Bucket Notifications
+type CephBucketTopicList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephBucketTopic `json:"items"`
+}
+
+// BucketTopicSpec represents the specification of a topic of bucket notifications
+type BucketTopicSpec struct {
+	// ObjectStoreName is the name of the object store on which the topic is defined
+	// +kubebuilder:validation:MinLength=1
+	ObjectStoreName string `json:"objectStoreName"`
+	// ObjectStoreNamespace is the namespace of the object store on which the topic is defined
+	// +kubebuilder:validation:MinLength=1
+	ObjectStoreNamespace string `json:"objectStoreNamespace"`
+	// OpaqueData is added to the notifications sent to the endpoint
+	// +optional
+	OpaqueData string `json:"opaqueData,omitempty"`
+	// Persistent sends the notifications asynchronously, so they are retried until the endpoint acknowledges them
+	// +optional
+	Persistent bool `json:"persistent,omitempty"`
+	// Endpoint is the endpoint receiving the notifications
+	Endpoint TopicEndpointSpec `json:"endpoint"`
+}
+
+// TopicEndpointSpec represents the endpoint of a topic, exactly one of http, amqp and kafka must be set
+type TopicEndpointSpec struct {
+	// HTTP is the spec of an HTTP endpoint
+	// +optional
+	// +nullable
+	HTTP *HTTPEndpointSpec `json:"http,omitempty"`
+	// AMQP is the spec of an AMQP endpoint
+	// +optional
+	// +nullable
+	AMQP *AMQPEndpointSpec `json:"amqp,omitempty"`
+	// Kafka is the spec of a Kafka endpoint
+	// +optional
+	// +nullable
+	Kafka *KafkaEndpointSpec `json:"kafka,omitempty"`
+	// CredentialsSecretName is the name of a secret in the namespace of the topic with the "username" and "password"
+	// of the endpoint, which are added to the URI of the endpoint
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// HTTPEndpointSpec represents the spec of an HTTP endpoint of a topic
+type HTTPEndpointSpec struct {
+	// URI is the URI of the endpoint, http[s]://<fqdn>[:<port>][/<resource>]
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URI string `json:"uri"`
+	// DisableVerifySSL disables the verification of the certificate of the endpoint
+	// +optional
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+	// SendCloudEvents sends the notifications with the CloudEvents HTTP binding
+	// +optional
+	SendCloudEvents bool `json:"sendCloudEvents,omitempty"`
+}
+
+// AMQPEndpointSpec represents the spec of an AMQP endpoint of a topic
+type AMQPEndpointSpec struct {
+	// URI is the URI of the endpoint, amqp[s]://<fqdn>[:<port>][/<vhost>]
+	// +kubebuilder:validation:Pattern=`^amqps?://`
+	URI string `json:"uri"`
+	// Exchange is the name of the exchange routing the notifications, which must exist
+	// +kubebuilder:validation:MinLength=1
+	Exchange string `json:"exchange"`
+	// DisableVerifySSL disables the verification of the certificate of the endpoint
+	// +optional
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+	// AckLevel is when a notification is acknowledged, "broker" by default
+	// +kubebuilder:validation:Enum=none;broker;routable
+	// +optional
+	AckLevel string `json:"ackLevel,omitempty"`
+}
+
+// KafkaEndpointSpec represents the spec of a Kafka endpoint of a topic
+type KafkaEndpointSpec struct {
+	// URI is the URI of the endpoint, kafka://<fqdn>[:<port>]
+	// +kubebuilder:validation:Pattern=`^kafka://`
+	URI string `json:"uri"`
+	// UseSSL connects to the broker with TLS
+	// +optional
+	UseSSL bool `json:"useSSL,omitempty"`
+	// DisableVerifySSL disables the verification of the certificate of the broker
+	// +optional
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+	// AckLevel is when a notification is acknowledged, "broker" by default
+	// +kubebuilder:validation:Enum=none;broker
+	// +optional
+	AckLevel string `json:"ackLevel,omitempty"`
+}
+
+// BucketTopicStatus represents the status of a topic of bucket notifications
+type BucketTopicStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ARN is the ARN of the topic generated by the object store
+	// +optional
+	// +nullable
+	ARN *string `json:"ARN,omitempty"`
+	// ObservedGeneration is the latest generation of the topic applied to the object store
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketNotification represents a Bucket Notifications, applied to the buckets of the ObjectBucketClaims with the
+// label "bucket-notification-<name>"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Topic",type=string,JSONPath=`.spec.topic`
+// +kubebuilder:subresource:status
+type CephBucketNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of the notification
+	Spec BucketNotificationSpec `json:"spec"`
+	// Status represents the status of the notification
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *BucketNotificationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketNotificationList represents a list of Ceph Object Bucket Notifications
+type CephBucketNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephBucketNotification `json:"items"`
+}
+
+// BucketNotificationSpec represents the specification of a bucket notification
+type BucketNotificationSpec struct {
+	// Topic is the name of the CephBucketTopic in the namespace of the notification
+	// +kubebuilder:validation:MinLength=1
+	Topic string `json:"topic"`
+	// Events are the events sending the notification, all the events by default
+	// +optional
+	Events []BucketNotificationEvent `json:"events,omitempty"`
+	// Filter selects the objects sending the notification
+	// +optional
+	// +nullable
+	Filter *NotificationFilterSpec `json:"filter,omitempty"`
+}
+
+// BucketNotificationEvent is an event type of the bucket notifications
+// +kubebuilder:validation:Enum="s3:ObjectCreated:*";"s3:ObjectCreated:Put";"s3:ObjectCreated:Post";"s3:ObjectCreated:Copy";"s3:ObjectCreated:CompleteMultipartUpload";"s3:ObjectRemoved:*";"s3:ObjectRemoved:Delete";"s3:ObjectRemoved:DeleteMarkerCreated"
+type BucketNotificationEvent string
+
+// NotificationFilterSpec represents the filter of the objects sending a bucket notification
+type NotificationFilterSpec struct {
+	// KeyFilters are the filters on the name of the object
+	// +optional
+	KeyFilters []NotificationKeyFilterRule `json:"keyFilters,omitempty"`
+	// MetadataFilters are the filters on the metadata of the object
+	// +optional
+	MetadataFilters []NotificationFilterRule `json:"metadataFilters,omitempty"`
+	// TagFilters are the filters on the tags of the object
+	// +optional
+	TagFilters []NotificationFilterRule `json:"tagFilters,omitempty"`
+}
+
+// NotificationKeyFilterRule represents a filter on the name of the objects
+type NotificationKeyFilterRule struct {
+	// Name is the type of the filter
+	// +kubebuilder:validation:Enum=prefix;suffix;regex
+	Name string `json:"name"`
+	// Value is the prefix, the suffix or the regex of the name of the objects
+	Value string `json:"value"`
+}
+
+// NotificationFilterRule represents a filter on the metadata or the tags of the objects
+type NotificationFilterRule struct {
+	// Name is the name of the metadata or the tag
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Value is the value of the metadata or the tag
+	Value string `json:"value"`
+}
+
+// BucketNotificationStatus represents the status of a bucket notification
+type BucketNotificationStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ObjectBucketClaims are the names of the ObjectBucketClaims with the notification on their bucket
+	// +optional
+	ObjectBucketClaims []string `json:"objectBucketClaims,omitempty"`
 }
 
 // CephObjectRealm represents a Ceph Object Store Gateway Realm
@@ -1500,7 +3492,7 @@ type CephObjectRealm struct {
 	Spec ObjectRealmSpec `json:"spec,omitempty"`
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
-	Status *Status `json:"status,omitempty"`
+	Status *MultisiteStatus `json:"status,omitempty"`
 }
 
 // CephObjectRealmList represents a list Ceph Object Store Gateway Realms
@@ -1511,6 +3503,21 @@ type CephObjectRealmList struct {
 	Items           []CephObjectRealm `json:"items"`
 }
 
+// MultisiteStatus represents the status of a Ceph Object Store Gateway Realm, Zone Group or Zone
+type MultisiteStatus struct {
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// Period is the id of the current period of the realm
+	// +optional
+	Period string `json:"period,omitempty"`
+	// PeriodEpoch is the epoch of the current period of the realm, incremented by each commit of the period
+	// +optional
+	PeriodEpoch int `json:"periodEpoch,omitempty"`
+	// PendingCommit describes the changes of the configuration not committed to the period yet
+	// +optional
+	PendingCommit string `json:"pendingCommit,omitempty"`
+}
+
 // ObjectRealmSpec represent the spec of an ObjectRealm
 type ObjectRealmSpec struct {
 	Pull PullSpec `json:"pull"`
@@ -1533,7 +3540,7 @@ type CephObjectZoneGroup struct {
 	Spec              ObjectZoneGroupSpec `json:"spec"`
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
-	Status *Status `json:"status,omitempty"`
+	Status *MultisiteStatus `json:"status,omitempty"`
 }
 
 // CephObjectZoneGroupList represents a list Ceph Object Store Gateway Zone Groups
@@ -1561,7 +3568,7 @@ type CephObjectZone struct {
 	Spec              ObjectZoneSpec `json:"spec"`
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
-	Status *Status `json:"status,omitempty"`
+	Status *MultisiteStatus `json:"status,omitempty"`
 }
 
 // CephObjectZoneList represents a list Ceph Object Store Gateway Zones
@@ -1584,6 +3591,28 @@ type ObjectZoneSpec struct {
 	// The data pool settings
 	// +nullable
 	DataPool PoolSpec `json:"dataPool"`
+
+	// Failover is the promotion of the zone to master of its zone group when the master zone is lost
+	// +optional
+	Failover ZoneFailoverSpec `json:"failover,omitempty"`
+}
+
+// ZoneFailoverSpec represents the promotion of a secondary zone to master of its zone group
+type ZoneFailoverSpec struct {
+	// Promote promotes the zone to master of its zone group, committing a new period and pointing
+	// the endpoints of the zone group to the endpoints of the zone
+	// +optional
+	Promote bool `json:"promote,omitempty"`
+
+	// Automatic promotes the zone when the endpoints of the master zone are unreachable for longer
+	// than the MasterUnreachableTimeout
+	// +optional
+	Automatic bool `json:"automatic,omitempty"`
+
+	// MasterUnreachableTimeout is the duration after which an unreachable master zone is considered lost
+	// in the automatic mode, "10m" by default
+	// +optional
+	MasterUnreachableTimeout string `json:"masterUnreachableTimeout,omitempty"`
 }
 
 // RGWServiceSpec represent the spec for RGW service
@@ -1624,6 +3653,12 @@ type NFSGaneshaSpec struct {
 
 	// Server is the Ganesha Server specification
 	Server GaneshaServerSpec `json:"server"`
+
+	// DeletionPolicy is what happens to the resources generated for the CR when the CR is deleted. With
+	// "Orphan", the deployments, services, secrets, configmaps and PVCs are kept. Defaults to "Delete".
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // GaneshaRADOSSpec represents the specification of a Ganesha RADOS object
@@ -1700,6 +3735,39 @@ type NetworkSpec struct {
 	// DualStack determines whether Ceph daemons should listen on both IPv4 and IPv6
 	// +optional
 	DualStack bool `json:"dualStack,omitempty"`
+
+	// IPFamilies are the IP families Ceph daemons should listen on, the first one being the primary family.
+	// Two families configure a dual-stack cluster. The family of the mon services is set accordingly.
+	// +kubebuilder:validation:MaxItems=2
+	// +nullable
+	// +optional
+	IPFamilies []IPFamilyType `json:"ipFamilies,omitempty"`
+
+	// Settings for the network connections of the Ceph daemons and clients
+	// +nullable
+	// +optional
+	Connections *ConnectionsSpec `json:"connections,omitempty"`
+}
+
+// ConnectionsSpec represents the settings of the network connections of the Ceph daemons and clients
+type ConnectionsSpec struct {
+	// RequireMsgr2 disables the msgr1 port of the mons so that only the msgr2 protocol is accepted.
+	// The clients must support msgr2, for example kernel clients require a kernel 5.11 or newer.
+	// +optional
+	RequireMsgr2 bool `json:"requireMsgr2,omitempty"`
+
+	// Encryption settings of the network connections
+	// +nullable
+	// +optional
+	Encryption *EncryptionSpec `json:"encryption,omitempty"`
+}
+
+// EncryptionSpec represents the encryption settings of the network connections
+type EncryptionSpec struct {
+	// Enforced requires the secure mode of the msgr2 protocol for the connections between the daemons
+	// and from the clients, so that all the traffic is encrypted on the wire.
+	// +optional
+	Enforced bool `json:"enforced,omitempty"`
 }
 
 // DisruptionManagementSpec configures management of daemon disruptions
@@ -1728,6 +3796,11 @@ type DisruptionManagementSpec struct {
 	// Namespace to look for MDBs by the machineDisruptionBudgetController
 	// +optional
 	MachineDisruptionBudgetNamespace string `json:"machineDisruptionBudgetNamespace,omitempty"`
+
+	// ManageNodeMaintenance sets the noout flag on the CRUSH hosts of the OSDs of the nodes that are cordoned or
+	// annotated with "ceph.rook.io/maintenance: true", and removes it when the node is back in service
+	// +optional
+	ManageNodeMaintenance bool `json:"manageNodeMaintenance,omitempty"`
 }
 
 // +genclient
@@ -1773,6 +3846,89 @@ type CephClientStatus struct {
 	Info map[string]string `json:"info,omitempty"`
 }
 
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephAuthExport exports the credentials of a Ceph client and the mon endpoints of the cluster to a secret
+// in another namespace, for the workloads not managed by Rook
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="TargetNamespace",type=string,JSONPath=`.spec.targetNamespace`
+// +kubebuilder:subresource:status
+type CephAuthExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of a Ceph auth export
+	Spec AuthExportSpec `json:"spec"`
+	// Status represents the status of a Ceph auth export
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *CephAuthExportStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephAuthExportList represents a list of Ceph auth exports
+type CephAuthExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephAuthExport `json:"items"`
+}
+
+// AuthExportSpec represents the specification of a Ceph auth export
+type AuthExportSpec struct {
+	// User is the name of the Ceph client to export, without the "client." prefix
+	User string `json:"user"`
+	// TargetNamespace is the namespace of the secret. The namespace must have the label
+	// "ceph.rook.io/auth-export" with the namespace of the cluster as value.
+	TargetNamespace string `json:"targetNamespace"`
+	// SecretName is the name of the secret, rook-ceph-auth-<name> by default
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// CephAuthExportStatus represents the status of a Ceph auth export
+type CephAuthExportStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	// +nullable
+	Info map[string]string `json:"info,omitempty"`
+}
+
+// DeletionPolicy is what happens to the resources generated for a CR when the CR is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the generated resources with the CR
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan keeps the generated resources, without their owner reference to the CR
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// MinimumResourcesPolicy is what happens to the resources of the daemons below their minimum viable memory
+type MinimumResourcesPolicy string
+
+const (
+	// MinimumResourcesPolicyWarn reports the resources below the minimums in the operator log
+	MinimumResourcesPolicyWarn MinimumResourcesPolicy = "Warn"
+	// MinimumResourcesPolicyReject refuses the cluster while resources are below the minimums
+	MinimumResourcesPolicyReject MinimumResourcesPolicy = "Reject"
+	// MinimumResourcesPolicyRaise raises the requests and limits below the minimums to the minimums
+	MinimumResourcesPolicyRaise MinimumResourcesPolicy = "Raise"
+)
+
+// ClusterProfile is a preset of defaults for a common deployment pattern
+type ClusterProfile string
+
+const (
+	// ClusterProfileArchive is an object-only cluster for cold storage, with an erasure coded 8+3 data pool
+	// for the object stores, a small mgr footprint and bigger rgw caches
+	ClusterProfileArchive ClusterProfile = "archive"
+)
+
 // CleanupPolicySpec represents a Ceph Cluster cleanup policy
 type CleanupPolicySpec struct {
 	// Confirmation represents the cleanup confirmation
@@ -1817,120 +3973,503 @@ type SanitizeDisksSpec struct {
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
-// CephRBDMirror represents a Ceph RBD Mirror
+// CephRBDMirror represents a Ceph RBD Mirror
+// +kubebuilder:subresource:status
+type CephRBDMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              RBDMirroringSpec `json:"spec"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *Status `json:"status,omitempty"`
+}
+
+// CephRBDMirrorList represents a list Ceph RBD Mirrors
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CephRBDMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephRBDMirror `json:"items"`
+}
+
+// RBDMirroringSpec represents the specification of an RBD mirror daemon
+type RBDMirroringSpec struct {
+	// Count represents the number of rbd mirror instance to run
+	// +kubebuilder:validation:Minimum=1
+	Count int `json:"count"`
+
+	// Peers represents the peers spec
+	// +nullable
+	// +optional
+	Peers MirroringPeerSpec `json:"peers,omitempty"`
+
+	// The affinity to place the rgw pods (default is to place on any available node)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Placement Placement `json:"placement,omitempty"`
+
+	// The annotations-related configuration to add/set on each Pod related object.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Annotations rook.Annotations `json:"annotations,omitempty"`
+
+	// The labels-related configuration to add/set on each Pod related object.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Labels rook.Labels `json:"labels,omitempty"`
+
+	// The resource requirements for the rbd mirror pods
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PriorityClassName sets priority class on the rbd mirror pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// MirroringPeerSpec represents the specification of a mirror peer
+type MirroringPeerSpec struct {
+	// SecretNames represents the Kubernetes Secret names to add rbd-mirror or cephfs-mirror peers
+	// +optional
+	SecretNames []string `json:"secretNames,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemMirror is the Ceph Filesystem Mirror object definition
+// +kubebuilder:subresource:status
+type CephFilesystemMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              FilesystemMirroringSpec `json:"spec"`
+	// +optional
+	Status *Status `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemMirrorList is a list of CephFilesystemMirror
+type CephFilesystemMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemMirror `json:"items"`
+}
+
+// FilesystemMirroringSpec is the filesystem mirroring specification
+type FilesystemMirroringSpec struct {
+	// The affinity to place the rgw pods (default is to place on any available node)
+	// +nullable
+	// +optional
+	Placement Placement `json:"placement,omitempty"`
+
+	// The annotations-related configuration to add/set on each Pod related object.
+	// +nullable
+	// +optional
+	Annotations rook.Annotations `json:"annotations,omitempty"`
+
+	// The labels-related configuration to add/set on each Pod related object.
+	// +nullable
+	// +optional
+	Labels rook.Labels `json:"labels,omitempty"`
+
+	// The resource requirements for the cephfs-mirror pods
+	// +nullable
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PriorityClassName sets priority class on the cephfs-mirror pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemSubVolumeGroup represents a Ceph Filesystem SubVolumeGroup
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:subresource:status
+type CephFilesystemSubVolumeGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of a Ceph Filesystem SubVolumeGroup
+	Spec CephFilesystemSubVolumeGroupSpec `json:"spec"`
+	// Status represents the status of a CephFilesystem SubVolumeGroup
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *CephFilesystemSubVolumeGroupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemSubVolumeGroupList represents a list of Ceph Filesystem SubVolumeGroups
+type CephFilesystemSubVolumeGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemSubVolumeGroup `json:"items"`
+}
+
+// CephFilesystemSubVolumeGroupSpec represents the specification of a Ceph Filesystem SubVolumeGroup
+type CephFilesystemSubVolumeGroupSpec struct {
+	// FilesystemName is the name of the Ceph Filesystem of the SubVolumeGroup, typically the name
+	// of the CephFilesystem CR
+	FilesystemName string `json:"filesystemName"`
+	// Pinning is the policy spreading the metadata of the subvolumes of the group across the MDS
+	// ranks. At most one policy can be set, the distributed pinning is applied if none is set.
+	// +optional
+	Pinning SubVolumeGroupPinningSpec `json:"pinning,omitempty"`
+}
+
+// SubVolumeGroupPinningSpec represents the pinning policy of a Ceph Filesystem SubVolumeGroup
+// See https://docs.ceph.com/en/latest/cephfs/fs-volumes/#pinning-subvolumes-and-subvolume-groups
+type SubVolumeGroupPinningSpec struct {
+	// Export pins the subvolume group to the given MDS rank, -1 removes the pin
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=256
+	// +nullable
+	// +optional
+	Export *int `json:"export,omitempty"`
+	// Distributed spreads the subvolumes of the group across the MDS ranks when set to 1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +nullable
+	// +optional
+	Distributed *int `json:"distributed,omitempty"`
+	// Random pins the directories of the group to a random MDS rank with the given probability,
+	// between 0.0 and 1.0
+	// +nullable
+	// +optional
+	Random *float64 `json:"random,omitempty"`
+}
+
+// CephFilesystemSubVolumeGroupStatus represents the status of a Ceph Filesystem SubVolumeGroup
+type CephFilesystemSubVolumeGroupStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// +optional
+	// +nullable
+	Info map[string]string `json:"info,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemStaticVolume represents a static volume of a pre-existing directory of a Ceph Filesystem
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:subresource:status
+type CephFilesystemStaticVolume struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of a Ceph Filesystem static volume
+	Spec CephFilesystemStaticVolumeSpec `json:"spec"`
+	// Status represents the status of a Ceph Filesystem static volume
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *CephFilesystemStaticVolumeStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemStaticVolumeList represents a list of Ceph Filesystem static volumes
+type CephFilesystemStaticVolumeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemStaticVolume `json:"items"`
+}
+
+// CephFilesystemStaticVolumeSpec represents the specification of a Ceph Filesystem static volume
+type CephFilesystemStaticVolumeSpec struct {
+	// FilesystemName is the name of the Ceph Filesystem of the directory, typically the name of the
+	// CephFilesystem CR
+	FilesystemName string `json:"filesystemName"`
+	// Path is the absolute path of the pre-existing directory in the filesystem
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+	// ClaimNamespace is the namespace of the PersistentVolumeClaim of the volume
+	ClaimNamespace string `json:"claimNamespace"`
+	// ClaimName is the name of the PersistentVolumeClaim of the volume, the name of the CR if not set
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+	// Capacity is the capacity of the volume, 1Gi if not set. The capacity is not enforced on the directory.
+	// +optional
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+	// ReadOnly restricts the access of the cephx user of the volume to reading the directory
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// CephFilesystemStaticVolumeStatus represents the status of a Ceph Filesystem static volume
+type CephFilesystemStaticVolumeStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// +optional
+	// +nullable
+	Info map[string]string `json:"info,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBenchmark represents a benchmark run once against a pool of the cluster
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Bandwidth",type=string,JSONPath=`.status.results.bandwidth`
+// +kubebuilder:printcolumn:name="IOPS",type=string,JSONPath=`.status.results.iops`
 // +kubebuilder:subresource:status
-type CephRBDMirror struct {
+type CephBenchmark struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              RBDMirroringSpec `json:"spec"`
+	// Spec represents the specification of a benchmark
+	Spec CephBenchmarkSpec `json:"spec"`
+	// Status represents the status and the results of a benchmark
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
-	Status *Status `json:"status,omitempty"`
+	Status *CephBenchmarkStatus `json:"status,omitempty"`
 }
 
-// CephRBDMirrorList represents a list Ceph RBD Mirrors
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
-type CephRBDMirrorList struct {
+
+// CephBenchmarkList represents a list of benchmarks
+type CephBenchmarkList struct {
 	metav1.TypeMeta `json:",inline"`
 	metav1.ListMeta `json:"metadata"`
-	Items           []CephRBDMirror `json:"items"`
+	Items           []CephBenchmark `json:"items"`
 }
 
-// RBDMirroringSpec represents the specification of an RBD mirror daemon
-type RBDMirroringSpec struct {
-	// Count represents the number of rbd mirror instance to run
-	// +kubebuilder:validation:Minimum=1
-	Count int `json:"count"`
-
-	// Peers represents the peers spec
-	// +nullable
+// CephBenchmarkSpec represents the specification of a benchmark, with exactly one type of benchmark
+type CephBenchmarkSpec struct {
+	// Rados runs "rados bench" against a pool
 	// +optional
-	Peers MirroringPeerSpec `json:"peers,omitempty"`
-
-	// The affinity to place the rgw pods (default is to place on any available node)
-	// +kubebuilder:pruning:PreserveUnknownFields
-	// +nullable
+	Rados *RadosBenchmarkSpec `json:"rados,omitempty"`
+	// RBD runs "rbd bench" against a temporary image of a pool
 	// +optional
-	Placement Placement `json:"placement,omitempty"`
+	RBD *RBDBenchmarkSpec `json:"rbd,omitempty"`
+}
 
-	// The annotations-related configuration to add/set on each Pod related object.
-	// +kubebuilder:pruning:PreserveUnknownFields
-	// +nullable
+// RadosBenchmarkSpec represents the settings of a rados benchmark
+type RadosBenchmarkSpec struct {
+	// Pool is the name of the pool
+	// +kubebuilder:validation:MinLength=1
+	Pool string `json:"pool"`
+	// Mode is the type of the benchmark, "write" by default. The objects read by the "seq" and "rand"
+	// modes are written first and removed after the benchmark.
+	// +kubebuilder:validation:Enum=write;seq;rand
 	// +optional
-	Annotations rook.Annotations `json:"annotations,omitempty"`
+	Mode string `json:"mode,omitempty"`
+	// DurationSeconds is the duration of the benchmark, 60 seconds by default
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	DurationSeconds int `json:"durationSeconds,omitempty"`
+	// ObjectSize is the size of the objects written, 4Mi by default
+	// +optional
+	ObjectSize resource.Quantity `json:"objectSize,omitempty"`
+	// Concurrency is the number of concurrent operations, 16 by default
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Concurrency int `json:"concurrency,omitempty"`
+}
 
-	// The labels-related configuration to add/set on each Pod related object.
-	// +kubebuilder:pruning:PreserveUnknownFields
-	// +nullable
+// RBDBenchmarkSpec represents the settings of an rbd benchmark
+type RBDBenchmarkSpec struct {
+	// Pool is the name of the pool of the temporary image
+	// +kubebuilder:validation:MinLength=1
+	Pool string `json:"pool"`
+	// IOType is the type of the IOs, "write" by default
+	// +kubebuilder:validation:Enum=write;read;readwrite
 	// +optional
-	Labels rook.Labels `json:"labels,omitempty"`
+	IOType string `json:"ioType,omitempty"`
+	// IOPattern is the pattern of the IOs, "rand" by default
+	// +kubebuilder:validation:Enum=rand;seq
+	// +optional
+	IOPattern string `json:"ioPattern,omitempty"`
+	// IOSize is the size of the IOs, 4Ki by default
+	// +optional
+	IOSize resource.Quantity `json:"ioSize,omitempty"`
+	// IOTotal is the total size of the IOs, which is also the size of the temporary image, 1Gi by default
+	// +optional
+	IOTotal resource.Quantity `json:"ioTotal,omitempty"`
+	// IOThreads is the number of concurrent IOs, 16 by default
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IOThreads int `json:"ioThreads,omitempty"`
+}
 
-	// The resource requirements for the rbd mirror pods
-	// +kubebuilder:pruning:PreserveUnknownFields
+// BenchmarkPhase is the phase of a benchmark
+type BenchmarkPhase string
+
+const (
+	// BenchmarkRunning means the job of the benchmark is running
+	BenchmarkRunning BenchmarkPhase = "Running"
+	// BenchmarkSucceeded means the benchmark completed and its results are reported
+	BenchmarkSucceeded BenchmarkPhase = "Succeeded"
+	// BenchmarkFailed means the benchmark could not run or complete
+	BenchmarkFailed BenchmarkPhase = "Failed"
+)
+
+// CephBenchmarkStatus represents the status of a benchmark
+type CephBenchmarkStatus struct {
+	// +optional
+	Phase BenchmarkPhase `json:"phase,omitempty"`
+	// Message is the reason of a failure
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the spec of the last benchmark run
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
 	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
 	// +optional
-	Resources v1.ResourceRequirements `json:"resources,omitempty"`
-
-	// PriorityClassName sets priority class on the rbd mirror pods
+	// +nullable
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Results are the results of the benchmark once it succeeded
 	// +optional
-	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// +nullable
+	Results *BenchmarkResults `json:"results,omitempty"`
 }
 
-// MirroringPeerSpec represents the specification of a mirror peer
-type MirroringPeerSpec struct {
-	// SecretNames represents the Kubernetes Secret names to add rbd-mirror or cephfs-mirror peers
+// BenchmarkResults represents the summary of the results of a benchmark
+type BenchmarkResults struct {
+	// Bandwidth is the average bandwidth, e.g. "512.25 MiB/s"
 	// +optional
-	SecretNames []string `json:"secretNames,omitempty"`
+	Bandwidth string `json:"bandwidth,omitempty"`
+	// IOPS is the average number of operations per second
+	// +optional
+	IOPS string `json:"iops,omitempty"`
+	// AverageLatency is the average latency of the operations, if reported by the benchmark
+	// +optional
+	AverageLatency string `json:"averageLatency,omitempty"`
 }
 
 // +genclient
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
-// CephFilesystemMirror is the Ceph Filesystem Mirror object definition
+// CephPoolMigration represents the migration of the data of the rbd images of a replicated pool to an erasure
+// coded pool, with the live migration of the images
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Migrated",type=integer,JSONPath=`.status.migratedImages`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.totalImages`
 // +kubebuilder:subresource:status
-type CephFilesystemMirror struct {
+type CephPoolMigration struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              FilesystemMirroringSpec `json:"spec"`
+	// Spec represents the specification of a pool migration
+	Spec CephPoolMigrationSpec `json:"spec"`
+	// Status represents the status and the progress of a pool migration
+	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
-	Status *Status `json:"status,omitempty"`
+	Status *CephPoolMigrationStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
-// CephFilesystemMirrorList is a list of CephFilesystemMirror
-type CephFilesystemMirrorList struct {
+// CephPoolMigrationList represents a list of pool migrations
+type CephPoolMigrationList struct {
 	metav1.TypeMeta `json:",inline"`
 	metav1.ListMeta `json:"metadata"`
-	Items           []CephFilesystemMirror `json:"items"`
+	Items           []CephPoolMigration `json:"items"`
 }
 
-// FilesystemMirroringSpec is the filesystem mirroring specification
-type FilesystemMirroringSpec struct {
-	// The affinity to place the rgw pods (default is to place on any available node)
-	// +nullable
+// CephPoolMigrationSpec represents the specification of a pool migration
+type CephPoolMigrationSpec struct {
+	// SourcePool is the name of the replicated pool of the images. The images stay in this pool, which keeps
+	// their metadata, so the volumes of the images do not change.
+	// +kubebuilder:validation:MinLength=1
+	SourcePool string `json:"sourcePool"`
+	// TargetDataPool is the name of the erasure coded pool the data of the images is moved to. The pool must
+	// allow the overwrites of the erasure coded pools, as the erasure coded CephBlockPools do.
+	// +kubebuilder:validation:MinLength=1
+	TargetDataPool string `json:"targetDataPool"`
+	// Images are the names of the images to migrate, all the images of the source pool by default
 	// +optional
-	Placement Placement `json:"placement,omitempty"`
-
-	// The annotations-related configuration to add/set on each Pod related object.
-	// +nullable
+	Images []string `json:"images,omitempty"`
+	// ManualCommit stops the migration of the images before it is committed, so the migration can still be
+	// aborted by deleting the CephPoolMigration. The migration is committed once ManualCommit is unset.
 	// +optional
-	Annotations rook.Annotations `json:"annotations,omitempty"`
+	ManualCommit bool `json:"manualCommit,omitempty"`
+}
 
-	// The labels-related configuration to add/set on each Pod related object.
+// PoolDataMigrationPhase is the phase of a pool migration
+type PoolDataMigrationPhase string
+
+const (
+	// PoolDataMigrationRunning means the images are being migrated
+	PoolDataMigrationRunning PoolDataMigrationPhase = "Running"
+	// PoolDataMigrationWaitingForClients means the remaining images are in use and can't be migrated until their
+	// clients are stopped
+	PoolDataMigrationWaitingForClients PoolDataMigrationPhase = "WaitingForClients"
+	// PoolDataMigrationWaitingForCommit means the data of the images is moved and the migration waits for
+	// ManualCommit to be unset
+	PoolDataMigrationWaitingForCommit PoolDataMigrationPhase = "WaitingForCommit"
+	// PoolDataMigrationSucceeded means all the images are migrated
+	PoolDataMigrationSucceeded PoolDataMigrationPhase = "Succeeded"
+	// PoolDataMigrationFailed means the migration is invalid or some images failed to migrate
+	PoolDataMigrationFailed PoolDataMigrationPhase = "Failed"
+)
+
+// ImageMigrationPhase is the phase of the migration of an image
+type ImageMigrationPhase string
+
+const (
+	// ImageMigrationWaitingForClients means the image is in use and is not migrated until its clients are stopped
+	ImageMigrationWaitingForClients ImageMigrationPhase = "WaitingForClients"
+	// ImageMigrationPrepared means the image is linked to the new image of the migration, the clients can use it
+	ImageMigrationPrepared ImageMigrationPhase = "Prepared"
+	// ImageMigrationExecuted means the data of the image is copied to the target data pool
+	ImageMigrationExecuted ImageMigrationPhase = "Executed"
+	// ImageMigrationCommitted means the migration of the image is committed and the source image is removed
+	ImageMigrationCommitted ImageMigrationPhase = "Committed"
+	// ImageMigrationFailed means the image failed to migrate
+	ImageMigrationFailed ImageMigrationPhase = "Failed"
+)
+
+// CephPoolMigrationStatus represents the status of a pool migration
+type CephPoolMigrationStatus struct {
+	// +optional
+	Phase PoolDataMigrationPhase `json:"phase,omitempty"`
+	// Message is the reason of a failure
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the spec of the migration
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
 	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
 	// +optional
-	Labels rook.Labels `json:"labels,omitempty"`
-
-	// The resource requirements for the cephfs-mirror pods
 	// +nullable
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// TotalImages is the number of images to migrate
 	// +optional
-	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	TotalImages int `json:"totalImages,omitempty"`
+	// MigratedImages is the number of images whose migration is committed
+	// +optional
+	MigratedImages int `json:"migratedImages,omitempty"`
+	// Images are the statuses of the migrations of the images
+	// +optional
+	// +nullable
+	Images []ImageMigrationStatus `json:"images,omitempty"`
+}
 
-	// PriorityClassName sets priority class on the cephfs-mirror pods
+// ImageMigrationStatus represents the status of the migration of an image
+type ImageMigrationStatus struct {
+	// Name is the name of the image
+	Name string `json:"name"`
 	// +optional
-	PriorityClassName string `json:"priorityClassName,omitempty"`
+	Phase ImageMigrationPhase `json:"phase,omitempty"`
+	// Message is the reason of a failure
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // IPFamilyType represents the single stack Ipv4 or Ipv6 protocol.
@@ -1959,8 +4498,284 @@ type StorageScopeSpec struct {
 	// +nullable
 	// +optional
 	StorageClassDeviceSets []StorageClassDeviceSet `json:"storageClassDeviceSets,omitempty"`
+	// MClock is the mclock scheduler settings of all the OSDs. The settings of a pool override
+	// them for the OSDs of its device class.
+	// +nullable
+	// +optional
+	MClock *MClockSpec `json:"mclock,omitempty"`
+	// PrepareFailurePolicy is what to do when the OSD prepare job of a node or PVC fails: "Halt" (the
+	// default) fails the reconcile of the cluster, "Continue" reconciles the rest of the cluster and
+	// reports the cluster as degraded
+	// +kubebuilder:validation:Enum=Halt;Continue
+	// +optional
+	PrepareFailurePolicy PrepareFailurePolicy `json:"prepareFailurePolicy,omitempty"`
+	// DriveGroups select the data, db and wal devices of the OSDs of groups of nodes by the properties
+	// of the devices, with the semantics of the ceph-volume drive groups
+	// +nullable
+	// +optional
+	DriveGroups []DriveGroup `json:"driveGroups,omitempty"`
+	// AutoRemoveOSD purges the OSDs that stay down and out for longer than a grace period, such as the
+	// OSDs of failed nodes, and deletes their deployments
+	// +nullable
+	// +optional
+	AutoRemoveOSD *AutoRemoveOSDSpec `json:"autoRemoveOSD,omitempty"`
+	// MemoryTarget sets the osd_memory_target of the OSDs from the memory limit of their pods, which
+	// is applied again to the running OSDs when the limit changes
+	// +nullable
+	// +optional
+	MemoryTarget *OSDMemoryTargetSpec `json:"memoryTarget,omitempty"`
+	// Provisioning limits the number of OSD prepare jobs running at the same time. All the prepare
+	// jobs are started at once if not set.
+	// +nullable
+	// +optional
+	Provisioning *OSDProvisioningSpec `json:"provisioning,omitempty"`
+	// TopologyLabels are the node labels setting the CRUSH location of the OSDs, ordered from the highest
+	// to the lowest level of the CRUSH hierarchy. They apply in addition to the well-known kubernetes and
+	// topology.rook.io labels, and take precedence over the well-known label of the same level.
+	// +nullable
+	// +optional
+	TopologyLabels []CrushTopologyLabel `json:"topologyLabels,omitempty"`
+	// WeightRampUp starts the new OSDs with a CRUSH weight of 0 and raises their weight in steps up to the
+	// size of their device, so that adding many OSDs at once doesn't move all the data at once
+	// +nullable
+	// +optional
+	WeightRampUp *OSDWeightRampUpSpec `json:"weightRampUp,omitempty"`
+	// DryRun runs the OSD prepare jobs in a mode only reporting the devices they would consume, without
+	// touching the disks nor creating OSDs, to verify the device selection before a rollout
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// Migration re-provisions the OSDs with a legacy format one failure domain at a time: the filestore OSDs
+	// are re-created with bluestore and the OSDs encrypted with LUKS1 are re-created with LUKS2
+	// +nullable
+	// +optional
+	Migration *OSDMigrationSpec `json:"migration,omitempty"`
+	// DeviceInventory reports the devices discovered on the nodes in the deviceInventory status, with the
+	// reasons why the devices are not available for OSDs
+	// +nullable
+	// +optional
+	DeviceInventory *DeviceInventorySpec `json:"deviceInventory,omitempty"`
+	// UpdateStrategy throttles the updates of the OSD deployments, such as after a change of the ceph.conf
+	// overrides, updating the OSDs in batches of a failure domain and waiting for the PGs to be clean between
+	// the batches
+	// +nullable
+	// +optional
+	UpdateStrategy *OSDUpdateStrategySpec `json:"updateStrategy,omitempty"`
+	// Scrub is the schedule of the scrubs of all the OSDs, which can be overridden for the OSDs of a device
+	// class. The scrub options are left untouched if not set, so they can still be managed manually.
+	// +nullable
+	// +optional
+	Scrub *ScrubSpec `json:"scrub,omitempty"`
+	// AllowRebalanceAboveNearfull creates the new OSDs even if the rebalance of the data to the new OSDs
+	// could fill the cluster above the nearfull ratio. The new OSDs are not created by default in that case.
+	// +optional
+	AllowRebalanceAboveNearfull bool `json:"allowRebalanceAboveNearfull,omitempty"`
+}
+
+// ScrubSpec represents the schedule of the scrubs of the OSDs
+type ScrubSpec struct {
+	ScrubScheduleSpec `json:",inline"`
+	// DeviceClasses override the schedule for the OSDs of the device classes
+	// +nullable
+	// +optional
+	DeviceClasses map[string]ScrubScheduleSpec `json:"deviceClasses,omitempty"`
+}
+
+// ScrubScheduleSpec represents the scrub settings of a group of OSDs. The settings left empty keep the Ceph
+// defaults.
+type ScrubScheduleSpec struct {
+	// BeginHour is the hour of the day the scrubs can start at, from 0 to 23
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	// +nullable
+	// +optional
+	BeginHour *int `json:"beginHour,omitempty"`
+	// EndHour is the hour of the day the scrubs cannot start anymore, from 0 to 23
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	// +nullable
+	// +optional
+	EndHour *int `json:"endHour,omitempty"`
+	// MaxScrubs is the maximum number of scrubs running at the same time on an OSD
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxScrubs int `json:"maxScrubs,omitempty"`
+	// DeepScrubInterval is the interval between two deep scrubs of a placement group, like 168h
+	// +optional
+	DeepScrubInterval *metav1.Duration `json:"deepScrubInterval,omitempty"`
+}
+
+// OSDUpdateStrategySpec represents how the OSD deployments are updated
+type OSDUpdateStrategySpec struct {
+	// MaxUpdatesInParallel is the maximum number of OSDs updated in a batch, 20 by default
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxUpdatesInParallel int `json:"maxUpdatesInParallel,omitempty"`
+	// FailureDomain is the CRUSH level of the OSDs updated in the same batch, such as host or zone. The
+	// batches can span several failure domains if not set.
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+	// WaitForCleanPGs waits for all the placement groups to be active and clean before updating the next
+	// batch of OSDs
+	// +optional
+	WaitForCleanPGs bool `json:"waitForCleanPGs,omitempty"`
+	// CleanPGsTimeout is how long to wait for the placement groups to be clean after a batch, 10m by default.
+	// The OSDs left are updated at the next reconcile, unless continueUpgradeAfterChecksEvenIfNotHealthy is
+	// true.
+	// +optional
+	CleanPGsTimeout *metav1.Duration `json:"cleanPGsTimeout,omitempty"`
+}
+
+// DeviceInventorySpec represents the settings of the inventory of the devices of the nodes
+type DeviceInventorySpec struct {
+	// Enabled reports the inventory of the devices in the status of the CephCluster
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval is the interval between the refreshes of the inventory, 10m by default
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// CrushTopologyLabel maps a node label to a level of the CRUSH hierarchy
+type CrushTopologyLabel struct {
+	// Label is the key of the node label, its value is the name of the CRUSH bucket
+	// +kubebuilder:validation:MinLength=1
+	Label string `json:"label"`
+	// Type is the CRUSH bucket type of the level
+	// +kubebuilder:validation:Enum=chassis;rack;row;pdu;pod;room;datacenter;zone;region
+	Type string `json:"type"`
+}
+
+// OSDWeightRampUpSpec represents the gradual increase of the CRUSH weight of the new OSDs
+type OSDWeightRampUpSpec struct {
+	// Enabled starts the new OSDs with a CRUSH weight of 0 and raises their weight in steps. A step is only
+	// taken when all the placement groups are active and clean.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// StepPercent is the percentage of the target weight of an OSD added at each step, 10 by default
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	StepPercent int `json:"stepPercent,omitempty"`
+	// Interval is the minimum time between two steps, 10m by default
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// OSDMigrationSpec represents the settings of the migration of the OSDs with a legacy format
+type OSDMigrationSpec struct {
+	// Enabled starts the migration. The OSDs of a failure domain are marked out, and are destroyed and
+	// prepared again once all the placement groups are active and clean.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Paused stops the migration once the OSDs of the current failure domain are prepared again
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// FailureDomain is the CRUSH level whose OSDs are migrated at the same time, host by default
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
 }
 
+// OSDProvisioningSpec represents the limits of the OSD prepare jobs running at the same time
+type OSDProvisioningSpec struct {
+	// MaxInFlight is the maximum number of prepare jobs running at the same time in the cluster,
+	// unlimited if 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// MaxInFlightPerNode is the maximum number of prepare jobs started at the same time on a node,
+	// unlimited if 0. The prepare jobs of a node hold the provisioning lease of the node while they prepare
+	// its devices, so the jobs beyond the first one wait for the lease. The node of a prepare job on a PVC is
+	// known once its volume is bound to a node.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxInFlightPerNode int `json:"maxInFlightPerNode,omitempty"`
+}
+
+// OSDMemoryTargetSpec represents the ratios of the memory limit of the OSD pods targeted by the OSDs
+type OSDMemoryTargetSpec struct {
+	// Ratio is the ratio of the memory limit of the pod targeted by an OSD, 0.8 by default
+	// +kubebuilder:validation:Pattern=`^(0?\.[0-9]+|1(\.0+)?)$`
+	// +optional
+	Ratio string `json:"ratio,omitempty"`
+	// DeviceClassRatios override the ratio for the OSDs of the device classes
+	// +nullable
+	// +optional
+	DeviceClassRatios map[string]string `json:"deviceClassRatios,omitempty"`
+}
+
+// AutoRemoveOSDSpec represents the automatic removal of the OSDs that are down and out
+type AutoRemoveOSDSpec struct {
+	// Enabled purges the OSDs that are down and out for longer than the grace period. An OSD is only
+	// removed when all the placement groups are active and clean, once its data was rebalanced.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// GracePeriod is how long an OSD must stay down and out before it is removed, 24h by default
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// DriveGroup selects the devices of the OSDs of the storage nodes matching its node affinity. The devices
+// of a drive group on a node are prepared together with "ceph-volume lvm batch", which spreads the db and
+// the wal of the OSDs over the db and wal devices.
+type DriveGroup struct {
+	// Name is the name of the drive group
+	Name string `json:"name"`
+	// NodeAffinity selects the storage nodes of the drive group, all the storage nodes if not set
+	// +nullable
+	// +optional
+	NodeAffinity *v1.NodeAffinity `json:"nodeAffinity,omitempty"`
+	// DataDevices selects the data devices of the OSDs
+	DataDevices DriveGroupDeviceFilter `json:"dataDevices"`
+	// DBDevices selects the devices of the db of the OSDs
+	// +nullable
+	// +optional
+	DBDevices *DriveGroupDeviceFilter `json:"dbDevices,omitempty"`
+	// WALDevices selects the devices of the wal of the OSDs
+	// +nullable
+	// +optional
+	WALDevices *DriveGroupDeviceFilter `json:"walDevices,omitempty"`
+	// OSDsPerDevice is the number of OSDs created on each data device
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	OSDsPerDevice int `json:"osdsPerDevice,omitempty"`
+	// DeviceClass is the crush device class of the OSDs, detected by ceph if not set
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+}
+
+// DriveGroupDeviceFilter selects the available disks of a node by their properties. A filter without any
+// property selects all the available disks.
+type DriveGroupDeviceFilter struct {
+	// Rotational selects the rotational disks if true and the non-rotational disks if false
+	// +optional
+	Rotational *bool `json:"rotational,omitempty"`
+	// Size selects the disks by their size, as "<min>:<max>", "<min>:", ":<max>" or "<size>" for an exact
+	// size, with the sizes as quantities, e.g. "100Gi:2Ti"
+	// +kubebuilder:validation:Pattern=`^[0-9.]*[a-zA-Z]*:?[0-9.]*[a-zA-Z]*$`
+	// +optional
+	Size string `json:"size,omitempty"`
+	// Model is a regular expression matching the model of the disks
+	// +optional
+	Model string `json:"model,omitempty"`
+	// Vendor is a regular expression matching the vendor of the disks
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+	// Limit is the maximum number of disks selected on each node, unlimited if 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Limit int `json:"limit,omitempty"`
+}
+
+// PrepareFailurePolicy is the policy applied when OSD prepare jobs fail
+type PrepareFailurePolicy string
+
+const (
+	// PrepareFailurePolicyHalt fails the reconcile of the cluster when an OSD prepare job fails
+	PrepareFailurePolicyHalt PrepareFailurePolicy = "Halt"
+	// PrepareFailurePolicyContinue reconciles the rest of the cluster when an OSD prepare job fails
+	PrepareFailurePolicyContinue PrepareFailurePolicy = "Continue"
+)
+
 // Node is a storage nodes
 // +nullable
 type Node struct {
@@ -1973,8 +4788,12 @@ type Node struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +nullable
 	// +optional
-	Config    map[string]string `json:"config,omitempty"`
-	Selection `json:",inline"`
+	Config map[string]string `json:"config,omitempty"`
+	// CephConfig are the Ceph config options of the OSDs of the node, such as osd_recovery_max_active,
+	// set in the mon config store for each of these OSDs. The options of a device take precedence.
+	// +optional
+	CephConfig map[string]string `json:"cephConfig,omitempty"`
+	Selection  `json:",inline"`
 }
 
 // Device represents a disk to use in the cluster
@@ -1987,6 +4806,10 @@ type Device struct {
 	// +nullable
 	// +optional
 	Config map[string]string `json:"config,omitempty"`
+	// CephConfig are the Ceph config options of the OSDs of the device, such as bluestore_cache_size,
+	// set in the mon config store for each of these OSDs
+	// +optional
+	CephConfig map[string]string `json:"cephConfig,omitempty"`
 }
 
 type Selection struct {
@@ -2049,6 +4872,39 @@ type ProbeSpec struct {
 // PriorityClassNamesSpec is a map of priority class names to be assigned to components
 type PriorityClassNamesSpec map[rook.KeyType]string
 
+// DNSSpec is a map of the DNS settings to be applied to the pods of the components
+type DNSSpec map[rook.KeyType]DaemonDNSSpec
+
+// DaemonDNSSpec represents the DNS settings of the pods of a component
+type DaemonDNSSpec struct {
+	// Policy is the DNS policy of the pods. The pods on the host network use ClusterFirstWithHostNet if not set,
+	// the other pods use the default policy of Kubernetes.
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	// +optional
+	Policy v1.DNSPolicy `json:"policy,omitempty"`
+	// Config is the DNS config of the pods, such as custom nameservers and searches. It is required with
+	// the None policy.
+	// +nullable
+	// +optional
+	Config *v1.PodDNSConfig `json:"config,omitempty"`
+}
+
+// ContainerOverridesSpec is a map of the overrides of the containers of the pods of the components
+type ContainerOverridesSpec map[rook.KeyType]DaemonContainerOverrides
+
+// DaemonContainerOverrides represents the arguments and the init containers added to the pods of a component
+type DaemonContainerOverrides struct {
+	// ExtraArgs are appended to the arguments of the main container of the pods
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+	// InitContainers run after the init containers of the pods. The init containers without an image run the
+	// image of the main container.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	InitContainers []v1.Container `json:"initContainers,omitempty"`
+}
+
 // StorageClassDeviceSet is a storage class device set
 // +nullable
 type StorageClassDeviceSet struct {
@@ -2091,4 +4947,18 @@ type StorageClassDeviceSet struct {
 	// Whether to encrypt the deviceSet
 	// +optional
 	Encrypted bool `json:"encrypted,omitempty"`
+	// PodSet runs the new OSDs of the set in pods managed by the operator instead of a deployment per OSD,
+	// which lowers the number of objects of very large clusters. The identity of each OSD is kept in the
+	// env of its pod, and the pods failed or deleted are created again by the OSD health checker.
+	// +optional
+	PodSet bool `json:"podSet,omitempty"`
+	// CompressionMode is the bluestore compression mode of the OSDs of the set, the default mode of the pools
+	// that do not set their own compression mode
+	// +kubebuilder:validation:Enum=none;passive;aggressive;force;""
+	// +optional
+	CompressionMode string `json:"compressionMode,omitempty"`
+	// CompressionAlgorithm is the bluestore compression algorithm of the OSDs of the set
+	// +kubebuilder:validation:Enum=snappy;zlib;zstd;lz4;""
+	// +optional
+	CompressionAlgorithm string `json:"compressionAlgorithm,omitempty"`
 }
@@ -26,17 +26,24 @@ import (
 
 type CephV1Interface interface {
 	RESTClient() rest.Interface
+	CephAuthExportsGetter
+	CephBenchmarksGetter
 	CephBlockPoolsGetter
+	CephBucketNotificationsGetter
+	CephBucketTopicsGetter
 	CephClientsGetter
 	CephClustersGetter
 	CephFilesystemsGetter
 	CephFilesystemMirrorsGetter
+	CephFilesystemStaticVolumesGetter
+	CephFilesystemSubVolumeGroupsGetter
 	CephNFSesGetter
 	CephObjectRealmsGetter
 	CephObjectStoresGetter
 	CephObjectStoreUsersGetter
 	CephObjectZonesGetter
 	CephObjectZoneGroupsGetter
+	CephPoolMigrationsGetter
 	CephRBDMirrorsGetter
 }
 
@@ -45,10 +52,26 @@ type CephV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *CephV1Client) CephAuthExports(namespace string) CephAuthExportInterface {
+	return newCephAuthExports(c, namespace)
+}
+
+func (c *CephV1Client) CephBenchmarks(namespace string) CephBenchmarkInterface {
+	return newCephBenchmarks(c, namespace)
+}
+
 func (c *CephV1Client) CephBlockPools(namespace string) CephBlockPoolInterface {
 	return newCephBlockPools(c, namespace)
 }
 
+func (c *CephV1Client) CephBucketNotifications(namespace string) CephBucketNotificationInterface {
+	return newCephBucketNotifications(c, namespace)
+}
+
+func (c *CephV1Client) CephBucketTopics(namespace string) CephBucketTopicInterface {
+	return newCephBucketTopics(c, namespace)
+}
+
 func (c *CephV1Client) CephClients(namespace string) CephClientInterface {
 	return newCephClients(c, namespace)
 }
@@ -65,6 +88,14 @@ func (c *CephV1Client) CephFilesystemMirrors(namespace string) CephFilesystemMir
 	return newCephFilesystemMirrors(c, namespace)
 }
 
+func (c *CephV1Client) CephFilesystemStaticVolumes(namespace string) CephFilesystemStaticVolumeInterface {
+	return newCephFilesystemStaticVolumes(c, namespace)
+}
+
+func (c *CephV1Client) CephFilesystemSubVolumeGroups(namespace string) CephFilesystemSubVolumeGroupInterface {
+	return newCephFilesystemSubVolumeGroups(c, namespace)
+}
+
 func (c *CephV1Client) CephNFSes(namespace string) CephNFSInterface {
 	return newCephNFSes(c, namespace)
 }
@@ -89,6 +120,10 @@ func (c *CephV1Client) CephObjectZoneGroups(namespace string) CephObjectZoneGrou
 	return newCephObjectZoneGroups(c, namespace)
 }
 
+func (c *CephV1Client) CephPoolMigrations(namespace string) CephPoolMigrationInterface {
+	return newCephPoolMigrations(c, namespace)
+}
+
 func (c *CephV1Client) CephRBDMirrors(namespace string) CephRBDMirrorInterface {
 	return newCephRBDMirrors(c, namespace)
 }
@@ -28,10 +28,26 @@ type FakeCephV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeCephV1) CephAuthExports(namespace string) v1.CephAuthExportInterface {
+	return &FakeCephAuthExports{c, namespace}
+}
+
+func (c *FakeCephV1) CephBenchmarks(namespace string) v1.CephBenchmarkInterface {
+	return &FakeCephBenchmarks{c, namespace}
+}
+
 func (c *FakeCephV1) CephBlockPools(namespace string) v1.CephBlockPoolInterface {
 	return &FakeCephBlockPools{c, namespace}
 }
 
+func (c *FakeCephV1) CephBucketNotifications(namespace string) v1.CephBucketNotificationInterface {
+	return &FakeCephBucketNotifications{c, namespace}
+}
+
+func (c *FakeCephV1) CephBucketTopics(namespace string) v1.CephBucketTopicInterface {
+	return &FakeCephBucketTopics{c, namespace}
+}
+
 func (c *FakeCephV1) CephClients(namespace string) v1.CephClientInterface {
 	return &FakeCephClients{c, namespace}
 }
@@ -48,6 +64,14 @@ func (c *FakeCephV1) CephFilesystemMirrors(namespace string) v1.CephFilesystemMi
 	return &FakeCephFilesystemMirrors{c, namespace}
 }
 
+func (c *FakeCephV1) CephFilesystemStaticVolumes(namespace string) v1.CephFilesystemStaticVolumeInterface {
+	return &FakeCephFilesystemStaticVolumes{c, namespace}
+}
+
+func (c *FakeCephV1) CephFilesystemSubVolumeGroups(namespace string) v1.CephFilesystemSubVolumeGroupInterface {
+	return &FakeCephFilesystemSubVolumeGroups{c, namespace}
+}
+
 func (c *FakeCephV1) CephNFSes(namespace string) v1.CephNFSInterface {
 	return &FakeCephNFSes{c, namespace}
 }
@@ -72,6 +96,10 @@ func (c *FakeCephV1) CephObjectZoneGroups(namespace string) v1.CephObjectZoneGro
 	return &FakeCephObjectZoneGroups{c, namespace}
 }
 
+func (c *FakeCephV1) CephPoolMigrations(namespace string) v1.CephPoolMigrationInterface {
+	return &FakeCephPoolMigrations{c, namespace}
+}
+
 func (c *FakeCephV1) CephRBDMirrors(namespace string) v1.CephRBDMirrorInterface {
 	return &FakeCephRBDMirrors{c, namespace}
 }
@@ -18,8 +18,16 @@ limitations under the License.
 
 package v1
 
+type CephAuthExportExpansion interface{}
+
+type CephBenchmarkExpansion interface{}
+
 type CephBlockPoolExpansion interface{}
 
+type CephBucketNotificationExpansion interface{}
+
+type CephBucketTopicExpansion interface{}
+
 type CephClientExpansion interface{}
 
 type CephClusterExpansion interface{}
@@ -28,6 +36,10 @@ type CephFilesystemExpansion interface{}
 
 type CephFilesystemMirrorExpansion interface{}
 
+type CephFilesystemStaticVolumeExpansion interface{}
+
+type CephFilesystemSubVolumeGroupExpansion interface{}
+
 type CephNFSExpansion interface{}
 
 type CephObjectRealmExpansion interface{}
@@ -40,4 +52,6 @@ type CephObjectZoneExpansion interface{}
 
 type CephObjectZoneGroupExpansion interface{}
 
+type CephPoolMigrationExpansion interface{}
+
 type CephRBDMirrorExpansion interface{}
@@ -18,6 +18,22 @@ limitations under the License.
 
 package v1
 
+// CephAuthExportListerExpansion allows custom methods to be added to
+// CephAuthExportLister.
+type CephAuthExportListerExpansion interface{}
+
+// CephAuthExportNamespaceListerExpansion allows custom methods to be added to
+// CephAuthExportNamespaceLister.
+type CephAuthExportNamespaceListerExpansion interface{}
+
+// CephBenchmarkListerExpansion allows custom methods to be added to
+// CephBenchmarkLister.
+type CephBenchmarkListerExpansion interface{}
+
+// CephBenchmarkNamespaceListerExpansion allows custom methods to be added to
+// CephBenchmarkNamespaceLister.
+type CephBenchmarkNamespaceListerExpansion interface{}
+
 // CephBlockPoolListerExpansion allows custom methods to be added to
 // CephBlockPoolLister.
 type CephBlockPoolListerExpansion interface{}
@@ -26,6 +42,22 @@ type CephBlockPoolListerExpansion interface{}
 // CephBlockPoolNamespaceLister.
 type CephBlockPoolNamespaceListerExpansion interface{}
 
+// CephBucketNotificationListerExpansion allows custom methods to be added to
+// CephBucketNotificationLister.
+type CephBucketNotificationListerExpansion interface{}
+
+// CephBucketNotificationNamespaceListerExpansion allows custom methods to be added to
+// CephBucketNotificationNamespaceLister.
+type CephBucketNotificationNamespaceListerExpansion interface{}
+
+// CephBucketTopicListerExpansion allows custom methods to be added to
+// CephBucketTopicLister.
+type CephBucketTopicListerExpansion interface{}
+
+// CephBucketTopicNamespaceListerExpansion allows custom methods to be added to
+// CephBucketTopicNamespaceLister.
+type CephBucketTopicNamespaceListerExpansion interface{}
+
 // CephClientListerExpansion allows custom methods to be added to
 // CephClientLister.
 type CephClientListerExpansion interface{}
@@ -58,6 +90,22 @@ type CephFilesystemMirrorListerExpansion interface{}
 // CephFilesystemMirrorNamespaceLister.
 type CephFilesystemMirrorNamespaceListerExpansion interface{}
 
+// CephFilesystemStaticVolumeListerExpansion allows custom methods to be added to
+// CephFilesystemStaticVolumeLister.
+type CephFilesystemStaticVolumeListerExpansion interface{}
+
+// CephFilesystemStaticVolumeNamespaceListerExpansion allows custom methods to be added to
+// CephFilesystemStaticVolumeNamespaceLister.
+type CephFilesystemStaticVolumeNamespaceListerExpansion interface{}
+
+// CephFilesystemSubVolumeGroupListerExpansion allows custom methods to be added to
+// CephFilesystemSubVolumeGroupLister.
+type CephFilesystemSubVolumeGroupListerExpansion interface{}
+
+// CephFilesystemSubVolumeGroupNamespaceListerExpansion allows custom methods to be added to
+// CephFilesystemSubVolumeGroupNamespaceLister.
+type CephFilesystemSubVolumeGroupNamespaceListerExpansion interface{}
+
 // CephNFSListerExpansion allows custom methods to be added to
 // CephNFSLister.
 type CephNFSListerExpansion interface{}
@@ -106,6 +154,14 @@ type CephObjectZoneGroupListerExpansion interface{}
 // CephObjectZoneGroupNamespaceLister.
 type CephObjectZoneGroupNamespaceListerExpansion interface{}
 
+// CephPoolMigrationListerExpansion allows custom methods to be added to
+// CephPoolMigrationLister.
+type CephPoolMigrationListerExpansion interface{}
+
+// CephPoolMigrationNamespaceListerExpansion allows custom methods to be added to
+// CephPoolMigrationNamespaceLister.
+type CephPoolMigrationNamespaceListerExpansion interface{}
+
 // CephRBDMirrorListerExpansion allows custom methods to be added to
 // CephRBDMirrorLister.
 type CephRBDMirrorListerExpansion interface{}
@@ -41,12 +41,20 @@ func supportedDeviceType(device string) bool {
 		device == sys.LVMType ||
 		device == sys.MultiPath ||
 		device == sys.PartType ||
-		device == sys.LinearType
+		device == sys.LinearType ||
+		device == sys.BcacheType ||
+		device == sys.DMCacheType
+}
+
+// isCacheDeviceType returns whether the device is fronted by a cache. The parents of these
+// devices are their backing and cache devices.
+func isCacheDeviceType(device string) bool {
+	return device == sys.BcacheType || device == sys.DMCacheType
 }
 
 // GetDeviceEmpty check whether a device is completely empty
 func GetDeviceEmpty(device *sys.LocalDisk) bool {
-	return device.Parent == "" && supportedDeviceType(device.Type) && len(device.Partitions) == 0 && device.Filesystem == ""
+	return (device.Parent == "" || isCacheDeviceType(device.Type)) && supportedDeviceType(device.Type) && len(device.Partitions) == 0 && device.Filesystem == ""
 }
 
 func ignoreDevice(d string) bool {
@@ -87,8 +95,8 @@ func DiscoverDevices(executor exec.Executor) ([]*sys.LocalDisk, error) {
 		// Test if device has child, if so we skip it and only consider the partitions
 		// which will come in later iterations of the loop
 		// We only test if the type is 'disk', this is a property reported by lsblk
-		// and means it's a parent block device
-		if disk.Type == sys.DiskType {
+		// and means it's a parent block device, bcache devices can be partitioned too
+		if disk.Type == sys.DiskType || disk.Type == sys.BcacheType {
 			deviceChild, err := sys.ListDevicesChild(executor, d)
 			if err != nil {
 				logger.Warningf("failed to detect child devices for device %q, assuming they are none. %v", d, err)
@@ -122,6 +130,14 @@ func PopulateDeviceInfo(d string, executor exec.Executor) (*sys.LocalDisk, error
 	if !ok {
 		return nil, errors.New("diskType is empty")
 	}
+	// the devices fronted by bcache or dm-cache are reported with the type of their cache
+	cacheType, err := sys.GetCacheType(diskProps["KNAME"], diskType, executor)
+	if err != nil {
+		logger.Warningf("failed to detect if device %q is fronted by a cache. %v", d, err)
+	} else if cacheType != "" {
+		logger.Debugf("device %q is a %q device", d, cacheType)
+		diskType = cacheType
+	}
 	if !supportedDeviceType(diskType) {
 		return nil, fmt.Errorf("unsupported diskType %+s", diskType)
 	}
@@ -137,9 +153,7 @@ func PopulateDeviceInfo(d string, executor exec.Executor) (*sys.LocalDisk, error
 
 	disk := &sys.LocalDisk{Name: d, UUID: diskUUID}
 
-	if val, ok := diskProps["TYPE"]; ok {
-		disk.Type = val
-	}
+	disk.Type = diskType
 	if val, ok := diskProps["SIZE"]; ok {
 		if size, err := strconv.ParseUint(val, 10, 64); err == nil {
 			disk.Size = size
@@ -16,9 +16,11 @@ limitations under the License.
 package clusterd
 
 import (
+	"fmt"
 	"testing"
 
 	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/rook/rook/pkg/util/sys"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,3 +55,47 @@ func TestIgnoreDevice(t *testing.T) {
 		assert.Equal(t, expected, ignoreDevice(dev), dev)
 	}
 }
+
+func TestPopulateCacheDeviceInfo(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, arg ...string) (string, error) {
+			logger.Infof("mock execute with output. %s %v", command, arg)
+			switch {
+			case command == "lsblk" && arg[0] == "/dev/bcache0":
+				return `SIZE="4000787030016" ROTA="1" RO="0" TYPE="disk" PKNAME="/dev/sdb" NAME="/dev/bcache0" KNAME="/dev/bcache0"`, nil
+			case command == "lsblk" && arg[0] == "/dev/dm-2":
+				return `SIZE="4000787030016" ROTA="1" RO="0" TYPE="dm" PKNAME="/dev/sdc" NAME="/dev/mapper/cached" KNAME="/dev/dm-2"`, nil
+			case command == "lsblk" && arg[0] == "/dev/dm-3":
+				return `SIZE="4000787030016" ROTA="1" RO="0" TYPE="dm" PKNAME="/dev/sdd" NAME="/dev/mapper/linear" KNAME="/dev/dm-3"`, nil
+			case command == "dmsetup" && arg[1] == "/dev/dm-2":
+				return "0 7814037168 cache 253:1 253:0 8:32 512 1 writeback smq 0", nil
+			case command == "dmsetup" && arg[1] == "/dev/dm-3":
+				return "0 7814037168 linear 8:48 0", nil
+			case command == "sgdisk":
+				return "Disk identifier (GUID): 18484D7E-5287-4CE9-AC73-D02FB69055CE", nil
+			}
+			return "", fmt.Errorf("unexpected command %s %v", command, arg)
+		},
+	}
+
+	disk, err := PopulateDeviceInfo("bcache0", executor)
+	assert.NoError(t, err)
+	assert.Equal(t, sys.BcacheType, disk.Type)
+	assert.Equal(t, "sdb", disk.Parent)
+	// the parent of a bcache device is its backing device
+	assert.True(t, GetDeviceEmpty(disk))
+
+	disk, err = PopulateDeviceInfo("dm-2", executor)
+	assert.NoError(t, err)
+	assert.Equal(t, sys.DMCacheType, disk.Type)
+	assert.Equal(t, "/dev/mapper/cached", disk.RealPath)
+	assert.True(t, GetDeviceEmpty(disk))
+
+	// the other device mapper devices are still not supported
+	_, err = PopulateDeviceInfo("dm-3", executor)
+	assert.Error(t, err)
+
+	// the backing and cache devices carry the bcache signature
+	backing := &sys.LocalDisk{Name: "sdb", Type: sys.DiskType, Filesystem: sys.BcacheFilesystem}
+	assert.False(t, GetDeviceEmpty(backing))
+}
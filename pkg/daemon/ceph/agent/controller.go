@@ -25,6 +25,7 @@ import (
 	opagent "github.com/rook/rook/pkg/operator/ceph/agent"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/provisioner"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -61,6 +62,7 @@ type ReconcileAgent struct {
 // Add creates a new CephClient Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
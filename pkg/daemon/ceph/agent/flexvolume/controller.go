@@ -56,7 +56,9 @@ const (
 	// PoolKey key for image name option.
 	ImageKey = "image"
 	// PoolKey key for data pool name option.
-	DataBlockPoolKey      = "dataBlockPool"
+	DataBlockPoolKey = "dataBlockPool"
+	// VolumeModeKey key for the volume mode option.
+	VolumeModeKey         = "volumeMode"
 	kubeletDefaultRootDir = "/var/lib/kubelet"
 )
 
@@ -371,6 +373,9 @@ func (c *Controller) GetAttachInfoFromMountDir(mountDir string, attachOptions *A
 	if attachOptions.StorageClass == "" {
 		attachOptions.StorageClass = pv.Spec.PersistentVolumeSource.FlexVolume.Options[StorageClassKey]
 	}
+	if attachOptions.VolumeMode == "" {
+		attachOptions.VolumeMode = pv.Spec.PersistentVolumeSource.FlexVolume.Options[VolumeModeKey]
+	}
 	if attachOptions.MountUser == "" {
 		attachOptions.MountUser = "admin"
 	}
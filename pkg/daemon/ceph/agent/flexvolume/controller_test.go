@@ -740,6 +740,7 @@ func TestGetAttachInfoFromMountDir(t *testing.T) {
 						PoolKey:          "pool123",
 						ImageKey:         "pvc-123",
 						DataBlockPoolKey: "",
+						VolumeModeKey:    "Block",
 					},
 				},
 			},
@@ -795,6 +796,7 @@ func TestGetAttachInfoFromMountDir(t *testing.T) {
 	assert.Equal(t, "pool123", opts.BlockPool)
 	assert.Equal(t, "storageClass1", opts.StorageClass)
 	assert.Equal(t, "testCluster", opts.ClusterNamespace)
+	assert.True(t, opts.IsBlockMode())
 }
 
 func TestParseClusterNamespace(t *testing.T) {
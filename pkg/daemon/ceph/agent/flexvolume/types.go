@@ -16,11 +16,19 @@ limitations under the License.
 
 package flexvolume
 
+import "strings"
+
 const (
 	// ReadOnly mount mode
 	ReadOnly = "ro"
 	// ReadWrite mount mode
 	ReadWrite = "rw"
+	// VolumeModeBlock is the volume mode exposing the mapped rbd device in the pod volume, without a filesystem
+	VolumeModeBlock = "Block"
+	// VolumeModeFilesystem is the default volume mode, mounting the filesystem of the rbd device in the pod volume
+	VolumeModeFilesystem = "Filesystem"
+	// BlockDeviceFile is the file of the pod volume the rbd device is bound to in the block volume mode
+	BlockDeviceFile = "block"
 )
 
 // VolumeManager handles flexvolume plugin storage operations
@@ -59,6 +67,12 @@ type AttachOptions struct {
 	Pod              string `json:"kubernetes.io/pod.name"`
 	PodID            string `json:"kubernetes.io/pod.uid"`
 	PodNamespace     string `json:"kubernetes.io/pod.namespace"`
+	VolumeMode       string `json:"volumeMode"`
+}
+
+// IsBlockMode returns whether the mapped rbd device is exposed in the pod volume instead of its filesystem
+func (o *AttachOptions) IsBlockMode() bool {
+	return strings.EqualFold(o.VolumeMode, VolumeModeBlock)
 }
 
 type ExpandOptions struct {
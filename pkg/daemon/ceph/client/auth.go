@@ -104,6 +104,30 @@ func AuthGetCaps(context *clusterd.Context, clusterInfo *ClusterInfo, name strin
 	return caps, err
 }
 
+// AuthListKeys returns the keys of all the users, by user name.
+func AuthListKeys(context *clusterd.Context, clusterInfo *ClusterInfo) (map[string]string, error) {
+	args := []string{"auth", "ls"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the auth keys")
+	}
+
+	var resp struct {
+		AuthDump []struct {
+			Entity string `json:"entity"`
+			Key    string `json:"key"`
+		} `json:"auth_dump"`
+	}
+	if err := json.Unmarshal(buf, &resp); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal auth ls response")
+	}
+	keys := map[string]string{}
+	for _, auth := range resp.AuthDump {
+		keys[auth.Entity] = auth.Key
+	}
+	return keys, nil
+}
+
 // AuthDelete will delete the given user.
 func AuthDelete(context *clusterd.Context, clusterInfo *ClusterInfo, name string) error {
 	logger.Infof("deleting ceph auth %q", name)
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// BlocklistEntry is an entry of the osd blocklist, a client address fenced from the cluster until the entry expires
+type BlocklistEntry struct {
+	Addr  string `json:"addr"`
+	Until string `json:"until"`
+}
+
+// IP returns the ip of the blocklisted address, without the port and the nonce
+func (e BlocklistEntry) IP() string {
+	addr := e.Addr
+	if i := strings.LastIndex(addr, "/"); i >= 0 {
+		addr = addr[:i]
+	}
+	if i := strings.LastIndex(addr, ":"); i >= 0 {
+		addr = addr[:i]
+	}
+	return strings.Trim(addr, "[]")
+}
+
+// blocklistCommand returns the name of the blocklist command, which was named "blacklist" before Pacific
+func blocklistCommand(clusterInfo *ClusterInfo) string {
+	if clusterInfo.CephVersion.IsAtLeastPacific() {
+		return "blocklist"
+	}
+	return "blacklist"
+}
+
+// ListOSDBlocklist returns the entries of the osd blocklist
+func ListOSDBlocklist(context *clusterd.Context, clusterInfo *ClusterInfo) ([]BlocklistEntry, error) {
+	args := []string{"osd", blocklistCommand(clusterInfo), "ls"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the osd blocklist")
+	}
+
+	var entries []BlocklistEntry
+	if err := json.Unmarshal(buf, &entries); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal osd blocklist response. %s", string(buf))
+	}
+	return entries, nil
+}
+
+// AddOSDBlocklist adds a client address to the osd blocklist. The entry expires after the given duration, or after
+// the default expiration of ceph if the duration is zero.
+func AddOSDBlocklist(context *clusterd.Context, clusterInfo *ClusterInfo, addr string, expire time.Duration) error {
+	args := []string{"osd", blocklistCommand(clusterInfo), "add", addr}
+	if expire > 0 {
+		args = append(args, fmt.Sprintf("%d", int(expire.Seconds())))
+	}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to add %q to the osd blocklist", addr)
+	}
+	logger.Infof("added %q to the osd blocklist", addr)
+	return nil
+}
+
+// RemoveOSDBlocklist removes a client address from the osd blocklist
+func RemoveOSDBlocklist(context *clusterd.Context, clusterInfo *ClusterInfo, addr string) error {
+	args := []string{"osd", blocklistCommand(clusterInfo), "rm", addr}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to remove %q from the osd blocklist", addr)
+	}
+	logger.Infof("removed %q from the osd blocklist", addr)
+	return nil
+}
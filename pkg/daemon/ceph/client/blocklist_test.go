@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklistEntryIP(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", BlocklistEntry{Addr: "10.0.0.1:0/3710147553"}.IP())
+	assert.Equal(t, "10.0.0.1", BlocklistEntry{Addr: "10.0.0.1:6800/12"}.IP())
+	assert.Equal(t, "fd00::1", BlocklistEntry{Addr: "[fd00::1]:0/12"}.IP())
+}
+
+func TestOSDBlocklist(t *testing.T) {
+	var commands [][]string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			commands = append(commands, args)
+			if args[0] == "osd" && args[2] == "ls" {
+				return `[{"addr":"10.0.0.1:0/3710147553","until":"2021-10-15T10:00:00.254017+0000"}]`, nil
+			}
+			if args[0] == "osd" && (args[2] == "add" || args[2] == "rm") {
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+	clusterInfo.CephVersion = cephver.Pacific
+
+	entries, err := ListOSDBlocklist(context, clusterInfo)
+	require.NoError(t, err)
+	assert.Equal(t, []BlocklistEntry{{Addr: "10.0.0.1:0/3710147553", Until: "2021-10-15T10:00:00.254017+0000"}}, entries)
+	assert.Equal(t, "blocklist", commands[0][1])
+
+	require.NoError(t, AddOSDBlocklist(context, clusterInfo, "10.0.0.2:0/1", time.Hour))
+	assert.Equal(t, []string{"osd", "blocklist", "add", "10.0.0.2:0/1", "3600"}, commands[1][:5])
+
+	require.NoError(t, RemoveOSDBlocklist(context, clusterInfo, "10.0.0.1:0/3710147553"))
+	assert.Equal(t, []string{"osd", "blocklist", "rm", "10.0.0.1:0/3710147553"}, commands[2][:4])
+
+	// the command is named blacklist before pacific
+	clusterInfo.CephVersion = cephver.Octopus
+	_, err = ListOSDBlocklist(context, clusterInfo)
+	require.NoError(t, err)
+	assert.Equal(t, "blacklist", commands[3][1])
+}
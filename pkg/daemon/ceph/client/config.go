@@ -262,7 +262,12 @@ func PopulateMonHostMembers(monitors map[string]*MonInfo) ([]string, []string) {
 		msgr2Endpoint := net.JoinHostPort(monIP, monPorts[0])
 		msgr1Endpoint := net.JoinHostPort(monIP, monPorts[1])
 
-		monHosts[i] = "[v2:" + msgr2Endpoint + ",v1:" + msgr1Endpoint + "]"
+		if currentMonPort == Msgr2port {
+			// the mon only accepts the msgr2 protocol
+			monHosts[i] = "[v2:" + msgr2Endpoint + "]"
+		} else {
+			monHosts[i] = "[v2:" + msgr2Endpoint + ",v1:" + msgr1Endpoint + "]"
+		}
 		i++
 	}
 
@@ -173,3 +173,17 @@ func verifyConfigValue(t *testing.T, actualConf *ini.File, section, key, expecte
 	actualVal := k.Value()
 	assert.Equal(t, expectedVal, actualVal)
 }
+
+func TestPopulateMonHostMembers(t *testing.T) {
+	monitors := map[string]*MonInfo{
+		"a": {Name: "a", Endpoint: "10.0.0.1:6789"},
+	}
+	members, hosts := PopulateMonHostMembers(monitors)
+	assert.Equal(t, []string{"a"}, members)
+	assert.Equal(t, []string{"[v2:10.0.0.1:3300,v1:10.0.0.1:6789]"}, hosts)
+
+	// a mon only accepting msgr2
+	monitors["a"].Endpoint = "10.0.0.1:3300"
+	_, hosts = PopulateMonHostMembers(monitors)
+	assert.Equal(t, []string{"[v2:10.0.0.1:3300]"}, hosts)
+}
@@ -53,6 +53,7 @@ type CrashList struct {
 	IoErrorOffset    int      `json:"io_error_offset,omitempty"`
 	IoErrorLength    int      `json:"iio_error_length,omitempty"`
 	Backtrace        []string `json:"backtrace,omitempty"`
+	MgrModule        string   `json:"mgr_module,omitempty"`
 }
 
 // GetCrashList gets the list of Crashes.
@@ -75,7 +75,7 @@ type ruleSpec struct {
 
 type stepSpec struct {
 	Operation string `json:"op"`
-	Number    uint   `json:"num"`
+	Number    int    `json:"num"`
 	Item      int    `json:"item"`
 	ItemName  string `json:"item_name"`
 	Type      string `json:"type"`
@@ -150,6 +150,15 @@ func GetCrushHostName(context *clusterd.Context, clusterInfo *ClusterInfo, osdID
 	return result.Location["host"], nil
 }
 
+// RemoveCrushBucket removes a bucket from the crush map. It fails if the bucket is not empty.
+func RemoveCrushBucket(context *clusterd.Context, clusterInfo *ClusterInfo, name string) error {
+	args := []string{"osd", "crush", "rm", name}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to remove crush bucket %q", name)
+	}
+	return nil
+}
+
 // NormalizeCrushName replaces . with -
 func NormalizeCrushName(name string) string {
 	return strings.Replace(name, ".", "-", -1)
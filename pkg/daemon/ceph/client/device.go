@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// lifeExpectancyFormat is the format of the life expectancy of the devices reported by the devicehealth mgr module
+const lifeExpectancyFormat = "2006-01-02 15:04:05"
+
+// DeviceInfo is a device of the daemons tracked by the devicehealth mgr module
+type DeviceInfo struct {
+	DevID    string           `json:"devid"`
+	Location []DeviceLocation `json:"location"`
+	Daemons  []string         `json:"daemons"`
+	// the life expectancy is only set once a prediction was made from the SMART data of the device
+	LifeExpectancyMin string `json:"life_expectancy_min,omitempty"`
+	LifeExpectancyMax string `json:"life_expectancy_max,omitempty"`
+}
+
+// DeviceLocation is the location of a device on a host
+type DeviceLocation struct {
+	Host string `json:"host"`
+	Dev  string `json:"dev"`
+	Path string `json:"path"`
+}
+
+// LifeExpectancy returns the earliest and the latest time the device is predicted to fail, and false if there is
+// no prediction for the device
+func (d DeviceInfo) LifeExpectancy() (time.Time, time.Time, bool) {
+	min, err := time.Parse(lifeExpectancyFormat, d.LifeExpectancyMin)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	max, err := time.Parse(lifeExpectancyFormat, d.LifeExpectancyMax)
+	if err != nil {
+		// the prediction may be open ended
+		max = min
+	}
+	return min, max, true
+}
+
+// ListDevices returns the devices of the daemons tracked by the devicehealth mgr module
+func ListDevices(context *clusterd.Context, clusterInfo *ClusterInfo) ([]DeviceInfo, error) {
+	args := []string{"device", "ls"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the devices")
+	}
+
+	var devices []DeviceInfo
+	if err := json.Unmarshal(buf, &devices); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal device list response. %s", string(buf))
+	}
+	return devices, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// featureMsgAddr2 is the feature bit of the daemons and clients supporting the msgr2 protocol
+const featureMsgAddr2 = uint64(1) << 59
+
+// ClusterFeatures is the features of the daemons and clients connected to the mons
+type ClusterFeatures struct {
+	Client []FeatureGroup `json:"client"`
+}
+
+// FeatureGroup is a group of connections sharing the same features
+type FeatureGroup struct {
+	Features string `json:"features"`
+	Release  string `json:"release"`
+	Num      int    `json:"num"`
+}
+
+// SupportsMsgr2 returns whether the connections of the group support the msgr2 protocol
+func (g FeatureGroup) SupportsMsgr2() (bool, error) {
+	features, err := strconv.ParseUint(strings.TrimPrefix(g.Features, "0x"), 16, 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse features %q", g.Features)
+	}
+	return features&featureMsgAddr2 != 0, nil
+}
+
+// GetFeatures calls the features mon_command
+func GetFeatures(context *clusterd.Context, clusterInfo *ClusterInfo) (ClusterFeatures, error) {
+	args := []string{"features"}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	buf, err := cmd.Run()
+	if err != nil {
+		return ClusterFeatures{}, errors.Wrap(err, "failed to get the cluster features")
+	}
+
+	var resp ClusterFeatures
+	err = json.Unmarshal(buf, &resp)
+	if err != nil {
+		return ClusterFeatures{}, errors.Wrapf(err, "unmarshal failed. raw buffer response: %s", buf)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFeatures(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "features" {
+			return `{"mon":[{"features":"0x3f01cfb9fffdffff","release":"luminous","num":3}],
+			"client":[{"features":"0x27018fb86aa42ada","release":"luminous","num":2},
+			{"features":"0x3f01cfb9fffdffff","release":"luminous","num":5}]}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+
+	features, err := GetFeatures(context, clusterInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(features.Client))
+	assert.Equal(t, 2, features.Client[0].Num)
+
+	// the kernel client without msgr2
+	supported, err := features.Client[0].SupportsMsgr2()
+	assert.NoError(t, err)
+	assert.False(t, supported)
+
+	// the userspace clients with msgr2
+	supported, err = features.Client[1].SupportsMsgr2()
+	assert.NoError(t, err)
+	assert.True(t, supported)
+
+	_, err = FeatureGroup{Features: "foo"}.SupportsMsgr2()
+	assert.Error(t, err)
+}
@@ -183,6 +183,46 @@ func SetNumMDSRanks(context *clusterd.Context, clusterInfo *ClusterInfo, fsName
 	return nil
 }
 
+// SetFilesystemSetting sets a setting of the mds map of a filesystem, such as session_timeout
+func SetFilesystemSetting(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, setting, value string) error {
+	args := []string{"fs", "set", fsName, setting, value}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to set %q to %q on filesystem %q", setting, value, fsName)
+	}
+	return nil
+}
+
+// CreateCephFSSubVolumeGroup creates a subvolume group in a filesystem, nothing is done if the group exists
+func CreateCephFSSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string) error {
+	args := []string{"fs", "subvolumegroup", "create", fsName, groupName}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to create subvolume group %q in filesystem %q", groupName, fsName)
+	}
+	logger.Infof("successfully created subvolume group %q in filesystem %q", groupName, fsName)
+	return nil
+}
+
+// PinCephFSSubVolumeGroup sets the pinning policy of a subvolume group. The pin type is "export",
+// "distributed" or "random".
+func PinCephFSSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName, pinType, pinSetting string) error {
+	args := []string{"fs", "subvolumegroup", "pin", fsName, groupName, pinType, pinSetting}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to set %s pin %q of subvolume group %q in filesystem %q", pinType, pinSetting, groupName, fsName)
+	}
+	logger.Infof("successfully set %s pin %q of subvolume group %q in filesystem %q", pinType, pinSetting, groupName, fsName)
+	return nil
+}
+
+// DeleteCephFSSubVolumeGroup deletes a subvolume group, the group must not have any subvolume
+func DeleteCephFSSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string) error {
+	args := []string{"fs", "subvolumegroup", "rm", fsName, groupName}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to delete subvolume group %q in filesystem %q", groupName, fsName)
+	}
+	logger.Infof("successfully deleted subvolume group %q in filesystem %q", groupName, fsName)
+	return nil
+}
+
 // FailAllStandbyReplayMDS: fail all mds in up:standby-replay state
 func FailAllStandbyReplayMDS(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) error {
 	fs, err := GetFilesystem(context, clusterInfo, fsName)
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// MDSSession is a representation of a client session returned by 'ceph tell mds.<fs>:<rank> session ls'
+type MDSSession struct {
+	ID      int64  `json:"id"`
+	State   string `json:"state"`
+	NumCaps int    `json:"num_caps"`
+	Entity  struct {
+		Addr struct {
+			Addr string `json:"addr"`
+		} `json:"addr"`
+	} `json:"entity"`
+	ClientMetadata struct {
+		Hostname string `json:"hostname"`
+		EntityID string `json:"entity_id"`
+		Root     string `json:"root"`
+	} `json:"client_metadata"`
+}
+
+// IP returns the ip of the address of the client, without the port
+func (s MDSSession) IP() string {
+	host, _, err := net.SplitHostPort(s.Entity.Addr.Addr)
+	if err != nil {
+		return s.Entity.Addr.Addr
+	}
+	return host
+}
+
+// mdsRankTarget returns the target of the commands sent to the mds of a rank of the filesystem
+func mdsRankTarget(fsName string, rank int) string {
+	return fmt.Sprintf("mds.%s:%d", fsName, rank)
+}
+
+// ListMDSSessions returns the sessions of the clients with the mds of rank 0 of the filesystem. All the clients of
+// the filesystem open a session with rank 0.
+func ListMDSSessions(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) ([]MDSSession, error) {
+	args := []string{"tell", mdsRankTarget(fsName, 0), "session", "ls"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the client sessions of filesystem %q", fsName)
+	}
+
+	var sessions []MDSSession
+	if err := json.Unmarshal(buf, &sessions); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal client sessions response. %s", string(buf))
+	}
+	return sessions, nil
+}
+
+// EvictMDSSession evicts a client from the filesystem. The client is also added to the osd blocklist, so it is
+// evicted from all the ranks.
+func EvictMDSSession(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string, id int64) error {
+	args := []string{"tell", mdsRankTarget(fsName, 0), "client", "evict", fmt.Sprintf("id=%d", id)}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to evict client %d of filesystem %q", id, fsName)
+	}
+	logger.Infof("evicted client %d of filesystem %q", id, fsName)
+	return nil
+}
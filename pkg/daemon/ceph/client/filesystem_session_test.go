@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMDSSessions(t *testing.T) {
+	var commands [][]string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			commands = append(commands, args)
+			if args[0] == "tell" && args[2] == "session" {
+				return `[{"id":4305,"entity":{"name":{"type":"client","num":4305},"addr":{"type":"v1","addr":"10.0.0.5:0","nonce":1234}},
+					"state":"stale","num_caps":12,"inst":"client.4305 v1:10.0.0.5:0/1234",
+					"client_metadata":{"client_features":{"feature_bits":"0x0000000000007bff"},"entity_id":"csi-cephfs-node","hostname":"node1","root":"/volumes/csi"}},
+					{"id":4306,"entity":{"addr":{"type":"any","addr":"[fd00::5]:0","nonce":1}},"state":"open","num_caps":1,"client_metadata":{}}]`, nil
+			}
+			if args[0] == "tell" && args[2] == "client" {
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+
+	sessions, err := ListMDSSessions(context, clusterInfo, "myfs")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, []string{"tell", "mds.myfs:0", "session", "ls"}, commands[0][:4])
+	assert.Equal(t, int64(4305), sessions[0].ID)
+	assert.Equal(t, "stale", sessions[0].State)
+	assert.Equal(t, 12, sessions[0].NumCaps)
+	assert.Equal(t, "node1", sessions[0].ClientMetadata.Hostname)
+	assert.Equal(t, "10.0.0.5", sessions[0].IP())
+	assert.Equal(t, "fd00::5", sessions[1].IP())
+
+	require.NoError(t, EvictMDSSession(context, clusterInfo, "myfs", 4305))
+	assert.Equal(t, []string{"tell", "mds.myfs:0", "client", "evict", "id=4305"}, commands[1][:5])
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"syscall"
+	"time"
 
 	"strconv"
 
@@ -68,6 +69,43 @@ func ListImages(context *clusterd.Context, clusterInfo *ClusterInfo, poolName st
 	return images, nil
 }
 
+// imageCreateTimeFormat is the format of the creation time of the images in the output of "rbd info"
+const imageCreateTimeFormat = "Mon Jan _2 15:04:05 2006"
+
+// GetImageCreateTime returns the creation time of an image
+func GetImageCreateTime(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName string) (time.Time, error) {
+	args := []string{"info", getImageSpec(name, poolName)}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to get info of image %q in pool %q", name, poolName)
+	}
+
+	var info struct {
+		CreateTimestamp string `json:"create_timestamp"`
+	}
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to unmarshal image info response. %s", string(buf))
+	}
+	createTime, err := time.Parse(imageCreateTimeFormat, info.CreateTimestamp)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse creation time %q of image %q", info.CreateTimestamp, name)
+	}
+	return createTime, nil
+}
+
+// TrashImage moves an image to the trash of its pool, from where it can be restored
+func TrashImage(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName string) error {
+	logger.Infof("moving rbd image %q of pool %q to the trash", name, poolName)
+	args := []string{"trash", "mv", getImageSpec(name, poolName)}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to move image %q of pool %q to the trash. %s", name, poolName, string(buf))
+	}
+	return nil
+}
+
 // CreateImage creates a block storage image.
 // If dataPoolName is not empty, the image will use poolName as the metadata pool and the dataPoolname for data.
 // If size is zero an empty image will be created. Otherwise, an image will be
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// ImageStatus is the status of an image from "rbd status"
+type ImageStatus struct {
+	Watchers []ImageWatcher `json:"watchers"`
+	// Migration is the live migration of the image, nil if the image is not migrating
+	Migration *ImageMigration `json:"migration,omitempty"`
+}
+
+// ImageWatcher is a client of an image
+type ImageWatcher struct {
+	Address string `json:"address"`
+}
+
+// ImageMigration is the state of the live migration of an image
+type ImageMigration struct {
+	SourcePoolName string `json:"source_pool_name"`
+	DestPoolName   string `json:"dest_pool_name"`
+	// State is "prepared", "executing" or "executed"
+	State string `json:"state"`
+}
+
+const (
+	// ImageMigrationStatePrepared is the state of a migration linking the image to the new image
+	ImageMigrationStatePrepared = "prepared"
+	// ImageMigrationStateExecuting is the state of a migration copying the data of the image
+	ImageMigrationStateExecuting = "executing"
+	// ImageMigrationStateExecuted is the state of a migration waiting to be committed
+	ImageMigrationStateExecuted = "executed"
+)
+
+// GetImageStatus returns the watchers and the live migration of an image
+func GetImageStatus(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName string) (*ImageStatus, error) {
+	args := []string{"status", getImageSpec(name, poolName)}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get status of image %q in pool %q. %s", name, poolName, string(buf))
+	}
+
+	var status ImageStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal image status response. %s", string(buf))
+	}
+	return &status, nil
+}
+
+// GetImageDataPool returns the data pool of an image, or an empty string if the data of the image is in its pool
+func GetImageDataPool(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName string) (string, error) {
+	args := []string{"info", getImageSpec(name, poolName)}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get info of image %q in pool %q. %s", name, poolName, string(buf))
+	}
+
+	var info struct {
+		DataPool string `json:"data_pool"`
+	}
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal image info response. %s", string(buf))
+	}
+	return info.DataPool, nil
+}
+
+// PrepareImageMigration links an image to a new image with the same name and its data in the data pool. The image
+// must not be in use, its clients can open it again once the migration is prepared.
+func PrepareImageMigration(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName, dataPoolName string) error {
+	logger.Infof("preparing the migration of rbd image %q of pool %q to data pool %q", name, poolName, dataPoolName)
+	args := []string{"migration", "prepare", getImageSpec(name, poolName), fmt.Sprintf("--data-pool=%s", dataPoolName)}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to prepare the migration of image %q of pool %q. %s", name, poolName, string(buf))
+	}
+	return nil
+}
+
+// ExecuteImageMigration copies the data of a prepared image to the new image, which may take a while
+func ExecuteImageMigration(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName string) error {
+	logger.Infof("executing the migration of rbd image %q of pool %q", name, poolName)
+	args := []string{"migration", "execute", getImageSpec(name, poolName), "--no-progress"}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to execute the migration of image %q of pool %q. %s", name, poolName, string(buf))
+	}
+	return nil
+}
+
+// CommitImageMigration commits the migration of an image, which removes the source image
+func CommitImageMigration(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName string) error {
+	logger.Infof("committing the migration of rbd image %q of pool %q", name, poolName)
+	args := []string{"migration", "commit", getImageSpec(name, poolName), "--no-progress"}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit the migration of image %q of pool %q. %s", name, poolName, string(buf))
+	}
+	return nil
+}
+
+// AbortImageMigration aborts the migration of an image which is not committed, which restores the source image
+func AbortImageMigration(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName string) error {
+	logger.Infof("aborting the migration of rbd image %q of pool %q", name, poolName)
+	args := []string{"migration", "abort", getImageSpec(name, poolName), "--no-progress"}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to abort the migration of image %q of pool %q. %s", name, poolName, string(buf))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetImageStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if command == "rbd" && args[0] == "status" {
+			assert.Equal(t, "pool1/image1", args[1])
+			return `{"watchers":[{"address":"10.0.0.1:0/1234","client":4567,"cookie":1}],` +
+				`"migration":{"source_pool_name":"pool1","source_pool_namespace":"","source_image_name":"image1",` +
+				`"dest_pool_name":"pool1","dest_pool_namespace":"","dest_image_name":"image1","state":"executed","state_description":""}}`, nil
+		}
+		return "", errors.Errorf("unexpected command %q %q", command, args)
+	}
+	status, err := GetImageStatus(context, clusterInfo, "image1", "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, []ImageWatcher{{Address: "10.0.0.1:0/1234"}}, status.Watchers)
+	assert.Equal(t, &ImageMigration{SourcePoolName: "pool1", DestPoolName: "pool1", State: ImageMigrationStateExecuted}, status.Migration)
+
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return `{"watchers":[]}`, nil
+	}
+	status, err = GetImageStatus(context, clusterInfo, "image1", "pool1")
+	assert.NoError(t, err)
+	assert.Empty(t, status.Watchers)
+	assert.Nil(t, status.Migration)
+}
+
+func TestPrepareImageMigration(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+
+	prepared := false
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if command == "rbd" && args[0] == "migration" && args[1] == "prepare" {
+			assert.Equal(t, "pool1/image1", args[2])
+			assert.Equal(t, "--data-pool=ec-pool", args[3])
+			prepared = true
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected command %q %q", command, args)
+	}
+	assert.NoError(t, PrepareImageMigration(context, clusterInfo, "image1", "pool1", "ec-pool"))
+	assert.True(t, prepared)
+
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "rbd: image is in use", errors.New("failed")
+	}
+	err := PrepareImageMigration(context, clusterInfo, "image1", "pool1", "ec-pool")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rbd: image is in use")
+}
@@ -18,6 +18,8 @@ package client
 
 import (
 	"encoding/json"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,8 +28,17 @@ import (
 
 var (
 	moduleEnableWaitTime = 5 * time.Second
+	balancerScoreRegexp  = regexp.MustCompile(`score ([0-9.eE+-]+)`)
 )
 
+// BalancerStatus is the status of the balancer module as reported by "ceph balancer status"
+type BalancerStatus struct {
+	Active              bool   `json:"active"`
+	Mode                string `json:"mode"`
+	LastOptimizeStarted string `json:"last_optimize_started"`
+	OptimizeResult      string `json:"optimize_result"`
+}
+
 func CephMgrMap(context *clusterd.Context, clusterInfo *ClusterInfo) (*MgrMap, error) {
 	args := []string{"mgr", "dump"}
 	buf, err := NewCephCommand(context, clusterInfo, args).Run()
@@ -184,3 +195,48 @@ func ConfigureBalancerModule(context *clusterd.Context, clusterInfo *ClusterInfo
 
 	return nil
 }
+
+// SetBalancerActive turns the balancer module on or off
+func SetBalancerActive(context *clusterd.Context, clusterInfo *ClusterInfo, active bool) error {
+	action := "off"
+	if active {
+		action = "on"
+	}
+	return enableDisableBalancerModule(context, clusterInfo, action)
+}
+
+// GetBalancerStatus returns the status of the balancer module
+func GetBalancerStatus(context *clusterd.Context, clusterInfo *ClusterInfo) (*BalancerStatus, error) {
+	args := []string{"balancer", "status"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get balancer status")
+	}
+
+	var status BalancerStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal balancer status")
+	}
+	return &status, nil
+}
+
+// GetBalancerScore returns the score of the data distribution of the cluster evaluated by the balancer module,
+// lower is better
+func GetBalancerScore(context *clusterd.Context, clusterInfo *ClusterInfo) (float64, error) {
+	args := []string{"balancer", "eval"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to evaluate the balancer score")
+	}
+
+	// the output is "current cluster score 0.012345 (lower is better)"
+	match := balancerScoreRegexp.FindStringSubmatch(string(buf))
+	if match == nil {
+		return 0, errors.Errorf("failed to find the score in the balancer evaluation %q", string(buf))
+	}
+	score, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse the balancer score %q", match[1])
+	}
+	return score, nil
+}
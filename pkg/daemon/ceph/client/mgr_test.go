@@ -143,3 +143,39 @@ func TestSetBalancerMode(t *testing.T) {
 	err := setBalancerMode(&clusterd.Context{Executor: executor}, AdminClusterInfo("mycluster"), "upmap")
 	assert.NoError(t, err)
 }
+
+func TestGetBalancerStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		switch {
+		case args[0] == "balancer" && args[1] == "status":
+			return `{"active":true,"last_optimize_duration":"0:00:00.001","last_optimize_started":"Thu Oct 14 02:00:03 2021","mode":"upmap","optimize_result":"Unable to find further optimization","plans":[]}`, nil
+
+		case args[0] == "balancer" && args[1] == "eval":
+			return "current cluster score 0.012345 (lower is better)\n", nil
+		}
+
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+	status, err := GetBalancerStatus(context, clusterInfo)
+	assert.NoError(t, err)
+	assert.True(t, status.Active)
+	assert.Equal(t, "upmap", status.Mode)
+	assert.Equal(t, "Thu Oct 14 02:00:03 2021", status.LastOptimizeStarted)
+	assert.Equal(t, "Unable to find further optimization", status.OptimizeResult)
+
+	score, err := GetBalancerScore(context, clusterInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.012345, score)
+
+	// the score must be found in the evaluation
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "Error: no pools", nil
+	}
+	_, err = GetBalancerScore(context, clusterInfo)
+	assert.Error(t, err)
+}
@@ -72,6 +72,18 @@ type MonDumpEntry struct {
 	CrushLocation string `json:"crush_location"`
 }
 
+// MonTimeSyncStatus represents the response from a time-sync-status mon_command
+type MonTimeSyncStatus struct {
+	TimeSkewStatus map[string]MonTimeSkew `json:"time_skew_status"`
+}
+
+// MonTimeSkew represents the clock skew of a single mon relative to the leader
+type MonTimeSkew struct {
+	Skew    float64 `json:"skew"`
+	Latency float64 `json:"latency"`
+	Health  string  `json:"health"`
+}
+
 // GetMonQuorumStatus calls quorum_status mon_command
 func GetMonQuorumStatus(context *clusterd.Context, clusterInfo *ClusterInfo) (MonStatusResponse, error) {
 	args := []string{"quorum_status"}
@@ -90,6 +102,35 @@ func GetMonQuorumStatus(context *clusterd.Context, clusterInfo *ClusterInfo) (Mo
 	return resp, nil
 }
 
+// GetMonTimeSyncStatus calls time-sync-status mon_command
+func GetMonTimeSyncStatus(context *clusterd.Context, clusterInfo *ClusterInfo) (MonTimeSyncStatus, error) {
+	args := []string{"time-sync-status"}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	buf, err := cmd.Run()
+	if err != nil {
+		return MonTimeSyncStatus{}, errors.Wrap(err, "mon time sync status failed")
+	}
+
+	var resp MonTimeSyncStatus
+	err = json.Unmarshal(buf, &resp)
+	if err != nil {
+		return MonTimeSyncStatus{}, errors.Wrapf(err, "unmarshal failed. raw buffer response: %s", buf)
+	}
+
+	return resp, nil
+}
+
+// CompactMonStore compacts the store of the mon, the command returns once the compaction is done
+func CompactMonStore(context *clusterd.Context, clusterInfo *ClusterInfo, monName string) error {
+	args := []string{"tell", "mon." + monName, "compact"}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	buf, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to compact the store of mon %q. %s", monName, string(buf))
+	}
+	return nil
+}
+
 // GetMonDump calls mon dump command
 func GetMonDump(context *clusterd.Context, clusterInfo *ClusterInfo) (MonDump, error) {
 	args := []string{"mon", "dump"}
@@ -137,3 +137,24 @@ func TestMonDump(t *testing.T) {
 	assert.Equal(t, 3, len(dump.Mons))
 	assert.Equal(t, 3, len(dump.Quorum))
 }
+
+func TestMonTimeSyncStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "time-sync-status" {
+			return `{"time_skew_status":{"a":{"skew":0,"latency":0,"health":"HEALTH_OK"},"b":{"skew":0.062,"latency":0.001,"health":"HEALTH_WARN"}},
+		"timechecks":{"epoch":6,"round":32,"round_status":"finished"}}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+
+	status, err := GetMonTimeSyncStatus(context, clusterInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(status.TimeSkewStatus))
+	assert.Equal(t, 0.0, status.TimeSkewStatus["a"].Skew)
+	assert.Equal(t, 0.062, status.TimeSkewStatus["b"].Skew)
+	assert.Equal(t, "HEALTH_WARN", status.TimeSkewStatus["b"].Health)
+}
@@ -49,6 +49,16 @@ type OSDNodeUsage struct {
 	Pgs         json.Number `json:"pgs"`
 }
 
+// OSDMetadata is the metadata reported by an OSD, with the kernel names of its devices
+type OSDMetadata struct {
+	ID          int    `json:"id"`
+	Hostname    string `json:"hostname"`
+	Devices     string `json:"devices"`
+	DevicePaths string `json:"device_paths"`
+	ObjectStore string `json:"osd_objectstore"`
+	CephVersion string `json:"ceph_version_short"`
+}
+
 type OSDPerfStats struct {
 	PerfInfo []struct {
 		ID    json.Number `json:"id"`
@@ -61,12 +71,16 @@ type OSDPerfStats struct {
 
 type OSDDump struct {
 	OSDs []struct {
-		OSD json.Number `json:"osd"`
-		Up  json.Number `json:"up"`
-		In  json.Number `json:"in"`
+		OSD   json.Number `json:"osd"`
+		Up    json.Number `json:"up"`
+		In    json.Number `json:"in"`
+		State []string    `json:"state"`
+		// UpFrom is the epoch the OSD last came up, which changes each time the OSD comes up again
+		UpFrom json.Number `json:"up_from"`
 	} `json:"osds"`
 	Flags          string              `json:"flags"`
 	CrushNodeFlags map[string][]string `json:"crush_node_flags"`
+	NearFullRatio  float64             `json:"nearfull_ratio"`
 }
 
 // IsFlagSet checks if an OSD flag is set
@@ -157,6 +171,7 @@ type OsdTree struct {
 		Status          string  `json:"status,omitempty"`
 		Reweight        float64 `json:"reweight,omitempty"`
 		PrimaryAffinity float64 `json:"primary_affinity,omitempty"`
+		DeviceClass     string  `json:"device_class,omitempty"`
 	} `json:"nodes"`
 	Stray []struct {
 		ID              int     `json:"id"`
@@ -216,6 +231,22 @@ func GetOSDUsage(context *clusterd.Context, clusterInfo *ClusterInfo) (*OSDUsage
 	return &osdUsage, nil
 }
 
+// GetOSDMetadata returns the metadata reported by all the OSDs that were up at least once
+func GetOSDMetadata(context *clusterd.Context, clusterInfo *ClusterInfo) ([]OSDMetadata, error) {
+	args := []string{"osd", "metadata"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get osd metadata")
+	}
+
+	var metadata []OSDMetadata
+	if err := json.Unmarshal(buf, &metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal osd metadata response")
+	}
+
+	return metadata, nil
+}
+
 func GetOSDPerfStats(context *clusterd.Context, clusterInfo *ClusterInfo) (*OSDPerfStats, error) {
 	args := []string{"osd", "perf"}
 	buf, err := NewCephCommand(context, clusterInfo, args).Run()
@@ -247,12 +278,69 @@ func GetOSDDump(context *clusterd.Context, clusterInfo *ClusterInfo) (*OSDDump,
 	return &osdDump, nil
 }
 
+// IsDestroyed returns whether the given OSD is destroyed, in which case a new OSD can reuse its ID
+func (dump *OSDDump) IsDestroyed(id int) bool {
+	for _, d := range dump.OSDs {
+		if d.OSD.String() != strconv.Itoa(id) {
+			continue
+		}
+		for _, state := range d.State {
+			if state == "destroyed" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DestroyOSD marks the OSD down and destroys it. The ID of the OSD and its position in the crush map
+// are kept so a new OSD can replace it.
+func DestroyOSD(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) error {
+	args := []string{"osd", "down", fmt.Sprintf("osd.%d", osdID)}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to mark osd.%d down", osdID)
+	}
+
+	args = []string{"osd", "destroy", fmt.Sprintf("osd.%d", osdID), "--yes-i-really-mean-it"}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to destroy osd.%d", osdID)
+	}
+	return nil
+}
+
+// PurgeOSD removes the OSD from the crush map, deletes its auth key and removes it from the osd map
+func PurgeOSD(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) error {
+	args := []string{"osd", "purge", fmt.Sprintf("osd.%d", osdID), "--force", "--yes-i-really-mean-it"}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to purge osd.%d", osdID)
+	}
+	return nil
+}
+
+// SetRunningOSDConfig sets the option on the running OSD, with precedence over the options of the mon store
+func SetRunningOSDConfig(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int, option, value string) error {
+	args := []string{"tell", fmt.Sprintf("osd.%d", osdID), "config", "set", option, value}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to set option %q of running osd.%d", option, osdID)
+	}
+	return nil
+}
+
 func OSDOut(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) (string, error) {
 	args := []string{"osd", "out", strconv.Itoa(osdID)}
 	buf, err := NewCephCommand(context, clusterInfo, args).Run()
 	return string(buf), err
 }
 
+// OSDIn marks the OSD in
+func OSDIn(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) error {
+	args := []string{"osd", "in", strconv.Itoa(osdID)}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to mark osd.%d in", osdID)
+	}
+	return nil
+}
+
 func OsdSafeToDestroy(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) (bool, error) {
 	args := []string{"osd", "safe-to-destroy", strconv.Itoa(osdID)}
 	cmd := NewCephCommand(context, clusterInfo, args)
@@ -392,3 +480,14 @@ func SetPrimaryAffinity(context *clusterd.Context, clusterInfo *ClusterInfo, osd
 	logger.Infof("successfully applied osd.%d primary-affinity %q", osdID, affinity)
 	return nil
 }
+
+// CrushReweight sets the CRUSH weight of an OSD, in TiB
+func CrushReweight(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int, weight string) error {
+	logger.Infof("setting the crush weight of osd.%d to %s", osdID, weight)
+	args := []string{"osd", "crush", "reweight", fmt.Sprintf("osd.%d", osdID), weight}
+	_, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the crush weight of osd.%d to %s", osdID, weight)
+	}
+	return nil
+}
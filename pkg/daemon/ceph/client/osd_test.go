@@ -226,3 +226,31 @@ func TestOSDOkToStop(t *testing.T) {
 		assert.NotContains(t, seenArgs[3], "--max") // do not issue the "--max" flag below pacific
 	})
 }
+
+func TestDestroyOSD(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "dump":
+				return `{"osds":[{"osd":0,"up":1,"in":1,"state":["exists","up"]},{"osd":1,"up":0,"in":0,"state":["autoout","exists","destroyed"]}]}`, nil
+			case args[0] == "osd" && (args[1] == "down" || args[1] == "destroy"):
+				commands = append(commands, fmt.Sprint(args[:3]))
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := DestroyOSD(context, AdminClusterInfo("mycluster"), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[osd down osd.1]", "[osd destroy osd.1]"}, commands)
+
+	osdDump, err := GetOSDDump(context, AdminClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	assert.False(t, osdDump.IsDestroyed(0))
+	assert.True(t, osdDump.IsDestroyed(1))
+	assert.False(t, osdDump.IsDestroyed(2))
+}
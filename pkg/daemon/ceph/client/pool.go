@@ -62,15 +62,18 @@ type CephStoragePoolStats struct {
 		Name  string `json:"name"`
 		ID    int    `json:"id"`
 		Stats struct {
-			BytesUsed    float64 `json:"bytes_used"`
-			RawBytesUsed float64 `json:"raw_bytes_used"`
-			MaxAvail     float64 `json:"max_avail"`
-			Objects      float64 `json:"objects"`
-			DirtyObjects float64 `json:"dirty"`
-			ReadIO       float64 `json:"rd"`
-			ReadBytes    float64 `json:"rd_bytes"`
-			WriteIO      float64 `json:"wr"`
-			WriteBytes   float64 `json:"wr_bytes"`
+			BytesUsed          float64 `json:"bytes_used"`
+			RawBytesUsed       float64 `json:"raw_bytes_used"`
+			MaxAvail           float64 `json:"max_avail"`
+			Objects            float64 `json:"objects"`
+			DirtyObjects       float64 `json:"dirty"`
+			ReadIO             float64 `json:"rd"`
+			ReadBytes          float64 `json:"rd_bytes"`
+			WriteIO            float64 `json:"wr"`
+			WriteBytes         float64 `json:"wr_bytes"`
+			Stored             float64 `json:"stored"`
+			CompressBytesUsed  float64 `json:"compress_bytes_used"`
+			CompressUnderBytes float64 `json:"compress_under_bytes"`
 		} `json:"stats"`
 	} `json:"pools"`
 }
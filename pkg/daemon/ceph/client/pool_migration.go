@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// PoolRecoveryStats is the recovery progress of a pool from "ceph osd pool stats"
+type PoolRecoveryStats struct {
+	MisplacedObjects int64   `json:"misplaced_objects"`
+	MisplacedTotal   int64   `json:"misplaced_total"`
+	MisplacedRatio   float64 `json:"misplaced_ratio"`
+	DegradedObjects  int64   `json:"degraded_objects"`
+}
+
+// crushRulePlacement is the placement of the data of a simple replicated crush rule
+type crushRulePlacement struct {
+	root          string
+	deviceClass   string
+	failureDomain string
+}
+
+// GetPoolCrushRule returns the name of the crush rule of a pool
+func GetPoolCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (string, error) {
+	args := []string{"osd", "pool", "get", poolName, "crush_rule"}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the crush rule of pool %q. %s", poolName, string(output))
+	}
+
+	var result struct {
+		CrushRule string `json:"crush_rule"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal the crush rule of pool %q", poolName)
+	}
+	return result.CrushRule, nil
+}
+
+func getCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, ruleName string) (ruleSpec, error) {
+	args := []string{"osd", "crush", "rule", "dump", ruleName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return ruleSpec{}, errors.Wrapf(err, "failed to get crush rule %q. %s", ruleName, string(output))
+	}
+
+	var rule ruleSpec
+	if err := json.Unmarshal(output, &rule); err != nil {
+		return ruleSpec{}, errors.Wrapf(err, "failed to unmarshal crush rule %q", ruleName)
+	}
+	return rule, nil
+}
+
+// getCrushRulePlacement returns the placement of a crush rule with a single "take" and "chooseleaf" step,
+// or nil for the other rules
+func getCrushRulePlacement(rule ruleSpec) *crushRulePlacement {
+	var placement *crushRulePlacement
+	for _, step := range rule.Steps {
+		switch {
+		case step.Operation == "take":
+			if placement != nil {
+				return nil
+			}
+			// the shadow root of a device class is named "<root>~<class>"
+			root := strings.SplitN(step.ItemName, "~", 2)
+			placement = &crushRulePlacement{root: root[0]}
+			if len(root) == 2 {
+				placement.deviceClass = root[1]
+			}
+		case strings.HasPrefix(step.Operation, "choose"):
+			if placement == nil || placement.failureDomain != "" {
+				return nil
+			}
+			placement.failureDomain = step.Type
+		}
+	}
+	if placement == nil || placement.failureDomain == "" {
+		return nil
+	}
+	return placement
+}
+
+func desiredCrushRulePlacement(clusterSpec *cephv1.ClusterSpec, pool cephv1.PoolSpec) crushRulePlacement {
+	placement := crushRulePlacement{root: pool.CrushRoot, deviceClass: pool.DeviceClass, failureDomain: pool.FailureDomain}
+	if placement.root == "" {
+		placement.root = GetCrushRootFromSpec(clusterSpec)
+	}
+	if placement.failureDomain == "" {
+		placement.failureDomain = cephv1.DefaultFailureDomain
+	}
+	return placement
+}
+
+// IsPoolCrushRuleMigratable returns whether the crush rule of a pool can be migrated when its device class,
+// failure domain or crush root changes. Only the replicated pools with a simple crush rule can be migrated.
+func IsPoolCrushRuleMigratable(clusterSpec *cephv1.ClusterSpec, pool cephv1.PoolSpec) bool {
+	return pool.IsReplicated() && !clusterSpec.IsStretchCluster() && !pool.IsHybridStoragePool() && pool.Replicated.ReplicasPerFailureDomain <= 1
+}
+
+// PoolCrushRuleMigrationTarget returns the crush rule the pool must be migrated to, or an empty string if the
+// current crush rule of the pool already places its data as specified by the spec
+func PoolCrushRuleMigrationTarget(context *clusterd.Context, clusterInfo *ClusterInfo, clusterSpec *cephv1.ClusterSpec, poolName string, pool cephv1.PoolSpec) (string, string, error) {
+	currentRuleName, err := GetPoolCrushRule(context, clusterInfo, poolName)
+	if err != nil {
+		return "", "", err
+	}
+	currentRule, err := getCrushRule(context, clusterInfo, currentRuleName)
+	if err != nil {
+		return "", "", err
+	}
+	current := getCrushRulePlacement(currentRule)
+	if current == nil {
+		logger.Debugf("not checking the migration of pool %q with the custom crush rule %q", poolName, currentRuleName)
+		return currentRuleName, "", nil
+	}
+
+	desired := desiredCrushRulePlacement(clusterSpec, pool)
+	if *current == desired {
+		return currentRuleName, "", nil
+	}
+	logger.Infof("crush rule %q of pool %q places the data on %+v instead of %+v", currentRuleName, poolName, *current, desired)
+	return currentRuleName, migrationCrushRuleName(poolName, desired), nil
+}
+
+// migrationCrushRuleName returns the name of the crush rule a pool is migrated to, e.g. "replicapool_default_host_ssd"
+func migrationCrushRuleName(poolName string, placement crushRulePlacement) string {
+	name := fmt.Sprintf("%s_%s_%s", poolName, placement.root, placement.failureDomain)
+	if placement.deviceClass != "" {
+		name = fmt.Sprintf("%s_%s", name, placement.deviceClass)
+	}
+	return name
+}
+
+// MigratePoolCrushRule creates the crush rule and sets it as the crush rule of the pool, which moves the data
+// of the pool to the OSDs selected by the new rule
+func MigratePoolCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, clusterSpec *cephv1.ClusterSpec, poolName, ruleName string, pool cephv1.PoolSpec) error {
+	if err := createReplicationCrushRule(context, clusterInfo, clusterSpec, ruleName, pool); err != nil {
+		return errors.Wrapf(err, "failed to create crush rule %q", ruleName)
+	}
+	if err := SetPoolProperty(context, clusterInfo, poolName, "crush_rule", ruleName); err != nil {
+		return errors.Wrapf(err, "failed to migrate pool %q to crush rule %q", poolName, ruleName)
+	}
+	return nil
+}
+
+// GetPoolRecoveryStats returns the recovery progress of a pool
+func GetPoolRecoveryStats(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (*PoolRecoveryStats, error) {
+	args := []string{"osd", "pool", "stats", poolName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the stats of pool %q. %s", poolName, string(output))
+	}
+
+	var stats []struct {
+		Recovery PoolRecoveryStats `json:"recovery"`
+	}
+	if err := json.Unmarshal(output, &stats); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the stats of pool %q", poolName)
+	}
+	if len(stats) == 0 {
+		return nil, errors.Errorf("no stats for pool %q", poolName)
+	}
+	return &stats[0].Recovery, nil
+}
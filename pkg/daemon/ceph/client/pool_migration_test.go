@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const hddCrushRule = `{"rule_id":1,"rule_name":"replicapool","ruleset":1,"type":1,"min_size":1,"max_size":10,"steps":[
+{"op":"take","item":-2,"item_name":"default~hdd"},{"op":"chooseleaf_firstn","num":0,"type":"host"},{"op":"emit"}]}`
+
+func TestPoolCrushRuleMigrationTarget(t *testing.T) {
+	rule := hddCrushRule
+	var created, migrated []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "get":
+				assert.Equal(t, "crush_rule", args[4])
+				return `{"pool":"replicapool","pool_id":2,"crush_rule":"replicapool"}`, nil
+			case args[0] == "osd" && args[1] == "crush" && args[2] == "rule" && args[3] == "dump":
+				return rule, nil
+			case args[0] == "osd" && args[1] == "crush" && args[2] == "rule" && args[3] == "create-replicated":
+				created = append(created, args[4:8]...)
+				return "", nil
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "set":
+				migrated = append(migrated, args[3:6]...)
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %q %v", command, args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterSpec := &cephv1.ClusterSpec{}
+	pool := cephv1.PoolSpec{DeviceClass: "hdd", Replicated: cephv1.ReplicatedSpec{Size: 3}}
+
+	// the rule places the data as specified
+	current, target, err := PoolCrushRuleMigrationTarget(context, AdminClusterInfo("mycluster"), clusterSpec, "replicapool", pool)
+	assert.NoError(t, err)
+	assert.Equal(t, "replicapool", current)
+	assert.Empty(t, target)
+
+	// the device class changes
+	pool.DeviceClass = "ssd"
+	_, target, err = PoolCrushRuleMigrationTarget(context, AdminClusterInfo("mycluster"), clusterSpec, "replicapool", pool)
+	assert.NoError(t, err)
+	assert.Equal(t, "replicapool_default_host_ssd", target)
+
+	err = MigratePoolCrushRule(context, AdminClusterInfo("mycluster"), clusterSpec, "replicapool", target, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"replicapool_default_host_ssd", "default", "host", "ssd"}, created)
+	assert.Equal(t, []string{"replicapool", "crush_rule", "replicapool_default_host_ssd"}, migrated)
+
+	// the failure domain changes and the device class is removed
+	pool.DeviceClass = ""
+	pool.FailureDomain = "osd"
+	_, target, err = PoolCrushRuleMigrationTarget(context, AdminClusterInfo("mycluster"), clusterSpec, "replicapool", pool)
+	assert.NoError(t, err)
+	assert.Equal(t, "replicapool_default_osd", target)
+
+	// custom rules are not migrated
+	rule = `{"rule_id":1,"rule_name":"replicapool","steps":[{"op":"take","item_name":"default~ssd"},{"op":"chooseleaf_firstn","num":1,"type":"host"},{"op":"emit"},
+{"op":"take","item_name":"default~hdd"},{"op":"chooseleaf_firstn","num":-1,"type":"host"},{"op":"emit"}]}`
+	_, target, err = PoolCrushRuleMigrationTarget(context, AdminClusterInfo("mycluster"), clusterSpec, "replicapool", pool)
+	assert.NoError(t, err)
+	assert.Empty(t, target)
+}
+
+func TestIsPoolCrushRuleMigratable(t *testing.T) {
+	clusterSpec := &cephv1.ClusterSpec{}
+	assert.True(t, IsPoolCrushRuleMigratable(clusterSpec, cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}))
+	assert.False(t, IsPoolCrushRuleMigratable(clusterSpec, cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}))
+	assert.False(t, IsPoolCrushRuleMigratable(clusterSpec, cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 4, ReplicasPerFailureDomain: 2}}))
+	assert.False(t, IsPoolCrushRuleMigratable(&cephv1.ClusterSpec{Mon: cephv1.MonSpec{StretchCluster: &cephv1.StretchClusterSpec{Zones: []cephv1.StretchClusterZoneSpec{{Name: "a"}, {Name: "b"}, {Name: "c", Arbiter: true}}}}},
+		cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 4}}))
+}
+
+func TestGetPoolRecoveryStats(t *testing.T) {
+	output := `[{"pool_name":"replicapool","pool_id":2,"recovery":{"misplaced_objects":300,"misplaced_total":1200,"misplaced_ratio":0.25},"recovery_rate":{},"client_io_rate":{}}]`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			assert.Equal(t, []string{"osd", "pool", "stats", "replicapool"}, args[:4])
+			return output, nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	stats, err := GetPoolRecoveryStats(context, AdminClusterInfo("mycluster"), "replicapool")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(300), stats.MisplacedObjects)
+	assert.Equal(t, 0.25, stats.MisplacedRatio)
+
+	// no recovery
+	output = `[{"pool_name":"replicapool","pool_id":2,"recovery":{},"recovery_rate":{},"client_io_rate":{}}]`
+	stats, err = GetPoolRecoveryStats(context, AdminClusterInfo("mycluster"), "replicapool")
+	assert.NoError(t, err)
+	assert.Zero(t, stats.MisplacedObjects)
+}
@@ -18,6 +18,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
@@ -64,10 +65,12 @@ type HealthStatus struct {
 type CheckMessage struct {
 	Severity string  `json:"severity"`
 	Summary  Summary `json:"summary"`
+	Muted    bool    `json:"muted"`
 }
 
 type Summary struct {
 	Message string `json:"message"`
+	Count   int    `json:"count"`
 }
 
 type MonMap struct {
@@ -127,6 +130,10 @@ type PgMap struct {
 	CacheFlushBps         uint64         `json:"flush_bytes_sec"`
 	CacheEvictBps         uint64         `json:"evict_bytes_sec"`
 	CachePromoteBps       uint64         `json:"promote_op_per_sec"`
+	DegradedObjects       uint64         `json:"degraded_objects"`
+	DegradedRatio         float64        `json:"degraded_ratio"`
+	MisplacedObjects      uint64         `json:"misplaced_objects"`
+	MisplacedRatio        float64        `json:"misplaced_ratio"`
 }
 
 type PgStateEntry struct {
@@ -203,6 +210,18 @@ func IsClusterClean(context *clusterd.Context, clusterInfo *ClusterInfo) (string
 	return msg, true, nil
 }
 
+// MuteHealthWarning mutes a health check for the ttl. A sticky mute is kept when the health check gets worse.
+func MuteHealthWarning(context *clusterd.Context, clusterInfo *ClusterInfo, code string, ttl time.Duration, sticky bool) error {
+	args := []string{"health", "mute", code, fmt.Sprintf("%ds", int(ttl.Seconds()))}
+	if sticky {
+		args = append(args, "--sticky")
+	}
+	if _, err := NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+		return errors.Wrapf(err, "failed to mute health check %q", code)
+	}
+	return nil
+}
+
 // IsClusterCleanError returns an error indicating if the cluster is fully clean yet (i.e., all placement
 // groups are in the active+clean state). It returns nil if the cluster is clean.
 // Using IsClusterClean is recommended if you want to differentiate between a failure of the status query and
@@ -17,6 +17,7 @@ limitations under the License.
 package osd
 
 import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
@@ -37,11 +38,18 @@ type OsdAgent struct {
 	storeConfig    config.StoreConfig
 	kv             *k8sutil.ConfigMapKVStore
 	pvcBacked      bool
+	driveGroups    []cephv1.DriveGroup
+	replaceOSDs    []cephv1.OSDReplacement
+	// replacedDevices are the IDs of the destroyed OSDs by the path of their wiped device
+	replacedDevices map[string]int
+	// dryRun only reports the devices that would be consumed, without touching them
+	dryRun bool
 }
 
 // NewAgent is the instantiation of the OSD agent
 func NewAgent(context *clusterd.Context, devices []DesiredDevice, metadataDevice string, forceFormat bool,
-	storeConfig config.StoreConfig, clusterInfo *cephclient.ClusterInfo, nodeName string, kv *k8sutil.ConfigMapKVStore, pvcBacked bool) *OsdAgent {
+	storeConfig config.StoreConfig, clusterInfo *cephclient.ClusterInfo, nodeName string, kv *k8sutil.ConfigMapKVStore, pvcBacked bool,
+	driveGroups []cephv1.DriveGroup, replaceOSDs []cephv1.OSDReplacement, dryRun bool) *OsdAgent {
 
 	return &OsdAgent{
 		devices:        devices,
@@ -52,6 +60,9 @@ func NewAgent(context *clusterd.Context, devices []DesiredDevice, metadataDevice
 		nodeName:       nodeName,
 		kv:             kv,
 		pvcBacked:      pvcBacked,
+		driveGroups:    driveGroups,
+		replaceOSDs:    replaceOSDs,
+		dryRun:         dryRun,
 	}
 }
 
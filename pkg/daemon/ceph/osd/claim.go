@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/util/sys"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// deviceClaimID returns the most persistent identifier available for a device
+func deviceClaimID(device *sys.LocalDisk) string {
+	if device.WWN != "" {
+		return "wwn-" + device.WWN
+	}
+	if device.Serial != "" {
+		return "serial-" + device.Serial
+	}
+	for _, link := range strings.Fields(device.DevLinks) {
+		if strings.HasPrefix(link, "/dev/disk/by-id/") {
+			return strings.TrimPrefix(link, "/dev/disk/by-id/")
+		}
+	}
+	return device.Name
+}
+
+// claimDevices records the ownership of the devices by the cluster with the given fsid and
+// removes from the mapping the devices that are already claimed by another cluster. The node is
+// updated with optimistic concurrency so two clusters provisioning the same node at the same time
+// cannot both claim a device. The ids of the devices claimed by this call are returned, so that
+// they can be released if the devices fail to be provisioned.
+func claimDevices(clientset kubernetes.Interface, nodeName, fsid string, devices *DeviceOsdMapping) ([]string, error) {
+	var rejected, claimed []string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		rejected, claimed = []string{}, []string{}
+		node, err := clientset.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get node %q", nodeName)
+		}
+		claims, err := oposd.GetDeviceClaims(node)
+		if err != nil {
+			return err
+		}
+
+		for name, entry := range devices.Entries {
+			if entry.DeviceInfo == nil {
+				continue
+			}
+			id := deviceClaimID(entry.DeviceInfo)
+			owner, ok := claims[id]
+			if ok && owner != fsid {
+				logger.Warningf("skipping device %q since it is claimed by the ceph cluster with fsid %q", name, owner)
+				rejected = append(rejected, name)
+				continue
+			}
+			if !ok {
+				logger.Infof("claiming device %q (%q) for the ceph cluster with fsid %q", name, id, fsid)
+				claims[id] = fsid
+				claimed = append(claimed, id)
+			}
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		return oposd.UpdateDeviceClaims(clientset, node, claims)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to claim devices on node %q", nodeName)
+	}
+
+	for _, name := range rejected {
+		delete(devices.Entries, name)
+	}
+	return claimed, nil
+}
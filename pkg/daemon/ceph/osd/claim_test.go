@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"testing"
+
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/rook/rook/pkg/util/sys"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeviceClaimID(t *testing.T) {
+	assert.Equal(t, "wwn-0x5000", deviceClaimID(&sys.LocalDisk{Name: "sda", WWN: "0x5000", Serial: "abc"}))
+	assert.Equal(t, "serial-abc", deviceClaimID(&sys.LocalDisk{Name: "sda", Serial: "abc"}))
+	assert.Equal(t, "ata-disk1", deviceClaimID(&sys.LocalDisk{Name: "sda", DevLinks: "/dev/disk/by-path/pci-0 /dev/disk/by-id/ata-disk1"}))
+	assert.Equal(t, "sda", deviceClaimID(&sys.LocalDisk{Name: "sda"}))
+}
+
+func TestClaimDevices(t *testing.T) {
+	clientset := test.New(t, 1)
+	newMapping := func() *DeviceOsdMapping {
+		return &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{
+			"sda": {Data: unassignedOSDID, DeviceInfo: &sys.LocalDisk{Name: "sda", Serial: "a"}},
+			"sdb": {Data: unassignedOSDID, DeviceInfo: &sys.LocalDisk{Name: "sdb", Serial: "b"}},
+		}}
+	}
+
+	// the first cluster claims both devices
+	devices := newMapping()
+	claimed, err := claimDevices(clientset, "node0", "fsid-1", devices)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(devices.Entries))
+	assert.ElementsMatch(t, []string{"serial-a", "serial-b"}, claimed)
+
+	node, err := clientset.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	claims, err := oposd.GetDeviceClaims(node)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"serial-a": "fsid-1", "serial-b": "fsid-1"}, claims)
+
+	// the first cluster can provision its own devices again
+	devices = newMapping()
+	claimed, err = claimDevices(clientset, "node0", "fsid-1", devices)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(devices.Entries))
+	assert.Empty(t, claimed)
+
+	// the second cluster is refused the devices of the first cluster
+	devices = newMapping()
+	devices.Entries["sdc"] = &DeviceOsdIDEntry{Data: unassignedOSDID, DeviceInfo: &sys.LocalDisk{Name: "sdc", Serial: "c"}}
+	claimed, err = claimDevices(clientset, "node0", "fsid-2", devices)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(devices.Entries))
+	assert.NotNil(t, devices.Entries["sdc"])
+	assert.Equal(t, []string{"serial-c"}, claimed)
+
+	// the claims of the devices that failed to be provisioned are released
+	err = oposd.ReleaseDeviceClaims(clientset, "node0", "fsid-1", "serial-b")
+	assert.NoError(t, err)
+	node, err = clientset.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	claims, err = oposd.GetDeviceClaims(node)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"serial-a": "fsid-1", "serial-c": "fsid-2"}, claims)
+
+	// releasing the claims of the first cluster leaves the claims of the second cluster
+	err = oposd.ReleaseDeviceClaims(clientset, "node0", "fsid-1")
+	assert.NoError(t, err)
+	node, err = clientset.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	claims, err = oposd.GetDeviceClaims(node)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"serial-c": "fsid-2"}, claims)
+
+	err = oposd.ReleaseDeviceClaims(clientset, "node0", "fsid-2")
+	assert.NoError(t, err)
+	node, err = clientset.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, ok := node.Annotations[oposd.DeviceClaimsAnnotation]
+	assert.False(t, ok)
+
+	// unknown node
+	_, err = claimDevices(clientset, "foo", "fsid-1", newMapping())
+	assert.Error(t, err)
+
+	// the devices are refused when the claims cannot be read
+	node.Annotations = map[string]string{oposd.DeviceClaimsAnnotation: "foo"}
+	_, err = clientset.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	_, err = claimDevices(clientset, "node0", "fsid-1", newMapping())
+	assert.Error(t, err)
+}
@@ -22,6 +22,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/sys"
 )
 
@@ -186,6 +188,13 @@ func Provision(context *clusterd.Context, agent *OsdAgent, crushLocation, topolo
 		return errors.Wrap(err, "failed to generate ceph config")
 	}
 
+	// the devices of the replaced OSDs are wiped before the discovery so they are prepared again
+	if agent.dryRun {
+		logger.Info("dry-run: not wiping the devices of the replaced osds")
+	} else if err := agent.wipeReplacedOSDs(context); err != nil {
+		return errors.Wrap(err, "failed to wipe the devices of the replaced osds")
+	}
+
 	logger.Infof("discovering hardware")
 
 	var rawDevices []*sys.LocalDisk
@@ -219,6 +228,24 @@ func Provision(context *clusterd.Context, agent *OsdAgent, crushLocation, topolo
 		return errors.Wrap(err, "failed to get available devices")
 	}
 
+	if agent.dryRun {
+		plan := agent.planDevices(devices)
+		logger.Infof("dry-run: %d device(s) would be consumed on %q: %+v", len(plan), agent.nodeName, plan)
+		status = oposd.OrchestrationStatus{Status: oposd.OrchestrationStatusCompleted, PvcBackedOSD: agent.pvcBacked, DryRun: true, Plan: plan}
+		oposd.UpdateNodeOrPVCStatus(agent.kv, agent.nodeName, status)
+		return nil
+	}
+
+	// another ceph cluster may be running on the same node, so refuse the devices it already owns
+	var claimed []string
+	nodeName := os.Getenv(k8sutil.NodeNameEnvVar)
+	if !agent.pvcBacked {
+		claimed, err = claimDevices(context.Clientset, nodeName, agent.clusterInfo.FSID, devices)
+		if err != nil {
+			return errors.Wrap(err, "failed to claim the devices")
+		}
+	}
+
 	// orchestration is about to start, update the status
 	status = oposd.OrchestrationStatus{Status: oposd.OrchestrationStatusOrchestrating, PvcBackedOSD: agent.pvcBacked}
 	oposd.UpdateNodeOrPVCStatus(agent.kv, agent.nodeName, status)
@@ -228,6 +255,12 @@ func Provision(context *clusterd.Context, agent *OsdAgent, crushLocation, topolo
 
 	deviceOSDs, err := agent.configureCVDevices(context, devices)
 	if err != nil {
+		// the devices can be claimed again, possibly by another cluster, once they are wiped
+		if len(claimed) > 0 {
+			if releaseErr := oposd.ReleaseDeviceClaims(context.Clientset, nodeName, agent.clusterInfo.FSID, claimed...); releaseErr != nil {
+				logger.Errorf("failed to release the claims of the devices that failed to be configured. %v", releaseErr)
+			}
+		}
 		return errors.Wrap(err, "failed to configure devices")
 	}
 
@@ -246,6 +279,8 @@ func Provision(context *clusterd.Context, agent *OsdAgent, crushLocation, topolo
 	for i := range deviceOSDs {
 		deviceOSDs[i].Location = crushLocation
 		deviceOSDs[i].TopologyAffinity = topologyAffinity
+		// the operator projects the rebalance to the new OSDs from their size
+		deviceOSDs[i].Size = blockDeviceSize(context, deviceOSDs[i].BlockPath)
 	}
 
 	logger.Infof("devices = %+v", deviceOSDs)
@@ -283,6 +318,24 @@ func Provision(context *clusterd.Context, agent *OsdAgent, crushLocation, topolo
 	return nil
 }
 
+// blockDeviceSize returns the size in bytes of the block device of an OSD, or 0 if it cannot be determined
+func blockDeviceSize(context *clusterd.Context, blockPath string) uint64 {
+	if blockPath == "" {
+		return 0
+	}
+	props, err := sys.GetDevicePropertiesFromPath(blockPath, context.Executor)
+	if err != nil {
+		logger.Warningf("failed to get the size of block device %q. %v", blockPath, err)
+		return 0
+	}
+	size, err := strconv.ParseUint(props["SIZE"], 10, 64)
+	if err != nil {
+		logger.Warningf("failed to parse the size %q of block device %q. %v", props["SIZE"], blockPath, err)
+		return 0
+	}
+	return size
+}
+
 func getAvailableDevices(context *clusterd.Context, agent *OsdAgent) (*DeviceOsdMapping, error) {
 	desiredDevices := agent.devices
 	logger.Debugf("desiredDevices are %+v", desiredDevices)
@@ -293,6 +346,7 @@ func getAvailableDevices(context *clusterd.Context, agent *OsdAgent) (*DeviceOsd
 	}
 
 	available := &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{}}
+	driveGroupCandidates := []*sys.LocalDisk{}
 	for _, device := range context.Devices {
 		// Ignore 'dm' device since they are not handled by c-v properly
 		// see: https://tracker.ceph.com/issues/43209
@@ -310,6 +364,10 @@ func getAvailableDevices(context *clusterd.Context, agent *OsdAgent) (*DeviceOsd
 				if isCephEncryptedBlock(context, agent.clusterInfo.FSID, device.Name) {
 					logger.Infof("encrypted disk %q is an OSD part of this cluster, considering it", device.Name)
 				}
+			} else if device.Filesystem == sys.BcacheFilesystem {
+				// never consume or wipe the backing and cache devices, the OSD goes on the bcache device
+				logger.Infof("skipping device %q because it is a backing or cache device of bcache", device.Name)
+				continue
 			} else {
 				logger.Infof("skipping device %q because it contains a filesystem %q", device.Name, device.Filesystem)
 				continue
@@ -372,6 +430,10 @@ func getAvailableDevices(context *clusterd.Context, agent *OsdAgent) (*DeviceOsd
 			logger.Infof("device %q is available.", device.Name)
 		}
 
+		if len(agent.driveGroups) > 0 && !agent.pvcBacked && (device.Type == sys.DiskType || device.Type == sys.BcacheType) {
+			driveGroupCandidates = append(driveGroupCandidates, device)
+		}
+
 		var deviceInfo *DeviceOsdIDEntry
 		if agent.metadataDevice != "" && agent.metadataDevice == device.Name {
 			// current device is desired as the metadata device
@@ -484,6 +546,12 @@ func getAvailableDevices(context *clusterd.Context, agent *OsdAgent) (*DeviceOsd
 		}
 	}
 
+	if len(driveGroupCandidates) > 0 {
+		if err := agent.selectDriveGroupDevices(driveGroupCandidates, available); err != nil {
+			return nil, err
+		}
+	}
+
 	return available, nil
 }
 
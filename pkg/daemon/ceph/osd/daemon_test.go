@@ -367,6 +367,20 @@ NAME="sdb1" SIZE="30" TYPE="part" PKNAME="sdb"`, nil
 	assert.Equal(t, 1, len(mapping.Entries))
 	assert.Equal(t, -1, mapping.Entries["sdt1"].Data)
 
+	// the devices fronted by a cache are used, not the backing and cache devices of bcache
+	context.Devices = []*sys.LocalDisk{
+		{Name: "bcache0", RealPath: "/dev/bcache0", Type: sys.BcacheType, Parent: "sdx"},
+		{Name: "sdx", RealPath: "/dev/sdx", Type: sys.DiskType, Filesystem: sys.BcacheFilesystem},
+		{Name: "nvme1n1p1", RealPath: "/dev/nvme1n1p1", Type: sys.PartType, Filesystem: sys.BcacheFilesystem},
+		{Name: "dm-4", RealPath: "/dev/mapper/cached", Type: sys.DMCacheType},
+	}
+	agent.devices = []DesiredDevice{{Name: "all"}}
+	mapping, err = getAvailableDevices(context, agent)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(mapping.Entries), mapping)
+	assert.Equal(t, -1, mapping.Entries["bcache0"].Data)
+	assert.Equal(t, -1, mapping.Entries["dm-4"].Data)
+
 	// test on PVC
 	context.Devices = []*sys.LocalDisk{
 		{Name: "/mnt/set1-0-data-qfhfk", RealPath: "/dev/xvdcy", Type: "data"},
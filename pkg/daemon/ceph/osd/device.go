@@ -47,6 +47,9 @@ type DesiredDevice struct {
 	InitialWeight      string
 	IsFilter           bool
 	IsDevicePathFilter bool
+	// DriveGroup is the name of the drive group that selected the device and DriveGroupRole its role in the group
+	DriveGroup     string
+	DriveGroupRole string
 }
 
 // DeviceOsdMapping represents the mapping of an OSD on disk
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/sys"
+)
+
+const (
+	driveGroupDataRole = "data"
+	driveGroupDBRole   = "db"
+	driveGroupWALRole  = "wal"
+
+	walDeviceFlag     = "--wal-devices"
+	driveGroupLogPath = "/tmp/ceph-log"
+)
+
+// selectDriveGroupDevices adds the available disks selected by the drive groups to the devices to configure.
+// The drive groups select the disks in their order, a disk being selected by the first drive group and role
+// matching it, and take precedence over the other device settings of the node.
+func (a *OsdAgent) selectDriveGroupDevices(candidates []*sys.LocalDisk, available *DeviceOsdMapping) error {
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+
+	selected := map[string]bool{}
+	for _, driveGroup := range a.driveGroups {
+		roles := []struct {
+			name   string
+			filter *cephv1.DriveGroupDeviceFilter
+		}{
+			{driveGroupDataRole, &driveGroup.DataDevices},
+			{driveGroupDBRole, driveGroup.DBDevices},
+			{driveGroupWALRole, driveGroup.WALDevices},
+		}
+		for _, role := range roles {
+			if role.filter == nil {
+				continue
+			}
+			count := 0
+			for _, device := range candidates {
+				if selected[device.Name] {
+					continue
+				}
+				if role.filter.Limit > 0 && count >= role.filter.Limit {
+					break
+				}
+				matched, err := role.filter.Matches(device.Rotational, device.Size, device.Model, device.Vendor)
+				if err != nil {
+					return errors.Wrapf(err, "failed to select the %s devices of drive group %q", role.name, driveGroup.Name)
+				}
+				if !matched {
+					continue
+				}
+
+				logger.Infof("device %q is selected as a %s device by drive group %q", device.Name, role.name, driveGroup.Name)
+				selected[device.Name] = true
+				count++
+				available.Entries[device.Name] = &DeviceOsdIDEntry{
+					Data: unassignedOSDID,
+					Config: DesiredDevice{
+						Name:           device.Name,
+						OSDsPerDevice:  driveGroup.OSDsPerDevice,
+						DeviceClass:    driveGroup.DeviceClass,
+						DriveGroup:     driveGroup.Name,
+						DriveGroupRole: role.name,
+					},
+					PersistentDevicePaths: strings.Fields(device.DevLinks),
+					DeviceInfo:            device,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hasDriveGroupDevices returns whether devices were selected by the drive groups
+func hasDriveGroupDevices(devices *DeviceOsdMapping) bool {
+	for _, device := range devices.Entries {
+		if device.Config.DriveGroup != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// initializeDriveGroups prepares the devices of each drive group with a single "ceph-volume lvm batch",
+// which creates the OSDs on the data devices and spreads their db and wal over the db and wal devices
+func (a *OsdAgent) initializeDriveGroups(context *clusterd.Context, devices *DeviceOsdMapping) error {
+	if len(devices.Entries) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(driveGroupLogPath, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create dir %q", driveGroupLogPath)
+	}
+
+	// Use stdbuf to capture the python output buffer such that we can write to the pod log as the logging happens
+	baseCommand := "stdbuf"
+	for _, driveGroup := range a.driveGroups {
+		args := a.driveGroupBatchArgs(driveGroup, devices)
+		if args == nil {
+			logger.Infof("no new data device selected by drive group %q", driveGroup.Name)
+			continue
+		}
+
+		logger.Infof("configuring the devices of drive group %q", driveGroup.Name)
+		reportArgs := append(args, "--report")
+		if err := context.Executor.ExecuteCommand(baseCommand, reportArgs...); err != nil {
+			return errors.Wrapf(err, "failed ceph-volume report of drive group %q", driveGroup.Name) // fail return here as validation provided by ceph-volume
+		}
+		if err := context.Executor.ExecuteCommand(baseCommand, args...); err != nil {
+			cvLog := readCVLogContent(path.Join(driveGroupLogPath, "ceph-volume.log"))
+			if cvLog != "" {
+				logger.Errorf("%s", cvLog)
+			}
+			return errors.Wrapf(err, "failed ceph-volume of drive group %q", driveGroup.Name)
+		}
+	}
+	return nil
+}
+
+// driveGroupBatchArgs returns the arguments of the "ceph-volume lvm batch" of a drive group, or nil if no
+// new data device is selected by the drive group
+func (a *OsdAgent) driveGroupBatchArgs(driveGroup cephv1.DriveGroup, devices *DeviceOsdMapping) []string {
+	deviceArgs := map[string][]string{}
+	for name, device := range devices.Entries {
+		if device.Config.DriveGroup != driveGroup.Name || device.Data != unassignedOSDID {
+			continue
+		}
+		deviceArg := path.Join("/dev", name)
+		// ceph-volume prefers to use /dev/mapper/<name> if the device has this kind of alias
+		for _, devlink := range device.PersistentDevicePaths {
+			if strings.HasPrefix(devlink, "/dev/mapper") {
+				deviceArg = devlink
+			}
+		}
+		deviceArgs[device.Config.DriveGroupRole] = append(deviceArgs[device.Config.DriveGroupRole], deviceArg)
+	}
+	if len(deviceArgs[driveGroupDataRole]) == 0 {
+		return nil
+	}
+
+	args := []string{"-oL", cephVolumeCmd, "--log-path", driveGroupLogPath, "lvm", "batch", "--prepare", "--bluestore", "--yes"}
+	if a.storeConfig.EncryptedDevice {
+		args = append(args, encryptedFlag)
+	}
+	osdsPerDevice := driveGroup.OSDsPerDevice
+	if osdsPerDevice == 0 {
+		osdsPerDevice = a.storeConfig.OSDsPerDevice
+	}
+	args = append(args, osdsPerDeviceFlag, sanitizeOSDsPerDevice(osdsPerDevice))
+	deviceClass := driveGroup.DeviceClass
+	if deviceClass == "" {
+		deviceClass = a.storeConfig.DeviceClass
+	}
+	if deviceClass != "" {
+		args = append(args, crushDeviceClassFlag, deviceClass)
+	}
+
+	for _, role := range []string{driveGroupDataRole, driveGroupDBRole, driveGroupWALRole} {
+		sort.Strings(deviceArgs[role])
+	}
+	args = append(args, deviceArgs[driveGroupDataRole]...)
+	if len(deviceArgs[driveGroupDBRole]) > 0 {
+		args = append(args, dbDeviceFlag)
+		args = append(args, deviceArgs[driveGroupDBRole]...)
+	}
+	if len(deviceArgs[driveGroupWALRole]) > 0 {
+		args = append(args, walDeviceFlag)
+		args = append(args, deviceArgs[driveGroupWALRole]...)
+	}
+	args = append(args, a.replacedOSDIDArgs(osdIDsFlag, deviceArgs[driveGroupDataRole], sanitizeOSDsPerDevice(osdsPerDevice))...)
+	return args
+}
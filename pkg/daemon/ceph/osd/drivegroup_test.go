@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/rook/rook/pkg/util/sys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectDriveGroupDevices(t *testing.T) {
+	hdd := true
+	ssd := false
+	candidates := []*sys.LocalDisk{
+		{Name: "sdd", Rotational: true, Size: 4000000000000, Model: "ST4000"},
+		{Name: "sda", Rotational: true, Size: 4000000000000, Model: "ST4000"},
+		{Name: "sdc", Rotational: true, Size: 2000000000000, Model: "ST2000"},
+		{Name: "nvme0n1", Rotational: false, Size: 800000000000, Model: "Samsung SSD 970"},
+		{Name: "nvme1n1", Rotational: false, Size: 800000000000, Model: "Samsung SSD 970", DevLinks: "/dev/disk/by-id/nvme-1"},
+		{Name: "sdb", Rotational: false, Size: 200000000000, Model: "INTEL SSD"},
+	}
+	agent := &OsdAgent{driveGroups: []cephv1.DriveGroup{
+		{
+			Name:        "hdd-with-nvme-db",
+			DataDevices: cephv1.DriveGroupDeviceFilter{Rotational: &hdd, Size: "3T:"},
+			DBDevices:   &cephv1.DriveGroupDeviceFilter{Rotational: &ssd, Model: "^Samsung", Limit: 1},
+			DeviceClass: "hdd",
+		},
+		{
+			Name:          "ssd",
+			DataDevices:   cephv1.DriveGroupDeviceFilter{Rotational: &ssd},
+			OSDsPerDevice: 2,
+		},
+	}}
+
+	available := &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{
+		// the drive groups take precedence over the device settings of the node
+		"sda": {Data: unassignedOSDID, Config: DesiredDevice{Name: "sda"}},
+	}}
+	err := agent.selectDriveGroupDevices(candidates, available)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(available.Entries))
+	for name, role := range map[string]string{"sda": driveGroupDataRole, "sdd": driveGroupDataRole, "nvme0n1": driveGroupDBRole} {
+		assert.Equal(t, "hdd-with-nvme-db", available.Entries[name].Config.DriveGroup, name)
+		assert.Equal(t, role, available.Entries[name].Config.DriveGroupRole, name)
+		assert.Equal(t, "hdd", available.Entries[name].Config.DeviceClass, name)
+	}
+	// the devices already selected by the first drive group are not selected again
+	for _, name := range []string{"nvme1n1", "sdb"} {
+		assert.Equal(t, "ssd", available.Entries[name].Config.DriveGroup, name)
+		assert.Equal(t, driveGroupDataRole, available.Entries[name].Config.DriveGroupRole, name)
+		assert.Equal(t, 2, available.Entries[name].Config.OSDsPerDevice, name)
+	}
+	assert.Equal(t, []string{"/dev/disk/by-id/nvme-1"}, available.Entries["nvme1n1"].PersistentDevicePaths)
+	// sdc is too small for the first drive group and rotational for the second
+	assert.NotContains(t, available.Entries, "sdc")
+
+	// invalid filter
+	agent.driveGroups[0].DataDevices.Size = "big"
+	err = agent.selectDriveGroupDevices(candidates, &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{}})
+	assert.Error(t, err)
+}
+
+func TestInitializeDriveGroups(t *testing.T) {
+	var commands [][]string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommand: func(command string, args ...string) error {
+			assert.Equal(t, "stdbuf", command)
+			commands = append(commands, args)
+			return nil
+		},
+	}
+	agent := &OsdAgent{
+		storeConfig: config.StoreConfig{OSDsPerDevice: 1, EncryptedDevice: true},
+		driveGroups: []cephv1.DriveGroup{
+			{Name: "hdd", DeviceClass: "hdd"},
+			{Name: "db-only"},
+			{Name: "ssd", OSDsPerDevice: 2},
+		},
+	}
+	devices := &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{
+		"sdb":     {Data: unassignedOSDID, Config: DesiredDevice{DriveGroup: "hdd", DriveGroupRole: driveGroupDataRole}},
+		"sda":     {Data: unassignedOSDID, Config: DesiredDevice{DriveGroup: "hdd", DriveGroupRole: driveGroupDataRole}},
+		"nvme0n1": {Data: unassignedOSDID, Config: DesiredDevice{DriveGroup: "hdd", DriveGroupRole: driveGroupDBRole}},
+		"nvme1n1": {Data: unassignedOSDID, Config: DesiredDevice{DriveGroup: "hdd", DriveGroupRole: driveGroupWALRole}, PersistentDevicePaths: []string{"/dev/mapper/nvme1"}},
+		"nvme2n1": {Data: unassignedOSDID, Config: DesiredDevice{DriveGroup: "db-only", DriveGroupRole: driveGroupDBRole}},
+		"sdc":     {Data: unassignedOSDID, Config: DesiredDevice{DriveGroup: "ssd", DriveGroupRole: driveGroupDataRole}},
+		// already configured
+		"sdd": {Data: 3, Config: DesiredDevice{DriveGroup: "ssd", DriveGroupRole: driveGroupDataRole}},
+	}}
+
+	err := agent.initializeDriveGroups(&clusterd.Context{Executor: executor}, devices)
+	assert.NoError(t, err)
+	expected := []string{
+		"-oL ceph-volume --log-path /tmp/ceph-log lvm batch --prepare --bluestore --yes --dmcrypt --osds-per-device 1 --crush-device-class hdd /dev/sda /dev/sdb --db-devices /dev/nvme0n1 --wal-devices /dev/mapper/nvme1 --report",
+		"-oL ceph-volume --log-path /tmp/ceph-log lvm batch --prepare --bluestore --yes --dmcrypt --osds-per-device 1 --crush-device-class hdd /dev/sda /dev/sdb --db-devices /dev/nvme0n1 --wal-devices /dev/mapper/nvme1",
+		"-oL ceph-volume --log-path /tmp/ceph-log lvm batch --prepare --bluestore --yes --dmcrypt --osds-per-device 2 /dev/sdc --report",
+		"-oL ceph-volume --log-path /tmp/ceph-log lvm batch --prepare --bluestore --yes --dmcrypt --osds-per-device 2 /dev/sdc",
+	}
+	actual := []string{}
+	for _, args := range commands {
+		actual = append(actual, strings.Join(args, " "))
+	}
+	assert.Equal(t, expected, actual)
+}
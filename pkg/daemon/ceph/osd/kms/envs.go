@@ -80,8 +80,10 @@ func VaultConfigToEnvVar(spec cephv1.ClusterSpec) []v1.EnvVar {
 		envs = append(envs, v1.EnvVar{Name: k, Value: v})
 	}
 
-	// Add the VAULT_TOKEN
-	envs = append(envs, vaultTokenEnvVarFromSecret(spec.Security.KeyManagementService.TokenSecretName))
+	// Add the VAULT_TOKEN, the pods log in with their service account token with the kubernetes auth method
+	if spec.Security.KeyManagementService.IsTokenAuthEnabled() {
+		envs = append(envs, vaultTokenEnvVarFromSecret(spec.Security.KeyManagementService.TokenSecretName))
+	}
 
 	// Add TLS env if any
 	envs = append(envs, vaultTLSEnvVarFromSecret(spec.Security.KeyManagementService.ConnectionDetails)...)
@@ -53,6 +53,15 @@ func TestVaultTLSEnvVarFromSecret(t *testing.T) {
 	assert.Contains(t, envVars, v1.EnvVar{Name: "VAULT_CACERT", Value: "/etc/vault/vault.ca"})
 	assert.Contains(t, envVars, v1.EnvVar{Name: "VAULT_TOKEN", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "vault-token"}, Key: "token"}}})
 
+	// Kubernetes auth, the pods log in with their service account token
+	spec = cephv1.ClusterSpec{Security: cephv1.SecuritySpec{KeyManagementService: cephv1.KeyManagementServiceSpec{ConnectionDetails: map[string]string{"KMS_PROVIDER": "vault", "VAULT_ADDR": "http://1.1.1.1:8200", "VAULT_AUTH_METHOD": "kubernetes", "VAULT_AUTH_KUBERNETES_ROLE": "rook-ceph"}}}}
+	envVars = VaultConfigToEnvVar(spec)
+	assert.Equal(t, 5, len(envVars))
+	assert.Contains(t, envVars, v1.EnvVar{Name: "VAULT_AUTH_METHOD", Value: "kubernetes"})
+	assert.Contains(t, envVars, v1.EnvVar{Name: "VAULT_AUTH_KUBERNETES_ROLE", Value: "rook-ceph"})
+	for _, env := range envVars {
+		assert.NotEqual(t, "VAULT_TOKEN", env.Name)
+	}
 }
 
 func TestConfigEnvsToMapString(t *testing.T) {
@@ -55,6 +55,44 @@ func (c *Config) storeSecretInKubernetes(pvcName, key string) error {
 	return nil
 }
 
+// updateSecretInKubernetes stores the dmcrypt key in a Kubernetes Secret, overwriting the existing key
+func (c *Config) updateSecretInKubernetes(pvcName, key string) error {
+	s, err := generateOSDEncryptedKeySecret(pvcName, key, c.clusterInfo)
+	if err != nil {
+		return err
+	}
+
+	_, err = k8sutil.CreateOrUpdateSecret(c.context.Clientset, s)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update ceph osd encryption key secret for pvc %q", pvcName)
+	}
+
+	return nil
+}
+
+// getSecretInKubernetes returns the dmcrypt key of the Kubernetes Secret, or an empty key if the Secret does not exist
+func (c *Config) getSecretInKubernetes(pvcName string) (string, error) {
+	s, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, GenerateOSDEncryptionSecretName(pvcName), metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get ceph osd encryption key secret for pvc %q", pvcName)
+	}
+
+	return string(s.Data[OsdEncryptionSecretNameKeyName]), nil
+}
+
+// deleteSecretInKubernetes deletes the Kubernetes Secret of the dmcrypt key
+func (c *Config) deleteSecretInKubernetes(pvcName string) error {
+	err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Delete(c.clusterInfo.Context, GenerateOSDEncryptionSecretName(pvcName), metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete ceph osd encryption key secret for pvc %q", pvcName)
+	}
+
+	return nil
+}
+
 func generateOSDEncryptedKeySecret(pvcName, key string, clusterInfo *cephclient.ClusterInfo) (*v1.Secret, error) {
 	s := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
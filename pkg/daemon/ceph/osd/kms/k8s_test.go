@@ -17,11 +17,59 @@ limitations under the License.
 package kms
 
 import (
+	"context"
 	"testing"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGenerateOSDEncryptionSecretName(t *testing.T) {
 	assert.Equal(t, "rook-ceph-osd-encryption-key-set1-data-0-7dwll", GenerateOSDEncryptionSecretName("set1-data-0-7dwll"))
 }
+
+func TestKubernetesSecret(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "rook-ceph", Context: ctx}
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	c := NewConfig(&clusterd.Context{Clientset: clientset}, &cephv1.ClusterSpec{}, clusterInfo)
+	require.True(t, c.IsK8s())
+
+	// no key yet
+	key, err := c.GetSecret("set1-data-0")
+	assert.NoError(t, err)
+	assert.Empty(t, key)
+
+	s := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-encryption-key-set1-data-0", Namespace: "rook-ceph"},
+		Data:       map[string][]byte{OsdEncryptionSecretNameKeyName: []byte("old-key")},
+	}
+	_, err = clientset.CoreV1().Secrets("rook-ceph").Create(ctx, s, metav1.CreateOptions{})
+	require.NoError(t, err)
+	key, err = c.GetSecret("set1-data-0")
+	assert.NoError(t, err)
+	assert.Equal(t, "old-key", key)
+
+	// the key is only overwritten by an update
+	assert.NoError(t, c.PutSecret("set1-data-0", "new-key"))
+	key, err = c.GetSecret("set1-data-0")
+	assert.NoError(t, err)
+	assert.Equal(t, "old-key", key)
+	assert.NoError(t, c.UpdateSecret("set1-data-0", "new-key"))
+	s, err = clientset.CoreV1().Secrets("rook-ceph").Get(ctx, "rook-ceph-osd-encryption-key-set1-data-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "new-key", s.StringData[OsdEncryptionSecretNameKeyName])
+
+	assert.NoError(t, c.DeleteSecret("set1-data-0"))
+	_, err = clientset.CoreV1().Secrets("rook-ceph").Get(ctx, "rook-ceph-osd-encryption-key-set1-data-0", metav1.GetOptions{})
+	assert.Error(t, err)
+	// deleting a missing key succeeds
+	assert.NoError(t, c.DeleteSecret("set1-data-0"))
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package kms
 
 import (
-	"context"
 	"os"
 	"strings"
 
@@ -29,7 +28,6 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -97,9 +95,39 @@ func (c *Config) PutSecret(secretName, secretValue string) error {
 	return nil
 }
 
+// UpdateSecret writes an encrypted key in a KMS, overwriting the existing key if any
+func (c *Config) UpdateSecret(secretName, secretValue string) error {
+	if c.IsK8s() {
+		err := c.updateSecretInKubernetes(secretName, secretValue)
+		if err != nil {
+			return errors.Wrap(err, "failed to update secret in kubernetes secret")
+		}
+	}
+	if c.IsVault() {
+		v, err := InitVault(c.context, c.clusterInfo.Namespace, c.clusterSpec.Security.KeyManagementService.ConnectionDetails)
+		if err != nil {
+			return errors.Wrap(err, "failed to init vault kms")
+		}
+		k := buildKeyContext(c.clusterSpec.Security.KeyManagementService.ConnectionDetails)
+		err = overwrite(v, GenerateOSDEncryptionSecretName(secretName), secretValue, k)
+		if err != nil {
+			return errors.Wrap(err, "failed to update secret in vault")
+		}
+	}
+
+	return nil
+}
+
 // GetSecret returns an encrypted key from a KMS
 func (c *Config) GetSecret(secretName string) (string, error) {
 	var value string
+	if c.IsK8s() {
+		var err error
+		value, err = c.getSecretInKubernetes(secretName)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get secret in kubernetes secret")
+		}
+	}
 	if c.IsVault() {
 		// Store the secret in Vault
 		v, err := InitVault(c.context, c.clusterInfo.Namespace, c.clusterSpec.Security.KeyManagementService.ConnectionDetails)
@@ -119,6 +147,12 @@ func (c *Config) GetSecret(secretName string) (string, error) {
 
 // DeleteSecret deletes an encrypted key from a KMS
 func (c *Config) DeleteSecret(secretName string) error {
+	if c.IsK8s() {
+		err := c.deleteSecretInKubernetes(secretName)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete secret in kubernetes secret")
+		}
+	}
 	if c.IsVault() {
 		// Store the secret in Vault
 		v, err := InitVault(c.context, c.clusterInfo.Namespace, c.clusterSpec.Security.KeyManagementService.ConnectionDetails)
@@ -150,9 +184,8 @@ func GetParam(kmsConfig map[string]string, param string) string {
 
 // ValidateConnectionDetails validates mandatory KMS connection details
 func ValidateConnectionDetails(clusterdContext *clusterd.Context, securitySpec *cephv1.SecuritySpec, ns string) error {
-	ctx := context.TODO()
-	// A token must be specified
-	if !securitySpec.KeyManagementService.IsTokenAuthEnabled() {
+	// A token or the kubernetes auth method must be specified
+	if !securitySpec.KeyManagementService.IsTokenAuthEnabled() && !securitySpec.KeyManagementService.IsK8sAuthEnabled() {
 		return errors.New("failed to validate kms configuration (missing token in spec)")
 	}
 
@@ -161,21 +194,16 @@ func ValidateConnectionDetails(clusterdContext *clusterd.Context, securitySpec *
 
 	// Validate potential token Secret presence
 	if securitySpec.KeyManagementService.IsTokenAuthEnabled() {
-		kmsToken, err := clusterdContext.Clientset.CoreV1().Secrets(ns).Get(ctx, securitySpec.KeyManagementService.TokenSecretName, metav1.GetOptions{})
+		tokenProvider := &secretTokenProvider{context: clusterdContext, namespace: ns, secretName: securitySpec.KeyManagementService.TokenSecretName}
+		token, err := tokenProvider.Token()
 		if err != nil {
-			return errors.Wrapf(err, "failed to fetch kms token secret %q", securitySpec.KeyManagementService.TokenSecretName)
-		}
-
-		// Check for empty token
-		token, ok := kmsToken.Data[KMSTokenSecretNameKey]
-		if !ok || len(token) == 0 {
-			return errors.Errorf("failed to read k8s kms secret %q key %q (not found or empty)", KMSTokenSecretNameKey, securitySpec.KeyManagementService.TokenSecretName)
+			return err
 		}
 
 		switch provider {
 		case "vault":
 			// Set the env variable
-			err = os.Setenv(api.EnvVaultToken, string(token))
+			err = os.Setenv(api.EnvVaultToken, token)
 			if err != nil {
 				return errors.Wrap(err, "failed to set vault kms token to an env var")
 			}
@@ -197,6 +225,14 @@ func ValidateConnectionDetails(clusterdContext *clusterd.Context, securitySpec *
 			return errors.Wrap(err, "failed to validate vault connection details")
 		}
 
+		// Without a token secret, the operator logs in with its own service account token
+		if !securitySpec.KeyManagementService.IsTokenAuthEnabled() {
+			err = SetTokenToEnvVar(clusterdContext, &securitySpec.KeyManagementService, provider, ns)
+			if err != nil {
+				return errors.Wrap(err, "failed to log in to vault with the kubernetes auth method")
+			}
+		}
+
 		secretEngine := securitySpec.KeyManagementService.ConnectionDetails[VaultSecretEngineKey]
 		switch secretEngine {
 		case VaultKVSecretEngineKey:
@@ -216,26 +252,27 @@ func ValidateConnectionDetails(clusterdContext *clusterd.Context, securitySpec *
 	return nil
 }
 
-// SetTokenToEnvVar sets a KMS token as an env variable
-func SetTokenToEnvVar(clusterdContext *clusterd.Context, tokenSecretName, provider, namespace string) error {
-	ctx := context.TODO()
-	// Get the secret containing the kms token
-	kmsToken, err := clusterdContext.Clientset.CoreV1().Secrets(namespace).Get(ctx, tokenSecretName, metav1.GetOptions{})
-	if err != nil {
-		return errors.Wrapf(err, "failed to fetch kms token secret %q", tokenSecretName)
-	}
-
+// SetTokenToEnvVar sets the KMS token of the token provider of the KMS as an env variable
+func SetTokenToEnvVar(clusterdContext *clusterd.Context, kmsSpec *cephv1.KeyManagementServiceSpec, provider, namespace string) error {
 	// We set the token as an env variable, the secrets lib will pick it up
-	var key, value string
+	var key string
 	switch provider {
 	case secrets.TypeVault:
 		key = api.EnvVaultToken
-		value = string(kmsToken.Data[KMSTokenSecretNameKey])
 	default:
 		logger.Debugf("unknown provider %q return nil", provider)
 		return nil
 	}
 
+	tokenProvider, err := NewTokenProvider(clusterdContext, kmsSpec, namespace)
+	if err != nil {
+		return err
+	}
+	value, err := tokenProvider.Token()
+	if err != nil {
+		return err
+	}
+
 	// Set the env variable
 	err = os.Setenv(key, value)
 	if err != nil {
@@ -162,7 +162,7 @@ func TestSetTokenToEnvVar(t *testing.T) {
 	_, err := context.Clientset.CoreV1().Secrets(ns).Create(ctx, s, metav1.CreateOptions{})
 	assert.NoError(t, err)
 
-	err = SetTokenToEnvVar(context, secretName, "vault", ns)
+	err = SetTokenToEnvVar(context, &cephv1.KeyManagementServiceSpec{TokenSecretName: secretName}, "vault", ns)
 	assert.NoError(t, err)
 	assert.Equal(t, os.Getenv("VAULT_TOKEN"), "toto")
 	os.Unsetenv("VAULT_TOKEN")
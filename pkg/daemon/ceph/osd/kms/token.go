@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/libopenstorage/secrets"
+	"github.com/libopenstorage/secrets/vault"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ServiceAccountTokenPath is the path of the token of the service account mounted in the pods
+	ServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// TokenProvider provides the token the KMS client authenticates to the KMS with
+type TokenProvider interface {
+	// Token returns a token valid for the KMS
+	Token() (string, error)
+}
+
+// secretTokenProvider provides the static token stored in a Kubernetes secret
+type secretTokenProvider struct {
+	context    *clusterd.Context
+	namespace  string
+	secretName string
+}
+
+// vaultKubernetesTokenProvider provides a Vault token issued by the Kubernetes auth method of Vault in exchange for
+// the service account token of the pod, so no static credentials are stored in a secret. The service account token
+// is read at each call since the kubelet rotates the projected tokens.
+type vaultKubernetesTokenProvider struct {
+	config map[string]string
+}
+
+// NewTokenProvider returns the provider of the token the KMS client authenticates with, the token of the token
+// secret if set, or else the token issued for the service account of the pod with the Kubernetes auth method
+func NewTokenProvider(clusterdContext *clusterd.Context, kmsSpec *cephv1.KeyManagementServiceSpec, namespace string) (TokenProvider, error) {
+	if kmsSpec.IsTokenAuthEnabled() {
+		return &secretTokenProvider{context: clusterdContext, namespace: namespace, secretName: kmsSpec.TokenSecretName}, nil
+	}
+	if kmsSpec.IsK8sAuthEnabled() {
+		switch GetParam(kmsSpec.ConnectionDetails, Provider) {
+		case secrets.TypeVault:
+			return &vaultKubernetesTokenProvider{config: kmsSpec.ConnectionDetails}, nil
+		default:
+			return nil, errors.Errorf("kubernetes auth method not supported by kms provider %q", GetParam(kmsSpec.ConnectionDetails, Provider))
+		}
+	}
+	return nil, errors.New("failed to validate kms configuration (missing token in spec)")
+}
+
+func (p *secretTokenProvider) Token() (string, error) {
+	kmsToken, err := p.context.Clientset.CoreV1().Secrets(p.namespace).Get(context.TODO(), p.secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to fetch kms token secret %q", p.secretName)
+	}
+
+	// Check for empty token
+	token, ok := kmsToken.Data[KMSTokenSecretNameKey]
+	if !ok || len(token) == 0 {
+		return "", errors.Errorf("failed to read k8s kms secret %q key %q (not found or empty)", KMSTokenSecretNameKey, p.secretName)
+	}
+	return string(token), nil
+}
+
+func (p *vaultKubernetesTokenProvider) Token() (string, error) {
+	role := GetParam(p.config, vault.AuthKubernetesRole)
+	if role == "" {
+		return "", errors.Errorf("failed to validate kms config %q. cannot be empty with the kubernetes auth method", vault.AuthKubernetesRole)
+	}
+	tokenPath := GetParam(p.config, vault.AuthKubernetesTokenPath)
+	if tokenPath == "" {
+		tokenPath = ServiceAccountTokenPath
+	}
+	mountPath := GetParam(p.config, vault.AuthMountPath)
+	if mountPath == "" {
+		mountPath = vault.AuthKubernetesMountPath
+	}
+
+	jwt, err := ioutil.ReadFile(tokenPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read service account token %q", tokenPath)
+	}
+
+	client, err := vaultClient(p.config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to initialize vault client")
+	}
+	// the login must not be sent with the token of a previous login
+	client.ClearToken()
+	if namespace := GetParam(p.config, api.EnvVaultNamespace); namespace != "" {
+		client.SetNamespace(namespace)
+	}
+
+	loginPath := path.Join("auth", mountPath, "login")
+	secret, err := client.Logical().Write(loginPath, map[string]interface{}{
+		"role": role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to log in to vault with role %q", role)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return "", errors.Errorf("failed to log in to vault with role %q (empty token)", role)
+	}
+	return secret.Auth.ClientToken, nil
+}
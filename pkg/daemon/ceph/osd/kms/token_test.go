@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultKubernetesTokenProvider(t *testing.T) {
+	// the other tests mock the vault client
+	oldVaultClient := vaultClient
+	defer func() { vaultClient = oldVaultClient }()
+	vaultClient = newVaultClient
+
+	// a vault server only accepting the service account token of the pod for the role
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var login map[string]string
+		if r.URL.Path != "/v1/auth/kubernetes/login" || json.NewDecoder(r.Body).Decode(&login) != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if login["role"] != "rook-ceph" || login["jwt"] != "service-account-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth": {"client_token": "vault-token"}}`))
+	}))
+	defer server.Close()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(tokenPath, []byte("service-account-token\n"), 0600))
+	kmsSpec := &cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{
+			"KMS_PROVIDER":                     "vault",
+			"VAULT_ADDR":                       server.URL,
+			"VAULT_AUTH_METHOD":                "kubernetes",
+			"VAULT_AUTH_KUBERNETES_TOKEN_PATH": tokenPath,
+		},
+	}
+	context := &clusterd.Context{Clientset: test.New(t, 1)}
+
+	// the role is mandatory
+	tokenProvider, err := NewTokenProvider(context, kmsSpec, "rook-ceph")
+	require.NoError(t, err)
+	_, err = tokenProvider.Token()
+	assert.Error(t, err)
+
+	kmsSpec.ConnectionDetails["VAULT_AUTH_KUBERNETES_ROLE"] = "rook-ceph"
+	token, err := tokenProvider.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "vault-token", token)
+
+	// the token is set for the secrets lib
+	err = SetTokenToEnvVar(context, kmsSpec, "vault", "rook-ceph")
+	assert.NoError(t, err)
+	assert.Equal(t, "vault-token", os.Getenv("VAULT_TOKEN"))
+	os.Unsetenv("VAULT_TOKEN")
+
+	kmsSpec.ConnectionDetails["VAULT_AUTH_KUBERNETES_ROLE"] = "other"
+	_, err = tokenProvider.Token()
+	assert.Error(t, err)
+
+	// the token secret has precedence
+	kmsSpec.TokenSecretName = "vault-token"
+	tokenProvider, err = NewTokenProvider(context, kmsSpec, "rook-ceph")
+	require.NoError(t, err)
+	_, ok := tokenProvider.(*secretTokenProvider)
+	assert.True(t, ok)
+
+	// an authentication must be configured
+	_, err = NewTokenProvider(context, &cephv1.KeyManagementServiceSpec{ConnectionDetails: map[string]string{"KMS_PROVIDER": "vault"}}, "rook-ceph")
+	assert.Error(t, err)
+}
@@ -145,12 +145,16 @@ func put(v secrets.Secrets, secretName, secretValue string, keyContext map[strin
 		return nil
 	}
 
+	return overwrite(v, secretName, secretValue, keyContext)
+}
+
+func overwrite(v secrets.Secrets, secretName, secretValue string, keyContext map[string]string) error {
 	// Build Secret
 	data := make(map[string]interface{})
 	data[secretName] = secretValue
 
 	// #nosec G104 Write the encryption key in Vault
-	err = v.PutSecret(secretName, data, keyContext)
+	err := v.PutSecret(secretName, data, keyContext)
 	if err != nil {
 		return errors.Wrapf(err, "failed to put secret %q in vault", secretName)
 	}
@@ -118,6 +118,23 @@ func BackendVersion(secretConfig map[string]string) (string, error) {
 	return "", errors.Errorf("secrets engine with mount path %q not found", backendPath)
 }
 
+// CheckVaultConnection checks the vault server of the connection details is reachable and unsealed
+func CheckVaultConnection(secretConfig map[string]string) error {
+	vaultClient, err := vaultClient(secretConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize vault client")
+	}
+
+	health, err := vaultClient.Sys().Health()
+	if err != nil {
+		return errors.Wrapf(err, "failed to reach vault at %q", secretConfig[api.EnvVaultAddress])
+	}
+	if health.Sealed {
+		return errors.Errorf("vault at %q is sealed", secretConfig[api.EnvVaultAddress])
+	}
+	return nil
+}
+
 func trimSlash(in string) string {
 	return strings.Trim(in, "/")
 }
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+var (
+	provisioningLeaseDuration      = 2 * time.Minute
+	provisioningLeaseRetryInterval = 5 * time.Second
+	provisioningLeaseWaitTimeout   = 30 * time.Minute
+)
+
+// AcquireProvisioningLease waits until the prepare job holds the provisioning lease of its node, so no other prepare
+// job nor the discover daemon enumerates or prepares the devices of the node at the same time. The lease is renewed
+// in the background until the returned function releases it. The lease is skipped if the operator did not set its
+// namespace.
+func AcquireProvisioningLease(clusterdContext *clusterd.Context, nodeName, namespace, holder string) (func(), error) {
+	if namespace == "" || nodeName == "" || holder == "" {
+		logger.Info("not acquiring the provisioning lease of the node since its namespace, the node or the pod name is not known")
+		return func() {}, nil
+	}
+
+	ctx := context.Background()
+	clientset := clusterdContext.Clientset
+	name := k8sutil.ProvisioningLeaseName(nodeName)
+	deadline := time.Now().Add(provisioningLeaseWaitTimeout)
+	for {
+		acquired, current, err := k8sutil.TryAcquireLease(ctx, clientset, namespace, name, holder, provisioningLeaseDuration)
+		if err != nil {
+			logger.Warningf("failed to acquire the provisioning lease %q. %v", name, err)
+		} else if acquired {
+			break
+		} else {
+			logger.Infof("waiting for %q to release the provisioning lease %q of node %q", current, name, nodeName)
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("failed to acquire the provisioning lease %q of node %q within %s", name, nodeName, provisioningLeaseWaitTimeout.String())
+		}
+		time.Sleep(provisioningLeaseRetryInterval)
+	}
+	logger.Infof("acquired the provisioning lease %q of node %q", name, nodeName)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(provisioningLeaseDuration / 3):
+				if _, current, err := k8sutil.TryAcquireLease(ctx, clientset, namespace, name, holder, provisioningLeaseDuration); err != nil {
+					logger.Warningf("failed to renew the provisioning lease %q. %v", name, err)
+				} else if current != holder {
+					logger.Warningf("the provisioning lease %q expired and is held by %q", name, current)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		if err := k8sutil.ReleaseLease(ctx, clientset, namespace, name, holder); err != nil {
+			logger.Warningf("failed to release the provisioning lease %q. %v", name, err)
+			return
+		}
+		logger.Infof("released the provisioning lease %q of node %q", name, nodeName)
+	}, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/util/sys"
+)
+
+// planDevices returns the devices the OSDs would be prepared on, without touching them
+func (a *OsdAgent) planDevices(devices *DeviceOsdMapping) []oposd.PlannedDevice {
+	plan := []oposd.PlannedDevice{}
+	for name, entry := range devices.Entries {
+		device := oposd.PlannedDevice{Name: name, Role: pvcDataTypeDevice}
+		if entry.DeviceInfo != nil {
+			device.Name = entry.DeviceInfo.Name
+			if entry.DeviceInfo.DevLinks != "" {
+				device.Paths = strings.Fields(entry.DeviceInfo.DevLinks)
+			}
+			device.Size = entry.DeviceInfo.Size
+			device.Type = entry.DeviceInfo.Type
+			device.Rotational = entry.DeviceInfo.Rotational
+		}
+		if !a.pvcBacked && !strings.HasPrefix(device.Name, "/") {
+			device.Name = filepath.Join("/dev", device.Name)
+		}
+
+		switch {
+		case entry.Config.DriveGroupRole != "":
+			device.Role = entry.Config.DriveGroupRole
+			device.DriveGroup = entry.Config.DriveGroup
+		case entry.DeviceInfo != nil && (entry.DeviceInfo.Type == pvcMetadataTypeDevice || entry.DeviceInfo.Type == pvcWalTypeDevice):
+			device.Role = entry.DeviceInfo.Type
+		case entry.Metadata != nil:
+			device.Role = pvcMetadataTypeDevice
+		}
+		if device.Role != pvcDataTypeDevice {
+			plan = append(plan, device)
+			continue
+		}
+
+		device.DeviceClass = entry.Config.DeviceClass
+		if device.DeviceClass == "" {
+			device.DeviceClass = a.storeConfig.DeviceClass
+		}
+		if device.DeviceClass == "" && entry.DeviceInfo != nil {
+			device.DeviceClass = sys.GetDiskDeviceClass(entry.DeviceInfo)
+		}
+		device.OSDsPerDevice = entry.Config.OSDsPerDevice
+		if device.OSDsPerDevice == 0 {
+			device.OSDsPerDevice = a.storeConfig.OSDsPerDevice
+		}
+		device.MetadataDevice = entry.Config.MetadataDevice
+		if device.MetadataDevice == "" {
+			device.MetadataDevice = a.metadataDevice
+		}
+		plan = append(plan, device)
+	}
+
+	sort.Slice(plan, func(i, j int) bool { return plan[i].Name < plan[j].Name })
+	return plan
+}
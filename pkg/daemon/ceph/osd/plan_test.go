@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"testing"
+
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+	"github.com/rook/rook/pkg/util/sys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanDevices(t *testing.T) {
+	agent := &OsdAgent{metadataDevice: "nvme0n1", storeConfig: config.StoreConfig{OSDsPerDevice: 1}}
+	devices := &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{
+		"sdc": {
+			Data:       unassignedOSDID,
+			Config:     DesiredDevice{Name: "sdc", OSDsPerDevice: 2, DeviceClass: "fast"},
+			DeviceInfo: &sys.LocalDisk{Name: "sdc", DevLinks: "/dev/disk/by-id/c /dev/disk/by-path/c", Size: 1000, Type: sys.DiskType},
+		},
+		"sdb": {
+			Data:       unassignedOSDID,
+			Config:     DesiredDevice{Name: "sdb"},
+			DeviceInfo: &sys.LocalDisk{Name: "sdb", Size: 2000, Type: sys.DiskType, Rotational: true},
+		},
+		"nvme0n1": {
+			Data:       unassignedOSDID,
+			Metadata:   []int{},
+			DeviceInfo: &sys.LocalDisk{Name: "nvme0n1", Size: 500, Type: sys.DiskType},
+		},
+		"sdd": {
+			Data:       unassignedOSDID,
+			Config:     DesiredDevice{Name: "sdd", DriveGroup: "group", DriveGroupRole: "db"},
+			DeviceInfo: &sys.LocalDisk{Name: "sdd", Size: 300, Type: sys.DiskType},
+		},
+	}}
+
+	assert.Equal(t, []oposd.PlannedDevice{
+		{Name: "/dev/nvme0n1", Size: 500, Type: sys.DiskType, Role: "metadata"},
+		{Name: "/dev/sdb", Size: 2000, Type: sys.DiskType, Rotational: true, Role: "data", DeviceClass: "hdd", OSDsPerDevice: 1, MetadataDevice: "nvme0n1"},
+		{Name: "/dev/sdc", Paths: []string{"/dev/disk/by-id/c", "/dev/disk/by-path/c"}, Size: 1000, Type: sys.DiskType, Role: "data", DeviceClass: "fast", OSDsPerDevice: 2, MetadataDevice: "nvme0n1"},
+		{Name: "/dev/sdd", Size: 300, Type: sys.DiskType, Role: "db", DriveGroup: "group"},
+	}, agent.planDevices(devices))
+
+	// the devices of an osd on pvc
+	agent = &OsdAgent{pvcBacked: true}
+	devices = &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{
+		pvcDataTypeDevice:     {Data: unassignedOSDID, DeviceInfo: &sys.LocalDisk{Name: "/mnt/set1-data-0", Type: pvcDataTypeDevice, Rotational: true}},
+		pvcMetadataTypeDevice: {Metadata: []int{1}, DeviceInfo: &sys.LocalDisk{Name: "/srv/set1-metadata-0", Type: pvcMetadataTypeDevice}},
+	}}
+	assert.Equal(t, []oposd.PlannedDevice{
+		{Name: "/mnt/set1-data-0", Type: pvcDataTypeDevice, Rotational: true, Role: "data", DeviceClass: "hdd"},
+		{Name: "/srv/set1-metadata-0", Type: pvcMetadataTypeDevice, Role: "metadata"},
+	}, agent.planDevices(devices))
+}
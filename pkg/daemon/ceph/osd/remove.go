@@ -80,12 +80,12 @@ func removeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInf
 		logger.Errorf("failed to exclude osd.%d out of the crush map. %v", osdID, err)
 	}
 
-	// Remove the OSD deployment
+	// Remove the OSD deployment, or the OSD pod if the OSD runs in a pod set
 	deploymentName := fmt.Sprintf("rook-ceph-osd-%d", osdID)
+	var osdLabels map[string]string
 	deployment, err := clusterdContext.Clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(clusterInfo.Context, deploymentName, metav1.GetOptions{})
-	if err != nil {
-		logger.Errorf("failed to fetch the deployment %q. %v", deploymentName, err)
-	} else {
+	if err == nil {
+		osdLabels = deployment.GetLabels()
 		logger.Infof("removing the OSD deployment %q", deploymentName)
 		if err := k8sutil.DeleteDeployment(clusterdContext.Clientset, clusterInfo.Namespace, deploymentName); err != nil {
 			if err != nil {
@@ -93,7 +93,18 @@ func removeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInf
 				logger.Errorf("failed to delete deployment for OSD %d. %v", osdID, err)
 			}
 		}
-		if pvcName, ok := deployment.GetLabels()[osd.OSDOverPVCLabelKey]; ok {
+	} else if pod, podErr := clusterdContext.Clientset.CoreV1().Pods(clusterInfo.Namespace).Get(clusterInfo.Context, deploymentName, metav1.GetOptions{}); podErr == nil && pod.Labels[osd.OSDPodSetLabelKey] != "" {
+		osdLabels = pod.GetLabels()
+		logger.Infof("removing the OSD pod %q of pod set %q", deploymentName, pod.Labels[osd.OSDPodSetLabelKey])
+		if err := k8sutil.DeletePod(clusterdContext.Clientset, clusterInfo.Namespace, deploymentName); err != nil {
+			// Continue purging the OSD even if the pod fails to be deleted
+			logger.Errorf("failed to delete pod for OSD %d. %v", osdID, err)
+		}
+	} else {
+		logger.Errorf("failed to fetch the deployment %q. %v", deploymentName, err)
+	}
+	if osdLabels != nil {
+		if pvcName, ok := osdLabels[osd.OSDOverPVCLabelKey]; ok {
 			labelSelector := fmt.Sprintf("%s=%s", osd.OSDOverPVCLabelKey, pvcName)
 			prepareJobList, err := clusterdContext.Clientset.BatchV1().Jobs(clusterInfo.Namespace).List(clusterInfo.Context, metav1.ListOptions{LabelSelector: labelSelector})
 			if err != nil && !kerrors.IsNotFound(err) {
@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+const (
+	osdIDFlag  = "--osd-id"
+	osdIDsFlag = "--osd-ids"
+	// the bluestore label and the LUKS header of a raw OSD are at the beginning of the device
+	wipeSizeMB = 100
+)
+
+// wipeReplacedOSDs wipes the devices of the OSDs the operator destroyed for their replacement, so the devices
+// are prepared again as new OSDs with the same IDs
+func (a *OsdAgent) wipeReplacedOSDs(context *clusterd.Context) error {
+	if len(a.replaceOSDs) == 0 {
+		return nil
+	}
+
+	osdDump, err := client.GetOSDDump(context, a.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd dump")
+	}
+
+	a.replacedDevices = map[string]int{}
+	for _, replacement := range a.replaceOSDs {
+		// a previous prepare job may have prepared the OSD again already
+		if !osdDump.IsDestroyed(replacement.ID) {
+			logger.Infof("osd.%d is not destroyed, not wiping its device", replacement.ID)
+			continue
+		}
+
+		device, err := a.wipeReplacedOSD(context, replacement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to wipe the device of osd.%d", replacement.ID)
+		}
+		logger.Infof("device %q of osd.%d is wiped, preparing it again with the same osd id", device, replacement.ID)
+		a.replacedDevices[device] = replacement.ID
+	}
+
+	return nil
+}
+
+// wipeReplacedOSD wipes the device of the OSD and returns its path
+func (a *OsdAgent) wipeReplacedOSD(context *clusterd.Context, replacement cephv1.OSDReplacement) (string, error) {
+	// the PVCs of the prepare job belong to the replaced OSD only, the first one is the data PVC
+	if a.pvcBacked {
+		for _, device := range a.devices {
+			if err := wipeDevice(context, device.Name); err != nil {
+				return "", err
+			}
+		}
+		return a.devices[0].Name, nil
+	}
+
+	lvmOSD, err := getLVMOSD(context, a.clusterInfo.FSID, replacement.ID)
+	if err != nil {
+		return "", err
+	}
+	if lvmOSD == nil {
+		// the kernel name of the device may have changed since the OSD was deployed, so the device is only
+		// wiped if it still holds the replaced OSD
+		if err := checkRawOSDDevice(context, a.clusterInfo.FSID, replacement.ID, replacement.Path); err != nil {
+			return "", err
+		}
+		return replacement.Path, wipeDevice(context, replacement.Path)
+	}
+
+	// zapping the OSD also removes its db and wal logical volumes from the metadata device
+	logger.Infof("zapping the logical volumes of osd.%d", replacement.ID)
+	args := []string{"lvm", "zap", "--destroy", osdIDFlag, strconv.Itoa(replacement.ID), "--osd-fsid", lvmOSD.Tags.OSDFSID}
+	if _, err := callCephVolume(context, true, args...); err != nil {
+		return "", errors.Wrapf(err, "failed to zap osd.%d", replacement.ID)
+	}
+	if len(lvmOSD.Devices) == 0 {
+		return "", errors.Errorf("failed to find the device of osd.%d", replacement.ID)
+	}
+	return lvmOSD.Devices[0], nil
+}
+
+// getLVMOSD returns the block logical volume of the OSD of the cluster, or nil if the OSD is not an LVM OSD
+func getLVMOSD(context *clusterd.Context, cephfsid string, osdID int) (*osdInfo, error) {
+	result, err := callCephVolume(context, false, "lvm", "list", "--format", "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve ceph-volume lvm list results")
+	}
+
+	var cephVolumeResult map[string][]osdInfo
+	if err := json.Unmarshal([]byte(result), &cephVolumeResult); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal ceph-volume lvm list results. %s", result)
+	}
+
+	for _, lv := range cephVolumeResult[strconv.Itoa(osdID)] {
+		if lv.Type == "block" && lv.Tags.ClusterFSID == cephfsid {
+			return &lv, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkRawOSDDevice returns an error unless the device holds the raw OSD of the cluster
+func checkRawOSDDevice(context *clusterd.Context, cephfsid string, osdID int, device string) error {
+	result, err := callCephVolume(context, false, "raw", "list", device, "--format", "json")
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve ceph-volume raw list results of device %q", device)
+	}
+
+	var cephVolumeResult map[string]osdInfoBlock
+	if err := json.Unmarshal([]byte(result), &cephVolumeResult); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal ceph-volume raw list results. %s", result)
+	}
+
+	for _, osd := range cephVolumeResult {
+		if osd.OsdID == osdID && osd.CephFsid == cephfsid {
+			return nil
+		}
+		logger.Warningf("device %q holds osd.%d of ceph cluster %q", device, osd.OsdID, osd.CephFsid)
+	}
+	return errors.Errorf("refusing to wipe device %q, it does not hold osd.%d of ceph cluster %q anymore", device, osdID, cephfsid)
+}
+
+func wipeDevice(context *clusterd.Context, device string) error {
+	logger.Infof("wiping device %q", device)
+	op, err := context.Executor.ExecuteCommandWithCombinedOutput("dd", "if=/dev/zero", fmt.Sprintf("of=%s", device), "bs=1M", fmt.Sprintf("count=%d", wipeSizeMB), "oflag=direct,dsync")
+	if err != nil {
+		return errors.Wrapf(err, "failed to wipe device %q. %s", device, op)
+	}
+	return nil
+}
+
+// replacedOSDIDArgs returns the args preparing the devices with the IDs of the OSDs they replace. The IDs
+// are only reused when all the devices replace a destroyed OSD, with a single OSD per device. The args
+// must follow the devices since ceph-volume accepts a list of IDs.
+func (a *OsdAgent) replacedOSDIDArgs(flag string, devices []string, osdsPerDevice string) []string {
+	ids := []string{}
+	for _, device := range devices {
+		if id, ok := a.replacedDevices[device]; ok {
+			ids = append(ids, strconv.Itoa(id))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if len(ids) != len(devices) || osdsPerDevice != "1" {
+		logger.Warningf("not reusing the ids %v of the replaced osds, devices %v are prepared together with %s osds per device", ids, devices, osdsPerDevice)
+		return nil
+	}
+	return append([]string{flag}, ids...)
+}
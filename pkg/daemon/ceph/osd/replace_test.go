@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWipeReplacedOSDs(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if command == "ceph" && args[0] == "osd" && args[1] == "dump" {
+				return `{"osds":[{"osd":3,"up":0,"in":0,"state":["exists","destroyed"]},{"osd":4,"up":0,"in":0,"state":["exists","destroyed"]},{"osd":5,"up":1,"in":1,"state":["exists","up"]}]}`, nil
+			}
+			if command == "stdbuf" && strings.Contains(strings.Join(args, " "), "lvm list") {
+				return `{"4":[{"name":"osd-block-4","path":"/dev/ceph-vg/osd-block-4","devices":["/dev/sdc"],"type":"block","tags":{"ceph.osd_fsid":"uuid-4","ceph.cluster_fsid":"fsid"}},
+{"name":"osd-db-4","path":"/dev/ceph-db/osd-db-4","devices":["/dev/nvme0n1"],"type":"db","tags":{"ceph.osd_fsid":"uuid-4","ceph.cluster_fsid":"fsid"}}]}`, nil
+			}
+			if command == "stdbuf" && strings.Contains(strings.Join(args, " "), "raw list /dev/sdb") {
+				return `{"uuid-3":{"ceph_fsid":"fsid","device":"/dev/sdb","osd_id":3,"osd_uuid":"uuid-3","type":"bluestore"}}`, nil
+			}
+			if command == "stdbuf" && strings.Contains(strings.Join(args, " "), "raw list /dev/sde") {
+				// the device was renamed, it now holds another osd
+				return `{"uuid-6":{"ceph_fsid":"fsid","device":"/dev/sde","osd_id":6,"osd_uuid":"uuid-6","type":"bluestore"}}`, nil
+			}
+			return "", errors.Errorf("unexpected command %q %v", command, args)
+		},
+		MockExecuteCommandWithCombinedOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			commands = append(commands, strings.Join(append([]string{command}, args...), " "))
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := client.AdminClusterInfo("mycluster")
+	clusterInfo.FSID = "fsid"
+
+	t.Run("devices of nodes", func(t *testing.T) {
+		commands = nil
+		agent := &OsdAgent{clusterInfo: clusterInfo, replaceOSDs: []cephv1.OSDReplacement{
+			{ID: 3, Path: "/dev/sdb"},
+			{ID: 4, Path: "/dev/ceph-vg/osd-block-4"},
+			// already prepared again by a previous prepare job
+			{ID: 5, Path: "/dev/sdd"},
+		}}
+		err := agent.wipeReplacedOSDs(context)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"dd if=/dev/zero of=/dev/sdb bs=1M count=100 oflag=direct,dsync",
+			"stdbuf -oL ceph-volume --log-path /tmp/ceph-log lvm zap --destroy --osd-id 4 --osd-fsid uuid-4",
+		}, commands)
+		assert.Equal(t, map[string]int{"/dev/sdb": 3, "/dev/sdc": 4}, agent.replacedDevices)
+	})
+
+	t.Run("stale path of a raw device holding another osd", func(t *testing.T) {
+		commands = nil
+		agent := &OsdAgent{clusterInfo: clusterInfo, replaceOSDs: []cephv1.OSDReplacement{{ID: 3, Path: "/dev/sde"}}}
+		err := agent.wipeReplacedOSDs(context)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "refusing to wipe device \"/dev/sde\"")
+		assert.Empty(t, commands)
+		assert.Empty(t, agent.replacedDevices)
+	})
+
+	t.Run("devices of a pvc", func(t *testing.T) {
+		commands = nil
+		agent := &OsdAgent{
+			clusterInfo: clusterInfo,
+			pvcBacked:   true,
+			devices:     []DesiredDevice{{Name: "/mnt/set1-data-0"}, {Name: "/srv/set1-metadata-0"}},
+			replaceOSDs: []cephv1.OSDReplacement{{ID: 3, Name: "set1-data-0", Path: "/dev/sdb"}},
+		}
+		err := agent.wipeReplacedOSDs(context)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"dd if=/dev/zero of=/mnt/set1-data-0 bs=1M count=100 oflag=direct,dsync",
+			"dd if=/dev/zero of=/srv/set1-metadata-0 bs=1M count=100 oflag=direct,dsync",
+		}, commands)
+		assert.Equal(t, map[string]int{"/mnt/set1-data-0": 3}, agent.replacedDevices)
+	})
+}
+
+func TestReplacedOSDIDArgs(t *testing.T) {
+	agent := &OsdAgent{}
+	assert.Nil(t, agent.replacedOSDIDArgs(osdIDsFlag, []string{"/dev/sdb"}, "1"))
+
+	agent.replacedDevices = map[string]int{"/dev/sdb": 3, "/dev/sdc": 4}
+	assert.Equal(t, []string{"--osd-id", "3"}, agent.replacedOSDIDArgs(osdIDFlag, []string{"/dev/sdb"}, "1"))
+	assert.Equal(t, []string{"--osd-ids", "3", "4"}, agent.replacedOSDIDArgs(osdIDsFlag, []string{"/dev/sdb", "/dev/sdc"}, "1"))
+	// new devices are prepared together with the replaced devices
+	assert.Nil(t, agent.replacedOSDIDArgs(osdIDsFlag, []string{"/dev/sdb", "/dev/sdd"}, "1"))
+	// several osds per device
+	assert.Nil(t, agent.replacedOSDIDArgs(osdIDsFlag, []string{"/dev/sdb"}, "2"))
+	assert.Nil(t, agent.replacedOSDIDArgs(osdIDsFlag, []string{"/dev/sdd"}, "1"))
+}
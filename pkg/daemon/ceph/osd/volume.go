@@ -72,9 +72,10 @@ type osdInfoBlock struct {
 }
 
 type osdInfo struct {
-	Name string  `json:"name"`
-	Path string  `json:"path"`
-	Tags osdTags `json:"tags"`
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Devices []string `json:"devices"`
+	Tags    osdTags  `json:"tags"`
 	// "block" for bluestore
 	Type string `json:"type"`
 }
@@ -160,14 +161,9 @@ func (a *OsdAgent) configureCVDevices(context *clusterd.Context, devices *Device
 				// For block mode
 				block = fmt.Sprintf("/mnt/%s", a.nodeName)
 
-				// This is hard to determine a potential metadata device here
-				// Also, I don't think (leseb) this code we have run in this condition
-				// I tried several things:
-				//    * evict a node, osd moves, the prepare job was never relaunched ever because we check for the osd deployment and skip the prepare
-				//    * restarted the operator, again the prepare job was not re-run
-				//
-				// I'm leaving this code with an empty metadata device for now
-				metadataBlock, walBlock = "", ""
+				// The metadata and wal devices are the ones of the PVCs of the device set, mounted by
+				// the operator like the data device
+				metadataBlock, walBlock = a.pvcMetadataAndWalDevices()
 
 				rawOsds, err = GetCephVolumeRawOSDs(context, a.clusterInfo, a.clusterInfo.FSID, block, metadataBlock, walBlock, lvBackedPV, false)
 				if err != nil {
@@ -220,8 +216,8 @@ func (a *OsdAgent) configureCVDevices(context *clusterd.Context, devices *Device
 		return nil, errors.Wrap(err, "failed to determine which ceph-volume mode to use")
 	}
 
-	// If not raw mode we must execute a few LVM prerequisites
-	if !useRawMode {
+	// If not raw mode or if drive groups are configured we must execute a few LVM prerequisites
+	if !useRawMode || hasDriveGroupDevices(devices) {
 		err = lvmPreReq(context, a.pvcBacked, lvBackedPV)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to run lvm prerequisites")
@@ -350,6 +346,9 @@ func (a *OsdAgent) initializeBlockPVC(context *clusterd.Context, devices *Device
 				immediateExecuteArgs = append(immediateExecuteArgs, []string{crushDeviceClassFlag, crushDeviceClass}...)
 			}
 
+			// reuse the id of the osd replaced on the pvc
+			immediateExecuteArgs = append(immediateExecuteArgs, a.replacedOSDIDArgs(osdIDFlag, []string{device.Config.Name}, "1")...)
+
 			if isEncrypted {
 				immediateExecuteArgs = append(immediateExecuteArgs, encryptedFlag)
 			}
@@ -513,13 +512,14 @@ func (a *OsdAgent) useRawMode(context *clusterd.Context, pvcBacked bool) (bool,
 		useRawMode = false
 	}
 
-	// ceph-volume raw mode does not support more than one OSD per disk
+	// ceph-volume raw mode does not support more than one OSD per disk, the disks are partitioned
+	// for raw mode since pacific
 	osdsPerDeviceCountString := sanitizeOSDsPerDevice(a.storeConfig.OSDsPerDevice)
 	osdsPerDeviceCount, err := strconv.Atoi(osdsPerDeviceCountString)
 	if err != nil {
 		return false, errors.Wrapf(err, "failed to convert string %q to integer", osdsPerDeviceCountString)
 	}
-	if osdsPerDeviceCount > 1 {
+	if osdsPerDeviceCount > 1 && !a.clusterInfo.CephVersion.IsAtLeastPacific() {
 		logger.Debugf("won't use raw mode since osd per device is %d", osdsPerDeviceCount)
 		useRawMode = false
 	}
@@ -542,8 +542,31 @@ func (a *OsdAgent) initializeDevices(context *clusterd.Context, devices *DeviceO
 	lvmDevices := &DeviceOsdMapping{
 		Entries: map[string]*DeviceOsdIDEntry{},
 	}
+	driveGroupDevices := &DeviceOsdMapping{
+		Entries: map[string]*DeviceOsdIDEntry{},
+	}
+	partitionedDevices := &DeviceOsdMapping{
+		Entries: map[string]*DeviceOsdIDEntry{},
+	}
 
 	for name, device := range devices.Entries {
+		// the devices of the drive groups are prepared together by drive group
+		if device.Config.DriveGroup != "" {
+			driveGroupDevices.Entries[name] = device
+			continue
+		}
+
+		// the disks with more than one OSD are split in a partition per OSD for raw mode, the
+		// partitions not being affected by the phantom partitions described below
+		if allowRawMode && a.partitionForRawMode(device) {
+			partitionedDevices.Entries[name] = device
+			continue
+		}
+		if a.deviceOSDCount(device) > 1 {
+			lvmDevices.Entries[name] = device
+			continue
+		}
+
 		// Even if we can use raw mode, do NOT use raw mode on disks. Ceph bluestore disks can
 		// sometimes appear as though they have "phantom" Atari (AHDI) partitions created on them
 		// when they don't in reality. This is due to a series of bugs in the Linux kernel when it
@@ -553,14 +576,21 @@ func (a *OsdAgent) initializeDevices(context *clusterd.Context, devices *DeviceO
 		// which reports only the phantom partitions (and malformed OSD info) when they exist and
 		// ignores the original (correct) OSDs created on the raw disk.
 		// See: https://github.com/rook/rook/issues/7940
-		if device.DeviceInfo.Type != sys.DiskType && allowRawMode {
+		// The bcache devices are whole disks as well, while the dm-cache devices are not
+		// partitioned by the kernel and are prepared like partitions.
+		if device.DeviceInfo.Type != sys.DiskType && device.DeviceInfo.Type != sys.BcacheType && allowRawMode {
 			rawDevices.Entries[name] = device
 			continue
 		}
 		lvmDevices.Entries[name] = device
 	}
 
-	err := a.initializeDevicesRawMode(context, rawDevices)
+	err := a.initializeDevicesRawPartitions(context, partitionedDevices)
+	if err != nil {
+		return err
+	}
+
+	err = a.initializeDevicesRawMode(context, rawDevices)
 	if err != nil {
 		return err
 	}
@@ -570,6 +600,11 @@ func (a *OsdAgent) initializeDevices(context *clusterd.Context, devices *DeviceO
 		return err
 	}
 
+	err = a.initializeDriveGroups(context, driveGroupDevices)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -590,6 +625,7 @@ func (a *OsdAgent) initializeDevicesRawMode(context *clusterd.Context, devices *
 
 			// assign the device class specific to the device
 			immediateExecuteArgs = a.appendDeviceClassArg(device, immediateExecuteArgs)
+			immediateExecuteArgs = append(immediateExecuteArgs, a.replacedOSDIDArgs(osdIDFlag, []string{deviceArg}, "1")...)
 
 			// execute ceph-volume with the device
 			op, err := context.Executor.ExecuteCommandWithCombinedOutput(baseCommand, immediateExecuteArgs...)
@@ -614,6 +650,56 @@ func (a *OsdAgent) initializeDevicesRawMode(context *clusterd.Context, devices *
 	return nil
 }
 
+// deviceOSDCount returns the number of OSDs to prepare on the device
+func (a *OsdAgent) deviceOSDCount(device *DeviceOsdIDEntry) int {
+	if device.Config.OSDsPerDevice > 1 {
+		return device.Config.OSDsPerDevice
+	}
+	count, _ := strconv.Atoi(sanitizeOSDsPerDevice(a.storeConfig.OSDsPerDevice))
+	return count
+}
+
+// partitionForRawMode returns whether the new device is a whole disk with more than one OSD, without
+// metadata device, to be split in a partition per OSD
+func (a *OsdAgent) partitionForRawMode(device *DeviceOsdIDEntry) bool {
+	return device.Data == -1 && device.Metadata == nil && device.Config.MetadataDevice == "" &&
+		device.DeviceInfo != nil && device.DeviceInfo.Type == sys.DiskType && a.deviceOSDCount(device) > 1
+}
+
+// initializeDevicesRawPartitions creates a partition per OSD on the disks and prepares the partitions
+// in raw mode. The OSDs are found by ID on the partitions when they are activated, so their deployments
+// follow the partitions when the kernel names of the disks change across reboots. The partitions of a
+// disk are removed if they cannot all be prepared, so that the disk is found empty by the next prepare job.
+func (a *OsdAgent) initializeDevicesRawPartitions(context *clusterd.Context, devices *DeviceOsdMapping) error {
+	for name, device := range devices.Entries {
+		count := a.deviceOSDCount(device)
+		logger.Infof("creating %d partitions on device %q for raw mode osds", count, name)
+		names, err := sys.CreatePartitions(name, device.DeviceInfo.Size, count, context.Executor)
+		if err != nil {
+			return errors.Wrapf(err, "failed to partition device %q", name)
+		}
+		partitions := &DeviceOsdMapping{
+			Entries: map[string]*DeviceOsdIDEntry{},
+		}
+		for _, partition := range names {
+			config := device.Config
+			config.Name = partition
+			config.OSDsPerDevice = 1
+			partitions.Entries[partition] = &DeviceOsdIDEntry{Data: -1, Config: config}
+		}
+
+		if err := a.initializeDevicesRawMode(context, partitions); err != nil {
+			logger.Errorf("failed to prepare the partitions of device %q, removing them. the osds already prepared on the device will not start", name)
+			if removeErr := sys.RemovePartitions(name, context.Executor); removeErr != nil {
+				logger.Errorf("failed to remove the partitions of device %q. %v", name, removeErr)
+			}
+			return errors.Wrapf(err, "failed to prepare the partitions of device %q", name)
+		}
+	}
+
+	return nil
+}
+
 func (a *OsdAgent) initializeDevicesLVMMode(context *clusterd.Context, devices *DeviceOsdMapping) error {
 	storeFlag := "--bluestore"
 
@@ -702,6 +788,7 @@ func (a *OsdAgent) initializeDevicesLVMMode(context *clusterd.Context, devices *
 
 				// assign the device class specific to the device
 				immediateExecuteArgs = a.appendDeviceClassArg(device, immediateExecuteArgs)
+				immediateExecuteArgs = append(immediateExecuteArgs, a.replacedOSDIDArgs(osdIDsFlag, []string{path.Join("/dev", name)}, deviceOSDCount)...)
 
 				// Reporting
 				immediateReportArgs := append(immediateExecuteArgs, []string{
@@ -764,6 +851,7 @@ func (a *OsdAgent) initializeDevicesLVMMode(context *clusterd.Context, devices *
 			dbDeviceFlag,
 			mdPath,
 		}...)
+		mdArgs = append(mdArgs, a.replacedOSDIDArgs(osdIDsFlag, strings.Split(conf["devices"], " "), conf["osdsperdevice"])...)
 
 		// Reporting
 		reportArgs := append(mdArgs, []string{
@@ -870,6 +958,20 @@ func sanitizeOSDsPerDevice(count int) string {
 	return strconv.Itoa(count)
 }
 
+// pvcMetadataAndWalDevices returns the paths of the metadata (db) and wal devices of an OSD on PVC,
+// empty if the device set has no metadata or wal template
+func (a *OsdAgent) pvcMetadataAndWalDevices() (string, string) {
+	var metadataBlock, walBlock string
+	for _, device := range a.devices {
+		if strings.HasPrefix(device.Name, "/srv") {
+			metadataBlock = device.Name
+		} else if strings.HasPrefix(device.Name, "/wal") {
+			walBlock = device.Name
+		}
+	}
+	return metadataBlock, walBlock
+}
+
 // GetCephVolumeLVMOSDs list OSD prepared with lvm mode
 func GetCephVolumeLVMOSDs(context *clusterd.Context, clusterInfo *client.ClusterInfo, cephfsid, lv string, skipLVRelease, lvBackedPV bool) ([]oposd.OSDInfo, error) {
 	// lv can be a block device if raw mode is used
@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -1255,6 +1256,18 @@ func TestSanitizeOSDsPerDevice(t *testing.T) {
 	assert.Equal(t, "2", sanitizeOSDsPerDevice(2))
 }
 
+func TestPVCMetadataAndWalDevices(t *testing.T) {
+	a := &OsdAgent{devices: []DesiredDevice{{Name: "/mnt/set0-data-0"}}}
+	metadataBlock, walBlock := a.pvcMetadataAndWalDevices()
+	assert.Equal(t, "", metadataBlock)
+	assert.Equal(t, "", walBlock)
+
+	a.devices = append(a.devices, DesiredDevice{Name: "/srv/set0-metadata-0"}, DesiredDevice{Name: "/wal/set0-wal-0"})
+	metadataBlock, walBlock = a.pvcMetadataAndWalDevices()
+	assert.Equal(t, "/srv/set0-metadata-0", metadataBlock)
+	assert.Equal(t, "/wal/set0-wal-0", walBlock)
+}
+
 func TestGetDatabaseSize(t *testing.T) {
 	assert.Equal(t, 0, getDatabaseSize(0, 0))
 	assert.Equal(t, 2048, getDatabaseSize(4096, 2048))
@@ -1416,6 +1429,64 @@ func TestInitializeBlockWithMD(t *testing.T) {
 
 }
 
+func TestInitializeDevicesRawPartitions(t *testing.T) {
+	devices := &DeviceOsdMapping{
+		Entries: map[string]*DeviceOsdIDEntry{
+			"nvme0n1": {Data: -1, Config: DesiredDevice{Name: "nvme0n1", OSDsPerDevice: 2, DeviceClass: "fast"}, DeviceInfo: &sys.LocalDisk{Type: sys.DiskType, Size: 100 * 1024 * 1024 * 1024}},
+			"vdb1":    {Data: -1, Config: DesiredDevice{Name: "vdb1"}, DeviceInfo: &sys.LocalDisk{Type: sys.PartType}},
+		},
+	}
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommand: func(command string, args ...string) error {
+			commands = append(commands, command+" "+strings.Join(args, " "))
+			return nil
+		},
+		MockExecuteCommandWithCombinedOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, command+" "+strings.Join(args, " "))
+			return "", nil
+		},
+	}
+	a := &OsdAgent{clusterInfo: &cephclient.ClusterInfo{CephVersion: cephver.CephVersion{Major: 16, Minor: 2, Extra: 6}}, nodeName: "node1"}
+	context := &clusterd.Context{Executor: executor}
+
+	err := a.initializeDevices(context, devices, true)
+	assert.NoError(t, err)
+	// the disk is split in a partition per osd, each partition being prepared in raw mode
+	assert.Contains(t, commands, "sgdisk --new=1:0:+51199M --change-name=1:ceph-osd-1 --new=2:0:0 --change-name=2:ceph-osd-2 /dev/nvme0n1")
+	assert.Contains(t, commands, "stdbuf -oL ceph-volume raw prepare --bluestore --data /dev/nvme0n1p1 --crush-device-class fast")
+	assert.Contains(t, commands, "stdbuf -oL ceph-volume raw prepare --bluestore --data /dev/nvme0n1p2 --crush-device-class fast")
+	assert.Contains(t, commands, "stdbuf -oL ceph-volume raw prepare --bluestore --data /dev/vdb1")
+	assert.Equal(t, 5, len(commands))
+
+	// the partitions are removed when one of them cannot be prepared
+	commands = []string{}
+	executor.MockExecuteCommandWithCombinedOutput = func(command string, args ...string) (string, error) {
+		commands = append(commands, command+" "+strings.Join(args, " "))
+		if strings.Contains(strings.Join(args, " "), "/dev/nvme0n1p2") {
+			return "", errors.New("mock failure")
+		}
+		return "", nil
+	}
+	devices = &DeviceOsdMapping{
+		Entries: map[string]*DeviceOsdIDEntry{
+			"nvme0n1": {Data: -1, Config: DesiredDevice{Name: "nvme0n1", OSDsPerDevice: 2}, DeviceInfo: &sys.LocalDisk{Type: sys.DiskType, Size: 100 * 1024 * 1024 * 1024}},
+		},
+	}
+	err = a.initializeDevices(context, devices, true)
+	assert.Error(t, err)
+	assert.Contains(t, commands, "sgdisk --zap-all /dev/nvme0n1")
+
+	// a disk with a metadata device is not partitioned
+	assert.False(t, a.partitionForRawMode(&DeviceOsdIDEntry{Data: -1, Config: DesiredDevice{OSDsPerDevice: 2, MetadataDevice: "sdd"}, DeviceInfo: &sys.LocalDisk{Type: sys.DiskType}}))
+	// nor a disk with a single osd
+	assert.False(t, a.partitionForRawMode(&DeviceOsdIDEntry{Data: -1, DeviceInfo: &sys.LocalDisk{Type: sys.DiskType}}))
+	// the osds per device of the node apply to all its disks
+	a.storeConfig.OSDsPerDevice = 3
+	assert.True(t, a.partitionForRawMode(&DeviceOsdIDEntry{Data: -1, DeviceInfo: &sys.LocalDisk{Type: sys.DiskType}}))
+	assert.False(t, a.partitionForRawMode(&DeviceOsdIDEntry{Data: -1, DeviceInfo: &sys.LocalDisk{Type: sys.PartType}}))
+}
+
 func TestUseRawMode(t *testing.T) {
 	type fields struct {
 		clusterInfo    *cephclient.ClusterInfo
@@ -1445,6 +1516,7 @@ func TestUseRawMode(t *testing.T) {
 		{"non-pvc with lvm octopus complex scenario not supported: encrypted", fields{&cephclient.ClusterInfo{CephVersion: cephver.CephVersion{Major: 15, Minor: 2, Extra: 9}}, "", config.StoreConfig{EncryptedDevice: true}, false}, args{&clusterd.Context{}, false}, false, false},
 		{"non-pvc with lvm nautilus complex scenario not supported: osd per device > 1", fields{&cephclient.ClusterInfo{CephVersion: cephver.CephVersion{Major: 14, Minor: 2, Extra: 14}}, "", config.StoreConfig{OSDsPerDevice: 2}, false}, args{&clusterd.Context{}, false}, false, false},
 		{"non-pvc with lvm octopus complex scenario not supported: osd per device > 1", fields{&cephclient.ClusterInfo{CephVersion: cephver.CephVersion{Major: 15, Minor: 2, Extra: 9}}, "", config.StoreConfig{OSDsPerDevice: 2}, false}, args{&clusterd.Context{}, false}, false, false},
+		{"non-pvc with raw pacific osd per device > 1", fields{&cephclient.ClusterInfo{CephVersion: cephver.CephVersion{Major: 16, Minor: 2, Extra: 1}}, "", config.StoreConfig{OSDsPerDevice: 2}, false}, args{&clusterd.Context{}, false}, true, false},
 		{"non-pvc with lvm nautilus complex scenario not supported: metadata dev", fields{&cephclient.ClusterInfo{CephVersion: cephver.CephVersion{Major: 14, Minor: 2, Extra: 14}}, "/dev/sdb", config.StoreConfig{}, false}, args{&clusterd.Context{}, false}, false, false},
 		{"non-pvc with lvm octopus complex scenario not supported: metadata dev", fields{&cephclient.ClusterInfo{CephVersion: cephver.CephVersion{Major: 15, Minor: 2, Extra: 9}}, "/dev/sdb", config.StoreConfig{}, false}, args{&clusterd.Context{}, false}, false, false},
 		{"non-pvc with lvm pacific complex scenario not supported: metadata dev", fields{&cephclient.ClusterInfo{CephVersion: cephver.CephVersion{Major: 16, Minor: 2, Extra: 1}}, "/dev/sdb", config.StoreConfig{}, false}, args{&clusterd.Context{}, false}, false, false},
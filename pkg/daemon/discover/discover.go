@@ -32,6 +32,7 @@ import (
 	"time"
 
 	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/sys"
@@ -62,6 +63,13 @@ var (
 	cm              *v1.ConfigMap
 	udevEventPeriod = time.Duration(5) * time.Second
 	useCVInventory  bool
+	podName         string
+	// the provisioning lease of the node is held while probing the devices, and the probe is retried later if
+	// a prepare job holds the lease
+	probeLeaseDuration = time.Minute
+	probeRetryInterval = 30 * time.Second
+
+	errNodeProvisioning = errors.New("the node is being provisioned")
 )
 
 // CephVolumeInventory is the Go struct representation of the json output
@@ -83,12 +91,17 @@ func Run(context *clusterd.Context, probeInterval time.Duration, useCV bool) err
 	nodeName = os.Getenv(k8sutil.NodeNameEnvVar)
 	namespace = os.Getenv(k8sutil.PodNamespaceEnvVar)
 	cmName = k8sutil.TruncateNodeName(LocalDiskCMName, nodeName)
+	podName = os.Getenv(k8sutil.PodNameEnvVar)
 	useCVInventory = useCV
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc, syscall.SIGTERM)
 
+	// retryProbe is set when a probe is postponed because a prepare job holds the provisioning lease of the node
+	var retryProbe <-chan time.Time
 	err := updateDeviceCM(context)
-	if err != nil {
+	if err == errNodeProvisioning {
+		retryProbe = time.After(probeRetryInterval)
+	} else if err != nil {
 		logger.Infof("failed to update device configmap: %v", err)
 		return err
 	}
@@ -101,13 +114,24 @@ func Run(context *clusterd.Context, probeInterval time.Duration, useCV bool) err
 			logger.Infof("shutdown signal received, exiting...")
 			return nil
 		case <-time.After(probeInterval):
-			if err := updateDeviceCM(context); err != nil {
+			if err := updateDeviceCM(context); err == errNodeProvisioning {
+				retryProbe = time.After(probeRetryInterval)
+			} else if err != nil {
 				logger.Errorf("failed to update device configmap during probe interval. %v", err)
 			}
+		case <-retryProbe:
+			retryProbe = nil
+			if err := updateDeviceCM(context); err == errNodeProvisioning {
+				retryProbe = time.After(probeRetryInterval)
+			} else if err != nil {
+				logger.Errorf("failed to update device configmap after the provisioning of the node. %v", err)
+			}
 		case _, ok := <-udevEvents:
 			if ok {
 				logger.Info("trigger probe from udev event")
-				if err := updateDeviceCM(context); err != nil {
+				if err := updateDeviceCM(context); err == errNodeProvisioning {
+					retryProbe = time.After(probeRetryInterval)
+				} else if err != nil {
 					logger.Errorf("failed to update device configmap triggered from udev event. %v", err)
 				}
 			} else {
@@ -324,6 +348,22 @@ func DeviceListsEqual(old, new string) (bool, error) {
 
 func updateDeviceCM(clusterdContext *clusterd.Context) error {
 	ctx := context.TODO()
+	// the devices are not probed while a prepare job enumerates or prepares them
+	leaseName := k8sutil.ProvisioningLeaseName(nodeName)
+	acquired, holder, err := k8sutil.TryAcquireLease(ctx, clusterdContext.Clientset, namespace, leaseName, podName, probeLeaseDuration)
+	if err != nil {
+		logger.Warningf("failed to acquire the provisioning lease %q, probing the devices anyway. %v", leaseName, err)
+	} else if !acquired {
+		logger.Infof("node %q is being provisioned by %q, probing the devices later", nodeName, holder)
+		return errNodeProvisioning
+	} else {
+		defer func() {
+			if err := k8sutil.ReleaseLease(ctx, clusterdContext.Clientset, namespace, leaseName, podName); err != nil {
+				logger.Warningf("failed to release the provisioning lease %q. %v", leaseName, err)
+			}
+		}()
+	}
+
 	logger.Infof("updating device configmap")
 	devices, err := probeDevices(clusterdContext)
 	if err != nil {
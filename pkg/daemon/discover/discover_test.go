@@ -18,14 +18,20 @@ limitations under the License.
 package discover
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/rook/rook/pkg/util/sys"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 const (
@@ -94,6 +100,41 @@ func TestProbeDevices(t *testing.T) {
 	assert.Equal(t, "ext2", devices[0].Filesystem)
 }
 
+func TestUpdateDeviceCMWithProvisioningLease(t *testing.T) {
+	ctx := context.TODO()
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return "", nil
+		},
+	}
+	clientset := fake.NewSimpleClientset()
+	clusterdContext := &clusterd.Context{Executor: executor, Clientset: clientset}
+	nodeName = "node1"
+	namespace = "rook-ceph"
+	podName = "rook-discover-abcde"
+	cmName = k8sutil.TruncateNodeName(LocalDiskCMName, nodeName)
+	cm = nil
+	defer func() { cm = nil }()
+
+	// the devices are not probed while a prepare job holds the lease of the node
+	leaseName := k8sutil.ProvisioningLeaseName(nodeName)
+	acquired, _, err := k8sutil.TryAcquireLease(ctx, clientset, namespace, leaseName, "rook-ceph-osd-prepare-node1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	assert.Equal(t, errNodeProvisioning, updateDeviceCM(clusterdContext))
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Get(ctx, cmName, metav1.GetOptions{})
+	assert.Error(t, err)
+
+	// the devices are probed once the lease is released, and the lease is released after the probe
+	require.NoError(t, k8sutil.ReleaseLease(ctx, clientset, namespace, leaseName, "rook-ceph-osd-prepare-node1"))
+	assert.NoError(t, updateDeviceCM(clusterdContext))
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Get(ctx, cmName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	holder, err := k8sutil.GetLeaseHolder(ctx, clientset, namespace, leaseName)
+	require.NoError(t, err)
+	assert.Empty(t, holder)
+}
+
 func TestMatchUdevMonitorFiltering(t *testing.T) {
 	// f <- matching function as configured
 	f := func(text string) bool {
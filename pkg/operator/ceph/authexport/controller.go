@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package authexport to export the credentials of the Ceph clients to the secrets of the CephAuthExport CRs
+package authexport
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-auth-export-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephAuthExportKind = reflect.TypeOf(cephv1.CephAuthExport{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephAuthExportKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// waitForRequeueIfExportNotAllowed waits for the target namespace to allow the export
+var waitForRequeueIfExportNotAllowed = reconcile.Result{Requeue: true, RequeueAfter: time.Minute}
+
+// ReconcileCephAuthExport reconciles a CephAuthExport object
+type ReconcileCephAuthExport struct {
+	client           client.Client
+	context          *clusterd.Context
+	opManagerContext context.Context
+}
+
+// Add creates a new CephAuthExport Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephAuthExport{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephAuthExport CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephAuthExport{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	// Build Handler function to return the list of ceph auth exports
+	// This is used by the watchers below
+	handlerFunc, err := opcontroller.ObjectToCRMapper(mgr.GetClient(), &cephv1.CephAuthExportList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	// Watch for ConfigMap "rook-ceph-mon-endpoints" update and reconcile, which will update the mon endpoints of the secrets
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: corev1.SchemeGroupVersion.String()}}}, handler.EnqueueRequestsFromMapFunc(handlerFunc), mon.PredicateMonEndpointChanges())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephAuthExport object and makes changes based on the state read
+// and what is in the CephAuthExport.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephAuthExport) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephAuthExport) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephAuthExport instance
+	authExport := &cephv1.CephAuthExport{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, authExport)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("cephAuthExport resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get cephAuthExport")
+	}
+
+	// Set a finalizer so we can delete the secret, which can't be owned by the CR in another namespace
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, authExport)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// DELETE: the CR was deleted. The secret is deleted even if the cluster is gone.
+	if !authExport.GetDeletionTimestamp().IsZero() {
+		logger.Infof("deleting the secrets of ceph auth export %q", authExport.Name)
+		if err := r.deleteSecrets(authExport, nil); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to delete the secrets of ceph auth export %q", authExport.Name)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, authExport)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// The CR was just created, initializing status fields
+	if authExport.Status == nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionProgressing, "")
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, _, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		return reconcileResponse, nil
+	}
+
+	// an invalid export is not retried until its spec is updated
+	if err := validateAuthExport(authExport); err != nil {
+		logger.Errorf("invalid ceph auth export %q. %v", authExport.Name, err)
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	// the namespace must allow the export, which is checked again until it does
+	if err := r.checkTargetNamespace(authExport); err != nil {
+		logger.Errorf("ceph auth export %q is not allowed. %v", authExport.Name, err)
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, err.Error())
+		return waitForRequeueIfExportNotAllowed, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	clusterInfo, _, _, err := mon.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+	clusterInfo.Context = r.opManagerContext
+
+	err = r.exportSecret(clusterInfo, authExport)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			logger.Info(opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
+		}
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, err.Error())
+		return reconcile.Result{}, errors.Wrapf(err, "failed to export the credentials of ceph auth export %q", authExport.Name)
+	}
+
+	// the secret of a previous target is removed once the new one exists
+	target := types.NamespacedName{Namespace: authExport.Spec.TargetNamespace, Name: secretName(authExport)}
+	if err := r.deleteSecrets(authExport, &target); err != nil {
+		logger.Warningf("failed to delete the previous secrets of ceph auth export %q. %v", authExport.Name, err)
+	}
+
+	// Success! Let's update the status
+	r.updateStatus(request.NamespacedName, cephv1.ConditionReady, "")
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileCephAuthExport) updateStatus(name types.NamespacedName, phase cephv1.ConditionType, message string) {
+	authExport := &cephv1.CephAuthExport{}
+	if err := r.client.Get(r.opManagerContext, name, authExport); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephAuthExport resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph auth export %q to update status to %q. %v", name, phase, err)
+		return
+	}
+	if authExport.Status == nil {
+		authExport.Status = &cephv1.CephAuthExportStatus{}
+	}
+
+	authExport.Status.Phase = phase
+	authExport.Status.Message = message
+	authExport.Status.Info = nil
+	if phase == cephv1.ConditionReady {
+		authExport.Status.Info = map[string]string{
+			"secretName":      secretName(authExport),
+			"targetNamespace": authExport.Spec.TargetNamespace,
+		}
+	}
+	if err := reporting.UpdateStatus(r.client, authExport); err != nil {
+		logger.Errorf("failed to set ceph auth export %q status to %q. %v", name, phase, err)
+		return
+	}
+	logger.Debugf("ceph auth export %q status updated to %q", name, phase)
+}
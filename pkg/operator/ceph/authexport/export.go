@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authexport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// AllowedLabel is the label of the namespaces that accept the secrets of the exports of the cluster
+	// in the namespace of the label value
+	AllowedLabel = "ceph.rook.io/auth-export"
+
+	appName = "rook-ceph-auth-export"
+	// the labels of the secrets with the export they belong to, since a secret in another namespace
+	// can't have an owner reference to the CR
+	exportNamespaceLabel = "ceph.rook.io/auth-export-namespace"
+	exportNameLabel      = "ceph.rook.io/auth-export-name"
+
+	// the keys of the secret. userID and userKey are the keys expected by the ceph CSI driver.
+	userIDKey     = "userID"
+	userKeyKey    = "userKey"
+	fsidKey       = "fsid"
+	monHostKey    = "monHost"
+	configKey     = "ceph.conf"
+	keyringKey    = "keyring"
+	secretNameFmt = "rook-ceph-auth-%s"
+)
+
+func validateAuthExport(authExport *cephv1.CephAuthExport) error {
+	spec := authExport.Spec
+	if spec.User == "" {
+		return errors.New("missing user")
+	}
+	if strings.HasPrefix(spec.User, "client.") {
+		return errors.Errorf("user %q must be set without the \"client.\" prefix", spec.User)
+	}
+	if client.IsReservedName(spec.User) {
+		return errors.Errorf("the credentials of the reserved user %q can't be exported", spec.User)
+	}
+	if spec.TargetNamespace == "" {
+		return errors.New("missing target namespace")
+	}
+	if spec.TargetNamespace == authExport.Namespace {
+		return errors.New("the target namespace must differ from the namespace of the cluster, use a CephClient instead")
+	}
+	if errs := validation.IsDNS1123Subdomain(secretName(authExport)); len(errs) > 0 {
+		return errors.Errorf("invalid secret name %q. %s", secretName(authExport), strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func secretName(authExport *cephv1.CephAuthExport) string {
+	if authExport.Spec.SecretName != "" {
+		return authExport.Spec.SecretName
+	}
+	return fmt.Sprintf(secretNameFmt, authExport.Name)
+}
+
+// checkTargetNamespace verifies that the target namespace accepts the secrets of the cluster. The export
+// doesn't give the users of the cluster namespace the rights to write to any namespace of the operator.
+func (r *ReconcileCephAuthExport) checkTargetNamespace(authExport *cephv1.CephAuthExport) error {
+	ns, err := r.context.Clientset.CoreV1().Namespaces().Get(r.opManagerContext, authExport.Spec.TargetNamespace, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get target namespace %q", authExport.Spec.TargetNamespace)
+	}
+	if ns.Labels[AllowedLabel] != authExport.Namespace {
+		return errors.Errorf("target namespace %q must have the label \"%s=%s\" to accept the secret", ns.Name, AllowedLabel, authExport.Namespace)
+	}
+	return nil
+}
+
+// exportSecret creates or updates the secret with the key of the user and the mon endpoints
+func (r *ReconcileCephAuthExport) exportSecret(clusterInfo *cephclient.ClusterInfo, authExport *cephv1.CephAuthExport) error {
+	entity := fmt.Sprintf("client.%s", authExport.Spec.User)
+	key, err := cephclient.AuthGetKey(r.context, clusterInfo, entity)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the key of user %q", entity)
+	}
+
+	_, monHosts := cephclient.PopulateMonHostMembers(clusterInfo.Monitors)
+	monHost := strings.Join(monHosts, ",")
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName(authExport),
+			Namespace: authExport.Spec.TargetNamespace,
+			Labels: map[string]string{
+				k8sutil.AppAttr:      appName,
+				exportNamespaceLabel: authExport.Namespace,
+				exportNameLabel:      authExport.Name,
+			},
+		},
+		StringData: map[string]string{
+			userIDKey:  authExport.Spec.User,
+			userKeyKey: key,
+			fsidKey:    clusterInfo.FSID,
+			monHostKey: monHost,
+			configKey:  fmt.Sprintf("[global]\nfsid = %s\nmon_host = %s\n", clusterInfo.FSID, monHost),
+			keyringKey: fmt.Sprintf("[%s]\nkey = %s\n", entity, key),
+		},
+		Type: k8sutil.RookType,
+	}
+
+	secrets := r.context.Clientset.CoreV1().Secrets(secret.Namespace)
+	existing, err := secrets.Get(r.opManagerContext, secret.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get secret %q", secret.Name)
+		}
+		if _, err := secrets.Create(r.opManagerContext, secret, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create secret %q in namespace %q", secret.Name, secret.Namespace)
+		}
+		logger.Infof("exported the credentials of user %q to secret %q in namespace %q", entity, secret.Name, secret.Namespace)
+		return nil
+	}
+
+	// a secret of the same name that is not one of the export is not overwritten
+	if existing.Labels[exportNamespaceLabel] != authExport.Namespace || existing.Labels[exportNameLabel] != authExport.Name {
+		return errors.Errorf("secret %q in namespace %q already exists and does not belong to the export", secret.Name, secret.Namespace)
+	}
+	if _, err := secrets.Update(r.opManagerContext, secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update secret %q in namespace %q", secret.Name, secret.Namespace)
+	}
+	logger.Debugf("updated secret %q in namespace %q", secret.Name, secret.Namespace)
+	return nil
+}
+
+// deleteSecrets deletes the secrets of the export in all the namespaces, except the one to keep if any
+func (r *ReconcileCephAuthExport) deleteSecrets(authExport *cephv1.CephAuthExport, keep *types.NamespacedName) error {
+	selector := fmt.Sprintf("%s=%s,%s=%s", exportNamespaceLabel, authExport.Namespace, exportNameLabel, authExport.Name)
+	secrets, err := r.context.Clientset.CoreV1().Secrets(metav1.NamespaceAll).List(r.opManagerContext, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the secrets of the export")
+	}
+
+	for _, secret := range secrets.Items {
+		if keep != nil && secret.Namespace == keep.Namespace && secret.Name == keep.Name {
+			continue
+		}
+		err := r.context.Clientset.CoreV1().Secrets(secret.Namespace).Delete(r.opManagerContext, secret.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete secret %q in namespace %q", secret.Name, secret.Namespace)
+		}
+		logger.Infof("deleted secret %q in namespace %q of ceph auth export %q", secret.Name, secret.Namespace, authExport.Name)
+	}
+	return nil
+}
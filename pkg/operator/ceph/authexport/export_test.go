@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authexport
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestValidateAuthExport(t *testing.T) {
+	authExport := &cephv1.CephAuthExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "rook-ceph"},
+		Spec:       cephv1.AuthExportSpec{User: "vm", TargetNamespace: "vms"},
+	}
+	assert.NoError(t, validateAuthExport(authExport))
+	assert.Equal(t, "rook-ceph-auth-vm", secretName(authExport))
+
+	authExport.Spec.SecretName = "ceph"
+	assert.NoError(t, validateAuthExport(authExport))
+	assert.Equal(t, "ceph", secretName(authExport))
+
+	authExport.Spec.SecretName = "Ceph_Secret"
+	assert.Error(t, validateAuthExport(authExport))
+	authExport.Spec.SecretName = ""
+
+	// the admin and the users of the daemons are not exported
+	for _, user := range []string{"", "admin", "client.vm", "rgw.store", "bootstrap-osd"} {
+		authExport.Spec.User = user
+		assert.Error(t, validateAuthExport(authExport), user)
+	}
+	authExport.Spec.User = "vm"
+
+	authExport.Spec.TargetNamespace = ""
+	assert.Error(t, validateAuthExport(authExport))
+	authExport.Spec.TargetNamespace = "rook-ceph"
+	assert.Error(t, validateAuthExport(authExport))
+}
+
+func TestExportSecret(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testop.New(t, 1)
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "auth" && args[1] == "get-key" && args[2] == "client.vm" {
+				return `{"key":"AQCvzWBeIV9lFRAAninzm+8XFxbSfTiPwoX50g=="}`, nil
+			}
+			return "", nil
+		},
+	}
+	r := &ReconcileCephAuthExport{context: &clusterd.Context{Clientset: clientset, Executor: executor}, opManagerContext: ctx}
+	clusterInfo := clienttest.CreateTestClusterInfo(1)
+	authExport := &cephv1.CephAuthExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "rook-ceph"},
+		Spec:       cephv1.AuthExportSpec{User: "vm", TargetNamespace: "vms"},
+	}
+
+	t.Run("the namespace must allow the export", func(t *testing.T) {
+		assert.Error(t, r.checkTargetNamespace(authExport))
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "vms", Labels: map[string]string{AllowedLabel: "other-cluster"}}}
+		_, err := clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+		require.NoError(t, err)
+		assert.Error(t, r.checkTargetNamespace(authExport))
+
+		ns.Labels[AllowedLabel] = "rook-ceph"
+		_, err = clientset.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		assert.NoError(t, r.checkTargetNamespace(authExport))
+	})
+
+	t.Run("export the key and the mon endpoints", func(t *testing.T) {
+		require.NoError(t, r.exportSecret(clusterInfo, authExport))
+		secret, err := clientset.CoreV1().Secrets("vms").Get(ctx, "rook-ceph-auth-vm", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "vm", secret.StringData[userIDKey])
+		assert.Equal(t, "AQCvzWBeIV9lFRAAninzm+8XFxbSfTiPwoX50g==", secret.StringData[userKeyKey])
+		assert.Equal(t, "12345", secret.StringData[fsidKey])
+		assert.Equal(t, "[v2:1.2.3.1:3300,v1:1.2.3.1:6789]", secret.StringData[monHostKey])
+		assert.Equal(t, "[global]\nfsid = 12345\nmon_host = [v2:1.2.3.1:3300,v1:1.2.3.1:6789]\n", secret.StringData[configKey])
+		assert.Equal(t, "[client.vm]\nkey = AQCvzWBeIV9lFRAAninzm+8XFxbSfTiPwoX50g==\n", secret.StringData[keyringKey])
+
+		// updated with the new mons
+		clusterInfo.Monitors["b"] = &cephclient.MonInfo{Name: "b", Endpoint: "1.2.3.2:3300"}
+		require.NoError(t, r.exportSecret(clusterInfo, authExport))
+		secret, err = clientset.CoreV1().Secrets("vms").Get(ctx, "rook-ceph-auth-vm", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, secret.StringData[monHostKey], "[v2:1.2.3.2:3300]")
+	})
+
+	t.Run("a secret of another owner is not overwritten", func(t *testing.T) {
+		other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app-secret", Namespace: "vms"}}
+		_, err := clientset.CoreV1().Secrets("vms").Create(ctx, other, metav1.CreateOptions{})
+		require.NoError(t, err)
+		otherExport := authExport.DeepCopy()
+		otherExport.Spec.SecretName = "app-secret"
+		assert.Error(t, r.exportSecret(clusterInfo, otherExport))
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		unknown := authExport.DeepCopy()
+		unknown.Spec.User = "unknown"
+		assert.Error(t, r.exportSecret(clusterInfo, unknown))
+	})
+
+	t.Run("delete the secrets of the previous targets", func(t *testing.T) {
+		moved := authExport.DeepCopy()
+		moved.Spec.SecretName = "ceph"
+		require.NoError(t, r.exportSecret(clusterInfo, moved))
+		require.NoError(t, r.deleteSecrets(moved, &types.NamespacedName{Namespace: "vms", Name: "ceph"}))
+		_, err := clientset.CoreV1().Secrets("vms").Get(ctx, "rook-ceph-auth-vm", metav1.GetOptions{})
+		assert.Error(t, err)
+		_, err = clientset.CoreV1().Secrets("vms").Get(ctx, "ceph", metav1.GetOptions{})
+		assert.NoError(t, err)
+		// the secrets that don't belong to the export are kept
+		_, err = clientset.CoreV1().Secrets("vms").Get(ctx, "app-secret", metav1.GetOptions{})
+		assert.NoError(t, err)
+
+		// all the secrets are deleted with the export
+		require.NoError(t, r.deleteSecrets(moved, nil))
+		_, err = clientset.CoreV1().Secrets("vms").Get(ctx, "ceph", metav1.GetOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	defaultRadosMode        = "write"
+	defaultDurationSeconds  = 60
+	defaultObjectSize       = "4Mi"
+	defaultConcurrency      = 16
+	defaultRBDIOType        = "write"
+	defaultRBDIOPattern     = "rand"
+	defaultRBDIOSize        = "4Ki"
+	defaultRBDIOTotal       = "1Gi"
+	defaultRBDIOThreads     = 16
+	benchmarkJobGracePeriod = 10 * time.Minute
+	rbdBenchmarkTimeout     = time.Hour
+
+	mib = 1024 * 1024
+)
+
+// the summary line of "rbd bench", e.g. "elapsed: 12   ops: 262144   ops/sec: 20886.6   bytes/sec: 82 MiB/s".
+// Older releases report the bytes per second without a unit.
+var rbdBenchSummaryRegexp = regexp.MustCompile(`ops/sec:\s*([0-9.]+)\s+bytes/sec:\s*([0-9.]+)\s*([KMGTPE]?i?B/s)?`)
+
+var byteUnits = map[string]float64{
+	"":      1,
+	"B/s":   1,
+	"KiB/s": 1 << 10,
+	"MiB/s": 1 << 20,
+	"GiB/s": 1 << 30,
+	"TiB/s": 1 << 40,
+	"PiB/s": 1 << 50,
+	"EiB/s": 1 << 60,
+}
+
+// radosBenchScript returns the script of a rados benchmark and the timeout of its job. The reads of
+// the "seq" and "rand" modes need the objects of a previous write, which are removed afterwards.
+func radosBenchScript(name string, spec *cephv1.RadosBenchmarkSpec) (string, time.Duration) {
+	mode := spec.Mode
+	if mode == "" {
+		mode = defaultRadosMode
+	}
+	duration := spec.DurationSeconds
+	if duration == 0 {
+		duration = defaultDurationSeconds
+	}
+	objectSize := quantityOrDefault(spec.ObjectSize, defaultObjectSize)
+	concurrency := spec.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultConcurrency
+	}
+
+	pool := shellQuote(spec.Pool)
+	run := shellQuote(runName(name))
+	bench := fmt.Sprintf("rados -p %s bench %d %%s -t %d --run-name %s", pool, duration, concurrency, run)
+	lines := []string{
+		"set -e",
+		fmt.Sprintf("cleanup() { rados -p %s cleanup --run-name %s >/dev/null; }", pool, run),
+		"trap cleanup EXIT",
+	}
+	phases := 1
+	if mode != defaultRadosMode {
+		lines = append(lines, fmt.Sprintf(bench, "write")+fmt.Sprintf(" -b %d --no-cleanup >/dev/null", objectSize))
+		lines = append(lines, fmt.Sprintf(bench, mode)+" --format json")
+		phases = 2
+	} else {
+		lines = append(lines, fmt.Sprintf(bench, "write")+fmt.Sprintf(" -b %d --format json", objectSize))
+	}
+
+	return strings.Join(lines, "\n"), time.Duration(phases*duration)*time.Second + benchmarkJobGracePeriod
+}
+
+// rbdBenchScript returns the script of an rbd benchmark on a temporary image and the timeout of its job.
+// The image is written first when the benchmark reads so that the reads are not served from a sparse image.
+func rbdBenchScript(name string, spec *cephv1.RBDBenchmarkSpec) (string, time.Duration) {
+	ioType := spec.IOType
+	if ioType == "" {
+		ioType = defaultRBDIOType
+	}
+	ioPattern := spec.IOPattern
+	if ioPattern == "" {
+		ioPattern = defaultRBDIOPattern
+	}
+	ioSize := quantityOrDefault(spec.IOSize, defaultRBDIOSize)
+	ioTotal := quantityOrDefault(spec.IOTotal, defaultRBDIOTotal)
+	ioThreads := spec.IOThreads
+	if ioThreads == 0 {
+		ioThreads = defaultRBDIOThreads
+	}
+
+	image := shellQuote(fmt.Sprintf("%s/%s", spec.Pool, runName(name)))
+	bench := fmt.Sprintf("rbd bench --io-type %%s --io-size %d --io-threads %d --io-total %d --io-pattern %%s %s", ioSize, ioThreads, ioTotal, image)
+	lines := []string{
+		"set -e",
+		// an image left by an interrupted benchmark is removed first
+		fmt.Sprintf("rbd rm --no-progress %s >/dev/null 2>&1 || true", image),
+		fmt.Sprintf("rbd create --size %dM %s", (ioTotal+mib-1)/mib, image),
+		fmt.Sprintf("cleanup() { rbd rm --no-progress %s >/dev/null; }", image),
+		"trap cleanup EXIT",
+	}
+	if ioType != defaultRBDIOType {
+		lines = append(lines, fmt.Sprintf(bench, "write", "seq")+" >/dev/null")
+	}
+	lines = append(lines, fmt.Sprintf(bench, ioType, ioPattern))
+
+	return strings.Join(lines, "\n"), rbdBenchmarkTimeout
+}
+
+// parseRadosBenchResults parses the json output of "rados bench", whose values are reported either as
+// strings or as numbers depending on the ceph release
+func parseRadosBenchResults(output string) (*cephv1.BenchmarkResults, error) {
+	start := strings.Index(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, errors.Errorf("no results in the output of rados bench %q", output)
+	}
+	var summary map[string]interface{}
+	if err := json.Unmarshal([]byte(output[start:end+1]), &summary); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the results of rados bench %q", output)
+	}
+
+	bandwidth, err := jsonFloat(summary, "bandwidth")
+	if err != nil {
+		return nil, err
+	}
+	iops, err := jsonFloat(summary, "average_iops")
+	if err != nil {
+		return nil, err
+	}
+	latency, err := jsonFloat(summary, "average_latency")
+	if err != nil {
+		return nil, err
+	}
+	return &cephv1.BenchmarkResults{
+		Bandwidth:      formatBandwidth(bandwidth),
+		IOPS:           formatIOPS(iops),
+		AverageLatency: fmt.Sprintf("%.2f ms", latency*1000),
+	}, nil
+}
+
+// parseRBDBenchResults parses the summary line of "rbd bench", which does not report the latency
+func parseRBDBenchResults(output string) (*cephv1.BenchmarkResults, error) {
+	matches := rbdBenchSummaryRegexp.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return nil, errors.Errorf("no results in the output of rbd bench %q", output)
+	}
+	summary := matches[len(matches)-1]
+	iops, err := strconv.ParseFloat(summary[1], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the ops/sec of rbd bench %q", summary[0])
+	}
+	bytesPerSec, err := strconv.ParseFloat(summary[2], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the bytes/sec of rbd bench %q", summary[0])
+	}
+	return &cephv1.BenchmarkResults{
+		Bandwidth: formatBandwidth(bytesPerSec * byteUnits[summary[3]] / mib),
+		IOPS:      formatIOPS(iops),
+	}, nil
+}
+
+func jsonFloat(summary map[string]interface{}, key string) (float64, error) {
+	switch value := summary[key].(type) {
+	case float64:
+		return value, nil
+	case string:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse %q of the results of rados bench", key)
+		}
+		return f, nil
+	default:
+		return 0, errors.Errorf("missing %q in the results of rados bench", key)
+	}
+}
+
+func formatBandwidth(mibPerSec float64) string {
+	return fmt.Sprintf("%.2f MiB/s", mibPerSec)
+}
+
+func formatIOPS(iops float64) string {
+	return strconv.FormatFloat(iops, 'f', 0, 64)
+}
+
+func quantityOrDefault(quantity resource.Quantity, defaultQuantity string) int64 {
+	if quantity.IsZero() {
+		quantity = resource.MustParse(defaultQuantity)
+	}
+	return quantity.Value()
+}
+
+// runName returns the name of the objects or the image of the benchmark
+func runName(name string) string {
+	return fmt.Sprintf("rook-benchmark-%s", name)
+}
+
+// shellQuote quotes a value in single quotes for the benchmark script
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
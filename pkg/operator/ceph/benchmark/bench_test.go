@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRadosBenchScript(t *testing.T) {
+	script, timeout := radosBenchScript("bench", &cephv1.RadosBenchmarkSpec{Pool: "replicapool"})
+	assert.Equal(t, `set -e
+cleanup() { rados -p 'replicapool' cleanup --run-name 'rook-benchmark-bench' >/dev/null; }
+trap cleanup EXIT
+rados -p 'replicapool' bench 60 write -t 16 --run-name 'rook-benchmark-bench' -b 4194304 --format json`, script)
+	assert.Equal(t, 11*time.Minute, timeout)
+
+	// the objects are written before they are read
+	script, timeout = radosBenchScript("bench", &cephv1.RadosBenchmarkSpec{Pool: "replicapool", Mode: "rand", DurationSeconds: 300, ObjectSize: resource.MustParse("64Ki"), Concurrency: 4})
+	assert.Equal(t, `set -e
+cleanup() { rados -p 'replicapool' cleanup --run-name 'rook-benchmark-bench' >/dev/null; }
+trap cleanup EXIT
+rados -p 'replicapool' bench 300 write -t 4 --run-name 'rook-benchmark-bench' -b 65536 --no-cleanup >/dev/null
+rados -p 'replicapool' bench 300 rand -t 4 --run-name 'rook-benchmark-bench' --format json`, script)
+	assert.Equal(t, 20*time.Minute, timeout)
+}
+
+func TestRBDBenchScript(t *testing.T) {
+	script, timeout := rbdBenchScript("bench", &cephv1.RBDBenchmarkSpec{Pool: "replicapool"})
+	assert.Equal(t, `set -e
+rbd rm --no-progress 'replicapool/rook-benchmark-bench' >/dev/null 2>&1 || true
+rbd create --size 1024M 'replicapool/rook-benchmark-bench'
+cleanup() { rbd rm --no-progress 'replicapool/rook-benchmark-bench' >/dev/null; }
+trap cleanup EXIT
+rbd bench --io-type write --io-size 4096 --io-threads 16 --io-total 1073741824 --io-pattern rand 'replicapool/rook-benchmark-bench'`, script)
+	assert.Equal(t, time.Hour, timeout)
+
+	// the image is written before it is read
+	script, _ = rbdBenchScript("bench", &cephv1.RBDBenchmarkSpec{Pool: "my'pool", IOType: "read", IOPattern: "seq", IOSize: resource.MustParse("1Mi"), IOTotal: resource.MustParse("100M"), IOThreads: 1})
+	assert.Equal(t, `set -e
+rbd rm --no-progress 'my'\''pool/rook-benchmark-bench' >/dev/null 2>&1 || true
+rbd create --size 96M 'my'\''pool/rook-benchmark-bench'
+cleanup() { rbd rm --no-progress 'my'\''pool/rook-benchmark-bench' >/dev/null; }
+trap cleanup EXIT
+rbd bench --io-type write --io-size 1048576 --io-threads 1 --io-total 100000000 --io-pattern seq 'my'\''pool/rook-benchmark-bench' >/dev/null
+rbd bench --io-type read --io-size 1048576 --io-threads 1 --io-total 100000000 --io-pattern seq 'my'\''pool/rook-benchmark-bench'`, script)
+}
+
+func TestParseRadosBenchResults(t *testing.T) {
+	// values reported as strings
+	results, err := parseRadosBenchResults(`{"total_time_run":"60.1","total_writes_made":"7680","write_size":"4194304","object_size":"4194304","bandwidth":"511.234","stddev_bandwidth":"20.1","average_iops":"127","stddev_iops":"5","max_iops":"140","min_iops":"110","average_latency":"0.124876","stddev_latency":"0.01"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.BenchmarkResults{Bandwidth: "511.23 MiB/s", IOPS: "127", AverageLatency: "124.88 ms"}, *results)
+
+	// values reported as numbers after some output of the benchmark
+	results, err = parseRadosBenchResults("hints = 1\n" + `{"total_time_run":60.0,"bandwidth":1024.5,"average_iops":256.4,"average_latency":0.0625}`)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.BenchmarkResults{Bandwidth: "1024.50 MiB/s", IOPS: "256", AverageLatency: "62.50 ms"}, *results)
+
+	_, err = parseRadosBenchResults("error opening pool replicapool")
+	assert.Error(t, err)
+	_, err = parseRadosBenchResults(`{"bandwidth":"1.0"}`)
+	assert.Error(t, err)
+}
+
+func TestParseRBDBenchResults(t *testing.T) {
+	output := `bench  type write io_size 4096 io_threads 16 bytes 1073741824 pattern random
+  SEC       OPS   OPS/SEC   BYTES/SEC
+    1     20656   20672.3    81 MiB/s
+elapsed: 12   ops: 262144   ops/sec: 20886.6   bytes/sec: 82 MiB/s
+`
+	results, err := parseRBDBenchResults(output)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.BenchmarkResults{Bandwidth: "82.00 MiB/s", IOPS: "20887"}, *results)
+
+	// older releases report the bytes per second without a unit
+	results, err = parseRBDBenchResults("elapsed:    12  ops:   262144  ops/sec: 20886.60  bytes/sec: 85551821.18")
+	assert.NoError(t, err)
+	assert.Equal(t, "81.59 MiB/s", results.Bandwidth)
+
+	_, err = parseRBDBenchResults("rbd: error opening pool")
+	assert.Error(t, err)
+}
+
+func TestValidateBenchmark(t *testing.T) {
+	benchmark := &cephv1.CephBenchmark{}
+	assert.Error(t, validateBenchmark(benchmark))
+
+	benchmark.Spec.Rados = &cephv1.RadosBenchmarkSpec{Pool: "replicapool"}
+	assert.NoError(t, validateBenchmark(benchmark))
+
+	benchmark.Spec.RBD = &cephv1.RBDBenchmarkSpec{Pool: "replicapool"}
+	assert.Error(t, validateBenchmark(benchmark))
+
+	benchmark.Spec.Rados = nil
+	assert.NoError(t, validateBenchmark(benchmark))
+	benchmark.Spec.RBD.Pool = ""
+	assert.Error(t, validateBenchmark(benchmark))
+}
+
+func TestIsCompleted(t *testing.T) {
+	benchmark := &cephv1.CephBenchmark{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	assert.False(t, isCompleted(benchmark))
+
+	benchmark.Status = &cephv1.CephBenchmarkStatus{Phase: cephv1.BenchmarkSucceeded, ObservedGeneration: 2}
+	assert.True(t, isCompleted(benchmark))
+	benchmark.Status.Phase = cephv1.BenchmarkFailed
+	assert.True(t, isCompleted(benchmark))
+
+	// an interrupted benchmark runs again
+	benchmark.Status.Phase = cephv1.BenchmarkRunning
+	assert.False(t, isCompleted(benchmark))
+
+	// an updated benchmark runs again
+	benchmark.Status.Phase = cephv1.BenchmarkSucceeded
+	benchmark.Generation = 3
+	assert.False(t, isCompleted(benchmark))
+}
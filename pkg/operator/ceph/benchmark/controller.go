@@ -0,0 +1,289 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package benchmark to run the rados and rbd benchmarks of the CephBenchmark CRs
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/k8sutil/cmdreporter"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-benchmark-controller"
+
+	benchmarkAppName    = "rook-ceph-benchmark"
+	benchmarkJobNameFmt = "rook-ceph-benchmark-%s"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBenchmarkKind = reflect.TypeOf(cephv1.CephBenchmark{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBenchmarkKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephBenchmark reconciles a CephBenchmark object
+type ReconcileCephBenchmark struct {
+	client           client.Client
+	scheme           *runtime.Scheme
+	context          *clusterd.Context
+	opConfig         opcontroller.OperatorConfig
+	opManagerContext context.Context
+}
+
+// Add creates a new CephBenchmark Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
+	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) reconcile.Reconciler {
+	return &ReconcileCephBenchmark{
+		client:           mgr.GetClient(),
+		scheme:           mgr.GetScheme(),
+		context:          context,
+		opConfig:         opConfig,
+		opManagerContext: opManagerContext,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller. A single benchmark runs at a time so that the benchmarks do not skew
+	// the results of each other.
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: 1})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBenchmark CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBenchmark{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephBenchmark object and makes changes based on the state read
+// and what is in the CephBenchmark.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephBenchmark) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephBenchmark) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBenchmark instance
+	benchmark := &cephv1.CephBenchmark{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, benchmark)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("cephBenchmark resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get cephBenchmark")
+	}
+
+	// A benchmark runs once for each generation of its spec
+	if isCompleted(benchmark) {
+		logger.Debugf("benchmark %q already completed", benchmark.Name)
+		return reconcile.Result{}, nil
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, _, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		return reconcileResponse, nil
+	}
+
+	// an invalid benchmark is not retried until its spec is updated
+	if err := validateBenchmark(benchmark); err != nil {
+		logger.Errorf("invalid ceph benchmark %q. %v", benchmark.Name, err)
+		r.updateStatus(request.NamespacedName, benchmark.Generation, failedStatus(err))
+		return reconcile.Result{}, nil
+	}
+
+	// the status is updated before running the job since the reconcile blocks until the benchmark completes
+	r.updateStatus(request.NamespacedName, benchmark.Generation, func(status *cephv1.CephBenchmarkStatus) {
+		status.Phase = cephv1.BenchmarkRunning
+		status.Message = ""
+		status.StartTime = &metav1.Time{Time: time.Now()}
+		status.CompletionTime = nil
+		status.Results = nil
+	})
+
+	results, err := r.runBenchmark(benchmark, cephCluster.Spec.CephVersion.Image)
+	if err != nil {
+		logger.Errorf("ceph benchmark %q failed. %v", benchmark.Name, err)
+		r.updateStatus(request.NamespacedName, benchmark.Generation, failedStatus(err))
+		return reconcile.Result{}, nil
+	}
+
+	logger.Infof("ceph benchmark %q succeeded. %+v", benchmark.Name, *results)
+	r.updateStatus(request.NamespacedName, benchmark.Generation, func(status *cephv1.CephBenchmarkStatus) {
+		status.Phase = cephv1.BenchmarkSucceeded
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+		status.Results = results
+	})
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// isCompleted returns whether the benchmark already succeeded or failed for the current generation
+// of its spec. A benchmark interrupted by a restart of the operator is run again.
+func isCompleted(benchmark *cephv1.CephBenchmark) bool {
+	status := benchmark.Status
+	if status == nil || status.ObservedGeneration != benchmark.Generation {
+		return false
+	}
+	return status.Phase == cephv1.BenchmarkSucceeded || status.Phase == cephv1.BenchmarkFailed
+}
+
+func validateBenchmark(benchmark *cephv1.CephBenchmark) error {
+	spec := benchmark.Spec
+	if (spec.Rados == nil) == (spec.RBD == nil) {
+		return errors.New("exactly one of the rados and the rbd benchmarks must be set")
+	}
+	if spec.Rados != nil && spec.Rados.Pool == "" {
+		return errors.New("missing pool of the rados benchmark")
+	}
+	if spec.RBD != nil && spec.RBD.Pool == "" {
+		return errors.New("missing pool of the rbd benchmark")
+	}
+	return nil
+}
+
+// runBenchmark runs the job of the benchmark with the admin credentials, waits for its completion and
+// returns its results
+func (r *ReconcileCephBenchmark) runBenchmark(benchmark *cephv1.CephBenchmark, cephImage string) (*cephv1.BenchmarkResults, error) {
+	var script string
+	var timeout time.Duration
+	if benchmark.Spec.Rados != nil {
+		script, timeout = radosBenchScript(benchmark.Name, benchmark.Spec.Rados)
+	} else {
+		script, timeout = rbdBenchScript(benchmark.Name, benchmark.Spec.RBD)
+	}
+
+	jobName := k8sutil.TruncateNodeName(benchmarkJobNameFmt, benchmark.Name)
+	reporter, err := cmdreporter.New(
+		r.context.Clientset,
+		k8sutil.NewOwnerInfo(benchmark, r.scheme),
+		benchmarkAppName,
+		jobName,
+		benchmark.Namespace,
+		[]string{"bash"},
+		[]string{"-c", script},
+		r.opConfig.Image,
+		cephImage,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set up the benchmark job")
+	}
+
+	job := reporter.Job()
+	job.Spec.Template.Spec.ServiceAccountName = "rook-ceph-cmd-reporter"
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, keyring.Volume().Admin())
+	container := &job.Spec.Template.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, keyring.VolumeMount().Admin())
+	container.Env = append(container.Env, opcontroller.DaemonEnvVars(cephImage)...)
+	container.Env = append(container.Env, v1.EnvVar{Name: "CEPH_ARGS", Value: fmt.Sprintf("-m $(ROOK_CEPH_MON_HOST) -k %s", keyring.VolumeMount().AdminKeyringFilePath())})
+
+	logger.Infof("running ceph benchmark %q", benchmark.Name)
+	stdout, stderr, retcode, err := reporter.Run(timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to complete the benchmark job")
+	}
+	if retcode != 0 {
+		return nil, errors.Errorf("benchmark job returned failure with retcode %d. stderr: %s", retcode, stderr)
+	}
+
+	if benchmark.Spec.Rados != nil {
+		return parseRadosBenchResults(stdout)
+	}
+	return parseRBDBenchResults(stdout)
+}
+
+func failedStatus(err error) func(status *cephv1.CephBenchmarkStatus) {
+	return func(status *cephv1.CephBenchmarkStatus) {
+		status.Phase = cephv1.BenchmarkFailed
+		status.Message = err.Error()
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+		status.Results = nil
+	}
+}
+
+// updateStatus updates the status of a benchmark for the given generation of its spec
+func (r *ReconcileCephBenchmark) updateStatus(name types.NamespacedName, generation int64, update func(status *cephv1.CephBenchmarkStatus)) {
+	benchmark := &cephv1.CephBenchmark{}
+	if err := r.client.Get(r.opManagerContext, name, benchmark); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBenchmark resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph benchmark %q to update status. %v", name, err)
+		return
+	}
+	if benchmark.Status == nil {
+		benchmark.Status = &cephv1.CephBenchmarkStatus{}
+	}
+
+	update(benchmark.Status)
+	benchmark.Status.ObservedGeneration = generation
+	if err := reporting.UpdateStatus(r.client, benchmark); err != nil {
+		logger.Errorf("failed to set ceph benchmark %q status to %q. %v", name, benchmark.Status.Phase, err)
+		return
+	}
+	logger.Debugf("ceph benchmark %q status updated to %q", name, benchmark.Status.Phase)
+}
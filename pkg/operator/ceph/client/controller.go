@@ -74,6 +74,7 @@ type ReconcileCephClient struct {
 // Add creates a new CephClient Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext))
 }
 
@@ -296,14 +297,20 @@ func (r *ReconcileCephClient) deleteClient(cephClient *cephv1.CephClient) error
 	return nil
 }
 
+var reservedNames = regexp.MustCompile("^admin$|^rgw.*$|^rbd-mirror$|^osd.[0-9]*$|^bootstrap-(mds|mgr|mon|osd|rgw|^rbd-mirror)$")
+
+// IsReservedName returns whether the client name is reserved for the Ceph daemons and the admin
+func IsReservedName(name string) bool {
+	return reservedNames.MatchString(name)
+}
+
 // ValidateClient the client arguments
 func ValidateClient(context *clusterd.Context, cephClient *cephv1.CephClient) error {
 	// Validate name
 	if cephClient.Name == "" {
 		return errors.New("missing name")
 	}
-	reservedNames := regexp.MustCompile("^admin$|^rgw.*$|^rbd-mirror$|^osd.[0-9]*$|^bootstrap-(mds|mgr|mon|osd|rgw|^rbd-mirror)$")
-	if reservedNames.Match([]byte(cephClient.Name)) {
+	if IsReservedName(cephClient.Name) {
 		return errors.Errorf("ignoring reserved name %q", cephClient.Name)
 	}
 
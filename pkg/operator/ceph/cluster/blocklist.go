@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// defaultBlocklistCheckInterval is the interval between the checks of the osd blocklist
+	defaultBlocklistCheckInterval = 5 * time.Minute
+)
+
+// blocklistChecker periodically reports the number of entries of the osd blocklist in the status of the CephCluster,
+// and removes the entries of the nodes that crashed, such as the clients fenced after a node failure, once the nodes
+// rejoined the cluster
+type blocklistChecker struct {
+	context            *clusterd.Context
+	clusterInfo        *cephclient.ClusterInfo
+	interval           time.Duration
+	clearRejoinedNodes bool
+	// fencedNodes are the nodes that were not ready when their blocklisted addresses were found, by address.
+	// Only these entries are removed once their node is ready again, the entries of the nodes that stayed ready
+	// are kept since the clients were blocklisted for another reason.
+	fencedNodes map[string]string
+}
+
+// newBlocklistChecker creates a new blocklistChecker object
+func newBlocklistChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec) *blocklistChecker {
+	b := &blocklistChecker{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultBlocklistCheckInterval,
+		fencedNodes: map[string]string{},
+	}
+	if spec := clusterSpec.HealthCheck.Blocklist; spec != nil {
+		b.clearRejoinedNodes = spec.ClearRejoinedNodes
+		if spec.Interval != nil {
+			logger.Infof("blocklist check interval is %s", spec.Interval.Duration.String())
+			b.interval = spec.Interval.Duration
+		}
+	}
+	return b
+}
+
+// checkBlocklist checks the blocklist right away and then at each interval
+func (b *blocklistChecker) checkBlocklist(ctx context.Context) {
+	for {
+		logger.Debug("checking the osd blocklist")
+		if err := b.check(); err != nil {
+			logger.Warningf("failed to check the osd blocklist. %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping the blocklist check in namespace %q", b.clusterInfo.Namespace)
+			return
+
+		case <-time.After(b.interval):
+		}
+	}
+}
+
+// check lists the blocklist, clears the entries of the nodes that rejoined if enabled, and updates the status
+func (b *blocklistChecker) check() error {
+	entries, err := cephclient.ListOSDBlocklist(b.context, b.clusterInfo)
+	if err != nil {
+		return err
+	}
+
+	if b.clearRejoinedNodes {
+		entries, err = b.clearStaleEntries(entries)
+		if err != nil {
+			return errors.Wrap(err, "failed to clear the stale entries of the blocklist")
+		}
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := b.context.Client.Get(b.clusterInfo.Context, b.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", b.clusterInfo.NamespacedName().String())
+	}
+	cephCluster.Status.Blocklist = &cephv1.BlocklistStatus{
+		Entries:     len(entries),
+		LastChecked: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := reporting.UpdateStatus(b.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the blocklist status")
+	}
+	return nil
+}
+
+// clearStaleEntries removes the entries of the nodes that were not ready when the entries were found and are ready
+// again, and returns the remaining entries
+func (b *blocklistChecker) clearStaleEntries(entries []cephclient.BlocklistEntry) ([]cephclient.BlocklistEntry, error) {
+	nodes, err := b.context.Clientset.CoreV1().Nodes().List(b.clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		return entries, errors.Wrap(err, "failed to list the nodes")
+	}
+	readyByIP := map[string]bool{}
+	nameByIP := map[string]string{}
+	for _, node := range nodes.Items {
+		for _, address := range node.Status.Addresses {
+			readyByIP[address.Address] = k8sutil.NodeIsReady(node)
+			nameByIP[address.Address] = node.Name
+		}
+	}
+
+	remaining := []cephclient.BlocklistEntry{}
+	fencedNodes := map[string]string{}
+	for _, entry := range entries {
+		ready, isNode := readyByIP[entry.IP()]
+		if !isNode {
+			remaining = append(remaining, entry)
+			continue
+		}
+		nodeName := nameByIP[entry.IP()]
+		if !ready {
+			fencedNodes[entry.Addr] = nodeName
+			remaining = append(remaining, entry)
+			continue
+		}
+		if _, ok := b.fencedNodes[entry.Addr]; !ok {
+			remaining = append(remaining, entry)
+			continue
+		}
+
+		logger.Infof("removing stale blocklist entry %q of node %q that rejoined the cluster", entry.Addr, nodeName)
+		if err := cephclient.RemoveOSDBlocklist(b.context, b.clusterInfo, entry.Addr); err != nil {
+			logger.Errorf("failed to remove stale blocklist entry %q. %v", entry.Addr, err)
+			fencedNodes[entry.Addr] = nodeName
+			remaining = append(remaining, entry)
+			continue
+		}
+		if err := reporting.RecordCorrectiveAction(b.clusterInfo.Context, b.context.Client, b.clusterInfo.NamespacedName(), cephv1.CorrectiveActionBlocklistCleared, entry.Addr, fmt.Sprintf("node %q rejoined the cluster", nodeName)); err != nil {
+			logger.Warningf("failed to record the removal of blocklist entry %q. %v", entry.Addr, err)
+		}
+	}
+	b.fencedNodes = fencedNodes
+	return remaining, nil
+}
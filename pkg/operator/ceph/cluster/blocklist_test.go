@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckBlocklist(t *testing.T) {
+	ctx := context.TODO()
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.Context = ctx
+	clusterInfo.CephVersion = cephver.Pacific
+	nsName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := fake.NewSimpleClientset()
+	test.AddReadyNode(t, clientset, "node1", "10.0.0.1")
+	test.AddReadyNode(t, clientset, "node2", "10.0.0.2")
+
+	entries := map[string]bool{"10.0.0.1:0/1": true, "10.0.0.2:0/2": true, "10.0.0.9:0/9": true}
+	var removed []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if args[0] == "osd" && args[1] == "blocklist" && args[2] == "ls" {
+				list := []string{}
+				for addr := range entries {
+					list = append(list, fmt.Sprintf(`{"addr":%q,"until":"2021-10-15T10:00:00.000000+0000"}`, addr))
+				}
+				return "[" + strings.Join(list, ",") + "]", nil
+			}
+			if args[0] == "osd" && args[1] == "blocklist" && args[2] == "rm" {
+				removed = append(removed, args[3])
+				delete(entries, args[3])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	clusterSpec := &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{Blocklist: &cephv1.BlocklistSpec{Enabled: true, ClearRejoinedNodes: true}}}
+	b := newBlocklistChecker(&clusterd.Context{Client: client, Clientset: clientset, Executor: executor}, clusterInfo, clusterSpec)
+	assert.Equal(t, defaultBlocklistCheckInterval, b.interval)
+
+	getStatus := func() *cephv1.BlocklistStatus {
+		cephCluster := &cephv1.CephCluster{}
+		require.NoError(t, client.Get(ctx, nsName, cephCluster))
+		return cephCluster.Status.Blocklist
+	}
+	setReady := func(name string, ready v1.ConditionStatus) {
+		node, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		node.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}}
+		_, err = clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+
+	// the entries of the ready nodes are kept
+	require.NoError(t, b.check())
+	assert.Empty(t, removed)
+	status := getStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, 3, status.Entries)
+	assert.NotEmpty(t, status.LastChecked)
+
+	// node1 crashed, its entry is cleared once it rejoined
+	setReady("node1", v1.ConditionFalse)
+	require.NoError(t, b.check())
+	assert.Empty(t, removed)
+	assert.Equal(t, map[string]string{"10.0.0.1:0/1": "node1"}, b.fencedNodes)
+
+	setReady("node1", v1.ConditionTrue)
+	require.NoError(t, b.check())
+	assert.Equal(t, []string{"10.0.0.1:0/1"}, removed)
+	assert.Equal(t, 2, getStatus().Entries)
+	assert.Empty(t, b.fencedNodes)
+
+	cephCluster = &cephv1.CephCluster{}
+	require.NoError(t, client.Get(ctx, nsName, cephCluster))
+	require.Equal(t, 1, len(cephCluster.Status.CorrectiveActions))
+	assert.Equal(t, cephv1.CorrectiveActionBlocklistCleared, cephCluster.Status.CorrectiveActions[0].Type)
+
+	// the entries are only reported when the clearing is disabled
+	b.clearRejoinedNodes = false
+	setReady("node2", v1.ConditionFalse)
+	require.NoError(t, b.check())
+	setReady("node2", v1.ConditionTrue)
+	require.NoError(t, b.check())
+	assert.Equal(t, []string{"10.0.0.1:0/1"}, removed)
+	assert.Equal(t, 2, getStatus().Entries)
+}
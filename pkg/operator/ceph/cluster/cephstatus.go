@@ -21,6 +21,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,6 +32,7 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/notification"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
@@ -42,8 +45,13 @@ import (
 var (
 	// defaultStatusCheckInterval is the interval to check the status of the ceph cluster
 	defaultStatusCheckInterval = 60 * time.Second
+	// defaultHealthMuteTTL is how long the health warnings of healthCheck.muteWarnings are muted
+	defaultHealthMuteTTL = time.Hour
 )
 
+// pgNotDeepScrubbedCheck is the health check of the PGs not deep scrubbed in time
+const pgNotDeepScrubbedCheck = "PG_NOT_DEEP_SCRUBBED"
+
 // cephStatusChecker aggregates the mon/cluster info needed to check the health of the monitors
 type cephStatusChecker struct {
 	context     *clusterd.Context
@@ -51,6 +59,8 @@ type cephStatusChecker struct {
 	interval    *time.Duration
 	client      client.Client
 	isExternal  bool
+	// mutedSince is when the warnings of healthCheck.muteWarnings raised were muted
+	mutedSince map[string]time.Time
 }
 
 // newCephStatusChecker creates a new HealthChecker object
@@ -147,6 +157,52 @@ func (c *cephStatusChecker) checkStatus() {
 	}
 
 	c.configureHealthSettings(status)
+	c.muteHealthWarnings(status)
+}
+
+// muteHealthWarnings mutes the warnings of healthCheck.muteWarnings when they are raised. A warning is only muted
+// once while it is raised, so it shows again if it outlasts its ttl, and is muted again after it cleared.
+func (c *cephStatusChecker) muteHealthWarnings(status cephclient.CephStatus) {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if !kerrors.IsNotFound(err) {
+			logger.Errorf("failed to get ceph cluster to mute the health warnings. %v", err)
+		}
+		return
+	}
+
+	now := time.Now()
+	mutedSince := map[string]time.Time{}
+	for _, mute := range cephCluster.Spec.HealthCheck.MuteWarnings {
+		check, ok := status.Health.Checks[mute.Code]
+		if !ok {
+			continue
+		}
+		if since, ok := c.mutedSince[mute.Code]; ok {
+			mutedSince[mute.Code] = since
+			continue
+		}
+		if check.Muted {
+			// muted by the admin
+			mutedSince[mute.Code] = now
+			continue
+		}
+
+		ttl := defaultHealthMuteTTL
+		if mute.TTL != nil {
+			ttl = mute.TTL.Duration
+		}
+		if err := cephclient.MuteHealthWarning(c.context, c.clusterInfo, mute.Code, ttl, mute.Sticky); err != nil {
+			logger.Errorf("failed to mute health warning %q. %v", mute.Code, err)
+			continue
+		}
+		logger.Infof("muted health warning %q for %s", mute.Code, ttl)
+		mutedSince[mute.Code] = now
+		if err := reporting.RecordCorrectiveAction(c.clusterInfo.Context, c.client, c.clusterInfo.NamespacedName(), cephv1.CorrectiveActionHealthMuted, mute.Code, fmt.Sprintf("muted for %s", ttl)); err != nil {
+			logger.Warningf("failed to record the mute of health warning %q. %v", mute.Code, err)
+		}
+	}
+	c.mutedSince = mutedSince
 }
 
 func (c *cephStatusChecker) configureHealthSettings(status cephclient.CephStatus) {
@@ -185,7 +241,10 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, cond
 	}
 
 	// Update with Ceph Status
+	previousStatus := cephCluster.Status.CephStatus
 	cephCluster.Status.CephStatus = toCustomResourceStatus(cephCluster.Status, status)
+	notifyHealthChange(c.context, cephCluster, previousStatus)
+	reportRecoveryMetrics(clusterName.Namespace, cephCluster.Status.CephStatus.Recovery, time.Now().UTC())
 
 	// versions store the ceph version of all the ceph daemons and overall cluster version
 	versions, err := cephclient.GetAllCephDaemonVersions(c.context, c.clusterInfo)
@@ -196,16 +255,46 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, cond
 		cephCluster.Status.CephStatus.Versions = versions
 	}
 
+	// the balancer status reports the score of the data distribution when the balancer is configured
+	if cephCluster.Spec.Mgr.Balancer != nil && !c.isExternal {
+		balancer, err := c.getBalancerStatus()
+		if err != nil {
+			logger.Errorf("failed to get the balancer status. %v", err)
+		} else {
+			cephCluster.Status.CephStatus.Balancer = balancer
+		}
+	}
+
 	// Update condition
 	logger.Debugf("updating ceph cluster %q status and condition to %+v, %v, %s, %s", clusterName.Namespace, status, conditionStatus, reason, message)
 	opcontroller.UpdateClusterCondition(c.context, cephCluster, c.clusterInfo.NamespacedName(), condition, conditionStatus, reason, message, true)
 }
 
+// getBalancerStatus returns the status of the balancer with the current score of the data distribution
+func (c *cephStatusChecker) getBalancerStatus() (*cephv1.BalancerStatus, error) {
+	status, err := cephclient.GetBalancerStatus(c.context, c.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	score, err := cephclient.GetBalancerScore(c.context, c.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &cephv1.BalancerStatus{
+		Active:              status.Active,
+		Mode:                status.Mode,
+		Score:               score,
+		LastOptimizeStarted: status.LastOptimizeStarted,
+		OptimizeResult:      status.OptimizeResult,
+	}, nil
+}
+
 // toCustomResourceStatus converts the ceph status to the struct expected for the CephCluster CR status
 func toCustomResourceStatus(currentStatus cephv1.ClusterStatus, newStatus *cephclient.CephStatus) *cephv1.CephStatus {
+	now := time.Now().UTC()
 	s := &cephv1.CephStatus{
 		Health:      newStatus.Health.Status,
-		LastChecked: formatTime(time.Now().UTC()),
+		LastChecked: formatTime(now),
 		Details:     make(map[string]cephv1.CephHealthMessage),
 	}
 	for name, message := range newStatus.Health.Checks {
@@ -219,7 +308,7 @@ func toCustomResourceStatus(currentStatus cephv1.ClusterStatus, newStatus *cephc
 		s.Capacity.TotalBytes = newStatus.PgMap.TotalBytes
 		s.Capacity.UsedBytes = newStatus.PgMap.UsedBytes
 		s.Capacity.AvailableBytes = newStatus.PgMap.AvailableBytes
-		s.Capacity.LastUpdated = formatTime(time.Now().UTC())
+		s.Capacity.LastUpdated = formatTime(now)
 	}
 
 	if currentStatus.CephStatus != nil {
@@ -233,9 +322,59 @@ func toCustomResourceStatus(currentStatus cephv1.ClusterStatus, newStatus *cephc
 			s.Capacity = currentStatus.CephStatus.Capacity
 		}
 	}
+
+	var previousRecovery *cephv1.RecoveryStatus
+	if currentStatus.CephStatus != nil {
+		previousRecovery = currentStatus.CephStatus.Recovery
+	}
+	s.Recovery = toRecoveryStatus(previousRecovery, newStatus.PgMap, now)
+	s.PGsNotDeepScrubbed = pgsNotDeepScrubbed(newStatus)
 	return s
 }
 
+// pgsNotDeepScrubbed returns the number of PGs the mgr reports as not deep scrubbed in time
+func pgsNotDeepScrubbed(status *cephclient.CephStatus) int {
+	check, ok := status.Health.Checks[pgNotDeepScrubbedCheck]
+	if !ok {
+		return 0
+	}
+	if check.Summary.Count > 0 {
+		return check.Summary.Count
+	}
+	// the message is like "2 pgs not deep-scrubbed in time"
+	var count int
+	if _, err := fmt.Sscanf(check.Summary.Message, "%d", &count); err != nil {
+		return 0
+	}
+	return count
+}
+
+// notifyHealthChange notifies the transitions of the ceph health. The first status of a healthy
+// cluster is not a transition.
+func notifyHealthChange(context *clusterd.Context, cephCluster *cephv1.CephCluster, previousStatus *cephv1.CephStatus) {
+	previousHealth := ""
+	if previousStatus != nil {
+		previousHealth = previousStatus.Health
+	}
+	health := cephCluster.Status.CephStatus.Health
+	if health == previousHealth || (previousHealth == "" && health == "HEALTH_OK") {
+		return
+	}
+
+	var checks []string
+	for name, check := range cephCluster.Status.CephStatus.Details {
+		checks = append(checks, fmt.Sprintf("%s: %s", name, check.Message))
+	}
+	sort.Strings(checks)
+	message := fmt.Sprintf("ceph health changed from %q to %q", previousHealth, health)
+	if len(checks) > 0 {
+		message = fmt.Sprintf("%s. %s", message, strings.Join(checks, "; "))
+	}
+	if err := notification.Notify(context, cephCluster, notification.HealthChanged, notification.HealthSeverity(health), message); err != nil {
+		logger.Errorf("failed to notify the ceph health change. %v", err)
+	}
+}
+
 func formatTime(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
@@ -266,6 +405,28 @@ func (c *ClusterController) updateClusterCephVersion(image string, cephVersion c
 	}
 }
 
+// updateClusterHotfixVersion records the validated version of the hotfix image in the status of the cluster
+func (c *ClusterController) updateClusterHotfixVersion(hotfixVersion *cephv1.ClusterVersion) {
+	cephCluster, err := c.context.RookClientset.CephV1().CephClusters(c.namespacedName.Namespace).Get(c.OpManagerCtx, c.namespacedName.Name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Errorf("failed to retrieve ceph cluster %q to update the hotfix version to %+v. %v", c.namespacedName.Name, hotfixVersion, err)
+		return
+	}
+
+	if reflect.DeepEqual(cephCluster.Status.HotfixVersion, hotfixVersion) {
+		return
+	}
+	cephCluster.Status.HotfixVersion = hotfixVersion
+	if err := reporting.UpdateStatus(c.client, cephCluster); err != nil {
+		logger.Errorf("failed to update cluster %q hotfix version. %v", c.namespacedName.Name, err)
+		return
+	}
+}
+
 func cephStatusOnError(errorMessage string) *cephclient.CephStatus {
 	details := make(map[string]cephclient.CheckMessage)
 	details["error"] = cephclient.CheckMessage{
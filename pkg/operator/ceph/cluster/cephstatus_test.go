@@ -22,11 +22,13 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	optest "github.com/rook/rook/pkg/operator/test"
@@ -35,6 +37,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestCephStatus(t *testing.T) {
@@ -99,6 +102,19 @@ func TestCephStatus(t *testing.T) {
 	assert.Equal(t, pgAvailMsg.Summary.Message, aggregateStatus.Details["PG_AVAILABILITY"].Message)
 	assert.Equal(t, pgAvailMsg.Severity, aggregateStatus.Details["PG_AVAILABILITY"].Severity)
 
+	assert.Equal(t, 0, aggregateStatus.PGsNotDeepScrubbed)
+
+	// The PGs not deep scrubbed in time are counted from the health check
+	notDeepScrubbedMsg := cephclient.CheckMessage{Severity: "HEALTH_WARN"}
+	notDeepScrubbedMsg.Summary.Message = "12 pgs not deep-scrubbed in time"
+	newStatus.Health.Checks["PG_NOT_DEEP_SCRUBBED"] = notDeepScrubbedMsg
+	aggregateStatus = toCustomResourceStatus(currentStatus, newStatus)
+	assert.Equal(t, 12, aggregateStatus.PGsNotDeepScrubbed)
+	notDeepScrubbedMsg.Summary.Count = 13
+	newStatus.Health.Checks["PG_NOT_DEEP_SCRUBBED"] = notDeepScrubbedMsg
+	aggregateStatus = toCustomResourceStatus(currentStatus, newStatus)
+	assert.Equal(t, 13, aggregateStatus.PGsNotDeepScrubbed)
+
 	// Test for storage capacity of the ceph cluster when there is no disk
 	newStatus = &cephclient.CephStatus{
 		PgMap: cephclient.PgMap{TotalBytes: 0},
@@ -143,9 +159,9 @@ func TestNewCephStatusChecker(t *testing.T) {
 		args args
 		want *cephStatusChecker
 	}{
-		{"default-interval", args{c, clusterInfo, &cephv1.ClusterSpec{}}, &cephStatusChecker{c, clusterInfo, &defaultStatusCheckInterval, c.Client, false}},
-		{"10s-interval", args{c, clusterInfo, &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, false}},
-		{"10s-interval-external", args{c, clusterInfo, &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}, HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, true}},
+		{"default-interval", args{c, clusterInfo, &cephv1.ClusterSpec{}}, &cephStatusChecker{c, clusterInfo, &defaultStatusCheckInterval, c.Client, false, nil}},
+		{"10s-interval", args{c, clusterInfo, &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, false, nil}},
+		{"10s-interval-external", args{c, clusterInfo, &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}, HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, true, nil}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -239,6 +255,68 @@ func TestConfigureHealthSettings(t *testing.T) {
 	}
 }
 
+func TestMuteHealthWarnings(t *testing.T) {
+	ctx := context.TODO()
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.Context = ctx
+	nsName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace},
+		Spec: cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{MuteWarnings: []cephv1.HealthMuteSpec{
+			{Code: "OSD_SLOW_PING_TIME_BACK", TTL: &metav1.Duration{Duration: 2 * time.Hour}, Sticky: true},
+			{Code: "OSD_SLOW_PING_TIME_FRONT"},
+		}}},
+	}
+	var mutes []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if args[0] == "health" && args[1] == "mute" {
+				mute := strings.Join(args[2:4], " ")
+				if args[4] == "--sticky" {
+					mute += " --sticky"
+				}
+				mutes = append(mutes, mute)
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	c := &cephStatusChecker{
+		context:     &clusterd.Context{Client: client, Executor: executor},
+		clusterInfo: clusterInfo,
+		client:      client,
+	}
+	status := func(checks map[string]cephclient.CheckMessage) cephclient.CephStatus {
+		return cephclient.CephStatus{Health: cephclient.HealthStatus{Status: "HEALTH_WARN", Checks: checks}}
+	}
+
+	// the warnings not in the spec are not muted
+	c.muteHealthWarnings(status(map[string]cephclient.CheckMessage{"MDS_ALL_DOWN": {Severity: "HEALTH_WARN"}}))
+	assert.Empty(t, mutes)
+
+	c.muteHealthWarnings(status(map[string]cephclient.CheckMessage{"OSD_SLOW_PING_TIME_BACK": {Severity: "HEALTH_WARN"}}))
+	assert.Equal(t, []string{"OSD_SLOW_PING_TIME_BACK 7200s --sticky"}, mutes)
+
+	// the warning is not muted again while it is raised, even after the mute expired
+	c.muteHealthWarnings(status(map[string]cephclient.CheckMessage{
+		"OSD_SLOW_PING_TIME_BACK":  {Severity: "HEALTH_WARN"},
+		"OSD_SLOW_PING_TIME_FRONT": {Severity: "HEALTH_WARN", Muted: true},
+	}))
+	assert.Equal(t, 1, len(mutes))
+
+	// the warning is muted again once it cleared
+	c.muteHealthWarnings(status(map[string]cephclient.CheckMessage{}))
+	c.muteHealthWarnings(status(map[string]cephclient.CheckMessage{"OSD_SLOW_PING_TIME_BACK": {Severity: "HEALTH_WARN"}}))
+	assert.Equal(t, 2, len(mutes))
+
+	cluster := &cephv1.CephCluster{}
+	assert.NoError(t, client.Get(ctx, nsName, cluster))
+	assert.Equal(t, 2, len(cluster.Status.CorrectiveActions))
+	assert.Equal(t, cephv1.CorrectiveActionHealthMuted, cluster.Status.CorrectiveActions[0].Type)
+}
+
 func TestForceDeleteStuckRookPodsOnNotReadyNodes(t *testing.T) {
 	ctx := context.TODO()
 	clientset := optest.New(t, 1)
@@ -95,6 +95,16 @@ func (c *ClusterController) startCleanUpJobs(cluster *cephv1.CephCluster, cephHo
 		if err := k8sutil.RunReplaceableJob(c.context.Clientset, job, true); err != nil {
 			logger.Errorf("failed to run cluster clean up job on node %q. %v", hostName, err)
 		}
+
+		// the devices of the node can now be used by another ceph cluster
+		nodeName, err := k8sutil.GetNodeNameFromHostname(c.context.Clientset, hostName)
+		if err != nil {
+			logger.Errorf("failed to get node name of host %q to release the device claims. %v", hostName, err)
+			continue
+		}
+		if err := osd.ReleaseDeviceClaims(c.context.Clientset, nodeName, clusterFSID); err != nil {
+			logger.Errorf("failed to release the device claims on node %q. %v", nodeName, err)
+		}
 	}
 }
 
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os/exec"
 	"path"
+	"strings"
 	"sync"
 	"syscall"
 
@@ -38,6 +39,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/ceph/notification"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
@@ -47,7 +49,8 @@ import (
 )
 
 const (
-	detectVersionName = "rook-ceph-detect-version"
+	detectVersionName       = "rook-ceph-detect-version"
+	detectHotfixVersionName = "rook-ceph-detect-hotfix-version"
 )
 
 type cluster struct {
@@ -60,6 +63,10 @@ type cluster struct {
 	ownerInfo          *k8sutil.OwnerInfo
 	isUpgrade          bool
 	monitoringRoutines map[string]*clusterHealth
+	// the osd monitor of the cluster, which follows the changes of the removal settings of the OSDs
+	osdChecker *osd.OSDHealthMonitor
+	// whether the failure of the current upgrade was notified
+	upgradeFailureNotified bool
 }
 
 type clusterHealth struct {
@@ -67,7 +74,9 @@ type clusterHealth struct {
 	internalCancel context.CancelFunc
 }
 
-func newCluster(c *cephv1.CephCluster, context *clusterd.Context, csiMutex *sync.Mutex, ownerInfo *k8sutil.OwnerInfo) *cluster {
+func newCluster(c *cephv1.CephCluster, context *clusterd.Context, csiMutex *sync.Mutex, ownerInfo *k8sutil.OwnerInfo, recorder *k8sutil.EventReporter) *cluster {
+	mons := mon.New(context, c.Namespace, c.Spec, ownerInfo, csiMutex)
+	mons.SetEventReporter(recorder)
 	return &cluster{
 		// at this phase of the cluster creation process, the identity components of the cluster are
 		// not yet established. we reserve this struct which is filled in as soon as the cluster's
@@ -79,7 +88,7 @@ func newCluster(c *cephv1.CephCluster, context *clusterd.Context, csiMutex *sync
 		namespacedName:     types.NamespacedName{Namespace: c.Namespace, Name: c.Name},
 		monitoringRoutines: make(map[string]*clusterHealth),
 		ownerInfo:          ownerInfo,
-		mons:               mon.New(context, c.Namespace, c.Spec, ownerInfo, csiMutex),
+		mons:               mons,
 	}
 }
 
@@ -91,6 +100,25 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 		return errors.Wrap(err, "failed to populate config override config map")
 	}
 
+	// Tune the kernel of the storage nodes before the daemons start. The daemons are still started if the
+	// tuning fails, the failure is reported in the Degraded condition of the cluster.
+	tuningErr := c.reconcileNodeTuning()
+	if tuningErr != nil {
+		logger.Errorf("failed to reconcile node tuning, continuing without it. %v", tuningErr)
+	}
+	if err := c.updateNodeTuningCondition(tuningErr); err != nil {
+		logger.Errorf("failed to report the node tuning condition. %v", err)
+	}
+
+	// The daemons held by the canary upgrade are upgraded once the upgrade is approved
+	canary, err := controller.GetCanaryUpgrade(c.ClusterInfo.Context, c.context, c.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the canary upgrade settings")
+	}
+	if err := canary.Reset(); err != nil {
+		return err
+	}
+
 	// Start the mon pods
 	controller.UpdateCondition(c.context, c.namespacedName, cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, "Configuring Ceph Mons")
 	clusterInfo, err := c.mons.Start(c.ClusterInfo, rookImage, cephVersion, *c.Spec)
@@ -119,6 +147,16 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 		return errors.Wrap(err, "failed to execute post actions after all the ceph monitors started")
 	}
 
+	// Update the other daemons only after all the mons were migrated when the host networking changed
+	err = c.reconcileNetworkMigration()
+	if err != nil {
+		if errors.Is(err, errNetworkMigrationInProgress) {
+			controller.UpdateCondition(c.context, c.namespacedName, cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, "Migrating Ceph Mons to the new network")
+			return err
+		}
+		return errors.Wrap(err, "failed to reconcile the network migration")
+	}
+
 	// Start Ceph manager
 	controller.UpdateCondition(c.context, c.namespacedName, cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, "Configuring Ceph Mgr(s)")
 	mgrs := mgr.New(c.context, c.ClusterInfo, *c.Spec, rookImage)
@@ -142,6 +180,10 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 		}
 	}
 
+	if err := c.completeNetworkMigration(); err != nil {
+		return errors.Wrap(err, "failed to complete the network migration")
+	}
+
 	logger.Infof("done reconciling ceph cluster in namespace %q", c.Namespace)
 
 	// We should be done updating by now
@@ -150,6 +192,7 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 
 		// reset the isUpgrade flag
 		c.isUpgrade = false
+		c.upgradeFailureNotified = false
 	}
 
 	return nil
@@ -165,6 +208,7 @@ func (c *ClusterController) initializeCluster(cluster *cluster) error {
 		}
 	}
 
+	var migrationErr error
 	clusterInfo, _, _, err := mon.LoadClusterInfo(c.context, c.OpManagerCtx, cluster.Namespace)
 	if err != nil {
 		logger.Infof("clusterInfo not yet found, must be a new cluster")
@@ -192,7 +236,10 @@ func (c *ClusterController) initializeCluster(cluster *cluster) error {
 		}
 
 		err = c.configureLocalCephCluster(cluster)
-		if err != nil {
+		if errors.Is(err, errNetworkMigrationInProgress) {
+			// keep monitoring the mons so that the health check fails them over to the new network
+			migrationErr = err
+		} else if err != nil {
 			controller.UpdateCondition(c.context, c.namespacedName, cephv1.ConditionProgressing, v1.ConditionFalse, cephv1.ClusterProgressingReason, err.Error())
 			return errors.Wrap(err, "failed to configure local ceph cluster")
 		}
@@ -204,7 +251,7 @@ func (c *ClusterController) initializeCluster(cluster *cluster) error {
 
 	// Start the monitoring if not already started
 	c.configureCephMonitoring(cluster, cluster.ClusterInfo)
-	return nil
+	return migrationErr
 }
 
 func (c *ClusterController) configureLocalCephCluster(cluster *cluster) error {
@@ -223,6 +270,10 @@ func (c *ClusterController) configureLocalCephCluster(cluster *cluster) error {
 	// Set the value of isUpgrade based on the image discovery done by detectAndValidateCephVersion()
 	cluster.isUpgrade = isUpgrade
 
+	if err := c.detectAndValidateHotfixVersion(cluster, *cephVersion); err != nil {
+		return errors.Wrap(err, "failed the ceph hotfix version check")
+	}
+
 	if cluster.Spec.IsStretchCluster() {
 		stretchVersion := cephver.CephVersion{Major: 16, Minor: 2, Build: 5}
 		if !cephVersion.IsAtLeast(stretchVersion) {
@@ -236,6 +287,12 @@ func (c *ClusterController) configureLocalCephCluster(cluster *cluster) error {
 	// Run the orchestration
 	err = cluster.reconcileCephDaemons(c.rookImage, *cephVersion)
 	if err != nil {
+		if errors.Is(err, errNetworkMigrationInProgress) {
+			return err
+		}
+		if cluster.isUpgrade {
+			c.notifyUpgradeFailure(cluster, *cephVersion, err)
+		}
 		return errors.Wrap(err, "failed to create cluster")
 	}
 
@@ -244,13 +301,42 @@ func (c *ClusterController) configureLocalCephCluster(cluster *cluster) error {
 	return nil
 }
 
+// notifyUpgradeFailure notifies the first failure of the upgrade to the given version
+func (c *ClusterController) notifyUpgradeFailure(cluster *cluster, cephVersion cephver.CephVersion, upgradeErr error) {
+	if cluster.upgradeFailureNotified {
+		return
+	}
+	cluster.upgradeFailureNotified = true
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.client.Get(c.OpManagerCtx, cluster.namespacedName, cephCluster); err != nil {
+		logger.Errorf("failed to get ceph cluster %q to notify the upgrade failure. %v", cluster.namespacedName.String(), err)
+		return
+	}
+	message := fmt.Sprintf("failed to upgrade the cluster to ceph version %q. %v", cephVersion.String(), upgradeErr)
+	if err := notification.Notify(c.context, cephCluster, notification.UpgradeFailed, notification.SeverityCritical, message); err != nil {
+		logger.Errorf("failed to notify the upgrade failure. %v", err)
+	}
+}
+
 // Validate the cluster Specs
 func preClusterStartValidation(cluster *cluster) error {
+	if cluster.Spec.Profile != "" {
+		logger.Infof("applying the defaults of the %q profile to the cluster", cluster.Spec.Profile)
+		cluster.Spec.ApplyProfileDefaults()
+	}
 	if cluster.Spec.Mon.Count == 0 {
 		logger.Warningf("mon count should be at least 1, will use default value of %d", mon.DefaultMonCount)
 		cluster.Spec.Mon.Count = mon.DefaultMonCount
 	}
-	if !cluster.Spec.Mon.AllowMultiplePerNode {
+	if err := validateMinimumResources(cluster.Spec); err != nil {
+		return err
+	}
+	if err := mon.ValidateAutoScale(*cluster.Spec); err != nil {
+		return errors.Wrap(err, "invalid mon autoscale settings")
+	}
+	// the scaled mon count is capped at the number of nodes that can run the mons when allowMultiplePerNode is false
+	if !cluster.Spec.Mon.AllowMultiplePerNode && !cluster.Spec.IsMonAutoScaled() {
 		// Check that there are enough nodes to have a chance of starting the requested number of mons
 		nodes, err := cluster.context.Clientset.CoreV1().Nodes().List(cluster.ClusterInfo.Context, metav1.ListOptions{})
 		if err == nil && len(nodes.Items) < cluster.Spec.Mon.Count {
@@ -260,6 +346,15 @@ func preClusterStartValidation(cluster *cluster) error {
 	if err := validateStretchCluster(cluster); err != nil {
 		return err
 	}
+	if err := cluster.Spec.DNS.Validate(); err != nil {
+		return errors.Wrap(err, "invalid dns settings")
+	}
+	if err := cluster.Spec.ContainerOverrides.Validate(); err != nil {
+		return errors.Wrap(err, "invalid container overrides")
+	}
+	if err := cluster.Spec.Network.ValidateIPFamilies(); err != nil {
+		return errors.Wrap(err, "invalid network ip families")
+	}
 	if cluster.Spec.Network.IsMultus() {
 		_, isPublic := cluster.Spec.Network.Selectors[config.PublicNetworkSelectorKeyName]
 		_, isCluster := cluster.Spec.Network.Selectors[config.ClusterNetworkSelectorKeyName]
@@ -303,6 +398,25 @@ func preClusterStartValidation(cluster *cluster) error {
 	return nil
 }
 
+// validateMinimumResources applies the minimum resources policy of the cluster to the memory requests and limits
+// below the minimum viable memory of the daemons
+func validateMinimumResources(spec *cephv1.ClusterSpec) error {
+	below := spec.ResourcesBelowMinimum()
+	if len(below) == 0 {
+		return nil
+	}
+	switch spec.MinimumResourcesPolicy {
+	case cephv1.MinimumResourcesPolicyReject:
+		return errors.Errorf("resources below the minimum viable memory of the daemons with minimumResourcesPolicy %q: %s", spec.MinimumResourcesPolicy, strings.Join(below, ", "))
+	case cephv1.MinimumResourcesPolicyRaise:
+		logger.Infof("raising the resources below the minimum viable memory of the daemons: %s", strings.Join(below, ", "))
+		spec.RaiseResourcesToMinimum()
+	default:
+		logger.Warningf("the daemons may be OOM-killed under load, such as during a backfill. set minimumResourcesPolicy to %q to raise their memory to the minimum: %s", cephv1.MinimumResourcesPolicyRaise, strings.Join(below, ", "))
+	}
+	return nil
+}
+
 func validateStretchCluster(cluster *cluster) error {
 	if !cluster.Spec.IsStretchCluster() {
 		return nil
@@ -459,6 +573,11 @@ func (c *cluster) postMonStartupActions() error {
 		return errors.Wrap(err, "failed to enable Ceph messenger version 2")
 	}
 
+	// Enforce the msgr2 protocol and the encryption of the connections if required
+	if err := c.mons.ConfigureConnections(); err != nil {
+		return errors.Wrap(err, "failed to configure the connections")
+	}
+
 	crushRoot := client.GetCrushRootFromSpec(c.Spec)
 	if crushRoot != "default" {
 		// Remove the root=default and replicated_rule which are created by
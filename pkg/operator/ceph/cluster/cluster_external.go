@@ -27,7 +27,9 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/cluster/crash"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/csi"
 	"github.com/rook/rook/pkg/operator/k8sutil"
@@ -68,10 +70,11 @@ func (c *ClusterController) configureExternalCephCluster(cluster *cluster) error
 	// Validate versions (local and external)
 	// If no image is specified we don't perform any checks
 	if cluster.Spec.CephVersion.Image != "" {
-		_, _, err = c.detectAndValidateCephVersion(cluster)
+		version, _, err := c.detectAndValidateCephVersion(cluster)
 		if err != nil {
 			return errors.Wrap(err, "failed to detect and validate ceph version")
 		}
+		cluster.ClusterInfo.CephVersion = *version
 
 		// Write the rook-config-override configmap (used by various daemons to apply config overrides)
 		// If we don't do this, daemons will never start, waiting forever for this configmap to be present
@@ -126,6 +129,12 @@ func (c *ClusterController) configureExternalCephCluster(cluster *cluster) error
 		}
 	}
 
+	if cluster.Spec.IsContributor() {
+		if err := c.configureContributedOSDs(cluster); err != nil {
+			return errors.Wrap(err, "failed to configure the osds contributed to the external cluster")
+		}
+	}
+
 	// enable monitoring if `monitoring: enabled: true`
 	// We need the Ceph version
 	if cluster.Spec.Monitoring.Enabled {
@@ -149,6 +158,26 @@ func (c *ClusterController) configureExternalCephCluster(cluster *cluster) error
 	return nil
 }
 
+// configureContributedOSDs runs the OSDs of the storage spec in this Kubernetes cluster and adds them to the
+// external cluster. The OSDs are created with the admin keyring of the external cluster, like the OSDs of a
+// local cluster.
+func (c *ClusterController) configureContributedOSDs(cluster *cluster) error {
+	if cluster.ClusterInfo.CephCred.Username != client.AdminUsername {
+		return errors.Errorf("the admin key of the external cluster is required to contribute osds, found the key of %q", cluster.ClusterInfo.CephCred.Username)
+	}
+	if err := keyring.GetSecretStore(c.context, cluster.ClusterInfo, cluster.ownerInfo).Admin().CreateOrUpdate(cluster.ClusterInfo); err != nil {
+		return errors.Wrap(err, "failed to save admin keyring secret")
+	}
+
+	opcontroller.UpdateCondition(c.context, c.namespacedName, cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, "Configuring the Ceph OSDs contributed to the external cluster")
+	osds := osd.New(c.context, cluster.ClusterInfo, *cluster.Spec, c.rookImage)
+	if err := osds.Start(); err != nil {
+		return errors.Wrap(err, "failed to start ceph osds")
+	}
+	logger.Infof("done reconciling the osds contributed to the external cluster in namespace %q", cluster.Namespace)
+	return nil
+}
+
 func purgeExternalCluster(clientset kubernetes.Interface, namespace string) {
 	ctx := context.TODO()
 	// Purge the config maps
@@ -188,6 +217,11 @@ func validateExternalClusterSpec(cluster *cluster) error {
 		}
 	}
 
+	// the contributed osds run with the image of the spec
+	if cluster.Spec.IsContributor() && cluster.Spec.CephVersion.Image == "" {
+		return errors.New("cephVersion.image must be specified to contribute osds to the external cluster")
+	}
+
 	// Validate external services port
 	if cluster.Spec.Monitoring.Enabled {
 		if cluster.Spec.Monitoring.ExternalMgrPrometheusPort == 0 {
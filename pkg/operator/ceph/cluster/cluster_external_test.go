@@ -43,4 +43,11 @@ func TestValidateExternalClusterSpec(t *testing.T) {
 	assert.NoError(t, err, err)
 	assert.Equal(t, uint16(9283), c.Spec.Monitoring.ExternalMgrPrometheusPort)
 
+	// the contributed osds need an image
+	c.Spec.External = cephv1.ExternalSpec{Enable: true, Contributor: true}
+	err = validateExternalClusterSpec(c)
+	assert.NoError(t, err)
+	c.Spec.CephVersion.Image = ""
+	err = validateExternalClusterSpec(c)
+	assert.Error(t, err)
 }
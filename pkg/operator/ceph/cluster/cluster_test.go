@@ -24,6 +24,9 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	testop "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestPreClusterStartValidation(t *testing.T) {
@@ -69,3 +72,31 @@ func TestPreClusterStartValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMinimumResources(t *testing.T) {
+	newSpec := func(policy cephv1.MinimumResourcesPolicy) *cephv1.ClusterSpec {
+		return &cephv1.ClusterSpec{
+			MinimumResourcesPolicy: policy,
+			Resources: cephv1.ResourceSpec{
+				cephv1.ResourcesKeyOSD: {Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}},
+			},
+		}
+	}
+
+	memoryOf := func(list v1.ResourceList) string {
+		memory := list[v1.ResourceMemory]
+		return memory.String()
+	}
+
+	// the resources below the minimum are only reported by default
+	spec := newSpec("")
+	assert.NoError(t, validateMinimumResources(spec))
+	assert.Equal(t, "1Gi", memoryOf(spec.Resources[cephv1.ResourcesKeyOSD].Requests))
+
+	spec = newSpec(cephv1.MinimumResourcesPolicyReject)
+	assert.Error(t, validateMinimumResources(spec))
+
+	spec = newSpec(cephv1.MinimumResourcesPolicyRaise)
+	assert.NoError(t, validateMinimumResources(spec))
+	assert.Equal(t, "2Gi", memoryOf(spec.Resources[cephv1.ResourcesKeyOSD].Requests))
+}
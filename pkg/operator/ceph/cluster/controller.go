@@ -86,7 +86,6 @@ type ClusterController struct {
 	rookImage        string
 	clusterMap       map[string]*cluster
 	csiConfigMutex   *sync.Mutex
-	osdChecker       *osd.OSDHealthMonitor
 	client           client.Client
 	namespacedName   types.NamespacedName
 	recorder         *k8sutil.EventReporter
@@ -105,6 +104,7 @@ type ReconcileCephCluster struct {
 // Add creates a new CephCluster Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, ctx *clusterd.Context, clusterController *ClusterController, opManagerContext context.Context) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, ctx, clusterController, opManagerContext), ctx)
 }
 
@@ -258,6 +258,9 @@ func (r *ReconcileCephCluster) reconcile(request reconcile.Request) (reconcile.R
 	// Do reconcile here!
 	ownerInfo := k8sutil.NewOwnerInfo(cephCluster, r.scheme)
 	if err := r.clusterController.reconcileCephCluster(cephCluster, ownerInfo); err != nil {
+		if errors.Is(err, errNetworkMigrationInProgress) {
+			return waitForNetworkMigration, cephCluster, nil
+		}
 		return reconcile.Result{}, cephCluster, errors.Wrapf(err, "failed to reconcile cluster %q", cephCluster.Name)
 	}
 
@@ -284,11 +287,24 @@ func (r *ReconcileCephCluster) reconcileDelete(cephCluster *cephv1.CephCluster)
 	}
 	reporting.ReportDeletionNotBlockedDueToDependents(logger, r.client, r.clusterController.recorder, cephCluster)
 
+	// The daemons keep running without the CephCluster if their resources are orphaned
+	orphan := opcontroller.IsOrphanDeletionPolicy(cephCluster.Spec.DeletionPolicy)
+	if orphan {
+		orphaned, err := opcontroller.OrphanGeneratedResources(r.opManagerContext, r.client, cephCluster)
+		if err != nil {
+			return reconcile.Result{}, cephCluster, errors.Wrapf(err, "failed to orphan the resources of CephCluster %q", nsName.String())
+		}
+		reporting.ReportResourcesOrphaned(logger, r.client, r.clusterController.recorder, cephCluster, orphaned)
+		if cephCluster.Spec.CleanupPolicy.HasDataDirCleanPolicy() {
+			logger.Warningf("skipping the cleanup of the hosts of CephCluster %q since its resources are orphaned", nsName.String())
+		}
+	}
+
 	doCleanup := true
 
 	// Start cluster clean up only if cleanupPolicy is applied to the ceph cluster
 	internalCtx := context.Context(r.opManagerContext)
-	if cephCluster.Spec.CleanupPolicy.HasDataDirCleanPolicy() && !cephCluster.Spec.External.Enable {
+	if cephCluster.Spec.CleanupPolicy.HasDataDirCleanPolicy() && !cephCluster.Spec.External.Enable && !orphan {
 		monSecret, clusterFSID, err := r.clusterController.getCleanUpDetails(cephCluster.Namespace)
 		if err != nil {
 			logger.Warningf("failed to get mon secret. skip cluster cleanup. remove finalizer. %v", err)
@@ -344,7 +360,7 @@ func (c *ClusterController) reconcileCephCluster(clusterObj *cephv1.CephCluster,
 	cluster, ok := c.clusterMap[clusterObj.Namespace]
 	if !ok {
 		// It's a new cluster so let's populate the struct
-		cluster = newCluster(clusterObj, c.context, c.csiConfigMutex, ownerInfo)
+		cluster = newCluster(clusterObj, c.context, c.csiConfigMutex, ownerInfo, c.recorder)
 	}
 	cluster.namespacedName = c.namespacedName
 
@@ -374,15 +390,9 @@ func (c *ClusterController) requestClusterDelete(cluster *cephv1.CephCluster) (r
 	logger.Infof("cleaning up CephCluster %q", nsName)
 
 	if cluster, ok := c.clusterMap[cluster.Namespace]; ok {
-		// We used to stop the bucket controller here but when we get a DELETE event for the CephCluster
-		// we will reload the CRD manager anyway so the bucket controller go routine will be stopped
-		// since the op manager context is cancelled.
-		// close the goroutines watching the health of the cluster (mons, osds, ceph status)
-		for _, daemon := range monitorDaemonList {
-			if monitoring, ok := cluster.monitoringRoutines[daemon]; ok && monitoring.internalCtx.Err() == nil { // if the context hasn't been cancelled
-				cluster.monitoringRoutines[daemon].internalCancel()
-			}
-		}
+		// The bucket provisioner of the cluster is stopped by the bucket controller when it gets the DELETE
+		// event of the CephCluster.
+		cluster.stopMonitoring()
 	}
 
 	if cluster.Spec.CleanupPolicy.AllowUninstallWithVolumes {
@@ -396,8 +406,9 @@ func (c *ClusterController) requestClusterDelete(cluster *cephv1.CephCluster) (r
 
 	if cluster.Spec.External.Enable {
 		purgeExternalCluster(c.context.Clientset, cluster.Namespace)
-	} else if cluster.Spec.Storage.IsOnPVCEncrypted() && cluster.Spec.Security.KeyManagementService.IsEnabled() {
+	} else if cluster.Spec.Storage.IsOnPVCEncrypted() && cluster.Spec.Security.KeyManagementService.IsEnabled() && !opcontroller.IsOrphanDeletionPolicy(cluster.Spec.DeletionPolicy) {
 		// If the StorageClass retain policy of an encrypted cluster with KMS is Delete we also delete the keys
+		// The keys are kept for the orphaned OSDs
 		// Delete keys from KMS
 		err := c.deleteOSDEncryptionKeyFromKMS(cluster)
 		if err != nil {
@@ -531,11 +542,11 @@ func (c *ClusterController) deleteOSDEncryptionKeyFromKMS(currentCluster *cephv1
 	// Initialize the KMS code
 	kmsConfig := kms.NewConfig(c.context, &currentCluster.Spec, c.clusterMap[currentCluster.Namespace].ClusterInfo)
 
-	// If token auth is used by the KMS we set it as an env variable
-	if currentCluster.Spec.Security.KeyManagementService.IsTokenAuthEnabled() {
-		err := kms.SetTokenToEnvVar(c.context, currentCluster.Spec.Security.KeyManagementService.TokenSecretName, kmsConfig.Provider, currentCluster.Namespace)
+	// If token or kubernetes auth is used by the KMS we set the token as an env variable
+	if currentCluster.Spec.Security.KeyManagementService.IsTokenAuthEnabled() || currentCluster.Spec.Security.KeyManagementService.IsK8sAuthEnabled() {
+		err := kms.SetTokenToEnvVar(c.context, &currentCluster.Spec.Security.KeyManagementService, kmsConfig.Provider, currentCluster.Namespace)
 		if err != nil {
-			return errors.Wrapf(err, "failed to fetch kms token secret %q", currentCluster.Spec.Security.KeyManagementService.TokenSecretName)
+			return errors.Wrap(err, "failed to get the kms token")
 		}
 	}
 
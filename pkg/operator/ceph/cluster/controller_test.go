@@ -26,6 +26,7 @@ import (
 	rookalpha "github.com/rook/rook/pkg/apis/rook.io/v1alpha2"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/agent/flexvolume/attachment"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	testop "github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
@@ -143,6 +144,38 @@ func TestReconcileDeleteCephCluster(t *testing.T) {
 	})
 }
 
+func TestRequestClusterDeleteStopsMonitoring(t *testing.T) {
+	clusterdCtx := &clusterd.Context{Clientset: k8sfake.NewSimpleClientset()}
+	controller := NewClusterController(clusterdCtx, "", &attachment.MockAttachment{})
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "rook-ceph"},
+		Spec: cephv1.ClusterSpec{
+			CleanupPolicy: cephv1.CleanupPolicySpec{AllowUninstallWithVolumes: true},
+		},
+	}
+	monCtx, monCancel := context.WithCancel(context.TODO())
+	osdCtx, osdCancel := context.WithCancel(context.TODO())
+	controller.clusterMap[cephCluster.Namespace] = &cluster{
+		Namespace:      cephCluster.Namespace,
+		namespacedName: types.NamespacedName{Name: cephCluster.Name, Namespace: cephCluster.Namespace},
+		monitoringRoutines: map[string]*clusterHealth{
+			"mon": {internalCtx: monCtx, internalCancel: monCancel},
+			"osd": {internalCtx: osdCtx, internalCancel: osdCancel},
+		},
+		osdChecker: &osd.OSDHealthMonitor{},
+	}
+	running := controller.clusterMap[cephCluster.Namespace]
+
+	_, err := controller.requestClusterDelete(cephCluster)
+	assert.NoError(t, err)
+	assert.Error(t, monCtx.Err())
+	assert.Error(t, osdCtx.Err())
+	assert.Empty(t, running.monitoringRoutines)
+	assert.Nil(t, running.osdChecker)
+	assert.Empty(t, controller.clusterMap)
+}
+
 func Test_checkIfVolumesExist(t *testing.T) {
 	t.Run("flexvolume enabled", func(t *testing.T) {
 		nodeName := "node841"
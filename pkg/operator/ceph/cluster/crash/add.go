@@ -50,6 +50,7 @@ const (
 
 // Add adds a new Controller based on nodedrain.ReconcileNode and registers the relevant watches and handlers
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
@@ -139,7 +140,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 			return []reconcile.Request{}
 		}),
 		),
-		// only enqueue the update event if the pod moved nodes
+		// only enqueue the update event if the pod moved nodes or stopped running its daemon
 		predicate.Funcs{
 			UpdateFunc: func(event event.UpdateEvent) bool {
 				oldPod, ok := event.ObjectOld.(*corev1.Pod)
@@ -150,8 +151,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				if !ok {
 					return false
 				}
-				// only enqueue if the nodename has changed
-				if oldPod.Spec.NodeName == newPod.Spec.NodeName {
+				// only enqueue if the nodename has changed or the pod terminated
+				if oldPod.Spec.NodeName == newPod.Spec.NodeName && isTerminatedPod(oldPod) == isTerminatedPod(newPod) {
 					return false
 				}
 				return true
@@ -180,3 +181,8 @@ func isCephPod(labels map[string]string, podName string) bool {
 
 	return false
 }
+
+// isTerminatedPod returns whether the pod is done, so its daemon doesn't run on its node anymore
+func isTerminatedPod(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
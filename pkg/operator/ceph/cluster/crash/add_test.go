@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestIsCephPod(t *testing.T) {
@@ -41,3 +42,14 @@ func TestIsCephPod(t *testing.T) {
 	b = isCephPod(labels, podName)
 	assert.True(t, b)
 }
+
+func TestIsTerminatedPod(t *testing.T) {
+	pod := &corev1.Pod{}
+	assert.False(t, isTerminatedPod(pod))
+	pod.Status.Phase = corev1.PodRunning
+	assert.False(t, isTerminatedPod(pod))
+	pod.Status.Phase = corev1.PodFailed
+	assert.True(t, isTerminatedPod(pod))
+	pod.Status.Phase = corev1.PodSucceeded
+	assert.True(t, isTerminatedPod(pod))
+}
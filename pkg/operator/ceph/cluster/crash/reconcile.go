@@ -34,6 +34,7 @@ import (
 
 	"github.com/rook/rook/pkg/operator/ceph/file/mds"
 	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
+	"github.com/rook/rook/pkg/operator/ceph/nfs"
 	"github.com/rook/rook/pkg/operator/ceph/object"
 
 	"github.com/coreos/pkg/capnslog"
@@ -149,7 +150,7 @@ func (r *ReconcileNode) reconcile(request reconcile.Request) (reconcile.Result,
 		// If the crash controller is disabled in the spec let's do a noop
 		if cephCluster.Spec.CrashCollector.Disable {
 			deploymentList := &appsv1.DeploymentList{}
-			namespaceListOpts := client.InNamespace(request.Namespace)
+			namespaceListOpts := client.InNamespace(namespace)
 
 			// Try to fetch the list of existing deployment and remove them
 			err := r.client.List(r.opManagerContext, deploymentList, client.MatchingLabels{k8sutil.AppAttr: AppName}, namespaceListOpts)
@@ -202,7 +203,7 @@ func (r *ReconcileNode) reconcile(request reconcile.Request) (reconcile.Result,
 			}
 		}
 
-		// If the node has Ceph pods we create a crash collector
+		// If the node has Ceph pods of the cluster we create a crash collector
 		if hasCephPods {
 			tolerations := uniqueTolerations.ToList()
 			op, err := r.createOrUpdateCephCrash(*node, tolerations, cephCluster, cephVersion)
@@ -213,7 +214,8 @@ func (r *ReconcileNode) reconcile(request reconcile.Request) (reconcile.Result,
 			// If there are no Ceph pods, check that there are no crash collector pods in case Ceph pods moved to another node
 			// Thus the crash collector must be removed from that node
 		} else {
-			err := r.listCrashCollectorAndDelete(request.Name, request.Namespace)
+			// only the crash collector of this cluster is removed, the node may run the daemons of other clusters
+			err := r.listCrashCollectorAndDelete(request.Name, namespace)
 			if err != nil {
 				return reconcile.Result{}, errors.Wrapf(err, "failed to list and delete crash collector deployments on node %q", request.Name)
 			}
@@ -229,7 +231,7 @@ func (r *ReconcileNode) reconcile(request reconcile.Request) (reconcile.Result,
 
 func (r *ReconcileNode) cephPodList() ([]corev1.Pod, error) {
 	cephPods := make([]corev1.Pod, 0)
-	cephAppNames := []string{mon.AppName, mgr.AppName, osd.AppName, object.AppName, mds.AppName, rbd.AppName, mirror.AppName}
+	cephAppNames := []string{mon.AppName, mgr.AppName, osd.AppName, object.AppName, mds.AppName, rbd.AppName, mirror.AppName, nfs.AppName}
 
 	for _, app := range cephAppNames {
 		podList := &corev1.PodList{}
@@ -238,7 +240,13 @@ func (r *ReconcileNode) cephPodList() ([]corev1.Pod, error) {
 			return cephPods, errors.Wrapf(err, "could not list the %q pods", app)
 		}
 
-		cephPods = append(cephPods, podList.Items...)
+		for _, pod := range podList.Items {
+			// the pods that are done, such as evicted pods, don't run a daemon on their node anymore
+			if isTerminatedPod(&pod) {
+				continue
+			}
+			cephPods = append(cephPods, pod)
+		}
 	}
 
 	return cephPods, nil
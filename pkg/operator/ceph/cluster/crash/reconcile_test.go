@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/nfs"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCephPodList(t *testing.T) {
+	pod := func(name, app string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph", Labels: map[string]string{k8sutil.AppAttr: app}},
+			Spec:       corev1.PodSpec{NodeName: "node1"},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(
+		pod("osd-0", osd.AppName, corev1.PodRunning),
+		pod("osd-1", osd.AppName, corev1.PodFailed),
+		pod("nfs-a", nfs.AppName, corev1.PodPending),
+		pod("other", "other", corev1.PodRunning),
+	).Build()
+	r := &ReconcileNode{client: client, opManagerContext: context.TODO()}
+
+	// the evicted pods and the pods of other apps are not ceph daemons on the node
+	pods, err := r.cephPodList()
+	require.NoError(t, err)
+	names := []string{}
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"osd-0", "nfs-a"}, names)
+}
+
+func TestListCrashCollectorAndDelete(t *testing.T) {
+	deployment := func(namespace, node string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      AppName + "-" + node,
+			Namespace: namespace,
+			Labels:    map[string]string{k8sutil.AppAttr: AppName, NodeNameLabel: node},
+		}}
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(
+		deployment("cluster-a", "node1"),
+		deployment("cluster-b", "node1"),
+		deployment("cluster-a", "node2"),
+	).Build()
+	r := &ReconcileNode{client: client, opManagerContext: context.TODO()}
+
+	// only the crash collector of the cluster on the node is removed
+	require.NoError(t, r.listCrashCollectorAndDelete("node1", "cluster-a"))
+	deployments := &appsv1.DeploymentList{}
+	require.NoError(t, client.List(context.TODO(), deployments))
+	remaining := []string{}
+	for _, d := range deployments.Items {
+		remaining = append(remaining, d.Namespace+"/"+d.Name)
+	}
+	assert.ElementsMatch(t, []string{"cluster-b/" + AppName + "-node1", "cluster-a/" + AppName + "-node2"}, remaining)
+}
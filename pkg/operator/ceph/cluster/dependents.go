@@ -35,6 +35,8 @@ var (
 		"CephRBDMirrors",
 		"CephFilesystems",
 		"CephFilesystemMirrors",
+		"CephFilesystemSubVolumeGroups",
+		"CephFilesystemStaticVolumes",
 		"CephObjectStores",
 		"CephObjectStoreUsers",
 		"CephObjectZones",
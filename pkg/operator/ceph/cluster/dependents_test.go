@@ -99,6 +99,28 @@ func TestCephClusterDependents(t *testing.T) {
 		assert.ElementsMatch(t, []string{"fsmirror"}, deps.OfPluralKind("CephFilesystemMirrors"))
 	})
 
+	t.Run("CephFilesystemSubVolumeGroups", func(t *testing.T) {
+		c = newClusterdCtx(
+			&cephv1.CephFilesystemSubVolumeGroup{ObjectMeta: meta("group-a")},
+		)
+		deps, err := CephClusterDependents(c, ns)
+		assert.NoError(t, err)
+		assert.False(t, deps.Empty())
+		assert.ElementsMatch(t, []string{"CephFilesystemSubVolumeGroups"}, deps.PluralKinds())
+		assert.ElementsMatch(t, []string{"group-a"}, deps.OfPluralKind("CephFilesystemSubVolumeGroups"))
+	})
+
+	t.Run("CephFilesystemStaticVolumes", func(t *testing.T) {
+		c = newClusterdCtx(
+			&cephv1.CephFilesystemStaticVolume{ObjectMeta: meta("static-a")},
+		)
+		deps, err := CephClusterDependents(c, ns)
+		assert.NoError(t, err)
+		assert.False(t, deps.Empty())
+		assert.ElementsMatch(t, []string{"CephFilesystemStaticVolumes"}, deps.PluralKinds())
+		assert.ElementsMatch(t, []string{"static-a"}, deps.OfPluralKind("CephFilesystemStaticVolumes"))
+	})
+
 	t.Run("CephObjectStores", func(t *testing.T) {
 		c = newClusterdCtx(
 			&cephv1.CephObjectStore{ObjectMeta: meta("objectstore-1")},
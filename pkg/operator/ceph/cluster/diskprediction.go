@@ -0,0 +1,311 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	diskPredictionModule = "diskprediction_local"
+)
+
+var (
+	defaultDiskPredictionInterval         = time.Hour
+	defaultDiskPredictionWarnThreshold    = 6 * 7 * 24 * time.Hour
+	defaultDiskPredictionMarkOutThreshold = 2 * 7 * 24 * time.Hour
+
+	diskPredictionLabels = []string{"namespace", "osd", "device"}
+
+	osdFailureRiskGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_osd_failure_risk",
+		Help: "Risk of failure of the device of the OSD predicted from its SMART data: 0 for low, 1 for medium and 2 for high",
+	}, diskPredictionLabels)
+	osdLifeExpectancyGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_osd_life_expectancy_seconds",
+		Help: "Earliest time until the device of the OSD is predicted to fail in seconds",
+	}, diskPredictionLabels)
+
+	// failureRiskLevels orders the risks, a known risk always has precedence over an unknown risk
+	failureRiskLevels = map[cephv1.FailureRisk]int{
+		cephv1.FailureRiskUnknown: -1,
+		cephv1.FailureRiskLow:     0,
+		cephv1.FailureRiskMedium:  1,
+		cephv1.FailureRiskHigh:    2,
+	}
+)
+
+func init() {
+	metrics.Registry.MustRegister(osdFailureRiskGauge, osdLifeExpectancyGauge)
+}
+
+// diskPredictionChecker periodically reports the failure risk of the OSDs predicted by the mgr from the SMART data
+// of their devices, which is collected by the devicehealth mgr module, and optionally marks out the OSDs at high risk
+type diskPredictionChecker struct {
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	interval         time.Duration
+	warnThreshold    time.Duration
+	markOutThreshold time.Duration
+	markOut          bool
+	// configured is whether the local prediction of the mgr was enabled
+	configured bool
+	// reported are the labels of the metrics set at the last check, to remove the metrics of the removed OSDs
+	reported map[string][]string
+}
+
+// newDiskPredictionChecker creates a new diskPredictionChecker object
+func newDiskPredictionChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec) *diskPredictionChecker {
+	d := &diskPredictionChecker{
+		context:          context,
+		clusterInfo:      clusterInfo,
+		interval:         defaultDiskPredictionInterval,
+		warnThreshold:    defaultDiskPredictionWarnThreshold,
+		markOutThreshold: defaultDiskPredictionMarkOutThreshold,
+		reported:         map[string][]string{},
+	}
+	if spec := clusterSpec.HealthCheck.DiskPrediction; spec != nil {
+		d.markOut = spec.MarkOut
+		if spec.Interval != nil {
+			logger.Infof("disk prediction check interval is %s", spec.Interval.Duration.String())
+			d.interval = spec.Interval.Duration
+		}
+		if spec.WarnThreshold != nil {
+			d.warnThreshold = spec.WarnThreshold.Duration
+		}
+		if spec.MarkOutThreshold != nil {
+			d.markOutThreshold = spec.MarkOutThreshold.Duration
+		}
+	}
+	return d
+}
+
+// checkDiskPrediction checks the predictions right away and then at each interval
+func (d *diskPredictionChecker) checkDiskPrediction(ctx context.Context) {
+	for {
+		logger.Debug("checking the disk failure predictions")
+		if err := d.check(time.Now().UTC()); err != nil {
+			logger.Warningf("failed to check the disk failure predictions. %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping the disk prediction check in namespace %q", d.clusterInfo.Namespace)
+			d.reportMetrics(nil, time.Time{})
+			return
+
+		case <-time.After(d.interval):
+		}
+	}
+}
+
+// configure enables the local failure prediction of the mgr
+func (d *diskPredictionChecker) configure() error {
+	if err := cephclient.MgrEnableModule(d.context, d.clusterInfo, diskPredictionModule, false); err != nil {
+		return errors.Wrapf(err, "failed to enable mgr module %q", diskPredictionModule)
+	}
+	monStore := opconfig.GetMonStore(d.context, d.clusterInfo)
+	if _, err := monStore.SetIfChanged("global", "device_failure_prediction_mode", "local"); err != nil {
+		return errors.Wrap(err, "failed to set the device failure prediction mode")
+	}
+	return nil
+}
+
+// failureRisk returns the risk of failure of a device from its predicted life expectancy
+func (d *diskPredictionChecker) failureRisk(device cephclient.DeviceInfo, now time.Time) cephv1.FailureRisk {
+	min, _, ok := device.LifeExpectancy()
+	switch {
+	case !ok:
+		return cephv1.FailureRiskUnknown
+	case min.Sub(now) < d.markOutThreshold:
+		return cephv1.FailureRiskHigh
+	case min.Sub(now) < d.warnThreshold:
+		return cephv1.FailureRiskMedium
+	default:
+		return cephv1.FailureRiskLow
+	}
+}
+
+// osdRisks returns the failure risk of each OSD. The risk of an OSD with several devices, such as a metadata device,
+// is the highest risk of its devices.
+func (d *diskPredictionChecker) osdRisks(devices []cephclient.DeviceInfo, now time.Time) map[int]*cephv1.OSDFailureRisk {
+	risks := map[int]*cephv1.OSDFailureRisk{}
+	for _, device := range devices {
+		risk := d.failureRisk(device, now)
+		for _, daemon := range device.Daemons {
+			if !strings.HasPrefix(daemon, "osd.") {
+				continue
+			}
+			id, err := strconv.Atoi(strings.TrimPrefix(daemon, "osd."))
+			if err != nil {
+				continue
+			}
+			if current, ok := risks[id]; ok && failureRiskLevels[current.Risk] >= failureRiskLevels[risk] {
+				continue
+			}
+			osdRisk := &cephv1.OSDFailureRisk{OSD: id, DeviceID: device.DevID, Risk: risk}
+			if len(device.Location) > 0 {
+				osdRisk.Host = device.Location[0].Host
+			}
+			if min, max, ok := device.LifeExpectancy(); ok {
+				osdRisk.LifeExpectancyMin = min.Format(time.RFC3339)
+				osdRisk.LifeExpectancyMax = max.Format(time.RFC3339)
+			}
+			risks[id] = osdRisk
+		}
+	}
+	return risks
+}
+
+// check reports the failure risk of the OSDs in the status of the cluster and in the metrics, and marks out an OSD
+// at high risk if enabled
+func (d *diskPredictionChecker) check(now time.Time) error {
+	if !d.configured {
+		if err := d.configure(); err != nil {
+			return err
+		}
+		d.configured = true
+	}
+
+	devices, err := cephclient.ListDevices(d.context, d.clusterInfo)
+	if err != nil {
+		return err
+	}
+	risks := d.osdRisks(devices, now)
+	d.reportMetrics(devices, now)
+
+	status := &cephv1.DiskPredictionStatus{
+		LastChecked: now.Format(time.RFC3339),
+		OSDsByRisk:  map[cephv1.FailureRisk]int{},
+	}
+	for _, risk := range risks {
+		status.OSDsByRisk[risk.Risk]++
+		if risk.Risk == cephv1.FailureRiskMedium || risk.Risk == cephv1.FailureRiskHigh {
+			status.AtRisk = append(status.AtRisk, *risk)
+		}
+	}
+	sort.Slice(status.AtRisk, func(i, j int) bool { return status.AtRisk[i].OSD < status.AtRisk[j].OSD })
+
+	if len(status.AtRisk) > 0 {
+		if err := d.markOutOSDs(status.AtRisk); err != nil {
+			logger.Errorf("failed to mark out the osds at high risk of failure. %v", err)
+		}
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := d.context.Client.Get(d.clusterInfo.Context, d.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", d.clusterInfo.NamespacedName().String())
+	}
+	cephCluster.Status.DiskPrediction = status
+	if err := reporting.UpdateStatus(d.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the disk prediction status")
+	}
+	return nil
+}
+
+// markOutOSDs reports which OSDs at risk are out, and marks out an OSD at high risk if enabled. A single OSD is
+// marked out per check, once the placement groups are clean, since marking out an OSD moves its data.
+func (d *diskPredictionChecker) markOutOSDs(atRisk []cephv1.OSDFailureRisk) error {
+	osdDump, err := cephclient.GetOSDDump(d.context, d.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd dump")
+	}
+	var toMarkOut *cephv1.OSDFailureRisk
+	for i := range atRisk {
+		_, in, err := osdDump.StatusByID(int64(atRisk[i].OSD))
+		if err != nil {
+			continue
+		}
+		atRisk[i].Out = in != 1
+		if !atRisk[i].Out && atRisk[i].Risk == cephv1.FailureRiskHigh && toMarkOut == nil {
+			toMarkOut = &atRisk[i]
+		}
+	}
+	if !d.markOut || toMarkOut == nil {
+		return nil
+	}
+
+	msg, clean, err := cephclient.IsClusterClean(d.context, d.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if the pgs are clean")
+	}
+	if !clean {
+		logger.Infof("waiting for the pgs to be clean to mark out osd.%d at high risk of failure. %s", toMarkOut.OSD, msg)
+		return nil
+	}
+
+	reason := fmt.Sprintf("device %q is predicted to fail after %s", toMarkOut.DeviceID, toMarkOut.LifeExpectancyMin)
+	logger.Warningf("marking out osd.%d since its %s", toMarkOut.OSD, reason)
+	if _, err := cephclient.OSDOut(d.context, d.clusterInfo, toMarkOut.OSD); err != nil {
+		return errors.Wrapf(err, "failed to mark out osd.%d", toMarkOut.OSD)
+	}
+	toMarkOut.Out = true
+	target := fmt.Sprintf("osd.%d", toMarkOut.OSD)
+	if err := reporting.RecordCorrectiveAction(d.clusterInfo.Context, d.context.Client, d.clusterInfo.NamespacedName(), cephv1.CorrectiveActionOSDMarkedOut, target, reason); err != nil {
+		logger.Warningf("failed to record the mark out of osd.%d. %v", toMarkOut.OSD, err)
+	}
+	return nil
+}
+
+// reportMetrics sets the failure risk gauges of the OSDs of each device, and removes the gauges of the OSDs and the
+// devices that were not found anymore
+func (d *diskPredictionChecker) reportMetrics(devices []cephclient.DeviceInfo, now time.Time) {
+	reported := map[string][]string{}
+	for _, device := range devices {
+		risk := d.failureRisk(device, now)
+		min, _, ok := device.LifeExpectancy()
+		if !ok {
+			continue
+		}
+		for _, daemon := range device.Daemons {
+			if !strings.HasPrefix(daemon, "osd.") {
+				continue
+			}
+			labels := []string{d.clusterInfo.Namespace, strings.TrimPrefix(daemon, "osd."), device.DevID}
+			osdFailureRiskGauge.WithLabelValues(labels...).Set(float64(failureRiskLevels[risk]))
+			osdLifeExpectancyGauge.WithLabelValues(labels...).Set(math.Max(0, min.Sub(now).Seconds()))
+			reported[strings.Join(labels, "/")] = labels
+		}
+	}
+	for key, labels := range d.reported {
+		if _, ok := reported[key]; !ok {
+			osdFailureRiskGauge.DeleteLabelValues(labels...)
+			osdLifeExpectancyGauge.DeleteLabelValues(labels...)
+		}
+	}
+	d.reported = reported
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckDiskPrediction(t *testing.T) {
+	ctx := context.TODO()
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.Context = ctx
+	nsName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+
+	now := time.Date(2021, 10, 15, 10, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	expectancy := func(d time.Duration) string {
+		return now.Add(d).Format("2006-01-02 15:04:05.000000")
+	}
+	// osd.0 has a healthy data device and a metadata device at high risk, osd.1 is at medium risk, osd.2 is at low
+	// risk and osd.3 has no prediction
+	devices := fmt.Sprintf(`[
+		{"devid":"VENDOR_DATA_0","location":[{"host":"node1","dev":"sdb","path":"/dev/sdb"}],"daemons":["osd.0"],"life_expectancy_min":%q,"life_expectancy_max":%q},
+		{"devid":"VENDOR_META_0","location":[{"host":"node1","dev":"sdc","path":"/dev/sdc"}],"daemons":["osd.0"],"life_expectancy_min":%q,"life_expectancy_max":%q},
+		{"devid":"VENDOR_DATA_1","location":[{"host":"node2","dev":"sdb","path":"/dev/sdb"}],"daemons":["osd.1"],"life_expectancy_min":%q,"life_expectancy_max":%q},
+		{"devid":"VENDOR_DATA_2","location":[{"host":"node2","dev":"sdc","path":"/dev/sdc"}],"daemons":["osd.2"],"life_expectancy_min":%q,"life_expectancy_max":%q},
+		{"devid":"VENDOR_DATA_3","location":[{"host":"node3","dev":"sdb","path":"/dev/sdb"}],"daemons":["osd.3","mon.a"]}]`,
+		expectancy(365*day), expectancy(400*day), expectancy(5*day), expectancy(10*day),
+		expectancy(20*day), expectancy(30*day), expectancy(100*day), expectancy(200*day))
+
+	pgsClean := false
+	var markedOut []string
+	monStore := map[string]string{}
+	var modules []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "device" && args[1] == "ls":
+				return devices, nil
+			case args[0] == "mgr" && args[1] == "module" && args[2] == "enable":
+				modules = append(modules, args[3])
+				return "", nil
+			case args[0] == "config" && args[1] == "get":
+				return monStore[args[2]+"/"+args[3]], nil
+			case args[0] == "config" && args[1] == "set":
+				monStore[args[2]+"/"+args[3]] = args[4]
+				return "", nil
+			case args[0] == "osd" && args[1] == "dump":
+				return `{"OSDs": [{"OSD": 0, "Up": 1, "In": 1}, {"OSD": 1, "Up": 1, "In": 1}, {"OSD": 2, "Up": 1, "In": 1}, {"OSD": 3, "Up": 1, "In": 1}]}`, nil
+			case args[0] == "status":
+				if pgsClean {
+					return `{"pgmap":{"num_pgs":10,"pgs_by_state":[{"state_name":"active+clean","count":10}]}}`, nil
+				}
+				return `{"pgmap":{"num_pgs":10,"pgs_by_state":[{"state_name":"active+clean","count":8},{"state_name":"active+undersized+degraded","count":2}]}}`, nil
+			case args[0] == "osd" && args[1] == "out":
+				markedOut = append(markedOut, args[2])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	clusterSpec := &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DiskPrediction: &cephv1.DiskPredictionSpec{Enabled: true}}}
+	d := newDiskPredictionChecker(&clusterd.Context{Client: client, Executor: executor}, clusterInfo, clusterSpec)
+	assert.Equal(t, defaultDiskPredictionInterval, d.interval)
+
+	getStatus := func() *cephv1.DiskPredictionStatus {
+		cephCluster := &cephv1.CephCluster{}
+		require.NoError(t, client.Get(ctx, nsName, cephCluster))
+		return cephCluster.Status.DiskPrediction
+	}
+
+	// the risks are reported without marking out the osds by default
+	require.NoError(t, d.check(now))
+	assert.Equal(t, []string{diskPredictionModule}, modules)
+	assert.Equal(t, "local", monStore["global/device_failure_prediction_mode"])
+	status := getStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, map[cephv1.FailureRisk]int{cephv1.FailureRiskHigh: 1, cephv1.FailureRiskMedium: 1, cephv1.FailureRiskLow: 1, cephv1.FailureRiskUnknown: 1}, status.OSDsByRisk)
+	require.Equal(t, 2, len(status.AtRisk))
+	assert.Equal(t, 0, status.AtRisk[0].OSD)
+	assert.Equal(t, "VENDOR_META_0", status.AtRisk[0].DeviceID)
+	assert.Equal(t, "node1", status.AtRisk[0].Host)
+	assert.Equal(t, cephv1.FailureRiskHigh, status.AtRisk[0].Risk)
+	assert.Equal(t, now.Add(5*day).Format(time.RFC3339), status.AtRisk[0].LifeExpectancyMin)
+	assert.False(t, status.AtRisk[0].Out)
+	assert.Equal(t, 1, status.AtRisk[1].OSD)
+	assert.Equal(t, cephv1.FailureRiskMedium, status.AtRisk[1].Risk)
+	assert.Empty(t, markedOut)
+	// the osds with a prediction have metrics
+	assert.Equal(t, 4, len(d.reported))
+
+	// the osd at high risk is only marked out once the pgs are clean
+	d.markOut = true
+	require.NoError(t, d.check(now))
+	assert.Empty(t, markedOut)
+	pgsClean = true
+	require.NoError(t, d.check(now))
+	assert.Equal(t, []string{"0"}, markedOut)
+	assert.True(t, getStatus().AtRisk[0].Out)
+	assert.Equal(t, 1, len(modules))
+
+	// the metrics of the removed devices are removed
+	devices = "[]"
+	require.NoError(t, d.check(now))
+	assert.Empty(t, d.reported)
+	assert.Empty(t, getStatus().AtRisk)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/discover"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/sys"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// minInventoryDeviceSize is the minimum size of a device for ceph-volume to create an OSD on it
+	minInventoryDeviceSize = 5 * 1024 * 1024 * 1024
+)
+
+var (
+	// defaultDeviceInventoryInterval is the interval between the refreshes of the device inventory
+	defaultDeviceInventoryInterval = 10 * time.Minute
+)
+
+// cephVolumeDevice is the availability of a device reported by "ceph-volume inventory"
+type cephVolumeDevice struct {
+	Available       bool     `json:"available"`
+	RejectedReasons []string `json:"rejected_reasons"`
+}
+
+// deviceInventoryPublisher periodically reports the devices found by the discovery daemon in the status of
+// the CephCluster, with the reasons why they are not available for OSDs
+type deviceInventoryPublisher struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	interval    time.Duration
+	// namespace is the namespace of the discovery configmaps, the namespace of the operator
+	namespace string
+}
+
+// newDeviceInventoryPublisher creates a new deviceInventoryPublisher object
+func newDeviceInventoryPublisher(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec) *deviceInventoryPublisher {
+	p := &deviceInventoryPublisher{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultDeviceInventoryInterval,
+		namespace:   os.Getenv(k8sutil.PodNamespaceEnvVar),
+	}
+	if spec := clusterSpec.Storage.DeviceInventory; spec != nil && spec.Interval != nil {
+		logger.Infof("device inventory interval is %s", spec.Interval.Duration.String())
+		p.interval = spec.Interval.Duration
+	}
+	return p
+}
+
+// publishInventory publishes the inventory right away and then at each interval
+func (p *deviceInventoryPublisher) publishInventory(ctx context.Context) {
+	for {
+		logger.Debug("publishing the device inventory")
+		if err := p.publish(); err != nil {
+			logger.Warningf("failed to publish the device inventory. %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping the device inventory in namespace %q", p.clusterInfo.Namespace)
+			return
+
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+// publish reads the devices of the discovery configmaps and updates the inventory in the status
+func (p *deviceInventoryPublisher) publish() error {
+	devices, err := discover.GetDiscoveredDevices(p.context, p.namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the discovered devices")
+	}
+	inventory := buildDeviceInventory(devices)
+	inventory.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	if len(inventory.Nodes) == 0 {
+		inventory.Message = "no device discovered, the discovery daemon may be disabled with ROOK_ENABLE_DISCOVERY_DAEMON"
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := p.context.Client.Get(p.clusterInfo.Context, p.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", p.clusterInfo.NamespacedName().String())
+	}
+	cephCluster.Status.DeviceInventory = inventory
+	if err := reporting.UpdateStatus(p.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the device inventory status")
+	}
+	return nil
+}
+
+// buildDeviceInventory returns the inventory of the devices, sorted by node and device name
+func buildDeviceInventory(devices map[string][]sys.LocalDisk) *cephv1.DeviceInventoryStatus {
+	inventory := &cephv1.DeviceInventoryStatus{}
+	for node, nodeDevices := range devices {
+		nodeInventory := cephv1.NodeDeviceInventory{Name: node}
+		for _, device := range nodeDevices {
+			nodeInventory.Devices = append(nodeInventory.Devices, inventoryDevice(device))
+		}
+		sort.Slice(nodeInventory.Devices, func(i, j int) bool {
+			return nodeInventory.Devices[i].Name < nodeInventory.Devices[j].Name
+		})
+		inventory.Nodes = append(inventory.Nodes, nodeInventory)
+	}
+	sort.Slice(inventory.Nodes, func(i, j int) bool {
+		return inventory.Nodes[i].Name < inventory.Nodes[j].Name
+	})
+	return inventory
+}
+
+// inventoryDevice returns the inventory of a device. The availability reported by ceph-volume is used when the
+// discovery daemon runs "ceph-volume inventory", otherwise the device must be writable, big enough and empty.
+func inventoryDevice(device sys.LocalDisk) cephv1.InventoryDevice {
+	d := cephv1.InventoryDevice{
+		Name:       device.Name,
+		Size:       device.Size,
+		Type:       device.Type,
+		Rotational: device.Rotational,
+		Model:      device.Model,
+		Serial:     device.Serial,
+	}
+
+	if device.CephVolumeData != "" {
+		cvDevice := cephVolumeDevice{}
+		if err := json.Unmarshal([]byte(device.CephVolumeData), &cvDevice); err == nil {
+			d.Available = cvDevice.Available
+			d.RejectedReasons = cvDevice.RejectedReasons
+			return d
+		}
+		logger.Warningf("failed to unmarshal the ceph-volume inventory of device %q", device.Name)
+	}
+
+	if device.Readonly {
+		d.RejectedReasons = append(d.RejectedReasons, "read-only")
+	}
+	if device.Size < minInventoryDeviceSize {
+		d.RejectedReasons = append(d.RejectedReasons, "Insufficient space (<5GB)")
+	}
+	if len(device.Partitions) > 0 {
+		d.RejectedReasons = append(d.RejectedReasons, fmt.Sprintf("has %d partitions", len(device.Partitions)))
+	}
+	if device.Filesystem != "" {
+		d.RejectedReasons = append(d.RejectedReasons, fmt.Sprintf("has a %s filesystem", device.Filesystem))
+	}
+	d.Available = len(d.RejectedReasons) == 0
+	return d
+}
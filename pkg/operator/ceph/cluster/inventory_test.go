@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	discoverDaemon "github.com/rook/rook/pkg/daemon/discover"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewDeviceInventoryPublisher(t *testing.T) {
+	clusterSpec := &cephv1.ClusterSpec{}
+	p := newDeviceInventoryPublisher(&clusterd.Context{}, cephclient.AdminClusterInfo("ns"), clusterSpec)
+	assert.Equal(t, defaultDeviceInventoryInterval, p.interval)
+
+	clusterSpec.Storage.DeviceInventory = &cephv1.DeviceInventorySpec{Enabled: true, Interval: &metav1.Duration{Duration: time.Minute}}
+	p = newDeviceInventoryPublisher(&clusterd.Context{}, cephclient.AdminClusterInfo("ns"), clusterSpec)
+	assert.Equal(t, time.Minute, p.interval)
+}
+
+func TestInventoryDevice(t *testing.T) {
+	d := inventoryDevice(sys.LocalDisk{Name: "sdb", Size: 10737418240, Type: "disk", Rotational: true, Model: "HDD", Empty: true})
+	assert.Equal(t, cephv1.InventoryDevice{Name: "sdb", Size: 10737418240, Type: "disk", Rotational: true, Model: "HDD", Available: true}, d)
+
+	d = inventoryDevice(sys.LocalDisk{Name: "sdc", Size: 1073741824, Readonly: true, Filesystem: "ext4", Partitions: []sys.Partition{{Name: "sdc1"}}})
+	assert.False(t, d.Available)
+	assert.Equal(t, []string{"read-only", "Insufficient space (<5GB)", "has 1 partitions", "has a ext4 filesystem"}, d.RejectedReasons)
+
+	// the availability reported by ceph-volume is used when known
+	d = inventoryDevice(sys.LocalDisk{Name: "sdd", Size: 10737418240, CephVolumeData: `{"path":"/dev/sdd","available":false,"rejected_reasons":["LVM detected","locked"]}`})
+	assert.False(t, d.Available)
+	assert.Equal(t, []string{"LVM detected", "locked"}, d.RejectedReasons)
+}
+
+func TestPublishDeviceInventory(t *testing.T) {
+	ctx := context.TODO()
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.Context = ctx
+	nsName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := fake.NewSimpleClientset()
+	p := &deviceInventoryPublisher{
+		context:     &clusterd.Context{Client: client, Clientset: clientset},
+		clusterInfo: clusterInfo,
+		namespace:   "rook-ceph",
+	}
+
+	// nothing discovered
+	require.NoError(t, p.publish())
+	require.NoError(t, client.Get(ctx, nsName, cephCluster))
+	require.NotNil(t, cephCluster.Status.DeviceInventory)
+	assert.Empty(t, cephCluster.Status.DeviceInventory.Nodes)
+	assert.Contains(t, cephCluster.Status.DeviceInventory.Message, "no device discovered")
+
+	for node, devices := range map[string][]sys.LocalDisk{
+		"node2": {{Name: "sdb", Size: 10737418240}},
+		"node1": {{Name: "sdc", Size: 10737418240, Filesystem: "xfs"}, {Name: "sdb", Size: 10737418240}},
+	} {
+		data, err := json.Marshal(devices)
+		require.NoError(t, err)
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "local-device-" + node,
+				Namespace: "rook-ceph",
+				Labels:    map[string]string{k8sutil.AppAttr: discoverDaemon.AppName, discoverDaemon.NodeAttr: node},
+			},
+			Data: map[string]string{discoverDaemon.LocalDiskCMData: string(data)},
+		}
+		_, err = clientset.CoreV1().ConfigMaps("rook-ceph").Create(ctx, cm, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, p.publish())
+	cephCluster = &cephv1.CephCluster{}
+	require.NoError(t, client.Get(ctx, nsName, cephCluster))
+	inventory := cephCluster.Status.DeviceInventory
+	require.NotNil(t, inventory)
+	assert.NotEmpty(t, inventory.LastUpdated)
+	assert.Empty(t, inventory.Message)
+	require.Equal(t, 2, len(inventory.Nodes))
+	assert.Equal(t, "node1", inventory.Nodes[0].Name)
+	assert.Equal(t, "sdb", inventory.Nodes[0].Devices[0].Name)
+	assert.True(t, inventory.Nodes[0].Devices[0].Available)
+	assert.Equal(t, "sdc", inventory.Nodes[0].Devices[1].Name)
+	assert.Equal(t, []string{"has a xfs filesystem"}, inventory.Nodes[0].Devices[1].RejectedReasons)
+	assert.Equal(t, "node2", inventory.Nodes[1].Name)
+}
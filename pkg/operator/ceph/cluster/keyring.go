@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+)
+
+var (
+	// defaultKeyringCheckInterval is the interval to check the keys of the secrets against the keys of the ceph users
+	defaultKeyringCheckInterval = 10 * time.Minute
+)
+
+// keyringChecker repairs the keys of the keyring and CSI secrets that differ from the keys of the ceph users
+type keyringChecker struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	recorder    *k8sutil.EventReporter
+	interval    time.Duration
+}
+
+// newKeyringChecker creates a new keyringChecker object
+func newKeyringChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, recorder *k8sutil.EventReporter) *keyringChecker {
+	c := &keyringChecker{
+		context:     context,
+		clusterInfo: clusterInfo,
+		recorder:    recorder,
+		interval:    defaultKeyringCheckInterval,
+	}
+	if interval := clusterSpec.HealthCheck.DaemonHealth.Keyring.Interval; interval != nil {
+		logger.Infof("keyring check interval is %s", interval.Duration.String())
+		c.interval = interval.Duration
+	}
+	return c
+}
+
+// checkKeyrings periodically checks the keys of the secrets
+func (c *keyringChecker) checkKeyrings(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping monitoring of keyrings in namespace %q", c.clusterInfo.Namespace)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debug("checking the keys of the secrets")
+			if err := c.repairKeyrings(); err != nil {
+				logger.Warningf("failed to check the keys of the secrets. %v", err)
+			}
+		}
+	}
+}
+
+// repairKeyrings replaces the keys of the secrets with the keys of the ceph users, which are the keys the
+// daemons and the clients authenticate with
+func (c *keyringChecker) repairKeyrings() error {
+	cephKeys, err := cephclient.AuthListKeys(c.context, c.clusterInfo)
+	if err != nil {
+		return err
+	}
+
+	keyringRepairs, err := keyring.GetSecretStore(c.context, c.clusterInfo, c.clusterInfo.OwnerInfo).RepairKeyringSecrets(cephKeys)
+	c.reportRepairs(keyringRepairs)
+	if err != nil {
+		return errors.Wrap(err, "failed to repair the keyring secrets")
+	}
+	csiRepairs, err := csi.RepairCSISecrets(c.context, c.clusterInfo, cephKeys)
+	c.reportRepairs(csiRepairs)
+	if err != nil {
+		return errors.Wrap(err, "failed to repair the csi secrets")
+	}
+	return nil
+}
+
+func (c *keyringChecker) reportRepairs(repairs []keyring.Repair) {
+	if len(repairs) == 0 || c.recorder == nil {
+		return
+	}
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		logger.Debugf("failed to get ceph cluster %q to report the keyring repairs. %v", c.clusterInfo.NamespacedName().String(), err)
+		return
+	}
+	for _, repair := range repairs {
+		message := fmt.Sprintf("replaced the key of ceph user %q in secret %q, it differed from the key in ceph", repair.User, repair.SecretName)
+		c.recorder.ReportIfNotPresent(cephCluster, v1.EventTypeWarning, string(cephv1.KeyringRepairedReason), message)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+)
+
+const (
+	balancerModeOff = "off"
+
+	balancerMaxMisplacedOption = "target_max_misplaced_ratio"
+	balancerBeginTimeOption    = "mgr/balancer/begin_time"
+	balancerEndTimeOption      = "mgr/balancer/end_time"
+	balancerBeginWeekdayOption = "mgr/balancer/begin_weekday"
+	balancerEndWeekdayOption   = "mgr/balancer/end_weekday"
+)
+
+// the options of the mon store managed with the balancer settings, in the order they are applied
+var balancerOptions = []string{
+	balancerMaxMisplacedOption,
+	balancerBeginTimeOption,
+	balancerEndTimeOption,
+	balancerBeginWeekdayOption,
+	balancerEndWeekdayOption,
+}
+
+// configureBalancerModule configures the balancer module with the balancer settings of the spec. The
+// schedule is enforced by the balancer module itself, so it keeps applying while the operator is down.
+func (c *Cluster) configureBalancerModule() error {
+	balancer := c.spec.Mgr.Balancer
+	if balancer == nil && IsModuleInSpec(c.spec.Mgr.Modules, balancerModuleName) {
+		// the balancer is configured with the settings of the module
+		return nil
+	}
+
+	if err := c.applyBalancerSettings(); err != nil {
+		return errors.Wrap(err, "failed to apply the balancer settings")
+	}
+
+	if balancer == nil {
+		// The balancer module must be configured on Octopus
+		// It is a bit confusing but as of Octopus modules that are in the "always_on_modules" list
+		// are "just" enabled, but still they must be configured to work properly
+		if !c.clusterInfo.CephVersion.IsAtLeastOctopus() {
+			return nil
+		}
+		return c.enableBalancerModule()
+	}
+
+	if balancer.Mode == balancerModeOff {
+		if err := cephclient.SetBalancerActive(c.context, c.clusterInfo, false); err != nil {
+			return errors.Wrapf(err, "failed to turn off mgr %q module", balancerModuleName)
+		}
+		return nil
+	}
+
+	mode := balancerModuleMode
+	if balancer.Mode != "" {
+		mode = balancer.Mode
+	}
+	if err := cephclient.ConfigureBalancerModule(c.context, c.clusterInfo, mode); err != nil {
+		return errors.Wrapf(err, "failed to configure module %q", balancerModuleName)
+	}
+	// the balancer is turned on explicitly since it may have been turned off with the "off" mode before
+	if err := cephclient.SetBalancerActive(c.context, c.clusterInfo, true); err != nil {
+		return errors.Wrapf(err, "failed to turn on mgr %q module", balancerModuleName)
+	}
+	return nil
+}
+
+// applyBalancerSettings sets the options of the balancer settings and removes the others so that the
+// ceph defaults apply again
+func (c *Cluster) applyBalancerSettings() error {
+	balancer := c.spec.Mgr.Balancer
+	if balancer != nil && balancer.MaxMisplacedRatio != nil && (*balancer.MaxMisplacedRatio < 0 || *balancer.MaxMisplacedRatio > 1) {
+		return errors.Errorf("invalid max misplaced ratio %v, must be between 0 and 1", *balancer.MaxMisplacedRatio)
+	}
+
+	settings := balancerSettings(balancer)
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	for _, option := range balancerOptions {
+		value, ok := settings[option]
+		if !ok {
+			if err := monStore.Delete("mgr", option); err != nil {
+				return errors.Wrapf(err, "failed to remove %q", option)
+			}
+			continue
+		}
+		if err := monStore.Set("mgr", option, value); err != nil {
+			return errors.Wrapf(err, "failed to set %q", option)
+		}
+	}
+	return nil
+}
+
+// balancerSettings returns the options of the mon store set by the balancer settings
+func balancerSettings(balancer *cephv1.BalancerSpec) map[string]string {
+	settings := map[string]string{}
+	if balancer == nil {
+		return settings
+	}
+	if balancer.MaxMisplacedRatio != nil {
+		settings[balancerMaxMisplacedOption] = strconv.FormatFloat(*balancer.MaxMisplacedRatio, 'f', -1, 64)
+	}
+	if schedule := balancer.Schedule; schedule != nil {
+		settings[balancerBeginTimeOption] = schedule.BeginTime
+		settings[balancerEndTimeOption] = schedule.EndTime
+		if schedule.BeginWeekday != nil {
+			settings[balancerBeginWeekdayOption] = strconv.Itoa(*schedule.BeginWeekday)
+		}
+		if schedule.EndWeekday != nil {
+			settings[balancerEndWeekdayOption] = strconv.Itoa(*schedule.EndWeekday)
+		}
+	}
+	return settings
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigureBalancerModule(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			// ignore the connection flags of the ceph command
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "--") {
+					args = args[:i]
+					break
+				}
+			}
+			commands = append(commands, strings.Join(args, " "))
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminClusterInfo("mycluster")
+	clusterInfo.CephVersion = cephver.Pacific
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	c := &Cluster{context: &clusterd.Context{Executor: executor, Client: cl}, clusterInfo: clusterInfo}
+
+	// the balancer is turned on in upmap mode and the settings are removed by default
+	assert.NoError(t, c.configureBalancerModule())
+	assert.Equal(t, []string{
+		"config rm mgr target_max_misplaced_ratio",
+		"config rm mgr mgr/balancer/begin_time",
+		"config rm mgr mgr/balancer/end_time",
+		"config rm mgr mgr/balancer/begin_weekday",
+		"config rm mgr mgr/balancer/end_weekday",
+		"osd set-require-min-compat-client luminous",
+		"balancer mode upmap",
+	}, commands)
+
+	// the balancer settings are applied
+	commands = nil
+	ratio := 0.01
+	beginWeekday, endWeekday := 1, 6
+	c.spec.Mgr.Balancer = &cephv1.BalancerSpec{
+		Mode:              "crush-compat",
+		MaxMisplacedRatio: &ratio,
+		Schedule:          &cephv1.BalancerScheduleSpec{BeginTime: "2200", EndTime: "0600", BeginWeekday: &beginWeekday, EndWeekday: &endWeekday},
+	}
+	assert.NoError(t, c.configureBalancerModule())
+	assert.Equal(t, []string{
+		"config set mgr target_max_misplaced_ratio 0.01",
+		"config set mgr mgr/balancer/begin_time 2200",
+		"config set mgr mgr/balancer/end_time 0600",
+		"config set mgr mgr/balancer/begin_weekday 1",
+		"config set mgr mgr/balancer/end_weekday 6",
+		"osd set-require-min-compat-client luminous",
+		"balancer mode crush-compat",
+		"balancer on",
+	}, commands)
+
+	// the max misplaced ratio is validated
+	ratio = 5
+	assert.Error(t, c.configureBalancerModule())
+
+	// the balancer is turned off
+	commands = nil
+	c.spec.Mgr.Balancer = &cephv1.BalancerSpec{Mode: "off"}
+	assert.NoError(t, c.configureBalancerModule())
+	assert.Equal(t, "balancer off", commands[len(commands)-1])
+
+	// the balancer settings cannot be combined with the settings of the module
+	c.spec.Mgr.Modules = []cephv1.Module{{Name: "balancer", Enabled: true}}
+	assert.Error(t, c.configureMgrModules())
+
+	// the balancer is left to the settings of the module
+	commands = nil
+	c.spec.Mgr.Balancer = nil
+	assert.NoError(t, c.configureBalancerModule())
+	assert.Empty(t, commands)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	// a module of the spec is disabled once it crashed the mgr this number of times within the window
+	moduleCrashLoopThreshold = 3
+	moduleCrashLoopWindow    = time.Hour
+)
+
+// checkModuleCrashLoops disables the modules enabled in the spec that repeatedly crashed the mgr. The
+// crashes are attributed to a module by the "mgr_module" of the crash reports, which requires the crash
+// collector.
+func (h *HealthChecker) checkModuleCrashLoops() error {
+	c := h.mgrCluster
+	enabledModules := map[string]bool{}
+	for _, module := range c.spec.Mgr.Modules {
+		if module.Enabled {
+			enabledModules[module.Name] = true
+		}
+	}
+	if len(enabledModules) == 0 {
+		return nil
+	}
+
+	crashes, err := cephclient.GetCrashList(c.context, c.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the crashes")
+	}
+	since := time.Now().Add(-moduleCrashLoopWindow)
+	moduleCrashes := map[string][]string{}
+	for _, crash := range crashes {
+		if !strings.HasPrefix(crash.Entity, "mgr.") || !enabledModules[crash.MgrModule] {
+			continue
+		}
+		timestamp, err := parseCrashTimestamp(crash.Timestamp)
+		if err != nil {
+			logger.Debugf("ignoring crash %q. %v", crash.ID, err)
+			continue
+		}
+		if timestamp.Before(since) {
+			continue
+		}
+		moduleCrashes[crash.MgrModule] = append(moduleCrashes[crash.MgrModule], crash.ID)
+	}
+
+	crashLoopingModules := []string{}
+	for name, crashIDs := range moduleCrashes {
+		if len(crashIDs) >= moduleCrashLoopThreshold {
+			crashLoopingModules = append(crashLoopingModules, name)
+		}
+	}
+	sort.Strings(crashLoopingModules)
+	for _, name := range crashLoopingModules {
+		if err := h.disableCrashLoopingModule(name, moduleCrashes[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// disableCrashLoopingModule disables the module and reports it in the status of the CephCluster so that
+// the module is not enabled again by the next reconcile
+func (h *HealthChecker) disableCrashLoopingModule(name string, crashIDs []string) error {
+	c := h.mgrCluster
+	reason := fmt.Sprintf("crashed the mgr %d times in %s", len(crashIDs), moduleCrashLoopWindow.String())
+	logger.Warningf("disabling mgr module %q that %s", name, reason)
+	if err := cephclient.MgrDisableModule(c.context, c.clusterInfo, name); err != nil {
+		return errors.Wrapf(err, "failed to disable mgr module %q", name)
+	}
+
+	// the crashes are archived so that they are not counted again once the module is enabled again
+	for _, crashID := range crashIDs {
+		if err := cephclient.ArchiveCrash(c.context, c.clusterInfo, crashID); err != nil {
+			logger.Warningf("failed to archive the crash of mgr module %q. %v", name, err)
+		}
+	}
+
+	if err := c.updateDisabledModules(func(disabledModules []string) []string {
+		return append(disabledModules, name)
+	}); err != nil {
+		return errors.Wrapf(err, "failed to report the disabled mgr module %q", name)
+	}
+	if err := reporting.RecordCorrectiveAction(c.clusterInfo.Context, c.context.Client, c.clusterInfo.NamespacedName(), cephv1.CorrectiveActionMgrModuleDisabled, name, reason); err != nil {
+		logger.Warningf("failed to record the disabling of mgr module %q. %v", name, err)
+	}
+	h.reportEvent(cephv1.MgrModuleCrashLoopReason, fmt.Sprintf("disabled mgr module %q that %s", name, reason))
+	return nil
+}
+
+// reconcileDisabledModules returns the modules disabled after crashing the mgr that are still enabled in
+// the spec. The other modules are removed from the status so that they are enabled again by the next
+// reconcile once they are enabled in the spec.
+func (c *Cluster) reconcileDisabledModules() (map[string]bool, error) {
+	enabledModules := map[string]bool{}
+	for _, module := range c.spec.Mgr.Modules {
+		if module.Enabled {
+			enabledModules[module.Name] = true
+		}
+	}
+
+	disabledModules := map[string]bool{}
+	err := c.updateDisabledModules(func(modules []string) []string {
+		kept := []string{}
+		for _, name := range modules {
+			if enabledModules[name] {
+				kept = append(kept, name)
+				disabledModules[name] = true
+			} else {
+				logger.Infof("mgr module %q disabled after crashing the mgr is not enabled in the spec anymore", name)
+			}
+		}
+		return kept
+	})
+	return disabledModules, err
+}
+
+// updateDisabledModules updates the modules disabled after crashing the mgr in the status of the
+// CephCluster, and whether the cluster is degraded because of them
+func (c *Cluster) updateDisabledModules(update func([]string) []string) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.clusterInfo.NamespacedName().String())
+	}
+
+	disabledModules := update(append([]string{}, cephCluster.Status.DisabledMgrModules...))
+	if len(disabledModules) == 0 {
+		disabledModules = nil
+	}
+	changed := false
+	if !reflect.DeepEqual(cephCluster.Status.DisabledMgrModules, disabledModules) {
+		cephCluster.Status.DisabledMgrModules = disabledModules
+		changed = true
+	}
+
+	existing := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	if len(disabledModules) > 0 {
+		message := fmt.Sprintf("mgr module(s) %s disabled after repeatedly crashing the mgr", strings.Join(disabledModules, ", "))
+		if existing == nil || existing.Status != v1.ConditionTrue || existing.Message != message {
+			cephv1.SetStatusCondition(&cephCluster.Status.Conditions, cephv1.Condition{
+				Type:    cephv1.ConditionDegraded,
+				Status:  v1.ConditionTrue,
+				Reason:  cephv1.MgrModuleCrashLoopReason,
+				Message: message,
+			})
+			changed = true
+		}
+	} else if existing != nil && existing.Status == v1.ConditionTrue && existing.Reason == cephv1.MgrModuleCrashLoopReason {
+		cephv1.SetStatusCondition(&cephCluster.Status.Conditions, cephv1.Condition{
+			Type:    cephv1.ConditionDegraded,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.MgrModuleCrashLoopResolvedReason,
+			Message: "no mgr module is disabled after crashing the mgr",
+		})
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the disabled mgr modules")
+	}
+	return nil
+}
+
+// parseCrashTimestamp parses the timestamp of a crash report, e.g. "2021-06-07T09:40:18.546735Z" or
+// "2020-05-18 10:43:15.442213Z" with older releases
+func parseCrashTimestamp(timestamp string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, strings.Replace(timestamp, " ", "T", 1))
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse crash timestamp %q", timestamp)
+	}
+	return t, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckModuleCrashLoops(t *testing.T) {
+	now := time.Now().UTC()
+	crashes := []cephclient.CrashList{
+		// crashes of the module within the window
+		{ID: "1", Entity: "mgr.a", Timestamp: now.Add(-time.Minute).Format("2006-01-02T15:04:05.000000Z"), MgrModule: "mymodule"},
+		{ID: "2", Entity: "mgr.b", Timestamp: now.Add(-10 * time.Minute).Format("2006-01-02T15:04:05.000000Z"), MgrModule: "mymodule"},
+		// a crash of the module before the window
+		{ID: "3", Entity: "mgr.a", Timestamp: now.Add(-2 * time.Hour).Format("2006-01-02T15:04:05.000000Z"), MgrModule: "mymodule"},
+		// crashes of other daemons or modules
+		{ID: "4", Entity: "osd.0", Timestamp: now.Format("2006-01-02T15:04:05.000000Z"), MgrModule: "mymodule"},
+		{ID: "5", Entity: "mgr.a", Timestamp: now.Format("2006-01-02T15:04:05.000000Z"), MgrModule: "othermodule"},
+		{ID: "6", Entity: "mgr.a", Timestamp: now.Format("2006-01-02T15:04:05.000000Z")},
+	}
+	var disabledModules, archivedCrashes []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "crash" && args[1] == "ls":
+				output, err := json.Marshal(crashes)
+				return string(output), err
+			case args[0] == "crash" && args[1] == "archive":
+				archivedCrashes = append(archivedCrashes, args[2])
+			case args[0] == "mgr" && args[1] == "module" && args[2] == "disable":
+				disabledModules = append(disabledModules, args[3])
+			}
+			return "", nil
+		},
+	}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.SetName("test")
+	c := &Cluster{
+		context:     &clusterd.Context{Executor: executor, Client: cl},
+		clusterInfo: clusterInfo,
+		spec: cephv1.ClusterSpec{Mgr: cephv1.MgrSpec{Modules: []cephv1.Module{
+			{Name: "mymodule", Enabled: true},
+			{Name: "othermodule", Enabled: true},
+		}}},
+	}
+	fakeRecorder := record.NewFakeRecorder(5)
+	h := NewHealthChecker(c, k8sutil.NewEventReporter(fakeRecorder))
+
+	// the module is not disabled below the threshold
+	err := h.checkModuleCrashLoops()
+	assert.NoError(t, err)
+	assert.Empty(t, disabledModules)
+
+	// the module is disabled once it reaches the threshold
+	crashes = append(crashes, cephclient.CrashList{ID: "7", Entity: "mgr.a", Timestamp: now.Format("2006-01-02 15:04:05.000000Z"), MgrModule: "mymodule"})
+	err = h.checkModuleCrashLoops()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mymodule"}, disabledModules)
+	assert.ElementsMatch(t, []string{"1", "2", "7"}, archivedCrashes)
+
+	err = cl.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mymodule"}, cephCluster.Status.DisabledMgrModules)
+	degraded := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, v1.ConditionTrue, degraded.Status)
+	assert.Equal(t, cephv1.MgrModuleCrashLoopReason, degraded.Reason)
+	assert.Contains(t, degraded.Message, "mymodule")
+	require.Equal(t, 1, len(cephCluster.Status.CorrectiveActions))
+	assert.Equal(t, cephv1.CorrectiveActionMgrModuleDisabled, cephCluster.Status.CorrectiveActions[0].Type)
+	assert.Equal(t, "mymodule", cephCluster.Status.CorrectiveActions[0].Target)
+	event := <-fakeRecorder.Events
+	assert.Contains(t, event, string(cephv1.MgrModuleCrashLoopReason))
+
+	// the module stays disabled while it is enabled in the spec
+	disabled, err := c.reconcileDisabledModules()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"mymodule": true}, disabled)
+
+	// the cluster is not degraded anymore once the module is removed from the spec
+	c.spec.Mgr.Modules = c.spec.Mgr.Modules[1:]
+	disabled, err = c.reconcileDisabledModules()
+	assert.NoError(t, err)
+	assert.Empty(t, disabled)
+	cephCluster = &cephv1.CephCluster{}
+	err = cl.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster)
+	require.NoError(t, err)
+	assert.Empty(t, cephCluster.Status.DisabledMgrModules)
+	degraded = cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, v1.ConditionFalse, degraded.Status)
+	assert.Equal(t, cephv1.MgrModuleCrashLoopResolvedReason, degraded.Reason)
+}
+
+func TestParseCrashTimestamp(t *testing.T) {
+	expected := time.Date(2021, 6, 7, 9, 40, 18, 546735000, time.UTC)
+	for _, timestamp := range []string{"2021-06-07T09:40:18.546735Z", "2021-06-07 09:40:18.546735Z"} {
+		parsed, err := parseCrashTimestamp(timestamp)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(parsed), fmt.Sprintf("%s != %s", expected, parsed))
+	}
+	_, err := parseCrashTimestamp("yesterday")
+	assert.Error(t, err)
+}
@@ -83,6 +83,13 @@ func (c *Cluster) configureDashboardModules() error {
 			return errors.Wrap(err, "failed to enable mgr dashboard module")
 		}
 	} else {
+		// the read-only account is deleted while the dashboard module is still enabled
+		if err := c.removeGrafana(); err != nil {
+			logger.Errorf("failed to remove the read-only dashboard account and the grafana provisioning. %v", err)
+		}
+		if err := c.deleteDashboardUsers(map[string]bool{}); err != nil {
+			logger.Errorf("failed to delete the dashboard accounts. %v", err)
+		}
 		if err := client.MgrDisableModule(c.context, c.clusterInfo, dashboardModuleName); err != nil {
 			logger.Errorf("failed to disable mgr dashboard module. %v", err)
 		}
@@ -94,6 +101,22 @@ func (c *Cluster) configureDashboardModules() error {
 		return errors.Wrap(err, "failed to initialize dashboard")
 	}
 
+	if err := c.configureGrafana(); err != nil {
+		return errors.Wrap(err, "failed to configure grafana")
+	}
+
+	if err := c.configureDashboardSSO(); err != nil {
+		return errors.Wrap(err, "failed to configure the single sign-on")
+	}
+
+	if err := c.configureDashboardUsers(); err != nil {
+		return errors.Wrap(err, "failed to configure the dashboard accounts")
+	}
+
+	if err := c.configureDashboardObjectGateway(); err != nil {
+		return errors.Wrap(err, "failed to configure the object gateway of the dashboard")
+	}
+
 	for _, daemonID := range c.getDaemonIDs() {
 		changed, err := c.configureDashboardModuleSettings(daemonID)
 		if err != nil {
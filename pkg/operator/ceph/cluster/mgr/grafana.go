@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/exec"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DashboardReadOnlySecretName is the name of the secret with the credentials of the read-only dashboard account
+	// #nosec because of the word `Secret`
+	DashboardReadOnlySecretName = "rook-ceph-dashboard-read-only"
+	// GrafanaProvisioningConfigMapName is the name of the configmap with the provisioning of Grafana
+	GrafanaProvisioningConfigMapName = "rook-ceph-grafana-provisioning"
+	// GrafanaDatasourcesKey is the key of the datasources provisioning in the configmap
+	GrafanaDatasourcesKey = "datasources.yaml"
+	// GrafanaDashboardsKey is the key of the dashboards provisioning in the configmap
+	GrafanaDashboardsKey = "dashboards.yaml"
+
+	dashboardReadOnlyUsername = "rook-grafana"
+	dashboardReadOnlyRole     = "read-only"
+	usernameKeyName           = "username"
+	alreadyExistsErrorCode    = int(syscall.EEXIST)
+
+	// the prometheus service of the monitoring examples
+	defaultPrometheusService = "rook-prometheus"
+	defaultPrometheusPort    = 9090
+	grafanaDashboardsPath    = "/var/lib/grafana/dashboards/ceph"
+)
+
+// configureGrafana generates the read-only dashboard account and the provisioning of Grafana,
+// or removes them if they are not enabled anymore
+func (c *Cluster) configureGrafana() error {
+	if c.spec.Dashboard.Grafana == nil || !c.spec.Dashboard.Grafana.Enabled {
+		return c.removeGrafana()
+	}
+	if !FileBasedPasswordSupported(c.clusterInfo) {
+		logger.Warningf("the read-only dashboard account is not supported with ceph version %q", c.clusterInfo.CephVersion.String())
+		return nil
+	}
+
+	if err := c.createDashboardReadOnlyUser(); err != nil {
+		return errors.Wrap(err, "failed to create the read-only dashboard account")
+	}
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GrafanaProvisioningConfigMapName,
+			Namespace: c.clusterInfo.Namespace,
+		},
+		Data: map[string]string{
+			GrafanaDatasourcesKey: fmt.Sprintf(`apiVersion: 1
+datasources:
+- name: Ceph
+  type: prometheus
+  access: proxy
+  url: %s
+  isDefault: true
+  editable: false
+`, c.prometheusURL()),
+			GrafanaDashboardsKey: fmt.Sprintf(`apiVersion: 1
+providers:
+- name: ceph
+  folder: Ceph
+  type: file
+  disableDeletion: true
+  options:
+    path: %s
+`, grafanaDashboardsPath),
+		},
+	}
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(configMap); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to grafana configmap %q", configMap.Name)
+	}
+	if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Create(c.clusterInfo.Context, configMap, metav1.CreateOptions{}); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrap(err, "failed to create the grafana provisioning configmap")
+		}
+		if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Update(c.clusterInfo.Context, configMap, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to update the grafana provisioning configmap")
+		}
+	}
+	return nil
+}
+
+// prometheusURL returns the URL of the prometheus datasource, which is the prometheus service of the
+// monitoring examples in the rules namespace if it is not set explicitly
+func (c *Cluster) prometheusURL() string {
+	if c.spec.Dashboard.Grafana.PrometheusURL != "" {
+		return c.spec.Dashboard.Grafana.PrometheusURL
+	}
+	namespace := c.clusterInfo.Namespace
+	if c.spec.Monitoring.RulesNamespace != "" {
+		namespace = c.spec.Monitoring.RulesNamespace
+	}
+	return fmt.Sprintf("http://%s.%s.svc:%d", defaultPrometheusService, namespace, defaultPrometheusPort)
+}
+
+// createDashboardReadOnlyUser creates the read-only dashboard account and saves its credentials in a
+// secret. The account is only created once, when its secret does not exist yet.
+func (c *Cluster) createDashboardReadOnlyUser() error {
+	_, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, DashboardReadOnlySecretName, metav1.GetOptions{})
+	if err == nil {
+		logger.Debug("the read-only dashboard account was already created")
+		return nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to get the read-only dashboard secret")
+	}
+
+	password, err := GeneratePassword(passwordLength)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate password")
+	}
+	file, err := util.CreateTempFile(password)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a temporary dashboard password file")
+	}
+	defer func() {
+		if err := os.Remove(file.Name()); err != nil {
+			logger.Errorf("failed to clean up dashboard password file %q. %v", file.Name(), err)
+		}
+	}()
+
+	logger.Infof("creating the read-only dashboard account %q", dashboardReadOnlyUsername)
+	args := []string{"dashboard", "ac-user-create", dashboardReadOnlyUsername, "-i", file.Name(), dashboardReadOnlyRole}
+	_, err = client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		// the account may remain from a previous attempt that failed to save the secret
+		exitCode, parsed := c.exitCode(err)
+		if !parsed || exitCode != alreadyExistsErrorCode {
+			return errors.Wrapf(err, "failed to create dashboard account %q", dashboardReadOnlyUsername)
+		}
+		args = []string{"dashboard", "ac-user-set-password", dashboardReadOnlyUsername, "-i", file.Name()}
+		if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+			return errors.Wrapf(err, "failed to set the password of dashboard account %q", dashboardReadOnlyUsername)
+		}
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DashboardReadOnlySecretName,
+			Namespace: c.clusterInfo.Namespace,
+		},
+		Data: map[string][]byte{
+			usernameKeyName: []byte(dashboardReadOnlyUsername),
+			passwordKeyName: []byte(password),
+		},
+		Type: k8sutil.RookType,
+	}
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(secret); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to dashboard secret %q", secret.Name)
+	}
+	if _, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Create(c.clusterInfo.Context, secret, metav1.CreateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to save the read-only dashboard secret")
+	}
+	logger.Infof("created the read-only dashboard account %q", dashboardReadOnlyUsername)
+	return nil
+}
+
+// removeGrafana deletes the read-only dashboard account if it was created, and the provisioning of Grafana
+func (c *Cluster) removeGrafana() error {
+	err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Delete(c.clusterInfo.Context, GrafanaProvisioningConfigMapName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete the grafana provisioning configmap")
+	}
+
+	_, err = c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, DashboardReadOnlySecretName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get the read-only dashboard secret")
+	}
+	logger.Infof("deleting the read-only dashboard account %q", dashboardReadOnlyUsername)
+	args := []string{"dashboard", "ac-user-delete", dashboardReadOnlyUsername}
+	if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+		return errors.Wrapf(err, "failed to delete dashboard account %q", dashboardReadOnlyUsername)
+	}
+	err = c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Delete(c.clusterInfo.Context, DashboardReadOnlySecretName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete the read-only dashboard secret")
+	}
+	return nil
+}
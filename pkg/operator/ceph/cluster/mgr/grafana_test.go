@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigureGrafana(t *testing.T) {
+	ctx := context.TODO()
+	var commands []string
+	userExists := false
+	exitCodeResponse := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			exitCodeResponse = 0
+			commands = append(commands, strings.Join(args[0:3], " "))
+			if args[1] == "ac-user-create" && userExists {
+				exitCodeResponse = alreadyExistsErrorCode
+				return "", errors.New("user already exists")
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{
+		Namespace:   "myns",
+		CephVersion: cephver.Pacific,
+		OwnerInfo:   cephclient.NewMinimumOwnerInfoWithOwnerRef(),
+		Context:     ctx,
+	}
+	c := &Cluster{clusterInfo: clusterInfo, context: &clusterd.Context{Clientset: clientset, Executor: executor},
+		spec: cephv1.ClusterSpec{
+			Dashboard: cephv1.DashboardSpec{Enabled: true},
+		},
+	}
+	c.exitCode = func(err error) (int, bool) {
+		return exitCodeResponse, exitCodeResponse != 0
+	}
+
+	// nothing to do when grafana is not enabled
+	err := c.configureGrafana()
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+
+	// the read-only account and the provisioning are created
+	c.spec.Dashboard.Grafana = &cephv1.DashboardGrafanaSpec{Enabled: true}
+	c.spec.Monitoring.RulesNamespace = "monitoring"
+	err = c.configureGrafana()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-create rook-grafana"}, commands)
+	secret, err := clientset.CoreV1().Secrets("myns").Get(ctx, DashboardReadOnlySecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "rook-grafana", string(secret.Data["username"]))
+	assert.Equal(t, passwordLength, len(secret.Data["password"]))
+	cm, err := clientset.CoreV1().ConfigMaps("myns").Get(ctx, GrafanaProvisioningConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, cm.Data[GrafanaDatasourcesKey], "url: http://rook-prometheus.monitoring.svc:9090")
+	assert.Contains(t, cm.Data[GrafanaDashboardsKey], "path: /var/lib/grafana/dashboards/ceph")
+
+	// the account is only created once and the prometheus url can be overridden
+	commands = nil
+	c.spec.Dashboard.Grafana.PrometheusURL = "http://prometheus:9090"
+	err = c.configureGrafana()
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+	cm, err = clientset.CoreV1().ConfigMaps("myns").Get(ctx, GrafanaProvisioningConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, cm.Data[GrafanaDatasourcesKey], "url: http://prometheus:9090")
+
+	// the account and the provisioning are removed
+	c.spec.Dashboard.Grafana.Enabled = false
+	err = c.configureGrafana()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-delete rook-grafana"}, commands)
+	_, err = clientset.CoreV1().Secrets("myns").Get(ctx, DashboardReadOnlySecretName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	_, err = clientset.CoreV1().ConfigMaps("myns").Get(ctx, GrafanaProvisioningConfigMapName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// the password of a remaining account is reset
+	commands = nil
+	userExists = true
+	c.spec.Dashboard.Grafana.Enabled = true
+	err = c.configureGrafana()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-create rook-grafana", "dashboard ac-user-set-password rook-grafana"}, commands)
+	_, err = clientset.CoreV1().Secrets("myns").Get(ctx, DashboardReadOnlySecretName, metav1.GetOptions{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/exec"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var (
+	// HealthCheckInterval is the default interval to check if the active mgr is responsive
+	HealthCheckInterval = 60 * time.Second
+	// UnresponsiveTimeout is the default duration the active mgr can be unresponsive before it is failed over
+	UnresponsiveTimeout = 5 * time.Minute
+
+	mgrProbeTimeout = 10 * time.Second
+	// the interval and the number of checks of the new active mgr after a failover
+	failoverWaitInterval = 5 * time.Second
+	failoverWaitRetries  = 12
+
+	// hooks for tests to override
+	probeMgrMetrics     = realProbeMgrMetrics
+	probeMgrAdminSocket = realProbeMgrAdminSocket
+)
+
+// HealthChecker checks that the active mgr is responsive and fails it over to a standby if it is
+// unresponsive for longer than the timeout
+type HealthChecker struct {
+	mgrCluster *Cluster
+	recorder   *k8sutil.EventReporter
+	interval   time.Duration
+	timeout    time.Duration
+	// the active mgr found unresponsive and since when
+	unresponsiveMgr   string
+	unresponsiveSince time.Time
+}
+
+// NewHealthChecker creates a new HealthChecker object
+func NewHealthChecker(mgrCluster *Cluster, recorder *k8sutil.EventReporter) *HealthChecker {
+	h := &HealthChecker{
+		mgrCluster: mgrCluster,
+		recorder:   recorder,
+		interval:   HealthCheckInterval,
+		timeout:    UnresponsiveTimeout,
+	}
+
+	healthCheck := mgrCluster.spec.HealthCheck.DaemonHealth.Manager
+	if healthCheck.Interval != nil {
+		logger.Debugf("ceph mgr status in namespace %q check interval %q", mgrCluster.clusterInfo.Namespace, healthCheck.Interval.Duration.String())
+		h.interval = healthCheck.Interval.Duration
+	}
+	if healthCheck.Timeout != "" {
+		timeout, err := time.ParseDuration(healthCheck.Timeout)
+		if err != nil {
+			logger.Warningf("invalid mgr timeout %q, using the default of %s. %v", healthCheck.Timeout, UnresponsiveTimeout.String(), err)
+		} else {
+			h.timeout = timeout
+		}
+	}
+	return h
+}
+
+// Check periodically checks the health of the active mgr
+func (h *HealthChecker) Check(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping monitoring of mgrs in namespace %q", h.mgrCluster.clusterInfo.Namespace)
+			return
+
+		case <-time.After(h.interval):
+			logger.Debug("checking health of the active mgr")
+			if err := h.checkMgrHealth(); err != nil {
+				logger.Warningf("failed to check the health of the active mgr. %v", err)
+			}
+			if err := h.checkModuleCrashLoops(); err != nil {
+				logger.Warningf("failed to check the crashes of the mgr modules. %v", err)
+			}
+		}
+	}
+}
+
+// checkMgrHealth probes the active mgr and fails it over if it has been unresponsive for longer than the timeout
+func (h *HealthChecker) checkMgrHealth() error {
+	activeName, err := h.mgrCluster.getActiveMgr()
+	if err != nil {
+		return err
+	}
+	if activeName == "" {
+		// kubernetes restarts the mgr pods, there is nothing to fail over
+		h.resetUnresponsiveMgr()
+		return nil
+	}
+
+	probeErr := h.probeMgr(activeName)
+	if probeErr == nil {
+		if h.unresponsiveMgr != "" {
+			logger.Infof("mgr %q is responsive again", h.unresponsiveMgr)
+		}
+		h.resetUnresponsiveMgr()
+		return nil
+	}
+
+	if h.unresponsiveMgr != activeName {
+		logger.Warningf("active mgr %q is unresponsive, it will be failed over if it is still unresponsive after %s. %v", activeName, h.timeout.String(), probeErr)
+		h.unresponsiveMgr = activeName
+		h.unresponsiveSince = time.Now()
+		return nil
+	}
+	if h.timeout == 0 || time.Since(h.unresponsiveSince) < h.timeout {
+		logger.Debugf("active mgr %q is unresponsive since %s. %v", activeName, h.unresponsiveSince.String(), probeErr)
+		return nil
+	}
+
+	return h.failMgr(activeName, probeErr)
+}
+
+func (h *HealthChecker) resetUnresponsiveMgr() {
+	h.unresponsiveMgr = ""
+	h.unresponsiveSince = time.Time{}
+}
+
+// probeMgr checks that the prometheus module and the admin socket of the mgr answer. A mgr without
+// a running pod is not probed since its pod is restarted by kubernetes.
+func (h *HealthChecker) probeMgr(name string) error {
+	c := h.mgrCluster
+	selector := labels.SelectorFromSet(c.selectorLabels(name)).String()
+	pods, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).List(c.clusterInfo.Context, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the pods of mgr %q", name)
+	}
+	scheme := "http"
+	if c.spec.Monitoring.TLS != nil {
+		scheme = "https"
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != v1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		if err := probeMgrMetrics(fmt.Sprintf("%s://%s:%d/", scheme, pod.Status.PodIP, DefaultMetricsPort)); err != nil {
+			return errors.Wrap(err, "the prometheus module does not answer")
+		}
+		if err := probeMgrAdminSocket(c.context, pod, name); err != nil {
+			return errors.Wrap(err, "the admin socket does not answer")
+		}
+		return nil
+	}
+	logger.Debugf("no running pod for mgr %q, not probing it", name)
+	return nil
+}
+
+func realProbeMgrMetrics(url string) error {
+	httpClient := &http.Client{
+		Timeout: mgrProbeTimeout,
+		// the certificate of the metrics is issued for the service rather than the pod IP, and the
+		// probe only checks that the module answers
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, // #nosec G402
+	}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func realProbeMgrAdminSocket(context *clusterd.Context, pod *v1.Pod, name string) error {
+	command := fmt.Sprintf("timeout %d ceph --admin-daemon /run/ceph/ceph-mgr.%s.asok status", int(mgrProbeTimeout.Seconds()), name)
+	_, stderr, err := context.RemoteExecutor.ExecWithOptions(exec.ExecOptions{
+		// Run with env -i to clean env variables in the exec context like the liveness probe
+		Command:       []string{"env", "-i", "sh", "-c", command},
+		Namespace:     pod.Namespace,
+		PodName:       pod.Name,
+		ContainerName: "mgr",
+		CaptureStdout: true,
+		CaptureStderr: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "%s", stderr)
+	}
+	return nil
+}
+
+// failMgr fails the unresponsive mgr over to a standby and points the services to the new active mgr
+func (h *HealthChecker) failMgr(name string, cause error) error {
+	c := h.mgrCluster
+	message := fmt.Sprintf("failing over mgr %q unresponsive for more than %s. %v", name, h.timeout.String(), cause)
+	logger.Warning(message)
+	args := []string{"mgr", "fail", name}
+	if _, err := cephclient.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+		return errors.Wrapf(err, "failed to fail mgr %q", name)
+	}
+	h.resetUnresponsiveMgr()
+	h.reportEvent(cephv1.MgrFailedOverReason, message)
+
+	for i := 0; i < failoverWaitRetries; i++ {
+		activeName, err := c.getActiveMgr()
+		if err != nil {
+			logger.Debugf("failed to get the active mgr after the failover. %v", err)
+		} else if activeName != "" && activeName != name {
+			logger.Infof("mgr %q is active after the failover of mgr %q", activeName, name)
+			return c.reconcileServices(activeName)
+		}
+		time.Sleep(failoverWaitInterval)
+	}
+	logger.Warningf("no mgr is active yet after the failover of mgr %q, the services will be updated by the mgr sidecar", name)
+	return nil
+}
+
+func (h *HealthChecker) reportEvent(reason cephv1.ConditionReason, message string) {
+	if h.recorder == nil {
+		return
+	}
+	c := h.mgrCluster
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		logger.Debugf("failed to get ceph cluster %q to report event %q. %v", c.clusterInfo.NamespacedName().String(), reason, err)
+		return
+	}
+	h.recorder.ReportIfNotPresent(cephCluster, v1.EventTypeWarning, string(reason), message)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewHealthChecker(t *testing.T) {
+	c := &Cluster{clusterInfo: cephclient.AdminClusterInfo("ns")}
+	h := NewHealthChecker(c, nil)
+	assert.Equal(t, HealthCheckInterval, h.interval)
+	assert.Equal(t, UnresponsiveTimeout, h.timeout)
+
+	c.spec.HealthCheck.DaemonHealth.Manager = cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: 30 * time.Second}, Timeout: "2m"}
+	h = NewHealthChecker(c, nil)
+	assert.Equal(t, 30*time.Second, h.interval)
+	assert.Equal(t, 2*time.Minute, h.timeout)
+
+	// the default timeout is used if the timeout is invalid
+	c.spec.HealthCheck.DaemonHealth.Manager.Timeout = "soon"
+	h = NewHealthChecker(c, nil)
+	assert.Equal(t, UnresponsiveTimeout, h.timeout)
+}
+
+func TestCheckMgrHealth(t *testing.T) {
+	originalMetrics, originalAdminSocket := probeMgrMetrics, probeMgrAdminSocket
+	originalRetries := failoverWaitRetries
+	defer func() {
+		probeMgrMetrics, probeMgrAdminSocket = originalMetrics, originalAdminSocket
+		failoverWaitRetries = originalRetries
+	}()
+	failoverWaitRetries = 1
+	var metricsErr, adminSocketErr error
+	var probedURL string
+	probeMgrMetrics = func(url string) error {
+		probedURL = url
+		return metricsErr
+	}
+	probeMgrAdminSocket = func(context *clusterd.Context, pod *v1.Pod, name string) error {
+		return adminSocketErr
+	}
+
+	activeMgr := "a"
+	var failedMgrs []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return fmt.Sprintf(`{"active_name":"%s"}`, activeMgr), nil
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if strings.Join(args[0:2], " ") == "mgr fail" {
+				failedMgrs = append(failedMgrs, args[2])
+				activeMgr = "b"
+			}
+			return "", nil
+		},
+	}
+	clientset := testop.New(t, 1)
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.SetName("test")
+	clusterInfo.CephVersion = cephver.Pacific
+	c := &Cluster{
+		context:     &clusterd.Context{Executor: executor, Clientset: clientset, Client: cl},
+		clusterInfo: clusterInfo,
+		spec:        cephv1.ClusterSpec{Mgr: cephv1.MgrSpec{Count: 2}},
+	}
+	for _, name := range []string{"a", "b"} {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mgr-" + name, Namespace: "ns", Labels: c.getPodLabels(name, true)},
+			Status:     v1.PodStatus{Phase: v1.PodRunning, PodIP: "10.0.0." + name},
+		}
+		_, err := clientset.CoreV1().Pods("ns").Create(context.TODO(), pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	fakeRecorder := record.NewFakeRecorder(5)
+	h := NewHealthChecker(c, k8sutil.NewEventReporter(fakeRecorder))
+
+	// the responsive mgr is not failed over
+	err := h.checkMgrHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://10.0.0.a:9283/", probedURL)
+	assert.Equal(t, "", h.unresponsiveMgr)
+
+	// the unresponsive mgr is not failed over before the timeout
+	metricsErr = errors.New("timeout")
+	err = h.checkMgrHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, "a", h.unresponsiveMgr)
+	err = h.checkMgrHealth()
+	assert.NoError(t, err)
+	assert.Empty(t, failedMgrs)
+
+	// the mgr responsive again is not failed over
+	metricsErr = nil
+	err = h.checkMgrHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, "", h.unresponsiveMgr)
+
+	// the mgr is failed over after the timeout and the services point to the new active mgr
+	adminSocketErr = errors.New("no answer")
+	err = h.checkMgrHealth()
+	assert.NoError(t, err)
+	h.unresponsiveSince = time.Now().Add(-UnresponsiveTimeout)
+	err = h.checkMgrHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, failedMgrs)
+	assert.Equal(t, "", h.unresponsiveMgr)
+	assert.Equal(t, 1, len(fakeRecorder.Events))
+	svc, err := clientset.CoreV1().Services("ns").Get(context.TODO(), AppName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "b", svc.Spec.Selector[controller.DaemonIDLabel])
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	metricsProxyContainerName = "metrics-proxy"
+	// the address and the port of the prometheus module when the metrics proxy serves the metrics port,
+	// so that the plaintext endpoint of the module is not reachable from the pod network
+	metricsModuleAddr = "127.0.0.1"
+	metricsModulePort = 9284
+
+	prometheusServerAddrOption = "mgr/prometheus/server_addr"
+	prometheusServerPortOption = "mgr/prometheus/server_port"
+
+	metricsTLSVolumeName  = "metrics-tls"
+	metricsTLSMountPath   = "/etc/rook/metrics-tls"
+	metricsAuthVolumeName = "metrics-auth"
+	metricsAuthMountPath  = "/etc/rook/metrics-auth"
+
+	// the keys of the tls and the auth secrets
+	metricsCertKey     = "tls.crt"
+	metricsKeyKey      = "tls.key"
+	metricsCAKey       = "ca.crt"
+	metricsTokenKey    = "token"
+	metricsUsernameKey = "username"
+	metricsPasswordKey = "password"
+)
+
+// configurePrometheusModuleAddress binds the prometheus module to the loopback interface when the
+// metrics proxy serves the metrics port, or restores the default address otherwise. It must be
+// configured before the mgr pods are updated since the module only binds at startup.
+func (c *Cluster) configurePrometheusModuleAddress() error {
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	if !c.spec.Monitoring.SecureMetrics() {
+		for _, option := range []string{prometheusServerAddrOption, prometheusServerPortOption} {
+			if err := monStore.Delete("mgr", option); err != nil {
+				return errors.Wrapf(err, "failed to remove %q", option)
+			}
+		}
+		return nil
+	}
+
+	if err := monStore.Set("mgr", prometheusServerAddrOption, metricsModuleAddr); err != nil {
+		return errors.Wrapf(err, "failed to set %q", prometheusServerAddrOption)
+	}
+	if err := monStore.Set("mgr", prometheusServerPortOption, strconv.Itoa(metricsModulePort)); err != nil {
+		return errors.Wrapf(err, "failed to set %q", prometheusServerPortOption)
+	}
+	return nil
+}
+
+// metricsProxyVolumes returns the volumes of the tls and the auth secrets of the metrics proxy
+func (c *Cluster) metricsProxyVolumes() []v1.Volume {
+	volumes := []v1.Volume{}
+	if tlsSpec := c.spec.Monitoring.TLS; tlsSpec != nil {
+		volumes = append(volumes, v1.Volume{
+			Name:         metricsTLSVolumeName,
+			VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: tlsSpec.SecretName}},
+		})
+	}
+	if auth := c.spec.Monitoring.Auth; auth != nil {
+		volumes = append(volumes, v1.Volume{
+			Name:         metricsAuthVolumeName,
+			VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: auth.SecretName}},
+		})
+	}
+	return volumes
+}
+
+// makeMetricsProxyContainer returns the sidecar serving the metrics port of the mgr pod over TLS and/or
+// with authentication in front of the prometheus module. The secrets are mounted rather than passed as
+// env variables so that a renewed certificate or a rotated credential is picked up without a restart.
+func (c *Cluster) makeMetricsProxyContainer() v1.Container {
+	container := v1.Container{
+		Args:  []string{"ceph", "mgr", "metrics-proxy"},
+		Name:  metricsProxyContainerName,
+		Image: c.rookVersion,
+		Env: []v1.EnvVar{
+			{Name: "ROOK_METRICS_PORT", Value: strconv.Itoa(int(DefaultMetricsPort))},
+			{Name: "ROOK_MODULE_PORT", Value: strconv.Itoa(metricsModulePort)},
+		},
+		Ports: []v1.ContainerPort{
+			{
+				Name:          "http-metrics",
+				ContainerPort: int32(DefaultMetricsPort),
+				Protocol:      v1.ProtocolTCP,
+			},
+		},
+		Resources: cephv1.GetMgrSidecarResources(c.spec.Resources),
+	}
+	if c.spec.Monitoring.TLS != nil {
+		container.Env = append(container.Env, v1.EnvVar{Name: "ROOK_TLS_DIR", Value: metricsTLSMountPath})
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{Name: metricsTLSVolumeName, MountPath: metricsTLSMountPath, ReadOnly: true})
+	}
+	if auth := c.spec.Monitoring.Auth; auth != nil {
+		container.Env = append(container.Env,
+			v1.EnvVar{Name: "ROOK_AUTH_TYPE", Value: string(auth.Type)},
+			v1.EnvVar{Name: "ROOK_AUTH_DIR", Value: metricsAuthMountPath})
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{Name: metricsAuthVolumeName, MountPath: metricsAuthMountPath, ReadOnly: true})
+	}
+	return container
+}
+
+// secureMetricsEndpoint configures the endpoint of the service monitor to scrape the metrics over TLS
+// and/or with the credentials of the auth secret
+func (c *Cluster) secureMetricsEndpoint(endpoint *monitoringv1.Endpoint) {
+	if tlsSpec := c.spec.Monitoring.TLS; tlsSpec != nil {
+		serverName := tlsSpec.ServerName
+		if serverName == "" {
+			serverName = fmt.Sprintf("%s.%s.svc", AppName, c.clusterInfo.Namespace)
+		}
+		endpoint.Scheme = "https"
+		endpoint.TLSConfig = &monitoringv1.TLSConfig{
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{
+				CA:         monitoringv1.SecretOrConfigMap{Secret: secretKeySelector(tlsSpec.SecretName, metricsCAKey)},
+				ServerName: serverName,
+			},
+		}
+	}
+
+	auth := c.spec.Monitoring.Auth
+	if auth == nil {
+		return
+	}
+	switch auth.Type {
+	case cephv1.MetricsAuthBearer:
+		endpoint.BearerTokenSecret = *secretKeySelector(auth.SecretName, metricsTokenKey)
+	case cephv1.MetricsAuthBasic:
+		endpoint.BasicAuth = &monitoringv1.BasicAuth{
+			Username: *secretKeySelector(auth.SecretName, metricsUsernameKey),
+			Password: *secretKeySelector(auth.SecretName, metricsPasswordKey),
+		}
+	}
+}
+
+func secretKeySelector(name, key string) *v1.SecretKeySelector {
+	return &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: name}, Key: key}
+}
+
+// MetricsProxy serves the metrics of the prometheus module of the local mgr over TLS and/or with
+// authentication
+type MetricsProxy struct {
+	// the port served by the proxy
+	MetricsPort int
+	// the port of the prometheus module on the loopback interface
+	ModulePort int
+	// the directory of the certificate and the key, or empty to serve plain http
+	TLSDir string
+	// the type of authentication, or empty to serve the metrics without authentication
+	AuthType cephv1.MetricsAuthType
+	// the directory of the credentials
+	AuthDir string
+}
+
+// Run serves the metrics port until the server fails
+func (p *MetricsProxy) Run() error {
+	handler, err := p.handler()
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Addr: fmt.Sprintf(":%d", p.MetricsPort), Handler: handler}
+	if p.TLSDir == "" {
+		logger.Infof("serving the metrics of the prometheus module on port %d", p.MetricsPort)
+		return server.ListenAndServe()
+	}
+
+	// the certificate is loaded for each connection so that a renewed certificate is served right away
+	server.TLSConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(path.Join(p.TLSDir, metricsCertKey), path.Join(p.TLSDir, metricsKeyKey))
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to load the metrics certificate")
+			}
+			return &cert, nil
+		},
+	}
+	logger.Infof("serving the metrics of the prometheus module over TLS on port %d", p.MetricsPort)
+	return server.ListenAndServeTLS("", "")
+}
+
+func (p *MetricsProxy) handler() (http.Handler, error) {
+	switch p.AuthType {
+	case "", cephv1.MetricsAuthBearer, cephv1.MetricsAuthBasic:
+	default:
+		return nil, errors.Errorf("invalid metrics auth type %q", p.AuthType)
+	}
+
+	target := &url.URL{Scheme: "http", Host: net.JoinHostPort(metricsModuleAddr, strconv.Itoa(p.ModulePort))}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the index page of the module is served without authentication so that the operator can
+		// probe the module, it does not expose any metrics
+		if r.URL.Path != "/" {
+			if err := p.authenticate(r); err != nil {
+				logger.Debugf("unauthorized request to %q from %q. %v", r.URL.Path, r.RemoteAddr, err)
+				if p.AuthType == cephv1.MetricsAuthBasic {
+					w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+				}
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+		}
+		r.Header.Del("Authorization")
+		proxy.ServeHTTP(w, r)
+	}), nil
+}
+
+// authenticate checks the credentials of the request against the credentials of the auth secret,
+// which are read for each request so that a rotated credential applies right away
+func (p *MetricsProxy) authenticate(r *http.Request) error {
+	switch p.AuthType {
+	case cephv1.MetricsAuthBearer:
+		token, err := p.readCredential(metricsTokenKey)
+		if err != nil {
+			return err
+		}
+		if !equalCredentials(r.Header.Get("Authorization"), "Bearer "+token) {
+			return errors.New("invalid bearer token")
+		}
+	case cephv1.MetricsAuthBasic:
+		username, password, ok := r.BasicAuth()
+		if !ok {
+			return errors.New("missing basic auth credentials")
+		}
+		expectedUsername, err := p.readCredential(metricsUsernameKey)
+		if err != nil {
+			return err
+		}
+		expectedPassword, err := p.readCredential(metricsPasswordKey)
+		if err != nil {
+			return err
+		}
+		// both credentials are compared to not reveal which one is invalid
+		validUsername := equalCredentials(username, expectedUsername)
+		validPassword := equalCredentials(password, expectedPassword)
+		if !validUsername || !validPassword {
+			return errors.New("invalid basic auth credentials")
+		}
+	}
+	return nil
+}
+
+func (p *MetricsProxy) readCredential(key string) (string, error) {
+	value, err := ioutil.ReadFile(path.Clean(path.Join(p.AuthDir, key)))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %q of the metrics auth secret", key)
+	}
+	credential := strings.TrimSpace(string(value))
+	if credential == "" {
+		return "", errors.Errorf("%q of the metrics auth secret is empty", key)
+	}
+	return credential, nil
+}
+
+func equalCredentials(actual, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurePrometheusModuleAddress(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			// ignore the connection flags of the ceph command
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "--") {
+					args = args[:i]
+					break
+				}
+			}
+			commands = append(commands, strings.Join(args, " "))
+			return "", nil
+		},
+	}
+	c := &Cluster{context: &clusterd.Context{Executor: executor}, clusterInfo: cephclient.AdminClusterInfo("mycluster")}
+
+	// the default address of the module is restored
+	assert.NoError(t, c.configurePrometheusModuleAddress())
+	assert.Equal(t, []string{
+		"config rm mgr mgr/prometheus/server_addr",
+		"config rm mgr mgr/prometheus/server_port",
+	}, commands)
+
+	// the module only listens on the loopback interface behind the proxy
+	commands = nil
+	c.spec.Monitoring.Auth = &cephv1.MetricsAuthSpec{Type: cephv1.MetricsAuthBearer, SecretName: "metrics-token"}
+	assert.NoError(t, c.configurePrometheusModuleAddress())
+	assert.Equal(t, []string{
+		"config set mgr mgr/prometheus/server_addr 127.0.0.1",
+		"config set mgr mgr/prometheus/server_port 9284",
+	}, commands)
+}
+
+func TestMetricsProxyPodSpec(t *testing.T) {
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	spec := cephv1.ClusterSpec{
+		Monitoring: cephv1.MonitoringSpec{
+			TLS:  &cephv1.MetricsTLSSpec{SecretName: "metrics-cert"},
+			Auth: &cephv1.MetricsAuthSpec{Type: cephv1.MetricsAuthBasic, SecretName: "metrics-creds"},
+		},
+	}
+	c := New(&clusterd.Context{}, clusterInfo, spec, "rook/rook:myversion")
+	mgrTestConfig := &mgrConfig{
+		DaemonID:     "a",
+		ResourceName: "rook-ceph-mgr-a",
+		DataPathMap:  config.NewStatelessDaemonDataPathMap(config.MgrType, "a", "ns", "/var/lib/rook/"),
+	}
+
+	d, err := c.makeDeployment(mgrTestConfig)
+	require.NoError(t, err)
+	containers := d.Spec.Template.Spec.Containers
+	require.Equal(t, 2, len(containers))
+
+	// the metrics port moves from the mgr to the proxy
+	for _, port := range containers[0].Ports {
+		assert.NotEqual(t, int32(DefaultMetricsPort), port.ContainerPort)
+	}
+	proxy := containers[1]
+	assert.Equal(t, metricsProxyContainerName, proxy.Name)
+	assert.Equal(t, "rook/rook:myversion", proxy.Image)
+	assert.Equal(t, int32(DefaultMetricsPort), proxy.Ports[0].ContainerPort)
+	assert.Equal(t, 2, len(proxy.VolumeMounts))
+	assert.Contains(t, d.Spec.Template.Spec.Volumes, c.metricsProxyVolumes()[0])
+	assert.Contains(t, d.Spec.Template.Spec.Volumes, c.metricsProxyVolumes()[1])
+	assert.Equal(t, "https", d.Spec.Template.Annotations["prometheus.io/scheme"])
+
+	// no proxy without TLS nor auth
+	c.spec.Monitoring = cephv1.MonitoringSpec{}
+	d, err = c.makeDeployment(mgrTestConfig)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(d.Spec.Template.Spec.Containers))
+	assert.Empty(t, c.metricsProxyVolumes())
+}
+
+func TestSecureMetricsEndpoint(t *testing.T) {
+	c := &Cluster{clusterInfo: cephclient.AdminClusterInfo("ns")}
+
+	endpoint := monitoringv1.Endpoint{Port: serviceMetricName}
+	c.secureMetricsEndpoint(&endpoint)
+	assert.Equal(t, monitoringv1.Endpoint{Port: serviceMetricName}, endpoint)
+
+	c.spec.Monitoring = cephv1.MonitoringSpec{
+		TLS:  &cephv1.MetricsTLSSpec{SecretName: "metrics-cert"},
+		Auth: &cephv1.MetricsAuthSpec{Type: cephv1.MetricsAuthBearer, SecretName: "metrics-token"},
+	}
+	c.secureMetricsEndpoint(&endpoint)
+	assert.Equal(t, "https", endpoint.Scheme)
+	assert.Equal(t, "rook-ceph-mgr.ns.svc", endpoint.TLSConfig.ServerName)
+	assert.Equal(t, *secretKeySelector("metrics-cert", "ca.crt"), *endpoint.TLSConfig.CA.Secret)
+	assert.Equal(t, *secretKeySelector("metrics-token", "token"), endpoint.BearerTokenSecret)
+	assert.Nil(t, endpoint.BasicAuth)
+
+	endpoint = monitoringv1.Endpoint{Port: serviceMetricName}
+	c.spec.Monitoring.TLS.ServerName = "metrics.example.com"
+	c.spec.Monitoring.Auth = &cephv1.MetricsAuthSpec{Type: cephv1.MetricsAuthBasic, SecretName: "metrics-creds"}
+	c.secureMetricsEndpoint(&endpoint)
+	assert.Equal(t, "metrics.example.com", endpoint.TLSConfig.ServerName)
+	assert.Equal(t, *secretKeySelector("metrics-creds", "username"), endpoint.BasicAuth.Username)
+	assert.Equal(t, *secretKeySelector("metrics-creds", "password"), endpoint.BasicAuth.Password)
+}
+
+func TestMetricsProxyAuth(t *testing.T) {
+	module := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the credentials are not forwarded to the module
+		assert.Empty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("metrics of " + r.URL.Path))
+	}))
+	defer module.Close()
+	moduleURL, err := url.Parse(module.URL)
+	require.NoError(t, err)
+	modulePort, err := strconv.Atoi(moduleURL.Port())
+	require.NoError(t, err)
+
+	authDir, err := ioutil.TempDir("", "metrics-auth")
+	require.NoError(t, err)
+	defer os.RemoveAll(authDir)
+	writeCredential := func(key, value string) {
+		require.NoError(t, ioutil.WriteFile(path.Join(authDir, key), []byte(value), 0600))
+	}
+
+	get := func(p *MetricsProxy, urlPath string, setAuth func(r *http.Request)) *httptest.ResponseRecorder {
+		handler, err := p.handler()
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, urlPath, nil)
+		if setAuth != nil {
+			setAuth(r)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("no auth", func(t *testing.T) {
+		p := &MetricsProxy{ModulePort: modulePort}
+		w := get(p, "/metrics", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "metrics of /metrics", w.Body.String())
+	})
+
+	t.Run("bearer", func(t *testing.T) {
+		writeCredential("token", "secret-token\n")
+		p := &MetricsProxy{ModulePort: modulePort, AuthType: cephv1.MetricsAuthBearer, AuthDir: authDir}
+		assert.Equal(t, http.StatusUnauthorized, get(p, "/metrics", nil).Code)
+		assert.Equal(t, http.StatusUnauthorized, get(p, "/metrics", func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") }).Code)
+		assert.Equal(t, http.StatusOK, get(p, "/metrics", func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret-token") }).Code)
+
+		// the index page of the module can be probed without credentials
+		assert.Equal(t, http.StatusOK, get(p, "/", nil).Code)
+
+		// a rotated token applies right away
+		writeCredential("token", "rotated-token")
+		assert.Equal(t, http.StatusUnauthorized, get(p, "/metrics", func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret-token") }).Code)
+		assert.Equal(t, http.StatusOK, get(p, "/metrics", func(r *http.Request) { r.Header.Set("Authorization", "Bearer rotated-token") }).Code)
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		writeCredential("username", "prometheus")
+		writeCredential("password", "secret-password")
+		p := &MetricsProxy{ModulePort: modulePort, AuthType: cephv1.MetricsAuthBasic, AuthDir: authDir}
+		w := get(p, "/metrics", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+		assert.Equal(t, http.StatusUnauthorized, get(p, "/metrics", func(r *http.Request) { r.SetBasicAuth("prometheus", "wrong") }).Code)
+		assert.Equal(t, http.StatusOK, get(p, "/metrics", func(r *http.Request) { r.SetBasicAuth("prometheus", "secret-password") }).Code)
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		p := &MetricsProxy{ModulePort: modulePort, AuthType: cephv1.MetricsAuthBearer, AuthDir: path.Join(authDir, "missing")}
+		assert.Equal(t, http.StatusUnauthorized, get(p, "/metrics", func(r *http.Request) { r.Header.Set("Authorization", "Bearer ") }).Code)
+	})
+
+	t.Run("invalid auth type", func(t *testing.T) {
+		p := &MetricsProxy{ModulePort: modulePort, AuthType: "digest"}
+		_, err := p.handler()
+		assert.Error(t, err)
+	})
+}
@@ -20,6 +20,7 @@ package mgr
 import (
 	"fmt"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -51,7 +52,7 @@ var PrometheusExternalRuleName = "prometheus-ceph-vVERSION-rules-external"
 const (
 	AppName                = "rook-ceph-mgr"
 	serviceAccountName     = "rook-ceph-mgr"
-	maxMgrCount            = 2
+	maxMgrCount            = 5
 	PrometheusModuleName   = "prometheus"
 	crashModuleName        = "crash"
 	PgautoscalerModuleName = "pg_autoscaler"
@@ -63,6 +64,12 @@ const (
 	cephMgrPodMinimumMemory uint64 = 512
 	// DefaultMetricsPort prometheus exporter port
 	DefaultMetricsPort uint16 = 9283
+	// MgrRoleLabel is the label of the mgr pods with their role, either active or standby
+	MgrRoleLabel = "mgr_role"
+	// MgrRoleActive is the role of the active mgr
+	MgrRoleActive = "active"
+	// MgrRoleStandby is the role of the standby mgrs
+	MgrRoleStandby = "standby"
 )
 
 // Cluster represents the Rook and environment configuration settings needed to set up Ceph mgrs.
@@ -118,6 +125,13 @@ func (c *Cluster) Start() error {
 	}
 
 	logger.Infof("start running mgr")
+	if err := c.configurePrometheusModuleAddress(); err != nil {
+		return errors.Wrap(err, "failed to configure the address of the prometheus module")
+	}
+	if err := c.configurePerfCounters(); err != nil {
+		return errors.Wrap(err, "failed to configure the perf counters of the daemons")
+	}
+
 	daemonIDs := c.getDaemonIDs()
 	var deploymentsToWaitFor []*v1.Deployment
 
@@ -220,6 +234,9 @@ func (c *Cluster) Start() error {
 		} else {
 			logger.Infof("prometheusRule deployed")
 		}
+		if err := c.reconcilePerfCountersRule(namespace); err != nil {
+			logger.Errorf("failed to reconcile the recording rules of the perf counters. %v", err)
+		}
 		logger.Debugf("ended monitoring deployment")
 	}
 	return nil
@@ -249,7 +266,8 @@ func (c *Cluster) ReconcileActiveMgrServices(daemonNameToUpdate string) error {
 		currentDaemon := svc.Spec.Selector[controller.DaemonIDLabel]
 		if currentDaemon == daemonNameToUpdate {
 			logger.Infof("mgr services already set to daemon %q, no need to update", daemonNameToUpdate)
-			return nil
+			// the pods may have been restarted since the role labels were set
+			return c.updateMgrRoleLabels(daemonNameToUpdate)
 		}
 		logger.Infof("mgr service currently set to %q, checking if need to update to %q", currentDaemon, daemonNameToUpdate)
 	}
@@ -305,13 +323,51 @@ func (c *Cluster) reconcileServices(activeDaemon string) error {
 		return errors.Wrap(err, "failed to create mgr metrics service")
 	}
 
+	// create the service exposing the ports of all the enabled modules
+	service, err = c.makeModulesService(activeDaemon)
+	if err != nil {
+		return err
+	}
+	if _, err := k8sutil.CreateOrUpdateService(c.context.Clientset, c.clusterInfo.Namespace, service); err != nil {
+		return errors.Wrap(err, "failed to create mgr modules service")
+	}
+
 	// enable monitoring if `monitoring: enabled: true`
 	if c.spec.Monitoring.Enabled {
 		if err := c.EnableServiceMonitor(activeDaemon); err != nil {
 			return errors.Wrap(err, "failed to enable service monitor")
 		}
+		if err := c.reconcileModulesServiceMonitor(); err != nil {
+			return errors.Wrap(err, "failed to reconcile the service monitor of the mgr modules")
+		}
 	}
 
+	return c.updateMgrRoleLabels(activeDaemon)
+}
+
+// updateMgrRoleLabels labels the pod of the active mgr as active and the pods of the other mgrs as
+// standbys, so that the active mgr can be selected by its role whatever the number of mgrs
+func (c *Cluster) updateMgrRoleLabels(activeDaemon string) error {
+	selector := fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)
+	pods, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).List(c.clusterInfo.Context, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrap(err, "failed to list mgr pods")
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		role := MgrRoleStandby
+		if pod.Labels[controller.DaemonIDLabel] == activeDaemon {
+			role = MgrRoleActive
+		}
+		if pod.Labels[MgrRoleLabel] == role {
+			continue
+		}
+		pod.Labels[MgrRoleLabel] = role
+		if _, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).Update(c.clusterInfo.Context, pod, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to set the role of mgr pod %q to %q", pod.Name, role)
+		}
+		logger.Infof("mgr pod %q is %s", pod.Name, role)
+	}
 	return nil
 }
 
@@ -322,12 +378,8 @@ func (c *Cluster) configureModules(daemonIDs []string) {
 	// "crash" is part of the "always_on_modules" list as of Octopus
 	if !c.clusterInfo.CephVersion.IsAtLeastOctopus() {
 		startModuleConfiguration("crash", c.enableCrashModule)
-	} else {
-		// The balancer module must be configured on Octopus
-		// It is a bit confusing but as of Octopus modules that are in the "always_on_modules" list
-		// are "just" enabled, but still they must be configured to work properly
-		startModuleConfiguration("balancer", c.enableBalancerModule)
 	}
+	startModuleConfiguration("balancer", c.configureBalancerModule)
 	startModuleConfiguration("mgr module(s) from the spec", c.configureMgrModules)
 }
 
@@ -377,6 +429,12 @@ func (c *Cluster) enableBalancerModule() error {
 }
 
 func (c *Cluster) configureMgrModules() error {
+	// The modules that repeatedly crashed the mgr stay disabled
+	disabledModules, err := c.reconcileDisabledModules()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the mgr modules disabled after crashing the mgr")
+	}
+
 	// Enable mgr modules from the spec
 	for _, module := range c.spec.Mgr.Modules {
 		if module.Name == "" {
@@ -385,15 +443,27 @@ func (c *Cluster) configureMgrModules() error {
 		if wellKnownModule(module.Name) {
 			return errors.Errorf("cannot configure mgr module %q that is configured with other cluster settings", module.Name)
 		}
+		if module.Name == balancerModuleName && c.spec.Mgr.Balancer != nil {
+			return errors.Errorf("cannot configure mgr module %q that is configured with the balancer settings", module.Name)
+		}
 		minVersion, versionOK := c.moduleMeetsMinVersion(module.Name)
 		if !versionOK {
 			return errors.Errorf("module %q cannot be configured because it requires at least Ceph version %q", module.Name, minVersion.String())
 		}
 
+		if module.Enabled && disabledModules[module.Name] {
+			logger.Warningf("not enabling mgr module %q that repeatedly crashed the mgr. Disable the module in the spec and enable it again to retry", module.Name)
+			continue
+		}
+
 		if module.Enabled {
 			if module.Name == balancerModuleName {
 				// Configure balancer module mode
-				err := cephclient.ConfigureBalancerModule(c.context, c.clusterInfo, balancerModuleMode)
+				mode := balancerModuleMode
+				if m, ok := module.Settings["mode"]; ok {
+					mode = m
+				}
+				err := cephclient.ConfigureBalancerModule(c.context, c.clusterInfo, mode)
 				if err != nil {
 					return errors.Wrapf(err, "failed to configure module %q", module.Name)
 				}
@@ -403,6 +473,10 @@ func (c *Cluster) configureMgrModules() error {
 				return errors.Wrapf(err, "failed to enable mgr module %q", module.Name)
 			}
 
+			if err := c.applyModuleSettings(module); err != nil {
+				return errors.Wrapf(err, "failed to apply the settings of mgr module %q", module.Name)
+			}
+
 			// Configure special settings for individual modules that are enabled
 			switch module.Name {
 			case PgautoscalerModuleName:
@@ -430,6 +504,49 @@ func (c *Cluster) configureMgrModules() error {
 	return nil
 }
 
+// applyModuleSettings sets the settings of the module in the centralized mon configuration database.
+// The settings are set at each reconcile so that they are restored if changed outside of the CephCluster.
+func (c *Cluster) applyModuleSettings(module cephv1.Module) error {
+	settings := moduleSettings(module)
+	if len(settings) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	// apply the settings in a predictable order
+	sort.Strings(keys)
+
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	for _, key := range keys {
+		if key == "" {
+			return errors.New("empty setting name")
+		}
+		option := fmt.Sprintf("mgr/%s/%s", module.Name, key)
+		if err := monStore.Set("mgr", option, settings[key]); err != nil {
+			return errors.Wrapf(err, "failed to set %q", option)
+		}
+	}
+	return nil
+}
+
+// moduleSettings returns the settings of the module, with the server port of the module set to the
+// port of the module spec unless it is already set
+func moduleSettings(module cephv1.Module) map[string]string {
+	if module.Port == 0 {
+		return module.Settings
+	}
+	if _, ok := module.Settings[moduleServerPortSetting]; ok {
+		return module.Settings
+	}
+	settings := map[string]string{moduleServerPortSetting: strconv.Itoa(module.Port)}
+	for key, value := range module.Settings {
+		settings[key] = value
+	}
+	return settings
+}
+
 func (c *Cluster) moduleMeetsMinVersion(name string) (*cephver.CephVersion, bool) {
 	minVersions := map[string]cephver.CephVersion{
 		// Put the modules here, example:
@@ -466,6 +583,7 @@ func (c *Cluster) EnableServiceMonitor(activeDaemon string) error {
 	if c.spec.External.Enable {
 		serviceMonitor.Spec.Endpoints[0].Port = controller.ServiceExternalMetricName
 	}
+	c.secureMetricsEndpoint(&serviceMonitor.Spec.Endpoints[0])
 	err = c.clusterInfo.OwnerInfo.SetControllerReference(serviceMonitor)
 	if err != nil {
 		return errors.Wrapf(err, "failed to set owner reference to service monitor %q", serviceMonitor.Name)
@@ -525,8 +643,10 @@ func applyMonitoringLabels(c *Cluster, serviceMonitor *monitoringv1.ServiceMonit
 					TargetLabel: "managedBy",
 					Replacement: managedBy,
 				}
-				serviceMonitor.Spec.Endpoints[0].RelabelConfigs = append(
-					serviceMonitor.Spec.Endpoints[0].RelabelConfigs, &relabelConfig)
+				for i := range serviceMonitor.Spec.Endpoints {
+					serviceMonitor.Spec.Endpoints[i].RelabelConfigs = append(
+						serviceMonitor.Spec.Endpoints[i].RelabelConfigs, &relabelConfig)
+				}
 			} else {
 				logger.Info("rook.io/managedBy not specified in monitoring labels")
 			}
@@ -38,6 +38,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -237,6 +238,39 @@ func TestMgrSidecarReconcile(t *testing.T) {
 	validateServiceMatches(t, c, "b")
 }
 
+func TestUpdateMgrRoleLabels(t *testing.T) {
+	clientset := testop.New(t, 1)
+	clusterInfo := cephclient.AdminClusterInfo("mycluster")
+	c := &Cluster{context: &clusterd.Context{Clientset: clientset}, clusterInfo: clusterInfo}
+	for _, daemonID := range []string{"a", "b", "c"} {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-mgr-" + daemonID,
+			Namespace: clusterInfo.Namespace,
+			Labels:    c.getPodLabels(daemonID, true),
+		}}
+		_, err := clientset.CoreV1().Pods(clusterInfo.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	roles := func() map[string]string {
+		pods, err := clientset.CoreV1().Pods(clusterInfo.Namespace).List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		roles := map[string]string{}
+		for _, pod := range pods.Items {
+			roles[pod.Name] = pod.Labels[MgrRoleLabel]
+		}
+		return roles
+	}
+
+	err := c.updateMgrRoleLabels("b")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"rook-ceph-mgr-a": "standby", "rook-ceph-mgr-b": "active", "rook-ceph-mgr-c": "standby"}, roles())
+
+	// the labels follow the active mgr
+	err = c.updateMgrRoleLabels("c")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"rook-ceph-mgr-a": "standby", "rook-ceph-mgr-b": "standby", "rook-ceph-mgr-c": "active"}, roles())
+}
+
 func validateServiceMatches(t *testing.T, c *Cluster, expectedActive string) {
 	// The service labels should match the active mgr
 	svc, err := c.context.Clientset.CoreV1().Services(c.clusterInfo.Namespace).Get(context.TODO(), "rook-ceph-mgr", metav1.GetOptions{})
@@ -255,6 +289,8 @@ func TestConfigureModules(t *testing.T) {
 	modulesDisabled := 0
 	configSettings := map[string]string{}
 	lastModuleConfigured := ""
+	var mgrSettings []string
+	balancerMode := ""
 	executor := &exectest.MockExecutor{
 		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
 			logger.Infof("Command: %s %v", command, args)
@@ -271,14 +307,23 @@ func TestConfigureModules(t *testing.T) {
 				if args[0] == "config" && args[1] == "set" && args[2] == "global" {
 					configSettings[args[3]] = args[4]
 				}
+				if args[0] == "config" && args[1] == "set" && args[2] == "mgr" {
+					mgrSettings = append(mgrSettings, args[3]+"="+args[4])
+				}
+				if args[0] == "balancer" && args[1] == "mode" {
+					balancerMode = args[2]
+				}
 			}
 			return "", nil //return "{\"key\":\"mysecurekey\"}", nil
 		},
 	}
 
 	clientset := testop.New(t, 3)
-	context := &clusterd.Context{Executor: executor, Clientset: clientset}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "mycluster"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	context := &clusterd.Context{Executor: executor, Clientset: clientset, Client: cl}
 	clusterInfo := cephclient.AdminClusterInfo("mycluster")
+	clusterInfo.SetName("test")
 	c := &Cluster{
 		context:     context,
 		clusterInfo: clusterInfo,
@@ -322,6 +367,55 @@ func TestConfigureModules(t *testing.T) {
 	assert.Equal(t, 1, modulesDisabled)
 	assert.Equal(t, "pg_autoscaler", lastModuleConfigured)
 	assert.Equal(t, 0, len(configSettings))
+
+	// the settings of an enabled module are applied
+	c.spec.Mgr.Modules = []cephv1.Module{
+		{Name: "devicehealth", Enabled: true, Settings: map[string]string{"retention_period": "86400", "enable_monitoring": "true"}},
+	}
+	assert.NoError(t, c.configureMgrModules())
+	assert.Equal(t, []string{"mgr/devicehealth/enable_monitoring=true", "mgr/devicehealth/retention_period=86400"}, mgrSettings)
+
+	// the settings are applied again at each reconcile
+	mgrSettings = nil
+	assert.NoError(t, c.configureMgrModules())
+	assert.Equal(t, []string{"mgr/devicehealth/enable_monitoring=true", "mgr/devicehealth/retention_period=86400"}, mgrSettings)
+
+	// the settings of a disabled module are not applied
+	mgrSettings = nil
+	c.spec.Mgr.Modules[0].Enabled = false
+	assert.NoError(t, c.configureMgrModules())
+	assert.Empty(t, mgrSettings)
+
+	// the balancer mode can be set with the settings
+	mgrSettings = nil
+	c.spec.Mgr.Modules = []cephv1.Module{
+		{Name: "balancer", Enabled: true, Settings: map[string]string{"mode": "crush-compat"}},
+	}
+	assert.NoError(t, c.configureMgrModules())
+	assert.Equal(t, "crush-compat", balancerMode)
+	assert.Equal(t, []string{"mgr/balancer/mode=crush-compat"}, mgrSettings)
+
+	// a module disabled after crashing the mgr is not enabled again
+	cephCluster.Status.DisabledMgrModules = []string{"mymodule"}
+	err := cl.Status().Update(clusterInfo.Context, cephCluster)
+	require.NoError(t, err)
+	modulesEnabled = 0
+	c.spec.Mgr.Modules = []cephv1.Module{
+		{Name: "mymodule", Enabled: true},
+	}
+	assert.NoError(t, c.configureMgrModules())
+	assert.Equal(t, 0, modulesEnabled)
+
+	// the module is enabled again once it was disabled in the spec
+	c.spec.Mgr.Modules[0].Enabled = false
+	assert.NoError(t, c.configureMgrModules())
+	cephCluster = &cephv1.CephCluster{}
+	err = cl.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster)
+	require.NoError(t, err)
+	assert.Empty(t, cephCluster.Status.DisabledMgrModules)
+	c.spec.Mgr.Modules[0].Enabled = true
+	assert.NoError(t, c.configureMgrModules())
+	assert.Equal(t, 1, modulesEnabled)
 }
 
 func TestMgrDaemons(t *testing.T) {
@@ -338,6 +432,15 @@ func TestMgrDaemons(t *testing.T) {
 	require.Equal(t, 2, len(daemons))
 	assert.Equal(t, "a", daemons[0])
 	assert.Equal(t, "b", daemons[1])
+
+	c.spec.Mgr.Count = 5
+	daemons = c.getDaemonIDs()
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, daemons)
+
+	// the count is capped
+	c.spec.Mgr.Count = 6
+	daemons = c.getDaemonIDs()
+	assert.Equal(t, maxMgrCount, len(daemons))
 }
 
 func TestApplyMonitoringLabels(t *testing.T) {
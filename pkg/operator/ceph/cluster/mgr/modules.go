@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ModulesServiceName is the name of the service exposing the ports of the enabled mgr modules
+	ModulesServiceName = "rook-ceph-mgr-modules"
+
+	moduleServerPortSetting = "server_port"
+	// the maximum length of the name of a service port
+	maxPortNameLength = 15
+)
+
+// modulesServiceLabels returns the labels of the modules service. They differ from the labels of the
+// metrics service so that the service monitor of the metrics service does not select it as well.
+func (c *Cluster) modulesServiceLabels() map[string]string {
+	return controller.AppLabels(ModulesServiceName, c.clusterInfo.Namespace)
+}
+
+// modulePortName returns the name of the service port of a module, e.g. "diskprediction" for the
+// "diskprediction_local" module, as the port names are limited to 15 characters
+func modulePortName(moduleName string) string {
+	name := strings.ReplaceAll(strings.ToLower(moduleName), "_", "-")
+	if len(name) > maxPortNameLength {
+		name = name[:maxPortNameLength]
+	}
+	return strings.Trim(name, "-")
+}
+
+// modulesServicePorts returns the named ports of the modules service: the prometheus metrics, the
+// dashboard if enabled, and the port of each enabled module listening on a port. The ports of the
+// modules that would take a port or a name already used are skipped.
+func (c *Cluster) modulesServicePorts() []v1.ServicePort {
+	ports := []v1.ServicePort{
+		{Name: serviceMetricName, Port: int32(DefaultMetricsPort), Protocol: v1.ProtocolTCP},
+	}
+	if c.spec.Dashboard.Enabled {
+		name := "https-dashboard"
+		if !c.spec.Dashboard.SSL {
+			name = "http-dashboard"
+		}
+		ports = append(ports, v1.ServicePort{Name: name, Port: int32(c.dashboardPort()), Protocol: v1.ProtocolTCP})
+	}
+
+	for _, module := range c.spec.Mgr.Modules {
+		if !module.Enabled || module.Port == 0 {
+			continue
+		}
+		port := v1.ServicePort{Name: modulePortName(module.Name), Port: int32(module.Port), Protocol: v1.ProtocolTCP}
+		if servicePortUsed(ports, port) {
+			logger.Warningf("port %d of mgr module %q is already exposed by the mgr modules service or its name %q is already used", module.Port, module.Name, port.Name)
+			continue
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+func servicePortUsed(ports []v1.ServicePort, port v1.ServicePort) bool {
+	for _, p := range ports {
+		if p.Name == port.Name || p.Port == port.Port {
+			return true
+		}
+	}
+	return false
+}
+
+// makeModulesService returns the service of the active mgr with a named port for each enabled module
+// listening on a port, so that the ports are updated when the modules are enabled or disabled
+func (c *Cluster) makeModulesService(activeDaemon string) (*v1.Service, error) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ModulesServiceName,
+			Namespace: c.clusterInfo.Namespace,
+			Labels:    c.modulesServiceLabels(),
+		},
+		Spec: v1.ServiceSpec{
+			Selector: c.selectorLabels(activeDaemon),
+			Type:     v1.ServiceTypeClusterIP,
+			Ports:    c.modulesServicePorts(),
+		},
+	}
+	err := c.clusterInfo.OwnerInfo.SetControllerReference(svc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference to modules service %q", svc.Name)
+	}
+	return svc, nil
+}
+
+// makeModulesServiceMonitor returns the service monitor scraping the ports of the modules with the
+// service monitor enabled, or nil if no module needs to be scraped
+func (c *Cluster) makeModulesServiceMonitor() (*monitoringv1.ServiceMonitor, error) {
+	ports := c.modulesServicePorts()
+	endpoints := []monitoringv1.Endpoint{}
+	for _, module := range c.spec.Mgr.Modules {
+		if !module.Enabled || module.Port == 0 || !module.ServiceMonitor {
+			continue
+		}
+		name := modulePortName(module.Name)
+		if !modulesServiceHasPort(ports, name, module.Port) {
+			continue
+		}
+		endpoints = append(endpoints, monitoringv1.Endpoint{Port: name, Path: "/metrics", Interval: "5s"})
+	}
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+
+	serviceMonitor := &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ModulesServiceName,
+			Namespace: c.clusterInfo.Namespace,
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			NamespaceSelector: monitoringv1.NamespaceSelector{MatchNames: []string{c.clusterInfo.Namespace}},
+			Selector:          metav1.LabelSelector{MatchLabels: c.modulesServiceLabels()},
+			Endpoints:         endpoints,
+		},
+	}
+	cephv1.GetMonitoringLabels(c.spec.Labels).ApplyToObjectMeta(&serviceMonitor.ObjectMeta)
+	err := c.clusterInfo.OwnerInfo.SetControllerReference(serviceMonitor)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference to service monitor %q", serviceMonitor.Name)
+	}
+	applyMonitoringLabels(c, serviceMonitor)
+	return serviceMonitor, nil
+}
+
+func modulesServiceHasPort(ports []v1.ServicePort, name string, port int) bool {
+	for _, p := range ports {
+		if p.Name == name && p.Port == int32(port) {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileModulesServiceMonitor creates or updates the service monitor of the modules, or deletes
+// it if no module needs to be scraped anymore
+func (c *Cluster) reconcileModulesServiceMonitor() error {
+	serviceMonitor, err := c.makeModulesServiceMonitor()
+	if err != nil {
+		return err
+	}
+	if serviceMonitor == nil {
+		return k8sutil.DeleteServiceMonitor(c.clusterInfo.Namespace, ModulesServiceName)
+	}
+	if _, err := k8sutil.CreateOrUpdateServiceMonitor(serviceMonitor); err != nil {
+		return errors.Wrap(err, "failed to create or update the service monitor of the mgr modules")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	optest "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestModulePortName(t *testing.T) {
+	assert.Equal(t, "rook", modulePortName("rook"))
+	assert.Equal(t, "pg-autoscaler", modulePortName("pg_autoscaler"))
+	assert.Equal(t, "diskprediction", modulePortName("diskprediction_local"))
+}
+
+func TestModuleSettings(t *testing.T) {
+	module := cephv1.Module{Name: "restful", Settings: map[string]string{"key": "value"}}
+	assert.Equal(t, map[string]string{"key": "value"}, moduleSettings(module))
+
+	module.Port = 8003
+	assert.Equal(t, map[string]string{"key": "value", "server_port": "8003"}, moduleSettings(module))
+	assert.Equal(t, 1, len(module.Settings))
+
+	// the server port set in the settings is kept
+	module.Settings["server_port"] = "8004"
+	assert.Equal(t, "8004", moduleSettings(module)["server_port"])
+}
+
+func TestModulesService(t *testing.T) {
+	clientset := optest.New(t, 1)
+	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", FSID: "myfsid", OwnerInfo: ownerInfo}
+	clusterSpec := cephv1.ClusterSpec{}
+	c := New(&clusterd.Context{Clientset: clientset}, clusterInfo, clusterSpec, "myversion")
+
+	portNames := func(s *v1.Service) map[string]int32 {
+		ports := map[string]int32{}
+		for _, p := range s.Spec.Ports {
+			ports[p.Name] = p.Port
+		}
+		return ports
+	}
+
+	// only the metrics are exposed by default
+	s, err := c.makeModulesService("a")
+	require.NoError(t, err)
+	assert.Equal(t, ModulesServiceName, s.Name)
+	assert.Equal(t, map[string]int32{"http-metrics": 9283}, portNames(s))
+	assert.Equal(t, "a", s.Spec.Selector[controller.DaemonIDLabel])
+	assert.Equal(t, ModulesServiceName, s.Labels["app"])
+	_, ok := s.Labels[controller.DaemonIDLabel]
+	assert.False(t, ok)
+
+	// the dashboard and the enabled modules listening on a port are exposed
+	c.spec.Dashboard = cephv1.DashboardSpec{Enabled: true, SSL: true}
+	c.spec.Mgr.Modules = []cephv1.Module{
+		{Name: "pg_autoscaler", Enabled: true},
+		{Name: "restful", Enabled: true, Port: 8003, ServiceMonitor: true},
+		{Name: "influx", Enabled: false, Port: 8004},
+		{Name: "conflicting", Enabled: true, Port: 9283},
+	}
+	s, err = c.makeModulesService("b")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int32{"http-metrics": 9283, "https-dashboard": 8443, "restful": 8003}, portNames(s))
+	assert.Equal(t, "b", s.Spec.Selector[controller.DaemonIDLabel])
+
+	// the service monitor scrapes the ports of the modules with the service monitor enabled
+	sm, err := c.makeModulesServiceMonitor()
+	require.NoError(t, err)
+	require.NotNil(t, sm)
+	assert.Equal(t, ModulesServiceName, sm.Name)
+	assert.Equal(t, c.modulesServiceLabels(), sm.Spec.Selector.MatchLabels)
+	require.Equal(t, 1, len(sm.Spec.Endpoints))
+	assert.Equal(t, "restful", sm.Spec.Endpoints[0].Port)
+
+	// the service monitor is not needed when the module is disabled
+	c.spec.Mgr.Modules[1].Enabled = false
+	sm, err = c.makeModulesServiceMonitor()
+	assert.NoError(t, err)
+	assert.Nil(t, sm)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DashboardObjectGatewayUser is the rgw user created by the dashboard for its object gateway management
+const DashboardObjectGatewayUser = "dashboard"
+
+// DashboardObjectGatewayEnabled returns whether the credentials of the object gateway management of the
+// dashboard are configured with "ceph dashboard set-rgw-credentials", which is available as of Pacific.
+// The object store controller configures its own dashboard user otherwise.
+func DashboardObjectGatewayEnabled(spec *cephv1.ClusterSpec, clusterInfo *cephclient.ClusterInfo) bool {
+	return spec.Dashboard.Enabled && spec.Dashboard.EnableObjectGateway && clusterInfo.CephVersion.IsAtLeastPacific()
+}
+
+// SetDashboardObjectGatewayCredentials configures the dashboard with the keys of its rgw user, which is
+// created if it does not exist yet. The keys are read from the rgw user each time, so running it again
+// applies the new keys of the user.
+func SetDashboardObjectGatewayCredentials(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) error {
+	args := []string{"dashboard", "set-rgw-credentials"}
+	if _, err := cephclient.NewCephCommand(context, clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+		return errors.Wrap(err, "failed to set the object gateway credentials of the dashboard")
+	}
+	logger.Info("configured the object gateway credentials of the dashboard")
+	return nil
+}
+
+// configureDashboardObjectGateway configures the object gateway management of the dashboard once an
+// object store exists in the cluster
+func (c *Cluster) configureDashboardObjectGateway() error {
+	if !c.spec.Dashboard.EnableObjectGateway {
+		return nil
+	}
+	if !DashboardObjectGatewayEnabled(&c.spec, c.clusterInfo) {
+		logger.Warningf("the object gateway credentials of the dashboard can only be configured as of ceph pacific, current version %q", c.clusterInfo.CephVersion.String())
+		return nil
+	}
+
+	objectStores := &cephv1.CephObjectStoreList{}
+	if err := c.context.Client.List(c.clusterInfo.Context, objectStores, client.InNamespace(c.clusterInfo.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list the object stores")
+	}
+	if len(objectStores.Items) == 0 {
+		logger.Debug("no object store, skipping the object gateway credentials of the dashboard")
+		return nil
+	}
+
+	return SetDashboardObjectGatewayCredentials(c.context, c.clusterInfo)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigureDashboardObjectGateway(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args[0:2], " "))
+			return "", nil
+		},
+	}
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", CephVersion: cephver.Pacific, Context: context.TODO()}
+	builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+	c := &Cluster{clusterInfo: clusterInfo, context: &clusterd.Context{Client: builder.Build(), Executor: executor}}
+	c.spec.Dashboard = cephv1.DashboardSpec{Enabled: true}
+
+	// nothing to do if not enabled
+	assert.NoError(t, c.configureDashboardObjectGateway())
+	assert.Empty(t, commands)
+
+	// nothing to do without object store
+	c.spec.Dashboard.EnableObjectGateway = true
+	assert.True(t, DashboardObjectGatewayEnabled(&c.spec, clusterInfo))
+	assert.NoError(t, c.configureDashboardObjectGateway())
+	assert.Empty(t, commands)
+
+	// the credentials are set once an object store exists
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "ns"}}
+	c.context.Client = builder.WithRuntimeObjects(store).Build()
+	assert.NoError(t, c.configureDashboardObjectGateway())
+	assert.Equal(t, []string{"dashboard set-rgw-credentials"}, commands)
+
+	// not supported before pacific
+	commands = nil
+	clusterInfo.CephVersion = cephver.Octopus
+	assert.False(t, DashboardObjectGatewayEnabled(&c.spec, clusterInfo))
+	assert.NoError(t, c.configureDashboardObjectGateway())
+	assert.Empty(t, commands)
+
+	// not enabled without the dashboard
+	clusterInfo.CephVersion = cephver.Pacific
+	c.spec.Dashboard.Enabled = false
+	assert.False(t, DashboardObjectGatewayEnabled(&c.spec, clusterInfo))
+}
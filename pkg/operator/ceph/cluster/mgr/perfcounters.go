@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+const (
+	mgrStatsThresholdOption = "mgr_stats_threshold"
+	// the daemons send none of their perf counters to the mgr above the critical priority
+	perfCountersDisabledThreshold = "11"
+	perfCountersRuleName          = "prometheus-ceph-perf-counters-rules"
+)
+
+// the lowest priority of the perf counters sent by the daemons to the mgr, as defined by Ceph
+var perfCounterPriorityThresholds = map[cephv1.PerfCounterPriority]string{
+	cephv1.PerfCounterPriorityCritical:    "10",
+	cephv1.PerfCounterPriorityInteresting: "8",
+	cephv1.PerfCounterPriorityUseful:      "5",
+}
+
+var perfCounterDaemonTypes = []cephv1.PerfCounterDaemonType{"mon", "mgr", "osd", "mds", "client"}
+
+// configurePerfCounters selects the perf counters sent by each type of daemon to the mgr, and thus exported
+// by the prometheus module. The settings are left untouched if not specified, so they can still be managed manually.
+func (c *Cluster) configurePerfCounters() error {
+	spec := c.spec.Monitoring.PerfCounters
+	if spec == nil {
+		return nil
+	}
+	thresholds, err := perfCounterThresholds(spec)
+	if err != nil {
+		return err
+	}
+
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	for _, daemonType := range perfCounterDaemonTypes {
+		if _, err := monStore.SetIfChanged(string(daemonType), mgrStatsThresholdOption, thresholds[daemonType]); err != nil {
+			return errors.Wrapf(err, "failed to set the perf counters threshold of the %s daemons", daemonType)
+		}
+	}
+	return nil
+}
+
+// perfCounterThresholds returns the threshold of the priority of the perf counters of each type of daemon
+func perfCounterThresholds(spec *cephv1.PerfCountersSpec) (map[cephv1.PerfCounterDaemonType]string, error) {
+	priority := spec.PriorityLevel
+	if priority == "" {
+		priority = cephv1.PerfCounterPriorityUseful
+	}
+	threshold, ok := perfCounterPriorityThresholds[priority]
+	if !ok {
+		return nil, errors.Errorf("invalid perf counters priority level %q", priority)
+	}
+
+	thresholds := map[cephv1.PerfCounterDaemonType]string{}
+	for _, daemonType := range perfCounterDaemonTypes {
+		thresholds[daemonType] = threshold
+		if len(spec.DaemonTypes) > 0 {
+			thresholds[daemonType] = perfCountersDisabledThreshold
+		}
+	}
+	for _, daemonType := range spec.DaemonTypes {
+		if _, ok := thresholds[daemonType]; !ok {
+			return nil, errors.Errorf("invalid perf counters daemon type %q", daemonType)
+		}
+		thresholds[daemonType] = threshold
+	}
+	return thresholds, nil
+}
+
+// reconcilePerfCountersRule deploys the recording rules of the perf counters if enabled, and removes them otherwise
+func (c *Cluster) reconcilePerfCountersRule(namespace string) error {
+	if c.spec.Monitoring.PerfCounters != nil && c.spec.Monitoring.PerfCounters.RecordingRules {
+		return c.DeployPrometheusRule(perfCountersRuleName, namespace)
+	}
+	return k8sutil.DeletePrometheusRule(namespace, perfCountersRuleName)
+}
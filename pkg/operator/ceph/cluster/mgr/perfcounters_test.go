@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfCounterThresholds(t *testing.T) {
+	thresholds, err := perfCounterThresholds(&cephv1.PerfCountersSpec{})
+	require.NoError(t, err)
+	assert.Equal(t, map[cephv1.PerfCounterDaemonType]string{"mon": "5", "mgr": "5", "osd": "5", "mds": "5", "client": "5"}, thresholds)
+
+	thresholds, err = perfCounterThresholds(&cephv1.PerfCountersSpec{
+		PriorityLevel: cephv1.PerfCounterPriorityCritical,
+		DaemonTypes:   []cephv1.PerfCounterDaemonType{"osd", "client"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[cephv1.PerfCounterDaemonType]string{"mon": "11", "mgr": "11", "osd": "10", "mds": "11", "client": "10"}, thresholds)
+
+	_, err = perfCounterThresholds(&cephv1.PerfCountersSpec{PriorityLevel: "debug"})
+	assert.Error(t, err)
+	_, err = perfCounterThresholds(&cephv1.PerfCountersSpec{DaemonTypes: []cephv1.PerfCounterDaemonType{"rgw"}})
+	assert.Error(t, err)
+}
+
+func TestConfigurePerfCounters(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			// ignore the connection flags of the ceph command
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "--") {
+					args = args[:i]
+					break
+				}
+			}
+			commands = append(commands, strings.Join(args, " "))
+			return "5", nil
+		},
+	}
+	c := &Cluster{context: &clusterd.Context{Executor: executor}, clusterInfo: cephclient.AdminClusterInfo("mycluster")}
+
+	// the settings are left untouched by default
+	assert.NoError(t, c.configurePerfCounters())
+	assert.Empty(t, commands)
+
+	c.spec.Monitoring.PerfCounters = &cephv1.PerfCountersSpec{
+		PriorityLevel: cephv1.PerfCounterPriorityInteresting,
+		DaemonTypes:   []cephv1.PerfCounterDaemonType{"osd"},
+	}
+	assert.NoError(t, c.configurePerfCounters())
+	assert.Equal(t, []string{
+		"config get mon mgr_stats_threshold",
+		"config set mon mgr_stats_threshold 11",
+		"config get mgr mgr_stats_threshold",
+		"config set mgr mgr_stats_threshold 11",
+		"config get osd mgr_stats_threshold",
+		"config set osd mgr_stats_threshold 8",
+		"config get mds mgr_stats_threshold",
+		"config set mds mgr_stats_threshold 11",
+		"config get client mgr_stats_threshold",
+		"config set client mgr_stats_threshold 11",
+	}, commands)
+}
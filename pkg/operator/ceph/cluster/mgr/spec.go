@@ -48,6 +48,10 @@ func (c *Cluster) makeDeployment(mgrConfig *mgrConfig) (*apps.Deployment, error)
 		adminKeyringVol, _ := keyring.Volume().Admin(), keyring.VolumeMount().Admin()
 		volumes = append(volumes, adminKeyringVol)
 	}
+	if ssoCertVolume, _ := c.ssoCertVolume(); ssoCertVolume != nil {
+		volumes = append(volumes, *ssoCertVolume)
+	}
+	volumes = append(volumes, c.metricsProxyVolumes()...)
 
 	podSpec := v1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
@@ -70,6 +74,11 @@ func (c *Cluster) makeDeployment(mgrConfig *mgrConfig) (*apps.Deployment, error)
 	}
 	cephv1.GetMgrPlacement(c.spec.Placement).ApplyToPodSpec(&podSpec.Spec)
 
+	// The metrics proxy serves the metrics port in front of the prometheus module
+	if c.spec.Monitoring.SecureMetrics() {
+		podSpec.Spec.Containers = append(podSpec.Spec.Containers, c.makeMetricsProxyContainer())
+	}
+
 	// Run the sidecar and require anti affinity only if there are multiple mgrs
 	if c.spec.Mgr.Count > 1 {
 		podSpec.Spec.Containers = append(podSpec.Spec.Containers, c.makeMgrSidecarContainer(mgrConfig))
@@ -101,6 +110,9 @@ func (c *Cluster) makeDeployment(mgrConfig *mgrConfig) (*apps.Deployment, error)
 		}
 		podSpec.Spec.Containers = append(podSpec.Spec.Containers, c.makeCmdProxySidecarContainer(mgrConfig))
 	}
+	cephv1.GetDaemonDNS(c.spec.DNS, cephv1.KeyMgr).ApplyToPodSpec(&podSpec.Spec)
+	cephv1.GetDaemonContainerOverrides(c.spec.ContainerOverrides, cephv1.KeyMgr).ApplyToPodSpec(&podSpec.Spec)
+	controller.ApplyReadOnlyRootFilesystem(c.spec.Security, &podSpec.Spec)
 
 	cephv1.GetMgrAnnotations(c.spec.Annotations).ApplyToObjectMeta(&podSpec.ObjectMeta)
 	c.applyPrometheusAnnotations(&podSpec.ObjectMeta)
@@ -138,7 +150,7 @@ func (c *Cluster) makeDeployment(mgrConfig *mgrConfig) (*apps.Deployment, error)
 func (c *Cluster) makeChownInitContainer(mgrConfig *mgrConfig) v1.Container {
 	return controller.ChownCephDataDirsInitContainer(
 		*mgrConfig.DataPathMap,
-		c.spec.CephVersion.Image,
+		c.spec.CephImage(cephv1.HotfixDaemonMgr),
 		controller.DaemonVolumeMounts(mgrConfig.DataPathMap, mgrConfig.ResourceName),
 		cephv1.GetMgrResources(c.spec.Resources),
 		controller.PodSecurityContext(),
@@ -160,7 +172,7 @@ func (c *Cluster) makeMgrDaemonContainer(mgrConfig *mgrConfig) v1.Container {
 			config.NewFlag("client-mount-gid", "0"),
 			"--foreground",
 		),
-		Image:        c.spec.CephVersion.Image,
+		Image:        c.spec.CephImage(cephv1.HotfixDaemonMgr),
 		VolumeMounts: controller.DaemonVolumeMounts(mgrConfig.DataPathMap, mgrConfig.ResourceName),
 		Ports: []v1.ContainerPort{
 			{
@@ -180,7 +192,7 @@ func (c *Cluster) makeMgrDaemonContainer(mgrConfig *mgrConfig) v1.Container {
 			},
 		},
 		Env: append(
-			controller.DaemonEnvVars(c.spec.CephVersion.Image),
+			controller.DaemonEnvVars(c.spec.CephImage(cephv1.HotfixDaemonMgr)),
 			c.cephMgrOrchestratorModuleEnvs()...,
 		),
 		Resources:       cephv1.GetMgrResources(c.spec.Resources),
@@ -189,6 +201,22 @@ func (c *Cluster) makeMgrDaemonContainer(mgrConfig *mgrConfig) v1.Container {
 		WorkingDir:      config.VarLogCephDir,
 	}
 
+	// The certificate signing the single sign-on requests is read by the dashboard
+	if _, ssoCertMount := c.ssoCertVolume(); ssoCertMount != nil {
+		container.VolumeMounts = append(container.VolumeMounts, *ssoCertMount)
+	}
+
+	// The metrics port is served by the metrics proxy
+	if c.spec.Monitoring.SecureMetrics() {
+		ports := []v1.ContainerPort{}
+		for _, port := range container.Ports {
+			if port.ContainerPort != int32(DefaultMetricsPort) {
+				ports = append(ports, port)
+			}
+		}
+		container.Ports = ports
+	}
+
 	// If the liveness probe is enabled
 	container = config.ConfigureLivenessProbe(cephv1.KeyMgr, container, c.spec.HealthCheck)
 
@@ -243,9 +271,9 @@ func (c *Cluster) makeCmdProxySidecarContainer(mgrConfig *mgrConfig) v1.Containe
 		Name:            client.CommandProxyInitContainerName,
 		Command:         []string{"sleep"},
 		Args:            []string{"infinity"},
-		Image:           c.spec.CephVersion.Image,
+		Image:           c.spec.CephImage(cephv1.HotfixDaemonMgr),
 		VolumeMounts:    append(controller.DaemonVolumeMounts(mgrConfig.DataPathMap, mgrConfig.ResourceName), adminKeyringVolMount),
-		Env:             append(controller.DaemonEnvVars(c.spec.CephVersion.Image), v1.EnvVar{Name: "CEPH_ARGS", Value: fmt.Sprintf("-m $(ROOK_CEPH_MON_HOST) -k %s", keyring.VolumeMount().AdminKeyringFilePath())}),
+		Env:             append(controller.DaemonEnvVars(c.spec.CephImage(cephv1.HotfixDaemonMgr)), v1.EnvVar{Name: "CEPH_ARGS", Value: fmt.Sprintf("-m $(ROOK_CEPH_MON_HOST) -k %s", keyring.VolumeMount().AdminKeyringFilePath())}),
 		Resources:       cephv1.GetMgrResources(c.spec.Resources),
 		SecurityContext: controller.PodSecurityContext(),
 	}
@@ -332,6 +360,9 @@ func (c *Cluster) applyPrometheusAnnotations(objectMeta *metav1.ObjectMeta) {
 			"prometheus.io/scrape": "true",
 			"prometheus.io/port":   strconv.Itoa(int(DefaultMetricsPort)),
 		}
+		if c.spec.Monitoring.TLS != nil {
+			t["prometheus.io/scheme"] = "https"
+		}
 
 		t.ApplyToObjectMeta(objectMeta)
 	}
@@ -83,6 +83,22 @@ func TestPodSpec(t *testing.T) {
 		assert.Equal(t, 5, len(d.Spec.Template.Spec.Containers[0].VolumeMounts))
 	})
 
+	t.Run("deployment with container overrides", func(t *testing.T) {
+		c.spec.ContainerOverrides = cephv1.ContainerOverridesSpec{
+			cephv1.KeyAll: {ExtraArgs: []string{"--debug_ms=1"}},
+			cephv1.KeyMgr: {InitContainers: []v1.Container{{Name: "udev", Command: []string{"udevadm", "trigger"}}}},
+			cephv1.KeyMon: {ExtraArgs: []string{"--mon_debug"}},
+		}
+		defer func() { c.spec.ContainerOverrides = nil }()
+		d, err := c.makeDeployment(&mgrTestConfig)
+		assert.NoError(t, err)
+		args := d.Spec.Template.Spec.Containers[0].Args
+		assert.Equal(t, "--debug_ms=1", args[len(args)-1])
+		initContainers := d.Spec.Template.Spec.InitContainers
+		assert.Equal(t, "udev", initContainers[len(initContainers)-1].Name)
+		assert.Equal(t, "quay.io/ceph/ceph:myceph", initContainers[len(initContainers)-1].Image)
+	})
+
 	t.Run("deployment with multus with new sidecar proxy command container", func(t *testing.T) {
 		c.spec.Network.Provider = "multus"
 		d, err := c.makeDeployment(&mgrTestConfig)
@@ -137,6 +153,18 @@ func TestHostNetwork(t *testing.T) {
 
 	assert.Equal(t, true, c.spec.Network.IsHost())
 	assert.Equal(t, v1.DNSClusterFirstWithHostNet, d.Spec.Template.Spec.DNSPolicy)
+	assert.Nil(t, d.Spec.Template.Spec.DNSConfig)
+
+	// the dns settings of the mgr override the default policy of the host network
+	dnsConfig := &v1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}}
+	c.spec.DNS = cephv1.DNSSpec{
+		cephv1.KeyAll: {Config: dnsConfig},
+		cephv1.KeyMgr: {Policy: v1.DNSNone},
+	}
+	d, err = c.makeDeployment(&mgrTestConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, v1.DNSNone, d.Spec.Template.Spec.DNSPolicy)
+	assert.Equal(t, dnsConfig, d.Spec.Template.Spec.DNSConfig)
 }
 
 func TestApplyPrometheusAnnotations(t *testing.T) {
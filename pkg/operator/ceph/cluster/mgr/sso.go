@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util/exec"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IdPMetadataKeyName is the key of the metadata of the identity provider in the secret of the SAML2 settings
+	IdPMetadataKeyName = "idp-metadata.xml"
+
+	ssoProtocolSAML2            = "saml2"
+	defaultIdPUsernameAttribute = "uid"
+	ssoCertVolumeName           = "dashboard-sso-cert"
+	// the directory of the certificate of the service provider in the mgr pods
+	ssoCertMountPath = "/etc/ceph/dashboard-sso"
+)
+
+// configureDashboardSSO sets up the single sign-on of the dashboard with the settings of the spec, or
+// disables it if it was set up by the operator and is not configured anymore. The setup is applied
+// at each reconcile, so that a renewed certificate or updated metadata of the identity provider is loaded.
+func (c *Cluster) configureDashboardSSO() error {
+	cephCluster := &cephv1.CephCluster{}
+	err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.clusterInfo.NamespacedName().String())
+	}
+
+	var status *cephv1.DashboardSSOStatus
+	if c.spec.Dashboard.SSO != nil && c.spec.Dashboard.SSO.SAML2 != nil {
+		saml2 := c.spec.Dashboard.SSO.SAML2
+		if err := c.setupSAML2(saml2); err != nil {
+			return errors.Wrap(err, "failed to set up the saml2 single sign-on")
+		}
+		baseURL := strings.TrimSuffix(saml2.BaseURL, "/")
+		status = &cephv1.DashboardSSOStatus{
+			Protocol:    ssoProtocolSAML2,
+			ACSURL:      baseURL + "/auth/saml2",
+			MetadataURL: baseURL + "/auth/saml2/metadata",
+		}
+	} else if cephCluster.Status.DashboardSSO != nil {
+		logger.Info("disabling the single sign-on of the dashboard")
+		args := []string{"dashboard", "sso", "disable"}
+		if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+			return errors.Wrap(err, "failed to disable the single sign-on")
+		}
+	}
+
+	if reflect.DeepEqual(cephCluster.Status.DashboardSSO, status) {
+		return nil
+	}
+	cephCluster.Status.DashboardSSO = status
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the single sign-on status")
+	}
+	return nil
+}
+
+// setupSAML2 sets up the single sign-on with a SAML 2.0 identity provider
+func (c *Cluster) setupSAML2(saml2 *cephv1.DashboardSAML2Spec) error {
+	args, err := c.saml2SetupArgs(saml2)
+	if err != nil {
+		return err
+	}
+	logger.Infof("setting up the saml2 single sign-on of the dashboard at %q", saml2.BaseURL)
+	if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+		return errors.Wrap(err, "failed to set up saml2")
+	}
+	args = []string{"dashboard", "sso", "enable", ssoProtocolSAML2}
+	if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+		return errors.Wrap(err, "failed to enable saml2")
+	}
+	return nil
+}
+
+// saml2SetupArgs returns the arguments of the saml2 setup command. The optional settings are positional,
+// so the username attribute is always set and the entity ID is set if the requests are signed.
+func (c *Cluster) saml2SetupArgs(saml2 *cephv1.DashboardSAML2Spec) ([]string, error) {
+	if saml2.BaseURL == "" {
+		return nil, errors.New("the base url of the dashboard is required")
+	}
+	idpMetadata := saml2.IdPMetadataURL
+	if saml2.IdPMetadataSecretName != "" {
+		secret, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, saml2.IdPMetadataSecretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the identity provider metadata secret %q", saml2.IdPMetadataSecretName)
+		}
+		metadata, ok := secret.Data[IdPMetadataKeyName]
+		if !ok {
+			return nil, errors.Errorf("key %q not found in secret %q", IdPMetadataKeyName, saml2.IdPMetadataSecretName)
+		}
+		idpMetadata = string(metadata)
+	}
+	if idpMetadata == "" {
+		return nil, errors.New("the metadata of the identity provider is required")
+	}
+
+	usernameAttribute := saml2.IdPUsernameAttribute
+	if usernameAttribute == "" {
+		usernameAttribute = defaultIdPUsernameAttribute
+	}
+	args := []string{"dashboard", "sso", "setup", ssoProtocolSAML2, saml2.BaseURL, idpMetadata, usernameAttribute}
+	if saml2.SPCertSecretName != "" {
+		if saml2.IdPEntityID == "" {
+			return nil, errors.New("the entity id of the identity provider is required to sign the requests")
+		}
+		// the certificate is read by the mgr from the secret mounted in its pod
+		args = append(args, saml2.IdPEntityID, path.Join(ssoCertMountPath, v1.TLSCertKey), path.Join(ssoCertMountPath, v1.TLSPrivateKeyKey))
+	} else if saml2.IdPEntityID != "" {
+		args = append(args, saml2.IdPEntityID)
+	}
+	return args, nil
+}
+
+// ssoCertVolume returns the volume and the mount of the certificate of the service provider if the
+// requests to the identity provider are signed
+func (c *Cluster) ssoCertVolume() (*v1.Volume, *v1.VolumeMount) {
+	sso := c.spec.Dashboard.SSO
+	if sso == nil || sso.SAML2 == nil || sso.SAML2.SPCertSecretName == "" {
+		return nil, nil
+	}
+	volume := &v1.Volume{
+		Name: ssoCertVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: sso.SAML2.SPCertSecretName},
+		},
+	}
+	mount := &v1.VolumeMount{Name: ssoCertVolumeName, MountPath: ssoCertMountPath, ReadOnly: true}
+	return volume, mount
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigureDashboardSSO(t *testing.T) {
+	ctx := context.TODO()
+	var commands [][]string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			// the arguments of the command without the connection flags
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "--connect-timeout=") || strings.HasPrefix(arg, "--cluster=") {
+					commands = append(commands, args[0:i])
+					break
+				}
+			}
+			return "", nil
+		},
+	}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", OwnerInfo: cephclient.NewMinimumOwnerInfoWithOwnerRef(), Context: ctx}
+	clusterInfo.SetName("rook")
+	c := &Cluster{clusterInfo: clusterInfo, context: &clusterd.Context{Clientset: clientset, Client: cl, Executor: executor}}
+
+	// nothing to do without sso
+	assert.NoError(t, c.configureDashboardSSO())
+	assert.Empty(t, commands)
+
+	// the metadata of the identity provider is read from the secret
+	_, err := clientset.CoreV1().Secrets("ns").Create(ctx, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "idp", Namespace: "ns"},
+		Data:       map[string][]byte{IdPMetadataKeyName: []byte("<xml/>")},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	c.spec.Dashboard.SSO = &cephv1.DashboardSSOSpec{SAML2: &cephv1.DashboardSAML2Spec{
+		BaseURL:               "https://dashboard.example.com/",
+		IdPMetadataSecretName: "idp",
+	}}
+	assert.NoError(t, c.configureDashboardSSO())
+	assert.Equal(t, [][]string{
+		{"dashboard", "sso", "setup", "saml2", "https://dashboard.example.com/", "<xml/>", "uid"},
+		{"dashboard", "sso", "enable", "saml2"},
+	}, commands)
+	require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cephCluster))
+	assert.Equal(t, &cephv1.DashboardSSOStatus{
+		Protocol:    "saml2",
+		ACSURL:      "https://dashboard.example.com/auth/saml2",
+		MetadataURL: "https://dashboard.example.com/auth/saml2/metadata",
+	}, cephCluster.Status.DashboardSSO)
+
+	// the requests are signed with the mounted certificate
+	commands = nil
+	c.spec.Dashboard.SSO.SAML2 = &cephv1.DashboardSAML2Spec{
+		BaseURL:          "https://dashboard.example.com",
+		IdPMetadataURL:   "https://idp.example.com/metadata",
+		IdPEntityID:      "https://idp.example.com",
+		SPCertSecretName: "sp-cert",
+	}
+	assert.NoError(t, c.configureDashboardSSO())
+	assert.Equal(t, []string{"dashboard", "sso", "setup", "saml2", "https://dashboard.example.com", "https://idp.example.com/metadata", "uid",
+		"https://idp.example.com", "/etc/ceph/dashboard-sso/tls.crt", "/etc/ceph/dashboard-sso/tls.key"}, commands[0])
+	volume, mount := c.ssoCertVolume()
+	require.NotNil(t, volume)
+	assert.Equal(t, "sp-cert", volume.Secret.SecretName)
+	assert.Equal(t, ssoCertMountPath, mount.MountPath)
+
+	// the entity id is required to sign the requests
+	c.spec.Dashboard.SSO.SAML2.IdPEntityID = ""
+	assert.Error(t, c.configureDashboardSSO())
+
+	// the sso is disabled when removed from the spec
+	commands = nil
+	c.spec.Dashboard.SSO = nil
+	assert.NoError(t, c.configureDashboardSSO())
+	assert.Equal(t, [][]string{{"dashboard", "sso", "disable"}}, commands)
+	cephCluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, clusterInfo.NamespacedName(), cephCluster))
+	assert.Nil(t, cephCluster.Status.DashboardSSO)
+	volume, _ = c.ssoCertVolume()
+	assert.Nil(t, volume)
+
+	// the sso is only disabled once
+	commands = nil
+	assert.NoError(t, c.configureDashboardSSO())
+	assert.Empty(t, commands)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/exec"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DashboardUserLabel is the label of the secrets of the dashboard accounts managed by the operator,
+	// with the username of the account as value
+	DashboardUserLabel = "ceph.rook.io/dashboard-user"
+
+	dashboardUserSecretPrefix = "rook-ceph-dashboard-user-"
+	notFoundErrorCode         = int(syscall.ENOENT)
+)
+
+// DashboardUserSecretName returns the name of the secret with the credentials of a dashboard account
+func DashboardUserSecretName(username string) string {
+	return dashboardUserSecretPrefix + username
+}
+
+// configureDashboardUsers creates the dashboard accounts of the spec and keeps their roles and passwords
+// up to date, and deletes the accounts removed from the spec
+func (c *Cluster) configureDashboardUsers() error {
+	if len(c.spec.Dashboard.Users) > 0 && !FileBasedPasswordSupported(c.clusterInfo) {
+		logger.Warningf("the dashboard accounts are not supported with ceph version %q", c.clusterInfo.CephVersion.String())
+		return nil
+	}
+
+	usernames := map[string]bool{}
+	for _, user := range c.spec.Dashboard.Users {
+		if user.Name == "" {
+			return errors.New("the name of the dashboard account is required")
+		}
+		if user.Name == dashboardUsername || user.Name == dashboardReadOnlyUsername {
+			return errors.Errorf("the dashboard account %q is reserved for the operator", user.Name)
+		}
+		if len(user.Roles) == 0 {
+			return errors.Errorf("no roles for dashboard account %q", user.Name)
+		}
+		if usernames[user.Name] {
+			return errors.Errorf("duplicate dashboard account %q", user.Name)
+		}
+		usernames[user.Name] = true
+		if err := c.configureDashboardUser(user); err != nil {
+			return errors.Wrapf(err, "failed to configure dashboard account %q", user.Name)
+		}
+	}
+	return c.deleteDashboardUsers(usernames)
+}
+
+// configureDashboardUser creates the account if its secret does not exist yet, otherwise sets the password
+// from the secret of the spec if it has changed. The roles are set at each reconcile.
+func (c *Cluster) configureDashboardUser(user cephv1.DashboardUserSpec) error {
+	password, err := c.dashboardUserPassword(user)
+	if err != nil {
+		return err
+	}
+	secretName := DashboardUserSecretName(user.Name)
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, secretName, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get dashboard account secret %q", secretName)
+		}
+		return c.createDashboardUser(user, password)
+	}
+
+	if password != "" && password != string(secret.Data[passwordKeyName]) {
+		logger.Infof("updating the password of dashboard account %q", user.Name)
+		if err := c.runDashboardPasswordCommand("ac-user-set-password", user.Name, password); err != nil {
+			return errors.Wrap(err, "failed to set the password")
+		}
+		secret.Data[passwordKeyName] = []byte(password)
+		if _, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Update(c.clusterInfo.Context, secret, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update dashboard account secret %q", secretName)
+		}
+	}
+
+	args := append([]string{"dashboard", "ac-user-set-roles", user.Name}, user.Roles...)
+	if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+		return errors.Wrap(err, "failed to set the roles")
+	}
+	return nil
+}
+
+// dashboardUserPassword returns the password of the secret of the spec, or empty if the password is generated
+func (c *Cluster) dashboardUserPassword(user cephv1.DashboardUserSpec) (string, error) {
+	if user.PasswordSecretName == "" {
+		return "", nil
+	}
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, user.PasswordSecretName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the password secret %q", user.PasswordSecretName)
+	}
+	password, err := decodeSecret(secret)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read the password secret %q", user.PasswordSecretName)
+	}
+	return password, nil
+}
+
+// createDashboardUser creates the account with the given password, or a generated one if empty, and
+// saves its credentials in the secret of the account
+func (c *Cluster) createDashboardUser(user cephv1.DashboardUserSpec, password string) error {
+	if password == "" {
+		var err error
+		password, err = GeneratePassword(passwordLength)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate password")
+		}
+	}
+
+	logger.Infof("creating dashboard account %q with roles %v", user.Name, user.Roles)
+	err := c.runDashboardPasswordCommand("ac-user-create", user.Name, password, user.Roles...)
+	if err != nil {
+		// the account may remain from a previous attempt that failed to save the secret
+		exitCode, parsed := c.exitCode(err)
+		if !parsed || exitCode != alreadyExistsErrorCode {
+			return errors.Wrap(err, "failed to create the account")
+		}
+		if err := c.runDashboardPasswordCommand("ac-user-set-password", user.Name, password); err != nil {
+			return errors.Wrap(err, "failed to set the password")
+		}
+		args := append([]string{"dashboard", "ac-user-set-roles", user.Name}, user.Roles...)
+		if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+			return errors.Wrap(err, "failed to set the roles")
+		}
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DashboardUserSecretName(user.Name),
+			Namespace: c.clusterInfo.Namespace,
+			Labels:    map[string]string{DashboardUserLabel: user.Name},
+		},
+		Data: map[string][]byte{
+			usernameKeyName: []byte(user.Name),
+			passwordKeyName: []byte(password),
+		},
+		Type: k8sutil.RookType,
+	}
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(secret); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to dashboard account secret %q", secret.Name)
+	}
+	if _, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Create(c.clusterInfo.Context, secret, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to save dashboard account secret %q", secret.Name)
+	}
+	logger.Infof("created dashboard account %q", user.Name)
+	return nil
+}
+
+// runDashboardPasswordCommand runs a dashboard command on an account with the password passed in a temporary file
+func (c *Cluster) runDashboardPasswordCommand(command, username, password string, extraArgs ...string) error {
+	file, err := util.CreateTempFile(password)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a temporary dashboard password file")
+	}
+	defer func() {
+		if err := os.Remove(file.Name()); err != nil {
+			logger.Errorf("failed to clean up dashboard password file %q. %v", file.Name(), err)
+		}
+	}()
+	args := append([]string{"dashboard", command, username, "-i", file.Name()}, extraArgs...)
+	_, err = client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+	return err
+}
+
+// deleteDashboardUsers deletes the accounts managed by the operator that are not in the given usernames,
+// and their secrets
+func (c *Cluster) deleteDashboardUsers(keep map[string]bool) error {
+	secrets, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).List(c.clusterInfo.Context, metav1.ListOptions{LabelSelector: DashboardUserLabel})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the dashboard account secrets")
+	}
+	for _, secret := range secrets.Items {
+		username := secret.Labels[DashboardUserLabel]
+		if keep[username] {
+			continue
+		}
+		logger.Infof("deleting dashboard account %q", username)
+		args := []string{"dashboard", "ac-user-delete", username}
+		if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+			exitCode, parsed := c.exitCode(err)
+			if !parsed || exitCode != notFoundErrorCode {
+				return errors.Wrapf(err, "failed to delete dashboard account %q", username)
+			}
+		}
+		err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Delete(c.clusterInfo.Context, secret.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete dashboard account secret %q", secret.Name)
+		}
+	}
+	return nil
+}
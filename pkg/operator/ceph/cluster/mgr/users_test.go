@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigureDashboardUsers(t *testing.T) {
+	ctx := context.TODO()
+	var commands []string
+	userExists := false
+	exitCodeResponse := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			exitCodeResponse = 0
+			commands = append(commands, strings.Join(args[0:3], " "))
+			if args[1] == "ac-user-create" && userExists {
+				exitCodeResponse = alreadyExistsErrorCode
+				return "", errors.New("user already exists")
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{
+		Namespace:   "myns",
+		CephVersion: cephver.Pacific,
+		OwnerInfo:   cephclient.NewMinimumOwnerInfoWithOwnerRef(),
+		Context:     ctx,
+	}
+	c := &Cluster{clusterInfo: clusterInfo, context: &clusterd.Context{Clientset: clientset, Executor: executor},
+		spec: cephv1.ClusterSpec{
+			Dashboard: cephv1.DashboardSpec{Enabled: true},
+		},
+	}
+	c.exitCode = func(err error) (int, bool) {
+		return exitCodeResponse, exitCodeResponse != 0
+	}
+
+	// nothing to do without accounts
+	err := c.configureDashboardUsers()
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+
+	// the account is created with a generated password
+	c.spec.Dashboard.Users = []cephv1.DashboardUserSpec{{Name: "noc", Roles: []string{"read-only"}}}
+	err = c.configureDashboardUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-create noc"}, commands)
+	secret, err := clientset.CoreV1().Secrets("myns").Get(ctx, DashboardUserSecretName("noc"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "noc", string(secret.Data["username"]))
+	assert.Equal(t, passwordLength, len(secret.Data["password"]))
+	assert.Equal(t, "noc", secret.Labels[DashboardUserLabel])
+
+	// the roles are set again at each reconcile
+	commands = nil
+	err = c.configureDashboardUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-set-roles noc"}, commands)
+
+	// the password of the secret of the spec is set when it changes
+	passwordSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "noc-password", Namespace: "myns"},
+		Data:       map[string][]byte{"password": []byte("mypassword")},
+	}
+	_, err = clientset.CoreV1().Secrets("myns").Create(ctx, passwordSecret, metav1.CreateOptions{})
+	require.NoError(t, err)
+	c.spec.Dashboard.Users[0].PasswordSecretName = "noc-password"
+	commands = nil
+	err = c.configureDashboardUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-set-password noc", "dashboard ac-user-set-roles noc"}, commands)
+	secret, err = clientset.CoreV1().Secrets("myns").Get(ctx, DashboardUserSecretName("noc"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "mypassword", string(secret.Data["password"]))
+
+	commands = nil
+	err = c.configureDashboardUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-set-roles noc"}, commands)
+
+	// the password of a remaining account is reset
+	commands = nil
+	userExists = true
+	c.spec.Dashboard.Users = append(c.spec.Dashboard.Users, cephv1.DashboardUserSpec{Name: "ops", Roles: []string{"block-manager", "pool-manager"}})
+	err = c.configureDashboardUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-set-roles noc", "dashboard ac-user-create ops", "dashboard ac-user-set-password ops", "dashboard ac-user-set-roles ops"}, commands)
+
+	// the accounts removed from the spec are deleted
+	commands = nil
+	c.spec.Dashboard.Users = c.spec.Dashboard.Users[1:]
+	err = c.configureDashboardUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard ac-user-set-roles ops", "dashboard ac-user-delete noc"}, commands)
+	_, err = clientset.CoreV1().Secrets("myns").Get(ctx, DashboardUserSecretName("noc"), metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// the accounts of the operator are reserved
+	c.spec.Dashboard.Users = []cephv1.DashboardUserSpec{{Name: dashboardUsername, Roles: []string{"read-only"}}}
+	err = c.configureDashboardUsers()
+	assert.Error(t, err)
+
+	// the roles are required
+	c.spec.Dashboard.Users = []cephv1.DashboardUserSpec{{Name: "noc"}}
+	err = c.configureDashboardUsers()
+	assert.Error(t, err)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultAutoScaleMinCount = 3
+	defaultAutoScaleMaxCount = 5
+)
+
+// ValidateAutoScale checks the scaling of the mon count with the number of failure domains
+func ValidateAutoScale(spec cephv1.ClusterSpec) error {
+	if !spec.IsMonAutoScaled() {
+		return nil
+	}
+	if spec.IsStretchCluster() {
+		return errors.New("the mon count of a stretch cluster cannot be scaled")
+	}
+	minCount, maxCount := autoScaleBounds(spec.Mon.AutoScale)
+	if minCount%2 == 0 || maxCount%2 == 0 {
+		return errors.Errorf("the min and max mon counts %d and %d must be odd", minCount, maxCount)
+	}
+	if minCount > maxCount {
+		return errors.Errorf("the min mon count %d is above the max mon count %d", minCount, maxCount)
+	}
+	return nil
+}
+
+func autoScaleBounds(spec *cephv1.MonAutoScaleSpec) (int, int) {
+	minCount, maxCount := spec.MinCount, spec.MaxCount
+	if minCount == 0 {
+		minCount = defaultAutoScaleMinCount
+	}
+	if maxCount == 0 {
+		maxCount = defaultAutoScaleMaxCount
+	}
+	return minCount, maxCount
+}
+
+// autoScaledMonCount returns the largest odd mon count not above the number of failure domains, within the
+// min and max counts. Unless multiple mons are allowed per node, the count is also capped at the number of
+// nodes that can run the mons, but never below the min count: the extra mons stay pending until a node can run
+// them. The count never shrinks by more mons than the quorum of the current mons tolerates losing.
+func autoScaledMonCount(spec *cephv1.MonAutoScaleSpec, failureDomains, nodes int, allowMultiplePerNode bool, current int) int {
+	minCount, maxCount := autoScaleBounds(spec)
+	count := largestOddCount(failureDomains)
+	if count > maxCount {
+		count = maxCount
+	}
+	if !allowMultiplePerNode && count > nodes {
+		count = largestOddCount(nodes)
+	}
+	if count < minCount {
+		count = minCount
+	}
+	if lowest := current - (current-1)/2; count < lowest {
+		count = lowest
+		if count%2 == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// largestOddCount returns the largest odd count not above n, at least 1
+func largestOddCount(n int) int {
+	if n < 1 {
+		return 1
+	}
+	if n%2 == 0 {
+		return n - 1
+	}
+	return n
+}
+
+// countFailureDomains returns the number of failure domains and the number of the nodes matching the mon
+// placement. The nodes that are not ready are counted so that the mon count does not shrink while a node restarts.
+func countFailureDomains(nodes []v1.Node, placement cephv1.Placement, label string) (int, int) {
+	domains := map[string]struct{}{}
+	monNodes := 0
+	for _, node := range nodes {
+		valid, err := k8sutil.NodeMeetsPlacementTerms(node, placement, false)
+		if err != nil {
+			logger.Warningf("failed to check if node %q can run the mons. %v", node.Name, err)
+			continue
+		}
+		if !valid {
+			continue
+		}
+		monNodes++
+		if domain, ok := node.Labels[label]; ok && domain != "" {
+			domains[domain] = struct{}{}
+		}
+	}
+	return len(domains), monNodes
+}
+
+// autoScaleMonCount scales the mon count with the number of failure domains of the nodes if mon.autoScale is
+// enabled. The mons are then added or removed one at a time by the health checks, and the extra mons are only
+// removed while all the mons are in quorum.
+func (c *Cluster) autoScaleMonCount() {
+	if !c.spec.IsMonAutoScaled() {
+		return
+	}
+	label := c.spec.Mon.AutoScale.FailureDomainLabel
+	if label == "" {
+		label = v1.LabelHostname
+	}
+
+	nodes, err := c.context.Clientset.CoreV1().Nodes().List(c.ClusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		// keep the last scaled count rather than the count of the spec
+		if c.autoScaledCount > 0 {
+			c.spec.Mon.Count = c.autoScaledCount
+		}
+		logger.Warningf("failed to list the nodes to scale the mon count, keeping %d mons. %v", c.spec.Mon.Count, err)
+		return
+	}
+	failureDomains, monNodes := countFailureDomains(nodes.Items, c.getMonPlacement(""), label)
+	current := c.autoScaledCount
+	if current == 0 {
+		current = len(c.ClusterInfo.Monitors)
+	}
+	count := autoScaledMonCount(c.spec.Mon.AutoScale, failureDomains, monNodes, c.spec.Mon.AllowMultiplePerNode, current)
+	if count != c.autoScaledCount {
+		logger.Infof("scaling the mon count to %d for %d failure domains with label %q", count, failureDomains, label)
+		if !c.spec.Mon.AllowMultiplePerNode && count > monNodes {
+			logger.Warningf("only %d node(s) can run the %d mons and allowMultiplePerNode is false, the extra mons will stay pending", monNodes, count)
+		}
+		c.autoScaledCount = count
+	}
+	c.spec.Mon.Count = count
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAutoScaledMonCount(t *testing.T) {
+	spec := &cephv1.MonAutoScaleSpec{Enabled: true}
+	assert.Equal(t, 3, autoScaledMonCount(spec, 3, 3, false, 0))
+	assert.Equal(t, 3, autoScaledMonCount(spec, 4, 4, false, 3))
+	assert.Equal(t, 5, autoScaledMonCount(spec, 5, 5, false, 3))
+	assert.Equal(t, 5, autoScaledMonCount(spec, 20, 20, false, 5))
+
+	// the count is capped at the number of nodes unless multiple mons are allowed per node, but never below
+	// the min count
+	assert.Equal(t, 3, autoScaledMonCount(spec, 1, 1, false, 0))
+	assert.Equal(t, 3, autoScaledMonCount(spec, 2, 2, false, 3))
+	assert.Equal(t, 3, autoScaledMonCount(spec, 0, 0, false, 0))
+	assert.Equal(t, 3, autoScaledMonCount(spec, 1, 1, true, 0))
+	assert.Equal(t, 3, autoScaledMonCount(spec, 2, 2, true, 3))
+	// a zone label counts fewer failure domains than nodes
+	assert.Equal(t, 3, autoScaledMonCount(spec, 2, 4, false, 3))
+
+	spec = &cephv1.MonAutoScaleSpec{Enabled: true, MinCount: 1, MaxCount: 7}
+	assert.Equal(t, 1, autoScaledMonCount(spec, 2, 2, false, 0))
+	assert.Equal(t, 1, autoScaledMonCount(spec, 2, 2, false, 1))
+	assert.Equal(t, 5, autoScaledMonCount(spec, 6, 6, false, 5))
+	assert.Equal(t, 7, autoScaledMonCount(spec, 8, 8, false, 5))
+	// the count does not shrink by more mons than the quorum tolerates losing
+	assert.Equal(t, 3, autoScaledMonCount(spec, 2, 2, false, 3))
+	assert.Equal(t, 3, autoScaledMonCount(spec, 1, 1, false, 5))
+	assert.Equal(t, 5, autoScaledMonCount(spec, 1, 1, false, 7))
+}
+
+func TestValidateAutoScale(t *testing.T) {
+	spec := cephv1.ClusterSpec{}
+	assert.NoError(t, ValidateAutoScale(spec))
+	spec.Mon.AutoScale = &cephv1.MonAutoScaleSpec{Enabled: true}
+	assert.NoError(t, ValidateAutoScale(spec))
+	spec.Mon.AutoScale.MaxCount = 4
+	assert.Error(t, ValidateAutoScale(spec))
+	spec.Mon.AutoScale = &cephv1.MonAutoScaleSpec{Enabled: true, MinCount: 5, MaxCount: 3}
+	assert.Error(t, ValidateAutoScale(spec))
+	spec.Mon.AutoScale = &cephv1.MonAutoScaleSpec{Enabled: true}
+	spec.Mon.StretchCluster = &cephv1.StretchClusterSpec{Zones: []cephv1.StretchClusterZoneSpec{{Name: "a"}}}
+	assert.Error(t, ValidateAutoScale(spec))
+}
+
+func TestAutoScaleMonCount(t *testing.T) {
+	clientset := test.New(t, 4)
+	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	spec := cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 3, AutoScale: &cephv1.MonAutoScaleSpec{Enabled: true}}}
+	c := New(&clusterd.Context{Clientset: clientset}, "ns", spec, ownerInfo, &sync.Mutex{})
+	c.ClusterInfo = cephclient.AdminClusterInfo("ns")
+
+	// four hosts run three mons
+	c.autoScaleMonCount()
+	assert.Equal(t, 3, c.spec.Mon.Count)
+
+	// the count grows once there are five hosts
+	test.AddReadyNode(t, clientset, "node4", "4.4.4.4")
+	c.autoScaleMonCount()
+	assert.Equal(t, 5, c.spec.Mon.Count)
+
+	// the nodes that are not ready are still counted
+	node, err := clientset.CoreV1().Nodes().Get(context.TODO(), "node4", metav1.GetOptions{})
+	assert.NoError(t, err)
+	node.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}}
+	_, err = clientset.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	c.autoScaleMonCount()
+	assert.Equal(t, 5, c.spec.Mon.Count)
+
+	// the count shrinks when the cluster contracts
+	assert.NoError(t, clientset.CoreV1().Nodes().Delete(context.TODO(), "node4", metav1.DeleteOptions{}))
+	c.autoScaleMonCount()
+	assert.Equal(t, 3, c.spec.Mon.Count)
+
+	// the failure domains are counted with the label
+	c.spec.Mon.AutoScale.FailureDomainLabel = "topology.kubernetes.io/zone"
+	for i := 0; i < 4; i++ {
+		node, err := clientset.CoreV1().Nodes().Get(context.TODO(), fmt.Sprintf("node%d", i), metav1.GetOptions{})
+		assert.NoError(t, err)
+		node.Labels["topology.kubernetes.io/zone"] = fmt.Sprintf("zone%d", i%2)
+		_, err = clientset.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+	}
+	c.spec.Mon.AutoScale.MinCount = 1
+	c.autoScaleMonCount()
+	// three mons are not scaled down to a single mon, the quorum of three mons only tolerates losing one mon
+	assert.Equal(t, 3, c.spec.Mon.Count)
+
+	// three nodes shrinking to two nodes keep the min count of mons, the extra mon stays pending
+	c.spec.Mon.AutoScale = &cephv1.MonAutoScaleSpec{Enabled: true}
+	c.spec.Mon.AutoScale.FailureDomainLabel = ""
+	assert.NoError(t, clientset.CoreV1().Nodes().Delete(context.TODO(), "node3", metav1.DeleteOptions{}))
+	c.autoScaleMonCount()
+	assert.Equal(t, 3, c.spec.Mon.Count)
+	assert.NoError(t, clientset.CoreV1().Nodes().Delete(context.TODO(), "node2", metav1.DeleteOptions{}))
+	c.autoScaleMonCount()
+	assert.Equal(t, 3, c.spec.Mon.Count)
+	c.spec.Mon.AllowMultiplePerNode = true
+	c.autoScaleMonCount()
+	assert.Equal(t, 3, c.spec.Mon.Count)
+}
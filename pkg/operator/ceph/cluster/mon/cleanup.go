@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MonRemovedAtAnnotation is the annotation of the PVC of a removed mon with the time the mon was found
+	// removed, the PVC is deleted once its retention period is over
+	MonRemovedAtAnnotation = "ceph.rook.io/mon-removed-at"
+
+	monCanaryLabel = "mon_canary"
+)
+
+// removeOrphanMonResources removes the resources left behind by the mons that are not part of the
+// cluster anymore, such as after an aborted failover. The deployments and the services of these mons are
+// deleted right away, their PVCs are deleted once the retention period of the mon PVCs is over.
+func (c *Cluster) removeOrphanMonResources() {
+	// never consider all the mons as orphaned if the mons are not known
+	if len(c.ClusterInfo.Monitors) == 0 {
+		logger.Debug("skipping check for orphaned mon resources since the mons are not known")
+		return
+	}
+
+	logger.Info("checking for orphaned mon resources")
+	c.removeOrphanMonDeployments()
+	c.removeOrphanMonServices()
+
+	if !c.monsUseVolumeClaimTemplates() {
+		logger.Debug("skipping check for orphaned mon pvcs since using the host path")
+		return
+	}
+	retained, err := c.removeOrphanMonPVCs(time.Now().UTC())
+	if err != nil {
+		logger.Infof("failed to check for orphaned mon pvcs. %v", err)
+		return
+	}
+	if err := c.updateRetainedMonPVCs(retained); err != nil {
+		logger.Warningf("failed to update the retained mon pvcs in the status. %v", err)
+	}
+}
+
+// orphanMonName returns the name of the mon of a resource if the mon is not part of the cluster anymore
+func (c *Cluster) orphanMonName(labels map[string]string) (string, bool) {
+	name, ok := labels[controller.DaemonIDLabel]
+	if !ok || labels[monCanaryLabel] == "true" {
+		// the canaries are removed separately
+		return "", false
+	}
+	if _, ok := c.ClusterInfo.Monitors[name]; ok {
+		return "", false
+	}
+	return name, true
+}
+
+func (c *Cluster) removeOrphanMonDeployments() {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).List(c.ClusterInfo.Context, opts)
+	if err != nil {
+		logger.Infof("failed to check for orphaned mon deployments. %v", err)
+		return
+	}
+
+	var gracePeriod int64
+	propagation := metav1.DeletePropagationForeground
+	options := &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod, PropagationPolicy: &propagation}
+	for _, d := range deployments.Items {
+		monName, orphaned := c.orphanMonName(d.Labels)
+		if !orphaned {
+			continue
+		}
+		logger.Infof("removing deployment %q since the mon %q is not part of the cluster anymore", d.Name, monName)
+		if err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Delete(c.ClusterInfo.Context, d.Name, *options); err != nil && !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to delete orphaned mon deployment %q. %v", d.Name, err)
+		}
+	}
+}
+
+func (c *Cluster) removeOrphanMonServices() {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	services, err := c.context.Clientset.CoreV1().Services(c.Namespace).List(c.ClusterInfo.Context, opts)
+	if err != nil {
+		logger.Infof("failed to check for orphaned mon services. %v", err)
+		return
+	}
+
+	for _, s := range services.Items {
+		monName, orphaned := c.orphanMonName(s.Labels)
+		if !orphaned {
+			continue
+		}
+		logger.Infof("removing service %q since the mon %q is not part of the cluster anymore", s.Name, monName)
+		if err := c.context.Clientset.CoreV1().Services(c.Namespace).Delete(c.ClusterInfo.Context, s.Name, metav1.DeleteOptions{}); err != nil && !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to delete orphaned mon service %q. %v", s.Name, err)
+		}
+	}
+}
+
+// removeOrphanMonPVCs deletes the PVCs of the mons without deployment whose retention period is over, and
+// returns the PVCs still retained
+func (c *Cluster) removeOrphanMonPVCs(now time.Time) ([]cephv1.RetainedMonPVC, error) {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	pvcs, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).List(c.ClusterInfo.Context, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list mon pvcs")
+	}
+
+	retentionPeriod := c.spec.MonPVCRetentionPeriod()
+	retained := []cephv1.RetainedMonPVC{}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		logger.Debugf("checking if pvc %q is orphaned", pvc.Name)
+
+		_, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, pvc.Name, metav1.GetOptions{})
+		if err == nil {
+			logger.Debugf("skipping pvc removal since the mon daemon %q still requires it", pvc.Name)
+			continue
+		}
+		if !kerrors.IsNotFound(err) {
+			logger.Infof("skipping pvc removal since the mon daemon %q might still require it. %v", pvc.Name, err)
+			continue
+		}
+
+		if retentionPeriod > 0 {
+			removedAt, err := time.Parse(time.RFC3339, pvc.Annotations[MonRemovedAtAnnotation])
+			if err != nil {
+				// the mon was just found removed, its pvc is retained from now on
+				removedAt = now
+				if pvc.Annotations == nil {
+					pvc.Annotations = map[string]string{}
+				}
+				pvc.Annotations[MonRemovedAtAnnotation] = removedAt.Format(time.RFC3339)
+				if _, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Update(c.ClusterInfo.Context, pvc, metav1.UpdateOptions{}); err != nil {
+					logger.Warningf("failed to annotate the retained mon pvc %q. %v", pvc.Name, err)
+					continue
+				}
+				logger.Infof("retaining pvc %q of the removed mon for %s", pvc.Name, retentionPeriod.String())
+			}
+			deleteAfter := removedAt.Add(retentionPeriod)
+			if now.Before(deleteAfter) {
+				retained = append(retained, cephv1.RetainedMonPVC{
+					Name:        pvc.Name,
+					Mon:         pvc.Labels[controller.DaemonIDLabel],
+					RemovedAt:   removedAt.Format(time.RFC3339),
+					DeleteAfter: deleteAfter.Format(time.RFC3339),
+				})
+				continue
+			}
+			logger.Infof("removing pvc %q since its retention period of %s is over", pvc.Name, retentionPeriod.String())
+		} else {
+			logger.Infof("removing pvc %q since it is no longer needed for the mon daemon", pvc.Name)
+		}
+
+		var gracePeriod int64 // delete immediately
+		propagation := metav1.DeletePropagationForeground
+		options := &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod, PropagationPolicy: &propagation}
+		err = c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Delete(c.ClusterInfo.Context, pvc.Name, *options)
+		if err != nil && !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to delete orphaned monitor pvc %q. %v", pvc.Name, err)
+		}
+	}
+	return retained, nil
+}
+
+// updateRetainedMonPVCs lists the retained mon PVCs in the status of the CephCluster if they changed
+func (c *Cluster) updateRetainedMonPVCs(retained []cephv1.RetainedMonPVC) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.ClusterInfo.NamespacedName().String())
+	}
+	if len(retained) == 0 {
+		retained = nil
+	}
+	if reflect.DeepEqual(cephCluster.Status.RetainedMonPVCs, retained) {
+		return nil
+	}
+	cephCluster.Status.RetainedMonPVCs = retained
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the retained mon pvcs")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRemoveOrphanMonResources(t *testing.T) {
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := test.New(t, 1)
+	context := &clusterd.Context{Client: cl, Clientset: clientset}
+	c := New(context, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef(), &sync.Mutex{})
+	setCommonMonProperties(c, 3, cephv1.MonSpec{Count: 3}, "myversion")
+	c.ClusterInfo.Namespace = "ns"
+	c.ClusterInfo.SetName("rook")
+	ctx := c.ClusterInfo.Context
+	c.spec.Mon.VolumeClaimTemplate = &v1.PersistentVolumeClaim{}
+	c.spec.Mon.Failover = &cephv1.MonFailoverSpec{PVCRetentionPeriod: &metav1.Duration{Duration: time.Hour}}
+
+	// mon "a" is part of the cluster, mon "d" was removed and "e" is a canary
+	for _, name := range []string{"a", "d", "e"} {
+		m := &monConfig{ResourceName: resourceName(name), DaemonName: name}
+		canary := name == "e"
+		d := &apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: m.ResourceName, Namespace: c.Namespace, Labels: c.getLabels(m, canary, true)}}
+		_, err := clientset.AppsV1().Deployments(c.Namespace).Create(ctx, d, metav1.CreateOptions{})
+		require.NoError(t, err)
+		s := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: m.ResourceName, Namespace: c.Namespace, Labels: c.getLabels(m, false, true)}}
+		_, err = clientset.CoreV1().Services(c.Namespace).Create(ctx, s, metav1.CreateOptions{})
+		require.NoError(t, err)
+		pvc, err := c.makeDeploymentPVC(m, false)
+		require.NoError(t, err)
+		_, err = clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Create(ctx, pvc, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	getCluster := func() *cephv1.CephCluster {
+		cluster := &cephv1.CephCluster{}
+		err := cl.Get(ctx, c.ClusterInfo.NamespacedName(), cluster)
+		require.NoError(t, err)
+		return cluster
+	}
+
+	// the deployment and the service of mon "d" are removed, its pvc is retained
+	c.removeOrphanMonResources()
+	_, err := clientset.AppsV1().Deployments(c.Namespace).Get(ctx, "rook-ceph-mon-d", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = clientset.CoreV1().Services(c.Namespace).Get(ctx, "rook-ceph-mon-d", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = clientset.AppsV1().Deployments(c.Namespace).Get(ctx, "rook-ceph-mon-e", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = clientset.AppsV1().Deployments(c.Namespace).Get(ctx, "rook-ceph-mon-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Get(ctx, "rook-ceph-mon-d", metav1.GetOptions{})
+	require.NoError(t, err)
+	removedAt, err := time.Parse(time.RFC3339, pvc.Annotations[MonRemovedAtAnnotation])
+	require.NoError(t, err)
+
+	retained := getCluster().Status.RetainedMonPVCs
+	require.Equal(t, 1, len(retained))
+	assert.Equal(t, "rook-ceph-mon-d", retained[0].Name)
+	assert.Equal(t, "d", retained[0].Mon)
+	assert.Equal(t, removedAt.Add(time.Hour).Format(time.RFC3339), retained[0].DeleteAfter)
+
+	// the pvc is kept until the end of its retention period
+	retainedPVCs, err := c.removeOrphanMonPVCs(removedAt.Add(30 * time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(retainedPVCs))
+
+	retainedPVCs, err = c.removeOrphanMonPVCs(removedAt.Add(2 * time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, retainedPVCs)
+	_, err = clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Get(ctx, "rook-ceph-mon-d", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Get(ctx, "rook-ceph-mon-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.updateRetainedMonPVCs(retainedPVCs))
+	assert.Nil(t, getCluster().Status.RetainedMonPVCs)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+var (
+	// a store staying above the size threshold after its compaction is not compacted again more often
+	minMonCompactionInterval = time.Hour
+)
+
+// compactMonStores compacts the store of a single mon per health check, if its last compaction is
+// older than the interval or its store is larger than the threshold. The stores are only compacted
+// while all the mons are in quorum, so that the slowdown of the compacted mon cannot break the quorum.
+// The time of the compaction is recorded in the mon health.
+func (c *Cluster) compactMonStores(monHealth *cephv1.MonHealthStatus) {
+	spec := c.spec.Mon.Compaction
+	if spec == nil || (spec.Interval == nil && spec.StoreSizeThreshold == nil) {
+		return
+	}
+	for _, mon := range monHealth.Mons {
+		if !mon.InQuorum {
+			logger.Infof("skipping the compaction of the mon stores since mon %q is out of quorum", mon.Name)
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	var candidate *cephv1.MonHealth
+	var candidateLastCompacted time.Time
+	for i, mon := range monHealth.Mons {
+		// the time is zero if the store was never compacted
+		lastCompacted, _ := time.Parse(time.RFC3339, mon.LastCompacted)
+		if !monStoreNeedsCompaction(spec, mon, lastCompacted, now) {
+			continue
+		}
+		// the store compacted least recently goes first
+		if candidate == nil || lastCompacted.Before(candidateLastCompacted) {
+			candidate = &monHealth.Mons[i]
+			candidateLastCompacted = lastCompacted
+		}
+	}
+	if candidate == nil {
+		return
+	}
+
+	logger.Infof("compacting the store of mon %q of size %d bytes", candidate.Name, candidate.StoreSizeBytes)
+	if err := cephclient.CompactMonStore(c.context, c.ClusterInfo, candidate.Name); err != nil {
+		logger.Errorf("failed to compact the store of mon %q. %v", candidate.Name, err)
+		return
+	}
+	candidate.LastCompacted = now.Format(time.RFC3339)
+	logger.Infof("compacted the store of mon %q", candidate.Name)
+}
+
+// monStoreNeedsCompaction returns whether the store of the mon is due for a compaction
+func monStoreNeedsCompaction(spec *cephv1.MonCompactionSpec, mon cephv1.MonHealth, lastCompacted, now time.Time) bool {
+	if spec.Interval != nil && now.Sub(lastCompacted) >= spec.Interval.Duration {
+		return true
+	}
+	if spec.StoreSizeThreshold != nil && mon.StoreSizeBytes > uint64(spec.StoreSizeThreshold.Value()) {
+		return now.Sub(lastCompacted) >= minMonCompactionInterval
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompactMonStores(t *testing.T) {
+	var compacted []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "tell" && args[2] == "compact" {
+				compacted = append(compacted, args[1])
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Clientset: test.New(t, 1), Executor: executor}
+	c := New(context, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef(), &sync.Mutex{})
+	setCommonMonProperties(c, 3, cephv1.MonSpec{Count: 3}, "myversion")
+
+	recently := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	longAgo := time.Now().UTC().Add(-48 * time.Hour).Format(time.RFC3339)
+	newMonHealth := func() *cephv1.MonHealthStatus {
+		return &cephv1.MonHealthStatus{Mons: []cephv1.MonHealth{
+			{Name: "a", InQuorum: true, StoreSizeBytes: 100, LastCompacted: recently},
+			{Name: "b", InQuorum: true, StoreSizeBytes: 5000, LastCompacted: longAgo},
+			{Name: "c", InQuorum: true, StoreSizeBytes: 100},
+		}}
+	}
+
+	// not compacted by default
+	monHealth := newMonHealth()
+	c.compactMonStores(monHealth)
+	assert.Empty(t, compacted)
+
+	// the mon never compacted goes first, a single mon is compacted per check
+	c.spec.Mon.Compaction = &cephv1.MonCompactionSpec{Interval: &metav1.Duration{Duration: 24 * time.Hour}}
+	c.compactMonStores(monHealth)
+	assert.Equal(t, []string{"mon.c"}, compacted)
+	assert.NotEmpty(t, monHealth.Mons[2].LastCompacted)
+	c.compactMonStores(monHealth)
+	assert.Equal(t, []string{"mon.c", "mon.b"}, compacted)
+	c.compactMonStores(monHealth)
+	assert.Equal(t, 2, len(compacted))
+
+	// the store above the threshold is compacted
+	compacted = nil
+	threshold := resource.MustParse("1Ki")
+	c.spec.Mon.Compaction = &cephv1.MonCompactionSpec{StoreSizeThreshold: &threshold}
+	monHealth = newMonHealth()
+	c.compactMonStores(monHealth)
+	assert.Equal(t, []string{"mon.b"}, compacted)
+	// but not again right after its compaction
+	c.compactMonStores(monHealth)
+	assert.Equal(t, 1, len(compacted))
+
+	// not compacted while a mon is out of quorum
+	compacted = nil
+	monHealth = newMonHealth()
+	monHealth.Mons[0].InQuorum = false
+	c.compactMonStores(monHealth)
+	assert.Empty(t, compacted)
+}
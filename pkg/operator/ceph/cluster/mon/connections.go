@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	secureMode = "secure"
+)
+
+var (
+	// the connection modes required for the encryption of all the traffic on the wire
+	secureModeOptions = []string{
+		"ms_cluster_mode",
+		"ms_service_mode",
+		"ms_client_mode",
+		"ms_mon_cluster_mode",
+		"ms_mon_service_mode",
+		"ms_mon_client_mode",
+	}
+)
+
+// requireMsgr2 returns whether the mons must only listen on the msgr2 port. The msgr1 port is kept
+// as long as connected clients do not support msgr2.
+func (c *Cluster) requireMsgr2() bool {
+	return c.spec.Network.RequireMsgr2() && !c.clientsIncompatible
+}
+
+// ConfigureConnections enforces the msgr2 protocol and the encryption of the connections if
+// requested in the cluster spec. The connected clients are validated first, and nothing is
+// enforced if any of them would be locked out.
+func (c *Cluster) ConfigureConnections() error {
+	enforce := c.spec.Network.RequireMsgr2() || c.spec.Network.EncryptionEnforced()
+	if enforce {
+		incompatible, err := c.countIncompatibleClients()
+		if err != nil {
+			return errors.Wrap(err, "failed to validate the compatibility of the clients")
+		}
+		c.clientsIncompatible = incompatible > 0
+		if c.clientsIncompatible {
+			message := fmt.Sprintf("%d connected clients do not support the msgr2 protocol and would be locked out, not enforcing the connection settings. upgrade the clients, for example kernel clients require a kernel 5.11 or newer", incompatible)
+			logger.Warning(message)
+			return c.updateClientsIncompatibleCondition(true, message)
+		}
+	} else {
+		c.clientsIncompatible = false
+	}
+	if err := c.updateClientsIncompatibleCondition(false, "all connected clients support the connection settings"); err != nil {
+		return err
+	}
+
+	monStore := config.GetMonStore(c.context, c.ClusterInfo)
+	return c.updateEnforcedConnectionOptions(func(enforced []string) ([]string, error) {
+		var err error
+		for _, option := range secureModeOptions {
+			enforced, err = applyConnectionOption(monStore, enforced, option, secureMode, c.spec.Network.EncryptionEnforced())
+			if err != nil {
+				return enforced, err
+			}
+		}
+		return applyConnectionOption(monStore, enforced, "ms_bind_msgr1", "false", c.requireMsgr2())
+	})
+}
+
+// applyConnectionOption sets the option globally if enforced and returns the options set by the operator.
+// When the option is not enforced anymore, it is only removed if the operator set it, so a value set by
+// the user is kept.
+func applyConnectionOption(monStore *config.MonStore, enforced []string, option, value string, enforce bool) ([]string, error) {
+	// the mons get the global settings, the value is the default if the option is not set
+	current, err := monStore.Get("mon", option)
+	if err != nil {
+		return enforced, errors.Wrapf(err, "failed to get the connection setting %q", option)
+	}
+
+	setByOperator := false
+	kept := []string{}
+	for _, o := range enforced {
+		if o == option {
+			setByOperator = true
+		} else {
+			kept = append(kept, o)
+		}
+	}
+
+	if enforce {
+		if current != value {
+			if err := monStore.Set("global", option, value); err != nil {
+				return enforced, errors.Wrapf(err, "failed to enforce the connection setting %q", option)
+			}
+			setByOperator = true
+		}
+		if setByOperator {
+			kept = append(kept, option)
+		}
+		return kept, nil
+	}
+
+	// the option is left to the user if they changed it since the operator set it
+	if setByOperator && current == value {
+		if err := monStore.Delete("global", option); err != nil {
+			return enforced, errors.Wrapf(err, "failed to remove the connection setting %q", option)
+		}
+	}
+	return kept, nil
+}
+
+// updateEnforcedConnectionOptions updates the connection options set by the operator in the status of
+// the CephCluster. The options are updated even if applying them failed midway.
+func (c *Cluster) updateEnforcedConnectionOptions(update func([]string) ([]string, error)) error {
+	cephCluster := &cephv1.CephCluster{}
+	err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.ClusterInfo.NamespacedName().String())
+	}
+
+	enforced, applyErr := update(append([]string{}, cephCluster.Status.EnforcedConnectionOptions...))
+	if len(enforced) == 0 {
+		enforced = nil
+	}
+	if !reflect.DeepEqual(cephCluster.Status.EnforcedConnectionOptions, enforced) {
+		cephCluster.Status.EnforcedConnectionOptions = enforced
+		if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+			if applyErr != nil {
+				logger.Errorf("failed to update the enforced connection options. %v", err)
+				return applyErr
+			}
+			return errors.Wrap(err, "failed to update the enforced connection options")
+		}
+	}
+	return applyErr
+}
+
+// countIncompatibleClients returns the number of connected clients not supporting the msgr2 protocol
+func (c *Cluster) countIncompatibleClients() (int, error) {
+	features, err := cephclient.GetFeatures(c.context, c.ClusterInfo)
+	if err != nil {
+		return 0, err
+	}
+
+	incompatible := 0
+	for _, group := range features.Client {
+		supported, err := group.SupportsMsgr2()
+		if err != nil {
+			return 0, err
+		}
+		if !supported {
+			logger.Warningf("%d clients of release %q do not support the msgr2 protocol", group.Num, group.Release)
+			incompatible += group.Num
+		}
+	}
+	return incompatible, nil
+}
+
+// updateClientsIncompatibleCondition sets the condition of the CephCluster reporting whether clients
+// would be locked out by the connection settings. The condition is only added to the status once
+// clients are found incompatible.
+func (c *Cluster) updateClientsIncompatibleCondition(incompatible bool, message string) error {
+	cephCluster := &cephv1.CephCluster{}
+	err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.ClusterInfo.NamespacedName().String())
+	}
+
+	condition := cephv1.Condition{
+		Type:    cephv1.ConditionClientsIncompatible,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.CompatibleClientsReason,
+		Message: message,
+	}
+	if incompatible {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = cephv1.IncompatibleClientsReason
+	} else {
+		existing := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionClientsIncompatible)
+		if existing == nil || existing.Status == v1.ConditionFalse {
+			// nothing to report
+			return nil
+		}
+	}
+
+	cephv1.SetStatusCondition(&cephCluster.Status.Conditions, condition)
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the clients incompatible condition")
+	}
+	return nil
+}
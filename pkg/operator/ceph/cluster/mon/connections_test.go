@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigureConnections(t *testing.T) {
+	clientFeatures := "0x3f01cfb9fffdffff"
+	configValue := ""
+	var configCommands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("executing command: %s %+v", command, args)
+			if args[0] == "features" {
+				return `{"client":[{"features":"` + clientFeatures + `","release":"luminous","num":2}]}`, nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return configValue, nil
+			}
+			if args[0] == "config" {
+				configCommands = append(configCommands, strings.Join(args[1:4], " "))
+			}
+			return "", nil
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	context := &clusterd.Context{Client: cl, Clientset: test.New(t, 1), Executor: executor}
+	c := New(context, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef(), &sync.Mutex{})
+	setCommonMonProperties(c, 1, cephv1.MonSpec{Count: 1}, "myversion")
+	c.ClusterInfo.Namespace = "ns"
+	c.ClusterInfo.SetName("rook")
+
+	getCondition := func() *cephv1.Condition {
+		err := cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+		require.NoError(t, err)
+		return cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionClientsIncompatible)
+	}
+
+	t.Run("nothing is enforced by default", func(t *testing.T) {
+		err := c.ConfigureConnections()
+		assert.NoError(t, err)
+		assert.Empty(t, configCommands)
+		assert.Nil(t, getCondition())
+		assert.Equal(t, DefaultMsgr1Port, c.newMonConfig(1, "").Port)
+	})
+
+	t.Run("enforced with compatible clients", func(t *testing.T) {
+		c.spec.Network.Connections = &cephv1.ConnectionsSpec{RequireMsgr2: true, Encryption: &cephv1.EncryptionSpec{Enforced: true}}
+		err := c.ConfigureConnections()
+		assert.NoError(t, err)
+		assert.Equal(t, 7, len(configCommands))
+		assert.Contains(t, configCommands, "set global ms_cluster_mode")
+		assert.Contains(t, configCommands, "set global ms_bind_msgr1")
+		assert.Nil(t, getCondition())
+		assert.Equal(t, DefaultMsgr2Port, c.newMonConfig(1, "").Port)
+		assert.Equal(t, 7, len(cephCluster.Status.EnforcedConnectionOptions))
+	})
+
+	t.Run("not enforced with incompatible clients", func(t *testing.T) {
+		configCommands = nil
+		clientFeatures = "0x27018fb86aa42ada"
+		err := c.ConfigureConnections()
+		assert.NoError(t, err)
+		assert.Empty(t, configCommands)
+		condition := getCondition()
+		require.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.IncompatibleClientsReason, condition.Reason)
+		assert.Equal(t, DefaultMsgr1Port, c.newMonConfig(1, "").Port)
+	})
+
+	t.Run("enforced after the clients are upgraded", func(t *testing.T) {
+		clientFeatures = "0x3f01cfb9fffdffff"
+		err := c.ConfigureConnections()
+		assert.NoError(t, err)
+		assert.Equal(t, 7, len(configCommands))
+		condition := getCondition()
+		require.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+		assert.Equal(t, cephv1.CompatibleClientsReason, condition.Reason)
+	})
+
+	t.Run("encryption settings are removed when no longer enforced", func(t *testing.T) {
+		configCommands = nil
+		configValue = "secure"
+		c.spec.Network.Connections.Encryption = nil
+		err := c.ConfigureConnections()
+		assert.NoError(t, err)
+		assert.Equal(t, 7, len(configCommands))
+		assert.Contains(t, configCommands, "rm global ms_cluster_mode")
+		assert.Contains(t, configCommands, "set global ms_bind_msgr1")
+		err = cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ms_bind_msgr1"}, cephCluster.Status.EnforcedConnectionOptions)
+	})
+
+	t.Run("the settings of the user are not removed", func(t *testing.T) {
+		// the user changed ms_bind_msgr1 since the operator set it and already set the encryption
+		configCommands = nil
+		configValue = "secure"
+		c.spec.Network.Connections = &cephv1.ConnectionsSpec{Encryption: &cephv1.EncryptionSpec{Enforced: true}}
+		err := c.ConfigureConnections()
+		assert.NoError(t, err)
+		assert.Empty(t, configCommands)
+
+		c.spec.Network.Connections = nil
+		err = c.ConfigureConnections()
+		assert.NoError(t, err)
+		assert.Empty(t, configCommands)
+		cephCluster = &cephv1.CephCluster{}
+		err = cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+		require.NoError(t, err)
+		assert.Empty(t, cephCluster.Status.EnforcedConnectionOptions)
+	})
+}
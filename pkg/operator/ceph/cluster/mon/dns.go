@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// DNSServiceName is the name of the headless service publishing the mons in DNS SRV records
+	DNSServiceName = "rook-ceph-mon"
+	// DNSConfigMapName is the name of the configmap with the configuration of the clients
+	// discovering the mons with DNS SRV records
+	DNSConfigMapName = "rook-ceph-mon-dns"
+	// DNSSRVNameKey is the key of the mon_dns_srv_name of the clients in the configmap
+	DNSSRVNameKey = "mon_dns_srv_name"
+	// DNSConfigKey is the key of the ceph.conf of the clients in the configmap
+	DNSConfigKey = "ceph.conf"
+
+	// the name of the service port is the service of the SRV records: _ceph-mon._tcp.<domain>
+	dnsSRVServiceName    = "ceph-mon"
+	defaultClusterDomain = "cluster.local"
+)
+
+// monDNSSRVName returns the mon_dns_srv_name of the clients. The domain of the SRV records follows
+// the service name after an underscore.
+func (c *Cluster) monDNSSRVName() string {
+	domain := defaultClusterDomain
+	if c.spec.Mon.DNSDiscovery.ClusterDomain != "" {
+		domain = c.spec.Mon.DNSDiscovery.ClusterDomain
+	}
+	return fmt.Sprintf("%s_%s.%s.svc.%s", dnsSRVServiceName, DNSServiceName, c.Namespace, domain)
+}
+
+// reconcileMonDNSDiscovery publishes the mons behind a headless service and the client configuration
+// discovering them with DNS SRV records, or removes them if the DNS discovery is disabled
+func (c *Cluster) reconcileMonDNSDiscovery() error {
+	if c.spec.Mon.DNSDiscovery == nil || !c.spec.Mon.DNSDiscovery.Enabled {
+		return c.removeMonDNSDiscovery()
+	}
+
+	// the SRV records point to the port the mons listen on, which is the target port of the headless service
+	port := DefaultMsgr1Port
+	if c.requireMsgr2() {
+		port = DefaultMsgr2Port
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DNSServiceName,
+			Namespace: c.Namespace,
+			Labels:    map[string]string{k8sutil.AppAttr: AppName, monClusterAttr: c.Namespace},
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: v1.ClusterIPNone,
+			Ports: []v1.ServicePort{
+				{
+					Name:       dnsSRVServiceName,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(port)),
+					Protocol:   v1.ProtocolTCP,
+				},
+			},
+			Selector: map[string]string{k8sutil.AppAttr: AppName, monClusterAttr: c.Namespace},
+		},
+	}
+	if err := c.ownerInfo.SetOwnerReference(service); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to mon dns service %q", service.Name)
+	}
+	if _, err := k8sutil.CreateOrUpdateService(c.context.Clientset, c.Namespace, service); err != nil {
+		return errors.Wrap(err, "failed to create mon dns service")
+	}
+
+	srvName := c.monDNSSRVName()
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DNSConfigMapName,
+			Namespace: c.Namespace,
+		},
+		Data: map[string]string{
+			DNSSRVNameKey: srvName,
+			DNSConfigKey:  fmt.Sprintf("[global]\n%s = %s\n", DNSSRVNameKey, srvName),
+		},
+	}
+	if err := c.ownerInfo.SetControllerReference(configMap); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to mon dns configmap %q", configMap.Name)
+	}
+	if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.Namespace).Create(c.ClusterInfo.Context, configMap, metav1.CreateOptions{}); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrap(err, "failed to create mon dns configmap")
+		}
+		if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.Namespace).Update(c.ClusterInfo.Context, configMap, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to update mon dns configmap")
+		}
+	}
+	logger.Infof("mons are discoverable with the dns srv name %q", srvName)
+	return nil
+}
+
+func (c *Cluster) removeMonDNSDiscovery() error {
+	if err := k8sutil.DeleteService(c.context.Clientset, c.Namespace, DNSServiceName); err != nil {
+		return errors.Wrap(err, "failed to delete mon dns service")
+	}
+	err := c.context.Clientset.CoreV1().ConfigMaps(c.Namespace).Delete(c.ClusterInfo.Context, DNSConfigMapName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete mon dns configmap")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileMonDNSDiscovery(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	c := New(&clusterd.Context{Clientset: clientset}, "ns", cephv1.ClusterSpec{}, &k8sutil.OwnerInfo{}, &sync.Mutex{})
+	c.ClusterInfo = client.AdminClusterInfo("ns")
+
+	// nothing is published by default
+	err := c.reconcileMonDNSDiscovery()
+	assert.NoError(t, err)
+	_, err = clientset.CoreV1().Services("ns").Get(ctx, DNSServiceName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// the mons are published behind a headless service
+	c.spec.Mon.DNSDiscovery = &cephv1.MonDNSDiscoverySpec{Enabled: true}
+	err = c.reconcileMonDNSDiscovery()
+	assert.NoError(t, err)
+	service, err := clientset.CoreV1().Services("ns").Get(ctx, DNSServiceName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, v1.ClusterIPNone, service.Spec.ClusterIP)
+	assert.Equal(t, "ceph-mon", service.Spec.Ports[0].Name)
+	assert.Equal(t, DefaultMsgr1Port, service.Spec.Ports[0].Port)
+	assert.Equal(t, map[string]string{"app": "rook-ceph-mon", "mon_cluster": "ns"}, service.Spec.Selector)
+	configMap, err := clientset.CoreV1().ConfigMaps("ns").Get(ctx, DNSConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "ceph-mon_rook-ceph-mon.ns.svc.cluster.local", configMap.Data[DNSSRVNameKey])
+	assert.Equal(t, "[global]\nmon_dns_srv_name = ceph-mon_rook-ceph-mon.ns.svc.cluster.local\n", configMap.Data[DNSConfigKey])
+
+	// the domain of the cluster is updated
+	c.spec.Mon.DNSDiscovery.ClusterDomain = "example.org"
+	err = c.reconcileMonDNSDiscovery()
+	assert.NoError(t, err)
+	configMap, err = clientset.CoreV1().ConfigMaps("ns").Get(ctx, DNSConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "ceph-mon_rook-ceph-mon.ns.svc.example.org", configMap.Data[DNSSRVNameKey])
+
+	// the service and configmap are removed when the discovery is disabled
+	c.spec.Mon.DNSDiscovery.Enabled = false
+	err = c.reconcileMonDNSDiscovery()
+	assert.NoError(t, err)
+	_, err = clientset.CoreV1().Services("ns").Get(ctx, DNSServiceName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	_, err = clientset.CoreV1().ConfigMaps("ns").Get(ctx, DNSConfigMapName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
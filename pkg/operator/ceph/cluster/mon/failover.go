@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// MonFailoverApprovalAnnotation is the annotation of the CephCluster approving the failover of
+	// the mon named in its value
+	MonFailoverApprovalAnnotation = "ceph.rook.io/approve-mon-failover"
+)
+
+// SetEventReporter sets the reporter of the events of the CephCluster
+func (c *Cluster) SetEventReporter(recorder *k8sutil.EventReporter) {
+	c.recorder = recorder
+}
+
+// isMonFailoverApproved returns whether the failover of the mon was approved with the annotation of
+// the CephCluster. If not, the failover is proposed with an event and a condition of the CephCluster
+// giving the reason of the failover.
+func (c *Cluster) isMonFailoverApproved(monName, reason string) (bool, error) {
+	cephCluster := &cephv1.CephCluster{}
+	err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get ceph cluster %q", c.ClusterInfo.NamespacedName().String())
+	}
+
+	if cephCluster.Annotations[MonFailoverApprovalAnnotation] == monName {
+		logger.Infof("failover of mon %q was approved", monName)
+		return true, nil
+	}
+
+	message := fmt.Sprintf("mon %q is %s. approve its failover with the annotation %s=%s on the CephCluster",
+		monName, reason, MonFailoverApprovalAnnotation, monName)
+	logger.Warning(message)
+	if c.recorder != nil {
+		c.recorder.ReportIfNotPresent(cephCluster, v1.EventTypeWarning, string(cephv1.MonFailoverAwaitingApprovalReason), message)
+	}
+	condition := cephv1.Condition{
+		Type:    cephv1.ConditionMonFailoverProposed,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.MonFailoverAwaitingApprovalReason,
+		Message: message,
+	}
+	if err := reporting.UpdateStatusCondition(c.context.Client, cephCluster, condition); err != nil {
+		return false, errors.Wrap(err, "failed to propose the mon failover")
+	}
+	return false, nil
+}
+
+// clearMonFailoverProposal resets the condition proposing a mon failover and removes the approval
+// annotation once the failover is done or not needed anymore
+func (c *Cluster) clearMonFailoverProposal() error {
+	cephCluster := &cephv1.CephCluster{}
+	err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.ClusterInfo.NamespacedName().String())
+	}
+
+	if _, ok := cephCluster.Annotations[MonFailoverApprovalAnnotation]; ok {
+		delete(cephCluster.Annotations, MonFailoverApprovalAnnotation)
+		if err := c.context.Client.Update(c.ClusterInfo.Context, cephCluster); err != nil {
+			return errors.Wrap(err, "failed to remove the mon failover approval annotation")
+		}
+	}
+
+	proposed := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionMonFailoverProposed)
+	if proposed == nil || proposed.Status == v1.ConditionFalse {
+		return nil
+	}
+	condition := cephv1.Condition{
+		Type:    cephv1.ConditionMonFailoverProposed,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.MonFailoverNotProposedReason,
+		Message: "no mon failover is waiting for approval",
+	}
+	if err := reporting.UpdateStatusCondition(c.context.Client, cephCluster, condition); err != nil {
+		return errors.Wrap(err, "failed to reset the mon failover proposal")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"sync"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMonFailoverApproval(t *testing.T) {
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	context := &clusterd.Context{Client: cl, Clientset: test.New(t, 1)}
+	c := New(context, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef(), &sync.Mutex{})
+	setCommonMonProperties(c, 3, cephv1.MonSpec{Count: 3}, "myversion")
+	c.ClusterInfo.Namespace = "ns"
+	c.ClusterInfo.SetName("rook")
+	recorder := record.NewFakeRecorder(10)
+	c.SetEventReporter(k8sutil.NewEventReporter(recorder))
+
+	getCluster := func() *cephv1.CephCluster {
+		cluster := &cephv1.CephCluster{}
+		err := cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cluster)
+		require.NoError(t, err)
+		return cluster
+	}
+
+	// no proposal to clear
+	err := c.clearMonFailoverProposal()
+	assert.NoError(t, err)
+	assert.Nil(t, cephv1.FindStatusCondition(getCluster().Status.Conditions, cephv1.ConditionMonFailoverProposed))
+
+	// the failover is proposed
+	approved, err := c.isMonFailoverApproved("a", "out of quorum for more than 10m0s")
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	condition := cephv1.FindStatusCondition(getCluster().Status.Conditions, cephv1.ConditionMonFailoverProposed)
+	require.NotNil(t, condition)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.MonFailoverAwaitingApprovalReason, condition.Reason)
+	assert.Contains(t, condition.Message, "ceph.rook.io/approve-mon-failover=a")
+	assert.Equal(t, 1, len(recorder.Events))
+
+	// the approval of another mon does not approve the failover
+	cluster := getCluster()
+	cluster.Annotations = map[string]string{MonFailoverApprovalAnnotation: "b"}
+	err = cl.Update(c.ClusterInfo.Context, cluster)
+	require.NoError(t, err)
+	approved, err = c.isMonFailoverApproved("a", "out of quorum for more than 10m0s")
+	assert.NoError(t, err)
+	assert.False(t, approved)
+
+	// the failover is approved
+	cluster = getCluster()
+	cluster.Annotations[MonFailoverApprovalAnnotation] = "a"
+	err = cl.Update(c.ClusterInfo.Context, cluster)
+	require.NoError(t, err)
+	approved, err = c.isMonFailoverApproved("a", "out of quorum for more than 10m0s")
+	assert.NoError(t, err)
+	assert.True(t, approved)
+
+	// the proposal is cleared after the failover
+	err = c.clearMonFailoverProposal()
+	assert.NoError(t, err)
+	cluster = getCluster()
+	_, ok := cluster.Annotations[MonFailoverApprovalAnnotation]
+	assert.False(t, ok)
+	condition = cephv1.FindStatusCondition(cluster.Status.Conditions, cephv1.ConditionMonFailoverProposed)
+	require.NotNil(t, condition)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, cephv1.MonFailoverNotProposedReason, condition.Reason)
+}
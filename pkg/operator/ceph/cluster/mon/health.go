@@ -19,7 +19,10 @@ package mon
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,8 +30,13 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephutil "github.com/rook/rook/pkg/daemon/ceph/util"
+	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/notification"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/exec"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,13 +45,16 @@ import (
 var (
 	// HealthCheckInterval is the interval to check if the mons are in quorum
 	HealthCheckInterval = 45 * time.Second
-	// MonOutTimeout is the duration to wait before removing/failover to a new mon pod
+	// MonOutTimeout is the default duration to wait before removing/failover to a new mon pod
 	MonOutTimeout = 10 * time.Minute
 
 	retriesBeforeNodeDrainFailover = 1
 	timeZero                       = time.Duration(0)
 	// Check whether mons are on the same node once per operator restart since it's a rare scheduling condition
 	needToCheckMonsOnSameNode = true
+
+	// hook for tests to override
+	getMonStoreSize = realGetMonStoreSize
 )
 
 // HealthChecker aggregates the mon/cluster info needed to check the health of the monitors
@@ -53,6 +64,10 @@ type HealthChecker struct {
 }
 
 func updateMonTimeout(monCluster *Cluster) {
+	// The timeout is kept per cluster, starting again from the default at each update so that
+	// the timeout is reset when it is removed from the CR
+	monOutTimeout := MonOutTimeout
+
 	// If the env was passed by the operator config, use that value
 	// This is an old behavior where we maintain backward compatibility
 	monTimeoutEnv := os.Getenv("ROOK_MON_OUT_TIMEOUT")
@@ -60,21 +75,40 @@ func updateMonTimeout(monCluster *Cluster) {
 		parsedInterval, err := time.ParseDuration(monTimeoutEnv)
 		// We ignore the error here since the default is 10min and it's unlikely to be a problem
 		if err == nil {
-			MonOutTimeout = parsedInterval
+			monOutTimeout = parsedInterval
 		}
 		// No env var, let's use the CR value if any
 	} else {
 		monCRDTimeoutSetting := monCluster.spec.HealthCheck.DaemonHealth.Monitor.Timeout
 		if monCRDTimeoutSetting != "" {
-			if monTimeout, err := time.ParseDuration(monCRDTimeoutSetting); err == nil {
-				if monTimeout == timeZero {
+			monTimeout, err := time.ParseDuration(monCRDTimeoutSetting)
+			if err != nil {
+				logger.Warningf("invalid mon timeout %q, using the default of %s. %v", monCRDTimeoutSetting, MonOutTimeout.String(), err)
+			} else {
+				if monTimeout == timeZero && monCluster.monOutTimeout != timeZero {
 					logger.Warning("monitor failover is disabled")
 				}
-				MonOutTimeout = monTimeout
+				monOutTimeout = monTimeout
 			}
 		}
 	}
 	// A third case is when the CRD is not set, in which case we use the default from MonOutTimeout
+	monCluster.monOutTimeout = monOutTimeout
+}
+
+// monClockSkewWarning returns the clock skew of a mon above which a warning is reported, or zero if
+// no warning must be reported
+func (c *Cluster) monClockSkewWarning() time.Duration {
+	setting := c.spec.HealthCheck.DaemonHealth.Monitor.ClockSkewWarning
+	if setting == "" {
+		return timeZero
+	}
+	threshold, err := time.ParseDuration(setting)
+	if err != nil {
+		logger.Warningf("invalid mon clock skew warning %q, not reporting the clock skew. %v", setting, err)
+		return timeZero
+	}
+	return threshold
 }
 
 func updateMonInterval(monCluster *Cluster, h *HealthChecker) {
@@ -126,6 +160,10 @@ func (hc *HealthChecker) Check(context context.Context) {
 			if err != nil {
 				logger.Warningf("failed to check mon health. %v", err)
 			}
+			err = hc.monCluster.updateMonHealthStatus()
+			if err != nil {
+				logger.Warningf("failed to update mon health status. %v", err)
+			}
 		}
 	}
 }
@@ -177,6 +215,7 @@ func (c *Cluster) checkHealth() error {
 
 	// Use a local mon count in case the user updates the crd in another goroutine.
 	// We need to complete a health check with a consistent value.
+	c.autoScaleMonCount()
 	desiredMonCount := c.spec.Mon.Count
 	logger.Debugf("targeting the mon count %d", desiredMonCount)
 
@@ -202,6 +241,8 @@ func (c *Cluster) checkHealth() error {
 				logger.Warningf("mon %q not in source of truth but in quorum, removing", mon.Name)
 				if err := c.removeMon(mon.Name); err != nil {
 					logger.Warningf("failed to remove mon %q. %v", mon.Name, err)
+				} else {
+					c.recordCorrectiveAction(cephv1.CorrectiveActionMonRemoval, mon.Name, "not in the source of truth but in quorum")
 				}
 				// only remove one extra mon per health check
 				return nil
@@ -227,10 +268,14 @@ func (c *Cluster) checkHealth() error {
 		logger.Debugf("mon %q NOT found in quorum. Mon quorum status: %+v", mon.Name, quorumStatus)
 
 		// if the time out is set to 0 this indicate that we don't want to trigger mon failover
-		if MonOutTimeout == timeZero {
+		if c.monOutTimeout == timeZero {
 			logger.Warningf("mon %q NOT found in quorum and health timeout is 0, mon will never fail over", mon.Name)
 			return nil
 		}
+		if c.spec.HealthCheck.DaemonHealth.Monitor.DisableFailover {
+			logger.Warningf("mon %q NOT found in quorum and the mon failover is disabled, mon will never fail over", mon.Name)
+			return nil
+		}
 
 		allMonsInQuorum = false
 
@@ -242,8 +287,8 @@ func (c *Cluster) checkHealth() error {
 
 		// when the timeout for the mon has been reached, continue to the
 		// normal failover mon pod part of the code
-		if time.Since(c.monTimeoutList[mon.Name]) <= MonOutTimeout {
-			timeToFailover := int(MonOutTimeout.Seconds() - time.Since(c.monTimeoutList[mon.Name]).Seconds())
+		if time.Since(c.monTimeoutList[mon.Name]) <= c.monOutTimeout {
+			timeToFailover := int(c.monOutTimeout.Seconds() - time.Since(c.monTimeoutList[mon.Name]).Seconds())
 			logger.Warningf("mon %q not found in quorum, waiting for timeout (%d seconds left) before failover", mon.Name, timeToFailover)
 			continue
 		}
@@ -254,28 +299,60 @@ func (c *Cluster) checkHealth() error {
 		if err != nil {
 			logger.Warningf("failed to check if mon %q is assigned to a node, continuing with mon failover. %v", mon.Name, err)
 		} else if !isScheduled && retriesBeforeNodeDrainFailover > 0 {
-			logger.Warningf("mon %q NOT found in quorum after timeout. Mon pod is not scheduled. Retrying with a timeout of %.2f seconds before failover", mon.Name, MonOutTimeout.Seconds())
+			logger.Warningf("mon %q NOT found in quorum after timeout. Mon pod is not scheduled. Retrying with a timeout of %.2f seconds before failover", mon.Name, c.monOutTimeout.Seconds())
 			delete(c.monTimeoutList, mon.Name)
 			retriesBeforeNodeDrainFailover = retriesBeforeNodeDrainFailover - 1
 			return nil
 		}
 		retriesBeforeNodeDrainFailover = 1
 
+		// wait for the failover to be approved if confirmation is required
+		if c.spec.RequireMonFailoverConfirmation() {
+			approved, err := c.isMonFailoverApproved(mon.Name, fmt.Sprintf("out of quorum for more than %s", c.monOutTimeout.String()))
+			if err != nil {
+				logger.Errorf("failed to check if the failover of mon %q is approved. %v", mon.Name, err)
+				continue
+			}
+			if !approved {
+				continue
+			}
+		}
+
 		logger.Warningf("mon %q NOT found in quorum and timeout exceeded, mon will be failed over", mon.Name)
-		if !c.failMon(len(quorumStatus.MonMap.Mons), desiredMonCount, mon.Name) {
+		reason := fmt.Sprintf("out of quorum for more than %s", c.monOutTimeout)
+		if !c.failMon(len(quorumStatus.MonMap.Mons), desiredMonCount, mon.Name, reason) {
 			// The failover was skipped, so we continue to see if another mon needs to failover
 			continue
 		}
 
+		if c.spec.RequireMonFailoverConfirmation() {
+			if err := c.clearMonFailoverProposal(); err != nil {
+				logger.Errorf("failed to clear the failover proposal of mon %q. %v", mon.Name, err)
+			}
+		}
+
 		// only deal with one unhealthy mon per health check
 		return nil
 	}
 
+	// the proposed failover is not needed anymore if the mons are back in quorum, unless it is the
+	// failover of a mon waiting for its migration to the new network
+	if allMonsInQuorum && c.spec.RequireMonFailoverConfirmation() {
+		migrating, err := c.MonsOnPreviousNetwork()
+		if err != nil {
+			logger.Errorf("failed to check the network of the mons. %v", err)
+		} else if len(migrating) == 0 {
+			if err := c.clearMonFailoverProposal(); err != nil {
+				logger.Errorf("failed to clear the mon failover proposal. %v", err)
+			}
+		}
+	}
+
 	// after all unhealthy mons have been removed or failed over
 	// handle all mons that haven't been in the Ceph mon map
 	for mon := range monsNotFound {
 		logger.Warningf("mon %s NOT found in ceph mon map, failover", mon)
-		c.failMon(len(c.ClusterInfo.Monitors), desiredMonCount, mon)
+		c.failMon(len(c.ClusterInfo.Monitors), desiredMonCount, mon, "not found in the ceph mon map")
 		// only deal with one "not found in ceph mon map" mon per health check
 		return nil
 	}
@@ -292,7 +369,12 @@ func (c *Cluster) checkHealth() error {
 			logger.Warningf("cannot reduce mon quorum size from 2 to 1")
 		} else {
 			logger.Infof("removing an extra mon. currently %d are in quorum and only %d are desired", len(quorumStatus.MonMap.Mons), desiredMonCount)
-			return c.removeMon(quorumStatus.MonMap.Mons[0].Name)
+			name := quorumStatus.MonMap.Mons[0].Name
+			if err := c.removeMon(name); err != nil {
+				return err
+			}
+			c.recordCorrectiveAction(cephv1.CorrectiveActionMonRemoval, name, fmt.Sprintf("the desired mon count decreased to %d", desiredMonCount))
+			return nil
 		}
 	}
 
@@ -301,6 +383,9 @@ func (c *Cluster) checkHealth() error {
 		logger.Debug("mon cluster is healthy, removing any existing canary deployment")
 		c.removeCanaryDeployments()
 
+		// clean up the resources of the mons removed from the cluster, and the mon pvcs whose retention is over
+		c.removeOrphanMonResources()
+
 		// Check whether two healthy mons are on the same node when they should not be.
 		// This should be a rare event to find them on the same node, so we just need to check
 		// once per operator restart.
@@ -308,19 +393,201 @@ func (c *Cluster) checkHealth() error {
 			needToCheckMonsOnSameNode = false
 			return c.evictMonIfMultipleOnSameNode()
 		}
+
+		// Restart the mons one at a time if requested
+		restarted, err := c.restartMonsIfRequested()
+		if err != nil {
+			return errors.Wrap(err, "failed to restart the mons")
+		}
+		if restarted {
+			return nil
+		}
+
+		// Fail over the mons still running on the previous network when the host networking changed
+		migrated, err := c.migrateMonNetwork()
+		if err != nil {
+			return errors.Wrap(err, "failed to migrate the mons to the new network")
+		}
+		if migrated {
+			return nil
+		}
+
+		// Fail over the mons still listening on the msgr1 port when only msgr2 is required
+		if c.requireMsgr2() {
+			return c.failoverMsgr1Mon()
+		}
 	}
 
 	return nil
 }
 
+// updateMonHealthStatus refreshes the mon health in the CephCluster status and the mon metrics
+func (c *Cluster) updateMonHealthStatus() error {
+	// the external cluster mons are not managed by rook
+	if c.spec.External.Enable {
+		return nil
+	}
+
+	if !c.ClusterInfo.IsInitialized(false) {
+		return errors.New("skipping mon health status update since cluster details are not initialized")
+	}
+
+	quorumStatus, err := cephclient.GetMonQuorumStatus(c.context, c.ClusterInfo)
+	if err != nil {
+		c.notifyQuorumChange(false, fmt.Sprintf("mon quorum is lost. %v", err))
+		return errors.Wrap(err, "failed to get mon quorum status")
+	}
+	c.notifyQuorumChange(true, fmt.Sprintf("mon quorum is restored with %d mons", len(quorumStatus.Quorum)))
+
+	// the clock skew is best effort, the quorum is what matters most
+	timeSyncStatus, err := cephclient.GetMonTimeSyncStatus(c.context, c.ClusterInfo)
+	if err != nil {
+		logger.Warningf("failed to get mon time sync status. %v", err)
+	}
+
+	monHealth := &cephv1.MonHealthStatus{
+		LastChecked:  time.Now().UTC().Format(time.RFC3339),
+		DesiredCount: c.spec.Mon.Count,
+	}
+	clockSkewWarning := c.monClockSkewWarning()
+	clockSkewMessages := []string{}
+	for _, mon := range quorumStatus.MonMap.Mons {
+		health := cephv1.MonHealth{
+			Name:     mon.Name,
+			Rank:     mon.Rank,
+			InQuorum: monInQuorum(mon, quorumStatus.Quorum),
+		}
+		if skew, ok := timeSyncStatus.TimeSkewStatus[mon.Name]; ok {
+			health.ClockSkewSeconds = skew.Skew
+			if clockSkewWarning != timeZero && math.Abs(skew.Skew) > clockSkewWarning.Seconds() {
+				message := fmt.Sprintf("clock skew of mon %q is %.3fs, above the warning threshold of %s", mon.Name, skew.Skew, clockSkewWarning.String())
+				logger.Warning(message)
+				clockSkewMessages = append(clockSkewMessages, message)
+			}
+		}
+		storeSize, err := getMonStoreSize(c, mon.Name)
+		if err != nil {
+			logger.Debugf("failed to get store size of mon %q. %v", mon.Name, err)
+		} else {
+			health.StoreSizeBytes = storeSize
+		}
+		monHealth.Mons = append(monHealth.Mons, health)
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	err = c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.ClusterInfo.NamespacedName().String())
+	}
+
+	// keep the time of the last compactions, then compact a store if needed
+	if cephCluster.Status.MonHealth != nil {
+		for i := range monHealth.Mons {
+			for _, previous := range cephCluster.Status.MonHealth.Mons {
+				if previous.Name == monHealth.Mons[i].Name {
+					monHealth.Mons[i].LastCompacted = previous.LastCompacted
+				}
+			}
+		}
+	}
+	c.compactMonStores(monHealth)
+
+	if c.recorder != nil {
+		for _, message := range clockSkewMessages {
+			c.recorder.ReportIfNotPresent(cephCluster, v1.EventTypeWarning, string(cephv1.MonClockSkewReason), message)
+		}
+	}
+
+	reportMonHealthMetrics(c.Namespace, cephCluster.Status.MonHealth, monHealth)
+
+	cephCluster.Status.MonHealth = monHealth
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update mon health status")
+	}
+	return nil
+}
+
+// notifyQuorumChange notifies the loss of the mon quorum, and its restoration once the loss was notified
+func (c *Cluster) notifyQuorumChange(inQuorum bool, message string) {
+	if c.quorumLost != inQuorum {
+		return
+	}
+	c.quorumLost = !inQuorum
+
+	cephCluster := &cephv1.CephCluster{}
+	err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		logger.Errorf("failed to get ceph cluster %q to notify the quorum change. %v", c.ClusterInfo.NamespacedName().String(), err)
+		return
+	}
+	eventType, severity := notification.QuorumRestored, notification.SeverityInfo
+	if !inQuorum {
+		eventType, severity = notification.QuorumLost, notification.SeverityCritical
+	}
+	if err := notification.Notify(c.context, cephCluster, eventType, severity, message); err != nil {
+		logger.Errorf("failed to notify the mon quorum change. %v", err)
+	}
+}
+
+// realGetMonStoreSize returns the size of the mon store by running du in the mon container
+func realGetMonStoreSize(c *Cluster, monName string) (uint64, error) {
+	if c.context.RemoteExecutor.RestClient == nil {
+		return 0, errors.New("remote executor is not configured")
+	}
+
+	monLabelSelector := fmt.Sprintf("%s=%s,%s=%s", k8sutil.AppAttr, AppName, controller.DaemonIDLabel, monName)
+	pods, err := c.context.Clientset.CoreV1().Pods(c.Namespace).List(c.ClusterInfo.Context, metav1.ListOptions{LabelSelector: monLabelSelector})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list pods for mon %q", monName)
+	}
+	if len(pods.Items) == 0 {
+		return 0, errors.Errorf("no pod found for mon %q", monName)
+	}
+
+	storePath := path.Join(config.NewStatefulDaemonDataPathMap(
+		c.spec.DataDirHostPath, dataDirRelativeHostPath(monName), config.MonType, monName, c.Namespace).ContainerDataDir, "store.db")
+	stdout, stderr, err := c.context.RemoteExecutor.ExecWithOptions(exec.ExecOptions{
+		Command:       []string{"du", "-sb", storePath},
+		Namespace:     c.Namespace,
+		PodName:       pods.Items[0].Name,
+		ContainerName: "mon",
+		CaptureStdout: true,
+		CaptureStderr: true,
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get size of %q. %s", storePath, stderr)
+	}
+
+	return parseDiskUsage(stdout)
+}
+
+// parseDiskUsage parses the output of "du -sb <path>"
+func parseDiskUsage(output string) (uint64, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return 0, errors.New("empty disk usage output")
+	}
+	size, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse disk usage %q", output)
+	}
+	return size, nil
+}
+
 // failMon compares the monCount against desiredMonCount
 // Returns whether the failover request was attempted. If false,
 // the operator should check for other mons to failover.
-func (c *Cluster) failMon(monCount, desiredMonCount int, name string) bool {
+func (c *Cluster) failMon(monCount, desiredMonCount int, name, reason string) bool {
 	if monCount > desiredMonCount {
 		// no need to create a new mon since we have an extra
 		if err := c.removeMon(name); err != nil {
 			logger.Errorf("failed to remove mon %q. %v", name, err)
+		} else {
+			c.recordCorrectiveAction(cephv1.CorrectiveActionMonRemoval, name, reason)
 		}
 	} else {
 		if c.spec.IsStretchCluster() && name == c.arbiterMon {
@@ -338,7 +605,7 @@ func (c *Cluster) failMon(monCount, desiredMonCount int, name string) bool {
 		}
 
 		// bring up a new mon to replace the unhealthy mon
-		if err := c.failoverMon(name); err != nil {
+		if err := c.failoverMon(name, reason); err != nil {
 			logger.Errorf("failed to failover mon %q. %v", name, err)
 		}
 
@@ -350,45 +617,6 @@ func (c *Cluster) failMon(monCount, desiredMonCount int, name string) bool {
 	return true
 }
 
-func (c *Cluster) removeOrphanMonResources() {
-	if c.spec.Mon.VolumeClaimTemplate == nil {
-		logger.Debug("skipping check for orphaned mon pvcs since using the host path")
-		return
-	}
-
-	logger.Info("checking for orphaned mon resources")
-
-	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
-	pvcs, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).List(c.ClusterInfo.Context, opts)
-	if err != nil {
-		logger.Infof("failed to check for orphaned mon pvcs. %v", err)
-		return
-	}
-
-	for _, pvc := range pvcs.Items {
-		logger.Debugf("checking if pvc %q is orphaned", pvc.Name)
-
-		_, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, pvc.Name, metav1.GetOptions{})
-		if err == nil {
-			logger.Debugf("skipping pvc removal since the mon daemon %q still requires it", pvc.Name)
-			continue
-		}
-		if !kerrors.IsNotFound(err) {
-			logger.Infof("skipping pvc removal since the mon daemon %q might still require it. %v", pvc.Name, err)
-			continue
-		}
-
-		logger.Infof("removing pvc %q since it is no longer needed for the mon daemon", pvc.Name)
-		var gracePeriod int64 // delete immediately
-		propagation := metav1.DeletePropagationForeground
-		options := &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod, PropagationPolicy: &propagation}
-		err = c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Delete(c.ClusterInfo.Context, pvc.Name, *options)
-		if err != nil {
-			logger.Warningf("failed to delete orphaned monitor pvc %q. %v", pvc.Name, err)
-		}
-	}
-}
-
 func (c *Cluster) updateMonDeploymentReplica(name string, enabled bool) error {
 	// get the existing deployment
 	d, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, resourceName(name), metav1.GetOptions{})
@@ -413,7 +641,7 @@ func (c *Cluster) updateMonDeploymentReplica(name string, enabled bool) error {
 	return nil
 }
 
-func (c *Cluster) failoverMon(name string) error {
+func (c *Cluster) failoverMon(name, reason string) error {
 	logger.Infof("Failing over monitor %q", name)
 
 	// Scale down the failed mon to allow a new one to start
@@ -486,7 +714,20 @@ func (c *Cluster) failoverMon(name string) error {
 	c.maxMonID++
 	newMonSucceeded = true
 
-	return c.removeMon(name)
+	if err := c.removeMon(name); err != nil {
+		return err
+	}
+	c.recordCorrectiveAction(cephv1.CorrectiveActionMonFailover, name, reason)
+	return nil
+}
+
+// recordCorrectiveAction adds the action to the history of the corrective actions in the status of
+// the CephCluster. The history is best effort, the action was already taken.
+func (c *Cluster) recordCorrectiveAction(actionType cephv1.CorrectiveActionType, name, reason string) {
+	target := fmt.Sprintf("mon.%s", name)
+	if err := reporting.RecordCorrectiveAction(c.ClusterInfo.Context, c.context.Client, c.ClusterInfo.NamespacedName(), actionType, target, reason); err != nil {
+		logger.Warningf("failed to record the corrective action %s of mon %q. %v", actionType, name, err)
+	}
 }
 
 // make a best effort to remove the mon and all its resources
@@ -524,8 +765,10 @@ func (c *Cluster) removeMon(daemonName string) error {
 		}
 	}
 
-	// Remove the PVC backing the mon if it existed
-	if err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Delete(c.ClusterInfo.Context, resourceName, metav1.DeleteOptions{}); err != nil {
+	// Remove the PVC backing the mon if it existed, unless it is retained for a while
+	if c.spec.MonPVCRetentionPeriod() > 0 {
+		logger.Infof("keeping pvc %q of the removed mon until its retention period is over", resourceName)
+	} else if err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Delete(c.ClusterInfo.Context, resourceName, metav1.DeleteOptions{}); err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Infof("mon pvc did not exist %q", resourceName)
 		} else {
@@ -688,8 +931,22 @@ func (c *Cluster) evictMonIfMultipleOnSameNode() error {
 		}
 
 		logger.Warningf("Both mons %q and %q are on node %q. Evicting mon %q", monName, previousMonName, pod.Spec.NodeName, monName)
-		return c.failoverMon(monName)
+		return c.failoverMon(monName, fmt.Sprintf("on the same node %q as mon %q", pod.Spec.NodeName, previousMonName))
 	}
 
 	return nil
 }
+
+// failoverMsgr1Mon fails over one mon that still listens on the msgr1 port so that it is replaced by a
+// mon listening only on the msgr2 port
+func (c *Cluster) failoverMsgr1Mon() error {
+	for _, mon := range c.ClusterInfo.Monitors {
+		if cephutil.GetPortFromEndpoint(mon.Endpoint) == DefaultMsgr2Port {
+			continue
+		}
+		logger.Infof("mon %q listens on the msgr1 port but only msgr2 is required. failing over the mon", mon.Name)
+		// only fail over one mon per health check
+		return c.failoverMon(mon.Name, "listening on the msgr1 port")
+	}
+	return nil
+}
@@ -18,19 +18,26 @@ package mon
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
 	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/notification"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	testopk8s "github.com/rook/rook/pkg/operator/k8sutil/test"
 	"github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
@@ -40,6 +47,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestCheckHealth(t *testing.T) {
@@ -60,6 +69,7 @@ func TestCheckHealth(t *testing.T) {
 	configDir, _ := ioutil.TempDir("", "")
 	defer os.RemoveAll(configDir)
 	context := &clusterd.Context{
+		Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		Clientset: clientset,
 		ConfigDir: configDir,
 		Executor:  executor,
@@ -99,7 +109,7 @@ func TestCheckHealth(t *testing.T) {
 	assert.ElementsMatch(t, []string{"rook-ceph-mon-a", "rook-ceph-mon-f"}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
 	testopk8s.ClearDeploymentsUpdated(deploymentsUpdated)
 
-	err = c.failoverMon("f")
+	err = c.failoverMon("f", "")
 	assert.Nil(t, err)
 	assert.ElementsMatch(t, []string{}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
 	testopk8s.ClearDeploymentsUpdated(deploymentsUpdated)
@@ -164,7 +174,7 @@ func TestEvictMonOnSameNode(t *testing.T) {
 			return "{\"key\":\"mysecurekey\"}", nil
 		},
 	}
-	context := &clusterd.Context{Clientset: clientset, ConfigDir: configDir, Executor: executor}
+	context := &clusterd.Context{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(), Clientset: clientset, ConfigDir: configDir, Executor: executor}
 	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
 	c := New(context, "ns", cephv1.ClusterSpec{}, ownerInfo, &sync.Mutex{})
 	setCommonMonProperties(c, 1, cephv1.MonSpec{Count: 0}, "myversion")
@@ -267,6 +277,7 @@ func TestCheckHealthNotFound(t *testing.T) {
 	configDir, _ := ioutil.TempDir("", "")
 	defer os.RemoveAll(configDir)
 	context := &clusterd.Context{
+		Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		Clientset: clientset,
 		ConfigDir: configDir,
 		Executor:  executor,
@@ -329,6 +340,7 @@ func TestAddRemoveMons(t *testing.T) {
 	configDir, _ := ioutil.TempDir("", "")
 	defer os.RemoveAll(configDir)
 	context := &clusterd.Context{
+		Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		Clientset: clientset,
 		ConfigDir: configDir,
 		Executor:  executor,
@@ -478,19 +490,31 @@ func TestUpdateMonTimeout(t *testing.T) {
 	t.Run("using default mon timeout", func(t *testing.T) {
 		m := &Cluster{}
 		updateMonTimeout(m)
-		assert.Equal(t, time.Minute*10, MonOutTimeout)
+		assert.Equal(t, time.Minute*10, m.monOutTimeout)
 	})
 	t.Run("using env var mon timeout", func(t *testing.T) {
 		os.Setenv("ROOK_MON_OUT_TIMEOUT", "10s")
 		defer os.Unsetenv("ROOK_MON_OUT_TIMEOUT")
 		m := &Cluster{}
 		updateMonTimeout(m)
-		assert.Equal(t, time.Second*10, MonOutTimeout)
+		assert.Equal(t, time.Second*10, m.monOutTimeout)
 	})
 	t.Run("using spec mon timeout", func(t *testing.T) {
-		m := &Cluster{spec: cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Monitor: cephv1.HealthCheckSpec{Timeout: "1m"}}}}}
+		m := &Cluster{spec: cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Monitor: cephv1.MonHealthCheckSpec{HealthCheckSpec: cephv1.HealthCheckSpec{Timeout: "1m"}}}}}}
+		updateMonTimeout(m)
+		assert.Equal(t, time.Minute, m.monOutTimeout)
+		// the timeout is per cluster
+		assert.Equal(t, time.Minute*10, MonOutTimeout)
+
+		// the default is used again when the spec is invalid or removed
+		m.spec.HealthCheck.DaemonHealth.Monitor.Timeout = "1"
 		updateMonTimeout(m)
-		assert.Equal(t, time.Minute, MonOutTimeout)
+		assert.Equal(t, time.Minute*10, m.monOutTimeout)
+		m.spec.HealthCheck.DaemonHealth.Monitor.Timeout = "1m"
+		updateMonTimeout(m)
+		m.spec.HealthCheck.DaemonHealth.Monitor.Timeout = ""
+		updateMonTimeout(m)
+		assert.Equal(t, time.Minute*10, m.monOutTimeout)
 	})
 }
 
@@ -512,9 +536,116 @@ func TestUpdateMonInterval(t *testing.T) {
 	t.Run("using spec mon timeout", func(t *testing.T) {
 		tm, err := time.ParseDuration("1m")
 		assert.NoError(t, err)
-		m := &Cluster{spec: cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Monitor: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: tm}}}}}}
+		m := &Cluster{spec: cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Monitor: cephv1.MonHealthCheckSpec{HealthCheckSpec: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: tm}}}}}}}
 		h := &HealthChecker{m, HealthCheckInterval}
 		updateMonInterval(m, h)
 		assert.Equal(t, time.Minute, h.interval)
 	})
 }
+
+func TestUpdateMonHealthStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("executing command: %s %+v", command, args)
+			if args[0] == "time-sync-status" {
+				return `{"time_skew_status":{"a":{"skew":0,"latency":0,"health":"HEALTH_OK"},"b":{"skew":0.25,"latency":0.001,"health":"HEALTH_WARN"}}}`, nil
+			}
+			// mon "b" is out of quorum
+			return `{"quorum":[0],"monmap":{"mons":[{"name":"a","rank":0},{"name":"b","rank":1}]}}`, nil
+		},
+	}
+	getMonStoreSize = func(c *Cluster, monName string) (uint64, error) {
+		if monName == "a" {
+			return 1024, nil
+		}
+		return 0, errors.New("no pod found")
+	}
+	defer func() { getMonStoreSize = realGetMonStoreSize }()
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	s := scheme.Scheme
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster).Build()
+	context := &clusterd.Context{Client: cl, Clientset: test.New(t, 1), Executor: executor}
+	c := New(context, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef(), &sync.Mutex{})
+	setCommonMonProperties(c, 2, cephv1.MonSpec{Count: 2}, "myversion")
+	c.ClusterInfo.Namespace = "ns"
+	c.ClusterInfo.SetName("rook")
+
+	err := c.updateMonHealthStatus()
+	assert.NoError(t, err)
+
+	err = cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+	assert.NoError(t, err)
+	require.NotNil(t, cephCluster.Status.MonHealth)
+	assert.NotEmpty(t, cephCluster.Status.MonHealth.LastChecked)
+	assert.Equal(t, []cephv1.MonHealth{
+		{Name: "a", Rank: 0, InQuorum: true, StoreSizeBytes: 1024},
+		{Name: "b", Rank: 1, InQuorum: false, ClockSkewSeconds: 0.25},
+	}, cephCluster.Status.MonHealth.Mons)
+
+	// a warning is reported for the mons with a clock skew above the threshold
+	recorder := record.NewFakeRecorder(10)
+	c.SetEventReporter(k8sutil.NewEventReporter(recorder))
+	c.spec.HealthCheck.DaemonHealth.Monitor.ClockSkewWarning = "100ms"
+	err = c.updateMonHealthStatus()
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(recorder.Events))
+	event := <-recorder.Events
+	assert.Contains(t, event, string(cephv1.MonClockSkewReason))
+	assert.Contains(t, event, `mon "b"`)
+
+	// an invalid threshold reports no warning
+	c.spec.HealthCheck.DaemonHealth.Monitor.ClockSkewWarning = "100"
+	assert.Equal(t, time.Duration(0), c.monClockSkewWarning())
+
+	// external clusters are skipped
+	c.spec.External.Enable = true
+	cephCluster.Status.MonHealth = nil
+	err = c.updateMonHealthStatus()
+	assert.NoError(t, err)
+}
+
+func TestParseDiskUsage(t *testing.T) {
+	size, err := parseDiskUsage("123456\t/var/lib/ceph/mon/ceph-a/store.db")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(123456), size)
+
+	_, err = parseDiskUsage("")
+	assert.Error(t, err)
+
+	_, err = parseDiskUsage("du: cannot access")
+	assert.Error(t, err)
+}
+
+func TestNotifyQuorumChange(t *testing.T) {
+	var notified []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := notification.Event{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		notified = append(notified, string(event.Type))
+	}))
+	defer server.Close()
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cephCluster.Spec.Notifications.Webhook = &cephv1.WebhookNotificationSpec{URL: server.URL, Severity: "info"}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	context := &clusterd.Context{Client: cl, Clientset: test.New(t, 1)}
+	c := New(context, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef(), &sync.Mutex{})
+	setCommonMonProperties(c, 3, cephv1.MonSpec{Count: 3}, "myversion")
+	c.ClusterInfo.Namespace = "ns"
+	c.ClusterInfo.SetName("rook")
+
+	// nothing to notify while in quorum
+	c.notifyQuorumChange(true, "in quorum")
+	assert.Empty(t, notified)
+
+	// the loss of quorum is notified once
+	c.notifyQuorumChange(false, "quorum lost")
+	c.notifyQuorumChange(false, "quorum lost")
+	assert.Equal(t, []string{"QuorumLost"}, notified)
+
+	// the restoration is notified once
+	c.notifyQuorumChange(true, "quorum restored")
+	c.notifyQuorumChange(true, "quorum restored")
+	assert.Equal(t, []string{"QuorumLost", "QuorumRestored"}, notified)
+}
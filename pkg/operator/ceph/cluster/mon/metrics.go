@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	monLabels = []string{"namespace", "mon"}
+
+	monInQuorumGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_mon_in_quorum",
+		Help: "Whether the mon is in quorum (1) or out of quorum (0)",
+	}, monLabels)
+	monRankGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_mon_rank",
+		Help: "Rank of the mon in the mon map",
+	}, monLabels)
+	monClockSkewGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_mon_clock_skew_seconds",
+		Help: "Clock skew of the mon relative to the leader in seconds",
+	}, monLabels)
+	monStoreSizeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_mon_store_size_bytes",
+		Help: "Size of the mon store on disk in bytes",
+	}, monLabels)
+)
+
+func init() {
+	// the controller-runtime registry is served by the operator's manager metrics endpoint
+	metrics.Registry.MustRegister(monInQuorumGauge, monRankGauge, monClockSkewGauge, monStoreSizeGauge)
+}
+
+// reportMonHealthMetrics sets the mon gauges for the current mon health and removes the series of
+// mons that are no longer in the mon map
+func reportMonHealthMetrics(namespace string, previous, current *cephv1.MonHealthStatus) {
+	if previous != nil {
+		found := map[string]bool{}
+		for _, mon := range current.Mons {
+			found[mon.Name] = true
+		}
+		for _, mon := range previous.Mons {
+			if !found[mon.Name] {
+				deleteMonHealthMetrics(namespace, mon.Name)
+			}
+		}
+	}
+
+	for _, mon := range current.Mons {
+		inQuorum := 0.0
+		if mon.InQuorum {
+			inQuorum = 1
+		}
+		monInQuorumGauge.WithLabelValues(namespace, mon.Name).Set(inQuorum)
+		monRankGauge.WithLabelValues(namespace, mon.Name).Set(float64(mon.Rank))
+		monClockSkewGauge.WithLabelValues(namespace, mon.Name).Set(mon.ClockSkewSeconds)
+		monStoreSizeGauge.WithLabelValues(namespace, mon.Name).Set(float64(mon.StoreSizeBytes))
+	}
+}
+
+func deleteMonHealthMetrics(namespace, monName string) {
+	monInQuorumGauge.DeleteLabelValues(namespace, monName)
+	monRankGauge.DeleteLabelValues(namespace, monName)
+	monClockSkewGauge.DeleteLabelValues(namespace, monName)
+	monStoreSizeGauge.DeleteLabelValues(namespace, monName)
+}
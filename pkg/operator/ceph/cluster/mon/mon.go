@@ -116,11 +116,19 @@ type Cluster struct {
 	maxMonID           int
 	waitForStart       bool
 	monTimeoutList     map[string]time.Time
+	monOutTimeout      time.Duration
 	mapping            *Mapping
 	ownerInfo          *k8sutil.OwnerInfo
 	csiConfigMutex     *sync.Mutex
 	isUpgrade          bool
 	arbiterMon         string
+	// whether connected clients do not support the enforced connection settings
+	clientsIncompatible bool
+	recorder            *k8sutil.EventReporter
+	// whether the loss of the mon quorum was notified
+	quorumLost bool
+	// the mon count scaled with the number of failure domains if mon.autoScale is enabled
+	autoScaledCount int
 }
 
 // monConfig for a single monitor
@@ -170,6 +178,7 @@ func New(context *clusterd.Context, namespace string, spec cephv1.ClusterSpec, o
 		maxMonID:       -1,
 		waitForStart:   true,
 		monTimeoutList: map[string]time.Time{},
+		monOutTimeout:  MonOutTimeout,
 		mapping: &Mapping{
 			Schedule: map[string]*MonScheduleInfo{},
 		},
@@ -210,6 +219,7 @@ func (c *Cluster) Start(clusterInfo *cephclient.ClusterInfo, rookVersion string,
 		return nil, errors.Wrap(err, "failed to initialize ceph cluster info")
 	}
 
+	c.autoScaleMonCount()
 	logger.Infof("targeting the mon count %d", c.spec.Mon.Count)
 
 	// create the mons for a new cluster or ensure mons are running in an existing cluster
@@ -300,6 +310,11 @@ func (c *Cluster) startMons(targetCount int) error {
 		return errors.Wrap(err, "failed to reconcile mon PDB")
 	}
 
+	// publish the mons for the clients discovering them with DNS SRV records
+	if err := c.reconcileMonDNSDiscovery(); err != nil {
+		return errors.Wrap(err, "failed to reconcile mon dns discovery")
+	}
+
 	// Check if there are orphaned mon resources that should be cleaned up at the end of a reconcile.
 	// There may be orphaned resources if a mon failover was aborted.
 	c.removeOrphanMonResources()
@@ -545,10 +560,16 @@ func (c *Cluster) clusterInfoToMonConfig(excludedMon string) []*monConfig {
 func (c *Cluster) newMonConfig(monID int, zone string) *monConfig {
 	daemonName := k8sutil.IndexToName(monID)
 
+	port := DefaultMsgr1Port
+	if c.requireMsgr2() {
+		// the mon only listens on the msgr2 port
+		port = DefaultMsgr2Port
+	}
+
 	return &monConfig{
 		ResourceName: resourceName(daemonName),
 		DaemonName:   daemonName,
-		Port:         DefaultMsgr1Port,
+		Port:         port,
 		Zone:         zone,
 		DataPathMap: config.NewStatefulDaemonDataPathMap(
 			c.spec.DataDirHostPath, dataDirRelativeHostPath(daemonName), config.MonType, daemonName, c.Namespace),
@@ -749,6 +770,15 @@ func (c *Cluster) initMonIPs(mons []*monConfig) error {
 		if c.ClusterInfo.Context.Err() != nil {
 			return c.ClusterInfo.Context.Err()
 		}
+		onPreviousNetwork, err := c.monOnPreviousNetwork(m.DaemonName)
+		if err != nil {
+			return err
+		}
+		if onPreviousNetwork {
+			// the mon keeps its endpoint until it is failed over to the new network
+			logger.Infof("keeping the endpoint of mon %q until it is migrated to the %s network", m.DaemonName, c.networkName())
+			continue
+		}
 		if c.spec.Network.IsHost() {
 			logger.Infof("setting mon endpoints for hostnetwork mode")
 			node, ok := c.mapping.Schedule[m.DaemonName]
@@ -905,6 +935,23 @@ func (c *Cluster) monVolumeClaimTemplate(mon *monConfig) *v1.PersistentVolumeCla
 	return c.spec.Mon.VolumeClaimTemplate
 }
 
+// monsUseVolumeClaimTemplates returns whether any mon may be backed by a PVC, either from the
+// default template or from the template of a stretch cluster zone
+func (c *Cluster) monsUseVolumeClaimTemplates() bool {
+	if c.spec.Mon.VolumeClaimTemplate != nil {
+		return true
+	}
+	if !c.spec.IsStretchCluster() {
+		return false
+	}
+	for _, zone := range c.spec.Mon.StretchCluster.Zones {
+		if zone.VolumeClaimTemplate != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Cluster) startDeployments(mons []*monConfig, requireAllInQuorum bool) error {
 	if len(mons) == 0 {
 		return errors.New("cannot start 0 mons")
@@ -1176,7 +1223,9 @@ func (c *Cluster) updateMon(m *monConfig, d *apps.Deployment) error {
 //    monitor. see scheduleMonitor() comment for more details.
 //
 // Note: an important assumption is that HostNetworking setting does not
-// change once a cluster is created.
+// change for an existing monitor. if the setting changes, the deployment of
+// the monitor is left as is until the monitor is failed over to the new
+// network by the health check.
 //
 // 2) if *not* HostNetworking -> stable IP from service; may avoid node selector
 //      a) when creating a new deployment
@@ -1198,20 +1247,25 @@ func (c *Cluster) startMon(m *monConfig, schedule *MonScheduleInfo) error {
 		return err
 	}
 
-	// Set the deployment hash as an annotation
-	err = patch.DefaultAnnotator.SetLastAppliedAnnotation(d)
-	if err != nil {
-		return errors.Wrapf(err, "failed to set annotation for deployment %q", d.Name)
-	}
-
 	existingDeployment, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, d.Name, metav1.GetOptions{})
 	if err == nil {
+		if c.onPreviousNetwork(existingDeployment) {
+			logger.Infof("skipping the update of mon %q until it is migrated to the %s network", m.DaemonName, c.networkName())
+			return nil
+		}
 		deploymentExists = true
 		pvcExists = controller.DaemonVolumesContainsPVC(existingDeployment.Spec.Template.Spec.Volumes)
+		keepRestartedAt(d, existingDeployment)
 	} else if !kerrors.IsNotFound(err) {
 		return errors.Wrapf(err, "failed to get mon deployment %s", d.Name)
 	}
 
+	// Set the deployment hash as an annotation
+	err = patch.DefaultAnnotator.SetLastAppliedAnnotation(d)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set annotation for deployment %q", d.Name)
+	}
+
 	// persistent storage is not altered after the deployment is created. this
 	// means we need to be careful when updating the deployment to avoid new
 	// changes to the crd to change an existing pod's persistent storage. the
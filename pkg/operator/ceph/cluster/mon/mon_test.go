@@ -567,6 +567,14 @@ func TestStretchMonVolumeClaimTemplate(t *testing.T) {
 			StretchCluster:      &cephv1.StretchClusterSpec{Zones: []cephv1.StretchClusterZoneSpec{{Name: "z1", VolumeClaimTemplate: zoneTemplate}, {Name: "z2"}, {Name: "z3"}}}}}},
 			args{&monConfig{Zone: "z1"}},
 			zoneTemplate},
+		{"zone template without default", fields{cephv1.ClusterSpec{Mon: cephv1.MonSpec{
+			StretchCluster: &cephv1.StretchClusterSpec{Zones: []cephv1.StretchClusterZoneSpec{{Name: "z1", VolumeClaimTemplate: zoneTemplate}, {Name: "z2"}, {Name: "z3"}}}}}},
+			args{&monConfig{Zone: "z1"}},
+			zoneTemplate},
+		{"other zone without default", fields{cephv1.ClusterSpec{Mon: cephv1.MonSpec{
+			StretchCluster: &cephv1.StretchClusterSpec{Zones: []cephv1.StretchClusterZoneSpec{{Name: "z1", VolumeClaimTemplate: zoneTemplate}, {Name: "z2"}, {Name: "z3"}}}}}},
+			args{&monConfig{Zone: "z2"}},
+			nil},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -576,8 +584,14 @@ func TestStretchMonVolumeClaimTemplate(t *testing.T) {
 			if got := c.monVolumeClaimTemplate(tt.args.mon); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Cluster.monVolumeClaimTemplate() = %v, want %v", got, tt.want)
 			}
+			// any mon with a template is backed by a pvc
+			if tt.want != nil {
+				assert.True(t, c.monsUseVolumeClaimTemplates())
+			}
 		})
 	}
+	c := &Cluster{}
+	assert.False(t, c.monsUseVolumeClaimTemplates())
 }
 
 func TestArbiterPlacement(t *testing.T) {
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	apps "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The address of a mon is part of its identity in the mon map, so a mon cannot be moved between the
+// host network and the pod network. When spec.network.hostNetwork changes on an existing cluster,
+// the mons still running on the previous network keep their deployment and endpoint untouched, and
+// the health check fails them over one at a time so that their replacements start on the new network.
+
+// onPreviousNetwork returns whether the mon deployment was created with a host networking setting
+// different from the cluster spec
+func (c *Cluster) onPreviousNetwork(d *apps.Deployment) bool {
+	return d.Spec.Template.Spec.HostNetwork != c.spec.Network.IsHost()
+}
+
+// monOnPreviousNetwork returns whether the mon still runs on the previous network and is waiting for
+// its migration. A mon without a deployment is not migrated since it will start on the new network.
+func (c *Cluster) monOnPreviousNetwork(name string) (bool, error) {
+	d, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, resourceName(name), metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get mon deployment %q", resourceName(name))
+	}
+	return c.onPreviousNetwork(d), nil
+}
+
+// migrateMonNetwork fails over a single mon still running on the previous network. It is only called
+// while all the mons are in quorum, so the next mon is migrated after its predecessor joined the quorum.
+// Each failover saves the mon endpoints and the csi config with the address of the new mon. If the
+// failovers require a confirmation, the mon is only failed over once its failover is approved. Returns
+// whether a mon was failed over or is waiting for the approval of its failover.
+func (c *Cluster) migrateMonNetwork() (bool, error) {
+	names := []string{}
+	for name := range c.ClusterInfo.Monitors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		onPreviousNetwork, err := c.monOnPreviousNetwork(name)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to check the network of the mons")
+		}
+		if !onPreviousNetwork {
+			continue
+		}
+		// only migrate one mon per health check
+		if c.spec.RequireMonFailoverConfirmation() {
+			approved, err := c.isMonFailoverApproved(name, fmt.Sprintf("waiting for its migration to the %s network", c.networkName()))
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to check if the failover of mon %q is approved", name)
+			}
+			if !approved {
+				return true, nil
+			}
+		}
+		logger.Infof("migrating mon %q to the %s network. failing over the mon", name, c.networkName())
+		if err := c.failoverMon(name, fmt.Sprintf("migrating to the %s network", c.networkName())); err != nil {
+			return true, err
+		}
+		if c.spec.RequireMonFailoverConfirmation() {
+			if err := c.clearMonFailoverProposal(); err != nil {
+				logger.Errorf("failed to clear the failover proposal of mon %q. %v", name, err)
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// MonsOnPreviousNetwork returns the sorted names of the mons still running on the previous network
+func (c *Cluster) MonsOnPreviousNetwork() ([]string, error) {
+	names := []string{}
+	for name := range c.ClusterInfo.Monitors {
+		onPreviousNetwork, err := c.monOnPreviousNetwork(name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check the network of the mons")
+		}
+		if onPreviousNetwork {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (c *Cluster) networkName() string {
+	if c.spec.Network.IsHost() {
+		return "host"
+	}
+	return "pod"
+}
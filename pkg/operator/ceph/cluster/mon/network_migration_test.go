@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMigrateMonNetwork(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	configDir, _ := ioutil.TempDir("", "")
+	defer os.RemoveAll(configDir)
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return "{\"key\":\"mysecurekey\"}", nil
+		},
+	}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	context := &clusterd.Context{Client: cl, Clientset: clientset, ConfigDir: configDir, Executor: executor}
+	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	c := New(context, "ns", cephv1.ClusterSpec{}, ownerInfo, &sync.Mutex{})
+	setCommonMonProperties(c, 2, cephv1.MonSpec{Count: 2, AllowMultiplePerNode: true}, "myversion")
+	c.ClusterInfo.Namespace = "ns"
+	c.ClusterInfo.SetName("rook")
+	c.maxMonID = 1
+	c.waitForStart = false
+	defer func(f func(c *Cluster, d *apps.Deployment) (SchedulingResult, error)) { waitForMonitorScheduling = f }(waitForMonitorScheduling)
+	waitForMonitorScheduling = func(c *Cluster, d *apps.Deployment) (SchedulingResult, error) {
+		node, _ := clientset.CoreV1().Nodes().Get(ctx, "node0", metav1.GetOptions{})
+		return SchedulingResult{Node: node}, nil
+	}
+
+	// the mons run on the pod network
+	for _, name := range []string{"a", "b"} {
+		d, err := c.makeDeployment(&monConfig{ResourceName: resourceName(name), DaemonName: name, DataPathMap: &config.DataPathMap{}}, false)
+		require.NoError(t, err)
+		_, err = clientset.AppsV1().Deployments(c.Namespace).Create(ctx, d, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	// nothing to migrate while the network is unchanged
+	onPreviousNetwork, err := c.monOnPreviousNetwork("a")
+	assert.NoError(t, err)
+	assert.False(t, onPreviousNetwork)
+	mons, err := c.MonsOnPreviousNetwork()
+	assert.NoError(t, err)
+	assert.Empty(t, mons)
+	migrated, err := c.migrateMonNetwork()
+	assert.NoError(t, err)
+	assert.False(t, migrated)
+
+	// the mons are migrated one at a time after switching to the host network
+	c.spec.Network.HostNetwork = true
+	onPreviousNetwork, err = c.monOnPreviousNetwork("a")
+	assert.NoError(t, err)
+	assert.True(t, onPreviousNetwork)
+	// a mon without deployment starts on the new network
+	onPreviousNetwork, err = c.monOnPreviousNetwork("z")
+	assert.NoError(t, err)
+	assert.False(t, onPreviousNetwork)
+	mons, err = c.MonsOnPreviousNetwork()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, mons)
+
+	// the migration waits for the approval of the failover when a confirmation is required
+	c.spec.Mon.Failover = &cephv1.MonFailoverSpec{RequireConfirmation: true}
+	migrated, err = c.migrateMonNetwork()
+	assert.NoError(t, err)
+	assert.True(t, migrated)
+	_, err = clientset.AppsV1().Deployments(c.Namespace).Get(ctx, resourceName("a"), metav1.GetOptions{})
+	assert.NoError(t, err)
+	cluster := &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, c.ClusterInfo.NamespacedName(), cluster))
+	proposed := cephv1.FindStatusCondition(cluster.Status.Conditions, cephv1.ConditionMonFailoverProposed)
+	require.NotNil(t, proposed)
+	assert.Contains(t, proposed.Message, "waiting for its migration to the host network")
+	cluster.Annotations = map[string]string{MonFailoverApprovalAnnotation: "a"}
+	require.NoError(t, cl.Update(ctx, cluster))
+
+	migrated, err = c.migrateMonNetwork()
+	assert.NoError(t, err)
+	assert.True(t, migrated)
+	_, err = clientset.AppsV1().Deployments(c.Namespace).Get(ctx, resourceName("a"), metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	d, err := clientset.AppsV1().Deployments(c.Namespace).Get(ctx, resourceName("c"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, d.Spec.Template.Spec.HostNetwork)
+	// the endpoint of the new mon is the node address
+	assert.Equal(t, "0.0.0.0:6789", c.ClusterInfo.Monitors["c"].Endpoint)
+	_, ok := c.ClusterInfo.Monitors["a"]
+	assert.False(t, ok)
+	cm, err := clientset.CoreV1().ConfigMaps(c.Namespace).Get(ctx, EndpointConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, cm.Data[EndpointDataKey], "c=0.0.0.0:6789")
+	assert.NotContains(t, cm.Data[EndpointDataKey], "a=")
+	// the approval is removed after the failover, and the failover is recorded in the corrective actions
+	cluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, c.ClusterInfo.NamespacedName(), cluster))
+	_, ok = cluster.Annotations[MonFailoverApprovalAnnotation]
+	assert.False(t, ok)
+	c.spec.Mon.Failover = nil
+	require.Len(t, cluster.Status.CorrectiveActions, 1)
+	assert.Equal(t, cephv1.CorrectiveActionMonFailover, cluster.Status.CorrectiveActions[0].Type)
+	assert.Equal(t, "mon.a", cluster.Status.CorrectiveActions[0].Target)
+	assert.Equal(t, "migrating to the host network", cluster.Status.CorrectiveActions[0].Reason)
+	mons, err = c.MonsOnPreviousNetwork()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, mons)
+
+	migrated, err = c.migrateMonNetwork()
+	assert.NoError(t, err)
+	assert.True(t, migrated)
+	migrated, err = c.migrateMonNetwork()
+	assert.NoError(t, err)
+	assert.False(t, migrated)
+	assert.Equal(t, 3, c.maxMonID)
+	mons, err = c.MonsOnPreviousNetwork()
+	assert.NoError(t, err)
+	assert.Empty(t, mons)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	apps "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RestartAnnotation is the annotation of the CephCluster requesting a rolling restart of the
+	// daemons named in its value
+	RestartAnnotation = "ceph.rook.io/restart"
+	// RestartedAtAnnotation is the annotation of the mon pods with the time of their last requested restart
+	RestartedAtAnnotation = "ceph.rook.io/restartedAt"
+	// restartStartedAtAnnotation is the annotation of the CephCluster with the time the requested restart started,
+	// to skip the mons already restarted when the restart is resumed after a failure
+	restartStartedAtAnnotation = "ceph.rook.io/restart-started-at"
+
+	restartMonsValue = "mon"
+)
+
+// restartMonsIfRequested restarts the mons one at a time if requested with the annotation of the
+// CephCluster, waiting for all the mons to be in quorum after each restart. The annotation is
+// removed once all the mons are restarted. Returns whether the mons were restarted.
+func (c *Cluster) restartMonsIfRequested() (bool, error) {
+	cephCluster := &cephv1.CephCluster{}
+	err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get ceph cluster %q", c.ClusterInfo.NamespacedName().String())
+	}
+	if cephCluster.Annotations[RestartAnnotation] != restartMonsValue {
+		return false, nil
+	}
+
+	names := []string{}
+	for name := range c.ClusterInfo.Monitors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// the start of the restart is recorded so that a restart failing partway resumes with the mons not restarted yet
+	restartedAt, ok := cephCluster.Annotations[restartStartedAtAnnotation]
+	if ok {
+		logger.Infof("resuming the restart of the mons started at %s. restarting mons %v one at a time", restartedAt, names)
+	} else {
+		restartedAt = time.Now().UTC().Format(time.RFC3339)
+		cephCluster.Annotations[restartStartedAtAnnotation] = restartedAt
+		if err := c.context.Client.Update(c.ClusterInfo.Context, cephCluster); err != nil {
+			return false, errors.Wrap(err, "failed to record the start of the mon restart")
+		}
+		logger.Infof("restart of the mons requested. restarting mons %v one at a time", names)
+	}
+
+	mons := c.clusterInfoToMonConfig("")
+	for _, name := range names {
+		restarted, err := c.restartMon(name, restartedAt)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to restart mon %q", name)
+		}
+		if !restarted {
+			logger.Infof("mon %q was already restarted", name)
+			continue
+		}
+		// the next mon is only restarted when all the mons are back in quorum
+		if err := c.waitForMonsToJoin(mons, true); err != nil {
+			return false, errors.Wrapf(err, "failed to wait for the quorum after restarting mon %q", name)
+		}
+		logger.Infof("restarted mon %q", name)
+	}
+
+	delete(cephCluster.Annotations, RestartAnnotation)
+	delete(cephCluster.Annotations, restartStartedAtAnnotation)
+	if err := c.context.Client.Update(c.ClusterInfo.Context, cephCluster); err != nil {
+		return true, errors.Wrap(err, "failed to remove the mon restart annotation")
+	}
+	logger.Infof("restarted all the mons")
+	return true, nil
+}
+
+// restartMon rolls the pod of the mon by updating the restart time in its pod template. Returns false if the mon
+// was already restarted at this time.
+func (c *Cluster) restartMon(name, restartedAt string) (bool, error) {
+	d, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, resourceName(name), metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get mon deployment %q", resourceName(name))
+	}
+	if d.Spec.Template.Annotations == nil {
+		d.Spec.Template.Annotations = map[string]string{}
+	}
+	if d.Spec.Template.Annotations[RestartedAtAnnotation] == restartedAt {
+		return false, nil
+	}
+	d.Spec.Template.Annotations[RestartedAtAnnotation] = restartedAt
+	logger.Infof("restarting mon %q", name)
+	return true, updateDeploymentAndWait(c.context, c.ClusterInfo, d, config.MonType, name, c.spec.SkipUpgradeChecks, false)
+}
+
+// keepRestartedAt keeps the time of the last restart of the mon in the pod template of its deployment,
+// so that the update of the deployment does not restart the mon again
+func keepRestartedAt(d, existing *apps.Deployment) {
+	restartedAt, ok := existing.Spec.Template.Annotations[RestartedAtAnnotation]
+	if !ok {
+		return
+	}
+	if d.Spec.Template.Annotations == nil {
+		d.Spec.Template.Annotations = map[string]string{}
+	}
+	d.Spec.Template.Annotations[RestartedAtAnnotation] = restartedAt
+}
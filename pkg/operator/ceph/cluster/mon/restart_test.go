@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	testopk8s "github.com/rook/rook/pkg/operator/k8sutil/test"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestartMonsIfRequested(t *testing.T) {
+	var deploymentsUpdated *[]*apps.Deployment
+	updateDeploymentAndWait, deploymentsUpdated = testopk8s.UpdateDeploymentAndWaitStub()
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := test.New(t, 1)
+	context := &clusterd.Context{Client: cl, Clientset: clientset}
+	c := New(context, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef(), &sync.Mutex{})
+	setCommonMonProperties(c, 2, cephv1.MonSpec{Count: 2}, "myversion")
+	c.ClusterInfo.Namespace = "ns"
+	c.ClusterInfo.SetName("rook")
+	c.waitForStart = false
+	for _, name := range []string{"a", "b"} {
+		d, err := c.makeDeployment(&monConfig{ResourceName: resourceName(name), DaemonName: name, DataPathMap: &config.DataPathMap{}}, false)
+		require.NoError(t, err)
+		_, err = clientset.AppsV1().Deployments(c.Namespace).Create(c.ClusterInfo.Context, d, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	// no restart requested
+	restarted, err := c.restartMonsIfRequested()
+	assert.NoError(t, err)
+	assert.False(t, restarted)
+	assert.Empty(t, *deploymentsUpdated)
+
+	// the mons are restarted one at a time
+	cluster := &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cluster))
+	cluster.Annotations = map[string]string{RestartAnnotation: "mon"}
+	require.NoError(t, cl.Update(c.ClusterInfo.Context, cluster))
+	restarted, err = c.restartMonsIfRequested()
+	assert.NoError(t, err)
+	assert.True(t, restarted)
+	assert.Equal(t, []string{"rook-ceph-mon-a", "rook-ceph-mon-b"}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
+	restartedAt := (*deploymentsUpdated)[0].Spec.Template.Annotations[RestartedAtAnnotation]
+	assert.NotEmpty(t, restartedAt)
+
+	// the annotation is removed after the restart
+	cluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cluster))
+	_, ok := cluster.Annotations[RestartAnnotation]
+	assert.False(t, ok)
+
+	// the restart time is kept when the deployment is updated
+	existing := (*deploymentsUpdated)[0]
+	d, err := c.makeDeployment(&monConfig{ResourceName: resourceName("a"), DaemonName: "a", DataPathMap: &config.DataPathMap{}}, false)
+	require.NoError(t, err)
+	keepRestartedAt(d, existing)
+	assert.Equal(t, restartedAt, d.Spec.Template.Annotations[RestartedAtAnnotation])
+
+	// a restart failing partway resumes with the mons not restarted yet
+	failMonB := true
+	updateDeploymentAndWait = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, d *apps.Deployment, daemonType, daemonName string, skipUpgradeChecks, continueUpgradeAfterChecksEvenIfNotHealthy bool) error {
+		if daemonName == "b" && failMonB {
+			return errors.New("mock failure")
+		}
+		*deploymentsUpdated = append(*deploymentsUpdated, d)
+		_, err := clientset.AppsV1().Deployments(d.Namespace).Update(clusterInfo.Context, d, metav1.UpdateOptions{})
+		return err
+	}
+	testopk8s.ClearDeploymentsUpdated(deploymentsUpdated)
+	cluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cluster))
+	cluster.Annotations = map[string]string{RestartAnnotation: "mon"}
+	require.NoError(t, cl.Update(c.ClusterInfo.Context, cluster))
+	_, err = c.restartMonsIfRequested()
+	assert.Error(t, err)
+	assert.Equal(t, []string{"rook-ceph-mon-a"}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
+	cluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cluster))
+	startedAt := cluster.Annotations[restartStartedAtAnnotation]
+	assert.NotEmpty(t, startedAt)
+
+	failMonB = false
+	testopk8s.ClearDeploymentsUpdated(deploymentsUpdated)
+	restarted, err = c.restartMonsIfRequested()
+	assert.NoError(t, err)
+	assert.True(t, restarted)
+	assert.Equal(t, []string{"rook-ceph-mon-b"}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
+	assert.Equal(t, startedAt, (*deploymentsUpdated)[0].Spec.Template.Annotations[RestartedAtAnnotation])
+	cluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cluster))
+	assert.NotContains(t, cluster.Annotations, RestartAnnotation)
+	assert.NotContains(t, cluster.Annotations, restartStartedAtAnnotation)
+}
@@ -20,6 +20,7 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -53,9 +54,16 @@ func (c *Cluster) createService(mon *monConfig) (string, error) {
 		return "", errors.Wrapf(err, "failed to set owner reference to mon service %q", svcDef.Name)
 	}
 
+	if mon.Port == DefaultMsgr2Port {
+		// The mon only accepts the msgr2 protocol
+		svcDef.Spec.Ports = []v1.ServicePort{}
+	}
+
 	// If deploying Nautilus or newer we need a new port for the monitor service
 	addServicePort(svcDef, "tcp-msgr2", DefaultMsgr2Port)
 
+	setServiceIPFamilies(svcDef, c.spec.Network.IPFamilies)
+
 	// Set the ClusterIP if the service does not exist and we expect a certain cluster IP
 	// For example, in disaster recovery the service might have been deleted accidentally, but we have the
 	// expected endpoint from the mon configmap.
@@ -76,11 +84,52 @@ func (c *Cluster) createService(mon *monConfig) (string, error) {
 		logger.Errorf("service ip not found for mon %q. if this is not a unit test, this is an error", mon.ResourceName)
 		return "", nil
 	}
+	if err := validateServiceIPFamilies(s, c.spec.Network.IPFamilies); err != nil {
+		return "", errors.Wrapf(err, "invalid service for mon %s", mon.DaemonName)
+	}
 
 	// mon endpoint are not actually like, they remain with the mgrs1 format
 	// however it's interesting to show that monitors can be addressed via 2 different ports
 	// in the end the service has msgr1 and msgr2 ports configured so it's not entirely wrong
-	logger.Infof("mon %q endpoint is [v2:%s:%s,v1:%s:%d]", mon.DaemonName, s.Spec.ClusterIP, strconv.Itoa(int(DefaultMsgr2Port)), s.Spec.ClusterIP, mon.Port)
+	if mon.Port == DefaultMsgr2Port {
+		logger.Infof("mon %q endpoint is [v2:%s:%s]", mon.DaemonName, s.Spec.ClusterIP, strconv.Itoa(int(DefaultMsgr2Port)))
+	} else {
+		logger.Infof("mon %q endpoint is [v2:%s:%s,v1:%s:%d]", mon.DaemonName, s.Spec.ClusterIP, strconv.Itoa(int(DefaultMsgr2Port)), s.Spec.ClusterIP, mon.Port)
+	}
 
 	return s.Spec.ClusterIP, nil
 }
+
+// setServiceIPFamilies requests the ip families of the network spec for the service. A single family requires a
+// single stack service, and two families a dual-stack service. The service keeps the default family of the
+// Kubernetes cluster if no family is set.
+func setServiceIPFamilies(svc *v1.Service, families []cephv1.IPFamilyType) {
+	if len(families) == 0 {
+		return
+	}
+	policy := v1.IPFamilyPolicySingleStack
+	if len(families) == 2 {
+		policy = v1.IPFamilyPolicyRequireDualStack
+	}
+	svc.Spec.IPFamilyPolicy = &policy
+	svc.Spec.IPFamilies = []v1.IPFamily{}
+	for _, family := range families {
+		svc.Spec.IPFamilies = append(svc.Spec.IPFamilies, v1.IPFamily(family))
+	}
+}
+
+// validateServiceIPFamilies checks the service was given the requested ip families, since an existing service
+// cannot change its primary family and a cluster without dual-stack support may not honor the policy
+func validateServiceIPFamilies(svc *v1.Service, families []cephv1.IPFamilyType) error {
+	if len(families) == 0 || len(svc.Spec.IPFamilies) == 0 {
+		// the families are not reported by the clusters without dual-stack support
+		return nil
+	}
+	if string(svc.Spec.IPFamilies[0]) != string(families[0]) {
+		return errors.Errorf("service %q has the primary ip family %q instead of %q. the primary family of an existing service cannot be changed", svc.Name, svc.Spec.IPFamilies[0], families[0])
+	}
+	if len(svc.Spec.IPFamilies) != len(families) {
+		return errors.Errorf("service %q has the ip families %v instead of %v. check the ipFamilyPolicy is supported by the cluster", svc.Name, svc.Spec.IPFamilies, families)
+	}
+	return nil
+}
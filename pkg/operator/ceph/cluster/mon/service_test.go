@@ -27,6 +27,7 @@ import (
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -56,3 +57,50 @@ func TestCreateService(t *testing.T) {
 	// the clusterIP will now be set to the expected value
 	assert.Equal(t, m.PublicIP, clusterIP)
 }
+
+func TestCreateServiceMsgr2Only(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	c := New(&clusterd.Context{Clientset: clientset}, "ns", cephv1.ClusterSpec{}, &k8sutil.OwnerInfo{}, &sync.Mutex{})
+	c.ClusterInfo = client.AdminClusterInfo("rook-ceph")
+	m := &monConfig{ResourceName: "rook-ceph-mon-a", DaemonName: "a", Port: DefaultMsgr2Port}
+	_, err := c.createService(m)
+	assert.NoError(t, err)
+
+	svc, err := clientset.CoreV1().Services(c.Namespace).Get(ctx, m.ResourceName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(svc.Spec.Ports))
+	assert.Equal(t, "tcp-msgr2", svc.Spec.Ports[0].Name)
+	assert.Equal(t, DefaultMsgr2Port, svc.Spec.Ports[0].Port)
+}
+
+func TestCreateServiceIPFamilies(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	spec := cephv1.ClusterSpec{Network: cephv1.NetworkSpec{IPFamilies: []cephv1.IPFamilyType{cephv1.IPv6, cephv1.IPv4}}}
+	c := New(&clusterd.Context{Clientset: clientset}, "ns", spec, &k8sutil.OwnerInfo{}, &sync.Mutex{})
+	c.ClusterInfo = client.AdminClusterInfo("rook-ceph")
+	m := &monConfig{ResourceName: "rook-ceph-mon-a", DaemonName: "a"}
+	_, err := c.createService(m)
+	assert.NoError(t, err)
+
+	svc, err := clientset.CoreV1().Services(c.Namespace).Get(ctx, m.ResourceName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, v1.IPFamilyPolicyRequireDualStack, *svc.Spec.IPFamilyPolicy)
+	assert.Equal(t, []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}, svc.Spec.IPFamilies)
+
+	// the primary family of the service is not the requested family
+	svc.Spec.IPFamilies = []v1.IPFamily{v1.IPv4Protocol}
+	assert.Error(t, validateServiceIPFamilies(svc, spec.Network.IPFamilies))
+	// the cluster does not support dual-stack
+	svc.Spec.IPFamilies = []v1.IPFamily{v1.IPv6Protocol}
+	assert.Error(t, validateServiceIPFamilies(svc, spec.Network.IPFamilies))
+
+	c.spec.Network.IPFamilies = []cephv1.IPFamilyType{cephv1.IPv6}
+	_, err = c.createService(m)
+	assert.NoError(t, err)
+	svc, err = clientset.CoreV1().Services(c.Namespace).Get(ctx, m.ResourceName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, v1.IPFamilyPolicySingleStack, *svc.Spec.IPFamilyPolicy)
+	assert.Equal(t, []v1.IPFamily{v1.IPv6Protocol}, svc.Spec.IPFamilies)
+}
@@ -31,6 +31,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -217,6 +218,9 @@ func (c *Cluster) makeMonPod(monConfig *monConfig, canary bool) (*corev1.Pod, er
 			return nil, err
 		}
 	}
+	cephv1.GetDaemonDNS(c.spec.DNS, cephv1.KeyMon).ApplyToPodSpec(&pod.Spec)
+	cephv1.GetDaemonContainerOverrides(c.spec.ContainerOverrides, cephv1.KeyMon).ApplyToPodSpec(&pod.Spec)
+	controller.ApplyReadOnlyRootFilesystem(c.spec.Security, &pod.Spec)
 
 	if c.spec.IsStretchCluster() {
 		nodeAffinity, err := k8sutil.GenerateNodeAffinity(fmt.Sprintf("%s=%s", StretchFailureDomainLabel(c.spec), monConfig.Zone))
@@ -273,7 +277,7 @@ func (c *Cluster) makeMonDaemonContainer(monConfig *monConfig) corev1.Container
 
 	// Handle the non-default port for host networking. If host networking is not being used,
 	// the service created elsewhere will handle the non-default port redirection to the default port inside the container.
-	if c.spec.Network.IsHost() && monConfig.Port != DefaultMsgr1Port {
+	if c.spec.Network.IsHost() && monConfig.Port != DefaultMsgr1Port && monConfig.Port != DefaultMsgr2Port {
 		logger.Warningf("Starting mon %s with host networking on a non-default port %d. The mon must be failed over before enabling msgr2.",
 			monConfig.DaemonName, monConfig.Port)
 		publicAddr = fmt.Sprintf("%s:%d", publicAddr, monConfig.Port)
@@ -337,6 +341,12 @@ func (c *Cluster) makeMonDaemonContainer(monConfig *monConfig) corev1.Container
 			config.NewFlag("public-bind-addr", controller.ContainerEnvVarReference(podIPEnvVar)))
 	}
 
+	if monConfig.Port == DefaultMsgr2Port {
+		// The mon only accepts the msgr2 protocol
+		container.Ports[0].Name = "tcp-msgr2"
+		container.Args = append(container.Args, config.NewFlag("ms-bind-msgr1", "false"))
+	}
+
 	// Add messenger 2 port
 	addContainerPort(container, "tcp-msgr2", 3300)
 
@@ -345,6 +355,13 @@ func (c *Cluster) makeMonDaemonContainer(monConfig *monConfig) corev1.Container
 
 // UpdateCephDeploymentAndWait verifies a deployment can be stopped or continued
 func UpdateCephDeploymentAndWait(context *clusterd.Context, clusterInfo *client.ClusterInfo, deployment *apps.Deployment, daemonType, daemonName string, skipUpgradeChecks, continueUpgradeAfterChecksEvenIfNotHealthy bool) error {
+	hold, err := holdCanaryUpgrade(context, clusterInfo, deployment)
+	if err != nil {
+		return err
+	}
+	if hold {
+		return nil
+	}
 
 	callback := func(action string) error {
 		// At this point, we are in an upgrade
@@ -380,6 +397,32 @@ func UpdateCephDeploymentAndWait(context *clusterd.Context, clusterInfo *client.
 		return nil
 	}
 
-	err := k8sutil.UpdateDeploymentAndWait(context, deployment, clusterInfo.Namespace, callback)
+	err = k8sutil.UpdateDeploymentAndWait(context, deployment, clusterInfo.Namespace, callback)
 	return err
 }
+
+// holdCanaryUpgrade returns whether the update of the deployment must wait for the approval of the canary
+// upgrade. The peers of the deployment are the deployments of the same app, such as all the mons.
+func holdCanaryUpgrade(context *clusterd.Context, clusterInfo *client.ClusterInfo, deployment *apps.Deployment) (bool, error) {
+	canary, err := controller.GetCanaryUpgrade(clusterInfo.Context, context, clusterInfo.Namespace)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get the canary upgrade settings")
+	}
+	if !canary.Enabled() {
+		return false, nil
+	}
+
+	current, err := context.Clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(clusterInfo.Context, deployment.Name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get deployment %q", deployment.Name)
+	}
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, deployment.Labels[k8sutil.AppAttr])}
+	peers, err := context.Clientset.AppsV1().Deployments(clusterInfo.Namespace).List(clusterInfo.Context, listOpts)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list the peers of deployment %q", deployment.Name)
+	}
+	return canary.Hold(current, deployment, peers.Items), nil
+}
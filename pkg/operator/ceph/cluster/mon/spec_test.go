@@ -173,3 +173,20 @@ func TestRequiredDuringScheduling(t *testing.T) {
 	testRequiredDuringScheduling(t, true, true, true)
 	testRequiredDuringScheduling(t, false, true, false)
 }
+
+func TestMsgr2OnlyMonContainer(t *testing.T) {
+	c := New(&clusterd.Context{Clientset: testop.New(t, 1)}, "ns", cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef(), &sync.Mutex{})
+	setCommonMonProperties(c, 0, cephv1.MonSpec{Count: 3, AllowMultiplePerNode: true}, "rook/rook:myversion")
+	monConfig := testGenMonConfig("a")
+
+	container := c.makeMonDaemonContainer(monConfig)
+	assert.Equal(t, "tcp-msgr1", container.Ports[0].Name)
+	assert.NotContains(t, container.Args, "--ms-bind-msgr1=false")
+
+	monConfig.Port = DefaultMsgr2Port
+	container = c.makeMonDaemonContainer(monConfig)
+	assert.Equal(t, 1, len(container.Ports))
+	assert.Equal(t, "tcp-msgr2", container.Ports[0].Name)
+	assert.Equal(t, DefaultMsgr2Port, container.Ports[0].ContainerPort)
+	assert.Contains(t, container.Args, "--ms-bind-msgr1=false")
+}
@@ -22,12 +22,13 @@ import (
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 )
 
 var (
-	monitorDaemonList = []string{"mon", "osd", "status"}
+	monitorDaemonList = []string{"mon", "osd", "status", "mgr", "keyring", "keyrotation", "weightrampup", "osdmigration", "topology", "deviceinventory", "blocklist", "diskprediction"}
 )
 
 func (c *ClusterController) configureCephMonitoring(cluster *cluster, clusterInfo *cephclient.ClusterInfo) {
@@ -59,6 +60,11 @@ func (c *ClusterController) configureCephMonitoring(cluster *cluster, clusterInf
 			}
 		}
 	}
+
+	// The running osd monitor follows the changes of the removal and flapping settings of the OSDs
+	if cluster.osdChecker != nil {
+		cluster.osdChecker.Update(cluster.Spec.RemoveOSDsIfOutAndSafeToRemove, cluster.Spec.Storage.AutoRemoveOSD, cluster.Spec.HealthCheck.OSDFlapping)
+	}
 }
 
 func isMonitoringEnabled(daemon string, clusterSpec *cephv1.ClusterSpec) bool {
@@ -71,6 +77,34 @@ func isMonitoringEnabled(daemon string, clusterSpec *cephv1.ClusterSpec) bool {
 
 	case "status":
 		return !clusterSpec.HealthCheck.DaemonHealth.Status.Disabled
+
+	case "mgr":
+		return !clusterSpec.HealthCheck.DaemonHealth.Manager.Disabled
+
+	case "keyring":
+		return !clusterSpec.HealthCheck.DaemonHealth.Keyring.Disabled
+
+	case "keyrotation":
+		return clusterSpec.Security.KeyRotation.Enabled
+
+	case "weightrampup":
+		return clusterSpec.Storage.WeightRampUp != nil && clusterSpec.Storage.WeightRampUp.Enabled
+
+	case "osdmigration":
+		// the migrator keeps running when the migration is disabled to mark the drained osds in again
+		return clusterSpec.Storage.Migration != nil
+
+	case "topology":
+		return clusterSpec.Monitoring.Topology.Enabled
+
+	case "deviceinventory":
+		return clusterSpec.Storage.DeviceInventory != nil && clusterSpec.Storage.DeviceInventory.Enabled
+
+	case "blocklist":
+		return clusterSpec.HealthCheck.Blocklist != nil && clusterSpec.HealthCheck.Blocklist.Enabled
+
+	case "diskprediction":
+		return clusterSpec.HealthCheck.DiskPrediction != nil && clusterSpec.HealthCheck.DiskPrediction.Enabled
 	}
 
 	return false
@@ -84,15 +118,88 @@ func (c *ClusterController) startMonitoringCheck(cluster *cluster, clusterInfo *
 		go healthChecker.Check(cluster.monitoringRoutines[daemon].internalCtx)
 
 	case "osd":
-		if !cluster.Spec.External.Enable {
-			c.osdChecker = osd.NewOSDHealthMonitor(c.context, clusterInfo, cluster.Spec.RemoveOSDsIfOutAndSafeToRemove, cluster.Spec.HealthCheck)
+		if !cluster.Spec.External.Enable || cluster.Spec.IsContributor() {
+			cluster.osdChecker = osd.NewOSDHealthMonitor(c.context, clusterInfo, cluster.Spec.RemoveOSDsIfOutAndSafeToRemove, cluster.Spec.Storage.AutoRemoveOSD, cluster.Spec.HealthCheck)
+			if cluster.Spec.IsContributor() {
+				// the other OSDs of the external cluster are not managed by this cluster
+				cluster.osdChecker.OwnedOSDsOnly()
+			}
 			logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
-			go c.osdChecker.Start(cluster.monitoringRoutines[daemon].internalCtx)
+			go cluster.osdChecker.Start(cluster.monitoringRoutines[daemon].internalCtx)
 		}
 
 	case "status":
 		cephChecker := newCephStatusChecker(c.context, clusterInfo, cluster.Spec)
 		logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
 		go cephChecker.checkCephStatus(cluster.monitoringRoutines[daemon].internalCtx)
+
+	case "mgr":
+		if !cluster.Spec.External.Enable {
+			healthChecker := mgr.NewHealthChecker(mgr.New(c.context, clusterInfo, *cluster.Spec, c.rookImage), c.recorder)
+			logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+			go healthChecker.Check(cluster.monitoringRoutines[daemon].internalCtx)
+		}
+
+	case "keyring":
+		if !cluster.Spec.External.Enable {
+			keyringChecker := newKeyringChecker(c.context, clusterInfo, cluster.Spec, c.recorder)
+			logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+			go keyringChecker.checkKeyrings(cluster.monitoringRoutines[daemon].internalCtx)
+		}
+
+	case "keyrotation":
+		if !cluster.Spec.External.Enable {
+			keyRotator := osd.NewKeyRotator(c.context, clusterInfo)
+			logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+			go keyRotator.Start(cluster.monitoringRoutines[daemon].internalCtx)
+		}
+
+	case "weightrampup":
+		if !cluster.Spec.External.Enable {
+			weightRampUp := osd.NewWeightRampUp(c.context, clusterInfo)
+			logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+			go weightRampUp.Start(cluster.monitoringRoutines[daemon].internalCtx)
+		}
+
+	case "osdmigration":
+		if !cluster.Spec.External.Enable {
+			osdMigrator := osd.NewOSDMigrator(c.context, clusterInfo)
+			logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+			go osdMigrator.Start(cluster.monitoringRoutines[daemon].internalCtx)
+		}
+
+	case "topology":
+		topologyPublisher := newTopologyPublisher(c.context, clusterInfo, cluster.Spec)
+		logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+		go topologyPublisher.publishTopology(cluster.monitoringRoutines[daemon].internalCtx)
+
+	case "deviceinventory":
+		inventoryPublisher := newDeviceInventoryPublisher(c.context, clusterInfo, cluster.Spec)
+		logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+		go inventoryPublisher.publishInventory(cluster.monitoringRoutines[daemon].internalCtx)
+
+	case "blocklist":
+		blocklistChecker := newBlocklistChecker(c.context, clusterInfo, cluster.Spec)
+		logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+		go blocklistChecker.checkBlocklist(cluster.monitoringRoutines[daemon].internalCtx)
+
+	case "diskprediction":
+		if !cluster.Spec.External.Enable {
+			diskPredictionChecker := newDiskPredictionChecker(c.context, clusterInfo, cluster.Spec)
+			logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+			go diskPredictionChecker.checkDiskPrediction(cluster.monitoringRoutines[daemon].internalCtx)
+		}
+	}
+}
+
+// stopMonitoring closes the goroutines watching the health of the cluster (mons, osds, ceph status) and releases
+// the osd monitor, so that nothing of the deleted cluster keeps running in the operator
+func (c *cluster) stopMonitoring() {
+	for daemon, monitoring := range c.monitoringRoutines {
+		if monitoring.internalCtx.Err() == nil { // if the context hasn't been cancelled
+			monitoring.internalCancel()
+		}
+		delete(c.monitoringRoutines, daemon)
 	}
+	c.osdChecker = nil
 }
@@ -34,7 +34,25 @@ func TestIsMonitoringEnabled(t *testing.T) {
 		want bool
 	}{
 		{"isEnabled", args{"mon", &cephv1.ClusterSpec{}}, true},
-		{"isDisabled", args{"mon", &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Monitor: cephv1.HealthCheckSpec{Disabled: true}}}}}, false},
+		{"isDisabled", args{"mon", &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Monitor: cephv1.MonHealthCheckSpec{HealthCheckSpec: cephv1.HealthCheckSpec{Disabled: true}}}}}}, false},
+		{"isMgrEnabled", args{"mgr", &cephv1.ClusterSpec{}}, true},
+		{"isMgrDisabled", args{"mgr", &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Manager: cephv1.HealthCheckSpec{Disabled: true}}}}}, false},
+		{"isKeyringEnabled", args{"keyring", &cephv1.ClusterSpec{}}, true},
+		{"isKeyringDisabled", args{"keyring", &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Keyring: cephv1.HealthCheckSpec{Disabled: true}}}}}, false},
+		{"isKeyRotationDisabled", args{"keyrotation", &cephv1.ClusterSpec{}}, false},
+		{"isKeyRotationEnabled", args{"keyrotation", &cephv1.ClusterSpec{Security: cephv1.SecuritySpec{KeyRotation: cephv1.KeyRotationSpec{Enabled: true}}}}, true},
+		{"isWeightRampUpDisabled", args{"weightrampup", &cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{WeightRampUp: &cephv1.OSDWeightRampUpSpec{}}}}, false},
+		{"isWeightRampUpEnabled", args{"weightrampup", &cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{WeightRampUp: &cephv1.OSDWeightRampUpSpec{Enabled: true}}}}, true},
+		{"isDeviceInventoryDisabled", args{"deviceinventory", &cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{DeviceInventory: &cephv1.DeviceInventorySpec{}}}}, false},
+		{"isDeviceInventoryEnabled", args{"deviceinventory", &cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{DeviceInventory: &cephv1.DeviceInventorySpec{Enabled: true}}}}, true},
+		{"isBlocklistDisabled", args{"blocklist", &cephv1.ClusterSpec{}}, false},
+		{"isBlocklistEnabled", args{"blocklist", &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{Blocklist: &cephv1.BlocklistSpec{Enabled: true}}}}, true},
+		{"isDiskPredictionDisabled", args{"diskprediction", &cephv1.ClusterSpec{}}, false},
+		{"isDiskPredictionEnabled", args{"diskprediction", &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DiskPrediction: &cephv1.DiskPredictionSpec{Enabled: true}}}}, true},
+		{"isOSDMigrationDisabled", args{"osdmigration", &cephv1.ClusterSpec{}}, false},
+		{"isOSDMigrationConfigured", args{"osdmigration", &cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{Migration: &cephv1.OSDMigrationSpec{}}}}, true},
+		{"isTopologyDisabled", args{"topology", &cephv1.ClusterSpec{}}, false},
+		{"isTopologyEnabled", args{"topology", &cephv1.ClusterSpec{Monitoring: cephv1.MonitoringSpec{Topology: cephv1.TopologySnapshotSpec{Enabled: true}}}}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
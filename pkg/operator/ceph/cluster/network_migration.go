@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// When spec.network.hostNetwork changes, the mon health check fails over the mons still running on the
+// previous network one at a time. The other daemons are only updated after all the mons run on the new
+// network, so the orchestration stops after the mons and is requeued until the mons are migrated.
+
+var (
+	// errNetworkMigrationInProgress is returned by the orchestration while mons are on the previous network
+	errNetworkMigrationInProgress = errors.New("waiting for the mons to migrate to the new network")
+
+	// waitForNetworkMigration requeues the reconcile while the mons are failed over by the health check
+	waitForNetworkMigration = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+)
+
+// reconcileNetworkMigration reports the progress of the migration of the mons to the new network and
+// returns errNetworkMigrationInProgress until all the mons run on the new network
+func (c *cluster) reconcileNetworkMigration() error {
+	mons, err := c.mons.MonsOnPreviousNetwork()
+	if err != nil {
+		return errors.Wrap(err, "failed to list the mons on the previous network")
+	}
+	if err := c.updateNetworkMigrationStatus(mons, false); err != nil {
+		return err
+	}
+	if len(mons) > 0 {
+		logger.Infof("waiting for mon(s) %q to migrate to the %s network before updating the other daemons", strings.Join(mons, ","), networkName(c.Spec))
+		return errNetworkMigrationInProgress
+	}
+	return nil
+}
+
+// completeNetworkMigration reports the end of the migration once all the daemons were updated
+func (c *cluster) completeNetworkMigration() error {
+	return c.updateNetworkMigrationStatus(nil, true)
+}
+
+func (c *cluster) updateNetworkMigrationStatus(mons []string, daemonsUpdated bool) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(context.TODO(), c.namespacedName, cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.namespacedName.String())
+	}
+
+	status := nextNetworkMigrationStatus(cephCluster.Status.NetworkMigration, networkName(c.Spec), mons, daemonsUpdated)
+	if reflect.DeepEqual(cephCluster.Status.NetworkMigration, status) {
+		return nil
+	}
+	cephCluster.Status.NetworkMigration = status
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the network migration status")
+	}
+	return nil
+}
+
+// nextNetworkMigrationStatus returns the status of the migration given the mons still on the previous
+// network, or nil if no migration ever started
+func nextNetworkMigrationStatus(current *cephv1.NetworkMigrationStatus, network string, mons []string, daemonsUpdated bool) *cephv1.NetworkMigrationStatus {
+	if len(mons) > 0 {
+		status := &cephv1.NetworkMigrationStatus{Network: network, StartTime: time.Now().UTC().Format(time.RFC3339)}
+		if current != nil && current.Network == network && current.Phase == cephv1.NetworkMigrationMigratingMons {
+			status.StartTime = current.StartTime
+		}
+		status.Phase = cephv1.NetworkMigrationMigratingMons
+		status.MonsOnPreviousNetwork = mons
+		status.Message = fmt.Sprintf("failing over %d mon(s) to the %s network", len(mons), network)
+		return status
+	}
+	if current == nil || current.Phase == cephv1.NetworkMigrationCompleted {
+		return current
+	}
+
+	status := current.DeepCopy()
+	status.MonsOnPreviousNetwork = nil
+	if daemonsUpdated {
+		status.Phase = cephv1.NetworkMigrationCompleted
+		status.Message = fmt.Sprintf("all the daemons run on the %s network", status.Network)
+	} else {
+		status.Phase = cephv1.NetworkMigrationRollingDaemons
+		status.Message = fmt.Sprintf("updating the daemons on the %s network", status.Network)
+	}
+	return status
+}
+
+func networkName(spec *cephv1.ClusterSpec) string {
+	if spec.Network.IsHost() {
+		return "host"
+	}
+	return "pod"
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNextNetworkMigrationStatus(t *testing.T) {
+	// no migration ever started
+	assert.Nil(t, nextNetworkMigrationStatus(nil, "host", nil, false))
+	assert.Nil(t, nextNetworkMigrationStatus(nil, "host", nil, true))
+
+	// the migration starts with mons on the previous network
+	status := nextNetworkMigrationStatus(nil, "host", []string{"a", "b"}, false)
+	require.NotNil(t, status)
+	assert.Equal(t, "host", status.Network)
+	assert.Equal(t, cephv1.NetworkMigrationMigratingMons, status.Phase)
+	assert.Equal(t, []string{"a", "b"}, status.MonsOnPreviousNetwork)
+	assert.NotEmpty(t, status.StartTime)
+
+	// the start time is kept while the mons are migrated
+	status.StartTime = "2021-01-01T00:00:00Z"
+	status = nextNetworkMigrationStatus(status, "host", []string{"b"}, false)
+	assert.Equal(t, cephv1.NetworkMigrationMigratingMons, status.Phase)
+	assert.Equal(t, []string{"b"}, status.MonsOnPreviousNetwork)
+	assert.Equal(t, "2021-01-01T00:00:00Z", status.StartTime)
+
+	// the other daemons are updated after the mons
+	status = nextNetworkMigrationStatus(status, "host", nil, false)
+	assert.Equal(t, cephv1.NetworkMigrationRollingDaemons, status.Phase)
+	assert.Nil(t, status.MonsOnPreviousNetwork)
+	assert.Equal(t, "2021-01-01T00:00:00Z", status.StartTime)
+	status = nextNetworkMigrationStatus(status, "host", nil, true)
+	assert.Equal(t, cephv1.NetworkMigrationCompleted, status.Phase)
+	assert.Equal(t, "all the daemons run on the host network", status.Message)
+
+	// a completed migration is left as is
+	assert.Equal(t, status, nextNetworkMigrationStatus(status, "host", nil, false))
+
+	// a new migration restarts the progress
+	status = nextNetworkMigrationStatus(status, "pod", []string{"c"}, false)
+	assert.Equal(t, "pod", status.Network)
+	assert.Equal(t, cephv1.NetworkMigrationMigratingMons, status.Phase)
+	assert.NotEqual(t, "2021-01-01T00:00:00Z", status.StartTime)
+}
+
+func TestUpdateNetworkMigrationStatus(t *testing.T) {
+	ctx := context.TODO()
+	nsName := types.NamespacedName{Namespace: "rook-ceph", Name: "my-cluster"}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+
+	// the switch of the host networking starting the migration is accepted by the admission webhook
+	updated := cephCluster.DeepCopy()
+	updated.Spec.Network.HostNetwork = true
+	require.NoError(t, updated.ValidateUpdate(cephCluster))
+
+	c := &cluster{
+		Spec:           &updated.Spec,
+		context:        &clusterd.Context{Client: cl},
+		namespacedName: nsName,
+	}
+
+	err := c.updateNetworkMigrationStatus([]string{"a"}, false)
+	assert.NoError(t, err)
+	cephCluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, nsName, cephCluster))
+	require.NotNil(t, cephCluster.Status.NetworkMigration)
+	assert.Equal(t, cephv1.NetworkMigrationMigratingMons, cephCluster.Status.NetworkMigration.Phase)
+	assert.Equal(t, []string{"a"}, cephCluster.Status.NetworkMigration.MonsOnPreviousNetwork)
+
+	err = c.updateNetworkMigrationStatus(nil, false)
+	assert.NoError(t, err)
+	err = c.completeNetworkMigration()
+	assert.NoError(t, err)
+	cephCluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, nsName, cephCluster))
+	assert.Equal(t, cephv1.NetworkMigrationCompleted, cephCluster.Status.NetworkMigration.Phase)
+	assert.Equal(t, "host", cephCluster.Status.NetworkMigration.Network)
+}
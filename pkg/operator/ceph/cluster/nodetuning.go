@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NodeTuningAppName is the name of the daemonset tuning the kernel of the storage nodes
+	NodeTuningAppName = "rook-ceph-node-tuning"
+	// the privileged pods of the daemonset need no access to the API, so they have their own service account
+	nodeTuningServiceAccount = "rook-ceph-node-tuning"
+
+	nodeTuningHostSysVolume = "host-sys"
+	nodeTuningHostSysPath   = "/host/sys"
+	// the settings are applied again periodically in case they are reset on the node
+	nodeTuningIntervalSeconds = 600
+)
+
+var (
+	// defaultNodeSysctls are the kernel parameters required by ceph on nodes with many OSDs
+	defaultNodeSysctls = map[string]string{
+		"fs.aio-max-nr":  "1048576",
+		"kernel.pid_max": "4194304",
+	}
+
+	sysctlNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]+([./][a-zA-Z0-9_\-]+)+$`)
+)
+
+// reconcileNodeTuning starts the node tuning daemonset if it is enabled and removes it otherwise
+func (c *cluster) reconcileNodeTuning() error {
+	ctx := context.TODO()
+	if !c.Spec.NodeTuning.Enabled {
+		err := c.context.Clientset.AppsV1().DaemonSets(c.Namespace).Delete(ctx, NodeTuningAppName, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete the %q daemonset", NodeTuningAppName)
+		}
+		if err == nil {
+			logger.Infof("removed the %q daemonset since node tuning is disabled", NodeTuningAppName)
+		}
+		return nil
+	}
+
+	ds, err := c.makeNodeTuningDaemonSet()
+	if err != nil {
+		return err
+	}
+	if err := c.ownerInfo.SetControllerReference(ds); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to the %q daemonset", NodeTuningAppName)
+	}
+	k8sutil.AddRookVersionLabelToDaemonSet(ds)
+
+	logger.Infof("starting the %q daemonset to tune the kernel of the storage nodes", NodeTuningAppName)
+	return k8sutil.CreateDaemonSet(NodeTuningAppName, c.Namespace, c.context.Clientset, ds)
+}
+
+// updateNodeTuningCondition reports the failure of the node tuning in the Degraded condition of the cluster,
+// since the daemons are reconciled without the node tuning
+func (c *cluster) updateNodeTuningCondition(tuningErr error) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.namespacedName.String())
+	}
+
+	existing := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	if tuningErr != nil {
+		message := fmt.Sprintf("the storage nodes are not tuned. %v", tuningErr)
+		if existing != nil && existing.Status == v1.ConditionTrue && existing.Message == message {
+			return nil
+		}
+		// the cluster degraded for another reason keeps its condition
+		if existing != nil && existing.Status == v1.ConditionTrue && existing.Reason != cephv1.NodeTuningFailedReason {
+			logger.Debugf("not reporting the failure of the node tuning, the cluster is already degraded with reason %q", existing.Reason)
+			return nil
+		}
+		cephv1.SetStatusCondition(&cephCluster.Status.Conditions, cephv1.Condition{
+			Type:    cephv1.ConditionDegraded,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.NodeTuningFailedReason,
+			Message: message,
+		})
+	} else if existing != nil && existing.Status == v1.ConditionTrue && existing.Reason == cephv1.NodeTuningFailedReason {
+		cephv1.SetStatusCondition(&cephCluster.Status.Conditions, cephv1.Condition{
+			Type:    cephv1.ConditionDegraded,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.NodeTuningSucceededReason,
+			Message: "the node tuning is not failing anymore",
+		})
+	} else {
+		return nil
+	}
+
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the node tuning condition")
+	}
+	return nil
+}
+
+func (c *cluster) makeNodeTuningDaemonSet() (*apps.DaemonSet, error) {
+	script, err := nodeTuningScript(c.Spec.NodeTuning)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := controller.AppLabels(NodeTuningAppName, c.Namespace)
+	podSpec := v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   NodeTuningAppName,
+			Labels: labels,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:            "tune",
+					Image:           c.Spec.CephVersion.Image,
+					Command:         []string{"/bin/bash", "-c", script},
+					SecurityContext: osd.PrivilegedContext(),
+					VolumeMounts: []v1.VolumeMount{
+						{Name: nodeTuningHostSysVolume, MountPath: nodeTuningHostSysPath},
+					},
+					Resources: cephv1.GetNodeTuningResources(c.Spec.Resources),
+				},
+			},
+			Volumes: []v1.Volume{
+				{Name: nodeTuningHostSysVolume, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/sys"}}},
+			},
+			// the host namespaces are required for the namespaced sysctls to apply to the node
+			HostNetwork:        true,
+			HostIPC:            true,
+			HostPID:            true,
+			DNSPolicy:          v1.DNSClusterFirstWithHostNet,
+			ServiceAccountName: nodeTuningServiceAccount,
+			PriorityClassName:  cephv1.GetOSDPriorityClassName(c.Spec.PriorityClassNames),
+		},
+	}
+	cephv1.GetNodeTuningPlacement(c.Spec.Placement).ApplyToPodSpec(&podSpec.Spec)
+
+	ds := &apps.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NodeTuningAppName,
+			Namespace: c.Namespace,
+			Labels:    labels,
+		},
+		Spec: apps.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			UpdateStrategy: apps.DaemonSetUpdateStrategy{
+				Type: apps.RollingUpdateDaemonSetStrategyType,
+			},
+			Template: podSpec,
+		},
+	}
+	return ds, nil
+}
+
+// nodeTuningScript returns the script applying the kernel settings on the node
+func nodeTuningScript(spec cephv1.NodeTuningSpec) (string, error) {
+	sysctls := map[string]string{}
+	for name, value := range defaultNodeSysctls {
+		sysctls[name] = value
+	}
+	for name, value := range spec.Sysctls {
+		if !sysctlNameRegex.MatchString(name) {
+			return "", errors.Errorf("invalid sysctl name %q", name)
+		}
+		if strings.ContainsAny(value, "'\n") {
+			return "", errors.Errorf("invalid value %q for sysctl %q", value, name)
+		}
+		sysctls[name] = value
+	}
+	names := []string{}
+	for name := range sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("  sysctl -w %s='%s'", name, sysctls[name]))
+	}
+
+	switch spec.TransparentHugePages {
+	case "":
+	case "always", "madvise", "never":
+		lines = append(lines, fmt.Sprintf("  echo %s > %s/kernel/mm/transparent_hugepage/enabled", spec.TransparentHugePages, nodeTuningHostSysPath))
+	default:
+		return "", errors.Errorf("invalid transparent huge pages mode %q", spec.TransparentHugePages)
+	}
+
+	return fmt.Sprintf(`set -e
+tune() {
+%s
+}
+while true; do
+  tune
+  sleep %d
+done
+`, strings.Join(lines, "\n"), nodeTuningIntervalSeconds), nil
+}
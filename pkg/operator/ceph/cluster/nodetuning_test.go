@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNodeTuningScript(t *testing.T) {
+	// defaults only
+	script, err := nodeTuningScript(cephv1.NodeTuningSpec{Enabled: true})
+	assert.NoError(t, err)
+	assert.Contains(t, script, "sysctl -w fs.aio-max-nr='1048576'")
+	assert.Contains(t, script, "sysctl -w kernel.pid_max='4194304'")
+	assert.NotContains(t, script, "transparent_hugepage")
+
+	// the user settings override the defaults
+	script, err = nodeTuningScript(cephv1.NodeTuningSpec{
+		Enabled:              true,
+		Sysctls:              map[string]string{"fs.aio-max-nr": "2097152", "net.core.somaxconn": "1024"},
+		TransparentHugePages: "never",
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, script, "sysctl -w fs.aio-max-nr='2097152'")
+	assert.NotContains(t, script, "sysctl -w fs.aio-max-nr='1048576'")
+	assert.Contains(t, script, "sysctl -w net.core.somaxconn='1024'")
+	assert.Contains(t, script, "echo never > /host/sys/kernel/mm/transparent_hugepage/enabled")
+
+	// invalid settings
+	_, err = nodeTuningScript(cephv1.NodeTuningSpec{Sysctls: map[string]string{"foo; reboot": "1"}})
+	assert.Error(t, err)
+	_, err = nodeTuningScript(cephv1.NodeTuningSpec{Sysctls: map[string]string{"kernel.pid_max": "1'; reboot; '"}})
+	assert.Error(t, err)
+	_, err = nodeTuningScript(cephv1.NodeTuningSpec{TransparentHugePages: "sometimes"})
+	assert.Error(t, err)
+}
+
+func TestReconcileNodeTuning(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	clientset := testop.New(t, 1)
+	c := &cluster{
+		Namespace: namespace,
+		Spec: &cephv1.ClusterSpec{
+			CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v16"},
+			NodeTuning:  cephv1.NodeTuningSpec{Enabled: true},
+			Placement: cephv1.PlacementSpec{
+				cephv1.KeyOSD: cephv1.Placement{Tolerations: []v1.Toleration{{Key: "storage-node"}}},
+			},
+		},
+		context:   &clusterd.Context{Clientset: clientset},
+		ownerInfo: k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""),
+	}
+
+	// the daemonset is created when enabled
+	err := c.reconcileNodeTuning()
+	assert.NoError(t, err)
+	ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, NodeTuningAppName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	podSpec := ds.Spec.Template.Spec
+	assert.Equal(t, "quay.io/ceph/ceph:v16", podSpec.Containers[0].Image)
+	assert.True(t, *podSpec.Containers[0].SecurityContext.Privileged)
+	assert.True(t, podSpec.HostNetwork)
+	assert.Equal(t, "rook-ceph-node-tuning", podSpec.ServiceAccountName)
+	assert.Equal(t, "storage-node", podSpec.Tolerations[0].Key)
+
+	// reconciling again updates the daemonset
+	err = c.reconcileNodeTuning()
+	assert.NoError(t, err)
+
+	// an invalid setting fails the reconcile
+	c.Spec.NodeTuning.TransparentHugePages = "sometimes"
+	err = c.reconcileNodeTuning()
+	assert.Error(t, err)
+
+	// the daemonset is removed when disabled
+	c.Spec.NodeTuning.Enabled = false
+	err = c.reconcileNodeTuning()
+	assert.NoError(t, err)
+	_, err = clientset.AppsV1().DaemonSets(namespace).Get(ctx, NodeTuningAppName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// disabling again is a no-op
+	err = c.reconcileNodeTuning()
+	assert.NoError(t, err)
+}
+
+func TestUpdateNodeTuningCondition(t *testing.T) {
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	c := &cluster{
+		context:        &clusterd.Context{Client: cl},
+		ClusterInfo:    clusterInfo,
+		namespacedName: types.NamespacedName{Name: "test", Namespace: "ns"},
+	}
+	getDegraded := func() *cephv1.Condition {
+		cephCluster := &cephv1.CephCluster{}
+		err := cl.Get(clusterInfo.Context, c.namespacedName, cephCluster)
+		require.NoError(t, err)
+		return cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	}
+
+	// no condition is set while the tuning succeeds
+	err := c.updateNodeTuningCondition(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, getDegraded())
+
+	// the cluster is degraded when the tuning fails
+	err = c.updateNodeTuningCondition(errors.New("invalid transparent huge pages mode"))
+	assert.NoError(t, err)
+	degraded := getDegraded()
+	require.NotNil(t, degraded)
+	assert.Equal(t, v1.ConditionTrue, degraded.Status)
+	assert.Equal(t, cephv1.NodeTuningFailedReason, degraded.Reason)
+	assert.Contains(t, degraded.Message, "invalid transparent huge pages mode")
+
+	// the condition is resolved once the tuning succeeds again
+	err = c.updateNodeTuningCondition(nil)
+	assert.NoError(t, err)
+	degraded = getDegraded()
+	require.NotNil(t, degraded)
+	assert.Equal(t, v1.ConditionFalse, degraded.Status)
+	assert.Equal(t, cephv1.NodeTuningSucceededReason, degraded.Reason)
+
+	// the cluster degraded for another reason keeps its condition
+	cephCluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(clusterInfo.Context, c.namespacedName, cephCluster))
+	cephv1.SetStatusCondition(&cephCluster.Status.Conditions, cephv1.Condition{
+		Type:   cephv1.ConditionDegraded,
+		Status: v1.ConditionTrue,
+		Reason: cephv1.ConditionReason("OtherReason"),
+	})
+	require.NoError(t, cl.Update(clusterInfo.Context, cephCluster))
+	err = c.updateNodeTuningCondition(errors.New("invalid transparent huge pages mode"))
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.ConditionReason("OtherReason"), getDegraded().Reason)
+	err = c.updateNodeTuningCondition(nil)
+	assert.NoError(t, err)
+	degraded = getDegraded()
+	assert.Equal(t, v1.ConditionTrue, degraded.Status)
+	assert.Equal(t, cephv1.ConditionReason("OtherReason"), degraded.Reason)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// capacityGuard projects the fullness of the cluster while the data is rebalanced to the OSDs created
+// during a reconcile
+type capacityGuard struct {
+	loaded        bool
+	usedBytes     uint64
+	totalBytes    uint64
+	nearFullRatio float64
+	// addedBytes is the capacity of the new OSDs already created during the reconcile
+	addedBytes uint64
+	// exceeded is the last projection above the nearfull ratio, and refused whether its OSDs were not created
+	exceeded string
+	refused  bool
+}
+
+// projectedRebalanceFullness returns the fullness of the existing capacity of the cluster while its data is
+// rebalanced to the new capacity. The share of the data moved to the new capacity is counted twice, since the
+// PGs are only removed from their previous OSDs once they are backfilled.
+func projectedRebalanceFullness(usedBytes, totalBytes, newBytes uint64) float64 {
+	if totalBytes == 0 {
+		return 0
+	}
+	moved := float64(usedBytes) * float64(newBytes) / float64(totalBytes+newBytes)
+	return (float64(usedBytes) + moved) / float64(totalBytes)
+}
+
+// load reads the usage of the cluster and its nearfull ratio once per reconcile
+func (g *capacityGuard) load(c *Cluster) error {
+	if g.loaded {
+		return nil
+	}
+	usage, err := cephclient.GetOSDUsage(c.context, c.clusterInfo)
+	if err != nil {
+		return err
+	}
+	totalKB, err := usage.Summary.TotalKB.Float64()
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the total capacity %q of the osds", usage.Summary.TotalKB)
+	}
+	usedKB, err := usage.Summary.TotalUsedKB.Float64()
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the used capacity %q of the osds", usage.Summary.TotalUsedKB)
+	}
+	dump, err := cephclient.GetOSDDump(c.context, c.clusterInfo)
+	if err != nil {
+		return err
+	}
+	g.totalBytes = uint64(totalKB) * 1024
+	g.usedBytes = uint64(usedKB) * 1024
+	g.nearFullRatio = dump.NearFullRatio
+	g.loaded = true
+	return nil
+}
+
+// allowNewOSDs returns whether the new OSDs reported by a prepare job can be created. They are not created if the
+// rebalance of the data to the new OSDs, and to the OSDs created before them during the reconcile, could fill the
+// cluster above the nearfull ratio, unless it is allowed in the storage spec.
+func (c *createConfig) allowNewOSDs(status *OrchestrationStatus, nodeOrPVCName string) bool {
+	newOSDs := 0
+	var newBytes uint64
+	for _, osd := range status.OSDs {
+		if c.deployments.Exists(osd.ID) {
+			continue
+		}
+		newOSDs++
+		size := osd.Size
+		if size == 0 && status.PvcBackedOSD {
+			size = c.cluster.pvcCapacity(nodeOrPVCName)
+		}
+		newBytes += size
+	}
+	if newBytes == 0 {
+		// no new OSD, or a prepare job not reporting the size of the OSDs
+		return true
+	}
+
+	if err := c.capacity.load(c.cluster); err != nil {
+		logger.Warningf("failed to project the rebalance to the %d new OSD(s) on %q, creating them. %v", newOSDs, nodeOrPVCName, err)
+		return true
+	}
+	if c.capacity.totalBytes == 0 || c.capacity.nearFullRatio == 0 {
+		// the first OSDs of the cluster
+		c.capacity.addedBytes += newBytes
+		return true
+	}
+
+	fullness := projectedRebalanceFullness(c.capacity.usedBytes, c.capacity.totalBytes, c.capacity.addedBytes+newBytes)
+	if fullness <= c.capacity.nearFullRatio {
+		logger.Infof("the rebalance to the %d new OSD(s) on %q fills the cluster up to %.0f%%", newOSDs, nodeOrPVCName, fullness*100)
+		c.capacity.addedBytes += newBytes
+		return true
+	}
+
+	c.capacity.exceeded = fmt.Sprintf("the rebalance to the %d new OSD(s) on %q could fill the cluster up to %.0f%%, above the nearfull ratio of %.0f%%",
+		newOSDs, nodeOrPVCName, fullness*100, c.capacity.nearFullRatio*100)
+	if c.cluster.spec.Storage.AllowRebalanceAboveNearfull {
+		logger.Warningf("%s. creating them since allowRebalanceAboveNearfull is true", c.capacity.exceeded)
+		c.capacity.addedBytes += newBytes
+		return true
+	}
+	logger.Warningf("not creating the new OSD(s) on %q. %s. set allowRebalanceAboveNearfull to create them anyway", nodeOrPVCName, c.capacity.exceeded)
+	c.capacity.refused = true
+	return false
+}
+
+// pvcCapacity returns the capacity in bytes of a PVC, or 0 if it is unknown
+func (c *Cluster) pvcCapacity(pvcName string) uint64 {
+	pvc, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, pvcName, metav1.GetOptions{})
+	if err != nil {
+		logger.Debugf("failed to get the capacity of pvc %q. %v", pvcName, err)
+		return 0
+	}
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok || capacity.Sign() <= 0 {
+		return 0
+	}
+	return uint64(capacity.Value())
+}
+
+// updateCapacityGuardStatus reports with a condition of the CephCluster whether the rebalance to the new OSDs
+// could fill the cluster above the nearfull ratio, and whether the new OSDs were created anyway
+func (c *Cluster) updateCapacityGuardStatus(guard *capacityGuard) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.clusterInfo.NamespacedName().String())
+	}
+
+	existing := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionRebalanceAboveNearfull)
+	var condition cephv1.Condition
+	switch {
+	case guard.exceeded != "" && guard.refused:
+		condition = cephv1.Condition{
+			Type:    cephv1.ConditionRebalanceAboveNearfull,
+			Status:  corev1.ConditionTrue,
+			Reason:  cephv1.OSDCreationRefusedReason,
+			Message: guard.exceeded + ". the new OSDs are not created unless storage.allowRebalanceAboveNearfull is true",
+		}
+	case guard.exceeded != "":
+		condition = cephv1.Condition{
+			Type:    cephv1.ConditionRebalanceAboveNearfull,
+			Status:  corev1.ConditionTrue,
+			Reason:  cephv1.OSDCreationAllowedReason,
+			Message: guard.exceeded + ". the new OSDs were created since storage.allowRebalanceAboveNearfull is true",
+		}
+	case existing != nil && existing.Status == corev1.ConditionTrue:
+		condition = cephv1.Condition{
+			Type:    cephv1.ConditionRebalanceAboveNearfull,
+			Status:  corev1.ConditionFalse,
+			Reason:  cephv1.RebalanceBelowNearfullReason,
+			Message: "no new OSD could fill the cluster above the nearfull ratio",
+		}
+	default:
+		return nil
+	}
+	if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+		return nil
+	}
+	if err := reporting.UpdateStatusCondition(c.context.Client, cephCluster, condition); err != nil {
+		return errors.Wrap(err, "failed to report the rebalance to the new osds")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProjectedRebalanceFullness(t *testing.T) {
+	assert.Equal(t, float64(0), projectedRebalanceFullness(0, 0, 100))
+	assert.Equal(t, 0.5, projectedRebalanceFullness(50, 100, 0))
+	// half of the data moves to the new capacity
+	assert.Equal(t, 0.75, projectedRebalanceFullness(50, 100, 100))
+	assert.InDelta(t, 0.6545, projectedRebalanceFullness(600, 1000, 100), 0.001)
+}
+
+func TestAllowNewOSDs(t *testing.T) {
+	const gib = uint64(1024 * 1024 * 1024)
+	osdDFCalls := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "df" {
+				osdDFCalls++
+				// 600GiB used of 1TiB
+				return `{"nodes":[],"summary":{"total_kb":1073741824,"total_kb_used":629145600,"total_kb_avail":444596224}}`, nil
+			}
+			if args[0] == "osd" && args[1] == "dump" {
+				return `{"osds":[],"nearfull_ratio":0.85}`, nil
+			}
+			return "", nil
+		},
+	}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.SetName("rook")
+	c := &Cluster{context: &clusterd.Context{Executor: executor, Clientset: test.New(t, 1), Client: cl}, clusterInfo: clusterInfo}
+	createConfig := c.newCreateConfig(c.newProvisionConfig(), nil, newExistenceListWithIDs(0))
+
+	getCondition := func() *cephv1.Condition {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, cl.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cluster))
+		return cephv1.FindStatusCondition(cluster.Status.Conditions, cephv1.ConditionRebalanceAboveNearfull)
+	}
+
+	// the size of the OSDs is unknown or the OSDs already exist
+	assert.True(t, createConfig.allowNewOSDs(&OrchestrationStatus{OSDs: []OSDInfo{{ID: 1}, {ID: 0, Size: 1000 * gib}}}, "node1"))
+	assert.Equal(t, 0, osdDFCalls)
+
+	// 53GiB move to the 100GiB, the cluster is filled up to 64% during the rebalance
+	assert.True(t, createConfig.allowNewOSDs(&OrchestrationStatus{OSDs: []OSDInfo{{ID: 1, Size: 100 * gib}}}, "node1"))
+	// another 400GiB would fill it up to 78%
+	assert.True(t, createConfig.allowNewOSDs(&OrchestrationStatus{OSDs: []OSDInfo{{ID: 2, Size: 200 * gib}, {ID: 3, Size: 200 * gib}}}, "node2"))
+	assert.Equal(t, 1, osdDFCalls)
+	assert.NoError(t, c.updateCapacityGuardStatus(createConfig.capacity))
+	assert.Nil(t, getCondition())
+
+	// another 1TiB would fill it up to 94%
+	assert.False(t, createConfig.allowNewOSDs(&OrchestrationStatus{OSDs: []OSDInfo{{ID: 4, Size: 1024 * gib}}}, "node3"))
+	assert.NoError(t, c.updateCapacityGuardStatus(createConfig.capacity))
+	condition := getCondition()
+	require.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.OSDCreationRefusedReason, condition.Reason)
+	assert.Contains(t, condition.Message, `1 new OSD(s) on "node3"`)
+
+	// the rebalance above nearfull is allowed
+	c.spec.Storage.AllowRebalanceAboveNearfull = true
+	createConfig = c.newCreateConfig(c.newProvisionConfig(), nil, newExistenceListWithIDs(0))
+	assert.True(t, createConfig.allowNewOSDs(&OrchestrationStatus{OSDs: []OSDInfo{{ID: 4, Size: 2048 * gib}}}, "node3"))
+	assert.NoError(t, c.updateCapacityGuardStatus(createConfig.capacity))
+	assert.Equal(t, cephv1.OSDCreationAllowedReason, getCondition().Reason)
+
+	// the condition is reset once no rebalance exceeds nearfull
+	createConfig = c.newCreateConfig(c.newProvisionConfig(), nil, newExistenceListWithIDs(0, 4))
+	assert.True(t, createConfig.allowNewOSDs(&OrchestrationStatus{OSDs: []OSDInfo{{ID: 4, Size: 2048 * gib}}}, "node3"))
+	assert.NoError(t, c.updateCapacityGuardStatus(createConfig.capacity))
+	condition = getCondition()
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, cephv1.RebalanceBelowNearfullReason, condition.Reason)
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CephConfigConfigMapName is the name of the configmap keeping the Ceph config options set on each OSD from the
+// node and device entries of the storage spec, to remove them from the mon config store when they are removed
+// from the spec
+const CephConfigConfigMapName = "rook-ceph-osd-ceph-config"
+
+// configureCephConfig sets the Ceph config options of the node and device entries of the storage spec on the
+// OSDs of these nodes and devices in the mon config store. The device of an OSD is found from the devices reported
+// in its metadata, so the OSDs that never started only get the options of their node until the next reconcile.
+func (c *Cluster) configureCephConfig() error {
+	applied, err := c.getAppliedCephConfig()
+	if err != nil {
+		return err
+	}
+	// the nodes that are not valid anymore, such as cordoned nodes, keep the options of their osds
+	storage := c.spec.Storage.DeepCopy()
+	if len(applied) == 0 && !hasCephConfig(storage) {
+		return nil
+	}
+
+	// the osds on pvcs are not in the node entries
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s,%s,!%s", k8sutil.AppAttr, AppName, NodeHostnameLabelKey, OSDOverPVCLabelKey)}
+	deployments, err := c.context.Clientset.AppsV1().Deployments(c.clusterInfo.Namespace).List(c.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osd deployments")
+	}
+	metadata, err := cephclient.GetOSDMetadata(c.context, c.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the devices of the osds")
+	}
+	osdMetadata := map[int]cephclient.OSDMetadata{}
+	for _, m := range metadata {
+		osdMetadata[m.ID] = m
+	}
+
+	desired := map[string]map[string]string{}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		osdID, err := getOSDID(d)
+		if err != nil {
+			return err
+		}
+		node := storage.ResolveNode(d.Labels[NodeHostnameLabelKey])
+		if node == nil {
+			continue
+		}
+		m, ok := osdMetadata[osdID]
+		if !ok {
+			m = cephclient.OSDMetadata{ID: osdID}
+		}
+		if options := osdCephConfig(node, &m); len(options) > 0 {
+			desired[fmt.Sprintf("osd.%d", osdID)] = options
+		}
+	}
+
+	monStore := opconfig.GetMonStore(c.context, c.clusterInfo)
+	for who, options := range desired {
+		for _, option := range sortedKeys(options) {
+			if _, err := monStore.SetIfChanged(who, option, options[option]); err != nil {
+				return errors.Wrapf(err, "failed to set option %q of %s", option, who)
+			}
+		}
+	}
+	for who, options := range applied {
+		for _, option := range sortedKeys(options) {
+			if _, ok := desired[who][option]; ok {
+				continue
+			}
+			if err := monStore.Delete(who, option); err != nil {
+				return errors.Wrapf(err, "failed to remove option %q of %s", option, who)
+			}
+		}
+	}
+
+	return c.saveAppliedCephConfig(desired)
+}
+
+// hasCephConfig returns whether a node or device entry of the storage spec has Ceph config options
+func hasCephConfig(storage *cephv1.StorageScopeSpec) bool {
+	for _, device := range storage.Devices {
+		if len(device.CephConfig) > 0 {
+			return true
+		}
+	}
+	for _, node := range storage.Nodes {
+		if len(node.CephConfig) > 0 {
+			return true
+		}
+		for _, device := range node.Devices {
+			if len(device.CephConfig) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// osdCephConfig returns the Ceph config options of an OSD of the node, the options of its device taking
+// precedence over the options of the node
+func osdCephConfig(node *cephv1.Node, metadata *cephclient.OSDMetadata) map[string]string {
+	options := map[string]string{}
+	for option, value := range node.CephConfig {
+		options[option] = value
+	}
+	for _, device := range node.Devices {
+		if !isOSDDevice(device, metadata) {
+			continue
+		}
+		for option, value := range device.CephConfig {
+			options[option] = value
+		}
+	}
+	return options
+}
+
+// isOSDDevice returns whether the device of the spec is one of the devices of the OSD. The OSDs report the kernel
+// names of their devices, such as "sdb", and their paths by path.
+func isOSDDevice(device cephv1.Device, metadata *cephclient.OSDMetadata) bool {
+	names := map[string]bool{}
+	for _, name := range strings.Split(metadata.Devices, ",") {
+		if name != "" {
+			names[name] = true
+		}
+	}
+	for _, devicePath := range strings.Split(metadata.DevicePaths, ",") {
+		if i := strings.Index(devicePath, "="); i >= 0 {
+			names[devicePath[i+1:]] = true
+		}
+	}
+	for _, name := range []string{device.Name, device.FullPath} {
+		if name == "" {
+			continue
+		}
+		if names[name] || names[strings.TrimPrefix(name, "/dev/")] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cluster) getAppliedCephConfig() (map[string]map[string]string, error) {
+	applied := map[string]map[string]string{}
+	configMap, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, CephConfigConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return applied, nil
+		}
+		return nil, errors.Wrap(err, "failed to get the ceph config options of the osds")
+	}
+	for who, value := range configMap.Data {
+		options := map[string]string{}
+		if err := json.Unmarshal([]byte(value), &options); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the ceph config options of %s", who)
+		}
+		applied[who] = options
+	}
+	return applied, nil
+}
+
+func (c *Cluster) saveAppliedCephConfig(applied map[string]map[string]string) error {
+	data := map[string]string{}
+	for who, options := range applied {
+		value, err := json.Marshal(options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize the ceph config options of %s", who)
+		}
+		data[who] = string(value)
+	}
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CephConfigConfigMapName,
+			Namespace: c.clusterInfo.Namespace,
+		},
+		Data: data,
+	}
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(configMap); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to configmap %q", configMap.Name)
+	}
+	if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Create(c.clusterInfo.Context, configMap, metav1.CreateOptions{}); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create configmap %q", configMap.Name)
+		}
+		if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Update(c.clusterInfo.Context, configMap, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update configmap %q", configMap.Name)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(options map[string]string) []string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
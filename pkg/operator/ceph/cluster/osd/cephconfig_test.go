@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsOSDDevice(t *testing.T) {
+	metadata := &cephclient.OSDMetadata{Devices: "sdb,nvme0n1", DevicePaths: "sdb=/dev/disk/by-path/pci-0000:00:10.0-scsi-0:0:1:0,nvme0n1=/dev/disk/by-path/pci-0000:04:00.0-nvme-1"}
+	assert.True(t, isOSDDevice(cephv1.Device{Name: "sdb"}, metadata))
+	assert.True(t, isOSDDevice(cephv1.Device{Name: "/dev/nvme0n1"}, metadata))
+	assert.True(t, isOSDDevice(cephv1.Device{FullPath: "/dev/disk/by-path/pci-0000:00:10.0-scsi-0:0:1:0"}, metadata))
+	assert.False(t, isOSDDevice(cephv1.Device{Name: "sdc"}, metadata))
+	assert.False(t, isOSDDevice(cephv1.Device{}, metadata))
+	assert.False(t, isOSDDevice(cephv1.Device{Name: "sdb"}, &cephclient.OSDMetadata{}))
+}
+
+func TestOSDCephConfig(t *testing.T) {
+	node := &cephv1.Node{
+		CephConfig: map[string]string{"osd_recovery_max_active": "3", "bluestore_cache_size": "1073741824"},
+		Selection: cephv1.Selection{Devices: []cephv1.Device{
+			{Name: "sdb", CephConfig: map[string]string{"bluestore_cache_size": "2147483648"}},
+			{Name: "sdc", CephConfig: map[string]string{"osd_max_backfills": "2"}},
+		}},
+	}
+	assert.Equal(t, map[string]string{"osd_recovery_max_active": "3", "bluestore_cache_size": "2147483648"},
+		osdCephConfig(node, &cephclient.OSDMetadata{Devices: "sdb"}))
+	assert.Equal(t, map[string]string{"osd_recovery_max_active": "3", "bluestore_cache_size": "1073741824"},
+		osdCephConfig(node, &cephclient.OSDMetadata{}))
+}
+
+func TestConfigureCephConfig(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	clientset := fake.NewSimpleClientset()
+	for id, node := range []string{"node1", "node2"} {
+		d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("rook-ceph-osd-%d", id),
+			Namespace: namespace,
+			Labels:    map[string]string{k8sutil.AppAttr: AppName, OsdIdLabelKey: strconv.Itoa(id), NodeHostnameLabelKey: node},
+		}}
+		_, err := clientset.AppsV1().Deployments(namespace).Create(ctx, d, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	store := map[string]string{}
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if args[0] == "osd" && args[1] == "metadata" {
+				commands = append(commands, "metadata")
+				return `[{"id":0,"hostname":"node1","devices":"sdb"},{"id":1,"hostname":"node2","devices":"sdc"}]`, nil
+			}
+			if args[0] == "config" {
+				key := args[2] + "/" + args[3]
+				switch args[1] {
+				case "get":
+					return store[key], nil
+				case "set":
+					commands = append(commands, "set "+key+"="+args[4])
+					store[key] = args[4]
+				case "rm":
+					commands = append(commands, "rm "+key)
+					delete(store, key)
+				}
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	clusterInfo := cephclient.AdminClusterInfo(namespace)
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	c := &Cluster{
+		context:     &clusterd.Context{Clientset: clientset, Executor: executor},
+		clusterInfo: clusterInfo,
+	}
+
+	// nothing to do without options
+	require.NoError(t, c.configureCephConfig())
+	assert.Empty(t, commands)
+
+	c.spec.Storage.Nodes = []cephv1.Node{
+		{Name: "node1", CephConfig: map[string]string{"osd_recovery_max_active": "3"}, Selection: cephv1.Selection{
+			Devices: []cephv1.Device{{Name: "sdb", CephConfig: map[string]string{"bluestore_cache_size": "2147483648"}}},
+		}},
+		{Name: "node2"},
+	}
+	require.NoError(t, c.configureCephConfig())
+	assert.Equal(t, []string{"metadata", "set osd.0/bluestore_cache_size=2147483648", "set osd.0/osd_recovery_max_active=3"}, commands)
+
+	// the options are not set again
+	commands = nil
+	require.NoError(t, c.configureCephConfig())
+	assert.Equal(t, []string{"metadata"}, commands)
+
+	// the options removed from the spec are removed from the mon store
+	commands = nil
+	c.spec.Storage.Nodes[0].CephConfig = nil
+	require.NoError(t, c.configureCephConfig())
+	assert.Equal(t, []string{"metadata", "rm osd.0/osd_recovery_max_active"}, commands)
+
+	commands = nil
+	c.spec.Storage.Nodes = []cephv1.Node{{Name: "node1"}, {Name: "node2"}}
+	require.NoError(t, c.configureCephConfig())
+	assert.Equal(t, []string{"metadata", "rm osd.0/bluestore_cache_size"}, commands)
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, CephConfigConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, configMap.Data)
+
+	// nothing left to do
+	commands = nil
+	require.NoError(t, c.configureCephConfig())
+	assert.Empty(t, commands)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// DeviceClaimsAnnotation is the node annotation recording the fsid of the ceph cluster owning
+	// each device of the node. Since nodes are not namespaced, the claims are shared by all the
+	// ceph clusters running on the same nodes.
+	DeviceClaimsAnnotation = "ceph.rook.io/device-claims"
+)
+
+// GetDeviceClaims returns the device claims of the node keyed by the device claim id
+func GetDeviceClaims(node *v1.Node) (map[string]string, error) {
+	claims := map[string]string{}
+	raw, ok := node.Annotations[DeviceClaimsAnnotation]
+	if !ok || raw == "" {
+		return claims, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &claims); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse device claims of node %q", node.Name)
+	}
+	return claims, nil
+}
+
+// UpdateDeviceClaims sets the device claims on the node with a merge patch of the annotation, which is all
+// the osd prepare jobs and the operator can change on the nodes. The patch fails with a conflict if the node
+// changed since it was read, so two clusters cannot claim the same device.
+func UpdateDeviceClaims(clientset kubernetes.Interface, node *v1.Node, claims map[string]string) error {
+	// a null annotation is removed by the merge patch
+	var value interface{}
+	if len(claims) > 0 {
+		raw, err := json.Marshal(claims)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize device claims of node %q", node.Name)
+		}
+		value = string(raw)
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": node.ResourceVersion,
+			"annotations":     map[string]interface{}{DeviceClaimsAnnotation: value},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize the device claims patch of node %q", node.Name)
+	}
+	_, err = clientset.CoreV1().Nodes().Patch(context.TODO(), node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// ReleaseDeviceClaims removes the device claims of the cluster with the given fsid from the node, only the
+// claims of the given device claim ids if any
+func ReleaseDeviceClaims(clientset kubernetes.Interface, nodeName, fsid string, ids ...string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := clientset.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get node %q", nodeName)
+		}
+		claims, err := GetDeviceClaims(node)
+		if err != nil {
+			return err
+		}
+
+		updated := false
+		for id, owner := range claims {
+			if owner != fsid || (len(ids) > 0 && !contains(ids, id)) {
+				continue
+			}
+			logger.Infof("releasing claim on device %q of node %q", id, nodeName)
+			delete(claims, id)
+			updated = true
+		}
+		if !updated {
+			return nil
+		}
+
+		return UpdateDeviceClaims(clientset, node, claims)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to release device claims on node %q", nodeName)
+	}
+	return nil
+}
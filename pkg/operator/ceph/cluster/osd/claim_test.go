@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeviceClaims(t *testing.T) {
+	clientset := test.New(t, 1)
+	getNode := func() *corev1.Node {
+		node, err := clientset.CoreV1().Nodes().Get(context.TODO(), "node0", metav1.GetOptions{})
+		assert.NoError(t, err)
+		return node
+	}
+
+	// no claims yet
+	node := getNode()
+	claims, err := GetDeviceClaims(node)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(claims))
+
+	err = UpdateDeviceClaims(clientset, node, map[string]string{"serial-a": "fsid-1"})
+	assert.NoError(t, err)
+	node = getNode()
+	assert.Equal(t, `{"serial-a":"fsid-1"}`, node.Annotations[DeviceClaimsAnnotation])
+	claims, err = GetDeviceClaims(node)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"serial-a": "fsid-1"}, claims)
+
+	// the annotation is removed when there are no more claims
+	err = UpdateDeviceClaims(clientset, node, map[string]string{})
+	assert.NoError(t, err)
+	node = getNode()
+	_, ok := node.Annotations[DeviceClaimsAnnotation]
+	assert.False(t, ok)
+
+	// invalid claims
+	node.Annotations = map[string]string{DeviceClaimsAnnotation: "foo"}
+	_, err = GetDeviceClaims(node)
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+)
+
+const (
+	compressionModeOption      = "bluestore_compression_mode"
+	compressionAlgorithmOption = "bluestore_compression_algorithm"
+)
+
+var (
+	compressionModes      = []string{"none", "passive", "aggressive", "force"}
+	compressionAlgorithms = []string{"snappy", "zlib", "zstd", "lz4"}
+)
+
+// validateCompression validates the bluestore compression settings of the storage config, of the nodes and of
+// the device sets
+func validateCompression(storage *cephv1.StorageScopeSpec) error {
+	if err := validateCompressionSettings(storage.Config[config.CompressionModeKey], storage.Config[config.CompressionAlgorithmKey]); err != nil {
+		return errors.Wrap(err, "invalid compression of the storage config")
+	}
+	for _, node := range storage.Nodes {
+		if err := validateCompressionSettings(node.Config[config.CompressionModeKey], node.Config[config.CompressionAlgorithmKey]); err != nil {
+			return errors.Wrapf(err, "invalid compression of node %q", node.Name)
+		}
+	}
+	for _, deviceSet := range storage.StorageClassDeviceSets {
+		if err := validateCompressionSettings(deviceSet.CompressionMode, deviceSet.CompressionAlgorithm); err != nil {
+			return errors.Wrapf(err, "invalid compression of device set %q", deviceSet.Name)
+		}
+	}
+	return nil
+}
+
+func validateCompressionSettings(mode, algorithm string) error {
+	if mode != "" && !contains(compressionModes, mode) {
+		return errors.Errorf("unsupported compression mode %q, must be one of %v", mode, compressionModes)
+	}
+	if algorithm != "" && !contains(compressionAlgorithms, algorithm) {
+		return errors.Errorf("unsupported compression algorithm %q, must be one of %v", algorithm, compressionAlgorithms)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// configureCompression sets the bluestore compression settings of the OSD in the mon store. The mode of the OSD
+// applies to the pools that do not set their own compression mode. The settings are left unchanged when they are
+// removed from the spec, they must be set to their default value to reset them.
+func (c *Cluster) configureCompression(osdID int, storeConfig config.StoreConfig) error {
+	if storeConfig.CompressionMode == "" && storeConfig.CompressionAlgorithm == "" {
+		return nil
+	}
+
+	monStore := opconfig.GetMonStore(c.context, c.clusterInfo)
+	who := fmt.Sprintf("osd.%d", osdID)
+	if storeConfig.CompressionMode != "" {
+		if _, err := monStore.SetIfChanged(who, compressionModeOption, storeConfig.CompressionMode); err != nil {
+			return errors.Wrapf(err, "failed to set the compression mode of osd.%d", osdID)
+		}
+	}
+	if storeConfig.CompressionAlgorithm != "" {
+		if _, err := monStore.SetIfChanged(who, compressionAlgorithmOption, storeConfig.CompressionAlgorithm); err != nil {
+			return errors.Wrapf(err, "failed to set the compression algorithm of osd.%d", osdID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCompression(t *testing.T) {
+	storage := &cephv1.StorageScopeSpec{}
+	assert.NoError(t, validateCompression(storage))
+
+	storage.Config = map[string]string{config.CompressionModeKey: "aggressive", config.CompressionAlgorithmKey: "zstd"}
+	storage.StorageClassDeviceSets = []cephv1.StorageClassDeviceSet{{Name: "set1", CompressionMode: "force", CompressionAlgorithm: "lz4"}}
+	assert.NoError(t, validateCompression(storage))
+
+	storage.StorageClassDeviceSets[0].CompressionAlgorithm = "gzip"
+	assert.Error(t, validateCompression(storage))
+	storage.StorageClassDeviceSets[0].CompressionAlgorithm = ""
+
+	storage.Nodes = []cephv1.Node{{Name: "node1", Config: map[string]string{config.CompressionModeKey: "always"}}}
+	assert.Error(t, validateCompression(storage))
+}
+
+func TestConfigureCompression(t *testing.T) {
+	monStore := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "config" && args[1] == "get":
+				return monStore[args[2]+"/"+args[3]], nil
+			case args[0] == "config" && args[1] == "set":
+				monStore[args[2]+"/"+args[3]] = args[4]
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", Context: context.TODO()}
+	c := New(&clusterd.Context{Executor: executor}, clusterInfo, cephv1.ClusterSpec{}, "myversion")
+
+	// nothing is set by default
+	assert.NoError(t, c.configureCompression(0, config.StoreConfig{}))
+	assert.Empty(t, monStore)
+
+	assert.NoError(t, c.configureCompression(0, config.StoreConfig{CompressionMode: "passive"}))
+	assert.NoError(t, c.configureCompression(1, config.StoreConfig{CompressionMode: "aggressive", CompressionAlgorithm: "zstd"}))
+	expected := map[string]string{
+		"osd.0/bluestore_compression_mode":      "passive",
+		"osd.1/bluestore_compression_mode":      "aggressive",
+		"osd.1/bluestore_compression_algorithm": "zstd",
+	}
+	assert.Equal(t, expected, monStore)
+}
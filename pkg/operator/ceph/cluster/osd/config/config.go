@@ -31,18 +31,24 @@ const (
 	DeviceClassKey     = "deviceClass"
 	InitialWeightKey   = "initialWeight"
 	PrimaryAffinityKey = "primaryAffinity"
+	// CompressionModeKey is the bluestore compression mode of the OSDs
+	CompressionModeKey = "compressionMode"
+	// CompressionAlgorithmKey is the bluestore compression algorithm of the OSDs
+	CompressionAlgorithmKey = "compressionAlgorithm"
 )
 
 // StoreConfig represents the configuration of an OSD on a device.
 type StoreConfig struct {
-	WalSizeMB       int    `json:"walSizeMB,omitempty"`
-	DatabaseSizeMB  int    `json:"databaseSizeMB,omitempty"`
-	OSDsPerDevice   int    `json:"osdsPerDevice,omitempty"`
-	EncryptedDevice bool   `json:"encryptedDevice,omitempty"`
-	MetadataDevice  string `json:"metadataDevice,omitempty"`
-	DeviceClass     string `json:"deviceClass,omitempty"`
-	InitialWeight   string `json:"initialWeight,omitempty"`
-	PrimaryAffinity string `json:"primaryAffinity,omitempty"`
+	WalSizeMB            int    `json:"walSizeMB,omitempty"`
+	DatabaseSizeMB       int    `json:"databaseSizeMB,omitempty"`
+	OSDsPerDevice        int    `json:"osdsPerDevice,omitempty"`
+	EncryptedDevice      bool   `json:"encryptedDevice,omitempty"`
+	MetadataDevice       string `json:"metadataDevice,omitempty"`
+	DeviceClass          string `json:"deviceClass,omitempty"`
+	InitialWeight        string `json:"initialWeight,omitempty"`
+	PrimaryAffinity      string `json:"primaryAffinity,omitempty"`
+	CompressionMode      string `json:"compressionMode,omitempty"`
+	CompressionAlgorithm string `json:"compressionAlgorithm,omitempty"`
 }
 
 // NewStoreConfig returns a StoreConfig with proper defaults set.
@@ -76,6 +82,10 @@ func ToStoreConfig(config map[string]string) StoreConfig {
 			storeConfig.InitialWeight = v
 		case PrimaryAffinityKey:
 			storeConfig.PrimaryAffinity = v
+		case CompressionModeKey:
+			storeConfig.CompressionMode = v
+		case CompressionAlgorithmKey:
+			storeConfig.CompressionAlgorithm = v
 		}
 	}
 
@@ -34,9 +34,11 @@ import (
 type createConfig struct {
 	cluster                  *Cluster
 	provisionConfig          *provisionConfig
-	awaitingStatusConfigMaps sets.String    // These status configmaps were created for OSD prepare jobs
-	finishedStatusConfigMaps sets.String    // Status configmaps are added here as provisioning is completed for them
-	deployments              *existenceList // these OSDs have existing deployments
+	awaitingStatusConfigMaps sets.String                     // These status configmaps were created for OSD prepare jobs
+	finishedStatusConfigMaps sets.String                     // Status configmaps are added here as provisioning is completed for them
+	deployments              *existenceList                  // these OSDs have existing deployments
+	plans                    map[string]*OrchestrationStatus // the dry-run results of the prepare jobs by node or PVC
+	capacity                 *capacityGuard                  // projects the rebalance to the new OSDs
 }
 
 // allow overriding these functions for unit tests
@@ -61,6 +63,8 @@ func (c *Cluster) newCreateConfig(
 		awaitingStatusConfigMaps,
 		sets.NewString(),
 		deployments,
+		map[string]*OrchestrationStatus{},
+		&capacityGuard{},
 	}
 }
 
@@ -89,6 +93,18 @@ func (c *createConfig) createNewOSDsFromStatus(
 		return
 	}
 
+	if status.DryRun {
+		logger.Infof("OSD prepare job on %q ran in dry-run mode, %d device(s) would be consumed", nodeOrPVCName, len(status.Plan))
+		c.plans[nodeOrPVCName] = status
+		c.doneWithStatus(nodeOrPVCName)
+		return
+	}
+
+	if !c.allowNewOSDs(status, nodeOrPVCName) {
+		c.doneWithStatus(nodeOrPVCName)
+		return
+	}
+
 	for _, osd := range status.OSDs {
 		if c.deployments.Exists(osd.ID) {
 			// This OSD will be handled by the updater
@@ -188,6 +204,7 @@ func (c *Cluster) startProvisioningOverPVCs(config *provisionConfig, errs *provi
 			schedulerName:    volume.SchedulerName,
 			encrypted:        volume.Encrypted,
 			deviceSetName:    volume.Name,
+			replaceOSDs:      c.replacementsOn(dataSource.ClaimName),
 		}
 		osdProps.storeConfig.DeviceClass = volume.CrushDeviceClass
 
@@ -212,10 +229,10 @@ func (c *Cluster) startProvisioningOverPVCs(config *provisionConfig, errs *provi
 
 			// We could set an env var in the Operator or a global var instead of the API call?
 			// Hopefully, the API is cheap and we can always retrieve the token if it has changed...
-			if c.spec.Security.KeyManagementService.IsTokenAuthEnabled() {
-				err := kms.SetTokenToEnvVar(c.context, c.spec.Security.KeyManagementService.TokenSecretName, kmsConfig.Provider, c.clusterInfo.Namespace)
+			if c.spec.Security.KeyManagementService.IsTokenAuthEnabled() || c.spec.Security.KeyManagementService.IsK8sAuthEnabled() {
+				err := kms.SetTokenToEnvVar(c.context, &c.spec.Security.KeyManagementService, kmsConfig.Provider, c.clusterInfo.Namespace)
 				if err != nil {
-					errMsg := fmt.Sprintf("failed to fetch kms token secret %q. %v", c.spec.Security.KeyManagementService.TokenSecretName, err)
+					errMsg := fmt.Sprintf("failed to get the kms token. %v", err)
 					errs.addError(errMsg)
 					continue
 				}
@@ -240,14 +257,14 @@ func (c *Cluster) startProvisioningOverPVCs(config *provisionConfig, errs *provi
 		status := OrchestrationStatus{Status: OrchestrationStatusStarting, PvcBackedOSD: true}
 		cmName := c.updateOSDStatus(osdProps.crushHostname, status)
 
-		if err := c.runPrepareJob(&osdProps, config); err != nil {
+		if err := c.prepareScheduler.schedule(&osdProps, config); err != nil {
 			c.handleOrchestrationFailure(errs, osdProps.crushHostname, "%v", err)
 			c.deleteStatusConfigMap(osdProps.crushHostname)
 			continue // do not record the status CM's name
 		}
 
 		// record the name of the status configmap that will eventually receive results from the
-		// OSD provisioning job we just created. This will help us determine when we are done
+		// OSD provisioning job we just created or queued. This will help us determine when we are done
 		// processing the results of provisioning jobs.
 		awaitingStatusConfigMaps.Insert(cmName)
 	}
@@ -306,6 +323,11 @@ func (c *Cluster) startProvisioningOverNodes(config *provisionConfig, errs *prov
 		return sets.NewString(), nil
 	}
 
+	if err := c.spec.Storage.ValidateDriveGroups(); err != nil {
+		errs.addError("failed to provision OSDs on nodes. invalid drive groups. %v", err)
+		return sets.NewString(), nil
+	}
+
 	awaitingStatusConfigMaps := sets.NewString()
 	for _, node := range c.ValidStorage.Nodes {
 		if c.clusterInfo.Context.Err() != nil {
@@ -327,27 +349,39 @@ func (c *Cluster) startProvisioningOverNodes(config *provisionConfig, errs *prov
 		// create the job that prepares osds on the node
 		storeConfig := osdconfig.ToStoreConfig(n.Config)
 		metadataDevice := osdconfig.MetadataDevice(n.Config)
+		driveGroups, err := c.nodeDriveGroups(n)
+		if err != nil {
+			c.handleOrchestrationFailure(errs, n.Name, "failed to find the drive groups of node %q. %v", n.Name, err)
+			continue
+		}
 		osdProps := osdProperties{
 			crushHostname:  n.Name,
 			devices:        n.Devices,
+			driveGroups:    driveGroups,
 			selection:      n.Selection,
 			resources:      n.Resources,
 			storeConfig:    storeConfig,
 			metadataDevice: metadataDevice,
+			replaceOSDs:    c.replacementsOn(n.Name),
+		}
+
+		if c.prepareScheduler.skip(n.Name) {
+			logger.Infof("skipping OSD prepare job for node %q, it completed before the provisioning was interrupted", n.Name)
+			continue
 		}
 
 		// update the orchestration status of this node to the starting state
 		status := OrchestrationStatus{Status: OrchestrationStatusStarting}
 		cmName := c.updateOSDStatus(n.Name, status)
 
-		if err := c.runPrepareJob(&osdProps, config); err != nil {
+		if err := c.prepareScheduler.schedule(&osdProps, config); err != nil {
 			c.handleOrchestrationFailure(errs, n.Name, "%v", err)
 			c.deleteStatusConfigMap(n.Name)
 			continue // do not record the status CM's name
 		}
 
 		// record the name of the status configmap that will eventually receive results from the
-		// OSD provisioning job we just created. This will help us determine when we are done
+		// OSD provisioning job we just created or queued. This will help us determine when we are done
 		// processing the results of provisioning jobs.
 		awaitingStatusConfigMaps.Insert(cmName)
 	}
@@ -355,6 +389,33 @@ func (c *Cluster) startProvisioningOverNodes(config *provisionConfig, errs *prov
 	return awaitingStatusConfigMaps, nil
 }
 
+// nodeDriveGroups returns the drive groups of the storage spec whose node affinity matches the node
+func (c *Cluster) nodeDriveGroups(n *cephv1.Node) ([]cephv1.DriveGroup, error) {
+	if len(c.spec.Storage.DriveGroups) == 0 {
+		return nil, nil
+	}
+
+	k8sNodes, err := k8sutil.GetKubernetesNodesMatchingRookNodes([]cephv1.Node{*n}, c.context.Clientset)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get kubernetes node %q", n.Name)
+	}
+	if len(k8sNodes) == 0 {
+		return nil, errors.Errorf("kubernetes node %q not found", n.Name)
+	}
+
+	driveGroups := []cephv1.DriveGroup{}
+	for _, driveGroup := range c.spec.Storage.DriveGroups {
+		matched, err := k8sutil.NodeMeetsAffinityTerms(k8sNodes[0], driveGroup.NodeAffinity)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to match the node affinity of drive group %q", driveGroup.Name)
+		}
+		if matched {
+			driveGroups = append(driveGroups, driveGroup)
+		}
+	}
+	return driveGroups, nil
+}
+
 func (c *Cluster) runPrepareJob(osdProps *osdProperties, config *provisionConfig) error {
 	nodeOrPVC := "node"
 	if osdProps.onPVC() {
@@ -388,6 +449,18 @@ func createDaemonOnPVC(c *Cluster, osd OSDInfo, pvcName string, config *provisio
 	message := fmt.Sprintf("Processing OSD %d on PVC %q", osd.ID, pvcName)
 	updateConditionFunc(c.context, c.clusterInfo.NamespacedName(), cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, message)
 
+	// An OSD started in a pod set keeps running in its pod set, even if the pod set is disabled later
+	pod, err := c.getOSDPod(osd.ID)
+	if err != nil {
+		return err
+	}
+	if pod != nil {
+		return c.reconcileOSDPod(d, pod.Labels[OSDPodSetLabelKey], pod)
+	}
+	if deviceSetName, podSet := c.podSetOfPVC(pvcName); podSet {
+		return c.reconcileOSDPod(d, deviceSetName, nil)
+	}
+
 	_, err = k8sutil.CreateDeployment(c.context.Clientset, d)
 	return errors.Wrapf(err, "failed to create deployment for OSD %d on PVC %q", osd.ID, pvcName)
 }
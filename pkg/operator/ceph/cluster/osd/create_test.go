@@ -26,6 +26,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -132,6 +133,20 @@ func Test_createNewOSDsFromStatus(t *testing.T) {
 		assert.True(t, createConfig.finishedStatusConfigMaps.Has(statusNameNode0))
 	})
 
+	t.Run("node: create no OSDs and keep the plan of a dry-run", func(t *testing.T) {
+		doSetup()
+		status = &OrchestrationStatus{
+			OSDs:   []OSDInfo{{ID: 0}},
+			DryRun: true,
+			Plan:   []PlannedDevice{{Name: "/dev/sdb", Role: "data"}},
+		}
+		createConfig.createNewOSDsFromStatus(status, "node0", errs)
+		assert.Zero(t, errs.len())
+		assert.Len(t, createCallsOnNode, 0)
+		assert.Equal(t, status, createConfig.plans["node0"])
+		assert.True(t, createConfig.finishedStatusConfigMaps.Has(statusNameNode0))
+	})
+
 	t.Run("test: node: create all OSDs on node when all do not exist", func(t *testing.T) {
 		doSetup()
 		status = &OrchestrationStatus{
@@ -446,6 +461,55 @@ func Test_startProvisioningOverPVCs(t *testing.T) {
 		assert.Len(t, cms.Items, 0)
 	})
 
+	t.Run("no prepare job for the PVC of an OSD of a pod set", func(t *testing.T) {
+		spec = cephv1.ClusterSpec{
+			Storage: cephv1.StorageScopeSpec{
+				StorageClassDeviceSets: []cephv1.StorageClassDeviceSet{
+					{
+						Name:   "set1",
+						Count:  1,
+						PodSet: true,
+						VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+							newDummyPVC("data", namespace, "10Gi", "gp2"),
+						},
+					},
+				},
+			},
+		}
+		clientset = test.NewComplexClientset(t) // reset to empty fake k8s environment
+		doSetup()
+		awaitingStatusConfigMaps, err = c.startProvisioningOverPVCs(config, errs)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, awaitingStatusConfigMaps.Len())
+		c.deleteAllStatusConfigMaps()
+
+		pvcs, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, pvcs.Items, 1)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rook-ceph-osd-0",
+				Namespace: namespace,
+				Labels: map[string]string{
+					k8sutil.AppAttr:    AppName,
+					OSDPodSetLabelKey:  "set1",
+					OSDOverPVCLabelKey: pvcs.Items[0].Name,
+				},
+			},
+		}
+		_, err = clientset.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		doSetup()
+		awaitingStatusConfigMaps, err = c.startProvisioningOverPVCs(config, errs)
+		assert.NoError(t, err)
+		assert.Zero(t, awaitingStatusConfigMaps.Len())
+		assert.Zero(t, errs.len())
+		jobs, err := clientset.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, jobs.Items, 1) // only the job of the first reconcile
+	})
+
 	// TODO: should we verify the osdProps set on the job?
 }
 
@@ -604,6 +668,51 @@ func Test_startProvisioningOverNodes(t *testing.T) {
 		assert.Zero(t, prepareJobsRun.Len())
 	})
 
+	t.Run("drive groups of the nodes", func(t *testing.T) {
+		spec = cephv1.ClusterSpec{
+			Storage: cephv1.StorageScopeSpec{
+				UseAllNodes: false,
+				Nodes:       []cephv1.Node{{Name: "node0"}, {Name: "node2"}},
+				DriveGroups: []cephv1.DriveGroup{
+					{Name: "all-nodes"},
+					{Name: "node2-only", NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{"node2"}},
+							}}},
+						},
+					}},
+				},
+			},
+			DataDirHostPath: dataDirHostPath,
+		}
+		doSetup()
+		driveGroups, err := c.nodeDriveGroups(&cephv1.Node{Name: "node0"})
+		assert.NoError(t, err)
+		assert.Equal(t, []cephv1.DriveGroup{spec.Storage.DriveGroups[0]}, driveGroups)
+		driveGroups, err = c.nodeDriveGroups(&cephv1.Node{Name: "node2"})
+		assert.NoError(t, err)
+		assert.Equal(t, spec.Storage.DriveGroups, driveGroups)
+		_, err = c.nodeDriveGroups(&cephv1.Node{Name: "node3"})
+		assert.Error(t, err)
+
+		prepareJobsRun, err = c.startProvisioningOverNodes(config, errs)
+		assert.NoError(t, err)
+		assert.Zero(t, errs.len())
+		assert.ElementsMatch(t,
+			[]string{statusNameNode0, statusNameNode2},
+			prepareJobsRun.List(),
+		)
+
+		// invalid drive groups fail the provisioning of all the nodes
+		spec.Storage.DriveGroups[1].Name = "all-nodes"
+		doSetup()
+		prepareJobsRun, err = c.startProvisioningOverNodes(config, errs)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, errs.len())
+		assert.Zero(t, prepareJobsRun.Len())
+	})
+
 	t.Run("failures running prepare jobs", func(t *testing.T) {
 		spec = cephv1.ClusterSpec{
 			Storage: cephv1.StorageScopeSpec{
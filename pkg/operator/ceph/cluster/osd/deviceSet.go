@@ -64,6 +64,11 @@ type deviceSet struct {
 	SchedulerName string
 	// Whether to encrypt the deviceSet
 	Encrypted bool
+	// Whether the new OSDs of the deviceSet run in pods managed by the operator
+	PodSet bool
+	// Bluestore compression mode and algorithm of the OSDs
+	CompressionMode      string
+	CompressionAlgorithm string
 }
 
 func (c *Cluster) prepareStorageClassDeviceSets(errs *provisionErrors) {
@@ -86,6 +91,11 @@ func (c *Cluster) prepareStorageClassDeviceSets(errs *provisionErrors) {
 			errs.addError("failed to provision OSDs on PVC for storageClassDeviceSet %q. no volumeClaimTemplate is specified. user must specify a volumeClaimTemplate", deviceSet.Name)
 			continue
 		}
+		// The disruption budgets with maxUnavailable do not allow evicting the pods without controller
+		if deviceSet.PodSet && c.spec.DisruptionManagement.ManagePodBudgets {
+			errs.addError("failed to provision OSDs on PVC for storageClassDeviceSet %q. the OSDs of a pod set require disabling managePodBudgets", deviceSet.Name)
+			continue
+		}
 
 		// Iterate through existing PVCs to ensure they are up-to-date, no metadata pvcs are missing, etc
 		highestExistingID := -1
@@ -141,6 +151,12 @@ func (c *Cluster) createDeviceSetPVCsForIndex(newDeviceSet cephv1.StorageClassDe
 			continue
 		}
 		typesFound.Insert(pvcTemplate.Name)
+		// a template with another name would get a PVC the OSD never uses
+		if len(newDeviceSet.VolumeClaimTemplates) > 1 && !isBluestorePVCType(pvcTemplate.Name) {
+			errs.addError("invalid volume claim template %q for device set %q. the templates of a device set with several templates must be named %q, %q or %q",
+				pvcTemplate.Name, newDeviceSet.Name, bluestorePVCData, bluestorePVCMetadata, bluestorePVCWal)
+			continue
+		}
 
 		pvc, err := c.createDeviceSetPVC(existingPVCs, newDeviceSet.Name, pvcTemplate, setIndex)
 		if err != nil {
@@ -155,13 +171,14 @@ func (c *Cluster) createDeviceSetPVCsForIndex(newDeviceSet cephv1.StorageClassDe
 			pvcType = bluestorePVCData
 		}
 
+		// the crush settings of the OSD come from the data template, the metadata and wal templates
+		// usually have another storage class
 		if pvcType == bluestorePVCData {
-			pvcSize := pvc.Spec.Resources.Requests[v1.ResourceStorage]
-			dataSize = pvcSize.String()
+			dataSize = osdPVCSize(pvc)
 			crushDeviceClass = pvcTemplate.Annotations["crushDeviceClass"]
+			crushInitialWeight = pvcTemplate.Annotations["crushInitialWeight"]
+			crushPrimaryAffinity = pvcTemplate.Annotations["crushPrimaryAffinity"]
 		}
-		crushInitialWeight = pvcTemplate.Annotations["crushInitialWeight"]
-		crushPrimaryAffinity = pvcTemplate.Annotations["crushPrimaryAffinity"]
 
 		pvcSources[pvcType] = v1.PersistentVolumeClaimVolumeSource{
 			ClaimName: pvc.GetName(),
@@ -185,9 +202,16 @@ func (c *Cluster) createDeviceSetPVCsForIndex(newDeviceSet cephv1.StorageClassDe
 		CrushInitialWeight:   crushInitialWeight,
 		CrushPrimaryAffinity: crushPrimaryAffinity,
 		Encrypted:            newDeviceSet.Encrypted,
+		PodSet:               newDeviceSet.PodSet,
+		CompressionMode:      newDeviceSet.CompressionMode,
+		CompressionAlgorithm: newDeviceSet.CompressionAlgorithm,
 	}
 }
 
+func isBluestorePVCType(name string) bool {
+	return name == bluestorePVCData || name == bluestorePVCMetadata || name == bluestorePVCWal
+}
+
 func (c *Cluster) createDeviceSetPVC(existingPVCs map[string]*v1.PersistentVolumeClaim, deviceSetName string, pvcTemplate v1.PersistentVolumeClaim, setIndex int) (*v1.PersistentVolumeClaim, error) {
 	// old labels and PVC ID for backward compatibility
 	pvcID := legacyDeviceSetPVCID(deviceSetName, setIndex)
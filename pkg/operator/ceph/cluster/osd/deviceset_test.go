@@ -270,3 +270,72 @@ func TestPrepareDeviceSetsWithCrushParams(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(pvcs.Items))
 }
+
+func TestPrepareDeviceSetsWithMetadataAndWal(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testexec.New(t, 1)
+	context := &clusterd.Context{
+		Clientset: clientset,
+	}
+	fastClass := "fast"
+	metadataClaim := testVolumeClaim("metadata")
+	metadataClaim.Spec.StorageClassName = &fastClass
+	metadataClaim.Annotations = map[string]string{"crushInitialWeight": "0.1"}
+	walClaim := testVolumeClaim("wal")
+	walClaim.Spec.StorageClassName = &fastClass
+	dataClaim := testVolumeClaim("data")
+	dataClaim.Annotations = map[string]string{"crushDeviceClass": "hdd", "crushInitialWeight": "0.75"}
+	deviceSet := cephv1.StorageClassDeviceSet{
+		Name:                 "mixed",
+		Count:                1,
+		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{dataClaim, metadataClaim, walClaim},
+	}
+
+	spec := cephv1.ClusterSpec{
+		Storage: cephv1.StorageScopeSpec{StorageClassDeviceSets: []cephv1.StorageClassDeviceSet{deviceSet}},
+	}
+	cluster := &Cluster{
+		context:     context,
+		clusterInfo: client.AdminClusterInfo("testns"),
+		spec:        spec,
+	}
+	// the PVCs of a device set index differ by their generated name
+	clientset.PrependReactor("create", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pvc := action.(k8stesting.CreateAction).GetObject().(*corev1.PersistentVolumeClaim)
+		pvc.Name = pvc.GenerateName
+		return false, nil, nil
+	})
+
+	errs := newProvisionErrors()
+	cluster.prepareStorageClassDeviceSets(errs)
+	assert.Zero(t, errs.len())
+	assert.Equal(t, 1, len(cluster.deviceSets))
+	assert.Len(t, cluster.deviceSets[0].PVCSources, 3)
+	assert.Contains(t, cluster.deviceSets[0].PVCSources, bluestorePVCMetadata)
+	assert.Contains(t, cluster.deviceSets[0].PVCSources, bluestorePVCWal)
+	// the crush settings come from the data template
+	assert.Equal(t, "hdd", cluster.deviceSets[0].CrushDeviceClass)
+	assert.Equal(t, "0.75", cluster.deviceSets[0].CrushInitialWeight)
+
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(cluster.clusterInfo.Namespace).List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(pvcs.Items))
+	for _, pvc := range pvcs.Items {
+		if pvc.Labels[CephDeviceSetPVCIDLabelKey] == "mixed-data-0" {
+			assert.Equal(t, "mysource", *pvc.Spec.StorageClassName)
+		} else {
+			assert.Equal(t, fastClass, *pvc.Spec.StorageClassName)
+		}
+	}
+
+	// a template with another name is not created when the device set has several templates
+	cluster.spec.Storage.StorageClassDeviceSets[0].Name = "unknown"
+	cluster.spec.Storage.StorageClassDeviceSets[0].VolumeClaimTemplates = []corev1.PersistentVolumeClaim{dataClaim, testVolumeClaim("journal")}
+	cluster.deviceSets = nil
+	errs = newProvisionErrors()
+	cluster.prepareStorageClassDeviceSets(errs)
+	assert.Equal(t, 1, errs.len())
+	pvcs, err = clientset.CoreV1().PersistentVolumeClaims(cluster.clusterInfo.Namespace).List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(pvcs.Items))
+}
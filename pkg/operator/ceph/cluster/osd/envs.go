@@ -17,6 +17,7 @@ limitations under the License.
 package osd
 
 import (
+	"os"
 	"strconv"
 
 	"github.com/rook/rook/pkg/daemon/ceph/client"
@@ -113,10 +114,32 @@ func nodeNameEnvVar(name string) v1.EnvVar {
 	return v1.EnvVar{Name: "ROOK_NODE_NAME", Value: name}
 }
 
+// provisioningLeaseNamespaceEnvVar is the namespace of the provisioning leases of the nodes, the namespace of the
+// operator where the discover daemon runs
+func provisioningLeaseNamespaceEnvVar() v1.EnvVar {
+	return v1.EnvVar{Name: k8sutil.ProvisioningLeaseNamespaceEnvVar, Value: os.Getenv(k8sutil.PodNamespaceEnvVar)}
+}
+
 func dataDevicesEnvVar(dataDevices string) v1.EnvVar {
 	return v1.EnvVar{Name: "ROOK_DATA_DEVICES", Value: dataDevices}
 }
 
+func driveGroupsEnvVar(driveGroups string) v1.EnvVar {
+	return v1.EnvVar{Name: "ROOK_DRIVE_GROUPS", Value: driveGroups}
+}
+
+func replaceOSDsEnvVar(replacements string) v1.EnvVar {
+	return v1.EnvVar{Name: "ROOK_REPLACE_OSDS", Value: replacements}
+}
+
+func dryRunEnvVar() v1.EnvVar {
+	return v1.EnvVar{Name: "ROOK_DRY_RUN", Value: "true"}
+}
+
+func topologyLabelsEnvVar(topologyLabels string) v1.EnvVar {
+	return v1.EnvVar{Name: "ROOK_TOPOLOGY_LABELS", Value: topologyLabels}
+}
+
 func deviceFilterEnvVar(filter string) v1.EnvVar {
 	return v1.EnvVar{Name: "ROOK_DATA_DEVICE_FILTER", Value: filter}
 }
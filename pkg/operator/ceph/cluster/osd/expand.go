@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const osdPVCSizeEnvVarName = "ROOK_OSD_PVC_SIZE"
+
+// osdPVCSize returns the size of the data PVC of an OSD. The size is the capacity of the volume once it is bound,
+// so the OSD is only restarted to expand bluefs once the volume was actually resized, and not as soon as a bigger
+// size is requested.
+func osdPVCSize(pvc *v1.PersistentVolumeClaim) string {
+	if capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]; ok && !capacity.IsZero() {
+		return capacity.String()
+	}
+	request := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	return request.String()
+}
+
+// osdDeploymentPVCSize returns the size of the data PVC the OSD of the deployment was started with
+func osdDeploymentPVCSize(d *appsv1.Deployment) (resource.Quantity, bool) {
+	for _, container := range d.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name != osdPVCSizeEnvVarName {
+				continue
+			}
+			size, err := resource.ParseQuantity(env.Value)
+			if err != nil {
+				return resource.Quantity{}, false
+			}
+			return size, true
+		}
+	}
+	return resource.Quantity{}, false
+}
+
+// checkOSDPVCExpansion restarts the OSDs whose data PVC was resized since they started, so their expand-bluefs
+// init container expands bluefs to the new size of the volume. The PVCs are resized between two reconciles of
+// the cluster, when the storage provider completes their expansion. A single OSD is restarted at a time, once
+// all the OSDs are running, the PGs are clean and the OSD is ok to stop.
+func (m *OSDHealthMonitor) checkOSDPVCExpansion() error {
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s,%s", k8sutil.AppAttr, AppName, OSDOverPVCLabelKey)}
+	deployments, err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).List(m.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osd deployments on pvc")
+	}
+	sort.Slice(deployments.Items, func(i, j int) bool { return deployments.Items[i].Name < deployments.Items[j].Name })
+
+	var toExpand *appsv1.Deployment
+	var newSize string
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if d.Status.ObservedGeneration < d.Generation || d.Status.ReadyReplicas < 1 {
+			// another osd is still restarting, or is down and must not be stopped with the next one
+			logger.Debugf("osd deployment %q is not ready, not expanding any osd", d.Name)
+			return nil
+		}
+		if toExpand != nil {
+			continue
+		}
+		currentSize, ok := osdDeploymentPVCSize(d)
+		if !ok {
+			continue
+		}
+		pvcName := d.Labels[OSDOverPVCLabelKey]
+		pvc, err := m.context.Clientset.CoreV1().PersistentVolumeClaims(m.clusterInfo.Namespace).Get(m.clusterInfo.Context, pvcName, metav1.GetOptions{})
+		if err != nil {
+			logger.Warningf("failed to get pvc %q of osd deployment %q. %v", pvcName, d.Name, err)
+			continue
+		}
+		capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]
+		if !ok || capacity.Cmp(currentSize) <= 0 {
+			continue
+		}
+		toExpand = d
+		newSize = osdPVCSize(pvc)
+	}
+	if toExpand == nil {
+		return nil
+	}
+
+	osdID, err := getOSDID(toExpand)
+	if err != nil {
+		return err
+	}
+	msg, clean, err := isClusterCleanFunc(m.context, m.clusterInfo)
+	if err != nil || !clean {
+		logger.Infof("waiting for the pgs to be clean before expanding osd %d to %s. %s", osdID, newSize, msg)
+		return nil
+	}
+	if shouldCheckOkToStopFunc(m.context, m.clusterInfo) {
+		if _, err := client.OSDOkToStop(m.context, m.clusterInfo, osdID, 1); err != nil {
+			logger.Infof("osd %d is not ok-to-stop, will try expanding it again later. %v", osdID, err)
+			return nil
+		}
+	}
+
+	for i := range toExpand.Spec.Template.Spec.Containers {
+		container := &toExpand.Spec.Template.Spec.Containers[i]
+		for j := range container.Env {
+			if container.Env[j].Name == osdPVCSizeEnvVarName {
+				container.Env[j].Value = newSize
+			}
+		}
+	}
+	logger.Infof("restarting osd %d to expand it to the new size %s of pvc %q", osdID, newSize, toExpand.Labels[OSDOverPVCLabelKey])
+	if _, err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).Update(m.clusterInfo.Context, toExpand, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update osd deployment %q to expand osd %d", toExpand.Name, osdID)
+	}
+	return nil
+}
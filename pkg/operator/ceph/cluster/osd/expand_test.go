@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testexec "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOSDPVCSize(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}},
+		},
+	}
+	// the requested size until the volume is bound
+	assert.Equal(t, "20Gi", osdPVCSize(pvc))
+
+	// the capacity of the volume while it is being resized
+	pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+	assert.Equal(t, "10Gi", osdPVCSize(pvc))
+}
+
+func TestCheckOSDPVCExpansion(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testexec.New(t, 1)
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	m := NewOSDHealthMonitor(&clusterd.Context{Clientset: clientset}, clusterInfo, false, nil, cephv1.CephClusterHealthCheckSpec{})
+
+	oldShouldCheckFunc := shouldCheckOkToStopFunc
+	oldIsClusterCleanFunc := isClusterCleanFunc
+	defer func() {
+		shouldCheckOkToStopFunc = oldShouldCheckFunc
+		isClusterCleanFunc = oldIsClusterCleanFunc
+	}()
+	shouldCheckOkToStopFunc = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) bool {
+		return false
+	}
+	clean := false
+	isClusterCleanFunc = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) (string, bool, error) {
+		return "pgs are not clean", clean, nil
+	}
+
+	for _, id := range []string{"0", "1"} {
+		pvcName := "set1-data-" + id
+		labels := map[string]string{k8sutil.AppAttr: AppName, OsdIdLabelKey: id, OSDOverPVCLabelKey: pvcName}
+		d := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-" + id, Namespace: "ns", Labels: labels},
+			Spec: apps.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "osd", Env: []corev1.EnvVar{{Name: osdPVCSizeEnvVarName, Value: "10Gi"}}}},
+					},
+				},
+			},
+			Status: apps.DeploymentStatus{ReadyReplicas: 1},
+		}
+		_, err := clientset.AppsV1().Deployments("ns").Create(ctx, d, metav1.CreateOptions{})
+		require.NoError(t, err)
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: "ns"},
+			Status:     corev1.PersistentVolumeClaimStatus{Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}},
+		}
+		_, err = clientset.CoreV1().PersistentVolumeClaims("ns").Create(ctx, pvc, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	getPVCSize := func(name string) string {
+		d, err := clientset.AppsV1().Deployments("ns").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		size, ok := osdDeploymentPVCSize(d)
+		require.True(t, ok)
+		return size.String()
+	}
+
+	// no osd is restarted while the pvcs keep their size
+	assert.NoError(t, m.checkOSDPVCExpansion())
+	assert.Equal(t, "10Gi", getPVCSize("rook-ceph-osd-0"))
+
+	// the pvc of osd 1 was resized, the osd waits for the pgs to be clean
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims("ns").Get(ctx, "set1-data-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	pvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("20Gi")
+	_, err = clientset.CoreV1().PersistentVolumeClaims("ns").Update(ctx, pvc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.NoError(t, m.checkOSDPVCExpansion())
+	assert.Equal(t, "10Gi", getPVCSize("rook-ceph-osd-1"))
+
+	clean = true
+	assert.NoError(t, m.checkOSDPVCExpansion())
+	assert.Equal(t, "20Gi", getPVCSize("rook-ceph-osd-1"))
+	assert.Equal(t, "10Gi", getPVCSize("rook-ceph-osd-0"))
+
+	// no other osd is restarted while an osd is not ready
+	pvc, err = clientset.CoreV1().PersistentVolumeClaims("ns").Get(ctx, "set1-data-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	pvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("20Gi")
+	_, err = clientset.CoreV1().PersistentVolumeClaims("ns").Update(ctx, pvc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	d, err := clientset.AppsV1().Deployments("ns").Get(ctx, "rook-ceph-osd-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	d.Status.ReadyReplicas = 0
+	_, err = clientset.AppsV1().Deployments("ns").Update(ctx, d, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.NoError(t, m.checkOSDPVCExpansion())
+	assert.Equal(t, "10Gi", getPVCSize("rook-ceph-osd-0"))
+}
@@ -28,13 +28,19 @@ import (
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	upStatus  = 1
 	inStatus  = 1
 	graceTime = 60 * time.Minute
+
+	defaultAutoRemoveOSDGracePeriod = 24 * time.Hour
+	defaultOSDMaxFlaps              = 5
+	defaultOSDFlappingWindow        = time.Hour
 )
 
 var (
@@ -47,15 +53,29 @@ type OSDHealthMonitor struct {
 	clusterInfo                    *client.ClusterInfo
 	removeOSDsIfOUTAndSafeToRemove bool
 	interval                       *time.Duration
+	autoRemoveOSD                  *cephv1.AutoRemoveOSDSpec
+	// downAndOutSince is when the OSDs were first seen down and out
+	downAndOutSince map[int]time.Time
+	osdFlapping     *cephv1.OSDFlappingSpec
+	// upFrom is the epoch the OSDs last came up, and flaps when they were seen coming up again
+	upFrom map[int]int64
+	flaps  map[int][]time.Time
+	// podSetPods are the pods of the OSD pod sets found at the last check, by name
+	podSetPods map[string]*v1.Pod
+	// ownedOSDsOnly limits the OSDs marked out or removed to the OSDs running in the namespace, when the OSDs
+	// are contributed to an external cluster whose other OSDs are managed elsewhere
+	ownedOSDsOnly bool
 }
 
 // NewOSDHealthMonitor instantiates OSD monitoring
-func NewOSDHealthMonitor(context *clusterd.Context, clusterInfo *client.ClusterInfo, removeOSDsIfOUTAndSafeToRemove bool, healthCheck cephv1.CephClusterHealthCheckSpec) *OSDHealthMonitor {
+func NewOSDHealthMonitor(context *clusterd.Context, clusterInfo *client.ClusterInfo, removeOSDsIfOUTAndSafeToRemove bool, autoRemoveOSD *cephv1.AutoRemoveOSDSpec, healthCheck cephv1.CephClusterHealthCheckSpec) *OSDHealthMonitor {
 	h := &OSDHealthMonitor{
 		context:                        context,
 		clusterInfo:                    clusterInfo,
 		removeOSDsIfOUTAndSafeToRemove: removeOSDsIfOUTAndSafeToRemove,
 		interval:                       &defaultHealthCheckInterval,
+		autoRemoveOSD:                  autoRemoveOSD,
+		osdFlapping:                    healthCheck.OSDFlapping,
 	}
 
 	// allow overriding the check interval
@@ -84,9 +104,17 @@ func (m *OSDHealthMonitor) Start(context context.Context) {
 	}
 }
 
-// Update updates the removeOSDsIfOUTAndSafeToRemove
-func (m *OSDHealthMonitor) Update(removeOSDsIfOUTAndSafeToRemove bool) {
+// Update updates the removeOSDsIfOUTAndSafeToRemove, the automatic removal of the OSDs and the detection of the
+// flapping OSDs
+func (m *OSDHealthMonitor) Update(removeOSDsIfOUTAndSafeToRemove bool, autoRemoveOSD *cephv1.AutoRemoveOSDSpec, osdFlapping *cephv1.OSDFlappingSpec) {
 	m.removeOSDsIfOUTAndSafeToRemove = removeOSDsIfOUTAndSafeToRemove
+	m.autoRemoveOSD = autoRemoveOSD
+	m.osdFlapping = osdFlapping
+}
+
+// OwnedOSDsOnly limits the monitor to the OSDs running in the namespace of the cluster
+func (m *OSDHealthMonitor) OwnedOSDsOnly() {
+	m.ownedOSDsOnly = true
 }
 
 // checkOSDHealth takes action when needed if the OSDs are not healthy
@@ -99,6 +127,18 @@ func (m *OSDHealthMonitor) checkOSDHealth() {
 	if err != nil {
 		logger.Debugf("failed to check device classes. %v", err)
 	}
+	err = m.checkOSDInventory()
+	if err != nil {
+		logger.Debugf("failed to check the status of the osds. %v", err)
+	}
+	err = m.checkOSDPodSets()
+	if err != nil {
+		logger.Warningf("failed to check the osd pod sets. %v", err)
+	}
+	err = m.checkOSDPVCExpansion()
+	if err != nil {
+		logger.Warningf("failed to check the expansion of the osd pvcs. %v", err)
+	}
 }
 
 func (m *OSDHealthMonitor) checkDeviceClasses() error {
@@ -119,7 +159,13 @@ func (m *OSDHealthMonitor) checkOSDDump() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to get osd dump")
 	}
+	if m.ownedOSDsOnly {
+		if err := m.filterOwnedOSDs(osdDump); err != nil {
+			return err
+		}
+	}
 
+	var downAndOutOSDs []int
 	for _, osdStatus := range osdDump.OSDs {
 		id64, err := osdStatus.OSD.Int64()
 		if err != nil {
@@ -143,6 +189,7 @@ func (m *OSDHealthMonitor) checkOSDDump() error {
 
 		if in != inStatus {
 			logger.Debugf("osd.%d is marked 'OUT'", id)
+			downAndOutOSDs = append(downAndOutOSDs, id)
 			if m.removeOSDsIfOUTAndSafeToRemove {
 				if err := m.removeOSDDeploymentIfSafeToDestroy(id); err != nil {
 					logger.Errorf("error handling marked out osd osd.%d. %v", id, err)
@@ -151,6 +198,229 @@ func (m *OSDHealthMonitor) checkOSDDump() error {
 		}
 	}
 
+	if err := m.markOutFlappingOSDs(osdDump); err != nil {
+		logger.Errorf("failed to mark out the flapping osds. %v", err)
+	}
+	return m.autoRemoveOSDs(downAndOutOSDs)
+}
+
+// filterOwnedOSDs removes from the osd dump the OSDs without a deployment or a pod in the namespace
+func (m *OSDHealthMonitor) filterOwnedOSDs(osdDump *client.OSDDump) error {
+	owned := map[string]bool{}
+	deployments, err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).List(m.clusterInfo.Context, metav1.ListOptions{LabelSelector: OsdIdLabelKey})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osd deployments")
+	}
+	for _, d := range deployments.Items {
+		owned[d.Labels[OsdIdLabelKey]] = true
+	}
+	podListOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s,%s", OSDPodSetLabelKey, OsdIdLabelKey)}
+	pods, err := m.context.Clientset.CoreV1().Pods(m.clusterInfo.Namespace).List(m.clusterInfo.Context, podListOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the pods of the osd pod sets")
+	}
+	for _, pod := range pods.Items {
+		owned[pod.Labels[OsdIdLabelKey]] = true
+	}
+
+	osds := osdDump.OSDs[:0]
+	for _, osdStatus := range osdDump.OSDs {
+		if owned[osdStatus.OSD.String()] {
+			osds = append(osds, osdStatus)
+		}
+	}
+	osdDump.OSDs = osds
+	return nil
+}
+
+// markOutFlappingOSDs marks out the OSDs that came up again maxFlaps times within the window. An OSD coming up
+// again is seen by a new up_from epoch in the osd map, so several flaps between two checks count as one.
+func (m *OSDHealthMonitor) markOutFlappingOSDs(osdDump *client.OSDDump) error {
+	if m.osdFlapping == nil || !m.osdFlapping.Enabled {
+		m.upFrom = nil
+		m.flaps = nil
+		return nil
+	}
+	maxFlaps := defaultOSDMaxFlaps
+	if m.osdFlapping.MaxFlaps > 0 {
+		maxFlaps = m.osdFlapping.MaxFlaps
+	}
+	window := defaultOSDFlappingWindow
+	if m.osdFlapping.Window != nil {
+		window = m.osdFlapping.Window.Duration
+	}
+
+	now := time.Now()
+	upFrom := map[int]int64{}
+	flaps := map[int][]time.Time{}
+	var flappingOSDs []int
+	for _, osdStatus := range osdDump.OSDs {
+		id64, err := osdStatus.OSD.Int64()
+		if err != nil {
+			continue
+		}
+		id := int(id64)
+		epoch, err := osdStatus.UpFrom.Int64()
+		if err != nil {
+			continue
+		}
+		upFrom[id] = epoch
+
+		// the flaps older than the window are forgotten
+		for _, flap := range m.flaps[id] {
+			if now.Sub(flap) < window {
+				flaps[id] = append(flaps[id], flap)
+			}
+		}
+		if previous, ok := m.upFrom[id]; ok && epoch > previous {
+			flaps[id] = append(flaps[id], now)
+		}
+		in, err := osdStatus.In.Int64()
+		if err == nil && in == inStatus && len(flaps[id]) >= maxFlaps {
+			flappingOSDs = append(flappingOSDs, id)
+		}
+	}
+	m.upFrom = upFrom
+	m.flaps = flaps
+
+	for _, id := range flappingOSDs {
+		reason := fmt.Sprintf("came up again %d times within %s", len(m.flaps[id]), window)
+		logger.Warningf("marking out osd.%d that %s", id, reason)
+		if _, err := client.OSDOut(m.context, m.clusterInfo, id); err != nil {
+			return errors.Wrapf(err, "failed to mark out flapping osd.%d", id)
+		}
+		delete(m.flaps, id)
+		target := fmt.Sprintf("osd.%d", id)
+		if err := reporting.RecordCorrectiveAction(m.clusterInfo.Context, m.context.Client, m.clusterInfo.NamespacedName(), cephv1.CorrectiveActionOSDMarkedOut, target, reason); err != nil {
+			logger.Warningf("failed to record the mark out of osd.%d. %v", id, err)
+		}
+	}
+	return nil
+}
+
+// autoRemoveOSDs purges an OSD that stayed down and out for longer than the grace period, once the placement groups
+// are clean. A single OSD is removed per check since removing an OSD from the crush map moves data.
+func (m *OSDHealthMonitor) autoRemoveOSDs(downAndOutOSDs []int) error {
+	if m.autoRemoveOSD == nil || !m.autoRemoveOSD.Enabled {
+		m.downAndOutSince = nil
+		return nil
+	}
+
+	// the OSDs that are up or in again are forgotten
+	now := time.Now()
+	downAndOutSince := map[int]time.Time{}
+	for _, id := range downAndOutOSDs {
+		since, ok := m.downAndOutSince[id]
+		if !ok {
+			since = now
+		}
+		downAndOutSince[id] = since
+	}
+	m.downAndOutSince = downAndOutSince
+
+	gracePeriod := defaultAutoRemoveOSDGracePeriod
+	if m.autoRemoveOSD.GracePeriod != nil {
+		gracePeriod = m.autoRemoveOSD.GracePeriod.Duration
+	}
+	for _, id := range downAndOutOSDs {
+		if now.Sub(m.downAndOutSince[id]) < gracePeriod {
+			logger.Debugf("osd.%d is down and out since %s, waiting for the grace period %s to remove it", id, m.downAndOutSince[id].Format(time.RFC3339), gracePeriod)
+			continue
+		}
+
+		msg, clean, err := client.IsClusterClean(m.context, m.clusterInfo)
+		if err != nil {
+			return errors.Wrap(err, "failed to check if the pgs are clean")
+		}
+		if !clean {
+			logger.Infof("waiting for the pgs to be clean to remove osd.%d that is down and out. %s", id, msg)
+			return nil
+		}
+		safeToDestroy, err := client.OsdSafeToDestroy(m.context, m.clusterInfo, id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if osd.%d is safe to destroy", id)
+		}
+		if !safeToDestroy {
+			logger.Infof("not removing osd.%d that is down and out since it is not safe to destroy", id)
+			continue
+		}
+
+		logger.Infof("removing osd.%d that is down and out for more than %s", id, gracePeriod)
+		if err := m.purgeOSD(id); err != nil {
+			return errors.Wrapf(err, "failed to remove osd.%d", id)
+		}
+		delete(m.downAndOutSince, id)
+		target := fmt.Sprintf("osd.%d", id)
+		if err := reporting.RecordCorrectiveAction(m.clusterInfo.Context, m.context.Client, m.clusterInfo.NamespacedName(), cephv1.CorrectiveActionOSDRemoval, target, fmt.Sprintf("down and out for more than %s", gracePeriod)); err != nil {
+			logger.Warningf("failed to record the removal of osd.%d. %v", id, err)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// purgeOSD deletes the deployment of the OSD and purges the OSD from the cluster. The PVC of an OSD on a PVC is kept
+// but detached from its device set, so a new PVC is created for the device set.
+func (m *OSDHealthMonitor) purgeOSD(id int) error {
+	hostName, err := client.GetCrushHostName(m.context, m.clusterInfo, id)
+	if err != nil {
+		logger.Warningf("failed to get the crush host of osd.%d. %v", id, err)
+	}
+
+	deploymentName := fmt.Sprintf(osdAppNameFmt, id)
+	d, err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).Get(m.clusterInfo.Context, deploymentName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get the deployment of osd.%d", id)
+	}
+	if err == nil {
+		if err := k8sutil.DeleteDeployment(m.context.Clientset, m.clusterInfo.Namespace, deploymentName); err != nil {
+			return errors.Wrapf(err, "failed to delete the deployment of osd.%d", id)
+		}
+		if pvcName, ok := d.Labels[OSDOverPVCLabelKey]; ok {
+			if err := m.detachOSDPVC(pvcName); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := client.PurgeOSD(m.context, m.clusterInfo, id); err != nil {
+		return err
+	}
+	if hostName != "" {
+		// the host is only removed from the crush map when it has no OSD left
+		if err := client.RemoveCrushBucket(m.context, m.clusterInfo, hostName); err != nil {
+			logger.Debugf("not removing crush host %q. %v", hostName, err)
+		}
+	}
+	return nil
+}
+
+// detachOSDPVC deletes the prepare job of the PVC and detaches the PVC from its device set
+func (m *OSDHealthMonitor) detachOSDPVC(pvcName string) error {
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", OSDOverPVCLabelKey, pvcName)}
+	jobs, err := m.context.Clientset.BatchV1().Jobs(m.clusterInfo.Namespace).List(m.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the osd prepare jobs of pvc %q", pvcName)
+	}
+	for _, job := range jobs.Items {
+		if err := k8sutil.DeleteBatchJob(m.context.Clientset, m.clusterInfo.Namespace, job.Name, false); err != nil {
+			return errors.Wrapf(err, "failed to delete osd prepare job %q", job.Name)
+		}
+	}
+
+	pvc, err := m.context.Clientset.CoreV1().PersistentVolumeClaims(m.clusterInfo.Namespace).Get(m.clusterInfo.Context, pvcName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get osd pvc %q", pvcName)
+	}
+	logger.Infof("detaching pvc %q from its device set", pvcName)
+	delete(pvc.Labels, CephDeviceSetPVCIDLabelKey)
+	if _, err := m.context.Clientset.CoreV1().PersistentVolumeClaims(m.clusterInfo.Namespace).Update(m.clusterInfo.Context, pvc, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to detach osd pvc %q from its device set", pvcName)
+	}
 	return nil
 }
 
@@ -178,6 +448,10 @@ func (m *OSDHealthMonitor) removeOSDDeploymentIfSafeToDestroy(outOSDid int) erro
 				if err := k8sutil.DeleteDeployment(m.context.Clientset, dp.Items[0].Namespace, dp.Items[0].Name); err != nil {
 					return errors.Wrapf(err, "failed to delete osd deployment %s", dp.Items[0].Name)
 				}
+				target := fmt.Sprintf("osd.%d", outOSDid)
+				if err := reporting.RecordCorrectiveAction(m.clusterInfo.Context, m.context.Client, m.clusterInfo.NamespacedName(), cephv1.CorrectiveActionOSDRemoval, target, "out and safe to destroy"); err != nil {
+					logger.Warningf("failed to record the removal of osd.%d. %v", outOSDid, err)
+				}
 			}
 		}
 	}
@@ -201,6 +475,10 @@ func (m *OSDHealthMonitor) updateCephStatus(devices []string) {
 		logger.Errorf("failed to retrieve ceph cluster %q to update ceph Storage. %v", m.clusterInfo.NamespacedName().Name, err)
 		return
 	}
+	if cephCluster.Status.CephStorage != nil {
+		// the status of the osds is updated separately
+		cephClusterStorage.OSDs = cephCluster.Status.CephStorage.OSDs
+	}
 	if !reflect.DeepEqual(cephCluster.Status.CephStorage, &cephClusterStorage) {
 		cephCluster.Status.CephStorage = &cephClusterStorage
 		if err := reporting.UpdateStatus(m.context.Client, cephCluster); err != nil {
@@ -31,6 +31,7 @@ import (
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -60,7 +61,10 @@ func TestOSDHealthCheck(t *testing.T) {
 	}
 
 	// Setting up objects needed to create OSD
+	nsName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
 	context := &clusterd.Context{
+		Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build(),
 		Executor:  executor,
 		Clientset: clientset,
 	}
@@ -87,7 +91,7 @@ func TestOSDHealthCheck(t *testing.T) {
 	assert.Equal(t, 1, len(dp.Items))
 
 	// Initializing an OSD monitoring
-	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, cephv1.CephClusterHealthCheckSpec{})
+	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, nil, cephv1.CephClusterHealthCheckSpec{})
 
 	// Run OSD monitoring routine
 	err := osdMon.checkOSDDump()
@@ -98,11 +102,210 @@ func TestOSDHealthCheck(t *testing.T) {
 	// Check if the osd deployment was deleted
 	dp, _ = context.Clientset.AppsV1().Deployments(clusterInfo.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%v=%d", OsdIdLabelKey, 0)})
 	assert.Equal(t, 0, len(dp.Items))
+
+	// Check if the removal was recorded in the corrective actions
+	cluster := &cephv1.CephCluster{}
+	assert.NoError(t, context.Client.Get(ctx, nsName, cluster))
+	assert.Equal(t, 1, len(cluster.Status.CorrectiveActions))
+	assert.Equal(t, cephv1.CorrectiveActionOSDRemoval, cluster.Status.CorrectiveActions[0].Type)
+	assert.Equal(t, "osd.0", cluster.Status.CorrectiveActions[0].Target)
+}
+
+func TestAutoRemoveOSDs(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testexec.New(t, 2)
+	clusterInfo := client.AdminClusterInfo("fake")
+
+	pgsClean := false
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("ExecuteCommandWithOutputFile: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "dump":
+				// osd.1 and osd.2 are down and out
+				return `{"OSDs": [{"OSD": 0, "Up": 1, "In": 1}, {"OSD": 1, "Up": 0, "In": 0}, {"OSD": 2, "Up": 0, "In": 0}]}`, nil
+			case args[0] == "status":
+				if pgsClean {
+					return `{"pgmap":{"num_pgs":10,"pgs_by_state":[{"state_name":"active+clean","count":10}]}}`, nil
+				}
+				return `{"pgmap":{"num_pgs":10,"pgs_by_state":[{"state_name":"active+clean","count":8},{"state_name":"active+undersized+degraded","count":2}]}}`, nil
+			case args[0] == "osd" && args[1] == "safe-to-destroy":
+				if args[2] == "1" {
+					return `{"safe_to_destroy":[1],"active":[],"missing_stats":[],"stored_pgs":[]}`, nil
+				}
+				return `{"safe_to_destroy":[],"active":[],"missing_stats":[],"stored_pgs":[2]}`, nil
+			case args[0] == "osd" && args[1] == "find":
+				return `{"osd":1,"crush_location":{"host":"node1","root":"default"}}`, nil
+			case args[0] == "osd" && (args[1] == "purge" || args[1] == "crush"):
+				commands = append(commands, fmt.Sprint(args[:4]))
+				return "", nil
+			}
+			return "", nil
+		},
+	}
+
+	nsName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+	context := &clusterd.Context{
+		Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build(),
+		Executor:  executor,
+		Clientset: clientset,
+	}
+
+	// osd.1 runs on a pvc of a device set
+	deployment := &apps.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "rook-ceph-osd-1",
+		Namespace: clusterInfo.Namespace,
+		Labels:    map[string]string{OsdIdLabelKey: "1", OSDOverPVCLabelKey: "set1-data-0"},
+	}}
+	_, err := clientset.AppsV1().Deployments(clusterInfo.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name:      "set1-data-0",
+		Namespace: clusterInfo.Namespace,
+		Labels:    map[string]string{CephDeviceSetPVCIDLabelKey: "set1-0"},
+	}}
+	_, err = clientset.CoreV1().PersistentVolumeClaims(clusterInfo.Namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	t.Run("disabled", func(t *testing.T) {
+		osdMon := NewOSDHealthMonitor(context, clusterInfo, false, &cephv1.AutoRemoveOSDSpec{}, cephv1.CephClusterHealthCheckSpec{})
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Nil(t, osdMon.downAndOutSince)
+		assert.Empty(t, commands)
+	})
+
+	osdMon := NewOSDHealthMonitor(context, clusterInfo, false, &cephv1.AutoRemoveOSDSpec{Enabled: true, GracePeriod: &metav1.Duration{Duration: time.Hour}}, cephv1.CephClusterHealthCheckSpec{})
+	t.Run("within the grace period", func(t *testing.T) {
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Equal(t, 2, len(osdMon.downAndOutSince))
+		assert.Empty(t, commands)
+	})
+
+	// the osds are down and out for longer than the grace period
+	osdMon.downAndOutSince[1] = time.Now().Add(-2 * time.Hour)
+	osdMon.downAndOutSince[2] = time.Now().Add(-2 * time.Hour)
+	t.Run("pgs not clean", func(t *testing.T) {
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Empty(t, commands)
+	})
+
+	t.Run("pgs clean", func(t *testing.T) {
+		pgsClean = true
+		assert.NoError(t, osdMon.checkOSDDump())
+		// osd.2 is not safe to destroy
+		assert.Equal(t, []string{"[osd purge osd.1 --force]", "[osd crush rm node1]"}, commands)
+		assert.Equal(t, 1, len(osdMon.downAndOutSince))
+
+		_, err := clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(ctx, "rook-ceph-osd-1", metav1.GetOptions{})
+		assert.Error(t, err)
+		pvc, err := clientset.CoreV1().PersistentVolumeClaims(clusterInfo.Namespace).Get(ctx, "set1-data-0", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.NotContains(t, pvc.Labels, CephDeviceSetPVCIDLabelKey)
+
+		cluster := &cephv1.CephCluster{}
+		assert.NoError(t, context.Client.Get(ctx, nsName, cluster))
+		assert.Equal(t, 1, len(cluster.Status.CorrectiveActions))
+		assert.Equal(t, "osd.1", cluster.Status.CorrectiveActions[0].Target)
+	})
+
+	t.Run("only the osds of the namespace when contributing osds", func(t *testing.T) {
+		commands = nil
+		osdMon := NewOSDHealthMonitor(context, clusterInfo, true, &cephv1.AutoRemoveOSDSpec{Enabled: true, GracePeriod: &metav1.Duration{}}, cephv1.CephClusterHealthCheckSpec{})
+		osdMon.OwnedOSDsOnly()
+		// osd.1 and osd.2 do not run in the namespace
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Empty(t, osdMon.downAndOutSince)
+		assert.Empty(t, commands)
+
+		// osd.2 runs in the namespace
+		deployment := &apps.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-osd-2",
+			Namespace: clusterInfo.Namespace,
+			Labels:    map[string]string{OsdIdLabelKey: "2"},
+		}}
+		_, err := clientset.AppsV1().Deployments(clusterInfo.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Equal(t, 1, len(osdMon.downAndOutSince))
+		assert.Contains(t, osdMon.downAndOutSince, 2)
+		// osd.2 is not safe to destroy
+		assert.Empty(t, commands)
+	})
+}
+
+func TestMarkOutFlappingOSDs(t *testing.T) {
+	ctx := context.TODO()
+	clusterInfo := client.AdminClusterInfo("fake")
+	upFrom := map[int]int{0: 10, 1: 10}
+	var outs []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("ExecuteCommandWithOutputFile: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "dump":
+				return fmt.Sprintf(`{"OSDs": [{"OSD": 0, "Up": 1, "In": 1, "up_from": %d}, {"OSD": 1, "Up": 1, "In": 1, "up_from": %d}]}`, upFrom[0], upFrom[1]), nil
+			case args[0] == "osd" && args[1] == "out":
+				outs = append(outs, args[2])
+				return "", nil
+			}
+			return "", nil
+		},
+	}
+	nsName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+	context := &clusterd.Context{
+		Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build(),
+		Executor:  executor,
+		Clientset: testexec.New(t, 1),
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		osdMon := NewOSDHealthMonitor(context, clusterInfo, false, nil, cephv1.CephClusterHealthCheckSpec{})
+		upFrom[0]++
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Nil(t, osdMon.upFrom)
+		assert.Empty(t, outs)
+	})
+
+	osdMon := NewOSDHealthMonitor(context, clusterInfo, false, nil, cephv1.CephClusterHealthCheckSpec{OSDFlapping: &cephv1.OSDFlappingSpec{Enabled: true, MaxFlaps: 3}})
+	t.Run("below the max flaps", func(t *testing.T) {
+		assert.NoError(t, osdMon.checkOSDDump())
+		for i := 0; i < 2; i++ {
+			upFrom[0]++
+			assert.NoError(t, osdMon.checkOSDDump())
+		}
+		assert.Equal(t, 2, len(osdMon.flaps[0]))
+		assert.Empty(t, osdMon.flaps[1])
+		assert.Empty(t, outs)
+	})
+
+	t.Run("flaps out of the window", func(t *testing.T) {
+		osdMon.flaps[0][0] = time.Now().Add(-2 * time.Hour)
+		upFrom[0]++
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Equal(t, 2, len(osdMon.flaps[0]))
+		assert.Empty(t, outs)
+	})
+
+	t.Run("flapping", func(t *testing.T) {
+		upFrom[0]++
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Equal(t, []string{"0"}, outs)
+		assert.Empty(t, osdMon.flaps[0])
+
+		cluster := &cephv1.CephCluster{}
+		assert.NoError(t, context.Client.Get(ctx, nsName, cluster))
+		assert.Equal(t, 1, len(cluster.Status.CorrectiveActions))
+		assert.Equal(t, cephv1.CorrectiveActionOSDMarkedOut, cluster.Status.CorrectiveActions[0].Type)
+		assert.Equal(t, "osd.0", cluster.Status.CorrectiveActions[0].Target)
+	})
 }
 
 func TestMonitorStart(t *testing.T) {
 	context, cancel := context.WithCancel(context.TODO())
-	osdMon := NewOSDHealthMonitor(&clusterd.Context{}, client.AdminClusterInfo("ns"), true, cephv1.CephClusterHealthCheckSpec{})
+	osdMon := NewOSDHealthMonitor(&clusterd.Context{}, client.AdminClusterInfo("ns"), true, nil, cephv1.CephClusterHealthCheckSpec{})
 	logger.Infof("starting osd monitor")
 	go osdMon.Start(context)
 	cancel()
@@ -122,12 +325,12 @@ func TestNewOSDHealthMonitor(t *testing.T) {
 		args args
 		want *OSDHealthMonitor
 	}{
-		{"default-interval", args{c, false, cephv1.CephClusterHealthCheckSpec{}}, &OSDHealthMonitor{c, clusterInfo, false, &defaultHealthCheckInterval}},
-		{"10s-interval", args{c, false, cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{ObjectStorageDaemon: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}, &OSDHealthMonitor{c, clusterInfo, false, &time10s}},
+		{"default-interval", args{c, false, cephv1.CephClusterHealthCheckSpec{}}, &OSDHealthMonitor{c, clusterInfo, false, &defaultHealthCheckInterval, nil, nil, nil, nil, nil, nil, false}},
+		{"10s-interval", args{c, false, cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{ObjectStorageDaemon: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}, &OSDHealthMonitor{c, clusterInfo, false, &time10s, nil, nil, nil, nil, nil, nil, false}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewOSDHealthMonitor(tt.args.context, clusterInfo, tt.args.removeOSDsIfOUTAndSafeToRemove, tt.args.healthCheck); !reflect.DeepEqual(got, tt.want) {
+			if got := NewOSDHealthMonitor(tt.args.context, clusterInfo, tt.args.removeOSDsIfOUTAndSafeToRemove, nil, tt.args.healthCheck); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewOSDHealthMonitor() = %v, want %v", got, tt.want)
 			}
 		})
@@ -169,7 +372,7 @@ func TestDeviceClasses(t *testing.T) {
 	}
 
 	// Initializing an OSD monitoring
-	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, cephv1.CephClusterHealthCheckSpec{})
+	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, nil, cephv1.CephClusterHealthCheckSpec{})
 
 	// Run OSD monitoring routine
 	err := osdMon.checkDeviceClasses()
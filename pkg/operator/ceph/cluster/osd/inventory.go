@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// checkOSDInventory reports the status of each OSD in the storage status of the CephCluster
+func (m *OSDHealthMonitor) checkOSDInventory() error {
+	osds, err := m.getOSDInventory()
+	if err != nil {
+		return err
+	}
+	return m.updateOSDInventory(osds)
+}
+
+// getOSDInventory returns the status of the OSDs of the osd map, with the node and the device or the PVC of their
+// pods, their crush device class and the version they reported
+func (m *OSDHealthMonitor) getOSDInventory() ([]cephv1.OSDStatus, error) {
+	osdDump, err := client.GetOSDDump(m.context, m.clusterInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get osd dump")
+	}
+	tree, err := client.HostTree(m.context, m.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	deviceClasses := map[int]string{}
+	for _, node := range tree.Nodes {
+		if node.Type == "osd" {
+			deviceClasses[node.ID] = node.DeviceClass
+		}
+	}
+	metadata, err := client.GetOSDMetadata(m.context, m.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	versions := map[int]string{}
+	for _, osd := range metadata {
+		versions[osd.ID] = osd.CephVersion
+	}
+
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	pods, err := m.context.Clientset.CoreV1().Pods(m.clusterInfo.Namespace).List(m.clusterInfo.Context, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the osd pods")
+	}
+	osdPods := map[int]*v1.Pod{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		id, err := strconv.Atoi(pod.Labels[OsdIdLabelKey])
+		if err != nil {
+			continue
+		}
+		// prefer the pod scheduled on a node while the previous pod of the osd is terminating
+		if existing, ok := osdPods[id]; !ok || existing.Spec.NodeName == "" || existing.DeletionTimestamp != nil {
+			osdPods[id] = pod
+		}
+	}
+
+	var osds []cephv1.OSDStatus
+	for _, osdStatus := range osdDump.OSDs {
+		id64, err := osdStatus.OSD.Int64()
+		if err != nil {
+			continue
+		}
+		id := int(id64)
+		up, in, err := osdDump.StatusByID(id64)
+		if err != nil {
+			return nil, err
+		}
+		osd := cephv1.OSDStatus{
+			ID:          id,
+			Up:          up == upStatus,
+			In:          in == inStatus,
+			DeviceClass: deviceClasses[id],
+			Version:     versions[id],
+		}
+		if pod, ok := osdPods[id]; ok {
+			osd.Node = osdPodNode(pod)
+			osd.PVC = pod.Labels[OSDOverPVCLabelKey]
+			if osd.PVC == "" {
+				osd.DevicePath = osdPodDevicePath(pod)
+			}
+		}
+		osds = append(osds, osd)
+	}
+	return osds, nil
+}
+
+// osdPodNode returns the node the pod of an OSD runs on, or the node it is pinned to if it is not scheduled
+func osdPodNode(pod *v1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+	return pod.Spec.NodeSelector[v1.LabelHostname]
+}
+
+// osdPodDevicePath returns the path of the device of the OSD from the env of its main container
+func osdPodDevicePath(pod *v1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	var path string
+	for _, env := range pod.Spec.Containers[0].Env {
+		if env.Name == "ROOK_BLOCK_PATH" || env.Name == "ROOK_LV_PATH" {
+			path = env.Value
+		}
+	}
+	return path
+}
+
+// updateOSDInventory updates the status of the OSDs in the storage status of the CephCluster
+func (m *OSDHealthMonitor) updateOSDInventory(osds []cephv1.OSDStatus) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve ceph cluster %q to update the status of the osds", m.clusterInfo.NamespacedName().Name)
+	}
+	if cephCluster.Status.CephStorage == nil && len(osds) == 0 {
+		return nil
+	}
+	storage := &cephv1.CephStorage{}
+	if cephCluster.Status.CephStorage != nil {
+		storage = cephCluster.Status.CephStorage.DeepCopy()
+	}
+	storage.OSDs = osds
+	if reflect.DeepEqual(cephCluster.Status.CephStorage, storage) {
+		return nil
+	}
+	cephCluster.Status.CephStorage = storage
+	if err := reporting.UpdateStatus(m.context.Client, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to update the status of the osds of cluster %q", m.clusterInfo.NamespacedName().Name)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testexec "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckOSDInventory(t *testing.T) {
+	ctx := context.TODO()
+	clusterInfo := client.AdminClusterInfo("ns")
+	clusterInfo.SetName("rook")
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "osd" && args[1] == "dump":
+				return `{"osds":[{"osd":0,"up":1,"in":1},{"osd":1,"up":0,"in":1},{"osd":2,"up":0,"in":0}]}`, nil
+			case args[0] == "osd" && args[1] == "tree":
+				return `{"nodes":[{"id":-1,"name":"default","type":"root"},{"id":0,"name":"osd.0","type":"osd","device_class":"ssd"},{"id":1,"name":"osd.1","type":"osd","device_class":"hdd"}]}`, nil
+			case args[0] == "osd" && args[1] == "metadata":
+				return `[{"id":0,"ceph_version_short":"16.2.6"},{"id":1,"ceph_version_short":"16.2.5"}]`, nil
+			}
+			return "", nil
+		},
+	}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cephCluster.Status.CephStorage = &cephv1.CephStorage{DeviceClasses: []cephv1.DeviceClasses{{Name: "ssd"}}}
+	c := &clusterd.Context{
+		Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build(),
+		Executor:  executor,
+		Clientset: testexec.New(t, 1),
+	}
+
+	osdPod := func(name, id string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns",
+				Labels:    map[string]string{k8sutil.AppAttr: AppName, OsdIdLabelKey: id},
+			},
+		}
+	}
+	pod0 := osdPod("rook-ceph-osd-0-abc", "0")
+	pod0.Spec.NodeName = "node1"
+	pod0.Spec.Containers = []corev1.Container{{Env: []corev1.EnvVar{{Name: "ROOK_BLOCK_PATH", Value: "/dev/sdb"}}}}
+	// the osd on a pvc is not scheduled yet
+	pod1 := osdPod("rook-ceph-osd-1-abc", "1")
+	pod1.Labels[OSDOverPVCLabelKey] = "set1-data-0"
+	pod1.Spec.NodeSelector = map[string]string{corev1.LabelHostname: "node2"}
+	for _, pod := range []*corev1.Pod{pod0, pod1} {
+		_, err := c.Clientset.CoreV1().Pods("ns").Create(ctx, pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	osdMon := NewOSDHealthMonitor(c, clusterInfo, false, nil, cephv1.CephClusterHealthCheckSpec{})
+	assert.NoError(t, osdMon.checkOSDInventory())
+
+	cluster := &cephv1.CephCluster{}
+	require.NoError(t, c.Client.Get(ctx, clusterInfo.NamespacedName(), cluster))
+	require.NotNil(t, cluster.Status.CephStorage)
+	assert.Equal(t, []cephv1.DeviceClasses{{Name: "ssd"}}, cluster.Status.CephStorage.DeviceClasses)
+	expected := []cephv1.OSDStatus{
+		{ID: 0, Node: "node1", DevicePath: "/dev/sdb", Up: true, In: true, DeviceClass: "ssd", Version: "16.2.6"},
+		{ID: 1, Node: "node2", PVC: "set1-data-0", Up: false, In: true, DeviceClass: "hdd", Version: "16.2.5"},
+		{ID: 2},
+	}
+	assert.Equal(t, expected, cluster.Status.CephStorage.OSDs)
+
+	// the device classes are updated without losing the status of the osds
+	osdMon.updateCephStatus([]string{"hdd", "ssd"})
+	require.NoError(t, c.Client.Get(ctx, clusterInfo.NamespacedName(), cluster))
+	assert.Equal(t, 2, len(cluster.Status.CephStorage.DeviceClasses))
+	assert.Equal(t, expected, cluster.Status.CephStorage.OSDs)
+}
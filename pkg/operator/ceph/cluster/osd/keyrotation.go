@@ -0,0 +1,501 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/libopenstorage/secrets"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RotateKeysAnnotation is the annotation of the CephCluster requesting the rotation of the keys of all the
+	// encrypted OSDs on PVC. The keys are rotated again each time the value of the annotation changes.
+	RotateKeysAnnotation = "ceph.rook.io/rotate-osd-keys"
+
+	keyRotationAppName       = "rook-ceph-osd-key-rotation"
+	keyRotationJobNameFmt    = "rook-ceph-osd-key-rotation-%d"
+	keyRotationContainerName = "key-rotation"
+	keyRotationKeyFileName   = "luks_key_next"
+	// the new key is kept in the KMS under the name of the PVC with this suffix until it is the current key,
+	// so an interrupted rotation is resumed with the same key
+	pendingKeySuffix           = "-next"
+	defaultKeyRotationInterval = 720 * time.Hour
+	keyRotationJobTimeout      = 10 * time.Minute
+
+	// keyRotationScript adds the new key to the LUKS devices with the current key, then removes the current
+	// key. Each step is skipped if a previous run already did it.
+	keyRotationScript = `
+set -e
+
+CURRENT_KEY_PATH=%s
+NEW_KEY_PATH=%s
+
+if cmp --silent "$CURRENT_KEY_PATH" "$NEW_KEY_PATH"; then
+	echo "the new key is already the current key"
+	exit 0
+fi
+
+for BLOCK_PATH in "$@"; do
+	if cryptsetup luksOpen --test-passphrase --disable-keyring --key-file "$NEW_KEY_PATH" "$BLOCK_PATH"; then
+		echo "new key already added to $BLOCK_PATH"
+	else
+		echo "adding the new key to $BLOCK_PATH"
+		cryptsetup luksAddKey --verbose --disable-keyring --key-file "$CURRENT_KEY_PATH" "$BLOCK_PATH" "$NEW_KEY_PATH"
+	fi
+
+	if cryptsetup luksOpen --test-passphrase --disable-keyring --key-file "$CURRENT_KEY_PATH" "$BLOCK_PATH"; then
+		echo "removing the current key from $BLOCK_PATH"
+		cryptsetup luksRemoveKey --verbose --disable-keyring "$BLOCK_PATH" "$CURRENT_KEY_PATH"
+	fi
+done
+`
+)
+
+var defaultKeyRotationCheckInterval = 5 * time.Minute
+
+// KeyRotator rotates the LUKS keys of the encrypted OSDs on PVC
+type KeyRotator struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	interval    time.Duration
+}
+
+// NewKeyRotator instantiates the rotation of the keys of the OSDs
+func NewKeyRotator(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) *KeyRotator {
+	return &KeyRotator{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultKeyRotationCheckInterval,
+	}
+}
+
+// Start checks at set intervals whether the keys of the OSDs must be rotated
+func (r *KeyRotator) Start(context context.Context) {
+	for {
+		select {
+		case <-time.After(r.interval):
+			logger.Debug("checking the rotation of the osd keys")
+			if err := r.rotateKeys(); err != nil {
+				logger.Errorf("failed to rotate the osd keys. %v", err)
+			}
+
+		case <-context.Done():
+			logger.Infof("stopping the rotation of the osd keys in namespace %q", r.clusterInfo.Namespace)
+			return
+		}
+	}
+}
+
+// rotateKeys rotates the keys of the OSDs whose rotation is due, requested, or interrupted, one OSD at a time
+func (r *KeyRotator) rotateKeys() error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := r.context.Client.Get(r.clusterInfo.Context, r.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", r.clusterInfo.NamespacedName().String())
+	}
+	spec := &cephCluster.Spec
+	if !spec.Security.KeyRotation.Enabled {
+		return nil
+	}
+
+	status := &cephv1.OSDKeyRotationStatus{}
+	if cephCluster.Status.OSDKeyRotation != nil {
+		status = cephCluster.Status.OSDKeyRotation.DeepCopy()
+	}
+	request := cephCluster.Annotations[RotateKeysAnnotation]
+	requested := request != "" && request != status.LastRequest
+	interval := defaultKeyRotationInterval
+	if spec.Security.KeyRotation.Interval != nil {
+		interval = spec.Security.KeyRotation.Interval.Duration
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := r.context.Clientset.AppsV1().Deployments(r.clusterInfo.Namespace).List(r.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osd deployments")
+	}
+
+	kmsConfig := kms.NewConfig(r.context, spec, r.clusterInfo)
+	if spec.Security.KeyManagementService.IsTokenAuthEnabled() || spec.Security.KeyManagementService.IsK8sAuthEnabled() {
+		err := kms.SetTokenToEnvVar(r.context, &spec.Security.KeyManagementService, kmsConfig.Provider, r.clusterInfo.Namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to get the kms token")
+		}
+	}
+
+	// the status only keeps the rotations of the existing encrypted OSDs
+	rotations := []cephv1.OSDKeyRotation{}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if !osdIsOnPVC(d) || !isEncryptedOSD(d) {
+			continue
+		}
+		osdID, err := getOSDID(d)
+		if err != nil {
+			logger.Errorf("failed to rotate the key of osd deployment %q. %v", d.Name, err)
+			continue
+		}
+		rotation := cephv1.OSDKeyRotation{ID: osdID, PVC: d.Labels[OSDOverPVCLabelKey]}
+		for _, previous := range status.OSDs {
+			if previous.ID == osdID && previous.PVC == rotation.PVC {
+				rotation = previous
+			}
+		}
+
+		pendingKey, err := kmsConfig.GetSecret(rotation.PVC + pendingKeySuffix)
+		if err != nil {
+			logger.Errorf("failed to get the pending key of osd.%d. %v", osdID, err)
+			rotations = append(rotations, rotation)
+			continue
+		}
+		if requested || pendingKey != "" || isKeyRotationDue(rotation, d, interval, time.Now()) {
+			rotation.Phase = cephv1.OSDKeyRotationRotating
+			rotation.Message = fmt.Sprintf("the key of osd.%d is being rotated", osdID)
+			setOSDKeyRotation(status, rotation)
+			if err := r.updateStatus(status); err != nil {
+				logger.Errorf("failed to update the status of the key rotation of osd.%d. %v", osdID, err)
+			}
+
+			if err := r.rotateKey(spec, kmsConfig, d, osdID, pendingKey); err != nil {
+				logger.Errorf("failed to rotate the key of osd.%d. %v", osdID, err)
+				rotation.Phase = cephv1.OSDKeyRotationFailed
+				rotation.Message = err.Error()
+			} else {
+				logger.Infof("rotated the key of osd.%d on pvc %q", osdID, rotation.PVC)
+				rotation.Phase = cephv1.OSDKeyRotationCompleted
+				rotation.Message = fmt.Sprintf("the key of osd.%d is rotated", osdID)
+				rotation.LastRotationTime = time.Now().UTC().Format(time.RFC3339)
+			}
+			setOSDKeyRotation(status, rotation)
+		}
+		rotations = append(rotations, rotation)
+	}
+
+	// the failed rotations of a request are retried as any failed rotation
+	if requested {
+		status.LastRequest = request
+	}
+	status.OSDs = rotations
+	return r.updateStatus(status)
+}
+
+// isKeyRotationDue returns whether the key of the OSD must be rotated. The key of an OSD that was never
+// rotated is as old as its deployment, and a failed rotation is retried right away.
+func isKeyRotationDue(rotation cephv1.OSDKeyRotation, d *appsv1.Deployment, interval time.Duration, now time.Time) bool {
+	if rotation.Phase == cephv1.OSDKeyRotationFailed || rotation.Phase == cephv1.OSDKeyRotationRotating {
+		return true
+	}
+	last := d.CreationTimestamp.Time
+	if rotation.LastRotationTime != "" {
+		t, err := time.Parse(time.RFC3339, rotation.LastRotationTime)
+		if err != nil {
+			logger.Warningf("failed to parse the last rotation time %q of the key of osd.%d. %v", rotation.LastRotationTime, rotation.ID, err)
+			return true
+		}
+		last = t
+	}
+	return now.Sub(last) >= interval
+}
+
+// rotateKey sets a new key on the devices of the OSD. The new key is stored in the KMS before it is added to
+// the devices, and only becomes the current key once the devices don't open with the previous key anymore,
+// so the rotation is resumed with the same key if it is interrupted.
+func (r *KeyRotator) rotateKey(spec *cephv1.ClusterSpec, kmsConfig *kms.Config, d *appsv1.Deployment, osdID int, pendingKey string) error {
+	pvcName := d.Labels[OSDOverPVCLabelKey]
+	currentKey, err := kmsConfig.GetSecret(pvcName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the key of pvc %q", pvcName)
+	}
+	if currentKey == "" {
+		return errors.Errorf("no key found for pvc %q", pvcName)
+	}
+
+	if pendingKey == "" {
+		pendingKey, err = generateDmCryptKey()
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate the new key of pvc %q", pvcName)
+		}
+		if err := kmsConfig.UpdateSecret(pvcName+pendingKeySuffix, pendingKey); err != nil {
+			return errors.Wrapf(err, "failed to store the new key of pvc %q", pvcName)
+		}
+	}
+
+	// the KMS was already updated if the rotation was interrupted before the pending key was deleted
+	if pendingKey != currentKey {
+		if err := r.runKeyRotationJob(spec, d, osdID); err != nil {
+			return err
+		}
+		if err := kmsConfig.UpdateSecret(pvcName, pendingKey); err != nil {
+			return errors.Wrapf(err, "failed to store the key of pvc %q", pvcName)
+		}
+	}
+
+	if err := kmsConfig.DeleteSecret(pvcName + pendingKeySuffix); err != nil {
+		return errors.Wrapf(err, "failed to delete the pending key of pvc %q", pvcName)
+	}
+	return nil
+}
+
+// runKeyRotationJob runs the job changing the key of the devices of the OSD and waits for its completion
+func (r *KeyRotator) runKeyRotationJob(spec *cephv1.ClusterSpec, d *appsv1.Deployment, osdID int) error {
+	nodeName, err := r.osdNodeName(osdID)
+	if err != nil {
+		return err
+	}
+	job, err := r.keyRotationJob(spec, d, osdID, nodeName)
+	if err != nil {
+		return err
+	}
+
+	if err := k8sutil.RunReplaceableJob(r.context.Clientset, job, true); err != nil {
+		return errors.Wrapf(err, "failed to run the key rotation job of osd.%d", osdID)
+	}
+	err = k8sutil.WaitForJobCompletion(r.context.Clientset, job, keyRotationJobTimeout)
+	if err != nil {
+		// the job is kept for its logs, it is replaced by the next rotation
+		return errors.Wrapf(err, "failed to complete the key rotation job of osd.%d", osdID)
+	}
+	if err := k8sutil.DeleteBatchJob(r.context.Clientset, job.Namespace, job.Name, false); err != nil {
+		logger.Warningf("failed to delete the key rotation job of osd.%d. %v", osdID, err)
+	}
+	return nil
+}
+
+// osdNodeName returns the node of the running OSD pod, whose devices the job must open on the same node
+func (r *KeyRotator) osdNodeName(osdID int) (string, error) {
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%d", OsdIdLabelKey, osdID)}
+	pods, err := r.context.Clientset.CoreV1().Pods(r.clusterInfo.Namespace).List(r.clusterInfo.Context, listOpts)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the pods of osd.%d", osdID)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == v1.PodRunning && pod.Spec.NodeName != "" {
+			return pod.Spec.NodeName, nil
+		}
+	}
+	return "", errors.Errorf("osd.%d is not running", osdID)
+}
+
+// keyRotationJob returns the job changing the key of the data, metadata and wal devices of the OSD, which all
+// share the same key
+func (r *KeyRotator) keyRotationJob(spec *cephv1.ClusterSpec, d *appsv1.Deployment, osdID int, nodeName string) (*batch.Job, error) {
+	pvcName := d.Labels[OSDOverPVCLabelKey]
+	volumes := []v1.Volume{}
+	volumeDevices := []v1.VolumeDevice{}
+	blockPaths := []string{}
+	for _, volume := range d.Spec.Template.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		devicePath := fmt.Sprintf("/%s", volume.PersistentVolumeClaim.ClaimName)
+		volumes = append(volumes, volume)
+		volumeDevices = append(volumeDevices, v1.VolumeDevice{Name: volume.Name, DevicePath: devicePath})
+		blockPaths = append(blockPaths, devicePath)
+	}
+	if len(blockPaths) == 0 {
+		return nil, errors.Errorf("no pvc found in the deployment of osd.%d", osdID)
+	}
+
+	keyVolume, keyMount, initContainers, err := keyRotationKeyVolume(spec, pvcName)
+	if err != nil {
+		return nil, err
+	}
+	volumes = append(volumes, keyVolume)
+
+	script := fmt.Sprintf(keyRotationScript, encryptionKeyPath(), path.Join(opconfig.EtcCephDir, keyRotationKeyFileName))
+	// the name of the container is the $0 of the script, followed by the paths of the devices
+	command := append([]string{"/bin/bash", "-c", script, keyRotationContainerName}, blockPaths...)
+
+	// a failed rotation is retried by the next check
+	backoffLimit := int32(0)
+	labels := map[string]string{
+		k8sutil.AppAttr:     keyRotationAppName,
+		k8sutil.ClusterAttr: r.clusterInfo.Namespace,
+		OsdIdLabelKey:       strconv.Itoa(osdID),
+	}
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(keyRotationJobNameFmt, osdID),
+			Namespace: r.clusterInfo.Namespace,
+			Labels:    labels,
+		},
+		Spec: batch.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: v1.PodSpec{
+					InitContainers: initContainers,
+					Containers: []v1.Container{
+						{
+							Name:            keyRotationContainerName,
+							Image:           spec.CephVersion.Image,
+							Command:         command,
+							VolumeDevices:   volumeDevices,
+							VolumeMounts:    []v1.VolumeMount{keyMount},
+							SecurityContext: PrivilegedContext(),
+						},
+					},
+					Volumes:            volumes,
+					RestartPolicy:      v1.RestartPolicyNever,
+					NodeSelector:       map[string]string{v1.LabelHostname: nodeName},
+					Tolerations:        d.Spec.Template.Spec.Tolerations,
+					ServiceAccountName: serviceAccountName,
+				},
+			},
+		},
+	}
+	k8sutil.AddRookVersionLabelToJob(job)
+	if err := r.clusterInfo.OwnerInfo.SetControllerReference(job); err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference to job %q", job.Name)
+	}
+	return job, nil
+}
+
+// keyRotationKeyVolume returns the volume with the current and the new keys. The keys of the Kubernetes
+// KMS are projected from their secrets, while the keys of Vault are fetched by init containers in memory.
+func keyRotationKeyVolume(spec *cephv1.ClusterSpec, pvcName string) (v1.Volume, v1.VolumeMount, []v1.Container, error) {
+	kmsSpec := spec.Security.KeyManagementService
+	mount := v1.VolumeMount{Name: osdEncryptionVolName, MountPath: opconfig.EtcCephDir}
+	if kms.GetParam(kmsSpec.ConnectionDetails, kms.Provider) != secrets.TypeVault {
+		var mode int32 = 0400
+		volume := v1.Volume{
+			Name: osdEncryptionVolName,
+			VolumeSource: v1.VolumeSource{
+				Projected: &v1.ProjectedVolumeSource{
+					Sources: []v1.VolumeProjection{
+						keyProjection(pvcName, encryptionKeyFileName),
+						keyProjection(pvcName+pendingKeySuffix, keyRotationKeyFileName),
+					},
+					DefaultMode: &mode,
+				},
+			},
+		}
+		mount.ReadOnly = true
+		return volume, mount, nil, nil
+	}
+
+	// the OSDs only fetch their key from Vault with a token, or with the token issued for their service account
+	if !kmsSpec.IsTokenAuthEnabled() && !kmsSpec.IsK8sAuthEnabled() {
+		return v1.Volume{}, v1.VolumeMount{}, nil, errors.New("the keys of the osds can only be rotated with the token or kubernetes authentication of vault")
+	}
+	volume := v1.Volume{
+		Name:         osdEncryptionVolName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}},
+	}
+	initContainers := []v1.Container{}
+	keys := map[string]string{
+		"get-key":     kms.GenerateOSDEncryptionSecretName(pvcName),
+		"get-new-key": kms.GenerateOSDEncryptionSecretName(pvcName + pendingKeySuffix),
+	}
+	keyFiles := map[string]string{
+		"get-key":     encryptionKeyPath(),
+		"get-new-key": path.Join(opconfig.EtcCephDir, keyRotationKeyFileName),
+	}
+	for _, name := range []string{"get-key", "get-new-key"} {
+		container := v1.Container{
+			Name:  name,
+			Image: spec.CephVersion.Image,
+			Command: []string{
+				"/bin/bash",
+				"-c",
+				fmt.Sprintf(getKEKFromVaultWithToken, keys[name], keyFiles[name]),
+			},
+			Env:          kms.VaultConfigToEnvVar(*spec),
+			VolumeMounts: []v1.VolumeMount{mount},
+		}
+		if kmsSpec.IsTLSEnabled() {
+			_, vaultVolMount := kms.VaultVolumeAndMount(kmsSpec.ConnectionDetails)
+			container.VolumeMounts = append(container.VolumeMounts, vaultVolMount)
+		}
+		initContainers = append(initContainers, container)
+	}
+	return volume, mount, initContainers, nil
+}
+
+func keyProjection(pvcName, fileName string) v1.VolumeProjection {
+	return v1.VolumeProjection{
+		Secret: &v1.SecretProjection{
+			LocalObjectReference: v1.LocalObjectReference{Name: kms.GenerateOSDEncryptionSecretName(pvcName)},
+			Items:                []v1.KeyToPath{{Key: kms.OsdEncryptionSecretNameKeyName, Path: fileName}},
+		},
+	}
+}
+
+// setOSDKeyRotation records the rotation, replacing any previous rotation of the same OSD
+func setOSDKeyRotation(status *cephv1.OSDKeyRotationStatus, rotation cephv1.OSDKeyRotation) {
+	for i := range status.OSDs {
+		if status.OSDs[i].ID == rotation.ID {
+			status.OSDs[i] = rotation
+			return
+		}
+	}
+	status.OSDs = append(status.OSDs, rotation)
+}
+
+// isEncryptedOSD returns whether the devices of the OSD on PVC are opened with a LUKS key
+func isEncryptedOSD(d *appsv1.Deployment) bool {
+	for _, container := range d.Spec.Template.Spec.InitContainers {
+		if container.Name == blockEncryptionOpenInitContainer {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *KeyRotator) updateStatus(status *cephv1.OSDKeyRotationStatus) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := r.context.Client.Get(r.clusterInfo.Context, r.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", r.clusterInfo.NamespacedName().String())
+	}
+
+	if reflect.DeepEqual(cephCluster.Status.OSDKeyRotation, status) {
+		return nil
+	}
+	cephCluster.Status.OSDKeyRotation = status
+	if err := reporting.UpdateStatus(r.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the OSD key rotation status")
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func encryptedOSDDeployment(id int, pvcName string, created time.Time) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "rook-ceph-osd-" + pvcName,
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				k8sutil.AppAttr:    AppName,
+				OsdIdLabelKey:      strconv.Itoa(id),
+				OSDOverPVCLabelKey: pvcName,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					InitContainers: []v1.Container{{Name: blockEncryptionOpenInitContainer}},
+					Volumes: []v1.Volume{
+						{Name: pvcName, VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName}}},
+						{Name: pvcName + "-bridge", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+						{Name: pvcName + "-db", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName + "-db"}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestIsKeyRotationDue(t *testing.T) {
+	now := time.Now()
+	d := encryptedOSDDeployment(0, "set1-data-0", now.Add(-2*time.Hour))
+	rotation := cephv1.OSDKeyRotation{ID: 0, PVC: "set1-data-0"}
+
+	// the key of an OSD never rotated is as old as its deployment
+	assert.True(t, isKeyRotationDue(rotation, d, time.Hour, now))
+	assert.False(t, isKeyRotationDue(rotation, d, 3*time.Hour, now))
+
+	rotation.Phase = cephv1.OSDKeyRotationCompleted
+	rotation.LastRotationTime = now.Add(-30 * time.Minute).UTC().Format(time.RFC3339)
+	assert.False(t, isKeyRotationDue(rotation, d, time.Hour, now))
+	assert.True(t, isKeyRotationDue(rotation, d, 10*time.Minute, now))
+
+	// a failed or interrupted rotation is retried
+	rotation.Phase = cephv1.OSDKeyRotationFailed
+	assert.True(t, isKeyRotationDue(rotation, d, time.Hour, now))
+	rotation.Phase = cephv1.OSDKeyRotationRotating
+	assert.True(t, isKeyRotationDue(rotation, d, time.Hour, now))
+
+	assert.True(t, isEncryptedOSD(d))
+	d.Spec.Template.Spec.InitContainers = nil
+	assert.False(t, isEncryptedOSD(d))
+}
+
+func TestKeyRotationJob(t *testing.T) {
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", Context: context.TODO()}
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	r := NewKeyRotator(&clusterd.Context{}, clusterInfo)
+	spec := &cephv1.ClusterSpec{CephVersion: cephv1.CephVersionSpec{Image: "ceph/ceph:v16"}}
+	d := encryptedOSDDeployment(0, "set1-data-0", time.Now())
+
+	t.Run("kubernetes kms", func(t *testing.T) {
+		job, err := r.keyRotationJob(spec, d, 0, "node1")
+		require.NoError(t, err)
+		assert.Equal(t, "rook-ceph-osd-key-rotation-0", job.Name)
+		podSpec := job.Spec.Template.Spec
+		assert.Equal(t, "node1", podSpec.NodeSelector[v1.LabelHostname])
+		assert.Empty(t, podSpec.InitContainers)
+		require.Equal(t, 1, len(podSpec.Containers))
+		container := podSpec.Containers[0]
+		assert.Equal(t, []string{"/set1-data-0", "/set1-data-0-db"}, container.Command[4:])
+		assert.Contains(t, container.Command[2], "CURRENT_KEY_PATH=/etc/ceph/luks_key\nNEW_KEY_PATH=/etc/ceph/luks_key_next")
+		assert.Equal(t, 2, len(container.VolumeDevices))
+		assert.True(t, *container.SecurityContext.Privileged)
+
+		// the data and db PVCs and the keys
+		require.Equal(t, 3, len(podSpec.Volumes))
+		keys := podSpec.Volumes[2].Projected.Sources
+		require.Equal(t, 2, len(keys))
+		assert.Equal(t, "rook-ceph-osd-encryption-key-set1-data-0", keys[0].Secret.Name)
+		assert.Equal(t, "luks_key", keys[0].Secret.Items[0].Path)
+		assert.Equal(t, "rook-ceph-osd-encryption-key-set1-data-0-next", keys[1].Secret.Name)
+		assert.Equal(t, "luks_key_next", keys[1].Secret.Items[0].Path)
+	})
+
+	t.Run("vault kms", func(t *testing.T) {
+		vaultSpec := spec.DeepCopy()
+		vaultSpec.Security.KeyManagementService.ConnectionDetails = map[string]string{"KMS_PROVIDER": "vault", "VAULT_ADDR": "http://vault:8200"}
+		_, err := r.keyRotationJob(vaultSpec, d, 0, "node1")
+		assert.Error(t, err)
+
+		vaultSpec.Security.KeyManagementService.TokenSecretName = "vault-token"
+		job, err := r.keyRotationJob(vaultSpec, d, 0, "node1")
+		require.NoError(t, err)
+		podSpec := job.Spec.Template.Spec
+		require.Equal(t, 2, len(podSpec.InitContainers))
+		assert.Contains(t, podSpec.InitContainers[0].Command[2], "KEK_NAME=rook-ceph-osd-encryption-key-set1-data-0\n")
+		assert.Contains(t, podSpec.InitContainers[1].Command[2], "KEK_NAME=rook-ceph-osd-encryption-key-set1-data-0-next\n")
+		assert.Equal(t, v1.StorageMediumMemory, podSpec.Volumes[2].EmptyDir.Medium)
+	})
+}
+
+func TestRotateKeys(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, CephVersion: cephver.Pacific, Context: ctx}
+	clusterInfo.SetName("testcluster")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcluster", Namespace: namespace},
+		Spec:       cephv1.ClusterSpec{Security: cephv1.SecuritySpec{KeyRotation: cephv1.KeyRotationSpec{Enabled: true}}},
+	}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := fake.NewSimpleClientset()
+	// the jobs complete right away
+	var jobs []string
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batch.Job)
+		jobs = append(jobs, job.Name)
+		job.Status.Succeeded = 1
+		return false, nil, nil
+	})
+	context := &clusterd.Context{Client: client, Clientset: clientset}
+	r := NewKeyRotator(context, clusterInfo)
+	kmsConfig := kms.NewConfig(context, &cephCluster.Spec, clusterInfo)
+
+	_, err := clientset.AppsV1().Deployments(namespace).Create(ctx, encryptedOSDDeployment(0, "set1-data-0", time.Now()), metav1.CreateOptions{})
+	require.NoError(t, err)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0-1", Namespace: namespace, Labels: map[string]string{OsdIdLabelKey: "0"}},
+		Spec:       v1.PodSpec{NodeName: "node1"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	_, err = clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+	key := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-encryption-key-set1-data-0", Namespace: namespace},
+		Data:       map[string][]byte{kms.OsdEncryptionSecretNameKeyName: []byte("old-key")},
+	}
+	_, err = clientset.CoreV1().Secrets(namespace).Create(ctx, key, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	getStatus := func() *cephv1.OSDKeyRotationStatus {
+		err := client.Get(ctx, clusterInfo.NamespacedName(), cephCluster)
+		require.NoError(t, err)
+		return cephCluster.Status.OSDKeyRotation
+	}
+
+	// the key of the new OSD is not rotated yet
+	require.NoError(t, r.rotateKeys())
+	status := getStatus()
+	require.Equal(t, 1, len(status.OSDs))
+	assert.Equal(t, cephv1.OSDKeyRotation{ID: 0, PVC: "set1-data-0"}, status.OSDs[0])
+	assert.Empty(t, jobs)
+
+	t.Run("an interrupted rotation is resumed", func(t *testing.T) {
+		// the KMS was updated with the new key before the rotation was interrupted
+		pending := key.DeepCopy()
+		pending.Name = "rook-ceph-osd-encryption-key-set1-data-0-next"
+		pending.Data[kms.OsdEncryptionSecretNameKeyName] = []byte("old-key")
+		_, err = clientset.CoreV1().Secrets(namespace).Create(ctx, pending, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		require.NoError(t, r.rotateKeys())
+		assert.Empty(t, jobs)
+		pendingKey, err := kmsConfig.GetSecret("set1-data-0-next")
+		require.NoError(t, err)
+		assert.Empty(t, pendingKey)
+		status := getStatus()
+		assert.Equal(t, cephv1.OSDKeyRotationCompleted, status.OSDs[0].Phase)
+		assert.NotEmpty(t, status.OSDs[0].LastRotationTime)
+	})
+
+	t.Run("rotation requested with the annotation", func(t *testing.T) {
+		cephCluster.Annotations = map[string]string{RotateKeysAnnotation: "1"}
+		require.NoError(t, client.Update(ctx, cephCluster))
+
+		require.NoError(t, r.rotateKeys())
+		assert.Equal(t, []string{"rook-ceph-osd-key-rotation-0"}, jobs)
+		status := getStatus()
+		assert.Equal(t, "1", status.LastRequest)
+		assert.Equal(t, cephv1.OSDKeyRotationCompleted, status.OSDs[0].Phase)
+		// the new key is the current key
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, "rook-ceph-osd-encryption-key-set1-data-0", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotEqual(t, "old-key", secret.StringData[kms.OsdEncryptionSecretNameKeyName])
+		assert.NotEmpty(t, secret.StringData[kms.OsdEncryptionSecretNameKeyName])
+
+		// the same request is not rotated again
+		require.NoError(t, r.rotateKeys())
+		assert.Equal(t, 1, len(jobs))
+	})
+
+	t.Run("the rotation of an OSD not running fails", func(t *testing.T) {
+		require.NoError(t, clientset.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}))
+		// the fake clientset doesn't convert the string data of the updated key
+		_, err = clientset.CoreV1().Secrets(namespace).Update(ctx, key, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		cephCluster.Annotations[RotateKeysAnnotation] = "2"
+		require.NoError(t, client.Update(ctx, cephCluster))
+
+		require.NoError(t, r.rotateKeys())
+		status := getStatus()
+		assert.Equal(t, cephv1.OSDKeyRotationFailed, status.OSDs[0].Phase)
+		assert.Contains(t, status.OSDs[0].Message, "osd.0 is not running")
+	})
+}
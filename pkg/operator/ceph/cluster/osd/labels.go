@@ -17,11 +17,17 @@ limitations under the License.
 package osd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -35,8 +41,24 @@ const (
 	OSDOverPVCLabelKey = "ceph.rook.io/pvc"
 	// TopologyLocationLabel is the crush location label added to OSD deployments
 	TopologyLocationLabel = "topology-location-%s"
+	// DeviceClassLabelKey is the device class label added to OSD deployments
+	DeviceClassLabelKey = "device-class"
+	// NodeHostnameLabelKey is the hostname of the node of the OSD added to the deployments of the OSDs
+	// that are not portable
+	NodeHostnameLabelKey = "node-hostname"
+	// TopologyConfigMapName is the name of the configmap publishing the topology of each OSD
+	TopologyConfigMapName = "rook-ceph-osd-topology"
 )
 
+// OSDTopology is the topology of an OSD published in the topology configmap
+type OSDTopology struct {
+	ID          int               `json:"id"`
+	Location    map[string]string `json:"location"`
+	DeviceClass string            `json:"deviceClass,omitempty"`
+	Node        string            `json:"node,omitempty"`
+	Portable    bool              `json:"portable"`
+}
+
 func makeStorageClassDeviceSetPVCLabel(storageClassDeviceSetName, pvcStorageClassDeviceSetPVCId string, setIndex int) map[string]string {
 	return map[string]string{
 		CephDeviceSetLabelKey:      storageClassDeviceSetName,
@@ -54,6 +76,9 @@ func (c *Cluster) getOSDLabels(osd OSDInfo, failureDomainValue string, portable
 	for k, v := range getOSDTopologyLocationLabels(osd.Location) {
 		labels[k] = v
 	}
+	if osd.DeviceClass != "" {
+		labels[DeviceClassLabelKey] = osd.DeviceClass
+	}
 	return labels
 }
 
@@ -68,3 +93,75 @@ func getOSDTopologyLocationLabels(topologyLocation string) map[string]string {
 	}
 	return labels
 }
+
+// publishOSDTopology saves the crush location, the device class and the node of each OSD in the
+// topology configmap, keyed by "osd.<id>". The topology is derived from the labels of the OSD
+// deployments, and the node from the OSD pods since the portable OSDs can move between nodes.
+func (c *Cluster) publishOSDTopology() error {
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := c.context.Clientset.AppsV1().Deployments(c.clusterInfo.Namespace).List(c.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list osd deployments")
+	}
+	pods, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).List(c.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list osd pods")
+	}
+	podNodes := map[string]string{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			podNodes[pod.Labels[OsdIdLabelKey]] = pod.Spec.NodeName
+		}
+	}
+
+	data := map[string]string{}
+	for _, d := range deployments.Items {
+		id, err := strconv.Atoi(d.Labels[OsdIdLabelKey])
+		if err != nil {
+			logger.Warningf("skipping the topology of osd deployment %q without osd id. %v", d.Name, err)
+			continue
+		}
+		topology := OSDTopology{
+			ID:          id,
+			Location:    map[string]string{},
+			DeviceClass: d.Labels[DeviceClassLabelKey],
+			Node:        d.Labels[NodeHostnameLabelKey],
+			Portable:    d.Labels[portableKey] == "true",
+		}
+		locationPrefix := fmt.Sprintf(TopologyLocationLabel, "")
+		for key, value := range d.Labels {
+			if strings.HasPrefix(key, locationPrefix) {
+				topology.Location[strings.TrimPrefix(key, locationPrefix)] = value
+			}
+		}
+		if node, ok := podNodes[d.Labels[OsdIdLabelKey]]; ok {
+			topology.Node = node
+		}
+		value, err := json.Marshal(topology)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize the topology of osd %d", id)
+		}
+		data[fmt.Sprintf("osd.%d", id)] = string(value)
+	}
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TopologyConfigMapName,
+			Namespace: c.clusterInfo.Namespace,
+		},
+		Data: data,
+	}
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(configMap); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to osd topology configmap %q", configMap.Name)
+	}
+	if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Create(c.clusterInfo.Context, configMap, metav1.CreateOptions{}); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrap(err, "failed to create osd topology configmap")
+		}
+		if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Update(c.clusterInfo.Context, configMap, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to update osd topology configmap")
+		}
+	}
+	logger.Debugf("published the topology of %d osds", len(data))
+	return nil
+}
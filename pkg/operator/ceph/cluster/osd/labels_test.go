@@ -17,9 +17,19 @@ limitations under the License.
 package osd
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestOSDTopologyLabels(t *testing.T) {
@@ -29,3 +39,52 @@ func TestOSDTopologyLabels(t *testing.T) {
 	assert.Equal(t, "ocs-deviceset-gp2-1-data-0-wh5wl", result["topology-location-host"])
 	assert.Equal(t, "us-east-1c", result["topology-location-zone"])
 }
+
+func TestPublishOSDTopology(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", OwnerInfo: cephclient.NewMinimumOwnerInfoWithOwnerRef(), Context: ctx}
+	c := New(&clusterd.Context{Clientset: clientset}, clusterInfo, cephv1.ClusterSpec{}, "myversion")
+
+	// the labels of the osds
+	labels := c.getOSDLabels(OSDInfo{ID: 0, DeviceClass: "ssd", Location: "root=default host=node1 rack=rack1"}, "", false)
+	assert.Equal(t, "ssd", labels[DeviceClassLabelKey])
+	assert.Equal(t, "rack1", labels["topology-location-rack"])
+	labels[NodeHostnameLabelKey] = "node1"
+	osd0 := &apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0", Namespace: "ns", Labels: labels}}
+	_, err := clientset.AppsV1().Deployments("ns").Create(ctx, osd0, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// the node of a portable osd is found from its pod
+	labels = c.getOSDLabels(OSDInfo{ID: 1, Location: "root=default host=set1-data-0 zone=zone-a"}, "", true)
+	_, ok := labels[DeviceClassLabelKey]
+	assert.False(t, ok)
+	osd1 := &apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-1", Namespace: "ns", Labels: labels}}
+	_, err = clientset.AppsV1().Deployments("ns").Create(ctx, osd1, metav1.CreateOptions{})
+	require.NoError(t, err)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-1-abc", Namespace: "ns", Labels: labels}, Spec: corev1.PodSpec{NodeName: "node2"}}
+	_, err = clientset.CoreV1().Pods("ns").Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	err = c.publishOSDTopology()
+	assert.NoError(t, err)
+	cm, err := clientset.CoreV1().ConfigMaps("ns").Get(ctx, TopologyConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(cm.Data))
+
+	topology := OSDTopology{}
+	require.NoError(t, json.Unmarshal([]byte(cm.Data["osd.0"]), &topology))
+	assert.Equal(t, OSDTopology{ID: 0, Location: map[string]string{"root": "default", "host": "node1", "rack": "rack1"}, DeviceClass: "ssd", Node: "node1"}, topology)
+	topology = OSDTopology{}
+	require.NoError(t, json.Unmarshal([]byte(cm.Data["osd.1"]), &topology))
+	assert.Equal(t, OSDTopology{ID: 1, Location: map[string]string{"root": "default", "host": "set1-data-0", "zone": "zone-a"}, Node: "node2", Portable: true}, topology)
+
+	// the topology is updated when an osd is removed
+	err = clientset.AppsV1().Deployments("ns").Delete(ctx, "rook-ceph-osd-1", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = c.publishOSDTopology()
+	assert.NoError(t, err)
+	cm, err = clientset.CoreV1().ConfigMaps("ns").Get(ctx, TopologyConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(cm.Data))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	osdMemoryTargetOption = "osd_memory_target"
+	// the ratio Ceph applies to the memory limit of the pod when the OSD starts
+	defaultMemoryTargetRatio = 0.8
+)
+
+// validateMemoryTarget validates the ratios of the memory targets of the OSDs
+func validateMemoryTarget(spec *cephv1.OSDMemoryTargetSpec) error {
+	if spec == nil {
+		return nil
+	}
+	if _, err := parseMemoryTargetRatio(spec.Ratio); err != nil {
+		return err
+	}
+	for deviceClass, ratio := range spec.DeviceClassRatios {
+		if _, err := parseMemoryTargetRatio(ratio); err != nil {
+			return errors.Wrapf(err, "invalid memory target ratio of device class %q", deviceClass)
+		}
+	}
+	return nil
+}
+
+// memoryTargetRatio returns the ratio of the memory limit of the pod targeted by the OSDs of the device class
+func memoryTargetRatio(spec *cephv1.OSDMemoryTargetSpec, deviceClass string) (float64, error) {
+	if ratio, ok := spec.DeviceClassRatios[deviceClass]; ok && deviceClass != "" {
+		return parseMemoryTargetRatio(ratio)
+	}
+	return parseMemoryTargetRatio(spec.Ratio)
+}
+
+func parseMemoryTargetRatio(ratio string) (float64, error) {
+	if ratio == "" {
+		return defaultMemoryTargetRatio, nil
+	}
+	value, err := strconv.ParseFloat(ratio, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse memory target ratio %q", ratio)
+	}
+	if value <= 0 || value > 1 {
+		return 0, errors.Errorf("memory target ratio %q must be greater than 0 and at most 1", ratio)
+	}
+	return value, nil
+}
+
+// configureMemoryTarget sets the osd_memory_target of the OSDs from the memory limit of their pods. Ceph
+// only derives the target from the limit when the OSD starts, and that value has precedence over the mon
+// store, so the target is also set on the running OSDs to apply a new limit or ratio without a restart.
+func (c *Cluster) configureMemoryTarget() error {
+	spec := c.spec.Storage.MemoryTarget
+	if spec == nil {
+		return nil
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := c.context.Clientset.AppsV1().Deployments(c.clusterInfo.Namespace).List(c.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osd deployments")
+	}
+
+	monStore := opconfig.GetMonStore(c.context, c.clusterInfo)
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		osdID, err := getOSDID(d)
+		if err != nil {
+			return err
+		}
+		ratio, err := memoryTargetRatio(spec, d.Labels[DeviceClassLabelKey])
+		if err != nil {
+			return err
+		}
+
+		var limit int64
+		for _, container := range d.Spec.Template.Spec.Containers {
+			if container.Name == "osd" {
+				limit = container.Resources.Limits.Memory().Value()
+			}
+		}
+		if limit == 0 {
+			logger.Debugf("osd.%d has no memory limit, keeping its memory target", osdID)
+			continue
+		}
+
+		who := fmt.Sprintf("osd.%d", osdID)
+		target := strconv.FormatInt(int64(float64(limit)*ratio), 10)
+		if _, err := monStore.SetIfChanged(who, osdMemoryTargetOption, target); err != nil {
+			return errors.Wrapf(err, "failed to set the memory target of osd.%d", osdID)
+		}
+		// the osd may be down or still starting, it gets the target on the next reconcile
+		if err := cephclient.SetRunningOSDConfig(c.context, c.clusterInfo, osdID, osdMemoryTargetOption, target); err != nil {
+			logger.Warningf("failed to set the memory target of running osd.%d. %v", osdID, err)
+		}
+	}
+
+	return nil
+}
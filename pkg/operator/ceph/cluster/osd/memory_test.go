@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateMemoryTarget(t *testing.T) {
+	assert.NoError(t, validateMemoryTarget(nil))
+	assert.NoError(t, validateMemoryTarget(&cephv1.OSDMemoryTargetSpec{}))
+	assert.NoError(t, validateMemoryTarget(&cephv1.OSDMemoryTargetSpec{Ratio: "0.6", DeviceClassRatios: map[string]string{"ssd": "1"}}))
+	assert.Error(t, validateMemoryTarget(&cephv1.OSDMemoryTargetSpec{Ratio: "1.5"}))
+	assert.Error(t, validateMemoryTarget(&cephv1.OSDMemoryTargetSpec{Ratio: "0"}))
+	assert.Error(t, validateMemoryTarget(&cephv1.OSDMemoryTargetSpec{DeviceClassRatios: map[string]string{"hdd": "half"}}))
+
+	spec := &cephv1.OSDMemoryTargetSpec{DeviceClassRatios: map[string]string{"ssd": "0.5"}}
+	ratio, err := memoryTargetRatio(spec, "hdd")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultMemoryTargetRatio, ratio)
+	ratio, err = memoryTargetRatio(spec, "ssd")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, ratio)
+}
+
+func TestConfigureMemoryTarget(t *testing.T) {
+	namespace := "ns"
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, CephVersion: cephver.Pacific, Context: context.TODO()}
+	clusterInfo.SetName("testcluster")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	clientset := fake.NewSimpleClientset()
+
+	monStore := map[string]string{}
+	running := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "config" && args[1] == "get":
+				return monStore[args[2]], nil
+			case args[0] == "config" && args[1] == "set":
+				monStore[args[2]] = args[4]
+				return "", nil
+			case args[0] == "tell" && args[2] == "config" && args[3] == "set":
+				running[args[1]] = args[5]
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	spec := cephv1.ClusterSpec{DataDirHostPath: "/rook"}
+	c := New(&clusterd.Context{Clientset: clientset, Executor: executor}, clusterInfo, spec, "myversion")
+	provisionConfig := &provisionConfig{DataPathMap: opconfig.NewDatalessDaemonDataPathMap(namespace, "/rook")}
+	createDeployment := func(osd OSDInfo, memoryLimit string) {
+		osdProps := osdProperties{crushHostname: "node1", selection: cephv1.Selection{}}
+		if memoryLimit != "" {
+			osdProps.resources = v1.ResourceRequirements{Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse(memoryLimit)}}
+		}
+		d, err := c.makeDeployment(osdProps, osd, provisionConfig)
+		require.NoError(t, err)
+		_, err = clientset.AppsV1().Deployments(namespace).Create(context.TODO(), d, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	createDeployment(OSDInfo{ID: 0, UUID: "uuid-0", BlockPath: "/dev/sdb", CVMode: "raw", DeviceClass: "hdd"}, "4Gi")
+	createDeployment(OSDInfo{ID: 1, UUID: "uuid-1", BlockPath: "/dev/sdc", CVMode: "raw", DeviceClass: "ssd"}, "4Gi")
+	createDeployment(OSDInfo{ID: 2, UUID: "uuid-2", BlockPath: "/dev/sdd", CVMode: "raw", DeviceClass: "ssd"}, "")
+
+	// the memory target is not managed by default
+	err := c.configureMemoryTarget()
+	assert.NoError(t, err)
+	assert.Empty(t, monStore)
+
+	c.spec.Storage.MemoryTarget = &cephv1.OSDMemoryTargetSpec{DeviceClassRatios: map[string]string{"ssd": "0.5"}}
+	err = c.configureMemoryTarget()
+	assert.NoError(t, err)
+	gi := int64(1024 * 1024 * 1024)
+	expected := map[string]string{
+		"osd.0": fmt.Sprint(int64(float64(4*gi) * defaultMemoryTargetRatio)),
+		"osd.1": fmt.Sprint(2 * gi),
+	}
+	assert.Equal(t, expected, monStore)
+	assert.Equal(t, expected, running)
+
+	// a new ratio is applied to the running osds
+	c.spec.Storage.MemoryTarget.Ratio = "0.75"
+	err = c.configureMemoryTarget()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprint(3*gi), monStore["osd.0"])
+	assert.Equal(t, fmt.Sprint(3*gi), running["osd.0"])
+	assert.Equal(t, fmt.Sprint(2*gi), running["osd.1"])
+}
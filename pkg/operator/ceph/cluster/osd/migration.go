@@ -0,0 +1,381 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/exec"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultMigrationFailureDomain = "host"
+	filestoreObjectStore          = "filestore"
+	luks1Type                     = "LUKS1"
+)
+
+var (
+	defaultOSDMigrationCheckInterval = time.Minute
+
+	// getOSDLUKSType returns the LUKS type of the encrypted block of a running OSD on PVC
+	getOSDLUKSType = realGetOSDLUKSType
+)
+
+// OSDMigrator re-provisions the OSDs with a legacy format one failure domain at a time, by marking them out
+// and replacing them once the placement groups are clean
+type OSDMigrator struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	interval    time.Duration
+}
+
+// NewOSDMigrator instantiates the migration of the OSDs with a legacy format
+func NewOSDMigrator(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) *OSDMigrator {
+	return &OSDMigrator{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultOSDMigrationCheckInterval,
+	}
+}
+
+// Start checks at set intervals the progress of the migration of the OSDs
+func (m *OSDMigrator) Start(context context.Context) {
+	for {
+		select {
+		case <-time.After(m.interval):
+			logger.Debug("checking the migration of the osds")
+			if err := m.migrate(); err != nil {
+				logger.Errorf("failed to migrate the osds. %v", err)
+			}
+
+		case <-context.Done():
+			logger.Infof("stopping the migration of the osds in namespace %q", m.clusterInfo.Namespace)
+			return
+		}
+	}
+}
+
+// migrate takes the next step of the migration: the OSDs of the next failure domain with legacy OSDs are
+// marked out, then replaced once all the placement groups are clean, and the next failure domain is only
+// migrated after the replaced OSDs are running again with the new format.
+func (m *OSDMigrator) migrate() error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", m.clusterInfo.NamespacedName().String())
+	}
+	status := &cephv1.OSDMigrationStatus{}
+	if cephCluster.Status.OSDMigration != nil {
+		status = cephCluster.Status.OSDMigration.DeepCopy()
+	}
+
+	spec := cephCluster.Spec.Storage.Migration
+	if spec == nil || !spec.Enabled {
+		// the OSDs drained for a migration are marked in again when it is disabled before they are replaced
+		if status.Phase == cephv1.OSDMigrationDraining {
+			for _, osdID := range status.OSDs {
+				if err := cephclient.OSDIn(m.context, m.clusterInfo, osdID); err != nil {
+					return err
+				}
+			}
+			logger.Infof("osd migration disabled, marked osds %v in again", status.OSDs)
+			return m.updateStatus(nil)
+		}
+		return nil
+	}
+	failureDomain := defaultMigrationFailureDomain
+	if spec.FailureDomain != "" {
+		failureDomain = spec.FailureDomain
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).List(m.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osd deployments")
+	}
+	legacy, err := m.legacyOSDs(deployments.Items)
+	if err != nil {
+		return err
+	}
+
+	switch status.Phase {
+	case cephv1.OSDMigrationDraining:
+		msg, clean, err := cephclient.IsClusterClean(m.context, m.clusterInfo)
+		if err != nil {
+			return errors.Wrap(err, "failed to check if the placement groups are clean")
+		}
+		if !clean {
+			status.Message = fmt.Sprintf("waiting for the placement groups to be clean before replacing osds %v. %s", status.OSDs, msg)
+			return m.updateStatus(status)
+		}
+		if err := m.requestReplacements(deployments.Items, status.OSDs); err != nil {
+			return err
+		}
+		logger.Infof("replacing osds %v of %q to migrate them", status.OSDs, status.FailureDomain)
+		status.Phase = cephv1.OSDMigrationReplacing
+		status.Message = fmt.Sprintf("osds %v are destroyed and prepared again", status.OSDs)
+		return m.updateStatus(status)
+
+	case cephv1.OSDMigrationReplacing:
+		if !isMigrationReplaced(deployments.Items, status.OSDs, legacy) {
+			status.Message = fmt.Sprintf("waiting for osds %v to run again with the new format", status.OSDs)
+			return m.updateStatus(status)
+		}
+		logger.Infof("osds %v of %q migrated", status.OSDs, status.FailureDomain)
+		status.MigratedOSDs += len(status.OSDs)
+		status.OSDs = nil
+		status.FailureDomain = ""
+	}
+
+	status.PendingOSDs = len(legacy)
+	if len(legacy) == 0 {
+		status.Phase = cephv1.OSDMigrationCompleted
+		status.Message = "no osd with a legacy format left"
+		return m.updateStatus(status)
+	}
+	if spec.Paused {
+		status.Phase = cephv1.OSDMigrationPaused
+		status.Message = fmt.Sprintf("migration paused with %d osds left to migrate", len(legacy))
+		return m.updateStatus(status)
+	}
+
+	domain, osdIDs := nextMigrationFailureDomain(deployments.Items, legacy, failureDomain)
+	if len(osdIDs) == 0 {
+		status.Message = fmt.Sprintf("no %s found for the osds %v left to migrate", failureDomain, sortedOSDIDs(legacy))
+		return m.updateStatus(status)
+	}
+	for _, osdID := range osdIDs {
+		if _, err := cephclient.OSDOut(m.context, m.clusterInfo, osdID); err != nil {
+			return errors.Wrapf(err, "failed to mark osd.%d out", osdID)
+		}
+	}
+	logger.Infof("draining osds %v of %q to migrate them", osdIDs, domain)
+	status.Phase = cephv1.OSDMigrationDraining
+	status.FailureDomain = domain
+	status.OSDs = osdIDs
+	status.Message = fmt.Sprintf("osds %v are out, waiting for the placement groups to be clean", osdIDs)
+	return m.updateStatus(status)
+}
+
+// legacyOSDs returns the OSDs with a legacy format and the reason: the filestore OSDs, and the OSDs on PVC
+// encrypted with LUKS1
+func (m *OSDMigrator) legacyOSDs(deployments []appsv1.Deployment) (map[int]string, error) {
+	metadata, err := cephclient.GetOSDMetadata(m.context, m.clusterInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the osd metadata")
+	}
+	objectStores := map[int]string{}
+	for _, osd := range metadata {
+		objectStores[osd.ID] = osd.ObjectStore
+	}
+
+	legacy := map[int]string{}
+	for i := range deployments {
+		d := &deployments[i]
+		osdID, err := getOSDID(d)
+		if err != nil {
+			logger.Errorf("failed to check the format of osd deployment %q. %v", d.Name, err)
+			continue
+		}
+		if objectStores[osdID] == filestoreObjectStore {
+			legacy[osdID] = filestoreObjectStore
+			continue
+		}
+		if osdIsOnPVC(d) && isEncryptedOSD(d) {
+			luksType, err := getOSDLUKSType(m.context, m.clusterInfo, d, osdID)
+			if err != nil {
+				logger.Warningf("failed to get the luks type of osd.%d. %v", osdID, err)
+				continue
+			}
+			if luksType == luks1Type {
+				legacy[osdID] = luks1Type
+			}
+		}
+	}
+	return legacy, nil
+}
+
+// requestReplacements annotates the deployments of the OSDs for their replacement
+func (m *OSDMigrator) requestReplacements(deployments []appsv1.Deployment, osdIDs []int) error {
+	for i := range deployments {
+		d := &deployments[i]
+		osdID, err := getOSDID(d)
+		if err != nil || !containsOSD(osdIDs, osdID) || controller.IsOSDReplacementRequested(d.Annotations) {
+			continue
+		}
+		if d.Annotations == nil {
+			d.Annotations = map[string]string{}
+		}
+		d.Annotations[controller.ReplaceOSDAnnotation] = "true"
+		if _, err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).Update(m.clusterInfo.Context, d, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to request the replacement of osd.%d", osdID)
+		}
+	}
+	return nil
+}
+
+// isMigrationReplaced returns whether the OSDs are running again with a deployment created by their
+// replacement and the new format
+func isMigrationReplaced(deployments []appsv1.Deployment, osdIDs []int, legacy map[int]string) bool {
+	replaced := map[int]bool{}
+	for i := range deployments {
+		d := &deployments[i]
+		osdID, err := getOSDID(d)
+		if err != nil || controller.IsOSDReplacementRequested(d.Annotations) {
+			continue
+		}
+		if _, ok := legacy[osdID]; !ok {
+			replaced[osdID] = true
+		}
+	}
+	for _, osdID := range osdIDs {
+		if !replaced[osdID] {
+			return false
+		}
+	}
+	return true
+}
+
+// nextMigrationFailureDomain returns the first failure domain in alphabetical order with OSDs to migrate,
+// and these OSDs
+func nextMigrationFailureDomain(deployments []appsv1.Deployment, legacy map[int]string, failureDomain string) (string, []int) {
+	domains := map[string][]int{}
+	label := fmt.Sprintf(TopologyLocationLabel, failureDomain)
+	for i := range deployments {
+		d := &deployments[i]
+		osdID, err := getOSDID(d)
+		if err != nil {
+			continue
+		}
+		if _, ok := legacy[osdID]; !ok {
+			continue
+		}
+		if name := d.Labels[label]; name != "" {
+			domains[name] = append(domains[name], osdID)
+		}
+	}
+	if len(domains) == 0 {
+		return "", nil
+	}
+
+	names := []string{}
+	for name := range domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	osdIDs := domains[names[0]]
+	sort.Ints(osdIDs)
+	return fmt.Sprintf("%s=%s", failureDomain, names[0]), osdIDs
+}
+
+func sortedOSDIDs(osds map[int]string) []int {
+	ids := []int{}
+	for id := range osds {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func containsOSD(osdIDs []int, osdID int) bool {
+	for _, id := range osdIDs {
+		if id == osdID {
+			return true
+		}
+	}
+	return false
+}
+
+// realGetOSDLUKSType reads the type of the encrypted block of the running OSD from the dm-crypt mapping
+func realGetOSDLUKSType(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, d *appsv1.Deployment, osdID int) (string, error) {
+	if context.RemoteExecutor.RestClient == nil {
+		return "", errors.New("remote executor is not configured")
+	}
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%d", OsdIdLabelKey, osdID)}
+	pods, err := context.Clientset.CoreV1().Pods(d.Namespace).List(clusterInfo.Context, listOpts)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the pods of osd.%d", osdID)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		stdout, stderr, err := context.RemoteExecutor.ExecWithOptions(exec.ExecOptions{
+			Command:       []string{"cryptsetup", "status", encryptionDMName(d.Labels[OSDOverPVCLabelKey], DmcryptBlockType)},
+			Namespace:     pod.Namespace,
+			PodName:       pod.Name,
+			ContainerName: "osd",
+			CaptureStdout: true,
+			CaptureStderr: true,
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get the status of the encrypted block of osd.%d. %s", osdID, stderr)
+		}
+		return parseLUKSType(stdout), nil
+	}
+	return "", errors.Errorf("osd.%d is not running", osdID)
+}
+
+// parseLUKSType returns the type in the output of "cryptsetup status"
+func parseLUKSType(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "type:" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+func (m *OSDMigrator) updateStatus(status *cephv1.OSDMigrationStatus) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", m.clusterInfo.NamespacedName().String())
+	}
+
+	if reflect.DeepEqual(cephCluster.Status.OSDMigration, status) {
+		return nil
+	}
+	cephCluster.Status.OSDMigration = status
+	if err := reporting.UpdateStatus(m.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the osd migration status")
+	}
+	return nil
+}
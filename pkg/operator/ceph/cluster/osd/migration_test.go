@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseLUKSType(t *testing.T) {
+	output := `/dev/mapper/set1-data-0-abcde-block-dmcrypt is active and is in use.
+  type:    LUKS1
+  cipher:  aes-xts-plain64
+  keysize: 512 bits`
+	assert.Equal(t, "LUKS1", parseLUKSType(output))
+	assert.Equal(t, "", parseLUKSType("not active"))
+}
+
+func migrationTestDeployment(namespace string, osdID int, host string) *appsv1.Deployment {
+	return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      fmt.Sprintf("rook-ceph-osd-%d", osdID),
+		Namespace: namespace,
+		Labels: map[string]string{
+			k8sutil.AppAttr: AppName,
+			OsdIdLabelKey:   strconv.Itoa(osdID),
+			fmt.Sprintf(TopologyLocationLabel, "host"): host,
+		},
+	}}
+}
+
+func TestNextMigrationFailureDomain(t *testing.T) {
+	deployments := []appsv1.Deployment{
+		*migrationTestDeployment("ns", 0, "node2"),
+		*migrationTestDeployment("ns", 1, "node1"),
+		*migrationTestDeployment("ns", 2, "node2"),
+		*migrationTestDeployment("ns", 3, "node1"),
+	}
+	domain, osdIDs := nextMigrationFailureDomain(deployments, map[int]string{0: filestoreObjectStore, 2: luks1Type, 3: luks1Type}, "host")
+	assert.Equal(t, "host=node1", domain)
+	assert.Equal(t, []int{3}, osdIDs)
+
+	domain, osdIDs = nextMigrationFailureDomain(deployments, map[int]string{0: filestoreObjectStore, 2: luks1Type}, "host")
+	assert.Equal(t, "host=node2", domain)
+	assert.Equal(t, []int{0, 2}, osdIDs)
+
+	// no osd has the label of the failure domain
+	domain, osdIDs = nextMigrationFailureDomain(deployments, map[int]string{0: filestoreObjectStore}, "rack")
+	assert.Equal(t, "", domain)
+	assert.Empty(t, osdIDs)
+}
+
+func TestIsMigrationReplaced(t *testing.T) {
+	d0 := migrationTestDeployment("ns", 0, "node1")
+	d1 := migrationTestDeployment("ns", 1, "node1")
+	d1.Annotations = map[string]string{controller.ReplaceOSDAnnotation: "true"}
+
+	// osd.1 is not replaced yet
+	assert.False(t, isMigrationReplaced([]appsv1.Deployment{*d0, *d1}, []int{0, 1}, map[int]string{}))
+	// osd.1 is destroyed and its deployment is gone
+	assert.False(t, isMigrationReplaced([]appsv1.Deployment{*d0}, []int{0, 1}, map[int]string{}))
+	// osd.1 runs again but still with the legacy format
+	d1.Annotations = nil
+	assert.False(t, isMigrationReplaced([]appsv1.Deployment{*d0, *d1}, []int{0, 1}, map[int]string{1: filestoreObjectStore}))
+	assert.True(t, isMigrationReplaced([]appsv1.Deployment{*d0, *d1}, []int{0, 1}, map[int]string{}))
+}
+
+func TestMigrateOSDs(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, CephVersion: cephver.Pacific, Context: ctx}
+	clusterInfo.SetName("testcluster")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcluster", Namespace: namespace},
+		Spec: cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{
+			Migration: &cephv1.OSDMigrationSpec{Enabled: true},
+		}},
+	}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := fake.NewSimpleClientset()
+	for id, host := range map[int]string{0: "node1", 1: "node1", 2: "node2"} {
+		_, err := clientset.AppsV1().Deployments(namespace).Create(ctx, migrationTestDeployment(namespace, id, host), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	objectStores := map[int]string{0: "filestore", 1: "bluestore", 2: "filestore"}
+	clean := false
+	var outs, ins []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "metadata":
+				return fmt.Sprintf(`[{"id":0,"osd_objectstore":%q},{"id":1,"osd_objectstore":%q},{"id":2,"osd_objectstore":%q}]`,
+					objectStores[0], objectStores[1], objectStores[2]), nil
+			case args[0] == "status":
+				if clean {
+					return `{"pgmap":{"num_pgs":1,"pgs_by_state":[{"state_name":"active+clean","count":1}]}}`, nil
+				}
+				return `{"pgmap":{"num_pgs":1,"pgs_by_state":[{"state_name":"active+remapped+backfilling","count":1}]}}`, nil
+			case args[0] == "osd" && args[1] == "out":
+				outs = append(outs, args[2])
+				return "", nil
+			case args[0] == "osd" && args[1] == "in":
+				ins = append(ins, args[2])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	m := NewOSDMigrator(&clusterd.Context{Client: client, Clientset: clientset, Executor: executor}, clusterInfo)
+
+	getStatus := func() *cephv1.OSDMigrationStatus {
+		// the cluster is read in a new object so the fields removed from the status are not kept
+		cephCluster = &cephv1.CephCluster{}
+		err := client.Get(ctx, clusterInfo.NamespacedName(), cephCluster)
+		require.NoError(t, err)
+		return cephCluster.Status.OSDMigration
+	}
+	isReplacementRequested := func(osdID int) bool {
+		d, err := clientset.AppsV1().Deployments(namespace).Get(ctx, fmt.Sprintf("rook-ceph-osd-%d", osdID), metav1.GetOptions{})
+		require.NoError(t, err)
+		return controller.IsOSDReplacementRequested(d.Annotations)
+	}
+
+	// the legacy osds of the first host are drained
+	require.NoError(t, m.migrate())
+	status := getStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, cephv1.OSDMigrationDraining, status.Phase)
+	assert.Equal(t, "host=node1", status.FailureDomain)
+	assert.Equal(t, []int{0}, status.OSDs)
+	assert.Equal(t, 2, status.PendingOSDs)
+	assert.Equal(t, []string{"0"}, outs)
+
+	// the osds are not replaced until the placement groups are clean
+	require.NoError(t, m.migrate())
+	assert.Contains(t, getStatus().Message, "waiting for the placement groups to be clean")
+	assert.False(t, isReplacementRequested(0))
+
+	clean = true
+	require.NoError(t, m.migrate())
+	assert.Equal(t, cephv1.OSDMigrationReplacing, getStatus().Phase)
+	assert.True(t, isReplacementRequested(0))
+
+	// the next host waits for the osd to run again with bluestore
+	require.NoError(t, m.migrate())
+	assert.Contains(t, getStatus().Message, "waiting for osds [0] to run again")
+
+	d := migrationTestDeployment(namespace, 0, "node1")
+	_, err := clientset.AppsV1().Deployments(namespace).Update(ctx, d, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	objectStores[0] = "bluestore"
+
+	// the migration pauses before the next host
+	cephCluster.Spec.Storage.Migration.Paused = true
+	require.NoError(t, client.Update(ctx, cephCluster))
+	require.NoError(t, m.migrate())
+	status = getStatus()
+	assert.Equal(t, cephv1.OSDMigrationPaused, status.Phase)
+	assert.Equal(t, 1, status.MigratedOSDs)
+	assert.Equal(t, 1, status.PendingOSDs)
+	assert.Empty(t, status.OSDs)
+
+	cephCluster.Spec.Storage.Migration.Paused = false
+	require.NoError(t, client.Update(ctx, cephCluster))
+	require.NoError(t, m.migrate())
+	status = getStatus()
+	assert.Equal(t, cephv1.OSDMigrationDraining, status.Phase)
+	assert.Equal(t, "host=node2", status.FailureDomain)
+	assert.Equal(t, []string{"0", "2"}, outs)
+
+	// the drained osds are marked in again when the migration is disabled
+	cephCluster.Spec.Storage.Migration.Enabled = false
+	require.NoError(t, client.Update(ctx, cephCluster))
+	require.NoError(t, m.migrate())
+	assert.Equal(t, []string{"2"}, ins)
+	assert.Nil(t, getStatus())
+}
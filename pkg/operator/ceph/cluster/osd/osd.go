@@ -34,6 +34,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	osdconfig "github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
@@ -78,17 +79,22 @@ type Cluster struct {
 	ValidStorage cephv1.StorageScopeSpec // valid subset of `Storage`, computed at runtime
 	kv           *k8sutil.ConfigMapKVStore
 	deviceSets   []deviceSet
+	replacements []cephv1.OSDReplacement
+	// prepareScheduler limits the OSD prepare jobs running at the same time
+	prepareScheduler *prepareScheduler
 }
 
 // New creates an instance of the OSD manager
 func New(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, spec cephv1.ClusterSpec, rookVersion string) *Cluster {
-	return &Cluster{
+	c := &Cluster{
 		context:     context,
 		clusterInfo: clusterInfo,
 		spec:        spec,
 		rookVersion: rookVersion,
 		kv:          k8sutil.NewConfigMapKVStore(clusterInfo.Namespace, context.Clientset, clusterInfo.OwnerInfo),
 	}
+	c.prepareScheduler = newPrepareScheduler(c)
+	return c
 }
 
 // OSDInfo represent all the properties of a given OSD
@@ -109,6 +115,8 @@ type OSDInfo struct {
 	Store         string `json:"store"`
 	// Ensure the OSD daemon has affinity with the same topology from the OSD prepare pod
 	TopologyAffinity string `json:"topologyAffinity"`
+	// Size is the size in bytes of the block device of the OSD, reported by the prepare job
+	Size uint64 `json:"size,omitempty"`
 }
 
 // OrchestrationStatus represents the status of an OSD orchestration
@@ -117,12 +125,33 @@ type OrchestrationStatus struct {
 	Status       string    `json:"status"`
 	PvcBackedOSD bool      `json:"pvc-backed-osd"`
 	Message      string    `json:"message"`
+	// DryRun is whether the prepare job only planned the devices to consume, the plan being in Plan
+	DryRun bool            `json:"dry-run,omitempty"`
+	Plan   []PlannedDevice `json:"plan,omitempty"`
+}
+
+// PlannedDevice is a device that an OSD prepare job would consume, reported in dry-run mode
+type PlannedDevice struct {
+	Name       string   `json:"name"`
+	Paths      []string `json:"paths,omitempty"`
+	Size       uint64   `json:"size"`
+	Type       string   `json:"type"`
+	Rotational bool     `json:"rotational"`
+	// Role is "data" for the devices of the OSDs, or "metadata", "db" or "wal" for the devices holding the
+	// metadata of the OSDs of other devices
+	Role           string `json:"role"`
+	DeviceClass    string `json:"deviceClass,omitempty"`
+	OSDsPerDevice  int    `json:"osdsPerDevice,omitempty"`
+	MetadataDevice string `json:"metadataDevice,omitempty"`
+	DriveGroup     string `json:"driveGroup,omitempty"`
 }
 
 type osdProperties struct {
 	//crushHostname refers to the hostname or PVC name when the OSD is provisioned on Nodes or PVC block device, respectively.
 	crushHostname       string
 	devices             []cephv1.Device
+	driveGroups         []cephv1.DriveGroup
+	replaceOSDs         []cephv1.OSDReplacement
 	pvc                 corev1.PersistentVolumeClaimVolumeSource
 	metadataPVC         corev1.PersistentVolumeClaimVolumeSource
 	walPVC              corev1.PersistentVolumeClaimVolumeSource
@@ -177,6 +206,18 @@ func (c *Cluster) Start() error {
 			}
 		}
 	}
+	if err := validateMemoryTarget(c.spec.Storage.MemoryTarget); err != nil {
+		return errors.Wrap(err, "failed to validate the memory target of the osds")
+	}
+	if err := validateTopologyLabels(c.spec.Storage.TopologyLabels); err != nil {
+		return errors.Wrap(err, "failed to validate the topology labels of the osds")
+	}
+	if err := validateCompression(&c.spec.Storage); err != nil {
+		return errors.Wrap(err, "failed to validate the compression of the osds")
+	}
+	if err := validateScrub(c.spec.Storage.Scrub); err != nil {
+		return errors.Wrap(err, "failed to validate the scrub schedule of the osds")
+	}
 	logger.Infof("start running osds in namespace %q", namespace)
 
 	if !c.spec.Storage.UseAllNodes && len(c.spec.Storage.Nodes) == 0 && len(c.spec.Storage.StorageClassDeviceSets) == 0 {
@@ -190,6 +231,16 @@ func (c *Cluster) Start() error {
 	}
 	logger.Infof("wait timeout for healthy OSDs during upgrade or restart is %q", c.clusterInfo.OsdUpgradeTimeout)
 
+	// destroy the OSDs to replace before the existing OSDs are updated, their devices are prepared again below
+	if err := c.startOSDReplacements(); err != nil {
+		return errors.Wrap(err, "failed to start the replacement of the OSDs")
+	}
+
+	// the new OSDs must start with a weight of 0 before they are created
+	if err := c.configureWeightRampUp(); err != nil {
+		return errors.Wrap(err, "failed to configure the ramp-up of the osd weights")
+	}
+
 	// prepare for updating existing OSDs
 	updateQueue, deployments, err := c.getOSDUpdateInfo(errs)
 	if err != nil {
@@ -197,8 +248,16 @@ func (c *Cluster) Start() error {
 	}
 	logger.Debugf("%d of %d OSD Deployments need updated", updateQueue.Len(), deployments.Len())
 	updateConfig := c.newUpdateConfig(config, updateQueue, deployments)
+	updateConfig.canary, err = controller.GetCanaryUpgrade(c.clusterInfo.Context, c.context, namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the canary upgrade settings")
+	}
 
 	// prepare for creating new OSDs
+	c.prepareScheduler = newPrepareScheduler(c)
+	if err := c.prepareScheduler.resume(); err != nil {
+		logger.Errorf("failed to resume the OSD provisioning, preparing all the nodes. %v", err)
+	}
 	statusConfigMaps := sets.NewString()
 
 	logger.Info("start provisioning the OSDs on PVCs, if needed")
@@ -208,6 +267,9 @@ func (c *Cluster) Start() error {
 	}
 	statusConfigMaps = statusConfigMaps.Union(pvcConfigMaps)
 
+	// the PVCs of the OSDs of the pod sets are not prepared again, their pods are updated here
+	c.updateOSDPodSets(config, errs)
+
 	logger.Info("start provisioning the OSDs on nodes, if needed")
 	nodeConfigMaps, err := c.startProvisioningOverNodes(config, errs)
 	if err != nil {
@@ -216,6 +278,9 @@ func (c *Cluster) Start() error {
 	statusConfigMaps = statusConfigMaps.Union(nodeConfigMaps)
 
 	createConfig := c.newCreateConfig(config, statusConfigMaps, deployments)
+	if err := c.prepareScheduler.updateStatus(false); err != nil {
+		logger.Errorf("failed to report the progress of the OSD provisioning. %v", err)
+	}
 
 	// do the update and create operations
 	err = c.updateAndCreateOSDs(createConfig, updateConfig, errs)
@@ -223,8 +288,34 @@ func (c *Cluster) Start() error {
 		return errors.Wrapf(err, "failed to update/create OSDs")
 	}
 
+	if err := c.prepareScheduler.updateStatus(true); err != nil {
+		logger.Errorf("failed to report the progress of the OSD provisioning. %v", err)
+	}
+
+	// the failures of the prepare jobs are reported whatever the failure policy
+	degraded := errs.len() > 0 && errs.onlyPrepareFailures() && c.spec.Storage.PrepareFailurePolicy == cephv1.PrepareFailurePolicyContinue
+	if err := c.updatePrepareFailuresStatus(errs.prepareFailures, degraded); err != nil {
+		logger.Errorf("failed to report the OSD prepare failures. %v", err)
+	}
+
+	if err := c.updateCapacityGuardStatus(createConfig.capacity); err != nil {
+		logger.Errorf("failed to report the rebalance to the new OSDs. %v", err)
+	}
+
+	if err := c.publishProvisioningPlan(createConfig.plans); err != nil {
+		logger.Errorf("failed to publish the provisioning plan of the OSDs. %v", err)
+	}
+
+	if err := c.completeOSDReplacements(); err != nil {
+		logger.Errorf("failed to report the OSD replacements. %v", err)
+	}
+
 	if errs.len() > 0 {
-		return errors.Errorf("%d failures encountered while running osds on nodes in namespace %q. %s",
+		if !degraded {
+			return errors.Errorf("%d failures encountered while running osds on nodes in namespace %q. %s",
+				errs.len(), namespace, errs.asMessages())
+		}
+		logger.Warningf("continuing the reconcile despite %d OSD prepare failures in namespace %q. %s",
 			errs.len(), namespace, errs.asMessages())
 	}
 
@@ -235,10 +326,45 @@ func (c *Cluster) Start() error {
 	// The following block is used to apply any command(s) required by an upgrade
 	c.applyUpgradeOSDFunctionality()
 
+	if err := c.configureMClock(); err != nil {
+		return errors.Wrap(err, "failed to configure the mclock scheduler of the osds")
+	}
+
+	if err := c.configureMemoryTarget(); err != nil {
+		return errors.Wrap(err, "failed to configure the memory target of the osds")
+	}
+
+	if err := c.configureScrub(); err != nil {
+		return errors.Wrap(err, "failed to configure the scrub schedule of the osds")
+	}
+
+	if err := c.configureCephConfig(); err != nil {
+		return errors.Wrap(err, "failed to configure the ceph config options of the osds")
+	}
+
+	// the topology is best effort, the osds are running anyway
+	if err := c.publishOSDTopology(); err != nil {
+		logger.Errorf("failed to publish the topology of the osds. %v", err)
+	}
+
 	logger.Infof("finished running OSDs in namespace %q", namespace)
 	return nil
 }
 
+// configureMClock applies the mclock scheduler settings of the cluster to all the OSDs. The
+// settings are left untouched if not specified, so they can still be managed manually.
+func (c *Cluster) configureMClock() error {
+	if c.spec.Storage.MClock == nil {
+		return nil
+	}
+	if !c.clusterInfo.CephVersion.IsAtLeastQuincy() {
+		logger.Warningf("mclock scheduler settings require ceph quincy or newer, not applying them on ceph version %q", c.clusterInfo.CephVersion.String())
+		return nil
+	}
+	monStore := opconfig.GetMonStore(c.context, c.clusterInfo)
+	return monStore.ApplyMClock("osd", c.spec.Storage.MClock)
+}
+
 func (c *Cluster) getExistingOSDDeploymentsOnPVCs() (sets.String, error) {
 	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s,%s", k8sutil.AppAttr, AppName, OSDOverPVCLabelKey)}
 
@@ -254,6 +380,16 @@ func (c *Cluster) getExistingOSDDeploymentsOnPVCs() (sets.String, error) {
 		}
 	}
 
+	// the OSDs of the pod sets run in pods instead of deployments
+	podListOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s,%s", OSDPodSetLabelKey, OSDOverPVCLabelKey)}
+	pods, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).List(c.clusterInfo.Context, podListOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query existing OSD pods of the pod sets")
+	}
+	for _, pod := range pods.Items {
+		result.Insert(pod.Labels[OSDOverPVCLabelKey])
+	}
+
 	return result, nil
 }
 
@@ -304,9 +440,11 @@ func deploymentOnPVC(c *Cluster, osd OSDInfo, pvcName string, config *provisionC
 func setOSDProperties(c *Cluster, osdProps osdProperties, osd OSDInfo) error {
 	// OSD's 'primary-affinity' has to be configured via command which goes through mons
 	if osdProps.storeConfig.PrimaryAffinity != "" {
-		return cephclient.SetPrimaryAffinity(c.context, c.clusterInfo, osd.ID, osdProps.storeConfig.PrimaryAffinity)
+		if err := cephclient.SetPrimaryAffinity(c.context, c.clusterInfo, osd.ID, osdProps.storeConfig.PrimaryAffinity); err != nil {
+			return err
+		}
 	}
-	return nil
+	return c.configureCompression(osd.ID, osdProps.storeConfig)
 }
 
 func (c *Cluster) resolveNode(nodeName, deviceClass string) *cephv1.Node {
@@ -385,6 +523,8 @@ func (c *Cluster) getOSDPropsForPVC(pvcName, osdDeviceClass string) (osdProperti
 			}
 			osdProps.storeConfig.InitialWeight = deviceSet.CrushInitialWeight
 			osdProps.storeConfig.PrimaryAffinity = deviceSet.CrushPrimaryAffinity
+			osdProps.storeConfig.CompressionMode = deviceSet.CompressionMode
+			osdProps.storeConfig.CompressionAlgorithm = deviceSet.CompressionAlgorithm
 
 			// If OSD isn't portable, we're getting the host name either from the osd deployment that was already initialized
 			// or from the osd prepare job from initial creation.
@@ -516,7 +656,7 @@ func (c *Cluster) getOSDInfo(d *appsv1.Deployment) (OSDInfo, error) {
 
 	// if the ROOK_TOPOLOGY_AFFINITY env var was not found in the loop above, detect it from the node
 	if isPVC && osd.TopologyAffinity == "" {
-		osd.TopologyAffinity, err = getTopologyFromNode(c.context.Clientset, d, osd)
+		osd.TopologyAffinity, err = getTopologyFromNode(c.context.Clientset, d, osd, c.spec.Storage.TopologyLabels)
 		if err != nil {
 			logger.Errorf("failed to get topology affinity for osd %d. %v", osd.ID, err)
 		}
@@ -534,7 +674,7 @@ func (c *Cluster) getOSDInfo(d *appsv1.Deployment) (OSDInfo, error) {
 	}
 
 	if !locationFound {
-		location, _, err := getLocationFromPod(c.context.Clientset, d, cephclient.GetCrushRootFromSpec(&c.spec))
+		location, _, err := getLocationFromPod(c.context.Clientset, d, cephclient.GetCrushRootFromSpec(&c.spec), c.spec.Storage.TopologyLabels)
 		if err != nil {
 			logger.Errorf("failed to get location. %v", err)
 		} else {
@@ -595,7 +735,7 @@ func getBlockPathFromActivateInitContainer(d *appsv1.Deployment) (string, error)
 	return "", errors.Errorf("failed to find activate init container")
 }
 
-func getLocationFromPod(clientset kubernetes.Interface, d *appsv1.Deployment, crushRoot string) (string, string, error) {
+func getLocationFromPod(clientset kubernetes.Interface, d *appsv1.Deployment, crushRoot string, topologyLabels []cephv1.CrushTopologyLabel) (string, string, error) {
 	ctx := context.TODO()
 	pods, err := clientset.CoreV1().Pods(d.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", OsdIdLabelKey, d.Labels[OsdIdLabelKey])})
 	if err != nil || len(pods.Items) == 0 {
@@ -613,10 +753,10 @@ func getLocationFromPod(clientset kubernetes.Interface, d *appsv1.Deployment, cr
 			hostName = pvcName
 		}
 	}
-	return GetLocationWithNode(clientset, nodeName, crushRoot, hostName)
+	return GetLocationWithNode(clientset, nodeName, crushRoot, hostName, topologyLabels)
 }
 
-func getTopologyFromNode(clientset kubernetes.Interface, d *appsv1.Deployment, osd OSDInfo) (string, error) {
+func getTopologyFromNode(clientset kubernetes.Interface, d *appsv1.Deployment, osd OSDInfo, topologyLabels []cephv1.CrushTopologyLabel) (string, error) {
 	portable, ok := d.GetLabels()[portableKey]
 	if !ok || portable != "true" {
 		// osd is not portable, no need to load the topology affinity
@@ -641,7 +781,7 @@ func getTopologyFromNode(clientset kubernetes.Interface, d *appsv1.Deployment, o
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get the node for topology affinity")
 	}
-	_, topologyAffinity := ExtractOSDTopologyFromLabels(node.Labels)
+	_, topologyAffinity := ExtractOSDTopologyFromLabels(node.Labels, topologyLabels)
 	logger.Infof("found osd %d topology affinity at %q", osd.ID, topologyAffinity)
 	return topologyAffinity, nil
 }
@@ -650,7 +790,8 @@ func getTopologyFromNode(clientset kubernetes.Interface, d *appsv1.Deployment, o
 //  location: The CRUSH properties for the OSD to apply
 //  topologyAffinity: The label to be applied to the OSD daemon to guarantee it will start in the same
 //		topology as the OSD prepare job.
-func GetLocationWithNode(clientset kubernetes.Interface, nodeName string, crushRoot, crushHostname string) (string, string, error) {
+// The topology labels are the custom topology labels of the storage spec.
+func GetLocationWithNode(clientset kubernetes.Interface, nodeName string, crushRoot, crushHostname string, topologyLabels []cephv1.CrushTopologyLabel) (string, string, error) {
 	node, err := getNode(clientset, nodeName)
 	if err != nil {
 		return "", "", errors.Wrap(err, "could not get the node for topology labels")
@@ -671,7 +812,7 @@ func GetLocationWithNode(clientset kubernetes.Interface, nodeName string, crushR
 	locArgs := []string{fmt.Sprintf("root=%s", crushRoot), fmt.Sprintf("host=%s", hostName)}
 
 	nodeLabels := node.GetLabels()
-	topologyAffinity := updateLocationWithNodeLabels(&locArgs, nodeLabels)
+	topologyAffinity := updateLocationWithNodeLabels(&locArgs, nodeLabels, topologyLabels)
 
 	loc := strings.Join(locArgs, " ")
 	logger.Infof("CRUSH location=%s", loc)
@@ -700,8 +841,8 @@ func getNode(clientset kubernetes.Interface, nodeName string) (*corev1.Node, err
 	return node, nil
 }
 
-func updateLocationWithNodeLabels(location *[]string, nodeLabels map[string]string) string {
-	topology, topologyAffinity := ExtractOSDTopologyFromLabels(nodeLabels)
+func updateLocationWithNodeLabels(location *[]string, nodeLabels map[string]string, topologyLabels []cephv1.CrushTopologyLabel) string {
+	topology, topologyAffinity := ExtractOSDTopologyFromLabels(nodeLabels, topologyLabels)
 
 	keys := make([]string, 0, len(topology))
 	for k := range topology {
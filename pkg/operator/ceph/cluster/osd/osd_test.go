@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	fakeclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephclientfake "github.com/rook/rook/pkg/daemon/ceph/client/fake"
@@ -44,6 +45,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestOSDProperties(t *testing.T) {
@@ -69,8 +71,11 @@ func TestStart(t *testing.T) {
 		Namespace:   "ns",
 		CephVersion: cephver.Nautilus,
 		Context:     context.TODO(),
+		OwnerInfo:   cephclient.NewMinimumOwnerInfoWithOwnerRef(),
 	}
-	context := &clusterd.Context{Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}
+	clusterInfo.SetName("rook-ceph-test")
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	context := &clusterd.Context{Client: client, Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}
 	spec := cephv1.ClusterSpec{}
 	c := New(context, clusterInfo, spec, "myversion")
 
@@ -163,6 +168,7 @@ func TestAddRemoveNode(t *testing.T) {
 	}
 
 	context := &clusterd.Context{
+		Client:        clientfake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		Clientset:     clientset,
 		ConfigDir:     "/var/lib/rook",
 		Executor:      executor,
@@ -297,7 +303,7 @@ func TestAddRemoveNode(t *testing.T) {
 	assert.NoError(t, err)
 
 	removeIfOutAndSafeToRemove := true
-	healthMon := NewOSDHealthMonitor(context, cephclient.AdminClusterInfo(namespace), removeIfOutAndSafeToRemove, cephv1.CephClusterHealthCheckSpec{})
+	healthMon := NewOSDHealthMonitor(context, cephclient.AdminClusterInfo(namespace), removeIfOutAndSafeToRemove, nil, cephv1.CephClusterHealthCheckSpec{})
 	healthMon.checkOSDHealth()
 	_, err = clientset.AppsV1().Deployments(namespace).Get(ctx, deploymentName(1), metav1.GetOptions{})
 	assert.True(t, k8serrors.IsNotFound(err))
@@ -328,7 +334,9 @@ func TestAddNodeFailure(t *testing.T) {
 	}
 	clusterInfo.SetName("testcluster")
 	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
-	context := &clusterd.Context{Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "testcluster", Namespace: "ns-add-remove"}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	context := &clusterd.Context{Client: client, Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}
 	spec := cephv1.ClusterSpec{
 		DataDirHostPath: context.ConfigDir,
 		Storage: cephv1.StorageScopeSpec{
@@ -358,6 +366,30 @@ func TestAddNodeFailure(t *testing.T) {
 	// verify orchestration failed (because the operator failed to create a job)
 	assert.True(t, startCompleted)
 	assert.NotNil(t, startErr)
+
+	// the failure of the node is reported in the status
+	err := client.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(cephCluster.Status.OSDPrepareFailures))
+	assert.Equal(t, nodeName, cephCluster.Status.OSDPrepareFailures[0].Name)
+	assert.Nil(t, cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded))
+
+	// the orchestration continues with the continue policy and the cluster is degraded
+	c.spec.Storage.PrepareFailurePolicy = cephv1.PrepareFailurePolicyContinue
+	startCompleted = false
+	go func() {
+		startErr = c.Start()
+		startCompleted = true
+	}()
+	waitForOrchestrationCompletion(c, nodeName, &startCompleted)
+	assert.NoError(t, startErr)
+	err = client.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(cephCluster.Status.OSDPrepareFailures))
+	degraded := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	assert.NotNil(t, degraded)
+	assert.Equal(t, corev1.ConditionTrue, degraded.Status)
+	assert.Equal(t, cephv1.OSDPrepareFailedReason, degraded.Reason)
 }
 
 func TestGetPVCHostName(t *testing.T) {
@@ -549,7 +581,7 @@ func TestDetectCrushLocation(t *testing.T) {
 	nodeLabels := map[string]string{}
 
 	// no change to the location if there are no labels
-	updateLocationWithNodeLabels(&location, nodeLabels)
+	updateLocationWithNodeLabels(&location, nodeLabels, nil)
 	assert.Equal(t, 1, len(location))
 	assert.Equal(t, "host=foo", location[0])
 
@@ -559,7 +591,7 @@ func TestDetectCrushLocation(t *testing.T) {
 		"invalid.topology.rook.io/rack": "r1",
 		"topology.rook.io/zone":         "z1",
 	}
-	updateLocationWithNodeLabels(&location, nodeLabels)
+	updateLocationWithNodeLabels(&location, nodeLabels, nil)
 	assert.Equal(t, 1, len(location))
 	assert.Equal(t, "host=foo", location[0])
 
@@ -578,7 +610,7 @@ func TestDetectCrushLocation(t *testing.T) {
 		"row=row1",
 		"zone=zone1",
 	}
-	updateLocationWithNodeLabels(&location, nodeLabels)
+	updateLocationWithNodeLabels(&location, nodeLabels, nil)
 
 	assert.Equal(t, 5, len(location))
 	for i, locString := range location {
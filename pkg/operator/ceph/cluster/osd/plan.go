@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProvisioningPlanConfigMapName is the name of the configmap with the devices the OSD prepare jobs would consume
+// in dry-run mode, in a key for each node or PVC
+const ProvisioningPlanConfigMapName = "rook-ceph-osd-provisioning-plan"
+
+// publishProvisioningPlan publishes the devices the prepare jobs would consume in dry-run mode, in the provisioning
+// plan configmap and the status of the CephCluster. The plan is removed when the dry-run mode is disabled.
+func (c *Cluster) publishProvisioningPlan(plans map[string]*OrchestrationStatus) error {
+	if !c.spec.Storage.DryRun {
+		err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Delete(c.clusterInfo.Context, ProvisioningPlanConfigMapName, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete configmap %q", ProvisioningPlanConfigMapName)
+		}
+		return c.updateProvisioningPlanStatus(nil)
+	}
+
+	data := map[string]string{}
+	status := &cephv1.OSDProvisioningPlan{Time: time.Now().UTC().Format(time.RFC3339), Nodes: []cephv1.OSDNodeProvisioningPlan{}}
+	for name, plan := range plans {
+		value, err := json.Marshal(plan.Plan)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize the provisioning plan of %q", name)
+		}
+		data[name] = string(value)
+
+		node := cephv1.OSDNodeProvisioningPlan{Name: name, PVC: plan.PvcBackedOSD}
+		for _, device := range plan.Plan {
+			node.Devices = append(node.Devices, device.Name)
+		}
+		status.Nodes = append(status.Nodes, node)
+	}
+	sort.Slice(status.Nodes, func(i, j int) bool { return status.Nodes[i].Name < status.Nodes[j].Name })
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ProvisioningPlanConfigMapName,
+			Namespace: c.clusterInfo.Namespace,
+		},
+		Data: data,
+	}
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(configMap); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to configmap %q", configMap.Name)
+	}
+	if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Create(c.clusterInfo.Context, configMap, metav1.CreateOptions{}); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create configmap %q", configMap.Name)
+		}
+		if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.clusterInfo.Namespace).Update(c.clusterInfo.Context, configMap, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update configmap %q", configMap.Name)
+		}
+	}
+	logger.Infof("published the provisioning plan of the osds on %d nodes or PVCs in configmap %q", len(data), configMap.Name)
+
+	return c.updateProvisioningPlanStatus(status)
+}
+
+func (c *Cluster) updateProvisioningPlanStatus(plan *cephv1.OSDProvisioningPlan) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.clusterInfo.NamespacedName().String())
+	}
+
+	if reflect.DeepEqual(cephCluster.Status.OSDProvisioningPlan, plan) {
+		return nil
+	}
+	cephCluster.Status.OSDProvisioningPlan = plan
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the OSD provisioning plan")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPublishProvisioningPlan(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	clusterInfo := cephclient.AdminClusterInfo(namespace)
+	clusterInfo.SetName("testcluster")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "testcluster", Namespace: namespace}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := fake.NewSimpleClientset()
+	c := &Cluster{
+		context:     &clusterd.Context{Client: client, Clientset: clientset},
+		clusterInfo: clusterInfo,
+		spec:        cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{DryRun: true}},
+	}
+	getStatus := func() *cephv1.OSDProvisioningPlan {
+		cluster := &cephv1.CephCluster{}
+		require.NoError(t, client.Get(ctx, clusterInfo.NamespacedName(), cluster))
+		return cluster.Status.OSDProvisioningPlan
+	}
+
+	plans := map[string]*OrchestrationStatus{
+		"node1": {DryRun: true, Plan: []PlannedDevice{{Name: "/dev/sdb", Role: "data"}, {Name: "/dev/sdc", Role: "data"}}},
+		"pvc0":  {DryRun: true, PvcBackedOSD: true, Plan: []PlannedDevice{{Name: "/mnt/pvc0", Role: "data"}}},
+		"node0": {DryRun: true, Plan: []PlannedDevice{}},
+	}
+	require.NoError(t, c.publishProvisioningPlan(plans))
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ProvisioningPlanConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"/dev/sdb","size":0,"type":"","rotational":false,"role":"data"},{"name":"/dev/sdc","size":0,"type":"","rotational":false,"role":"data"}]`, configMap.Data["node1"])
+	assert.Equal(t, "[]", configMap.Data["node0"])
+	status := getStatus()
+	require.NotNil(t, status)
+	assert.NotEmpty(t, status.Time)
+	assert.Equal(t, []cephv1.OSDNodeProvisioningPlan{
+		{Name: "node0"},
+		{Name: "node1", Devices: []string{"/dev/sdb", "/dev/sdc"}},
+		{Name: "pvc0", PVC: true, Devices: []string{"/mnt/pvc0"}},
+	}, status.Nodes)
+
+	// the plan is updated by the next dry-run
+	delete(plans, "node1")
+	require.NoError(t, c.publishProvisioningPlan(plans))
+	configMap, err = clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ProvisioningPlanConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, configMap.Data, "node1")
+	assert.Equal(t, 2, len(getStatus().Nodes))
+
+	// the plan is removed with the dry-run mode
+	c.spec.Storage.DryRun = false
+	require.NoError(t, c.publishProvisioningPlan(map[string]*OrchestrationStatus{}))
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ProvisioningPlanConfigMapName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	assert.Nil(t, getStatus())
+	require.NoError(t, c.publishProvisioningPlan(map[string]*OrchestrationStatus{}))
+}
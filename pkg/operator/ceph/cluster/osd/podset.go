@@ -0,0 +1,267 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OSDPodSetLabelKey is the label of the OSD pods managed by the operator instead of a deployment, with the
+	// name of their device set
+	OSDPodSetLabelKey = "ceph.rook.io/osd-pod-set"
+	// osdPodSpecHashAnnotation is the hash of the spec of an OSD pod of a pod set, to find the pods to update
+	osdPodSpecHashAnnotation = "ceph.rook.io/pod-spec-hash"
+)
+
+// podSetOfPVC returns the name of the device set of the PVC if the new OSDs of the device set run in a pod set
+func (c *Cluster) podSetOfPVC(pvcName string) (string, bool) {
+	for _, deviceSet := range c.deviceSets {
+		if dataSource, ok := deviceSet.PVCSources[bluestorePVCData]; ok && dataSource.ClaimName == pvcName {
+			return deviceSet.Name, deviceSet.PodSet
+		}
+	}
+	return "", false
+}
+
+// makeOSDPod returns the pod of an OSD of a pod set, with the pod template of the deployment that would run the OSD
+func makeOSDPod(d *appsv1.Deployment, deviceSetName string) (*v1.Pod, error) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            d.Name,
+			Namespace:       d.Namespace,
+			Labels:          map[string]string{},
+			Annotations:     map[string]string{},
+			OwnerReferences: d.OwnerReferences,
+		},
+		Spec: *d.Spec.Template.Spec.DeepCopy(),
+	}
+	for k, v := range d.Labels {
+		pod.Labels[k] = v
+	}
+	for k, v := range d.Spec.Template.Labels {
+		pod.Labels[k] = v
+	}
+	pod.Labels[OSDPodSetLabelKey] = deviceSetName
+	for k, v := range d.Spec.Template.Annotations {
+		pod.Annotations[k] = v
+	}
+
+	spec, err := json.Marshal(pod.Spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the spec of pod %q", pod.Name)
+	}
+	pod.Annotations[osdPodSpecHashAnnotation] = k8sutil.Hash(string(spec))
+	return pod, nil
+}
+
+// newOSDPodFrom returns a new pod with the metadata and the spec of an existing pod of a pod set, to create the
+// pod again on any node its placement allows
+func newOSDPodFrom(pod *v1.Pod) *v1.Pod {
+	newPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			Labels:          pod.Labels,
+			Annotations:     pod.Annotations,
+			OwnerReferences: pod.OwnerReferences,
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	newPod.Spec.NodeName = ""
+	return newPod
+}
+
+// getOSDPod returns the pod of the OSD if the OSD runs in a pod set, nil otherwise
+func (c *Cluster) getOSDPod(osdID int) (*v1.Pod, error) {
+	pod, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, deploymentName(osdID), metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the pod of osd %d", osdID)
+	}
+	if _, ok := pod.Labels[OSDPodSetLabelKey]; !ok {
+		return nil, nil
+	}
+	return pod, nil
+}
+
+// reconcileOSDPod creates the pod of an OSD of a pod set, or creates it again if its spec changed and the OSD is
+// ok to stop
+func (c *Cluster) reconcileOSDPod(d *appsv1.Deployment, deviceSetName string, existing *v1.Pod) error {
+	osdID, err := getOSDID(d)
+	if err != nil {
+		return err
+	}
+	pod, err := makeOSDPod(d, deviceSetName)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		if existing.Annotations[osdPodSpecHashAnnotation] == pod.Annotations[osdPodSpecHashAnnotation] {
+			logger.Debugf("pod of osd %d is up to date", osdID)
+			return nil
+		}
+		if shouldCheckOkToStopFunc(c.context, c.clusterInfo) {
+			if _, err := cephclient.OSDOkToStop(c.context, c.clusterInfo, osdID, 1); err != nil {
+				if !c.spec.ContinueUpgradeAfterChecksEvenIfNotHealthy {
+					logger.Infof("osd %d is not ok-to-stop. will try updating its pod again later", osdID)
+					return nil
+				}
+				logger.Infof("osd %d is not ok-to-stop but 'continueUpgradeAfterChecksEvenIfNotHealthy' is true, so continuing to update it", osdID)
+			}
+		}
+		logger.Infof("updating the pod of osd %d of pod set %q", osdID, deviceSetName)
+		if err := k8sutil.DeletePod(c.context.Clientset, c.clusterInfo.Namespace, existing.Name); err != nil {
+			return errors.Wrapf(err, "failed to delete the pod of osd %d to update it", osdID)
+		}
+	}
+
+	if _, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).Create(c.clusterInfo.Context, pod, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create the pod of osd %d", osdID)
+	}
+	logger.Infof("started the pod of osd %d of pod set %q", osdID, deviceSetName)
+	return nil
+}
+
+// updateOSDPodSets updates the pods of the OSDs of the pod sets whose spec changed. Their PVCs are not prepared
+// again once their OSD runs, so the pods are not updated when the OSDs are created.
+func (c *Cluster) updateOSDPodSets(config *provisionConfig, errs *provisionErrors) {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s,%s", OSDPodSetLabelKey, OSDOverPVCLabelKey)}
+	pods, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).List(c.clusterInfo.Context, opts)
+	if err != nil {
+		errs.addError("failed to list the pods of the osd pod sets. %v", err)
+		return
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		pvcName := pod.Labels[OSDOverPVCLabelKey]
+		osdInfo, err := c.getOSDInfo(deploymentOfOSDPod(pod))
+		if err != nil {
+			errs.addError("failed to update the pod %q of the osd pod set. %v", pod.Name, err)
+			continue
+		}
+		d, err := deploymentOnPVCFunc(c, osdInfo, pvcName, config)
+		if err != nil {
+			errs.addError("failed to update osd %d on PVC %q. %v", osdInfo.ID, pvcName, err)
+			continue
+		}
+		if err := c.reconcileOSDPod(d, pod.Labels[OSDPodSetLabelKey], pod); err != nil {
+			errs.addError("failed to update osd %d on PVC %q. %v", osdInfo.ID, pvcName, err)
+		}
+	}
+}
+
+// deploymentOfOSDPod returns a deployment with the metadata and the spec of the pod of an OSD of a pod set, to read
+// the OSD info the same way as from the deployment of an OSD
+func deploymentOfOSDPod(pod *v1.Pod) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Labels:    pod.Labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: pod.Labels},
+				Spec:       pod.Spec,
+			},
+		},
+	}
+}
+
+// checkOSDPodSets creates again the pods of the OSDs of the pod sets that failed, such as when evicted, or that were
+// deleted since the last check. The pods of the OSDs that do not exist anymore are not created again.
+func (m *OSDHealthMonitor) checkOSDPodSets() error {
+	opts := metav1.ListOptions{LabelSelector: OSDPodSetLabelKey}
+	pods, err := m.context.Clientset.CoreV1().Pods(m.clusterInfo.Namespace).List(m.clusterInfo.Context, opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the pods of the osd pod sets")
+	}
+	if len(pods.Items) == 0 && len(m.podSetPods) == 0 {
+		return nil
+	}
+
+	current := map[string]*v1.Pod{}
+	toCreate := map[string]*v1.Pod{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		current[pod.Name] = newOSDPodFrom(pod)
+		if pod.Status.Phase == v1.PodFailed {
+			logger.Infof("deleting the failed pod %q of the osd pod set. %s", pod.Name, pod.Status.Reason)
+			if err := k8sutil.DeletePod(m.context.Clientset, pod.Namespace, pod.Name); err != nil {
+				logger.Errorf("failed to delete failed pod %q. %v", pod.Name, err)
+				continue
+			}
+			toCreate[pod.Name] = current[pod.Name]
+		}
+	}
+	for name, pod := range m.podSetPods {
+		if _, ok := current[name]; !ok {
+			logger.Infof("pod %q of the osd pod set was deleted", name)
+			current[name] = pod
+			toCreate[name] = pod
+		}
+	}
+	m.podSetPods = current
+	if len(toCreate) == 0 {
+		return nil
+	}
+
+	osdDump, err := cephclient.GetOSDDump(m.context, m.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd dump")
+	}
+	existingOSDs := map[string]bool{}
+	for _, osd := range osdDump.OSDs {
+		existingOSDs[osd.OSD.String()] = true
+	}
+
+	for name, pod := range toCreate {
+		osdID := pod.Labels[OsdIdLabelKey]
+		if _, err := strconv.Atoi(osdID); err != nil || !existingOSDs[osdID] {
+			logger.Infof("not creating again pod %q since osd %q does not exist anymore", name, osdID)
+			delete(m.podSetPods, name)
+			continue
+		}
+		if _, err := m.context.Clientset.CoreV1().Pods(pod.Namespace).Create(m.clusterInfo.Context, pod, metav1.CreateOptions{}); err != nil {
+			logger.Errorf("failed to create again pod %q of osd %s. %v", name, osdID, err)
+			continue
+		}
+		logger.Infof("created again pod %q of osd %s", name, osdID)
+		target := fmt.Sprintf("osd.%s", osdID)
+		if err := reporting.RecordCorrectiveAction(m.clusterInfo.Context, m.context.Client, m.clusterInfo.NamespacedName(), cephv1.CorrectiveActionOSDPodRecreated, target, "the pod failed or was deleted"); err != nil {
+			logger.Warningf("failed to record the creation of the pod of osd %s. %v", osdID, err)
+		}
+	}
+	return nil
+}
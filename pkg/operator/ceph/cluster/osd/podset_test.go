@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testexec "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPodSetDeployment(osdID, image string) *apps.Deployment {
+	labels := map[string]string{
+		k8sutil.AppAttr: AppName,
+		OsdIdLabelKey:   osdID,
+	}
+	return &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-" + osdID, Namespace: "ns", Labels: labels},
+		Spec: apps.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					NodeName:   "node1",
+					Containers: []corev1.Container{{Name: "osd", Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func TestReconcileOSDPod(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testexec.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", Context: ctx}
+	c := New(&clusterd.Context{Clientset: clientset}, clusterInfo, cephv1.ClusterSpec{}, "myversion")
+
+	oldShouldCheckFunc := shouldCheckOkToStopFunc
+	defer func() { shouldCheckOkToStopFunc = oldShouldCheckFunc }()
+	shouldCheckOkToStopFunc = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) bool {
+		return false
+	}
+
+	// the pod is created with the template of the deployment
+	d := newPodSetDeployment("1", "ceph:v15")
+	err := c.reconcileOSDPod(d, "set1", nil)
+	assert.NoError(t, err)
+	pod, err := c.getOSDPod(1)
+	require.NoError(t, err)
+	require.NotNil(t, pod)
+	assert.Equal(t, "set1", pod.Labels[OSDPodSetLabelKey])
+	assert.Equal(t, "1", pod.Labels[OsdIdLabelKey])
+	assert.Equal(t, "ceph:v15", pod.Spec.Containers[0].Image)
+	hash := pod.Annotations[osdPodSpecHashAnnotation]
+	assert.NotEmpty(t, hash)
+
+	// the pod is not touched if its spec did not change
+	pod.Labels["unchanged"] = "true"
+	_, err = clientset.CoreV1().Pods("ns").Update(ctx, pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = c.reconcileOSDPod(d, "set1", pod)
+	assert.NoError(t, err)
+	pod, err = c.getOSDPod(1)
+	require.NoError(t, err)
+	assert.Equal(t, "true", pod.Labels["unchanged"])
+
+	// the pod is created again if its spec changed
+	d = newPodSetDeployment("1", "ceph:v16")
+	err = c.reconcileOSDPod(d, "set1", pod)
+	assert.NoError(t, err)
+	pod, err = c.getOSDPod(1)
+	require.NoError(t, err)
+	assert.Equal(t, "ceph:v16", pod.Spec.Containers[0].Image)
+	assert.NotEqual(t, hash, pod.Annotations[osdPodSpecHashAnnotation])
+	_, ok := pod.Labels["unchanged"]
+	assert.False(t, ok)
+
+	// the pods without the pod set label are not pods of a pod set
+	delete(pod.Labels, OSDPodSetLabelKey)
+	_, err = clientset.CoreV1().Pods("ns").Update(ctx, pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	pod, err = c.getOSDPod(1)
+	assert.NoError(t, err)
+	assert.Nil(t, pod)
+}
+
+func TestCheckOSDPodSets(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testexec.New(t, 1)
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "dump" {
+				// osd 2 was removed from the cluster
+				return `{"OSDs": [{"OSD": 0, "Up": 1, "In": 1}, {"OSD": 1, "Up": 0, "In": 1}]}`, nil
+			}
+			return "", nil
+		},
+	}
+	nsName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+	context := &clusterd.Context{
+		Client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build(),
+		Executor:  executor,
+		Clientset: clientset,
+	}
+	m := NewOSDHealthMonitor(context, clusterInfo, false, nil, cephv1.CephClusterHealthCheckSpec{})
+
+	for _, id := range []string{"0", "1", "2"} {
+		pod, err := makeOSDPod(newPodSetDeployment(id, "ceph:v16"), "set1")
+		require.NoError(t, err)
+		pod.Namespace = clusterInfo.Namespace
+		_, err = clientset.CoreV1().Pods(clusterInfo.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	// the pods are only recorded at the first check
+	assert.NoError(t, m.checkOSDPodSets())
+	assert.Equal(t, 3, len(m.podSetPods))
+
+	// the failed pod of osd 0 is created again on any node
+	pod, err := clientset.CoreV1().Pods(clusterInfo.Namespace).Get(ctx, "rook-ceph-osd-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	pod.Status.Phase = corev1.PodFailed
+	pod.Status.Reason = "Evicted"
+	_, err = clientset.CoreV1().Pods(clusterInfo.Namespace).Update(ctx, pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	// the pods of osd 1 and 2 are deleted
+	for _, name := range []string{"rook-ceph-osd-1", "rook-ceph-osd-2"} {
+		err = clientset.CoreV1().Pods(clusterInfo.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		require.NoError(t, err)
+	}
+
+	assert.NoError(t, m.checkOSDPodSets())
+	pod, err = clientset.CoreV1().Pods(clusterInfo.Namespace).Get(ctx, "rook-ceph-osd-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.PodPhase(""), pod.Status.Phase)
+	assert.Equal(t, "", pod.Spec.NodeName)
+	_, err = clientset.CoreV1().Pods(clusterInfo.Namespace).Get(ctx, "rook-ceph-osd-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	// osd 2 does not exist anymore
+	_, err = clientset.CoreV1().Pods(clusterInfo.Namespace).Get(ctx, "rook-ceph-osd-2", metav1.GetOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, 2, len(m.podSetPods))
+
+	cluster := &cephv1.CephCluster{}
+	err = context.Client.Get(ctx, nsName, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(cluster.Status.CorrectiveActions))
+}
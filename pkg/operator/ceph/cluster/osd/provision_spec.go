@@ -156,6 +156,8 @@ func (c *Cluster) provisionPodTemplateSpec(osdProps osdProperties, restart v1.Re
 	if c.spec.Network.IsHost() {
 		podSpec.DNSPolicy = v1.DNSClusterFirstWithHostNet
 	}
+	cephv1.GetDaemonDNS(c.spec.DNS, cephv1.KeyOSDPrepare).ApplyToPodSpec(&podSpec)
+	cephv1.GetDaemonContainerOverrides(c.spec.ContainerOverrides, cephv1.KeyOSDPrepare).ApplyToPodSpec(&podSpec)
 	if osdProps.onPVC() {
 		c.applyAllPlacementIfNeeded(&podSpec)
 		// apply storageClassDeviceSets.preparePlacement
@@ -223,6 +225,32 @@ func (c *Cluster) provisionOSDContainer(osdProps osdProperties, copyBinariesMoun
 	} else if osdProps.selection.GetUseAllDevices() {
 		envVars = append(envVars, deviceFilterEnvVar("all"))
 	}
+	if len(osdProps.driveGroups) > 0 && !osdProps.onPVC() {
+		marshalledDriveGroups, err := json.Marshal(osdProps.driveGroups)
+		if err != nil {
+			return v1.Container{}, errors.Wrapf(err, "failed to JSON marshal drive groups for node %q", osdProps.crushHostname)
+		}
+		envVars = append(envVars, driveGroupsEnvVar(string(marshalledDriveGroups)))
+	}
+	if len(osdProps.replaceOSDs) > 0 {
+		marshalledReplacements, err := json.Marshal(osdProps.replaceOSDs)
+		if err != nil {
+			return v1.Container{}, errors.Wrapf(err, "failed to JSON marshal the OSDs replaced on %q", osdProps.crushHostname)
+		}
+		envVars = append(envVars, replaceOSDsEnvVar(string(marshalledReplacements)))
+	}
+	if c.spec.Storage.DryRun {
+		envVars = append(envVars, dryRunEnvVar())
+	}
+	if len(c.spec.Storage.TopologyLabels) > 0 {
+		marshalledTopologyLabels, err := json.Marshal(c.spec.Storage.TopologyLabels)
+		if err != nil {
+			return v1.Container{}, errors.Wrap(err, "failed to JSON marshal the topology labels")
+		}
+		envVars = append(envVars, topologyLabelsEnvVar(string(marshalledTopologyLabels)))
+	}
+	// the prepare job holds the provisioning lease of its node while it enumerates and prepares the devices
+	envVars = append(envVars, k8sutil.NameEnvVar(), provisioningLeaseNamespaceEnvVar())
 	envVars = append(envVars, v1.EnvVar{Name: "ROOK_CEPH_VERSION", Value: c.clusterInfo.CephVersion.CephVersionFormatted()})
 	envVars = append(envVars, crushDeviceClassEnvVar(osdProps.storeConfig.DeviceClass))
 	envVars = append(envVars, crushInitialWeightEnvVar(osdProps.storeConfig.InitialWeight))
@@ -0,0 +1,267 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// startOSDReplacements destroys the OSDs whose deployment is annotated for replacement. The replacements
+// are recorded in the status of the cluster before the deployments of the OSDs are deleted, so that the
+// OSDs are still tracked if they fail to be destroyed, and before the prepare jobs wipe and prepare the
+// devices again.
+func (c *Cluster) startOSDReplacements() error {
+	replacements, err := c.getOSDReplacements()
+	if err != nil {
+		return err
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := c.context.Clientset.AppsV1().Deployments(c.clusterInfo.Namespace).List(c.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osd deployments")
+	}
+	deployed := map[int]bool{}
+	for i := range deployments.Items {
+		if osdID, err := getOSDID(&deployments.Items[i]); err == nil {
+			deployed[osdID] = true
+		}
+	}
+
+	// the replacements of the OSDs still deployed are checked again below, so they are dropped if the
+	// annotation was removed
+	cancelled := map[int]bool{}
+	c.replacements = []cephv1.OSDReplacement{}
+	for _, replacement := range replacements {
+		if replacement.Phase == cephv1.OSDReplacementPending ||
+			(replacement.Phase == cephv1.OSDReplacementDestroying && deployed[replacement.ID]) {
+			cancelled[replacement.ID] = true
+			continue
+		}
+		c.replacements = append(c.replacements, replacement)
+	}
+
+	// retry destroying the OSDs whose deployment is already deleted
+	for _, replacement := range c.replacements {
+		if replacement.Phase == cephv1.OSDReplacementDestroying {
+			if err := c.destroyReplacedOSD(replacement); err != nil {
+				return err
+			}
+		}
+	}
+
+	var osdDump *cephclient.OSDDump
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if !controller.IsOSDReplacementRequested(d.Annotations) {
+			continue
+		}
+		if osdDump == nil {
+			osdDump, err = cephclient.GetOSDDump(c.context, c.clusterInfo)
+			if err != nil {
+				return errors.Wrap(err, "failed to get the osd dump")
+			}
+		}
+
+		osdID, err := c.replaceOSD(d, osdDump)
+		if err != nil {
+			return err
+		}
+		delete(cancelled, osdID)
+	}
+
+	// the OSDs drained for a replacement are marked in again when the annotation is removed
+	for osdID := range cancelled {
+		logger.Infof("replacement of osd.%d cancelled, marking it in again", osdID)
+		if err := cephclient.OSDIn(c.context, c.clusterInfo, osdID); err != nil {
+			return err
+		}
+	}
+
+	return c.updateOSDReplacementsStatus()
+}
+
+// replaceOSD drains the OSD of the deployment and destroys it once no placement group is mapped to it
+// anymore. The OSD is not drained while stopping it would make placement groups inactive.
+func (c *Cluster) replaceOSD(d *appsv1.Deployment, osdDump *cephclient.OSDDump) (int, error) {
+	osd, err := c.getOSDInfo(d)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get the osd info of deployment %q", d.Name)
+	}
+	nodeOrPVCName, err := getNodeOrPVCName(d)
+	if err != nil {
+		return 0, err
+	}
+	replacement := cephv1.OSDReplacement{ID: osd.ID, Name: nodeOrPVCName, Path: osd.BlockPath, Phase: cephv1.OSDReplacementPending}
+
+	up, _, err := osdDump.StatusByID(int64(osd.ID))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get the status of osd.%d", osd.ID)
+	}
+	const upStatus int64 = 1
+	if up == upStatus {
+		if _, err := cephclient.OSDOkToStop(c.context, c.clusterInfo, osd.ID, 1); err != nil {
+			logger.Infof("waiting to replace osd.%d until it can be stopped. %v", osd.ID, err)
+			replacement.Message = fmt.Sprintf("osd.%d cannot be stopped yet without making placement groups inactive", osd.ID)
+			c.setOSDReplacement(replacement)
+			return osd.ID, nil
+		}
+	}
+
+	// ceph refuses to destroy the OSD until its placement groups are moved to the other OSDs
+	if _, err := cephclient.OSDOut(c.context, c.clusterInfo, osd.ID); err != nil {
+		return 0, errors.Wrapf(err, "failed to mark osd.%d out", osd.ID)
+	}
+	safeToDestroy, err := cephclient.OsdSafeToDestroy(c.context, c.clusterInfo, osd.ID)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to check if osd.%d is safe to destroy", osd.ID)
+	}
+	if !safeToDestroy {
+		logger.Infof("waiting to replace osd.%d until its placement groups are moved to the other osds", osd.ID)
+		replacement.Message = fmt.Sprintf("osd.%d is out, waiting for its placement groups to move to the other OSDs", osd.ID)
+		c.setOSDReplacement(replacement)
+		return osd.ID, nil
+	}
+
+	// record the replacement before the deployment is deleted, the annotation is deleted with it
+	logger.Infof("replacing osd.%d on %q", osd.ID, nodeOrPVCName)
+	replacement.Phase = cephv1.OSDReplacementDestroying
+	replacement.Message = fmt.Sprintf("osd.%d is safe to destroy", osd.ID)
+	c.setOSDReplacement(replacement)
+	if err := c.updateOSDReplacementsStatus(); err != nil {
+		return 0, err
+	}
+	if err := k8sutil.DeleteDeployment(c.context.Clientset, c.clusterInfo.Namespace, d.Name); err != nil {
+		return 0, errors.Wrapf(err, "failed to delete the deployment of osd.%d", osd.ID)
+	}
+	return osd.ID, c.destroyReplacedOSD(replacement)
+}
+
+// destroyReplacedOSD destroys the OSD whose deployment was deleted for its replacement. The replacement
+// stays in the Destroying phase until the OSD is destroyed, so that a failure is retried at the next
+// reconcile.
+func (c *Cluster) destroyReplacedOSD(replacement cephv1.OSDReplacement) error {
+	if err := cephclient.DestroyOSD(c.context, c.clusterInfo, replacement.ID); err != nil {
+		replacement.Message = fmt.Sprintf("failed to destroy osd.%d, retrying. %v", replacement.ID, err)
+		c.setOSDReplacement(replacement)
+		if statusErr := c.updateOSDReplacementsStatus(); statusErr != nil {
+			logger.Errorf("failed to record the failure to destroy osd.%d. %v", replacement.ID, statusErr)
+		}
+		return err
+	}
+
+	replacement.Phase = cephv1.OSDReplacementPreparing
+	replacement.Message = fmt.Sprintf("osd.%d is destroyed, its device is wiped and prepared again", replacement.ID)
+	replacement.StartTime = time.Now().UTC().Format(time.RFC3339)
+	c.setOSDReplacement(replacement)
+	// record the destroyed OSD right away, its device is wiped by the prepare job
+	return c.updateOSDReplacementsStatus()
+}
+
+// completeOSDReplacements completes the replacements of the OSDs running again
+func (c *Cluster) completeOSDReplacements() error {
+	for i, replacement := range c.replacements {
+		if replacement.Phase != cephv1.OSDReplacementPreparing {
+			continue
+		}
+		deploymentName := fmt.Sprintf(osdAppNameFmt, replacement.ID)
+		_, err := c.context.Clientset.AppsV1().Deployments(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, deploymentName, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				logger.Infof("osd.%d on %q is not prepared again yet", replacement.ID, replacement.Name)
+				continue
+			}
+			return errors.Wrapf(err, "failed to get the deployment of osd.%d", replacement.ID)
+		}
+		logger.Infof("replacement of osd.%d on %q completed", replacement.ID, replacement.Name)
+		c.replacements[i].Phase = cephv1.OSDReplacementCompleted
+		c.replacements[i].Message = fmt.Sprintf("osd.%d is running again", replacement.ID)
+		c.replacements[i].CompletionTime = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	return c.updateOSDReplacementsStatus()
+}
+
+// replacementsOn returns the OSDs being replaced on the node or PVC, whose devices the prepare job wipes
+func (c *Cluster) replacementsOn(nodeOrPVCName string) []cephv1.OSDReplacement {
+	var replacements []cephv1.OSDReplacement
+	for _, replacement := range c.replacements {
+		if replacement.Name == nodeOrPVCName && replacement.Phase == cephv1.OSDReplacementPreparing {
+			replacements = append(replacements, replacement)
+		}
+	}
+	return replacements
+}
+
+// setOSDReplacement records the replacement, replacing any previous replacement of the same OSD
+func (c *Cluster) setOSDReplacement(replacement cephv1.OSDReplacement) {
+	for i := range c.replacements {
+		if c.replacements[i].ID == replacement.ID {
+			c.replacements[i] = replacement
+			return
+		}
+	}
+	c.replacements = append(c.replacements, replacement)
+}
+
+func (c *Cluster) getOSDReplacements() ([]cephv1.OSDReplacement, error) {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get ceph cluster %q", c.clusterInfo.NamespacedName().String())
+	}
+	return cephCluster.Status.OSDReplacements, nil
+}
+
+func (c *Cluster) updateOSDReplacementsStatus() error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.clusterInfo.NamespacedName().String())
+	}
+
+	replacements := c.replacements
+	if len(replacements) == 0 {
+		replacements = nil
+	}
+	if reflect.DeepEqual(cephCluster.Status.OSDReplacements, replacements) {
+		return nil
+	}
+	cephCluster.Status.OSDReplacements = replacements
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the OSD replacements")
+	}
+	return nil
+}
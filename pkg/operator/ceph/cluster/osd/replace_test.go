@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOSDReplacements(t *testing.T) {
+	namespace := "ns"
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, CephVersion: cephver.Pacific, Context: context.TODO()}
+	clusterInfo.SetName("testcluster")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "testcluster", Namespace: namespace}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := fake.NewSimpleClientset()
+
+	var commands []string
+	destroyFails := true
+	drained := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "dump":
+				// osd.3 is up, osd.4 and osd.6 are down
+				return `{"osds":[{"osd":3,"up":1,"in":1},{"osd":4,"up":0,"in":1},{"osd":6,"up":0,"in":1}]}`, nil
+			case args[0] == "osd" && args[1] == "ok-to-stop":
+				return "", errors.New("osd.3 is not ok to stop")
+			case args[0] == "osd" && args[1] == "safe-to-destroy":
+				// the placement groups of osd.6 are not moved yet
+				if args[2] == "6" && !drained {
+					return `{"safe_to_destroy":[]}`, nil
+				}
+				return fmt.Sprintf(`{"safe_to_destroy":[%s]}`, args[2]), nil
+			case args[0] == "osd" && args[1] == "destroy" && destroyFails:
+				return "", errors.New("osd.4 is not safe to destroy")
+			case args[0] == "osd" && (args[1] == "out" || args[1] == "in" || args[1] == "down" || args[1] == "destroy"):
+				commands = append(commands, fmt.Sprint(args[:3]))
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	c := New(&clusterd.Context{Client: client, Clientset: clientset, Executor: executor}, clusterInfo, cephv1.ClusterSpec{DataDirHostPath: "/rook"}, "myversion")
+	provisionConfig := &provisionConfig{DataPathMap: opconfig.NewDatalessDaemonDataPathMap(namespace, "/rook")}
+	useAllDevices := true
+	osdProps := osdProperties{crushHostname: "node1", selection: cephv1.Selection{UseAllDevices: &useAllDevices}}
+	createDeployment := func(osd OSDInfo, replace bool) {
+		d, err := c.makeDeployment(osdProps, osd, provisionConfig)
+		require.NoError(t, err)
+		if replace {
+			d.Annotations = map[string]string{controller.ReplaceOSDAnnotation: "true"}
+		}
+		_, err = clientset.AppsV1().Deployments(namespace).Create(context.TODO(), d, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	createDeployment(OSDInfo{ID: 3, UUID: "uuid-3", BlockPath: "/dev/sdb", CVMode: "raw"}, true)
+	createDeployment(OSDInfo{ID: 4, UUID: "uuid-4", BlockPath: "/dev/ceph-vg/osd-block-4", CVMode: "lvm"}, true)
+	createDeployment(OSDInfo{ID: 5, UUID: "uuid-5", BlockPath: "/dev/sdd", CVMode: "raw"}, false)
+	createDeployment(OSDInfo{ID: 6, UUID: "uuid-6", BlockPath: "/dev/sde", CVMode: "raw"}, true)
+
+	getReplacements := func() []cephv1.OSDReplacement {
+		err := client.Get(context.TODO(), clusterInfo.NamespacedName(), cephCluster)
+		require.NoError(t, err)
+		return cephCluster.Status.OSDReplacements
+	}
+
+	// osd.4 fails to be destroyed after its deployment is deleted, its replacement is still recorded
+	err := c.startOSDReplacements()
+	assert.Error(t, err)
+	assert.Equal(t, []string{"[osd out 4]", "[osd down osd.4]"}, commands)
+	replacements := getReplacements()
+	require.Equal(t, 2, len(replacements))
+	assert.Equal(t, 3, replacements[0].ID)
+	assert.Equal(t, cephv1.OSDReplacementPending, replacements[0].Phase)
+	assert.Equal(t, 4, replacements[1].ID)
+	assert.Equal(t, cephv1.OSDReplacementDestroying, replacements[1].Phase)
+	assert.Contains(t, replacements[1].Message, "failed to destroy osd.4")
+	_, err = clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "rook-ceph-osd-4", metav1.GetOptions{})
+	assert.Error(t, err)
+	assert.Empty(t, c.replacementsOn("node1"))
+
+	// the destroy of osd.4 is retried, osd.3 waits until it can be stopped and osd.6 until it is drained
+	destroyFails = false
+	commands = nil
+	err = c.startOSDReplacements()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[osd down osd.4]", "[osd destroy osd.4]", "[osd out 6]"}, commands)
+	replacements = getReplacements()
+	require.Equal(t, 3, len(replacements))
+	assert.Equal(t, 4, replacements[0].ID)
+	assert.Equal(t, "node1", replacements[0].Name)
+	assert.Equal(t, "/dev/ceph-vg/osd-block-4", replacements[0].Path)
+	assert.Equal(t, cephv1.OSDReplacementPreparing, replacements[0].Phase)
+	assert.NotEmpty(t, replacements[0].StartTime)
+	assert.Equal(t, 3, replacements[1].ID)
+	assert.Equal(t, cephv1.OSDReplacementPending, replacements[1].Phase)
+	assert.Equal(t, 6, replacements[2].ID)
+	assert.Equal(t, cephv1.OSDReplacementPending, replacements[2].Phase)
+	_, err = clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "rook-ceph-osd-6", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// osd.6 is destroyed once it is drained
+	drained = true
+	commands = nil
+	err = c.startOSDReplacements()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[osd out 6]", "[osd down osd.6]", "[osd destroy osd.6]"}, commands)
+	replacements = getReplacements()
+	require.Equal(t, 3, len(replacements))
+	assert.Equal(t, 6, replacements[2].ID)
+	assert.Equal(t, cephv1.OSDReplacementPreparing, replacements[2].Phase)
+	assert.Equal(t, "/dev/sde", replacements[2].Path)
+
+	// the prepare job of the node wipes the devices of osd.4 and osd.6
+	assert.Equal(t, []cephv1.OSDReplacement{replacements[0], replacements[2]}, c.replacementsOn("node1"))
+	assert.Empty(t, c.replacementsOn("node2"))
+
+	// the replacement is not completed until osd.4 runs again
+	err = c.completeOSDReplacements()
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.OSDReplacementPreparing, getReplacements()[0].Phase)
+	createDeployment(OSDInfo{ID: 4, UUID: "uuid-4-new", BlockPath: "/dev/ceph-vg2/osd-block-4", CVMode: "lvm"}, false)
+	err = c.completeOSDReplacements()
+	assert.NoError(t, err)
+	replacements = getReplacements()
+	assert.Equal(t, cephv1.OSDReplacementCompleted, replacements[0].Phase)
+	assert.NotEmpty(t, replacements[0].CompletionTime)
+	assert.Equal(t, cephv1.OSDReplacementPreparing, replacements[2].Phase)
+	assert.Equal(t, []cephv1.OSDReplacement{replacements[2]}, c.replacementsOn("node1"))
+
+	// the pending replacement is dropped once the annotation is removed, and the osd is marked in again
+	d, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "rook-ceph-osd-3", metav1.GetOptions{})
+	require.NoError(t, err)
+	d.Annotations = nil
+	_, err = clientset.AppsV1().Deployments(namespace).Update(context.TODO(), d, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	commands = nil
+	err = c.startOSDReplacements()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[osd in 3]"}, commands)
+	replacements = getReplacements()
+	require.Equal(t, 2, len(replacements))
+	assert.Equal(t, 4, replacements[0].ID)
+	assert.Equal(t, cephv1.OSDReplacementCompleted, replacements[0].Phase)
+	assert.Equal(t, 6, replacements[1].ID)
+}
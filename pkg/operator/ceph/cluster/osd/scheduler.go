@@ -0,0 +1,294 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// prepareScheduler starts the OSD prepare jobs of a reconcile within the limits of the provisioning spec,
+// cluster-wide and per node. The jobs beyond the limits wait in a queue and are started in order as the
+// running jobs finish.
+type prepareScheduler struct {
+	cluster            *Cluster
+	maxInFlight        int
+	maxInFlightPerNode int
+	pending            []pendingPrepareJob
+	inFlight           map[string]string // node of the running prepare jobs by node or PVC name, empty if unknown
+	completed          sets.String
+	completedNodes     sets.String
+	failed             sets.String
+	// resumed are the nodes completed by an interrupted reconcile of the same generation of the cluster
+	resumed      sets.String
+	generation   int64
+	startTime    string
+	reportStatus bool
+}
+
+type pendingPrepareJob struct {
+	osdProps *osdProperties
+	config   *provisionConfig
+	node     string
+}
+
+func newPrepareScheduler(c *Cluster) *prepareScheduler {
+	s := &prepareScheduler{
+		cluster:        c,
+		inFlight:       map[string]string{},
+		completed:      sets.NewString(),
+		completedNodes: sets.NewString(),
+		failed:         sets.NewString(),
+		resumed:        sets.NewString(),
+	}
+	if c.spec.Storage.Provisioning != nil {
+		s.maxInFlight = c.spec.Storage.Provisioning.MaxInFlight
+		s.maxInFlightPerNode = c.spec.Storage.Provisioning.MaxInFlightPerNode
+	}
+	return s
+}
+
+// resume loads the progress of the prepare jobs from the CephCluster status. The nodes whose prepare job
+// completed are skipped if the previous reconcile of the same generation was interrupted, such as by a
+// restart of the operator. The progress of the prepare jobs is reported in the status from then on.
+func (s *prepareScheduler) resume() error {
+	cephCluster, err := s.getCephCluster()
+	if err != nil || cephCluster == nil {
+		return err
+	}
+
+	s.reportStatus = true
+	s.generation = cephCluster.Generation
+	s.startTime = time.Now().UTC().Format(time.RFC3339)
+	previous := cephCluster.Status.OSDProvisioning
+	if previous == nil || previous.Generation != s.generation || previous.CompletionTime != "" {
+		return nil
+	}
+	if len(previous.CompletedNodes) > 0 {
+		logger.Infof("resuming the OSD provisioning interrupted at generation %d, skipping the prepare jobs of the completed nodes %v", s.generation, previous.CompletedNodes)
+	}
+	s.startTime = previous.StartTime
+	s.resumed.Insert(previous.CompletedNodes...)
+	s.completed.Insert(previous.CompletedNodes...)
+	s.completedNodes.Insert(previous.CompletedNodes...)
+	return nil
+}
+
+// skip returns whether the prepare job of the node already completed before the reconcile was interrupted
+func (s *prepareScheduler) skip(nodeName string) bool {
+	return s.resumed.Has(nodeName)
+}
+
+// schedule runs the prepare job if the limits allow it, or queues it until a running job finishes
+func (s *prepareScheduler) schedule(osdProps *osdProperties, config *provisionConfig) error {
+	node := osdProps.crushHostname
+	if osdProps.onPVC() {
+		node = s.cluster.pvcNode(osdProps.pvc.ClaimName)
+	}
+
+	if !s.canStart(node) {
+		logger.Infof("waiting for a running OSD prepare job to finish before starting the one of %q", osdProps.crushHostname)
+		s.pending = append(s.pending, pendingPrepareJob{osdProps: osdProps, config: config, node: node})
+		return nil
+	}
+	return s.start(osdProps, config, node)
+}
+
+func (s *prepareScheduler) start(osdProps *osdProperties, config *provisionConfig, node string) error {
+	if err := s.cluster.runPrepareJob(osdProps, config); err != nil {
+		s.failed.Insert(osdProps.crushHostname)
+		return err
+	}
+	s.inFlight[osdProps.crushHostname] = node
+	return nil
+}
+
+func (s *prepareScheduler) canStart(node string) bool {
+	if s.maxInFlight > 0 && len(s.inFlight) >= s.maxInFlight {
+		return false
+	}
+	if s.maxInFlightPerNode > 0 && node != "" {
+		count := 0
+		for _, n := range s.inFlight {
+			if n == node {
+				count++
+			}
+		}
+		if count >= s.maxInFlightPerNode {
+			return false
+		}
+	}
+	return true
+}
+
+// finished records the result of a running prepare job. The results of the jobs that were not started by
+// this reconcile are ignored.
+func (s *prepareScheduler) finished(nodeOrPVCName string, pvcBacked, failed bool) {
+	if _, ok := s.inFlight[nodeOrPVCName]; !ok {
+		return
+	}
+	delete(s.inFlight, nodeOrPVCName)
+	if failed {
+		s.failed.Insert(nodeOrPVCName)
+		return
+	}
+	s.completed.Insert(nodeOrPVCName)
+	if !pvcBacked {
+		s.completedNodes.Insert(nodeOrPVCName)
+	}
+}
+
+// startPending starts the queued prepare jobs the limits allow, in order. The jobs that fail to start are
+// done with, like the jobs that fail.
+func (s *prepareScheduler) startPending(createConfig *createConfig, errs *provisionErrors) {
+	pending := []pendingPrepareJob{}
+	for _, job := range s.pending {
+		if !s.canStart(job.node) {
+			pending = append(pending, job)
+			continue
+		}
+		if err := s.start(job.osdProps, job.config, job.node); err != nil {
+			s.cluster.handleOrchestrationFailure(errs, job.osdProps.crushHostname, "%v", err)
+			s.cluster.deleteStatusConfigMap(job.osdProps.crushHostname)
+			createConfig.doneWithStatus(job.osdProps.crushHostname)
+		}
+	}
+	s.pending = pending
+}
+
+func (s *prepareScheduler) status(done bool) *cephv1.OSDProvisioningStatus {
+	status := &cephv1.OSDProvisioningStatus{
+		Generation: s.generation,
+		Pending:    len(s.pending),
+		InFlight:   len(s.inFlight),
+		Completed:  s.completed.Len(),
+		Failed:     s.failed.Len(),
+		StartTime:  s.startTime,
+	}
+	if done {
+		status.CompletionTime = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		status.CompletedNodes = s.completedNodes.List()
+		status.Nodes = s.nodesStatus()
+	}
+	return status
+}
+
+// nodesStatus returns the prepare jobs running and waiting on each node, with the holder of the provisioning
+// lease of the node
+func (s *prepareScheduler) nodesStatus() []cephv1.OSDProvisioningNodeStatus {
+	nodes := map[string]*cephv1.OSDProvisioningNodeStatus{}
+	nodeStatus := func(node string) *cephv1.OSDProvisioningNodeStatus {
+		if _, ok := nodes[node]; !ok {
+			nodes[node] = &cephv1.OSDProvisioningNodeStatus{Name: node}
+		}
+		return nodes[node]
+	}
+	for name, node := range s.inFlight {
+		status := nodeStatus(node)
+		status.InFlight = append(status.InFlight, name)
+	}
+	for _, job := range s.pending {
+		status := nodeStatus(job.node)
+		status.Pending = append(status.Pending, job.osdProps.crushHostname)
+	}
+
+	leaseNamespace := os.Getenv(k8sutil.PodNamespaceEnvVar)
+	result := []cephv1.OSDProvisioningNodeStatus{}
+	for _, status := range nodes {
+		sort.Strings(status.InFlight)
+		if status.Name != "" && leaseNamespace != "" {
+			holder, err := k8sutil.GetLeaseHolder(s.cluster.clusterInfo.Context, s.cluster.context.Clientset, leaseNamespace, k8sutil.ProvisioningLeaseName(status.Name))
+			if err != nil {
+				logger.Debugf("failed to get the holder of the provisioning lease of node %q. %v", status.Name, err)
+			}
+			status.LeaseHolder = holder
+		}
+		result = append(result, *status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// updateStatus reports the progress of the prepare jobs in the CephCluster status
+func (s *prepareScheduler) updateStatus(done bool) error {
+	if !s.reportStatus {
+		return nil
+	}
+	cephCluster, err := s.getCephCluster()
+	if err != nil || cephCluster == nil {
+		return err
+	}
+
+	status := s.status(done)
+	if reflect.DeepEqual(cephCluster.Status.OSDProvisioning, status) {
+		return nil
+	}
+	cephCluster.Status.OSDProvisioning = status
+	if err := reporting.UpdateStatus(s.cluster.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the progress of the OSD provisioning")
+	}
+	return nil
+}
+
+func (s *prepareScheduler) getCephCluster() (*cephv1.CephCluster, error) {
+	c := s.cluster
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get ceph cluster %q", c.clusterInfo.NamespacedName().String())
+	}
+	return cephCluster, nil
+}
+
+// pvcNode returns the node of the volume bound to the PVC, or an empty string if the volume is not bound to
+// a node yet or is not local to a node
+func (c *Cluster) pvcNode(claimName string) string {
+	pvc, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, claimName, metav1.GetOptions{})
+	if err != nil || pvc.Spec.VolumeName == "" {
+		return ""
+	}
+	pv, err := c.context.Clientset.CoreV1().PersistentVolumes().Get(c.clusterInfo.Context, pvc.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return ""
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == corev1.LabelHostname && expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+				return expr.Values[0]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPrepareScheduler(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, CephVersion: cephver.Nautilus, Context: ctx}
+	clusterInfo.SetName("mycluster")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	useAllDevices := true
+	spec := cephv1.ClusterSpec{
+		DataDirHostPath: "/var/lib/rook",
+		Storage: cephv1.StorageScopeSpec{
+			UseAllNodes:  true,
+			Selection:    cephv1.Selection{UseAllDevices: &useAllDevices},
+			Provisioning: &cephv1.OSDProvisioningSpec{MaxInFlight: 2},
+		},
+	}
+
+	var c *Cluster
+	doSetup := func(cephCluster *cephv1.CephCluster) {
+		clientset := test.New(t, 3) // fake clientset with 3 nodes
+		client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+		c = New(&clusterd.Context{Clientset: clientset, Client: client}, clusterInfo, spec, "rook/rook:master")
+	}
+	countJobs := func() int {
+		jobs, err := c.context.Clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		return len(jobs.Items)
+	}
+	getStatus := func() *cephv1.OSDProvisioningStatus {
+		cephCluster := &cephv1.CephCluster{}
+		err := c.context.Client.Get(ctx, types.NamespacedName{Name: "mycluster", Namespace: namespace}, cephCluster)
+		require.NoError(t, err)
+		return cephCluster.Status.OSDProvisioning
+	}
+
+	t.Run("limit the prepare jobs in flight", func(t *testing.T) {
+		doSetup(&cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "mycluster", Namespace: namespace, Generation: 3}})
+		assert.NoError(t, c.prepareScheduler.resume())
+		config := c.newProvisionConfig()
+		errs := newProvisionErrors()
+		awaiting, err := c.startProvisioningOverNodes(config, errs)
+		assert.NoError(t, err)
+		assert.Zero(t, errs.len())
+		// all the nodes are awaited, only two jobs run
+		assert.Equal(t, 3, awaiting.Len())
+		assert.Equal(t, 2, countJobs())
+		assert.Len(t, c.prepareScheduler.inFlight, 2)
+		require.Len(t, c.prepareScheduler.pending, 1)
+		queued := c.prepareScheduler.pending[0].osdProps.crushHostname
+
+		// the first job to complete makes room for the queued job
+		createConfig := c.newCreateConfig(config, awaiting, newExistenceListWithCapacity(0))
+		var completed string
+		for name := range c.prepareScheduler.inFlight {
+			completed = name
+			break
+		}
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: statusConfigMapName(completed), Labels: map[string]string{nodeLabelKey: completed}},
+			Data:       map[string]string{orchestrationStatusKey: `{"status":"completed"}`},
+		}
+		c.createOSDsForStatusMap(cm, createConfig, errs)
+		assert.Zero(t, errs.len())
+		assert.Equal(t, 3, countJobs())
+		assert.Empty(t, c.prepareScheduler.pending)
+		assert.Contains(t, c.prepareScheduler.inFlight, queued)
+
+		status := getStatus()
+		require.NotNil(t, status)
+		assert.Equal(t, int64(3), status.Generation)
+		assert.Equal(t, 2, status.InFlight)
+		assert.Equal(t, 1, status.Completed)
+		assert.Equal(t, []string{completed}, status.CompletedNodes)
+		assert.Empty(t, status.CompletionTime)
+
+		// the status of a job that is not in flight is ignored
+		c.prepareScheduler.finished("unknown", false, false)
+		assert.Equal(t, 1, c.prepareScheduler.completed.Len())
+
+		assert.NoError(t, c.prepareScheduler.updateStatus(true))
+		status = getStatus()
+		assert.NotEmpty(t, status.CompletionTime)
+		assert.Empty(t, status.CompletedNodes)
+	})
+
+	t.Run("resume the provisioning of the same generation", func(t *testing.T) {
+		cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "mycluster", Namespace: namespace, Generation: 3}}
+		cephCluster.Status.OSDProvisioning = &cephv1.OSDProvisioningStatus{Generation: 3, Completed: 1, StartTime: "2021-06-01T10:00:00Z", CompletedNodes: []string{"node1"}}
+		doSetup(cephCluster)
+		assert.NoError(t, c.prepareScheduler.resume())
+		awaiting, err := c.startProvisioningOverNodes(c.newProvisionConfig(), newProvisionErrors())
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{statusConfigMapName("node0"), statusConfigMapName("node2")}, awaiting.List())
+		assert.Equal(t, 2, countJobs())
+		assert.Equal(t, "2021-06-01T10:00:00Z", c.prepareScheduler.startTime)
+		assert.Equal(t, 1, c.prepareScheduler.completed.Len())
+	})
+
+	t.Run("don't resume another generation or a completed provisioning", func(t *testing.T) {
+		cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "mycluster", Namespace: namespace, Generation: 4}}
+		cephCluster.Status.OSDProvisioning = &cephv1.OSDProvisioningStatus{Generation: 3, CompletedNodes: []string{"node1"}}
+		doSetup(cephCluster)
+		assert.NoError(t, c.prepareScheduler.resume())
+		assert.False(t, c.prepareScheduler.skip("node1"))
+
+		cephCluster.Generation = 3
+		cephCluster.Status.OSDProvisioning.CompletionTime = "2021-06-01T10:00:00Z"
+		doSetup(cephCluster)
+		assert.NoError(t, c.prepareScheduler.resume())
+		assert.False(t, c.prepareScheduler.skip("node1"))
+	})
+}
+
+func TestPrepareSchedulerPerNode(t *testing.T) {
+	s := &prepareScheduler{maxInFlightPerNode: 1, inFlight: map[string]string{"pvc-0": "node0", "pvc-1": ""}}
+	assert.False(t, s.canStart("node0"))
+	assert.True(t, s.canStart("node1"))
+	// the limit doesn't apply to the jobs of unknown nodes
+	assert.True(t, s.canStart(""))
+
+	s.maxInFlight = 2
+	assert.False(t, s.canStart("node1"))
+}
+
+func TestPrepareSchedulerNodesStatus(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "rook-ceph", Context: ctx}
+	c := New(&clusterd.Context{Clientset: clientset}, clusterInfo, cephv1.ClusterSpec{}, "rook/rook:master")
+	// the prepare jobs of a node are not limited by default
+	assert.Equal(t, 0, c.prepareScheduler.maxInFlightPerNode)
+
+	os.Setenv(k8sutil.PodNamespaceEnvVar, "rook-ceph-system")
+	defer os.Unsetenv(k8sutil.PodNamespaceEnvVar)
+	acquired, _, err := k8sutil.TryAcquireLease(ctx, clientset, "rook-ceph-system", k8sutil.ProvisioningLeaseName("node0"), "rook-ceph-osd-prepare-pvc-0", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	s := c.prepareScheduler
+	s.inFlight = map[string]string{"pvc-0": "node0", "pvc-1": ""}
+	s.pending = []pendingPrepareJob{
+		{osdProps: &osdProperties{crushHostname: "pvc-3"}, node: "node0"},
+		{osdProps: &osdProperties{crushHostname: "pvc-2"}, node: "node0"},
+	}
+	status := s.status(false)
+	require.Len(t, status.Nodes, 2)
+	assert.Equal(t, cephv1.OSDProvisioningNodeStatus{InFlight: []string{"pvc-1"}}, status.Nodes[0])
+	assert.Equal(t, cephv1.OSDProvisioningNodeStatus{
+		Name:        "node0",
+		InFlight:    []string{"pvc-0"},
+		Pending:     []string{"pvc-3", "pvc-2"},
+		LeaseHolder: "rook-ceph-osd-prepare-pvc-0",
+	}, status.Nodes[1])
+
+	// the queue is not reported once the prepare jobs are done
+	assert.Empty(t, s.status(true).Nodes)
+}
+
+func TestPVCNode(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", Context: ctx}
+	c := &Cluster{context: &clusterd.Context{Clientset: clientset}, clusterInfo: clusterInfo}
+
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "set-data-0", Namespace: "ns"}}
+	_, err := clientset.CoreV1().PersistentVolumeClaims("ns").Create(ctx, pvc, metav1.CreateOptions{})
+	require.NoError(t, err)
+	// not bound
+	assert.Equal(t, "", c.pvcNode("set-data-0"))
+	assert.Equal(t, "", c.pvcNode("missing"))
+
+	pvc.Spec.VolumeName = "local-pv"
+	_, err = clientset.CoreV1().PersistentVolumeClaims("ns").Update(ctx, pvc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "local-pv"}}
+	pv.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{"node0"}}},
+	}}}}
+	_, err = clientset.CoreV1().PersistentVolumes().Create(ctx, pv, metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "node0", c.pvcNode("set-data-0"))
+}
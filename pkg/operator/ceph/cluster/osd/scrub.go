@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+)
+
+// validateScrub validates the scrub schedule of the OSDs and of their device classes
+func validateScrub(spec *cephv1.ScrubSpec) error {
+	if spec == nil {
+		return nil
+	}
+	if err := opconfig.ValidateScrubSchedule(&spec.ScrubScheduleSpec); err != nil {
+		return err
+	}
+	for deviceClass, schedule := range spec.DeviceClasses {
+		schedule := schedule
+		if err := opconfig.ValidateScrubSchedule(&schedule); err != nil {
+			return errors.Wrapf(err, "invalid scrub schedule of device class %q", deviceClass)
+		}
+	}
+	return nil
+}
+
+// configureScrub applies the scrub schedule of the cluster to all the OSDs, and the schedules of the device
+// classes to the OSDs of the classes. The schedule of the device classes removed from the settings is removed.
+// The deep scrub interval of all the OSDs is also set for the mgr, which reports the PGs not deep scrubbed in
+// time. The options are left untouched if the schedule is not specified.
+func (c *Cluster) configureScrub() error {
+	spec := c.spec.Storage.Scrub
+	if spec == nil {
+		return nil
+	}
+
+	monStore := opconfig.GetMonStore(c.context, c.clusterInfo)
+	if err := monStore.ApplyScrub("osd", &spec.ScrubScheduleSpec); err != nil {
+		return err
+	}
+	interval := opconfig.ScrubOptions(&spec.ScrubScheduleSpec)[opconfig.DeepScrubIntervalOption]
+	if interval == "" {
+		if err := monStore.Delete("mgr", opconfig.DeepScrubIntervalOption); err != nil {
+			return errors.Wrap(err, "failed to remove the deep scrub interval of the mgr")
+		}
+	} else if err := monStore.Set("mgr", opconfig.DeepScrubIntervalOption, interval); err != nil {
+		return errors.Wrap(err, "failed to set the deep scrub interval of the mgr")
+	}
+
+	deviceClasses, err := cephclient.GetDeviceClasses(c.context, c.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the device classes of the osds")
+	}
+	for deviceClass := range spec.DeviceClasses {
+		deviceClasses = append(deviceClasses, deviceClass)
+	}
+	sort.Strings(deviceClasses)
+	applied := map[string]bool{}
+	for _, deviceClass := range deviceClasses {
+		if applied[deviceClass] {
+			continue
+		}
+		applied[deviceClass] = true
+		var schedule *cephv1.ScrubScheduleSpec
+		if s, ok := spec.DeviceClasses[deviceClass]; ok {
+			schedule = &s
+		}
+		if err := monStore.ApplyScrub(fmt.Sprintf("osd/class:%s", deviceClass), schedule); err != nil {
+			return errors.Wrapf(err, "failed to apply the scrub schedule of device class %q", deviceClass)
+		}
+	}
+	return nil
+}
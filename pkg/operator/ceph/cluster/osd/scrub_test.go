@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigureScrub(t *testing.T) {
+	sets := map[string]string{}
+	removed := map[string]bool{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "crush" && args[2] == "class" && args[3] == "ls" {
+				return `["hdd","ssd"]`, nil
+			}
+			if args[0] == "config" && args[1] == "set" {
+				sets[args[2]+" "+args[3]] = args[4]
+			}
+			if args[0] == "config" && args[1] == "rm" {
+				removed[args[2]+" "+args[3]] = true
+			}
+			return "", nil
+		},
+	}
+	c := &Cluster{context: &clusterd.Context{Executor: executor}, clusterInfo: cephclient.AdminClusterInfo("ns")}
+
+	// the options are left untouched without a schedule
+	assert.NoError(t, c.configureScrub())
+	assert.Empty(t, sets)
+	assert.Empty(t, removed)
+
+	begin, end := 22, 6
+	c.spec.Storage.Scrub = &cephv1.ScrubSpec{
+		ScrubScheduleSpec: cephv1.ScrubScheduleSpec{
+			BeginHour:         &begin,
+			EndHour:           &end,
+			DeepScrubInterval: &metav1.Duration{Duration: 48 * time.Hour},
+		},
+		DeviceClasses: map[string]cephv1.ScrubScheduleSpec{
+			"ssd":  {MaxScrubs: 3},
+			"nvme": {MaxScrubs: 4},
+		},
+	}
+	assert.NoError(t, c.configureScrub())
+	assert.Equal(t, map[string]string{
+		"osd osd_scrub_begin_hour":      "22",
+		"osd osd_scrub_end_hour":        "6",
+		"osd osd_deep_scrub_interval":   "172800",
+		"mgr osd_deep_scrub_interval":   "172800",
+		"osd/class:ssd osd_max_scrubs":  "3",
+		"osd/class:nvme osd_max_scrubs": "4",
+	}, sets)
+	assert.True(t, removed["osd osd_max_scrubs"])
+	// the schedule of the device class that is not in the settings is removed
+	assert.True(t, removed["osd/class:hdd osd_max_scrubs"])
+	assert.True(t, removed["osd/class:hdd osd_scrub_begin_hour"])
+	assert.False(t, removed["mgr osd_deep_scrub_interval"])
+
+	// invalid schedules
+	invalid := 30
+	assert.Error(t, validateScrub(&cephv1.ScrubSpec{DeviceClasses: map[string]cephv1.ScrubScheduleSpec{"hdd": {BeginHour: &invalid}}}))
+	assert.NoError(t, validateScrub(nil))
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package osd
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -26,6 +27,7 @@ import (
 	"github.com/libopenstorage/secrets"
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
 	kms "github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	cephkey "github.com/rook/rook/pkg/operator/ceph/config/keyring"
@@ -56,6 +58,9 @@ const (
 	expandEncryptedPVCOSDInitContainer            = "expand-encrypted-bluefs"
 	encryptedPVCStatusOSDInitContainer            = "encrypted-block-status"
 	encryptionKeyFileName                         = "luks_key"
+	crushLocationInitContainer                    = "crush-location"
+	crushLocationVolumeName                       = "crush-location"
+	crushLocationMountPath                        = "/var/lib/rook/crush-location"
 	// DmcryptBlockType is a portion of the device mapper name for the encrypted OSD on PVC block.db (rocksdb db)
 	DmcryptBlockType = "block-dmcrypt"
 	// DmcryptMetadataType is a portion of the device mapper name for the encrypted OSD on PVC block
@@ -67,6 +72,10 @@ const (
 	bluestoreWalName      = "block.wal"
 )
 
+// lvmWritableDirs are the directories lvm and cryptsetup write to when activating an OSD, mounted from emptyDir
+// volumes when the root filesystem of the containers is read-only
+var lvmWritableDirs = []string{"/run/lvm", "/run/lock/lvm", "/run/cryptsetup", "/etc/lvm/archive", "/etc/lvm/backup"}
+
 const (
 	activateOSDOnNodeCode = `
 set -o errexit
@@ -81,8 +90,12 @@ OSD_DATA_DIR=/var/lib/ceph/osd/ceph-"$OSD_ID"
 CV_MODE=%s
 DEVICE="$%s"
 
-# create new keyring
-ceph -n client.admin auth get-or-create osd."$OSD_ID" mon 'allow profile osd' mgr 'allow profile osd' osd 'allow *' -k /etc/ceph/admin-keyring-store/keyring
+# the osd data dir is kept on the host, so an osd activated before, such as before the node rebooted, is activated
+# again from its local metadata without waiting for the mons to be reachable
+if [[ ! -s "$OSD_DATA_DIR"/keyring ]]; then
+	# create new keyring
+	ceph -n client.admin auth get-or-create osd."$OSD_ID" mon 'allow profile osd' mgr 'allow profile osd' osd 'allow *' -k /etc/ceph/admin-keyring-store/keyring
+fi
 
 # active the osd with ceph-volume
 if [[ "$CV_MODE" == "lvm" ]]; then
@@ -112,7 +125,8 @@ else
 	#  returns user-friendly device names which can change when systems reboot. To
 	# keep OSD pods from crashing repeatedly after a reboot, we need to check if the
 	# block device we have is still correct, and if it isn't correct, we need to
-	# scan all the disks to find the right one.
+	# scan all the disks to find the right one. The db and wal devices are found with
+	# the block device, since their names can change as well.
 	OSD_LIST="$(mktemp)"
 
 	function find_device() {
@@ -121,8 +135,9 @@ else
 		python3 -c "
 import sys, json
 for _, info in json.load(sys.stdin).items():
-	if info['osd_id'] == $OSD_ID:
-		print(info['device'], end='')
+	if info['osd_id'] == $OSD_ID and info.get('osd_uuid', '$OSD_UUID') == '$OSD_UUID':
+		# the fields are separated by '|' so that a missing db device does not shift the wal device
+		print(info['device'], info.get('device_db', ''), info.get('device_wal', ''), sep='|', end='')
 		print('found device: ' + info['device'], file=sys.stderr) # log the disk we found to stderr
 		sys.exit(0)  # don't keep processing once the disk is found
 sys.exit('no disk found with OSD ID $OSD_ID')
@@ -132,16 +147,31 @@ sys.exit('no disk found with OSD ID $OSD_ID')
 	ceph-volume raw list "$DEVICE" > "$OSD_LIST"
 	cat "$OSD_LIST"
 
-	if ! find_device < "$OSD_LIST"; then
+	if ! DEVICES="$(find_device < "$OSD_LIST")"; then
 		ceph-volume raw list > "$OSD_LIST"
 		cat "$OSD_LIST"
 
-		DEVICE="$(find_device < "$OSD_LIST")"
+		DEVICES="$(find_device < "$OSD_LIST")"
 	fi
+	IFS='|' read -r DEVICE DB_DEVICE WAL_DEVICE <<< "$DEVICES"
 	[[ -z "$DEVICE" ]] && { echo "no device" ; exit 1 ; }
 
+	# the links of the osd data dir kept on the host point to the device names before the
+	# reboot, remove the stale ones so they are created again for the current names
+	for LINK in block:"$DEVICE" block.db:"$DB_DEVICE" block.wal:"$WAL_DEVICE"; do
+		LINK_PATH="$OSD_DATA_DIR/${LINK%%%%:*}"
+		LINK_DEVICE="${LINK#*:}"
+		if [[ -n "$LINK_DEVICE" && -L "$LINK_PATH" && "$(readlink "$LINK_PATH")" != "$LINK_DEVICE" ]]; then
+			rm --verbose "$LINK_PATH"
+		fi
+	done
+
+	ACTIVATE_ARGS=(--device "$DEVICE")
+	[[ -n "$DB_DEVICE" ]] && ACTIVATE_ARGS+=(--block.db "$DB_DEVICE")
+	[[ -n "$WAL_DEVICE" ]] && ACTIVATE_ARGS+=(--block.wal "$WAL_DEVICE")
+
 	# ceph-volume raw mode only supports bluestore so we don't need to pass a store flag
-	ceph-volume raw activate --device "$DEVICE" --no-systemd --no-tmpfs
+	ceph-volume raw activate "${ACTIVATE_ARGS[@]}" --no-systemd --no-tmpfs
 fi
 `
 
@@ -202,7 +232,7 @@ set -e
 KEK_NAME=%s
 KEY_PATH=%s
 CURL_PAYLOAD=$(mktemp)
-ARGS=(--silent --show-error --request GET --header "X-Vault-Token: ${VAULT_TOKEN//[$'\t\r\n']}")
+ARGS=(--silent --show-error)
 PYTHON_DATA_PARSE="['data']"
 
 # If a vault namespace is set
@@ -236,6 +266,21 @@ if [ -n "$VAULT_TLS_SERVER_NAME" ]; then
   ARGS+=(--connect-to ::"${VAULT_TLS_SERVER_NAME}":)
 fi
 
+# Without a token, log in with the service account token of the pod if the kubernetes auth method is used
+if [ -z "$VAULT_TOKEN" ] && [[ "$VAULT_AUTH_METHOD" == "kubernetes" ]]; then
+  LOGIN_DATA=$(mktemp)
+  python3 -c "import sys, json; print(json.dumps({'role': sys.argv[1], 'jwt': open(sys.argv[2]).read().strip()}), end='')" \
+    "$VAULT_AUTH_KUBERNETES_ROLE" "${VAULT_AUTH_KUBERNETES_TOKEN_PATH:-/var/run/secrets/kubernetes.io/serviceaccount/token}" > "$LOGIN_DATA"
+  curl "${ARGS[@]}" --request POST --data @"$LOGIN_DATA" "$VAULT_ADDR"/v1/auth/"${VAULT_AUTH_MOUNT_PATH:-kubernetes}"/login > "$CURL_PAYLOAD"
+  rm -f "$LOGIN_DATA"
+  if ! VAULT_TOKEN=$(python3 -c "import sys, json; print(json.load(sys.stdin)['auth']['client_token'], end='')" < "$CURL_PAYLOAD"); then
+    echo "failed to log in to vault with role $VAULT_AUTH_KUBERNETES_ROLE"
+    cat "$CURL_PAYLOAD"
+    exit 1
+  fi
+fi
+ARGS+=(--request GET --header "X-Vault-Token: ${VAULT_TOKEN//[$'\t\r\n']}")
+
 # trim VAULT_BACKEND_PATH for last character '/' to avoid a redirect response from the server
 VAULT_BACKEND_PATH="${VAULT_BACKEND_PATH%%/}"
 
@@ -301,6 +346,16 @@ if [ -b "$PVC_DEST" ]; then
 fi
 
 cp "${CP_ARGS[@]}" "$PVC_SOURCE" "$PVC_DEST"
+`
+
+	// The location written by the crush-location init container is passed last to the OSD, so it overrides the
+	// location found by the prepare job
+	crushLocationWrapper = `
+set -o errexit
+set -o nounset
+
+CRUSH_LOCATION="$(cat %s)"
+exec "$@" "--crush-location=${CRUSH_LOCATION}"
 `
 )
 
@@ -465,7 +520,7 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 	// If the OSD runs on PVC
 	if osdProps.onPVC() {
 		// add the PVC size to the pod spec so that if the size changes the OSD will be restarted and pick up the change
-		envVars = append(envVars, v1.EnvVar{Name: "ROOK_OSD_PVC_SIZE", Value: osdProps.pvcSize})
+		envVars = append(envVars, v1.EnvVar{Name: osdPVCSizeEnvVarName, Value: osdProps.pvcSize})
 		// if the pod is portable, keep track of the topology affinity
 		if osdProps.portable {
 			envVars = append(envVars, v1.EnvVar{Name: "ROOK_TOPOLOGY_AFFINITY", Value: osd.TopologyAffinity})
@@ -612,6 +667,20 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 			securityContext,
 		))
 
+	// A portable OSD follows the topology of the node it currently runs on, which may be in another zone after the
+	// OSD was rescheduled, so its CRUSH location is detected again at each start
+	if osdProps.onPVC() && osdProps.portable {
+		crushLocationVolume, crushLocationContainer, err := c.getCrushLocationInitContainer(osdProps, securityContext)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, crushLocationVolume)
+		volumeMounts = append(volumeMounts, crushLocationContainer.VolumeMounts[0])
+		initContainers = append(initContainers, crushLocationContainer)
+		args = append(command, args...)
+		command = []string{"/bin/bash", "-c", fmt.Sprintf(crushLocationWrapper, path.Join(crushLocationMountPath, "location")), "osd"}
+	}
+
 	podTemplateSpec := v1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   AppName,
@@ -664,6 +733,9 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 			return nil, err
 		}
 	}
+	cephv1.GetDaemonDNS(c.spec.DNS, cephv1.KeyOSD).ApplyToPodSpec(&podTemplateSpec.Spec)
+	cephv1.GetDaemonContainerOverrides(c.spec.ContainerOverrides, cephv1.KeyOSD).ApplyToPodSpec(&podTemplateSpec.Spec)
+	controller.ApplyReadOnlyRootFilesystem(c.spec.Security, &podTemplateSpec.Spec, lvmWritableDirs...)
 
 	k8sutil.RemoveDuplicateEnvVars(&podTemplateSpec.Spec)
 
@@ -696,6 +768,8 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 	}
 	if !osdProps.portable {
 		deployment.Spec.Template.Spec.NodeSelector = map[string]string{v1.LabelHostname: osdProps.crushHostname}
+		k8sutil.AddLabelToDeployment(NodeHostnameLabelKey, osdProps.crushHostname, deployment)
+		k8sutil.AddLabelToPod(NodeHostnameLabelKey, osdProps.crushHostname, &deployment.Spec.Template)
 	}
 	// Replace default unreachable node toleration if the osd pod is portable and based in PVC
 	if osdProps.onPVC() && osdProps.portable {
@@ -791,6 +865,38 @@ func (c *Cluster) getCopyBinariesContainer() (v1.Volume, *v1.Container) {
 	}
 }
 
+// getCrushLocationInitContainer returns the container writing the CRUSH location of a portable OSD on PVC from the
+// labels of the node the OSD runs on. The root and the host of the location do not change when the OSD moves.
+func (c *Cluster) getCrushLocationInitContainer(osdProps osdProperties, securityContext *v1.SecurityContext) (v1.Volume, v1.Container, error) {
+	volume := v1.Volume{Name: crushLocationVolumeName, VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}
+	mount := v1.VolumeMount{Name: crushLocationVolumeName, MountPath: crushLocationMountPath}
+
+	envVars := []v1.EnvVar{
+		k8sutil.NodeEnvVar(),
+		{Name: CrushRootVarName, Value: client.GetCrushRootFromSpec(&c.spec)},
+		{Name: "ROOK_CRUSHMAP_HOSTNAME", Value: osdProps.crushHostname},
+	}
+	if len(c.spec.Storage.TopologyLabels) > 0 {
+		marshalledTopologyLabels, err := json.Marshal(c.spec.Storage.TopologyLabels)
+		if err != nil {
+			return v1.Volume{}, v1.Container{}, errors.Wrap(err, "failed to JSON marshal the topology labels")
+		}
+		envVars = append(envVars, topologyLabelsEnvVar(string(marshalledTopologyLabels)))
+	}
+
+	return volume, v1.Container{
+		Args: []string{
+			"ceph", "osd", "crush-location",
+			"--location-file", path.Join(crushLocationMountPath, "location"),
+		},
+		Name:            crushLocationInitContainer,
+		Image:           c.rookVersion,
+		Env:             envVars,
+		VolumeMounts:    []v1.VolumeMount{mount},
+		SecurityContext: securityContext,
+	}, nil
+}
+
 // This container runs all the actions needed to activate an OSD before we can run the OSD process
 func (c *Cluster) getActivateOSDInitContainer(configDir, namespace, osdID string, osdInfo OSDInfo, osdProps osdProperties) ([]v1.Volume, *v1.Container) {
 	// We need to use hostPath because the same reason as written in the comment of getDataBridgeVolumeSource()
@@ -951,7 +1057,7 @@ func (c *Cluster) getPVCEncryptionOpenInitContainerActivate(mountPath string, os
 		kmsProvider := kms.GetParam(c.spec.Security.KeyManagementService.ConnectionDetails, kms.Provider)
 		// Get Vault KEK from KMS container
 		if kmsProvider == secrets.TypeVault {
-			if c.spec.Security.KeyManagementService.IsTokenAuthEnabled() {
+			if c.spec.Security.KeyManagementService.IsTokenAuthEnabled() || c.spec.Security.KeyManagementService.IsK8sAuthEnabled() {
 				getKEKFromKMSContainer := c.generateVaultGetKEK(osdProps)
 
 				// Volume mount to store the encrypted key
@@ -18,6 +18,12 @@ limitations under the License.
 package osd
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -31,6 +37,7 @@ import (
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -72,6 +79,27 @@ func TestPodContainer(t *testing.T) {
 		vars := operatortest.FindDuplicateEnvVars(c)
 		assert.Equal(t, 0, len(vars))
 	}
+	for _, env := range c.Spec.Containers[0].Env {
+		assert.NotEqual(t, "ROOK_DRIVE_GROUPS", env.Name)
+	}
+
+	// the drive groups of the node are passed to the prepare job
+	osdProps.driveGroups = []cephv1.DriveGroup{{Name: "hdd", DataDevices: cephv1.DriveGroupDeviceFilter{Size: "1Ti:"}}}
+	c, err = cluster.provisionPodTemplateSpec(osdProps, v1.RestartPolicyAlways, dataPathMap)
+	assert.Nil(t, err)
+	assert.Contains(t, c.Spec.Containers[0].Env, driveGroupsEnvVar(`[{"name":"hdd","dataDevices":{"size":"1Ti:"}}]`))
+
+	// the OSDs replaced on the node are passed to the prepare job
+	osdProps.replaceOSDs = []cephv1.OSDReplacement{{ID: 3, Name: "node1", Path: "/dev/sdb", Phase: cephv1.OSDReplacementPreparing}}
+	c, err = cluster.provisionPodTemplateSpec(osdProps, v1.RestartPolicyAlways, dataPathMap)
+	assert.Nil(t, err)
+	assert.Contains(t, c.Spec.Containers[0].Env, replaceOSDsEnvVar(`[{"id":3,"name":"node1","path":"/dev/sdb","phase":"Preparing"}]`))
+
+	// the topology labels of the storage spec are passed to the prepare job
+	cluster.spec.Storage.TopologyLabels = []cephv1.CrushTopologyLabel{{Label: "example.com/room", Type: "room"}}
+	c, err = cluster.provisionPodTemplateSpec(osdProps, v1.RestartPolicyAlways, dataPathMap)
+	assert.Nil(t, err)
+	assert.Contains(t, c.Spec.Containers[0].Env, topologyLabelsEnvVar(`[{"label":"example.com/room","type":"room"}]`))
 }
 
 func TestDaemonset(t *testing.T) {
@@ -415,6 +443,27 @@ func testPodDevices(t *testing.T, dataDir, deviceName string, allDevices bool) {
 		assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Args, flag)
 	}
 
+	// Test the crush location of a portable OSD on PVC is detected at each start
+	assert.Equal(t, "ceph-osd", deployment.Spec.Template.Spec.Containers[0].Command[0])
+	osdProp.portable = true
+	c.spec.Storage.TopologyLabels = []cephv1.CrushTopologyLabel{{Label: "topology.kubernetes.io/zone", Type: "zone"}}
+	deployment, err = c.makeDeployment(osdProp, osd, dataPathMap)
+	assert.NoError(t, err)
+	initContainers := deployment.Spec.Template.Spec.InitContainers
+	crushLocationCont := initContainers[len(initContainers)-1]
+	assert.Equal(t, "crush-location", crushLocationCont.Name)
+	assert.Equal(t, "rook/rook:myversion", crushLocationCont.Image)
+	assert.Equal(t, []string{"ceph", "osd", "crush-location", "--location-file", "/var/lib/rook/crush-location/location"}, crushLocationCont.Args)
+	verifyEnvVar(t, crushLocationCont.Env, "ROOK_CRUSHMAP_HOSTNAME", n.Name, true)
+	verifyEnvVar(t, crushLocationCont.Env, "ROOK_TOPOLOGY_LABELS", `[{"label":"topology.kubernetes.io/zone","type":"zone"}]`, true)
+	cont = deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "/bin/bash", cont.Command[0])
+	assert.Contains(t, cont.Command[2], "cat /var/lib/rook/crush-location/location")
+	assert.Equal(t, "ceph-osd", cont.Args[0])
+	assert.Equal(t, "crush-location", cont.VolumeMounts[len(cont.VolumeMounts)-1].Name)
+	osdProp.portable = false
+	c.spec.Storage.TopologyLabels = nil
+
 	// Test shareProcessNamespace presence
 	assert.True(t, deployment.Spec.Template.Spec.HostPID)
 	if deployment.Spec.Template.Spec.ShareProcessNamespace != nil {
@@ -593,6 +642,49 @@ func TestHostNetwork(t *testing.T) {
 	assert.Equal(t, v1.DNSClusterFirstWithHostNet, r.Spec.Template.Spec.DNSPolicy)
 }
 
+func TestRestrictedProfileLVMWritableDirs(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clusterInfo := &cephclient.ClusterInfo{
+		Namespace:   "ns",
+		CephVersion: cephver.Nautilus,
+	}
+	clusterInfo.SetName("test")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	context := &clusterd.Context{Clientset: clientset, ConfigDir: "/var/lib/rook", Executor: &exectest.MockExecutor{}}
+	spec := cephv1.ClusterSpec{
+		Storage:  cephv1.StorageScopeSpec{Nodes: []cephv1.Node{{Name: "node1"}}},
+		Security: cephv1.SecuritySpec{Profile: cephv1.SecurityProfileRestricted},
+	}
+	c := New(context, clusterInfo, spec, "rook/rook:myversion")
+
+	osdProp := osdProperties{
+		crushHostname: "node1",
+		storeConfig:   config.StoreConfig{},
+	}
+	dataPathMap := &provisionConfig{
+		DataPathMap: opconfig.NewDatalessDaemonDataPathMap(c.clusterInfo.Namespace, "/var/lib/rook"),
+	}
+	osd := OSDInfo{ID: 0, CVMode: "lvm"}
+
+	d, err := c.makeDeployment(osdProp, osd, dataPathMap)
+	require.NoError(t, err)
+
+	assert.Contains(t, lvmWritableDirs, "/run/lock/lvm")
+	containers := append(d.Spec.Template.Spec.InitContainers, d.Spec.Template.Spec.Containers...)
+	for _, container := range containers {
+		if container.Name == "log-collector" {
+			continue
+		}
+		mountPaths := map[string]bool{}
+		for _, m := range container.VolumeMounts {
+			mountPaths[m.MountPath] = true
+		}
+		for _, dir := range lvmWritableDirs {
+			assert.True(t, mountPaths[dir], "container %q does not mount %q", container.Name, dir)
+		}
+	}
+}
+
 func TestOsdPrepareResources(t *testing.T) {
 	clientset := fake.NewSimpleClientset()
 
@@ -669,6 +761,83 @@ func TestClusterGetPVCEncryptionInitContainerActivate(t *testing.T) {
 	assert.Equal(t, 3, len(containers))
 }
 
+// runActivateScript runs the script of the activate init container of a raw OSD on a node, with the ceph and
+// ceph-volume commands replaced by stubs logging their arguments, and returns the commands and the osd data dir
+func runActivateScript(t *testing.T, rawList string, keyring bool, links map[string]string) (string, string, error) {
+	for _, cmd := range []string{"bash", "python3"} {
+		if _, err := exec.LookPath(cmd); err != nil {
+			t.Skipf("%s is required to run the activate script", cmd)
+		}
+	}
+	tmpDir, err := ioutil.TempDir("", "activate")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+	dataDir := filepath.Join(tmpDir, "osd")
+	binDir := filepath.Join(tmpDir, "bin")
+	logFile := filepath.Join(tmpDir, "commands")
+	rawListFile := filepath.Join(tmpDir, "raw-list.json")
+	require.NoError(t, os.Mkdir(dataDir, 0755))
+	require.NoError(t, os.Mkdir(binDir, 0755))
+	require.NoError(t, ioutil.WriteFile(rawListFile, []byte(rawList), 0644))
+	stubs := map[string]string{
+		"ceph":        fmt.Sprintf("#!/bin/bash\necho \"ceph $*\" >> %s\n", logFile),
+		"ceph-volume": fmt.Sprintf("#!/bin/bash\necho \"ceph-volume $*\" >> %s\nif [[ \"$1 $2\" == \"raw list\" ]]; then cat %s; fi\n", logFile, rawListFile),
+	}
+	for name, stub := range stubs {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(binDir, name), []byte(stub), 0755))
+	}
+	if keyring {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dataDir, "keyring"), []byte("[osd.0]\n"), 0600))
+	}
+	for name, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(dataDir, name)))
+	}
+
+	c := New(&clusterd.Context{}, &cephclient.ClusterInfo{OwnerInfo: &k8sutil.OwnerInfo{}}, cephv1.ClusterSpec{}, "rook/rook:myversion")
+	osdInfo := OSDInfo{ID: 0, UUID: "uuid-0", BlockPath: "/dev/sda", CVMode: "raw"}
+	_, container := c.getActivateOSDInitContainer("/var/lib/rook", "ns", "0", osdInfo, osdProperties{})
+	script := strings.Replace(container.Command[2], `/var/lib/ceph/osd/ceph-"$OSD_ID"`, dataDir, 1)
+	cmd := exec.Command("bash", "-c", script)
+	cmd.Env = append(os.Environ(), "PATH="+binDir+":"+os.Getenv("PATH"), "ROOK_OSD_ID=0", "ROOK_BLOCK_PATH=/dev/sda")
+	output, scriptErr := cmd.CombinedOutput()
+	logger.Infof("activate script output: %s", string(output))
+	commands, err := ioutil.ReadFile(logFile)
+	require.NoError(t, err)
+	return string(commands), dataDir, scriptErr
+}
+
+func TestActivateOSDOnNodeScript(t *testing.T) {
+	linkExists := func(dataDir, name string) bool {
+		_, err := os.Lstat(filepath.Join(dataDir, name))
+		return err == nil
+	}
+
+	// the device names changed after a reboot, for an osd with a wal device but no db device
+	rawList := `{"uuid-0": {"osd_id": 0, "osd_uuid": "uuid-0", "device": "/dev/sdb", "device_wal": "/dev/sdc"}}`
+	commands, dataDir, err := runActivateScript(t, rawList, true, map[string]string{"block": "/dev/sda", "block.wal": "/dev/sdd"})
+	assert.NoError(t, err)
+	// the osd activated before is activated again from its keyring without the mons
+	assert.NotContains(t, commands, "ceph -n client.admin auth")
+	assert.Contains(t, commands, "ceph-volume raw activate --device /dev/sdb --block.wal /dev/sdc --no-systemd --no-tmpfs\n")
+	assert.False(t, linkExists(dataDir, "block"))
+	assert.False(t, linkExists(dataDir, "block.wal"))
+
+	// the keyring of a new osd is created, and the links still pointing to the devices are kept
+	rawList = `{"uuid-0": {"osd_id": 0, "osd_uuid": "uuid-0", "device": "/dev/sdb", "device_db": "/dev/sdc", "device_wal": "/dev/sdd"}}`
+	commands, dataDir, err = runActivateScript(t, rawList, false, map[string]string{"block": "/dev/sdb", "block.db": "/dev/sde"})
+	assert.NoError(t, err)
+	assert.Contains(t, commands, "ceph -n client.admin auth get-or-create osd.0")
+	assert.Contains(t, commands, "ceph-volume raw activate --device /dev/sdb --block.db /dev/sdc --block.wal /dev/sdd --no-systemd --no-tmpfs\n")
+	assert.True(t, linkExists(dataDir, "block"))
+	assert.False(t, linkExists(dataDir, "block.db"))
+
+	// the osd with another uuid on the device is not activated
+	rawList = `{"uuid-1": {"osd_id": 0, "osd_uuid": "uuid-1", "device": "/dev/sdb"}}`
+	commands, _, err = runActivateScript(t, rawList, true, nil)
+	assert.Error(t, err)
+	assert.NotContains(t, commands, "raw activate")
+}
+
 // WARNING! modifies c.deviceSets
 func getDummyDeploymentOnPVC(clientset *fake.Clientset, c *Cluster, pvcName string, osdID int) *appsv1.Deployment {
 	osd := OSDInfo{
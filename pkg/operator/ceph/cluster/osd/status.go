@@ -20,12 +20,16 @@ package osd
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8swatch "k8s.io/apimachinery/pkg/watch"
@@ -70,6 +74,8 @@ func (c *Cluster) newProvisionConfig() *provisionConfig {
 // internal list of errors. The errors will be reported at the end of provisioning.
 type provisionErrors struct {
 	errors []error
+	// the failures of the prepare jobs by node or PVC, which are also in the list of errors
+	prepareFailures []cephv1.OSDPrepareFailure
 }
 
 func newProvisionErrors() *provisionErrors {
@@ -83,6 +89,17 @@ func (e *provisionErrors) addError(message string, args ...interface{}) {
 	e.errors = append(e.errors, errors.Errorf(message, args...))
 }
 
+// addPrepareFailure adds the error of the failed prepare job of a node or PVC
+func (e *provisionErrors) addPrepareFailure(nodeOrPVCName, message string, args ...interface{}) {
+	e.addError(message, args...)
+	e.prepareFailures = append(e.prepareFailures, cephv1.OSDPrepareFailure{Name: nodeOrPVCName, Message: fmt.Sprintf(message, args...)})
+}
+
+// onlyPrepareFailures returns whether all the errors are failures of prepare jobs
+func (e *provisionErrors) onlyPrepareFailures() bool {
+	return len(e.errors) == len(e.prepareFailures)
+}
+
 func (e *provisionErrors) len() int {
 	return len(e.errors)
 }
@@ -129,7 +146,7 @@ func UpdateNodeOrPVCStatus(kv *k8sutil.ConfigMapKVStore, nodeOrPVC string, statu
 }
 
 func (c *Cluster) handleOrchestrationFailure(errors *provisionErrors, nodeName, message string, args ...interface{}) {
-	errors.addError(message, args...)
+	errors.addPrepareFailure(nodeName, message, args...)
 	status := OrchestrationStatus{Status: OrchestrationStatusFailed, Message: message}
 	UpdateNodeOrPVCStatus(c.kv, nodeName, status)
 }
@@ -272,7 +289,8 @@ func (c *Cluster) updateAndCreateOSDsLoop(
 			// Log progress
 			c, cExp := createConfig.progress()
 			u, uExp := updateConfig.progress()
-			logger.Infof("waiting... %d of %d OSD prepare jobs have finished processing and %d of %d OSDs have been updated", c, cExp, u, uExp)
+			logger.Infof("waiting... %d of %d OSD prepare jobs have finished processing (%d running, %d queued) and %d of %d OSDs have been updated",
+				c, cExp, len(createConfig.cluster.prepareScheduler.inFlight), len(createConfig.cluster.prepareScheduler.pending), u, uExp)
 		}
 	}
 
@@ -324,17 +342,28 @@ func (c *Cluster) createOSDsForStatusMap(
 	if status.Status == OrchestrationStatusCompleted {
 		createConfig.createNewOSDsFromStatus(status, nodeOrPVCName, errs)
 		c.deleteStatusConfigMap(nodeOrPVCName) // remove the provisioning status configmap
+		c.prepareJobFinished(nodeOrPVCName, status.PvcBackedOSD, false, createConfig, errs)
 		return
 	}
 
 	if status.Status == OrchestrationStatusFailed {
 		createConfig.doneWithStatus(nodeOrPVCName)
-		errs.addError("failed to provision OSD(s) on %s %s. %+v", nodeOrPVC, nodeOrPVCName, status)
+		errs.addPrepareFailure(nodeOrPVCName, "failed to provision OSD(s) on %s %s. %+v", nodeOrPVC, nodeOrPVCName, status)
 		c.deleteStatusConfigMap(nodeOrPVCName) // remove the provisioning status configmap
+		c.prepareJobFinished(nodeOrPVCName, status.PvcBackedOSD, true, createConfig, errs)
 		return
 	}
 }
 
+// prepareJobFinished starts the queued prepare jobs the finished job makes room for
+func (c *Cluster) prepareJobFinished(nodeOrPVCName string, pvcBacked, failed bool, createConfig *createConfig, errs *provisionErrors) {
+	c.prepareScheduler.finished(nodeOrPVCName, pvcBacked, failed)
+	c.prepareScheduler.startPending(createConfig, errs)
+	if err := c.prepareScheduler.updateStatus(false); err != nil {
+		logger.Errorf("failed to report the progress of the OSD provisioning. %v", err)
+	}
+}
+
 func statusConfigMapName(nodeOrPVCName string) string {
 	return k8sutil.TruncateNodeName(orchestrationStatusMapName, nodeOrPVCName)
 }
@@ -363,3 +392,52 @@ func (c *Cluster) deleteAllStatusConfigMaps() {
 		}
 	}
 }
+
+// updatePrepareFailuresStatus reports the failures of the prepare jobs in the status of the CephCluster, and
+// whether the cluster is degraded because the reconcile continued despite the failures
+func (c *Cluster) updatePrepareFailuresStatus(failures []cephv1.OSDPrepareFailure, degraded bool) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", c.clusterInfo.NamespacedName().String())
+	}
+
+	changed := false
+	if !reflect.DeepEqual(cephCluster.Status.OSDPrepareFailures, failures) {
+		cephCluster.Status.OSDPrepareFailures = failures
+		changed = true
+	}
+
+	existing := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	if degraded {
+		message := fmt.Sprintf("%d OSD prepare job(s) failed, the OSDs of the failed nodes or PVCs are not running", len(failures))
+		if existing == nil || existing.Status != corev1.ConditionTrue || existing.Message != message {
+			cephv1.SetStatusCondition(&cephCluster.Status.Conditions, cephv1.Condition{
+				Type:    cephv1.ConditionDegraded,
+				Status:  corev1.ConditionTrue,
+				Reason:  cephv1.OSDPrepareFailedReason,
+				Message: message,
+			})
+			changed = true
+		}
+	} else if existing != nil && existing.Status == corev1.ConditionTrue && existing.Reason == cephv1.OSDPrepareFailedReason {
+		cephv1.SetStatusCondition(&cephCluster.Status.Conditions, cephv1.Condition{
+			Type:    cephv1.ConditionDegraded,
+			Status:  corev1.ConditionFalse,
+			Reason:  cephv1.OSDPrepareSucceededReason,
+			Message: "the OSD prepare jobs are not failing anymore",
+		})
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the OSD prepare failures")
+	}
+	return nil
+}
@@ -22,6 +22,8 @@ package osd
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -44,9 +46,10 @@ const (
 	topologyLabelPrefix = "topology.rook.io/"
 )
 
-// ExtractTopologyFromLabels extracts rook topology from labels and returns a map from topology type to value
-func ExtractOSDTopologyFromLabels(labels map[string]string) (map[string]string, string) {
-	topology, topologyAffinity := extractTopologyFromLabels(labels)
+// ExtractTopologyFromLabels extracts rook topology from labels and returns a map from topology type to value.
+// The custom labels are the topology labels declared in the storage spec.
+func ExtractOSDTopologyFromLabels(labels map[string]string, customLabels []cephv1.CrushTopologyLabel) (map[string]string, string) {
+	topology, topologyAffinity := extractTopologyFromLabels(labels, customLabels)
 
 	// Ensure the topology names are normalized for CRUSH
 	for name, value := range topology {
@@ -56,42 +59,29 @@ func ExtractOSDTopologyFromLabels(labels map[string]string) (map[string]string,
 }
 
 // ExtractTopologyFromLabels extracts rook topology from labels and returns a map from topology type to value
-func extractTopologyFromLabels(labels map[string]string) (map[string]string, string) {
+func extractTopologyFromLabels(labels map[string]string, customLabels []cephv1.CrushTopologyLabel) (map[string]string, string) {
 	topology := make(map[string]string)
+	// the node label of each topology type found
+	topologyLabels := make(map[string]string)
 
-	// The topology affinity for the osd is the lowest topology label found in the hierarchy,
-	// not including the host name
-	var topologyAffinity string
-
-	// check for the region k8s topology label that was deprecated in 1.17
-	const regionLabel = "region"
-	region, ok := labels[corev1.LabelZoneRegion]
-	if ok {
-		topology[regionLabel] = region
-		topologyAffinity = formatTopologyAffinity(corev1.LabelZoneRegion, region)
+	setTopology := func(topologyID, label string) {
+		if value, ok := labels[label]; ok {
+			topology[topologyID] = value
+			topologyLabels[topologyID] = label
+		}
 	}
 
-	// check for the region k8s topology label that is GA in 1.17.
-	region, ok = labels[corev1.LabelZoneRegionStable]
-	if ok {
-		topology[regionLabel] = region
-		topologyAffinity = formatTopologyAffinity(corev1.LabelZoneRegionStable, region)
-	}
+	// check for the region k8s topology label that was deprecated in 1.17,
+	// then for the region k8s topology label that is GA in 1.17.
+	const regionLabel = "region"
+	setTopology(regionLabel, corev1.LabelZoneRegion)
+	setTopology(regionLabel, corev1.LabelZoneRegionStable)
 
-	// check for the zone k8s topology label that was deprecated in 1.17
+	// check for the zone k8s topology label that was deprecated in 1.17,
+	// then for the zone k8s topology label that is GA in 1.17.
 	const zoneLabel = "zone"
-	zone, ok := labels[corev1.LabelZoneFailureDomain]
-	if ok {
-		topology[zoneLabel] = zone
-		topologyAffinity = formatTopologyAffinity(corev1.LabelZoneFailureDomain, zone)
-	}
-
-	// check for the zone k8s topology label that is GA in 1.17.
-	zone, ok = labels[corev1.LabelZoneFailureDomainStable]
-	if ok {
-		topology[zoneLabel] = zone
-		topologyAffinity = formatTopologyAffinity(corev1.LabelZoneFailureDomainStable, zone)
-	}
+	setTopology(zoneLabel, corev1.LabelZoneFailureDomain)
+	setTopology(zoneLabel, corev1.LabelZoneFailureDomainStable)
 
 	// get host
 	host, ok := labels[corev1.LabelHostname]
@@ -100,19 +90,52 @@ func extractTopologyFromLabels(labels map[string]string) (map[string]string, str
 	}
 
 	// get the labels for the CRUSH map hierarchy
-	// iterate in reverse order so that the last topology found will be the lowest level in the hierarchy
-	// for the topology affinity
-	for i := len(CRUSHTopologyLabels) - 1; i >= 0; i-- {
-		topologyID := CRUSHTopologyLabels[i]
-		label := topologyLabelPrefix + topologyID
-		if value, ok := labels[label]; ok {
-			topology[topologyID] = value
-			topologyAffinity = formatTopologyAffinity(label, value)
+	for _, topologyID := range CRUSHTopologyLabels {
+		setTopology(topologyID, topologyLabelPrefix+topologyID)
+	}
+
+	// the labels declared in the storage spec take precedence over the well-known labels
+	for _, customLabel := range customLabels {
+		setTopology(customLabel.Type, customLabel.Label)
+	}
+
+	// The topology affinity for the osd is the lowest topology label found in the hierarchy,
+	// not including the host name
+	var topologyAffinity string
+	for _, topologyID := range CRUSHMapLevelsOrdered[1:] {
+		if label, ok := topologyLabels[topologyID]; ok {
+			topologyAffinity = formatTopologyAffinity(label, topology[topologyID])
+			break
 		}
 	}
 	return topology, topologyAffinity
 }
 
+// validateTopologyLabels validates the topology labels of the storage spec, which must be ordered from the
+// highest to the lowest level of the CRUSH hierarchy
+func validateTopologyLabels(customLabels []cephv1.CrushTopologyLabel) error {
+	previousLevel := len(CRUSHMapLevelsOrdered)
+	for _, customLabel := range customLabels {
+		if customLabel.Label == "" {
+			return errors.Errorf("the label of topology type %q is required", customLabel.Type)
+		}
+		level := -1
+		for i, topologyID := range CRUSHMapLevelsOrdered[1:] {
+			if topologyID == customLabel.Type {
+				level = i
+			}
+		}
+		if level == -1 {
+			return errors.Errorf("invalid topology type %q of label %q", customLabel.Type, customLabel.Label)
+		}
+		if level >= previousLevel {
+			return errors.Errorf("topology type %q of label %q must be lower in the CRUSH hierarchy than the types of the previous labels", customLabel.Type, customLabel.Label)
+		}
+		previousLevel = level
+	}
+	return nil
+}
+
 func formatTopologyAffinity(label, value string) string {
 	return fmt.Sprintf("%s=%s", label, value)
 }
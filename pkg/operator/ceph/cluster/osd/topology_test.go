@@ -22,6 +22,7 @@ package osd
 import (
 	"testing"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -49,7 +50,7 @@ func TestCleanTopologyLabels(t *testing.T) {
 		"topology.rook.io/row":              "r.row",
 		"topology.rook.io/datacenter":       "d.datacenter",
 	}
-	topology, affinity := ExtractOSDTopologyFromLabels(nodeLabels)
+	topology, affinity := ExtractOSDTopologyFromLabels(nodeLabels, nil)
 	assert.Equal(t, 6, len(topology))
 	assert.Equal(t, "r-region", topology["region"])
 	assert.Equal(t, "z-zone", topology["zone"])
@@ -62,7 +63,7 @@ func TestCleanTopologyLabels(t *testing.T) {
 
 func TestTopologyLabels(t *testing.T) {
 	nodeLabels := map[string]string{}
-	topology, affinity := extractTopologyFromLabels(nodeLabels)
+	topology, affinity := extractTopologyFromLabels(nodeLabels, nil)
 	assert.Equal(t, 0, len(topology))
 	assert.Equal(t, "", affinity)
 
@@ -71,7 +72,7 @@ func TestTopologyLabels(t *testing.T) {
 		"region": "badregion",
 		"zone":   "badzone",
 	}
-	topology, affinity = extractTopologyFromLabels(nodeLabels)
+	topology, affinity = extractTopologyFromLabels(nodeLabels, nil)
 	assert.Equal(t, 0, len(topology))
 	assert.Equal(t, "", affinity)
 
@@ -80,7 +81,7 @@ func TestTopologyLabels(t *testing.T) {
 		"topology.rook.io/region": "r1",
 		"topology.rook.io/zone":   "z1",
 	}
-	topology, affinity = extractTopologyFromLabels(nodeLabels)
+	topology, affinity = extractTopologyFromLabels(nodeLabels, nil)
 	assert.Equal(t, 0, len(topology))
 	assert.Equal(t, "", affinity)
 
@@ -93,7 +94,7 @@ func TestTopologyLabels(t *testing.T) {
 		"topology.rook.io/row":              "row1",
 		"topology.rook.io/datacenter":       "d1",
 	}
-	topology, affinity = extractTopologyFromLabels(nodeLabels)
+	topology, affinity = extractTopologyFromLabels(nodeLabels, nil)
 	assert.Equal(t, 6, len(topology))
 	assert.Equal(t, "r1", topology["region"])
 	assert.Equal(t, "z1", topology["zone"])
@@ -108,7 +109,7 @@ func TestTopologyLabels(t *testing.T) {
 		corev1.LabelZoneRegion:        "r1",
 		corev1.LabelZoneFailureDomain: "z1",
 	}
-	topology, affinity = extractTopologyFromLabels(nodeLabels)
+	topology, affinity = extractTopologyFromLabels(nodeLabels, nil)
 	assert.Equal(t, 2, len(topology))
 	assert.Equal(t, "r1", topology["region"])
 	assert.Equal(t, "z1", topology["zone"])
@@ -121,7 +122,7 @@ func TestTopologyLabels(t *testing.T) {
 		corev1.LabelZoneRegion:              "oldregion",
 		corev1.LabelZoneFailureDomain:       "oldzone",
 	}
-	topology, affinity = extractTopologyFromLabels(nodeLabels)
+	topology, affinity = extractTopologyFromLabels(nodeLabels, nil)
 	assert.Equal(t, 2, len(topology))
 	assert.Equal(t, "r1", topology["region"])
 	assert.Equal(t, "z1", topology["zone"])
@@ -131,7 +132,73 @@ func TestTopologyLabels(t *testing.T) {
 	nodeLabels = map[string]string{
 		"topology.rook.io/row/bad": "r1",
 	}
-	topology, affinity = extractTopologyFromLabels(nodeLabels)
+	topology, affinity = extractTopologyFromLabels(nodeLabels, nil)
 	assert.Equal(t, 0, len(topology))
 	assert.Equal(t, "", affinity)
 }
+
+func TestCustomTopologyLabels(t *testing.T) {
+	customLabels := []cephv1.CrushTopologyLabel{
+		{Label: "example.com/room", Type: "room"},
+		{Label: "example.com/pdu", Type: "pdu"},
+		{Label: "example.com/rack", Type: "rack"},
+	}
+
+	// the custom labels are added to the well-known labels
+	nodeLabels := map[string]string{
+		corev1.LabelZoneFailureDomainStable: "z1",
+		corev1.LabelHostname:                "host.name",
+		"example.com/room":                  "room.1",
+		"example.com/pdu":                   "pdu1",
+	}
+	topology, affinity := ExtractOSDTopologyFromLabels(nodeLabels, customLabels)
+	assert.Equal(t, 4, len(topology))
+	assert.Equal(t, "z1", topology["zone"])
+	assert.Equal(t, "room-1", topology["room"])
+	assert.Equal(t, "pdu1", topology["pdu"])
+	assert.Equal(t, "example.com/pdu=pdu1", affinity)
+
+	// the custom label takes precedence over the well-known label of the same type
+	nodeLabels = map[string]string{
+		"topology.rook.io/rack": "r1",
+		"topology.rook.io/row":  "row1",
+		"example.com/rack":      "r2",
+	}
+	topology, affinity = extractTopologyFromLabels(nodeLabels, customLabels)
+	assert.Equal(t, 2, len(topology))
+	assert.Equal(t, "r2", topology["rack"])
+	assert.Equal(t, "row1", topology["row"])
+	assert.Equal(t, "example.com/rack=r2", affinity)
+
+	// the affinity is the lowest level, even if it comes from a well-known label
+	nodeLabels = map[string]string{
+		"topology.rook.io/chassis": "c1",
+		"example.com/room":         "room1",
+	}
+	_, affinity = extractTopologyFromLabels(nodeLabels, customLabels)
+	assert.Equal(t, "topology.rook.io/chassis=c1", affinity)
+}
+
+func TestValidateTopologyLabels(t *testing.T) {
+	assert.NoError(t, validateTopologyLabels(nil))
+	assert.NoError(t, validateTopologyLabels([]cephv1.CrushTopologyLabel{
+		{Label: "example.com/region", Type: "region"},
+		{Label: "example.com/room", Type: "room"},
+		{Label: "example.com/chassis", Type: "chassis"},
+	}))
+
+	// the labels must be ordered from the highest to the lowest level
+	assert.Error(t, validateTopologyLabels([]cephv1.CrushTopologyLabel{
+		{Label: "example.com/rack", Type: "rack"},
+		{Label: "example.com/room", Type: "room"},
+	}))
+	// a level is declared once
+	assert.Error(t, validateTopologyLabels([]cephv1.CrushTopologyLabel{
+		{Label: "example.com/rack", Type: "rack"},
+		{Label: "example.com/rack2", Type: "rack"},
+	}))
+	// the host and the unknown types are not supported
+	assert.Error(t, validateTopologyLabels([]cephv1.CrushTopologyLabel{{Label: "example.com/host", Type: "host"}}))
+	assert.Error(t, validateTopologyLabels([]cephv1.CrushTopologyLabel{{Label: "example.com/shelf", Type: "shelf"}}))
+	assert.Error(t, validateTopologyLabels([]cephv1.CrushTopologyLabel{{Type: "rack"}}))
+}
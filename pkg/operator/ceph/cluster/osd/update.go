@@ -20,10 +20,12 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -39,6 +41,11 @@ var (
 	deploymentOnNodeFunc                 = deploymentOnNode
 	deploymentOnPVCFunc                  = deploymentOnPVC
 	shouldCheckOkToStopFunc              = cephclient.OSDUpdateShouldCheckOkToStop
+	isClusterCleanFunc                   = cephclient.IsClusterClean
+	// cleanPGsCheckInterval is the minimum time between two checks of the PGs while waiting for them to be
+	// clean between two batches of updates
+	cleanPGsCheckInterval  = 10 * time.Second
+	defaultCleanPGsTimeout = 10 * time.Minute
 )
 
 type updateConfig struct {
@@ -47,6 +54,16 @@ type updateConfig struct {
 	queue            *updateQueue   // these OSDs need updated
 	numUpdatesNeeded int            // the number of OSDs that needed updating
 	deployments      *existenceList // these OSDs have existing deployments
+	// canary holds the upgrade of the OSDs once an OSD of their device class was upgraded, until the
+	// upgrade is approved
+	canary *controller.CanaryUpgrade
+	// batchUpdated is whether the last batch of OSDs changed deployments, the PGs must be clean before
+	// updating the next batch if the update strategy waits for them
+	batchUpdated bool
+	// waitingSince is when the wait for the PGs to be clean started, and lastCleanCheck when they were last
+	// checked
+	waitingSince   time.Time
+	lastCleanCheck time.Time
 }
 
 func (c *Cluster) newUpdateConfig(
@@ -55,11 +72,11 @@ func (c *Cluster) newUpdateConfig(
 	deployments *existenceList,
 ) *updateConfig {
 	return &updateConfig{
-		c,
-		provisionConfig,
-		queue,
-		queue.Len(),
-		deployments,
+		cluster:          c,
+		provisionConfig:  provisionConfig,
+		queue:            queue,
+		numUpdatesNeeded: queue.Len(),
+		deployments:      deployments,
 	}
 }
 
@@ -75,6 +92,9 @@ func (c *updateConfig) updateExistingOSDs(errs *provisionErrors) {
 	if c.doneUpdating() {
 		return // no more OSDs to update
 	}
+	if !c.readyForNextBatch(errs) {
+		return
+	}
 	osdIDQuery, _ := c.queue.Pop()
 
 	var osdIDs []int
@@ -84,7 +104,7 @@ func (c *updateConfig) updateExistingOSDs(errs *provisionErrors) {
 		// less than 3 OSDs in the cluster or the cluster is on a single node. E.g., in CI :wink:.
 		osdIDs = []int{osdIDQuery}
 	} else {
-		osdIDs, err = cephclient.OSDOkToStop(c.cluster.context, c.cluster.clusterInfo, osdIDQuery, maxUpdatesInParallel)
+		osdIDs, err = cephclient.OSDOkToStop(c.cluster.context, c.cluster.clusterInfo, osdIDQuery, c.maxUpdatesInParallel())
 		if err != nil {
 			if c.cluster.spec.ContinueUpgradeAfterChecksEvenIfNotHealthy {
 				logger.Infof("OSD %d is not ok-to-stop but 'continueUpgradeAfterChecksEvenIfNotHealthy' is true, so continuing to update it", osdIDQuery)
@@ -97,8 +117,16 @@ func (c *updateConfig) updateExistingOSDs(errs *provisionErrors) {
 		}
 	}
 
+	osdIDs = c.limitBatch(osdIDQuery, osdIDs)
 	logger.Debugf("updating OSDs: %v", osdIDs)
 
+	canaryPeers, err := c.canaryPeers()
+	if err != nil {
+		errs.addError("failed to update OSDs %v. %v", osdIDs, err)
+		c.queue.Remove(osdIDs)
+		return
+	}
+
 	updatedDeployments := make([]*appsv1.Deployment, 0, len(osdIDs))
 	listIDs := []string{} // use this to build the k8s api selector query
 	for _, osdID := range osdIDs {
@@ -170,6 +198,12 @@ func (c *updateConfig) updateExistingOSDs(errs *provisionErrors) {
 			errs.addError("%v", errors.Wrapf(err, "failed to update OSD %d", osdID))
 			continue
 		}
+		if c.holdCanaryUpgrade(dep, updatedDep, osdInfo.DeviceClass, canaryPeers, updatedDeployments) {
+			continue
+		}
+		if k8sutil.DeploymentChanged(dep, updatedDep) {
+			c.batchUpdated = true
+		}
 
 		updatedDeployments = append(updatedDeployments, updatedDep)
 		listIDs = append(listIDs, strconv.Itoa(osdID))
@@ -188,6 +222,144 @@ func (c *updateConfig) updateExistingOSDs(errs *provisionErrors) {
 	c.queue.Remove(osdIDs)
 }
 
+// canaryPeers returns the OSD deployments when the canary upgrade is enabled
+func (c *updateConfig) canaryPeers() ([]appsv1.Deployment, error) {
+	if !c.canary.Enabled() {
+		return nil, nil
+	}
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := c.cluster.context.Clientset.AppsV1().Deployments(c.cluster.clusterInfo.Namespace).List(c.cluster.clusterInfo.Context, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the osd deployments for the canary upgrade")
+	}
+	return deployments.Items, nil
+}
+
+// holdCanaryUpgrade returns whether the update of the OSD must wait for the approval of the canary upgrade.
+// A single OSD of each device class is upgraded before the approval, including the OSDs of the current batch
+// that are not updated yet.
+func (c *updateConfig) holdCanaryUpgrade(current, updated *appsv1.Deployment, deviceClass string, peers []appsv1.Deployment, batch []*appsv1.Deployment) bool {
+	if !c.canary.Enabled() {
+		return false
+	}
+	classPeers := []appsv1.Deployment{}
+	for _, d := range peers {
+		if d.Labels[DeviceClassLabelKey] == deviceClass {
+			classPeers = append(classPeers, d)
+		}
+	}
+	for _, d := range batch {
+		if d.Labels[DeviceClassLabelKey] == deviceClass {
+			classPeers = append(classPeers, *d)
+		}
+	}
+	return c.canary.Hold(current, updated, classPeers)
+}
+
+// maxUpdatesInParallel returns the maximum number of OSDs updated in a batch
+func (c *updateConfig) maxUpdatesInParallel() int {
+	if strategy := c.cluster.spec.Storage.UpdateStrategy; strategy != nil && strategy.MaxUpdatesInParallel > 0 {
+		return strategy.MaxUpdatesInParallel
+	}
+	return maxUpdatesInParallel
+}
+
+// limitBatch keeps the OSDs of the batch in the failure domain of the queried OSD if the update strategy sets a
+// failure domain, and at most the maximum number of OSDs updated in a batch. The queried OSD is always kept.
+func (c *updateConfig) limitBatch(osdIDQuery int, osdIDs []int) []int {
+	strategy := c.cluster.spec.Storage.UpdateStrategy
+	if strategy == nil || len(osdIDs) <= 1 {
+		return osdIDs
+	}
+
+	batch := []int{osdIDQuery}
+	if strategy.FailureDomain == "" {
+		for _, osdID := range osdIDs {
+			if osdID != osdIDQuery && len(batch) < c.maxUpdatesInParallel() {
+				batch = append(batch, osdID)
+			}
+		}
+		return batch
+	}
+
+	listIDs := []string{}
+	for _, osdID := range osdIDs {
+		listIDs = append(listIDs, strconv.Itoa(osdID))
+	}
+	deployments, err := c.cluster.getFuncToListDeploymentsWithIDs(listIDs)()
+	if err != nil {
+		logger.Warningf("failed to list the deployments of osds %v to find their failure domain, updating osd %d alone. %v", osdIDs, osdIDQuery, err)
+		return batch
+	}
+	label := fmt.Sprintf(TopologyLocationLabel, strategy.FailureDomain)
+	domains := map[int]string{}
+	for i := range deployments.Items {
+		osdID, err := getOSDID(&deployments.Items[i])
+		if err != nil {
+			continue
+		}
+		domains[osdID] = deployments.Items[i].Labels[label]
+	}
+	domain := domains[osdIDQuery]
+	if domain == "" {
+		logger.Infof("no %s found for osd %d, updating it alone", strategy.FailureDomain, osdIDQuery)
+		return batch
+	}
+	for _, osdID := range osdIDs {
+		if osdID != osdIDQuery && domains[osdID] == domain && len(batch) < c.maxUpdatesInParallel() {
+			batch = append(batch, osdID)
+		}
+	}
+	logger.Debugf("updating osds %v of %s %q", batch, strategy.FailureDomain, domain)
+	return batch
+}
+
+// readyForNextBatch returns whether the next batch of OSDs can be updated. If the update strategy waits for the
+// PGs to be clean, the next batch is only updated once all the PGs are clean after the last batch changed
+// deployments. The OSDs left are not updated during this reconcile if the PGs are not clean before the timeout.
+func (c *updateConfig) readyForNextBatch(errs *provisionErrors) bool {
+	strategy := c.cluster.spec.Storage.UpdateStrategy
+	if !c.batchUpdated || strategy == nil || !strategy.WaitForCleanPGs {
+		return true
+	}
+
+	now := time.Now()
+	if c.waitingSince.IsZero() {
+		c.waitingSince = now
+	}
+	if now.Sub(c.lastCleanCheck) < cleanPGsCheckInterval {
+		return false
+	}
+	c.lastCleanCheck = now
+
+	msg, clean, err := isClusterCleanFunc(c.cluster.context, c.cluster.clusterInfo)
+	if err == nil && clean {
+		logger.Info("pgs are clean, updating the next osds")
+		c.batchUpdated = false
+		c.waitingSince = time.Time{}
+		return true
+	}
+
+	timeout := defaultCleanPGsTimeout
+	if strategy.CleanPGsTimeout != nil {
+		timeout = strategy.CleanPGsTimeout.Duration
+	}
+	if now.Sub(c.waitingSince) < timeout {
+		logger.Infof("waiting for the pgs to be clean before updating the next osds. %s", msg)
+		return false
+	}
+
+	if c.cluster.spec.ContinueUpgradeAfterChecksEvenIfNotHealthy {
+		logger.Infof("pgs are not clean after %s but 'continueUpgradeAfterChecksEvenIfNotHealthy' is true, so continuing to update the osds. %s", timeout.String(), msg)
+		c.batchUpdated = false
+		c.waitingSince = time.Time{}
+		return true
+	}
+	errs.addError("gave up waiting %s for the pgs to be clean, %d osds will be updated at the next reconcile. %s", timeout.String(), c.queue.Len(), msg)
+	c.queue = newUpdateQueueWithCapacity(0)
+	return false
+}
+
 // getOSDUpdateInfo returns an update queue of OSDs which need updated and an existence list of OSD
 // Deployments which already exist.
 func (c *Cluster) getOSDUpdateInfo(errs *provisionErrors) (*updateQueue, *existenceList, error) {
@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/coreos/pkg/capnslog"
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephclientfake "github.com/rook/rook/pkg/daemon/ceph/client/fake"
@@ -40,6 +42,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_updateExistingOSDs(t *testing.T) {
@@ -610,6 +613,127 @@ func createDeploymentOrPanic(clientset *fake.Clientset, d *appsv1.Deployment) {
 	}
 }
 
+func Test_updateStrategy(t *testing.T) {
+	namespace := "my-namespace"
+	clientset := fake.NewSimpleClientset()
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, Context: context.TODO()}
+	strategy := &cephv1.OSDUpdateStrategySpec{MaxUpdatesInParallel: 2}
+	spec := cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{UpdateStrategy: strategy}}
+	c := New(&clusterd.Context{Clientset: clientset}, clusterInfo, spec, "rook/rook:master")
+	updateConfig := c.newUpdateConfig(c.newProvisionConfig(), newUpdateQueueWithIDs(0, 1, 2, 3, 4), newExistenceListWithIDs(0, 1, 2, 3, 4))
+
+	hosts := map[int]string{0: "node0", 1: "node1", 2: "node0", 3: "node0", 4: "node1"}
+	for osdID, host := range hosts {
+		d := &appsv1.Deployment{}
+		d.SetName(deploymentName(osdID))
+		d.SetNamespace(namespace)
+		d.SetLabels(map[string]string{
+			OsdIdLabelKey: strconv.Itoa(osdID),
+			fmt.Sprintf(TopologyLocationLabel, "host"): host,
+		})
+		createDeploymentOrPanic(clientset, d)
+	}
+
+	t.Run("batches are limited to the max updates in parallel", func(t *testing.T) {
+		assert.Equal(t, 2, updateConfig.maxUpdatesInParallel())
+		assert.Equal(t, []int{1, 0}, updateConfig.limitBatch(1, []int{0, 1, 2, 3}))
+		assert.Equal(t, []int{3}, updateConfig.limitBatch(3, []int{3}))
+	})
+
+	t.Run("batches are limited to the failure domain of the queried OSD", func(t *testing.T) {
+		strategy.FailureDomain = "host"
+		strategy.MaxUpdatesInParallel = 20
+		assert.Equal(t, []int{0, 2, 3}, updateConfig.limitBatch(0, []int{0, 1, 2, 3, 4}))
+		assert.Equal(t, []int{4, 1}, updateConfig.limitBatch(4, []int{0, 1, 2, 3, 4}))
+		// an OSD without failure domain is updated alone
+		strategy.FailureDomain = "zone"
+		assert.Equal(t, []int{4}, updateConfig.limitBatch(4, []int{0, 1, 2, 3, 4}))
+	})
+
+	t.Run("wait for clean pgs between batches", func(t *testing.T) {
+		oldCleanFunc := isClusterCleanFunc
+		oldInterval := cleanPGsCheckInterval
+		defer func() {
+			isClusterCleanFunc = oldCleanFunc
+			cleanPGsCheckInterval = oldInterval
+		}()
+		cleanPGsCheckInterval = 0
+		clean := false
+		isClusterCleanFunc = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) (string, bool, error) {
+			return "pgs are recovering", clean, nil
+		}
+		errs := newProvisionErrors()
+
+		// no need to wait if the strategy does not wait or if the last batch did not change deployments
+		updateConfig.batchUpdated = true
+		assert.True(t, updateConfig.readyForNextBatch(errs))
+		strategy.WaitForCleanPGs = true
+		updateConfig.batchUpdated = false
+		assert.True(t, updateConfig.readyForNextBatch(errs))
+
+		updateConfig.batchUpdated = true
+		assert.False(t, updateConfig.readyForNextBatch(errs))
+		assert.False(t, updateConfig.waitingSince.IsZero())
+		clean = true
+		assert.True(t, updateConfig.readyForNextBatch(errs))
+		assert.False(t, updateConfig.batchUpdated)
+		assert.True(t, updateConfig.waitingSince.IsZero())
+		assert.Equal(t, 0, errs.len())
+
+		// the osds left are not updated once the timeout is over
+		clean = false
+		strategy.CleanPGsTimeout = &metav1.Duration{Duration: time.Minute}
+		updateConfig.batchUpdated = true
+		updateConfig.waitingSince = time.Now().Add(-2 * time.Minute)
+		assert.False(t, updateConfig.readyForNextBatch(errs))
+		assert.Equal(t, 1, errs.len())
+		assert.True(t, updateConfig.doneUpdating())
+	})
+}
+
+func Test_holdCanaryUpgrade(t *testing.T) {
+	namespace := "my-namespace"
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: namespace}}
+	cephCluster.Spec.CephVersion = cephv1.CephVersionSpec{Image: "ceph:v16", Canary: true}
+	cl := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := fake.NewSimpleClientset()
+	clusterdContext := &clusterd.Context{Client: cl, Clientset: clientset}
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, Context: context.TODO()}
+	c := New(clusterdContext, clusterInfo, cephv1.ClusterSpec{}, "rook/rook:master")
+	updateConfig := c.newUpdateConfig(c.newProvisionConfig(), newUpdateQueueWithIDs(0, 1, 2), newExistenceListWithIDs(0, 1, 2))
+
+	osdDeployment := func(osdID int, deviceClass, image string) *appsv1.Deployment {
+		d := &appsv1.Deployment{}
+		d.SetName(deploymentName(osdID))
+		d.SetNamespace(namespace)
+		d.SetLabels(map[string]string{k8sutil.AppAttr: AppName, DeviceClassLabelKey: deviceClass})
+		d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "osd", Image: image}}
+		return d
+	}
+	createDeploymentOrPanic(clientset, osdDeployment(0, "hdd", "ceph:v16"))
+	createDeploymentOrPanic(clientset, osdDeployment(1, "hdd", "ceph:v15"))
+	createDeploymentOrPanic(clientset, osdDeployment(2, "ssd", "ceph:v15"))
+
+	// the canary upgrade is disabled
+	peers, err := updateConfig.canaryPeers()
+	assert.NoError(t, err)
+	assert.Empty(t, peers)
+	assert.False(t, updateConfig.holdCanaryUpgrade(osdDeployment(1, "hdd", "ceph:v15"), osdDeployment(1, "hdd", "ceph:v16"), "hdd", peers, nil))
+
+	updateConfig.canary, err = controller.GetCanaryUpgrade(context.TODO(), clusterdContext, namespace)
+	assert.NoError(t, err)
+	peers, err = updateConfig.canaryPeers()
+	assert.NoError(t, err)
+	assert.Len(t, peers, 3)
+
+	// osd 0 is the canary of the hdd class
+	assert.True(t, updateConfig.holdCanaryUpgrade(osdDeployment(1, "hdd", "ceph:v15"), osdDeployment(1, "hdd", "ceph:v16"), "hdd", peers, nil))
+	// the first osd of the ssd class is its canary, but not the next one of the same batch
+	assert.False(t, updateConfig.holdCanaryUpgrade(osdDeployment(2, "ssd", "ceph:v15"), osdDeployment(2, "ssd", "ceph:v16"), "ssd", peers, nil))
+	batch := []*appsv1.Deployment{osdDeployment(2, "ssd", "ceph:v16")}
+	assert.True(t, updateConfig.holdCanaryUpgrade(osdDeployment(3, "ssd", "ceph:v15"), osdDeployment(3, "ssd", "ceph:v16"), "ssd", peers, batch))
+}
+
 func Test_updateQueue(t *testing.T) {
 	q := newUpdateQueueWithCapacity(2)
 	assert.Equal(t, 2, cap(q.q))
@@ -0,0 +1,312 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	osdCrushInitialWeightOption    = "osd_crush_initial_weight"
+	defaultWeightRampUpStepPercent = 10
+	defaultWeightRampUpInterval    = 10 * time.Minute
+	// weights are compared with the precision of the crush map
+	weightRampUpTolerance = 0.00001
+)
+
+var defaultWeightRampUpCheckInterval = time.Minute
+
+// WeightRampUp raises the CRUSH weight of the new OSDs in steps, up to the size of their device
+type WeightRampUp struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	interval    time.Duration
+}
+
+// NewWeightRampUp instantiates the ramp-up of the CRUSH weight of the new OSDs
+func NewWeightRampUp(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) *WeightRampUp {
+	return &WeightRampUp{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultWeightRampUpCheckInterval,
+	}
+}
+
+// Start checks at set intervals whether the weight of the new OSDs must be raised
+func (r *WeightRampUp) Start(context context.Context) {
+	for {
+		select {
+		case <-time.After(r.interval):
+			logger.Debug("checking the ramp-up of the osd weights")
+			if err := r.rampUp(); err != nil {
+				logger.Errorf("failed to ramp up the osd weights. %v", err)
+			}
+
+		case <-context.Done():
+			logger.Infof("stopping the ramp-up of the osd weights in namespace %q", r.clusterInfo.Namespace)
+			return
+		}
+	}
+}
+
+// rampUp raises the weight of all the OSDs being ramped up by one step, once the interval since the last step
+// elapsed and all the placement groups are active and clean. Only the OSDs created after the ramp-up was enabled
+// and starting with a weight of 0 are ramped up, so the weights set by the admin are left untouched.
+func (r *WeightRampUp) rampUp() error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := r.context.Client.Get(r.clusterInfo.Context, r.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", r.clusterInfo.NamespacedName().String())
+	}
+	spec := cephCluster.Spec.Storage.WeightRampUp
+	if spec == nil || !spec.Enabled {
+		return nil
+	}
+	stepPercent := defaultWeightRampUpStepPercent
+	if spec.StepPercent > 0 {
+		stepPercent = spec.StepPercent
+	}
+	interval := defaultWeightRampUpInterval
+	if spec.Interval != nil {
+		interval = spec.Interval.Duration
+	}
+
+	now := time.Now()
+	status := &cephv1.OSDWeightRampUpStatus{Since: now.UTC().Format(time.RFC3339)}
+	if cephCluster.Status.OSDWeightRampUp != nil {
+		status = cephCluster.Status.OSDWeightRampUp.DeepCopy()
+	}
+	since, err := time.Parse(time.RFC3339, status.Since)
+	if err != nil {
+		logger.Warningf("failed to parse the start time %q of the ramp-up of the osd weights, ramping up the osds created from now. %v", status.Since, err)
+		since = now
+		status.Since = now.UTC().Format(time.RFC3339)
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := r.context.Clientset.AppsV1().Deployments(r.clusterInfo.Namespace).List(r.clusterInfo.Context, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osd deployments")
+	}
+	usage, err := cephclient.GetOSDUsage(r.context, r.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the osd usage")
+	}
+	nodes := map[int]cephclient.OSDNodeUsage{}
+	for _, node := range usage.OSDNodes {
+		nodes[node.ID] = node
+	}
+
+	// the status only keeps the ramp-ups of the existing OSDs
+	rampUps := []cephv1.OSDWeightRampUp{}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if d.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+		osdID, err := getOSDID(d)
+		if err != nil {
+			logger.Errorf("failed to ramp up the weight of osd deployment %q. %v", d.Name, err)
+			continue
+		}
+		rampUp := cephv1.OSDWeightRampUp{ID: osdID}
+		for _, previous := range status.OSDs {
+			if previous.ID == osdID {
+				rampUp = previous
+			}
+		}
+
+		node, ok := nodes[osdID]
+		if !ok {
+			// the osd is not in the crush map yet
+			if rampUp.Phase != "" {
+				rampUps = append(rampUps, rampUp)
+			}
+			continue
+		}
+		weight, err := node.CrushWeight.Float64()
+		if err != nil {
+			logger.Errorf("failed to parse the crush weight %q of osd.%d. %v", node.CrushWeight, osdID, err)
+			continue
+		}
+		if rampUp.Phase == "" {
+			if weight > 0 {
+				// the weight was set when the osd was created or by the admin
+				rampUp.Phase = cephv1.OSDWeightRampUpCompleted
+			} else {
+				kb, err := node.KB.Int64()
+				if err != nil || kb == 0 {
+					// the size is known once the osd is up
+					continue
+				}
+				logger.Infof("ramping up the crush weight of new osd.%d", osdID)
+				rampUp.Phase = cephv1.OSDWeightRampUpRampingUp
+				rampUp.TargetWeight = formatWeight(float64(kb) / (1 << 30))
+			}
+		}
+		rampUp.Weight = formatWeight(weight)
+		rampUps = append(rampUps, rampUp)
+	}
+	status.OSDs = rampUps
+
+	if isWeightRampUpStepDue(status, interval, now) {
+		msg, clean, err := cephclient.IsClusterClean(r.context, r.clusterInfo)
+		if err != nil {
+			return errors.Wrap(err, "failed to check the placement groups before raising the osd weights")
+		}
+		if !clean {
+			logger.Infof("waiting for the placement groups to be clean before raising the osd weights. %s", msg)
+			status.Message = fmt.Sprintf("waiting for the placement groups to be clean. %s", msg)
+		} else {
+			raised := 0
+			for i := range status.OSDs {
+				rampUp := &status.OSDs[i]
+				if rampUp.Phase != cephv1.OSDWeightRampUpRampingUp {
+					continue
+				}
+				if err := r.raiseWeight(rampUp, stepPercent); err != nil {
+					logger.Errorf("failed to raise the crush weight of osd.%d. %v", rampUp.ID, err)
+					continue
+				}
+				raised++
+			}
+			status.LastStepTime = now.UTC().Format(time.RFC3339)
+			status.Message = fmt.Sprintf("raised the crush weight of %d osds", raised)
+		}
+	}
+
+	return r.updateStatus(status)
+}
+
+// raiseWeight raises the weight of the OSD by one step, without going over the target weight
+func (r *WeightRampUp) raiseWeight(rampUp *cephv1.OSDWeightRampUp, stepPercent int) error {
+	weight, err := strconv.ParseFloat(rampUp.Weight, 64)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the weight %q", rampUp.Weight)
+	}
+	target, err := strconv.ParseFloat(rampUp.TargetWeight, 64)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the target weight %q", rampUp.TargetWeight)
+	}
+
+	next := nextWeight(weight, target, stepPercent)
+	if err := cephclient.CrushReweight(r.context, r.clusterInfo, rampUp.ID, formatWeight(next)); err != nil {
+		return err
+	}
+	rampUp.Weight = formatWeight(next)
+	if next >= target {
+		logger.Infof("osd.%d reached its target crush weight %s", rampUp.ID, rampUp.TargetWeight)
+		rampUp.Phase = cephv1.OSDWeightRampUpCompleted
+	}
+	return nil
+}
+
+// isWeightRampUpStepDue returns whether some OSDs are ramped up and the interval since the last step elapsed
+func isWeightRampUpStepDue(status *cephv1.OSDWeightRampUpStatus, interval time.Duration, now time.Time) bool {
+	rampingUp := false
+	for _, rampUp := range status.OSDs {
+		if rampUp.Phase == cephv1.OSDWeightRampUpRampingUp {
+			rampingUp = true
+		}
+	}
+	if !rampingUp {
+		return false
+	}
+	if status.LastStepTime == "" {
+		return true
+	}
+	last, err := time.Parse(time.RFC3339, status.LastStepTime)
+	if err != nil {
+		logger.Warningf("failed to parse the time %q of the last step of the ramp-up of the osd weights. %v", status.LastStepTime, err)
+		return true
+	}
+	return now.Sub(last) >= interval
+}
+
+// nextWeight returns the weight after one step of the percentage of the target weight, capped to the target
+func nextWeight(weight, target float64, stepPercent int) float64 {
+	next := weight + target*float64(stepPercent)/100
+	if next > target-weightRampUpTolerance {
+		return target
+	}
+	return next
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', 5, 64)
+}
+
+// configureWeightRampUp starts the new OSDs with a crush weight of 0 when their weight is ramped up. The
+// initial weight set by the operator is removed when the ramp-up is disabled.
+func (c *Cluster) configureWeightRampUp() error {
+	monStore := opconfig.GetMonStore(c.context, c.clusterInfo)
+	if c.spec.Storage.WeightRampUp != nil && c.spec.Storage.WeightRampUp.Enabled {
+		if _, err := monStore.SetIfChanged("osd", osdCrushInitialWeightOption, "0"); err != nil {
+			return errors.Wrap(err, "failed to set the initial crush weight of the osds")
+		}
+		return nil
+	}
+
+	value, err := monStore.Get("osd", osdCrushInitialWeightOption)
+	if err != nil {
+		logger.Warningf("failed to get the initial crush weight of the osds. %v", err)
+		return nil
+	}
+	if value == "0" {
+		if err := monStore.Delete("osd", osdCrushInitialWeightOption); err != nil {
+			return errors.Wrap(err, "failed to remove the initial crush weight of the osds")
+		}
+	}
+	return nil
+}
+
+func (r *WeightRampUp) updateStatus(status *cephv1.OSDWeightRampUpStatus) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := r.context.Client.Get(r.clusterInfo.Context, r.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", r.clusterInfo.NamespacedName().String())
+	}
+
+	if reflect.DeepEqual(cephCluster.Status.OSDWeightRampUp, status) {
+		return nil
+	}
+	cephCluster.Status.OSDWeightRampUp = status
+	if err := reporting.UpdateStatus(r.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the OSD weight ramp-up status")
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNextWeight(t *testing.T) {
+	assert.Equal(t, 0.1, nextWeight(0, 1, 10))
+	assert.InDelta(t, 0.6, nextWeight(0.5, 1, 10), weightRampUpTolerance)
+	// the weight is capped to the target
+	assert.Equal(t, 1.0, nextWeight(0.95, 1, 10))
+	assert.Equal(t, 1.0, nextWeight(0.9, 1, 10))
+	assert.Equal(t, 2.0, nextWeight(0, 2, 100))
+}
+
+func TestIsWeightRampUpStepDue(t *testing.T) {
+	now := time.Now()
+	status := &cephv1.OSDWeightRampUpStatus{OSDs: []cephv1.OSDWeightRampUp{{ID: 0, Phase: cephv1.OSDWeightRampUpCompleted}}}
+	assert.False(t, isWeightRampUpStepDue(status, time.Minute, now))
+
+	status.OSDs = append(status.OSDs, cephv1.OSDWeightRampUp{ID: 1, Phase: cephv1.OSDWeightRampUpRampingUp})
+	assert.True(t, isWeightRampUpStepDue(status, time.Minute, now))
+
+	status.LastStepTime = now.Add(-30 * time.Second).UTC().Format(time.RFC3339)
+	assert.False(t, isWeightRampUpStepDue(status, time.Minute, now))
+	assert.True(t, isWeightRampUpStepDue(status, 10*time.Second, now))
+}
+
+func TestRampUpWeights(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, CephVersion: cephver.Pacific, Context: ctx}
+	clusterInfo.SetName("testcluster")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcluster", Namespace: namespace},
+		Spec: cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{
+			WeightRampUp: &cephv1.OSDWeightRampUpSpec{Enabled: true, StepPercent: 50, Interval: &metav1.Duration{}},
+		}},
+	}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	clientset := fake.NewSimpleClientset()
+
+	// osd.0 is new with a 2TiB device, osd.1 is new with a weight set by the admin
+	weights := map[int]string{0: "0", 1: "1.00000"}
+	clean := false
+	var reweights []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "df":
+				return fmt.Sprintf(`{"nodes":[{"id":0,"crush_weight":%s,"kb":2147483648},{"id":1,"crush_weight":%s,"kb":1073741824}]}`, weights[0], weights[1]), nil
+			case args[0] == "status":
+				if clean {
+					return `{"pgmap":{"num_pgs":1,"pgs_by_state":[{"state_name":"active+clean","count":1}]}}`, nil
+				}
+				return `{"pgmap":{"num_pgs":1,"pgs_by_state":[{"state_name":"active+remapped+backfilling","count":1}]}}`, nil
+			case args[0] == "osd" && args[1] == "crush" && args[2] == "reweight":
+				reweights = append(reweights, args[3]+"="+args[4])
+				id, _ := strconv.Atoi(args[3][len("osd."):])
+				weights[id] = args[4]
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	r := NewWeightRampUp(&clusterd.Context{Client: client, Clientset: clientset, Executor: executor}, clusterInfo)
+
+	getStatus := func() *cephv1.OSDWeightRampUpStatus {
+		err := client.Get(ctx, clusterInfo.NamespacedName(), cephCluster)
+		require.NoError(t, err)
+		return cephCluster.Status.OSDWeightRampUp
+	}
+
+	// the ramp-up starts with no osds
+	require.NoError(t, r.rampUp())
+	status := getStatus()
+	require.NotNil(t, status)
+	assert.NotEmpty(t, status.Since)
+	assert.Empty(t, status.OSDs)
+
+	// the osds created before the ramp-up are not ramped up
+	for id := 0; id < 3; id++ {
+		d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("rook-ceph-osd-%d", id),
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(time.Hour)),
+			Labels:            map[string]string{k8sutil.AppAttr: AppName, OsdIdLabelKey: strconv.Itoa(id)},
+		}}
+		if id == 2 {
+			d.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		}
+		_, err := clientset.AppsV1().Deployments(namespace).Create(ctx, d, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	// the weights are not raised until the placement groups are clean
+	require.NoError(t, r.rampUp())
+	status = getStatus()
+	assert.Equal(t, []cephv1.OSDWeightRampUp{
+		{ID: 0, Phase: cephv1.OSDWeightRampUpRampingUp, Weight: "0.00000", TargetWeight: "2.00000"},
+		{ID: 1, Phase: cephv1.OSDWeightRampUpCompleted, Weight: "1.00000"},
+	}, status.OSDs)
+	assert.Contains(t, status.Message, "waiting for the placement groups to be clean")
+	assert.Empty(t, reweights)
+
+	clean = true
+	require.NoError(t, r.rampUp())
+	assert.Equal(t, []string{"osd.0=1.00000"}, reweights)
+	status = getStatus()
+	assert.Equal(t, cephv1.OSDWeightRampUpRampingUp, status.OSDs[0].Phase)
+	assert.Equal(t, "1.00000", status.OSDs[0].Weight)
+	assert.NotEmpty(t, status.LastStepTime)
+
+	// the next step waits for the interval
+	cephCluster.Spec.Storage.WeightRampUp.Interval = &metav1.Duration{Duration: time.Hour}
+	require.NoError(t, client.Update(ctx, cephCluster))
+	require.NoError(t, r.rampUp())
+	assert.Equal(t, 1, len(reweights))
+
+	cephCluster.Spec.Storage.WeightRampUp.Interval = &metav1.Duration{}
+	require.NoError(t, client.Update(ctx, cephCluster))
+	require.NoError(t, r.rampUp())
+	assert.Equal(t, []string{"osd.0=1.00000", "osd.0=2.00000"}, reweights)
+	status = getStatus()
+	assert.Equal(t, cephv1.OSDWeightRampUpCompleted, status.OSDs[0].Phase)
+
+	// the completed osds are not ramped up again
+	require.NoError(t, r.rampUp())
+	assert.Equal(t, 2, len(reweights))
+}
+
+func TestConfigureWeightRampUp(t *testing.T) {
+	initialWeight := "-1.000000"
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if args[0] == "config" {
+				commands = append(commands, args[1])
+				switch args[1] {
+				case "get":
+					return initialWeight, nil
+				case "set":
+					initialWeight = args[4]
+				case "rm":
+					initialWeight = "-1.000000"
+				}
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	c := &Cluster{
+		context:     &clusterd.Context{Executor: executor},
+		clusterInfo: cephclient.AdminClusterInfo("ns"),
+		spec:        cephv1.ClusterSpec{},
+	}
+
+	// nothing to remove if the ramp-up was never enabled
+	require.NoError(t, c.configureWeightRampUp())
+	assert.Equal(t, []string{"get"}, commands)
+
+	commands = nil
+	c.spec.Storage.WeightRampUp = &cephv1.OSDWeightRampUpSpec{Enabled: true}
+	require.NoError(t, c.configureWeightRampUp())
+	assert.Equal(t, []string{"get", "set"}, commands)
+	assert.Equal(t, "0", initialWeight)
+
+	commands = nil
+	c.spec.Storage.WeightRampUp.Enabled = false
+	require.NoError(t, c.configureWeightRampUp())
+	assert.Equal(t, []string{"get", "rm"}, commands)
+}
@@ -151,6 +151,14 @@ func watchControllerPredicate() predicate.Funcs {
 
 					return false
 
+				} else if objOld.GetAnnotations()[controller.UpgradeApprovalAnnotation] != objNew.GetAnnotations()[controller.UpgradeApprovalAnnotation] {
+					logger.Infof("upgrade of CR %q was approved, reloading the manager to complete the upgrade of the daemons", objNew.Name)
+
+					// Restart the orchestrations, the daemons held by the canary upgrade are upgraded once approved
+					controller.ReloadManager()
+
+					return false
+
 				} else if objOld.GetGeneration() != objNew.GetGeneration() {
 					logger.Debugf("skipping resource %q update with unchanged spec", objNew.Name)
 				}
@@ -90,6 +90,7 @@ type peerSpec struct {
 // Add creates a new cephRBDMirror Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
@@ -177,6 +178,7 @@ func (r *ReconcileCephRBDMirror) reconcile(request reconcile.Request) (reconcile
 		logger.Debugf("CephCluster resource not ready in namespace %q, retrying in %q.", request.NamespacedName.Namespace, reconcileResponse.RequeueAfter.String())
 		return reconcileResponse, nil
 	}
+	opcontroller.RemoveUnvalidatedHotfix(&cephCluster)
 	r.cephClusterSpec = &cephCluster.Spec
 
 	// Populate clusterInfo
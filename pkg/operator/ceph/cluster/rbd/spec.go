@@ -67,6 +67,8 @@ func (r *ReconcileCephRBDMirror) makeDeployment(daemonConfig *daemonConfig, rbdM
 			return nil, err
 		}
 	}
+	cephv1.GetDaemonDNS(r.cephClusterSpec.DNS, cephv1.KeyRBDMirror).ApplyToPodSpec(&podSpec.Spec)
+	cephv1.GetDaemonContainerOverrides(r.cephClusterSpec.ContainerOverrides, cephv1.KeyRBDMirror).ApplyToPodSpec(&podSpec.Spec)
 	rbdMirror.Spec.Placement.ApplyToPodSpec(&podSpec.Spec)
 
 	replicas := int32(rbdMirror.Spec.Count)
@@ -96,7 +98,7 @@ func (r *ReconcileCephRBDMirror) makeDeployment(daemonConfig *daemonConfig, rbdM
 func (r *ReconcileCephRBDMirror) makeChownInitContainer(daemonConfig *daemonConfig, rbdMirror *cephv1.CephRBDMirror) v1.Container {
 	return controller.ChownCephDataDirsInitContainer(
 		*daemonConfig.DataPathMap,
-		r.cephClusterSpec.CephVersion.Image,
+		r.cephClusterSpec.CephImage(cephv1.HotfixDaemonRbdMirror),
 		controller.DaemonVolumeMounts(daemonConfig.DataPathMap, daemonConfig.ResourceName),
 		rbdMirror.Spec.Resources,
 		controller.PodSecurityContext(),
@@ -114,9 +116,9 @@ func (r *ReconcileCephRBDMirror) makeMirroringDaemonContainer(daemonConfig *daem
 			"--foreground",
 			"--name="+fullDaemonName(daemonConfig.DaemonID),
 		),
-		Image:           r.cephClusterSpec.CephVersion.Image,
+		Image:           r.cephClusterSpec.CephImage(cephv1.HotfixDaemonRbdMirror),
 		VolumeMounts:    controller.DaemonVolumeMounts(daemonConfig.DataPathMap, daemonConfig.ResourceName),
-		Env:             controller.DaemonEnvVars(r.cephClusterSpec.CephVersion.Image),
+		Env:             controller.DaemonEnvVars(r.cephClusterSpec.CephImage(cephv1.HotfixDaemonRbdMirror)),
 		Resources:       rbdMirror.Spec.Resources,
 		SecurityContext: controller.PodSecurityContext(),
 		WorkingDir:      config.VarLogCephDir,
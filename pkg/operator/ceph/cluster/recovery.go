@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	recoveryLabels = []string{"namespace"}
+
+	recoveryDegradedRatioGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_recovery_degraded_ratio",
+		Help: "Ratio of the object copies that are degraded",
+	}, recoveryLabels)
+	recoveryMisplacedRatioGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_recovery_misplaced_ratio",
+		Help: "Ratio of the object copies that are misplaced",
+	}, recoveryLabels)
+	recoveryObjectsPerSecGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_recovery_objects_per_second",
+		Help: "Rate of the recovery and the backfill in objects per second",
+	}, recoveryLabels)
+	recoveryRemainingSecondsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_recovery_estimated_remaining_seconds",
+		Help: "Estimated time until the recovery and the backfill complete in seconds",
+	}, recoveryLabels)
+)
+
+func init() {
+	metrics.Registry.MustRegister(recoveryDegradedRatioGauge, recoveryMisplacedRatioGauge, recoveryObjectsPerSecGauge, recoveryRemainingSecondsGauge)
+}
+
+// toRecoveryStatus returns the progress of the recovery and the backfill, or nil if no object is degraded
+// or misplaced. The start of the recovery is kept from the previous status, so the estimate survives
+// the restarts of the operator.
+func toRecoveryStatus(previous *cephv1.RecoveryStatus, pgMap cephclient.PgMap, now time.Time) *cephv1.RecoveryStatus {
+	remaining := pgMap.DegradedObjects + pgMap.MisplacedObjects
+	if remaining == 0 {
+		return nil
+	}
+
+	s := &cephv1.RecoveryStatus{
+		DegradedObjects:         pgMap.DegradedObjects,
+		DegradedPercent:         toPercent(pgMap.DegradedRatio),
+		MisplacedObjects:        pgMap.MisplacedObjects,
+		MisplacedPercent:        toPercent(pgMap.MisplacedRatio),
+		RecoveringObjectsPerSec: pgMap.RecoveryObjectsPerSec,
+		StartTime:               formatTime(now),
+		StartObjects:            remaining,
+	}
+	// the recovery starts over when more objects are degraded or misplaced, such as by another change of the topology
+	if previous != nil && previous.StartObjects >= remaining {
+		if startTime, err := time.Parse(time.RFC3339, previous.StartTime); err == nil {
+			s.StartTime = previous.StartTime
+			s.StartObjects = previous.StartObjects
+			if elapsed := now.Sub(startTime); remaining < s.StartObjects && elapsed > 0 {
+				rate := float64(s.StartObjects-remaining) / elapsed.Seconds()
+				s.EstimatedCompletionTime = formatTime(now.Add(time.Duration(float64(remaining) / rate * float64(time.Second))))
+			}
+		}
+	}
+	return s
+}
+
+func toPercent(ratio float64) float64 {
+	return math.Round(ratio*10000) / 100
+}
+
+// reportRecoveryMetrics sets the recovery gauges, which are removed when the recovery is completed
+func reportRecoveryMetrics(namespace string, recovery *cephv1.RecoveryStatus, now time.Time) {
+	if recovery == nil {
+		recoveryDegradedRatioGauge.DeleteLabelValues(namespace)
+		recoveryMisplacedRatioGauge.DeleteLabelValues(namespace)
+		recoveryObjectsPerSecGauge.DeleteLabelValues(namespace)
+		recoveryRemainingSecondsGauge.DeleteLabelValues(namespace)
+		return
+	}
+
+	recoveryDegradedRatioGauge.WithLabelValues(namespace).Set(recovery.DegradedPercent / 100)
+	recoveryMisplacedRatioGauge.WithLabelValues(namespace).Set(recovery.MisplacedPercent / 100)
+	recoveryObjectsPerSecGauge.WithLabelValues(namespace).Set(float64(recovery.RecoveringObjectsPerSec))
+	if completion, err := time.Parse(time.RFC3339, recovery.EstimatedCompletionTime); err == nil {
+		recoveryRemainingSecondsGauge.WithLabelValues(namespace).Set(math.Max(0, completion.Sub(now).Seconds()))
+	} else {
+		recoveryRemainingSecondsGauge.DeleteLabelValues(namespace)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryStatus(t *testing.T) {
+	start := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	// no recovery
+	assert.Nil(t, toRecoveryStatus(nil, cephclient.PgMap{}, start))
+
+	// the recovery starts
+	s := toRecoveryStatus(nil, cephclient.PgMap{DegradedObjects: 100, DegradedRatio: 0.012345, MisplacedObjects: 900, MisplacedRatio: 0.25, RecoveryObjectsPerSec: 5}, start)
+	require.NotNil(t, s)
+	assert.Equal(t, 1.23, s.DegradedPercent)
+	assert.Equal(t, 25.0, s.MisplacedPercent)
+	assert.Equal(t, uint64(5), s.RecoveringObjectsPerSec)
+	assert.Equal(t, "2021-06-01T10:00:00Z", s.StartTime)
+	assert.Equal(t, uint64(1000), s.StartObjects)
+	assert.Empty(t, s.EstimatedCompletionTime)
+
+	// 200 objects recovered in 10 minutes, the 800 remaining take 40 minutes
+	s = toRecoveryStatus(s, cephclient.PgMap{DegradedObjects: 0, MisplacedObjects: 800, MisplacedRatio: 0.2}, start.Add(10*time.Minute))
+	require.NotNil(t, s)
+	assert.Equal(t, "2021-06-01T10:00:00Z", s.StartTime)
+	assert.Equal(t, uint64(1000), s.StartObjects)
+	assert.Equal(t, "2021-06-01T10:50:00Z", s.EstimatedCompletionTime)
+
+	// more objects are misplaced, the recovery starts over
+	later := start.Add(20 * time.Minute)
+	s = toRecoveryStatus(s, cephclient.PgMap{MisplacedObjects: 1500, MisplacedRatio: 0.3}, later)
+	require.NotNil(t, s)
+	assert.Equal(t, "2021-06-01T10:20:00Z", s.StartTime)
+	assert.Equal(t, uint64(1500), s.StartObjects)
+	assert.Empty(t, s.EstimatedCompletionTime)
+
+	// the recovery completes
+	assert.Nil(t, toRecoveryStatus(s, cephclient.PgMap{}, later.Add(time.Hour)))
+}
+
+func TestRecoveryMetrics(t *testing.T) {
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	s := toRecoveryStatus(nil, cephclient.PgMap{MisplacedObjects: 1000, MisplacedRatio: 0.1, RecoveryObjectsPerSec: 20}, now.Add(-time.Hour))
+	s = toRecoveryStatus(s, cephclient.PgMap{MisplacedObjects: 500, MisplacedRatio: 0.05, RecoveryObjectsPerSec: 20}, now)
+
+	reportRecoveryMetrics("ns", s, now)
+	assert.Equal(t, 0.05, testutil.ToFloat64(recoveryMisplacedRatioGauge.WithLabelValues("ns")))
+	assert.Equal(t, 20.0, testutil.ToFloat64(recoveryObjectsPerSecGauge.WithLabelValues("ns")))
+	assert.Equal(t, 3600.0, testutil.ToFloat64(recoveryRemainingSecondsGauge.WithLabelValues("ns")))
+
+	reportRecoveryMetrics("ns", nil, now)
+	assert.Equal(t, 0, testutil.CollectAndCount(recoveryMisplacedRatioGauge))
+	assert.Equal(t, 0, testutil.CollectAndCount(recoveryRemainingSecondsGauge))
+}
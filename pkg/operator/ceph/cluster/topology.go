@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// topologyConfigMapName is the name of the configmap of the snapshot of the storage topology
+	topologyConfigMapName = "rook-ceph-topology"
+	// topologyConfigMapKey is the key of the snapshot in the configmap
+	topologyConfigMapKey = "topology.json"
+)
+
+var (
+	// defaultTopologyInterval is the interval between the refreshes of the snapshot of the storage topology
+	defaultTopologyInterval = 5 * time.Minute
+)
+
+// topologySnapshot is the snapshot of the storage topology, with the output of the ceph commands as is so
+// that the consumers get all the details reported by ceph
+type topologySnapshot struct {
+	// the time of the snapshot, so that the consumers can detect a stale snapshot
+	Time string `json:"time"`
+	FSID string `json:"fsid"`
+	// the output of "ceph osd pool ls detail"
+	Pools json.RawMessage `json:"pools"`
+	// the output of "ceph osd crush rule dump"
+	CrushRules json.RawMessage `json:"crushRules"`
+	// the output of "ceph osd tree"
+	OSDTree json.RawMessage `json:"osdTree"`
+	// the output of "ceph df"
+	Capacity json.RawMessage `json:"capacity"`
+}
+
+// topologyPublisher periodically publishes the snapshot of the storage topology in a configmap, so that
+// portals can render the topology with the permission to read the configmap only
+type topologyPublisher struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	interval    time.Duration
+}
+
+// newTopologyPublisher creates a new topologyPublisher object
+func newTopologyPublisher(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec) *topologyPublisher {
+	p := &topologyPublisher{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultTopologyInterval,
+	}
+	if interval := clusterSpec.Monitoring.Topology.Interval; interval != nil {
+		logger.Infof("topology snapshot interval is %s", interval.Duration.String())
+		p.interval = interval.Duration
+	}
+	return p
+}
+
+// publishTopology publishes the snapshot right away and then at each interval
+func (p *topologyPublisher) publishTopology(ctx context.Context) {
+	for {
+		logger.Debug("publishing the snapshot of the storage topology")
+		if err := p.publish(); err != nil {
+			logger.Warningf("failed to publish the snapshot of the storage topology. %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping the snapshots of the storage topology in namespace %q", p.clusterInfo.Namespace)
+			return
+
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+// publish takes a snapshot of the storage topology and stores it in the configmap
+func (p *topologyPublisher) publish() error {
+	snapshot, err := p.snapshot()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the snapshot of the storage topology")
+	}
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      topologyConfigMapName,
+			Namespace: p.clusterInfo.Namespace,
+		},
+		Data: map[string]string{topologyConfigMapKey: string(data)},
+	}
+	if err := p.clusterInfo.OwnerInfo.SetControllerReference(configMap); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to topology configmap %q", configMap.Name)
+	}
+	if _, err := p.context.Clientset.CoreV1().ConfigMaps(p.clusterInfo.Namespace).Create(p.clusterInfo.Context, configMap, metav1.CreateOptions{}); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrap(err, "failed to create the topology configmap")
+		}
+		if _, err := p.context.Clientset.CoreV1().ConfigMaps(p.clusterInfo.Namespace).Update(p.clusterInfo.Context, configMap, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to update the topology configmap")
+		}
+	}
+	return nil
+}
+
+func (p *topologyPublisher) snapshot() (*topologySnapshot, error) {
+	snapshot := &topologySnapshot{
+		Time: time.Now().UTC().Format(time.RFC3339),
+		FSID: p.clusterInfo.FSID,
+	}
+	for _, item := range []struct {
+		args   []string
+		output *json.RawMessage
+	}{
+		{[]string{"osd", "pool", "ls", "detail"}, &snapshot.Pools},
+		{[]string{"osd", "crush", "rule", "dump"}, &snapshot.CrushRules},
+		{[]string{"osd", "tree"}, &snapshot.OSDTree},
+		{[]string{"df"}, &snapshot.Capacity},
+	} {
+		buf, err := cephclient.NewCephCommand(p.context, p.clusterInfo, item.args).Run()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to run %q", item.args)
+		}
+		if !json.Valid(buf) {
+			return nil, errors.Errorf("invalid json output of %q", item.args)
+		}
+		*item.output = buf
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewTopologyPublisher(t *testing.T) {
+	clusterSpec := &cephv1.ClusterSpec{}
+	p := newTopologyPublisher(&clusterd.Context{}, cephclient.AdminClusterInfo("ns"), clusterSpec)
+	assert.Equal(t, defaultTopologyInterval, p.interval)
+
+	clusterSpec.Monitoring.Topology.Interval = &metav1.Duration{Duration: time.Minute}
+	p = newTopologyPublisher(&clusterd.Context{}, cephclient.AdminClusterInfo("ns"), clusterSpec)
+	assert.Equal(t, time.Minute, p.interval)
+}
+
+func TestPublishTopology(t *testing.T) {
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.FSID = "fsid"
+	failDF := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "pool":
+				return `[{"pool_name":"replicapool","size":3,"crush_rule":1}]`, nil
+			case args[0] == "osd" && args[1] == "crush":
+				return `[{"rule_id":1,"rule_name":"replicapool"}]`, nil
+			case args[0] == "osd" && args[1] == "tree":
+				return `{"nodes":[{"id":-1,"name":"default","type":"root"}],"stray":[]}`, nil
+			case args[0] == "df":
+				if failDF {
+					return "", errors.New("df failed")
+				}
+				return `{"stats":{"total_bytes":1000,"total_used_raw_bytes":100}}`, nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	c := &clusterd.Context{Executor: executor, Clientset: fake.NewSimpleClientset()}
+	p := newTopologyPublisher(c, clusterInfo, &cephv1.ClusterSpec{})
+
+	// the snapshot is published in the configmap
+	require.NoError(t, p.publish())
+	cm, err := c.Clientset.CoreV1().ConfigMaps("ns").Get(context.TODO(), topologyConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	var snapshot map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(cm.Data[topologyConfigMapKey]), &snapshot))
+	assert.Equal(t, "fsid", snapshot["fsid"])
+	assert.NotEmpty(t, snapshot["time"])
+	assert.Equal(t, "replicapool", snapshot["pools"].([]interface{})[0].(map[string]interface{})["pool_name"])
+	assert.Equal(t, "replicapool", snapshot["crushRules"].([]interface{})[0].(map[string]interface{})["rule_name"])
+	assert.NotNil(t, snapshot["osdTree"].(map[string]interface{})["nodes"])
+	assert.NotNil(t, snapshot["capacity"].(map[string]interface{})["stats"])
+
+	// the configmap is updated
+	require.NoError(t, p.publish())
+
+	// the last snapshot is kept when a command fails
+	failDF = true
+	assert.Error(t, p.publish())
+	cm2, err := c.Clientset.CoreV1().ConfigMaps("ns").Get(context.TODO(), topologyConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, cm2.Data[topologyConfigMapKey])
+}
@@ -50,6 +50,53 @@ func (c *ClusterController) detectAndValidateCephVersion(cluster *cluster) (*cep
 	return version, cluster.isUpgrade, nil
 }
 
+// detectAndValidateHotfixVersion detects the version of the hotfix image and checks that it can run along
+// with the version of the cluster before the hotfix is rolled out to the daemons
+func (c *ClusterController) detectAndValidateHotfixVersion(cluster *cluster, version cephver.CephVersion) error {
+	hotfix := cluster.Spec.CephVersion.Hotfix
+	if hotfix == nil {
+		c.updateClusterHotfixVersion(nil)
+		return nil
+	}
+
+	hotfixSpec := *cluster.Spec
+	hotfixSpec.CephVersion.Image = hotfix.Image
+	hotfixVersion, err := controller.DetectCephVersion(
+		c.rookImage,
+		cluster.Namespace,
+		detectHotfixVersionName,
+		cluster.ownerInfo,
+		c.context.Clientset,
+		&hotfixSpec,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to detect the version of hotfix image %q", hotfix.Image)
+	}
+
+	if err := validateHotfixVersion(version, *hotfixVersion); err != nil {
+		return errors.Wrapf(err, "invalid hotfix image %q", hotfix.Image)
+	}
+	logger.Infof("rolling out hotfix version %q to the daemons %v", hotfixVersion.String(), hotfix.Daemons)
+
+	c.updateClusterHotfixVersion(&cephv1.ClusterVersion{
+		Image:   hotfix.Image,
+		Version: controller.GetCephVersionLabel(*hotfixVersion),
+	})
+	return nil
+}
+
+// validateHotfixVersion checks that the hotfix is the same ceph release as the cluster and not older, since
+// the daemons of the hotfix must keep working with the rest of the cluster
+func validateHotfixVersion(clusterVersion, hotfixVersion cephver.CephVersion) error {
+	if hotfixVersion.Major != clusterVersion.Major {
+		return errors.Errorf("hotfix version %q is not the same ceph release as the cluster version %q", hotfixVersion.String(), clusterVersion.String())
+	}
+	if cephver.IsInferior(hotfixVersion, clusterVersion) {
+		return errors.Errorf("hotfix version %q is lower than the cluster version %q", hotfixVersion.String(), clusterVersion.String())
+	}
+	return nil
+}
+
 func (c *cluster) printOverallCephVersion() {
 	versions, err := daemonclient.GetAllCephDaemonVersions(c.context, c.ClusterInfo)
 	if err != nil {
@@ -57,8 +104,9 @@ func (c *cluster) printOverallCephVersion() {
 		return
 	}
 
-	if len(versions.Overall) == 1 {
-		for v := range versions.Overall {
+	overall := withoutHotfixDaemons(*versions, c.Spec.CephVersion.Hotfix).Overall
+	if len(overall) == 1 {
+		for v := range overall {
 			version, err := cephver.ExtractCephVersion(v)
 			if err != nil {
 				logger.Errorf("failed to extract ceph version. %v", err)
@@ -69,8 +117,44 @@ func (c *cluster) printOverallCephVersion() {
 		}
 	} else {
 		// This shouldn't happen, but let's log just in case
-		logger.Warningf("upgrade orchestration completed but somehow we still have more than one Ceph version running. %v:", versions.Overall)
+		logger.Warningf("upgrade orchestration completed but somehow we still have more than one Ceph version running. %v:", overall)
+	}
+}
+
+// withoutHotfixDaemons returns the running versions without the types of daemons running the hotfix image,
+// with the overall versions of the other daemons. The hotfix version differs from the version of the image
+// of the cluster, which is not an upgrade of the cluster.
+func withoutHotfixDaemons(versions cephv1.CephDaemonsVersions, hotfix *cephv1.CephHotfixSpec) cephv1.CephDaemonsVersions {
+	if hotfix == nil || len(hotfix.Daemons) == 0 {
+		return versions
+	}
+
+	hotfixed := map[cephv1.HotfixDaemonType]bool{}
+	for _, d := range hotfix.Daemons {
+		hotfixed[d] = true
+	}
+	filtered := cephv1.CephDaemonsVersions{Mon: versions.Mon, Osd: versions.Osd, Overall: map[string]int{}}
+	if !hotfixed[cephv1.HotfixDaemonMgr] {
+		filtered.Mgr = versions.Mgr
+	}
+	if !hotfixed[cephv1.HotfixDaemonMds] {
+		filtered.Mds = versions.Mds
+	}
+	if !hotfixed[cephv1.HotfixDaemonRgw] {
+		filtered.Rgw = versions.Rgw
+	}
+	if !hotfixed[cephv1.HotfixDaemonRbdMirror] {
+		filtered.RbdMirror = versions.RbdMirror
+	}
+	if !hotfixed[cephv1.HotfixDaemonCephFSMirror] {
+		filtered.CephFSMirror = versions.CephFSMirror
+	}
+	for _, daemonVersions := range []map[string]int{filtered.Mon, filtered.Mgr, filtered.Osd, filtered.Rgw, filtered.Mds, filtered.RbdMirror, filtered.CephFSMirror} {
+		for v, count := range daemonVersions {
+			filtered.Overall[v] += count
+		}
 	}
+	return filtered
 }
 
 // This function compare the Ceph spec image and the cluster running version
@@ -179,7 +263,8 @@ func (c *cluster) validateCephVersion(version *cephver.CephVersion) error {
 		return nil
 	}
 
-	runningVersions := *versions
+	// the daemons running a hotfix are not compared to the image of the cluster
+	runningVersions := withoutHotfixDaemons(*versions, c.Spec.CephVersion.Hotfix)
 	differentImages, err := diffImageSpecAndClusterRunningVersion(*version, runningVersions)
 	if err != nil {
 		logger.Errorf("failed to determine if we should upgrade or not. %v", err)
@@ -200,3 +200,52 @@ func testSpec(t *testing.T) *cluster {
 	}
 	return &cluster{Spec: &cephv1.ClusterSpec{}, context: context}
 }
+
+func TestValidateHotfixVersion(t *testing.T) {
+	clusterVersion := cephver.CephVersion{Major: 16, Minor: 2, Extra: 6}
+
+	// a newer build of the same release is valid
+	assert.NoError(t, validateHotfixVersion(clusterVersion, cephver.CephVersion{Major: 16, Minor: 2, Extra: 6, Build: 1}))
+	assert.NoError(t, validateHotfixVersion(clusterVersion, cephver.CephVersion{Major: 16, Minor: 2, Extra: 7}))
+	assert.NoError(t, validateHotfixVersion(clusterVersion, clusterVersion))
+
+	// an older version is not valid
+	assert.Error(t, validateHotfixVersion(clusterVersion, cephver.CephVersion{Major: 16, Minor: 2, Extra: 5}))
+
+	// another release is not valid
+	assert.Error(t, validateHotfixVersion(clusterVersion, cephver.CephVersion{Major: 17, Minor: 2, Extra: 0}))
+	assert.Error(t, validateHotfixVersion(clusterVersion, cephver.CephVersion{Major: 15, Minor: 2, Extra: 14}))
+}
+
+func TestDiffImageSpecWithRunningHotfix(t *testing.T) {
+	imageVersion := cephver.CephVersion{Major: 16, Minor: 2, Extra: 6, CommitID: "ee28fb57e47e9f88813e24bbf4c14496ca299d31"}
+	runningVersions := cephv1.CephDaemonsVersions{
+		Mon: map[string]int{"ceph version 16.2.6 (ee28fb57e47e9f88813e24bbf4c14496ca299d31) pacific (stable)": 3},
+		Mgr: map[string]int{"ceph version 16.2.7 (dd0603118f56ab514f133c8d2e3adfc983942503) pacific (stable)": 1},
+		Osd: map[string]int{"ceph version 16.2.6 (ee28fb57e47e9f88813e24bbf4c14496ca299d31) pacific (stable)": 3},
+		Overall: map[string]int{
+			"ceph version 16.2.6 (ee28fb57e47e9f88813e24bbf4c14496ca299d31) pacific (stable)": 6,
+			"ceph version 16.2.7 (dd0603118f56ab514f133c8d2e3adfc983942503) pacific (stable)": 1,
+		},
+	}
+
+	// the mgr running the hotfix looks like an upgrade in progress
+	m, err := diffImageSpecAndClusterRunningVersion(imageVersion, runningVersions)
+	assert.NoError(t, err)
+	assert.True(t, m)
+
+	// the hotfixed daemons are not compared to the image of the cluster
+	hotfix := &cephv1.CephHotfixSpec{Image: "quay.io/ceph/ceph:v16.2.7", Daemons: []cephv1.HotfixDaemonType{cephv1.HotfixDaemonMgr}}
+	filtered := withoutHotfixDaemons(runningVersions, hotfix)
+	assert.Nil(t, filtered.Mgr)
+	assert.Equal(t, map[string]int{"ceph version 16.2.6 (ee28fb57e47e9f88813e24bbf4c14496ca299d31) pacific (stable)": 6}, filtered.Overall)
+	m, err = diffImageSpecAndClusterRunningVersion(imageVersion, filtered)
+	assert.NoError(t, err)
+	assert.False(t, m)
+
+	// an upgrade of the other daemons is still detected
+	assert.Equal(t, runningVersions, withoutHotfixDaemons(runningVersions, nil))
+	m, err = diffImageSpecAndClusterRunningVersion(cephver.CephVersion{Major: 16, Minor: 2, Extra: 7}, filtered)
+	assert.NoError(t, err)
+	assert.True(t, m)
+}
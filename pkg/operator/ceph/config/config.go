@@ -145,7 +145,7 @@ func SetOrRemoveDefaultConfigs(
 	// Apply Multus if needed
 	if clusterSpec.Network.IsMultus() {
 		logger.Info("configuring ceph network(s) with multus")
-		cephNetworks, err := generateNetworkSettings(context, clusterInfo.Namespace, clusterSpec.Network.Selectors)
+		cephNetworks, err := generateNetworkSettings(context, clusterInfo.Namespace, clusterSpec.Network.Selectors, clusterSpec.Network.GetIPFamilies())
 		if err != nil {
 			return errors.Wrap(err, "failed to generate network settings")
 		}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyring
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Repair is a key of a secret that was replaced with the key of the Ceph user
+type Repair struct {
+	SecretName string
+	User       string
+}
+
+// RepairKeyringSecrets replaces the keys of the keyring secrets that differ from the keys of the Ceph
+// users, such as after a partial restore. The users of the keyrings that are not known by Ceph are
+// skipped, such as "mon.".
+func (k *SecretStore) RepairKeyringSecrets(cephKeys map[string]string) ([]Repair, error) {
+	secrets, err := k.context.Clientset.CoreV1().Secrets(k.clusterInfo.Namespace).List(k.clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the secrets")
+	}
+
+	repairs := []Repair{}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		keyring, ok := secret.Data[keyringFileName]
+		if !ok || !strings.HasSuffix(secret.Name, keyringSecretName("")) {
+			continue
+		}
+
+		repaired := string(keyring)
+		for user, key := range ParseKeys(repaired) {
+			cephKey, ok := cephKeys[user]
+			if !ok || cephKey == key {
+				continue
+			}
+			repaired = ReplaceKey(repaired, user, cephKey)
+			repairs = append(repairs, Repair{SecretName: secret.Name, User: user})
+		}
+		if repaired == string(keyring) {
+			continue
+		}
+
+		logger.Warningf("repairing keyring secret %q, its keys differ from the keys of the ceph users", secret.Name)
+		secret.Data[keyringFileName] = []byte(repaired)
+		if err := k.updateSecret(secret); err != nil {
+			return repairs, err
+		}
+	}
+	return repairs, nil
+}
+
+// RepairKeySecret replaces the key stored in the data field of the secret if it differs from the key of
+// the Ceph user. A missing secret is not repaired, it is created by the reconcile of its owner.
+func (k *SecretStore) RepairKeySecret(secretName, field, user string, cephKeys map[string]string) (*Repair, error) {
+	cephKey, ok := cephKeys[user]
+	if !ok {
+		return nil, nil
+	}
+	secret, err := k.context.Clientset.CoreV1().Secrets(k.clusterInfo.Namespace).Get(k.clusterInfo.Context, secretName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get secret %q", secretName)
+	}
+	if string(secret.Data[field]) == cephKey {
+		return nil, nil
+	}
+
+	logger.Warningf("repairing secret %q, its key differs from the key of ceph user %q", secretName, user)
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[field] = []byte(cephKey)
+	if err := k.updateSecret(secret); err != nil {
+		return nil, err
+	}
+	return &Repair{SecretName: secretName, User: user}, nil
+}
+
+func (k *SecretStore) updateSecret(secret *v1.Secret) error {
+	if _, err := k.context.Clientset.CoreV1().Secrets(k.clusterInfo.Namespace).Update(k.clusterInfo.Context, secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update secret %q", secret.Name)
+	}
+	return nil
+}
+
+// ParseKeys returns the keys of the users of the keyring, by user name
+func ParseKeys(keyring string) map[string]string {
+	keys := map[string]string{}
+	user := ""
+	for _, line := range strings.Split(keyring, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			user = strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+			continue
+		}
+		if key, ok := parseKeyLine(line); ok && user != "" {
+			keys[user] = key
+		}
+	}
+	return keys
+}
+
+// ReplaceKey replaces the key of the user in the keyring, keeping the rest of the keyring as is
+func ReplaceKey(keyring, user, key string) string {
+	lines := strings.Split(keyring, "\n")
+	current := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			current = strings.TrimSuffix(strings.TrimPrefix(trimmed, "["), "]")
+			continue
+		}
+		if _, ok := parseKeyLine(trimmed); ok && current == user {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			lines[i] = indent + "key = " + key
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseKeyLine(line string) (string, bool) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) != "key" {
+		return "", false
+	}
+	return strings.TrimSpace(parts[1]), true
+}
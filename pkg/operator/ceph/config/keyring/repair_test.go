@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyring
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testMonKeyring = `
+[mon.]
+	key = monkey
+	caps mon = "allow *"
+
+[client.admin]
+	key = oldadminkey
+	caps mon = "allow *"
+`
+
+func TestParseAndReplaceKeys(t *testing.T) {
+	assert.Equal(t, map[string]string{"mon.": "monkey", "client.admin": "oldadminkey"}, ParseKeys(testMonKeyring))
+	assert.Empty(t, ParseKeys(""))
+
+	replaced := ReplaceKey(testMonKeyring, "client.admin", "adminkey")
+	assert.Equal(t, map[string]string{"mon.": "monkey", "client.admin": "adminkey"}, ParseKeys(replaced))
+	assert.Contains(t, replaced, "\tkey = adminkey\n")
+	assert.Contains(t, replaced, `caps mon = "allow *"`)
+	// unknown users are left as is
+	assert.Equal(t, testMonKeyring, ReplaceKey(testMonKeyring, "mgr.a", "mgrkey"))
+}
+
+func TestRepairSecrets(t *testing.T) {
+	ctx := context.TODO()
+	ns := "rook-ceph"
+	clientset := fake.NewSimpleClientset()
+	s := GetSecretStore(&clusterd.Context{Clientset: clientset}, cephclient.AdminClusterInfo(ns), &k8sutil.OwnerInfo{})
+	createSecret := func(name string, data map[string]string) {
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}, Data: map[string][]byte{}}
+		for k, v := range data {
+			secret.Data[k] = []byte(v)
+		}
+		_, err := clientset.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	getSecret := func(name, field string) string {
+		secret, err := clientset.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return string(secret.Data[field])
+	}
+	createSecret("rook-ceph-mons-keyring", map[string]string{"keyring": testMonKeyring})
+	createSecret("rook-ceph-mgr-a-keyring", map[string]string{"keyring": "[mgr.a]\n\tkey = mgrkey\n"})
+	createSecret("rook-csi-rbd-node", map[string]string{"userID": "csi-rbd-node", "userKey": "oldcsikey"})
+	// not a keyring
+	createSecret("other-keyring", map[string]string{"token": "key = x"})
+	cephKeys := map[string]string{"client.admin": "adminkey", "mgr.a": "mgrkey", "client.csi-rbd-node": "csikey"}
+
+	t.Run("keyring secrets", func(t *testing.T) {
+		repairs, err := s.RepairKeyringSecrets(cephKeys)
+		assert.NoError(t, err)
+		assert.Equal(t, []Repair{{SecretName: "rook-ceph-mons-keyring", User: "client.admin"}}, repairs)
+		assert.Equal(t, map[string]string{"mon.": "monkey", "client.admin": "adminkey"}, ParseKeys(getSecret("rook-ceph-mons-keyring", "keyring")))
+		assert.Equal(t, "[mgr.a]\n\tkey = mgrkey\n", getSecret("rook-ceph-mgr-a-keyring", "keyring"))
+
+		// nothing left to repair
+		repairs, err = s.RepairKeyringSecrets(cephKeys)
+		assert.NoError(t, err)
+		assert.Empty(t, repairs)
+	})
+
+	t.Run("key secrets", func(t *testing.T) {
+		repair, err := s.RepairKeySecret("rook-csi-rbd-node", "userKey", "client.csi-rbd-node", cephKeys)
+		assert.NoError(t, err)
+		assert.Equal(t, &Repair{SecretName: "rook-csi-rbd-node", User: "client.csi-rbd-node"}, repair)
+		assert.Equal(t, "csikey", getSecret("rook-csi-rbd-node", "userKey"))
+		assert.Equal(t, "csi-rbd-node", getSecret("rook-csi-rbd-node", "userID"))
+
+		repair, err = s.RepairKeySecret("rook-csi-rbd-node", "userKey", "client.csi-rbd-node", cephKeys)
+		assert.NoError(t, err)
+		assert.Nil(t, repair)
+		// missing secrets and users are skipped
+		repair, err = s.RepairKeySecret("rook-csi-rbd-provisioner", "userKey", "client.csi-rbd-node", cephKeys)
+		assert.NoError(t, err)
+		assert.Nil(t, repair)
+		repair, err = s.RepairKeySecret("rook-csi-rbd-node", "userKey", "client.unknown", cephKeys)
+		assert.NoError(t, err)
+		assert.Nil(t, repair)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+const (
+	mclockProfileOption = "osd_mclock_profile"
+	mclockCustomProfile = "custom"
+)
+
+var (
+	// the classes of IO of the mclock scheduler, the options are named osd_mclock_scheduler_<class>_<setting>
+	mclockClasses   = []string{"client", "background_recovery", "background_best_effort"}
+	mclockSettings  = []string{"res", "wgt", "lim"}
+	mclockOptionFmt = "osd_mclock_scheduler_%s_%s"
+)
+
+// ValidateMClockSpec validates the mclock scheduler settings
+func ValidateMClockSpec(spec *cephv1.MClockSpec) error {
+	if spec == nil {
+		return nil
+	}
+	if hasMClockAllocations(spec) && spec.Profile != "" && spec.Profile != mclockCustomProfile {
+		return errors.Errorf("mclock allocations require the %q profile, not %q", mclockCustomProfile, spec.Profile)
+	}
+	return nil
+}
+
+// MClockOptions returns the values of all the mclock options managed by Rook for the given
+// settings. The options with an empty value keep the Ceph defaults.
+func MClockOptions(spec *cephv1.MClockSpec) map[string]string {
+	options := map[string]string{mclockProfileOption: ""}
+	for _, class := range mclockClasses {
+		for _, setting := range mclockSettings {
+			options[fmt.Sprintf(mclockOptionFmt, class, setting)] = ""
+		}
+	}
+	if spec == nil {
+		return options
+	}
+
+	options[mclockProfileOption] = spec.Profile
+	if hasMClockAllocations(spec) {
+		options[mclockProfileOption] = mclockCustomProfile
+	}
+	allocations := []*cephv1.MClockAllocationSpec{spec.Client, spec.Recovery, spec.BestEffort}
+	for i, allocation := range allocations {
+		if allocation == nil {
+			continue
+		}
+		options[fmt.Sprintf(mclockOptionFmt, mclockClasses[i], "res")] = allocation.Reservation
+		if allocation.Weight > 0 {
+			options[fmt.Sprintf(mclockOptionFmt, mclockClasses[i], "wgt")] = strconv.Itoa(allocation.Weight)
+		}
+		options[fmt.Sprintf(mclockOptionFmt, mclockClasses[i], "lim")] = allocation.Limit
+	}
+	return options
+}
+
+// ApplyMClock sets the mclock options of the given settings for the OSDs matching "who", which
+// is "osd" or a mask such as "osd/class:ssd". The options not in the settings are removed.
+func (m *MonStore) ApplyMClock(who string, spec *cephv1.MClockSpec) error {
+	if err := ValidateMClockSpec(spec); err != nil {
+		return err
+	}
+	for option, value := range MClockOptions(spec) {
+		if value == "" {
+			if err := m.Delete(who, option); err != nil {
+				return errors.Wrapf(err, "failed to remove mclock option %q for %q", option, who)
+			}
+			continue
+		}
+		if err := m.Set(who, option, value); err != nil {
+			return errors.Wrapf(err, "failed to set mclock option %q for %q", option, who)
+		}
+	}
+	return nil
+}
+
+func hasMClockAllocations(spec *cephv1.MClockSpec) bool {
+	return spec.Client != nil || spec.Recovery != nil || spec.BestEffort != nil
+}
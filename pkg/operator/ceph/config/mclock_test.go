@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMClockOptions(t *testing.T) {
+	// all the options are removed without settings
+	options := MClockOptions(nil)
+	assert.Equal(t, 10, len(options))
+	for _, value := range options {
+		assert.Equal(t, "", value)
+	}
+
+	// a built-in profile
+	options = MClockOptions(&cephv1.MClockSpec{Profile: "high_client_ops"})
+	assert.Equal(t, "high_client_ops", options["osd_mclock_profile"])
+	assert.Equal(t, "", options["osd_mclock_scheduler_client_wgt"])
+
+	// the allocations select the custom profile
+	options = MClockOptions(&cephv1.MClockSpec{
+		Client:     &cephv1.MClockAllocationSpec{Reservation: "0.4", Weight: 5},
+		BestEffort: &cephv1.MClockAllocationSpec{Limit: "0.1"},
+	})
+	assert.Equal(t, "custom", options["osd_mclock_profile"])
+	assert.Equal(t, "0.4", options["osd_mclock_scheduler_client_res"])
+	assert.Equal(t, "5", options["osd_mclock_scheduler_client_wgt"])
+	assert.Equal(t, "", options["osd_mclock_scheduler_client_lim"])
+	assert.Equal(t, "", options["osd_mclock_scheduler_background_recovery_wgt"])
+	assert.Equal(t, "0.1", options["osd_mclock_scheduler_background_best_effort_lim"])
+}
+
+func TestApplyMClock(t *testing.T) {
+	sets := map[string]string{}
+	removed := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[1] == "set" {
+				sets[args[2]+" "+args[3]] = args[4]
+			}
+			if args[1] == "rm" {
+				removed = append(removed, args[2]+" "+args[3])
+			}
+			return "", nil
+		},
+	}
+	monStore := GetMonStore(&clusterd.Context{Executor: executor}, client.AdminClusterInfo("mycluster"))
+
+	err := monStore.ApplyMClock("osd/class:ssd", &cephv1.MClockSpec{Recovery: &cephv1.MClockAllocationSpec{Weight: 1, Limit: "0.2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"osd/class:ssd osd_mclock_profile":                           "custom",
+		"osd/class:ssd osd_mclock_scheduler_background_recovery_wgt": "1",
+		"osd/class:ssd osd_mclock_scheduler_background_recovery_lim": "0.2",
+	}, sets)
+	assert.Equal(t, 7, len(removed))
+	assert.Contains(t, removed, "osd/class:ssd osd_mclock_scheduler_client_wgt")
+
+	// invalid settings are not applied
+	sets = map[string]string{}
+	err = monStore.ApplyMClock("osd", &cephv1.MClockSpec{Profile: "balanced", Client: &cephv1.MClockAllocationSpec{Weight: 1}})
+	assert.Error(t, err)
+	assert.Empty(t, sets)
+}
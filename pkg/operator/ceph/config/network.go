@@ -20,9 +20,12 @@ package config
 import (
 	"context"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -45,9 +48,13 @@ var (
 	NetworkSelectors = []string{PublicNetworkSelectorKeyName, ClusterNetworkSelectorKeyName}
 )
 
-func generateNetworkSettings(clusterdContext *clusterd.Context, namespace string, networkSelectors map[string]string) ([]Option, error) {
+// generateNetworkSettings returns the public and cluster networks of the multus network attachment definitions. The
+// families of the networks must be among the ip families of the cluster. If no family is set, the daemons are bound
+// to the families of the networks, so that IPv6 and dual-stack networks are supported without further settings.
+func generateNetworkSettings(clusterdContext *clusterd.Context, namespace string, networkSelectors map[string]string, ipFamilies []cephv1.IPFamilyType) ([]Option, error) {
 	ctx := context.TODO()
 	cephNetworks := []Option{}
+	networkFamilies := map[cephv1.IPFamilyType]bool{}
 
 	for _, selectorKey := range NetworkSelectors {
 		// skip if selector is not specified
@@ -80,11 +87,61 @@ func generateNetworkSettings(clusterdContext *clusterd.Context, namespace string
 		} else {
 			return []Option{}, errors.Errorf("empty subnet from network attachment definition %q", networkSelectors[selectorKey])
 		}
+
+		for _, family := range getNetworkFamilies(networkRange) {
+			if len(ipFamilies) > 0 && !containsIPFamily(ipFamilies, family) {
+				return []Option{}, errors.Errorf("%s network %q of network attachment definition %q is not in the ip families %v of the cluster", family, networkRange, networkSelectors[selectorKey], ipFamilies)
+			}
+			networkFamilies[family] = true
+		}
+	}
+
+	// IPv4 only is the default of the daemons
+	if len(ipFamilies) == 0 && networkFamilies[cephv1.IPv6] {
+		logger.Infof("binding the daemons to the families of the networks, IPv4: %t, IPv6: true", networkFamilies[cephv1.IPv4])
+		cephNetworks = append(cephNetworks,
+			configOverride("global", "ms_bind_ipv4", strconv.FormatBool(networkFamilies[cephv1.IPv4])),
+			configOverride("global", "ms_bind_ipv6", "true"),
+		)
 	}
 
 	return cephNetworks, nil
 }
 
+// getNetworkFamilies returns the ip families of the subnets of a network range
+func getNetworkFamilies(networkRange string) []cephv1.IPFamilyType {
+	var families []cephv1.IPFamilyType
+	for _, subnet := range strings.Split(networkRange, ",") {
+		// the whereabouts ranges can be set as "<first ip>-<last ip>/<prefix>"
+		address := strings.Split(strings.TrimSpace(subnet), "/")[0]
+		if i := strings.LastIndex(address, "-"); i != -1 {
+			address = address[i+1:]
+		}
+		ip := net.ParseIP(address)
+		if ip == nil {
+			logger.Warningf("failed to detect the ip family of subnet %q", subnet)
+			continue
+		}
+		family := cephv1.IPv6
+		if ip.To4() != nil {
+			family = cephv1.IPv4
+		}
+		if !containsIPFamily(families, family) {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+func containsIPFamily(families []cephv1.IPFamilyType, family cephv1.IPFamilyType) bool {
+	for _, f := range families {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
+
 func GetMultusNamespace(nad string) (string, string) {
 	tmp := strings.Split(nad, "/")
 	if len(tmp) == 2 {
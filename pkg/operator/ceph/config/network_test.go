@@ -23,6 +23,7 @@ import (
 
 	networkv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	fakenetclient "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/fake"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	testop "github.com/rook/rook/pkg/operator/test"
@@ -39,7 +40,7 @@ func TestGenerateNetworkSettings(t *testing.T) {
 			NetworkClient: fakenetclient.NewSimpleClientset().K8sCniCncfIoV1(),
 		}
 		netSelector := map[string]string{"public": "public-network-attach-def"}
-		_, err := generateNetworkSettings(ctx, ns, netSelector)
+		_, err := generateNetworkSettings(ctx, ns, netSelector, nil)
 		assert.Error(t, err)
 
 	})
@@ -66,7 +67,7 @@ func TestGenerateNetworkSettings(t *testing.T) {
 			_, err := ctx.NetworkClient.NetworkAttachmentDefinitions(ns).Create(ctxt, &networks[i], metav1.CreateOptions{})
 			assert.NoError(t, err)
 		}
-		cephNetwork, err := generateNetworkSettings(ctx, ns, netSelector)
+		cephNetwork, err := generateNetworkSettings(ctx, ns, netSelector, nil)
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, cephNetwork, expectedNetworks, fmt.Sprintf("networks: %+v", cephNetwork))
 	})
@@ -92,7 +93,7 @@ func TestGenerateNetworkSettings(t *testing.T) {
 			_, err := ctx.NetworkClient.NetworkAttachmentDefinitions(ns).Create(ctxt, &networks[i], metav1.CreateOptions{})
 			assert.NoError(t, err)
 		}
-		cephNetwork, err := generateNetworkSettings(ctx, ns, netSelector)
+		cephNetwork, err := generateNetworkSettings(ctx, ns, netSelector, nil)
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, cephNetwork, expectedNetworks, fmt.Sprintf("networks: %+v", cephNetwork))
 
@@ -127,11 +128,74 @@ func TestGenerateNetworkSettings(t *testing.T) {
 			_, err := ctx.NetworkClient.NetworkAttachmentDefinitions(ns).Create(ctxt, &networks[i], metav1.CreateOptions{})
 			assert.NoError(t, err)
 		}
-		cephNetwork, err := generateNetworkSettings(ctx, ns, netSelector)
+		cephNetwork, err := generateNetworkSettings(ctx, ns, netSelector, nil)
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, cephNetwork, expectedNetworks, fmt.Sprintf("networks: %+v", cephNetwork))
 
 	})
+
+	t.Run("public and cluster network of different families", func(*testing.T) {
+		ctxt := context.TODO()
+		ns := "rook-ceph"
+		clientset := testop.New(t, 1)
+		ctx := &clusterd.Context{
+			Clientset:     clientset,
+			NetworkClient: fakenetclient.NewSimpleClientset().K8sCniCncfIoV1(),
+		}
+		netSelector := map[string]string{
+			"public":  "public-network-attach-def",
+			"cluster": "cluster-network-attach-def",
+		}
+		clusterNetwork := getClusterNetwork()
+		clusterNetwork.Spec.Config = `{"cniVersion": "0.3.0", "type": "macvlan", "ipam": {"type": "whereabouts", "range": "fd00:10::/64"}}`
+		networks := []networkv1.NetworkAttachmentDefinition{getPublicNetwork(), clusterNetwork}
+		expectedNetworks := []Option{
+			{
+				Who:    "global",
+				Option: "public_network",
+				Value:  "192.168.0.0/24",
+			},
+			{
+				Who:    "global",
+				Option: "cluster_network",
+				Value:  "fd00:10::/64",
+			},
+			{
+				Who:    "global",
+				Option: "ms_bind_ipv4",
+				Value:  "true",
+			},
+			{
+				Who:    "global",
+				Option: "ms_bind_ipv6",
+				Value:  "true",
+			},
+		}
+		for i := range networks {
+			_, err := ctx.NetworkClient.NetworkAttachmentDefinitions(ns).Create(ctxt, &networks[i], metav1.CreateOptions{})
+			assert.NoError(t, err)
+		}
+		cephNetwork, err := generateNetworkSettings(ctx, ns, netSelector, nil)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, cephNetwork, expectedNetworks, fmt.Sprintf("networks: %+v", cephNetwork))
+
+		// the binding is set by the ip families of the cluster
+		cephNetwork, err = generateNetworkSettings(ctx, ns, netSelector, []cephv1.IPFamilyType{cephv1.IPv4, cephv1.IPv6})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, cephNetwork, expectedNetworks[:2], fmt.Sprintf("networks: %+v", cephNetwork))
+
+		// the cluster network is not in the ip families of the cluster
+		_, err = generateNetworkSettings(ctx, ns, netSelector, []cephv1.IPFamilyType{cephv1.IPv4})
+		assert.Error(t, err)
+	})
+}
+
+func TestGetNetworkFamilies(t *testing.T) {
+	assert.Equal(t, []cephv1.IPFamilyType{cephv1.IPv4}, getNetworkFamilies("192.168.0.0/24"))
+	assert.Equal(t, []cephv1.IPFamilyType{cephv1.IPv6}, getNetworkFamilies("fd00::/64"))
+	assert.Equal(t, []cephv1.IPFamilyType{cephv1.IPv4, cephv1.IPv6}, getNetworkFamilies("10.0.0.5/24, fd00::5/64,10.0.1.5/24"))
+	assert.Equal(t, []cephv1.IPFamilyType{cephv1.IPv4}, getNetworkFamilies("192.168.2.225-192.168.2.230/28"))
+	assert.Nil(t, getNetworkFamilies("invalid"))
 }
 
 func getPublicNetwork() networkv1.NetworkAttachmentDefinition {
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+const (
+	scrubBeginHourOption = "osd_scrub_begin_hour"
+	scrubEndHourOption   = "osd_scrub_end_hour"
+	maxScrubsOption      = "osd_max_scrubs"
+	// DeepScrubIntervalOption is the interval between two deep scrubs of a PG, which the mgr also needs
+	// to report the PGs not deep scrubbed in time
+	DeepScrubIntervalOption = "osd_deep_scrub_interval"
+)
+
+// ValidateScrubSchedule validates the scrub settings of a group of OSDs
+func ValidateScrubSchedule(spec *cephv1.ScrubScheduleSpec) error {
+	if spec == nil {
+		return nil
+	}
+	for _, hour := range []*int{spec.BeginHour, spec.EndHour} {
+		if hour != nil && (*hour < 0 || *hour > 23) {
+			return errors.Errorf("scrub hour %d must be between 0 and 23", *hour)
+		}
+	}
+	if spec.MaxScrubs < 0 {
+		return errors.Errorf("max scrubs %d must be positive", spec.MaxScrubs)
+	}
+	if spec.DeepScrubInterval != nil && spec.DeepScrubInterval.Duration <= 0 {
+		return errors.Errorf("deep scrub interval %q must be positive", spec.DeepScrubInterval.Duration.String())
+	}
+	return nil
+}
+
+// ScrubOptions returns the values of all the scrub options managed by Rook for the given settings.
+// The options with an empty value keep the Ceph defaults.
+func ScrubOptions(spec *cephv1.ScrubScheduleSpec) map[string]string {
+	options := map[string]string{
+		scrubBeginHourOption:    "",
+		scrubEndHourOption:      "",
+		maxScrubsOption:         "",
+		DeepScrubIntervalOption: "",
+	}
+	if spec == nil {
+		return options
+	}
+	if spec.BeginHour != nil {
+		options[scrubBeginHourOption] = strconv.Itoa(*spec.BeginHour)
+	}
+	if spec.EndHour != nil {
+		options[scrubEndHourOption] = strconv.Itoa(*spec.EndHour)
+	}
+	if spec.MaxScrubs > 0 {
+		options[maxScrubsOption] = strconv.Itoa(spec.MaxScrubs)
+	}
+	if spec.DeepScrubInterval != nil {
+		options[DeepScrubIntervalOption] = strconv.FormatInt(int64(spec.DeepScrubInterval.Seconds()), 10)
+	}
+	return options
+}
+
+// ApplyScrub sets the scrub options of the given settings for the OSDs matching "who", which is
+// "osd" or a mask such as "osd/class:ssd". The options not in the settings are removed.
+func (m *MonStore) ApplyScrub(who string, spec *cephv1.ScrubScheduleSpec) error {
+	if err := ValidateScrubSchedule(spec); err != nil {
+		return err
+	}
+	for option, value := range ScrubOptions(spec) {
+		if value == "" {
+			if err := m.Delete(who, option); err != nil {
+				return errors.Wrapf(err, "failed to remove scrub option %q for %q", option, who)
+			}
+			continue
+		}
+		if err := m.Set(who, option, value); err != nil {
+			return errors.Wrapf(err, "failed to set scrub option %q for %q", option, who)
+		}
+	}
+	return nil
+}
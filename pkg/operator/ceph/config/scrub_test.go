@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScrubOptions(t *testing.T) {
+	// all the options are removed without settings
+	options := ScrubOptions(nil)
+	assert.Equal(t, 4, len(options))
+	for _, value := range options {
+		assert.Equal(t, "", value)
+	}
+
+	begin, end := 0, 6
+	options = ScrubOptions(&cephv1.ScrubScheduleSpec{
+		BeginHour:         &begin,
+		EndHour:           &end,
+		MaxScrubs:         2,
+		DeepScrubInterval: &metav1.Duration{Duration: 14 * 24 * time.Hour},
+	})
+	assert.Equal(t, "0", options["osd_scrub_begin_hour"])
+	assert.Equal(t, "6", options["osd_scrub_end_hour"])
+	assert.Equal(t, "2", options["osd_max_scrubs"])
+	assert.Equal(t, "1209600", options["osd_deep_scrub_interval"])
+
+	// invalid settings
+	invalid := 24
+	assert.Error(t, ValidateScrubSchedule(&cephv1.ScrubScheduleSpec{EndHour: &invalid}))
+	assert.Error(t, ValidateScrubSchedule(&cephv1.ScrubScheduleSpec{DeepScrubInterval: &metav1.Duration{}}))
+	assert.NoError(t, ValidateScrubSchedule(nil))
+}
+
+func TestApplyScrub(t *testing.T) {
+	sets := map[string]string{}
+	removed := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[1] == "set" {
+				sets[args[2]+" "+args[3]] = args[4]
+			}
+			if args[1] == "rm" {
+				removed = append(removed, args[2]+" "+args[3])
+			}
+			return "", nil
+		},
+	}
+	monStore := GetMonStore(&clusterd.Context{Executor: executor}, client.AdminClusterInfo("mycluster"))
+
+	err := monStore.ApplyScrub("osd/class:hdd", &cephv1.ScrubScheduleSpec{MaxScrubs: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"osd/class:hdd osd_max_scrubs": "1"}, sets)
+	assert.Equal(t, 3, len(removed))
+	assert.Contains(t, removed, "osd/class:hdd osd_deep_scrub_interval")
+}
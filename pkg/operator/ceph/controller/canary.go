@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// UpgradeApprovalAnnotation is the annotation of the CephCluster approving the upgrade of all the
+	// daemons to the image in its value when the canary upgrade is enabled
+	UpgradeApprovalAnnotation = "ceph.rook.io/approve-upgrade"
+)
+
+// CanaryUpgrade holds the upgrades of the daemons to a new image once a canary daemon of the same type
+// runs the image, until the upgrade is approved
+type CanaryUpgrade struct {
+	context *clusterd.Context
+	cluster *cephv1.CephCluster
+}
+
+// GetCanaryUpgrade returns the canary upgrade settings of the CephCluster of the namespace. The canary
+// upgrade is disabled if there is no CephCluster.
+func GetCanaryUpgrade(ctx context.Context, c *clusterd.Context, namespace string) (*CanaryUpgrade, error) {
+	if c.Client == nil {
+		return &CanaryUpgrade{}, nil
+	}
+	clusterList := &cephv1.CephClusterList{}
+	if err := c.Client.List(ctx, clusterList, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the ceph clusters in namespace %q", namespace)
+	}
+	if len(clusterList.Items) == 0 {
+		return &CanaryUpgrade{}, nil
+	}
+	return &CanaryUpgrade{context: c, cluster: &clusterList.Items[0]}, nil
+}
+
+// Enabled returns whether the upgrades of the daemons wait for approval once the canaries are upgraded
+func (u *CanaryUpgrade) Enabled() bool {
+	return u != nil && u.cluster != nil && u.cluster.Spec.CephVersion.Canary
+}
+
+// Approved returns whether the upgrade of all the daemons to the image was approved
+func (u *CanaryUpgrade) Approved(image string) bool {
+	return u.cluster != nil && u.cluster.Annotations[UpgradeApprovalAnnotation] == image
+}
+
+// Hold returns whether the update of a deployment to a new image must wait for approval because one of
+// its peers, the deployments of the same type of daemon, already runs the new image. The upgrade waiting
+// for approval is reported with a condition of the CephCluster.
+func (u *CanaryUpgrade) Hold(current, desired *appsv1.Deployment, peers []appsv1.Deployment) bool {
+	if !u.Enabled() {
+		return false
+	}
+	image := deploymentImage(desired)
+	if image == "" || image == deploymentImage(current) || u.Approved(image) {
+		return false
+	}
+	for i := range peers {
+		if peers[i].Name == current.Name || deploymentImage(&peers[i]) != image {
+			continue
+		}
+		logger.Infof("not upgrading deployment %q to image %q until the upgrade is approved, the canary deployment %q runs the image", current.Name, image, peers[i].Name)
+		if err := u.reportAwaitingApproval(image); err != nil {
+			logger.Errorf("failed to report the upgrade waiting for approval. %v", err)
+		}
+		return true
+	}
+	return false
+}
+
+// Reset resets the condition of the upgrade waiting for approval once the upgrade of the image of the
+// cluster was approved or the canary upgrade is disabled
+func (u *CanaryUpgrade) Reset() error {
+	if u.cluster == nil {
+		return nil
+	}
+	if u.Enabled() && !u.Approved(u.cluster.Spec.CephVersion.Image) {
+		return nil
+	}
+	awaiting := cephv1.FindStatusCondition(u.cluster.Status.Conditions, cephv1.ConditionUpgradeAwaitingApproval)
+	if awaiting == nil || awaiting.Status == v1.ConditionFalse {
+		return nil
+	}
+	condition := cephv1.Condition{
+		Type:    cephv1.ConditionUpgradeAwaitingApproval,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.UpgradeNotAwaitingApprovalReason,
+		Message: "no upgrade is waiting for approval",
+	}
+	if err := reporting.UpdateStatusCondition(u.context.Client, u.cluster, condition); err != nil {
+		return errors.Wrap(err, "failed to reset the upgrade waiting for approval")
+	}
+	return nil
+}
+
+func (u *CanaryUpgrade) reportAwaitingApproval(image string) error {
+	message := fmt.Sprintf("the canary daemons were upgraded to image %q. approve the upgrade of the other daemons with the annotation %s=%s on the CephCluster",
+		image, UpgradeApprovalAnnotation, image)
+	condition := cephv1.Condition{
+		Type:    cephv1.ConditionUpgradeAwaitingApproval,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.UpgradeAwaitingApprovalReason,
+		Message: message,
+	}
+	return reporting.UpdateStatusCondition(u.context.Client, u.cluster, condition)
+}
+
+// deploymentImage returns the image of the main container of the deployment
+func deploymentImage(d *appsv1.Deployment) string {
+	if d == nil || len(d.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+	return d.Spec.Template.Spec.Containers[0].Image
+}
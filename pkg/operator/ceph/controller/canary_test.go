@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCanaryUpgrade(t *testing.T) {
+	ctx := context.TODO()
+	deployment := func(name, image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: appsv1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "daemon", Image: image}},
+			}}},
+		}
+	}
+	peers := []appsv1.Deployment{*deployment("mon-a", "ceph:v16"), *deployment("mon-b", "ceph:v15"), *deployment("mon-c", "ceph:v15")}
+
+	t.Run("no cluster", func(t *testing.T) {
+		canary, err := GetCanaryUpgrade(ctx, &clusterd.Context{}, "ns")
+		assert.NoError(t, err)
+		assert.False(t, canary.Enabled())
+		assert.False(t, canary.Hold(deployment("mon-b", "ceph:v15"), deployment("mon-b", "ceph:v16"), peers))
+		assert.NoError(t, canary.Reset())
+	})
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+	cephCluster.Spec.CephVersion = cephv1.CephVersionSpec{Image: "ceph:v16", Canary: true}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	c := &clusterd.Context{Client: cl}
+	getCluster := func() *cephv1.CephCluster {
+		cluster := &cephv1.CephCluster{}
+		err := cl.Get(ctx, types.NamespacedName{Name: "rook", Namespace: "ns"}, cluster)
+		require.NoError(t, err)
+		return cluster
+	}
+
+	t.Run("the first daemon is the canary", func(t *testing.T) {
+		canary, err := GetCanaryUpgrade(ctx, c, "ns")
+		assert.NoError(t, err)
+		assert.True(t, canary.Enabled())
+		oldPeers := []appsv1.Deployment{*deployment("mon-a", "ceph:v15"), *deployment("mon-b", "ceph:v15")}
+		assert.False(t, canary.Hold(deployment("mon-a", "ceph:v15"), deployment("mon-a", "ceph:v16"), oldPeers))
+		// the canary is updated again with the same image
+		assert.False(t, canary.Hold(deployment("mon-a", "ceph:v16"), deployment("mon-a", "ceph:v16"), peers))
+		assert.Nil(t, cephv1.FindStatusCondition(getCluster().Status.Conditions, cephv1.ConditionUpgradeAwaitingApproval))
+	})
+
+	t.Run("the other daemons wait for approval", func(t *testing.T) {
+		canary, err := GetCanaryUpgrade(ctx, c, "ns")
+		assert.NoError(t, err)
+		assert.True(t, canary.Hold(deployment("mon-b", "ceph:v15"), deployment("mon-b", "ceph:v16"), peers))
+		condition := cephv1.FindStatusCondition(getCluster().Status.Conditions, cephv1.ConditionUpgradeAwaitingApproval)
+		require.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.UpgradeAwaitingApprovalReason, condition.Reason)
+		assert.Contains(t, condition.Message, "ceph.rook.io/approve-upgrade=ceph:v16")
+
+		// the upgrade is not approved yet
+		canary, err = GetCanaryUpgrade(ctx, c, "ns")
+		assert.NoError(t, err)
+		assert.NoError(t, canary.Reset())
+		assert.Equal(t, v1.ConditionTrue, cephv1.FindStatusCondition(getCluster().Status.Conditions, cephv1.ConditionUpgradeAwaitingApproval).Status)
+	})
+
+	t.Run("the upgrade of another image is not approved", func(t *testing.T) {
+		cluster := getCluster()
+		cluster.Annotations = map[string]string{UpgradeApprovalAnnotation: "ceph:v17"}
+		require.NoError(t, cl.Update(ctx, cluster))
+		canary, err := GetCanaryUpgrade(ctx, c, "ns")
+		assert.NoError(t, err)
+		assert.True(t, canary.Hold(deployment("mon-b", "ceph:v15"), deployment("mon-b", "ceph:v16"), peers))
+	})
+
+	t.Run("approved", func(t *testing.T) {
+		cluster := getCluster()
+		cluster.Annotations = map[string]string{UpgradeApprovalAnnotation: "ceph:v16"}
+		require.NoError(t, cl.Update(ctx, cluster))
+		canary, err := GetCanaryUpgrade(ctx, c, "ns")
+		assert.NoError(t, err)
+		assert.False(t, canary.Hold(deployment("mon-b", "ceph:v15"), deployment("mon-b", "ceph:v16"), peers))
+
+		assert.NoError(t, canary.Reset())
+		condition := cephv1.FindStatusCondition(getCluster().Status.Conditions, cephv1.ConditionUpgradeAwaitingApproval)
+		require.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+		assert.Equal(t, cephv1.UpgradeNotAwaitingApprovalReason, condition.Reason)
+	})
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	hookAppName       = "rook-ceph-hook"
+	hookLabel         = "hook"
+	hookJobNameFmt    = "rook-ceph-hook-%s"
+	hookContainerName = "hook"
+)
+
+// WaitForRequeueIfHooksPending waits for the resource to be ready or for the jobs of its hooks to complete
+var WaitForRequeueIfHooksPending = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+
+// HookResource is the resource running the hooks, which owns their jobs
+type HookResource struct {
+	// Kind is the kind of the resource, e.g. "CephObjectStore"
+	Kind      string
+	Name      string
+	Namespace string
+	OwnerInfo *k8sutil.OwnerInfo
+}
+
+// ReconcileHooks runs the post-ready hooks of a ready resource that did not succeed yet, and returns
+// their status along with whether a job is still running. A hook runs a single job, so a failed hook
+// is only run again when its job is deleted.
+func ReconcileHooks(ctx context.Context, clientset kubernetes.Interface, resource HookResource, hooks []cephv1.HookSpec, statuses []cephv1.HookStatus) ([]cephv1.HookStatus, bool, error) {
+	newStatuses := []cephv1.HookStatus{}
+	running := false
+	for _, hook := range hooks {
+		if status := findHookStatus(statuses, hook.Name); status != nil && status.Phase == cephv1.HookSucceeded {
+			newStatuses = append(newStatuses, *status)
+			continue
+		}
+
+		status, err := reconcileHookJob(ctx, clientset, resource, hook)
+		if err != nil {
+			return statuses, false, errors.Wrapf(err, "failed to run hook %q of %s %q", hook.Name, resource.Kind, resource.Name)
+		}
+		if status.Phase == cephv1.HookRunning {
+			running = true
+		}
+		newStatuses = append(newStatuses, status)
+	}
+	return newStatuses, running, nil
+}
+
+func findHookStatus(statuses []cephv1.HookStatus, name string) *cephv1.HookStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// reconcileHookJob creates the job of the hook if it does not exist, and returns its status
+func reconcileHookJob(ctx context.Context, clientset kubernetes.Interface, resource HookResource, hook cephv1.HookSpec) (cephv1.HookStatus, error) {
+	job, err := makeHookJob(resource, hook)
+	if err != nil {
+		return cephv1.HookStatus{}, err
+	}
+	status := cephv1.HookStatus{Name: hook.Name, JobName: job.Name, Phase: cephv1.HookRunning}
+
+	existingJob, err := clientset.BatchV1().Jobs(resource.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return status, errors.Wrapf(err, "failed to get job %q", job.Name)
+		}
+		logger.Infof("running hook %q of %s %q", hook.Name, resource.Kind, resource.Name)
+		if _, err := clientset.BatchV1().Jobs(resource.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+			return status, errors.Wrapf(err, "failed to create job %q", job.Name)
+		}
+		return status, nil
+	}
+
+	for _, condition := range existingJob.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batch.JobComplete:
+			status.Phase = cephv1.HookSucceeded
+			status.CompletionTime = existingJob.Status.CompletionTime
+			logger.Infof("hook %q of %s %q succeeded", hook.Name, resource.Kind, resource.Name)
+		case batch.JobFailed:
+			status.Phase = cephv1.HookFailed
+			status.Message = fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+			logger.Errorf("hook %q of %s %q failed. %s", hook.Name, resource.Kind, resource.Name, status.Message)
+		}
+	}
+	return status, nil
+}
+
+func makeHookJob(resource HookResource, hook cephv1.HookSpec) (*batch.Job, error) {
+	labels := AppLabels(hookAppName, resource.Namespace)
+	labels[hookLabel] = hook.Name
+
+	podSpec := v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: labels},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:    hookContainerName,
+					Image:   hook.Image,
+					Command: hook.Command,
+					Args:    hook.Args,
+					Env: append([]v1.EnvVar{
+						{Name: "ROOK_HOOK_RESOURCE_KIND", Value: resource.Kind},
+						{Name: "ROOK_HOOK_RESOURCE_NAME", Value: resource.Name},
+						{Name: "ROOK_HOOK_RESOURCE_NAMESPACE", Value: resource.Namespace},
+					}, hook.Env...),
+				},
+			},
+			ServiceAccountName: hook.ServiceAccountName,
+			RestartPolicy:      v1.RestartPolicyNever,
+		},
+	}
+
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      hookJobName(resource, hook.Name),
+			Namespace: resource.Namespace,
+			Labels:    labels,
+		},
+		Spec: batch.JobSpec{
+			BackoffLimit: hook.BackoffLimit,
+			Template:     podSpec,
+		},
+	}
+	if err := resource.OwnerInfo.SetControllerReference(job); err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference to job %q", job.Name)
+	}
+	return job, nil
+}
+
+// hookJobName returns the name of the job of a hook, unique for the kind and the name of the resource
+func hookJobName(resource HookResource, hookName string) string {
+	return k8sutil.TruncateNodeName(hookJobNameFmt, fmt.Sprintf("%s-%s-%s", strings.ToLower(resource.Kind), resource.Name, hookName))
+}
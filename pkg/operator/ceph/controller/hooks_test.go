@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcileHooks(t *testing.T) {
+	ctx := context.TODO()
+	clientset := fake.NewSimpleClientset()
+	resource := HookResource{
+		Kind:      "CephObjectStore",
+		Name:      "my-store",
+		Namespace: "rook-ceph",
+		OwnerInfo: k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{Name: "my-store", UID: "uid"}, "rook-ceph"),
+	}
+	hooks := []cephv1.HookSpec{
+		{Name: "seed", Image: "amazon/aws-cli", Args: []string{"s3", "mb", "s3://seed"}, Env: []v1.EnvVar{{Name: "FOO", Value: "bar"}}},
+		{Name: "acl", Image: "amazon/aws-cli", ServiceAccountName: "hooks"},
+	}
+	setJobCondition := func(name string, conditionType batch.JobConditionType) {
+		job, err := clientset.BatchV1().Jobs("rook-ceph").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		job.Status.Conditions = []batch.JobCondition{{Type: conditionType, Status: v1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "too many retries"}}
+		job.Status.CompletionTime = &metav1.Time{}
+		_, err = clientset.BatchV1().Jobs("rook-ceph").Update(ctx, job, metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+
+	// the jobs of the hooks are created
+	statuses, running, err := ReconcileHooks(ctx, clientset, resource, hooks, nil)
+	assert.NoError(t, err)
+	assert.True(t, running)
+	require.Equal(t, 2, len(statuses))
+	assert.Equal(t, cephv1.HookStatus{Name: "seed", JobName: "rook-ceph-hook-cephobjectstore-my-store-seed", Phase: cephv1.HookRunning}, statuses[0])
+	job, err := clientset.BatchV1().Jobs("rook-ceph").Get(ctx, statuses[0].JobName, metav1.GetOptions{})
+	require.NoError(t, err)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "amazon/aws-cli", container.Image)
+	assert.Equal(t, []string{"s3", "mb", "s3://seed"}, container.Args)
+	assert.Contains(t, container.Env, v1.EnvVar{Name: "ROOK_HOOK_RESOURCE_NAME", Value: "my-store"})
+	assert.Contains(t, container.Env, v1.EnvVar{Name: "FOO", Value: "bar"})
+	assert.Equal(t, "my-store", job.OwnerReferences[0].Name)
+
+	// the status follows the jobs
+	setJobCondition(statuses[0].JobName, batch.JobComplete)
+	setJobCondition(statuses[1].JobName, batch.JobFailed)
+	statuses, running, err = ReconcileHooks(ctx, clientset, resource, hooks, statuses)
+	assert.NoError(t, err)
+	assert.False(t, running)
+	assert.Equal(t, cephv1.HookSucceeded, statuses[0].Phase)
+	assert.NotNil(t, statuses[0].CompletionTime)
+	assert.Equal(t, cephv1.HookFailed, statuses[1].Phase)
+	assert.Equal(t, "BackoffLimitExceeded: too many retries", statuses[1].Message)
+
+	// a succeeded hook is not run again even if its job is deleted, a failed hook is
+	for _, status := range statuses {
+		assert.NoError(t, clientset.BatchV1().Jobs("rook-ceph").Delete(ctx, status.JobName, metav1.DeleteOptions{}))
+	}
+	statuses, running, err = ReconcileHooks(ctx, clientset, resource, hooks, statuses)
+	assert.NoError(t, err)
+	assert.True(t, running)
+	assert.Equal(t, cephv1.HookSucceeded, statuses[0].Phase)
+	assert.Equal(t, cephv1.HookRunning, statuses[1].Phase)
+	jobs, err := clientset.BatchV1().Jobs("rook-ceph").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobs.Items))
+	assert.Equal(t, statuses[1].JobName, jobs.Items[0].Name)
+
+	// the status of the removed hooks is dropped
+	statuses, _, err = ReconcileHooks(ctx, clientset, resource, hooks[:1], statuses)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(statuses))
+}
+
+func TestHookJobName(t *testing.T) {
+	resource := HookResource{Kind: "CephFilesystem", Name: "myfs"}
+	assert.Equal(t, "rook-ceph-hook-cephfilesystem-myfs-subvolumes", hookJobName(resource, "subvolumes"))
+
+	// long names are hashed
+	resource.Name = strings.Repeat("a", 40)
+	name := hookJobName(resource, "subvolumes")
+	assert.True(t, len(name) <= 63)
+	assert.True(t, strings.HasPrefix(name, "rook-ceph-hook-"))
+}
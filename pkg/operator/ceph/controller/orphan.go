@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/util/dependents"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OrphanedFromAnnotation is set on the resources orphaned by the deletion of their CR, with the kind and the name
+// of the CR, so they can be found for a manual recovery
+const OrphanedFromAnnotation = "ceph.rook.io/orphaned-from"
+
+// IsOrphanDeletionPolicy returns whether the resources generated for a CR are kept when the CR is deleted
+func IsOrphanDeletionPolicy(policy cephv1.DeletionPolicy) bool {
+	return policy == cephv1.DeletionPolicyOrphan
+}
+
+// OrphanGeneratedResources removes the owner references to the CR from the deployments, services, secrets,
+// configmaps and PVCs in the namespace of the CR, so they are not garbage collected with the CR. It returns
+// the orphaned resources.
+func OrphanGeneratedResources(ctx context.Context, c client.Client, owner client.Object) (*dependents.DependentList, error) {
+	orphaned := dependents.NewDependentList()
+	ownerName := fmt.Sprintf("%s/%s", owner.GetObjectKind().GroupVersionKind().Kind, owner.GetName())
+
+	for _, generated := range []struct {
+		pluralKind string
+		list       client.ObjectList
+	}{
+		{"Deployments", &appsv1.DeploymentList{}},
+		{"Services", &corev1.ServiceList{}},
+		{"Secrets", &corev1.SecretList{}},
+		{"ConfigMaps", &corev1.ConfigMapList{}},
+		{"PersistentVolumeClaims", &corev1.PersistentVolumeClaimList{}},
+	} {
+		if err := c.List(ctx, generated.list, client.InNamespace(owner.GetNamespace())); err != nil {
+			return orphaned, errors.Wrapf(err, "failed to list %s", generated.pluralKind)
+		}
+		items, err := meta.ExtractList(generated.list)
+		if err != nil {
+			return orphaned, errors.Wrapf(err, "failed to read the list of %s", generated.pluralKind)
+		}
+
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			refs := []metav1.OwnerReference{}
+			for _, ref := range obj.GetOwnerReferences() {
+				if ref.UID != owner.GetUID() {
+					refs = append(refs, ref)
+				}
+			}
+			if len(refs) == len(obj.GetOwnerReferences()) {
+				continue
+			}
+
+			obj.SetOwnerReferences(refs)
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[OrphanedFromAnnotation] = ownerName
+			obj.SetAnnotations(annotations)
+			if err := c.Update(ctx, obj); err != nil {
+				return orphaned, errors.Wrapf(err, "failed to remove the owner reference of %s %q", generated.pluralKind, obj.GetName())
+			}
+			orphaned.Add(generated.pluralKind, obj.GetName())
+		}
+	}
+
+	return orphaned, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOrphanGeneratedResources(t *testing.T) {
+	ctx := context.TODO()
+	owner := &cephv1.CephNFS{
+		TypeMeta:   metav1.TypeMeta{Kind: "CephNFS"},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nfs", Namespace: "rook-ceph", UID: "nfs-uid"},
+	}
+	ownedBy := func(uids ...types.UID) metav1.ObjectMeta {
+		meta := metav1.ObjectMeta{Namespace: "rook-ceph"}
+		for _, uid := range uids {
+			meta.OwnerReferences = append(meta.OwnerReferences, metav1.OwnerReference{UID: uid, Name: string(uid)})
+		}
+		return meta
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: ownedBy("nfs-uid")}
+	deployment.Name = "rook-ceph-nfs-my-nfs-a"
+	service := &corev1.Service{ObjectMeta: ownedBy("other-uid", "nfs-uid")}
+	service.Name = "rook-ceph-nfs-my-nfs-a"
+	otherSecret := &corev1.Secret{ObjectMeta: ownedBy("other-uid")}
+	otherSecret.Name = "other"
+	client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(deployment, service, otherSecret).Build()
+
+	orphaned, err := OrphanGeneratedResources(ctx, client, owner)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Deployments", "Services"}, orphaned.PluralKinds())
+	assert.Equal(t, []string{"rook-ceph-nfs-my-nfs-a"}, orphaned.OfPluralKind("Deployments"))
+
+	// the owner reference to the CR is removed and the CR is recorded
+	d := &appsv1.Deployment{}
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Namespace: "rook-ceph", Name: deployment.Name}, d))
+	assert.Empty(t, d.OwnerReferences)
+	assert.Equal(t, "CephNFS/my-nfs", d.Annotations[OrphanedFromAnnotation])
+
+	// the other owners are kept
+	s := &corev1.Service{}
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Namespace: "rook-ceph", Name: service.Name}, s))
+	require.Equal(t, 1, len(s.OwnerReferences))
+	assert.Equal(t, types.UID("other-uid"), s.OwnerReferences[0].UID)
+
+	// the resources of other owners are untouched
+	secret := &corev1.Secret{}
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Namespace: "rook-ceph", Name: "other"}, secret))
+	assert.Equal(t, 1, len(secret.OwnerReferences))
+	assert.Empty(t, secret.Annotations)
+
+	// nothing left to orphan
+	orphaned, err = OrphanGeneratedResources(ctx, client, owner)
+	require.NoError(t, err)
+	assert.True(t, orphaned.Empty())
+
+	assert.True(t, IsOrphanDeletionPolicy(cephv1.DeletionPolicyOrphan))
+	assert.False(t, IsOrphanDeletionPolicy(cephv1.DeletionPolicyDelete))
+	assert.False(t, IsOrphanDeletionPolicy(""))
+}
@@ -215,3 +215,19 @@ func TestIsDoNotReconcile(t *testing.T) {
 	b = IsDoNotReconcile(l)
 	assert.True(t, b)
 }
+
+func TestIsOSDReplacementRequested(t *testing.T) {
+	oldDeployment := &appsv1.Deployment{}
+	newDeployment := &appsv1.Deployment{}
+	assert.False(t, isOSDReplacementRequested(oldDeployment, newDeployment))
+
+	newDeployment.Annotations = map[string]string{ReplaceOSDAnnotation: "false"}
+	assert.False(t, isOSDReplacementRequested(oldDeployment, newDeployment))
+
+	newDeployment.Annotations[ReplaceOSDAnnotation] = "true"
+	assert.True(t, isOSDReplacementRequested(oldDeployment, newDeployment))
+
+	// the replacement was already requested
+	oldDeployment.Annotations = map[string]string{ReplaceOSDAnnotation: "true"}
+	assert.False(t, isOSDReplacementRequested(oldDeployment, newDeployment))
+}
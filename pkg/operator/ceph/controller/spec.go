@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/coreos/pkg/capnslog"
@@ -48,6 +49,7 @@ const (
 	volumeMountSubPath                    = "data"
 	crashVolumeName                       = "rook-ceph-crash"
 	daemonSocketDir                       = "/run/ceph"
+	writableDirVolumePrefix               = "writable-"
 	initialDelaySecondsNonOSDDaemon int32 = 10
 	initialDelaySecondsOSDDaemon    int32 = 45
 	logCollector                          = "log-collector"
@@ -335,29 +337,27 @@ func AdminFlags(cluster *client.ClusterInfo) []string {
 	)
 }
 
+// NetworkBindingFlags returns the flags binding the Ceph daemons to the IP families of the network spec
 func NetworkBindingFlags(cluster *client.ClusterInfo, spec *cephv1.ClusterSpec) []string {
 	var args []string
 
-	// As of Pacific, Ceph supports dual-stack, so setting IPv6 family without disabling IPv4 binding actually enables dual-stack
-	// This is likely not user's intent, so on Pacific let's make sure to disable IPv4 when IPv6 is selected
-	if !spec.Network.DualStack {
-		switch spec.Network.IPFamily {
-		case cephv1.IPv4:
-			args = append(args, config.NewFlag("ms-bind-ipv4", "true"))
-			args = append(args, config.NewFlag("ms-bind-ipv6", "false"))
+	families := spec.Network.GetIPFamilies()
+	switch len(families) {
+	case 1:
+		// As of Pacific, Ceph supports dual-stack, so setting IPv6 family without disabling IPv4 binding actually enables dual-stack
+		// This is likely not user's intent, so let's make sure to disable the other family
+		bindIPv6 := families[0] == cephv1.IPv6
+		args = append(args, config.NewFlag("ms-bind-ipv4", strconv.FormatBool(!bindIPv6)))
+		args = append(args, config.NewFlag("ms-bind-ipv6", strconv.FormatBool(bindIPv6)))
 
-		case cephv1.IPv6:
-			args = append(args, config.NewFlag("ms-bind-ipv4", "false"))
-			args = append(args, config.NewFlag("ms-bind-ipv6", "true"))
-		}
-	} else {
+	case 2:
 		if cluster.CephVersion.IsAtLeastPacific() {
 			args = append(args, config.NewFlag("ms-bind-ipv4", "true"))
 			args = append(args, config.NewFlag("ms-bind-ipv6", "true"))
 		} else {
 			logger.Info("dual-stack is only supported on ceph pacific")
 			// Still acknowledge IPv6, nothing to do for IPv4 since it will always be "on"
-			if spec.Network.IPFamily == cephv1.IPv6 {
+			if families[0] == cephv1.IPv6 {
 				args = append(args, config.NewFlag("ms-bind-ipv6", "true"))
 			}
 		}
@@ -582,6 +582,11 @@ func GenerateLivenessProbeExecDaemon(daemonType, daemonID string) *v1.Probe {
 	}
 }
 
+// DaemonSocketPath returns the path of the admin socket of a daemon
+func DaemonSocketPath(daemonType, daemonID string) string {
+	return getDaemonConfig(daemonType, daemonID).buildSocketPath()
+}
+
 func getDaemonConfig(daemonType, daemonID string) *daemonConfig {
 	return &daemonConfig{
 		daemonType: string(daemonType),
@@ -618,6 +623,75 @@ func PodSecurityContext() *v1.SecurityContext {
 	}
 }
 
+// ApplyReadOnlyRootFilesystem runs the containers of the pod of a Ceph daemon with a read-only root filesystem if
+// the restricted security profile is selected. The directories the daemon writes to, and the extra directories
+// given, are mounted from emptyDir volumes in each container that does not already mount a volume on them. The
+// log collector still needs to write the logrotate config and keeps a writable root filesystem.
+func ApplyReadOnlyRootFilesystem(security cephv1.SecuritySpec, podSpec *v1.PodSpec, extraWritableDirs ...string) {
+	if !security.ReadOnlyRootFilesystem() {
+		return
+	}
+
+	writableDirs := append([]string{"/tmp", daemonSocketDir, config.VarLogCephDir, config.VarLibCephCrashDir}, extraWritableDirs...)
+	usedVolumes := map[string]bool{}
+	applyToContainer := func(c *v1.Container) {
+		if c.Name == logCollector {
+			return
+		}
+		if c.SecurityContext == nil {
+			c.SecurityContext = &v1.SecurityContext{}
+		} else {
+			// the security context may be shared by several containers
+			c.SecurityContext = c.SecurityContext.DeepCopy()
+		}
+		readOnly := true
+		c.SecurityContext.ReadOnlyRootFilesystem = &readOnly
+
+		for _, dir := range writableDirs {
+			if containerMountsDir(c, dir) {
+				continue
+			}
+			volumeName := writableDirVolumeName(dir)
+			c.VolumeMounts = append(c.VolumeMounts, v1.VolumeMount{Name: volumeName, MountPath: dir})
+			usedVolumes[volumeName] = true
+		}
+	}
+	for i := range podSpec.InitContainers {
+		applyToContainer(&podSpec.InitContainers[i])
+	}
+	for i := range podSpec.Containers {
+		applyToContainer(&podSpec.Containers[i])
+	}
+
+	for _, dir := range writableDirs {
+		volumeName := writableDirVolumeName(dir)
+		if !usedVolumes[volumeName] {
+			continue
+		}
+		podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+			Name:         volumeName,
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		})
+		// the volume of a directory given twice is only added once
+		delete(usedVolumes, volumeName)
+	}
+}
+
+// writableDirVolumeName returns the name of the emptyDir volume mounted on a directory, e.g. "writable-run-ceph"
+func writableDirVolumeName(dir string) string {
+	return writableDirVolumePrefix + strings.ReplaceAll(strings.Trim(path.Clean(dir), "/"), "/", "-")
+}
+
+// containerMountsDir returns whether a volume is mounted on the directory in the container
+func containerMountsDir(c *v1.Container, dir string) bool {
+	for _, mount := range c.VolumeMounts {
+		if path.Clean(mount.MountPath) == path.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
 // LogCollectorContainer runs a cron job to rotate logs
 func LogCollectorContainer(daemonID, ns string, c cephv1.ClusterSpec) *v1.Container {
 	return &v1.Container{
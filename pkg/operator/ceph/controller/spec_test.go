@@ -230,6 +230,9 @@ func TestNetworkBindingFlags(t *testing.T) {
 		{"pacific-ipv4", args{cluster: &client.ClusterInfo{CephVersion: version.Pacific}, spec: &cephv1.ClusterSpec{Network: cephv1.NetworkSpec{IPFamily: cephv1.IPv4}}}, []string{ipv4FlagTrue, ipv6FlagFalse}},
 		{"pacific-ipv6", args{cluster: &client.ClusterInfo{CephVersion: version.Pacific}, spec: &cephv1.ClusterSpec{Network: cephv1.NetworkSpec{IPFamily: cephv1.IPv6}}}, []string{ipv4FlagFalse, ipv6FlagTrue}},
 		{"pacific-dualstack-supported", args{cluster: &client.ClusterInfo{CephVersion: version.Pacific}, spec: &cephv1.ClusterSpec{Network: cephv1.NetworkSpec{IPFamily: cephv1.IPv6, DualStack: true}}}, []string{ipv4FlagTrue, ipv6FlagTrue}},
+		{"pacific-ipfamilies-ipv6", args{cluster: &client.ClusterInfo{CephVersion: version.Pacific}, spec: &cephv1.ClusterSpec{Network: cephv1.NetworkSpec{IPFamilies: []cephv1.IPFamilyType{cephv1.IPv6}}}}, []string{ipv4FlagFalse, ipv6FlagTrue}},
+		{"pacific-ipfamilies-dualstack", args{cluster: &client.ClusterInfo{CephVersion: version.Pacific}, spec: &cephv1.ClusterSpec{Network: cephv1.NetworkSpec{IPFamilies: []cephv1.IPFamilyType{cephv1.IPv6, cephv1.IPv4}}}}, []string{ipv4FlagTrue, ipv6FlagTrue}},
+		{"octopus-ipfamilies-dualstack-unsupported", args{cluster: &client.ClusterInfo{CephVersion: version.Octopus}, spec: &cephv1.ClusterSpec{Network: cephv1.NetworkSpec{IPFamilies: []cephv1.IPFamilyType{cephv1.IPv6, cephv1.IPv4}}}}, []string{ipv6FlagTrue}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -242,6 +245,60 @@ func TestNetworkBindingFlags(t *testing.T) {
 	}
 }
 
+func TestApplyReadOnlyRootFilesystem(t *testing.T) {
+	securityContext := PodSecurityContext()
+	newPodSpec := func() v1.PodSpec {
+		return v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init", SecurityContext: securityContext}},
+			Containers: []v1.Container{
+				{Name: "mon", SecurityContext: securityContext, VolumeMounts: []v1.VolumeMount{{Name: "rook-ceph-log", MountPath: "/var/log/ceph/"}}},
+				{Name: logCollector},
+			},
+		}
+	}
+
+	// the default profile does not change the pod
+	podSpec := newPodSpec()
+	ApplyReadOnlyRootFilesystem(cephv1.SecuritySpec{}, &podSpec)
+	assert.Equal(t, newPodSpec(), podSpec)
+
+	podSpec = newPodSpec()
+	ApplyReadOnlyRootFilesystem(cephv1.SecuritySpec{Profile: cephv1.SecurityProfileRestricted}, &podSpec, "/run/lvm")
+	for _, c := range []v1.Container{podSpec.InitContainers[0], podSpec.Containers[0]} {
+		assert.True(t, *c.SecurityContext.ReadOnlyRootFilesystem, c.Name)
+		assert.False(t, *c.SecurityContext.Privileged, c.Name)
+	}
+	// the shared security context is not modified
+	assert.Nil(t, securityContext.ReadOnlyRootFilesystem)
+	assert.Nil(t, podSpec.Containers[1].SecurityContext)
+	assert.Empty(t, podSpec.Containers[1].VolumeMounts)
+
+	mountPaths := func(c v1.Container) map[string]string {
+		paths := map[string]string{}
+		for _, m := range c.VolumeMounts {
+			paths[m.MountPath] = m.Name
+		}
+		return paths
+	}
+	assert.Equal(t, map[string]string{
+		"/tmp":                "writable-tmp",
+		"/run/ceph":           "writable-run-ceph",
+		"/var/log/ceph":       "writable-var-log-ceph",
+		"/var/lib/ceph/crash": "writable-var-lib-ceph-crash",
+		"/run/lvm":            "writable-run-lvm",
+	}, mountPaths(podSpec.InitContainers[0]))
+	// the log dir mounted from the host is kept
+	assert.Equal(t, "rook-ceph-log", mountPaths(podSpec.Containers[0])["/var/log/ceph/"])
+	assert.NotContains(t, mountPaths(podSpec.Containers[0]), "/var/log/ceph")
+
+	volumes := []string{}
+	for _, v := range podSpec.Volumes {
+		assert.NotNil(t, v.EmptyDir)
+		volumes = append(volumes, v.Name)
+	}
+	assert.Equal(t, []string{"writable-tmp", "writable-run-ceph", "writable-var-log-ceph", "writable-var-lib-ceph-crash", "writable-run-lvm"}, volumes)
+}
+
 func TestExtractMgrIP(t *testing.T) {
 	activeMgrRaw := "172.17.0.12:6801/2535462469"
 	ip := extractMgrIP(activeMgrRaw)
@@ -59,6 +59,20 @@ func GetImageVersion(cephCluster cephv1.CephCluster) (*cephver.CephVersion, erro
 	return nil, errors.New("attempt to determine ceph version for the current cluster image timed out")
 }
 
+// RemoveUnvalidatedHotfix removes the hotfix from the spec of the cluster until the cluster controller has
+// validated the version of the hotfix image, so that the daemons keep the image of the cluster meanwhile
+func RemoveUnvalidatedHotfix(cephCluster *cephv1.CephCluster) {
+	hotfix := cephCluster.Spec.CephVersion.Hotfix
+	if hotfix == nil {
+		return
+	}
+	if cephCluster.Status.HotfixVersion != nil && cephCluster.Status.HotfixVersion.Image == hotfix.Image {
+		return
+	}
+	logger.Infof("the version of hotfix image %q is not validated yet, the daemons keep the image of the cluster", hotfix.Image)
+	cephCluster.Spec.CephVersion.Hotfix = nil
+}
+
 // DetectCephVersion loads the ceph version from the image and checks that it meets the version requirements to
 // run in the cluster
 func DetectCephVersion(rookImage, namespace, jobName string, ownerInfo *k8sutil.OwnerInfo, clientset kubernetes.Interface, cephClusterSpec *cephv1.ClusterSpec) (*version.CephVersion, error) {
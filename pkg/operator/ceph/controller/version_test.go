@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveUnvalidatedHotfix(t *testing.T) {
+	hotfix := &cephv1.CephHotfixSpec{Image: "quay.io/ceph/ceph:v16.2.6-hotfix", Daemons: []cephv1.HotfixDaemonType{cephv1.HotfixDaemonRgw}}
+	cephCluster := cephv1.CephCluster{}
+
+	// the hotfix is removed until its version is validated
+	cephCluster.Spec.CephVersion.Hotfix = hotfix
+	RemoveUnvalidatedHotfix(&cephCluster)
+	assert.Nil(t, cephCluster.Spec.CephVersion.Hotfix)
+
+	// the hotfix is removed if another image was validated
+	cephCluster.Spec.CephVersion.Hotfix = hotfix
+	cephCluster.Status.HotfixVersion = &cephv1.ClusterVersion{Image: "quay.io/ceph/ceph:v16.2.5-hotfix", Version: "16.2.5-0"}
+	RemoveUnvalidatedHotfix(&cephCluster)
+	assert.Nil(t, cephCluster.Spec.CephVersion.Hotfix)
+
+	// the validated hotfix is kept
+	cephCluster.Spec.CephVersion.Hotfix = hotfix
+	cephCluster.Status.HotfixVersion = &cephv1.ClusterVersion{Image: hotfix.Image, Version: "16.2.6-1"}
+	RemoveUnvalidatedHotfix(&cephCluster)
+	assert.Equal(t, hotfix, cephCluster.Spec.CephVersion.Hotfix)
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/rook/rook/pkg/apis/rook.io/v1alpha2"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/agent"
+	"github.com/rook/rook/pkg/operator/ceph/authexport"
+	"github.com/rook/rook/pkg/operator/ceph/benchmark"
 	"github.com/rook/rook/pkg/operator/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/cluster"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/crash"
@@ -33,23 +35,34 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/machinedisruption"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/machinelabel"
+	"github.com/rook/rook/pkg/operator/ceph/disruption/nodemaintenance"
 	"github.com/rook/rook/pkg/operator/ceph/file"
 	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
+	"github.com/rook/rook/pkg/operator/ceph/file/staticvolume"
+	"github.com/rook/rook/pkg/operator/ceph/file/subvolumegroup"
 	"github.com/rook/rook/pkg/operator/ceph/nfs"
 	"github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/ceph/object/bucket"
+	"github.com/rook/rook/pkg/operator/ceph/object/notification"
 	"github.com/rook/rook/pkg/operator/ceph/object/realm"
+	"github.com/rook/rook/pkg/operator/ceph/object/topic"
 	objectuser "github.com/rook/rook/pkg/operator/ceph/object/user"
 	"github.com/rook/rook/pkg/operator/ceph/object/zone"
 	"github.com/rook/rook/pkg/operator/ceph/object/zonegroup"
 	"github.com/rook/rook/pkg/operator/ceph/pool"
+	"github.com/rook/rook/pkg/operator/ceph/poolmigration"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
 	mapiv1 "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
 	healthchecking "github.com/openshift/machine-api-operator/pkg/apis/healthchecking/v1alpha1"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -65,6 +78,7 @@ var (
 		healthchecking.AddToScheme,
 		cephv1.AddToScheme,
 		v1alpha2.AddToScheme,
+		bktv1alpha1.AddToScheme,
 	}
 )
 
@@ -80,6 +94,7 @@ var (
 // AddToManagerFuncsMaintenance is a list of functions to add all Controllers to the Manager (entrypoint for controller)
 var AddToManagerFuncsMaintenance = []func(manager.Manager, *controllerconfig.Context) error{
 	clusterdisruption.Add,
+	nodemaintenance.Add,
 }
 
 // MachineDisruptionBudgetAddToManagerFuncs is a list of fencing related functions to add all Controllers to the Manager (entrypoint for controller)
@@ -96,12 +111,19 @@ var AddToManagerFuncs = []func(manager.Manager, *clusterd.Context, context.Conte
 	realm.Add,
 	zonegroup.Add,
 	zone.Add,
+	topic.Add,
+	notification.Add,
 	object.Add,
 	file.Add,
 	nfs.Add,
 	rbd.Add,
 	client.Add,
 	mirror.Add,
+	subvolumegroup.Add,
+	staticvolume.Add,
+	benchmark.Add,
+	poolmigration.Add,
+	authexport.Add,
 	Add,
 	csi.Add,
 	agent.Add,
@@ -169,10 +191,15 @@ func (o *Operator) startCRDManager(context context.Context, mgrErrorCh chan erro
 		Namespace:      o.config.NamespaceToWatch,
 		Scheme:         scheme,
 		CertDir:        certDir,
+		// These objects are only read occasionally, caching them would start informers
+		// over the whole cluster and keep all the objects in memory.
+		ClientDisableCacheFor: []crclient.Object{&corev1.Service{}, &storagev1.StorageClass{}},
 	}
 
 	logger.Info("setting up the controller-runtime manager")
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOpts)
+	// the manager has its own rate limiter so that the controllers and the orchestration of the clusters do not
+	// wait for each other
+	mgr, err := ctrl.NewManager(k8sutil.CopyConfigWithOwnRateLimiter(o.context.KubeConfig), mgrOpts)
 	if err != nil {
 		mgrErrorCh <- errors.Wrap(err, "failed to set up overall controller-runtime manager")
 		return
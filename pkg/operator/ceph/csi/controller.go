@@ -53,6 +53,7 @@ type ReconcileCSI struct {
 // Add creates a new Ceph CSI Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
@@ -186,10 +187,38 @@ func (r *ReconcileCSI) reconcile(request reconcile.Request) (reconcile.Result, e
 		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to create pool ID mapping config map")
 	}
 
-	err = r.validateAndConfigureDrivers(serverVersion, ownerInfo)
+	objectOnly, err := r.objectOnlyClusters(cephClusters.Items)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to check if the clusters only serve objects")
+	}
+
+	err = r.validateAndConfigureDrivers(serverVersion, ownerInfo, objectOnly)
 	if err != nil {
 		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed configure ceph csi")
 	}
 
 	return reconcile.Result{}, nil
 }
+
+// objectOnlyClusters returns whether all the clusters only serve objects, so the rbd and cephfs drivers
+// are not needed. The drivers are kept for a cluster with block pools or filesystems, whatever its profile.
+func (r *ReconcileCSI) objectOnlyClusters(clusters []cephv1.CephCluster) (bool, error) {
+	for _, cluster := range clusters {
+		if cluster.Spec.External.Enable || !cluster.Spec.IsObjectOnly() {
+			return false, nil
+		}
+		pools := &cephv1.CephBlockPoolList{}
+		if err := r.client.List(r.opManagerContext, pools, client.InNamespace(cluster.Namespace)); err != nil {
+			return false, errors.Wrapf(err, "failed to list the block pools in namespace %q", cluster.Namespace)
+		}
+		filesystems := &cephv1.CephFilesystemList{}
+		if err := r.client.List(r.opManagerContext, filesystems, client.InNamespace(cluster.Namespace)); err != nil {
+			return false, errors.Wrapf(err, "failed to list the filesystems in namespace %q", cluster.Namespace)
+		}
+		if len(pools.Items) > 0 || len(filesystems.Items) > 0 {
+			logger.Infof("keeping the rbd and cephfs drivers for the block pools and filesystems of cluster %q with profile %q", cluster.Namespace, cluster.Spec.Profile)
+			return false, nil
+		}
+	}
+	return len(clusters) > 0, nil
+}
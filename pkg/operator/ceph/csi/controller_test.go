@@ -156,3 +156,36 @@ func TestCephCSIController(t *testing.T) {
 		assert.Equal(t, 2, len(ds.Items), ds)
 	})
 }
+
+func TestObjectOnlyClusters(t *testing.T) {
+	archive := cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "archive", Namespace: "archive"},
+		Spec:       cephv1.ClusterSpec{Profile: cephv1.ClusterProfileArchive},
+	}
+	pool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"}}
+	r := &ReconcileCSI{
+		client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(pool).Build(),
+		opManagerContext: context.TODO(),
+	}
+	objectOnly := func(clusters ...cephv1.CephCluster) bool {
+		objectOnly, err := r.objectOnlyClusters(clusters)
+		assert.NoError(t, err)
+		return objectOnly
+	}
+	assert.False(t, objectOnly())
+	assert.True(t, objectOnly(archive, archive))
+	assert.False(t, objectOnly(archive, cephv1.CephCluster{}))
+
+	// the drivers of an external cluster are always needed
+	external := archive
+	external.Spec.External.Enable = true
+	assert.False(t, objectOnly(external))
+
+	// the drivers are kept for the block pools and filesystems of an archive cluster
+	withPool := archive
+	withPool.Namespace = "rook-ceph"
+	assert.False(t, objectOnly(withPool))
+	filesystem := &cephv1.CephFilesystem{ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "archive"}}
+	assert.NoError(t, r.client.Create(context.TODO(), filesystem))
+	assert.False(t, objectOnly(archive))
+}
@@ -24,7 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/version"
 )
 
-func (r *ReconcileCSI) validateAndConfigureDrivers(serverVersion *version.Info, ownerInfo *k8sutil.OwnerInfo) error {
+func (r *ReconcileCSI) validateAndConfigureDrivers(serverVersion *version.Info, ownerInfo *k8sutil.OwnerInfo, objectOnly bool) error {
 	var (
 		v   *CephCSIVersion
 		err error
@@ -34,6 +34,12 @@ func (r *ReconcileCSI) validateAndConfigureDrivers(serverVersion *version.Info,
 		return errors.Wrapf(err, "failed to configure CSI parameters")
 	}
 
+	if objectOnly && (EnableRBD || EnableCephFS) {
+		logger.Info("all the ceph clusters only serve objects, not running the rbd and cephfs csi drivers")
+		EnableRBD = false
+		EnableCephFS = false
+	}
+
 	if err = validateCSIParam(); err != nil {
 		return errors.Wrapf(err, "failed to validate CSI parameters")
 	}
@@ -65,6 +65,13 @@ func predicateController() predicate.Funcs {
 				}
 			}
 
+			// the rbd and cephfs drivers are not needed when all the clusters only serve objects
+			if old, ok := e.ObjectOld.(*cephv1.CephCluster); ok {
+				if new, ok := e.ObjectNew.(*cephv1.CephCluster); ok {
+					return old.Spec.IsObjectOnly() != new.Spec.IsObjectOnly()
+				}
+			}
+
 			return false
 		},
 
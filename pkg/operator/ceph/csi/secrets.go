@@ -203,3 +203,30 @@ func CreateCSISecrets(context *clusterd.Context, clusterInfo *client.ClusterInfo
 
 	return nil
 }
+
+// RepairCSISecrets replaces the keys of the CSI secrets that differ from the keys of the Ceph users
+func RepairCSISecrets(context *clusterd.Context, clusterInfo *client.ClusterInfo, cephKeys map[string]string) ([]keyring.Repair, error) {
+	k := keyring.GetSecretStore(context, clusterInfo, clusterInfo.OwnerInfo)
+	csiSecrets := []struct {
+		secretName string
+		field      string
+		user       string
+	}{
+		{CsiRBDProvisionerSecret, "userKey", csiKeyringRBDProvisionerUsername},
+		{CsiRBDNodeSecret, "userKey", csiKeyringRBDNodeUsername},
+		{CsiCephFSProvisionerSecret, "adminKey", csiKeyringCephFSProvisionerUsername},
+		{CsiCephFSNodeSecret, "adminKey", csiKeyringCephFSNodeUsername},
+	}
+
+	repairs := []keyring.Repair{}
+	for _, s := range csiSecrets {
+		repair, err := k.RepairKeySecret(s.secretName, s.field, s.user, cephKeys)
+		if err != nil {
+			return repairs, errors.Wrapf(err, "failed to repair csi secret %q", s.secretName)
+		}
+		if repair != nil {
+			repairs = append(repairs, *repair)
+		}
+	}
+	return repairs, nil
+}
@@ -56,6 +56,8 @@ type Param struct {
 	EnableRBDSnapshotter           bool
 	EnableCephFSSnapshotter        bool
 	EnableVolumeReplicationSideCar bool
+	EnableReadAffinity             bool
+	CrushLocationLabels            string
 	LogLevel                       uint8
 	CephFSGRPCMetricsPort          uint16
 	CephFSLivenessMetricsPort      uint16
@@ -172,6 +174,11 @@ const (
 	// default provisioner replicas
 	defaultProvisionerReplicas int32 = 2
 
+	// the node labels of the crush location of the rbd nodeplugin, the same as the topology labels of the OSDs
+	defaultCrushLocationLabels = "topology.kubernetes.io/region,topology.kubernetes.io/zone," +
+		"topology.rook.io/datacenter,topology.rook.io/room,topology.rook.io/pod,topology.rook.io/pdu," +
+		"topology.rook.io/row,topology.rook.io/rack,topology.rook.io/chassis"
+
 	// update strategy
 	rollingUpdate = "RollingUpdate"
 	onDelete      = "OnDelete"
@@ -307,6 +314,8 @@ func (r *ReconcileCSI) startDrivers(ver *version.Info, ownerInfo *k8sutil.OwnerI
 		tp.EnableVolumeReplicationSideCar = true
 	}
 
+	tp.EnableReadAffinity, tp.CrushLocationLabels = getReadAffinityOptions(r.opConfig.Parameters, v)
+
 	if strings.EqualFold(k8sutil.GetValue(r.opConfig.Parameters, "CSI_CEPHFS_PLUGIN_UPDATE_STRATEGY", rollingUpdate), onDelete) {
 		tp.CephFSPluginUpdateStrategy = onDelete
 	} else {
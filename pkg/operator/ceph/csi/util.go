@@ -189,3 +189,19 @@ func GetPodAntiAffinity(key, value string) corev1.PodAntiAffinity {
 		},
 	}
 }
+
+// getReadAffinityOptions returns whether the rbd nodeplugin reads from the OSDs closest to the node
+// and the node labels forming the crush location of the node. The read affinity is disabled if the
+// ceph csi version is known to not support it.
+func getReadAffinityOptions(opConfig map[string]string, v *CephCSIVersion) (bool, string) {
+	if !strings.EqualFold(k8sutil.GetValue(opConfig, "CSI_ENABLE_READ_AFFINITY", "false"), "true") {
+		return false, ""
+	}
+	if v != nil && !v.SupportsReadAffinity() {
+		logger.Warningf("read affinity requires ceph csi %s or newer, not enabling it with ceph csi %s", releasev3100.String(), v.String())
+		return false, ""
+	}
+	labels := k8sutil.GetValue(opConfig, "CSI_CRUSH_LOCATION_LABELS", defaultCrushLocationLabels)
+	logger.Infof("enabling read affinity of the rbd nodeplugin with the crush location labels %q", labels)
+	return true, labels
+}
@@ -80,3 +80,41 @@ func TestGetPortFromConfig(t *testing.T) {
 	err = os.Unsetenv(key)
 	assert.Nil(t, err)
 }
+
+func TestGetReadAffinityOptions(t *testing.T) {
+	// disabled by default
+	enabled, labels := getReadAffinityOptions(map[string]string{}, nil)
+	assert.False(t, enabled)
+	assert.Empty(t, labels)
+
+	// enabled with the default labels
+	opConfig := map[string]string{"CSI_ENABLE_READ_AFFINITY": "true"}
+	enabled, labels = getReadAffinityOptions(opConfig, &CephCSIVersion{3, 10, 0})
+	assert.True(t, enabled)
+	assert.Equal(t, defaultCrushLocationLabels, labels)
+
+	// the version is unknown when unsupported versions are allowed
+	opConfig["CSI_CRUSH_LOCATION_LABELS"] = "topology.kubernetes.io/zone"
+	enabled, labels = getReadAffinityOptions(opConfig, nil)
+	assert.True(t, enabled)
+	assert.Equal(t, "topology.kubernetes.io/zone", labels)
+
+	// not supported by the ceph csi version
+	enabled, labels = getReadAffinityOptions(opConfig, &releasev340)
+	assert.False(t, enabled)
+	assert.Empty(t, labels)
+}
+
+func TestReadAffinityTemplate(t *testing.T) {
+	tp := templateParam{
+		Param:     CSIParam,
+		Namespace: "foo",
+	}
+	tp.EnableReadAffinity = true
+	tp.CrushLocationLabels = "topology.kubernetes.io/zone"
+	ds, err := templateToDaemonSet("test-ds", RBDPluginTemplatePath, tp)
+	assert.NoError(t, err)
+	args := ds.Spec.Template.Spec.Containers[1].Args
+	assert.Contains(t, args, "--enable-read-affinity=true")
+	assert.Contains(t, args, "--crush-location-labels=topology.kubernetes.io/zone")
+}
@@ -35,6 +35,8 @@ var (
 		releasev330,
 		releasev340,
 	}
+	// the first release passing the crush location of the node to the rbd nodeplugin
+	releasev3100 = CephCSIVersion{3, 10, 0}
 	// for parsing the output of `cephcsi`
 	versionCSIPattern = regexp.MustCompile(`v(\d+)\.(\d+)\.(\d+)`)
 )
@@ -73,6 +75,11 @@ func (v *CephCSIVersion) Supported() bool {
 	return false
 }
 
+// SupportsReadAffinity checks if the detected version can read from the closest OSDs
+func (v *CephCSIVersion) SupportsReadAffinity() bool {
+	return v.isAtLeast(&releasev3100)
+}
+
 func (v *CephCSIVersion) isAtLeast(version *CephCSIVersion) bool {
 	if v.Major > version.Major {
 		return true
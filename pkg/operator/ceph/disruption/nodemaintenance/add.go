@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemaintenance
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Add adds a new Controller to the Manager based on nodemaintenance.ReconcileNodeMaintenance and registers the relevant watches and handlers.
+func Add(mgr manager.Manager, context *controllerconfig.Context) error {
+	reconcileNodeMaintenance := &ReconcileNodeMaintenance{
+		client:  mgr.GetClient(),
+		scheme:  mgr.GetScheme(),
+		context: context,
+	}
+
+	reconciler := reconcile.Reconciler(reconcileNodeMaintenance)
+	// create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: reconciler})
+	if err != nil {
+		return errors.Wrapf(err, "could not create controller %q", controllerName)
+	}
+
+	// Watch for the changes of the node maintenance settings of the CephClusters
+	err = c.Watch(&source.Kind{Type: &cephv1.CephCluster{}}, &handler.EnqueueRequestForObject{}, predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, ok := e.ObjectOld.(*cephv1.CephCluster)
+			if !ok {
+				return false
+			}
+			newCluster, ok := e.ObjectNew.(*cephv1.CephCluster)
+			if !ok {
+				return false
+			}
+			return oldCluster.Spec.DisruptionManagement.ManageNodeMaintenance != newCluster.Spec.DisruptionManagement.ManageNodeMaintenance
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not watch cephclusters")
+	}
+
+	// Watch for the nodes entering or leaving maintenance and enqueue all the CephClusters, since the OSDs of
+	// several clusters may run on a node
+	err = c.Watch(&source.Kind{Type: &corev1.Node{}}, handler.EnqueueRequestsFromMapFunc(
+		handler.MapFunc(func(obj client.Object) []reconcile.Request {
+			cephClusters := &cephv1.CephClusterList{}
+			if err := reconcileNodeMaintenance.client.List(context.OpManagerContext, cephClusters); err != nil {
+				logger.Errorf("failed to list the cephclusters to reconcile the maintenance of node %q. %v", obj.GetName(), err)
+				return []reconcile.Request{}
+			}
+			requests := []reconcile.Request{}
+			for _, cephCluster := range cephClusters.Items {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cephCluster.Namespace, Name: cephCluster.Name}})
+			}
+			return requests
+		}),
+	), predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			node, ok := e.Object.(*corev1.Node)
+			return ok && isNodeInMaintenance(node)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			return isNodeInMaintenance(oldNode) != isNodeInMaintenance(newNode)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return true
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not watch nodes")
+	}
+
+	// Watch for the osd pods moving to a node, so the OSDs started on a node in maintenance are set noout
+	return c.Watch(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(
+		handler.MapFunc(func(obj client.Object) []reconcile.Request {
+			labels := obj.GetLabels()
+			if labels[k8sutil.AppAttr] != osd.AppName || labels[k8sutil.ClusterAttr] == "" {
+				return []reconcile.Request{}
+			}
+			// the name of the cluster is not known from the pod, the reconcile gets the CephCluster of the namespace
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace()}}}
+		}),
+	), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			newPod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			return oldPod.Spec.NodeName != newPod.Spec.NodeName
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	})
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemaintenance
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	controllerName = "nodemaintenance-controller"
+	// MaintenanceAnnotation is the annotation of a node announcing its maintenance, for the maintenances that
+	// don't cordon the node
+	MaintenanceAnnotation = "ceph.rook.io/maintenance"
+	nooutFlag             = "noout"
+)
+
+var (
+	logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+	hostLocationLabel = fmt.Sprintf(osd.TopologyLocationLabel, "host")
+)
+
+// ReconcileNodeMaintenance sets the noout flag on the CRUSH hosts of the OSDs of the nodes in maintenance
+type ReconcileNodeMaintenance struct {
+	scheme  *runtime.Scheme
+	client  client.Client
+	context *controllerconfig.Context
+}
+
+// Reconcile is the implementation of reconcile function for ReconcileNodeMaintenance
+// which ensures that the OSDs of the nodes in maintenance are set noout
+// The Controller will requeue the Request to be processed again if an error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileNodeMaintenance) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// wrapping reconcile because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	result, err := r.reconcile(request)
+	if err != nil {
+		logger.Error(err)
+	}
+	return result, err
+}
+
+func (r *ReconcileNodeMaintenance) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	logger.Debugf("reconciling the node maintenance of %q", request.NamespacedName)
+
+	// the osd pods only know the namespace of their cluster
+	cephClusters := &cephv1.CephClusterList{}
+	if err := r.client.List(r.context.OpManagerContext, cephClusters, client.InNamespace(request.Namespace)); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "could not get cephclusters in namespace %q", request.Namespace)
+	}
+	if len(cephClusters.Items) == 0 {
+		logger.Debugf("no cephcluster in namespace %q", request.Namespace)
+		return reconcile.Result{}, nil
+	}
+	cephCluster := &cephClusters.Items[0]
+	if cephCluster.Spec.External.Enable || !cephCluster.GetDeletionTimestamp().IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	previous := []cephv1.NodeMaintenance{}
+	if cephCluster.Status.NodeMaintenance != nil {
+		previous = cephCluster.Status.NodeMaintenance.Nodes
+	}
+	if !cephCluster.Spec.DisruptionManagement.ManageNodeMaintenance && len(previous) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	nodes := []cephv1.NodeMaintenance{}
+	if cephCluster.Spec.DisruptionManagement.ManageNodeMaintenance {
+		var err error
+		nodes, err = r.nodesInMaintenance(cephCluster.Namespace, previous)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	clusterInfo := cephclient.NewClusterInfo(cephCluster.Namespace, cephCluster.Name)
+	clusterInfo.CephCred.Username = cephclient.AdminUsername
+	clusterInfo.Context = r.context.OpManagerContext
+	if err := r.setNoout(clusterInfo, hostsOf(previous), hostsOf(nodes)); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	status := &cephv1.NodeMaintenanceStatus{Nodes: nodes}
+	if len(nodes) == 0 {
+		status = nil
+	}
+	if reflect.DeepEqual(cephCluster.Status.NodeMaintenance, status) {
+		return reconcile.Result{}, nil
+	}
+	cephCluster.Status.NodeMaintenance = status
+	if err := reporting.UpdateStatus(r.client, cephCluster); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to update the node maintenance status")
+	}
+	return reconcile.Result{}, nil
+}
+
+// nodesInMaintenance returns the nodes in maintenance with the CRUSH hosts of their OSDs. The hosts are found from
+// the OSD pods running on the node and the OSD deployments scheduled on the node, since the pods are evicted when
+// the node is drained. The hosts of a node still in maintenance are kept once the OSDs moved to other nodes.
+func (r *ReconcileNodeMaintenance) nodesInMaintenance(namespace string, previous []cephv1.NodeMaintenance) ([]cephv1.NodeMaintenance, error) {
+	osdLabels := client.MatchingLabels{k8sutil.AppAttr: osd.AppName}
+	hostsByNode := map[string]sets.String{}
+	addHost := func(node, host string) {
+		if node == "" || host == "" {
+			return
+		}
+		if _, ok := hostsByNode[node]; !ok {
+			hostsByNode[node] = sets.NewString()
+		}
+		hostsByNode[node].Insert(host)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.client.List(r.context.OpManagerContext, pods, client.InNamespace(namespace), osdLabels); err != nil {
+		return nil, errors.Wrap(err, "failed to list the osd pods")
+	}
+	for _, pod := range pods.Items {
+		addHost(pod.Spec.NodeName, pod.Labels[hostLocationLabel])
+	}
+
+	for _, previousNode := range previous {
+		for _, host := range previousNode.Hosts {
+			addHost(previousNode.Node, host)
+		}
+	}
+
+	// the deployments of the osds on nodes select the node by its hostname label
+	hostnames := map[string]sets.String{}
+	deployments := &appsv1.DeploymentList{}
+	if err := r.client.List(r.context.OpManagerContext, deployments, client.InNamespace(namespace), osdLabels); err != nil {
+		return nil, errors.Wrap(err, "failed to list the osd deployments")
+	}
+	for _, d := range deployments.Items {
+		hostname := d.Spec.Template.Spec.NodeSelector[corev1.LabelHostname]
+		if hostname == "" {
+			continue
+		}
+		if _, ok := hostnames[hostname]; !ok {
+			hostnames[hostname] = sets.NewString()
+		}
+		hostnames[hostname].Insert(d.Spec.Template.Labels[hostLocationLabel])
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.client.List(r.context.OpManagerContext, nodeList); err != nil {
+		return nil, errors.Wrap(err, "failed to list the nodes")
+	}
+	inMaintenance := map[string]bool{}
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		for _, host := range hostnames[node.Labels[corev1.LabelHostname]].List() {
+			addHost(node.Name, host)
+		}
+		inMaintenance[node.Name] = isNodeInMaintenance(node)
+	}
+
+	nodes := []cephv1.NodeMaintenance{}
+	nodeNames := []string{}
+	for nodeName := range hostsByNode {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		// the noout flag of a deleted node is removed
+		if !inMaintenance[nodeName] {
+			continue
+		}
+		nodes = append(nodes, cephv1.NodeMaintenance{Node: nodeName, Hosts: hostsByNode[nodeName].List()})
+	}
+	return nodes, nil
+}
+
+// setNoout sets the noout flag on the hosts of the nodes in maintenance, and removes it from the hosts the
+// operator set noout that are not in maintenance anymore
+func (r *ReconcileNodeMaintenance) setNoout(clusterInfo *cephclient.ClusterInfo, previousHosts, hosts sets.String) error {
+	if previousHosts.Equal(hosts) {
+		return nil
+	}
+	osdDump, err := cephclient.GetOSDDump(r.context.ClusterdContext, clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the osd dump")
+	}
+
+	for _, host := range hosts.Difference(previousHosts).List() {
+		logger.Infof("setting noout on the osds of crush host %q in maintenance", host)
+		if _, err := osdDump.UpdateFlagOnCrushUnit(r.context.ClusterdContext, clusterInfo, true, host, nooutFlag); err != nil {
+			return errors.Wrapf(err, "failed to set noout on crush host %q", host)
+		}
+	}
+	for _, host := range previousHosts.Difference(hosts).List() {
+		logger.Infof("removing noout from the osds of crush host %q back from maintenance", host)
+		if _, err := osdDump.UpdateFlagOnCrushUnit(r.context.ClusterdContext, clusterInfo, false, host, nooutFlag); err != nil {
+			return errors.Wrapf(err, "failed to remove noout from crush host %q", host)
+		}
+	}
+	return nil
+}
+
+func hostsOf(nodes []cephv1.NodeMaintenance) sets.String {
+	hosts := sets.NewString()
+	for _, node := range nodes {
+		hosts.Insert(node.Hosts...)
+	}
+	return hosts
+}
+
+// isNodeInMaintenance returns whether the node is cordoned or annotated for maintenance
+func isNodeInMaintenance(node *corev1.Node) bool {
+	return node.Spec.Unschedulable || node.Annotations[MaintenanceAnnotation] == "true"
+}
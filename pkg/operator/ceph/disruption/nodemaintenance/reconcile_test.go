@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemaintenance
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIsNodeInMaintenance(t *testing.T) {
+	node := &corev1.Node{}
+	assert.False(t, isNodeInMaintenance(node))
+	node.Spec.Unschedulable = true
+	assert.True(t, isNodeInMaintenance(node))
+	node.Spec.Unschedulable = false
+	node.Annotations = map[string]string{MaintenanceAnnotation: "true"}
+	assert.True(t, isNodeInMaintenance(node))
+	node.Annotations[MaintenanceAnnotation] = "false"
+	assert.False(t, isNodeInMaintenance(node))
+}
+
+func TestReconcileNodeMaintenance(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	s := scheme.Scheme
+	require.NoError(t, appsv1.AddToScheme(s))
+	require.NoError(t, corev1.AddToScheme(s))
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace},
+		Spec:       cephv1.ClusterSpec{DisruptionManagement: cephv1.DisruptionManagementSpec{ManageNodeMaintenance: true}},
+	}
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelHostname: name}}}
+	}
+	// the osd pod of node1 was evicted, its deployment still selects node1
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0", Namespace: namespace, Labels: map[string]string{k8sutil.AppAttr: osd.AppName}}}
+	deployment.Spec.Template.Labels = map[string]string{k8sutil.AppAttr: osd.AppName, hostLocationLabel: "node1"}
+	deployment.Spec.Template.Spec.NodeSelector = map[string]string{corev1.LabelHostname: "node1"}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-1-abc", Namespace: namespace, Labels: map[string]string{k8sutil.AppAttr: osd.AppName, hostLocationLabel: "node2"}},
+		Spec:       corev1.PodSpec{NodeName: "node2"},
+	}
+	node1 := node("node1")
+	node1.Spec.Unschedulable = true
+	client := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cephCluster, node1, node("node2"), deployment, pod).Build()
+
+	flags := map[string][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "osd" && args[1] == "dump":
+				dump, _ := json.Marshal(map[string]interface{}{"crush_node_flags": flags})
+				return string(dump), nil
+			case args[0] == "osd" && args[1] == "set-group":
+				flags[args[3]] = []string{args[2]}
+				return "", nil
+			case args[0] == "osd" && args[1] == "unset-group":
+				delete(flags, args[3])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	r := &ReconcileNodeMaintenance{
+		client:  client,
+		scheme:  s,
+		context: &controllerconfig.Context{ClusterdContext: &clusterd.Context{Executor: executor}, OpManagerContext: ctx},
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace}}
+	getStatus := func() *cephv1.NodeMaintenanceStatus {
+		c := &cephv1.CephCluster{}
+		require.NoError(t, client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "my-cluster"}, c))
+		return c.Status.NodeMaintenance
+	}
+	updateNode := func(name string, update func(*corev1.Node)) {
+		n := &corev1.Node{}
+		require.NoError(t, client.Get(ctx, types.NamespacedName{Name: name}, n))
+		update(n)
+		require.NoError(t, client.Update(ctx, n))
+	}
+
+	// the host of the cordoned node is set noout
+	_, err := r.reconcile(request)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"node1": {"noout"}}, flags)
+	assert.Equal(t, &cephv1.NodeMaintenanceStatus{Nodes: []cephv1.NodeMaintenance{{Node: "node1", Hosts: []string{"node1"}}}}, getStatus())
+
+	// the node annotated for maintenance is set noout
+	updateNode("node2", func(n *corev1.Node) { n.Annotations = map[string]string{MaintenanceAnnotation: "true"} })
+	_, err = r.reconcile(request)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"node1": {"noout"}, "node2": {"noout"}}, flags)
+	assert.Equal(t, 2, len(getStatus().Nodes))
+
+	// the uncordoned node is not noout anymore
+	updateNode("node1", func(n *corev1.Node) { n.Spec.Unschedulable = false })
+	_, err = r.reconcile(request)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"node2": {"noout"}}, flags)
+	assert.Equal(t, &cephv1.NodeMaintenanceStatus{Nodes: []cephv1.NodeMaintenance{{Node: "node2", Hosts: []string{"node2"}}}}, getStatus())
+
+	// the flags set by the operator are removed when the feature is disabled
+	c := &cephv1.CephCluster{}
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "my-cluster"}, c))
+	c.Spec.DisruptionManagement.ManageNodeMaintenance = false
+	require.NoError(t, client.Update(ctx, c))
+	_, err = r.reconcile(request)
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+	assert.Nil(t, getStatus())
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -78,18 +79,21 @@ type ReconcileCephFilesystem struct {
 	clusterInfo      *cephclient.ClusterInfo
 	fsContexts       map[string]*fsHealth
 	opManagerContext context.Context
+	recorder         *k8sutil.EventReporter
 	opConfig         opcontroller.OperatorConfig
 }
 
 type fsHealth struct {
-	internalCtx    context.Context
-	internalCancel context.CancelFunc
-	started        bool
+	internalCtx     context.Context
+	internalCancel  context.CancelFunc
+	started         bool
+	sessionsStarted bool
 }
 
 // Add creates a new CephFilesystem Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
@@ -101,6 +105,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 		context:          context,
 		fsContexts:       make(map[string]*fsHealth),
 		opManagerContext: opManagerContext,
+		recorder:         k8sutil.NewEventReporter(mgr.GetEventRecorderFor("rook-" + controllerName)),
 		opConfig:         opConfig,
 	}
 }
@@ -205,6 +210,7 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 		}
 		return reconcileResponse, nil
 	}
+	opcontroller.RemoveUnvalidatedHotfix(&cephCluster)
 	r.cephClusterSpec = &cephCluster.Spec
 
 	// Initialize the contexts, they allow us to track multiple CephFilesystems in the same namespace
@@ -233,11 +239,20 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 		}
 		r.clusterInfo.CephVersion = runningCephVersion
 
-		// Detect against running version only
-		logger.Debugf("deleting filesystem %q", cephFilesystem.Name)
-		err = r.reconcileDeleteFilesystem(cephFilesystem)
-		if err != nil {
-			return reconcile.Result{}, errors.Wrapf(err, "failed to delete filesystem %q. ", cephFilesystem.Name)
+		// The orphaned mds daemons keep serving the filesystem
+		if opcontroller.IsOrphanDeletionPolicy(cephFilesystem.Spec.DeletionPolicy) {
+			orphaned, err := opcontroller.OrphanGeneratedResources(r.opManagerContext, r.client, cephFilesystem)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to orphan the resources of filesystem %q", cephFilesystem.Name)
+			}
+			reporting.ReportResourcesOrphaned(logger, r.client, r.recorder, cephFilesystem, orphaned)
+		} else {
+			// Detect against running version only
+			logger.Debugf("deleting filesystem %q", cephFilesystem.Name)
+			err = r.reconcileDeleteFilesystem(cephFilesystem)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to delete filesystem %q. ", cephFilesystem.Name)
+			}
 		}
 
 		// If the ceph fs still in the map, we must remove it during CR deletion
@@ -359,11 +374,57 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 		r.updateStatus(r.client, request.NamespacedName, cephv1.ConditionReady, nil)
 	}
 
+	// Start monitoring the client sessions
+	if !cephFilesystem.Spec.Clients.StatusCheck.Disabled {
+		if r.fsContexts[fsChannelKeyName(cephFilesystem)].sessionsStarted {
+			logger.Debug("ceph filesystem client sessions monitoring go routine already running!")
+		} else {
+			checker := newSessionChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, &cephFilesystem.Spec, cephFilesystem.Name)
+			go checker.checkSessions(r.fsContexts[fsChannelKeyName(cephFilesystem)].internalCtx)
+			r.fsContexts[fsChannelKeyName(cephFilesystem)].sessionsStarted = true
+		}
+	}
+
+	// Run the post-ready hooks
+	reconcileResponse, err = r.reconcileHooks(cephFilesystem, request.NamespacedName)
+	if err != nil || !reconcileResponse.IsZero() {
+		return reconcileResponse, err
+	}
+
 	// Return and do not requeue
 	logger.Debug("done reconciling")
 	return reconcile.Result{}, nil
 }
 
+// reconcileHooks runs the post-ready hooks of the filesystem, which is ready once it is reconciled
+func (r *ReconcileCephFilesystem) reconcileHooks(cephFilesystem *cephv1.CephFilesystem, namespacedName types.NamespacedName) (reconcile.Result, error) {
+	var statuses []cephv1.HookStatus
+	if cephFilesystem.Status != nil {
+		statuses = cephFilesystem.Status.Hooks
+	}
+	if len(cephFilesystem.Spec.Hooks.PostReady) == 0 && len(statuses) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	resource := opcontroller.HookResource{
+		Kind:      cephFilesystemKind,
+		Name:      cephFilesystem.Name,
+		Namespace: cephFilesystem.Namespace,
+		OwnerInfo: k8sutil.NewOwnerInfo(cephFilesystem, r.scheme),
+	}
+	newStatuses, running, err := opcontroller.ReconcileHooks(r.opManagerContext, r.context.Clientset, resource, cephFilesystem.Spec.Hooks.PostReady, statuses)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !reflect.DeepEqual(newStatuses, statuses) {
+		r.updateStatusHooks(namespacedName, newStatuses)
+	}
+	if running {
+		return opcontroller.WaitForRequeueIfHooksPending, nil
+	}
+	return reconcile.Result{}, nil
+}
+
 func (r *ReconcileCephFilesystem) reconcileCreateFilesystem(cephFilesystem *cephv1.CephFilesystem) (reconcile.Result, error) {
 	if r.cephClusterSpec.External.Enable {
 		_, err := opcontroller.ValidateCephVersionsBetweenLocalAndExternalClusters(r.context, r.clusterInfo)
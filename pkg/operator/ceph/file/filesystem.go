@@ -18,6 +18,7 @@ package file
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/rook/rook/pkg/operator/k8sutil"
 
@@ -27,6 +28,7 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/file/mds"
 	"github.com/rook/rook/pkg/operator/ceph/pool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -74,6 +76,33 @@ func createFilesystem(
 		}
 	}
 
+	if err := configureSessions(context, clusterInfo, fs); err != nil {
+		return errors.Wrapf(err, "failed to configure the client sessions of filesystem %q", fs.Name)
+	}
+
+	return nil
+}
+
+// configureSessions sets the timeouts of the client sessions of the filesystem from the tuning of the metadata
+// servers. The timeouts are left untouched if they are not set.
+func configureSessions(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, fs cephv1.CephFilesystem) error {
+	tuning := fs.Spec.MetadataServer.Tuning
+	if tuning == nil {
+		return nil
+	}
+	settings := map[string]*metav1.Duration{
+		"session_timeout":   tuning.SessionTimeout,
+		"session_autoclose": tuning.SessionAutoclose,
+	}
+	for setting, timeout := range settings {
+		if timeout == nil {
+			continue
+		}
+		seconds := strconv.Itoa(int(timeout.Seconds()))
+		if err := cephclient.SetFilesystemSetting(context, clusterInfo, fs.Name, setting, seconds); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -27,6 +27,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
@@ -158,6 +159,8 @@ func fsExecutor(t *testing.T, fsName, configDir string, multiFS bool, createData
 					return "", nil
 				} else if contains(args, "config") && contains(args, "mds_join_fs") {
 					return "", nil
+				} else if contains(args, "config") && contains(args, "mds_max_caps_per_client") {
+					return "", nil
 				} else if contains(args, "flag") && contains(args, "enable_multiple") {
 					return "", nil
 				} else if reflect.DeepEqual(args[0:5], []string{"osd", "crush", "rule", "create-replicated", fsName + "-data1"}) {
@@ -221,6 +224,8 @@ func fsExecutor(t *testing.T, fsName, configDir string, multiFS bool, createData
 				return "", nil
 			} else if contains(args, "config") && contains(args, "mds_join_fs") {
 				return "", nil
+			} else if contains(args, "config") && contains(args, "mds_max_caps_per_client") {
+				return "", nil
 			} else if contains(args, "config") && contains(args, "get") {
 				return "{}", nil
 			} else if reflect.DeepEqual(args[0:5], []string{"osd", "crush", "rule", "create-replicated", fsName + "-data1"}) {
@@ -524,3 +529,32 @@ func validateStart(ctx context.Context, t *testing.T, context *clusterd.Context,
 	assert.Nil(t, err)
 	assert.Equal(t, fmt.Sprintf("rook-ceph-mds-%s-b", fs.Name), r.Name)
 }
+
+func TestConfigureSessions(t *testing.T) {
+	settings := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "set" {
+				settings[args[2]+" "+args[3]] = args[4]
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	fs := fsTest("myfs")
+
+	// the sessions are left untouched without tuning
+	assert.NoError(t, configureSessions(context, clusterInfo, fs))
+	assert.Empty(t, settings)
+
+	fs.Spec.MetadataServer.Tuning = &cephv1.MDSTuningSpec{
+		SessionTimeout: &metav1.Duration{Duration: 2 * time.Minute},
+	}
+	assert.NoError(t, configureSessions(context, clusterInfo, fs))
+	assert.Equal(t, map[string]string{"myfs session_timeout": "120"}, settings)
+
+	fs.Spec.MetadataServer.Tuning.SessionAutoclose = &metav1.Duration{Duration: 10 * time.Minute}
+	assert.NoError(t, configureSessions(context, clusterInfo, fs))
+	assert.Equal(t, map[string]string{"myfs session_timeout": "120", "myfs session_autoclose": "600"}, settings)
+}
@@ -19,8 +19,10 @@ package mds
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -28,6 +30,8 @@ import (
 )
 
 const (
+	maxCapsPerClientOption = "mds_max_caps_per_client"
+
 	keyringTemplate = `
 [mds.%s]
 key = %s
@@ -69,7 +73,10 @@ func (c *Cluster) setDefaultFlagsMonConfigStore(mdsID string) error {
 	configOptions := make(map[string]string)
 
 	// Set mds cache memory limit to the best appropriate value
-	if !c.fs.Spec.MetadataServer.Resources.Limits.Memory().IsZero() {
+	tuning := c.fs.Spec.MetadataServer.Tuning
+	if tuning != nil && tuning.CacheMemoryLimit != nil && !tuning.CacheMemoryLimit.IsZero() {
+		configOptions["mds_cache_memory_limit"] = strconv.FormatInt(tuning.CacheMemoryLimit.Value(), 10)
+	} else if !c.fs.Spec.MetadataServer.Resources.Limits.Memory().IsZero() {
 		mdsCacheMemoryLimit := float64(c.fs.Spec.MetadataServer.Resources.Limits.Memory().Value()) * mdsCacheMemoryLimitFactor
 		configOptions["mds_cache_memory_limit"] = strconv.Itoa(int(mdsCacheMemoryLimit))
 	} else if !c.fs.Spec.MetadataServer.Resources.Requests.Memory().IsZero() {
@@ -82,6 +89,13 @@ func (c *Cluster) setDefaultFlagsMonConfigStore(mdsID string) error {
 		configOptions["mds_join_fs"] = c.fs.Name
 	}
 
+	// the max caps per client is removed when it is not in the tuning anymore
+	if tuning != nil && tuning.MaxCapsPerClient > 0 {
+		configOptions[maxCapsPerClientOption] = strconv.Itoa(tuning.MaxCapsPerClient)
+	} else if err := monStore.Delete(who, maxCapsPerClientOption); err != nil {
+		return errors.Wrapf(err, "failed to remove %q on %q", maxCapsPerClientOption, who)
+	}
+
 	for flag, val := range configOptions {
 		err := monStore.Set(who, flag, val)
 		if err != nil {
@@ -91,3 +105,30 @@ func (c *Cluster) setDefaultFlagsMonConfigStore(mdsID string) error {
 
 	return nil
 }
+
+// validateTuning validates the tuning of the metadata servers. The cache memory limit must be lower than the
+// memory limit of the pods, since the metadata servers use more memory than the limit of their cache.
+func validateTuning(spec *cephv1.MetadataServerSpec) error {
+	tuning := spec.Tuning
+	if tuning == nil {
+		return nil
+	}
+	if tuning.CacheMemoryLimit != nil {
+		if tuning.CacheMemoryLimit.Sign() < 0 {
+			return errors.Errorf("mds cache memory limit %q must be positive", tuning.CacheMemoryLimit.String())
+		}
+		podLimit := spec.Resources.Limits.Memory()
+		if !podLimit.IsZero() && tuning.CacheMemoryLimit.Cmp(*podLimit) >= 0 {
+			return errors.Errorf("mds cache memory limit %q must be lower than the memory limit %q of the mds pods", tuning.CacheMemoryLimit.String(), podLimit.String())
+		}
+	}
+	if tuning.MaxCapsPerClient < 0 {
+		return errors.Errorf("mds max caps per client %d must be positive", tuning.MaxCapsPerClient)
+	}
+	for name, d := range map[string]*metav1.Duration{"session timeout": tuning.SessionTimeout, "session autoclose": tuning.SessionAutoclose} {
+		if d != nil && d.Duration < time.Second {
+			return errors.Errorf("mds %s %q must be at least one second", name, d.Duration.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mds
+
+import (
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateTuning(t *testing.T) {
+	spec := &cephv1.MetadataServerSpec{}
+	assert.NoError(t, validateTuning(spec))
+
+	cacheLimit := resource.MustParse("4Gi")
+	spec.Tuning = &cephv1.MDSTuningSpec{CacheMemoryLimit: &cacheLimit, MaxCapsPerClient: 500000}
+	assert.NoError(t, validateTuning(spec))
+
+	// the cache limit must be lower than the memory limit of the pods
+	spec.Resources.Limits = v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")}
+	assert.Error(t, validateTuning(spec))
+	spec.Resources.Limits = v1.ResourceList{v1.ResourceMemory: resource.MustParse("8Gi")}
+	assert.NoError(t, validateTuning(spec))
+
+	spec.Tuning.SessionAutoclose = &metav1.Duration{Duration: time.Millisecond}
+	assert.Error(t, validateTuning(spec))
+}
+
+func TestSetDefaultFlagsMonConfigStore(t *testing.T) {
+	sets := map[string]string{}
+	removed := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "config" && args[1] == "set" {
+				sets[args[2]+" "+args[3]] = args[4]
+			}
+			if args[0] == "config" && args[1] == "rm" {
+				removed = append(removed, args[2]+" "+args[3])
+			}
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminClusterInfo("ns")
+	clusterInfo.CephVersion = cephver.Pacific
+	fs := cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "ns"},
+		Spec: cephv1.FilesystemSpec{
+			MetadataServer: cephv1.MetadataServerSpec{
+				ActiveCount: 1,
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+				},
+			},
+		},
+	}
+	newCluster := func() *Cluster {
+		return NewCluster(clusterInfo, &clusterd.Context{Executor: executor}, &cephv1.ClusterSpec{}, fs, &k8sutil.OwnerInfo{}, "/var/lib/rook/")
+	}
+
+	// the cache limit is derived from the memory limit without tuning
+	assert.NoError(t, newCluster().setDefaultFlagsMonConfigStore("myfs-a"))
+	assert.Equal(t, "2147483648", sets["mds.myfs-a mds_cache_memory_limit"])
+	assert.Equal(t, "myfs", sets["mds.myfs-a mds_join_fs"])
+	assert.Equal(t, []string{"mds.myfs-a mds_max_caps_per_client"}, removed)
+
+	// the tuning overrides the cache limit
+	cacheLimit := resource.MustParse("3Gi")
+	fs.Spec.MetadataServer.Tuning = &cephv1.MDSTuningSpec{CacheMemoryLimit: &cacheLimit, MaxCapsPerClient: 100000}
+	removed = []string{}
+	assert.NoError(t, newCluster().setDefaultFlagsMonConfigStore("myfs-a"))
+	assert.Equal(t, "3221225472", sets["mds.myfs-a mds_cache_memory_limit"])
+	assert.Equal(t, "100000", sets["mds.myfs-a mds_max_caps_per_client"])
+	assert.Empty(t, removed)
+}
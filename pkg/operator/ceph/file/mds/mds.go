@@ -96,6 +96,9 @@ func (c *Cluster) Start() error {
 	if err != nil {
 		return errors.Wrap(err, "error checking pod memory")
 	}
+	if err := validateTuning(&c.fs.Spec.MetadataServer); err != nil {
+		return errors.Wrap(err, "invalid mds tuning")
+	}
 
 	// If attempt was made to prepare daemons for upgrade, make sure that an attempt is made to
 	// bring fs state back to desired when this method returns with any error or success.
@@ -106,6 +106,8 @@ func (c *Cluster) makeDeployment(mdsConfig *mdsConfig, namespace string) (*apps.
 			return nil, err
 		}
 	}
+	cephv1.GetDaemonDNS(c.clusterSpec.DNS, cephv1.KeyMds).ApplyToPodSpec(&d.Spec.Template.Spec)
+	cephv1.GetDaemonContainerOverrides(c.clusterSpec.ContainerOverrides, cephv1.KeyMds).ApplyToPodSpec(&d.Spec.Template.Spec)
 
 	k8sutil.AddRookVersionLabelToDeployment(d)
 	c.fs.Spec.MetadataServer.Annotations.ApplyToObjectMeta(&d.ObjectMeta)
@@ -118,7 +120,7 @@ func (c *Cluster) makeDeployment(mdsConfig *mdsConfig, namespace string) (*apps.
 func (c *Cluster) makeChownInitContainer(mdsConfig *mdsConfig) v1.Container {
 	return controller.ChownCephDataDirsInitContainer(
 		*mdsConfig.DataPathMap,
-		c.clusterSpec.CephVersion.Image,
+		c.clusterSpec.CephImage(cephv1.HotfixDaemonMds),
 		controller.DaemonVolumeMounts(mdsConfig.DataPathMap, mdsConfig.ResourceName),
 		c.fs.Spec.MetadataServer.Resources,
 		controller.PodSecurityContext(),
@@ -142,9 +144,9 @@ func (c *Cluster) makeMdsDaemonContainer(mdsConfig *mdsConfig) v1.Container {
 			"ceph-mds",
 		},
 		Args:            args,
-		Image:           c.clusterSpec.CephVersion.Image,
+		Image:           c.clusterSpec.CephImage(cephv1.HotfixDaemonMds),
 		VolumeMounts:    controller.DaemonVolumeMounts(mdsConfig.DataPathMap, mdsConfig.ResourceName),
-		Env:             append(controller.DaemonEnvVars(c.clusterSpec.CephVersion.Image), k8sutil.PodIPEnvVar(podIPEnvVar)),
+		Env:             append(controller.DaemonEnvVars(c.clusterSpec.CephImage(cephv1.HotfixDaemonMds)), k8sutil.PodIPEnvVar(podIPEnvVar)),
 		Resources:       c.fs.Spec.MetadataServer.Resources,
 		SecurityContext: controller.PodSecurityContext(),
 		LivenessProbe:   controller.GenerateLivenessProbeExecDaemon(config.MdsType, mdsConfig.DaemonID),
@@ -82,6 +82,7 @@ type ReconcileFilesystemMirror struct {
 // Add creates a new CephFilesystemMirror Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
@@ -164,6 +165,7 @@ func (r *ReconcileFilesystemMirror) reconcile(request reconcile.Request) (reconc
 		return reconcileResponse, nil
 	}
 
+	opcontroller.RemoveUnvalidatedHotfix(&cephCluster)
 	// Assign the clusterSpec
 	r.cephClusterSpec = &cephCluster.Spec
 
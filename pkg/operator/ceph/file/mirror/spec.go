@@ -68,6 +68,8 @@ func (r *ReconcileFilesystemMirror) makeDeployment(daemonConfig *daemonConfig, f
 			return nil, err
 		}
 	}
+	cephv1.GetDaemonDNS(r.cephClusterSpec.DNS, cephv1.KeyFSMirror).ApplyToPodSpec(&podSpec.Spec)
+	cephv1.GetDaemonContainerOverrides(r.cephClusterSpec.ContainerOverrides, cephv1.KeyFSMirror).ApplyToPodSpec(&podSpec.Spec)
 	fsMirror.Spec.Placement.ApplyToPodSpec(&podSpec.Spec)
 
 	replicas := int32(1)
@@ -97,7 +99,7 @@ func (r *ReconcileFilesystemMirror) makeDeployment(daemonConfig *daemonConfig, f
 func (r *ReconcileFilesystemMirror) makeChownInitContainer(daemonConfig *daemonConfig, fsMirror *cephv1.CephFilesystemMirror) v1.Container {
 	return controller.ChownCephDataDirsInitContainer(
 		*daemonConfig.DataPathMap,
-		r.cephClusterSpec.CephVersion.Image,
+		r.cephClusterSpec.CephImage(cephv1.HotfixDaemonCephFSMirror),
 		controller.DaemonVolumeMounts(daemonConfig.DataPathMap, daemonConfig.ResourceName),
 		fsMirror.Spec.Resources,
 		controller.PodSecurityContext(),
@@ -115,9 +117,9 @@ func (r *ReconcileFilesystemMirror) makeFsMirroringDaemonContainer(daemonConfig
 			"--foreground",
 			"--name="+user,
 		),
-		Image:           r.cephClusterSpec.CephVersion.Image,
+		Image:           r.cephClusterSpec.CephImage(cephv1.HotfixDaemonCephFSMirror),
 		VolumeMounts:    controller.DaemonVolumeMounts(daemonConfig.DataPathMap, daemonConfig.ResourceName),
-		Env:             controller.DaemonEnvVars(r.cephClusterSpec.CephVersion.Image),
+		Env:             controller.DaemonEnvVars(r.cephClusterSpec.CephImage(cephv1.HotfixDaemonCephFSMirror)),
 		Resources:       fsMirror.Spec.Resources,
 		SecurityContext: controller.PodSecurityContext(),
 		// TODO:
@@ -0,0 +1,298 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// EvictClientsAnnotation is the annotation of the CephFilesystem requesting the eviction of client sessions,
+	// "stale" to evict the stale sessions or a comma-separated list of client ids. Each value is handled once.
+	EvictClientsAnnotation = "ceph.rook.io/evict-clients"
+
+	evictStaleSessionsRequest = "stale"
+	staleSessionState         = "stale"
+)
+
+type sessionChecker struct {
+	context        *clusterd.Context
+	interval       time.Duration
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	fsName         string
+}
+
+// newSessionChecker creates a checker of the sessions of the clients of a filesystem
+func newSessionChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, fsSpec *cephv1.FilesystemSpec, fsName string) *sessionChecker {
+	c := &sessionChecker{
+		context:        context,
+		interval:       defaultHealthCheckInterval,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		client:         client,
+		fsName:         fsName,
+	}
+
+	// allow overriding the check interval
+	checkInterval := fsSpec.Clients.StatusCheck.Interval
+	if checkInterval != nil {
+		logger.Infof("filesystem %q client sessions check interval is %q", namespacedName.Name, checkInterval)
+		c.interval = checkInterval.Duration
+	}
+
+	return c
+}
+
+// checkSessions periodically reports the sessions of the clients and evicts the sessions to evict
+func (c *sessionChecker) checkSessions(context context.Context) {
+	for {
+		if err := c.checkSessionsOnce(); err != nil {
+			logger.Errorf("failed to check the client sessions of filesystem %q. %v", c.namespacedName.Name, err)
+		}
+
+		select {
+		case <-context.Done():
+			logger.Infof("stopping monitoring the client sessions of filesystem %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debugf("checking the client sessions of filesystem %q", c.namespacedName.Name)
+		}
+	}
+}
+
+func (c *sessionChecker) checkSessionsOnce() error {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve ceph filesystem %q", c.namespacedName.Name)
+	}
+	if fs.Spec.Clients.StatusCheck.Disabled {
+		return nil
+	}
+
+	status := &cephv1.FilesystemClientsStatus{}
+	if fs.Status != nil && fs.Status.Clients != nil {
+		status = fs.Status.Clients.DeepCopy()
+	}
+	err := c.reconcileSessions(fs, status)
+	status.Details = ""
+	if err != nil {
+		status.Details = err.Error()
+	}
+	status.LastChecked = time.Now().UTC().Format(time.RFC3339)
+	c.updateStatusClients(fs, status)
+	return err
+}
+
+// reconcileSessions lists the sessions of the clients, then evicts the sessions requested with the evict-clients
+// annotation and the stale sessions of the deleted nodes and pods
+func (c *sessionChecker) reconcileSessions(fs *cephv1.CephFilesystem, status *cephv1.FilesystemClientsStatus) error {
+	mdsSessions, err := cephclient.ListMDSSessions(c.context, c.clusterInfo, c.fsName)
+	if err != nil {
+		return err
+	}
+	sessions, err := c.mapSessions(mdsSessions)
+	if err != nil {
+		return err
+	}
+	status.Sessions = sessions
+
+	request := fs.Annotations[EvictClientsAnnotation]
+	if request == "" {
+		status.EvictionRequest = ""
+	}
+	evict := map[int64]string{}
+	if request != "" && request != status.EvictionRequest {
+		requested, err := sessionsToEvict(request, sessions)
+		if err != nil {
+			return err
+		}
+		for _, id := range requested {
+			if sessionsFrom(sessions, id) == nil {
+				logger.Warningf("client %d of filesystem %q requested by %s=%q has no session", id, c.fsName, EvictClientsAnnotation, request)
+				continue
+			}
+			evict[id] = fmt.Sprintf("requested by %s=%q", EvictClientsAnnotation, request)
+		}
+	}
+	if fs.Spec.Clients.EvictFromDeletedNodes {
+		for _, session := range sessions {
+			if session.State == staleSessionState && session.Node == "" && session.Pod == "" {
+				evict[session.ID] = "the node or the pod of the client no longer exists"
+			}
+		}
+	}
+	if len(evict) == 0 {
+		status.EvictionRequest = request
+		return nil
+	}
+
+	status.Evicted = nil
+	remaining := []cephv1.FilesystemClientSession{}
+	for _, session := range sessions {
+		reason, ok := evict[session.ID]
+		if !ok {
+			remaining = append(remaining, session)
+			continue
+		}
+		logger.Infof("evicting client %d with %d caps of filesystem %q, %s", session.ID, session.NumCaps, c.fsName, reason)
+		if err := cephclient.EvictMDSSession(c.context, c.clusterInfo, c.fsName, session.ID); err != nil {
+			// the request is retried at the next check
+			status.Sessions = append(remaining, sessionsFrom(sessions, session.ID)...)
+			return err
+		}
+		status.Evicted = append(status.Evicted, session)
+	}
+	status.Sessions = remaining
+	status.EvictionRequest = request
+	return nil
+}
+
+// sessionsToEvict returns the ids of the sessions requested with the value of the evict-clients annotation
+func sessionsToEvict(request string, sessions []cephv1.FilesystemClientSession) ([]int64, error) {
+	var ids []int64
+	if request == evictStaleSessionsRequest {
+		for _, session := range sessions {
+			if session.State == staleSessionState {
+				ids = append(ids, session.ID)
+			}
+		}
+		return ids, nil
+	}
+
+	for _, value := range strings.Split(request, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid %s=%q, expected %q or a comma-separated list of client ids", EvictClientsAnnotation, request, evictStaleSessionsRequest)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// sessionsFrom returns the sessions from the session with the given id, or nil if there is no such session
+func sessionsFrom(sessions []cephv1.FilesystemClientSession, id int64) []cephv1.FilesystemClientSession {
+	for i, session := range sessions {
+		if session.ID == id {
+			return sessions[i:]
+		}
+	}
+	return nil
+}
+
+// mapSessions returns the status of the sessions with the node or the pod of each client. A client is on a node if
+// it reports the hostname of the node or has the address of the node, such as the kernel clients of the CSI driver,
+// and in a pod if it has the address of the pod.
+func (c *sessionChecker) mapSessions(mdsSessions []cephclient.MDSSession) ([]cephv1.FilesystemClientSession, error) {
+	nodes, err := c.context.Clientset.CoreV1().Nodes().List(c.clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the nodes")
+	}
+	nodeNames := map[string]string{}
+	nodeAddresses := map[string]string{}
+	for _, node := range nodes.Items {
+		nodeNames[node.Name] = node.Name
+		for _, addr := range node.Status.Addresses {
+			nodeAddresses[addr.Address] = node.Name
+			if addr.Type == corev1.NodeHostName {
+				nodeNames[addr.Address] = node.Name
+			}
+		}
+	}
+
+	pods := map[string]string{}
+	var sessions []cephv1.FilesystemClientSession
+	for _, mdsSession := range mdsSessions {
+		session := cephv1.FilesystemClientSession{
+			ID:       mdsSession.ID,
+			State:    mdsSession.State,
+			Address:  mdsSession.IP(),
+			Hostname: mdsSession.ClientMetadata.Hostname,
+			NumCaps:  mdsSession.NumCaps,
+		}
+		if node, ok := nodeNames[session.Hostname]; ok {
+			session.Node = node
+		} else if node, ok := nodeAddresses[session.Address]; ok {
+			session.Node = node
+		} else if session.Address != "" {
+			pod, ok := pods[session.Address]
+			if !ok {
+				pod, err = c.findPodByIP(session.Address)
+				if err != nil {
+					return nil, err
+				}
+				pods[session.Address] = pod
+			}
+			session.Pod = pod
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// findPodByIP returns the running pod with the ip as "namespace/name", or an empty string if there is none
+func (c *sessionChecker) findPodByIP(ip string) (string, error) {
+	opts := metav1.ListOptions{FieldSelector: fmt.Sprintf("status.podIP=%s", ip)}
+	pods, err := c.context.Clientset.CoreV1().Pods("").List(c.clusterInfo.Context, opts)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the pods with ip %q", ip)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.PodIP == ip && pod.DeletionTimestamp == nil {
+			return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name), nil
+		}
+	}
+	return "", nil
+}
+
+// updateStatusClients updates the status of the sessions of the clients of a fs CR
+func (c *sessionChecker) updateStatusClients(fs *cephv1.CephFilesystem, status *cephv1.FilesystemClientsStatus) {
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+	if reflect.DeepEqual(fs.Status.Clients, status) {
+		return
+	}
+	fs.Status.Clients = status
+	if err := reporting.UpdateStatus(c.client, fs); err != nil {
+		logger.Errorf("failed to set the status of the client sessions of filesystem %q. %v", c.namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("filesystem %q client sessions status updated", c.namespacedName.Name)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckSessions(t *testing.T) {
+	sessions := map[int64]string{
+		// on the node by hostname
+		1: `{"id":1,"entity":{"addr":{"addr":"10.0.0.1:0"}},"state":"open","num_caps":3,"client_metadata":{"hostname":"node1"}}`,
+		// on the node by address
+		2: `{"id":2,"entity":{"addr":{"addr":"10.0.0.1:0"}},"state":"stale","num_caps":5,"client_metadata":{"hostname":"host1"}}`,
+		// in a pod
+		3: `{"id":3,"entity":{"addr":{"addr":"10.1.0.5:0"}},"state":"open","num_caps":1,"client_metadata":{"hostname":"app"}}`,
+		// on a deleted node
+		4: `{"id":4,"entity":{"addr":{"addr":"10.0.0.9:0"}},"state":"stale","num_caps":8,"client_metadata":{"hostname":"node9"}}`,
+	}
+	var evicted []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "tell" && args[2] == "session" {
+				var list []string
+				for id := int64(1); id <= 4; id++ {
+					if session, ok := sessions[id]; ok {
+						list = append(list, session)
+					}
+				}
+				return "[" + strings.Join(list, ",") + "]", nil
+			}
+			if args[0] == "tell" && args[2] == "client" && args[3] == "evict" {
+				evicted = append(evicted, args[4])
+				var id int64
+				_, err := fmt.Sscanf(args[4], "id=%d", &id)
+				require.NoError(t, err)
+				delete(sessions, id)
+				return "", nil
+			}
+			return "", nil
+		},
+	}
+
+	clientset := test.New(t, 0)
+	test.AddReadyNode(t, clientset, "node1", "10.0.0.1")
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"}, Status: v1.PodStatus{PodIP: "10.1.0.5"}}
+	_, err := clientset.CoreV1().Pods("apps").Create(context.TODO(), pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	fs := &cephv1.CephFilesystem{ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "rook-ceph"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(fs).Build()
+	namespacedName := types.NamespacedName{Name: "myfs", Namespace: "rook-ceph"}
+	clusterInfo := cephclient.AdminClusterInfo("rook-ceph")
+	c := newSessionChecker(&clusterd.Context{Executor: executor, Clientset: clientset}, cl, clusterInfo, namespacedName, &fs.Spec, "myfs")
+
+	getStatus := func() *cephv1.FilesystemClientsStatus {
+		fs := &cephv1.CephFilesystem{}
+		require.NoError(t, cl.Get(context.TODO(), namespacedName, fs))
+		require.NotNil(t, fs.Status)
+		return fs.Status.Clients
+	}
+	updateFilesystem := func(update func(fs *cephv1.CephFilesystem)) {
+		fs := &cephv1.CephFilesystem{}
+		require.NoError(t, cl.Get(context.TODO(), namespacedName, fs))
+		update(fs)
+		require.NoError(t, cl.Update(context.TODO(), fs))
+	}
+
+	// the sessions are reported with their node or pod
+	require.NoError(t, c.checkSessionsOnce())
+	status := getStatus()
+	assert.Equal(t, []cephv1.FilesystemClientSession{
+		{ID: 1, State: "open", Address: "10.0.0.1", Hostname: "node1", Node: "node1", NumCaps: 3},
+		{ID: 2, State: "stale", Address: "10.0.0.1", Hostname: "host1", Node: "node1", NumCaps: 5},
+		{ID: 3, State: "open", Address: "10.1.0.5", Hostname: "app", Pod: "apps/app", NumCaps: 1},
+		{ID: 4, State: "stale", Address: "10.0.0.9", Hostname: "node9", NumCaps: 8},
+	}, status.Sessions)
+	assert.NotEmpty(t, status.LastChecked)
+	assert.Empty(t, evicted)
+
+	// the stale session of the deleted node is evicted
+	updateFilesystem(func(fs *cephv1.CephFilesystem) { fs.Spec.Clients.EvictFromDeletedNodes = true })
+	require.NoError(t, c.checkSessionsOnce())
+	assert.Equal(t, []string{"id=4"}, evicted)
+	status = getStatus()
+	assert.Len(t, status.Sessions, 3)
+	assert.Equal(t, int64(4), status.Evicted[0].ID)
+
+	// the stale sessions are evicted on demand, once
+	evicted = nil
+	updateFilesystem(func(fs *cephv1.CephFilesystem) {
+		fs.Annotations = map[string]string{EvictClientsAnnotation: "stale"}
+	})
+	require.NoError(t, c.checkSessionsOnce())
+	require.NoError(t, c.checkSessionsOnce())
+	assert.Equal(t, []string{"id=2"}, evicted)
+	status = getStatus()
+	assert.Equal(t, "stale", status.EvictionRequest)
+	assert.Len(t, status.Sessions, 2)
+
+	// the sessions are evicted by id
+	evicted = nil
+	updateFilesystem(func(fs *cephv1.CephFilesystem) { fs.Annotations[EvictClientsAnnotation] = "3, 7" })
+	require.NoError(t, c.checkSessionsOnce())
+	assert.Equal(t, []string{"id=3"}, evicted)
+	assert.Equal(t, "3, 7", getStatus().EvictionRequest)
+
+	// an invalid request is reported
+	evicted = nil
+	updateFilesystem(func(fs *cephv1.CephFilesystem) { fs.Annotations[EvictClientsAnnotation] = "all" })
+	assert.Error(t, c.checkSessionsOnce())
+	assert.Empty(t, evicted)
+	assert.Contains(t, getStatus().Details, "invalid")
+
+	// the request is reset with the annotation
+	updateFilesystem(func(fs *cephv1.CephFilesystem) { fs.Annotations = nil })
+	require.NoError(t, c.checkSessionsOnce())
+	status = getStatus()
+	assert.Equal(t, "", status.EvictionRequest)
+	assert.Equal(t, "", status.Details)
+}
@@ -0,0 +1,478 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package staticvolume to manage the static volumes of pre-existing CephFS directories
+package staticvolume
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-fs-staticvolume-controller"
+
+	// StaticVolumeLabel is the label of the PV and the PVC of a static volume with the name of its CR
+	StaticVolumeLabel = "ceph.rook.io/cephfs-static-volume"
+
+	defaultCapacity = "1Gi"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephFilesystemStaticVolumeKind = reflect.TypeOf(cephv1.CephFilesystemStaticVolume{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephFilesystemStaticVolumeKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephFilesystemStaticVolume reconciles a CephFilesystemStaticVolume object
+type ReconcileCephFilesystemStaticVolume struct {
+	client           client.Client
+	scheme           *runtime.Scheme
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+}
+
+// Add creates a new CephFilesystemStaticVolume Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephFilesystemStaticVolume{
+		client:           mgr.GetClient(),
+		scheme:           mgr.GetScheme(),
+		context:          context,
+		opManagerContext: opManagerContext,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephFilesystemStaticVolume CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephFilesystemStaticVolume{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephFilesystemStaticVolume object and makes changes based on the state read
+// and what is in the CephFilesystemStaticVolume.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephFilesystemStaticVolume) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephFilesystemStaticVolume) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephFilesystemStaticVolume instance
+	staticVolume := &cephv1.CephFilesystemStaticVolume{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, staticVolume)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("cephFilesystemStaticVolume resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get cephFilesystemStaticVolume")
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, staticVolume)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// The CR was just created, initializing status fields
+	if staticVolume.Status == nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionProgressing)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the deleteStaticVolume() function since everything is gone already
+		//
+		// Also, only remove the finalizer if the CephCluster is gone
+		// If not, we should wait for it to be ready
+		// This handles the case where the operator is not ready to accept Ceph command but the cluster exists
+		if !staticVolume.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, staticVolume)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	// DELETE: the CR was deleted
+	if !staticVolume.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("deleting static volume %q", staticVolume.Name)
+		if err := r.deleteStaticVolume(staticVolume); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to delete ceph filesystem static volume %q", staticVolume.Name)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, staticVolume)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the static volume settings
+	if err := validateStaticVolume(staticVolume); err != nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid ceph filesystem static volume %q", staticVolume.Name)
+	}
+
+	// Create the static volume
+	err = r.createStaticVolume(staticVolume)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			logger.Info(opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
+		}
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create ceph filesystem static volume %q", staticVolume.Name)
+	}
+
+	// Success! Let's update the status
+	r.updateStatus(request.NamespacedName, cephv1.ConditionReady)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+func validateStaticVolume(staticVolume *cephv1.CephFilesystemStaticVolume) error {
+	if staticVolume.Spec.FilesystemName == "" {
+		return errors.New("missing filesystem name")
+	}
+	if !strings.HasPrefix(staticVolume.Spec.Path, "/") {
+		return errors.Errorf("path %q must be absolute", staticVolume.Spec.Path)
+	}
+	if staticVolume.Spec.ClaimNamespace == "" {
+		return errors.New("missing claim namespace")
+	}
+	return nil
+}
+
+// createStaticVolume creates the cephx user scoped to the directory, its secret, and the PV/PVC pair
+// of the static volume. The PV and the PVC are not updated once created since the volume source of
+// a PV is immutable.
+func (r *ReconcileCephFilesystemStaticVolume) createStaticVolume(staticVolume *cephv1.CephFilesystemStaticVolume) error {
+	if csi.CephFSDriverName == "" {
+		return errors.New("the CephFS CSI driver is not configured")
+	}
+	logger.Infof("creating ceph filesystem static volume %q for path %q of filesystem %q", staticVolume.Name, staticVolume.Spec.Path, staticVolume.Spec.FilesystemName)
+
+	if err := r.createUser(staticVolume); err != nil {
+		return errors.Wrap(err, "failed to create the cephx user of the static volume")
+	}
+
+	pv := r.makePersistentVolume(staticVolume)
+	_, err := r.context.Clientset.CoreV1().PersistentVolumes().Create(r.opManagerContext, pv, metav1.CreateOptions{})
+	if err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create persistent volume %q", pv.Name)
+		}
+		logger.Debugf("persistent volume %q already exists", pv.Name)
+	}
+
+	pvc := makePersistentVolumeClaim(staticVolume, pv)
+	_, err = r.context.Clientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(r.opManagerContext, pvc, metav1.CreateOptions{})
+	if err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create persistent volume claim %q in namespace %q", pvc.Name, pvc.Namespace)
+		}
+		logger.Debugf("persistent volume claim %q in namespace %q already exists", pvc.Name, pvc.Namespace)
+	}
+
+	logger.Infof("created ceph filesystem static volume %q", staticVolume.Name)
+	return nil
+}
+
+// createUser creates the cephx user of the static volume with access to the directory only, and
+// saves its key in the node stage secret of the volume
+func (r *ReconcileCephFilesystemStaticVolume) createUser(staticVolume *cephv1.CephFilesystemStaticVolume) error {
+	userName := generateUserName(staticVolume)
+	caps := generateUserCaps(staticVolume)
+
+	// create the user if necessary or update its caps in case the access changed
+	key, err := cephclient.AuthGetKey(r.context, r.clusterInfo, userName)
+	if err != nil {
+		key, err = cephclient.AuthGetOrCreateKey(r.context, r.clusterInfo, userName, caps)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create user %q", userName)
+		}
+	} else {
+		err = cephclient.AuthUpdateCaps(r.context, r.clusterInfo, userName, caps)
+		if err != nil {
+			return errors.Wrapf(err, "user %q exists, failed to update user caps", userName)
+		}
+	}
+
+	// the keys of the secret are the ones expected by the CSI driver for static volumes
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      generateSecretName(staticVolume),
+			Namespace: staticVolume.Namespace,
+		},
+		StringData: map[string]string{
+			"userID":  strings.TrimPrefix(userName, "client."),
+			"userKey": key,
+		},
+		Type: k8sutil.RookType,
+	}
+
+	// Set CephFilesystemStaticVolume owner ref to the Secret
+	err = controllerutil.SetControllerReference(staticVolume, secret, r.scheme)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to static volume secret %q", secret.Name)
+	}
+
+	_, err = r.context.Clientset.CoreV1().Secrets(secret.Namespace).Create(r.opManagerContext, secret, metav1.CreateOptions{})
+	if err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create secret %q", secret.Name)
+		}
+		if _, err := r.context.Clientset.CoreV1().Secrets(secret.Namespace).Update(r.opManagerContext, secret, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update secret %q", secret.Name)
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileCephFilesystemStaticVolume) makePersistentVolume(staticVolume *cephv1.CephFilesystemStaticVolume) *v1.PersistentVolume {
+	name := generatePersistentVolumeName(staticVolume)
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{StaticVolumeLabel: staticVolume.Name},
+		},
+		Spec: v1.PersistentVolumeSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			Capacity:    v1.ResourceList{v1.ResourceStorage: capacity(staticVolume)},
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{
+					Driver:       csi.CephFSDriverName,
+					VolumeHandle: name,
+					ReadOnly:     staticVolume.Spec.ReadOnly,
+					NodeStageSecretRef: &v1.SecretReference{
+						Name:      generateSecretName(staticVolume),
+						Namespace: staticVolume.Namespace,
+					},
+					VolumeAttributes: map[string]string{
+						"clusterID":    staticVolume.Namespace,
+						"fsName":       staticVolume.Spec.FilesystemName,
+						"staticVolume": "true",
+						"rootPath":     staticVolume.Spec.Path,
+					},
+				},
+			},
+			// bind the PV to the PVC of the static volume only
+			ClaimRef: &v1.ObjectReference{
+				Namespace: staticVolume.Spec.ClaimNamespace,
+				Name:      claimName(staticVolume),
+			},
+			// the pre-existing data is never deleted
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+			VolumeMode:                    volumeModePtr(v1.PersistentVolumeFilesystem),
+		},
+	}
+}
+
+func makePersistentVolumeClaim(staticVolume *cephv1.CephFilesystemStaticVolume, pv *v1.PersistentVolume) *v1.PersistentVolumeClaim {
+	// the empty storage class prevents the dynamic provisioning of the claim
+	storageClassName := ""
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimName(staticVolume),
+			Namespace: staticVolume.Spec.ClaimNamespace,
+			Labels:    map[string]string{StaticVolumeLabel: staticVolume.Name},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			StorageClassName: &storageClassName,
+			VolumeName:       pv.Name,
+			VolumeMode:       volumeModePtr(v1.PersistentVolumeFilesystem),
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: capacity(staticVolume)},
+			},
+		},
+	}
+}
+
+// deleteStaticVolume deletes the PV/PVC pair and the cephx user of the static volume. The data of
+// the directory is kept.
+func (r *ReconcileCephFilesystemStaticVolume) deleteStaticVolume(staticVolume *cephv1.CephFilesystemStaticVolume) error {
+	name := claimName(staticVolume)
+	err := r.context.Clientset.CoreV1().PersistentVolumeClaims(staticVolume.Spec.ClaimNamespace).Delete(r.opManagerContext, name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete persistent volume claim %q in namespace %q", name, staticVolume.Spec.ClaimNamespace)
+	}
+
+	name = generatePersistentVolumeName(staticVolume)
+	err = r.context.Clientset.CoreV1().PersistentVolumes().Delete(r.opManagerContext, name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete persistent volume %q", name)
+	}
+
+	if err := cephclient.AuthDelete(r.context, r.clusterInfo, generateUserName(staticVolume)); err != nil {
+		return errors.Wrap(err, "failed to delete the cephx user of the static volume")
+	}
+
+	logger.Infof("deleted ceph filesystem static volume %q", staticVolume.Name)
+	return nil
+}
+
+func generateUserName(staticVolume *cephv1.CephFilesystemStaticVolume) string {
+	return fmt.Sprintf("client.cephfs-static-%s", staticVolume.Name)
+}
+
+// generateUserCaps returns the caps restricting the access of the user to the directory
+func generateUserCaps(staticVolume *cephv1.CephFilesystemStaticVolume) []string {
+	access := "rw"
+	if staticVolume.Spec.ReadOnly {
+		access = "r"
+	}
+	return []string{
+		"mon", "allow r",
+		"mds", fmt.Sprintf("allow %s path=%s", access, staticVolume.Spec.Path),
+		"osd", fmt.Sprintf("allow %s tag cephfs data=%s", access, staticVolume.Spec.FilesystemName),
+	}
+}
+
+func generateSecretName(staticVolume *cephv1.CephFilesystemStaticVolume) string {
+	return fmt.Sprintf("rook-ceph-cephfs-static-%s", staticVolume.Name)
+}
+
+// generatePersistentVolumeName returns the name of the PV, which includes the namespace of the CR
+// since the PVs are not namespaced
+func generatePersistentVolumeName(staticVolume *cephv1.CephFilesystemStaticVolume) string {
+	return fmt.Sprintf("cephfs-static-%s-%s", staticVolume.Namespace, staticVolume.Name)
+}
+
+func claimName(staticVolume *cephv1.CephFilesystemStaticVolume) string {
+	if staticVolume.Spec.ClaimName != "" {
+		return staticVolume.Spec.ClaimName
+	}
+	return staticVolume.Name
+}
+
+func capacity(staticVolume *cephv1.CephFilesystemStaticVolume) resource.Quantity {
+	if staticVolume.Spec.Capacity.IsZero() {
+		return resource.MustParse(defaultCapacity)
+	}
+	return staticVolume.Spec.Capacity
+}
+
+func volumeModePtr(mode v1.PersistentVolumeMode) *v1.PersistentVolumeMode {
+	return &mode
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileCephFilesystemStaticVolume) updateStatus(name types.NamespacedName, status cephv1.ConditionType) {
+	staticVolume := &cephv1.CephFilesystemStaticVolume{}
+	if err := r.client.Get(r.opManagerContext, name, staticVolume); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystemStaticVolume resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph filesystem static volume %q to update status to %q. %v", name, status, err)
+		return
+	}
+	if staticVolume.Status == nil {
+		staticVolume.Status = &cephv1.CephFilesystemStaticVolumeStatus{}
+	}
+
+	staticVolume.Status.Phase = status
+	staticVolume.Status.Info = nil
+	if status == cephv1.ConditionReady {
+		staticVolume.Status.Info = map[string]string{
+			"persistentVolume":      generatePersistentVolumeName(staticVolume),
+			"persistentVolumeClaim": fmt.Sprintf("%s/%s", staticVolume.Spec.ClaimNamespace, claimName(staticVolume)),
+			"cephxUser":             generateUserName(staticVolume),
+		}
+	}
+	if err := reporting.UpdateStatus(r.client, staticVolume); err != nil {
+		logger.Errorf("failed to set ceph filesystem static volume %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("ceph filesystem static volume %q status updated to %q", name, status)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package staticvolume
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestGenerateUserCaps(t *testing.T) {
+	staticVolume := &cephv1.CephFilesystemStaticVolume{
+		Spec: cephv1.CephFilesystemStaticVolumeSpec{FilesystemName: "myfs", Path: "/data"},
+	}
+	assert.Equal(t, []string{"mon", "allow r", "mds", "allow rw path=/data", "osd", "allow rw tag cephfs data=myfs"}, generateUserCaps(staticVolume))
+
+	staticVolume.Spec.ReadOnly = true
+	assert.Equal(t, []string{"mon", "allow r", "mds", "allow r path=/data", "osd", "allow r tag cephfs data=myfs"}, generateUserCaps(staticVolume))
+}
+
+func TestValidateStaticVolume(t *testing.T) {
+	staticVolume := &cephv1.CephFilesystemStaticVolume{
+		Spec: cephv1.CephFilesystemStaticVolumeSpec{FilesystemName: "myfs", Path: "/data", ClaimNamespace: "app"},
+	}
+	assert.NoError(t, validateStaticVolume(staticVolume))
+
+	staticVolume.Spec.Path = "data"
+	assert.Error(t, validateStaticVolume(staticVolume))
+
+	staticVolume.Spec.Path = "/data"
+	staticVolume.Spec.ClaimNamespace = ""
+	assert.Error(t, validateStaticVolume(staticVolume))
+}
+
+func TestCephFilesystemStaticVolumeController(t *testing.T) {
+	ctx := context.TODO()
+	var (
+		name      = "legacy-data"
+		namespace = "rook-ceph"
+	)
+	csi.CephFSDriverName = "rook-ceph.cephfs.csi.ceph.com"
+	defer func() { csi.CephFSDriverName = "" }()
+
+	staticVolume := &cephv1.CephFilesystemStaticVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cephv1.CephFilesystemStaticVolumeSpec{
+			FilesystemName: "myfs",
+			Path:           "/volumes/legacy",
+			ClaimNamespace: "app",
+			Capacity:       resource.MustParse("10Gi"),
+		},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespace,
+			Namespace: namespace,
+		},
+		Status: cephv1.ClusterStatus{
+			Phase: cephv1.ConditionReady,
+			CephVersion: &cephv1.ClusterVersion{
+				Version: "16.2.6-0",
+			},
+			CephStatus: &cephv1.CephStatus{
+				Health: "HEALTH_OK",
+			},
+		},
+	}
+
+	var commands []string
+	userExists := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "auth" {
+				commands = append(commands, strings.Join(args[0:3], " "))
+				switch args[1] {
+				case "get-key":
+					if !userExists {
+						return "", errors.New("user not found")
+					}
+					return `{"key":"mykey"}`, nil
+				case "get-or-create-key":
+					userExists = true
+					return `{"key":"mykey"}`, nil
+				}
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{
+		Executor:      executor,
+		Clientset:     testop.New(t, 1),
+		RookClientset: rookclient.NewSimpleClientset(),
+	}
+
+	// Mock clusterInfo
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-mon",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"fsid":         []byte(name),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	s := scheme.Scheme
+	object := []runtime.Object{staticVolume, cephCluster}
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(object...).Build()
+	c.Client = cl
+	r := &ReconcileCephFilesystemStaticVolume{
+		client:           cl,
+		scheme:           s,
+		context:          c,
+		opManagerContext: ctx,
+	}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// the user, its secret and the PV/PVC pair are created
+	res, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, []string{"auth get-key client.cephfs-static-legacy-data", "auth get-or-create-key client.cephfs-static-legacy-data"}, commands)
+	err = r.client.Get(ctx, req.NamespacedName, staticVolume)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.ConditionReady, staticVolume.Status.Phase)
+	assert.Equal(t, "app/legacy-data", staticVolume.Status.Info["persistentVolumeClaim"])
+
+	userSecret, err := c.Clientset.CoreV1().Secrets(namespace).Get(ctx, "rook-ceph-cephfs-static-legacy-data", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "cephfs-static-legacy-data", userSecret.StringData["userID"])
+	assert.Equal(t, "mykey", userSecret.StringData["userKey"])
+
+	pv, err := c.Clientset.CoreV1().PersistentVolumes().Get(ctx, "cephfs-static-rook-ceph-legacy-data", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, csi.CephFSDriverName, pv.Spec.CSI.Driver)
+	assert.Equal(t, pv.Name, pv.Spec.CSI.VolumeHandle)
+	assert.Equal(t, "/volumes/legacy", pv.Spec.CSI.VolumeAttributes["rootPath"])
+	assert.Equal(t, "true", pv.Spec.CSI.VolumeAttributes["staticVolume"])
+	assert.Equal(t, "rook-ceph-cephfs-static-legacy-data", pv.Spec.CSI.NodeStageSecretRef.Name)
+	assert.Equal(t, v1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+	assert.Equal(t, "app", pv.Spec.ClaimRef.Namespace)
+	assert.Equal(t, "legacy-data", pv.Spec.ClaimRef.Name)
+	capacity := pv.Spec.Capacity[v1.ResourceStorage]
+	assert.Equal(t, "10Gi", capacity.String())
+
+	pvc, err := c.Clientset.CoreV1().PersistentVolumeClaims("app").Get(ctx, "legacy-data", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, pv.Name, pvc.Spec.VolumeName)
+	assert.Equal(t, "", *pvc.Spec.StorageClassName)
+
+	// the caps of an existing user are updated
+	commands = nil
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"auth get-key client.cephfs-static-legacy-data", "auth caps client.cephfs-static-legacy-data"}, commands)
+
+	// the PV/PVC pair and the user are deleted
+	commands = nil
+	err = r.deleteStaticVolume(staticVolume)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"auth del client.cephfs-static-legacy-data"}, commands)
+	_, err = c.Clientset.CoreV1().PersistentVolumes().Get(ctx, pv.Name, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	_, err = c.Clientset.CoreV1().PersistentVolumeClaims("app").Get(ctx, "legacy-data", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
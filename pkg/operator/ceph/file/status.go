@@ -53,6 +53,28 @@ func (r *ReconcileCephFilesystem) updateStatus(client client.Client, namespacedN
 	logger.Debugf("filesystem %q status updated to %q", fs.Name, status)
 }
 
+// updateStatusHooks updates the status of the post-ready hooks of a fs CR
+func (r *ReconcileCephFilesystem) updateStatusHooks(namespacedName types.NamespacedName, hooks []cephv1.HookStatus) {
+	fs := &cephv1.CephFilesystem{}
+	if err := r.client.Get(r.opManagerContext, namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve filesystem %q to update the status of the hooks. %v", namespacedName, err)
+		return
+	}
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+	fs.Status.Hooks = hooks
+	if err := reporting.UpdateStatus(r.client, fs); err != nil {
+		logger.Warningf("failed to set the status of the hooks of filesystem %q. %v", fs.Name, err)
+		return
+	}
+	logger.Debugf("filesystem %q hooks status updated", fs.Name)
+}
+
 // updateStatusBucket updates an object with a given status
 func (c *mirrorChecker) updateStatusMirroring(mirrorStatus []cephv1.FilesystemMirroringInfo, snapSchedStatus []cephv1.FilesystemSnapshotSchedulesSpec, details string) {
 	fs := &cephv1.CephFilesystem{}
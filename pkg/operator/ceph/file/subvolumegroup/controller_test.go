@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subvolumegroup
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestPinningPolicy(t *testing.T) {
+	zero, one := 0, 1
+	half, tooLarge := 0.5, 1.5
+
+	// distributed by default
+	pinType, pinSetting, err := pinningPolicy(cephv1.SubVolumeGroupPinningSpec{})
+	assert.NoError(t, err)
+	assert.Equal(t, "distributed", pinType)
+	assert.Equal(t, "1", pinSetting)
+
+	pinType, pinSetting, err = pinningPolicy(cephv1.SubVolumeGroupPinningSpec{Distributed: &zero})
+	assert.NoError(t, err)
+	assert.Equal(t, "distributed", pinType)
+	assert.Equal(t, "0", pinSetting)
+
+	pinType, pinSetting, err = pinningPolicy(cephv1.SubVolumeGroupPinningSpec{Export: &one})
+	assert.NoError(t, err)
+	assert.Equal(t, "export", pinType)
+	assert.Equal(t, "1", pinSetting)
+
+	pinType, pinSetting, err = pinningPolicy(cephv1.SubVolumeGroupPinningSpec{Random: &half})
+	assert.NoError(t, err)
+	assert.Equal(t, "random", pinType)
+	assert.Equal(t, "0.5", pinSetting)
+
+	_, _, err = pinningPolicy(cephv1.SubVolumeGroupPinningSpec{Random: &tooLarge})
+	assert.Error(t, err)
+
+	// a single policy can be set
+	_, _, err = pinningPolicy(cephv1.SubVolumeGroupPinningSpec{Export: &one, Random: &half})
+	assert.Error(t, err)
+}
+
+func TestCephFilesystemSubVolumeGroupController(t *testing.T) {
+	ctx := context.TODO()
+	var (
+		name      = "group-a"
+		namespace = "rook-ceph"
+	)
+	half := 0.5
+	cephFilesystemSubVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cephv1.CephFilesystemSubVolumeGroupSpec{
+			FilesystemName: "myfs",
+			Pinning:        cephv1.SubVolumeGroupPinningSpec{Random: &half},
+		},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespace,
+			Namespace: namespace,
+		},
+		Status: cephv1.ClusterStatus{
+			Phase: cephv1.ConditionReady,
+			CephVersion: &cephv1.ClusterVersion{
+				Version: "16.2.6-0",
+			},
+			CephStatus: &cephv1.CephStatus{
+				Health: "HEALTH_OK",
+			},
+		},
+	}
+
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "fs" && args[1] == "subvolumegroup" {
+				// the arguments of the subvolumegroup command without the connection flags
+				for i, arg := range args {
+					if strings.HasPrefix(arg, "--") {
+						commands = append(commands, strings.Join(args[2:i], " "))
+						break
+					}
+				}
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{
+		Executor:      executor,
+		Clientset:     testop.New(t, 1),
+		RookClientset: rookclient.NewSimpleClientset(),
+	}
+
+	// Mock clusterInfo
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-mon",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"fsid":         []byte(name),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	s := scheme.Scheme
+	object := []runtime.Object{cephFilesystemSubVolumeGroup, cephCluster}
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(object...).Build()
+	c.Client = cl
+	r := &ReconcileCephFilesystemSubVolumeGroup{
+		client:           cl,
+		context:          c,
+		opManagerContext: ctx,
+	}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// the subvolume group is created and pinned
+	res, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, []string{"create myfs group-a", "pin myfs group-a random 0.5"}, commands)
+	err = r.client.Get(ctx, req.NamespacedName, cephFilesystemSubVolumeGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.ConditionReady, cephFilesystemSubVolumeGroup.Status.Phase)
+	assert.Equal(t, "random=0.5", cephFilesystemSubVolumeGroup.Status.Info["pinning"])
+
+	// an invalid pinning fails the reconcile
+	commands = nil
+	one := 1
+	cephFilesystemSubVolumeGroup.Spec.Pinning.Export = &one
+	err = r.client.Update(ctx, cephFilesystemSubVolumeGroup)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Empty(t, commands)
+	err = r.client.Get(ctx, req.NamespacedName, cephFilesystemSubVolumeGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.ConditionFailure, cephFilesystemSubVolumeGroup.Status.Phase)
+}
@@ -78,11 +78,13 @@ type ReconcileCephNFS struct {
 	clusterInfo      *cephclient.ClusterInfo
 	opManagerContext context.Context
 	opConfig         opcontroller.OperatorConfig
+	recorder         *k8sutil.EventReporter
 }
 
 // Add creates a new cephNFS Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
@@ -94,6 +96,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 		context:          context,
 		opManagerContext: opManagerContext,
 		opConfig:         opConfig,
+		recorder:         k8sutil.NewEventReporter(mgr.GetEventRecorderFor("rook-" + controllerName)),
 	}
 }
 
@@ -204,9 +207,18 @@ func (r *ReconcileCephNFS) reconcile(request reconcile.Request) (reconcile.Resul
 		}
 		r.clusterInfo.CephVersion = runningCephVersion
 
-		err = r.removeServersFromDatabase(cephNFS, 0)
-		if err != nil {
-			return reconcile.Result{}, errors.Wrapf(err, "failed to delete filesystem %q. ", cephNFS.Name)
+		// The orphaned servers stay in the database
+		if opcontroller.IsOrphanDeletionPolicy(cephNFS.Spec.DeletionPolicy) {
+			orphaned, err := opcontroller.OrphanGeneratedResources(r.opManagerContext, r.client, cephNFS)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to orphan the resources of ceph nfs %q", cephNFS.Name)
+			}
+			reporting.ReportResourcesOrphaned(logger, r.client, r.recorder, cephNFS, orphaned)
+		} else {
+			err = r.removeServersFromDatabase(cephNFS, 0)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to delete filesystem %q. ", cephNFS.Name)
+			}
 		}
 
 		// Remove finalizer
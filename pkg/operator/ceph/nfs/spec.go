@@ -135,6 +135,8 @@ func (r *ReconcileCephNFS) makeDeployment(nfs *cephv1.CephNFS, cfg daemonConfig)
 	if r.cephClusterSpec.Network.IsHost() {
 		podSpec.DNSPolicy = v1.DNSClusterFirstWithHostNet
 	}
+	cephv1.GetDaemonDNS(r.cephClusterSpec.DNS, cephv1.KeyNFS).ApplyToPodSpec(&podSpec)
+	cephv1.GetDaemonContainerOverrides(r.cephClusterSpec.ContainerOverrides, cephv1.KeyNFS).ApplyToPodSpec(&podSpec)
 	nfs.Spec.Server.Placement.ApplyToPodSpec(&podSpec)
 
 	podTemplateSpec := v1.PodTemplateSpec{
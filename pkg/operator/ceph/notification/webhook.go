@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notification sends the critical events of a cluster to external sinks.
+package notification
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", "op-notification")
+
+// Severity is the severity of a notification
+type Severity string
+
+// EventType is the type of event notified
+type EventType string
+
+const (
+	// SeverityInfo is the severity of the events reporting a recovery
+	SeverityInfo Severity = "info"
+	// SeverityWarning is the severity of the events reporting a degraded cluster
+	SeverityWarning Severity = "warning"
+	// SeverityCritical is the severity of the events requiring an immediate action
+	SeverityCritical Severity = "critical"
+
+	// HealthChanged is the event of a change of the ceph health
+	HealthChanged EventType = "HealthChanged"
+	// QuorumLost is the event of the loss of the mon quorum
+	QuorumLost EventType = "QuorumLost"
+	// QuorumRestored is the event of the mon quorum being restored
+	QuorumRestored EventType = "QuorumRestored"
+	// UpgradeFailed is the event of a failed upgrade of the cluster
+	UpgradeFailed EventType = "UpgradeFailed"
+
+	// WebhookSecretKey is the key of the secret signing the webhook notifications
+	WebhookSecretKey = "secret"
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the notification body
+	SignatureHeader = "X-Rook-Signature"
+)
+
+var (
+	severityLevels = map[Severity]int{SeverityInfo: 0, SeverityWarning: 1, SeverityCritical: 2}
+
+	// the client posting the notifications, a slow webhook must not block the health checks for long
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// Event is the body of a notification
+type Event struct {
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace"`
+	Type      EventType `json:"type"`
+	Severity  Severity  `json:"severity"`
+	Message   string    `json:"message"`
+	Timestamp string    `json:"timestamp"`
+}
+
+// HealthSeverity returns the severity of a transition to the given ceph health
+func HealthSeverity(health string) Severity {
+	switch health {
+	case "HEALTH_OK":
+		return SeverityInfo
+	case "HEALTH_WARN":
+		return SeverityWarning
+	default:
+		return SeverityCritical
+	}
+}
+
+// Notify sends the event to the notification sinks of the cluster. The events less severe than the
+// severity filter of a sink are not sent.
+func Notify(context *clusterd.Context, cephCluster *cephv1.CephCluster, eventType EventType, severity Severity, message string) error {
+	webhook := cephCluster.Spec.Notifications.Webhook
+	if webhook == nil {
+		return nil
+	}
+	minSeverity := SeverityCritical
+	if webhook.Severity != "" {
+		minSeverity = Severity(webhook.Severity)
+	}
+	if severityLevels[severity] < severityLevels[minSeverity] {
+		logger.Debugf("not sending %s notification %q of severity %q below %q", eventType, message, severity, minSeverity)
+		return nil
+	}
+
+	event := Event{
+		Cluster:   cephCluster.Name,
+		Namespace: cephCluster.Namespace,
+		Type:      eventType,
+		Severity:  severity,
+		Message:   message,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notification")
+	}
+
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if webhook.SecretName != "" {
+		secret, err := context.Clientset.CoreV1().Secrets(cephCluster.Namespace).Get(request.Context(), webhook.SecretName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get webhook secret %q", webhook.SecretName)
+		}
+		key, ok := secret.Data[WebhookSecretKey]
+		if !ok {
+			return errors.Errorf("webhook secret %q has no %q key", webhook.SecretName, WebhookSecretKey)
+		}
+		request.Header.Set(SignatureHeader, "sha256="+sign(key, body))
+	}
+
+	logger.Infof("sending %s notification of cluster %q to the webhook", eventType, cephCluster.Name)
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to send notification to the webhook")
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return errors.Errorf("webhook rejected the notification with status %q", response.Status)
+	}
+	return nil
+}
+
+// sign returns the hex encoded HMAC-SHA256 of the body
+func sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
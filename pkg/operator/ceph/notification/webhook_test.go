@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNotify(t *testing.T) {
+	var received []Event
+	var signature string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		event := Event{}
+		require.NoError(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+		signature = r.Header.Get(SignatureHeader)
+		if signature != "" {
+			assert.Equal(t, "sha256="+sign([]byte("mykey"), body), signature)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	clusterdContext := &clusterd.Context{Clientset: test.New(t, 1)}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook", Namespace: "ns"}}
+
+	// no sink
+	err := Notify(clusterdContext, cephCluster, QuorumLost, SeverityCritical, "quorum lost")
+	assert.NoError(t, err)
+	assert.Empty(t, received)
+
+	// the critical events are sent by default
+	cephCluster.Spec.Notifications.Webhook = &cephv1.WebhookNotificationSpec{URL: server.URL}
+	err = Notify(clusterdContext, cephCluster, HealthChanged, SeverityWarning, "health is HEALTH_WARN")
+	assert.NoError(t, err)
+	assert.Empty(t, received)
+	err = Notify(clusterdContext, cephCluster, QuorumLost, SeverityCritical, "quorum lost")
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(received))
+	assert.Equal(t, Event{Cluster: "rook", Namespace: "ns", Type: QuorumLost, Severity: SeverityCritical, Message: "quorum lost", Timestamp: received[0].Timestamp}, received[0])
+	assert.Empty(t, signature)
+
+	// the severity filter is lowered and the notifications are signed
+	cephCluster.Spec.Notifications.Webhook.Severity = "info"
+	cephCluster.Spec.Notifications.Webhook.SecretName = "webhook-secret"
+	err = Notify(clusterdContext, cephCluster, QuorumRestored, SeverityInfo, "quorum restored")
+	assert.Error(t, err)
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "webhook-secret", Namespace: "ns"}, Data: map[string][]byte{WebhookSecretKey: []byte("mykey")}}
+	_, err = clusterdContext.Clientset.CoreV1().Secrets("ns").Create(context.TODO(), secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+	err = Notify(clusterdContext, cephCluster, QuorumRestored, SeverityInfo, "quorum restored")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(received))
+	assert.NotEmpty(t, signature)
+
+	// the webhook rejects the notification
+	status = http.StatusInternalServerError
+	err = Notify(clusterdContext, cephCluster, UpgradeFailed, SeverityCritical, "upgrade failed")
+	assert.Error(t, err)
+}
+
+func TestHealthSeverity(t *testing.T) {
+	assert.Equal(t, SeverityInfo, HealthSeverity("HEALTH_OK"))
+	assert.Equal(t, SeverityWarning, HealthSeverity("HEALTH_WARN"))
+	assert.Equal(t, SeverityCritical, HealthSeverity("HEALTH_ERR"))
+}
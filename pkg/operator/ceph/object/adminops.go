@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/pkg/errors"
+)
+
+// The admin ops API calls of this file are not implemented by the go-ceph client yet. They are signed like the
+// calls of the go-ceph client.
+
+// callAdminOps sends a request to the admin ops API with the endpoint, the credentials and the http client of the
+// go-ceph client. The path may already start the query, such as "/user?caps".
+func callAdminOps(ctx context.Context, api *admin.API, method, path string, args url.Values) ([]byte, error) {
+	args.Set("format", "json")
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	requestURL := fmt.Sprintf("%s/admin%s%s%s", api.Endpoint, path, separator, args.Encode())
+	body, err := SendSignedRequest(ctx, api.HTTPClient, api.AccessKey, api.SecretKey, method, requestURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "admin ops request %s %q failed", method, path)
+	}
+	return body, nil
+}
+
+// SendSignedRequest sends a request to the rgw signed with the keys of an object store user and returns the body of
+// the response. The http client is usually the client of the admin ops API, which trusts the object store.
+func SendSignedRequest(ctx context.Context, httpClient admin.HTTPClient, accessKey, secretKey, method, requestURL string, body []byte) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+
+	signer := v4.NewSigner(credentials.NewStaticCredentials(accessKey, secretKey, ""))
+	if _, err := signer.Sign(request, bytes.NewReader(body), "s3", "default", time.Now()); err != nil {
+		return nil, errors.Wrap(err, "failed to sign request")
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response")
+	}
+	if response.StatusCode >= 300 {
+		var status struct {
+			Code string `json:"Code"`
+		}
+		if err := json.Unmarshal(responseBody, &status); err == nil && status.Code != "" {
+			return nil, errors.Errorf("request failed with code %q", status.Code)
+		}
+		return nil, errors.Errorf("request failed with status %d. %s", response.StatusCode, string(responseBody))
+	}
+	return responseBody, nil
+}
+
+// AddUserCaps adds admin capabilities to a user, such as "users=read;buckets=*"
+func AddUserCaps(ctx context.Context, api *admin.API, uid, caps string) error {
+	args := url.Values{"uid": {uid}, "user-caps": {caps}}
+	if _, err := callAdminOps(ctx, api, http.MethodPut, "/user?caps", args); err != nil {
+		return errors.Wrapf(err, "failed to add caps %q to user %q", caps, uid)
+	}
+	return nil
+}
+
+// RemoveUserCaps removes admin capabilities from a user
+func RemoveUserCaps(ctx context.Context, api *admin.API, uid, caps string) error {
+	args := url.Values{"uid": {uid}, "user-caps": {caps}}
+	if _, err := callAdminOps(ctx, api, http.MethodDelete, "/user?caps", args); err != nil {
+		return errors.Wrapf(err, "failed to remove caps %q from user %q", caps, uid)
+	}
+	return nil
+}
+
+// SetBucketQuota sets the quota of each bucket of a user. A negative limit disables the limit.
+func SetBucketQuota(ctx context.Context, api *admin.API, uid string, enabled bool, maxSize, maxObjects int64) error {
+	args := url.Values{
+		"uid":         {uid},
+		"quota-type":  {"bucket"},
+		"enabled":     {strconv.FormatBool(enabled)},
+		"max-size":    {strconv.FormatInt(maxSize, 10)},
+		"max-objects": {strconv.FormatInt(maxObjects, 10)},
+	}
+	if _, err := callAdminOps(ctx, api, http.MethodPut, "/user?quota", args); err != nil {
+		return errors.Wrapf(err, "failed to set the bucket quota of user %q", uid)
+	}
+	return nil
+}
+
+// CreateUserKey generates a new S3 key for a user and returns all the S3 keys of the user
+func CreateUserKey(ctx context.Context, api *admin.API, uid string) ([]admin.UserKeySpec, error) {
+	args := url.Values{"uid": {uid}, "key-type": {"s3"}, "generate-key": {"true"}}
+	body, err := callAdminOps(ctx, api, http.MethodPut, "/user?key", args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create a key for user %q", uid)
+	}
+	var keys []admin.UserKeySpec
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the keys of user %q. %s", uid, string(body))
+	}
+	return keys, nil
+}
+
+// RemoveUserKey removes an S3 key of a user
+func RemoveUserKey(ctx context.Context, api *admin.API, uid, accessKey string) error {
+	args := url.Values{"uid": {uid}, "key-type": {"s3"}, "access-key": {accessKey}}
+	if _, err := callAdminOps(ctx, api, http.MethodDelete, "/user?key", args); err != nil {
+		return errors.Wrapf(err, "failed to remove key %q of user %q", accessKey, uid)
+	}
+	return nil
+}
@@ -194,3 +194,31 @@ func GetBucket(c *Context, bucket string) (*ObjectBucket, int, error) {
 
 	return &ObjectBucket{Name: bucket, ObjectBucketMetadata: ObjectBucketMetadata{Owner: metadata.Owner, CreatedAt: metadata.CreatedAt}, ObjectBucketStats: *stat}, RGWErrorNone, nil
 }
+
+// LinkBucket makes the user the owner of the bucket
+func LinkBucket(c *Context, bucketName, userID string) error {
+	result, err := runAdminCommand(c,
+		false,
+		"bucket",
+		"link",
+		"--bucket", bucketName,
+		"--uid", userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to link bucket %q to user %q. %s", bucketName, userID, result)
+	}
+	return nil
+}
+
+// ChownBucket makes the user the owner of the objects of the bucket, after the bucket was linked to the user
+func ChownBucket(c *Context, bucketName, userID string) error {
+	result, err := runAdminCommand(c,
+		false,
+		"bucket",
+		"chown",
+		"--bucket", bucketName,
+		"--uid", userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to change the owner of the objects of bucket %q to user %q. %s", bucketName, userID, result)
+	}
+	return nil
+}
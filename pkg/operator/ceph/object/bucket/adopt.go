@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/pkg/errors"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+)
+
+const (
+	// BucketAdoptPolicyFail fails the provisioning if the bucket already exists, this is the default
+	BucketAdoptPolicyFail = "Fail"
+	// BucketAdoptPolicyAdopt transfers an existing bucket to the user of the OBC instead of creating it
+	BucketAdoptPolicyAdopt = "Adopt"
+
+	bucketAdoptPolicyKey = "bucketAdoptPolicy"
+	bucketOwnerKey       = "bucketOwner"
+)
+
+// BucketAdoptPolicy returns the adopt policy of the additional config of an OBC
+func BucketAdoptPolicy(additionalConfig map[string]string) (string, error) {
+	policy, ok := additionalConfig[bucketAdoptPolicyKey]
+	if !ok || policy == "" {
+		return BucketAdoptPolicyFail, nil
+	}
+	if policy != BucketAdoptPolicyFail && policy != BucketAdoptPolicyAdopt {
+		return "", errors.Errorf("invalid %s %q, must be %q or %q", bucketAdoptPolicyKey, policy, BucketAdoptPolicyFail, BucketAdoptPolicyAdopt)
+	}
+	return policy, nil
+}
+
+// bucketToAdopt returns the owner of the existing bucket to adopt, or empty if the bucket must be created.
+// The ownership of the bucket is verified against the owner expected in the additional config of the OBC,
+// so that an OBC cannot take over a bucket of another user by its name.
+func (p *Provisioner) bucketToAdopt(additionalConfig map[string]string) (string, error) {
+	policy, err := BucketAdoptPolicy(additionalConfig)
+	if err != nil {
+		return "", err
+	}
+	if policy != BucketAdoptPolicyAdopt {
+		return "", nil
+	}
+
+	bucket, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: p.adminBucketName()})
+	if err != nil {
+		if errors.Is(err, admin.ErrNoSuchBucket) {
+			logger.Infof("bucket %q does not exist yet, creating it instead of adopting it", p.bucketName)
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get bucket %q", p.bucketName)
+	}
+
+	expectedOwner := additionalConfig[bucketOwnerKey]
+	if expectedOwner == "" {
+		return "", errors.Errorf("the current owner of bucket %q must be set with %q to adopt it", p.bucketName, bucketOwnerKey)
+	}
+	if bucket.Owner != expectedOwner {
+		return "", errors.Errorf("bucket %q is owned by %q, not by %q", p.bucketName, bucket.Owner, expectedOwner)
+	}
+	return bucket.Owner, nil
+}
+
+// adoptBucket makes the user of the OBC the owner of the existing bucket and of its objects
+func (p *Provisioner) adoptBucket(previousOwner string) error {
+	logger.Infof("adopting bucket %q of user %q for user %q", p.bucketName, previousOwner, p.cephUserName)
+	if err := cephObject.LinkBucket(p.objectContext, p.adminBucketName(), p.cephUserName); err != nil {
+		return err
+	}
+	// the objects keep their owner if the command fails or times out on a large bucket, which only
+	// restricts the access of the new owner to the objects with a private acl
+	if err := cephObject.ChownBucket(p.objectContext, p.adminBucketName(), p.cephUserName); err != nil {
+		logger.Warningf("failed to change the owner of the objects of adopted bucket %q, run `radosgw-admin bucket chown --bucket %s --uid %s` to complete the adoption. %v", p.bucketName, p.adminBucketName(), p.cephUserName, err)
+	}
+	logger.Infof("adopted bucket %q", p.bucketName)
+	return nil
+}
+
+// releaseBucket gives the adopted bucket back to its previous owner after a failed provisioning
+func (p *Provisioner) releaseBucket(previousOwner string) {
+	if err := cephObject.LinkBucket(p.objectContext, p.adminBucketName(), previousOwner); err != nil {
+		logger.Errorf("failed to give bucket %q back to user %q. %v", p.bucketName, previousOwner, err)
+		return
+	}
+	if err := cephObject.ChownBucket(p.objectContext, p.adminBucketName(), previousOwner); err != nil {
+		logger.Warningf("failed to give the objects of bucket %q back to user %q. %v", p.bucketName, previousOwner, err)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketAdoptPolicy(t *testing.T) {
+	policy, err := BucketAdoptPolicy(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, BucketAdoptPolicyFail, policy)
+
+	policy, err = BucketAdoptPolicy(map[string]string{"bucketAdoptPolicy": "Adopt"})
+	assert.NoError(t, err)
+	assert.Equal(t, BucketAdoptPolicyAdopt, policy)
+
+	_, err = BucketAdoptPolicy(map[string]string{"bucketAdoptPolicy": "Steal"})
+	assert.Error(t, err)
+}
+
+func TestBucketToAdopt(t *testing.T) {
+	bucketExists := true
+	mockClient := &object.MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			if !bucketExists {
+				return &http.Response{
+					StatusCode: 404,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"Code":"NoSuchBucket"}`))),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"bucket":"legacy-bucket","owner":"legacy-user"}`))),
+			}, nil
+		},
+	}
+	adminClient, err := admin.New("rgw.test", "accesskey", "secretkey", mockClient)
+	require.NoError(t, err)
+	p := NewProvisioner(&clusterd.Context{}, client.AdminClusterInfo("ns"))
+	p.adminOpsClient = adminClient
+	p.setBucketName("legacy-bucket")
+
+	// the bucket is created when the adoption is not requested
+	owner, err := p.bucketToAdopt(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", owner)
+
+	// the owner of the bucket is required
+	owner, err = p.bucketToAdopt(map[string]string{"bucketAdoptPolicy": "Adopt"})
+	assert.Error(t, err)
+	assert.Equal(t, "", owner)
+
+	// the owner of the bucket must match
+	_, err = p.bucketToAdopt(map[string]string{"bucketAdoptPolicy": "Adopt", "bucketOwner": "someone-else"})
+	assert.Error(t, err)
+
+	owner, err = p.bucketToAdopt(map[string]string{"bucketAdoptPolicy": "Adopt", "bucketOwner": "legacy-user"})
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy-user", owner)
+
+	// a missing bucket is created
+	bucketExists = false
+	owner, err = p.bucketToAdopt(map[string]string{"bucketAdoptPolicy": "Adopt", "bucketOwner": "legacy-user"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", owner)
+}
@@ -32,6 +32,7 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -49,11 +50,20 @@ type ReconcileBucket struct {
 	clusterInfo      *cephclient.ClusterInfo
 	opConfig         opcontroller.OperatorConfig
 	opManagerContext context.Context
+	// provisioners are the running bucket provisioners by CephCluster. A provisioner runs its own
+	// clients and informers, so it is only started once for a cluster and stopped with the cluster.
+	provisioners map[types.NamespacedName]*provisionerContext
+}
+
+type provisionerContext struct {
+	internalCtx    context.Context
+	internalCancel context.CancelFunc
 }
 
 // Add creates a new Ceph CSI Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
@@ -64,6 +74,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 		context:          context,
 		opConfig:         opConfig,
 		opManagerContext: opManagerContext,
+		provisioners:     make(map[types.NamespacedName]*provisionerContext),
 	}
 }
 
@@ -112,6 +123,7 @@ func (r *ReconcileBucket) reconcile(request reconcile.Request) (reconcile.Result
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Infof("no ceph cluster found in %+v. not deploying the bucket provisioner", request.NamespacedName)
+			r.stopProvisioner(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -120,11 +132,19 @@ func (r *ReconcileBucket) reconcile(request reconcile.Request) (reconcile.Result
 
 	if !cephCluster.DeletionTimestamp.IsZero() {
 		logger.Debug("ceph cluster is being deleted, no need to reconcile the bucket provisioner")
+		r.stopProvisioner(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 
 	if !cephCluster.Spec.External.Enable && cephCluster.Spec.CleanupPolicy.HasDataDirCleanPolicy() {
 		logger.Debug("ceph cluster has cleanup policy, the cluster will soon go away, no need to reconcile the bucket provisioner")
+		r.stopProvisioner(request.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	// The provisioner is running until the cluster is deleted, the operator config changes reload the manager
+	if running, ok := r.provisioners[request.NamespacedName]; ok && running.internalCtx.Err() == nil {
+		logger.Debugf("bucket provisioner of ceph cluster %q already running", request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 
@@ -168,12 +188,16 @@ func (r *ReconcileBucket) reconcile(request reconcile.Request) (reconcile.Result
 	bucketController, _ := NewBucketController(r.context.KubeConfig, bucketProvisioner, r.opConfig.Parameters)
 
 	// We must run this in a go routine since RunWithContext() blocks and waits for the context to
-	// be Done. However, since it has a context, the go routine will exit on reload with SIGHUP
-	errChan := make(chan error)
+	// be Done. However, since it has a context, the go routine will exit on reload with SIGHUP or
+	// when the cluster is deleted. A provisioner that fails is started again by the next reconcile.
+	internalCtx, internalCancel := context.WithCancel(r.opManagerContext)
+	r.provisioners[request.NamespacedName] = &provisionerContext{internalCtx: internalCtx, internalCancel: internalCancel}
+	errChan := make(chan error, 1)
 	go func() {
-		err = bucketController.RunWithContext(r.opManagerContext)
+		err = bucketController.RunWithContext(internalCtx)
 		if err != nil {
 			logger.Errorf("failed to run bucket controller. %v", err)
+			internalCancel()
 			errChan <- err
 		}
 	}()
@@ -187,3 +211,14 @@ func (r *ReconcileBucket) reconcile(request reconcile.Request) (reconcile.Result
 		return reconcile.Result{}, nil
 	}
 }
+
+// stopProvisioner stops the bucket provisioner of the cluster, releasing its clients and informers
+func (r *ReconcileBucket) stopProvisioner(name types.NamespacedName) {
+	running, ok := r.provisioners[name]
+	if !ok {
+		return
+	}
+	logger.Infof("stopping the bucket provisioner of ceph cluster %q", name)
+	running.internalCancel()
+	delete(r.provisioners, name)
+}
@@ -77,6 +77,7 @@ func TestCephBucketController(t *testing.T) {
 				ServiceAccount:    "foo",
 			},
 			opManagerContext: context.TODO(),
+			provisioners:     make(map[types.NamespacedName]*provisionerContext),
 		}
 		ctx := context.TODO()
 		res, err := r.Reconcile(ctx, req)
@@ -151,6 +152,7 @@ func TestCephBucketController(t *testing.T) {
 				ServiceAccount:    "foo",
 			},
 			opManagerContext: ctx,
+			provisioners:     make(map[types.NamespacedName]*provisionerContext),
 		}
 
 		// Mock clusterInfo
@@ -173,9 +175,26 @@ func TestCephBucketController(t *testing.T) {
 		res, err := r.Reconcile(ctx, req)
 		assert.NoError(t, err)
 		assert.False(t, res.Requeue)
+		running, ok := r.provisioners[req.NamespacedName]
+		assert.True(t, ok)
+
+		// the provisioner is only started once for the cluster
+		res, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+		assert.Equal(t, running, r.provisioners[req.NamespacedName])
 
 		// wait a few seconds for the manager to start
 		time.Sleep(2 * time.Second)
+
+		// the provisioner is stopped with the cluster
+		err = cl.Delete(ctx, cephCluster)
+		assert.NoError(t, err)
+		res, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+		assert.Error(t, running.internalCtx.Err())
+		assert.Empty(t, r.provisioners)
 		cancel()
 	})
 }
@@ -65,7 +65,9 @@ func predicateController() predicate.Funcs {
 		},
 
 		DeleteFunc: func(e event.DeleteEvent) bool {
-			return false
+			// the bucket provisioner of a deleted cluster is stopped
+			_, ok := e.Object.(*cephv1.CephCluster)
+			return ok
 		},
 
 		GenericFunc: func(e event.GenericEvent) bool {
@@ -47,6 +47,8 @@ type Provisioner struct {
 	storeDomainName string
 	storePort       int32
 	region          string
+	// tenant is the rgw tenant of the user and of the bucket, empty without tenant
+	tenant string
 	// access keys for acct for the bucket *owner*
 	cephUserName         string
 	accessKeyID          string
@@ -75,31 +77,55 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 	}
 	logger.Infof("Provision: creating bucket %q for OBC %q", p.bucketName, options.ObjectBucketClaim.Name)
 
+	// an existing bucket is adopted instead of being created if requested by the OBC
+	previousOwner, err := p.bucketToAdopt(options.ObjectBucketClaim.Spec.AdditionalConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Provision: can't adopt bucket %q", p.bucketName)
+	}
+
 	// dynamically create a new ceph user
 	p.accessKeyID, p.secretAccessKey, err = p.createCephUser("")
 	if err != nil {
 		return nil, errors.Wrap(err, "Provision: can't create ceph user")
 	}
 
-	s3svc, err := cephObject.NewS3Agent(p.accessKeyID, p.secretAccessKey, p.getObjectStoreEndpoint(), p.region, logger.LevelAt(capnslog.DEBUG), p.tlsCert)
-	if err != nil {
-		p.deleteOBCResourceLogError("")
-		return nil, err
+	// the adopted bucket is given back to its owner instead of being deleted on failure
+	cleanup := func() {
+		if previousOwner != "" {
+			p.releaseBucket(previousOwner)
+			p.deleteOBCResourceLogError("")
+			return
+		}
+		p.deleteOBCResourceLogError(p.bucketName)
 	}
 
-	// create the bucket
-	err = s3svc.CreateBucket(p.bucketName)
-	if err != nil {
-		err = errors.Wrapf(err, "error creating bucket %q", p.bucketName)
-		logger.Errorf(err.Error())
-		p.deleteOBCResourceLogError("")
-		return nil, err
+	if previousOwner != "" {
+		err = p.adoptBucket(previousOwner)
+		if err != nil {
+			p.deleteOBCResourceLogError("")
+			return nil, err
+		}
+	} else {
+		s3svc, err := cephObject.NewS3Agent(p.accessKeyID, p.secretAccessKey, p.getObjectStoreEndpoint(), p.region, logger.LevelAt(capnslog.DEBUG), p.tlsCert)
+		if err != nil {
+			p.deleteOBCResourceLogError("")
+			return nil, err
+		}
+
+		// create the bucket
+		err = s3svc.CreateBucket(p.bucketName)
+		if err != nil {
+			err = errors.Wrapf(err, "error creating bucket %q", p.bucketName)
+			logger.Errorf(err.Error())
+			p.deleteOBCResourceLogError("")
+			return nil, err
+		}
 	}
 
 	singleBucketQuota := 1
 	_, err = p.adminOpsClient.ModifyUser(p.clusterInfo.Context, admin.User{ID: p.cephUserName, MaxBuckets: &singleBucketQuota})
 	if err != nil {
-		p.deleteOBCResourceLogError(p.bucketName)
+		cleanup()
 		return nil, err
 	}
 	logger.Infof("set user %q bucket max to %d", p.cephUserName, singleBucketQuota)
@@ -107,7 +133,7 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 	// setting quota limit if it is enabled
 	err = p.setAdditionalSettings(options)
 	if err != nil {
-		p.deleteOBCResourceLogError(p.bucketName)
+		cleanup()
 		return nil, err
 	}
 
@@ -128,7 +154,7 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 
 	// check and make sure the bucket exists
 	logger.Infof("Checking for existing bucket %q", p.bucketName)
-	if exists, err := p.bucketExists(p.bucketName); !exists {
+	if exists, err := p.bucketExists(p.adminBucketName()); !exists {
 		return nil, errors.Wrapf(err, "bucket %s does not exist", p.bucketName)
 	}
 
@@ -146,7 +172,7 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 	}
 
 	// get the bucket's owner via the bucket metadata
-	stats, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: p.bucketName})
+	stats, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: p.adminBucketName()})
 	if err != nil {
 		p.deleteOBCResourceLogError("")
 		return nil, errors.Wrapf(err, "failed to get bucket %q stats", p.bucketName)
@@ -235,7 +261,7 @@ func (p Provisioner) Revoke(ob *bktv1alpha1.ObjectBucket) error {
 	}
 	logger.Infof("Revoke: denying access to bucket %q for OB %q", p.bucketName, ob.Name)
 
-	bucket, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: p.bucketName})
+	bucket, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: p.adminBucketName()})
 	if err != nil {
 		logger.Errorf("%v", err)
 	} else {
@@ -338,6 +364,9 @@ func (p *Provisioner) initializeCreateOrGrant(options *apibkt.BucketOptions) err
 	p.setObjectStoreName(sc)
 	p.setRegion(sc)
 	p.setAdditionalConfigData(obc.Spec.AdditionalConfig)
+	if err := p.setTenant(obc.Spec.AdditionalConfig); err != nil {
+		return errors.Wrapf(err, "invalid tenant for OBC %q in namespace %q", obc.Name, obc.Namespace)
+	}
 	p.setEndpoint(sc)
 	err = p.setObjectContext()
 	if err != nil {
@@ -378,6 +407,8 @@ func (p *Provisioner) initializeDeleteOrRevoke(ob *bktv1alpha1.ObjectBucket) err
 	// set receiver fields from OB data
 	p.setBucketName(getBucketName(ob))
 	p.cephUserName = getCephUser(ob)
+	// the user of a tenant was created with the tenant in its id
+	p.tenant = cephObject.UserTenant(p.cephUserName)
 	p.objectStoreName = getObjectStoreName(sc)
 	p.setEndpoint(sc)
 	err = p.setObjectContext()
@@ -506,6 +537,20 @@ func (p *Provisioner) setAdditionalConfigData(additionalConfigData map[string]st
 	p.additionalConfigData = additionalConfigData
 }
 
+// setTenant sets the rgw tenant requested in the additional config of the OBC
+func (p *Provisioner) setTenant(additionalConfig map[string]string) error {
+	p.tenant = Tenant(additionalConfig)
+	if p.tenant == "" {
+		return nil
+	}
+	return cephObject.ValidateTenantName(p.tenant)
+}
+
+// adminBucketName returns the name of the bucket qualified with its tenant for the admin ops api
+func (p *Provisioner) adminBucketName() string {
+	return cephObject.TenantBucketName(p.tenant, p.bucketName)
+}
+
 func (p *Provisioner) setEndpoint(sc *storagev1.StorageClass) {
 	p.endpoint = sc.Parameters[objectStoreEndpoint]
 }
@@ -17,10 +17,15 @@ limitations under the License.
 package bucket
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/ceph/go-ceph/rgw/admin"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/clusterd"
@@ -28,6 +33,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -132,3 +138,50 @@ func TestMaxSizeToInt64(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateCephUserInTenant(t *testing.T) {
+	var createdUID string
+	mockClient := &object.MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			uid := req.URL.Query().Get("uid")
+			if req.Method == http.MethodGet {
+				return &http.Response{
+					StatusCode: 404,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"Code":"NoSuchUser"}`))),
+				}, nil
+			}
+			createdUID = uid
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(fmt.Sprintf(`{"user_id":%q,"keys":[{"user":%q,"access_key":"access","secret_key":"secret"}]}`, uid, uid)))),
+			}, nil
+		},
+	}
+	adminClient, err := admin.New("rgw.test", "accesskey", "secretkey", mockClient)
+	require.NoError(t, err)
+	p := NewProvisioner(&clusterd.Context{}, client.AdminClusterInfo("ns"))
+	p.adminOpsClient = adminClient
+
+	// the tenant must be a valid rgw tenant
+	assert.Error(t, p.setTenant(map[string]string{"tenant": "team-a"}))
+	require.NoError(t, p.setTenant(map[string]string{"tenant": "team_a"}))
+	p.setBucketName("photos")
+	assert.Equal(t, "team_a/photos", p.adminBucketName())
+
+	// the generated user is created in the tenant
+	accessKey, secretKey, err := p.createCephUser("")
+	require.NoError(t, err)
+	assert.Equal(t, "access", accessKey)
+	assert.Equal(t, "secret", secretKey)
+	assert.True(t, strings.HasPrefix(p.cephUserName, "team_a$ceph-user-"), p.cephUserName)
+	assert.Equal(t, p.cephUserName, createdUID)
+	assert.Equal(t, "team_a", object.UserTenant(p.cephUserName))
+
+	// without tenant
+	require.NoError(t, p.setTenant(map[string]string{}))
+	assert.Equal(t, "photos", p.adminBucketName())
+	_, _, err = p.createCephUser("")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(p.cephUserName, "ceph-user-"), p.cephUserName)
+	assert.Equal(t, "", object.UserTenant(p.cephUserName))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ceph/go-ceph/rgw/admin"
 	"github.com/pkg/errors"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
 )
 
 func (p *Provisioner) bucketExists(name string) (bool, error) {
@@ -40,12 +41,13 @@ func (p *Provisioner) createCephUser(username string) (accKey string, secKey str
 			return "", "", errors.Wrap(err, "no user name provided and unable to generate a unique name")
 		}
 	}
-	p.cephUserName = username
+	// the user of a tenant is created with the tenant in its id, the keys are generated by rgw in both cases
+	p.cephUserName = cephObject.TenantUserID(p.tenant, username)
 
-	logger.Infof("creating Ceph user %q", username)
+	logger.Infof("creating Ceph user %q", p.cephUserName)
 	userConfig := admin.User{
-		ID:          username,
-		DisplayName: p.cephUserName,
+		ID:          p.cephUserName,
+		DisplayName: username,
 	}
 
 	var u admin.User
@@ -57,11 +59,11 @@ func (p *Provisioner) createCephUser(username string) (accKey string, secKey str
 				return "", "", errors.Wrapf(err, "failed to create ceph object user %v", userConfig.ID)
 			}
 		} else {
-			return "", "", errors.Wrapf(err, "failed to get ceph user %q", username)
+			return "", "", errors.Wrapf(err, "failed to get ceph user %q", p.cephUserName)
 		}
 	}
 
-	logger.Infof("successfully created Ceph user %q with access keys", username)
+	logger.Infof("successfully created Ceph user %q with access keys", p.cephUserName)
 	return u.Keys[0].AccessKey, u.Keys[0].SecretKey, nil
 }
 
@@ -78,7 +80,7 @@ func (p *Provisioner) genUserName() (genName string, err error) {
 	// when notUnique == true, the loop breaks and `name` contains the latest generated name
 	for i := 0; notUnique && i < maxTries; i++ {
 		genName = fmt.Sprintf("%s-%s", prefix, randomString(genUserLen))
-		if notUnique, err = p.userExists(genName); err != nil {
+		if notUnique, err = p.userExists(cephObject.TenantUserID(p.tenant, genName)); err != nil {
 			return "", err
 		}
 	}
@@ -95,7 +97,7 @@ func (p *Provisioner) deleteOBCResource(bucketName string) error {
 	if len(bucketName) > 0 {
 		// delete bucket with purge option to remove all objects
 		thePurge := true
-		err := p.adminOpsClient.RemoveBucket(p.clusterInfo.Context, admin.Bucket{Bucket: bucketName, PurgeObject: &thePurge})
+		err := p.adminOpsClient.RemoveBucket(p.clusterInfo.Context, admin.Bucket{Bucket: cephObject.TenantBucketName(p.tenant, bucketName), PurgeObject: &thePurge})
 		if err == nil {
 			logger.Infof("bucket %q successfully deleted", p.bucketName)
 		} else if errors.Is(err, admin.ErrNoSuchBucket) {
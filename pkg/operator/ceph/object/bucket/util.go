@@ -121,3 +121,8 @@ func MaxObjectQuota(AdditionalConfig map[string]string) string {
 func MaxSizeQuota(AdditionalConfig map[string]string) string {
 	return AdditionalConfig["maxSize"]
 }
+
+// Tenant returns the rgw tenant of the user and bucket of the OBC, empty without tenant
+func Tenant(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["tenant"]
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
 	v1 "k8s.io/api/core/v1"
@@ -54,6 +55,9 @@ caps osd = "allow rwx"
 	HttpTimeOut                     = time.Second * 15
 )
 
+// CustomCABundlePath is the path of the CA bundle of the caBundleRef of the object store in the rgw pods
+const CustomCABundlePath = caBundleSourceCustomDir + caBundleFileName
+
 var (
 	rgwFrontendName = "beast"
 )
@@ -118,6 +122,13 @@ func (c *clusterConfig) setDefaultFlagsMonConfigStore(rgwName string) error {
 	configOptions["rgw_enable_usage_log"] = "true"
 	configOptions["rgw_zone"] = c.store.Name
 	configOptions["rgw_zonegroup"] = c.store.Name
+	if c.clusterSpec.Profile == cephv1.ClusterProfileArchive {
+		// keep more bucket and user metadata in the cache, and buffer more data per request before
+		// reading or writing the erasure coded pool
+		configOptions["rgw_cache_lru_size"] = "100000"
+		configOptions["rgw_put_obj_min_window_size"] = "33554432"
+		configOptions["rgw_get_obj_window_size"] = "33554432"
+	}
 
 	for flag, val := range configOptions {
 		err := monStore.Set(who, flag, val)
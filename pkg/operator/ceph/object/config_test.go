@@ -24,6 +24,7 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -95,3 +96,32 @@ func TestGenerateCephXUser(t *testing.T) {
 	fakeUser := generateCephXUser("rook-ceph-rgw-fake-store-fake-user")
 	assert.Equal(t, "client.rgw.fake.store.fake.user", fakeUser)
 }
+
+func TestSetDefaultFlagsMonConfigStore(t *testing.T) {
+	options := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "config" && args[1] == "set" {
+				options[args[3]] = args[4]
+			}
+			return "", nil
+		},
+	}
+	cfg := newConfig(t)
+	cfg.context.Executor = executor
+	cfg.clusterInfo = cephclient.AdminClusterInfo("ns")
+	cfg.store.Name = "my-store"
+
+	err := cfg.setDefaultFlagsMonConfigStore("rook-ceph-rgw-my-store-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-store", options["rgw_zone"])
+	_, ok := options["rgw_cache_lru_size"]
+	assert.False(t, ok)
+
+	// the rgw caches are bigger in an archive cluster
+	cfg.clusterSpec.Profile = cephv1.ClusterProfileArchive
+	err = cfg.setDefaultFlagsMonConfigStore("rook-ceph-rgw-my-store-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "100000", options["rgw_cache_lru_size"])
+	assert.Equal(t, "33554432", options["rgw_put_obj_min_window_size"])
+}
@@ -99,6 +99,7 @@ type objectStoreHealth struct {
 // Add creates a new cephObjectStore Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
@@ -203,6 +204,7 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 
 		return reconcileResponse, cephObjectStore, nil
 	}
+	opcontroller.RemoveUnvalidatedHotfix(&cephCluster)
 	r.clusterSpec = &cephCluster.Spec
 
 	// Initialize the channel for this object store
@@ -266,13 +268,22 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 				r.objectStoreContexts[cephObjectStore.Name].internalCancel()
 				r.objectStoreContexts[cephObjectStore.Name].started = false
 
-				cfg := clusterConfig{
-					context:     r.context,
-					store:       cephObjectStore,
-					clusterSpec: r.clusterSpec,
-					clusterInfo: r.clusterInfo,
+				// The orphaned gateways keep their keys, realm and pools
+				if opcontroller.IsOrphanDeletionPolicy(cephObjectStore.Spec.DeletionPolicy) {
+					orphaned, err := opcontroller.OrphanGeneratedResources(r.opManagerContext, r.client, cephObjectStore)
+					if err != nil {
+						return reconcile.Result{}, cephObjectStore, errors.Wrapf(err, "failed to orphan the resources of CephObjectStore %q", request.NamespacedName.String())
+					}
+					reporting.ReportResourcesOrphaned(logger, r.client, r.recorder, cephObjectStore, orphaned)
+				} else {
+					cfg := clusterConfig{
+						context:     r.context,
+						store:       cephObjectStore,
+						clusterSpec: r.clusterSpec,
+						clusterInfo: r.clusterInfo,
+					}
+					cfg.deleteStore()
 				}
-				cfg.deleteStore()
 
 				// Remove object store from the map
 				delete(r.objectStoreContexts, cephObjectStore.Name)
@@ -318,6 +329,9 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	}
 	r.clusterInfo.CephVersion = *desiredCephVersion
 
+	// the pools not set in the store get the defaults of the profile of the cluster
+	cephObjectStore.Spec.ApplyProfileDefaults(r.clusterSpec.Profile)
+
 	// validate the store settings
 	if err := r.validateStore(cephObjectStore); err != nil {
 		return reconcile.Result{}, cephObjectStore, errors.Wrapf(err, "invalid object store %q arguments", cephObjectStore.Name)
@@ -348,11 +362,54 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	// Set Progressing status, we are done reconciling, the health check go routine will update the status
 	updateStatus(r.client, request.NamespacedName, cephv1.ConditionProgressing, buildStatusInfo(cephObjectStore))
 
+	// Run the post-ready hooks
+	result, err := r.reconcileHooks(cephObjectStore, request.NamespacedName)
+	if err != nil || !result.IsZero() {
+		return result, cephObjectStore, err
+	}
+
 	// Return and do not requeue
 	logger.Debug("done reconciling")
 	return reconcile.Result{}, cephObjectStore, nil
 }
 
+// reconcileHooks runs the post-ready hooks once the object store first answers the requests of the
+// bucket health checker, or right away if the health checker is disabled
+func (r *ReconcileCephObjectStore) reconcileHooks(cephObjectStore *cephv1.CephObjectStore, namespacedName types.NamespacedName) (reconcile.Result, error) {
+	var statuses []cephv1.HookStatus
+	ready := cephObjectStore.Spec.HealthCheck.Bucket.Disabled
+	if status := cephObjectStore.Status; status != nil {
+		statuses = status.Hooks
+		ready = ready || (status.BucketStatus != nil && status.BucketStatus.Health == cephv1.ConditionConnected)
+	}
+	if len(cephObjectStore.Spec.Hooks.PostReady) == 0 && len(statuses) == 0 {
+		return reconcile.Result{}, nil
+	}
+	// the hooks keep running if the object store was ready before
+	if !ready && len(statuses) == 0 {
+		logger.Debugf("waiting for object store %q to be ready to run the hooks", namespacedName.String())
+		return opcontroller.WaitForRequeueIfHooksPending, nil
+	}
+
+	resource := opcontroller.HookResource{
+		Kind:      cephObjectStoreKind,
+		Name:      cephObjectStore.Name,
+		Namespace: cephObjectStore.Namespace,
+		OwnerInfo: k8sutil.NewOwnerInfo(cephObjectStore, r.scheme),
+	}
+	newStatuses, running, err := opcontroller.ReconcileHooks(r.opManagerContext, r.context.Clientset, resource, cephObjectStore.Spec.Hooks.PostReady, statuses)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !reflect.DeepEqual(newStatuses, statuses) {
+		updateStatusHooks(r.client, namespacedName, newStatuses)
+	}
+	if running {
+		return opcontroller.WaitForRequeueIfHooksPending, nil
+	}
+	return reconcile.Result{}, nil
+}
+
 func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *cephv1.CephObjectStore, namespacedName types.NamespacedName, cluster cephv1.ClusterSpec) (reconcile.Result, error) {
 	ownerInfo := k8sutil.NewOwnerInfo(cephObjectStore, r.scheme)
 	cfg := clusterConfig{
@@ -439,6 +496,11 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 			return r.setFailedStatus(namespacedName, "failed to configure multisite for object store", err)
 		}
 
+		// Check the KMS of the server side encryption before the rgw pods are configured with it
+		if err := cfg.checkEncryptionConnections(); err != nil {
+			return r.setFailedStatus(namespacedName, "failed to validate the server side encryption", err)
+		}
+
 		// Create or Update Store
 		err = cfg.createOrUpdateStore(realmName, zoneGroupName, zoneName)
 		if err != nil {
@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"path"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/libopenstorage/secrets/vault"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// kmipProvider is the KMS_PROVIDER of a KMIP server, only supported by rgw for the SSE-KMS encryption
+	kmipProvider = "kmip"
+	// KMIPEndpointKey is the connection detail of the address of the KMIP server, as host:port
+	KMIPEndpointKey = "KMIP_ENDPOINT"
+	// KMIPTLSServerNameKey is the connection detail of the name of the KMIP server in its certificate, the
+	// host of the endpoint if not set
+	KMIPTLSServerNameKey = "KMIP_TLS_SERVER_NAME"
+	// the keys of the secret of the TLS credentials of rgw for the KMIP server
+	kmipCACertKey     = "CA_CERT"
+	kmipClientCertKey = "CLIENT_CERT"
+	kmipClientKeyKey  = "CLIENT_KEY"
+
+	kmipVolumeName     = "rgw-kmip"
+	kmipDir            = "/etc/kmip"
+	kmipCACertFile     = "ca.crt"
+	kmipClientCertFile = "client.crt"
+	kmipClientKeyFile  = "client.key"
+
+	// the vault token of the SSE-S3 encryption is copied next to the one of the SSE-KMS encryption
+	sseS3VaultVolumeName = "rgw-sse-s3-vault"
+	sseS3VaultDir        = "/etc/vault-sse-s3"
+	sseS3VaultFileName   = "vault-sse-s3.token"
+	sseS3DefaultPath     = "transit"
+
+	kmsConnectionTimeout = 10 * time.Second
+)
+
+var (
+	// the connections to the KMS, mocked in the unit tests
+	checkVaultConnection = kms.CheckVaultConnection
+	checkKMIPConnection  = dialKMIP
+)
+
+func isKMIP(spec cephv1.KeyManagementServiceSpec) bool {
+	return kms.GetParam(spec.ConnectionDetails, kms.Provider) == kmipProvider
+}
+
+// validateKMIP checks the endpoint of the KMIP server and the TLS credentials of rgw are set
+func (c *clusterConfig) validateKMIP() error {
+	spec := c.store.Spec.Security.KeyManagementService
+	if kms.GetParam(spec.ConnectionDetails, KMIPEndpointKey) == "" {
+		return errors.Errorf("failed to validate kmip config %q. cannot be empty", KMIPEndpointKey)
+	}
+	if !spec.IsTokenAuthEnabled() {
+		return errors.New("failed to validate kmip config, the tokenSecretName of the TLS credentials of rgw is required")
+	}
+	_, err := c.kmipCredentials()
+	return err
+}
+
+// kmipCredentials returns the secret of the TLS credentials of rgw for the KMIP server
+func (c *clusterConfig) kmipCredentials() (*v1.Secret, error) {
+	secretName := c.store.Spec.Security.KeyManagementService.TokenSecretName
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(c.clusterInfo.Context, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get kmip secret %q", secretName)
+	}
+	for _, key := range []string{kmipCACertKey, kmipClientCertKey, kmipClientKeyKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, errors.Errorf("failed to find key %q in kmip secret %q", key, secretName)
+		}
+	}
+	return secret, nil
+}
+
+// CheckRGWSSES3 returns whether the SSE-S3 encryption is enabled, and validates its KMS
+func (c *clusterConfig) CheckRGWSSES3() (bool, error) {
+	if c.store.Spec.Security == nil || !c.store.Spec.Security.ServerSideEncryptionS3.IsEnabled() {
+		return false, nil
+	}
+	if !c.clusterInfo.CephVersion.IsAtLeastQuincy() {
+		return false, errors.Errorf("the SSE-S3 encryption requires ceph quincy, the version is %q", c.clusterInfo.CephVersion.String())
+	}
+	spec := &c.store.Spec.Security.ServerSideEncryptionS3
+	if kms.GetParam(spec.ConnectionDetails, kms.Provider) != "vault" {
+		return false, errors.New("failed to validate SSE-S3 kms provider, only vault is supported")
+	}
+	if !spec.IsTokenAuthEnabled() {
+		return false, errors.New("failed to validate SSE-S3 vault auth, rgw only supports the token authentication")
+	}
+	if engine := kms.GetParam(spec.ConnectionDetails, kms.VaultSecretEngineKey); engine != "" && engine != kms.VaultTransitSecretEngineKey {
+		return false, errors.Errorf("failed to validate SSE-S3 vault secret engine %q, only transit is supported", engine)
+	}
+	if err := kms.ValidateConnectionDetails(c.context, &cephv1.SecuritySpec{KeyManagementService: *spec}, c.store.Namespace); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkEncryptionConnections checks the KMS of the SSE-KMS and SSE-S3 encryption are reachable, so that an
+// unreachable KMS fails the reconcile of the object store instead of the requests of the S3 clients
+func (c *clusterConfig) checkEncryptionConnections() error {
+	kmsEnabled, err := c.CheckRGWKMS()
+	if err != nil {
+		return errors.Wrap(err, "invalid SSE-KMS settings")
+	}
+	if kmsEnabled {
+		spec := c.store.Spec.Security.KeyManagementService
+		if isKMIP(spec) {
+			var secret *v1.Secret
+			secret, err = c.kmipCredentials()
+			if err == nil {
+				err = checkKMIPConnection(spec, secret)
+			}
+		} else {
+			err = checkVaultConnection(spec.ConnectionDetails)
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to connect to the kms of the SSE-KMS encryption")
+		}
+	}
+
+	sseS3Enabled, err := c.CheckRGWSSES3()
+	if err != nil {
+		return errors.Wrap(err, "invalid SSE-S3 settings")
+	}
+	if sseS3Enabled {
+		if err := checkVaultConnection(c.store.Spec.Security.ServerSideEncryptionS3.ConnectionDetails); err != nil {
+			return errors.Wrap(err, "failed to connect to the kms of the SSE-S3 encryption")
+		}
+	}
+	return nil
+}
+
+// dialKMIP opens a TLS connection to the KMIP server with the credentials of rgw
+func dialKMIP(spec cephv1.KeyManagementServiceSpec, secret *v1.Secret) error {
+	cert, err := tls.X509KeyPair(secret.Data[kmipClientCertKey], secret.Data[kmipClientKeyKey])
+	if err != nil {
+		return errors.Wrap(err, "failed to load the kmip client certificate")
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(secret.Data[kmipCACertKey]) {
+		return errors.New("failed to load the kmip ca certificate")
+	}
+
+	endpoint := kms.GetParam(spec.ConnectionDetails, KMIPEndpointKey)
+	serverName := kms.GetParam(spec.ConnectionDetails, KMIPTLSServerNameKey)
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			return errors.Wrapf(err, "invalid kmip endpoint %q", endpoint)
+		}
+		serverName = host
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+		ServerName:   serverName,
+		MinVersion:   tls.VersionTLS12,
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: kmsConnectionTimeout}, "tcp", endpoint, config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to reach kmip server at %q", endpoint)
+	}
+	return conn.Close()
+}
+
+// kmipVolume returns the volume of the TLS credentials of rgw for the KMIP server
+func kmipVolume(secretName string) v1.Volume {
+	mode := int32(0444)
+	return v1.Volume{
+		Name: kmipVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: secretName,
+				Items: []v1.KeyToPath{
+					{Key: kmipCACertKey, Path: kmipCACertFile},
+					{Key: kmipClientCertKey, Path: kmipClientCertFile},
+					{Key: kmipClientKeyKey, Path: kmipClientKeyFile},
+				},
+				DefaultMode: &mode,
+			}}}
+}
+
+func kmipVolumeMount() v1.VolumeMount {
+	return v1.VolumeMount{Name: kmipVolumeName, MountPath: kmipDir, ReadOnly: true}
+}
+
+func (c *clusterConfig) kmipFlags() []string {
+	return []string{
+		cephconfig.NewFlag("rgw crypt kmip addr",
+			kms.GetParam(c.store.Spec.Security.KeyManagementService.ConnectionDetails, KMIPEndpointKey)),
+		cephconfig.NewFlag("rgw crypt kmip ca path", path.Join(kmipDir, kmipCACertFile)),
+		cephconfig.NewFlag("rgw crypt kmip client cert", path.Join(kmipDir, kmipClientCertFile)),
+		cephconfig.NewFlag("rgw crypt kmip client key", path.Join(kmipDir, kmipClientKeyFile)),
+	}
+}
+
+// sseS3VaultVolume returns the volume of the vault token of the SSE-S3 encryption
+func sseS3VaultVolume(tokenSecretName string) v1.Volume {
+	return v1.Volume{
+		Name: sseS3VaultVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: tokenSecretName,
+				Items: []v1.KeyToPath{
+					{Key: kms.KMSTokenSecretNameKey, Path: kms.VaultFileName},
+				}}}}
+}
+
+func (c *clusterConfig) sseS3VaultTokenInitContainer(rgwConfig *rgwConfig) v1.Container {
+	volMount := v1.VolumeMount{Name: sseS3VaultVolumeName, MountPath: sseS3VaultDir, ReadOnly: true}
+	return c.tokenFileInitContainer(rgwConfig, "vault-sse-s3-initcontainer-token-file-setup", volMount, sseS3VaultFileName)
+}
+
+func (c *clusterConfig) sseS3Flags() []string {
+	details := c.store.Spec.Security.ServerSideEncryptionS3.ConnectionDetails
+	backendPath := kms.GetParam(details, vault.VaultBackendPathKey)
+	if backendPath == "" {
+		backendPath = sseS3DefaultPath
+	}
+	return []string{
+		cephconfig.NewFlag("rgw crypt sse s3 backend", "vault"),
+		cephconfig.NewFlag("rgw crypt sse s3 vault addr", details[api.EnvVaultAddress]),
+		cephconfig.NewFlag("rgw crypt sse s3 vault auth", kms.KMSTokenSecretNameKey),
+		cephconfig.NewFlag("rgw crypt sse s3 vault token file", path.Join(c.DataPathMap.ContainerDataDir, sseS3VaultFileName)),
+		cephconfig.NewFlag("rgw crypt sse s3 vault prefix", path.Join("/v1/", backendPath)),
+		cephconfig.NewFlag("rgw crypt sse s3 vault secret engine", kms.VaultTransitSecretEngineKey),
+	}
+}
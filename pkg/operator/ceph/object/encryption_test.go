@@ -0,0 +1,188 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newEncryptionConfig(t *testing.T) *clusterConfig {
+	store := simpleStore()
+	store.Spec.Security = &cephv1.ObjectStoreSecuritySpec{}
+	info := clienttest.CreateTestClusterInfo(1)
+	info.CephVersion = cephver.Quincy
+	return &clusterConfig{
+		context:     &clusterd.Context{Clientset: test.New(t, 1)},
+		clusterInfo: info,
+		store:       store,
+		clusterSpec: &cephv1.ClusterSpec{},
+		DataPathMap: cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, store.Name, store.Namespace, "/var/lib/rook/"),
+	}
+}
+
+func createSecret(t *testing.T, c *clusterConfig, name string, data map[string][]byte) {
+	s := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.store.Namespace}, Data: data}
+	_, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Create(context.TODO(), s, metav1.CreateOptions{})
+	assert.NoError(t, err)
+}
+
+func TestCheckRGWSSES3(t *testing.T) {
+	c := newEncryptionConfig(t)
+
+	// without SSE-S3
+	enabled, err := c.CheckRGWSSES3()
+	assert.False(t, enabled)
+	assert.NoError(t, err)
+
+	c.store.Spec.Security.ServerSideEncryptionS3 = cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{"KMS_PROVIDER": "vault", "VAULT_ADDR": "https://1.1.1.1:8200"},
+		TokenSecretName:   "rgw-sse-s3-token",
+	}
+	createSecret(t, c, "rgw-sse-s3-token", map[string][]byte{"token": []byte("myt-otkenbenvqrev")})
+	enabled, err = c.CheckRGWSSES3()
+	assert.True(t, enabled)
+	assert.NoError(t, err)
+
+	// only the transit engine is supported
+	c.store.Spec.Security.ServerSideEncryptionS3.ConnectionDetails["VAULT_SECRET_ENGINE"] = "kv"
+	_, err = c.CheckRGWSSES3()
+	assert.Error(t, err)
+	c.store.Spec.Security.ServerSideEncryptionS3.ConnectionDetails["VAULT_SECRET_ENGINE"] = "transit"
+
+	// SSE-S3 requires quincy
+	c.clusterInfo.CephVersion = cephver.Pacific
+	_, err = c.CheckRGWSSES3()
+	assert.Error(t, err)
+	c.clusterInfo.CephVersion = cephver.Quincy
+
+	// the token is required
+	c.store.Spec.Security.ServerSideEncryptionS3.TokenSecretName = ""
+	_, err = c.CheckRGWSSES3()
+	assert.Error(t, err)
+	c.store.Spec.Security.ServerSideEncryptionS3.TokenSecretName = "rgw-sse-s3-token"
+
+	rgwConfig := &rgwConfig{ResourceName: fmt.Sprintf("%s-%s-a", AppName, c.store.Name)}
+	podTemplate, err := c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, sseS3VaultVolumeName, podTemplate.Spec.Volumes[len(podTemplate.Spec.Volumes)-1].Name)
+	initContainer := podTemplate.Spec.InitContainers[len(podTemplate.Spec.InitContainers)-1]
+	assert.Contains(t, initContainer.Command[2], "VAULT_TOKEN_NEW_PATH=/var/lib/ceph/rgw/ceph-default/vault-sse-s3.token")
+
+	args := c.makeDaemonContainer(rgwConfig).Args
+	assert.Contains(t, args, "--rgw-crypt-sse-s3-backend=vault")
+	assert.Contains(t, args, "--rgw-crypt-sse-s3-vault-addr=https://1.1.1.1:8200")
+	assert.Contains(t, args, "--rgw-crypt-sse-s3-vault-prefix=/v1/transit")
+	assert.Contains(t, args, "--rgw-crypt-sse-s3-vault-token-file=/var/lib/ceph/rgw/ceph-default/vault-sse-s3.token")
+}
+
+func TestRGWKMIP(t *testing.T) {
+	c := newEncryptionConfig(t)
+	c.store.Spec.Security.KeyManagementService = cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{"KMS_PROVIDER": "kmip"},
+		TokenSecretName:   "rgw-kmip-credentials",
+	}
+
+	// the endpoint is required
+	_, err := c.CheckRGWKMS()
+	assert.Error(t, err)
+	c.store.Spec.Security.KeyManagementService.ConnectionDetails[KMIPEndpointKey] = "kmip.example.com:5696"
+
+	// the credentials are required
+	_, err = c.CheckRGWKMS()
+	assert.Error(t, err)
+	createSecret(t, c, "rgw-kmip-credentials", map[string][]byte{
+		"CA_CERT":     []byte("ca"),
+		"CLIENT_CERT": []byte("cert"),
+		"CLIENT_KEY":  []byte("key"),
+	})
+	enabled, err := c.CheckRGWKMS()
+	assert.True(t, enabled)
+	assert.NoError(t, err)
+
+	rgwConfig := &rgwConfig{ResourceName: fmt.Sprintf("%s-%s-a", AppName, c.store.Name)}
+	podTemplate, err := c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, kmipVolumeName, podTemplate.Spec.Volumes[len(podTemplate.Spec.Volumes)-1].Name)
+
+	container := c.makeDaemonContainer(rgwConfig)
+	assert.Contains(t, container.Args, "--rgw-crypt-s3-kms-backend=kmip")
+	assert.Contains(t, container.Args, "--rgw-crypt-kmip-addr=kmip.example.com:5696")
+	assert.Contains(t, container.Args, "--rgw-crypt-kmip-client-key=/etc/kmip/client.key")
+	assert.Equal(t, kmipVolumeMount(), container.VolumeMounts[len(container.VolumeMounts)-1])
+}
+
+func TestCheckEncryptionConnections(t *testing.T) {
+	defer func() {
+		checkVaultConnection = kms.CheckVaultConnection
+		checkKMIPConnection = dialKMIP
+	}()
+	var vaultAddresses []string
+	checkVaultConnection = func(config map[string]string) error {
+		vaultAddresses = append(vaultAddresses, config["VAULT_ADDR"])
+		if config["VAULT_ADDR"] == "https://sealed:8200" {
+			return errors.New("vault is sealed")
+		}
+		return nil
+	}
+	kmipChecked := false
+	checkKMIPConnection = func(spec cephv1.KeyManagementServiceSpec, secret *v1.Secret) error {
+		kmipChecked = true
+		assert.Equal(t, "rgw-kmip-credentials", secret.Name)
+		return nil
+	}
+
+	c := newEncryptionConfig(t)
+	assert.NoError(t, c.checkEncryptionConnections())
+	assert.Empty(t, vaultAddresses)
+
+	c.store.Spec.Security.KeyManagementService = cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{"KMS_PROVIDER": "kmip", KMIPEndpointKey: "kmip.example.com:5696"},
+		TokenSecretName:   "rgw-kmip-credentials",
+	}
+	createSecret(t, c, "rgw-kmip-credentials", map[string][]byte{
+		"CA_CERT":     []byte("ca"),
+		"CLIENT_CERT": []byte("cert"),
+		"CLIENT_KEY":  []byte("key"),
+	})
+	c.store.Spec.Security.ServerSideEncryptionS3 = cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{"KMS_PROVIDER": "vault", "VAULT_ADDR": "https://vault:8200"},
+		TokenSecretName:   "rgw-sse-s3-token",
+	}
+	createSecret(t, c, "rgw-sse-s3-token", map[string][]byte{"token": []byte("myt-otkenbenvqrev")})
+	assert.NoError(t, c.checkEncryptionConnections())
+	assert.True(t, kmipChecked)
+	assert.Equal(t, []string{"https://vault:8200"}, vaultAddresses)
+
+	c.store.Spec.Security.ServerSideEncryptionS3.ConnectionDetails["VAULT_ADDR"] = "https://sealed:8200"
+	err := c.checkEncryptionConnections()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "vault is sealed")
+}
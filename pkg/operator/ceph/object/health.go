@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -185,9 +186,26 @@ func (c *bucketChecker) checkObjectStoreHealth() error {
 	// Update the EndpointStatus in the CR to reflect the healthyness
 	updateStatusBucket(c.client, c.namespacedName, cephv1.ConditionConnected, "")
 
+	if err := c.checkDashboardObjectGateway(); err != nil {
+		logger.Warningf("failed to check the object gateway credentials of the dashboard for object store %q. %v", c.namespacedName.String(), err)
+	}
+
 	return nil
 }
 
+// checkDashboardObjectGateway keeps the object gateway credentials of the dashboard updated with the keys
+// of the dashboard rgw user when they are managed with the dashboard settings of the CephCluster
+func (c *bucketChecker) checkDashboardObjectGateway() error {
+	cephClusters := &cephv1.CephClusterList{}
+	if err := c.client.List(c.objContext.clusterInfo.Context, cephClusters, client.InNamespace(c.namespacedName.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list the ceph clusters")
+	}
+	if len(cephClusters.Items) == 0 || !mgr.DashboardObjectGatewayEnabled(&cephClusters.Items[0].Spec, c.objContext.clusterInfo) {
+		return nil
+	}
+	return updateDashboardObjectGatewayCredentials(&c.objContext.Context)
+}
+
 func cleanupObjectHealthCheck(s3client *S3Agent, objectStoreUID string) {
 	bucketToDelete := genHealthCheckerBucketName(objectStoreUID)
 	logger.Debugf("deleting object %q from bucket %q", s3HealthCheckObjectKey, bucketToDelete)
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// instanceGroupLabel is the label of the deployments and the pods of an instance group with the name of the group
+	instanceGroupLabel = "rgw_instance_group"
+	// TrafficWeightAnnotation is the percentage of the traffic of the object store to send to the pods of the service
+	TrafficWeightAnnotation = "ceph.rook.io/rgw-traffic-weight"
+)
+
+// instanceGroupResourceName returns the name of the deployment and the service of an instance group,
+// rook-ceph-rgw-<store_name>-<group_name>
+func instanceGroupResourceName(storeName, groupName string) string {
+	return fmt.Sprintf("%s-%s-%s", AppName, storeName, groupName)
+}
+
+// forInstanceGroup returns the config of the rgw pods of an instance group, the gateway settings of the group
+// replacing the ones of the object store
+func (c *clusterConfig) forInstanceGroup(group cephv1.RGWInstanceGroupSpec) *clusterConfig {
+	groupConfig := *c
+	groupConfig.store = c.store.DeepCopy()
+	groupConfig.store.Spec.Gateway.Instances = group.Instances
+	groupConfig.store.Spec.Gateway.Placement = group.Placement
+	groupConfig.store.Spec.Gateway.Resources = group.Resources
+	groupConfig.store.Spec.Gateway.PriorityClassName = group.PriorityClassName
+	groupConfig.instanceGroup = &group
+	return &groupConfig
+}
+
+// daemonLabels returns the labels of the rgw pods. The pods of an instance group have their own daemon id,
+// so they are not selected by the deployment and the service of the gateway pods.
+func (c *clusterConfig) daemonLabels(includeNewLabels bool) map[string]string {
+	if c.instanceGroup == nil {
+		return getLabels(c.store.Name, c.store.Namespace, includeNewLabels)
+	}
+	daemonID := fmt.Sprintf("%s-%s", c.store.Name, c.instanceGroup.Name)
+	labels := controller.CephDaemonAppLabels(AppName, c.store.Namespace, "rgw", daemonID, includeNewLabels)
+	labels["rook_object_store"] = c.store.Name
+	labels[instanceGroupLabel] = c.instanceGroup.Name
+	return labels
+}
+
+// serviceName returns the name of the service of the rgw pods
+func (c *clusterConfig) serviceName() string {
+	if c.instanceGroup == nil {
+		return instanceName(c.store.Name)
+	}
+	return instanceGroupResourceName(c.store.Name, c.instanceGroup.Name)
+}
+
+// trafficWeight returns the percentage of the traffic of the object store to send to the rgw pods. The
+// gateway pods get the traffic left by the instance groups. There is no weight without instance groups.
+func (c *clusterConfig) trafficWeight() (int32, bool) {
+	if len(c.store.Spec.Gateway.InstanceGroups) == 0 {
+		return 0, false
+	}
+	if c.instanceGroup != nil {
+		return c.instanceGroup.Weight, true
+	}
+	weight := int32(100)
+	for _, group := range c.store.Spec.Gateway.InstanceGroups {
+		weight -= group.Weight
+	}
+	if weight < 0 {
+		weight = 0
+	}
+	return weight, true
+}
+
+// startInstanceGroups creates or updates the deployments and the services of the instance groups, and removes
+// the ones of the groups removed from the spec
+func (c *clusterConfig) startInstanceGroups(realmName, zoneGroupName, zoneName string) error {
+	groups := c.store.Spec.Gateway.InstanceGroups
+	if len(groups) > 0 && !c.clusterInfo.CephVersion.IsAtLeastPacific() {
+		return errors.Errorf("instance groups require ceph pacific or newer, the ceph version is %q", c.clusterInfo.CephVersion.String())
+	}
+
+	for _, group := range groups {
+		groupConfig := c.forInstanceGroup(group)
+		rgwConfig := &rgwConfig{
+			ResourceName: instanceGroupResourceName(c.store.Name, group.Name),
+			DaemonID:     fmt.Sprintf("%s-%s", c.store.Name, group.Name),
+			Realm:        realmName,
+			ZoneGroup:    zoneGroupName,
+			Zone:         zoneName,
+		}
+		if err := groupConfig.startRGWDeployment(rgwConfig, group.Name); err != nil {
+			return errors.Wrapf(err, "failed to start the rgw pods of instance group %q", group.Name)
+		}
+		if _, err := groupConfig.reconcileService(groupConfig.store); err != nil {
+			return errors.Wrapf(err, "failed to reconcile the service of instance group %q", group.Name)
+		}
+	}
+
+	return c.removeInstanceGroups()
+}
+
+// removeInstanceGroups deletes the deployments, the services and the keys of the instance groups that are not
+// in the spec anymore
+func (c *clusterConfig) removeInstanceGroups() error {
+	selector := fmt.Sprintf("rook_object_store=%s,%s", c.store.Name, instanceGroupLabel)
+	deps, err := k8sutil.GetDeployments(c.context.Clientset, c.store.Namespace, selector)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to list the instance group deployments of object store %q", c.store.Name)
+	}
+
+	groups := map[string]bool{}
+	for _, group := range c.store.Spec.Gateway.InstanceGroups {
+		groups[group.Name] = true
+	}
+	for _, d := range deps.Items {
+		groupName := d.Labels[instanceGroupLabel]
+		if groups[groupName] {
+			continue
+		}
+		logger.Infof("removing instance group %q of object store %q", groupName, c.store.Name)
+		if err := k8sutil.DeleteDeployment(c.context.Clientset, c.store.Namespace, d.Name); err != nil {
+			return errors.Wrapf(err, "failed to delete deployment %q of instance group %q", d.Name, groupName)
+		}
+		err := c.context.Clientset.CoreV1().Services(c.store.Namespace).Delete(c.clusterInfo.Context, d.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to delete service %q of instance group %q. %v", d.Name, groupName, err)
+		}
+		secretName := c.generateSecretName(groupName)
+		err = c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Delete(c.clusterInfo.Context, secretName, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to delete rgw secret %q of instance group %q. %v", secretName, groupName, err)
+		}
+		if err := c.deleteRgwCephObjects(d.Name); err != nil {
+			logger.Warningf("%v", err)
+		}
+	}
+	return nil
+}
+
+// applyTrafficWeight sets the traffic weight of the rgw pods in the annotations of their service
+func (c *clusterConfig) applyTrafficWeight(meta *metav1.ObjectMeta) {
+	weight, ok := c.trafficWeight()
+	if !ok {
+		return
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[TrafficWeightAnnotation] = strconv.Itoa(int(weight))
+}
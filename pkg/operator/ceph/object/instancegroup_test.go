@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStartInstanceGroups(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testop.New(t, 3)
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "auth" && args[1] == "get-or-create-key" {
+				return `{"key":"mysecurekey"}`, nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{}`, nil
+			}
+			return `{"id":"test-id"}`, nil
+		},
+	}
+	info := clienttest.CreateTestClusterInfo(1)
+	info.CephVersion = cephver.Pacific
+	store := simpleStore()
+	store.Spec.Gateway.Instances = 2
+	store.Spec.Gateway.InstanceGroups = []cephv1.RGWInstanceGroupSpec{{
+		Name:      "new",
+		Instances: 3,
+		Resources: v1.ResourceRequirements{Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")}},
+		Weight:    30,
+	}}
+	c := &clusterConfig{
+		context:     &clusterd.Context{Clientset: clientset, Executor: executor},
+		clusterInfo: info,
+		store:       store,
+		rookVersion: "v1.1.0",
+		clusterSpec: &cephv1.ClusterSpec{},
+		ownerInfo:   cephclient.NewMinimumOwnerInfoWithOwnerRef(),
+		DataPathMap: config.NewStatelessDaemonDataPathMap(config.RgwType, store.Name, store.Namespace, "/var/lib/rook/"),
+	}
+
+	require.NoError(t, c.startRGWPods(store.Name, store.Name, store.Name))
+	require.NoError(t, c.startInstanceGroups(store.Name, store.Name, store.Name))
+	_, err := c.reconcileService(store)
+	require.NoError(t, err)
+
+	// the gateway pods and the pods of the group have their own deployment and service
+	groupName := instanceGroupResourceName(store.Name, "new")
+	d, err := clientset.AppsV1().Deployments(store.Namespace).Get(ctx, groupName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), *d.Spec.Replicas)
+	assert.Equal(t, "new", d.Spec.Selector.MatchLabels[instanceGroupLabel])
+	assert.Equal(t, "default-new", d.Spec.Template.Labels["rgw"])
+	assert.Equal(t, "4Gi", d.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String())
+	d, err = clientset.AppsV1().Deployments(store.Namespace).Get(ctx, instanceName(store.Name)+"-a", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *d.Spec.Replicas)
+	assert.NotContains(t, d.Spec.Template.Labels, instanceGroupLabel)
+
+	svc, err := clientset.CoreV1().Services(store.Namespace).Get(ctx, groupName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "30", svc.Annotations[TrafficWeightAnnotation])
+	assert.Equal(t, "default-new", svc.Spec.Selector["rgw"])
+	svc, err = clientset.CoreV1().Services(store.Namespace).Get(ctx, instanceName(store.Name), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "70", svc.Annotations[TrafficWeightAnnotation])
+	assert.Equal(t, "default", svc.Spec.Selector["rgw"])
+
+	// the group deployment is not scaled down with the gateway pods
+	updateDeploymentAndWait = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, deployment *apps.Deployment, daemonType, daemonName string, skipUpgradeChecks, continueUpgradeAfterChecksEvenIfNotHealthy bool) error {
+		return nil
+	}
+	defer func() { updateDeploymentAndWait = mon.UpdateCephDeploymentAndWait }()
+	require.NoError(t, c.startRGWPods(store.Name, store.Name, store.Name))
+	_, err = clientset.AppsV1().Deployments(store.Namespace).Get(ctx, groupName, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// the group is removed
+	store.Spec.Gateway.InstanceGroups = nil
+	require.NoError(t, c.startInstanceGroups(store.Name, store.Name, store.Name))
+	_, err = clientset.AppsV1().Deployments(store.Namespace).Get(ctx, groupName, metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = clientset.CoreV1().Services(store.Namespace).Get(ctx, groupName, metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = clientset.AppsV1().Deployments(store.Namespace).Get(ctx, instanceName(store.Name)+"-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// instance groups require pacific
+	store.Spec.Gateway.InstanceGroups = []cephv1.RGWInstanceGroupSpec{{Name: "new", Instances: 1}}
+	info.CephVersion = cephver.Octopus
+	assert.Error(t, c.startInstanceGroups(store.Name, store.Name, store.Name))
+}
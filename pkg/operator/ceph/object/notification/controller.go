@@ -0,0 +1,403 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notification to manage the bucket notifications of the buckets of the ObjectBucketClaims
+package notification
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-bucket-notification-controller"
+
+	// NotificationLabelPrefix is the prefix of the label of an ObjectBucketClaim with the name of a
+	// CephBucketNotification to apply to its bucket, such as "bucket-notification-<name>: <name>"
+	NotificationLabelPrefix = "bucket-notification-"
+
+	// the keys of the secret of the ObjectBucketClaim with the keys of the owner of the bucket
+	obcAccessKeyName = "AWS_ACCESS_KEY_ID"
+	obcSecretKeyName = "AWS_SECRET_ACCESS_KEY"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBucketNotificationKind = reflect.TypeOf(cephv1.CephBucketNotification{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBucketNotificationKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// waitForRequeueIfTopicNotReady waits for the topic of the notification to be created
+var waitForRequeueIfTopicNotReady = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+// newMultisiteAdminOpsCtxFunc help us mocking the admin ops API client in unit test
+var newMultisiteAdminOpsCtxFunc = object.NewMultisiteAdminOpsContext
+
+// ReconcileBucketNotification reconciles a CephBucketNotification object
+type ReconcileBucketNotification struct {
+	client           client.Client
+	context          *clusterd.Context
+	opManagerContext context.Context
+}
+
+// Add creates a new CephBucketNotification Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileBucketNotification{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBucketNotification CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketNotification{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	// Watch the topics and the ObjectBucketClaims to reconcile the notifications of their namespace, since the
+	// labels of an ObjectBucketClaim may no longer name a notification applied to its bucket
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketTopic{TypeMeta: metav1.TypeMeta{Kind: "CephBucketTopic", APIVersion: cephv1.SchemeGroupVersion.String()}}}, handler.EnqueueRequestsFromMapFunc(notificationsInNamespace(mgr.GetClient())))
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &bktv1alpha1.ObjectBucketClaim{TypeMeta: metav1.TypeMeta{Kind: "ObjectBucketClaim", APIVersion: bktv1alpha1.SchemeGroupVersion.String()}}}, handler.EnqueueRequestsFromMapFunc(notificationsInNamespace(mgr.GetClient())))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// notificationsInNamespace returns the requests of the notifications in the namespace of an object
+func notificationsInNamespace(c client.Client) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		notifications := &cephv1.CephBucketNotificationList{}
+		if err := c.List(context.TODO(), notifications, client.InNamespace(o.GetNamespace())); err != nil {
+			logger.Errorf("failed to list the ceph bucket notifications in namespace %q. %v", o.GetNamespace(), err)
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, notification := range notifications.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: notification.Name, Namespace: notification.Namespace}})
+		}
+		return requests
+	}
+}
+
+// Reconcile reads that state of the cluster for a CephBucketNotification object and makes changes based on the
+// state read and what is in the CephBucketNotification.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileBucketNotification) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileBucketNotification) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBucketNotification instance
+	notification := &cephv1.CephBucketNotification{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, notification)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketNotification resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephBucketNotification")
+	}
+
+	// Set a finalizer so we can delete the notification from the buckets
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, notification)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// The CR was just created, initializing status fields
+	if notification.Status == nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionProgressing, "", nil)
+	}
+
+	topic := &cephv1.CephBucketTopic{}
+	err = r.client.Get(r.opManagerContext, types.NamespacedName{Name: notification.Spec.Topic, Namespace: notification.Namespace}, topic)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to get ceph bucket topic %q", notification.Spec.Topic)
+		}
+		if !notification.GetDeletionTimestamp().IsZero() {
+			// the notifications are gone with the topic
+			logger.Infof("ceph bucket topic %q of ceph bucket notification %q not found, not removing the notification from the buckets", notification.Spec.Topic, notification.Name)
+			return reconcile.Result{}, r.removeFinalizer(notification)
+		}
+		r.updateStatus(request.NamespacedName, cephv1.ConditionProgressing, fmt.Sprintf("waiting for ceph bucket topic %q", notification.Spec.Topic), nil)
+		return waitForRequeueIfTopicNotReady, nil
+	}
+	if notification.GetDeletionTimestamp().IsZero() && (topic.Status == nil || topic.Status.ARN == nil || topic.Status.Phase != cephv1.ConditionReady) {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionProgressing, fmt.Sprintf("waiting for ceph bucket topic %q", notification.Spec.Topic), nil)
+		return waitForRequeueIfTopicNotReady, nil
+	}
+
+	// Make sure a CephCluster is present in the namespace of the object store otherwise do nothing
+	storeName := types.NamespacedName{Name: topic.Spec.ObjectStoreName, Namespace: topic.Spec.ObjectStoreNamespace}
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, storeName, controllerName)
+	if !isReadyToReconcile {
+		// the buckets are gone with the cluster
+		if !notification.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			return reconcile.Result{}, r.removeFinalizer(notification)
+		}
+		return reconcileResponse, nil
+	}
+
+	opsContext, err := r.adminOpsContext(cephCluster, storeName)
+	if err != nil {
+		if !notification.GetDeletionTimestamp().IsZero() && kerrors.IsNotFound(errors.Cause(err)) {
+			// the buckets are gone with the object store
+			return reconcile.Result{}, r.removeFinalizer(notification)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// DELETE: the CR was deleted
+	if !notification.GetDeletionTimestamp().IsZero() {
+		logger.Infof("deleting ceph bucket notification %q from its buckets", notification.Name)
+		if _, err := r.deleteFromBuckets(opsContext, notification, nil); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.removeFinalizer(notification)
+	}
+
+	obcs, err := r.applyToBuckets(opsContext, notification, *topic.Status.ARN)
+	if err != nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, err.Error(), obcs)
+		return reconcile.Result{}, err
+	}
+
+	// Success! Let's update the status
+	r.updateStatus(request.NamespacedName, cephv1.ConditionReady, "", obcs)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// adminOpsContext returns the admin ops context of the object store of the topic
+func (r *ReconcileBucketNotification) adminOpsContext(cephCluster cephv1.CephCluster, storeName types.NamespacedName) (*object.AdminOpsContext, error) {
+	// Populate clusterInfo during each reconcile
+	clusterInfo, _, _, err := mon.LoadClusterInfo(r.context, r.opManagerContext, storeName.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to populate cluster info")
+	}
+	store := &cephv1.CephObjectStore{}
+	if err := r.client.Get(r.opManagerContext, storeName, store); err != nil {
+		return nil, errors.Wrapf(err, "failed to get object store %q", storeName)
+	}
+	objContext, err := object.NewMultisiteContext(r.context, clusterInfo, store)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the context of object store %q", storeName)
+	}
+	objContext.CephClusterSpec = cephCluster.Spec
+	opsContext, err := newMultisiteAdminOpsCtxFunc(objContext, &store.Spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialized rgw admin ops client api")
+	}
+	return opsContext, nil
+}
+
+// applyToBuckets applies the notification to the buckets of the ObjectBucketClaims with the label of the notification,
+// and deletes it from the buckets of the ObjectBucketClaims without the label. Returns the names of the
+// ObjectBucketClaims with the notification on their bucket.
+func (r *ReconcileBucketNotification) applyToBuckets(opsContext *object.AdminOpsContext, notification *cephv1.CephBucketNotification, topicARN string) ([]string, error) {
+	obcs := &bktv1alpha1.ObjectBucketClaimList{}
+	err := r.client.List(r.opManagerContext, obcs, client.InNamespace(notification.Namespace), client.HasLabels{NotificationLabelPrefix + notification.Name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the object bucket claims of ceph bucket notification %q", notification.Name)
+	}
+
+	config := newTopicConfiguration(notification, topicARN)
+	labeled := map[string]bool{}
+	for i := range obcs.Items {
+		obc := &obcs.Items[i]
+		if obc.Status.Phase != bktv1alpha1.ObjectBucketClaimStatusPhaseBound || obc.Spec.BucketName == "" {
+			logger.Debugf("object bucket claim %q of ceph bucket notification %q is not bound yet", obc.Name, notification.Name)
+			continue
+		}
+		labeled[obc.Name] = true
+	}
+
+	// the notification is deleted from the buckets first, so the status reports them until they are deleted
+	applied, err := r.deleteFromBuckets(opsContext, notification, labeled)
+	if err != nil {
+		return applied, err
+	}
+
+	for i := range obcs.Items {
+		obc := &obcs.Items[i]
+		if !labeled[obc.Name] {
+			continue
+		}
+		bucket, err := r.bucketClient(opsContext, obc)
+		if err != nil {
+			return applied, err
+		}
+		if err := bucket.putNotification(r.opManagerContext, config); err != nil {
+			return applied, err
+		}
+		logger.Infof("applied ceph bucket notification %q to bucket %q of object bucket claim %q", notification.Name, obc.Spec.BucketName, obc.Name)
+		applied = appendName(applied, obc.Name)
+	}
+	return applied, nil
+}
+
+// deleteFromBuckets deletes the notification from the buckets of the ObjectBucketClaims of the status which are not
+// kept. Returns the ObjectBucketClaims of the status with the notification on their bucket.
+func (r *ReconcileBucketNotification) deleteFromBuckets(opsContext *object.AdminOpsContext, notification *cephv1.CephBucketNotification, keep map[string]bool) ([]string, error) {
+	if notification.Status == nil {
+		return nil, nil
+	}
+	remaining := []string{}
+	for i, name := range notification.Status.ObjectBucketClaims {
+		if keep[name] {
+			remaining = append(remaining, name)
+			continue
+		}
+		obc := &bktv1alpha1.ObjectBucketClaim{}
+		err := r.client.Get(r.opManagerContext, types.NamespacedName{Name: name, Namespace: notification.Namespace}, obc)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				logger.Infof("object bucket claim %q of ceph bucket notification %q not found", name, notification.Name)
+				continue
+			}
+			return append(remaining, notification.Status.ObjectBucketClaims[i:]...), errors.Wrapf(err, "failed to get object bucket claim %q", name)
+		}
+		bucket, err := r.bucketClient(opsContext, obc)
+		if err == nil {
+			err = bucket.deleteNotification(r.opManagerContext, notification.Name)
+		}
+		if err != nil {
+			return append(remaining, notification.Status.ObjectBucketClaims[i:]...), err
+		}
+		logger.Infof("deleted ceph bucket notification %q from bucket %q of object bucket claim %q", notification.Name, obc.Spec.BucketName, obc.Name)
+	}
+	return remaining, nil
+}
+
+// bucketClient returns the client of the bucket of the ObjectBucketClaim with the keys of its secret
+func (r *ReconcileBucketNotification) bucketClient(opsContext *object.AdminOpsContext, obc *bktv1alpha1.ObjectBucketClaim) (*bucketClient, error) {
+	secret := &corev1.Secret{}
+	err := r.client.Get(r.opManagerContext, types.NamespacedName{Name: obc.Name, Namespace: obc.Namespace}, secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the secret of object bucket claim %q", obc.Name)
+	}
+	accessKey, secretKey := string(secret.Data[obcAccessKeyName]), string(secret.Data[obcSecretKeyName])
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.Errorf("missing the keys in the secret of object bucket claim %q", obc.Name)
+	}
+	return &bucketClient{opsContext: opsContext, bucket: obc.Spec.BucketName, accessKey: accessKey, secretKey: secretKey}, nil
+}
+
+// appendName appends the name to the sorted names if it is not there yet
+func appendName(names []string, name string) []string {
+	i := sort.SearchStrings(names, name)
+	if i < len(names) && names[i] == name {
+		return names
+	}
+	names = append(names, "")
+	copy(names[i+1:], names[i:])
+	names[i] = name
+	return names
+}
+
+func (r *ReconcileBucketNotification) removeFinalizer(notification *cephv1.CephBucketNotification) error {
+	if err := opcontroller.RemoveFinalizer(r.client, notification); err != nil {
+		return errors.Wrap(err, "failed to remove finalizer")
+	}
+	return nil
+}
+
+// updateStatus updates an object with a given status. The ObjectBucketClaims are kept when they are nil.
+func (r *ReconcileBucketNotification) updateStatus(name types.NamespacedName, phase cephv1.ConditionType, message string, obcs []string) {
+	notification := &cephv1.CephBucketNotification{}
+	if err := r.client.Get(r.opManagerContext, name, notification); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketNotification resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph bucket notification %q to update status to %q. %v", name, phase, err)
+		return
+	}
+	if notification.Status == nil {
+		notification.Status = &cephv1.BucketNotificationStatus{}
+	}
+
+	notification.Status.Phase = phase
+	notification.Status.Message = message
+	if obcs != nil {
+		notification.Status.ObjectBucketClaims = obcs
+	}
+	if err := reporting.UpdateStatus(r.client, notification); err != nil {
+		logger.Errorf("failed to set ceph bucket notification %q status to %q. %v", name, phase, err)
+		return
+	}
+	logger.Debugf("ceph bucket notification %q status updated to %q", name, phase)
+}
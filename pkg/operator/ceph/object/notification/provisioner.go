@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+)
+
+// notificationConfiguration is the notification configuration of a bucket, with the metadata and tags filters of
+// the rgw, which the S3 client of the aws sdk does not support
+type notificationConfiguration struct {
+	XMLName             xml.Name             `xml:"NotificationConfiguration"`
+	TopicConfigurations []topicConfiguration `xml:"TopicConfiguration"`
+}
+
+type topicConfiguration struct {
+	ID     string              `xml:"Id"`
+	Topic  string              `xml:"Topic"`
+	Events []string            `xml:"Event"`
+	Filter *notificationFilter `xml:"Filter,omitempty"`
+}
+
+type notificationFilter struct {
+	Key      *filterRules `xml:"S3Key,omitempty"`
+	Metadata *filterRules `xml:"S3Metadata,omitempty"`
+	Tags     *filterRules `xml:"S3Tags,omitempty"`
+}
+
+type filterRules struct {
+	Rules []filterRule `xml:"FilterRule"`
+}
+
+type filterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+// bucketClient sends the notification requests of a bucket with the keys of the owner of the bucket
+type bucketClient struct {
+	opsContext *object.AdminOpsContext
+	bucket     string
+	accessKey  string
+	secretKey  string
+}
+
+// newTopicConfiguration returns the configuration of the notification on a bucket
+func newTopicConfiguration(notification *cephv1.CephBucketNotification, topicARN string) topicConfiguration {
+	config := topicConfiguration{
+		ID:    notification.Name,
+		Topic: topicARN,
+	}
+	for _, event := range notification.Spec.Events {
+		config.Events = append(config.Events, string(event))
+	}
+
+	filter := notification.Spec.Filter
+	if filter == nil {
+		return config
+	}
+	config.Filter = &notificationFilter{}
+	if len(filter.KeyFilters) > 0 {
+		config.Filter.Key = &filterRules{}
+		for _, rule := range filter.KeyFilters {
+			config.Filter.Key.Rules = append(config.Filter.Key.Rules, filterRule{Name: rule.Name, Value: rule.Value})
+		}
+	}
+	if len(filter.MetadataFilters) > 0 {
+		config.Filter.Metadata = &filterRules{}
+		for _, rule := range filter.MetadataFilters {
+			config.Filter.Metadata.Rules = append(config.Filter.Metadata.Rules, filterRule{Name: rule.Name, Value: rule.Value})
+		}
+	}
+	if len(filter.TagFilters) > 0 {
+		config.Filter.Tags = &filterRules{}
+		for _, rule := range filter.TagFilters {
+			config.Filter.Tags.Rules = append(config.Filter.Tags.Rules, filterRule{Name: rule.Name, Value: rule.Value})
+		}
+	}
+	return config
+}
+
+func (c *bucketClient) send(ctx context.Context, method, query string, body []byte) ([]byte, error) {
+	requestURL := fmt.Sprintf("%s/%s?%s", c.opsContext.Endpoint, url.PathEscape(c.bucket), query)
+	return object.SendSignedRequest(ctx, c.opsContext.AdminOpsClient.HTTPClient, c.accessKey, c.secretKey, method, requestURL, body)
+}
+
+// putNotification adds or updates the notification of the bucket. The notifications of the bucket are put back with
+// the notification, since the newer versions of the rgw replace all the notifications of the bucket.
+func (c *bucketClient) putNotification(ctx context.Context, config topicConfiguration) error {
+	body, err := c.send(ctx, http.MethodGet, "notification", nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the notifications of bucket %q", c.bucket)
+	}
+	current := notificationConfiguration{}
+	if len(body) > 0 {
+		if err := xml.Unmarshal(body, &current); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal the notifications of bucket %q. %s", c.bucket, string(body))
+		}
+	}
+
+	updated := notificationConfiguration{}
+	for _, existing := range current.TopicConfigurations {
+		if existing.ID != config.ID {
+			updated.TopicConfigurations = append(updated.TopicConfigurations, existing)
+		}
+	}
+	updated.TopicConfigurations = append(updated.TopicConfigurations, config)
+	body, err = xml.Marshal(updated)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the notifications of bucket %q", c.bucket)
+	}
+	if _, err := c.send(ctx, http.MethodPut, "notification", body); err != nil {
+		return errors.Wrapf(err, "failed to put notification %q on bucket %q", config.ID, c.bucket)
+	}
+	return nil
+}
+
+// deleteNotification deletes the notification of the bucket
+func (c *bucketClient) deleteNotification(ctx context.Context, id string) error {
+	if _, err := c.send(ctx, http.MethodDelete, "notification="+url.QueryEscape(id), nil); err != nil {
+		return errors.Wrapf(err, "failed to delete notification %q of bucket %q", id, c.bucket)
+	}
+	return nil
+}
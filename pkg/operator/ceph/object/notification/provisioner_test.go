@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const topicARN = "arn:aws:sns:my-store::my-topic"
+
+func newNotification() *cephv1.CephBucketNotification {
+	return &cephv1.CephBucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-notification", Namespace: "my-app"},
+		Spec: cephv1.BucketNotificationSpec{
+			Topic:  "my-topic",
+			Events: []cephv1.BucketNotificationEvent{"s3:ObjectCreated:*", "s3:ObjectRemoved:Delete"},
+		},
+	}
+}
+
+func TestNewTopicConfiguration(t *testing.T) {
+	notification := newNotification()
+	config := newTopicConfiguration(notification, topicARN)
+	assert.Equal(t, topicConfiguration{
+		ID:     "my-notification",
+		Topic:  topicARN,
+		Events: []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:Delete"},
+	}, config)
+
+	notification.Spec.Filter = &cephv1.NotificationFilterSpec{
+		KeyFilters:      []cephv1.NotificationKeyFilterRule{{Name: "prefix", Value: "images/"}},
+		MetadataFilters: []cephv1.NotificationFilterRule{{Name: "x-amz-meta-color", Value: "blue"}},
+	}
+	config = newTopicConfiguration(notification, topicARN)
+	body, err := xml.Marshal(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "<topicConfiguration><Id>my-notification</Id><Topic>"+topicARN+"</Topic>"+
+		"<Event>s3:ObjectCreated:*</Event><Event>s3:ObjectRemoved:Delete</Event>"+
+		"<Filter><S3Key><FilterRule><Name>prefix</Name><Value>images/</Value></FilterRule></S3Key>"+
+		"<S3Metadata><FilterRule><Name>x-amz-meta-color</Name><Value>blue</Value></FilterRule></S3Metadata></Filter>"+
+		"</topicConfiguration>", string(body))
+}
+
+func TestBucketClient(t *testing.T) {
+	var requests []string
+	var putBody string
+	current := ""
+	mockClient := &object.MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, fmt.Sprintf("%s %s?%s", req.Method, req.URL.Path, req.URL.RawQuery))
+			assert.Contains(t, req.Header.Get("Authorization"), "Credential=BUCKETOWNER/")
+			body := ""
+			switch req.Method {
+			case http.MethodGet:
+				body = current
+			case http.MethodPut:
+				b, err := ioutil.ReadAll(req.Body)
+				assert.NoError(t, err)
+				putBody = string(b)
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil
+		},
+	}
+	adminClient, err := admin.New("rook-ceph-rgw-my-store.rook-ceph.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", mockClient)
+	assert.NoError(t, err)
+	opsContext := &object.AdminOpsContext{
+		Context:        object.Context{Endpoint: "http://rook-ceph-rgw-my-store.rook-ceph.svc:80"},
+		AdminOpsClient: adminClient,
+	}
+	bucket := &bucketClient{opsContext: opsContext, bucket: "my-bucket", accessKey: "BUCKETOWNER", secretKey: "secret"}
+	config := newTopicConfiguration(newNotification(), topicARN)
+
+	t.Run("put the first notification", func(t *testing.T) {
+		assert.NoError(t, bucket.putNotification(context.TODO(), config))
+		assert.Equal(t, []string{"GET /my-bucket?notification=", "PUT /my-bucket?notification="}, requests)
+		assert.Equal(t, "<NotificationConfiguration><TopicConfiguration><Id>my-notification</Id><Topic>"+topicARN+"</Topic>"+
+			"<Event>s3:ObjectCreated:*</Event><Event>s3:ObjectRemoved:Delete</Event></TopicConfiguration></NotificationConfiguration>", putBody)
+	})
+
+	t.Run("other notifications are kept", func(t *testing.T) {
+		current = `<NotificationConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">` +
+			`<TopicConfiguration><Id>other</Id><Topic>arn:aws:sns:my-store::other</Topic><Event>s3:ObjectCreated:Put</Event></TopicConfiguration>` +
+			`<TopicConfiguration><Id>my-notification</Id><Topic>arn:aws:sns:my-store::old</Topic><Event>s3:ObjectCreated:Put</Event></TopicConfiguration>` +
+			`</NotificationConfiguration>`
+		assert.NoError(t, bucket.putNotification(context.TODO(), config))
+		updated := notificationConfiguration{}
+		assert.NoError(t, xml.Unmarshal([]byte(putBody), &updated))
+		assert.Len(t, updated.TopicConfigurations, 2)
+		assert.Equal(t, "other", updated.TopicConfigurations[0].ID)
+		assert.Equal(t, config, updated.TopicConfigurations[1])
+	})
+
+	t.Run("delete the notification", func(t *testing.T) {
+		requests = nil
+		assert.NoError(t, bucket.deleteNotification(context.TODO(), "my-notification"))
+		assert.Equal(t, []string{"DELETE /my-bucket?notification=my-notification"}, requests)
+	})
+}
+
+func TestAppendName(t *testing.T) {
+	names := appendName(nil, "obc-b")
+	names = appendName(names, "obc-a")
+	names = appendName(names, "obc-c")
+	names = appendName(names, "obc-b")
+	assert.Equal(t, []string{"obc-a", "obc-b", "obc-c"}, names)
+}
@@ -72,12 +72,16 @@ type idType struct {
 }
 
 type zoneGroupType struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Endpoints    []string   `json:"endpoints"`
 	MasterZoneID string     `json:"master_zone"`
 	IsMaster     string     `json:"is_master"`
 	Zones        []zoneType `json:"zones"`
 }
 
 type zoneType struct {
+	ID        string   `json:"id"`
 	Name      string   `json:"name"`
 	Endpoints []string `json:"endpoints"`
 }
@@ -926,6 +930,29 @@ func enableRGWDashboard(context *Context) error {
 	return nil
 }
 
+// updateDashboardObjectGatewayCredentials configures the object gateway credentials of the dashboard again
+// when the access key configured in the dashboard is not the key of the dashboard rgw user anymore, e.g.
+// after the keys of the user were rotated
+func updateDashboardObjectGatewayCredentials(context *Context) error {
+	args := []string{"dashboard", "get-rgw-api-access-key"}
+	configuredKey, err := cephclient.NewCephCommand(context.Context, context.clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the object gateway access key of the dashboard")
+	}
+
+	user, errCode, err := GetUser(context, mgr.DashboardObjectGatewayUser)
+	if err != nil && errCode != RGWErrorNotFound {
+		return errors.Wrapf(err, "failed to get rgw user %q", mgr.DashboardObjectGatewayUser)
+	}
+	// the configured key is a json map of the keys by realm on multisite clusters
+	if user != nil && user.AccessKey != nil && *user.AccessKey != "" && strings.Contains(string(configuredKey), *user.AccessKey) {
+		return nil
+	}
+
+	logger.Infof("the access key of rgw user %q is not configured in the dashboard, updating the object gateway credentials of the dashboard", mgr.DashboardObjectGatewayUser)
+	return mgr.SetDashboardObjectGatewayCredentials(context.Context, context.clusterInfo)
+}
+
 func disableRGWDashboard(context *Context) {
 	logger.Info("disabling the dashboard api user and secret key")
 
@@ -291,3 +291,44 @@ func TestDashboard(t *testing.T) {
 	assert.True(t, checkdashboard)
 	disableRGWDashboard(objContext)
 }
+
+func TestUpdateDashboardObjectGatewayCredentials(t *testing.T) {
+	configuredKey := access_key
+	setCredentials := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "dashboard" && args[1] == "get-rgw-api-access-key" {
+				return configuredKey, nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "user" {
+				return dashboardAdminCreateJSON, nil
+			}
+			if args[0] == "dashboard" && args[1] == "set-rgw-credentials" {
+				setCredentials++
+			}
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{
+		Namespace:   "mycluster",
+		CephVersion: cephver.Pacific,
+		Context:     context.TODO(),
+	}, "myobject")
+
+	// the configured key is the key of the user
+	assert.NoError(t, updateDashboardObjectGatewayCredentials(objContext))
+	assert.Equal(t, 0, setCredentials)
+
+	// the keys of the multisite realms are configured as json
+	configuredKey = fmt.Sprintf(`{"realm-a": %q}`, access_key)
+	assert.NoError(t, updateDashboardObjectGatewayCredentials(objContext))
+	assert.Equal(t, 0, setCredentials)
+
+	// the keys of the user were rotated
+	configuredKey = "OLDACCESSKEY"
+	assert.NoError(t, updateDashboardObjectGatewayCredentials(objContext))
+	assert.Equal(t, 1, setCredentials)
+}
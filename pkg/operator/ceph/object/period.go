@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type periodType struct {
+	ID              string `json:"id"`
+	Epoch           int    `json:"epoch"`
+	MasterZoneGroup string `json:"master_zonegroup"`
+	MasterZone      string `json:"master_zone"`
+	PeriodMap       struct {
+		ZoneGroups []zoneGroupType `json:"zonegroups"`
+	} `json:"period_map"`
+}
+
+// DecodePeriod parses the output of `radosgw-admin period get`
+func DecodePeriod(data string) (periodType, error) {
+	var period periodType
+	err := json.Unmarshal([]byte(data), &period)
+	if err != nil {
+		return period, errors.Wrap(err, "failed to unmarshal json")
+	}
+
+	return period, nil
+}
+
+// GetPeriod returns the current period of the realm
+func GetPeriod(objContext *Context, realmName string) (periodType, error) {
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realmName)
+	output, err := RunAdminCommandNoMultisite(objContext, true, "period", "get", realmArg)
+	if err != nil {
+		return periodType{}, errors.Wrapf(err, "failed to get the period of realm %q", realmName)
+	}
+	period, err := DecodePeriod(output)
+	if err != nil {
+		return periodType{}, errors.Wrap(err, "failed to parse `radosgw-admin period get` output")
+	}
+	return period, nil
+}
+
+// CommitPeriod commits the changes of the configuration of the realm to a new period. It is only called when the
+// configuration drifted from the current period, see PeriodDrift, so that a failed commit is retried by the next
+// reconcile without incrementing the epoch of the period once there is nothing left to commit.
+func CommitPeriod(objContext *Context, realmName, drift string, args ...string) (periodType, error) {
+	logger.Infof("committing the period of realm %q. %s", realmName, drift)
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realmName)
+	commitArgs := append([]string{"period", "update", "--commit", realmArg}, args...)
+	output, err := RunAdminCommandNoMultisite(objContext, false, commitArgs...)
+	if err != nil {
+		return periodType{}, errors.Wrapf(err, "failed to commit the period of realm %q for reason %q", realmName, output)
+	}
+	period, err := GetPeriod(objContext, realmName)
+	if err != nil {
+		return periodType{}, err
+	}
+	logger.Infof("committed period %q epoch %d of realm %q", period.ID, period.Epoch, realmName)
+	return period, nil
+}
+
+// PeriodDrift returns a description of the changes of the configuration of the zone group not committed to the
+// period, or an empty string if the period is up to date
+func PeriodDrift(period periodType, zoneGroup zoneGroupType) string {
+	var committed *zoneGroupType
+	for i, zg := range period.PeriodMap.ZoneGroups {
+		if zg.ID == zoneGroup.ID {
+			committed = &period.PeriodMap.ZoneGroups[i]
+			break
+		}
+	}
+	if committed == nil {
+		return fmt.Sprintf("zone group %q is not in the period", zoneGroup.Name)
+	}
+	if committed.MasterZoneID != zoneGroup.MasterZoneID {
+		return fmt.Sprintf("the master zone of zone group %q changed from %q to %q", zoneGroup.Name, committed.MasterZoneID, zoneGroup.MasterZoneID)
+	}
+	if !sameEndpoints(committed.Endpoints, zoneGroup.Endpoints) {
+		return fmt.Sprintf("the endpoints of zone group %q changed from %v to %v", zoneGroup.Name, committed.Endpoints, zoneGroup.Endpoints)
+	}
+	committedZones := map[string]zoneType{}
+	for _, zone := range committed.Zones {
+		committedZones[zone.ID] = zone
+	}
+	for _, zone := range zoneGroup.Zones {
+		committedZone, ok := committedZones[zone.ID]
+		if !ok {
+			return fmt.Sprintf("zone %q is not in the period", zone.Name)
+		}
+		if !sameEndpoints(committedZone.Endpoints, zone.Endpoints) {
+			return fmt.Sprintf("the endpoints of zone %q changed from %v to %v", zone.Name, committedZone.Endpoints, zone.Endpoints)
+		}
+		delete(committedZones, zone.ID)
+	}
+	for _, zone := range committedZones {
+		return fmt.Sprintf("zone %q was removed from zone group %q", zone.Name, zoneGroup.Name)
+	}
+	return ""
+}
+
+// SyncZoneGroupEndpoints points the endpoints of the zone group to the endpoints of its master zone, which can
+// change when the gateways of the master zone change or when another zone is promoted. Returns whether the zone
+// group was modified.
+func SyncZoneGroupEndpoints(objContext *Context, realmName string, zoneGroup *zoneGroupType) (bool, error) {
+	var masterEndpoints []string
+	for _, zone := range zoneGroup.Zones {
+		if zone.ID == zoneGroup.MasterZoneID {
+			masterEndpoints = zone.Endpoints
+		}
+	}
+	if len(masterEndpoints) == 0 || sameEndpoints(masterEndpoints, zoneGroup.Endpoints) {
+		return false, nil
+	}
+
+	logger.Infof("setting the endpoints of zone group %q to the endpoints %v of its master zone", zoneGroup.Name, masterEndpoints)
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realmName)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zoneGroup.Name)
+	endpointArg := fmt.Sprintf("--endpoints=%s", strings.Join(masterEndpoints, ","))
+	output, err := RunAdminCommandNoMultisite(objContext, false, "zonegroup", "modify", realmArg, zoneGroupArg, endpointArg)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to set the endpoints of zone group %q for reason %q", zoneGroup.Name, output)
+	}
+	zoneGroup.Endpoints = masterEndpoints
+	return true, nil
+}
+
+// ReconcilePeriod points the endpoints of the zone group to its master zone and commits the changes of the zone
+// group not in the current period of the realm. The zone group is only committed once it has a master zone. Returns
+// the current period, and the changes not committed yet if the zone group has no master zone or the commit failed.
+func ReconcilePeriod(objContext *Context, realmName, zoneGroupName, zoneName string) (periodType, string, error) {
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realmName)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zoneGroupName)
+
+	output, err := RunAdminCommandNoMultisite(objContext, true, "zonegroup", "get", realmArg, zoneGroupArg)
+	if err != nil {
+		return periodType{}, "", errors.Wrapf(err, "failed to get ceph zone group %q", zoneGroupName)
+	}
+	zoneGroup, err := DecodeZoneGroupConfig(output)
+	if err != nil {
+		return periodType{}, "", errors.Wrap(err, "failed to parse `radosgw-admin zonegroup get` output")
+	}
+	if _, err := SyncZoneGroupEndpoints(objContext, realmName, &zoneGroup); err != nil {
+		return periodType{}, "", err
+	}
+
+	period, err := GetPeriod(objContext, realmName)
+	if err != nil {
+		return periodType{}, "", err
+	}
+	drift := PeriodDrift(period, zoneGroup)
+	if drift == "" {
+		return period, "", nil
+	}
+	if zoneGroup.MasterZoneID == "" {
+		drift = fmt.Sprintf("%s. waiting for the master zone of the zone group to commit the period", drift)
+		logger.Infof("not committing the period of realm %q yet. %s", realmName, drift)
+		return period, drift, nil
+	}
+
+	commitArgs := []string{zoneGroupArg}
+	if zoneName != "" {
+		commitArgs = append(commitArgs, fmt.Sprintf("--rgw-zone=%s", zoneName))
+	}
+	committed, err := CommitPeriod(objContext, realmName, drift, commitArgs...)
+	if err != nil {
+		return period, drift, err
+	}
+	return committed, "", nil
+}
+
+func sameEndpoints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
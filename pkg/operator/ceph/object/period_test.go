@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodDrift(t *testing.T) {
+	zoneGroup := zoneGroupType{
+		ID:           "zg",
+		Name:         "zonegroup-a",
+		Endpoints:    []string{"http://a:80", "http://b:80"},
+		MasterZoneID: "zone-a",
+		Zones:        []zoneType{{ID: "zone-a", Name: "zone-a", Endpoints: []string{"http://a:80", "http://b:80"}}},
+	}
+	period := periodType{ID: "period", Epoch: 2}
+	assert.Contains(t, PeriodDrift(period, zoneGroup), "is not in the period")
+
+	committed := zoneGroup
+	committed.Endpoints = []string{"http://b:80", "http://a:80"}
+	period.PeriodMap.ZoneGroups = []zoneGroupType{committed}
+	assert.Equal(t, "", PeriodDrift(period, zoneGroup))
+
+	zoneGroup.MasterZoneID = "zone-b"
+	assert.Contains(t, PeriodDrift(period, zoneGroup), "master zone")
+	zoneGroup.MasterZoneID = "zone-a"
+
+	zoneGroup.Endpoints = []string{"http://c:80"}
+	assert.Contains(t, PeriodDrift(period, zoneGroup), "endpoints of zone group")
+	zoneGroup.Endpoints = committed.Endpoints
+
+	zoneGroup.Zones = []zoneType{{ID: "zone-a", Name: "zone-a", Endpoints: []string{"http://c:80"}}}
+	assert.Contains(t, PeriodDrift(period, zoneGroup), "endpoints of zone \"zone-a\"")
+
+	zoneGroup.Zones = append(committed.Zones, zoneType{ID: "zone-b", Name: "zone-b"})
+	assert.Contains(t, PeriodDrift(period, zoneGroup), "zone \"zone-b\" is not in the period")
+
+	zoneGroup.Zones = nil
+	assert.Contains(t, PeriodDrift(period, zoneGroup), "was removed")
+}
+
+func TestReconcilePeriod(t *testing.T) {
+	zoneGroupJSON := `{"id": "zg", "name": "zonegroup-a", "endpoints": ["http://a:80"], "master_zone": "zone-a",
+		"zones": [{"id": "zone-a", "name": "zone-a", "endpoints": ["http://b:80"]}]}`
+	periodJSON := `{"id": "period", "epoch": 2, "period_map": {"zonegroups": []}}`
+	var commands []string
+	commitErr := errors.New("failed to reach the master zone")
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "zonegroup" && args[1] == "get" {
+				return zoneGroupJSON, nil
+			}
+			if args[0] == "period" && args[1] == "get" {
+				return periodJSON, nil
+			}
+			commands = append(commands, strings.Join(args[0:2], " "))
+			if args[0] == "period" && args[1] == "update" {
+				if commitErr != nil {
+					return "", commitErr
+				}
+				periodJSON = `{"id": "period", "epoch": 3, "period_map": {"zonegroups": [{"id": "zg", "name": "zonegroup-a",
+					"endpoints": ["http://b:80"], "master_zone": "zone-a",
+					"zones": [{"id": "zone-a", "name": "zone-a", "endpoints": ["http://b:80"]}]}]}}`
+			}
+			if args[0] == "zonegroup" && args[1] == "modify" {
+				zoneGroupJSON = strings.Replace(zoneGroupJSON, `"endpoints": ["http://a:80"]`, `"endpoints": ["http://b:80"]`, 1)
+			}
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, client.AdminClusterInfo("mycluster"), "zonegroup-a")
+
+	// the endpoints of the zone group follow the master zone, and the commit fails
+	period, pending, err := ReconcilePeriod(objContext, "realm-a", "zonegroup-a", "")
+	assert.Error(t, err)
+	assert.Equal(t, 2, period.Epoch)
+	assert.Contains(t, pending, "is not in the period")
+	assert.Equal(t, []string{"zonegroup modify", "period update"}, commands)
+
+	// the commit is retried
+	commands = nil
+	commitErr = nil
+	period, pending, err = ReconcilePeriod(objContext, "realm-a", "zonegroup-a", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, period.Epoch)
+	assert.Equal(t, "", pending)
+	assert.Equal(t, []string{"period update"}, commands)
+
+	// nothing to commit once the period is up to date
+	commands = nil
+	period, pending, err = ReconcilePeriod(objContext, "realm-a", "zonegroup-a", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, period.Epoch)
+	assert.Equal(t, "", pending)
+	assert.Empty(t, commands)
+
+	// the zone group is not committed without a master zone
+	zoneGroupJSON = `{"id": "zg2", "name": "zonegroup-b", "endpoints": [], "master_zone": "", "zones": []}`
+	_, pending, err = ReconcilePeriod(objContext, "realm-a", "zonegroup-b", "")
+	assert.NoError(t, err)
+	assert.Contains(t, pending, "waiting for the master zone")
+	assert.Empty(t, commands)
+}
@@ -18,6 +18,7 @@ package object
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -254,12 +255,18 @@ func (ps *PolicyStatement) WithSID(sid string) *PolicyStatement {
 
 const awsPrinciple = "AWS"
 const arnPrefixPrinciple = "arn:aws:iam:::user/%s"
+const arnPrefixTenantPrinciple = "arn:aws:iam::%s:user/%s"
 const arnPrefixResource = "arn:aws:s3:::%s"
 
 // ForPrincipals adds users to the PolicyStatement
 func (ps *PolicyStatement) ForPrincipals(users ...string) *PolicyStatement {
 	principals := ps.Principal[awsPrinciple]
 	for _, u := range users {
+		// the users of a tenant are qualified with the tenant in the arn
+		if tenant := UserTenant(u); tenant != "" {
+			principals = append(principals, fmt.Sprintf(arnPrefixTenantPrinciple, tenant, strings.TrimPrefix(u, tenant+"$")))
+			continue
+		}
 		principals = append(principals, fmt.Sprintf(arnPrefixPrinciple, u))
 	}
 	ps.Principal[awsPrinciple] = principals
@@ -82,6 +82,7 @@ type ReconcileObjectRealm struct {
 // Add creates a new CephObjectRealm Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext))
 }
 
@@ -198,6 +199,14 @@ func (r *ReconcileObjectRealm) reconcile(request reconcile.Request) (reconcile.R
 		}
 	}
 
+	// Track the current period of the realm
+	objContext := object.NewContext(r.context, r.clusterInfo, cephObjectRealm.Name)
+	period, err := object.GetPeriod(objContext, cephObjectRealm.Name)
+	if err != nil {
+		return r.setFailedStatus(request.NamespacedName, "failed to get the period of ceph realm", err)
+	}
+	r.updatePeriodStatus(request.NamespacedName, period.ID, period.Epoch)
+
 	// Set Ready status, we are done reconciling
 	r.updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
@@ -290,6 +299,11 @@ func (r *ReconcileObjectRealm) createRealmKeys(realm *cephv1.CephObjectRealm) (r
 	}
 
 	if _, err = r.context.Clientset.CoreV1().Secrets(realm.Namespace).Create(r.opManagerContext, secret, metav1.CreateOptions{}); err != nil {
+		if kerrors.IsAlreadyExists(err) {
+			// the keys were created by a previous reconcile, they must not change once the realm exists
+			logger.Debugf("secrets for keys already exist for realm %q", realm.Name)
+			return reconcile.Result{}, nil
+		}
 		return reconcile.Result{}, errors.Wrap(err, "failed to save rgw secrets")
 	}
 	logger.Infof("secrets for keys have been created for realm %q", realm.Name)
@@ -325,7 +339,7 @@ func (r *ReconcileObjectRealm) updateStatus(client client.Client, name types.Nam
 		return
 	}
 	if objectRealm.Status == nil {
-		objectRealm.Status = &cephv1.Status{}
+		objectRealm.Status = &cephv1.MultisiteStatus{}
 	}
 
 	objectRealm.Status.Phase = status
@@ -335,3 +349,30 @@ func (r *ReconcileObjectRealm) updateStatus(client client.Client, name types.Nam
 	}
 	logger.Debugf("object realm %q status updated to %q", name, status)
 }
+
+// updatePeriodStatus updates the current period of a realm in its status
+func (r *ReconcileObjectRealm) updatePeriodStatus(name types.NamespacedName, period string, epoch int) {
+	objectRealm := &cephv1.CephObjectRealm{}
+	if err := r.client.Get(r.opManagerContext, name, objectRealm); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectRealm resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object realm %q to update the period status. %v", name, err)
+		return
+	}
+	if objectRealm.Status == nil {
+		objectRealm.Status = &cephv1.MultisiteStatus{}
+	}
+	if objectRealm.Status.Period == period && objectRealm.Status.PeriodEpoch == epoch {
+		return
+	}
+
+	objectRealm.Status.Period = period
+	objectRealm.Status.PeriodEpoch = epoch
+	if err := reporting.UpdateStatus(r.client, objectRealm); err != nil {
+		logger.Errorf("failed to set object realm %q period status. %v", name, err)
+		return
+	}
+	logger.Infof("object realm %q is at period %q epoch %d", name, period, epoch)
+}
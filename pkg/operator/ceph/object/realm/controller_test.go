@@ -49,6 +49,16 @@ var (
 		"current_period": "df665ecb-1762-47a9-9c66-f938d251c02a",
 		"epoch": 2
 	}`
+	periodGetJSON = `{
+		"id": "df665ecb-1762-47a9-9c66-f938d251c02a",
+		"epoch": 3,
+		"period_map": {
+			"zonegroups": []
+		},
+		"master_zonegroup": "",
+		"master_zone": "",
+		"realm_name": "realm-a"
+	}`
 )
 
 func TestCephObjectRealmController(t *testing.T) {
@@ -147,6 +157,9 @@ func TestCephObjectRealmController(t *testing.T) {
 			if args[0] == "realm" && args[1] == "get" {
 				return realmGetJSON, nil
 			}
+			if args[0] == "period" && args[1] == "get" {
+				return periodGetJSON, nil
+			}
 			return "", nil
 		},
 	}
@@ -161,6 +174,9 @@ func TestCephObjectRealmController(t *testing.T) {
 	assert.False(t, res.Requeue)
 	err = r.client.Get(context.TODO(), req.NamespacedName, objectRealm)
 	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReadyStatus, objectRealm.Status.Phase)
+	assert.Equal(t, "df665ecb-1762-47a9-9c66-f938d251c02a", objectRealm.Status.Period)
+	assert.Equal(t, 3, objectRealm.Status.PeriodEpoch)
 }
 
 func TestPullCephRealm(t *testing.T) {
@@ -197,6 +213,16 @@ func TestCreateRealmKeys(t *testing.T) {
 	res, err := r.createRealmKeys(objectRealm)
 	assert.NoError(t, err)
 	assert.False(t, res.Requeue)
+
+	// the keys of the realm are kept by the next reconciles
+	secret, err := r.context.Clientset.CoreV1().Secrets(namespace).Get(context.TODO(), name+"-keys", metav1.GetOptions{})
+	assert.NoError(t, err)
+	res, err = r.createRealmKeys(objectRealm)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	existing, err := r.context.Clientset.CoreV1().Secrets(namespace).Get(context.TODO(), name+"-keys", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Data, existing.Data)
 }
 
 func TestCreateCephRealm(t *testing.T) {
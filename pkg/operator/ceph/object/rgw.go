@@ -30,6 +30,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/pool"
@@ -50,6 +51,8 @@ type clusterConfig struct {
 	ownerInfo   *k8sutil.OwnerInfo
 	DataPathMap *config.DataPathMap
 	client      client.Client
+	// instanceGroup is the instance group of the rgw pods, nil for the gateway pods
+	instanceGroup *cephv1.RGWInstanceGroupSpec
 }
 
 type rgwConfig struct {
@@ -69,10 +72,20 @@ func (c *clusterConfig) createOrUpdateStore(realmName, zoneGroupName, zoneName s
 		return errors.Wrap(err, "failed to start rgw pods")
 	}
 
-	objContext := NewContext(c.context, c.clusterInfo, c.store.Namespace)
-	err := enableRGWDashboard(objContext)
-	if err != nil {
-		logger.Warningf("failed to enable dashboard for rgw. %v", err)
+	if err := c.startInstanceGroups(realmName, zoneGroupName, zoneName); err != nil {
+		return errors.Wrap(err, "failed to start the rgw pods of the instance groups")
+	}
+
+	if mgr.DashboardObjectGatewayEnabled(c.clusterSpec, c.clusterInfo) {
+		if err := mgr.SetDashboardObjectGatewayCredentials(c.context, c.clusterInfo); err != nil {
+			logger.Warningf("failed to configure the object gateway credentials of the dashboard. %v", err)
+		}
+	} else {
+		objContext := NewContext(c.context, c.clusterInfo, c.store.Namespace)
+		err := enableRGWDashboard(objContext)
+		if err != nil {
+			logger.Warningf("failed to enable dashboard for rgw. %v", err)
+		}
 	}
 
 	logger.Infof("created object store %q in namespace %q", c.store.Name, c.store.Namespace)
@@ -94,8 +107,6 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 		desiredRgwInstances = 1
 	}
 	for i := 0; i < desiredRgwInstances; i++ {
-		var err error
-
 		daemonLetterID := k8sutil.IndexToName(i)
 		// Each rgw is id'ed by <store_name>-<letterID>
 		daemonName := fmt.Sprintf("%s-%s", c.store.Name, daemonLetterID)
@@ -110,62 +121,8 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 			Zone:         zoneName,
 		}
 
-		// We set the owner reference of the Secret to the Object controller instead of the replicaset
-		// because we watch for that resource and reconcile if anything happens to it
-		_, err = c.generateKeyring(rgwConfig)
-		if err != nil {
-			return errors.Wrap(err, "failed to create rgw keyring")
-		}
-
-		// Set the rgw config flags
-		// Previously we were checking if the deployment was present, if not we would set the config flags
-		// Which means that we would only set the flag on newly created CephObjectStore CR
-		// Unfortunately, on upgrade we would not set the flags which is not ideal for old clusters where we were no setting those flags
-		// The KV supports setting those flags even if the RGW is running
-		logger.Info("setting rgw config flags")
-		err = c.setDefaultFlagsMonConfigStore(rgwConfig.ResourceName)
-		if err != nil {
-			// Getting EPERM typically happens when the flag may not be modified at runtime
-			// This is fine to ignore
-			code, ok := exec.ExitStatus(err)
-			if ok && code != int(syscall.EPERM) {
-				return errors.Wrap(err, "failed to set default rgw config options")
-			}
-		}
-
-		// Create deployment
-		deployment, err := c.createDeployment(rgwConfig)
-		if err != nil {
-			return nil
-		}
-		logger.Infof("object store %q deployment %q created", c.store.Name, deployment.Name)
-
-		// Set owner ref to cephObjectStore object
-		err = c.ownerInfo.SetControllerReference(deployment)
-		if err != nil {
-			return errors.Wrapf(err, "failed to set owner reference for rgw deployment %q", deployment.Name)
-		}
-
-		// Set the deployment hash as an annotation
-		err = patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment)
-		if err != nil {
-			return errors.Wrapf(err, "failed to set annotation for deployment %q", deployment.Name)
-		}
-
-		_, createErr := c.context.Clientset.AppsV1().Deployments(c.store.Namespace).Create(c.clusterInfo.Context, deployment, metav1.CreateOptions{})
-		if createErr != nil {
-			if !kerrors.IsAlreadyExists(createErr) {
-				return errors.Wrap(createErr, "failed to create rgw deployment")
-			}
-			logger.Infof("object store %q deployment %q already exists. updating if needed", c.store.Name, deployment.Name)
-			if err := updateDeploymentAndWait(c.context, c.clusterInfo, deployment, config.RgwType, daemonLetterID, c.clusterSpec.SkipUpgradeChecks, c.clusterSpec.ContinueUpgradeAfterChecksEvenIfNotHealthy); err != nil {
-				return errors.Wrapf(err, "failed to update object store %q deployment %q", c.store.Name, deployment.Name)
-			}
-		}
-
-		// Generate the mime.types file after the rep. controller as well for the same reason as keyring
-		if err := c.generateMimeTypes(); err != nil {
-			return errors.Wrap(err, "failed to generate the rgw mime.types config")
+		if err := c.startRGWDeployment(rgwConfig, daemonLetterID); err != nil {
+			return err
 		}
 	}
 
@@ -214,6 +171,71 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 	return nil
 }
 
+// startRGWDeployment creates or updates the keyring, the config and the deployment of the rgw pods
+func (c *clusterConfig) startRGWDeployment(rgwConfig *rgwConfig, daemonID string) error {
+	var err error
+
+	// We set the owner reference of the Secret to the Object controller instead of the replicaset
+	// because we watch for that resource and reconcile if anything happens to it
+	_, err = c.generateKeyring(rgwConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create rgw keyring")
+	}
+
+	// Set the rgw config flags
+	// Previously we were checking if the deployment was present, if not we would set the config flags
+	// Which means that we would only set the flag on newly created CephObjectStore CR
+	// Unfortunately, on upgrade we would not set the flags which is not ideal for old clusters where we were no setting those flags
+	// The KV supports setting those flags even if the RGW is running
+	logger.Info("setting rgw config flags")
+	err = c.setDefaultFlagsMonConfigStore(rgwConfig.ResourceName)
+	if err != nil {
+		// Getting EPERM typically happens when the flag may not be modified at runtime
+		// This is fine to ignore
+		code, ok := exec.ExitStatus(err)
+		if ok && code != int(syscall.EPERM) {
+			return errors.Wrap(err, "failed to set default rgw config options")
+		}
+	}
+
+	// Create deployment
+	deployment, err := c.createDeployment(rgwConfig)
+	if err != nil {
+		return nil
+	}
+	logger.Infof("object store %q deployment %q created", c.store.Name, deployment.Name)
+
+	// Set owner ref to cephObjectStore object
+	err = c.ownerInfo.SetControllerReference(deployment)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set owner reference for rgw deployment %q", deployment.Name)
+	}
+
+	// Set the deployment hash as an annotation
+	err = patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set annotation for deployment %q", deployment.Name)
+	}
+
+	_, createErr := c.context.Clientset.AppsV1().Deployments(c.store.Namespace).Create(c.clusterInfo.Context, deployment, metav1.CreateOptions{})
+	if createErr != nil {
+		if !kerrors.IsAlreadyExists(createErr) {
+			return errors.Wrap(createErr, "failed to create rgw deployment")
+		}
+		logger.Infof("object store %q deployment %q already exists. updating if needed", c.store.Name, deployment.Name)
+		if err := updateDeploymentAndWait(c.context, c.clusterInfo, deployment, config.RgwType, daemonID, c.clusterSpec.SkipUpgradeChecks, c.clusterSpec.ContinueUpgradeAfterChecksEvenIfNotHealthy); err != nil {
+			return errors.Wrapf(err, "failed to update object store %q deployment %q", c.store.Name, deployment.Name)
+		}
+	}
+
+	// Generate the mime.types file after the rep. controller as well for the same reason as keyring
+	if err := c.generateMimeTypes(); err != nil {
+		return errors.Wrap(err, "failed to generate the rgw mime.types config")
+	}
+
+	return nil
+}
+
 // Delete the object store.
 // WARNING: This is a very destructive action that deletes all metadata and data pools.
 func (c *clusterConfig) deleteStore() {
@@ -230,6 +252,11 @@ func (c *clusterConfig) deleteStore() {
 				logger.Errorf("failed to delete rgw CephX keys and configuration. Error: %v", err)
 			}
 		}
+		for _, group := range c.store.Spec.Gateway.InstanceGroups {
+			if err := c.deleteRgwCephObjects(instanceGroupResourceName(c.store.Name, group.Name)); err != nil {
+				logger.Errorf("failed to delete rgw CephX keys and configuration of instance group %q. Error: %v", group.Name, err)
+			}
+		}
 
 		// Delete the realm and pools
 		objContext, err := NewMultisiteContext(c.context, c.clusterInfo, c.store)
@@ -239,7 +266,10 @@ func (c *clusterConfig) deleteStore() {
 
 		objContext.Endpoint = c.store.Status.Info["endpoint"]
 
-		go disableRGWDashboard(objContext)
+		// the dashboard rgw user configured by the mgr is removed with the pools of the store
+		if !mgr.DashboardObjectGatewayEnabled(c.clusterSpec, c.clusterInfo) {
+			go disableRGWDashboard(objContext)
+		}
 
 		err = deleteRealmAndPools(objContext, c.store.Spec)
 		if err != nil {
@@ -272,8 +302,9 @@ func instanceName(name string) string {
 	return fmt.Sprintf("%s-%s", AppName, name)
 }
 
+// storeLabelSelector selects the deployments of the gateway pods, not the ones of the instance groups
 func (c *clusterConfig) storeLabelSelector() string {
-	return fmt.Sprintf("rook_object_store=%s", c.store.Name)
+	return fmt.Sprintf("rook_object_store=%s,!%s", c.store.Name, instanceGroupLabel)
 }
 
 // Validate the object store arguments
@@ -67,7 +67,7 @@ func TestStartRGW(t *testing.T) {
 
 	// start a basic cluster
 	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
-	c := &clusterConfig{context, info, store, version, &cephv1.ClusterSpec{}, ownerInfo, data, r.client}
+	c := &clusterConfig{context: context, clusterInfo: info, store: store, rookVersion: version, clusterSpec: &cephv1.ClusterSpec{}, ownerInfo: ownerInfo, DataPathMap: data, client: r.client}
 	err := c.startRGWPods(store.Name, store.Name, store.Name)
 	assert.Nil(t, err)
 
@@ -113,7 +113,7 @@ func TestCreateObjectStore(t *testing.T) {
 	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(object...).Build()
 	r := &ReconcileCephObjectStore{client: cl, scheme: s}
 	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
-	c := &clusterConfig{context, info, store, "1.2.3.4", &cephv1.ClusterSpec{}, ownerInfo, data, r.client}
+	c := &clusterConfig{context: context, clusterInfo: info, store: store, rookVersion: "1.2.3.4", clusterSpec: &cephv1.ClusterSpec{}, ownerInfo: ownerInfo, DataPathMap: data, client: r.client}
 	err := c.createOrUpdateStore(store.Name, store.Name, store.Name)
 	assert.Nil(t, err)
 }
@@ -133,14 +133,14 @@ func TestGenerateSecretName(t *testing.T) {
 	cl := fake.NewClientBuilder().Build()
 
 	// start a basic cluster
-	c := &clusterConfig{&clusterd.Context{},
-		&cephclient.ClusterInfo{},
-		&cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "mycluster"}},
-		"v1.1.0",
-		&cephv1.ClusterSpec{},
-		&k8sutil.OwnerInfo{},
-		&config.DataPathMap{},
-		cl}
+	c := &clusterConfig{context: &clusterd.Context{},
+		clusterInfo: &cephclient.ClusterInfo{},
+		store:       &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "mycluster"}},
+		rookVersion: "v1.1.0",
+		clusterSpec: &cephv1.ClusterSpec{},
+		ownerInfo:   &k8sutil.OwnerInfo{},
+		DataPathMap: &config.DataPathMap{},
+		client:      cl}
 	secret := c.generateSecretName("a")
 	assert.Equal(t, "rook-ceph-rgw-default-a-keyring", secret)
 }
@@ -51,6 +51,27 @@ chmod --verbose 400 $VAULT_TOKEN_NEW_PATH
 
 chown --verbose ceph:ceph $VAULT_TOKEN_NEW_PATH
 `
+	// drainRGWScript waits until the rgw daemon has no active requests left, or until the grace period expires.
+	// The pod is removed from the endpoints of the service when it starts terminating, the rgw daemon is first
+	// given some time before checking its requests so the load balancers stop sending it new connections.
+	drainRGWScript = `
+GRACE_PERIOD=%d
+QUIESCE_PERIOD=%d
+
+sleep $QUIESCE_PERIOD
+for _ in $(seq $((GRACE_PERIOD - QUIESCE_PERIOD))); do
+  ACTIVE=$(ceph --admin-daemon %s perf dump rgw 2>/dev/null | grep -o '"qactive": *[0-9]*' | grep -o '[0-9]*$')
+  if [ -z "$ACTIVE" ] || [ "$ACTIVE" -eq 0 ]; then
+    exit 0
+  fi
+  echo "waiting for $ACTIVE active requests"
+  sleep 1
+done
+`
+	defaultDrainGracePeriodSeconds int32 = 30
+	drainQuiescePeriodSeconds      int32 = 5
+	// the rgw daemon is given the default termination grace period of kubernetes to stop after the drain
+	rgwStopGracePeriodSeconds int64 = 30
 )
 
 func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment, error) {
@@ -67,11 +88,11 @@ func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      rgwConfig.ResourceName,
 			Namespace: c.store.Namespace,
-			Labels:    getLabels(c.store.Name, c.store.Namespace, true),
+			Labels:    c.daemonLabels(true),
 		},
 		Spec: apps.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
-				MatchLabels: getLabels(c.store.Name, c.store.Namespace, false),
+				MatchLabels: c.daemonLabels(false),
 			},
 			Template: pod,
 			Replicas: &replicas,
@@ -106,6 +127,10 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 		HostNetwork:       c.clusterSpec.Network.IsHost(),
 		PriorityClassName: c.store.Spec.Gateway.PriorityClassName,
 	}
+	if gracePeriod := c.drainGracePeriodSeconds(); gracePeriod > 0 {
+		terminationGracePeriod := int64(gracePeriod) + rgwStopGracePeriodSeconds
+		podSpec.TerminationGracePeriodSeconds = &terminationGracePeriod
+	}
 
 	// If the log collector is enabled we add the side-car container
 	if c.clusterSpec.LogCollector.Enabled {
@@ -156,23 +181,36 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 		return v1.PodTemplateSpec{}, err
 	}
 	if kmsEnabled {
-		if c.store.Spec.Security.KeyManagementService.IsTokenAuthEnabled() {
+		if isKMIP(c.store.Spec.Security.KeyManagementService) {
+			podSpec.Volumes = append(podSpec.Volumes,
+				kmipVolume(c.store.Spec.Security.KeyManagementService.TokenSecretName))
+		} else if c.store.Spec.Security.KeyManagementService.IsTokenAuthEnabled() {
 			podSpec.Volumes = append(podSpec.Volumes,
 				kms.VaultTokenFileVolume(c.store.Spec.Security.KeyManagementService.TokenSecretName))
 			podSpec.InitContainers = append(podSpec.InitContainers,
 				c.vaultTokenInitContainer(rgwConfig))
 		}
 	}
+	sseS3Enabled, err := c.CheckRGWSSES3()
+	if err != nil {
+		return v1.PodTemplateSpec{}, err
+	}
+	if sseS3Enabled {
+		podSpec.Volumes = append(podSpec.Volumes,
+			sseS3VaultVolume(c.store.Spec.Security.ServerSideEncryptionS3.TokenSecretName))
+		podSpec.InitContainers = append(podSpec.InitContainers,
+			c.sseS3VaultTokenInitContainer(rgwConfig))
+	}
 	c.store.Spec.Gateway.Placement.ApplyToPodSpec(&podSpec)
 
 	// If host networking is not enabled, preferred pod anti-affinity is added to the rgw daemons
-	labels := getLabels(c.store.Name, c.store.Namespace, false)
+	labels := c.daemonLabels(false)
 	k8sutil.SetNodeAntiAffinityForPod(&podSpec, c.clusterSpec.Network.IsHost(), v1.LabelHostname, labels, nil)
 
 	podTemplateSpec := v1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   rgwConfig.ResourceName,
-			Labels: getLabels(c.store.Name, c.store.Namespace, true),
+			Labels: c.daemonLabels(true),
 		},
 		Spec: podSpec,
 	}
@@ -186,6 +224,8 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 			return podTemplateSpec, err
 		}
 	}
+	cephv1.GetDaemonDNS(c.clusterSpec.DNS, cephv1.KeyRGW).ApplyToPodSpec(&podTemplateSpec.Spec)
+	cephv1.GetDaemonContainerOverrides(c.clusterSpec.ContainerOverrides, cephv1.KeyRGW).ApplyToPodSpec(&podTemplateSpec.Spec)
 
 	return podTemplateSpec, nil
 }
@@ -203,7 +243,7 @@ func (c *clusterConfig) createCaBundleUpdateInitContainer(rgwConfig *rgwConfig)
 		Args: []string{
 			fmt.Sprintf("/usr/bin/update-ca-trust extract; cp -rf %s/* %s", caBundleExtractedDir, updatedCaBundleDir),
 		},
-		Image:           c.clusterSpec.CephVersion.Image,
+		Image:           c.clusterSpec.CephImage(cephv1.HotfixDaemonRgw),
 		VolumeMounts:    volumeMounts,
 		Resources:       c.store.Spec.Gateway.Resources,
 		SecurityContext: controller.PodSecurityContext(),
@@ -219,15 +259,20 @@ func (c *clusterConfig) createCaBundleUpdateInitContainer(rgwConfig *rgwConfig)
 // init container.
 func (c *clusterConfig) vaultTokenInitContainer(rgwConfig *rgwConfig) v1.Container {
 	_, volMount := kms.VaultVolumeAndMount(c.store.Spec.Security.KeyManagementService.ConnectionDetails)
+	return c.tokenFileInitContainer(rgwConfig, "vault-initcontainer-token-file-setup", volMount, kms.VaultFileName)
+}
+
+// tokenFileInitContainer copies the vault token mounted with the volume mount to the given file of the data dir
+func (c *clusterConfig) tokenFileInitContainer(rgwConfig *rgwConfig, name string, volMount v1.VolumeMount, fileName string) v1.Container {
 	return v1.Container{
-		Name: "vault-initcontainer-token-file-setup",
+		Name: name,
 		Command: []string{
 			"/bin/bash",
 			"-c",
 			fmt.Sprintf(setupVaultTokenFile,
-				path.Join(kms.EtcVaultDir, kms.VaultFileName), path.Join(c.DataPathMap.ContainerDataDir, kms.VaultFileName)),
+				path.Join(volMount.MountPath, kms.VaultFileName), path.Join(c.DataPathMap.ContainerDataDir, fileName)),
 		},
-		Image: c.clusterSpec.CephVersion.Image,
+		Image: c.clusterSpec.CephImage(cephv1.HotfixDaemonRgw),
 		VolumeMounts: append(
 			controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName), volMount),
 		Resources:       c.store.Spec.Gateway.Resources,
@@ -238,7 +283,7 @@ func (c *clusterConfig) vaultTokenInitContainer(rgwConfig *rgwConfig) v1.Contain
 func (c *clusterConfig) makeChownInitContainer(rgwConfig *rgwConfig) v1.Container {
 	return controller.ChownCephDataDirsInitContainer(
 		*c.DataPathMap,
-		c.clusterSpec.CephVersion.Image,
+		c.clusterSpec.CephImage(cephv1.HotfixDaemonRgw),
 		controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName),
 		c.store.Spec.Gateway.Resources,
 		controller.PodSecurityContext(),
@@ -249,7 +294,7 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) v1.Container {
 	// start the rgw daemon in the foreground
 	container := v1.Container{
 		Name:  "rgw",
-		Image: c.clusterSpec.CephVersion.Image,
+		Image: c.clusterSpec.CephImage(cephv1.HotfixDaemonRgw),
 		Command: []string{
 			"radosgw",
 		},
@@ -268,9 +313,10 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) v1.Container {
 			controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName),
 			c.mimeTypesVolumeMount(),
 		),
-		Env:             controller.DaemonEnvVars(c.clusterSpec.CephVersion.Image),
+		Env:             controller.DaemonEnvVars(c.clusterSpec.CephImage(cephv1.HotfixDaemonRgw)),
 		Resources:       c.store.Spec.Gateway.Resources,
 		LivenessProbe:   c.generateLiveProbe(),
+		Lifecycle:       c.generateDrainLifecycle(rgwConfig),
 		SecurityContext: controller.PodSecurityContext(),
 		WorkingDir:      cephconfig.VarLogCephDir,
 	}
@@ -294,21 +340,35 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) v1.Container {
 	if kmsEnabled {
 		container.Args = append(container.Args,
 			cephconfig.NewFlag("rgw crypt s3 kms backend",
-				c.store.Spec.Security.KeyManagementService.ConnectionDetails[kms.Provider]),
-			cephconfig.NewFlag("rgw crypt vault addr",
-				c.store.Spec.Security.KeyManagementService.ConnectionDetails[api.EnvVaultAddress]),
-		)
-		if c.store.Spec.Security.KeyManagementService.IsTokenAuthEnabled() {
+				c.store.Spec.Security.KeyManagementService.ConnectionDetails[kms.Provider]))
+		if isKMIP(c.store.Spec.Security.KeyManagementService) {
+			container.Args = append(container.Args, c.kmipFlags()...)
+			container.VolumeMounts = append(container.VolumeMounts, kmipVolumeMount())
+		} else {
 			container.Args = append(container.Args,
-				cephconfig.NewFlag("rgw crypt vault auth", kms.KMSTokenSecretNameKey),
-				cephconfig.NewFlag("rgw crypt vault token file",
-					path.Join(c.DataPathMap.ContainerDataDir, kms.VaultFileName)),
-				cephconfig.NewFlag("rgw crypt vault prefix", c.vaultPrefixRGW()),
-				cephconfig.NewFlag("rgw crypt vault secret engine",
-					c.store.Spec.Security.KeyManagementService.ConnectionDetails[kms.VaultSecretEngineKey]),
+				cephconfig.NewFlag("rgw crypt vault addr",
+					c.store.Spec.Security.KeyManagementService.ConnectionDetails[api.EnvVaultAddress]),
 			)
+			if c.store.Spec.Security.KeyManagementService.IsTokenAuthEnabled() {
+				container.Args = append(container.Args,
+					cephconfig.NewFlag("rgw crypt vault auth", kms.KMSTokenSecretNameKey),
+					cephconfig.NewFlag("rgw crypt vault token file",
+						path.Join(c.DataPathMap.ContainerDataDir, kms.VaultFileName)),
+					cephconfig.NewFlag("rgw crypt vault prefix", c.vaultPrefixRGW()),
+					cephconfig.NewFlag("rgw crypt vault secret engine",
+						c.store.Spec.Security.KeyManagementService.ConnectionDetails[kms.VaultSecretEngineKey]),
+				)
+			}
 		}
 	}
+	sseS3Enabled, err := c.CheckRGWSSES3()
+	if err != nil {
+		logger.Errorf("failed to enable SSE-S3. %v", err)
+		return v1.Container{}
+	}
+	if sseS3Enabled {
+		container.Args = append(container.Args, c.sseS3Flags()...)
+	}
 	return container
 }
 
@@ -341,6 +401,52 @@ func (c *clusterConfig) generateLiveProbe() *v1.Probe {
 	}
 }
 
+// generateDrainLifecycle returns the preStop hook draining the in-flight requests of the rgw daemon before it
+// is stopped, so the clients behind load balancers do not get errors on scale-downs and updates
+func (c *clusterConfig) generateDrainLifecycle(rgwConfig *rgwConfig) *v1.Lifecycle {
+	gracePeriod := c.drainGracePeriodSeconds()
+	if gracePeriod == 0 {
+		return nil
+	}
+	quiescePeriod := drainQuiescePeriodSeconds
+	if quiescePeriod > gracePeriod {
+		quiescePeriod = gracePeriod
+	}
+	socketPath := controller.DaemonSocketPath("client", strings.TrimPrefix(generateCephXUser(rgwConfig.ResourceName), "client."))
+
+	return &v1.Lifecycle{
+		PreStop: &v1.Handler{
+			Exec: &v1.ExecAction{
+				// Run with env -i to clean env variables in the exec context
+				// This avoids conflict with the CEPH_ARGS env
+				Command: []string{
+					"env",
+					"-i",
+					"sh",
+					"-c",
+					fmt.Sprintf(drainRGWScript, gracePeriod, quiescePeriod, socketPath),
+				},
+			},
+		},
+	}
+}
+
+// drainGracePeriodSeconds returns the time given to the rgw daemon to complete its requests, or 0 if the
+// requests are not drained
+func (c *clusterConfig) drainGracePeriodSeconds() int32 {
+	drain := c.store.Spec.Gateway.Drain
+	if drain == nil {
+		return defaultDrainGracePeriodSeconds
+	}
+	if drain.Disabled {
+		return 0
+	}
+	if drain.GracePeriodSeconds == 0 {
+		return defaultDrainGracePeriodSeconds
+	}
+	return drain.GracePeriodSeconds
+}
+
 func (c *clusterConfig) generateLiveProbeScheme() v1.URIScheme {
 	// Default to HTTP
 	uriScheme := v1.URISchemeHTTP
@@ -373,15 +479,16 @@ func (c *clusterConfig) generateLiveProbePort() intstr.IntOrString {
 func (c *clusterConfig) generateService(cephObjectStore *cephv1.CephObjectStore) *v1.Service {
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      instanceName(cephObjectStore.Name),
+			Name:      c.serviceName(),
 			Namespace: cephObjectStore.Namespace,
-			Labels:    getLabels(cephObjectStore.Name, cephObjectStore.Namespace, true),
+			Labels:    c.daemonLabels(true),
 		},
 	}
 
 	if c.store.Spec.Gateway.Service != nil {
 		c.store.Spec.Gateway.Service.Annotations.ApplyToObjectMeta(&svc.ObjectMeta)
 	}
+	c.applyTrafficWeight(&svc.ObjectMeta)
 	if c.clusterSpec.Network.IsHost() {
 		svc.Spec.ClusterIP = v1.ClusterIPNone
 	}
@@ -394,7 +501,7 @@ func (c *clusterConfig) generateService(cephObjectStore *cephv1.CephObjectStore)
 	} else {
 		// If the cluster is not external we add the Selector
 		svc.Spec = v1.ServiceSpec{
-			Selector: getLabels(cephObjectStore.Name, cephObjectStore.Namespace, false),
+			Selector: c.daemonLabels(false),
 		}
 	}
 
@@ -483,7 +590,17 @@ func (c *clusterConfig) vaultPrefixRGW() string {
 
 func (c *clusterConfig) CheckRGWKMS() (bool, error) {
 	if c.store.Spec.Security != nil && c.store.Spec.Security.KeyManagementService.IsEnabled() {
-		err := kms.ValidateConnectionDetails(c.context, c.store.Spec.Security, c.store.Namespace)
+		if isKMIP(c.store.Spec.Security.KeyManagementService) {
+			if err := c.validateKMIP(); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+		// rgw reads a static vault token from a file, it cannot log in with its service account token
+		if !c.store.Spec.Security.KeyManagementService.IsTokenAuthEnabled() {
+			return false, errors.New("failed to validate vault auth, rgw only supports the token authentication")
+		}
+		err := kms.ValidateConnectionDetails(c.context, &c.store.Spec.Security.SecuritySpec, c.store.Namespace)
 		if err != nil {
 			return false, err
 		}
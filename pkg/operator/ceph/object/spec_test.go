@@ -328,7 +328,7 @@ func TestCheckRGWKMS(t *testing.T) {
 	// Placeholder
 	context := &clusterd.Context{Clientset: test.New(t, 3)}
 	store := simpleStore()
-	store.Spec.Security = &cephv1.SecuritySpec{KeyManagementService: cephv1.KeyManagementServiceSpec{ConnectionDetails: map[string]string{}}}
+	store.Spec.Security = &cephv1.ObjectStoreSecuritySpec{SecuritySpec: cephv1.SecuritySpec{KeyManagementService: cephv1.KeyManagementServiceSpec{ConnectionDetails: map[string]string{}}}}
 	c := &clusterConfig{
 		context: context,
 		store:   store,
@@ -380,3 +380,45 @@ func TestCheckRGWKMS(t *testing.T) {
 	assert.True(t, b)
 	assert.NoError(t, err)
 }
+
+func TestGenerateDrainLifecycle(t *testing.T) {
+	store := simpleStore()
+	c := &clusterConfig{
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		store:       store,
+		clusterSpec: &cephv1.ClusterSpec{},
+		DataPathMap: cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, "default", "rook-ceph", "/var/lib/rook/"),
+	}
+	rgwConfig := &rgwConfig{ResourceName: fmt.Sprintf("%s-%s-a", AppName, store.Name)}
+
+	t.Run("drained by default", func(t *testing.T) {
+		lifecycle := c.generateDrainLifecycle(rgwConfig)
+		assert.NotNil(t, lifecycle)
+		script := lifecycle.PreStop.Exec.Command[4]
+		assert.Contains(t, script, "GRACE_PERIOD=30\n")
+		assert.Contains(t, script, "QUIESCE_PERIOD=5\n")
+		assert.Contains(t, script, "ceph --admin-daemon /run/ceph/ceph-client.rgw.default.a.asok perf dump rgw")
+
+		s, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(60), *s.Spec.TerminationGracePeriodSeconds)
+		assert.Equal(t, lifecycle, s.Spec.Containers[0].Lifecycle)
+	})
+
+	t.Run("grace period shorter than the quiesce period", func(t *testing.T) {
+		store.Spec.Gateway.Drain = &cephv1.RGWDrainSpec{GracePeriodSeconds: 3}
+		script := c.generateDrainLifecycle(rgwConfig).PreStop.Exec.Command[4]
+		assert.Contains(t, script, "GRACE_PERIOD=3\n")
+		assert.Contains(t, script, "QUIESCE_PERIOD=3\n")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		store.Spec.Gateway.Drain = &cephv1.RGWDrainSpec{Disabled: true}
+		assert.Nil(t, c.generateDrainLifecycle(rgwConfig))
+
+		s, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		assert.Nil(t, s.Spec.TerminationGracePeriodSeconds)
+		assert.Nil(t, s.Spec.Containers[0].Lifecycle)
+	})
+}
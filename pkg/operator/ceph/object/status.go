@@ -107,6 +107,31 @@ func updateStatusBucket(client client.Client, name types.NamespacedName, status
 	logger.Debugf("object store %q status updated to %v", name.String(), status)
 }
 
+// updateStatusHooks updates the status of the post-ready hooks of an object store
+func updateStatusHooks(client client.Client, name types.NamespacedName, hooks []cephv1.HookStatus) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		objectStore := &cephv1.CephObjectStore{}
+		if err := client.Get(context.TODO(), name, objectStore); err != nil {
+			if kerrors.IsNotFound(err) {
+				logger.Debug("CephObjectStore resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve object store %q to update the status of the hooks", name.String())
+		}
+		if objectStore.Status == nil {
+			objectStore.Status = &cephv1.ObjectStoreStatus{}
+		}
+		objectStore.Status.Hooks = hooks
+		if err := reporting.UpdateStatus(client, objectStore); err != nil {
+			return errors.Wrapf(err, "failed to set the status of the hooks of object store %q", name.String())
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
 func buildStatusInfo(cephObjectStore *cephv1.CephObjectStore) map[string]string {
 	m := make(map[string]string)
 
@@ -0,0 +1,278 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topic to manage the topics of the bucket notifications of the object stores
+package topic
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-bucket-topic-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBucketTopicKind = reflect.TypeOf(cephv1.CephBucketTopic{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBucketTopicKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// newMultisiteAdminOpsCtxFunc help us mocking the admin ops API client in unit test
+var newMultisiteAdminOpsCtxFunc = object.NewMultisiteAdminOpsContext
+
+// ReconcileBucketTopic reconciles a CephBucketTopic object
+type ReconcileBucketTopic struct {
+	client           client.Client
+	context          *clusterd.Context
+	opManagerContext context.Context
+}
+
+// Add creates a new CephBucketTopic Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileBucketTopic{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBucketTopic CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketTopic{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephBucketTopic object and makes changes based on the state read
+// and what is in the CephBucketTopic.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileBucketTopic) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileBucketTopic) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBucketTopic instance
+	topic := &cephv1.CephBucketTopic{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, topic)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketTopic resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephBucketTopic")
+	}
+
+	// Set a finalizer so we can delete the topic from the object store
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, topic)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// The CR was just created, initializing status fields
+	if topic.Status == nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionProgressing, "", nil)
+	}
+
+	// an invalid topic is not retried until its spec is updated
+	if err := validateTopic(topic); err != nil {
+		if !topic.GetDeletionTimestamp().IsZero() {
+			return reconcile.Result{}, r.removeFinalizer(topic)
+		}
+		logger.Errorf("invalid ceph bucket topic %q. %v", topic.Name, err)
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, err.Error(), nil)
+		return reconcile.Result{}, nil
+	}
+
+	// Make sure a CephCluster is present in the namespace of the object store otherwise do nothing
+	storeName := types.NamespacedName{Name: topic.Spec.ObjectStoreName, Namespace: topic.Spec.ObjectStoreNamespace}
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, storeName, controllerName)
+	if !isReadyToReconcile {
+		// the topic is gone with the cluster
+		if !topic.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			return reconcile.Result{}, r.removeFinalizer(topic)
+		}
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	clusterInfo, _, _, err := mon.LoadClusterInfo(r.context, r.opManagerContext, storeName.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	store := &cephv1.CephObjectStore{}
+	err = r.client.Get(r.opManagerContext, storeName, store)
+	if err != nil {
+		if kerrors.IsNotFound(err) && !topic.GetDeletionTimestamp().IsZero() {
+			// the topic is gone with the object store
+			return reconcile.Result{}, r.removeFinalizer(topic)
+		}
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, fmt.Sprintf("failed to get object store %q", storeName), nil)
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+	objContext, err := object.NewMultisiteContext(r.context, clusterInfo, store)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get the context of object store %q", storeName)
+	}
+	objContext.CephClusterSpec = cephCluster.Spec
+	opsContext, err := newMultisiteAdminOpsCtxFunc(objContext, &store.Spec)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to initialized rgw admin ops client api")
+	}
+	snsClient, err := createSNSClientFunc(opsContext)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// DELETE: the CR was deleted
+	if !topic.GetDeletionTimestamp().IsZero() {
+		if topic.Status != nil && topic.Status.ARN != nil {
+			logger.Infof("deleting ceph bucket topic %q", topic.Name)
+			if err := deleteTopic(snsClient, *topic.Status.ARN); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, r.removeFinalizer(topic)
+	}
+
+	creds, err := r.endpointCredentials(topic)
+	if err != nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, err.Error(), nil)
+		return reconcile.Result{}, err
+	}
+	attributes, err := topicAttributes(topic, creds, store)
+	if err != nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, err.Error(), nil)
+		return reconcile.Result{}, nil
+	}
+	arn, err := createTopic(snsClient, topic, attributes)
+	if err != nil {
+		r.updateStatus(request.NamespacedName, cephv1.ConditionFailure, err.Error(), nil)
+		return reconcile.Result{}, err
+	}
+	logger.Infof("created ceph bucket topic %q with ARN %q", topic.Name, arn)
+
+	// Success! Let's update the status
+	r.updateStatus(request.NamespacedName, cephv1.ConditionReady, "", &arn)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// endpointCredentials returns the credentials of the endpoint of the topic, nil if the topic has no credentials
+func (r *ReconcileBucketTopic) endpointCredentials(topic *cephv1.CephBucketTopic) (*endpointCredentials, error) {
+	secretName := topic.Spec.Endpoint.CredentialsSecretName
+	if secretName == "" {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.client.Get(r.opManagerContext, types.NamespacedName{Name: secretName, Namespace: topic.Namespace}, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the credentials secret %q of the endpoint", secretName)
+	}
+	username, password := string(secret.Data[credentialsUsernameKey]), string(secret.Data[credentialsPasswordKey])
+	if username == "" || password == "" {
+		return nil, errors.Errorf("missing %q or %q in the credentials secret %q of the endpoint", credentialsUsernameKey, credentialsPasswordKey, secretName)
+	}
+	return &endpointCredentials{username: username, password: password}, nil
+}
+
+func (r *ReconcileBucketTopic) removeFinalizer(topic *cephv1.CephBucketTopic) error {
+	if err := opcontroller.RemoveFinalizer(r.client, topic); err != nil {
+		return errors.Wrap(err, "failed to remove finalizer")
+	}
+	return nil
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileBucketTopic) updateStatus(name types.NamespacedName, phase cephv1.ConditionType, message string, arn *string) {
+	topic := &cephv1.CephBucketTopic{}
+	if err := r.client.Get(r.opManagerContext, name, topic); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketTopic resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph bucket topic %q to update status to %q. %v", name, phase, err)
+		return
+	}
+	if topic.Status == nil {
+		topic.Status = &cephv1.BucketTopicStatus{}
+	}
+
+	topic.Status.Phase = phase
+	topic.Status.Message = message
+	if arn != nil {
+		topic.Status.ARN = arn
+		topic.Status.ObservedGeneration = topic.Generation
+	}
+	if err := reporting.UpdateStatus(r.client, topic); err != nil {
+		logger.Errorf("failed to set ceph bucket topic %q status to %q. %v", name, phase, err)
+		return
+	}
+	logger.Debugf("ceph bucket topic %q status updated to %q", name, phase)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topic
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+)
+
+const (
+	// the keys of the credentials secret of the endpoint
+	credentialsUsernameKey = "username"
+	credentialsPasswordKey = "password"
+)
+
+// createSNSClientFunc creates the client of the topics API of the object store, mocked in unit tests
+var createSNSClientFunc = createSNSClient
+
+// endpointCredentials are the credentials of the endpoint of a topic
+type endpointCredentials struct {
+	username string
+	password string
+}
+
+// createSNSClient creates a client of the topics API of the object store with the keys of the admin ops user
+func createSNSClient(objContext *object.AdminOpsContext) (snsiface.SNSAPI, error) {
+	client := http.Client{
+		Timeout: object.HttpTimeOut,
+	}
+	if len(objContext.TlsCert) > 0 {
+		client.Transport = object.BuildTransportTLS(objContext.TlsCert)
+	}
+	sess, err := session.NewSession(
+		aws.NewConfig().
+			WithRegion("us-east-1").
+			WithCredentials(credentials.NewStaticCredentials(objContext.AdminOpsUserAccessKey, objContext.AdminOpsUserSecretKey, "")).
+			WithEndpoint(objContext.Endpoint).
+			WithMaxRetries(5).
+			WithDisableSSL(len(objContext.TlsCert) == 0).
+			WithHTTPClient(&client),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create session of the topics API")
+	}
+	return sns.New(sess), nil
+}
+
+// validateTopic validates the spec of a topic
+func validateTopic(topic *cephv1.CephBucketTopic) error {
+	if topic.Spec.ObjectStoreName == "" || topic.Spec.ObjectStoreNamespace == "" {
+		return errors.New("missing object store name or namespace")
+	}
+	endpoints := 0
+	for _, set := range []bool{topic.Spec.Endpoint.HTTP != nil, topic.Spec.Endpoint.AMQP != nil, topic.Spec.Endpoint.Kafka != nil} {
+		if set {
+			endpoints++
+		}
+	}
+	if endpoints != 1 {
+		return errors.Errorf("expected exactly one of the http, amqp and kafka endpoints, found %d", endpoints)
+	}
+	if topic.Spec.Endpoint.AMQP != nil && topic.Spec.Endpoint.AMQP.Exchange == "" {
+		return errors.New("missing exchange of the amqp endpoint")
+	}
+	return nil
+}
+
+// topicAttributes returns the attributes of the topic in the object store. The CA bundle of the object store is used
+// to verify the certificate of the amqp and kafka endpoints, the http endpoints are verified with the system CAs of
+// the rgw pods, which include the CA bundle.
+func topicAttributes(topic *cephv1.CephBucketTopic, creds *endpointCredentials, store *cephv1.CephObjectStore) (map[string]*string, error) {
+	attributes := map[string]string{
+		"persistent": strconv.FormatBool(topic.Spec.Persistent),
+	}
+	if topic.Spec.OpaqueData != "" {
+		attributes["OpaqueData"] = topic.Spec.OpaqueData
+	}
+
+	var uri string
+	endpoint := topic.Spec.Endpoint
+	caBundle := store.Spec.Gateway.CaBundleRef != ""
+	switch {
+	case endpoint.HTTP != nil:
+		uri = endpoint.HTTP.URI
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.HTTP.DisableVerifySSL)
+		if endpoint.HTTP.SendCloudEvents {
+			attributes["cloudevents"] = "true"
+		}
+	case endpoint.AMQP != nil:
+		uri = endpoint.AMQP.URI
+		attributes["amqp-exchange"] = endpoint.AMQP.Exchange
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.AMQP.DisableVerifySSL)
+		if endpoint.AMQP.AckLevel != "" {
+			attributes["amqp-ack-level"] = endpoint.AMQP.AckLevel
+		}
+		if caBundle {
+			attributes["ca-location"] = object.CustomCABundlePath
+		}
+	case endpoint.Kafka != nil:
+		uri = endpoint.Kafka.URI
+		attributes["use-ssl"] = strconv.FormatBool(endpoint.Kafka.UseSSL)
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.Kafka.DisableVerifySSL)
+		if endpoint.Kafka.AckLevel != "" {
+			attributes["kafka-ack-level"] = endpoint.Kafka.AckLevel
+		}
+		if caBundle && endpoint.Kafka.UseSSL {
+			attributes["ca-location"] = object.CustomCABundlePath
+		}
+	}
+
+	pushEndpoint, err := url.Parse(uri)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the uri of the endpoint %q", uri)
+	}
+	if creds != nil {
+		pushEndpoint.User = url.UserPassword(creds.username, creds.password)
+	}
+	attributes["push-endpoint"] = pushEndpoint.String()
+
+	return aws.StringMap(attributes), nil
+}
+
+// createTopic creates or updates the topic in the object store and returns its ARN
+func createTopic(snsClient snsiface.SNSAPI, topic *cephv1.CephBucketTopic, attributes map[string]*string) (string, error) {
+	output, err := snsClient.CreateTopic(&sns.CreateTopicInput{
+		Name:       aws.String(topic.Name),
+		Attributes: attributes,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create topic %q", topic.Name)
+	}
+	if output.TopicArn == nil {
+		return "", errors.Errorf("no ARN returned for topic %q", topic.Name)
+	}
+	return *output.TopicArn, nil
+}
+
+// deleteTopic deletes the topic from the object store
+func deleteTopic(snsClient snsiface.SNSAPI, arn string) error {
+	if _, err := snsClient.DeleteTopic(&sns.DeleteTopicInput{TopicArn: aws.String(arn)}); err != nil {
+		return errors.Wrapf(err, "failed to delete topic %q", arn)
+	}
+	return nil
+}
@@ -18,6 +18,8 @@ package object
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"strings"
 	"syscall"
 
@@ -35,6 +37,9 @@ const (
 	ErrorCodeFileExists = 17
 )
 
+// validTenantName matches the names accepted by rgw for the tenants
+var validTenantName = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
 // An ObjectUser defines the details of an object store user.
 type ObjectUser struct {
 	UserID       string              `json:"userId"`
@@ -197,3 +202,36 @@ func DeleteUser(c *Context, id string, opts ...string) (string, error) {
 
 	return result, errors.Wrapf(err, "failed to delete s3 user uid=%q", id)
 }
+
+// ValidateTenantName validates the name of an rgw tenant, which is made of letters, digits and underscores
+func ValidateTenantName(tenant string) error {
+	if !validTenantName.MatchString(tenant) {
+		return errors.Errorf("invalid tenant %q, only letters, digits and underscores are allowed", tenant)
+	}
+	return nil
+}
+
+// TenantUserID returns the rgw id of the user in the tenant, or the user itself without tenant
+func TenantUserID(tenant, user string) string {
+	if tenant == "" {
+		return user
+	}
+	return fmt.Sprintf("%s$%s", tenant, user)
+}
+
+// TenantBucketName returns the name of the bucket of the tenant expected by the admin commands,
+// or the bucket itself without tenant
+func TenantBucketName(tenant, bucket string) string {
+	if tenant == "" {
+		return bucket
+	}
+	return fmt.Sprintf("%s/%s", tenant, bucket)
+}
+
+// UserTenant returns the tenant of an rgw user id, or empty if the user has no tenant
+func UserTenant(userID string) string {
+	if i := strings.Index(userID, "$"); i >= 0 {
+		return userID[:i]
+	}
+	return ""
+}
@@ -21,6 +21,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ceph/go-ceph/rgw/admin"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
@@ -53,6 +56,10 @@ const (
 	controllerName = "ceph-object-store-user-controller"
 )
 
+// keysVerifyInterval is the interval to verify the keys of the user secret against the keys of the user,
+// which may differ after a partial restore
+var keysVerifyInterval = 10 * time.Minute
+
 // newMultisiteAdminOpsCtxFunc help us mocking the admin ops API client in unit test
 var newMultisiteAdminOpsCtxFunc = object.NewMultisiteAdminOpsContext
 
@@ -76,11 +83,15 @@ type ReconcileObjectStoreUser struct {
 	cephClusterSpec  *cephv1.ClusterSpec
 	clusterInfo      *cephclient.ClusterInfo
 	opManagerContext context.Context
+	recorder         *k8sutil.EventReporter
+	// keysRotated is whether the keys were rotated by the current reconcile
+	keysRotated bool
 }
 
 // Add creates a new CephObjectStoreUser Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext))
 }
 
@@ -91,6 +102,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 		scheme:           mgr.GetScheme(),
 		context:          context,
 		opManagerContext: opManagerContext,
+		recorder:         k8sutil.NewEventReporter(mgr.GetEventRecorderFor("rook-" + controllerName)),
 	}
 }
 
@@ -252,9 +264,9 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 	// Set Ready status, we are done reconciling
 	r.updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
-	// Return and do not requeue
+	// Requeue to verify the keys of the secret again
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: keysVerifyInterval}, nil
 }
 
 func (r *ReconcileObjectStoreUser) reconcileCephUser(cephObjectStoreUser *cephv1.CephObjectStoreUser) (reconcile.Result, error) {
@@ -283,13 +295,19 @@ func (r *ReconcileObjectStoreUser) createorUpdateCephUser(u *cephv1.CephObjectSt
 		} else {
 			return errors.Wrapf(err, "failed to get details from ceph object user %q", u.Name)
 		}
-	} else if *user.MaxBuckets != *r.userConfig.MaxBuckets {
-		// TODO: handle update for user capabilities, depends on https://github.com/ceph/go-ceph/pull/571
-		user, err = r.objContext.AdminOpsClient.ModifyUser(r.opManagerContext, *r.userConfig)
+	} else {
+		if *user.MaxBuckets != *r.userConfig.MaxBuckets {
+			user, err = r.objContext.AdminOpsClient.ModifyUser(r.opManagerContext, *r.userConfig)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create ceph object user %v", &r.userConfig.ID)
+			}
+			logCreateOrUpdate = fmt.Sprintf("updated ceph object user %q", u.Name)
+		}
+		// the capabilities are only set by the admin ops API when the user is created
+		err = r.reconcileCaps(user)
 		if err != nil {
-			return errors.Wrapf(err, "failed to create ceph object user %v", &r.userConfig.ID)
+			return errors.Wrapf(err, "failed to update capabilities of user %q", u.Name)
 		}
-		logCreateOrUpdate = fmt.Sprintf("updated ceph object user %q", u.Name)
 	}
 
 	var quotaEnabled = false
@@ -306,7 +324,7 @@ func (r *ReconcileObjectStoreUser) createorUpdateCephUser(u *cephv1.CephObjectSt
 		}
 	}
 	userQuota := admin.QuotaSpec{
-		UID:        u.Name,
+		UID:        r.userConfig.ID,
 		Enabled:    &quotaEnabled,
 		MaxSize:    &maxSize,
 		MaxObjects: &maxObjects,
@@ -316,14 +334,114 @@ func (r *ReconcileObjectStoreUser) createorUpdateCephUser(u *cephv1.CephObjectSt
 		return errors.Wrapf(err, "failed to set quotas for user %q", u.Name)
 	}
 
-	// Set access and secret key
-	r.userConfig.Keys[0].AccessKey = user.Keys[0].AccessKey
-	r.userConfig.Keys[0].SecretKey = user.Keys[0].SecretKey
+	err = r.reconcileBucketQuota(u, user)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set bucket quotas for user %q", u.Name)
+	}
+
+	// Set access and secret key, rotated if needed
+	key, err := r.reconcileKeys(u, user)
+	if err != nil {
+		return errors.Wrapf(err, "failed to rotate keys of user %q", u.Name)
+	}
+	r.userConfig.Keys[0].AccessKey = key.AccessKey
+	r.userConfig.Keys[0].SecretKey = key.SecretKey
 	logger.Info(logCreateOrUpdate)
 
 	return nil
 }
 
+// reconcileCaps adds and removes the admin capabilities of the user to match the capabilities of the spec
+func (r *ReconcileObjectStoreUser) reconcileCaps(user admin.User) error {
+	desired := parseUserCaps(r.userConfig.UserCaps)
+	current := map[string]string{}
+	for _, c := range user.Caps {
+		current[c.Type] = normalizeCapPerm(c.Perm)
+	}
+
+	var remove, add []string
+	for capType, perm := range current {
+		if desired[capType] != perm {
+			remove = append(remove, fmt.Sprintf("%s=%s", capType, perm))
+		}
+	}
+	for capType, perm := range desired {
+		if current[capType] != perm {
+			add = append(add, fmt.Sprintf("%s=%s", capType, perm))
+		}
+	}
+	sort.Strings(remove)
+	sort.Strings(add)
+
+	if len(remove) > 0 {
+		err := object.RemoveUserCaps(r.opManagerContext, r.objContext.AdminOpsClient, r.userConfig.ID, strings.Join(remove, ";"))
+		if err != nil {
+			return err
+		}
+		logger.Infof("removed capabilities %q of ceph object user %q", remove, r.userConfig.ID)
+	}
+	if len(add) > 0 {
+		err := object.AddUserCaps(r.opManagerContext, r.objContext.AdminOpsClient, r.userConfig.ID, strings.Join(add, ";"))
+		if err != nil {
+			return err
+		}
+		logger.Infof("added capabilities %q to ceph object user %q", add, r.userConfig.ID)
+	}
+	return nil
+}
+
+// parseUserCaps returns the permission of each type of capability of caps such as "users=read;buckets=*;"
+func parseUserCaps(caps string) map[string]string {
+	parsed := map[string]string{}
+	for _, c := range strings.Split(caps, ";") {
+		parts := strings.SplitN(c, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		parsed[strings.TrimSpace(parts[0])] = normalizeCapPerm(parts[1])
+	}
+	return parsed
+}
+
+// normalizeCapPerm returns the permission as reported by rgw, which reports "read, write" as "*"
+func normalizeCapPerm(perm string) string {
+	perm = strings.ReplaceAll(strings.TrimSpace(perm), " ", "")
+	if perm == "read,write" || perm == "write,read" {
+		return "*"
+	}
+	return perm
+}
+
+// reconcileBucketQuota sets the quota of each bucket of the user when it differs from the spec
+func (r *ReconcileObjectStoreUser) reconcileBucketQuota(u *cephv1.CephObjectStoreUser, user admin.User) error {
+	var enabled = false
+	var maxSize int64 = -1
+	var maxObjects int64 = -1
+	if u.Spec.Quotas != nil && u.Spec.Quotas.Bucket != nil {
+		if u.Spec.Quotas.Bucket.MaxObjects != nil {
+			maxObjects = *u.Spec.Quotas.Bucket.MaxObjects
+			enabled = true
+		}
+		if u.Spec.Quotas.Bucket.MaxSize != nil {
+			maxSize = u.Spec.Quotas.Bucket.MaxSize.Value()
+			enabled = true
+		}
+	}
+
+	current := user.BucketQuota
+	if current.Enabled != nil && *current.Enabled == enabled &&
+		current.MaxSize != nil && *current.MaxSize == maxSize &&
+		current.MaxObjects != nil && *current.MaxObjects == maxObjects {
+		return nil
+	}
+	err := object.SetBucketQuota(r.opManagerContext, r.objContext.AdminOpsClient, r.userConfig.ID, enabled, maxSize, maxObjects)
+	if err != nil {
+		return err
+	}
+	logger.Infof("set bucket quota of ceph object user %q, enabled %t, max size %d, max objects %d", r.userConfig.ID, enabled, maxSize, maxObjects)
+	return nil
+}
+
 func (r *ReconcileObjectStoreUser) initializeObjectStoreContext(u *cephv1.CephObjectStoreUser) error {
 	err := r.objectStoreInitialized(u)
 	if err != nil {
@@ -360,9 +478,9 @@ func generateUserConfig(user *cephv1.CephObjectStoreUser) admin.User {
 		displayName = user.Name
 	}
 
-	// create the user
+	// create the user, in its tenant if any
 	userConfig := admin.User{
-		ID:          user.Name,
+		ID:          object.TenantUserID(user.Spec.Tenant, user.Name),
 		DisplayName: displayName,
 		Keys:        make([]admin.UserKeySpec, 1),
 	}
@@ -438,15 +556,44 @@ func (r *ReconcileObjectStoreUser) reconcileCephUserSecret(cephObjectStoreUser *
 		return reconcile.Result{}, errors.Wrapf(err, "failed to set owner reference of ceph object user secret %q", secret.Name)
 	}
 
+	keysDiffer, err := r.secretKeysDiffer(secret)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Create Kubernetes Secret
 	err = opcontroller.CreateOrUpdateObject(r.client, secret)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create or update ceph object user %q secret", secret.Name)
 	}
 
+	if keysDiffer && r.keysRotated {
+		logger.Infof("updated the keys in secret %q after the rotation of the keys of object store user %q", secret.Name, cephObjectStoreUser.Name)
+	} else if keysDiffer {
+		message := fmt.Sprintf("replaced the keys in secret %q, they differed from the keys of the object store user", secret.Name)
+		logger.Warningf("%s %q", message, cephObjectStoreUser.Name)
+		if r.recorder != nil {
+			r.recorder.ReportIfNotPresent(cephObjectStoreUser, corev1.EventTypeWarning, string(cephv1.KeyringRepairedReason), message)
+		}
+	}
+
 	return reconcile.Result{}, nil
 }
 
+// secretKeysDiffer returns whether the existing secret of the user holds other keys than the keys of the user
+func (r *ReconcileObjectStoreUser) secretKeysDiffer(secret *corev1.Secret) (bool, error) {
+	existing := &corev1.Secret{}
+	err := r.client.Get(r.opManagerContext, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, existing)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get ceph object user secret %q", secret.Name)
+	}
+	return string(existing.Data["AccessKey"]) != secret.StringData["AccessKey"] ||
+		string(existing.Data["SecretKey"]) != secret.StringData["SecretKey"], nil
+}
+
 func (r *ReconcileObjectStoreUser) objectStoreInitialized(cephObjectStoreUser *cephv1.CephObjectStoreUser) error {
 	_, err := r.getObjectStore(cephObjectStoreUser.Spec.Store)
 	if err != nil {
@@ -520,7 +667,7 @@ func (r *ReconcileObjectStoreUser) getRgwPodList(cephObjectStoreUser *cephv1.Cep
 
 // Delete the user
 func (r *ReconcileObjectStoreUser) deleteUser(u *cephv1.CephObjectStoreUser) error {
-	err := r.objContext.AdminOpsClient.RemoveUser(r.opManagerContext, admin.User{ID: u.Name})
+	err := r.objContext.AdminOpsClient.RemoveUser(r.opManagerContext, admin.User{ID: object.TenantUserID(u.Spec.Tenant, u.Name)})
 	if err != nil {
 		if errors.Is(err, admin.ErrNoSuchUser) {
 			logger.Warningf("user %q does not exist, nothing to remove", u.Name)
@@ -546,6 +693,11 @@ func (r *ReconcileObjectStoreUser) validateUser(u *cephv1.CephObjectStoreUser) e
 			return errors.New("missing store")
 		}
 	}
+	if u.Spec.Tenant != "" {
+		if err := object.ValidateTenantName(u.Spec.Tenant); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
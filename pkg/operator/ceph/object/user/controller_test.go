@@ -358,7 +358,8 @@ func TestCreateorUpdateCephUser(t *testing.T) {
 				if req.URL.RawQuery == "display-name=my-user&format=json&max-buckets=1000&uid=my-user" ||
 					req.URL.RawQuery == "display-name=my-user&format=json&max-buckets=200&uid=my-user" ||
 					req.URL.RawQuery == "display-name=my-user&format=json&max-buckets=1000&uid=my-user&user-caps=users%3Dread%3Bbuckets%3Dread%3B" ||
-					req.URL.RawQuery == "display-name=my-user&format=json&max-buckets=200&uid=my-user&user-caps=users%3Dread%3Bbuckets%3Dread%3B" {
+					req.URL.RawQuery == "display-name=my-user&format=json&max-buckets=200&uid=my-user&user-caps=users%3Dread%3Bbuckets%3Dread%3B" ||
+					req.URL.RawQuery == "display-name=my-user&format=json&max-buckets=1000&uid=my_tenant%24my-user" {
 					return &http.Response{
 						StatusCode: 200,
 						Body:       ioutil.NopCloser(bytes.NewReader([]byte(userCreateJSON))),
@@ -370,7 +371,9 @@ func TestCreateorUpdateCephUser(t *testing.T) {
 				if req.URL.RawQuery == "enabled=false&format=json&max-objects=-1&max-size=-1&quota=&quota-type=user&uid=my-user" ||
 					req.URL.RawQuery == "enabled=true&format=json&max-objects=10000&max-size=-1&quota=&quota-type=user&uid=my-user" ||
 					req.URL.RawQuery == "enabled=true&format=json&max-objects=-1&max-size=10000000000&quota=&quota-type=user&uid=my-user" ||
-					req.URL.RawQuery == "enabled=true&format=json&max-objects=10000&max-size=10000000000&quota=&quota-type=user&uid=my-user" {
+					req.URL.RawQuery == "enabled=true&format=json&max-objects=10000&max-size=10000000000&quota=&quota-type=user&uid=my-user" ||
+					req.URL.RawQuery == "enabled=false&format=json&max-objects=-1&max-size=-1&quota=&quota-type=user&uid=my_tenant%24my-user" ||
+					req.URL.RawQuery == "caps=&format=json&uid=my-user&user-caps=buckets%3Dread%3Busers%3Dread" {
 					return &http.Response{
 						StatusCode: 200,
 						Body:       ioutil.NopCloser(bytes.NewReader([]byte(userCreateJSON))),
@@ -476,4 +479,133 @@ func TestCreateorUpdateCephUser(t *testing.T) {
 		err = r.createorUpdateCephUser(objectUser)
 		assert.NoError(t, err)
 	})
+
+	t.Run("user in a tenant", func(t *testing.T) {
+		objectUser.Spec.Capabilities = nil
+		objectUser.Spec.Quotas = nil
+		objectUser.Spec.Tenant = "my_tenant"
+		userConfig = generateUserConfig(objectUser)
+		assert.Equal(t, "my_tenant$my-user", userConfig.ID)
+		r.userConfig = &userConfig
+		err = r.createorUpdateCephUser(objectUser)
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidateUserTenant(t *testing.T) {
+	r := &ReconcileObjectStoreUser{cephClusterSpec: &cephv1.ClusterSpec{}}
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store},
+	}
+	assert.NoError(t, r.validateUser(u))
+
+	u.Spec.Tenant = "team_a"
+	assert.NoError(t, r.validateUser(u))
+
+	u.Spec.Tenant = "team-a"
+	assert.Error(t, r.validateUser(u))
+}
+
+func TestSecretKeysDiffer(t *testing.T) {
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-object-user-my-store-my-user", Namespace: namespace},
+		Data:       map[string][]byte{"AccessKey": []byte("access"), "SecretKey": []byte("secret")},
+	}
+	cl := fake.NewClientBuilder().WithRuntimeObjects(existing).Build()
+	r := &ReconcileObjectStoreUser{client: cl, opManagerContext: context.TODO()}
+	secret := func(accessKey, secretKey string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: existing.Name, Namespace: namespace},
+			StringData: map[string]string{"AccessKey": accessKey, "SecretKey": secretKey},
+		}
+	}
+
+	differ, err := r.secretKeysDiffer(secret("access", "secret"))
+	assert.NoError(t, err)
+	assert.False(t, differ)
+	differ, err = r.secretKeysDiffer(secret("access", "restored"))
+	assert.NoError(t, err)
+	assert.True(t, differ)
+
+	// a new secret is not a repair
+	newSecret := secret("access", "secret")
+	newSecret.Name = "rook-ceph-object-user-my-store-other"
+	differ, err = r.secretKeysDiffer(newSecret)
+	assert.NoError(t, err)
+	assert.False(t, differ)
+}
+
+// newRecordingAdminOpsContext returns an admin ops context recording each request as "<method> <query>", which
+// responds with the body returned for the request
+func newRecordingAdminOpsContext(t *testing.T, requests *[]string, body func(req *http.Request) string) *cephobject.AdminOpsContext {
+	mockClient := &cephobject.MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			*requests = append(*requests, fmt.Sprintf("%s %s", req.Method, req.URL.RawQuery))
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(body(req)))),
+			}, nil
+		},
+	}
+	adminClient, err := admin.New("rook-ceph-rgw-my-store.mycluster.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", mockClient)
+	assert.NoError(t, err)
+	return &cephobject.AdminOpsContext{AdminOpsClient: adminClient}
+}
+
+func TestReconcileCaps(t *testing.T) {
+	var requests []string
+	r := &ReconcileObjectStoreUser{
+		objContext:       newRecordingAdminOpsContext(t, &requests, func(req *http.Request) string { return "[]" }),
+		opManagerContext: context.TODO(),
+	}
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: cephv1.ObjectStoreUserSpec{
+			Store:        store,
+			Capabilities: &cephv1.ObjectUserCapSpec{User: "read", Bucket: "read, write"},
+		},
+	}
+	userConfig := generateUserConfig(u)
+	r.userConfig = &userConfig
+
+	// the caps of the spec are already set
+	user := admin.User{Caps: []admin.UserCapSpec{{Type: "users", Perm: "read"}, {Type: "buckets", Perm: "*"}}}
+	assert.NoError(t, r.reconcileCaps(user))
+	assert.Empty(t, requests)
+
+	// the changed and removed caps are removed before the caps of the spec are added
+	user = admin.User{Caps: []admin.UserCapSpec{{Type: "users", Perm: "*"}, {Type: "usage", Perm: "read"}}}
+	assert.NoError(t, r.reconcileCaps(user))
+	assert.Equal(t, []string{
+		"DELETE caps=&format=json&uid=my-user&user-caps=usage%3Dread%3Busers%3D%2A",
+		"PUT caps=&format=json&uid=my-user&user-caps=buckets%3D%2A%3Busers%3Dread",
+	}, requests)
+}
+
+func TestReconcileBucketQuota(t *testing.T) {
+	var requests []string
+	r := &ReconcileObjectStoreUser{
+		objContext:       newRecordingAdminOpsContext(t, &requests, func(req *http.Request) string { return "" }),
+		opManagerContext: context.TODO(),
+	}
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store},
+	}
+	userConfig := generateUserConfig(u)
+	r.userConfig = &userConfig
+	enabled := false
+	var unlimited int64 = -1
+	user := admin.User{BucketQuota: admin.QuotaSpec{Enabled: &enabled, MaxSize: &unlimited, MaxObjects: &unlimited}}
+
+	// the quota is disabled already
+	assert.NoError(t, r.reconcileBucketQuota(u, user))
+	assert.Empty(t, requests)
+
+	maxsize, err := resource.ParseQuantity(maxsizestr)
+	assert.NoError(t, err)
+	u.Spec.Quotas = &cephv1.ObjectUserQuotaSpec{Bucket: &cephv1.ObjectBucketQuotaSpec{MaxSize: &maxsize, MaxObjects: &maxobject}}
+	assert.NoError(t, r.reconcileBucketQuota(u, user))
+	assert.Equal(t, []string{"PUT enabled=true&format=json&max-objects=10000&max-size=10000000000&quota=&quota-type=bucket&uid=my-user"}, requests)
 }
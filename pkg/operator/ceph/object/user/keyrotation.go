@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"time"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// RotateKeysAnnotation is the annotation of the CephObjectStoreUser requesting the rotation of its keys when the
+	// key rotation is enabled. The keys are rotated each time the value of the annotation changes.
+	RotateKeysAnnotation = "ceph.rook.io/rotate-keys"
+
+	defaultKeyRotationInterval    = 720 * time.Hour
+	defaultKeyRotationGracePeriod = time.Hour
+)
+
+// reconcileKeys returns the key of the user to store in the secret. When the key rotation is enabled, a new key is
+// created when the rotation is due or requested, and the previous keys are removed after the grace period, so the
+// clients always find a valid key in the secret.
+func (r *ReconcileObjectStoreUser) reconcileKeys(u *cephv1.CephObjectStoreUser, user admin.User) (admin.UserKeySpec, error) {
+	r.keysRotated = false
+	if len(user.Keys) == 0 {
+		return admin.UserKeySpec{}, errors.Errorf("ceph object user %q has no keys", u.Name)
+	}
+	rotation := u.Spec.KeyRotation
+	status := &cephv1.ObjectUserKeyRotationStatus{}
+	if u.Status != nil && u.Status.KeyRotation != nil {
+		status = u.Status.KeyRotation.DeepCopy()
+	}
+	key := currentKey(user.Keys, status.PreviousAccessKey)
+	now := time.Now()
+
+	if status.PreviousAccessKey != "" {
+		gracePeriod := defaultKeyRotationGracePeriod
+		if rotation != nil && rotation.GracePeriod != nil {
+			gracePeriod = rotation.GracePeriod.Duration
+		}
+		if status.LastRotationTime != nil && now.Before(status.LastRotationTime.Add(gracePeriod)) {
+			return key, nil
+		}
+		for _, k := range user.Keys {
+			if k.AccessKey == key.AccessKey {
+				continue
+			}
+			err := object.RemoveUserKey(r.opManagerContext, r.objContext.AdminOpsClient, r.userConfig.ID, k.AccessKey)
+			if err != nil {
+				return admin.UserKeySpec{}, err
+			}
+			logger.Infof("removed key %q of ceph object user %q after the rotation grace period", k.AccessKey, u.Name)
+		}
+		status.PreviousAccessKey = ""
+		return key, r.updateKeyRotationStatus(u, status)
+	}
+
+	if rotation == nil || !rotation.Enabled {
+		return key, nil
+	}
+	interval := defaultKeyRotationInterval
+	if rotation.Interval != nil {
+		interval = rotation.Interval.Duration
+	}
+	lastRotation := u.CreationTimestamp.Time
+	if status.LastRotationTime != nil {
+		lastRotation = status.LastRotationTime.Time
+	}
+	request := u.Annotations[RotateKeysAnnotation]
+	requested := request != "" && request != status.LastRequest
+	if !requested && now.Before(lastRotation.Add(interval)) {
+		return key, nil
+	}
+
+	keys, err := object.CreateUserKey(r.opManagerContext, r.objContext.AdminOpsClient, r.userConfig.ID)
+	if err != nil {
+		return admin.UserKeySpec{}, err
+	}
+	newKey, ok := addedKey(user.Keys, keys)
+	if !ok {
+		return admin.UserKeySpec{}, errors.Errorf("failed to find the new key of ceph object user %q", u.Name)
+	}
+
+	// the previous key is recorded before the secret is updated so it is removed even if the secret update fails
+	status.PreviousAccessKey = key.AccessKey
+	status.LastRotationTime = &metav1.Time{Time: now}
+	status.LastRequest = request
+	if err := r.updateKeyRotationStatus(u, status); err != nil {
+		return admin.UserKeySpec{}, err
+	}
+	logger.Infof("rotated the keys of ceph object user %q, key %q is removed after the grace period", u.Name, key.AccessKey)
+	r.keysRotated = true
+	return newKey, nil
+}
+
+// currentKey returns the newest key of the user, which is the first key that was not replaced by the last rotation
+func currentKey(keys []admin.UserKeySpec, previousAccessKey string) admin.UserKeySpec {
+	for _, k := range keys {
+		if k.AccessKey != previousAccessKey {
+			return k
+		}
+	}
+	return keys[0]
+}
+
+// addedKey returns the key of keys that is not in the previous keys
+func addedKey(previous, keys []admin.UserKeySpec) (admin.UserKeySpec, bool) {
+	existing := map[string]bool{}
+	for _, k := range previous {
+		existing[k.AccessKey] = true
+	}
+	for _, k := range keys {
+		if !existing[k.AccessKey] {
+			return k, true
+		}
+	}
+	return admin.UserKeySpec{}, false
+}
+
+// updateKeyRotationStatus updates the status of the rotation of the keys of the user
+func (r *ReconcileObjectStoreUser) updateKeyRotationStatus(u *cephv1.CephObjectStoreUser, status *cephv1.ObjectUserKeyRotationStatus) error {
+	user := &cephv1.CephObjectStoreUser{}
+	if err := r.client.Get(r.opManagerContext, types.NamespacedName{Name: u.Name, Namespace: u.Namespace}, user); err != nil {
+		return errors.Wrapf(err, "failed to retrieve object store user %q to update the key rotation status", u.Name)
+	}
+	if user.Status == nil {
+		user.Status = &cephv1.ObjectStoreUserStatus{}
+	}
+	user.Status.KeyRotation = status
+	if err := reporting.UpdateStatus(r.client, user); err != nil {
+		return errors.Wrapf(err, "failed to update the key rotation status of object store user %q", u.Name)
+	}
+	u.Status = user.Status
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileKeys(t *testing.T) {
+	oldKey := admin.UserKeySpec{User: "my-user", AccessKey: "OLDACCESS", SecretKey: "oldsecret"}
+	newKey := admin.UserKeySpec{User: "my-user", AccessKey: "NEWACCESS", SecretKey: "newsecret"}
+	var requests []string
+	objContext := newRecordingAdminOpsContext(t, &requests, func(req *http.Request) string {
+		if req.Method == http.MethodPut {
+			return `[{"user":"my-user","access_key":"OLDACCESS","secret_key":"oldsecret"},{"user":"my-user","access_key":"NEWACCESS","secret_key":"newsecret"}]`
+		}
+		return ""
+	})
+
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.Now()},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectStoreUser{})
+	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(u).Build()
+	require.NoError(t, cl.Get(context.TODO(), namespacedName, u))
+	userConfig := generateUserConfig(u)
+	r := &ReconcileObjectStoreUser{client: cl, objContext: objContext, userConfig: &userConfig, opManagerContext: context.TODO()}
+
+	t.Run("rotation disabled", func(t *testing.T) {
+		key, err := r.reconcileKeys(u, admin.User{Keys: []admin.UserKeySpec{oldKey}})
+		assert.NoError(t, err)
+		assert.Equal(t, oldKey, key)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("rotation not due", func(t *testing.T) {
+		u.Spec.KeyRotation = &cephv1.ObjectUserKeyRotationSpec{Enabled: true}
+		key, err := r.reconcileKeys(u, admin.User{Keys: []admin.UserKeySpec{oldKey}})
+		assert.NoError(t, err)
+		assert.Equal(t, oldKey, key)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("rotation requested", func(t *testing.T) {
+		u.Annotations = map[string]string{RotateKeysAnnotation: "1"}
+		key, err := r.reconcileKeys(u, admin.User{Keys: []admin.UserKeySpec{oldKey}})
+		assert.NoError(t, err)
+		assert.Equal(t, newKey, key)
+		assert.True(t, r.keysRotated)
+		assert.Equal(t, []string{"PUT format=json&generate-key=true&key=&key-type=s3&uid=my-user"}, requests)
+
+		stored := &cephv1.CephObjectStoreUser{}
+		require.NoError(t, cl.Get(context.TODO(), namespacedName, stored))
+		assert.Equal(t, "OLDACCESS", stored.Status.KeyRotation.PreviousAccessKey)
+		assert.Equal(t, "1", stored.Status.KeyRotation.LastRequest)
+		assert.NotNil(t, stored.Status.KeyRotation.LastRotationTime)
+	})
+
+	t.Run("previous key kept during the grace period", func(t *testing.T) {
+		requests = nil
+		key, err := r.reconcileKeys(u, admin.User{Keys: []admin.UserKeySpec{oldKey, newKey}})
+		assert.NoError(t, err)
+		assert.Equal(t, newKey, key)
+		assert.False(t, r.keysRotated)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("previous key removed after the grace period", func(t *testing.T) {
+		u.Spec.KeyRotation.GracePeriod = &metav1.Duration{Duration: time.Minute}
+		u.Status.KeyRotation.LastRotationTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+		key, err := r.reconcileKeys(u, admin.User{Keys: []admin.UserKeySpec{oldKey, newKey}})
+		assert.NoError(t, err)
+		assert.Equal(t, newKey, key)
+		assert.Equal(t, []string{"DELETE access-key=OLDACCESS&format=json&key=&key-type=s3&uid=my-user"}, requests)
+		assert.Empty(t, u.Status.KeyRotation.PreviousAccessKey)
+	})
+
+	t.Run("rotation request handled once", func(t *testing.T) {
+		requests = nil
+		key, err := r.reconcileKeys(u, admin.User{Keys: []admin.UserKeySpec{newKey}})
+		assert.NoError(t, err)
+		assert.Equal(t, newKey, key)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("rotation due after the interval", func(t *testing.T) {
+		u.Spec.KeyRotation.Interval = &metav1.Duration{Duration: time.Hour}
+		u.Status.KeyRotation.LastRotationTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		_, err := r.reconcileKeys(u, admin.User{Keys: []admin.UserKeySpec{newKey}})
+		assert.NoError(t, err)
+		assert.True(t, r.keysRotated)
+		assert.Len(t, requests, 1)
+		assert.Equal(t, "NEWACCESS", u.Status.KeyRotation.PreviousAccessKey)
+	})
+}
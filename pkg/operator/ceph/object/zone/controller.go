@@ -73,21 +73,25 @@ type ReconcileObjectZone struct {
 	clusterInfo      *cephclient.ClusterInfo
 	clusterSpec      *cephv1.ClusterSpec
 	opManagerContext context.Context
+	// the time since when the master zone of each zone is unreachable in the automatic failover mode
+	masterUnreachableSince map[string]time.Time
 }
 
 // Add creates a new CephObjectZone Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext))
 }
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
 	return &ReconcileObjectZone{
-		client:           mgr.GetClient(),
-		scheme:           mgr.GetScheme(),
-		context:          context,
-		opManagerContext: opManagerContext,
+		client:                 mgr.GetClient(),
+		scheme:                 mgr.GetScheme(),
+		context:                context,
+		opManagerContext:       opManagerContext,
+		masterUnreachableSince: map[string]time.Time{},
 	}
 }
 
@@ -195,9 +199,27 @@ func (r *ReconcileObjectZone) reconcile(request reconcile.Request) (reconcile.Re
 		return r.setFailedStatus(request.NamespacedName, "failed to create ceph zone", err)
 	}
 
+	// Promote the zone to master if requested or if the master zone is lost
+	checkMasterAfter, err := r.reconcileFailover(cephObjectZone, realmName)
+	if err != nil {
+		return r.setFailedStatus(request.NamespacedName, "failed to reconcile the failover of ceph zone", err)
+	}
+
+	// Commit the changes of the zone not in the period yet
+	period, pendingCommit, err := object.ReconcilePeriod(object.NewContext(r.context, r.clusterInfo, cephObjectZone.Name), realmName, cephObjectZone.Spec.ZoneGroup, cephObjectZone.Name)
+	r.updatePeriodStatus(request.NamespacedName, period.ID, period.Epoch, pendingCommit)
+	if err != nil {
+		return r.setFailedStatus(request.NamespacedName, "failed to commit the period of ceph zone", err)
+	}
+
 	// Set Ready status, we are done reconciling
 	r.updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
+	// Check the master zone again in the automatic failover mode
+	if checkMasterAfter > 0 {
+		return reconcile.Result{Requeue: true, RequeueAfter: checkMasterAfter}, nil
+	}
+
 	// Return and do not requeue
 	logger.Debug("zone done reconciling")
 	return reconcile.Result{}, nil
@@ -336,6 +358,9 @@ func (r *ReconcileObjectZone) validateZoneCR(z *cephv1.CephObjectZone) error {
 	if err := pool.ValidatePoolSpec(r.context, r.clusterInfo, r.clusterSpec, &z.Spec.DataPool); err != nil {
 		return errors.Wrap(err, "invalid data pool spec")
 	}
+	if _, err := masterUnreachableTimeout(z); err != nil {
+		return errors.Wrap(err, "invalid failover spec")
+	}
 	return nil
 }
 
@@ -356,7 +381,7 @@ func (r *ReconcileObjectZone) updateStatus(client client.Client, name types.Name
 		return
 	}
 	if objectZone.Status == nil {
-		objectZone.Status = &cephv1.Status{}
+		objectZone.Status = &cephv1.MultisiteStatus{}
 	}
 
 	objectZone.Status.Phase = status
@@ -366,3 +391,36 @@ func (r *ReconcileObjectZone) updateStatus(client client.Client, name types.Name
 	}
 	logger.Debugf("object zone %q status updated to %q", name, status)
 }
+
+// updatePeriodStatus updates the period of the realm and the changes of a zone not committed yet in its status.
+// The period is kept if it is empty.
+func (r *ReconcileObjectZone) updatePeriodStatus(name types.NamespacedName, period string, epoch int, pendingCommit string) {
+	objectZone := &cephv1.CephObjectZone{}
+	if err := r.client.Get(r.opManagerContext, name, objectZone); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectZone resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object zone %q to update the period status. %v", name, err)
+		return
+	}
+	if objectZone.Status == nil {
+		objectZone.Status = &cephv1.MultisiteStatus{}
+	}
+	status := objectZone.Status.DeepCopy()
+	if period != "" {
+		status.Period = period
+		status.PeriodEpoch = epoch
+	}
+	status.PendingCommit = pendingCommit
+	if *status == *objectZone.Status {
+		return
+	}
+
+	objectZone.Status = status
+	if err := reporting.UpdateStatus(r.client, objectZone); err != nil {
+		logger.Errorf("failed to set object zone %q period status. %v", name, err)
+		return
+	}
+	logger.Debugf("object zone %q is at period %q epoch %d", name, status.Period, status.PeriodEpoch)
+}
@@ -82,6 +82,25 @@ const (
 		"default_placement": "default-placement",
 		"realm_id": "237e6250-5f7d-4b85-9359-8cb2b1848507"
 	}`
+	periodGetJSON = `{
+		"id": "3ee7933c-da65-4255-a5a6-f7348aaf2ece",
+		"epoch": 4,
+		"period_map": {
+			"zonegroups": [
+				{
+					"id": "fd8ff110-d3fd-49b4-b24f-f6cd3dddfedf",
+					"name": "zonegroup-a",
+					"endpoints": [":80"],
+					"master_zone": "6cb39d2c-3005-49da-9be3-c1a92a97d28a",
+					"zones": [
+						{"id": "6cb39d2c-3005-49da-9be3-c1a92a97d28a", "name": "zone-group", "endpoints": [":80"]}
+					]
+				}
+			]
+		},
+		"master_zonegroup": "fd8ff110-d3fd-49b4-b24f-f6cd3dddfedf",
+		"master_zone": "6cb39d2c-3005-49da-9be3-c1a92a97d28a"
+	}`
 	zoneGetOutput  = `{"id": "test-id"}`
 	zoneCreateJSON = `{
     		"id": "b1abbebb-e8ae-4c3b-880e-b009728bad53",
@@ -324,6 +343,12 @@ func TestCephObjectZoneController(t *testing.T) {
 			if args[0] == "zone" && args[1] == "create" {
 				return zoneCreateJSON, nil
 			}
+			if args[0] == "period" && args[1] == "get" {
+				return periodGetJSON, nil
+			}
+			if args[0] == "period" && args[1] == "update" {
+				t.Fatal("the period is up to date and must not be committed")
+			}
 			return "", nil
 		},
 	}
@@ -359,4 +384,7 @@ func TestCephObjectZoneController(t *testing.T) {
 	assert.False(t, res.Requeue)
 	err = r.client.Get(context.TODO(), req.NamespacedName, objectZone)
 	assert.NoError(t, err)
+	assert.Equal(t, "3ee7933c-da65-4255-a5a6-f7348aaf2ece", objectZone.Status.Period)
+	assert.Equal(t, 4, objectZone.Status.PeriodEpoch)
+	assert.Empty(t, objectZone.Status.PendingCommit)
 }
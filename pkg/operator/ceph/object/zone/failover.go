@@ -0,0 +1,196 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the default duration after which an unreachable master zone is considered lost
+	defaultMasterUnreachableTimeout = 10 * time.Minute
+	// the interval between the checks of the master zone in the automatic mode
+	masterCheckInterval = 30 * time.Second
+	masterCheckTimeout  = 5 * time.Second
+)
+
+// checkEndpoint returns an error if the rgw endpoint does not answer. Any HTTP response, even an
+// error status, means that the gateway is reachable.
+var checkEndpoint = func(endpoint string) error {
+	httpClient := &http.Client{Timeout: masterCheckTimeout}
+	resp, err := httpClient.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// masterUnreachableTimeout returns the duration after which an unreachable master zone is promoted
+// in the automatic mode
+func masterUnreachableTimeout(zone *cephv1.CephObjectZone) (time.Duration, error) {
+	if zone.Spec.Failover.MasterUnreachableTimeout == "" {
+		return defaultMasterUnreachableTimeout, nil
+	}
+	timeout, err := time.ParseDuration(zone.Spec.Failover.MasterUnreachableTimeout)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse master unreachable timeout %q", zone.Spec.Failover.MasterUnreachableTimeout)
+	}
+	return timeout, nil
+}
+
+// reconcileFailover promotes the zone to master of its zone group if requested, or in the automatic
+// mode when the master zone has been unreachable for longer than the timeout. Returns the duration
+// after which the master zone must be checked again, or zero if it does not need to be checked.
+func (r *ReconcileObjectZone) reconcileFailover(zone *cephv1.CephObjectZone, realmName string) (time.Duration, error) {
+	if !zone.Spec.Failover.Promote && !zone.Spec.Failover.Automatic {
+		return 0, nil
+	}
+	key := fmt.Sprintf("%s/%s", zone.Namespace, zone.Name)
+
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realmName)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zone.Spec.ZoneGroup)
+	objContext := object.NewContext(r.context, r.clusterInfo, zone.Name)
+	output, err := object.RunAdminCommandNoMultisite(objContext, true, "zonegroup", "get", realmArg, zoneGroupArg)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get ceph zone group %q", zone.Spec.ZoneGroup)
+	}
+	zoneGroupJson, err := object.DecodeZoneGroupConfig(output)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse `radosgw-admin zonegroup get` output")
+	}
+
+	var zoneEndpoints, masterEndpoints []string
+	isMaster := false
+	for _, z := range zoneGroupJson.Zones {
+		if z.Name == zone.Name {
+			zoneEndpoints = z.Endpoints
+			isMaster = z.ID == zoneGroupJson.MasterZoneID
+		}
+		if z.ID == zoneGroupJson.MasterZoneID {
+			masterEndpoints = z.Endpoints
+		}
+	}
+	if isMaster {
+		logger.Debugf("zone %q is the master zone of zone group %q", zone.Name, zone.Spec.ZoneGroup)
+		delete(r.masterUnreachableSince, key)
+		return 0, nil
+	}
+
+	if !zone.Spec.Failover.Promote {
+		if masterZoneReachable(masterEndpoints) {
+			delete(r.masterUnreachableSince, key)
+			return masterCheckInterval, nil
+		}
+		timeout, err := masterUnreachableTimeout(zone)
+		if err != nil {
+			return 0, err
+		}
+		since, ok := r.masterUnreachableSince[key]
+		if !ok {
+			logger.Warningf("master zone of zone group %q is unreachable at %v. zone %q will be promoted if it is still unreachable after %s", zone.Spec.ZoneGroup, masterEndpoints, zone.Name, timeout.String())
+			r.masterUnreachableSince[key] = time.Now()
+			return masterCheckInterval, nil
+		}
+		if time.Since(since) < timeout {
+			return masterCheckInterval, nil
+		}
+		logger.Warningf("master zone of zone group %q is unreachable for more than %s", zone.Spec.ZoneGroup, timeout.String())
+	}
+
+	if err := r.promoteZone(objContext, zone, realmName, zoneEndpoints); err != nil {
+		return 0, err
+	}
+	delete(r.masterUnreachableSince, key)
+	return 0, nil
+}
+
+// masterZoneReachable returns whether any of the endpoints of the master zone answers
+func masterZoneReachable(endpoints []string) bool {
+	for _, endpoint := range endpoints {
+		err := checkEndpoint(endpoint)
+		if err == nil {
+			return true
+		}
+		logger.Debugf("master zone endpoint %q is unreachable. %v", endpoint, err)
+	}
+	return false
+}
+
+// promoteZone makes the zone the master zone of its zone group, points the endpoints of the zone group
+// to the endpoints of the zone, and commits the period. The gateways of the zone are restarted to
+// load the new period.
+func (r *ReconcileObjectZone) promoteZone(objContext *object.Context, zone *cephv1.CephObjectZone, realmName string, zoneEndpoints []string) error {
+	logger.Infof("promoting zone %q to master of zone group %q", zone.Name, zone.Spec.ZoneGroup)
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realmName)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zone.Spec.ZoneGroup)
+	zoneArg := fmt.Sprintf("--rgw-zone=%s", zone.Name)
+
+	output, err := object.RunAdminCommandNoMultisite(objContext, false, "zone", "modify", realmArg, zoneGroupArg, zoneArg, "--master", "--default")
+	if err != nil {
+		return errors.Wrapf(err, "failed to promote zone %q to master for reason %q", zone.Name, output)
+	}
+
+	if len(zoneEndpoints) > 0 {
+		endpointArg := fmt.Sprintf("--endpoints=%s", strings.Join(zoneEndpoints, ","))
+		output, err = object.RunAdminCommandNoMultisite(objContext, false, "zonegroup", "modify", realmArg, zoneGroupArg, endpointArg)
+		if err != nil {
+			return errors.Wrapf(err, "failed to set the endpoints of zone group %q for reason %q", zone.Spec.ZoneGroup, output)
+		}
+	} else {
+		logger.Warningf("zone %q has no endpoints yet, the endpoints of zone group %q are not updated", zone.Name, zone.Spec.ZoneGroup)
+	}
+
+	output, err = object.RunAdminCommandNoMultisite(objContext, false, "period", "update", "--commit", realmArg, zoneGroupArg, zoneArg)
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit the period after promoting zone %q for reason %q", zone.Name, output)
+	}
+
+	if err := r.restartZoneGateways(zone); err != nil {
+		return errors.Wrapf(err, "failed to restart the gateways of zone %q", zone.Name)
+	}
+	logger.Infof("promoted zone %q to master of zone group %q", zone.Name, zone.Spec.ZoneGroup)
+	return nil
+}
+
+// restartZoneGateways deletes the rgw pods of the object stores of the zone
+func (r *ReconcileObjectZone) restartZoneGateways(zone *cephv1.CephObjectZone) error {
+	stores := &cephv1.CephObjectStoreList{}
+	if err := r.client.List(r.opManagerContext, stores, client.InNamespace(zone.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list object stores")
+	}
+	for _, store := range stores.Items {
+		if store.Spec.Zone.Name != zone.Name {
+			continue
+		}
+		selector := fmt.Sprintf("app=%s,rook_object_store=%s", object.AppName, store.Name)
+		err := r.context.Clientset.CoreV1().Pods(zone.Namespace).DeleteCollection(r.opManagerContext, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete the rgw pods of object store %q", store.Name)
+		}
+		logger.Infof("restarted the rgw pods of object store %q", store.Name)
+	}
+	return nil
+}
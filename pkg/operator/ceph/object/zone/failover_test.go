@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const multisiteZoneGroupJSON = `{
+	"name": "zonegroup-a",
+	"master_zone": "id-zone-a",
+	"zones": [
+		{"id": "id-zone-a", "name": "zone-a", "endpoints": ["http://zone-a:80"]},
+		{"id": "id-zone-b", "name": "zone-b", "endpoints": ["http://zone-b:80"]}
+	]
+}`
+
+func TestReconcileFailover(t *testing.T) {
+	var commands []string
+	zoneGroupJSON := multisiteZoneGroupJSON
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "zonegroup" && args[1] == "get" {
+				return zoneGroupJSON, nil
+			}
+			// the arguments of the command without the connection flags
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "--cluster=") {
+					commands = append(commands, strings.Join(args[0:i], " "))
+					break
+				}
+			}
+			return "", nil
+		},
+	}
+	masterReachable := true
+	checkEndpoint = func(endpoint string) error {
+		if masterReachable {
+			return nil
+		}
+		return errors.New("connection refused")
+	}
+
+	store := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store-b", Namespace: "ns"},
+		Spec:       cephv1.ObjectStoreSpec{Zone: cephv1.ZoneSpec{Name: "zone-b"}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(store).Build()
+	r := &ReconcileObjectZone{
+		client:                 cl,
+		context:                &clusterd.Context{Executor: executor, Clientset: test.New(t, 1)},
+		clusterInfo:            cephclient.AdminClusterInfo("ns"),
+		opManagerContext:       context.TODO(),
+		masterUnreachableSince: map[string]time.Time{},
+	}
+	zone := &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "zone-b", Namespace: "ns"},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a"},
+	}
+
+	// nothing to do without failover
+	checkAfter, err := r.reconcileFailover(zone, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), checkAfter)
+	assert.Empty(t, commands)
+
+	// the zone is not promoted while the master zone is reachable
+	zone.Spec.Failover.Automatic = true
+	checkAfter, err = r.reconcileFailover(zone, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, masterCheckInterval, checkAfter)
+	assert.Empty(t, commands)
+
+	// the zone is not promoted before the timeout
+	masterReachable = false
+	checkAfter, err = r.reconcileFailover(zone, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, masterCheckInterval, checkAfter)
+	assert.Empty(t, commands)
+	assert.Contains(t, r.masterUnreachableSince, "ns/zone-b")
+
+	// the zone is promoted after the timeout
+	r.masterUnreachableSince["ns/zone-b"] = time.Now().Add(-defaultMasterUnreachableTimeout)
+	checkAfter, err = r.reconcileFailover(zone, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), checkAfter)
+	assert.Equal(t, []string{
+		"zone modify --rgw-realm=realm-a --rgw-zonegroup=zonegroup-a --rgw-zone=zone-b --master --default",
+		"zonegroup modify --rgw-realm=realm-a --rgw-zonegroup=zonegroup-a --endpoints=http://zone-b:80",
+		"period update --commit --rgw-realm=realm-a --rgw-zonegroup=zonegroup-a --rgw-zone=zone-b",
+	}, commands)
+	assert.NotContains(t, r.masterUnreachableSince, "ns/zone-b")
+
+	// the zone is promoted on request even if the master zone is reachable
+	commands = nil
+	masterReachable = true
+	zone.Spec.Failover = cephv1.ZoneFailoverSpec{Promote: true}
+	_, err = r.reconcileFailover(zone, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(commands))
+
+	// nothing to do once the zone is the master zone
+	commands = nil
+	zoneGroupJSON = strings.Replace(multisiteZoneGroupJSON, `"master_zone": "id-zone-a"`, `"master_zone": "id-zone-b"`, 1)
+	checkAfter, err = r.reconcileFailover(zone, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), checkAfter)
+	assert.Empty(t, commands)
+}
+
+func TestMasterUnreachableTimeout(t *testing.T) {
+	zone := &cephv1.CephObjectZone{}
+	timeout, err := masterUnreachableTimeout(zone)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultMasterUnreachableTimeout, timeout)
+
+	zone.Spec.Failover.MasterUnreachableTimeout = "2m"
+	timeout, err = masterUnreachableTimeout(zone)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, timeout)
+
+	zone.Spec.Failover.MasterUnreachableTimeout = "soon"
+	_, err = masterUnreachableTimeout(zone)
+	assert.Error(t, err)
+}
@@ -76,6 +76,7 @@ type ReconcileObjectZoneGroup struct {
 // Add creates a new CephObjectZoneGroup Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext))
 }
 
@@ -191,6 +192,12 @@ func (r *ReconcileObjectZoneGroup) reconcile(request reconcile.Request) (reconci
 		return r.setFailedStatus(request.NamespacedName, "failed to create ceph zone group", err)
 	}
 
+	// Commit the changes of the zone group not in the period yet
+	err = r.reconcilePeriod(cephObjectZoneGroup)
+	if err != nil {
+		return r.setFailedStatus(request.NamespacedName, "failed to commit the period of ceph zone group", err)
+	}
+
 	// Set Ready status, we are done reconciling
 	r.updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
@@ -258,6 +265,15 @@ func (r *ReconcileObjectZoneGroup) createCephZoneGroup(zoneGroup *cephv1.CephObj
 	return reconcile.Result{}, nil
 }
 
+// reconcilePeriod commits the changes of the zone group not in the current period of the realm
+func (r *ReconcileObjectZoneGroup) reconcilePeriod(zoneGroup *cephv1.CephObjectZoneGroup) error {
+	name := types.NamespacedName{Name: zoneGroup.Name, Namespace: zoneGroup.Namespace}
+	objContext := object.NewContext(r.context, r.clusterInfo, zoneGroup.Name)
+	period, pendingCommit, err := object.ReconcilePeriod(objContext, zoneGroup.Spec.Realm, zoneGroup.Name, "")
+	r.updatePeriodStatus(name, period.ID, period.Epoch, pendingCommit)
+	return err
+}
+
 func (r *ReconcileObjectZoneGroup) reconcileObjectRealm(zoneGroup *cephv1.CephObjectZoneGroup) (reconcile.Result, error) {
 	// Verify the object realm API object actually exists
 	cephObjectRealm := &cephv1.CephObjectRealm{}
@@ -307,7 +323,7 @@ func (r *ReconcileObjectZoneGroup) updateStatus(client client.Client, name types
 		return
 	}
 	if objectZoneGroup.Status == nil {
-		objectZoneGroup.Status = &cephv1.Status{}
+		objectZoneGroup.Status = &cephv1.MultisiteStatus{}
 	}
 
 	objectZoneGroup.Status.Phase = status
@@ -317,3 +333,36 @@ func (r *ReconcileObjectZoneGroup) updateStatus(client client.Client, name types
 	}
 	logger.Debugf("object zone group %q status updated to %q", name, status)
 }
+
+// updatePeriodStatus updates the period of the realm and the changes of a zone group not committed yet in its
+// status. The period is kept if it is empty.
+func (r *ReconcileObjectZoneGroup) updatePeriodStatus(name types.NamespacedName, period string, epoch int, pendingCommit string) {
+	objectZoneGroup := &cephv1.CephObjectZoneGroup{}
+	if err := r.client.Get(r.opManagerContext, name, objectZoneGroup); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectZoneGroup resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object zone group %q to update the period status. %v", name, err)
+		return
+	}
+	if objectZoneGroup.Status == nil {
+		objectZoneGroup.Status = &cephv1.MultisiteStatus{}
+	}
+	status := objectZoneGroup.Status.DeepCopy()
+	if period != "" {
+		status.Period = period
+		status.PeriodEpoch = epoch
+	}
+	status.PendingCommit = pendingCommit
+	if *status == *objectZoneGroup.Status {
+		return
+	}
+
+	objectZoneGroup.Status = status
+	if err := reporting.UpdateStatus(r.client, objectZoneGroup); err != nil {
+		logger.Errorf("failed to set object zone group %q period status. %v", name, err)
+		return
+	}
+	logger.Debugf("object zone group %q is at period %q epoch %d", name, status.Period, status.PeriodEpoch)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	defaultCapacityCheckInterval = 5 * time.Minute
+)
+
+// capacityChecker periodically collects the usage and the compression statistics of a pool
+type capacityChecker struct {
+	context        *clusterd.Context
+	interval       *time.Duration
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	poolName       string
+}
+
+// newCapacityChecker creates a new capacityChecker object
+func newCapacityChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, poolSpec *cephv1.PoolSpec, poolName string) *capacityChecker {
+	c := &capacityChecker{
+		context:        context,
+		interval:       &defaultCapacityCheckInterval,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		client:         client,
+		poolName:       poolName,
+	}
+
+	// allow overriding the check interval
+	checkInterval := poolSpec.StatusCheck.Capacity.Interval
+	if checkInterval != nil {
+		logger.Infof("pool capacity status check interval for block pool %q is %q", namespacedName.Name, checkInterval.Duration.String())
+		c.interval = &checkInterval.Duration
+	}
+
+	return c
+}
+
+// checkCapacity periodically collects the statistics of the pool
+func (c *capacityChecker) checkCapacity(context context.Context) {
+	// collect the statistics immediately before starting the loop
+	c.checkCapacityStatus()
+
+	for {
+		select {
+		case <-context.Done():
+			logger.Infof("stopping monitoring pool capacity status %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(*c.interval):
+			logger.Debugf("checking pool capacity status %q", c.namespacedName.Name)
+			c.checkCapacityStatus()
+		}
+	}
+}
+
+func (c *capacityChecker) checkCapacityStatus() {
+	status, err := c.collectCapacityStatus()
+	if err != nil {
+		logger.Debugf("failed to check pool capacity status for ceph block pool %q. %v", c.namespacedName.Name, err)
+		status = &cephv1.PoolCapacityStatus{Details: err.Error()}
+	}
+	c.updateStatusCapacity(status)
+}
+
+func (c *capacityChecker) collectCapacityStatus() (*cephv1.PoolCapacityStatus, error) {
+	stats, err := cephclient.GetPoolStats(c.context, c.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	for _, pool := range stats.Pools {
+		if pool.Name != c.poolName {
+			continue
+		}
+		status := &cephv1.PoolCapacityStatus{
+			StoredBytes:             uint64(pool.Stats.Stored),
+			UsedBytes:               uint64(pool.Stats.BytesUsed),
+			CompressedBytes:         uint64(pool.Stats.CompressBytesUsed),
+			CompressedOriginalBytes: uint64(pool.Stats.CompressUnderBytes),
+			LastChecked:             time.Now().UTC().Format(time.RFC3339),
+		}
+		if status.CompressedBytes > 0 {
+			status.CompressionRatio = fmt.Sprintf("%.2f", float64(status.CompressedOriginalBytes)/float64(status.CompressedBytes))
+		}
+		if status.CompressedOriginalBytes > status.CompressedBytes {
+			status.CompressionSavedBytes = status.CompressedOriginalBytes - status.CompressedBytes
+		}
+		return status, nil
+	}
+	return nil, errors.Errorf("pool %q not found in the pool stats", c.poolName)
+}
+
+// updateStatusCapacity updates the capacity status of the pool CR
+func (c *capacityChecker) updateStatusCapacity(status *cephv1.PoolCapacityStatus) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update capacity status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	blockPool.Status.CapacityStatus = status
+	if err := reporting.UpdateStatus(c.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q capacity status. %v", c.namespacedName.Name, err)
+		return
+	}
+
+	logger.Debugf("ceph block pool %q capacity status updated", c.namespacedName.Name)
+}
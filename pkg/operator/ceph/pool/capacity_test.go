@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const dfDetail = `{"stats":{"total_bytes":32212254720},"pools":[
+{"name":"device_health_metrics","id":1,"stats":{"stored":0,"objects":0,"bytes_used":0,"max_avail":9949892608}},
+{"name":"replicapool","id":2,"stats":{"stored":3221225472,"objects":800,"bytes_used":6442450944,"max_avail":9949892608,"compress_bytes_used":1073741824,"compress_under_bytes":2684354560}}]}`
+
+func TestCheckCapacityStatus(t *testing.T) {
+	namespacedName := types.NamespacedName{Namespace: "rook-ceph", Name: "replicapool"}
+	blockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(blockPool).Build()
+
+	output := dfDetail
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "df" && args[1] == "detail" {
+				return output, nil
+			}
+			return "", errors.Errorf("unexpected command %q %v", command, args)
+		},
+	}
+	clusterInfo := cephclient.AdminClusterInfo(namespacedName.Namespace)
+	spec := &cephv1.PoolSpec{}
+	checker := newCapacityChecker(&clusterd.Context{Executor: executor}, cl, clusterInfo, namespacedName, spec, namespacedName.Name)
+	assert.Equal(t, 5*time.Minute, *checker.interval)
+
+	checker.checkCapacityStatus()
+	err := cl.Get(context.TODO(), namespacedName, blockPool)
+	assert.NoError(t, err)
+	status := blockPool.Status.CapacityStatus
+	assert.Equal(t, uint64(3221225472), status.StoredBytes)
+	assert.Equal(t, uint64(6442450944), status.UsedBytes)
+	assert.Equal(t, uint64(1073741824), status.CompressedBytes)
+	assert.Equal(t, uint64(2684354560), status.CompressedOriginalBytes)
+	assert.Equal(t, "2.50", status.CompressionRatio)
+	assert.Equal(t, uint64(1610612736), status.CompressionSavedBytes)
+	assert.NotEmpty(t, status.LastChecked)
+	assert.Empty(t, status.Details)
+
+	// no compressed data
+	checker.poolName = "device_health_metrics"
+	status, err = checker.collectCapacityStatus()
+	assert.NoError(t, err)
+	assert.Empty(t, status.CompressionRatio)
+	assert.Zero(t, status.CompressionSavedBytes)
+
+	// the pool does not exist yet
+	checker.poolName = "newpool"
+	_, err = checker.collectCapacityStatus()
+	assert.Error(t, err)
+
+	// the error is reported in the status
+	checker.poolName = namespacedName.Name
+	output = "not json"
+	checker.checkCapacityStatus()
+	blockPool = &cephv1.CephBlockPool{}
+	err = cl.Get(context.TODO(), namespacedName, blockPool)
+	assert.NoError(t, err)
+	assert.Contains(t, blockPool.Status.CapacityStatus.Details, "failed to unmarshal pool stats")
+	assert.Zero(t, blockPool.Status.CapacityStatus.StoredBytes)
+
+	// the interval can be overridden
+	spec.StatusCheck.Capacity.Interval = &metav1.Duration{Duration: time.Minute}
+	checker = newCapacityChecker(&clusterd.Context{Executor: executor}, cl, clusterInfo, namespacedName, spec, namespacedName.Name)
+	assert.Equal(t, time.Minute, *checker.interval)
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/coreos/pkg/capnslog"
@@ -34,11 +35,13 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/csi/peermap"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -71,6 +74,8 @@ type ReconcileCephBlockPool struct {
 	context           *clusterd.Context
 	clusterInfo       *cephclient.ClusterInfo
 	blockPoolContexts map[string]*blockPoolHealth
+	capacityContexts  map[string]*blockPoolHealth
+	imageGCContexts   map[string]*imageGCRoutine
 	opManagerContext  context.Context
 }
 
@@ -80,9 +85,16 @@ type blockPoolHealth struct {
 	started        bool
 }
 
+// imageGCRoutine is the running image gc of a pool, restarted when its spec changes
+type imageGCRoutine struct {
+	internalCancel context.CancelFunc
+	spec           cephv1.ImageGCSpec
+}
+
 // Add creates a new CephBlockPool Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
 	return add(mgr, newReconciler(mgr, context, opManagerContext))
 }
 
@@ -93,6 +105,8 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 		scheme:            mgr.GetScheme(),
 		context:           context,
 		blockPoolContexts: make(map[string]*blockPoolHealth),
+		capacityContexts:  make(map[string]*blockPoolHealth),
+		imageGCContexts:   make(map[string]*imageGCRoutine),
 		opManagerContext:  opManagerContext,
 	}
 }
@@ -214,6 +228,8 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		if blockPoolContextsExists {
 			r.cancelMirrorMonitoring(blockPoolChannelKey)
 		}
+		r.cancelCapacityMonitoring(blockPoolChannelKey)
+		r.cancelImageGC(blockPoolChannelKey)
 
 		logger.Infof("deleting pool %q", cephBlockPool.Name)
 		err := deletePool(r.context, clusterInfo, cephBlockPool)
@@ -226,6 +242,16 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 			logger.Errorf("failed to disable stats collection for pool(s). %v", err)
 		}
 
+		// remove the mclock settings of the pool from the osds of its device class
+		if cephBlockPool.Spec.MClock != nil {
+			cephVersion, err := opcontroller.GetImageVersion(cephCluster)
+			if err != nil {
+				logger.Errorf("failed to fetch ceph version from cephcluster %q. %v", cephCluster.Name, err)
+			} else if err := configureMClock(r.context, clusterInfo, *cephVersion); err != nil {
+				logger.Errorf("failed to remove the mclock settings of pool %q. %v", cephBlockPool.Name, err)
+			}
+		}
+
 		// Remove finalizer
 		err = opcontroller.RemoveFinalizer(r.client, cephBlockPool)
 		if err != nil {
@@ -275,11 +301,29 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		return reconcileResponse, errors.Wrapf(err, "failed to create pool %q.", cephBlockPool.GetName())
 	}
 
+	// migrate the pool to a new crush rule if its device class, failure domain or crush root changed
+	migrationResult, err := r.reconcileCrushRuleMigration(clusterInfo, &cephCluster.Spec, cephBlockPool)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to migrate the crush rule of pool %q", cephBlockPool.GetName())
+	}
+
 	// enable/disable RBD stats collection based on cephBlockPool spec
 	if err := configureRBDStats(r.context, clusterInfo); err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to enable/disable stats collection for pool(s)")
 	}
 
+	// apply the mclock scheduler settings of the pools to the osds of their device class
+	if err := configureMClock(r.context, clusterInfo, *cephVersion); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to configure the mclock scheduler of the pool(s)")
+	}
+
+	// Run the goroutine to update the capacity status
+	r.reconcileCapacityMonitoring(cephBlockPool, request.NamespacedName, blockPoolChannelKey)
+
+	// Run the goroutine to collect the orphaned images
+	r.reconcileImageGC(cephBlockPool, request.NamespacedName, blockPoolChannelKey)
+
 	checker := newMirrorChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, &cephBlockPool.Spec, cephBlockPool.Name)
 	// ADD PEERS
 	logger.Debug("reconciling create rbd mirror peer configuration")
@@ -333,9 +377,9 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		}
 	}
 
-	// Return and do not requeue
+	// Return and only requeue to check the progress of a migration
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
+	return migrationResult, nil
 }
 
 func (r *ReconcileCephBlockPool) reconcileCreatePool(clusterInfo *cephclient.ClusterInfo, cephCluster *cephv1.ClusterSpec, cephBlockPool *cephv1.CephBlockPool) (reconcile.Result, error) {
@@ -408,6 +452,63 @@ func configureRBDStats(clusterContext *clusterd.Context, clusterInfo *cephclient
 	return nil
 }
 
+// configureMClock applies the mclock scheduler settings of the pools to the OSDs of their device
+// class. The settings are removed for the device classes of the pools without mclock settings. If
+// several pools of a device class have mclock settings, the first pool by name is honored.
+func configureMClock(clusterContext *clusterd.Context, clusterInfo *cephclient.ClusterInfo, cephVersion cephver.CephVersion) error {
+	cephBlockPoolList := &cephv1.CephBlockPoolList{}
+	err := clusterContext.Client.List(context.TODO(), cephBlockPoolList, client.InNamespace(clusterInfo.Namespace))
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve list of CephBlockPool")
+	}
+	sort.Slice(cephBlockPoolList.Items, func(i, j int) bool {
+		return cephBlockPoolList.Items[i].Name < cephBlockPoolList.Items[j].Name
+	})
+
+	settings := map[string]*cephv1.MClockSpec{}
+	var deviceClasses []string
+	for _, cephBlockPool := range cephBlockPoolList.Items {
+		deviceClass := cephBlockPool.Spec.DeviceClass
+		if deviceClass == "" {
+			continue
+		}
+		if _, ok := settings[deviceClass]; !ok {
+			deviceClasses = append(deviceClasses, deviceClass)
+			settings[deviceClass] = nil
+		}
+		if cephBlockPool.GetDeletionTimestamp() != nil || cephBlockPool.Spec.MClock == nil {
+			continue
+		}
+		if settings[deviceClass] == nil {
+			settings[deviceClass] = cephBlockPool.Spec.MClock
+		} else if !reflect.DeepEqual(settings[deviceClass], cephBlockPool.Spec.MClock) {
+			logger.Warningf("ignoring the mclock settings of pool %q, other settings are already applied to the osds of device class %q", cephBlockPool.Name, deviceClass)
+		}
+	}
+	if len(deviceClasses) == 0 {
+		return nil
+	}
+
+	if !cephVersion.IsAtLeastQuincy() {
+		for _, deviceClass := range deviceClasses {
+			if settings[deviceClass] != nil {
+				logger.Warningf("mclock scheduler settings require ceph quincy or newer, not applying them on ceph version %q", cephVersion.String())
+				break
+			}
+		}
+		return nil
+	}
+
+	monStore := config.GetMonStore(clusterContext, clusterInfo)
+	for _, deviceClass := range deviceClasses {
+		if err := monStore.ApplyMClock(fmt.Sprintf("osd/class:%s", deviceClass), settings[deviceClass]); err != nil {
+			return errors.Wrapf(err, "failed to configure the mclock scheduler of the osds of device class %q", deviceClass)
+		}
+	}
+	logger.Debugf("configured the mclock scheduler of the osds of device classes %v", deviceClasses)
+	return nil
+}
+
 func (r *ReconcileCephBlockPool) cancelMirrorMonitoring(cephBlockPoolName string) {
 	// Cancel the context to stop the go routine
 	r.blockPoolContexts[cephBlockPoolName].internalCancel()
@@ -415,3 +516,70 @@ func (r *ReconcileCephBlockPool) cancelMirrorMonitoring(cephBlockPoolName string
 	// Remove ceph block pool from the map
 	delete(r.blockPoolContexts, cephBlockPoolName)
 }
+
+// reconcileCapacityMonitoring starts or stops the periodic collection of the capacity status of the pool
+func (r *ReconcileCephBlockPool) reconcileCapacityMonitoring(cephBlockPool *cephv1.CephBlockPool, namespacedName types.NamespacedName, cephBlockPoolName string) {
+	_, started := r.capacityContexts[cephBlockPoolName]
+	if cephBlockPool.Spec.StatusCheck.Capacity.Disabled {
+		if started {
+			r.cancelCapacityMonitoring(cephBlockPoolName)
+			// Reset the capacity status
+			newCapacityChecker(r.context, r.client, r.clusterInfo, namespacedName, &cephBlockPool.Spec, cephBlockPool.Name).updateStatusCapacity(nil)
+		}
+		return
+	}
+	if started {
+		logger.Debug("pool capacity monitoring go routine already running!")
+		return
+	}
+
+	internalCtx, internalCancel := context.WithCancel(r.opManagerContext)
+	r.capacityContexts[cephBlockPoolName] = &blockPoolHealth{
+		internalCtx:    internalCtx,
+		internalCancel: internalCancel,
+		started:        true,
+	}
+	checker := newCapacityChecker(r.context, r.client, r.clusterInfo, namespacedName, &cephBlockPool.Spec, cephBlockPool.Name)
+	go checker.checkCapacity(internalCtx)
+}
+
+func (r *ReconcileCephBlockPool) cancelCapacityMonitoring(cephBlockPoolName string) {
+	if capacityContext, ok := r.capacityContexts[cephBlockPoolName]; ok {
+		// Cancel the context to stop the go routine
+		capacityContext.internalCancel()
+		delete(r.capacityContexts, cephBlockPoolName)
+	}
+}
+
+// reconcileImageGC starts, restarts or stops the periodic collection of the orphaned images of the pool
+func (r *ReconcileCephBlockPool) reconcileImageGC(cephBlockPool *cephv1.CephBlockPool, namespacedName types.NamespacedName, cephBlockPoolName string) {
+	spec := cephBlockPool.Spec.ImageGC
+	routine, started := r.imageGCContexts[cephBlockPoolName]
+	if spec == nil || !spec.Enabled {
+		if started {
+			r.cancelImageGC(cephBlockPoolName)
+			newImageGCChecker(r.context, r.client, r.clusterInfo, namespacedName, cephv1.ImageGCSpec{}, cephBlockPool.Name).updateStatusImageGC(nil)
+		}
+		return
+	}
+	if started {
+		if reflect.DeepEqual(routine.spec, *spec) {
+			logger.Debug("pool image gc go routine already running!")
+			return
+		}
+		logger.Infof("restarting the image gc of block pool %q since its settings changed", cephBlockPool.Name)
+		r.cancelImageGC(cephBlockPoolName)
+	}
+
+	internalCtx, internalCancel := context.WithCancel(r.opManagerContext)
+	r.imageGCContexts[cephBlockPoolName] = &imageGCRoutine{internalCancel: internalCancel, spec: *spec.DeepCopy()}
+	checker := newImageGCChecker(r.context, r.client, r.clusterInfo, namespacedName, *spec.DeepCopy(), cephBlockPool.Name)
+	go checker.collectImages(internalCtx)
+}
+
+func (r *ReconcileCephBlockPool) cancelImageGC(cephBlockPoolName string) {
+	if routine, ok := r.imageGCContexts[cephBlockPoolName]; ok {
+		routine.internalCancel()
+		delete(r.imageGCContexts, cephBlockPoolName)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	testop "github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
@@ -166,6 +167,9 @@ func TestCephBlockPoolController(t *testing.T) {
 				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_ERR"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
 			}
 
+			if output, ok := crushRuleMigrationTestOutput(args); ok {
+				return output, nil
+			}
 			return "", nil
 		},
 	}
@@ -188,6 +192,8 @@ func TestCephBlockPoolController(t *testing.T) {
 		scheme:            s,
 		context:           c,
 		blockPoolContexts: make(map[string]*blockPoolHealth),
+		capacityContexts:  make(map[string]*blockPoolHealth),
+		imageGCContexts:   make(map[string]*imageGCRoutine),
 		opManagerContext:  context.TODO(),
 	}
 
@@ -243,6 +249,8 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			capacityContexts:  make(map[string]*blockPoolHealth),
+			imageGCContexts:   make(map[string]*imageGCRoutine),
 			opManagerContext:  context.TODO(),
 		}
 
@@ -270,6 +278,9 @@ func TestCephBlockPoolController(t *testing.T) {
 				if args[0] == "config" && args[2] == "mgr." && args[3] == "mgr/prometheus/rbd_stats_pools" {
 					return "", nil
 				}
+				if output, ok := crushRuleMigrationTestOutput(args); ok {
+					return output, nil
+				}
 
 				return "", nil
 			},
@@ -300,6 +311,8 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			capacityContexts:  make(map[string]*blockPoolHealth),
+			imageGCContexts:   make(map[string]*imageGCRoutine),
 			opManagerContext:  context.TODO(),
 		}
 		res, err := r.Reconcile(ctx, req)
@@ -362,6 +375,9 @@ func TestCephBlockPoolController(t *testing.T) {
 				if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "bootstrap" && args[4] == "create" {
 					return `eyJmc2lkIjoiYzZiMDg3ZjItNzgyOS00ZGJiLWJjZmMtNTNkYzM0ZTBiMzVkIiwiY2xpZW50X2lkIjoicmJkLW1pcnJvci1wZWVyIiwia2V5IjoiQVFBV1lsWmZVQ1Q2RGhBQVBtVnAwbGtubDA5YVZWS3lyRVV1NEE9PSIsIm1vbl9ob3N0IjoiW3YyOjE5Mi4xNjguMTExLjEwOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTA6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjEyOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTI6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjExOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTE6Njc4OV0ifQ==`, nil
 				}
+				if output, ok := crushRuleMigrationTestOutput(args); ok {
+					return output, nil
+				}
 				return "", nil
 			},
 		}
@@ -371,6 +387,8 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			capacityContexts:  make(map[string]*blockPoolHealth),
+			imageGCContexts:   make(map[string]*imageGCRoutine),
 			opManagerContext:  context.TODO(),
 		}
 
@@ -412,6 +430,8 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			capacityContexts:  make(map[string]*blockPoolHealth),
+			imageGCContexts:   make(map[string]*imageGCRoutine),
 			opManagerContext:  context.TODO(),
 		}
 
@@ -449,6 +469,8 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			capacityContexts:  make(map[string]*blockPoolHealth),
+			imageGCContexts:   make(map[string]*imageGCRoutine),
 			opManagerContext:  context.TODO(),
 		}
 		pool.Spec.Mirroring.Enabled = false
@@ -548,3 +570,63 @@ func TestConfigureRBDStats(t *testing.T) {
 	err = configureRBDStats(context, clusterInfo)
 	assert.NotNil(t, err)
 }
+
+func TestConfigureMClock(t *testing.T) {
+	namespace := "rook-ceph"
+	configCommands := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if args[0] == "config" && args[1] == "set" {
+				configCommands[args[2]+" "+args[3]] = args[4]
+				return "", nil
+			}
+			if args[0] == "config" && args[1] == "rm" {
+				configCommands[args[2]+" "+args[3]] = ""
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected arguments %q", args)
+		},
+	}
+	s := runtime.NewScheme()
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephBlockPool{}, &cephv1.CephBlockPoolList{})
+	newPool := func(name, deviceClass string, mclock *cephv1.MClockSpec) *cephv1.CephBlockPool {
+		return &cephv1.CephBlockPool{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cephv1.PoolSpec{DeviceClass: deviceClass, MClock: mclock},
+		}
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := cephclient.AdminClusterInfo(namespace)
+	quincy := cephver.CephVersion{Major: 17}
+
+	// no device class is managed without pools of a device class
+	context.Client = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(newPool("a", "", nil)).Build()
+	err := configureMClock(context, clusterInfo, quincy)
+	assert.NoError(t, err)
+	assert.Empty(t, configCommands)
+
+	// the settings of the first pool by name are applied to the osds of its device class
+	objects := []runtime.Object{
+		newPool("b", "ssd", &cephv1.MClockSpec{Client: &cephv1.MClockAllocationSpec{Weight: 2, Limit: "0.5"}}),
+		newPool("c", "ssd", &cephv1.MClockSpec{Profile: "high_recovery_ops"}),
+		newPool("d", "hdd", nil),
+	}
+	context.Client = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+	err = configureMClock(context, clusterInfo, quincy)
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", configCommands["osd/class:ssd osd_mclock_profile"])
+	assert.Equal(t, "2", configCommands["osd/class:ssd osd_mclock_scheduler_client_wgt"])
+	assert.Equal(t, "0.5", configCommands["osd/class:ssd osd_mclock_scheduler_client_lim"])
+	assert.Equal(t, "", configCommands["osd/class:ssd osd_mclock_scheduler_client_res"])
+	// the settings are removed for the device classes of pools without settings
+	value, ok := configCommands["osd/class:hdd osd_mclock_profile"]
+	assert.True(t, ok)
+	assert.Equal(t, "", value)
+
+	// nothing is applied before quincy
+	configCommands = map[string]string{}
+	err = configureMClock(context, clusterInfo, cephver.Pacific)
+	assert.NoError(t, err)
+	assert.Empty(t, configCommands)
+}
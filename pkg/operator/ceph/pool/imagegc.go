@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	rbdCSIDriverSuffix = "rbd.csi.ceph.com"
+	// the images of the volumes cloned by the CSI driver first exist with this suffix
+	tempCloneSuffix = "-temp"
+)
+
+var (
+	defaultImageGCInterval = time.Hour
+	defaultImageGCMinAge   = 24 * time.Hour
+	defaultImageGCPrefix   = "csi-vol-"
+)
+
+// imageGCChecker periodically finds the RBD images of a pool that were provisioned by the CSI driver but have no
+// persistent volume, such as when the provisioning was interrupted, and reports them or moves them to the trash
+type imageGCChecker struct {
+	context        *clusterd.Context
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	poolName       string
+	spec           cephv1.ImageGCSpec
+	interval       time.Duration
+	minAge         time.Duration
+	prefix         string
+}
+
+// newImageGCChecker creates a new imageGCChecker object
+func newImageGCChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, spec cephv1.ImageGCSpec, poolName string) *imageGCChecker {
+	c := &imageGCChecker{
+		context:        context,
+		client:         client,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		poolName:       poolName,
+		spec:           spec,
+		interval:       defaultImageGCInterval,
+		minAge:         defaultImageGCMinAge,
+		prefix:         defaultImageGCPrefix,
+	}
+	if spec.Interval != nil {
+		logger.Infof("image gc interval for block pool %q is %q", namespacedName.Name, spec.Interval.Duration.String())
+		c.interval = spec.Interval.Duration
+	}
+	if spec.MinAge != nil {
+		c.minAge = spec.MinAge.Duration
+	}
+	if spec.ImagePrefix != "" {
+		c.prefix = spec.ImagePrefix
+	}
+	return c
+}
+
+// collectImages scans the images of the pool right away and then at each interval
+func (c *imageGCChecker) collectImages(context context.Context) {
+	for {
+		status, err := c.scan(time.Now())
+		if err != nil {
+			logger.Warningf("failed to scan the orphaned images of block pool %q. %v", c.namespacedName.Name, err)
+			status = &cephv1.ImageGCStatus{Message: err.Error()}
+		}
+		c.updateStatusImageGC(status)
+
+		select {
+		case <-context.Done():
+			logger.Infof("stopping the image gc of block pool %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+		}
+	}
+}
+
+// volumeImages returns the names of the images of the pool used by the persistent volumes of the CSI driver. The
+// images of the persistent volumes of the pools with the same name in other Ceph clusters are also kept.
+func (c *imageGCChecker) volumeImages() (map[string]bool, error) {
+	pvs, err := c.context.Clientset.CoreV1().PersistentVolumes().List(c.clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the persistent volumes")
+	}
+	images := map[string]bool{}
+	for _, pv := range pvs.Items {
+		csi := pv.Spec.CSI
+		if csi == nil || !strings.HasSuffix(csi.Driver, rbdCSIDriverSuffix) {
+			continue
+		}
+		if pool, ok := csi.VolumeAttributes["pool"]; ok && pool != c.poolName {
+			continue
+		}
+		if name := csi.VolumeAttributes["imageName"]; name != "" {
+			images[name] = true
+		}
+		// the handle of a static volume is the name of its image
+		images[csi.VolumeHandle] = true
+	}
+	return images, nil
+}
+
+// scan finds the orphaned images of the pool, the images with the CSI prefix older than the minimum age without
+// persistent volume, and moves them to the trash if enabled
+func (c *imageGCChecker) scan(now time.Time) (*cephv1.ImageGCStatus, error) {
+	// the persistent volumes are listed before the images, so the images of the volumes provisioned in the
+	// meantime are not orphaned
+	volumeImages, err := c.volumeImages()
+	if err != nil {
+		return nil, err
+	}
+	images, err := cephclient.ListImages(c.context, c.clusterInfo, c.poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &cephv1.ImageGCStatus{LastChecked: now.UTC().Format(time.RFC3339)}
+	for _, image := range images {
+		if !strings.HasPrefix(image.Name, c.prefix) {
+			continue
+		}
+		if volumeImages[image.Name] || volumeImages[strings.TrimSuffix(image.Name, tempCloneSuffix)] {
+			continue
+		}
+		createTime, err := cephclient.GetImageCreateTime(c.context, c.clusterInfo, image.Name, c.poolName)
+		if err != nil {
+			logger.Warningf("not collecting image %q of block pool %q since its age is unknown. %v", image.Name, c.namespacedName.Name, err)
+			continue
+		}
+		if now.Sub(createTime) < c.minAge {
+			logger.Debugf("image %q of block pool %q without persistent volume is not older than %s", image.Name, c.namespacedName.Name, c.minAge.String())
+			continue
+		}
+
+		if c.spec.Action != cephv1.ImageGCActionTrash {
+			logger.Infof("image %q of block pool %q is orphaned", image.Name, c.namespacedName.Name)
+			status.OrphanedImages = append(status.OrphanedImages, image.Name)
+			continue
+		}
+		// an image still mapped on a node cannot be moved to the trash
+		if err := cephclient.TrashImage(c.context, c.clusterInfo, image.Name, c.poolName); err != nil {
+			logger.Warningf("failed to move orphaned image %q of block pool %q to the trash. %v", image.Name, c.namespacedName.Name, err)
+			status.OrphanedImages = append(status.OrphanedImages, image.Name)
+			continue
+		}
+		status.TrashedImages = append(status.TrashedImages, image.Name)
+	}
+	sort.Strings(status.OrphanedImages)
+	sort.Strings(status.TrashedImages)
+	return status, nil
+}
+
+// updateStatusImageGC updates the orphaned images in the status of the pool CR
+func (c *imageGCChecker) updateStatusImageGC(status *cephv1.ImageGCStatus) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update the image gc status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	blockPool.Status.ImageGC = status
+	if err := reporting.UpdateStatus(c.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q image gc status. %v", c.namespacedName.Name, err)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newCSIVolume(name, driver, pool, imageName string) *v1.PersistentVolume {
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{
+					Driver:           driver,
+					VolumeHandle:     "0001-0009-rook-ceph-0000000000000002-" + name,
+					VolumeAttributes: map[string]string{"pool": pool, "imageName": imageName},
+				},
+			},
+		},
+	}
+}
+
+func TestScanOrphanedImages(t *testing.T) {
+	namespacedName := types.NamespacedName{Namespace: "rook-ceph", Name: "replicapool"}
+	blockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(blockPool).Build()
+	clientset := kfake.NewSimpleClientset(
+		newCSIVolume("pvc-1", "rook-ceph.rbd.csi.ceph.com", "replicapool", "csi-vol-1"),
+		// the volume of the same image in another pool does not keep the image
+		newCSIVolume("pvc-2", "rook-ceph.rbd.csi.ceph.com", "otherpool", "csi-vol-2"),
+		newCSIVolume("pvc-5", "rook-ceph.rbd.csi.ceph.com", "replicapool", "csi-vol-5"),
+	)
+
+	now := time.Date(2021, 10, 15, 10, 0, 0, 0, time.UTC)
+	created := map[string]time.Time{
+		"csi-vol-1":      now.Add(-48 * time.Hour),
+		"csi-vol-2":      now.Add(-48 * time.Hour),
+		"csi-vol-3":      now.Add(-48 * time.Hour),
+		"csi-vol-4":      now.Add(-time.Hour),
+		"csi-vol-5-temp": now.Add(-48 * time.Hour),
+		"other":          now.Add(-48 * time.Hour),
+	}
+	var trashed []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case command == "rbd" && args[0] == "ls":
+				return `[{"image":"csi-vol-1"},{"image":"csi-vol-2"},{"image":"csi-vol-3"},{"image":"csi-vol-4"},{"image":"csi-vol-5-temp"},{"image":"other"}]`, nil
+			case command == "rbd" && args[0] == "info":
+				name := args[1][len("replicapool/"):]
+				return `{"name":"` + name + `","create_timestamp":"` + created[name].Format("Mon Jan _2 15:04:05 2006") + `"}`, nil
+			case command == "rbd" && args[0] == "trash" && args[1] == "mv":
+				trashed = append(trashed, args[2])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %q %v", command, args)
+		},
+	}
+	clusterInfo := cephclient.AdminClusterInfo(namespacedName.Namespace)
+	context := &clusterd.Context{Executor: executor, Clientset: clientset}
+	checker := newImageGCChecker(context, cl, clusterInfo, namespacedName, cephv1.ImageGCSpec{Enabled: true}, namespacedName.Name)
+	assert.Equal(t, defaultImageGCInterval, checker.interval)
+
+	// the orphaned images older than the minimum age are only reported by default
+	status, err := checker.scan(now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"csi-vol-2", "csi-vol-3"}, status.OrphanedImages)
+	assert.Empty(t, status.TrashedImages)
+	assert.Empty(t, trashed)
+
+	checker.updateStatusImageGC(status)
+	err = cl.Get(clusterInfo.Context, namespacedName, blockPool)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"csi-vol-2", "csi-vol-3"}, blockPool.Status.ImageGC.OrphanedImages)
+
+	// the orphaned images are moved to the trash
+	spec := cephv1.ImageGCSpec{Enabled: true, Action: cephv1.ImageGCActionTrash, MinAge: &metav1.Duration{Duration: 30 * time.Minute}}
+	checker = newImageGCChecker(context, cl, clusterInfo, namespacedName, spec, namespacedName.Name)
+	status, err = checker.scan(now)
+	require.NoError(t, err)
+	assert.Empty(t, status.OrphanedImages)
+	assert.Equal(t, []string{"csi-vol-2", "csi-vol-3", "csi-vol-4"}, status.TrashedImages)
+	assert.Equal(t, []string{"replicapool/csi-vol-2", "replicapool/csi-vol-3", "replicapool/csi-vol-4"}, trashed)
+}
+
+func TestReconcileImageGC(t *testing.T) {
+	namespacedName := types.NamespacedName{Namespace: "rook-ceph", Name: "replicapool"}
+	blockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(blockPool).Build()
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return "[]", nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	r := &ReconcileCephBlockPool{
+		client:           cl,
+		context:          &clusterd.Context{Executor: executor, Clientset: kfake.NewSimpleClientset()},
+		clusterInfo:      cephclient.AdminClusterInfo(namespacedName.Namespace),
+		imageGCContexts:  make(map[string]*imageGCRoutine),
+		opManagerContext: ctx,
+	}
+	key := "rook-ceph-replicapool"
+
+	r.reconcileImageGC(blockPool, namespacedName, key)
+	assert.Empty(t, r.imageGCContexts)
+
+	blockPool.Spec.ImageGC = &cephv1.ImageGCSpec{Enabled: true}
+	r.reconcileImageGC(blockPool, namespacedName, key)
+	require.Contains(t, r.imageGCContexts, key)
+	first := r.imageGCContexts[key]
+
+	// the image gc is restarted when its settings change
+	r.reconcileImageGC(blockPool, namespacedName, key)
+	assert.Equal(t, first, r.imageGCContexts[key])
+	blockPool.Spec.ImageGC.Action = cephv1.ImageGCActionTrash
+	r.reconcileImageGC(blockPool, namespacedName, key)
+	assert.NotEqual(t, first, r.imageGCContexts[key])
+
+	blockPool.Spec.ImageGC.Enabled = false
+	r.reconcileImageGC(blockPool, namespacedName, key)
+	assert.Empty(t, r.imageGCContexts)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	defaultMigrationMaxBackfills = 1
+	maxBackfillsOption           = "osd_max_backfills"
+)
+
+var (
+	// the progress of a migration is checked at this interval until the migration completes
+	migrationCheckInterval = time.Minute
+)
+
+// reconcileCrushRuleMigration migrates the pool to a new crush rule when its device class, failure domain or
+// crush root changes, and reports the progress of the migration in the status of the pool until all its data
+// is moved. The backfill of the OSDs is throttled during the migration.
+func (r *ReconcileCephBlockPool) reconcileCrushRuleMigration(clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, cephBlockPool *cephv1.CephBlockPool) (reconcile.Result, error) {
+	if !cephclient.IsPoolCrushRuleMigratable(clusterSpec, cephBlockPool.Spec) {
+		return reconcile.Result{}, nil
+	}
+	namespacedName := types.NamespacedName{Namespace: cephBlockPool.Namespace, Name: cephBlockPool.Name}
+
+	currentRule, targetRule, err := cephclient.PoolCrushRuleMigrationTarget(r.context, clusterInfo, clusterSpec, cephBlockPool.Name, cephBlockPool.Spec)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to check the crush rule of pool %q", cephBlockPool.Name)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if targetRule != "" {
+		if err := r.throttleMigration(clusterInfo, cephBlockPool.Spec); err != nil {
+			return reconcile.Result{}, err
+		}
+		logger.Infof("migrating pool %q from crush rule %q to crush rule %q", cephBlockPool.Name, currentRule, targetRule)
+		if err := cephclient.MigratePoolCrushRule(r.context, clusterInfo, clusterSpec, cephBlockPool.Name, targetRule, cephBlockPool.Spec); err != nil {
+			return reconcile.Result{}, err
+		}
+		// the progress is only checked at the next interval, once the placement groups are remapped
+		r.updateStatusMigration(namespacedName, &cephv1.PoolMigrationStatus{
+			Phase:             cephv1.PoolMigrationInProgress,
+			PreviousCrushRule: currentRule,
+			CrushRule:         targetRule,
+			StartTime:         now,
+			LastChecked:       now,
+		})
+		return reconcile.Result{RequeueAfter: migrationCheckInterval}, nil
+	}
+
+	if cephBlockPool.Status == nil || cephBlockPool.Status.Migration == nil || cephBlockPool.Status.Migration.Phase != cephv1.PoolMigrationInProgress {
+		return reconcile.Result{}, nil
+	}
+
+	status := cephBlockPool.Status.Migration.DeepCopy()
+	status.LastChecked = now
+	stats, err := cephclient.GetPoolRecoveryStats(r.context, clusterInfo, cephBlockPool.Name)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to check the migration progress of pool %q", cephBlockPool.Name)
+	}
+	if stats.MisplacedObjects > 0 {
+		status.MisplacedObjects = stats.MisplacedObjects
+		status.Progress = fmt.Sprintf("%.2f%%", (1-stats.MisplacedRatio)*100)
+		logger.Infof("migration of pool %q to crush rule %q in progress, %d objects misplaced (%s moved)", cephBlockPool.Name, status.CrushRule, stats.MisplacedObjects, status.Progress)
+		r.updateStatusMigration(namespacedName, status)
+		return reconcile.Result{RequeueAfter: migrationCheckInterval}, nil
+	}
+
+	logger.Infof("migration of pool %q to crush rule %q completed", cephBlockPool.Name, status.CrushRule)
+	if err := r.removeMigrationThrottle(clusterInfo, cephBlockPool); err != nil {
+		return reconcile.Result{}, err
+	}
+	status.Phase = cephv1.PoolMigrationCompleted
+	status.MisplacedObjects = 0
+	status.Progress = "100.00%"
+	status.CompletionTime = now
+	r.updateStatusMigration(namespacedName, status)
+	return reconcile.Result{}, nil
+}
+
+// migrationThrottleTarget returns the section of the config of the OSDs throttled during the migration of a pool,
+// the OSDs of the device class of the pool if any
+func migrationThrottleTarget(pool cephv1.PoolSpec) string {
+	if pool.DeviceClass == "" {
+		return "osd"
+	}
+	return fmt.Sprintf("osd/class:%s", pool.DeviceClass)
+}
+
+func (r *ReconcileCephBlockPool) throttleMigration(clusterInfo *cephclient.ClusterInfo, pool cephv1.PoolSpec) error {
+	maxBackfills := defaultMigrationMaxBackfills
+	if pool.Migration != nil && pool.Migration.MaxBackfills > 0 {
+		maxBackfills = pool.Migration.MaxBackfills
+	}
+	who := migrationThrottleTarget(pool)
+	if err := config.GetMonStore(r.context, clusterInfo).Set(who, maxBackfillsOption, strconv.Itoa(maxBackfills)); err != nil {
+		return errors.Wrapf(err, "failed to throttle the backfill of %q", who)
+	}
+	return nil
+}
+
+// removeMigrationThrottle removes the throttling of the backfill once no other pool is migrated to the same OSDs
+func (r *ReconcileCephBlockPool) removeMigrationThrottle(clusterInfo *cephclient.ClusterInfo, cephBlockPool *cephv1.CephBlockPool) error {
+	who := migrationThrottleTarget(cephBlockPool.Spec)
+	cephBlockPoolList := &cephv1.CephBlockPoolList{}
+	if err := r.client.List(context.TODO(), cephBlockPoolList, client.InNamespace(cephBlockPool.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to retrieve list of CephBlockPool")
+	}
+	for _, other := range cephBlockPoolList.Items {
+		if other.Name == cephBlockPool.Name || other.Status == nil || other.Status.Migration == nil {
+			continue
+		}
+		if other.Status.Migration.Phase == cephv1.PoolMigrationInProgress && migrationThrottleTarget(other.Spec) == who {
+			logger.Infof("keeping the backfill of %q throttled for the migration of pool %q", who, other.Name)
+			return nil
+		}
+	}
+
+	if err := config.GetMonStore(r.context, clusterInfo).Delete(who, maxBackfillsOption); err != nil {
+		return errors.Wrapf(err, "failed to remove the backfill throttling of %q", who)
+	}
+	return nil
+}
+
+func (r *ReconcileCephBlockPool) updateStatusMigration(namespacedName types.NamespacedName, status *cephv1.PoolMigrationStatus) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := r.client.Get(r.opManagerContext, namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update migration status. %v", namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	blockPool.Status.Migration = status
+	if err := reporting.UpdateStatus(r.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q migration status. %v", namespacedName.Name, err)
+		return
+	}
+
+	logger.Debugf("ceph block pool %q migration status updated", namespacedName.Name)
+}
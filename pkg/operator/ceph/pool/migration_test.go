@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// crushRuleMigrationTestOutput returns the output of the commands checking the crush rule of a pool, with
+// a rule placing the data of the pool on the hosts of the default root
+func crushRuleMigrationTestOutput(args []string) (string, bool) {
+	if len(args) > 4 && args[0] == "osd" && args[1] == "pool" && args[2] == "get" && args[4] == "crush_rule" {
+		return fmt.Sprintf(`{"pool":%q,"crush_rule":%q}`, args[3], args[3]), true
+	}
+	if len(args) > 4 && args[0] == "osd" && args[1] == "crush" && args[2] == "rule" && args[3] == "dump" {
+		return fmt.Sprintf(`{"rule_name":%q,"steps":[{"op":"take","item_name":"default"},{"op":"chooseleaf_firstn","num":0,"type":"host"},{"op":"emit"}]}`, args[4]), true
+	}
+	return "", false
+}
+
+func TestReconcileCrushRuleMigration(t *testing.T) {
+	namespacedName := types.NamespacedName{Namespace: "rook-ceph", Name: "replicapool"}
+	blockPool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace},
+		Spec:       cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}},
+	}
+	// another pool migrated to the same device class
+	otherPool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "otherpool", Namespace: namespacedName.Namespace},
+		Spec:       cephv1.PoolSpec{DeviceClass: "ssd", Replicated: cephv1.ReplicatedSpec{Size: 3}},
+		Status:     &cephv1.CephBlockPoolStatus{Migration: &cephv1.PoolMigrationStatus{Phase: cephv1.PoolMigrationInProgress}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(blockPool, otherPool).Build()
+
+	currentRule := "replicapool"
+	recovery := `{"misplaced_objects":300,"misplaced_total":1200,"misplaced_ratio":0.25}`
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "get" && args[4] == "crush_rule":
+				return fmt.Sprintf(`{"pool":"replicapool","crush_rule":%q}`, currentRule), nil
+			case args[0] == "osd" && args[1] == "crush" && args[2] == "rule" && args[3] == "dump":
+				if args[4] == "replicapool" {
+					return `{"rule_name":"replicapool","steps":[{"op":"take","item_name":"default~hdd"},{"op":"chooseleaf_firstn","num":0,"type":"host"},{"op":"emit"}]}`, nil
+				}
+				return fmt.Sprintf(`{"rule_name":%q,"steps":[{"op":"take","item_name":"default~ssd"},{"op":"chooseleaf_firstn","num":0,"type":"host"},{"op":"emit"}]}`, args[4]), nil
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "stats":
+				return fmt.Sprintf(`[{"pool_name":"replicapool","recovery":%s}]`, recovery), nil
+			case args[0] == "osd" && args[1] == "crush" && args[2] == "rule" && args[3] == "create-replicated",
+				args[0] == "osd" && args[1] == "pool" && args[2] == "set",
+				args[0] == "config":
+				commands = append(commands, fmt.Sprint(args[:6]))
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %q %v", command, args)
+		},
+	}
+	clusterInfo := cephclient.AdminClusterInfo(namespacedName.Namespace)
+	r := &ReconcileCephBlockPool{
+		client:           cl,
+		context:          &clusterd.Context{Executor: executor, Client: cl},
+		opManagerContext: context.TODO(),
+	}
+	clusterSpec := &cephv1.ClusterSpec{}
+	getPool := func() *cephv1.CephBlockPool {
+		p := &cephv1.CephBlockPool{}
+		err := cl.Get(context.TODO(), namespacedName, p)
+		require.NoError(t, err)
+		p.Spec = blockPool.Spec
+		return p
+	}
+
+	// the pool is not migrated while its rule places the data as specified
+	blockPool.Spec.DeviceClass = "hdd"
+	result, err := r.reconcileCrushRuleMigration(clusterInfo, clusterSpec, blockPool)
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Empty(t, commands)
+
+	// the device class changes
+	blockPool.Spec.DeviceClass = "ssd"
+	blockPool.Spec.Migration = &cephv1.PoolMigrationSpec{MaxBackfills: 2}
+	result, err = r.reconcileCrushRuleMigration(clusterInfo, clusterSpec, blockPool)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	assert.Equal(t, []string{
+		"[config set osd/class:ssd osd_max_backfills 2 --connect-timeout=15]",
+		"[osd crush rule create-replicated replicapool_default_host_ssd default]",
+		"[osd pool set replicapool crush_rule replicapool_default_host_ssd]",
+	}, commands)
+	blockPool = getPool()
+	status := blockPool.Status.Migration
+	assert.Equal(t, cephv1.PoolMigrationInProgress, status.Phase)
+	assert.Equal(t, "replicapool", status.PreviousCrushRule)
+	assert.Equal(t, "replicapool_default_host_ssd", status.CrushRule)
+	assert.NotEmpty(t, status.StartTime)
+
+	// the progress is reported while objects are misplaced
+	currentRule = "replicapool_default_host_ssd"
+	commands = nil
+	result, err = r.reconcileCrushRuleMigration(clusterInfo, clusterSpec, blockPool)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	assert.Empty(t, commands)
+	blockPool = getPool()
+	assert.Equal(t, cephv1.PoolMigrationInProgress, blockPool.Status.Migration.Phase)
+	assert.Equal(t, int64(300), blockPool.Status.Migration.MisplacedObjects)
+	assert.Equal(t, "75.00%", blockPool.Status.Migration.Progress)
+
+	// the migration completes, the throttling is kept for the migration of the other pool
+	recovery = `{}`
+	result, err = r.reconcileCrushRuleMigration(clusterInfo, clusterSpec, blockPool)
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Empty(t, commands)
+	blockPool = getPool()
+	assert.Equal(t, cephv1.PoolMigrationCompleted, blockPool.Status.Migration.Phase)
+	assert.Equal(t, "100.00%", blockPool.Status.Migration.Progress)
+	assert.Zero(t, blockPool.Status.Migration.MisplacedObjects)
+	assert.NotEmpty(t, blockPool.Status.Migration.CompletionTime)
+
+	// the throttling is removed once no other pool is migrated to the device class
+	otherPool = &cephv1.CephBlockPool{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespacedName.Namespace, Name: "otherpool"}, otherPool)
+	require.NoError(t, err)
+	otherPool.Status.Migration.Phase = cephv1.PoolMigrationCompleted
+	err = cl.Update(context.TODO(), otherPool)
+	require.NoError(t, err)
+	blockPool.Status.Migration.Phase = cephv1.PoolMigrationInProgress
+	_, err = r.reconcileCrushRuleMigration(clusterInfo, clusterSpec, blockPool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[config rm osd/class:ssd osd_max_backfills --connect-timeout=15 --cluster=rook-ceph]"}, commands)
+
+	// erasure coded pools are not migrated
+	commands = nil
+	ecPool := &cephv1.CephBlockPool{Spec: cephv1.PoolSpec{DeviceClass: "hdd", ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}}
+	result, err = r.reconcileCrushRuleMigration(clusterInfo, clusterSpec, ecPool)
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Empty(t, commands)
+}
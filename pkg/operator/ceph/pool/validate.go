@@ -23,6 +23,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
 )
 
 // ValidatePool Validate the pool arguments
@@ -71,6 +72,16 @@ func ValidatePoolSpec(context *clusterd.Context, clusterInfo *cephclient.Cluster
 		}
 	}
 
+	// validate the mclock scheduler settings, which are applied to the osds of the device class
+	if p.MClock != nil {
+		if p.DeviceClass == "" {
+			return errors.New("mclock settings require the device class of the pool")
+		}
+		if err := config.ValidateMClockSpec(p.MClock); err != nil {
+			return errors.Wrap(err, "invalid mclock settings")
+		}
+	}
+
 	var crush client.CrushMap
 	var err error
 	if p.FailureDomain != "" || p.CrushRoot != "" {
@@ -49,6 +49,20 @@ func TestValidatePool(t *testing.T) {
 	err = ValidatePool(context, clusterInfo, clusterSpec, &p)
 	assert.NotNil(t, err)
 
+	// mclock settings require the device class
+	p = cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
+	p.Spec.MClock = &cephv1.MClockSpec{Profile: "high_client_ops"}
+	err = ValidatePool(context, clusterInfo, clusterSpec, &p)
+	assert.Error(t, err)
+	p.Spec.DeviceClass = "ssd"
+	err = ValidatePool(context, clusterInfo, clusterSpec, &p)
+	assert.NoError(t, err)
+
+	// mclock allocations require the custom profile
+	p.Spec.MClock.Client = &cephv1.MClockAllocationSpec{Weight: 2}
+	err = ValidatePool(context, clusterInfo, clusterSpec, &p)
+	assert.Error(t, err)
+
 	// must not specify both replication and EC settings
 	p = cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
 	p.Spec.Replicated.Size = 1
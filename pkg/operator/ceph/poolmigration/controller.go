@@ -0,0 +1,279 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package poolmigration to migrate the data of the rbd images of a replicated pool to an erasure coded pool
+package poolmigration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-pool-migration-controller"
+
+	// the images in use are checked again at this interval until their clients are stopped
+	waitForClientsInterval = time.Minute
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephPoolMigrationKind = reflect.TypeOf(cephv1.CephPoolMigration{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephPoolMigrationKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephPoolMigration reconciles a CephPoolMigration object
+type ReconcileCephPoolMigration struct {
+	client           client.Client
+	context          *clusterd.Context
+	opManagerContext context.Context
+}
+
+// Add creates a new CephPoolMigration Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	opManagerContext = k8sutil.WithControllerName(opManagerContext, controllerName)
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephPoolMigration{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller. A single image is migrated at a time so that the migrations do not slow down
+	// the clients of the cluster.
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: 1})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephPoolMigration CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephPoolMigration{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephPoolMigration object and makes changes based on the state
+// read and what is in the CephPoolMigration.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephPoolMigration) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephPoolMigration) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephPoolMigration instance
+	migration := &cephv1.CephPoolMigration{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, migration)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephPoolMigration resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephPoolMigration")
+	}
+
+	// Set a finalizer so we can abort the migrations which are not committed
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, migration)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// the images are gone with the cluster
+		if !migration.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			return reconcile.Result{}, r.removeFinalizer(migration)
+		}
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	clusterInfo, _, _, err := mon.LoadClusterInfo(r.context, r.opManagerContext, request.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	// DELETE: the CR was deleted
+	if !migration.GetDeletionTimestamp().IsZero() {
+		logger.Infof("aborting the migrations of the images of ceph pool migration %q which are not committed", migration.Name)
+		if err := abortMigrations(r.context, clusterInfo, migration.Spec, migration.Status); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to abort ceph pool migration %q", migration.Name)
+		}
+		return reconcile.Result{}, r.removeFinalizer(migration)
+	}
+
+	// A migration completes once for each generation of its spec
+	if isCompleted(migration) {
+		logger.Debugf("ceph pool migration %q already completed", migration.Name)
+		return reconcile.Result{}, nil
+	}
+
+	status := migration.Status
+	if status == nil || status.ObservedGeneration != migration.Generation {
+		// an invalid migration is not retried until its spec is updated
+		if err := validateMigration(r.context, clusterInfo, migration.Spec); err != nil {
+			logger.Errorf("invalid ceph pool migration %q. %v", migration.Name, err)
+			r.updateStatus(request.NamespacedName, migration.Generation, func(status *cephv1.CephPoolMigrationStatus) {
+				status.Phase = cephv1.PoolDataMigrationFailed
+				status.Message = err.Error()
+				status.CompletionTime = &metav1.Time{Time: time.Now()}
+			})
+			return reconcile.Result{}, nil
+		}
+		names, err := migrationImages(r.context, clusterInfo, migration.Spec)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to list the images of ceph pool migration %q", migration.Name)
+		}
+		logger.Infof("migrating the data of %d images of pool %q to data pool %q", len(names), migration.Spec.SourcePool, migration.Spec.TargetDataPool)
+		status = r.updateStatus(request.NamespacedName, migration.Generation, func(status *cephv1.CephPoolMigrationStatus) {
+			status.Phase = cephv1.PoolDataMigrationRunning
+			status.Message = ""
+			status.StartTime = &metav1.Time{Time: time.Now()}
+			status.CompletionTime = nil
+			initImages(status, names)
+		})
+		if status == nil {
+			return reconcile.Result{}, errors.Errorf("failed to start ceph pool migration %q", migration.Name)
+		}
+	}
+
+	// a single image is migrated by each reconcile, so the progress is reported and the deletion of the CR
+	// aborts the migration between the images
+	i := nextImage(status, migration.Spec.ManualCommit)
+	if i >= 0 {
+		name := status.Images[i].Name
+		phase, err := migrateImage(r.context, clusterInfo, migration.Spec, name)
+		message := ""
+		if err != nil {
+			logger.Errorf("failed to migrate image %q of ceph pool migration %q. %v", name, migration.Name, err)
+			message = err.Error()
+		}
+		r.updateStatus(request.NamespacedName, migration.Generation, func(status *cephv1.CephPoolMigrationStatus) {
+			status.Phase = cephv1.PoolDataMigrationRunning
+			for j := range status.Images {
+				if status.Images[j].Name == name {
+					status.Images[j].Phase = phase
+					status.Images[j].Message = message
+				}
+			}
+			updateCounts(status)
+		})
+		return opcontroller.ImmediateRetryResultNoBackoff, nil
+	}
+
+	var phase cephv1.PoolDataMigrationPhase
+	r.updateStatus(request.NamespacedName, migration.Generation, func(status *cephv1.CephPoolMigrationStatus) {
+		phase, status.Message = completedPhase(status)
+		status.Phase = phase
+		if phase == cephv1.PoolDataMigrationSucceeded || phase == cephv1.PoolDataMigrationFailed {
+			status.CompletionTime = &metav1.Time{Time: time.Now()}
+		}
+	})
+	logger.Infof("ceph pool migration %q is %q", migration.Name, phase)
+	if phase == cephv1.PoolDataMigrationWaitingForClients {
+		return reconcile.Result{RequeueAfter: waitForClientsInterval}, nil
+	}
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// isCompleted returns whether the migration already succeeded, failed or waits for the commit for the current
+// generation of its spec
+func isCompleted(migration *cephv1.CephPoolMigration) bool {
+	status := migration.Status
+	if status == nil || status.ObservedGeneration != migration.Generation {
+		return false
+	}
+	return status.Phase == cephv1.PoolDataMigrationSucceeded || status.Phase == cephv1.PoolDataMigrationFailed || status.Phase == cephv1.PoolDataMigrationWaitingForCommit
+}
+
+func (r *ReconcileCephPoolMigration) removeFinalizer(migration *cephv1.CephPoolMigration) error {
+	if err := opcontroller.RemoveFinalizer(r.client, migration); err != nil {
+		return errors.Wrap(err, "failed to remove finalizer")
+	}
+	return nil
+}
+
+// updateStatus updates the status of a migration for the given generation of its spec, and returns the updated
+// status or nil if the status was not updated
+func (r *ReconcileCephPoolMigration) updateStatus(name types.NamespacedName, generation int64, update func(status *cephv1.CephPoolMigrationStatus)) *cephv1.CephPoolMigrationStatus {
+	migration := &cephv1.CephPoolMigration{}
+	if err := r.client.Get(r.opManagerContext, name, migration); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephPoolMigration resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		logger.Warningf("failed to retrieve ceph pool migration %q to update status. %v", name, err)
+		return nil
+	}
+	if migration.Status == nil {
+		migration.Status = &cephv1.CephPoolMigrationStatus{}
+	}
+
+	update(migration.Status)
+	migration.Status.ObservedGeneration = generation
+	if err := reporting.UpdateStatus(r.client, migration); err != nil {
+		logger.Errorf("failed to set ceph pool migration %q status to %q. %v", name, migration.Status.Phase, err)
+		return nil
+	}
+	logger.Debugf("ceph pool migration %q status updated to %q", name, migration.Status.Phase)
+	return migration.Status
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poolmigration
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+// validateMigration checks the migration moves the data of the images from a replicated pool to an erasure coded pool
+func validateMigration(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, spec cephv1.CephPoolMigrationSpec) error {
+	if spec.SourcePool == "" || spec.TargetDataPool == "" {
+		return errors.New("missing source pool or target data pool")
+	}
+	if spec.SourcePool == spec.TargetDataPool {
+		return errors.New("the target data pool must differ from the source pool")
+	}
+	source, err := cephclient.GetPoolDetails(context, clusterInfo, spec.SourcePool)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get source pool %q", spec.SourcePool)
+	}
+	if source.ErasureCodeProfile != "" {
+		return errors.Errorf("source pool %q is erasure coded, the images must be in a replicated pool", spec.SourcePool)
+	}
+	target, err := cephclient.GetPoolDetails(context, clusterInfo, spec.TargetDataPool)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get target data pool %q", spec.TargetDataPool)
+	}
+	if target.ErasureCodeProfile == "" {
+		return errors.Errorf("target data pool %q is not erasure coded", spec.TargetDataPool)
+	}
+	return nil
+}
+
+// migrationImages returns the names of the images to migrate, sorted
+func migrationImages(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, spec cephv1.CephPoolMigrationSpec) ([]string, error) {
+	names := map[string]bool{}
+	if len(spec.Images) > 0 {
+		for _, name := range spec.Images {
+			names[name] = true
+		}
+	} else {
+		// the images are listed once for each of their snapshots
+		images, err := cephclient.ListImages(context, clusterInfo, spec.SourcePool)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range images {
+			names[image.Name] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// initImages sets the images of a new generation of the migration in the status. The phases of the images that
+// are still in the migration are kept, and the failed images are retried.
+func initImages(status *cephv1.CephPoolMigrationStatus, names []string) {
+	phases := map[string]cephv1.ImageMigrationPhase{}
+	for _, image := range status.Images {
+		if image.Phase != cephv1.ImageMigrationFailed {
+			phases[image.Name] = image.Phase
+		}
+	}
+	status.Images = make([]cephv1.ImageMigrationStatus, 0, len(names))
+	for _, name := range names {
+		status.Images = append(status.Images, cephv1.ImageMigrationStatus{Name: name, Phase: phases[name]})
+	}
+	updateCounts(status)
+}
+
+// nextImage returns the next image to migrate, or -1 if no image can be migrated now
+func nextImage(status *cephv1.CephPoolMigrationStatus, manualCommit bool) int {
+	for i, image := range status.Images {
+		switch image.Phase {
+		case "", cephv1.ImageMigrationPrepared:
+			return i
+		case cephv1.ImageMigrationExecuted:
+			if !manualCommit {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// completedPhase returns the phase of the migration once no image can be migrated. The images waiting for their
+// clients are retried on the next check.
+func completedPhase(status *cephv1.CephPoolMigrationStatus) (cephv1.PoolDataMigrationPhase, string) {
+	var waiting, executed, failed int
+	for i, image := range status.Images {
+		switch image.Phase {
+		case cephv1.ImageMigrationWaitingForClients:
+			waiting++
+			status.Images[i].Phase = ""
+		case cephv1.ImageMigrationExecuted:
+			executed++
+		case cephv1.ImageMigrationFailed:
+			failed++
+		}
+	}
+	switch {
+	case waiting > 0:
+		return cephv1.PoolDataMigrationWaitingForClients, ""
+	case failed > 0:
+		return cephv1.PoolDataMigrationFailed, "failed to migrate some images"
+	case executed > 0:
+		return cephv1.PoolDataMigrationWaitingForCommit, ""
+	}
+	return cephv1.PoolDataMigrationSucceeded, ""
+}
+
+func updateCounts(status *cephv1.CephPoolMigrationStatus) {
+	status.TotalImages = len(status.Images)
+	status.MigratedImages = 0
+	for _, image := range status.Images {
+		if image.Phase == cephv1.ImageMigrationCommitted {
+			status.MigratedImages++
+		}
+	}
+}
+
+// migrateImage moves the data of an image to the target data pool, from the current state of the migration of
+// the image, and returns the phase reached by the migration of the image
+func migrateImage(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, spec cephv1.CephPoolMigrationSpec, name string) (cephv1.ImageMigrationPhase, error) {
+	status, err := cephclient.GetImageStatus(context, clusterInfo, name, spec.SourcePool)
+	if err != nil {
+		return cephv1.ImageMigrationFailed, err
+	}
+
+	state := ""
+	if status.Migration != nil {
+		state = status.Migration.State
+	} else {
+		dataPool, err := cephclient.GetImageDataPool(context, clusterInfo, name, spec.SourcePool)
+		if err != nil {
+			return cephv1.ImageMigrationFailed, err
+		}
+		if dataPool == spec.TargetDataPool {
+			// the migration of the image is already committed
+			return cephv1.ImageMigrationCommitted, nil
+		}
+		// the clients of the image must be stopped until the migration is prepared
+		if len(status.Watchers) > 0 {
+			logger.Infof("waiting for the %d clients of image %q to stop before migrating it", len(status.Watchers), name)
+			return cephv1.ImageMigrationWaitingForClients, nil
+		}
+		if err := cephclient.PrepareImageMigration(context, clusterInfo, name, spec.SourcePool, spec.TargetDataPool); err != nil {
+			return cephv1.ImageMigrationFailed, err
+		}
+		state = cephclient.ImageMigrationStatePrepared
+	}
+
+	if state == cephclient.ImageMigrationStatePrepared || state == cephclient.ImageMigrationStateExecuting {
+		if err := cephclient.ExecuteImageMigration(context, clusterInfo, name, spec.SourcePool); err != nil {
+			return cephv1.ImageMigrationFailed, err
+		}
+		state = cephclient.ImageMigrationStateExecuted
+	}
+
+	if state != cephclient.ImageMigrationStateExecuted {
+		return cephv1.ImageMigrationFailed, errors.Errorf("unexpected migration state %q of image %q", state, name)
+	}
+	if spec.ManualCommit {
+		return cephv1.ImageMigrationExecuted, nil
+	}
+	if err := cephclient.CommitImageMigration(context, clusterInfo, name, spec.SourcePool); err != nil {
+		return cephv1.ImageMigrationFailed, err
+	}
+	return cephv1.ImageMigrationCommitted, nil
+}
+
+// abortMigrations aborts the migrations of the images which are not committed
+func abortMigrations(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, spec cephv1.CephPoolMigrationSpec, status *cephv1.CephPoolMigrationStatus) error {
+	if status == nil {
+		return nil
+	}
+	for _, image := range status.Images {
+		if image.Phase == cephv1.ImageMigrationCommitted {
+			continue
+		}
+		imageStatus, err := cephclient.GetImageStatus(context, clusterInfo, image.Name, spec.SourcePool)
+		if err != nil {
+			return err
+		}
+		if imageStatus.Migration == nil {
+			continue
+		}
+		if err := cephclient.AbortImageMigration(context, clusterInfo, image.Name, spec.SourcePool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
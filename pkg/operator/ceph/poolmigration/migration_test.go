@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poolmigration
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var spec = cephv1.CephPoolMigrationSpec{SourcePool: "replicapool", TargetDataPool: "ec-data-pool"}
+
+// newExecutor returns an executor mocking the rbd commands on an image with the given status and data pool,
+// recording the migration commands
+func newExecutor(status, dataPool string, commands *[]string) *exectest.MockExecutor {
+	return &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case command == "rbd" && args[0] == "status":
+				return status, nil
+			case command == "rbd" && args[0] == "info":
+				return `{"name":"img1","data_pool":"` + dataPool + `"}`, nil
+			case command == "rbd" && args[0] == "migration":
+				*commands = append(*commands, args[1]+" "+args[2])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %q %q", command, args)
+		},
+	}
+}
+
+func TestMigrateImage(t *testing.T) {
+	clusterInfo := cephclient.AdminClusterInfo("mycluster")
+	notMigrating := `{"watchers":[]}`
+
+	t.Run("full migration", func(t *testing.T) {
+		var commands []string
+		context := &clusterd.Context{Executor: newExecutor(notMigrating, "", &commands)}
+		phase, err := migrateImage(context, clusterInfo, spec, "img1")
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ImageMigrationCommitted, phase)
+		assert.Equal(t, []string{"prepare replicapool/img1", "execute replicapool/img1", "commit replicapool/img1"}, commands)
+	})
+
+	t.Run("manual commit", func(t *testing.T) {
+		var commands []string
+		context := &clusterd.Context{Executor: newExecutor(notMigrating, "", &commands)}
+		manualSpec := spec
+		manualSpec.ManualCommit = true
+		phase, err := migrateImage(context, clusterInfo, manualSpec, "img1")
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ImageMigrationExecuted, phase)
+		assert.Equal(t, []string{"prepare replicapool/img1", "execute replicapool/img1"}, commands)
+	})
+
+	t.Run("image in use", func(t *testing.T) {
+		var commands []string
+		context := &clusterd.Context{Executor: newExecutor(`{"watchers":[{"address":"10.0.0.1:0/1234"}]}`, "", &commands)}
+		phase, err := migrateImage(context, clusterInfo, spec, "img1")
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ImageMigrationWaitingForClients, phase)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("migration resumed", func(t *testing.T) {
+		var commands []string
+		status := `{"watchers":[{"address":"10.0.0.1:0/1234"}],"migration":{"source_pool_name":"replicapool","dest_pool_name":"replicapool","state":"executed"}}`
+		context := &clusterd.Context{Executor: newExecutor(status, "", &commands)}
+		phase, err := migrateImage(context, clusterInfo, spec, "img1")
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ImageMigrationCommitted, phase)
+		assert.Equal(t, []string{"commit replicapool/img1"}, commands)
+	})
+
+	t.Run("already migrated", func(t *testing.T) {
+		var commands []string
+		context := &clusterd.Context{Executor: newExecutor(notMigrating, "ec-data-pool", &commands)}
+		phase, err := migrateImage(context, clusterInfo, spec, "img1")
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ImageMigrationCommitted, phase)
+		assert.Empty(t, commands)
+	})
+}
+
+func TestAbortMigrations(t *testing.T) {
+	clusterInfo := cephclient.AdminClusterInfo("mycluster")
+	var commands []string
+	status := `{"watchers":[],"migration":{"source_pool_name":"replicapool","dest_pool_name":"replicapool","state":"executed"}}`
+	context := &clusterd.Context{Executor: newExecutor(status, "", &commands)}
+
+	assert.NoError(t, abortMigrations(context, clusterInfo, spec, nil))
+	migrationStatus := &cephv1.CephPoolMigrationStatus{Images: []cephv1.ImageMigrationStatus{
+		{Name: "img1", Phase: cephv1.ImageMigrationCommitted},
+		{Name: "img2", Phase: cephv1.ImageMigrationExecuted},
+	}}
+	assert.NoError(t, abortMigrations(context, clusterInfo, spec, migrationStatus))
+	assert.Equal(t, []string{"abort replicapool/img2"}, commands)
+}
+
+func TestMigrationPhases(t *testing.T) {
+	status := &cephv1.CephPoolMigrationStatus{Images: []cephv1.ImageMigrationStatus{
+		{Name: "img1", Phase: cephv1.ImageMigrationCommitted},
+		{Name: "img2", Phase: cephv1.ImageMigrationFailed, Message: "failed"},
+		{Name: "img3", Phase: cephv1.ImageMigrationExecuted},
+	}}
+	initImages(status, []string{"img1", "img2", "img3", "img4"})
+	assert.Equal(t, 4, status.TotalImages)
+	assert.Equal(t, 1, status.MigratedImages)
+	assert.Equal(t, cephv1.ImageMigrationPhase(""), status.Images[1].Phase)
+	assert.Equal(t, cephv1.ImageMigrationExecuted, status.Images[2].Phase)
+
+	assert.Equal(t, 1, nextImage(status, false))
+	status.Images[1].Phase = cephv1.ImageMigrationWaitingForClients
+	assert.Equal(t, 2, nextImage(status, false))
+	assert.Equal(t, 3, nextImage(status, true))
+	status.Images[3].Phase = cephv1.ImageMigrationFailed
+	assert.Equal(t, -1, nextImage(status, true))
+
+	phase, _ := completedPhase(status)
+	assert.Equal(t, cephv1.PoolDataMigrationWaitingForClients, phase)
+	assert.Equal(t, cephv1.ImageMigrationPhase(""), status.Images[1].Phase)
+
+	status.Images[1].Phase = cephv1.ImageMigrationCommitted
+	phase, message := completedPhase(status)
+	assert.Equal(t, cephv1.PoolDataMigrationFailed, phase)
+	assert.NotEmpty(t, message)
+
+	status.Images[3].Phase = cephv1.ImageMigrationCommitted
+	phase, _ = completedPhase(status)
+	assert.Equal(t, cephv1.PoolDataMigrationWaitingForCommit, phase)
+
+	status.Images[2].Phase = cephv1.ImageMigrationCommitted
+	phase, _ = completedPhase(status)
+	assert.Equal(t, cephv1.PoolDataMigrationSucceeded, phase)
+}
+
+func TestValidateMigration(t *testing.T) {
+	clusterInfo := cephclient.AdminClusterInfo("mycluster")
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if command == "ceph" && args[0] == "osd" && args[1] == "pool" && args[2] == "get" {
+				switch args[3] {
+				case "replicapool":
+					return `{"pool":"replicapool","pool_id":1,"size":3}`, nil
+				case "ec-data-pool":
+					return `{"pool":"ec-data-pool","pool_id":2,"size":3,"erasure_code_profile":"ec-data-pool_ecprofile"}`, nil
+				}
+				return "", errors.New("ENOENT")
+			}
+			return "", errors.Errorf("unexpected command %q %q", command, args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	assert.NoError(t, validateMigration(context, clusterInfo, spec))
+	assert.Error(t, validateMigration(context, clusterInfo, cephv1.CephPoolMigrationSpec{SourcePool: "replicapool"}))
+	assert.Error(t, validateMigration(context, clusterInfo, cephv1.CephPoolMigrationSpec{SourcePool: "replicapool", TargetDataPool: "replicapool"}))
+	assert.Error(t, validateMigration(context, clusterInfo, cephv1.CephPoolMigrationSpec{SourcePool: "ec-data-pool", TargetDataPool: "replicapool"}))
+	assert.Error(t, validateMigration(context, clusterInfo, cephv1.CephPoolMigrationSpec{SourcePool: "replicapool", TargetDataPool: "missing"}))
+}
@@ -61,6 +61,9 @@ type provisionerConfig struct {
 
 	// Optional: For erasure coded pools the data pool must be given
 	dataBlockPool string
+
+	// Optional: `Block` exposes the rbd device in the pod volume without a filesystem. Default is `Filesystem`
+	volumeMode string
 }
 
 // New creates RookVolumeProvisioner
@@ -78,6 +81,11 @@ func (p *RookVolumeProvisioner) Provision(_ context.Context, options controller.
 	if options.PVC.Spec.Selector != nil {
 		return nil, controller.ProvisioningFinished, errors.New("claim Selector is not supported")
 	}
+	// the flex volume plugin of the kubelet cannot map raw block volumes, the block volume mode of the flex driver
+	// is set in the storage class and exposes the device in the pod volume of a filesystem claim instead
+	if options.PVC.Spec.VolumeMode != nil && *options.PVC.Spec.VolumeMode == v1.PersistentVolumeBlock {
+		return nil, controller.ProvisioningFinished, errors.Errorf("claim volumeMode %q is not supported by the flex driver, set the %q parameter of the storage class instead", v1.PersistentVolumeBlock, flexvolume.VolumeModeKey)
+	}
 
 	cfg, err := parseClassParameters(options.StorageClass.Parameters)
 	if err != nil {
@@ -153,6 +161,9 @@ func (p *RookVolumeProvisioner) Provision(_ context.Context, options controller.
 			},
 		},
 	}
+	if cfg.volumeMode != "" {
+		pv.Spec.PersistentVolumeSource.FlexVolume.Options[flexvolume.VolumeModeKey] = cfg.volumeMode
+	}
 	logger.Infof("successfully created Rook Block volume %+v", pv.Spec.PersistentVolumeSource.FlexVolume)
 	return pv, controller.ProvisioningFinished, nil
 }
@@ -234,6 +245,11 @@ func parseClassParameters(params map[string]string) (*provisionerConfig, error)
 			cfg.fstype = v
 		case "datablockpool":
 			cfg.dataBlockPool = v
+		case "volumemode":
+			if v != flexvolume.VolumeModeBlock && v != flexvolume.VolumeModeFilesystem {
+				return nil, errors.Errorf("invalid volumeMode %q, must be %q or %q", v, flexvolume.VolumeModeBlock, flexvolume.VolumeModeFilesystem)
+			}
+			cfg.volumeMode = v
 		default:
 			return nil, errors.Errorf("invalid option %q for volume plugin %s", k, "rookVolumeProvisioner")
 		}
@@ -189,6 +189,29 @@ func TestParseClassParametersNoPool(t *testing.T) {
 
 }
 
+func TestParseClassParametersVolumeMode(t *testing.T) {
+	cfg := map[string]string{"pool": "testPool", "volumeMode": "Block"}
+	provConfig, err := parseClassParameters(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, "Block", provConfig.volumeMode)
+
+	cfg["volumeMode"] = "Raw"
+	_, err = parseClassParameters(cfg)
+	assert.EqualError(t, err, "invalid volumeMode \"Raw\", must be \"Block\" or \"Filesystem\"")
+}
+
+func TestProvisionBlockVolumeMode(t *testing.T) {
+	provisioner := New(&clusterd.Context{}, "foo.io")
+	claim := newClaim("claim-1", "uid-1-1", "class-1", "", "class-1", nil)
+	volumeMode := v1.PersistentVolumeBlock
+	claim.Spec.VolumeMode = &volumeMode
+	volume := newProvisionOptions(newStorageClass("class-1", "foo.io/block", map[string]string{"pool": "testpool"}, v1.PersistentVolumeReclaimRetain), claim, v1.PersistentVolumeReclaimRetain)
+
+	_, ps, err := provisioner.Provision(context.TODO(), volume)
+	assert.Error(t, err)
+	assert.Equal(t, controller.ProvisioningFinished, ps)
+}
+
 func TestParseClassParametersInvalidOption(t *testing.T) {
 	cfg := make(map[string]string)
 	cfg["pool"] = "testPool"
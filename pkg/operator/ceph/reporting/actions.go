@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporting
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MaxCorrectiveActions is the number of the most recent corrective actions kept in the status of the CephCluster
+const MaxCorrectiveActions = 50
+
+// RecordCorrectiveAction adds an automated corrective action taken by the operator to the history
+// in the status of the CephCluster. The oldest actions are dropped from the history once it is full.
+func RecordCorrectiveAction(
+	ctx context.Context, client client.Client, nsName types.NamespacedName,
+	actionType cephv1.CorrectiveActionType, target, reason string,
+) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := client.Get(ctx, nsName, cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", nsName.String())
+	}
+
+	action := cephv1.CorrectiveAction{
+		Time:   time.Now().UTC().Format(time.RFC3339),
+		Type:   actionType,
+		Target: target,
+		Reason: reason,
+	}
+	actions := append(cephCluster.Status.CorrectiveActions, action)
+	if len(actions) > MaxCorrectiveActions {
+		actions = actions[len(actions)-MaxCorrectiveActions:]
+	}
+	cephCluster.Status.CorrectiveActions = actions
+
+	if err := UpdateStatus(client, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to record corrective action %s of %q", actionType, target)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordCorrectiveAction(t *testing.T) {
+	ctx := context.TODO()
+	nsName := types.NamespacedName{Namespace: "rook-ceph", Name: "my-cluster"}
+
+	// nothing to record when the cluster is deleted
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	err := RecordCorrectiveAction(ctx, cl, nsName, cephv1.CorrectiveActionMonFailover, "a", "out of quorum")
+	assert.NoError(t, err)
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+	cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+	err = RecordCorrectiveAction(ctx, cl, nsName, cephv1.CorrectiveActionMonFailover, "a", "out of quorum")
+	assert.NoError(t, err)
+	cluster := &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, nsName, cluster))
+	require.Len(t, cluster.Status.CorrectiveActions, 1)
+	action := cluster.Status.CorrectiveActions[0]
+	assert.Equal(t, cephv1.CorrectiveActionMonFailover, action.Type)
+	assert.Equal(t, "a", action.Target)
+	assert.Equal(t, "out of quorum", action.Reason)
+	assert.NotEmpty(t, action.Time)
+
+	// only the most recent actions are kept
+	for i := 0; i < MaxCorrectiveActions; i++ {
+		err = RecordCorrectiveAction(ctx, cl, nsName, cephv1.CorrectiveActionOSDRemoval, fmt.Sprintf("osd.%d", i), "")
+		require.NoError(t, err)
+	}
+	cluster = &cephv1.CephCluster{}
+	require.NoError(t, cl.Get(ctx, nsName, cluster))
+	require.Len(t, cluster.Status.CorrectiveActions, MaxCorrectiveActions)
+	assert.Equal(t, "osd.0", cluster.Status.CorrectiveActions[0].Target)
+	assert.Equal(t, fmt.Sprintf("osd.%d", MaxCorrectiveActions-1), cluster.Status.CorrectiveActions[MaxCorrectiveActions-1].Target)
+}
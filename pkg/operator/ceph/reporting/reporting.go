@@ -147,3 +147,48 @@ func ReportDeletionNotBlockedDueToDependents(
 		logger.Warningf("continuing deletion of %s %q without setting the condition. %v", kind, nsName.String(), err)
 	}
 }
+
+// ReportResourcesOrphaned reports the resources kept after the deletion of a Rook-Ceph object because of
+// its deletion policy in 3 ways:
+// 1. to the given logger
+// 2. as an event on the object (via the given event recorder), which is kept after the object is gone
+// 3. as a condition on the object, if the object has conditions
+func ReportResourcesOrphaned(
+	logger *capnslog.PackageLogger, client client.Client, recorder *k8sutil.EventReporter, obj client.Object, orphaned *dependents.DependentList,
+) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	nsName := types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+	orphanedMsg := orphaned.StringWithHeader("resources of %s %q orphaned by the deletion policy", kind, nsName.String())
+
+	// 1. log
+	logger.Info(orphanedMsg)
+
+	// 2. event
+	if recorder != nil {
+		recorder.ReportIfNotPresent(obj, corev1.EventTypeNormal, string(cephv1.ResourcesOrphanedReason), orphanedMsg)
+	}
+
+	// 3. condition
+	conditionObj, ok := obj.(cephv1.StatusConditionGetter)
+	if !ok {
+		return
+	}
+	orphanedCond := cephv1.Condition{
+		Type:    cephv1.ConditionResourcesOrphaned,
+		Status:  corev1.ConditionTrue,
+		Reason:  cephv1.ResourcesOrphanedReason,
+		Message: orphanedMsg,
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := client.Get(context.TODO(), nsName, conditionObj); err != nil {
+			return errors.Wrapf(err, "failed to get latest %s %q", kind, nsName.String())
+		}
+		return UpdateStatusCondition(client, conditionObj, orphanedCond)
+	})
+	if err != nil {
+		logger.Warningf("continuing deletion of %s %q without setting the orphaned condition. %v", kind, nsName.String(), err)
+	}
+}
@@ -19,6 +19,7 @@ package reporting
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -28,13 +29,20 @@ import (
 )
 
 // UpdateStatus updates an object with a given status. The object is updated with the latest version
-// from the server on a successful update.
+// from the server on a successful update. The update is skipped if the object was not changed since its
+// status was last written and the status is the same, or only its volatile fields such as the heartbeat
+// of the conditions changed less than VolatileStatusInterval ago.
 func UpdateStatus(client client.Client, obj client.Object) error {
 	nsName := types.NamespacedName{
 		Namespace: obj.GetNamespace(),
 		Name:      obj.GetName(),
 	}
 
+	now := time.Now()
+	if statusWrites.skip(obj, now) {
+		return nil
+	}
+
 	// Try to update the status
 	err := client.Status().Update(context.Background(), obj)
 	// If the object doesn't exist yet, we need to initialize it
@@ -42,8 +50,10 @@ func UpdateStatus(client client.Client, obj client.Object) error {
 		err = client.Update(context.Background(), obj)
 	}
 	if err != nil {
+		statusWrites.forget(obj)
 		return errors.Wrapf(err, "failed to update object %q status", nsName.String())
 	}
+	statusWrites.record(obj, now)
 
 	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
@@ -175,3 +176,108 @@ func TestUpdateStatusCondition(t *testing.T) {
 		assert.Equal(t, "update", cond.Message)
 	})
 }
+
+func TestUpdateStatusSkipsUnchangedStatus(t *testing.T) {
+	fakeObject := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "skip",
+			Namespace: "rook-ceph",
+		},
+		Status: &cephv1.ObjectStoreStatus{},
+	}
+	nsName := types.NamespacedName{
+		Namespace: fakeObject.Namespace,
+		Name:      fakeObject.Name,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, fakeObject)
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(fakeObject.DeepCopy()).Build()
+
+	oldInterval := VolatileStatusInterval
+	defer func() { VolatileStatusInterval = oldInterval }()
+
+	obj := &cephv1.CephObjectStore{}
+	err := cl.Get(context.TODO(), nsName, obj)
+	assert.NoError(t, err)
+	obj.Status.Phase = cephv1.ConditionReady
+	cephv1.SetStatusCondition(&obj.Status.Conditions, cephv1.Condition{Type: cephv1.ConditionReady, Status: v1.ConditionTrue})
+	err = UpdateStatus(cl, obj)
+	assert.NoError(t, err)
+	written := obj.ResourceVersion
+
+	t.Run("unchanged status is not written", func(t *testing.T) {
+		err := UpdateStatus(cl, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, written, obj.ResourceVersion)
+	})
+
+	t.Run("heartbeat is only written once the volatile interval is over", func(t *testing.T) {
+		obj.Status.Conditions[0].LastHeartbeatTime = metav1.NewTime(obj.Status.Conditions[0].LastHeartbeatTime.Add(time.Minute))
+		err := UpdateStatus(cl, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, written, obj.ResourceVersion)
+
+		VolatileStatusInterval = 0
+		err = UpdateStatus(cl, obj)
+		assert.NoError(t, err)
+		assert.NotEqual(t, written, obj.ResourceVersion)
+		written = obj.ResourceVersion
+		VolatileStatusInterval = oldInterval
+	})
+
+	t.Run("changed status is written", func(t *testing.T) {
+		obj.Status.Phase = cephv1.ConditionFailure
+		err := UpdateStatus(cl, obj)
+		assert.NoError(t, err)
+		assert.NotEqual(t, written, obj.ResourceVersion)
+		written = obj.ResourceVersion
+	})
+
+	t.Run("status is written if the object was changed by someone else", func(t *testing.T) {
+		other := &cephv1.CephObjectStore{}
+		err := cl.Get(context.TODO(), nsName, other)
+		assert.NoError(t, err)
+		other.Status.Phase = cephv1.ConditionReady
+		err = cl.Status().Update(context.TODO(), other)
+		assert.NoError(t, err)
+
+		err = cl.Get(context.TODO(), nsName, obj)
+		assert.NoError(t, err)
+		obj.Status.Phase = cephv1.ConditionFailure
+		err = UpdateStatus(cl, obj)
+		assert.NoError(t, err)
+		err = cl.Get(context.TODO(), nsName, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ConditionFailure, obj.Status.Phase)
+	})
+}
+
+func TestStatusWriterForgetsDeletedObjects(t *testing.T) {
+	w := newStatusWriter()
+	now := time.Now()
+	obj := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "rook-ceph", ResourceVersion: "1"},
+		Status:     &cephv1.ObjectStoreStatus{Phase: cephv1.ConditionReady},
+	}
+	w.record(obj, now)
+	assert.Len(t, w.written, 1)
+
+	// the status of an object being deleted is forgotten
+	obj.DeletionTimestamp = &metav1.Time{Time: now}
+	w.record(obj, now)
+	assert.Empty(t, w.written)
+
+	// the statuses not written for longer than the retention are forgotten
+	deleted := obj.DeepCopy()
+	deleted.Name = "deleted"
+	deleted.DeletionTimestamp = nil
+	w.record(deleted, now)
+	obj.DeletionTimestamp = nil
+	w.record(obj, now.Add(statusRetention/2))
+	assert.Len(t, w.written, 2)
+	w.record(obj, now.Add(statusRetention))
+	assert.Len(t, w.written, 1)
+	assert.Contains(t, w.written, statusKey(obj))
+}
+
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporting
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// VolatileStatusInterval is the minimum time between two updates of the status of an object when only the
+	// volatile fields of the status changed, such as the heartbeat of the conditions
+	VolatileStatusInterval = 5 * time.Minute
+
+	// volatileStatusFields are the fields of the status refreshed at each check even when nothing else changed
+	volatileStatusFields = map[string]bool{
+		"lastHeartbeatTime": true,
+		"lastChecked":       true,
+	}
+
+	// statusRetention is the time the status written for an object is remembered without being written again,
+	// so that the objects deleted without a last status write are eventually forgotten
+	statusRetention = time.Hour
+
+	statusWrites = newStatusWriter()
+)
+
+// statusWriter remembers the status last written for each object, to skip the updates that would not change
+// the status stored by the API server, and to coalesce the updates only refreshing the volatile fields
+type statusWriter struct {
+	mutex   sync.Mutex
+	written map[string]writtenStatus
+	// prunedAt is when the statuses older than statusRetention were last forgotten
+	prunedAt time.Time
+}
+
+type writtenStatus struct {
+	resourceVersion string
+	status          interface{}
+	at              time.Time
+}
+
+func newStatusWriter() *statusWriter {
+	return &statusWriter{written: map[string]writtenStatus{}}
+}
+
+func statusKey(obj client.Object) string {
+	return fmt.Sprintf("%T/%s/%s/%s", obj, obj.GetNamespace(), obj.GetName(), obj.GetUID())
+}
+
+// objectStatus returns the status of an object as unstructured content, nil if it has none
+func objectStatus(obj client.Object) interface{} {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		// the status is always written if it cannot be compared
+		return nil
+	}
+	return content["status"]
+}
+
+// withoutVolatileFields returns a copy of an unstructured status without its volatile fields
+func withoutVolatileFields(status interface{}) interface{} {
+	switch s := status.(type) {
+	case map[string]interface{}:
+		stripped := make(map[string]interface{}, len(s))
+		for k, v := range s {
+			if !volatileStatusFields[k] {
+				stripped[k] = withoutVolatileFields(v)
+			}
+		}
+		return stripped
+	case []interface{}:
+		stripped := make([]interface{}, 0, len(s))
+		for _, v := range s {
+			stripped = append(stripped, withoutVolatileFields(v))
+		}
+		return stripped
+	default:
+		return status
+	}
+}
+
+// skip returns whether the update of the status of the object can be skipped. It can be skipped if the object
+// was not updated since the status was last written by the operator, and if the status did not change or only
+// its volatile fields changed less than VolatileStatusInterval ago.
+func (w *statusWriter) skip(obj client.Object, now time.Time) bool {
+	status := objectStatus(obj)
+	if status == nil {
+		return false
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	last, ok := w.written[statusKey(obj)]
+	if !ok || last.resourceVersion == "" || last.resourceVersion != obj.GetResourceVersion() {
+		return false
+	}
+	if reflect.DeepEqual(last.status, status) {
+		return true
+	}
+	if now.Sub(last.at) < VolatileStatusInterval && reflect.DeepEqual(withoutVolatileFields(last.status), withoutVolatileFields(status)) {
+		return true
+	}
+	return false
+}
+
+// record remembers the status written for the object, with the resource version returned by the API server.
+// The status of an object being deleted is not remembered since the object goes away.
+func (w *statusWriter) record(obj client.Object, now time.Time) {
+	status := objectStatus(obj)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.prune(now)
+	if status == nil || !obj.GetDeletionTimestamp().IsZero() {
+		delete(w.written, statusKey(obj))
+		return
+	}
+	w.written[statusKey(obj)] = writtenStatus{resourceVersion: obj.GetResourceVersion(), status: status, at: now}
+}
+
+// prune forgets the statuses not written for longer than statusRetention, at most once per retention period.
+// The caller must hold the mutex.
+func (w *statusWriter) prune(now time.Time) {
+	if now.Sub(w.prunedAt) < statusRetention {
+		return
+	}
+	for key, written := range w.written {
+		if now.Sub(written.at) >= statusRetention {
+			delete(w.written, key)
+		}
+	}
+	w.prunedAt = now
+}
+
+// forget drops the status remembered for the object, such as when it was deleted
+func (w *statusWriter) forget(obj client.Object) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	delete(w.written, statusKey(obj))
+}
@@ -287,32 +287,50 @@ func ListDevices(clusterdContext *clusterd.Context, namespace, nodeName string)
 			logger.Infof("no configmap match, retry #%d", retryCount)
 			continue
 		}
-		devices = make(map[string][]sys.LocalDisk, len(cms.Items))
-		for _, cm := range cms.Items {
-			node := cm.ObjectMeta.Labels[discoverDaemon.NodeAttr]
-			if len(nodeName) > 0 && node != nodeName {
-				continue
-			}
-			deviceJson := cm.Data[discoverDaemon.LocalDiskCMData]
-			logger.Debugf("node %s, device %s", node, deviceJson)
-
-			if len(node) == 0 || len(deviceJson) == 0 {
-				continue
-			}
-			var d []sys.LocalDisk
-			err = json.Unmarshal([]byte(deviceJson), &d)
-			if err != nil {
-				logger.Warningf("failed to unmarshal %s", deviceJson)
-				continue
-			}
-			devices[node] = d
-		}
+		devices = devicesFromConfigMaps(cms.Items, nodeName)
 		break
 	}
 	logger.Debugf("discovery found the following devices %+v", devices)
 	return devices, nil
 }
 
+// GetDiscoveredDevices returns the devices discovered on all the nodes, without waiting for the discovery
+// configmaps to appear
+func GetDiscoveredDevices(clusterdContext *clusterd.Context, namespace string) (map[string][]sys.LocalDisk, error) {
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, discoverDaemon.AppName)}
+	cms, err := clusterdContext.Clientset.CoreV1().ConfigMaps(namespace).List(context.TODO(), listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device configmaps: %+v", err)
+	}
+	return devicesFromConfigMaps(cms.Items, ""), nil
+}
+
+// devicesFromConfigMaps returns the devices of the discovery configmaps by node, of all the nodes if the
+// node name is empty
+func devicesFromConfigMaps(cms []v1.ConfigMap, nodeName string) map[string][]sys.LocalDisk {
+	devices := make(map[string][]sys.LocalDisk, len(cms))
+	for _, cm := range cms {
+		node := cm.ObjectMeta.Labels[discoverDaemon.NodeAttr]
+		if len(nodeName) > 0 && node != nodeName {
+			continue
+		}
+		deviceJson := cm.Data[discoverDaemon.LocalDiskCMData]
+		logger.Debugf("node %s, device %s", node, deviceJson)
+
+		if len(node) == 0 || len(deviceJson) == 0 {
+			continue
+		}
+		var d []sys.LocalDisk
+		err := json.Unmarshal([]byte(deviceJson), &d)
+		if err != nil {
+			logger.Warningf("failed to unmarshal %s", deviceJson)
+			continue
+		}
+		devices[node] = d
+	}
+	return devices
+}
+
 // ListDevicesInUse lists all devices on a node that are already used by existing clusters.
 func ListDevicesInUse(clusterdContext *clusterd.Context, namespace, nodeName string) ([]sys.LocalDisk, error) {
 	ctx := context.TODO()
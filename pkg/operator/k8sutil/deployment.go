@@ -339,16 +339,7 @@ func updateDeployment(
 	}
 
 	// Check whether the current deployment and newly generated one are identical
-	patchChanged := false
-	patchResult, err := patch.DefaultPatchMaker.Calculate(oldDeployment, deployment)
-	if err != nil {
-		logger.Warningf("failed to calculate diff between current deployment %q and newly generated one. assuming it changed. %v", oldDeployment.Name, err)
-		patchChanged = true
-	} else if !patchResult.IsEmpty() {
-		patchChanged = true
-	}
-
-	if patchChanged {
+	if DeploymentChanged(oldDeployment, deployment) {
 		// Set hash annotation to the newly generated deployment
 		err := patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment)
 		if err != nil {
@@ -367,6 +358,16 @@ func updateDeployment(
 	return oldDeployment, nil, nil
 }
 
+// DeploymentChanged returns whether updating the current deployment with the newly generated one would change it
+func DeploymentChanged(current, desired *appsv1.Deployment) bool {
+	patchResult, err := patch.DefaultPatchMaker.Calculate(current, desired)
+	if err != nil {
+		logger.Warningf("failed to calculate diff between current deployment %q and newly generated one. assuming it changed. %v", current.Name, err)
+		return true
+	}
+	return !patchResult.IsEmpty()
+}
+
 // GetDeployments returns a list of deployment names labels matching a given selector
 // example of a label selector might be "app=rook-ceph-mon, mon!=b"
 // more: https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ProvisioningLeaseNamespaceEnvVar is the env variable with the namespace of the provisioning leases of the
+	// nodes, the namespace of the operator
+	ProvisioningLeaseNamespaceEnvVar = "ROOK_PROVISIONING_LEASE_NAMESPACE"
+
+	provisioningLeaseNameFmt = "rook-ceph-provisioning-%s"
+)
+
+// leaseClock allows the unit tests to set the time of the leases
+var leaseClock = time.Now
+
+// ProvisioningLeaseName returns the name of the provisioning lease of a node. The lease is held by the OSD prepare
+// jobs and the discover daemon while they enumerate or prepare the devices of the node, so they don't race.
+func ProvisioningLeaseName(nodeName string) string {
+	return TruncateNodeName(provisioningLeaseNameFmt, nodeName)
+}
+
+// TryAcquireLease acquires the lease for the holder if the lease is free or expired, or renews it if the holder
+// already holds it. If another holder holds the lease, it returns false with the name of that holder.
+func TryAcquireLease(ctx context.Context, clientset kubernetes.Interface, namespace, name, holder string, duration time.Duration) (bool, string, error) {
+	now := metav1.NewMicroTime(leaseClock())
+	durationSeconds := int32(duration.Seconds())
+	lease, err := clientset.CoordinationV1().Leases(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, "", errors.Wrapf(err, "failed to get lease %q", name)
+		}
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if _, err := clientset.CoordinationV1().Leases(namespace).Create(ctx, lease, metav1.CreateOptions{}); err != nil {
+			if kerrors.IsAlreadyExists(err) {
+				// another holder created the lease first
+				return false, "", nil
+			}
+			return false, "", errors.Wrapf(err, "failed to create lease %q", name)
+		}
+		return true, holder, nil
+	}
+
+	if current := leaseHolder(lease); current != "" && current != holder {
+		return false, current, nil
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		lease.Spec.HolderIdentity = &holder
+		lease.Spec.AcquireTime = &now
+		transitions := int32(1)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions + 1
+		}
+		lease.Spec.LeaseTransitions = &transitions
+	}
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	if _, err := clientset.CoordinationV1().Leases(namespace).Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		if kerrors.IsConflict(err) {
+			// another holder updated the lease first
+			return false, "", nil
+		}
+		return false, "", errors.Wrapf(err, "failed to update lease %q", name)
+	}
+	return true, holder, nil
+}
+
+// ReleaseLease releases the lease if the holder holds it
+func ReleaseLease(ctx context.Context, clientset kubernetes.Interface, namespace, name, holder string) error {
+	lease, err := clientset.CoordinationV1().Leases(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get lease %q", name)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		return nil
+	}
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.RenewTime = nil
+	if _, err := clientset.CoordinationV1().Leases(namespace).Update(ctx, lease, metav1.UpdateOptions{}); err != nil && !kerrors.IsConflict(err) {
+		return errors.Wrapf(err, "failed to release lease %q", name)
+	}
+	return nil
+}
+
+// GetLeaseHolder returns the holder of the lease, or an empty string if the lease is free or expired
+func GetLeaseHolder(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (string, error) {
+	lease, err := clientset.CoordinationV1().Leases(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get lease %q", name)
+	}
+	return leaseHolder(lease), nil
+}
+
+// leaseHolder returns the holder of the lease if it did not expire
+func leaseHolder(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return ""
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return ""
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	if !leaseClock().Before(expiry) {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
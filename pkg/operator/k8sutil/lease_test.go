@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestProvisioningLease(t *testing.T) {
+	ctx := context.TODO()
+	clientset := fake.NewSimpleClientset()
+	now := time.Date(2021, 10, 15, 10, 0, 0, 0, time.UTC)
+	leaseClock = func() time.Time { return now }
+	defer func() { leaseClock = time.Now }()
+	name := ProvisioningLeaseName("node1")
+	assert.Equal(t, "rook-ceph-provisioning-node1", name)
+
+	// the free lease is acquired
+	acquired, holder, err := TryAcquireLease(ctx, clientset, "rook-ceph", name, "prepare-node1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, "prepare-node1", holder)
+
+	// the lease is not acquired by another holder until it expires
+	now = now.Add(30 * time.Second)
+	acquired, holder, err = TryAcquireLease(ctx, clientset, "rook-ceph", name, "discover", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.Equal(t, "prepare-node1", holder)
+
+	// the holder renews the lease
+	acquired, _, err = TryAcquireLease(ctx, clientset, "rook-ceph", name, "prepare-node1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	now = now.Add(45 * time.Second)
+	holder, err = GetLeaseHolder(ctx, clientset, "rook-ceph", name)
+	require.NoError(t, err)
+	assert.Equal(t, "prepare-node1", holder)
+
+	// the expired lease is acquired by another holder
+	now = now.Add(time.Minute)
+	holder, err = GetLeaseHolder(ctx, clientset, "rook-ceph", name)
+	require.NoError(t, err)
+	assert.Empty(t, holder)
+	acquired, _, err = TryAcquireLease(ctx, clientset, "rook-ceph", name, "discover", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// only the holder releases the lease
+	assert.NoError(t, ReleaseLease(ctx, clientset, "rook-ceph", name, "prepare-node1"))
+	holder, err = GetLeaseHolder(ctx, clientset, "rook-ceph", name)
+	require.NoError(t, err)
+	assert.Equal(t, "discover", holder)
+	assert.NoError(t, ReleaseLease(ctx, clientset, "rook-ceph", name, "discover"))
+	holder, err = GetLeaseHolder(ctx, clientset, "rook-ceph", name)
+	require.NoError(t, err)
+	assert.Empty(t, holder)
+}
@@ -374,6 +374,19 @@ func ForceDeletePodIfStuck(clusterdContext *clusterd.Context, pod v1.Pod) error
 	return nil
 }
 
+// DeletePod makes a best effort at deleting a pod, then waits for it to be deleted
+func DeletePod(clientset kubernetes.Interface, namespace, name string) error {
+	ctx := context.TODO()
+	deleteAction := func(options *metav1.DeleteOptions) error {
+		return clientset.CoreV1().Pods(namespace).Delete(ctx, name, *options)
+	}
+	getAction := func() error {
+		_, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		return err
+	}
+	return deleteResourceAndWait(namespace, name, "pod", deleteAction, getAction)
+}
+
 func RemoveDuplicateEnvVars(pod *v1.PodSpec) {
 	for i := range pod.Containers {
 		removeDuplicateEnvVarsFromContainer(&pod.Containers[i])
@@ -87,6 +87,19 @@ func CreateOrUpdateServiceMonitor(serviceMonitorDefinition *monitoringv1.Service
 	return sm, nil
 }
 
+// DeleteServiceMonitor deletes the serviceMonitor object if it exists
+func DeleteServiceMonitor(namespace, name string) error {
+	client, err := getMonitoringClient()
+	if err != nil {
+		return fmt.Errorf("failed to get monitoring client. %v", err)
+	}
+	err = client.MonitoringV1().ServiceMonitors(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete servicemonitor. %v", err)
+	}
+	return nil
+}
+
 // GetPrometheusRule returns provided prometheus rules or an error
 func GetPrometheusRule(ruleFilePath string) (*monitoringv1.PrometheusRule, error) {
 	ruleFile, err := ioutil.ReadFile(filepath.Clean(ruleFilePath))
@@ -130,3 +143,16 @@ func CreateOrUpdatePrometheusRule(prometheusRule *monitoringv1.PrometheusRule) (
 	}
 	return promRule, nil
 }
+
+// DeletePrometheusRule deletes the prometheusRule object if it exists
+func DeletePrometheusRule(namespace, name string) error {
+	client, err := getMonitoringClient()
+	if err != nil {
+		return fmt.Errorf("failed to get monitoring client. %v", err)
+	}
+	err = client.MonitoringV1().PrometheusRules(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete prometheusRule. %v", err)
+	}
+	return nil
+}
@@ -46,4 +46,10 @@ func TestGetPrometheusRule(t *testing.T) {
 	// Labels should be present as they are used by prometheus for identifying rules
 	assert.NotNil(t, rules.GetLabels())
 	assert.NotNil(t, rules.Spec.Groups)
+
+	filePath = path.Join(projectRoot, "/cluster/examples/kubernetes/ceph/monitoring/prometheus-ceph-perf-counters-rules.yaml")
+	rules, err = GetPrometheusRule(filePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "prometheus-ceph-perf-counters-rules", rules.GetName())
+	assert.NotNil(t, rules.Spec.Groups)
 }
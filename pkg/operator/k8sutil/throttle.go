@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DefaultClientQPS is the default rate of the requests of the operator to the Kubernetes API
+	DefaultClientQPS = 20
+	// DefaultClientBurst is the default burst of the requests of the operator to the Kubernetes API
+	DefaultClientBurst = 30
+	// DefaultControllerRetryBudget is the default share of the rate and of the burst of the requests a single
+	// controller can use
+	DefaultControllerRetryBudget = 0.5
+
+	// unknownController is the controller of the requests sent outside of a controller
+	unknownController = "operator"
+)
+
+type controllerNameKey struct{}
+
+var (
+	clientThrottleWaitHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rook_ceph_k8s_client_throttle_wait_seconds",
+		Help:    "Time the requests of a controller to the Kubernetes API waited for the client-side rate limiter",
+		Buckets: []float64{0.001, 0.01, 0.1, 0.5, 1, 5, 10, 30, 60},
+	}, []string{"controller"})
+	clientRetryBudgetExhaustedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rook_ceph_k8s_client_retry_budget_exhausted_total",
+		Help: "Number of requests of a controller to the Kubernetes API delayed because the controller used its retry budget",
+	}, []string{"controller"})
+)
+
+func init() {
+	// the controller-runtime registry is served by the operator's manager metrics endpoint
+	metrics.Registry.MustRegister(clientThrottleWaitHistogram, clientRetryBudgetExhaustedCounter)
+}
+
+// WithControllerName returns a context attributing the requests to the Kubernetes API sent with it to a controller
+func WithControllerName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, controllerNameKey{}, name)
+}
+
+// ControllerNameFromContext returns the controller the requests sent with the context are attributed to
+func ControllerNameFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if name, ok := ctx.Value(controllerNameKey{}).(string); ok && name != "" {
+			return name
+		}
+	}
+	return unknownController
+}
+
+// ConfigureClientThrottling sets the rate and the burst of the requests to the Kubernetes API of the clients built
+// from the config. The requests of each controller are limited to the retry budget, a share of the rate and of the
+// burst, so that a controller retrying its requests cannot starve the other controllers. The time the requests
+// wait for the rate limiter is reported per controller.
+func ConfigureClientThrottling(config *rest.Config, qps float32, burst int, retryBudget float64) {
+	if qps <= 0 {
+		qps = DefaultClientQPS
+	}
+	if burst <= 0 {
+		burst = DefaultClientBurst
+	}
+	if retryBudget <= 0 || retryBudget > 1 {
+		retryBudget = DefaultControllerRetryBudget
+	}
+	config.QPS = qps
+	config.Burst = burst
+	config.RateLimiter = newThrottledRateLimiter(qps, burst, retryBudget)
+}
+
+// CopyConfigWithOwnRateLimiter returns a copy of the config whose clients do not share the rate limiter of the clients
+// built from the config, with the same throttling. rest.CopyConfig keeps the same rate limiter, so the
+// controller-runtime manager and the clientset of the operator would wait for each other.
+func CopyConfigWithOwnRateLimiter(config *rest.Config) *rest.Config {
+	copied := rest.CopyConfig(config)
+	if limiter, ok := config.RateLimiter.(*throttledRateLimiter); ok {
+		copied.RateLimiter = newThrottledRateLimiter(limiter.qps, limiter.burst, limiter.retryBudget)
+	}
+	return copied
+}
+
+// throttledRateLimiter limits the requests of all the controllers, and the requests of each controller to its budget.
+// The requests sent outside of a controller, such as the orchestration of the clusters, are only limited by the rate
+// and the burst of all the requests.
+type throttledRateLimiter struct {
+	flowcontrol.RateLimiter
+	qps             float32
+	burst           int
+	retryBudget     float64
+	controllerQPS   float32
+	controllerBurst int
+	mutex           sync.Mutex
+	controllers     map[string]flowcontrol.RateLimiter
+}
+
+func newThrottledRateLimiter(qps float32, burst int, retryBudget float64) *throttledRateLimiter {
+	return &throttledRateLimiter{
+		RateLimiter:     flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+		qps:             qps,
+		burst:           burst,
+		retryBudget:     retryBudget,
+		controllerQPS:   float32(math.Max(float64(qps)*retryBudget, 1)),
+		controllerBurst: int(math.Max(math.Floor(float64(burst)*retryBudget), 1)),
+		controllers:     map[string]flowcontrol.RateLimiter{},
+	}
+}
+
+func (l *throttledRateLimiter) controllerLimiter(name string) flowcontrol.RateLimiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	limiter, ok := l.controllers[name]
+	if !ok {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(l.controllerQPS, l.controllerBurst)
+		l.controllers[name] = limiter
+	}
+	return limiter
+}
+
+// Wait waits for the budget of the controller of the request, then for the rate limiter of all the controllers
+func (l *throttledRateLimiter) Wait(ctx context.Context) error {
+	name := ControllerNameFromContext(ctx)
+	start := time.Now()
+	defer func() {
+		clientThrottleWaitHistogram.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	}()
+
+	if name == unknownController {
+		return l.RateLimiter.Wait(ctx)
+	}
+	limiter := l.controllerLimiter(name)
+	if !limiter.TryAccept() {
+		clientRetryBudgetExhaustedCounter.WithLabelValues(name).Inc()
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return l.RateLimiter.Wait(ctx)
+}
+
+// Stop stops the rate limiters of all the controllers
+func (l *throttledRateLimiter) Stop() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, limiter := range l.controllers {
+		limiter.Stop()
+	}
+	l.RateLimiter.Stop()
+}
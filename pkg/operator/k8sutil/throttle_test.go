@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+func TestControllerNameFromContext(t *testing.T) {
+	ctx := context.TODO()
+	assert.Equal(t, "operator", ControllerNameFromContext(ctx))
+	assert.Equal(t, "ceph-block-pool-controller", ControllerNameFromContext(WithControllerName(ctx, "ceph-block-pool-controller")))
+}
+
+func TestConfigureClientThrottling(t *testing.T) {
+	config := &rest.Config{}
+	ConfigureClientThrottling(config, 0, 0, 2)
+	assert.Equal(t, float32(DefaultClientQPS), config.QPS)
+	assert.Equal(t, DefaultClientBurst, config.Burst)
+	limiter := config.RateLimiter.(*throttledRateLimiter)
+	assert.Equal(t, float32(10), limiter.controllerQPS)
+	assert.Equal(t, 15, limiter.controllerBurst)
+
+	ConfigureClientThrottling(config, 1, 4, 0.5)
+	assert.Equal(t, float32(1), config.QPS)
+	assert.Equal(t, 4, config.Burst)
+	limiter = config.RateLimiter.(*throttledRateLimiter)
+	defer limiter.Stop()
+	// the budget of a controller is never below one request
+	assert.Equal(t, float32(1), limiter.controllerQPS)
+	assert.Equal(t, 2, limiter.controllerBurst)
+
+	// a controller uses its budget, the other controllers can still send requests
+	pool := WithControllerName(context.TODO(), "pool")
+	assert.NoError(t, limiter.Wait(pool))
+	assert.NoError(t, limiter.Wait(pool))
+	assert.Equal(t, float64(0), testutil.ToFloat64(clientRetryBudgetExhaustedCounter.WithLabelValues("pool")))
+	assert.True(t, limiter.TryAccept())
+	assert.NoError(t, limiter.Wait(WithControllerName(context.TODO(), "object")))
+
+	canceled, cancel := context.WithCancel(pool)
+	cancel()
+	assert.Error(t, limiter.Wait(canceled))
+	assert.Equal(t, float64(1), testutil.ToFloat64(clientRetryBudgetExhaustedCounter.WithLabelValues("pool")))
+	// the wait time is reported per controller
+	assert.Equal(t, 2, testutil.CollectAndCount(clientThrottleWaitHistogram))
+
+	// the requests sent outside of a controller have no budget of their own
+	assert.NoError(t, limiter.Wait(context.TODO()))
+	assert.NotContains(t, limiter.controllers, "operator")
+}
+
+func TestCopyConfigWithOwnRateLimiter(t *testing.T) {
+	config := &rest.Config{Host: "https://1.2.3.4"}
+	ConfigureClientThrottling(config, 10, 20, 0.5)
+	copied := CopyConfigWithOwnRateLimiter(config)
+	assert.Equal(t, config.Host, copied.Host)
+	limiter := config.RateLimiter.(*throttledRateLimiter)
+	copiedLimiter := copied.RateLimiter.(*throttledRateLimiter)
+	assert.NotSame(t, limiter, copiedLimiter)
+	assert.Equal(t, limiter.controllerQPS, copiedLimiter.controllerQPS)
+	assert.Equal(t, limiter.controllerBurst, copiedLimiter.controllerBurst)
+	limiter.Stop()
+	copiedLimiter.Stop()
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	osexec "os/exec"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -43,13 +44,23 @@ const (
 	MultiPath = "mpath"
 	// LinearType is a linear type
 	LinearType = "linear"
-	sgdiskCmd  = "sgdisk"
+	// DMType is a device mapper device not managed by LVM, dm-crypt or multipath
+	DMType = "dm"
+	// BcacheType is a bcache device, reported as a disk by lsblk
+	BcacheType = "bcache"
+	// DMCacheType is a device mapper device with the cache or writecache target
+	DMCacheType = "dm-cache"
+	// BcacheFilesystem is the signature of the backing and cache devices of bcache
+	BcacheFilesystem = "bcache"
+	sgdiskCmd        = "sgdisk"
 	// CephLVPrefix is the prefix of a LV owned by ceph-volume
 	CephLVPrefix = "ceph--"
 	// DeviceMapperPrefix is the prefix of a LV from the device mapper interface
 	DeviceMapperPrefix = "dm-"
 )
 
+var isBcache = regexp.MustCompile("^bcache[0-9]+$")
+
 // CephVolumeInventory represents the output of the ceph-volume inventory command
 type CephVolumeInventory struct {
 	Path            string          `json:"path"`
@@ -241,6 +252,33 @@ func IsLV(devicePath string, executor exec.Executor) (bool, error) {
 	return diskType == LVMType, nil
 }
 
+// GetCacheType returns the type of the device when it is fronted by a cache, BcacheType or
+// DMCacheType, or an empty string otherwise. The kernel name is the one of the device, like
+// "bcache0" or "dm-3", and the disk type is the TYPE reported by lsblk.
+func GetCacheType(kernelName, diskType string, executor exec.Executor) (string, error) {
+	kernelName = path.Base(kernelName)
+	if diskType == DiskType && isBcache.MatchString(kernelName) {
+		return BcacheType, nil
+	}
+	if diskType != DMType {
+		// the cache LVs of lvmcache are reported and handled as LVs
+		return "", nil
+	}
+
+	output, err := executor.ExecuteCommandWithOutput("dmsetup", "table", path.Join("/dev", kernelName))
+	if err != nil {
+		return "", fmt.Errorf("failed to get the device mapper table of %q. %v", kernelName, err)
+	}
+	// each line of the table is "<start> <length> <target> <target args>"
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 2 && (fields[2] == "cache" || fields[2] == "writecache") {
+			return DMCacheType, nil
+		}
+	}
+	return "", nil
+}
+
 // GetUdevInfo gets udev information
 func GetUdevInfo(device string, executor exec.Executor) (map[string]string, error) {
 	output, err := executor.ExecuteCommandWithOutput("udevadm", "info", "--query=property", fmt.Sprintf("/dev/%s", device))
@@ -285,6 +323,65 @@ func GetDiskUUID(device string, executor exec.Executor) (string, error) {
 	return parseUUID(device, output)
 }
 
+// PartitionName returns the kernel name of a partition of the device, for example "sdb2" or "nvme0n1p2"
+func PartitionName(device string, number int) string {
+	device = strings.TrimPrefix(device, "/dev/")
+	if device != "" && device[len(device)-1] >= '0' && device[len(device)-1] <= '9' {
+		return fmt.Sprintf("%sp%d", device, number)
+	}
+	return fmt.Sprintf("%s%d", device, number)
+}
+
+// CreatePartitions splits a device without partitions in count GPT partitions of the same size, and
+// returns the kernel names of the partitions
+func CreatePartitions(device string, size uint64, count int, executor exec.Executor) ([]string, error) {
+	device = strings.TrimPrefix(device, "/dev/")
+	if count < 1 {
+		return nil, fmt.Errorf("invalid number of partitions %d for device %q", count, device)
+	}
+	// keep a MiB at each end of the device for the alignment and the GPT headers
+	sizeMiB := size / (1024 * 1024)
+	if sizeMiB <= 2 || (sizeMiB-2)/uint64(count) == 0 {
+		return nil, fmt.Errorf("device %q of %d bytes is too small for %d partitions", device, size, count)
+	}
+	partitionSizeMiB := (sizeMiB - 2) / uint64(count)
+
+	args := []string{}
+	partitions := []string{}
+	for i := 1; i <= count; i++ {
+		end := fmt.Sprintf("+%dM", partitionSizeMiB)
+		if i == count {
+			// the last partition takes the rest of the device
+			end = "0"
+		}
+		args = append(args, fmt.Sprintf("--new=%d:0:%s", i, end), fmt.Sprintf("--change-name=%d:ceph-osd-%d", i, i))
+		partitions = append(partitions, PartitionName(device, i))
+	}
+	args = append(args, path.Join("/dev", device))
+	if err := executor.ExecuteCommand(sgdiskCmd, args...); err != nil {
+		return nil, fmt.Errorf("failed to partition device %q. %v", device, err)
+	}
+
+	// wait for the device files of the new partitions
+	if err := executor.ExecuteCommand("udevadm", "settle", "--timeout=30"); err != nil {
+		logger.Warningf("failed to wait for the partitions of device %q. %v", device, err)
+	}
+	return partitions, nil
+}
+
+// RemovePartitions destroys the partition table of a device partitioned by CreatePartitions, to leave
+// the device empty when its partitions could not be prepared
+func RemovePartitions(device string, executor exec.Executor) error {
+	device = strings.TrimPrefix(device, "/dev/")
+	if err := executor.ExecuteCommand(sgdiskCmd, "--zap-all", path.Join("/dev", device)); err != nil {
+		return fmt.Errorf("failed to remove the partitions of device %q. %v", device, err)
+	}
+	if err := executor.ExecuteCommand("udevadm", "settle", "--timeout=30"); err != nil {
+		logger.Warningf("failed to wait for the removal of the partitions of device %q. %v", device, err)
+	}
+	return nil
+}
+
 func GetDiskDeviceClass(disk *LocalDisk) string {
 	if disk.Rotational {
 		return "hdd"
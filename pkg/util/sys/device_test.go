@@ -196,3 +196,111 @@ func TestListDevicesChildListDevicesChild(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 3, len(child))
 }
+
+func TestGetCacheType(t *testing.T) {
+	dmTable := ""
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, arg ...string) (string, error) {
+			logger.Infof("command %s %v", command, arg)
+			if command == "dmsetup" && arg[0] == "table" && arg[1] == "/dev/dm-3" {
+				return dmTable, nil
+			}
+			return "", fmt.Errorf("unexpected command %s %v", command, arg)
+		},
+	}
+
+	cacheType, err := GetCacheType("bcache0", DiskType, executor)
+	assert.NoError(t, err)
+	assert.Equal(t, BcacheType, cacheType)
+
+	cacheType, err = GetCacheType("/dev/bcache12", DiskType, executor)
+	assert.NoError(t, err)
+	assert.Equal(t, BcacheType, cacheType)
+
+	cacheType, err = GetCacheType("sdb", DiskType, executor)
+	assert.NoError(t, err)
+	assert.Equal(t, "", cacheType)
+
+	// LVs are not checked, lvmcache LVs are handled as LVs
+	cacheType, err = GetCacheType("dm-3", LVMType, executor)
+	assert.NoError(t, err)
+	assert.Equal(t, "", cacheType)
+
+	dmTable = "0 1953525168 cache 253:1 253:0 253:2 512 1 writethrough smq 0"
+	cacheType, err = GetCacheType("dm-3", DMType, executor)
+	assert.NoError(t, err)
+	assert.Equal(t, DMCacheType, cacheType)
+
+	dmTable = "0 1953525168 writecache s 253:0 253:1 4096 0"
+	cacheType, err = GetCacheType("dm-3", DMType, executor)
+	assert.NoError(t, err)
+	assert.Equal(t, DMCacheType, cacheType)
+
+	dmTable = "0 1953525168 linear 8:16 0"
+	cacheType, err = GetCacheType("dm-3", DMType, executor)
+	assert.NoError(t, err)
+	assert.Equal(t, "", cacheType)
+
+	_, err = GetCacheType("dm-4", DMType, executor)
+	assert.Error(t, err)
+}
+
+func TestPartitionName(t *testing.T) {
+	assert.Equal(t, "sdb2", PartitionName("sdb", 2))
+	assert.Equal(t, "sdb1", PartitionName("/dev/sdb", 1))
+	assert.Equal(t, "nvme0n1p3", PartitionName("nvme0n1", 3))
+	assert.Equal(t, "loop0p1", PartitionName("/dev/loop0", 1))
+}
+
+func TestCreatePartitions(t *testing.T) {
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommand: func(command string, arg ...string) error {
+			commands = append(commands, append([]string{command}, arg...))
+			return nil
+		},
+	}
+
+	// 1 TiB split in 3 partitions
+	partitions, err := CreatePartitions("nvme0n1", 1024*1024*1024*1024, 3, executor)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nvme0n1p1", "nvme0n1p2", "nvme0n1p3"}, partitions)
+	assert.Equal(t, 2, len(commands))
+	assert.Equal(t, []string{"sgdisk",
+		"--new=1:0:+349524M", "--change-name=1:ceph-osd-1",
+		"--new=2:0:+349524M", "--change-name=2:ceph-osd-2",
+		"--new=3:0:0", "--change-name=3:ceph-osd-3",
+		"/dev/nvme0n1"}, commands[0])
+	assert.Equal(t, []string{"udevadm", "settle", "--timeout=30"}, commands[1])
+
+	// invalid count and size
+	_, err = CreatePartitions("sdb", 1024*1024*1024, 0, executor)
+	assert.Error(t, err)
+	_, err = CreatePartitions("sdb", 2*1024*1024, 1, executor)
+	assert.Error(t, err)
+
+	// sgdisk failure
+	executor.MockExecuteCommand = func(command string, arg ...string) error {
+		return fmt.Errorf("mock failure")
+	}
+	_, err = CreatePartitions("sdb", 1024*1024*1024, 2, executor)
+	assert.Error(t, err)
+}
+
+func TestRemovePartitions(t *testing.T) {
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommand: func(command string, arg ...string) error {
+			commands = append(commands, append([]string{command}, arg...))
+			return nil
+		},
+	}
+
+	assert.NoError(t, RemovePartitions("/dev/nvme0n1", executor))
+	assert.Equal(t, [][]string{{"sgdisk", "--zap-all", "/dev/nvme0n1"}, {"udevadm", "settle", "--timeout=30"}}, commands)
+
+	executor.MockExecuteCommand = func(command string, arg ...string) error {
+		return fmt.Errorf("mock failure")
+	}
+	assert.Error(t, RemovePartitions("sdb", executor))
+}
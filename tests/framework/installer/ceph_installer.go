@@ -690,10 +690,16 @@ func (h *CephInstaller) UninstallRookFromMultipleNS(manifests ...CephManifests)
 				logger.Infof("done deleting all the resources in the common external manifest")
 			}
 		} else {
+			h.k8shelper.PrintResources(namespace, "cephauthexports.ceph.rook.io")
+			h.k8shelper.PrintResources(namespace, "cephbenchmarks.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephblockpools.ceph.rook.io")
+			h.k8shelper.PrintResources(namespace, "cephbucketnotifications.ceph.rook.io")
+			h.k8shelper.PrintResources(namespace, "cephbuckettopics.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephclients.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephclusters.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephfilesystemmirrors.ceph.rook.io")
+			h.k8shelper.PrintResources(namespace, "cephfilesystemstaticvolumes.ceph.rook.io")
+			h.k8shelper.PrintResources(namespace, "cephfilesystemsubvolumegroups.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephfilesystems.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephnfses.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephobjectrealms.ceph.rook.io")
@@ -701,6 +707,7 @@ func (h *CephInstaller) UninstallRookFromMultipleNS(manifests ...CephManifests)
 			h.k8shelper.PrintResources(namespace, "cephobjectstoreusers.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephobjectzonegroups.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephobjectzones.ceph.rook.io")
+			h.k8shelper.PrintResources(namespace, "cephpoolmigrations.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "cephrbdmirrors.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "objectbucketclaims.ceph.rook.io")
 			h.k8shelper.PrintResources(namespace, "objectbuckets.ceph.rook.io")